@@ -38,21 +38,28 @@ import (
 	"github.com/joho/godotenv"
 	"go.opentelemetry.io/otel/attribute"
 	otelmetric "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/ashita-ai/akashi/api"
+	"github.com/ashita-ai/akashi/internal/auditsink"
 	"github.com/ashita-ai/akashi/internal/auth"
 	"github.com/ashita-ai/akashi/internal/authz"
 	"github.com/ashita-ai/akashi/internal/config"
 	"github.com/ashita-ai/akashi/internal/conflicts"
+	akashigrpc "github.com/ashita-ai/akashi/internal/grpc"
 	"github.com/ashita-ai/akashi/internal/integrity"
 	"github.com/ashita-ai/akashi/internal/mcp"
+	"github.com/ashita-ai/akashi/internal/metrics"
 	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/ratelimit"
 	"github.com/ashita-ai/akashi/internal/search"
 	"github.com/ashita-ai/akashi/internal/server"
 	"github.com/ashita-ai/akashi/internal/service/autoassess"
 	"github.com/ashita-ai/akashi/internal/service/autoresolve"
+	"github.com/ashita-ai/akashi/internal/service/conflictsuggest"
 	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/service/duplicates"
 	"github.com/ashita-ai/akashi/internal/service/embedding"
 	"github.com/ashita-ai/akashi/internal/service/quality"
 	"github.com/ashita-ai/akashi/internal/service/trace"
@@ -68,6 +75,7 @@ type App struct {
 	cfg             config.Config
 	db              *storage.DB
 	srv             *server.Server
+	grpcSrv         *akashigrpc.Server // nil when AKASHI_GRPC_PORT is 0
 	buf             *trace.Buffer
 	outbox          *search.OutboxWorker
 	qdrantIndex     *search.QdrantIndex // nil when Qdrant is not configured
@@ -81,6 +89,9 @@ type App struct {
 	decisionHooks   []server.DecisionHook
 	logger          *slog.Logger
 	autoResolver    *autoresolve.Service
+	duplicates      *duplicates.Service
+	suggestions     *conflictsuggest.Service // nil when conflict suggestions are disabled
+	auditSink       *auditsink.Writer        // nil when no audit sink is configured
 	version         string
 
 	bgLoops sync.WaitGroup // tracks background goroutines for graceful shutdown
@@ -124,6 +135,9 @@ func New(opts ...Option) (*App, error) {
 	if o.notifyURL != "" {
 		cfg.NotifyURL = o.notifyURL
 	}
+	if o.replicaURL != "" {
+		cfg.DatabaseReplicaURL = o.replicaURL
+	}
 	version := o.version
 	if version == "" {
 		version = "dev"
@@ -131,8 +145,15 @@ func New(opts ...Option) (*App, error) {
 
 	logger.Info("akashi starting", "version", version, "port", cfg.Port)
 
-	// Initialize OpenTelemetry.
-	otelShutdown, err := telemetry.Init(ctx(opts), cfg.OTELEndpoint, cfg.ServiceName, version, cfg.OTELInsecure, cfg.OTELSampleRate)
+	// Initialize OpenTelemetry. The Prometheus exporter, when enabled, is one
+	// more reader on the same meter provider.
+	var metricsExporter *metrics.Exporter
+	var metricReaders []sdkmetric.Reader
+	if cfg.MetricsEnabled {
+		metricsExporter = metrics.New()
+		metricReaders = append(metricReaders, metricsExporter.Reader())
+	}
+	otelShutdown, err := telemetry.Init(ctx(opts), cfg.OTELEndpoint, cfg.ServiceName, version, cfg.OTELInsecure, cfg.OTELSampleRate, metricReaders...)
 	if err != nil {
 		return nil, fmt.Errorf("telemetry: %w", err)
 	}
@@ -149,14 +170,17 @@ func New(opts ...Option) (*App, error) {
 
 	// Connect to database.
 	db, err := storage.New(context.Background(), cfg.DatabaseURL, cfg.NotifyURL, logger, storage.PoolOptions{
-		MaxConns: cfg.DBMaxConns,
-		MinConns: cfg.DBMinConns,
+		MaxConns:   cfg.DBMaxConns,
+		MinConns:   cfg.DBMinConns,
+		ReplicaDSN: cfg.DatabaseReplicaURL,
 	})
 	if err != nil {
 		_ = otelShutdown(context.Background())
 		return nil, fmt.Errorf("storage: %w", err)
 	}
 	db.RegisterPoolMetrics()
+	db.SetEvidenceInlineMaxBytes(cfg.EvidenceInlineMaxBytes)
+	db.SetConfidencePrecision(cfg.ConfidencePrecision)
 
 	// Run OSS migrations.
 	if cfg.SkipEmbeddedMigrations {
@@ -191,6 +215,18 @@ func New(opts ...Option) (*App, error) {
 		return nil, fmt.Errorf("critical table 'decisions' does not exist after migration — check that pgvector and timescaledb extensions are created (see docker/init.sql)")
 	}
 
+	if err := auth.SetHashParams(auth.HashParams{
+		Algorithm:  cfg.APIKeyHashAlgorithm,
+		Time:       uint32(cfg.APIKeyArgon2Time),      //nolint:gosec // validated >= 1 by config
+		MemoryKiB:  uint32(cfg.APIKeyArgon2MemoryKiB), //nolint:gosec // validated <= 4194304 by config
+		Threads:    uint8(cfg.APIKeyArgon2Threads),    //nolint:gosec // validated 1..255 by config
+		BcryptCost: cfg.APIKeyBcryptCost,
+	}); err != nil {
+		db.Close(context.Background())
+		_ = otelShutdown(context.Background())
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
 	// Create JWT manager.
 	jwtMgr, err := auth.NewJWTManager(cfg.JWTPrivateKeyPath, cfg.JWTPublicKeyPath, cfg.JWTExpiration)
 	if err != nil {
@@ -198,6 +234,7 @@ func New(opts ...Option) (*App, error) {
 		_ = otelShutdown(context.Background())
 		return nil, fmt.Errorf("auth: %w", err)
 	}
+	jwtMgr.WithRefreshExpiration(cfg.JWTRefreshExpiration)
 
 	// Create embedding provider — external override takes priority over auto-detect.
 	var embedder embedding.Provider
@@ -206,6 +243,11 @@ func New(opts ...Option) (*App, error) {
 	} else {
 		embedder = newEmbeddingProvider(cfg, logger)
 	}
+	if colDims, err := db.EmbeddingDimensions(context.Background()); err != nil {
+		logger.Warn("embedding dimension check skipped", "error", err)
+	} else {
+		embedder = checkEmbeddingDimensions(embedder, colDims, logger)
+	}
 
 	// Initialize Qdrant search index and outbox worker.
 	var searcher search.Searcher
@@ -231,7 +273,8 @@ func New(opts ...Option) (*App, error) {
 			return nil, fmt.Errorf("qdrant ensure collection: %w", err)
 		}
 		searcher = qdrantIndex
-		outboxWorker = search.NewOutboxWorker(db.Pool(), qdrantIndex, logger, cfg.OutboxPollInterval, cfg.OutboxBatchSize)
+		outboxWorker = search.NewOutboxWorker(db.Pool(), qdrantIndex, logger, cfg.OutboxPollInterval, cfg.OutboxBatchSize).
+			WithMaxAttempts(cfg.OutboxMaxAttempts)
 		logger.Info("qdrant: enabled", "collection", cfg.QdrantCollection)
 	} else {
 		logger.Info("qdrant: disabled (no QDRANT_URL)")
@@ -270,7 +313,9 @@ func New(opts ...Option) (*App, error) {
 		WithScoringThresholds(cfg.ConflictClaimTopicSimFloor, cfg.ConflictClaimDivFloor, cfg.ConflictDecisionTopicSimFloor).
 		WithCandidateLimit(cfg.ConflictCandidateLimit).
 		WithEarlyExitFloor(cfg.ConflictEarlyExitFloor).
-		WithOutcomeSimFloor(cfg.ConflictOutcomeSimFloor)
+		WithOutcomeSimFloor(cfg.ConflictOutcomeSimFloor).
+		WithClaimOverlap(cfg.ConflictClaimOverlapLimit, cfg.ConflictClaimOverlapSimFloor).
+		WithConsensusDrift(cfg.ConflictConsensusMinority, cfg.ConflictConsensusMinCohort)
 	if qdrantIndex != nil {
 		conflictScorer = conflictScorer.WithCandidateFinder(qdrantIndex)
 	}
@@ -309,6 +354,27 @@ func New(opts ...Option) (*App, error) {
 	// service (for trace-time signals) and the MCP server (for resolve-time signals).
 	assessor := autoassess.New(db, logger)
 	decisionSvc.SetAutoAssessor(assessor)
+	decisionSvc.SetOrgSettingsReader(db)
+	decisionSvc.SetBatchWindow(cfg.DecisionBatchWindow)
+	decisionSvc.SetConfidencePrecision(cfg.ConfidencePrecision)
+	decisionSvc.SetContextSnapshotLimit(cfg.ContextSnapshotMaxBytes, cfg.ContextSnapshotOversize)
+	decisionSvc.SetFlipFlopDetection(db, cfg.FlipFlopMinFlips, cfg.FlipFlopWindow)
+	decisionSvc.SetDuplicateDetection(db, cfg.DuplicateTraceThreshold)
+	decisionSvc.SetCompletenessWeights(quality.BuildWeights(cfg.CompletenessWeights))
+
+	// Audit sink: mirror every traced decision to an external append-only store.
+	var auditSink *auditsink.Writer
+	if cfg.AuditSinkURL != "" {
+		sink, err := auditsink.Open(cfg.AuditSinkURL)
+		if err != nil {
+			db.Close(context.Background())
+			_ = otelShutdown(context.Background())
+			return nil, fmt.Errorf("audit sink: %w", err)
+		}
+		auditSink = auditsink.NewWriter(sink, cfg.AuditSinkMode, cfg.AuditSinkTimeout, cfg.AuditSinkQueueSize, logger)
+		decisionSvc.SetAuditSink(auditSink)
+		logger.Info("audit sink: enabled", "mode", cfg.AuditSinkMode)
+	}
 
 	// Embedding backfills (non-fatal).
 	if n, err := decisionSvc.BackfillEmbeddings(context.Background(), 500); err != nil {
@@ -326,6 +392,11 @@ func New(opts ...Option) (*App, error) {
 	} else if n > 0 {
 		logger.Info("claims backfill complete", "count", n)
 	}
+	if n, err := decisionSvc.BackfillCompletenessScores(context.Background(), 500); err != nil {
+		logger.Warn("completeness score backfill failed", "error", err)
+	} else if n > 0 {
+		logger.Info("completeness score backfill complete", "count", n)
+	}
 
 	// Force conflict rescore if configured.
 	if cfg.ForceConflictRescore && conflictScorer.HasLLMValidator() {
@@ -421,7 +492,8 @@ func New(opts ...Option) (*App, error) {
 	if cfg.RateLimitEnabled {
 		limiter = ratelimit.NewMemoryLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)
 		logger.Info("rate limiting: memory (in-process token bucket)",
-			"rps", cfg.RateLimitRPS, "burst", cfg.RateLimitBurst)
+			"rps", cfg.RateLimitRPS, "burst", cfg.RateLimitBurst,
+			"per_agent_rps", cfg.RateLimitPerAgentRPS, "per_agent_burst", cfg.RateLimitPerAgentBurst)
 	} else {
 		limiter = ratelimit.NoopLimiter{}
 		logger.Info("rate limiting: disabled")
@@ -449,7 +521,25 @@ func New(opts ...Option) (*App, error) {
 		middlewares = append(middlewares, func(h http.Handler) http.Handler { return mw(h) })
 	}
 
+	// Avoid wrapping a nil *OutboxWorker in a non-nil interface.
+	var outboxFlusher server.OutboxFlusher
+	if outboxWorker != nil {
+		outboxFlusher = outboxWorker
+	}
+	var vectorCollection server.VectorCollection
+	if qdrantIndex != nil {
+		vectorCollection = qdrantIndex
+	}
+
 	// Create HTTP server.
+	var metricsHandler http.Handler
+	if metricsExporter != nil {
+		metricsHandler = metricsExporter.Handler(cfg.MetricsToken.Value())
+		if cfg.MetricsToken == "" {
+			logger.Warn("prometheus metrics enabled at /metrics without AKASHI_METRICS_TOKEN; restrict network access")
+		}
+	}
+
 	srv := server.New(server.ServerConfig{
 		DB:                          db,
 		JWTMgr:                      jwtMgr,
@@ -462,13 +552,17 @@ func New(opts ...Option) (*App, error) {
 		Port:                        cfg.Port,
 		ReadTimeout:                 cfg.ReadTimeout,
 		WriteTimeout:                cfg.WriteTimeout,
+		RouteTimeouts:               routeTimeouts(cfg.RouteTimeouts),
 		MCPServer:                   mcpSrv.MCPServer(),
 		Version:                     version,
 		MaxRequestBodyBytes:         cfg.MaxRequestBodyBytes,
 		RateLimiter:                 limiter,
+		RateLimitPerAgent:           ratelimit.Limit{RPS: cfg.RateLimitPerAgentRPS, Burst: cfg.RateLimitPerAgentBurst},
 		TrustProxy:                  cfg.TrustProxy,
 		CORSAllowedOrigins:          cfg.CORSAllowedOrigins,
+		CORSPolicies:                corsPolicies(cfg.CORSPolicies),
 		EnableDestructiveDelete:     cfg.EnableDestructiveDelete,
+		SoftDelete:                  cfg.SoftDelete,
 		RetentionInterval:           cfg.RetentionInterval,
 		UIFS:                        uiFS,
 		OpenAPISpec:                 api.OpenAPISpec,
@@ -481,8 +575,23 @@ func New(opts ...Option) (*App, error) {
 		SignupEnabled:               cfg.SignupEnabled,
 		ResolutionRecorder:          conflictScorer,
 		ConflictValidator:           conflictValidator,
+		ConflictRescorer:            conflictScorer,
+		Outbox:                      outboxFlusher,
 		HighConfidenceWarnThreshold: cfg.HighConfidenceWarnThreshold,
 		ExportPageSize:              cfg.ExportPageSize,
+		TraceBatchMax:               cfg.TraceBatchMax,
+		EventSchemaValidation:       cfg.EventSchemaValidation,
+		AccessLog: server.AccessLogConfig{
+			Enabled:       cfg.AccessLogEnabled,
+			SampleRate:    cfg.AccessLogSampleRate,
+			MaxIDs:        cfg.AccessLogMaxIDs,
+			Requests:      cfg.AccessLogRequests,
+			FlushInterval: cfg.AccessLogFlushInterval,
+		},
+		ReviewSLA:        cfg.ReviewSLA,
+		EmbeddingModel:   cfg.EmbeddingModelProfile,
+		VectorCollection: vectorCollection,
+		MetricsHandler:   metricsHandler,
 	})
 
 	// Wire akashi_check → IDE hook gate.
@@ -496,6 +605,31 @@ func New(opts ...Option) (*App, error) {
 	// marker is cleared.
 	mcpSrv.SetTraceCompleteNotify(srv.Handlers().NotifyTraceComplete)
 
+	var grpcSrv *akashigrpc.Server
+	if cfg.GRPCPort > 0 {
+		var grpcCreds credentials.TransportCredentials
+		if cfg.GRPCTLSCertFile != "" {
+			grpcCreds, err = credentials.NewServerTLSFromFile(cfg.GRPCTLSCertFile, cfg.GRPCTLSKeyFile)
+			if err != nil {
+				db.Close(context.Background())
+				_ = otelShutdown(context.Background())
+				return nil, fmt.Errorf("grpc tls: %w", err)
+			}
+		}
+		grpcSrv = akashigrpc.New(akashigrpc.ServerConfig{
+			DB:          db,
+			JWTMgr:      jwtMgr,
+			DecisionSvc: decisionSvc,
+			Broker:      broker,
+			GrantCache:  grantCache,
+			// Same buckets, org overrides, and per-agent cap as the HTTP API.
+			RateLimiter: srv,
+			Creds:       grpcCreds,
+			Logger:      logger,
+			Port:        cfg.GRPCPort,
+		})
+	}
+
 	// Seed admin agent.
 	if err := srv.Handlers().SeedAdmin(context.Background(), cfg.AdminAPIKey.Value()); err != nil {
 		db.Close(context.Background())
@@ -514,6 +648,7 @@ func New(opts ...Option) (*App, error) {
 		cfg:                 cfg,
 		db:                  db,
 		srv:                 srv,
+		grpcSrv:             grpcSrv,
 		buf:                 buf,
 		outbox:              outboxWorker,
 		qdrantIndex:         qdrantIndex,
@@ -527,6 +662,9 @@ func New(opts ...Option) (*App, error) {
 		decisionHooks:       decisionHooks,
 		logger:              logger,
 		autoResolver:        autoresolve.New(db, logger),
+		duplicates:          duplicates.New(db, cfg.DuplicateSimilarityFloor, logger),
+		suggestions:         newConflictSuggestions(cfg, db, logger),
+		auditSink:           auditSink,
 		version:             version,
 		integrityViolations: integrityViolations,
 	}, nil
@@ -539,7 +677,10 @@ func (a *App) Run(ctx context.Context) error {
 	// Start background services.
 	a.buf.Start(ctx)
 	if a.outbox != nil {
-		a.outbox.Start(ctx)
+		// Not cancelled with ctx: the worker keeps syncing until the outbox
+		// shutdown phase drains it, after the requests and async work that
+		// write outbox entries have finished.
+		a.outbox.Start(context.WithoutCancel(ctx))
 	}
 	if a.broker != nil {
 		a.bgLoops.Add(1)
@@ -558,11 +699,18 @@ func (a *App) Run(ctx context.Context) error {
 		a.integrityAuditLoop,
 		a.integrityFullAuditLoop,
 		a.idempotencyCleanupLoop,
+		a.runIdleSweepLoop,
+		a.eventRetentionLoop,
 		a.hookCheckCleanupLoop,
+		a.evidenceBlobSweepLoop,
 		a.retentionLoop,
 		a.claimEmbeddingRetryLoop,
 		a.percentileRefreshLoop,
 		a.autoResolveLoop,
+		a.reviewOverdueLoop,
+		a.conflictSuggestionLoop,
+		a.duplicateScanLoop,
+		a.cdcCompactionLoop,
 	} {
 		a.bgLoops.Add(1)
 		go func() {
@@ -571,13 +719,20 @@ func (a *App) Run(ctx context.Context) error {
 		}()
 	}
 
-	// Start HTTP server.
-	errCh := make(chan error, 1)
+	// Start HTTP server, and the gRPC server when enabled.
+	errCh := make(chan error, 2)
 	go func() {
 		if err := a.srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errCh <- err
 		}
 	}()
+	if a.grpcSrv != nil {
+		go func() {
+			if err := a.grpcSrv.Start(); err != nil {
+				errCh <- err
+			}
+		}()
+	}
 
 	// Block until signal or server error.
 	select {
@@ -589,75 +744,153 @@ func (a *App) Run(ctx context.Context) error {
 	return a.Shutdown(context.Background())
 }
 
-// Shutdown performs a three-phase graceful shutdown:
-// (1) stop accepting HTTP requests and drain in-flight,
-// (2) flush the event buffer to Postgres,
-// (3) drain remaining outbox entries to Qdrant.
-// It then closes the database pool and OTEL provider.
+// Shutdown runs the shutdown phases in order. By default background loops
+// stop first, then HTTP and gRPC stop accepting requests and in-flight trace
+// work drains, so the search outbox, audit sink, and event buffer drain only
+// once nothing can add to them. AKASHI_SHUTDOWN_ORDER reorders those phases;
+// releasing clients and closing the database pool always come last.
 func (a *App) Shutdown(ctx context.Context) error {
 	a.logger.Info("akashi shutting down")
+	for _, phase := range a.shutdownPhases() {
+		a.logger.Debug("shutdown phase", "phase", phase.name)
+		if err := phase.run(ctx); err != nil {
+			return err
+		}
+	}
+	a.logger.Info("akashi stopped")
+	return nil
+}
+
+// shutdownPhase is one step of Shutdown. Each phase applies its own timeout;
+// an error aborts the phases after it.
+type shutdownPhase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// shutdownPhases returns the shutdown steps in the order Shutdown runs them:
+// the phases of cfg.ShutdownOrder (config.DefaultShutdownOrder when unset),
+// then cleanup and the database.
+func (a *App) shutdownPhases() []shutdownPhase {
+	steps := map[string]func(ctx context.Context) error{
+		"loops":      a.drainLoops,
+		"http":       a.stopServers,
+		"async":      a.drainAsync,
+		"outbox":     a.drainOutbox,
+		"audit_sink": a.closeAuditSink,
+		"buffer":     a.drainBuffer,
+	}
+	order := a.cfg.ShutdownOrder
+	if len(order) == 0 {
+		order = config.DefaultShutdownOrder
+	}
+	phases := make([]shutdownPhase, 0, len(order)+2)
+	for _, name := range order {
+		phases = append(phases, shutdownPhase{name, steps[name]})
+	}
+	return append(phases,
+		shutdownPhase{"cleanup", a.closeClients},
+		shutdownPhase{"database", a.closeDB},
+	)
+}
+
+// drainLoops waits for background loops to exit. The Run() context was
+// cancelled before Shutdown was called, so loops are already stopping. The
+// wait is bounded to avoid hanging on a stuck goroutine.
+func (a *App) drainLoops(ctx context.Context) error {
+	bgDone := make(chan struct{})
+	go func() { a.bgLoops.Wait(); close(bgDone) }()
+	loopCtx, loopCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownLoopDrainTimeout)
+	defer loopCancel()
+	select {
+	case <-bgDone:
+		a.logger.Info("all background loops exited")
+	case <-loopCtx.Done():
+		a.logger.Warn("background loops did not exit within timeout, proceeding with shutdown",
+			"configured_timeout", a.cfg.ShutdownLoopDrainTimeout,
+		)
+	}
+	return nil
+}
+
+// drainOutbox stops the outbox worker and syncs its pending entries to
+// Qdrant. Entries it cannot sync in time stay in Postgres and sync on next
+// startup.
+func (a *App) drainOutbox(ctx context.Context) error {
+	if a.outbox == nil {
+		return nil
+	}
+	outboxCtx, outboxCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownOutboxDrainTimeout)
+	defer outboxCancel()
+	a.outbox.Drain(outboxCtx)
+	if outboxCtx.Err() != nil {
+		a.logger.Error("search outbox drain did not complete within timeout — Qdrant index may be stale",
+			"error", outboxCtx.Err(),
+			"configured_timeout", a.cfg.ShutdownOutboxDrainTimeout,
+		)
+	}
+	return nil
+}
 
-	// Phase 1: HTTP drain.
+// stopServers stops accepting HTTP and gRPC requests and drains in-flight ones.
+func (a *App) stopServers(ctx context.Context) error {
 	httpCtx, httpCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownHTTPTimeout)
+	defer httpCancel()
 	if err := a.srv.Shutdown(httpCtx); err != nil {
 		a.logger.Error("http shutdown error", "error", err)
 	}
-	httpCancel()
+	if a.grpcSrv != nil {
+		a.grpcSrv.GracefulStop(httpCtx)
+	}
+	return nil
+}
 
-	// Phase 1.5: wait for in-flight post-trace async work (claim generation,
-	// conflict scoring) so goroutines finish their DB writes before pool close.
+// drainAsync waits for in-flight post-trace async work (claim generation,
+// conflict scoring) so goroutines finish their DB writes before pool close.
+func (a *App) drainAsync(ctx context.Context) error {
 	asyncCtx, asyncCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownAsyncDrainTimeout)
+	defer asyncCancel()
 	if err := a.decisionSvc.DrainAsync(asyncCtx); err != nil {
 		a.logger.Warn("async post-trace drain incomplete — some claims or conflict scores may be missing",
 			"error", err,
 			"configured_timeout", a.cfg.ShutdownAsyncDrainTimeout,
 		)
 	}
-	asyncCancel()
+	return nil
+}
+
+// closeAuditSink delivers queued audit sink records once all trace paths
+// have stopped.
+func (a *App) closeAuditSink(ctx context.Context) error {
+	if a.auditSink == nil {
+		return nil
+	}
+	sinkCtx, sinkCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownAsyncDrainTimeout)
+	defer sinkCancel()
+	if err := a.auditSink.Close(sinkCtx); err != nil {
+		a.logger.Error("audit sink drain incomplete — some decisions were not mirrored", "error", err)
+	}
+	return nil
+}
 
-	// Phase 2: buffer drain.
+// drainBuffer flushes the event buffer to Postgres. A failure stops the
+// shutdown before the pool closes.
+func (a *App) drainBuffer(ctx context.Context) error {
 	bufCtx, bufCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownBufferDrainTimeout)
+	defer bufCancel()
 	if err := a.buf.Drain(bufCtx); err != nil {
 		a.logger.Error("event buffer drain incomplete — unflushed events will be lost",
 			"error", err,
 			"remaining_events", a.buf.Len(),
 			"configured_timeout", a.cfg.ShutdownBufferDrainTimeout,
 		)
-		bufCancel()
 		return fmt.Errorf("buffer drain failed: %w", err)
 	}
-	bufCancel()
-
-	// Phase 3: outbox drain.
-	if a.outbox != nil {
-		outboxCtx, outboxCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownOutboxDrainTimeout)
-		a.outbox.Drain(outboxCtx)
-		if outboxCtx.Err() != nil {
-			a.logger.Error("search outbox drain did not complete within timeout — Qdrant index may be stale",
-				"error", outboxCtx.Err(),
-				"configured_timeout", a.cfg.ShutdownOutboxDrainTimeout,
-			)
-		}
-		outboxCancel()
-	}
-
-	// Wait for background loops to exit. The Run() context was cancelled
-	// before Shutdown was called, so loops are draining. We bound the wait
-	// to avoid hanging on a stuck goroutine.
-	bgDone := make(chan struct{})
-	go func() { a.bgLoops.Wait(); close(bgDone) }()
-	loopCtx, loopCancel := contextWithOptionalTimeout(ctx, a.cfg.ShutdownLoopDrainTimeout)
-	select {
-	case <-bgDone:
-		a.logger.Info("all background loops exited")
-	case <-loopCtx.Done():
-		a.logger.Warn("background loops did not exit within timeout, proceeding with shutdown",
-			"configured_timeout", a.cfg.ShutdownLoopDrainTimeout,
-		)
-	}
-	loopCancel()
+	return nil
+}
 
-	// Cleanup.
+// closeClients releases caches, limiters, and the Qdrant and OTEL clients.
+func (a *App) closeClients(ctx context.Context) error {
 	a.grantCache.Close()
 	if a.limiter != nil {
 		_ = a.limiter.Close()
@@ -667,9 +900,11 @@ func (a *App) Shutdown(ctx context.Context) error {
 		_ = a.qdrantIndex.Close()
 	}
 	_ = a.otelShutdown(ctx)
-	a.db.Close(ctx)
+	return nil
+}
 
-	a.logger.Info("akashi stopped")
+func (a *App) closeDB(ctx context.Context) error {
+	a.db.Close(ctx)
 	return nil
 }
 
@@ -783,15 +1018,7 @@ func (a *App) conflictRefreshLoop(ctx context.Context) {
 						}()
 					}
 
-					payload, err := json.Marshal(map[string]any{
-						"org_id":        c.OrgID,
-						"conflict_kind": c.ConflictKind,
-						"decision_a_id": c.DecisionAID,
-						"decision_b_id": c.DecisionBID,
-						"agent_a":       c.AgentA,
-						"agent_b":       c.AgentB,
-						"decision_type": c.DecisionType,
-					})
+					payload, err := server.ConflictEventPayload(c)
 					if err != nil {
 						a.logger.Warn("conflict notify marshal failed", "error", err)
 						continue
@@ -1174,18 +1401,117 @@ func (a *App) idempotencyCleanupLoop(ctx context.Context) {
 	})
 }
 
+// runIdleSweepBatch bounds how many runs one sweep transaction closes.
+const runIdleSweepBatch = 500
+
+// runIdleSweepLoop closes runs that have gone quiet for longer than
+// AKASHI_RUN_IDLE_TIMEOUT, so runs whose agent crashed before completing
+// them do not stay "running" forever. Disabled when the timeout is zero.
+func (a *App) runIdleSweepLoop(ctx context.Context) {
+	if a.cfg.RunIdleTimeout <= 0 {
+		return
+	}
+	status := model.RunStatus(a.cfg.RunIdleStatus)
+	a.runLoop(ctx, "runIdleSweep", a.cfg.RunIdleSweepInterval, func(ctx context.Context) {
+		opCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		cutoff := time.Now().Add(-a.cfg.RunIdleTimeout)
+		total := 0
+		for {
+			closed, err := a.db.CloseIdleRuns(opCtx, cutoff, status, runIdleSweepBatch)
+			if err != nil {
+				a.logger.Warn("run idle sweep failed", "error", err)
+				break
+			}
+			total += closed
+			if closed < runIdleSweepBatch {
+				break
+			}
+		}
+		if total > 0 {
+			a.logger.Info("run idle sweep closed runs", "closed", total, "status", status)
+		}
+	})
+}
+
+// eventRetentionLoop deletes raw agent_events older than
+// AKASHI_EVENT_RETENTION, or older than an org's event_retention_days config
+// override where one is set. Orgs without an override keep their events
+// forever when the global retention window is zero.
+func (a *App) eventRetentionLoop(ctx context.Context) {
+	if a.cfg.EventRetentionInterval <= 0 {
+		return
+	}
+	a.runLoop(ctx, "eventRetention", a.cfg.EventRetentionInterval, func(ctx context.Context) {
+		opCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		defer cancel()
+		overrides, err := a.db.ListOrgEventRetentionOverrides(opCtx)
+		if err != nil {
+			a.logger.Warn("event retention: list org overrides failed", "error", err)
+			return
+		}
+
+		var deleted int64
+		overridden := make([]uuid.UUID, 0, len(overrides))
+		for orgID, days := range overrides {
+			overridden = append(overridden, orgID)
+			if days <= 0 {
+				continue
+			}
+			n, err := a.db.DeleteOrgEventsOlderThan(opCtx, orgID, time.Now().AddDate(0, 0, -days))
+			deleted += n
+			if err != nil {
+				a.logger.Warn("event retention failed", "org_id", orgID, "error", err, "deleted", n)
+			}
+		}
+		if a.cfg.EventRetention > 0 {
+			n, err := a.db.DeleteEventsOlderThan(opCtx, time.Now().Add(-a.cfg.EventRetention), overridden...)
+			deleted += n
+			if err != nil {
+				a.logger.Warn("event retention failed", "error", err, "deleted", n)
+			}
+		}
+		if deleted > 0 {
+			a.logger.Info("event retention deleted rows", "deleted", deleted)
+		}
+	})
+}
+
 func (a *App) hookCheckCleanupLoop(ctx context.Context) {
 	a.runLoop(ctx, "hookCheckCleanup", 10*time.Minute, func(_ context.Context) {
 		a.srv.Handlers().CleanupHookChecks()
 	})
 }
 
+// evidenceBlobGrace is how long an evidence blob must go unreferenced before
+// the sweep deletes it. Writes refresh last_referenced_at before inserting the
+// evidence row that points at the blob, so the grace covers that window.
+const evidenceBlobGrace = time.Hour
+
+// evidenceBlobSweepLoop deletes evidence blobs left behind when the evidence
+// referencing them was deleted.
+func (a *App) evidenceBlobSweepLoop(ctx context.Context) {
+	a.runLoop(ctx, "evidenceBlobSweep", time.Hour, func(ctx context.Context) {
+		opCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		deleted, err := a.db.DeleteOrphanEvidenceBlobs(opCtx, time.Now().Add(-evidenceBlobGrace))
+		if err != nil {
+			a.logger.Warn("evidence blob sweep failed", "error", err)
+			return
+		}
+		if deleted > 0 {
+			a.logger.Info("evidence blob sweep deleted blobs", "deleted", deleted)
+		}
+	})
+}
+
 func (a *App) retentionLoop(ctx context.Context) {
 	if a.cfg.RetentionInterval <= 0 {
 		return
 	}
 	a.runLoop(ctx, "retention", a.cfg.RetentionInterval, func(ctx context.Context) {
 		a.runRetention(ctx)
+		a.purgeTombstones(ctx)
 	})
 }
 
@@ -1270,6 +1596,122 @@ func (a *App) autoResolveLoop(ctx context.Context) {
 	})
 }
 
+// conflictSuggestionLoop periodically writes LLM explanations and suggested
+// resolutions for open conflicts. No-op unless conflict suggestions are enabled.
+func (a *App) conflictSuggestionLoop(ctx context.Context) {
+	if a.suggestions == nil {
+		return
+	}
+	a.runLoop(ctx, "conflictSuggestion", a.cfg.ConflictSuggestionInterval, func(ctx context.Context) {
+		if err := a.suggestions.RunOnce(ctx); err != nil {
+			a.logger.Warn("conflict suggestion loop failed", "error", err)
+		}
+	})
+}
+
+// reviewOverdueLoop periodically routes newly raised review flags to
+// reviewers and notifies subscribers when decisions flagged for review pass
+// the review SLA without being reviewed. Each flag is notified at most once
+// per flagging.
+func (a *App) reviewOverdueLoop(ctx context.Context) {
+	if a.cfg.ReviewOverdueInterval <= 0 {
+		return
+	}
+	a.runLoop(ctx, "reviewOverdue", a.cfg.ReviewOverdueInterval, func(ctx context.Context) {
+		a.routeReviewFlags(ctx)
+
+		flags, err := a.db.ClaimOverdueReviewFlags(ctx, time.Now().Add(-a.cfg.ReviewSLA), 100)
+		if err != nil {
+			a.logger.Warn("review overdue loop failed", "error", err)
+			return
+		}
+		for _, f := range flags {
+			payload, err := json.Marshal(map[string]any{
+				"source":      "review_overdue",
+				"decision_id": f.DecisionID,
+				"org_id":      f.OrgID,
+				"reason":      f.Reason,
+				"flagged_at":  f.FlaggedAt,
+			})
+			if err != nil {
+				a.logger.Warn("review overdue notify marshal failed", "error", err)
+				continue
+			}
+			if err := a.db.Notify(ctx, storage.ChannelDecisions, string(payload)); err != nil {
+				a.logger.Warn("review overdue notify failed", "decision_id", f.DecisionID, "error", err)
+			}
+		}
+		if len(flags) > 0 {
+			a.logger.Info("review overdue notifications sent", "count", len(flags))
+		}
+	})
+}
+
+// routeReviewFlags assigns reviewers, per each org's review_routing setting,
+// to flags raised by database triggers (low confidence, conflicts), which
+// are not routed when they are created.
+func (a *App) routeReviewFlags(ctx context.Context) {
+	orgIDs, err := a.db.ListOrgsWithUnroutedReviewFlags(ctx)
+	if err != nil {
+		a.logger.Warn("review routing failed", "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		settings, err := a.db.GetOrgSettings(ctx, orgID)
+		if err != nil {
+			a.logger.Warn("review routing: load org settings failed", "org_id", orgID, "error", err)
+			continue
+		}
+		routed, err := a.db.RouteReviewFlags(ctx, orgID, settings.Settings.ReviewRouting)
+		if err != nil {
+			a.logger.Warn("review routing failed", "org_id", orgID, "error", err)
+			continue
+		}
+		if routed > 0 {
+			a.logger.Info("review flags routed", "org_id", orgID, "count", routed)
+		}
+	}
+}
+
+// duplicateScanLoop periodically rebuilds each org's near-duplicate decision
+// pairs, which back GET /v1/stats/duplicates.
+func (a *App) duplicateScanLoop(ctx context.Context) {
+	if a.cfg.DuplicateScanInterval <= 0 {
+		return
+	}
+	a.runLoop(ctx, "duplicateScan", a.cfg.DuplicateScanInterval, func(ctx context.Context) {
+		if err := a.duplicates.RunOnce(ctx); err != nil {
+			a.logger.Warn("duplicate scan loop failed", "error", err)
+		}
+	})
+}
+
+// cdcCompactionLoop periodically numbers pending decision change log entries
+// and deletes sequenced entries older than AKASHI_CDC_RETENTION. Polls of
+// GET /v1/cdc/decisions also sequence, so this only bounds the log's size.
+func (a *App) cdcCompactionLoop(ctx context.Context) {
+	if a.cfg.CDCCompactionInterval <= 0 {
+		return
+	}
+	a.runLoop(ctx, "cdcCompaction", a.cfg.CDCCompactionInterval, func(ctx context.Context) {
+		if _, err := a.db.SequenceDecisionChanges(ctx); err != nil {
+			a.logger.Warn("cdc compaction: sequence failed", "error", err)
+			return
+		}
+		if a.cfg.CDCRetention <= 0 {
+			return
+		}
+		deleted, err := a.db.CompactDecisionChanges(ctx, time.Now().Add(-a.cfg.CDCRetention))
+		if err != nil {
+			a.logger.Warn("cdc compaction failed", "error", err)
+			return
+		}
+		if deleted > 0 {
+			a.logger.Info("cdc compaction deleted entries", "deleted", deleted)
+		}
+	})
+}
+
 // runRetention processes data retention policies for all orgs that have a
 // retention_days set. Each org gets its own deletion_log entry.
 func (a *App) runRetention(ctx context.Context) {
@@ -1326,6 +1768,42 @@ func (a *App) runRetention(ctx context.Context) {
 	}
 }
 
+// purgeTombstones hard-deletes agents tombstoned (AKASHI_SOFT_DELETE) longer
+// than AKASHI_TOMBSTONE_RETENTION ago, recording each in deletion_log.
+func (a *App) purgeTombstones(ctx context.Context) {
+	if a.cfg.TombstoneRetention <= 0 {
+		return
+	}
+	opCtx, cancel := context.WithTimeout(ctx, a.cfg.RetentionInterval/2)
+	defer cancel()
+
+	cutoff := time.Now().UTC().Add(-a.cfg.TombstoneRetention)
+	purged, err := a.db.PurgeTombstonedData(opCtx, cutoff)
+	if err != nil {
+		a.logger.Warn("retention: tombstone purge failed", "error", err, "purged", len(purged))
+	}
+	for _, p := range purged {
+		logID, err := a.db.StartDeletionLog(opCtx, p.OrgID, "policy", "",
+			map[string]any{"agent_id": p.AgentID, "tombstoned_before": cutoff})
+		if err != nil {
+			a.logger.Warn("retention: failed to start deletion log", "org_id", p.OrgID, "error", err)
+			continue
+		}
+		countMap := map[string]any{
+			"decisions":    p.Deleted.Decisions,
+			"alternatives": p.Deleted.Alternatives,
+			"evidence":     p.Deleted.Evidence,
+			"claims":       p.Deleted.Claims,
+			"events":       p.Deleted.Events,
+		}
+		if cerr := a.db.CompleteDeletionLog(opCtx, p.OrgID, logID, countMap); cerr != nil {
+			a.logger.Warn("retention: failed to complete deletion log", "org_id", p.OrgID, "error", cerr)
+		}
+		a.logger.Info("retention: purged tombstoned agent",
+			"org_id", p.OrgID, "agent_id", p.AgentID, "decisions", p.Deleted.Decisions)
+	}
+}
+
 // ── Adapters (defined here because this file imports both sides) ───────────────
 
 // decisionHookAdapter wraps an akashi.EventHook to satisfy server.DecisionHook.
@@ -1437,6 +1915,24 @@ func toPublicConflict(c model.DecisionConflict) Conflict {
 	}
 }
 
+// corsPolicies converts AKASHI_CORS_POLICIES entries to the server's form.
+func corsPolicies(in []config.CORSPolicy) []server.CORSPolicy {
+	out := make([]server.CORSPolicy, len(in))
+	for i, p := range in {
+		out[i] = server.CORSPolicy(p)
+	}
+	return out
+}
+
+// routeTimeouts converts AKASHI_ROUTE_TIMEOUTS entries to the server's form.
+func routeTimeouts(in []config.RouteTimeout) []server.RouteTimeout {
+	out := make([]server.RouteTimeout, len(in))
+	for i, rt := range in {
+		out[i] = server.RouteTimeout(rt)
+	}
+	return out
+}
+
 // derefOr returns the dereferenced value of ptr, or fallback if ptr is nil.
 func derefOr[T any](ptr *T, fallback T) T {
 	if ptr != nil {
@@ -1450,6 +1946,10 @@ func derefOr[T any](ptr *T, fallback T) T {
 func newEmbeddingProvider(cfg config.Config, logger *slog.Logger) embedding.Provider {
 	dims := cfg.EmbeddingDimensions
 
+	if len(cfg.EmbeddingFallback) > 0 {
+		return newFallbackEmbeddingProvider(cfg, logger)
+	}
+
 	switch cfg.EmbeddingProvider {
 	case "openai":
 		if cfg.OpenAIAPIKey == "" {
@@ -1490,6 +1990,74 @@ func newEmbeddingProvider(cfg config.Config, logger *slog.Logger) embedding.Prov
 	}
 }
 
+// checkEmbeddingDimensions compares the provider's dimension with the
+// database's embedding columns. On a mismatch every insert would fail inside
+// pgvector, so it logs how to migrate and returns a noop provider instead:
+// decisions keep being traced without vectors and the backfill embeds them
+// once the dimensions agree.
+func checkEmbeddingDimensions(embedder embedding.Provider, colDims int, logger *slog.Logger) embedding.Provider {
+	if _, noop := embedder.(*embedding.NoopProvider); noop {
+		return embedder
+	}
+	dims := embedder.Dimensions()
+	if dims == colDims {
+		return embedder
+	}
+	logger.Error("embedding dimension mismatch: provider vectors do not fit the database columns, semantic search disabled",
+		"provider_dimensions", dims,
+		"column_dimensions", colDims,
+		"remediation", fmt.Sprintf(
+			"run `go run ./scripts/reembed -dims %d` to clear existing vectors and resize the columns, "+
+				"restart, then re-embed each org with POST /v1/admin/reembed", dims))
+	return embedding.NewNoopProvider(colDims)
+}
+
+// newFallbackEmbeddingProvider builds the AKASHI_EMBEDDING_FALLBACK chain.
+// Unusable entries (openai without an API key) are skipped; an empty chain
+// degrades to noop like the single-provider modes do.
+func newFallbackEmbeddingProvider(cfg config.Config, logger *slog.Logger) embedding.Provider {
+	dims := cfg.EmbeddingDimensions
+
+	var backends []embedding.Backend
+	for _, name := range cfg.EmbeddingFallback {
+		switch name {
+		case "ollama":
+			backends = append(backends, embedding.Backend{
+				Name:     name,
+				Provider: embedding.NewOllamaProvider(cfg.OllamaURL, cfg.OllamaModel, dims),
+			})
+		case "openai":
+			if cfg.OpenAIAPIKey == "" {
+				logger.Error("OPENAI_API_KEY required for openai in AKASHI_EMBEDDING_FALLBACK, skipping")
+				continue
+			}
+			p, err := embedding.NewOpenAIProvider(cfg.OpenAIAPIKey.Value(), cfg.EmbeddingModel, dims)
+			if err != nil {
+				logger.Error("openai provider init failed, skipping", "error", err)
+				continue
+			}
+			backends = append(backends, embedding.Backend{Name: name, Provider: p})
+		}
+	}
+	if len(backends) == 0 {
+		logger.Warn("no usable provider in AKASHI_EMBEDDING_FALLBACK, using noop (semantic search disabled)")
+		return embedding.NewNoopProvider(dims)
+	}
+
+	names := make([]string, len(backends))
+	for i, b := range backends {
+		names[i] = b.Name
+	}
+	p, err := embedding.NewFallbackProvider(backends, cfg.EmbeddingFallbackTimeout, logger)
+	if err != nil {
+		logger.Error("embedding fallback chain init failed", "error", err)
+		return embedding.NewNoopProvider(dims)
+	}
+	logger.Info("embedding provider: fallback chain", "providers", names,
+		"timeout", cfg.EmbeddingFallbackTimeout, "dimensions", dims)
+	return p
+}
+
 func newConflictValidator(cfg config.Config, logger *slog.Logger) conflicts.Validator {
 	if cfg.ConflictLLMModel != "" {
 		logger.Info("conflict validator: ollama", "model", cfg.ConflictLLMModel, "url", cfg.OllamaURL, "num_threads", cfg.ConflictLLMThreads)
@@ -1503,6 +2071,29 @@ func newConflictValidator(cfg config.Config, logger *slog.Logger) conflicts.Vali
 	return conflicts.NoopValidator{}
 }
 
+// newConflictSuggestions creates the conflict suggestion worker when enabled,
+// using the same LLM selection as the conflict validator. Returns nil when
+// suggestions are disabled or no LLM is available.
+func newConflictSuggestions(cfg config.Config, db *storage.DB, logger *slog.Logger) *conflictsuggest.Service {
+	if !cfg.ConflictSuggestionsEnabled {
+		return nil
+	}
+	var suggester conflicts.Suggester
+	switch {
+	case cfg.ConflictLLMModel != "":
+		logger.Info("conflict suggestions: ollama", "model", cfg.ConflictLLMModel, "url", cfg.OllamaURL,
+			"rate_per_minute", cfg.ConflictSuggestionRatePerMinute)
+		suggester = conflicts.NewOllamaSuggester(cfg.OllamaURL, cfg.ConflictLLMModel, cfg.ConflictLLMThreads)
+	case cfg.OpenAIAPIKey != "":
+		logger.Info("conflict suggestions: openai (gpt-4o-mini)", "rate_per_minute", cfg.ConflictSuggestionRatePerMinute)
+		suggester = conflicts.NewOpenAISuggester(cfg.OpenAIAPIKey.Value(), "gpt-4o-mini")
+	default:
+		logger.Warn("conflict suggestions enabled but no LLM configured, suggestions disabled")
+		return nil
+	}
+	return conflictsuggest.New(db, suggester, cfg.ConflictSuggestionRatePerMinute, cfg.ConflictSuggestionInterval, logger)
+}
+
 // newClaimExtractor creates an LLM-backed claim extractor when configured.
 // Returns nil when LLM claim extraction is disabled or no LLM is available.
 func newClaimExtractor(cfg config.Config, logger *slog.Logger) conflicts.ClaimExtractor {
@@ -1,9 +1,16 @@
 package akashi
 
 import (
+	"io"
+	"log/slog"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/config"
+	"github.com/ashita-ai/akashi/internal/service/embedding"
 )
 
 func TestDerefOr(t *testing.T) {
@@ -32,3 +39,60 @@ func TestDerefOr(t *testing.T) {
 		assert.Equal(t, "", derefOr(&s, "fallback"))
 	})
 }
+
+func TestCheckEmbeddingDimensions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("keeps provider when dimensions match", func(t *testing.T) {
+		p := embedding.NewOllamaProvider("", "mxbai-embed-large", 1024)
+		assert.Same(t, p, checkEmbeddingDimensions(p, 1024, logger))
+	})
+
+	t.Run("falls back to noop on mismatch", func(t *testing.T) {
+		p := embedding.NewOllamaProvider("", "nomic-embed-text", 768)
+		got := checkEmbeddingDimensions(p, 1024, logger)
+		assert.IsType(t, &embedding.NoopProvider{}, got)
+		assert.Equal(t, 1024, got.Dimensions())
+	})
+
+	t.Run("leaves noop provider alone", func(t *testing.T) {
+		p := embedding.NewNoopProvider(1536)
+		assert.Same(t, p, checkEmbeddingDimensions(p, 1024, logger))
+	})
+}
+
+func TestShutdownPhaseOrder(t *testing.T) {
+	var names []string
+	for _, phase := range (&App{}).shutdownPhases() {
+		names = append(names, phase.name)
+	}
+	before := func(first, second string) {
+		t.Helper()
+		i, j := slices.Index(names, first), slices.Index(names, second)
+		if i < 0 || j < 0 {
+			t.Fatalf("missing phase %q or %q in %v", first, second, names)
+		}
+		assert.Less(t, i, j, "%q must run before %q", first, second)
+	}
+
+	before("loops", "http")
+	// The outbox, audit sink, and event buffer drain only once no request
+	// or post-trace work can add to them.
+	before("http", "async")
+	before("async", "outbox")
+	before("async", "audit_sink")
+	before("async", "buffer")
+	assert.Equal(t, "database", names[len(names)-1], "the pool closes last")
+}
+
+func TestShutdownPhaseOrder_Configured(t *testing.T) {
+	order := []string{"http", "async", "buffer", "outbox", "audit_sink", "loops"}
+	app := &App{cfg: config.Config{ShutdownOrder: order}}
+
+	var names []string
+	for _, phase := range app.shutdownPhases() {
+		require.NotNil(t, phase.run, "phase %q has no step", phase.name)
+		names = append(names, phase.name)
+	}
+	assert.Equal(t, append(slices.Clone(order), "cleanup", "database"), names)
+}
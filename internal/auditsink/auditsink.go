@@ -0,0 +1,301 @@
+// Package auditsink mirrors every written decision to an external,
+// append-only store (a local log file or an HTTP endpoint such as a SIEM
+// collector). The mirror is a second record of each decision's canonical
+// fields and content hash, independent of the Akashi database, so tampering
+// with the database is detectable by comparing the two.
+package auditsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/telemetry"
+)
+
+// Delivery modes.
+const (
+	// ModeAsync queues records and delivers them in the background with
+	// retries. Traces never wait on the sink; records are lost only if the
+	// queue overflows or the sink stays down past shutdown.
+	ModeAsync = "async"
+	// ModeBlock writes the record inside the trace transaction, before
+	// commit. A sink failure fails the trace, so every committed decision
+	// has a mirrored record.
+	ModeBlock = "block"
+)
+
+// Record is the mirrored form of a decision: its identity, canonical hashed
+// fields, and content hash. VerifyContentHash over the canonical fields
+// must accept ContentHash.
+type Record struct {
+	DecisionID   uuid.UUID  `json:"decision_id"`
+	OrgID        uuid.UUID  `json:"org_id"`
+	RunID        uuid.UUID  `json:"run_id"`
+	AgentID      string     `json:"agent_id"`
+	DecisionType string     `json:"decision_type"`
+	Outcome      string     `json:"outcome"`
+	Confidence   float32    `json:"confidence"`
+	Reasoning    *string    `json:"reasoning,omitempty"`
+	SupersedesID *uuid.UUID `json:"supersedes_id,omitempty"`
+	ContentHash  string     `json:"content_hash"`
+	ValidFrom    time.Time  `json:"valid_from"`
+	RecordedAt   time.Time  `json:"recorded_at"`
+}
+
+// NewRecord builds the mirrored record for d.
+func NewRecord(d model.Decision) Record {
+	return Record{
+		DecisionID:   d.ID,
+		OrgID:        d.OrgID,
+		RunID:        d.RunID,
+		AgentID:      d.AgentID,
+		DecisionType: d.DecisionType,
+		Outcome:      d.Outcome,
+		Confidence:   d.Confidence,
+		Reasoning:    d.Reasoning,
+		SupersedesID: d.SupersedesID,
+		ContentHash:  d.ContentHash,
+		ValidFrom:    d.ValidFrom,
+		RecordedAt:   time.Now().UTC(),
+	}
+}
+
+// Sink is an external append-only store for decision records.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+	Close() error
+}
+
+// Open returns the sink for rawURL: file:///path appends NDJSON to a local
+// file; http:// and https:// POST each record as JSON.
+func Open(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("auditsink: parse url: %w", err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, errors.New("auditsink: file url has no path")
+		}
+		return NewFileSink(u.Path)
+	case "http", "https":
+		return NewHTTPSink(rawURL), nil
+	default:
+		return nil, fmt.Errorf("auditsink: unsupported scheme %q (use file, http, or https)", u.Scheme)
+	}
+}
+
+// FileSink appends one JSON record per line to a file opened in append-only
+// mode, syncing after every record.
+type FileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileSink opens (creating if needed) path for appending.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) //nolint:gosec // path is operator configuration
+	if err != nil {
+		return nil, fmt.Errorf("auditsink: open file: %w", err)
+	}
+	return &FileSink{f: f}, nil
+}
+
+// Write appends r and syncs the file.
+func (s *FileSink) Write(_ context.Context, r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("auditsink: marshal record: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(line); err != nil {
+		return fmt.Errorf("auditsink: write file: %w", err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return fmt.Errorf("auditsink: sync file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// HTTPSink POSTs each record as a JSON body. Any non-2xx response is an error.
+type HTTPSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPSink creates a sink that posts to url.
+func NewHTTPSink(url string) *HTTPSink {
+	return &HTTPSink{url: url, httpClient: &http.Client{}}
+}
+
+// Write posts r to the sink URL. The caller's context bounds the request.
+func (s *HTTPSink) Write(ctx context.Context, r Record) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("auditsink: marshal record: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("auditsink: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", r.DecisionID.String())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("auditsink: post: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("auditsink: post: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close is a no-op.
+func (s *HTTPSink) Close() error { return nil }
+
+// asyncAttempts is how many times the async worker tries to deliver a record.
+const asyncAttempts = 5
+
+// Writer delivers records to a Sink in the configured mode.
+type Writer struct {
+	sink    Sink
+	mode    string
+	timeout time.Duration
+	logger  *slog.Logger
+
+	queue chan Record
+	done  chan struct{}
+	stop  context.CancelFunc
+
+	failures metric.Int64Counter // records that could not be delivered
+}
+
+// NewWriter wraps sink. timeout bounds each delivery attempt. In async mode
+// queueSize records may be pending; call Close to drain them.
+func NewWriter(sink Sink, mode string, timeout time.Duration, queueSize int, logger *slog.Logger) *Writer {
+	meter := telemetry.Meter("akashi/auditsink")
+	failures, _ := meter.Int64Counter("akashi.audit_sink.failures",
+		metric.WithDescription("Decision records that could not be delivered to the audit sink"),
+	)
+	w := &Writer{
+		sink:     sink,
+		mode:     mode,
+		timeout:  timeout,
+		logger:   logger,
+		failures: failures,
+	}
+	if mode == ModeAsync {
+		ctx, cancel := context.WithCancel(context.Background())
+		w.queue = make(chan Record, queueSize)
+		w.done = make(chan struct{})
+		w.stop = cancel
+		go w.run(ctx)
+	}
+	return w
+}
+
+// Blocking reports whether records are written synchronously.
+func (w *Writer) Blocking() bool { return w.mode == ModeBlock }
+
+// Mirror delivers the record for d. In block mode it returns the sink's
+// error; in async mode it only enqueues, dropping the record (and counting
+// a failure) if the queue is full.
+func (w *Writer) Mirror(ctx context.Context, d model.Decision) error {
+	r := NewRecord(d)
+	if w.Blocking() {
+		if err := w.write(ctx, r); err != nil {
+			w.failures.Add(ctx, 1)
+			return err
+		}
+		return nil
+	}
+	select {
+	case w.queue <- r:
+	default:
+		w.failures.Add(ctx, 1)
+		w.logger.Error("audit sink: queue full, decision record dropped", "decision_id", r.DecisionID)
+	}
+	return nil
+}
+
+func (w *Writer) write(ctx context.Context, r Record) error {
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+	return w.sink.Write(ctx, r)
+}
+
+// run delivers queued records until the queue is closed, retrying each with
+// exponential backoff. ctx is cancelled by Close when the drain times out.
+func (w *Writer) run(ctx context.Context) {
+	defer close(w.done)
+	for r := range w.queue {
+		backoff := 200 * time.Millisecond
+		var err error
+		for attempt := 1; attempt <= asyncAttempts; attempt++ {
+			if err = w.write(ctx, r); err == nil || ctx.Err() != nil {
+				break
+			}
+			if attempt < asyncAttempts {
+				select {
+				case <-time.After(backoff):
+				case <-ctx.Done():
+				}
+				backoff *= 2
+			}
+		}
+		if err != nil {
+			w.failures.Add(context.Background(), 1)
+			w.logger.Error("audit sink: decision record not delivered",
+				"decision_id", r.DecisionID, "error", err)
+		}
+	}
+}
+
+// Close drains queued records (async mode) until ctx expires, then closes
+// the sink. Mirror must not be called after Close.
+func (w *Writer) Close(ctx context.Context) error {
+	var drainErr error
+	if w.queue != nil {
+		close(w.queue)
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			w.stop()
+			<-w.done
+			drainErr = fmt.Errorf("auditsink: drain: %w", ctx.Err())
+		}
+		w.stop()
+	}
+	return errors.Join(drainErr, w.sink.Close())
+}
@@ -0,0 +1,183 @@
+package auditsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/integrity"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func testDecision() model.Decision {
+	reasoning := "cheaper at our scale"
+	d := model.Decision{
+		ID:           uuid.New(),
+		OrgID:        uuid.New(),
+		RunID:        uuid.New(),
+		AgentID:      "planner",
+		DecisionType: "architecture",
+		Outcome:      "use postgres",
+		Confidence:   0.8,
+		Reasoning:    &reasoning,
+		ValidFrom:    time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	d.ContentHash = integrity.ComputeContentHash(d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+	return d
+}
+
+func TestRecordReproducesContentHash(t *testing.T) {
+	d := testDecision()
+	r := NewRecord(d)
+	assert.Equal(t, d.ContentHash,
+		integrity.ComputeContentHash(r.DecisionID, r.DecisionType, r.Outcome, r.Confidence, r.Reasoning, r.ValidFrom))
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.ndjson")
+	s, err := Open("file://" + path)
+	require.NoError(t, err)
+	assert.IsType(t, &FileSink{}, s)
+	require.NoError(t, s.Close())
+
+	s, err = Open("https://siem.example.com/ingest")
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPSink{}, s)
+
+	_, err = Open("s3://bucket/key")
+	assert.Error(t, err)
+}
+
+func TestFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.ndjson")
+	s, err := NewFileSink(path)
+	require.NoError(t, err)
+	d1, d2 := testDecision(), testDecision()
+	require.NoError(t, s.Write(context.Background(), NewRecord(d1)))
+	require.NoError(t, s.Write(context.Background(), NewRecord(d2)))
+	require.NoError(t, s.Close())
+
+	// Reopening appends rather than truncating.
+	s, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Write(context.Background(), NewRecord(d1)))
+	require.NoError(t, s.Close())
+
+	f, err := os.Open(path) //nolint:gosec // test temp file
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	var ids []uuid.UUID
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r Record
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &r))
+		ids = append(ids, r.DecisionID)
+	}
+	assert.Equal(t, []uuid.UUID{d1.ID, d2.ID, d1.ID}, ids)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got Record
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	d := testDecision()
+	s := NewHTTPSink(srv.URL)
+	require.NoError(t, s.Write(context.Background(), NewRecord(d)))
+	assert.Equal(t, d.ID, got.DecisionID)
+	assert.Equal(t, d.ContentHash, got.ContentHash)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, s.Write(context.Background(), NewRecord(d)))
+}
+
+// memSink records writes and fails while failing is set.
+type memSink struct {
+	mu      sync.Mutex
+	records []Record
+	failing bool
+	closed  bool
+}
+
+func (m *memSink) Write(_ context.Context, r Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failing {
+		return errors.New("sink down")
+	}
+	m.records = append(m.records, r)
+	return nil
+}
+
+func (m *memSink) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
+
+func (m *memSink) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.records)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+func TestWriterBlockReturnsSinkError(t *testing.T) {
+	sink := &memSink{failing: true}
+	w := NewWriter(sink, ModeBlock, time.Second, 1, testLogger())
+	assert.True(t, w.Blocking())
+	assert.Error(t, w.Mirror(context.Background(), testDecision()))
+
+	sink.failing = false
+	require.NoError(t, w.Mirror(context.Background(), testDecision()))
+	assert.Equal(t, 1, sink.count())
+	require.NoError(t, w.Close(context.Background()))
+	assert.True(t, sink.closed)
+}
+
+func TestWriterAsyncDrainsOnClose(t *testing.T) {
+	sink := &memSink{}
+	w := NewWriter(sink, ModeAsync, time.Second, 100, testLogger())
+	assert.False(t, w.Blocking())
+	for range 10 {
+		require.NoError(t, w.Mirror(context.Background(), testDecision()))
+	}
+	require.NoError(t, w.Close(context.Background()))
+	assert.Equal(t, 10, sink.count())
+	assert.True(t, sink.closed)
+}
+
+func TestWriterAsyncCloseTimesOut(t *testing.T) {
+	sink := &memSink{failing: true}
+	w := NewWriter(sink, ModeAsync, time.Second, 10, testLogger())
+	require.NoError(t, w.Mirror(context.Background(), testDecision()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := w.Close(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, sink.count())
+}
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"log/slog"
@@ -29,8 +31,26 @@ type Claims struct {
 	Role     model.AgentRole `json:"role"`
 	APIKeyID *uuid.UUID      `json:"api_key_id,omitempty"` // Set when authenticated via a managed API key.
 	ScopedBy string          `json:"scoped_by,omitempty"`  // Set when issued via POST /auth/scoped-token; contains the issuing admin's agent_id.
+	// Namespace pins the token to a single decision namespace. Empty means the
+	// caller may choose one per request via the X-Akashi-Namespace header.
+	Namespace string `json:"namespace,omitempty"`
+	// TokenType distinguishes refresh tokens from access tokens. Empty means
+	// access, which keeps tokens issued before the claim existed valid.
+	TokenType TokenType `json:"token_type,omitempty"`
 }
 
+// TokenType is the kind of JWT a JWTManager issues.
+type TokenType string
+
+const (
+	// TokenTypeAccess authenticates API requests.
+	TokenTypeAccess TokenType = "access"
+	// TokenTypeRefresh can only be exchanged at POST /auth/refresh for a new
+	// access token. Its ID claim is the refresh_tokens row that tracks
+	// revocation.
+	TokenTypeRefresh TokenType = "refresh"
+)
+
 // ActorID returns the best available identity for the authenticated caller.
 // It prefers AgentID (set on API-key auth) and falls back to Subject (set on JWT auth).
 func (c *Claims) ActorID() string {
@@ -43,11 +63,15 @@ func (c *Claims) ActorID() string {
 // MaxScopedTokenTTL is the maximum lifetime of a scoped token.
 const MaxScopedTokenTTL = time.Hour
 
+// DefaultRefreshTokenTTL is the refresh token lifetime when none is configured.
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
 // JWTManager handles JWT creation and validation using Ed25519.
 type JWTManager struct {
-	privateKey ed25519.PrivateKey
-	publicKey  ed25519.PublicKey
-	expiration time.Duration
+	privateKey        ed25519.PrivateKey
+	publicKey         ed25519.PublicKey
+	expiration        time.Duration
+	refreshExpiration time.Duration
 }
 
 // NewJWTManager creates a JWTManager from PEM key files.
@@ -59,7 +83,7 @@ func NewJWTManager(privateKeyPath, publicKeyPath string, expiration time.Duratio
 		if err != nil {
 			return nil, fmt.Errorf("auth: generate key pair: %w", err)
 		}
-		return &JWTManager{privateKey: priv, publicKey: pub, expiration: expiration}, nil
+		return &JWTManager{privateKey: priv, publicKey: pub, expiration: expiration, refreshExpiration: DefaultRefreshTokenTTL}, nil
 	}
 
 	privPEM, err := os.ReadFile(privateKeyPath) //nolint:gosec // paths come from validated config, not user input
@@ -103,7 +127,16 @@ func NewJWTManager(privateKeyPath, publicKeyPath string, expiration time.Duratio
 		return nil, fmt.Errorf("auth: public key does not match private key")
 	}
 
-	return &JWTManager{privateKey: edPriv, publicKey: edPub, expiration: expiration}, nil
+	return &JWTManager{privateKey: edPriv, publicKey: edPub, expiration: expiration, refreshExpiration: DefaultRefreshTokenTTL}, nil
+}
+
+// WithRefreshExpiration sets the refresh token lifetime. Non-positive values
+// are ignored and leave DefaultRefreshTokenTTL in place.
+func (m *JWTManager) WithRefreshExpiration(d time.Duration) *JWTManager {
+	if d > 0 {
+		m.refreshExpiration = d
+	}
+	return m
 }
 
 // IssueToken creates a signed JWT for the given agent.
@@ -120,9 +153,10 @@ func (m *JWTManager) IssueToken(agent model.Agent) (string, time.Time, error) {
 			ExpiresAt: jwt.NewNumericDate(exp),
 			ID:        uuid.New().String(),
 		},
-		AgentID: agent.AgentID,
-		OrgID:   agent.OrgID,
-		Role:    agent.Role,
+		AgentID:   agent.AgentID,
+		OrgID:     agent.OrgID,
+		Role:      agent.Role,
+		TokenType: TokenTypeAccess,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
@@ -133,10 +167,50 @@ func (m *JWTManager) IssueToken(agent model.Agent) (string, time.Time, error) {
 	return signed, exp, nil
 }
 
+// IssueRefreshToken creates a signed refresh token for the given agent. The
+// returned ID is the token's ID claim; callers store it with
+// HashRefreshToken(token) so the token can be rotated and revoked.
+func (m *JWTManager) IssueRefreshToken(agent model.Agent) (string, uuid.UUID, time.Time, error) {
+	now := time.Now().UTC()
+	exp := now.Add(m.refreshExpiration)
+	id := uuid.New()
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   agent.ID.String(),
+			Issuer:    "akashi",
+			Audience:  jwt.ClaimStrings{"akashi"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+			ID:        id.String(),
+		},
+		AgentID:   agent.AgentID,
+		OrgID:     agent.OrgID,
+		Role:      agent.Role,
+		TokenType: TokenTypeRefresh,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	signed, err := token.SignedString(m.privateKey)
+	if err != nil {
+		return "", uuid.Nil, time.Time{}, fmt.Errorf("auth: sign refresh token: %w", err)
+	}
+	return signed, id, exp, nil
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh token, the form in
+// which refresh tokens are stored. Refresh tokens are high-entropy signed
+// JWTs, so a fast hash is sufficient.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // IssueScopedToken issues a short-lived token that acts as targetAgent but
 // carries the issuing admin's agent_id in the ScopedBy claim. TTL is capped
-// at MaxScopedTokenTTL regardless of the requested value.
-func (m *JWTManager) IssueScopedToken(issuingAdminAgentID string, target model.Agent, ttl time.Duration) (string, time.Time, error) {
+// at MaxScopedTokenTTL regardless of the requested value. A non-empty
+// namespace pins the token to that decision namespace.
+func (m *JWTManager) IssueScopedToken(issuingAdminAgentID string, target model.Agent, ttl time.Duration, namespace string) (string, time.Time, error) {
 	if ttl <= 0 || ttl > MaxScopedTokenTTL {
 		ttl = MaxScopedTokenTTL
 	}
@@ -153,10 +227,12 @@ func (m *JWTManager) IssueScopedToken(issuingAdminAgentID string, target model.A
 			ExpiresAt: jwt.NewNumericDate(exp),
 			ID:        uuid.New().String(),
 		},
-		AgentID:  target.AgentID,
-		OrgID:    target.OrgID,
-		Role:     target.Role,
-		ScopedBy: issuingAdminAgentID,
+		AgentID:   target.AgentID,
+		OrgID:     target.OrgID,
+		Role:      target.Role,
+		ScopedBy:  issuingAdminAgentID,
+		Namespace: namespace,
+		TokenType: TokenTypeAccess,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
@@ -167,8 +243,37 @@ func (m *JWTManager) IssueScopedToken(issuingAdminAgentID string, target model.A
 	return signed, exp, nil
 }
 
-// ValidateToken parses and validates a JWT, returning the claims.
+// ValidateToken parses and validates an access token, returning the claims.
+// Refresh tokens are rejected.
 func (m *JWTManager) ValidateToken(tokenStr string) (*Claims, error) {
+	claims, err := m.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != "" && claims.TokenType != TokenTypeAccess {
+		return nil, fmt.Errorf("auth: %s token cannot authenticate requests", claims.TokenType)
+	}
+	return claims, nil
+}
+
+// ValidateRefreshToken parses and validates a refresh token, returning the
+// claims. It checks only the signature and expiry; whether the token has
+// been rotated or revoked is recorded in storage.
+func (m *JWTManager) ValidateRefreshToken(tokenStr string) (*Claims, error) {
+	claims, err := m.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, fmt.Errorf("auth: not a refresh token")
+	}
+	if _, err := uuid.Parse(claims.ID); err != nil {
+		return nil, fmt.Errorf("auth: invalid refresh token id: %w", err)
+	}
+	return claims, nil
+}
+
+func (m *JWTManager) parse(tokenStr string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(
 		tokenStr,
 		&Claims{},
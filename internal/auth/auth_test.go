@@ -6,9 +6,11 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
 
 	"github.com/ashita-ai/akashi/internal/auth"
 	"github.com/ashita-ai/akashi/internal/model"
@@ -200,7 +203,7 @@ func TestIssueScopedToken(t *testing.T) {
 	}
 
 	t.Run("claims carry target identity and scoped_by", func(t *testing.T) {
-		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 5*time.Minute)
+		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 5*time.Minute, "")
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 		assert.True(t, expiresAt.After(time.Now()))
@@ -215,7 +218,7 @@ func TestIssueScopedToken(t *testing.T) {
 	})
 
 	t.Run("TTL is capped at MaxScopedTokenTTL", func(t *testing.T) {
-		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 48*time.Hour)
+		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 48*time.Hour, "")
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 		// Should expire within MaxScopedTokenTTL, not 48 hours.
@@ -224,19 +227,28 @@ func TestIssueScopedToken(t *testing.T) {
 	})
 
 	t.Run("zero TTL defaults to MaxScopedTokenTTL", func(t *testing.T) {
-		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 0)
+		token, expiresAt, err := mgr.IssueScopedToken(admin.AgentID, target, 0, "")
 		require.NoError(t, err)
 		assert.NotEmpty(t, token)
 		assert.True(t, expiresAt.After(time.Now()))
 	})
 
 	t.Run("token is valid and passes ValidateToken", func(t *testing.T) {
-		token, _, err := mgr.IssueScopedToken(admin.AgentID, target, 5*time.Minute)
+		token, _, err := mgr.IssueScopedToken(admin.AgentID, target, 5*time.Minute, "")
 		require.NoError(t, err)
 		claims, err := mgr.ValidateToken(token)
 		require.NoError(t, err)
 		assert.Equal(t, target.ID.String(), claims.Subject)
 		assert.Equal(t, "akashi", claims.Issuer)
+		assert.Empty(t, claims.Namespace)
+	})
+
+	t.Run("namespace is carried in the claims", func(t *testing.T) {
+		token, _, err := mgr.IssueScopedToken(admin.AgentID, target, 5*time.Minute, "staging")
+		require.NoError(t, err)
+		claims, err := mgr.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "staging", claims.Namespace)
 	})
 }
 
@@ -432,6 +444,113 @@ func TestDummyVerify(t *testing.T) {
 	auth.DummyVerify()
 }
 
+// ---------- Hash parameters and rehash-on-verify ----------
+
+// setHashParams switches the package hash parameters for one test.
+func setHashParams(t *testing.T, p auth.HashParams) {
+	t.Helper()
+	require.NoError(t, auth.SetHashParams(p))
+	t.Cleanup(func() { require.NoError(t, auth.SetHashParams(auth.DefaultHashParams())) })
+}
+
+func TestHashAPIKey_EncodesParams(t *testing.T) {
+	hash, err := auth.HashAPIKey("test-key-123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=65536,t=1,p=4$"), hash)
+	assert.False(t, auth.NeedsRehash(hash), "hash with current params needs no rehash")
+}
+
+func TestVerifyAPIKey_LegacyFormat(t *testing.T) {
+	// Hash in the original salt$hash format, produced with the legacy
+	// parameters (t=1, m=64MB, p=4) that were never stored with the hash.
+	salt := []byte("0123456789abcdef")
+	digest := argon2.IDKey([]byte("legacy-key"), salt, 1, 64*1024, 4, 32)
+	legacy := base64.StdEncoding.EncodeToString(salt) + "$" + base64.StdEncoding.EncodeToString(digest)
+
+	valid, err := auth.VerifyAPIKey("legacy-key", legacy)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = auth.VerifyAPIKey("wrong-key", legacy)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	assert.True(t, auth.NeedsRehash(legacy), "legacy hashes are upgraded to the self-describing format")
+}
+
+func TestVerifyAPIKey_OldParamsAfterCostIncrease(t *testing.T) {
+	setHashParams(t, auth.HashParams{Algorithm: auth.HashAlgorithmArgon2id, Time: 1, MemoryKiB: 1024, Threads: 1})
+	old, err := auth.HashAPIKey("rotating-key")
+	require.NoError(t, err)
+
+	setHashParams(t, auth.HashParams{Algorithm: auth.HashAlgorithmArgon2id, Time: 2, MemoryKiB: 2048, Threads: 2})
+	valid, err := auth.VerifyAPIKey("rotating-key", old)
+	require.NoError(t, err)
+	assert.True(t, valid, "verification uses the params stored with the hash")
+	assert.True(t, auth.NeedsRehash(old))
+
+	// Rehash-on-verify: the new hash uses the current params and still verifies.
+	upgraded, err := auth.HashAPIKey("rotating-key")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(upgraded, "$argon2id$v=19$m=2048,t=2,p=2$"), upgraded)
+	assert.False(t, auth.NeedsRehash(upgraded))
+	valid, err = auth.VerifyAPIKey("rotating-key", upgraded)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestHashAPIKey_Bcrypt(t *testing.T) {
+	argonHash, err := auth.HashAPIKey("switching-key")
+	require.NoError(t, err)
+
+	setHashParams(t, auth.HashParams{Algorithm: auth.HashAlgorithmBcrypt, BcryptCost: 4})
+	assert.True(t, auth.NeedsRehash(argonHash), "algorithm change requires rehash")
+
+	// Managed keys exceed bcrypt's 72-byte input limit.
+	rawKey, _, err := model.GenerateRawKey()
+	require.NoError(t, err)
+	hash, err := auth.HashAPIKey(rawKey)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"), hash)
+	assert.False(t, auth.NeedsRehash(hash))
+
+	valid, err := auth.VerifyAPIKey(rawKey, hash)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = auth.VerifyAPIKey(rawKey+"x", hash)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	// Argon2id hashes still verify after switching to bcrypt.
+	valid, err = auth.VerifyAPIKey("switching-key", argonHash)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	setHashParams(t, auth.HashParams{Algorithm: auth.HashAlgorithmBcrypt, BcryptCost: 5})
+	assert.True(t, auth.NeedsRehash(hash), "bcrypt cost increase requires rehash")
+	auth.DummyVerify()
+}
+
+func TestSetHashParams_Invalid(t *testing.T) {
+	for _, p := range []auth.HashParams{
+		{Algorithm: "scrypt"},
+		{Algorithm: auth.HashAlgorithmArgon2id, Time: 0, MemoryKiB: 1024, Threads: 1},
+		{Algorithm: auth.HashAlgorithmArgon2id, Time: 1, MemoryKiB: 16, Threads: 4},
+		{Algorithm: auth.HashAlgorithmBcrypt, BcryptCost: 32},
+	} {
+		assert.Error(t, auth.SetHashParams(p), "%+v", p)
+	}
+}
+
+func TestVerifyAPIKey_InvalidPHCFormat(t *testing.T) {
+	_, err := auth.VerifyAPIKey("some-key", "$argon2id$v=19$m=65536,t=1,p=4$onlysalt")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid hash format")
+
+	_, err = auth.VerifyAPIKey("some-key", "$argon2id$v=19$m=99999999,t=1,p=4$c2FsdA$aGFzaA")
+	require.Error(t, err, "oversized memory parameter must be rejected before hashing")
+}
+
 // ---------- ValidateToken: expired token ----------
 
 func TestValidateToken_ExpiredToken(t *testing.T) {
@@ -477,7 +596,7 @@ func TestIssueScopedToken_NegativeTTL(t *testing.T) {
 		Role:    model.RoleAgent,
 	}
 
-	token, expiresAt, err := mgr.IssueScopedToken("admin", target, -5*time.Minute)
+	token, expiresAt, err := mgr.IssueScopedToken("admin", target, -5*time.Minute, "")
 	require.NoError(t, err)
 	assert.NotEmpty(t, token)
 	// Negative TTL should default to MaxScopedTokenTTL.
@@ -507,3 +626,50 @@ func TestValidateToken_MalformedSubject(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid subject")
 }
+
+// ---------- Refresh tokens ----------
+
+func TestIssueRefreshToken_TypesAreDistinct(t *testing.T) {
+	mgr, err := auth.NewJWTManager("", "", 15*time.Minute)
+	require.NoError(t, err)
+	mgr.WithRefreshExpiration(48 * time.Hour)
+
+	agent := model.Agent{ID: uuid.New(), AgentID: "refresh-agent", OrgID: uuid.New(), Role: model.RoleReader}
+
+	refresh, id, exp, err := mgr.IssueRefreshToken(agent)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), exp, time.Minute)
+
+	claims, err := mgr.ValidateRefreshToken(refresh)
+	require.NoError(t, err)
+	assert.Equal(t, auth.TokenTypeRefresh, claims.TokenType)
+	assert.Equal(t, id.String(), claims.ID)
+	assert.Equal(t, "refresh-agent", claims.AgentID)
+	assert.Equal(t, agent.OrgID, claims.OrgID)
+
+	_, err = mgr.ValidateToken(refresh)
+	require.Error(t, err, "refresh tokens must not authenticate API requests")
+
+	access, _, err := mgr.IssueToken(agent)
+	require.NoError(t, err)
+	_, err = mgr.ValidateRefreshToken(access)
+	require.Error(t, err, "access tokens must not be exchangeable for new tokens")
+}
+
+func TestValidateRefreshToken_Expired(t *testing.T) {
+	mgr, err := auth.NewJWTManager("", "", time.Hour)
+	require.NoError(t, err)
+	mgr.WithRefreshExpiration(time.Nanosecond)
+
+	refresh, _, _, err := mgr.IssueRefreshToken(model.Agent{ID: uuid.New(), AgentID: "expiring-refresh"})
+	require.NoError(t, err)
+	_, err = mgr.ValidateRefreshToken(refresh)
+	require.Error(t, err)
+}
+
+func TestHashRefreshToken(t *testing.T) {
+	h := auth.HashRefreshToken("token-a")
+	assert.Len(t, h, 64)
+	assert.Equal(t, h, auth.HashRefreshToken("token-a"))
+	assert.NotEqual(t, h, auth.HashRefreshToken("token-b"))
+}
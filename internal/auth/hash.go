@@ -2,63 +2,261 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// Hash algorithms accepted by SetHashParams.
 const (
+	HashAlgorithmArgon2id = "argon2id"
+	HashAlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	// Legacy cost parameters. Hashes in the original "salt$hash" format carry
+	// no parameters and were all produced with these values.
 	argonTime    = 1
 	argonMemory  = 64 * 1024 // 64 MB
 	argonThreads = 4
 	argonKeyLen  = 32
 	saltLen      = 16
+
+	// maxArgonMemoryKiB bounds the memory a stored hash can make Verify
+	// allocate (4 GB).
+	maxArgonMemoryKiB = 4 * 1024 * 1024
 )
 
-// HashAPIKey hashes an API key using Argon2id.
+// HashParams selects the algorithm and cost used to hash new API keys. The
+// parameters are encoded into every hash, so verification always uses the
+// values a key was hashed with, whatever the current settings are.
+type HashParams struct {
+	Algorithm  string // HashAlgorithmArgon2id or HashAlgorithmBcrypt.
+	Time       uint32 // Argon2id passes over memory.
+	MemoryKiB  uint32 // Argon2id memory in KiB.
+	Threads    uint8  // Argon2id parallelism.
+	BcryptCost int    // bcrypt work factor (log2 rounds).
+}
+
+// DefaultHashParams returns Argon2id with the cost parameters API keys have
+// always been hashed with.
+func DefaultHashParams() HashParams {
+	return HashParams{
+		Algorithm:  HashAlgorithmArgon2id,
+		Time:       argonTime,
+		MemoryKiB:  argonMemory,
+		Threads:    argonThreads,
+		BcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+// Validate checks that p names a supported algorithm with usable costs.
+func (p HashParams) Validate() error {
+	switch p.Algorithm {
+	case HashAlgorithmArgon2id:
+		if p.Time < 1 {
+			return errors.New("auth: argon2id time must be >= 1")
+		}
+		if p.Threads < 1 {
+			return errors.New("auth: argon2id threads must be >= 1")
+		}
+		if p.MemoryKiB < 8*uint32(p.Threads) || p.MemoryKiB > maxArgonMemoryKiB {
+			return fmt.Errorf("auth: argon2id memory must be between 8 KiB per thread and %d KiB (got %d KiB for %d threads)", maxArgonMemoryKiB, p.MemoryKiB, p.Threads)
+		}
+	case HashAlgorithmBcrypt:
+		if p.BcryptCost < bcrypt.MinCost || p.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("auth: bcrypt cost must be between %d and %d (got %d)", bcrypt.MinCost, bcrypt.MaxCost, p.BcryptCost)
+		}
+	default:
+		return fmt.Errorf("auth: unsupported hash algorithm %q", p.Algorithm)
+	}
+	return nil
+}
+
+var currentHashParams atomic.Pointer[HashParams]
+
+// SetHashParams sets the parameters HashAPIKey uses for new hashes and that
+// NeedsRehash compares stored hashes against. Call once at startup.
+func SetHashParams(p HashParams) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	currentHashParams.Store(&p)
+	return nil
+}
+
+func hashParams() HashParams {
+	if p := currentHashParams.Load(); p != nil {
+		return *p
+	}
+	return DefaultHashParams()
+}
+
+// HashAPIKey hashes an API key with the current hash parameters. Argon2id
+// hashes use the PHC string format ($argon2id$v=19$m=...,t=...,p=...$salt$hash);
+// bcrypt hashes use the standard $2a$ format.
 func HashAPIKey(apiKey string) (string, error) {
+	p := hashParams()
+	if p.Algorithm == HashAlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword(bcryptInput(apiKey), p.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("auth: bcrypt hash: %w", err)
+		}
+		return string(hash), nil
+	}
+
 	salt := make([]byte, saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("auth: generate salt: %w", err)
 	}
 
-	hash := argon2.IDKey([]byte(apiKey), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	hash := argon2.IDKey([]byte(apiKey), salt, p.Time, p.MemoryKiB, p.Threads, argonKeyLen)
 
-	encoded := fmt.Sprintf("%s$%s",
-		base64.StdEncoding.EncodeToString(salt),
-		base64.StdEncoding.EncodeToString(hash),
+	encoded := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.MemoryKiB, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
 	)
 	return encoded, nil
 }
 
-// DummyVerify performs an Argon2id hash with the same cost parameters as real
+// bcryptInput pre-hashes an API key for bcrypt, which ignores input past 72
+// bytes; managed keys are longer than that.
+func bcryptInput(apiKey string) []byte {
+	sum := sha256.Sum256([]byte(apiKey))
+	return []byte(base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// dummyBcryptHash is a bcrypt hash of a fixed input, used by DummyVerify.
+var dummyBcryptHash atomic.Pointer[[]byte]
+
+// DummyVerify performs a hash with the same cost parameters as real
 // verification. Call this on auth failure paths where no real hash was checked,
 // so that response timing does not reveal whether an agent_id exists.
 func DummyVerify() {
-	argon2.IDKey([]byte("dummy"), make([]byte, saltLen), argonTime, argonMemory, argonThreads, argonKeyLen)
+	p := hashParams()
+	if p.Algorithm == HashAlgorithmBcrypt {
+		if h := dummyBcryptHash.Load(); h != nil {
+			if cost, err := bcrypt.Cost(*h); err == nil && cost == p.BcryptCost {
+				_ = bcrypt.CompareHashAndPassword(*h, bcryptInput("dummy"))
+				return
+			}
+		}
+		// Generating costs the same as comparing, so the first call is no faster.
+		h, err := bcrypt.GenerateFromPassword(bcryptInput("dummy"), p.BcryptCost)
+		if err == nil {
+			dummyBcryptHash.Store(&h)
+		}
+		return
+	}
+	argon2.IDKey([]byte("dummy"), make([]byte, saltLen), p.Time, p.MemoryKiB, p.Threads, argonKeyLen)
 }
 
-// VerifyAPIKey checks an API key against an Argon2id hash.
+// VerifyAPIKey checks an API key against a hash produced by HashAPIKey, using
+// the algorithm and parameters recorded in the hash. Hashes in the legacy
+// "salt$hash" format are verified with the legacy Argon2id parameters.
 func VerifyAPIKey(apiKey, encoded string) (bool, error) {
+	if isBcryptHash(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), bcryptInput(apiKey))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("auth: bcrypt verify: %w", err)
+		}
+		return true, nil
+	}
+
+	p, salt, expectedHash, err := decodeArgon2Hash(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	computedHash := argon2.IDKey([]byte(apiKey), salt, p.Time, p.MemoryKiB, p.Threads, argonKeyLen)
+
+	return subtle.ConstantTimeCompare(expectedHash, computedHash) == 1, nil
+}
+
+// NeedsRehash reports whether a stored hash was produced with an algorithm or
+// parameters other than the current ones. Callers re-hash the key after a
+// successful VerifyAPIKey so stored hashes follow cost increases.
+func NeedsRehash(encoded string) bool {
+	cur := hashParams()
+	if isBcryptHash(encoded) {
+		if cur.Algorithm != HashAlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != cur.BcryptCost
+	}
+	if cur.Algorithm != HashAlgorithmArgon2id || !strings.HasPrefix(encoded, "$argon2id$") {
+		return true
+	}
+	p, _, _, err := decodeArgon2Hash(encoded)
+	return err != nil || p.Time != cur.Time || p.MemoryKiB != cur.MemoryKiB || p.Threads != cur.Threads
+}
+
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// decodeArgon2Hash parses a PHC-format Argon2id hash or a legacy
+// "salt$hash" one into its parameters, salt, and digest.
+func decodeArgon2Hash(encoded string) (HashParams, []byte, []byte, error) {
+	if !strings.HasPrefix(encoded, "$argon2id$") {
+		return decodeLegacyHash(encoded)
+	}
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return HashParams{}, nil, nil, fmt.Errorf("auth: invalid hash format")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return HashParams{}, nil, nil, fmt.Errorf("auth: unsupported argon2 version %q", parts[2])
+	}
+	p := HashParams{Algorithm: HashAlgorithmArgon2id}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.MemoryKiB, &p.Time, &p.Threads); err != nil {
+		return HashParams{}, nil, nil, fmt.Errorf("auth: decode argon2 params: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return HashParams{}, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return HashParams{}, nil, nil, fmt.Errorf("auth: decode salt: %w", err)
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return HashParams{}, nil, nil, fmt.Errorf("auth: decode hash: %w", err)
+	}
+	return p, salt, hash, nil
+}
+
+func decodeLegacyHash(encoded string) (HashParams, []byte, []byte, error) {
 	parts := strings.SplitN(encoded, "$", 2)
 	if len(parts) != 2 {
-		return false, fmt.Errorf("auth: invalid hash format")
+		return HashParams{}, nil, nil, fmt.Errorf("auth: invalid hash format")
 	}
 
 	salt, err := base64.StdEncoding.DecodeString(parts[0])
 	if err != nil {
-		return false, fmt.Errorf("auth: decode salt: %w", err)
+		return HashParams{}, nil, nil, fmt.Errorf("auth: decode salt: %w", err)
 	}
 
 	expectedHash, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return false, fmt.Errorf("auth: decode hash: %w", err)
+		return HashParams{}, nil, nil, fmt.Errorf("auth: decode hash: %w", err)
 	}
 
-	computedHash := argon2.IDKey([]byte(apiKey), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
-
-	return subtle.ConstantTimeCompare(expectedHash, computedHash) == 1, nil
+	return DefaultHashParams(), salt, expectedHash, nil
 }
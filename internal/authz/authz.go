@@ -10,6 +10,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -179,6 +181,61 @@ func FilterDecisions(ctx context.Context, db storage.Store, claims *auth.Claims,
 	return allowed, nil
 }
 
+// FilterAgentIDs removes agent_ids the caller is not authorized to read, so
+// hints such as agent_id suggestions never reveal agents the caller cannot
+// otherwise see. cache may be nil to disable caching.
+func FilterAgentIDs(ctx context.Context, db storage.Store, claims *auth.Claims, agentIDs []string, cache *GrantCache) ([]string, error) {
+	granted, err := LoadGrantedSet(ctx, db, claims, cache)
+	if err != nil {
+		return nil, err
+	}
+	if granted == nil {
+		return agentIDs, nil
+	}
+
+	var allowed []string
+	for _, id := range agentIDs {
+		if granted[id] {
+			allowed = append(allowed, id)
+		}
+	}
+	return allowed, nil
+}
+
+// ScopeFiltersToGranted narrows filters.AgentIDs to the agents the caller may
+// read, so searches rank only within the caller's granted set instead of
+// ranking the whole org and filtering afterwards (which would leak other
+// agents' content through truncated result counts). An existing AgentIDs
+// filter is intersected with the granted set. Admin+ callers are left
+// unrestricted. Returns false when the caller can see none of the requested
+// agents; the search should then return no results without running.
+// cache may be nil to disable caching.
+func ScopeFiltersToGranted(ctx context.Context, db storage.Store, claims *auth.Claims, filters *model.QueryFilters, cache *GrantCache) (bool, error) {
+	granted, err := LoadGrantedSet(ctx, db, claims, cache)
+	if err != nil {
+		return false, err
+	}
+	if granted == nil {
+		return true, nil
+	}
+
+	var ids []string
+	if len(filters.AgentIDs) > 0 {
+		for _, id := range filters.AgentIDs {
+			if granted[id] {
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		for id := range granted {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+	}
+	filters.AgentIDs = ids
+	return len(ids) > 0, nil
+}
+
 // FilterSearchResults removes search results the caller is not authorized to see.
 // cache may be nil to disable caching.
 func FilterSearchResults(ctx context.Context, db storage.Store, claims *auth.Claims, results []model.SearchResult, cache *GrantCache) ([]model.SearchResult, error) {
@@ -220,6 +277,48 @@ func FilterConflicts(ctx context.Context, db storage.Store, claims *auth.Claims,
 	return allowed, nil
 }
 
+// RedactConflictCounterparts blanks the side of each conflict opposite
+// decisionID when the caller cannot access that side's agent, instead of
+// dropping the conflict as FilterConflicts does. The caller must already be
+// allowed to see decisionID. Only that side's decision ID is kept; fields
+// that quote or identify it (explanation, suggestion, resolution note, and
+// consensus_drift minority IDs) are blanked too. cache may be nil to disable
+// caching.
+func RedactConflictCounterparts(ctx context.Context, db storage.Store, claims *auth.Claims, decisionID uuid.UUID, conflicts []model.DecisionConflict, cache *GrantCache) ([]model.DecisionConflict, error) {
+	granted, err := LoadGrantedSet(ctx, db, claims, cache)
+	if err != nil {
+		return nil, err
+	}
+	if granted == nil {
+		return conflicts, nil
+	}
+
+	for i := range conflicts {
+		c := &conflicts[i]
+		if c.DecisionAID == decisionID {
+			if granted[c.AgentB] {
+				continue
+			}
+			c.AgentB, c.RunB, c.DecisionTypeB, c.OutcomeB, c.ConfidenceB = "", uuid.Nil, "", "", 0
+			c.ReasoningB, c.DecidedAtB, c.ClaimTextB, c.ProjectB = nil, time.Time{}, nil, nil
+		} else {
+			if granted[c.AgentA] {
+				continue
+			}
+			c.AgentA, c.RunA, c.DecisionTypeA, c.OutcomeA, c.ConfidenceA = "", uuid.Nil, "", "", 0
+			c.ReasoningA, c.DecidedAtA, c.ClaimTextA, c.ProjectA = nil, time.Time{}, nil, nil
+			// DecisionType mirrors side A; report the visible side's type.
+			c.DecisionType = c.DecisionTypeB
+		}
+		c.Explanation = nil
+		c.Suggestion = nil
+		c.ResolutionNote = nil
+		c.MinorityIDs = nil
+		c.CounterpartRedacted = true
+	}
+	return conflicts, nil
+}
+
 // FilterConflictGroups removes conflict groups the caller cannot see.
 // A caller must have access to BOTH agents in the group (same rule as conflicts).
 // cache may be nil to disable caching.
@@ -268,3 +367,26 @@ func FilterLineage(ctx context.Context, db storage.Store, claims *auth.Claims, l
 
 	return lineage, nil
 }
+
+// FilterAncestry removes ancestors the caller cannot see and recomputes the
+// rolled-up stale counters so they only reflect visible decisions.
+func FilterAncestry(ctx context.Context, db storage.Store, claims *auth.Claims, health storage.DecisionAncestryHealth, cache *GrantCache) (storage.DecisionAncestryHealth, error) {
+	granted, err := LoadGrantedSet(ctx, db, claims, cache)
+	if err != nil {
+		return health, err
+	}
+	if granted == nil {
+		return health, nil
+	}
+
+	allowed := make([]storage.AncestryEntry, 0, len(health.Ancestors))
+	for _, a := range health.Ancestors {
+		if granted[a.AgentID] {
+			allowed = append(allowed, a)
+		}
+	}
+	health.Ancestors = allowed
+	health.Summarize()
+
+	return health, nil
+}
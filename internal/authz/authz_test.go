@@ -251,6 +251,37 @@ func TestFilterSearchResults_Filtered(t *testing.T) {
 	assert.Equal(t, agent.AgentID, filtered[0].Decision.AgentID)
 }
 
+func TestScopeFiltersToGranted(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	agent := createTestAgent(t, "scope-"+suffix, model.RoleAgent, nil)
+	claims := makeClaims(agent.AgentID, agent.ID, model.RoleAgent)
+	ctx := context.Background()
+
+	var f model.QueryFilters
+	ok, err := authz.ScopeFiltersToGranted(ctx, testDB, claims, &f, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{agent.AgentID}, f.AgentIDs, "empty filter is narrowed to the granted set")
+
+	f = model.QueryFilters{AgentIDs: []string{"other-" + suffix, agent.AgentID}}
+	ok, err = authz.ScopeFiltersToGranted(ctx, testDB, claims, &f, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{agent.AgentID}, f.AgentIDs, "requested agents are intersected with the granted set")
+
+	f = model.QueryFilters{AgentIDs: []string{"other-" + suffix}}
+	ok, err = authz.ScopeFiltersToGranted(ctx, testDB, claims, &f, nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "no accessible agents left")
+
+	admin := makeClaims("admin-"+suffix, uuid.New(), model.RoleAdmin)
+	f = model.QueryFilters{}
+	ok, err = authz.ScopeFiltersToGranted(ctx, testDB, admin, &f, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, f.AgentIDs, "admin search stays org-wide")
+}
+
 func TestCanAccessAgent_NilClaims(t *testing.T) {
 	ok, err := authz.CanAccessAgent(context.Background(), testDB, nil, "any-agent")
 	require.NoError(t, err)
@@ -5,6 +5,8 @@ package authz_test
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -372,6 +374,87 @@ func TestFilterConflicts_ReaderNeedsAccessToBothAgents(t *testing.T) {
 	assert.Equal(t, agentA.AgentID, filtered[0].AgentB)
 }
 
+func TestRedactConflictCounterparts_HidesInaccessibleSide(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	reader := createTestAgent(t, "redact-reader-"+suffix, model.RoleReader, nil)
+	hidden := createTestAgent(t, "redact-hidden-"+suffix, model.RoleAgent, nil)
+
+	focal := uuid.New()
+	explanation := "redis vs memcached"
+	reasoning := "memcached is simpler"
+	claim := "memcached has fewer moving parts"
+	project := "hidden-project"
+	note := "kept memcached after load tests"
+	conflicts := []model.DecisionConflict{
+		// Counterpart is the reader's own decision: left intact.
+		{DecisionAID: focal, DecisionBID: uuid.New(), AgentA: reader.AgentID, AgentB: reader.AgentID,
+			OutcomeA: "use redis", OutcomeB: "use redis cluster"},
+		// Counterpart (side A) belongs to an agent the reader cannot see.
+		{
+			DecisionAID: uuid.New(), DecisionBID: focal,
+			AgentA: hidden.AgentID, RunA: uuid.New(), DecisionType: "cache", DecisionTypeA: "cache",
+			OutcomeA: "use memcached", ConfidenceA: 0.9, ReasoningA: &reasoning,
+			DecidedAtA: time.Now(), ClaimTextA: &claim, ProjectA: &project,
+			AgentB: reader.AgentID, DecisionTypeB: "caching", OutcomeB: "use redis",
+			Explanation:    &explanation,
+			Suggestion:     &model.ConflictSuggestion{Explanation: "memcached is simpler"},
+			ResolutionNote: &note,
+			MinorityIDs:    []uuid.UUID{uuid.New()},
+		},
+		// Counterpart (side B) belongs to an agent the reader cannot see.
+		{
+			DecisionAID: focal, DecisionBID: uuid.New(),
+			AgentA: reader.AgentID, DecisionType: "cache", DecisionTypeA: "cache", OutcomeA: "use redis",
+			AgentB: hidden.AgentID, RunB: uuid.New(), DecisionTypeB: "cache",
+			OutcomeB: "use memcached", ConfidenceB: 0.8, ReasoningB: &reasoning,
+			DecidedAtB: time.Now(), ClaimTextB: &claim, ProjectB: &project,
+			ResolutionNote: &note,
+		},
+	}
+	hiddenDecision := conflicts[1].DecisionAID
+
+	claims := makeClaims(reader.AgentID, reader.ID, model.RoleReader)
+	got, err := authz.RedactConflictCounterparts(context.Background(), testDB, claims, focal, conflicts, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 3, "redaction must not drop conflicts")
+
+	assert.False(t, got[0].CounterpartRedacted)
+	assert.Equal(t, "use redis cluster", got[0].OutcomeB)
+
+	// assertSideBlank checks that every field of the hidden side except its
+	// decision ID is zero, so a field added to DecisionConflict later cannot
+	// leak without this test noticing.
+	assertSideBlank := func(c model.DecisionConflict, side string) {
+		t.Helper()
+		v := reflect.ValueOf(c)
+		for i := range v.NumField() {
+			name := v.Type().Field(i).Name
+			if strings.HasSuffix(name, side) && name != "Decision"+side+"ID" {
+				assert.True(t, v.Field(i).IsZero(), "hidden-side field %s must be blanked", name)
+			}
+		}
+	}
+
+	c := got[1]
+	assert.True(t, c.CounterpartRedacted)
+	assertSideBlank(c, "A")
+	assert.Equal(t, hiddenDecision, c.DecisionAID)
+	assert.Nil(t, c.Explanation)
+	assert.Nil(t, c.Suggestion)
+	assert.Nil(t, c.ResolutionNote)
+	assert.Nil(t, c.MinorityIDs)
+	assert.Equal(t, "caching", c.DecisionType, "decision_type reports the visible side")
+	assert.Equal(t, "use redis", c.OutcomeB, "the focal side is kept")
+	assert.Equal(t, reader.AgentID, c.AgentB)
+
+	c = got[2]
+	assert.True(t, c.CounterpartRedacted)
+	assertSideBlank(c, "B")
+	assert.Nil(t, c.ResolutionNote)
+	assert.Equal(t, "use redis", c.OutcomeA, "the focal side is kept")
+	assert.Equal(t, "cache", c.DecisionType)
+}
+
 func TestFilterConflicts_AdminSeesAll(t *testing.T) {
 	claims := makeClaims("admin-conf", uuid.New(), model.RoleAdmin)
 
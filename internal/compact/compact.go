@@ -38,6 +38,7 @@ func Decision(d model.Decision) map[string]any {
 		"agreement_count": d.AgreementCount,
 		"conflict_count":  d.ConflictCount,
 	}
+	setIfPresent(m, "effective_confidence", d.EffectiveConfidence)
 	if d.Reasoning != nil && *d.Reasoning != "" {
 		m["reasoning"] = Truncate(*d.Reasoning, MaxCompactReasoning)
 	}
@@ -134,6 +135,7 @@ func Conflict(c model.DecisionConflict, consensusNote string) map[string]any {
 	}
 	setIfPresent(m, "category", c.Category)
 	setIfPresent(m, "severity", c.Severity)
+	setIfPresent(m, "reason_code", c.ReasonCode)
 	if c.Explanation != nil && *c.Explanation != "" {
 		m["explanation"] = *c.Explanation
 	}
@@ -193,6 +195,9 @@ func ConflictGroup(g model.ConflictGroup) map[string]any {
 func SearchResult(r model.SearchResult) map[string]any {
 	m := Decision(r.Decision)
 	m["similarity_score"] = r.SimilarityScore
+	if len(r.Highlights) > 0 {
+		m["highlights"] = r.Highlights
+	}
 	return m
 }
 
@@ -388,6 +393,12 @@ func Truncate(s string, maxLen int) string {
 	return string(runes[:maxLen]) + "..."
 }
 
+// SuggestionNote is appended to a check summary when the requested agent_id
+// does not exist but similar ones do.
+func SuggestionNote(suggestions []string) string {
+	return fmt.Sprintf(" The requested agent_id does not exist; did you mean %s? See suggestions.", strings.Join(suggestions, ", "))
+}
+
 // CheckResult builds the concise check response map from full decision and conflict data.
 // This is shared between the MCP tool layer and the HTTP API format=concise path.
 func CheckResult(resp model.CheckResponse, canSuggestPrecedent bool) map[string]any {
@@ -418,6 +429,9 @@ func CheckResult(resp model.CheckResponse, canSuggestPrecedent bool) map[string]
 	if len(resp.PriorResolutions) > 0 {
 		summary += fmt.Sprintf(" %d prior conflict(s) for this decision type were formally resolved; winning approach(es) listed in prior_resolutions.", len(resp.PriorResolutions))
 	}
+	if len(resp.Suggestions) > 0 {
+		summary += SuggestionNote(resp.Suggestions)
+	}
 
 	result := map[string]any{
 		"has_precedent":     resp.HasPrecedent,
@@ -432,6 +446,9 @@ func CheckResult(resp model.CheckResponse, canSuggestPrecedent bool) map[string]
 	if resp.ConflictsUnavailable {
 		result["conflicts_unavailable"] = true
 	}
+	if len(resp.Suggestions) > 0 {
+		result["suggestions"] = resp.Suggestions
+	}
 
 	// precedent_ref_hint: the UUID of the best candidate for precedent_ref in the
 	// subsequent akashi_trace call. We pick the most recent decision with fewer than
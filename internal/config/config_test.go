@@ -312,6 +312,58 @@ func TestLoad_EmbeddingProviderSelection(t *testing.T) {
 	}
 }
 
+func TestLoad_EmbeddingFallback(t *testing.T) {
+	t.Setenv("AKASHI_EMBEDDING_FALLBACK", "openai, ollama")
+	t.Setenv("AKASHI_EMBEDDING_MODEL", "text-embedding-3-small")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed, got: %v", err)
+	}
+	if len(cfg.EmbeddingFallback) != 2 || cfg.EmbeddingFallback[0] != "openai" || cfg.EmbeddingFallback[1] != "ollama" {
+		t.Fatalf("expected EmbeddingFallback [openai ollama], got %v", cfg.EmbeddingFallback)
+	}
+	if cfg.EmbeddingFallbackTimeout != 10*time.Second {
+		t.Fatalf("expected default EmbeddingFallbackTimeout 10s, got %v", cfg.EmbeddingFallbackTimeout)
+	}
+	// The threshold profile follows the first provider in the chain.
+	if cfg.EmbeddingModelProfile != "text-embedding-3-small" {
+		t.Fatalf("expected EmbeddingModelProfile %q, got %q", "text-embedding-3-small", cfg.EmbeddingModelProfile)
+	}
+}
+
+func TestValidate_EmbeddingFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback []string
+		timeout  time.Duration
+		wantErr  string
+	}{
+		{"valid chain", []string{"ollama", "openai"}, time.Second, ""},
+		{"unknown provider", []string{"ollama", "cohere"}, time.Second, "AKASHI_EMBEDDING_FALLBACK"},
+		{"duplicate provider", []string{"ollama", "ollama"}, time.Second, "more than once"},
+		{"zero timeout", []string{"ollama", "openai"}, 0, "AKASHI_EMBEDDING_FALLBACK_TIMEOUT"},
+		{"unset ignores timeout", nil, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validBaseConfig()
+			cfg.EmbeddingFallback = tt.fallback
+			cfg.EmbeddingFallbackTimeout = tt.timeout
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error mentioning %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestLoad_QdrantURLValidation(t *testing.T) {
 	t.Run("explicit URL", func(t *testing.T) {
 		qdrantURL := "https://qdrant.example.com:6334"
@@ -530,6 +582,53 @@ func TestValidate_InvalidPort(t *testing.T) {
 	}
 }
 
+func TestValidate_GRPCTLSFilesSetTogether(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.GRPCTLSCertFile = "/etc/akashi/grpc.crt"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for a cert without a key")
+	}
+	if !contains(err.Error(), "AKASHI_GRPC_TLS_KEY_FILE") {
+		t.Fatalf("error should mention AKASHI_GRPC_TLS_KEY_FILE, got: %s", err.Error())
+	}
+
+	cfg.GRPCTLSKeyFile = "/etc/akashi/grpc.key"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("cert and key together should validate, got: %v", err)
+	}
+}
+
+func TestValidate_ShutdownOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  []string
+		errStr string
+	}{
+		{"default", nil, ""},
+		{"reordered", []string{"http", "async", "buffer", "outbox", "audit_sink", "loops"}, ""},
+		{"unknown phase", []string{"loops", "http", "async", "outbox", "audit_sink", "buffer", "database"}, `unknown phase "database"`},
+		{"duplicate phase", []string{"loops", "http", "http", "async", "outbox", "audit_sink", "buffer"}, `phase "http" more than once`},
+		{"missing phase", []string{"loops", "http", "async", "outbox", "audit_sink"}, "must list every phase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validBaseConfig()
+			cfg.ShutdownOrder = tt.order
+			err := cfg.Validate()
+			if tt.errStr == "" {
+				if err != nil {
+					t.Fatalf("unexpected validation error: %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.errStr) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errStr, err)
+			}
+		})
+	}
+}
+
 func TestValidate_NegativeTimeouts(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -661,6 +760,54 @@ func TestValidate_RateLimitEnabledRequiresPositiveValues(t *testing.T) {
 	})
 }
 
+func TestValidate_RateLimitPerAgent(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		cfg := validBaseConfig()
+		cfg.RateLimitEnabled = true
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected no validation error, got: %v", err)
+		}
+	})
+
+	t.Run("negative RPS", func(t *testing.T) {
+		cfg := validBaseConfig()
+		cfg.RateLimitEnabled = true
+		cfg.RateLimitPerAgentRPS = -1
+
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("expected validation error for negative RateLimitPerAgentRPS")
+		}
+		if !contains(err.Error(), "AKASHI_RATE_LIMIT_PER_AGENT_RPS") {
+			t.Fatalf("error should mention AKASHI_RATE_LIMIT_PER_AGENT_RPS, got: %s", err.Error())
+		}
+	})
+
+	t.Run("RPS without burst", func(t *testing.T) {
+		cfg := validBaseConfig()
+		cfg.RateLimitEnabled = true
+		cfg.RateLimitPerAgentRPS = 10
+
+		err := cfg.Validate()
+		if err == nil {
+			t.Fatal("expected validation error for missing RateLimitPerAgentBurst")
+		}
+		if !contains(err.Error(), "AKASHI_RATE_LIMIT_PER_AGENT_BURST") {
+			t.Fatalf("error should mention AKASHI_RATE_LIMIT_PER_AGENT_BURST, got: %s", err.Error())
+		}
+	})
+
+	t.Run("RPS with burst", func(t *testing.T) {
+		cfg := validBaseConfig()
+		cfg.RateLimitEnabled = true
+		cfg.RateLimitPerAgentRPS = 10
+		cfg.RateLimitPerAgentBurst = 20
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected no validation error, got: %v", err)
+		}
+	})
+}
+
 func TestValidate_KeyFileValidation(t *testing.T) {
 	t.Run("directory instead of file", func(t *testing.T) {
 		dir := t.TempDir()
@@ -722,6 +869,11 @@ func TestValidate_ZeroIntervals(t *testing.T) {
 			setter: func(c *Config) { c.OutboxPollInterval = 0 },
 			errStr: "AKASHI_OUTBOX_POLL_INTERVAL",
 		},
+		{
+			name:   "zero outbox max attempts",
+			setter: func(c *Config) { c.OutboxMaxAttempts = 0 },
+			errStr: "AKASHI_OUTBOX_MAX_ATTEMPTS",
+		},
 		{
 			name:   "zero conflict refresh interval",
 			setter: func(c *Config) { c.ConflictRefreshInterval = 0 },
@@ -819,6 +971,7 @@ func validBaseConfig() Config {
 		ShutdownOutboxDrainTimeout: 0,
 		ShutdownLoopDrainTimeout:   10 * time.Second,
 		OutboxPollInterval:         1 * time.Second,
+		OutboxMaxAttempts:          10,
 		ConflictRefreshInterval:    30 * time.Second,
 		IntegrityProofInterval:     5 * time.Minute,
 		IntegrityAuditInterval:     15 * time.Minute,
@@ -828,11 +981,26 @@ func validBaseConfig() Config {
 		IdempotencyCleanupInterval: 1 * time.Hour,
 		IdempotencyCompletedTTL:    7 * 24 * time.Hour,
 		IdempotencyAbandonedTTL:    24 * time.Hour,
+		ReviewSLA:                  24 * time.Hour,
+		DuplicateSimilarityFloor:   0.9,
+		DuplicateTraceThreshold:    0.97,
 		RateLimitEnabled:           true,
 		RateLimitRPS:               100,
 		RateLimitBurst:             200,
 		WALDir:                     "./data/wal",
 		ExportPageSize:             100,
+		TraceBatchMax:              100,
+		ContextSnapshotMaxBytes:    4096,
+		FlipFlopWindow:             24 * time.Hour,
+		APIKeyHashAlgorithm:        "argon2id",
+		APIKeyArgon2Time:           1,
+		APIKeyArgon2MemoryKiB:      64 * 1024,
+		APIKeyArgon2Threads:        4,
+		APIKeyBcryptCost:           10,
+		ContextSnapshotOversize:    "truncate",
+		AuditSinkMode:              "async",
+		AuditSinkTimeout:           5 * time.Second,
+		AuditSinkQueueSize:         10000,
 	}
 }
 
@@ -1358,3 +1526,615 @@ func TestLoad_ExportPageSizeBoundaries(t *testing.T) {
 		}
 	})
 }
+
+func TestLoad_TraceBatchMax(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed with defaults, got: %v", err)
+	}
+	if cfg.TraceBatchMax != 100 {
+		t.Fatalf("expected default TraceBatchMax 100, got %d", cfg.TraceBatchMax)
+	}
+
+	for _, value := range []string{"0", "1001"} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("AKASHI_TRACE_BATCH_MAX", value)
+			_, err := Load()
+			if err == nil {
+				t.Fatalf("expected Load() to fail for AKASHI_TRACE_BATCH_MAX=%s", value)
+			}
+			if !contains(err.Error(), "AKASHI_TRACE_BATCH_MAX must be between 1 and 1000") {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestLoad_AccessLogDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed with defaults, got: %v", err)
+	}
+	if cfg.AccessLogEnabled {
+		t.Fatal("expected access log disabled by default")
+	}
+	if cfg.AccessLogSampleRate != 1.0 {
+		t.Fatalf("expected default AccessLogSampleRate 1.0, got %f", cfg.AccessLogSampleRate)
+	}
+	if cfg.AccessLogMaxIDs != 100 {
+		t.Fatalf("expected default AccessLogMaxIDs 100, got %d", cfg.AccessLogMaxIDs)
+	}
+}
+
+func TestValidate_AccessLogBounds(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.AccessLogEnabled = true
+	cfg.AccessLogSampleRate = 1.5
+	cfg.AccessLogMaxIDs = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for out-of-range access log settings")
+	}
+	if !contains(err.Error(), "AKASHI_ACCESS_LOG_SAMPLE_RATE") {
+		t.Fatalf("error should mention AKASHI_ACCESS_LOG_SAMPLE_RATE, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_ACCESS_LOG_MAX_IDS") {
+		t.Fatalf("error should mention AKASHI_ACCESS_LOG_MAX_IDS, got: %s", err.Error())
+	}
+
+	// The same values are ignored while the access log is disabled.
+	cfg.AccessLogEnabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled access log to skip validation, got: %v", err)
+	}
+}
+
+func TestLoad_AccessLogRequestsDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed with defaults, got: %v", err)
+	}
+	if cfg.AccessLogRequests {
+		t.Fatal("expected per-request access log disabled by default")
+	}
+	if cfg.AccessLogFlushInterval != time.Second {
+		t.Fatalf("expected default AccessLogFlushInterval 1s, got %s", cfg.AccessLogFlushInterval)
+	}
+}
+
+func TestValidate_AccessLogFlushInterval(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.AccessLogRequests = true
+	cfg.AccessLogFlushInterval = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for non-positive access log flush interval")
+	}
+	if !contains(err.Error(), "AKASHI_ACCESS_LOG_FLUSH_INTERVAL") {
+		t.Fatalf("error should mention AKASHI_ACCESS_LOG_FLUSH_INTERVAL, got: %s", err.Error())
+	}
+
+	cfg.AccessLogRequests = false
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled access log to skip validation, got: %v", err)
+	}
+}
+
+func TestLoad_CORSPolicies(t *testing.T) {
+	t.Setenv("AKASHI_CORS_POLICIES", `[{"origin":"https://partner.example.com","methods":["GET"]},{"origin":"https://app.example.com","allow_credentials":true}]`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed, got: %v", err)
+	}
+	if len(cfg.CORSPolicies) != 2 {
+		t.Fatalf("expected 2 CORS policies, got %d", len(cfg.CORSPolicies))
+	}
+	if got := cfg.CORSPolicies[0]; got.Origin != "https://partner.example.com" || len(got.Methods) != 1 || got.Methods[0] != "GET" {
+		t.Fatalf("unexpected first policy: %+v", got)
+	}
+	if !cfg.CORSPolicies[1].AllowCredentials {
+		t.Fatal("expected second policy to allow credentials")
+	}
+}
+
+func TestLoad_CORSPoliciesInvalidJSON(t *testing.T) {
+	t.Setenv("AKASHI_CORS_POLICIES", `[{"origin":"https://partner.example.com","method":["GET"]}]`)
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected Load() to reject an unknown field")
+	}
+	if !contains(err.Error(), "AKASHI_CORS_POLICIES") {
+		t.Fatalf("error should mention AKASHI_CORS_POLICIES, got: %s", err.Error())
+	}
+}
+
+func TestValidate_CORSPolicies(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.CORSPolicies = []CORSPolicy{
+		{Origin: ""},
+		{Origin: "*", AllowCredentials: true},
+		{Origin: "https://a.example.com", Methods: []string{"GET", "TRACE"}},
+		{Origin: "https://a.example.com"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors for CORS policies")
+	}
+	for _, want := range []string{"must set origin", "cannot allow credentials", `unsupported method "TRACE"`, "duplicate origin"} {
+		if !contains(err.Error(), want) {
+			t.Fatalf("error should mention %q, got: %s", want, err.Error())
+		}
+	}
+}
+
+func TestLoad_RouteTimeouts(t *testing.T) {
+	t.Setenv("AKASHI_ROUTE_TIMEOUTS", `[{"route":"GET /v1/export/decisions","write_timeout":"0"},{"route":"POST /auth/token","read_timeout":"5s","write_timeout":"5s"}]`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed, got: %v", err)
+	}
+	if len(cfg.RouteTimeouts) != 2 {
+		t.Fatalf("expected 2 route timeouts, got %d", len(cfg.RouteTimeouts))
+	}
+	export := cfg.RouteTimeouts[0]
+	if export.ReadTimeout != nil || export.WriteTimeout == nil || *export.WriteTimeout != 0 {
+		t.Fatalf("unexpected export route timeout: %+v", export)
+	}
+	token := cfg.RouteTimeouts[1]
+	if token.ReadTimeout == nil || *token.ReadTimeout != 5*time.Second || token.WriteTimeout == nil || *token.WriteTimeout != 5*time.Second {
+		t.Fatalf("unexpected token route timeout: %+v", token)
+	}
+}
+
+func TestLoad_RouteTimeoutsInvalidDuration(t *testing.T) {
+	t.Setenv("AKASHI_ROUTE_TIMEOUTS", `[{"route":"POST /auth/token","read_timeout":"-1s"}]`)
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected Load() to reject a negative duration")
+	}
+	if !contains(err.Error(), "AKASHI_ROUTE_TIMEOUTS") {
+		t.Fatalf("error should mention AKASHI_ROUTE_TIMEOUTS, got: %s", err.Error())
+	}
+}
+
+func TestValidate_RouteTimeouts(t *testing.T) {
+	write := time.Minute
+	cfg := validBaseConfig()
+	cfg.RouteTimeouts = []RouteTimeout{
+		{Route: "", WriteTimeout: &write},
+		{Route: "GET /v1/export/decisions", WriteTimeout: &write},
+		{Route: "GET /v1/export/decisions", WriteTimeout: &write},
+		{Route: "POST /v1/query"},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors for route timeouts")
+	}
+	for _, want := range []string{"must set route", "duplicate route", "must set read_timeout or write_timeout"} {
+		if !contains(err.Error(), want) {
+			t.Fatalf("error should mention %q, got: %s", want, err.Error())
+		}
+	}
+}
+
+func TestLoad_ReviewDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed with defaults, got: %v", err)
+	}
+	if cfg.ReviewSLA != 24*time.Hour {
+		t.Fatalf("expected default ReviewSLA 24h, got %s", cfg.ReviewSLA)
+	}
+	if cfg.ReviewOverdueInterval != 5*time.Minute {
+		t.Fatalf("expected default ReviewOverdueInterval 5m, got %s", cfg.ReviewOverdueInterval)
+	}
+}
+
+func TestValidate_ReviewSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ReviewSLA = 0
+	cfg.ReviewOverdueInterval = -time.Second
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for review settings")
+	}
+	if !contains(err.Error(), "AKASHI_REVIEW_SLA") {
+		t.Fatalf("error should mention AKASHI_REVIEW_SLA, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_REVIEW_OVERDUE_INTERVAL") {
+		t.Fatalf("error should mention AKASHI_REVIEW_OVERDUE_INTERVAL, got: %s", err.Error())
+	}
+
+	// A zero interval disables the overdue sweep and is valid.
+	cfg.ReviewSLA = time.Hour
+	cfg.ReviewOverdueInterval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected zero overdue interval to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_CDCSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.CDCRetention = -time.Hour
+	cfg.CDCCompactionInterval = -time.Second
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for CDC settings")
+	}
+	if !contains(err.Error(), "AKASHI_CDC_RETENTION") {
+		t.Fatalf("error should mention AKASHI_CDC_RETENTION, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_CDC_COMPACTION_INTERVAL") {
+		t.Fatalf("error should mention AKASHI_CDC_COMPACTION_INTERVAL, got: %s", err.Error())
+	}
+
+	cfg.CDCRetention = 0
+	cfg.CDCCompactionInterval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected unbounded retention with compaction disabled to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_RunIdleSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.RunIdleStatus = "bogus"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("status is only checked when the sweeper is enabled, got: %v", err)
+	}
+
+	cfg.RunIdleTimeout = time.Hour
+	cfg.RunIdleSweepInterval = 5 * time.Minute
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_RUN_IDLE_STATUS") {
+		t.Fatalf("expected AKASHI_RUN_IDLE_STATUS error, got: %v", err)
+	}
+
+	for _, status := range []string{"abandoned", "completed"} {
+		cfg.RunIdleStatus = status
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("status %q should be valid, got: %v", status, err)
+		}
+	}
+
+	cfg.RunIdleSweepInterval = 0
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_RUN_IDLE_SWEEP_INTERVAL") {
+		t.Fatalf("expected AKASHI_RUN_IDLE_SWEEP_INTERVAL error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.RunIdleTimeout = -time.Minute
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_RUN_IDLE_TIMEOUT") {
+		t.Fatalf("expected AKASHI_RUN_IDLE_TIMEOUT error, got: %v", err)
+	}
+}
+
+func TestValidate_EventRetention(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.EventRetentionInterval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("interval is only checked when retention is enabled, got: %v", err)
+	}
+
+	cfg.EventRetention = 30 * 24 * time.Hour
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_EVENT_RETENTION_INTERVAL") {
+		t.Fatalf("expected AKASHI_EVENT_RETENTION_INTERVAL error, got: %v", err)
+	}
+
+	cfg.EventRetentionInterval = time.Hour
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got: %v", err)
+	}
+
+	cfg.EventRetention = -time.Hour
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_EVENT_RETENTION must be >= 0") {
+		t.Fatalf("expected AKASHI_EVENT_RETENTION error, got: %v", err)
+	}
+}
+
+func TestValidate_TombstoneRetention(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.TombstoneRetention = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("0 keeps tombstones forever and is valid, got: %v", err)
+	}
+
+	cfg.TombstoneRetention = -time.Hour
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_TOMBSTONE_RETENTION must be >= 0") {
+		t.Fatalf("expected AKASHI_TOMBSTONE_RETENTION error, got: %v", err)
+	}
+}
+
+func TestValidate_ConfidencePrecision(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ConfidencePrecision = 7
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_CONFIDENCE_PRECISION") {
+		t.Fatalf("expected AKASHI_CONFIDENCE_PRECISION error, got: %v", err)
+	}
+
+	cfg.ConfidencePrecision = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected negative precision to be rejected")
+	}
+
+	for _, p := range []int{0, 2, 6} {
+		cfg.ConfidencePrecision = p
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("precision %d should be valid, got: %v", p, err)
+		}
+	}
+}
+
+func TestValidate_DuplicateScanSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.DuplicateScanInterval = -time.Second
+	cfg.DuplicateSimilarityFloor = 1.5
+	cfg.DuplicateTraceThreshold = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for duplicate scan settings")
+	}
+	if !contains(err.Error(), "AKASHI_DUPLICATE_SCAN_INTERVAL") {
+		t.Fatalf("error should mention AKASHI_DUPLICATE_SCAN_INTERVAL, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_DUPLICATE_SIMILARITY_FLOOR") {
+		t.Fatalf("error should mention AKASHI_DUPLICATE_SIMILARITY_FLOOR, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_DUPLICATE_SIMILARITY_THRESHOLD") {
+		t.Fatalf("error should mention AKASHI_DUPLICATE_SIMILARITY_THRESHOLD, got: %s", err.Error())
+	}
+
+	cfg.DuplicateScanInterval = 0
+	cfg.DuplicateSimilarityFloor = 0.9
+	cfg.DuplicateTraceThreshold = 0.97
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled scan with default floor to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_ContextSnapshotSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ContextSnapshotMaxBytes = 512
+
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_CONTEXT_SNAPSHOT_MAX_BYTES") {
+		t.Fatalf("expected AKASHI_CONTEXT_SNAPSHOT_MAX_BYTES validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.ContextSnapshotOversize = "drop"
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_CONTEXT_SNAPSHOT_OVERSIZE") {
+		t.Fatalf("expected AKASHI_CONTEXT_SNAPSHOT_OVERSIZE validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.ContextSnapshotOversize = "reject"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected reject policy to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_AuditSinkSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.AuditSinkMode = "sync"
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_AUDIT_SINK_MODE") {
+		t.Fatalf("expected AKASHI_AUDIT_SINK_MODE validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.AuditSinkQueueSize = 0
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_AUDIT_SINK_QUEUE_SIZE") {
+		t.Fatalf("expected AKASHI_AUDIT_SINK_QUEUE_SIZE validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.AuditSinkMode = "block"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected block mode to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_DecisionBatchWindow(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.DecisionBatchWindow = -time.Second
+
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_DECISION_BATCH_WINDOW") {
+		t.Fatalf("expected AKASHI_DECISION_BATCH_WINDOW validation error, got: %v", err)
+	}
+
+	cfg.DecisionBatchWindow = 30 * time.Second
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected positive batch window to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_APIKeyHashSettings(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		envVar string
+	}{
+		{"unknown algorithm", func(c *Config) { c.APIKeyHashAlgorithm = "md5" }, "AKASHI_API_KEY_HASH_ALGORITHM"},
+		{"zero argon2 time", func(c *Config) { c.APIKeyArgon2Time = 0 }, "AKASHI_API_KEY_ARGON2_TIME"},
+		{"too many threads", func(c *Config) { c.APIKeyArgon2Threads = 256 }, "AKASHI_API_KEY_ARGON2_THREADS"},
+		{"memory below per-thread minimum", func(c *Config) { c.APIKeyArgon2MemoryKiB = 16 }, "AKASHI_API_KEY_ARGON2_MEMORY_KIB"},
+		{"bcrypt cost too high", func(c *Config) { c.APIKeyBcryptCost = 32 }, "AKASHI_API_KEY_BCRYPT_COST"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validBaseConfig()
+			tc.mutate(&cfg)
+			err := cfg.Validate()
+			if err == nil || !contains(err.Error(), tc.envVar) {
+				t.Fatalf("expected %s validation error, got: %v", tc.envVar, err)
+			}
+		})
+	}
+
+	cfg := validBaseConfig()
+	cfg.APIKeyHashAlgorithm = "bcrypt"
+	cfg.APIKeyBcryptCost = 12
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected bcrypt settings to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_FlipFlopSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.FlipFlopMinFlips = -1
+
+	err := cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_FLIP_FLOP_MIN_FLIPS") {
+		t.Fatalf("expected AKASHI_FLIP_FLOP_MIN_FLIPS validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.FlipFlopWindow = 0
+	err = cfg.Validate()
+	if err == nil || !contains(err.Error(), "AKASHI_FLIP_FLOP_WINDOW") {
+		t.Fatalf("expected AKASHI_FLIP_FLOP_WINDOW validation error, got: %v", err)
+	}
+
+	cfg = validBaseConfig()
+	cfg.FlipFlopMinFlips = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled detection to be valid, got: %v", err)
+	}
+}
+
+func TestLoad_ConflictSuggestionDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed with defaults, got: %v", err)
+	}
+	if cfg.ConflictSuggestionsEnabled {
+		t.Fatal("expected conflict suggestions disabled by default")
+	}
+	if cfg.ConflictSuggestionInterval != 30*time.Second {
+		t.Fatalf("expected default ConflictSuggestionInterval 30s, got %s", cfg.ConflictSuggestionInterval)
+	}
+	if cfg.ConflictSuggestionRatePerMinute != 10 {
+		t.Fatalf("expected default ConflictSuggestionRatePerMinute 10, got %d", cfg.ConflictSuggestionRatePerMinute)
+	}
+}
+
+func TestValidate_ConflictSuggestionSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ConflictSuggestionsEnabled = true
+	cfg.ConflictSuggestionInterval = 0
+	cfg.ConflictSuggestionRatePerMinute = 0
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for conflict suggestion settings")
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_SUGGESTION_INTERVAL") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_SUGGESTION_INTERVAL, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_SUGGESTION_RATE_PER_MINUTE") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_SUGGESTION_RATE_PER_MINUTE, got: %s", err.Error())
+	}
+
+	cfg.ConflictSuggestionsEnabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled suggestions to skip validation, got: %v", err)
+	}
+}
+
+func TestValidate_ClaimOverlapSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ConflictClaimOverlapLimit = -1
+	cfg.ConflictClaimOverlapSimFloor = 1.5
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for claim overlap settings")
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_CLAIM_OVERLAP_LIMIT") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_CLAIM_OVERLAP_LIMIT, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_CLAIM_OVERLAP_SIM_FLOOR") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_CLAIM_OVERLAP_SIM_FLOOR, got: %s", err.Error())
+	}
+
+	cfg.ConflictClaimOverlapLimit = 0
+	cfg.ConflictClaimOverlapSimFloor = 0.6
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled claim overlap to be valid, got: %v", err)
+	}
+}
+
+func TestValidate_ConsensusDriftSettings(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.ConflictConsensusMinority = 0.5
+	cfg.ConflictConsensusMinCohort = 1
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error for consensus drift settings")
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_CONSENSUS_MINORITY_FRACTION") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_CONSENSUS_MINORITY_FRACTION, got: %s", err.Error())
+	}
+	if !contains(err.Error(), "AKASHI_CONFLICT_CONSENSUS_MIN_COHORT") {
+		t.Fatalf("error should mention AKASHI_CONFLICT_CONSENSUS_MIN_COHORT, got: %s", err.Error())
+	}
+
+	cfg.ConflictConsensusMinority = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled consensus drift to skip cohort validation, got: %v", err)
+	}
+}
+
+func TestLoad_CompletenessWeights(t *testing.T) {
+	t.Setenv("AKASHI_COMPLETENESS_WEIGHTS", `{"evidence":0.25,"precedent":0}`)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected Load() to succeed, got: %v", err)
+	}
+	if len(cfg.CompletenessWeights) != 2 || cfg.CompletenessWeights["evidence"] != 0.25 || cfg.CompletenessWeights["precedent"] != 0 {
+		t.Fatalf("unexpected completeness weights: %v", cfg.CompletenessWeights)
+	}
+
+	t.Setenv("AKASHI_COMPLETENESS_WEIGHTS", `{"evidence":"high"}`)
+	if _, err := Load(); err == nil || !contains(err.Error(), "AKASHI_COMPLETENESS_WEIGHTS") {
+		t.Fatalf("expected Load() to reject a non-numeric weight, got: %v", err)
+	}
+}
+
+func TestValidate_CompletenessWeights(t *testing.T) {
+	cfg := validBaseConfig()
+	cfg.CompletenessWeights = map[string]float64{"evidance": 0.2, "reasoning": -0.1}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors for completeness weights")
+	}
+	for _, want := range []string{`unknown factor "evidance"`, "reasoning must be >= 0"} {
+		if !contains(err.Error(), want) {
+			t.Fatalf("error should mention %q, got: %s", want, err.Error())
+		}
+	}
+
+	cfg.CompletenessWeights = map[string]float64{
+		"confidence": 0, "reasoning": 0, "alternatives": 0, "evidence": 0, "outcome": 0, "precedent": 0,
+	}
+	if err := cfg.Validate(); err == nil || !contains(err.Error(), "at least one factor") {
+		t.Fatalf("expected all-zero weights to be rejected, got: %v", err)
+	}
+}
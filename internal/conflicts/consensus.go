@@ -0,0 +1,214 @@
+//go:build !lite
+
+package conflicts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// Consensus-drift defaults. A cohort of related decisions drifts when most of
+// them agree and a small minority reaches a different outcome.
+const (
+	defaultConsensusOutcomeSimFloor = 0.85
+	defaultConsensusMinorityMax     = 0.30
+	defaultConsensusMinCohort       = 5
+)
+
+// ConsensusMember is one decision in a cohort examined for consensus drift.
+type ConsensusMember struct {
+	ID               uuid.UUID
+	OutcomeEmbedding []float32
+}
+
+// ConsensusConfig tunes DetectConsensusDrift. Zero fields take the package
+// defaults.
+type ConsensusConfig struct {
+	// OutcomeSimFloor is the outcome embedding cosine similarity at which two
+	// decisions count as reaching the same outcome.
+	OutcomeSimFloor float64
+	// MaxMinorityFraction is the exclusive upper bound on the minority's share
+	// of the cohort. A larger minority is a genuine split, not drift.
+	MaxMinorityFraction float64
+	// MinCohort is the smallest cohort for which a majority is meaningful.
+	MinCohort int
+}
+
+func (c ConsensusConfig) withDefaults() ConsensusConfig {
+	if c.OutcomeSimFloor <= 0 {
+		c.OutcomeSimFloor = defaultConsensusOutcomeSimFloor
+	}
+	if c.MaxMinorityFraction <= 0 {
+		c.MaxMinorityFraction = defaultConsensusMinorityMax
+	}
+	if c.MinCohort <= 0 {
+		c.MinCohort = defaultConsensusMinCohort
+	}
+	return c
+}
+
+// ConsensusDrift is the majority/minority split of a drifting cohort.
+// MajorityIDs[0] and MinorityIDs[0] are the exemplars recorded as the
+// conflict's A and B sides.
+type ConsensusDrift struct {
+	MajorityIDs      []uuid.UUID
+	MinorityIDs      []uuid.UUID
+	MinorityFraction float64
+}
+
+// DetectConsensusDrift clusters a cohort of topically related decisions by
+// outcome embedding and reports drift when the largest cluster holds a clear
+// majority and the remaining decisions make up less than
+// cfg.MaxMinorityFraction of the cohort.
+//
+// Clustering is single-pass leader clustering: each member joins the first
+// cluster whose leader it matches at cfg.OutcomeSimFloor, or starts a new
+// one. Members without an outcome embedding are ignored. Cohorts are small
+// (the scored decision plus its candidates), so the quadratic worst case is
+// irrelevant. Ties for the largest cluster have no majority and never drift.
+func DetectConsensusDrift(members []ConsensusMember, cfg ConsensusConfig) (ConsensusDrift, bool) {
+	cfg = cfg.withDefaults()
+
+	type cluster struct {
+		leader  []float32
+		members []uuid.UUID
+	}
+	var clusters []*cluster
+	total := 0
+	for _, m := range members {
+		if len(m.OutcomeEmbedding) == 0 {
+			continue
+		}
+		total++
+		var home *cluster
+		for _, c := range clusters {
+			if cosineSimilarity(c.leader, m.OutcomeEmbedding) >= cfg.OutcomeSimFloor {
+				home = c
+				break
+			}
+		}
+		if home == nil {
+			home = &cluster{leader: m.OutcomeEmbedding}
+			clusters = append(clusters, home)
+		}
+		home.members = append(home.members, m.ID)
+	}
+	if total < cfg.MinCohort || len(clusters) < 2 {
+		return ConsensusDrift{}, false
+	}
+
+	majority, tied := 0, false
+	for i, c := range clusters[1:] {
+		switch n, best := len(c.members), len(clusters[majority].members); {
+		case n > best:
+			majority, tied = i+1, false
+		case n == best:
+			tied = true
+		}
+	}
+	if tied {
+		return ConsensusDrift{}, false
+	}
+
+	drift := ConsensusDrift{MajorityIDs: clusters[majority].members}
+	for i, c := range clusters {
+		if i != majority {
+			drift.MinorityIDs = append(drift.MinorityIDs, c.members...)
+		}
+	}
+	drift.MinorityFraction = float64(len(drift.MinorityIDs)) / float64(total)
+	if drift.MinorityFraction >= cfg.MaxMinorityFraction {
+		return ConsensusDrift{}, false
+	}
+	return drift, true
+}
+
+// checkConsensusDrift records a consensus_drift conflict when d departs from
+// an established majority of its cohort: d plus the candidates of the same
+// decision type at or above the decision topic similarity floor, excluding
+// d's own revisions. Drift is recorded only when d is in the minority, so
+// each departure is reported by the decision that made it.
+func (s *Scorer) checkConsensusDrift(ctx context.Context, d model.Decision, candidates []model.Decision, revisionChain map[uuid.UUID]bool) {
+	if s.consensus.MaxMinorityFraction <= 0 {
+		return
+	}
+
+	cohort := []model.Decision{d}
+	for _, cand := range candidates {
+		if revisionChain[cand.ID] || cand.DecisionType != d.DecisionType || cand.OutcomeEmbedding == nil {
+			continue
+		}
+		if cosineSimilarity(d.Embedding.Slice(), cand.Embedding.Slice()) < s.decisionTopicSimFloor {
+			continue
+		}
+		cohort = append(cohort, cand)
+	}
+	// Oldest first: the earliest decisions lead the clusters and become the
+	// exemplars, so later re-detections update the same row.
+	sort.SliceStable(cohort, func(i, j int) bool { return cohort[i].ValidFrom.Before(cohort[j].ValidFrom) })
+
+	members := make([]ConsensusMember, len(cohort))
+	byID := make(map[uuid.UUID]model.Decision, len(cohort))
+	for i, c := range cohort {
+		members[i] = ConsensusMember{ID: c.ID, OutcomeEmbedding: c.OutcomeEmbedding.Slice()}
+		byID[c.ID] = c
+	}
+	drift, ok := DetectConsensusDrift(members, s.consensus)
+	if !ok || !slices.Contains(drift.MinorityIDs, d.ID) {
+		return
+	}
+
+	majority, minority := byID[drift.MajorityIDs[0]], byID[drift.MinorityIDs[0]]
+	topicSim := cosineSimilarity(majority.Embedding.Slice(), minority.Embedding.Slice())
+	outcomeDiv := math.Max(0, 1-cosineSimilarity(majority.OutcomeEmbedding.Slice(), minority.OutcomeEmbedding.Slice()))
+	sig := topicSim * outcomeDiv
+	explanation := fmt.Sprintf("%d of %d related %s decisions diverge from the majority outcome",
+		len(drift.MinorityIDs), len(members), d.DecisionType)
+
+	_, inserted, err := s.db.UpsertConsensusDriftConflict(ctx, model.DecisionConflict{
+		DecisionAID:       majority.ID,
+		DecisionBID:       minority.ID,
+		OrgID:             d.OrgID,
+		AgentA:            majority.AgentID,
+		AgentB:            minority.AgentID,
+		DecisionTypeA:     majority.DecisionType,
+		DecisionTypeB:     minority.DecisionType,
+		OutcomeA:          majority.Outcome,
+		OutcomeB:          minority.Outcome,
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		Explanation:       &explanation,
+		ProjectA:          majority.Project,
+		ProjectB:          minority.Project,
+		MinorityIDs:       drift.MinorityIDs,
+	})
+	if err != nil {
+		s.logger.Warn("conflict scorer: record consensus drift failed", "decision_id", d.ID, "error", err)
+		return
+	}
+	if !inserted {
+		return
+	}
+	s.metrics.detected.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("scoring_method", "embedding"),
+		attribute.String("relationship", derefOrUnknown(nil)),
+		attribute.String("conflict_kind", string(model.ConflictKindConsensusDrift)),
+		attribute.String("severity", derefOrUnknown(nil)),
+	))
+	s.logger.Info("conflict scorer: consensus drift",
+		"decision_id", d.ID, "minority", len(drift.MinorityIDs), "cohort", len(members))
+	if err := s.db.Notify(ctx, storage.ChannelConflicts, `{"source":"scorer","org_id":"`+d.OrgID.String()+`"}`); err != nil {
+		s.logger.Debug("conflict scorer: notify failed", "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build !lite
+
+package conflicts
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jitter returns a unit-ish vector along axis with a small perturbation so
+// cluster members are similar but not identical.
+func jitter(axis int, eps float32) []float32 {
+	v := []float32{eps, eps, eps, eps}
+	v[axis] = 1
+	return v
+}
+
+func TestDetectConsensusDrift_FlagsMinority(t *testing.T) {
+	var members []ConsensusMember
+	var majority []uuid.UUID
+	for i := range 8 {
+		id := uuid.New()
+		majority = append(majority, id)
+		members = append(members, ConsensusMember{ID: id, OutcomeEmbedding: jitter(0, float32(i)*0.01)})
+	}
+	minorityA, minorityB := uuid.New(), uuid.New()
+	members = append(members,
+		ConsensusMember{ID: minorityA, OutcomeEmbedding: jitter(1, 0.02)},
+		ConsensusMember{ID: minorityB, OutcomeEmbedding: jitter(1, 0.03)},
+	)
+
+	drift, ok := DetectConsensusDrift(members, ConsensusConfig{})
+	require.True(t, ok)
+	assert.ElementsMatch(t, []uuid.UUID{minorityA, minorityB}, drift.MinorityIDs)
+	assert.ElementsMatch(t, majority, drift.MajorityIDs)
+	assert.InDelta(t, 0.2, drift.MinorityFraction, 1e-9)
+}
+
+func TestDetectConsensusDrift_NoDrift(t *testing.T) {
+	cohort := func(majority, minority int) []ConsensusMember {
+		var ms []ConsensusMember
+		for range majority {
+			ms = append(ms, ConsensusMember{ID: uuid.New(), OutcomeEmbedding: jitter(0, 0.01)})
+		}
+		for range minority {
+			ms = append(ms, ConsensusMember{ID: uuid.New(), OutcomeEmbedding: jitter(2, 0.01)})
+		}
+		return ms
+	}
+
+	_, ok := DetectConsensusDrift(cohort(10, 0), ConsensusConfig{})
+	assert.False(t, ok, "unanimous cohort has no minority")
+
+	_, ok = DetectConsensusDrift(cohort(6, 4), ConsensusConfig{})
+	assert.False(t, ok, "40% minority is a split, not drift")
+
+	_, ok = DetectConsensusDrift(cohort(3, 1), ConsensusConfig{})
+	assert.False(t, ok, "cohort below the minimum size")
+
+	_, ok = DetectConsensusDrift(cohort(6, 4), ConsensusConfig{MaxMinorityFraction: 0.5})
+	assert.True(t, ok, "threshold is configurable")
+
+	tie := append(cohort(3, 3), ConsensusMember{ID: uuid.New(), OutcomeEmbedding: jitter(3, 0)})
+	_, ok = DetectConsensusDrift(tie, ConsensusConfig{MaxMinorityFraction: 0.9})
+	assert.False(t, ok, "tied largest clusters have no majority")
+
+	missing := append(cohort(3, 1), ConsensusMember{ID: uuid.New()})
+	_, ok = DetectConsensusDrift(missing, ConsensusConfig{})
+	assert.False(t, ok, "members without outcome embeddings do not count toward the cohort")
+}
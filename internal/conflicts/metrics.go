@@ -24,6 +24,7 @@ type Metrics struct {
 	crossBranchFiltered    metric.Int64Counter
 	selfCorrectionFiltered metric.Int64Counter
 	outcomeSimFiltered     metric.Int64Counter
+	claimOverlapCandidates metric.Int64Counter
 
 	confidenceFloorFiltered metric.Int64Counter
 	noopClaimGateFiltered   metric.Int64Counter
@@ -155,6 +156,14 @@ func (s *Scorer) registerMetrics() {
 		s.metrics.outcomeSimFiltered, _ = meter.Int64Counter("akashi.conflicts.outcome_sim_filtered.fallback")
 	}
 
+	s.metrics.claimOverlapCandidates, err = meter.Int64Counter("akashi.conflicts.claim_overlap_candidates",
+		metric.WithDescription("Candidate decisions found only through claim-overlap retrieval, not decision-level search"),
+	)
+	if err != nil {
+		s.logger.Warn("conflicts: failed to create akashi.conflicts.claim_overlap_candidates metric", "error", err)
+		s.metrics.claimOverlapCandidates, _ = meter.Int64Counter("akashi.conflicts.claim_overlap_candidates.fallback")
+	}
+
 	s.metrics.confidenceFloorFiltered, err = meter.Int64Counter("akashi.conflicts.confidence_floor_filtered",
 		metric.WithDescription("Candidate pairs filtered by confidence floor (both decisions too exploratory)"),
 	)
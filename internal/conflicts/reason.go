@@ -0,0 +1,46 @@
+package conflicts
+
+import (
+	"math"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// ReasonInput holds the scoring signals used to pick a conflict's reason code.
+type ReasonInput struct {
+	TopicSimilarity   float64 // cosine similarity of the two decisions' topics (0-1)
+	OutcomeDivergence float64 // divergence of the winning outcome/claim pair (0-1)
+	ConfidenceA       float32 // decision confidence of side A (0-1); 0 means unknown
+	ConfidenceB       float32 // decision confidence of side B (0-1); 0 means unknown
+	Relationship      string  // LLM-classified relationship; "" when no validator ran
+}
+
+const (
+	// sameInputTopicSimilarity is the topic similarity at or above which two
+	// decisions are treated as answering the same question.
+	sameInputTopicSimilarity = 0.9
+
+	// minConfidenceGap is the smallest confidence difference that can make a
+	// conflict confidence-driven rather than outcome-driven.
+	minConfidenceGap = 0.3
+)
+
+// DeriveReasonCode reports which signal dominated a scored conflict:
+//
+//   - confidence_divergence when the confidence gap is at least minConfidenceGap
+//     and larger than the outcome divergence, unless the LLM confirmed an
+//     outright contradiction.
+//   - same_input_different_outcome when the topics are near-identical.
+//   - outcome_opposite otherwise.
+func DeriveReasonCode(input ReasonInput) model.ConflictReasonCode {
+	if input.ConfidenceA > 0 && input.ConfidenceB > 0 && input.Relationship != "contradiction" {
+		gap := math.Abs(float64(input.ConfidenceA) - float64(input.ConfidenceB))
+		if gap >= minConfidenceGap && gap > input.OutcomeDivergence {
+			return model.ConflictReasonConfidenceDivergence
+		}
+	}
+	if input.TopicSimilarity >= sameInputTopicSimilarity {
+		return model.ConflictReasonSameInputDifferentOutcome
+	}
+	return model.ConflictReasonOutcomeOpposite
+}
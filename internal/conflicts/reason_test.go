@@ -0,0 +1,58 @@
+package conflicts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestDeriveReasonCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input ReasonInput
+		want  model.ConflictReasonCode
+	}{
+		{
+			name:  "divergent outcomes on related topics",
+			input: ReasonInput{TopicSimilarity: 0.75, OutcomeDivergence: 0.6, ConfidenceA: 0.8, ConfidenceB: 0.7},
+			want:  model.ConflictReasonOutcomeOpposite,
+		},
+		{
+			name:  "near-identical topics",
+			input: ReasonInput{TopicSimilarity: 0.95, OutcomeDivergence: 0.5, ConfidenceA: 0.8, ConfidenceB: 0.8},
+			want:  model.ConflictReasonSameInputDifferentOutcome,
+		},
+		{
+			name:  "confidence gap dominates",
+			input: ReasonInput{TopicSimilarity: 0.95, OutcomeDivergence: 0.1, ConfidenceA: 0.9, ConfidenceB: 0.3},
+			want:  model.ConflictReasonConfidenceDivergence,
+		},
+		{
+			name:  "confidence gap below minimum",
+			input: ReasonInput{TopicSimilarity: 0.8, OutcomeDivergence: 0.1, ConfidenceA: 0.9, ConfidenceB: 0.7},
+			want:  model.ConflictReasonOutcomeOpposite,
+		},
+		{
+			name:  "outcome divergence exceeds confidence gap",
+			input: ReasonInput{TopicSimilarity: 0.8, OutcomeDivergence: 0.7, ConfidenceA: 0.9, ConfidenceB: 0.4},
+			want:  model.ConflictReasonOutcomeOpposite,
+		},
+		{
+			name:  "LLM-confirmed contradiction is never confidence-only",
+			input: ReasonInput{TopicSimilarity: 0.8, OutcomeDivergence: 0.1, ConfidenceA: 0.9, ConfidenceB: 0.3, Relationship: "contradiction"},
+			want:  model.ConflictReasonOutcomeOpposite,
+		},
+		{
+			name:  "unknown confidence ignores the gap",
+			input: ReasonInput{TopicSimilarity: 0.8, OutcomeDivergence: 0.1, ConfidenceA: 0.9},
+			want:  model.ConflictReasonOutcomeOpposite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DeriveReasonCode(tt.input))
+		})
+	}
+}
@@ -133,6 +133,17 @@ type Scorer struct {
 	// agreement at similarity >= 0.85, calibrated against 30 real mxbai-embed-large
 	// decisions — see claimDivFloor and defaultOutcomeSimFloor constants).
 	outcomeSimFloor float64
+
+	// claimOverlapLimit is how many nearest existing claims are retrieved per
+	// claim of the scored decision; their parent decisions join the candidate
+	// set. claimOverlapSimFloor is the similarity a neighbor claim must reach.
+	// 0 limit disables claim-overlap retrieval.
+	claimOverlapLimit    int
+	claimOverlapSimFloor float64
+
+	// consensus configures consensus-drift detection over each scored
+	// decision's cohort. MaxMinorityFraction 0 disables.
+	consensus ConsensusConfig
 }
 
 // WithCandidateFinder wires a Qdrant-backed CandidateFinder for conflict candidate
@@ -201,6 +212,40 @@ func (s *Scorer) WithOutcomeSimFloor(floor float64) *Scorer {
 	return s
 }
 
+// WithClaimOverlap configures claim-overlap candidate retrieval: each claim of
+// the scored decision fetches its limit nearest existing claims (same org,
+// decision type, namespace, and project scope) at or above simFloor cosine
+// similarity, and their parent decisions are scored alongside the Qdrant
+// candidates. This catches contradictions between specific claims in
+// decisions whose overall embeddings are not close. The lookup uses the
+// claims ANN index, so cost is bounded by claims x limit rather than the
+// size of the claims table. limit 0 disables; negative values are ignored.
+func (s *Scorer) WithClaimOverlap(limit int, simFloor float64) *Scorer {
+	if limit >= 0 {
+		s.claimOverlapLimit = limit
+	}
+	if simFloor > 0 {
+		s.claimOverlapSimFloor = simFloor
+	}
+	return s
+}
+
+// WithConsensusDrift enables consensus-drift detection: when a scored
+// decision lands in a minority of fewer than maxMinorityFraction of its
+// cohort (the decision plus same-type candidates at or above the decision
+// topic similarity floor, at least minCohort strong), a consensus_drift
+// conflict is recorded. maxMinorityFraction 0 disables; negative values are
+// ignored, as is a non-positive minCohort.
+func (s *Scorer) WithConsensusDrift(maxMinorityFraction float64, minCohort int) *Scorer {
+	if maxMinorityFraction >= 0 {
+		s.consensus.MaxMinorityFraction = maxMinorityFraction
+	}
+	if minCohort > 0 {
+		s.consensus.MinCohort = minCohort
+	}
+	return s
+}
+
 // WithCrossEncoder configures a cross-encoder reranking step between significance
 // scoring and LLM validation. Pairs scoring below the threshold are skipped
 // without an LLM call, reducing validation cost. Only active when using the
@@ -240,6 +285,8 @@ func NewScorer(db *storage.DB, logger *slog.Logger, significanceThreshold float6
 		claimTopicSimFloor:    claimTopicSimFloor,
 		claimDivFloor:         claimDivFloor,
 		decisionTopicSimFloor: decisionTopicSimFloor,
+		claimOverlapLimit:     defaultClaimOverlapLimit,
+		claimOverlapSimFloor:  claimTopicSimFloor,
 	}
 	s.registerMetrics()
 	return s
@@ -262,6 +309,12 @@ const claimDivFloor = 0.15
 // sufficiently different topics that claim-level analysis adds noise.
 const decisionTopicSimFloor = 0.7
 
+// defaultClaimOverlapLimit is the default number of nearest existing claims
+// retrieved per claim for claim-overlap candidates. Decisions rarely carry
+// more than ten conflict-relevant claims, so the default bounds the lookup at
+// about fifty neighbor claims per scored decision.
+const defaultClaimOverlapLimit = 5
+
 // defaultOutcomeSimFloor is the minimum outcome embedding cosine similarity
 // above which two decisions are considered to effectively agree. Derived from
 // the same calibration basis as claimDivFloor: claimDivFloor = 0.15 implies
@@ -276,6 +329,22 @@ const defaultOutcomeSimFloor = 0.85
 // conflicts. Applied before computing cosine similarities to save CPU.
 const confidenceFloorProduct = 0.0225
 
+// pairThreshold returns the significance threshold for a pair of decision
+// types: each type's per-org override, or the global default when it has
+// none, taking the lower of the two so a pair is flagged if either type
+// would flag it.
+func pairThreshold(overrides map[string]float64, global float64, typeA, typeB string) float64 {
+	a, ok := overrides[typeA]
+	if !ok {
+		a = global
+	}
+	b, ok := overrides[typeB]
+	if !ok {
+		b = global
+	}
+	return math.Min(a, b)
+}
+
 // pairCache tracks decision pairs that have already been evaluated within a
 // single backfill run. This prevents duplicate LLM calls when both sides of
 // a pair are processed concurrently (decision A finds B as candidate, and
@@ -333,7 +402,7 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 		return
 	}
 
-	if s.finder == nil {
+	if s.finder == nil && s.claimOverlapLimit == 0 {
 		s.logger.Debug("conflict scorer: no candidate finder configured, skipping", "decision_id", decisionID)
 		return
 	}
@@ -353,12 +422,19 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 		}
 	}
 
-	qdrantResults, err := s.finder.FindSimilar(ctx, orgID, d.Embedding.Slice(), decisionID, projects, s.candidateLimit)
-	if err != nil {
-		s.logger.Warn("conflict scorer: qdrant find similar failed", "decision_id", decisionID, "error", err)
-		return
+	var neighborIDs []uuid.UUID
+	if s.finder != nil {
+		qdrantResults, err := s.finder.FindSimilar(ctx, orgID, d.Embedding.Slice(), decisionID, projects, s.candidateLimit)
+		if err != nil {
+			s.logger.Warn("conflict scorer: qdrant find similar failed", "decision_id", decisionID, "error", err)
+			return
+		}
+		for _, r := range qdrantResults {
+			neighborIDs = append(neighborIDs, r.DecisionID)
+		}
 	}
-	if len(qdrantResults) == 0 {
+	neighborIDs = s.addClaimOverlapCandidates(ctx, d, projects, neighborIDs)
+	if len(neighborIDs) == 0 {
 		return
 	}
 
@@ -366,10 +442,6 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 	// (outcome_embedding, reasoning, agent_context) for the scoring pipeline.
 	// GetDecisionEmbeddings and GetDecisionsByIDs are independent queries on
 	// the same ID set — run them in parallel to halve the latency (#554).
-	neighborIDs := make([]uuid.UUID, len(qdrantResults))
-	for i, r := range qdrantResults {
-		neighborIDs[i] = r.DecisionID
-	}
 
 	var (
 		embMap       map[uuid.UUID][2]pgvector.Vector
@@ -395,17 +467,35 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 	// (i.e. those that have both embeddings). Re-attach embeddings since
 	// GetDecisionsByIDs doesn't return them.
 	candidates := make([]model.Decision, 0, len(embMap))
+	var crossModel int
 	for id, embs := range embMap {
 		cand, ok := candidateMap[id]
 		if !ok {
 			continue
 		}
+		// Conflicts never cross namespaces: staging decisions must not
+		// contradict production ones. Qdrant has no namespace payload, so
+		// the scope is enforced here after hydration.
+		if cand.Namespace != d.Namespace {
+			continue
+		}
+		// Mid-migration the corpus mixes vectors from two embedding models,
+		// and cosine similarity across models is meaningless. Qdrant has no
+		// model payload, so the check also happens here.
+		if !model.EmbeddingModelsCompatible(d.EmbeddingModel, cand.EmbeddingModel) {
+			crossModel++
+			continue
+		}
 		cand.Embedding = &embs[0]
 		cand.OutcomeEmbedding = &embs[1]
 		candidates = append(candidates, cand)
 	}
 
 	if len(candidates) == 0 {
+		if crossModel > 0 {
+			s.logger.Warn("conflict scorer: no same-model neighbors, skipping cross-model candidates",
+				"decision_id", decisionID, "embedding_model", *d.EmbeddingModel, "cross_model_candidates", crossModel)
+		}
 		return
 	}
 
@@ -418,6 +508,8 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 		}
 	}
 
+	s.checkConsensusDrift(ctx, d, candidates, revisionChain)
+
 	// Check once whether an LLM validator is active. Used both for the
 	// directToLLM bypass below and for the validation gate further down.
 	_, isNoop := s.validator.(NoopValidator)
@@ -543,6 +635,30 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 		return rate
 	}
 
+	// --- Per-type and per-org significance thresholds: loaded once, on first use ---
+	var typeThresholds map[string]float64
+	orgThreshold := s.threshold
+	thresholdLookup := func(typeA, typeB string) float64 {
+		if typeThresholds == nil {
+			typeThresholds = make(map[string]float64)
+			overrides, err := s.db.ListConflictThresholds(ctx, orgID)
+			if err != nil {
+				s.logger.Warn("conflict scorer: threshold overrides lookup failed, using global threshold",
+					"decision_id", decisionID, "error", err)
+			}
+			for _, o := range overrides {
+				typeThresholds[o.DecisionType] = o.SignificanceThreshold
+			}
+			orgConfig, err := s.db.GetOrgConfigOverrides(ctx, orgID)
+			if err != nil {
+				s.logger.Warn("conflict scorer: org config overrides lookup failed, using global threshold",
+					"decision_id", decisionID, "error", err)
+			}
+			orgThreshold = orgConfig.SignificanceThreshold(s.threshold)
+		}
+		return pairThreshold(typeThresholds, orgThreshold, typeA, typeB)
+	}
+
 	// --- Sorted iteration with early exit ---
 	examined := 0
 	inserted := 0
@@ -576,7 +692,7 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 		// decision type pair exceeds 80%, double the significance threshold.
 		// This creates a feedback loop from labeled data — type pairs that
 		// consistently produce false positives get stricter gating.
-		effectiveThreshold := s.threshold
+		effectiveThreshold := thresholdLookup(d.DecisionType, sc.cand.DecisionType)
 		fpRate := fpRateLookup(d.DecisionType, sc.cand.DecisionType)
 		if fpRate > 0.80 {
 			effectiveThreshold *= 2.0
@@ -839,6 +955,14 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 			}
 		}
 
+		reasonCode := string(DeriveReasonCode(ReasonInput{
+			TopicSimilarity:   sc.topicSim,
+			OutcomeDivergence: sc.bestDiv,
+			ConfidenceA:       d.Confidence,
+			ConfidenceB:       cand.Confidence,
+			Relationship:      derefString(relationship),
+		}))
+
 		kind := model.ConflictKindCrossAgent
 		if d.AgentID == cand.AgentID {
 			kind = model.ConflictKindSelfContradiction
@@ -873,6 +997,7 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 			Category:           category,
 			Severity:           severity,
 			Relationship:       relationship,
+			ReasonCode:         &reasonCode,
 			ConfidenceWeight:   ptr(sc.confWeight),
 			TemporalDecay:      ptr(sc.decay),
 			Status:             "open",
@@ -1012,6 +1137,51 @@ func (s *Scorer) scoreForDecision(ctx context.Context, decisionID, orgID uuid.UU
 	}
 }
 
+// addClaimOverlapCandidates appends to ids the decisions found by
+// claim-overlap retrieval that are not already present. Lookup failures are
+// logged and leave ids unchanged.
+func (s *Scorer) addClaimOverlapCandidates(ctx context.Context, d model.Decision, projects []string, ids []uuid.UUID) []uuid.UUID {
+	if s.claimOverlapLimit == 0 {
+		return ids
+	}
+	overlap, err := s.db.FindClaimOverlapDecisionIDs(ctx, d.ID, d.OrgID, storage.ClaimOverlapQuery{
+		DecisionType:  d.DecisionType,
+		Namespace:     d.Namespace,
+		Projects:      projects,
+		Categories:    []string{string(ClaimFinding), string(ClaimAssessment)},
+		Limit:         s.claimOverlapLimit,
+		MinSimilarity: s.claimOverlapSimFloor,
+	})
+	if err != nil {
+		s.logger.Warn("conflict scorer: claim overlap lookup failed", "decision_id", d.ID, "error", err)
+		return ids
+	}
+	return mergeCandidateIDs(ids, overlap, func(added int) {
+		s.metrics.claimOverlapCandidates.Add(ctx, int64(added))
+	})
+}
+
+// mergeCandidateIDs appends the IDs in extra that are not already in ids and
+// reports how many were added.
+func mergeCandidateIDs(ids, extra []uuid.UUID, report func(added int)) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	added := 0
+	for _, id := range extra {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+			added++
+		}
+	}
+	if added > 0 {
+		report(added)
+	}
+	return ids
+}
+
 // bestClaimConflict finds the most significant claim-level conflict between
 // two decisions. Returns (significance, divergence, claimTextA, claimTextB).
 // If no claim pairs qualify, returns (0, 0, "", "").
@@ -1074,6 +1244,22 @@ func (s *Scorer) BackfillScoring(ctx context.Context, batchSize int) (int, error
 	if err != nil {
 		return 0, err
 	}
+	return s.scoreRefs(ctx, refs)
+}
+
+// RescoreDecisions re-runs conflict scoring for an explicit set of decisions,
+// regardless of whether they were scored before. Used by the admin rescore
+// endpoint after tuning thresholds or fixing scorer bugs. Existing
+// scored_conflicts rows for the same pairs are updated in place by the
+// InsertScoredConflict upsert, with the same reopen semantics as trace time.
+//
+// Returns the number of decisions processed.
+func (s *Scorer) RescoreDecisions(ctx context.Context, refs []storage.DecisionRef) (int, error) {
+	return s.scoreRefs(ctx, refs)
+}
+
+// scoreRefs scores refs using the backfill worker pool and a shared pair cache.
+func (s *Scorer) scoreRefs(ctx context.Context, refs []storage.DecisionRef) (int, error) {
 	if len(refs) == 0 {
 		return 0, nil
 	}
@@ -4,6 +4,7 @@ package conflicts
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"math"
@@ -292,6 +293,66 @@ func TestScoreForDecision(t *testing.T) {
 	assert.True(t, found, "expected a conflict between decisionA and decisionB")
 }
 
+func TestScoreForDecision_NamespaceIsolation(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	orgID := uuid.Nil
+
+	suffix := uuid.New().String()[:8]
+	agentID := "scorer-ns-" + suffix
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		OrgID:   orgID,
+		Name:    agentID,
+		Role:    model.RoleAgent,
+	})
+	require.NoError(t, err)
+
+	runA := createRun(t, agentID, orgID)
+	runB := createRun(t, agentID, orgID)
+
+	// Same topic, divergent outcomes — would conflict within one namespace.
+	topicEmb := makeEmbedding(0, 1.0)
+	outcomeEmbA := makeEmbedding(1, 1.0)
+	outcomeEmbB := makeEmbedding(2, 1.0)
+	prod, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:            runA.ID,
+		AgentID:          agentID,
+		OrgID:            orgID,
+		DecisionType:     "architecture",
+		Outcome:          "chose Redis for caching",
+		Confidence:       0.8,
+		Embedding:        &topicEmb,
+		OutcomeEmbedding: &outcomeEmbA,
+		Namespace:        "prod",
+	})
+	require.NoError(t, err)
+
+	staging, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:            runB.ID,
+		AgentID:          agentID,
+		OrgID:            orgID,
+		DecisionType:     "architecture",
+		Outcome:          "chose Memcached for caching",
+		Confidence:       0.7,
+		Embedding:        &topicEmb,
+		OutcomeEmbedding: &outcomeEmbB,
+		Namespace:        "staging",
+	})
+	require.NoError(t, err)
+
+	scorer := NewScorer(testDB, logger, 0.1, stubConflictValidator{}, 0, 0)
+	scorer = scorer.WithCandidateFinder(storage.NewPgCandidateFinder(testDB))
+	scorer.ScoreForDecision(ctx, staging.ID, orgID)
+
+	conflicts, err := testDB.ListConflicts(ctx, orgID, storage.ConflictFilters{}, 1000, 0)
+	require.NoError(t, err)
+	for _, c := range conflicts {
+		involvesStaging := c.DecisionAID == staging.ID || c.DecisionBID == staging.ID
+		assert.False(t, involvesStaging, "staging decision must not conflict with decisions in other namespaces (prod decision %s)", prod.ID)
+	}
+}
+
 func TestScoreForDecision_NoEmbeddings(t *testing.T) {
 	ctx := context.Background()
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
@@ -3154,3 +3215,199 @@ func TestBenchmarkDataset_HasFPCategories(t *testing.T) {
 	assert.Equal(t, 40, labelCounts["related_not_contradicting"], "10 original + 30 FP category pairs")
 	assert.Equal(t, 10, labelCounts["unrelated_false_positive"])
 }
+
+func TestScoreForDecision_ClaimOverlapWithoutFinder(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.Nil
+	suffix := uuid.New().String()[:8]
+	agentID := "claim-overlap-" + suffix
+	decisionType := "claim_overlap_" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: orgID, Name: agentID, Role: model.RoleAgent,
+	})
+	require.NoError(t, err)
+
+	topicEmb := makeEmbedding(260, 1.0)
+	outcomeA := makeEmbedding(261, 1.0)
+	outcomeB := makeEmbedding(262, 1.0)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: createRun(t, agentID, orgID).ID, AgentID: agentID, OrgID: orgID,
+		DecisionType: decisionType, Outcome: "Cache invalidation is handled by TTLs.",
+		Confidence: 0.8, Embedding: &topicEmb, OutcomeEmbedding: &outcomeA,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: createRun(t, agentID, orgID).ID, AgentID: agentID, OrgID: orgID,
+		DecisionType: decisionType, Outcome: "Cache invalidation needs explicit purges.",
+		Confidence: 0.8, Embedding: &topicEmb, OutcomeEmbedding: &outcomeB,
+	})
+	require.NoError(t, err)
+
+	claimA, claimB := makeClaimVectorPair(510, 511, 0.70)
+	require.NoError(t, testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: dA.ID, OrgID: orgID, ClaimIdx: 0, ClaimText: "TTLs are enough to invalidate the cache.", Embedding: &claimA},
+	}))
+	require.NoError(t, testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: dB.ID, OrgID: orgID, ClaimIdx: 0, ClaimText: "TTLs are not enough to invalidate the cache.", Embedding: &claimB},
+	}))
+
+	ids, err := testDB.FindClaimOverlapDecisionIDs(ctx, dB.ID, orgID, storage.ClaimOverlapQuery{
+		DecisionType: decisionType, Namespace: dB.Namespace,
+		Categories: []string{string(ClaimFinding)}, Limit: 5, MinSimilarity: 0.6,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{dA.ID}, ids)
+
+	pairConflicts := func() int {
+		conflicts, err := testDB.ListConflicts(ctx, orgID, storage.ConflictFilters{DecisionType: &decisionType}, 100, 0)
+		require.NoError(t, err)
+		return len(conflicts)
+	}
+
+	// No Qdrant finder and claim overlap disabled: nothing to score against.
+	NewScorer(testDB, slog.Default(), 0.1, nil, 0, 0).WithClaimOverlap(0, 0).ScoreForDecision(ctx, dB.ID, orgID)
+	assert.Equal(t, 0, pairConflicts())
+
+	// Claim-overlap retrieval alone surfaces dA as a candidate.
+	NewScorer(testDB, slog.Default(), 0.1, nil, 0, 0).ScoreForDecision(ctx, dB.ID, orgID)
+	assert.Equal(t, 1, pairConflicts())
+}
+
+func TestScoreForDecision_PerTypeThreshold(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	orgID := uuid.Nil
+	suffix := uuid.New().String()[:8]
+
+	agentA := "threshold-a-" + suffix
+	agentB := "threshold-b-" + suffix
+	for _, id := range []string{agentA, agentB} {
+		_, err := testDB.CreateAgent(ctx, model.Agent{AgentID: id, OrgID: orgID, Name: id, Role: model.RoleAgent})
+		require.NoError(t, err)
+	}
+
+	// A binary type flags at low divergence; a tolerant type needs more.
+	strictType := "loan_approval_" + suffix
+	tolerantType := "routing_" + suffix
+	audit := storage.MutationAuditEntry{
+		RequestID: "threshold-" + suffix, OrgID: orgID,
+		ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "set_conflict_threshold", ResourceType: "conflict_threshold",
+	}
+	for typ, threshold := range map[string]float64{strictType: 0.2, tolerantType: 0.6} {
+		_, err := testDB.UpsertConflictThreshold(ctx, model.ConflictThreshold{
+			OrgID: orgID, DecisionType: typ, SignificanceThreshold: threshold, UpdatedBy: "admin",
+		}, audit)
+		require.NoError(t, err)
+	}
+
+	// Each type gets an identical pair: topic similarity 0.6 (below the
+	// direct-to-validator bypass) and orthogonal outcomes, so significance is
+	// 0.6 * 1.0 * 0.9 = 0.54 for both — above the strict threshold, below
+	// the tolerant one, and above the 0.3 global default.
+	scorePair := func(decisionType string, base int) uuid.UUID {
+		topicA, topicB := makeClaimVectorPair(base, base+1, 0.6)
+		outcomeA := makeEmbedding(base+2, 1.0)
+		outcomeB := makeEmbedding(base+3, 1.0)
+		first, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: createRun(t, agentA, orgID).ID, AgentID: agentA, OrgID: orgID,
+			DecisionType: decisionType, Outcome: "approve", Confidence: 0.9,
+			Embedding: &topicA, OutcomeEmbedding: &outcomeA,
+		})
+		require.NoError(t, err)
+		second, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: createRun(t, agentB, orgID).ID, AgentID: agentB, OrgID: orgID,
+			DecisionType: decisionType, Outcome: "deny", Confidence: 0.9,
+			Embedding: &topicB, OutcomeEmbedding: &outcomeB,
+		})
+		require.NoError(t, err)
+
+		scorer := NewScorer(testDB, logger, 0.3, stubConflictValidator{}, 0, 0).
+			WithCandidateFinder(storage.NewPgCandidateFinder(testDB))
+		scorer.ScoreForDecision(ctx, second.ID, orgID)
+		return first.ID
+	}
+	strictFirst := scorePair(strictType, 960)
+	tolerantFirst := scorePair(tolerantType, 970)
+
+	conflicts, err := testDB.ListConflicts(ctx, orgID, storage.ConflictFilters{}, 1000, 0)
+	require.NoError(t, err)
+	var strictCount, tolerantCount int
+	for _, c := range conflicts {
+		switch {
+		case c.DecisionAID == strictFirst || c.DecisionBID == strictFirst:
+			strictCount++
+		case c.DecisionAID == tolerantFirst || c.DecisionBID == tolerantFirst:
+			tolerantCount++
+		}
+	}
+	assert.Equal(t, 1, strictCount, "strict type should flag the pair")
+	assert.Equal(t, 0, tolerantCount, "tolerant type should not flag an identical pair")
+}
+
+func TestScoreForDecision_OrgThresholdOverride(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	// Two fresh orgs score an identical pair with significance 0.54 (see
+	// TestScoreForDecision_PerTypeThreshold). Only the overriding org raises
+	// its threshold above that.
+	overriddenOrg, defaultOrg := uuid.New(), uuid.New()
+	for _, org := range []uuid.UUID{overriddenOrg, defaultOrg} {
+		_, err := testDB.Pool().Exec(ctx,
+			`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
+			org, "org-threshold-"+suffix, "org-threshold-"+org.String()[:8])
+		require.NoError(t, err)
+	}
+	require.NoError(t, testDB.SetOrgSetting(ctx, overriddenOrg, model.OrgSettingConfigOverrides,
+		json.RawMessage(`{"conflict_significance_threshold": 0.6}`), "admin",
+		storage.MutationAuditEntry{
+			RequestID: "org-threshold-" + suffix, OrgID: overriddenOrg,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "org_setting_updated", ResourceType: "org_settings", ResourceID: overriddenOrg.String(),
+		}))
+
+	scorePair := func(orgID uuid.UUID, base int) int {
+		agentA, agentB := "org-threshold-a-"+suffix, "org-threshold-b-"+suffix
+		for _, id := range []string{agentA, agentB} {
+			_, err := testDB.CreateAgent(ctx, model.Agent{AgentID: id, OrgID: orgID, Name: id, Role: model.RoleAgent})
+			require.NoError(t, err)
+		}
+		topicA, topicB := makeClaimVectorPair(base, base+1, 0.6)
+		outcomeA := makeEmbedding(base+2, 1.0)
+		outcomeB := makeEmbedding(base+3, 1.0)
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: createRun(t, agentA, orgID).ID, AgentID: agentA, OrgID: orgID,
+			DecisionType: "architecture", Outcome: "approve", Confidence: 0.9,
+			Embedding: &topicA, OutcomeEmbedding: &outcomeA,
+		})
+		require.NoError(t, err)
+		second, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: createRun(t, agentB, orgID).ID, AgentID: agentB, OrgID: orgID,
+			DecisionType: "architecture", Outcome: "deny", Confidence: 0.9,
+			Embedding: &topicB, OutcomeEmbedding: &outcomeB,
+		})
+		require.NoError(t, err)
+
+		NewScorer(testDB, slog.Default(), 0.3, stubConflictValidator{}, 0, 0).
+			WithCandidateFinder(storage.NewPgCandidateFinder(testDB)).
+			ScoreForDecision(ctx, second.ID, orgID)
+
+		conflicts, err := testDB.ListConflicts(ctx, orgID, storage.ConflictFilters{}, 100, 0)
+		require.NoError(t, err)
+		return len(conflicts)
+	}
+
+	assert.Equal(t, 0, scorePair(overriddenOrg, 1000), "org override of 0.6 should suppress the pair")
+	assert.Equal(t, 1, scorePair(defaultOrg, 1010), "other orgs keep the global 0.3 threshold")
+}
+
+func TestPairThreshold(t *testing.T) {
+	overrides := map[string]float64{"loan_approval": 0.1, "routing": 0.6}
+	assert.Equal(t, 0.3, pairThreshold(overrides, 0.3, "architecture", "architecture"))
+	assert.Equal(t, 0.6, pairThreshold(overrides, 0.3, "routing", "routing"))
+	assert.Equal(t, 0.3, pairThreshold(overrides, 0.3, "routing", "architecture"))
+	assert.Equal(t, 0.1, pairThreshold(overrides, 0.3, "routing", "loan_approval"))
+}
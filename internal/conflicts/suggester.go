@@ -0,0 +1,260 @@
+package conflicts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ashita-ai/akashi/internal/compact"
+)
+
+// SuggestInput holds the conflict context given to a Suggester.
+type SuggestInput struct {
+	DecisionType string
+	AgentA       string
+	AgentB       string
+	OutcomeA     string
+	OutcomeB     string
+	ReasoningA   string
+	ReasoningB   string
+	// Explanation is the validator's classification note, when present.
+	Explanation string
+}
+
+// Suggestion is an LLM-written triage note for a detected conflict.
+type Suggestion struct {
+	Explanation string // Why the two decisions conflict.
+	Resolution  string // A suggested way to resolve the conflict.
+}
+
+// Suggester pre-explains detected conflicts so reviewers do not have to read
+// both decisions in full before triaging. Unlike the Validator it never
+// decides whether a conflict exists; it only describes one already detected.
+type Suggester interface {
+	Suggest(ctx context.Context, input SuggestInput) (Suggestion, error)
+	// Model names the LLM producing suggestions, recorded alongside them.
+	Model() string
+}
+
+// maxSuggestionFieldLen caps each stored suggestion field. The prompt asks for
+// a few sentences; anything longer is the model rambling.
+const maxSuggestionFieldLen = 1000
+
+// suggesterPrompt is the system prompt for conflict resolution suggestions.
+const suggesterPrompt = `You help reviewers triage conflicts between decisions recorded by AI agents.
+
+You are given two decisions that an automated detector flagged as conflicting.
+Write:
+1. "explanation": one to three sentences on why the two decisions conflict — the specific point they disagree on.
+2. "resolution": one to three sentences suggesting how to resolve it — which decision to prefer and why, what evidence would settle it, or how the decisions could be reconciled.
+
+Be concrete and refer to the decisions' content. Do not restate both decisions in full.
+
+Respond with a JSON object only. No markdown.
+Example: {"explanation":"Agent A chose Redis for session storage while agent B chose Postgres for the same sessions.","resolution":"Prefer Postgres unless session reads exceed what the primary can serve; benchmark read volume before deciding."}`
+
+// formatSuggestPrompt renders the user message for a suggestion request.
+func formatSuggestPrompt(input SuggestInput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Decision type: %s\n\n", input.DecisionType)
+	fmt.Fprintf(&b, "Decision A (agent %s):\n%s\n", input.AgentA, compact.Truncate(input.OutcomeA, 1500))
+	if input.ReasoningA != "" {
+		fmt.Fprintf(&b, "[Reasoning: %s]\n", compact.Truncate(input.ReasoningA, 500))
+	}
+	fmt.Fprintf(&b, "\nDecision B (agent %s):\n%s\n", input.AgentB, compact.Truncate(input.OutcomeB, 1500))
+	if input.ReasoningB != "" {
+		fmt.Fprintf(&b, "[Reasoning: %s]\n", compact.Truncate(input.ReasoningB, 500))
+	}
+	if input.Explanation != "" {
+		fmt.Fprintf(&b, "\nDetector note: %s\n", compact.Truncate(input.Explanation, 300))
+	}
+	return b.String()
+}
+
+// SuggestionParseError reports an LLM reply that could not be turned into a
+// Suggestion. Retrying the same conflict is unlikely to help.
+type SuggestionParseError struct {
+	Err error
+}
+
+func (e *SuggestionParseError) Error() string { return "suggester: " + e.Err.Error() }
+
+func (e *SuggestionParseError) Unwrap() error { return e.Err }
+
+// ParseSuggestionResponse parses the LLM's JSON reply into a Suggestion.
+// Malformed replies return a *SuggestionParseError.
+func ParseSuggestionResponse(raw string) (Suggestion, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "```") {
+		lines := strings.Split(raw, "\n")
+		if len(lines) >= 3 {
+			raw = strings.Join(lines[1:len(lines)-1], "\n")
+		}
+	}
+	raw = strings.TrimSpace(raw)
+
+	var out struct {
+		Explanation string `json:"explanation"`
+		Resolution  string `json:"resolution"`
+	}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		return Suggestion{}, &SuggestionParseError{Err: fmt.Errorf("parse JSON: %w (raw: %.200s)", err, raw)}
+	}
+	s := Suggestion{
+		Explanation: compact.Truncate(strings.TrimSpace(out.Explanation), maxSuggestionFieldLen),
+		Resolution:  compact.Truncate(strings.TrimSpace(out.Resolution), maxSuggestionFieldLen),
+	}
+	if s.Explanation == "" || s.Resolution == "" {
+		return Suggestion{}, &SuggestionParseError{Err: errors.New("response missing explanation or resolution")}
+	}
+	return s, nil
+}
+
+// OllamaSuggester writes conflict suggestions using a local Ollama chat model.
+type OllamaSuggester struct {
+	baseURL    string
+	model      string
+	numThreads int
+	httpClient *http.Client
+}
+
+// NewOllamaSuggester creates a suggester backed by Ollama.
+func NewOllamaSuggester(baseURL, model string, numThreads int) *OllamaSuggester {
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	return &OllamaSuggester{
+		baseURL:    baseURL,
+		model:      model,
+		numThreads: numThreads,
+		httpClient: &http.Client{
+			Timeout: ollamaPerCallTimeout + 5*time.Second,
+		},
+	}
+}
+
+// Model returns the Ollama model name.
+func (s *OllamaSuggester) Model() string { return s.model }
+
+func (s *OllamaSuggester) Suggest(ctx context.Context, input SuggestInput) (Suggestion, error) {
+	callCtx, cancel := context.WithTimeout(ctx, ollamaPerCallTimeout)
+	defer cancel()
+
+	var opts *ollamaOptions
+	if s.numThreads > 0 {
+		opts = &ollamaOptions{NumThread: s.numThreads}
+	}
+
+	body, err := json.Marshal(ollamaChatRequest{
+		Model: s.model,
+		Messages: []ollamaChatMessage{
+			{Role: "system", Content: suggesterPrompt},
+			{Role: "user", Content: formatSuggestPrompt(input)},
+		},
+		Stream:    false,
+		KeepAlive: "72h",
+		Options:   opts,
+	})
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("ollama suggester: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, s.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("ollama suggester: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("ollama suggester: request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Suggestion{}, fmt.Errorf("ollama suggester: status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Suggestion{}, fmt.Errorf("ollama suggester: decode response: %w", err)
+	}
+
+	return ParseSuggestionResponse(result.Message.Content)
+}
+
+// OpenAISuggester writes conflict suggestions using the OpenAI chat API.
+type OpenAISuggester struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOpenAISuggester creates a suggester backed by OpenAI.
+func NewOpenAISuggester(apiKey, model string) *OpenAISuggester {
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &OpenAISuggester{
+		apiKey: apiKey,
+		model:  model,
+		httpClient: &http.Client{
+			Timeout: perCallTimeout + 5*time.Second,
+		},
+	}
+}
+
+// Model returns the OpenAI model name.
+func (s *OpenAISuggester) Model() string { return s.model }
+
+func (s *OpenAISuggester) Suggest(ctx context.Context, input SuggestInput) (Suggestion, error) {
+	callCtx, cancel := context.WithTimeout(ctx, perCallTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(openAIChatRequest{
+		Model: s.model,
+		Messages: []openAIChatMessage{
+			{Role: "system", Content: suggesterPrompt},
+			{Role: "user", Content: formatSuggestPrompt(input)},
+		},
+	})
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("openai suggester: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(callCtx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("openai suggester: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return Suggestion{}, fmt.Errorf("openai suggester: request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Suggestion{}, fmt.Errorf("openai suggester: status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Suggestion{}, fmt.Errorf("openai suggester: decode response: %w", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return Suggestion{}, fmt.Errorf("openai suggester: no choices in response")
+	}
+
+	return ParseSuggestionResponse(result.Choices[0].Message.Content)
+}
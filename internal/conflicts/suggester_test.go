@@ -0,0 +1,90 @@
+package conflicts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuggestionResponse_ValidJSON(t *testing.T) {
+	s, err := ParseSuggestionResponse(`{"explanation":"They pick different caches.","resolution":"Prefer Redis; it is already deployed."}`)
+	require.NoError(t, err)
+	assert.Equal(t, "They pick different caches.", s.Explanation)
+	assert.Equal(t, "Prefer Redis; it is already deployed.", s.Resolution)
+}
+
+func TestParseSuggestionResponse_MarkdownCodeFence(t *testing.T) {
+	raw := "```json\n" + `{"explanation":"x differs","resolution":"pick x"}` + "\n```"
+	s, err := ParseSuggestionResponse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "pick x", s.Resolution)
+}
+
+func TestParseSuggestionResponse_Invalid(t *testing.T) {
+	_, err := ParseSuggestionResponse("The decisions conflict because...")
+	var perr *SuggestionParseError
+	assert.ErrorAs(t, err, &perr)
+
+	_, err = ParseSuggestionResponse(`{"explanation":"only half"}`)
+	assert.ErrorAs(t, err, &perr)
+}
+
+func TestParseSuggestionResponse_TruncatesLongFields(t *testing.T) {
+	long := strings.Repeat("a", 5*maxSuggestionFieldLen)
+	s, err := ParseSuggestionResponse(`{"explanation":"` + long + `","resolution":"ok"}`)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(s.Explanation), maxSuggestionFieldLen+3)
+}
+
+func TestOllamaSuggester_Suggest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+
+		var req ollamaChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "test-model", req.Model)
+		require.Len(t, req.Messages, 2)
+		assert.Equal(t, "system", req.Messages[0].Role)
+		assert.Contains(t, req.Messages[1].Content, "chose Redis for caching")
+		assert.Contains(t, req.Messages[1].Content, "chose Memcached for caching")
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ollamaChatResponse{
+			Message: struct {
+				Content string `json:"content"`
+			}{
+				Content: `{"explanation":"Both pick a cache for the same service.","resolution":"Keep Redis; Memcached lacks persistence the service needs."}`,
+			},
+		})
+	}))
+	defer srv.Close()
+
+	s := NewOllamaSuggester(srv.URL, "test-model", 0)
+	assert.Equal(t, "test-model", s.Model())
+	got, err := s.Suggest(context.Background(), SuggestInput{
+		DecisionType: "architecture",
+		AgentA:       "planner",
+		AgentB:       "coder",
+		OutcomeA:     "chose Redis for caching",
+		OutcomeB:     "chose Memcached for caching",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, got.Explanation, "cache")
+	assert.Contains(t, got.Resolution, "Redis")
+}
+
+func TestOllamaSuggester_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewOllamaSuggester(srv.URL, "test-model", 0).Suggest(context.Background(), SuggestInput{})
+	assert.ErrorContains(t, err, "status 500")
+}
@@ -12,13 +12,15 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/model"
 )
 
 type contextKey string
 
 const (
-	keyClaims contextKey = "claims"
-	keyOrgID  contextKey = "org_id"
+	keyClaims    contextKey = "claims"
+	keyOrgID     contextKey = "org_id"
+	keyNamespace contextKey = "namespace"
 )
 
 // WithClaims returns a new context carrying the given claims.
@@ -43,3 +45,17 @@ func OrgIDFromContext(ctx context.Context) uuid.UUID {
 	}
 	return uuid.Nil
 }
+
+// WithNamespace returns a new context carrying the resolved decision namespace.
+func WithNamespace(ctx context.Context, ns string) context.Context {
+	return context.WithValue(ctx, keyNamespace, ns)
+}
+
+// NamespaceFromContext extracts the decision namespace from the context,
+// falling back to model.DefaultNamespace when none was resolved.
+func NamespaceFromContext(ctx context.Context) string {
+	if v, ok := ctx.Value(keyNamespace).(string); ok && v != "" {
+		return v
+	}
+	return model.DefaultNamespace
+}
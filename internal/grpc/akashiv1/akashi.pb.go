@@ -0,0 +1,1670 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: akashi/v1/akashi.proto
+
+package akashiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TraceRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	TraceId         *string                `protobuf:"bytes,2,opt,name=trace_id,json=traceId,proto3,oneof" json:"trace_id,omitempty"`
+	Decision        *TraceDecision         `protobuf:"bytes,3,opt,name=decision,proto3" json:"decision,omitempty"`
+	Metadata        *structpb.Struct       `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	PrecedentRef    *string                `protobuf:"bytes,5,opt,name=precedent_ref,json=precedentRef,proto3,oneof" json:"precedent_ref,omitempty"`
+	PrecedentReason *string                `protobuf:"bytes,6,opt,name=precedent_reason,json=precedentReason,proto3,oneof" json:"precedent_reason,omitempty"`
+	SupersedesId    *string                `protobuf:"bytes,7,opt,name=supersedes_id,json=supersedesId,proto3,oneof" json:"supersedes_id,omitempty"`
+	SessionId       *string                `protobuf:"bytes,8,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
+	// Self-reported context, recorded under agent_context.client.
+	Model   *string `protobuf:"bytes,9,opt,name=model,proto3,oneof" json:"model,omitempty"`
+	Task    *string `protobuf:"bytes,10,opt,name=task,proto3,oneof" json:"task,omitempty"`
+	Project *string `protobuf:"bytes,11,opt,name=project,proto3,oneof" json:"project,omitempty"`
+	// Update the agent's standing decision: supersede its most recent active
+	// decision of the same type whose metadata matches on these keys, like
+	// supersede_matching in POST /v1/trace. Exclusive with supersedes_id.
+	SupersedeMatching *SupersedeMatching `protobuf:"bytes,12,opt,name=supersede_matching,json=supersedeMatching,proto3" json:"supersede_matching,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TraceRequest) Reset() {
+	*x = TraceRequest{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceRequest) ProtoMessage() {}
+
+func (x *TraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceRequest.ProtoReflect.Descriptor instead.
+func (*TraceRequest) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TraceRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetTraceId() string {
+	if x != nil && x.TraceId != nil {
+		return *x.TraceId
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetDecision() *TraceDecision {
+	if x != nil {
+		return x.Decision
+	}
+	return nil
+}
+
+func (x *TraceRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *TraceRequest) GetPrecedentRef() string {
+	if x != nil && x.PrecedentRef != nil {
+		return *x.PrecedentRef
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetPrecedentReason() string {
+	if x != nil && x.PrecedentReason != nil {
+		return *x.PrecedentReason
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetSupersedesId() string {
+	if x != nil && x.SupersedesId != nil {
+		return *x.SupersedesId
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetSessionId() string {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetModel() string {
+	if x != nil && x.Model != nil {
+		return *x.Model
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetTask() string {
+	if x != nil && x.Task != nil {
+		return *x.Task
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetProject() string {
+	if x != nil && x.Project != nil {
+		return *x.Project
+	}
+	return ""
+}
+
+func (x *TraceRequest) GetSupersedeMatching() *SupersedeMatching {
+	if x != nil {
+		return x.SupersedeMatching
+	}
+	return nil
+}
+
+type TraceDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DecisionType  string                 `protobuf:"bytes,1,opt,name=decision_type,json=decisionType,proto3" json:"decision_type,omitempty"`
+	Outcome       string                 `protobuf:"bytes,2,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Confidence    float32                `protobuf:"fixed32,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Reasoning     *string                `protobuf:"bytes,4,opt,name=reasoning,proto3,oneof" json:"reasoning,omitempty"`
+	Alternatives  []*TraceAlternative    `protobuf:"bytes,5,rep,name=alternatives,proto3" json:"alternatives,omitempty"`
+	Evidence      []*TraceEvidence       `protobuf:"bytes,6,rep,name=evidence,proto3" json:"evidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceDecision) Reset() {
+	*x = TraceDecision{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceDecision) ProtoMessage() {}
+
+func (x *TraceDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceDecision.ProtoReflect.Descriptor instead.
+func (*TraceDecision) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TraceDecision) GetDecisionType() string {
+	if x != nil {
+		return x.DecisionType
+	}
+	return ""
+}
+
+func (x *TraceDecision) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *TraceDecision) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *TraceDecision) GetReasoning() string {
+	if x != nil && x.Reasoning != nil {
+		return *x.Reasoning
+	}
+	return ""
+}
+
+func (x *TraceDecision) GetAlternatives() []*TraceAlternative {
+	if x != nil {
+		return x.Alternatives
+	}
+	return nil
+}
+
+func (x *TraceDecision) GetEvidence() []*TraceEvidence {
+	if x != nil {
+		return x.Evidence
+	}
+	return nil
+}
+
+type TraceAlternative struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Label           string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	RejectionReason *string                `protobuf:"bytes,2,opt,name=rejection_reason,json=rejectionReason,proto3,oneof" json:"rejection_reason,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TraceAlternative) Reset() {
+	*x = TraceAlternative{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceAlternative) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceAlternative) ProtoMessage() {}
+
+func (x *TraceAlternative) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceAlternative.ProtoReflect.Descriptor instead.
+func (*TraceAlternative) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TraceAlternative) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *TraceAlternative) GetRejectionReason() string {
+	if x != nil && x.RejectionReason != nil {
+		return *x.RejectionReason
+	}
+	return ""
+}
+
+type TraceEvidence struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SourceType     string                 `protobuf:"bytes,1,opt,name=source_type,json=sourceType,proto3" json:"source_type,omitempty"`
+	SourceUri      *string                `protobuf:"bytes,2,opt,name=source_uri,json=sourceUri,proto3,oneof" json:"source_uri,omitempty"`
+	Content        string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	RelevanceScore *float32               `protobuf:"fixed32,4,opt,name=relevance_score,json=relevanceScore,proto3,oneof" json:"relevance_score,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TraceEvidence) Reset() {
+	*x = TraceEvidence{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceEvidence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceEvidence) ProtoMessage() {}
+
+func (x *TraceEvidence) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceEvidence.ProtoReflect.Descriptor instead.
+func (*TraceEvidence) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TraceEvidence) GetSourceType() string {
+	if x != nil {
+		return x.SourceType
+	}
+	return ""
+}
+
+func (x *TraceEvidence) GetSourceUri() string {
+	if x != nil && x.SourceUri != nil {
+		return *x.SourceUri
+	}
+	return ""
+}
+
+func (x *TraceEvidence) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *TraceEvidence) GetRelevanceScore() float32 {
+	if x != nil && x.RelevanceScore != nil {
+		return *x.RelevanceScore
+	}
+	return 0
+}
+
+type SupersedeMatching struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupersedeMatching) Reset() {
+	*x = SupersedeMatching{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupersedeMatching) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupersedeMatching) ProtoMessage() {}
+
+func (x *SupersedeMatching) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupersedeMatching.ProtoReflect.Descriptor instead.
+func (*SupersedeMatching) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SupersedeMatching) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type TraceResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RunId            string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	DecisionId       string                 `protobuf:"bytes,2,opt,name=decision_id,json=decisionId,proto3" json:"decision_id,omitempty"`
+	EventCount       int32                  `protobuf:"varint,3,opt,name=event_count,json=eventCount,proto3" json:"event_count,omitempty"`
+	EmbeddingSkipped bool                   `protobuf:"varint,4,opt,name=embedding_skipped,json=embeddingSkipped,proto3" json:"embedding_skipped,omitempty"`
+	SupersededId     *string                `protobuf:"bytes,5,opt,name=superseded_id,json=supersededId,proto3,oneof" json:"superseded_id,omitempty"`
+	// Set when the trace matched an existing decision and nothing was recorded.
+	DuplicateOf   *string `protobuf:"bytes,6,opt,name=duplicate_of,json=duplicateOf,proto3,oneof" json:"duplicate_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceResponse) Reset() {
+	*x = TraceResponse{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResponse) ProtoMessage() {}
+
+func (x *TraceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResponse.ProtoReflect.Descriptor instead.
+func (*TraceResponse) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TraceResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *TraceResponse) GetDecisionId() string {
+	if x != nil {
+		return x.DecisionId
+	}
+	return ""
+}
+
+func (x *TraceResponse) GetEventCount() int32 {
+	if x != nil {
+		return x.EventCount
+	}
+	return 0
+}
+
+func (x *TraceResponse) GetEmbeddingSkipped() bool {
+	if x != nil {
+		return x.EmbeddingSkipped
+	}
+	return false
+}
+
+func (x *TraceResponse) GetSupersededId() string {
+	if x != nil && x.SupersededId != nil {
+		return *x.SupersededId
+	}
+	return ""
+}
+
+func (x *TraceResponse) GetDuplicateOf() string {
+	if x != nil && x.DuplicateOf != nil {
+		return *x.DuplicateOf
+	}
+	return ""
+}
+
+type QueryFilters struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentIds      []string               `protobuf:"bytes,1,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	DecisionTypes []string               `protobuf:"bytes,2,rep,name=decision_types,json=decisionTypes,proto3" json:"decision_types,omitempty"`
+	ConfidenceMin *float32               `protobuf:"fixed32,3,opt,name=confidence_min,json=confidenceMin,proto3,oneof" json:"confidence_min,omitempty"`
+	Outcome       *string                `protobuf:"bytes,4,opt,name=outcome,proto3,oneof" json:"outcome,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	SessionId     *string                `protobuf:"bytes,7,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
+	Project       *string                `protobuf:"bytes,8,opt,name=project,proto3,oneof" json:"project,omitempty"`
+	Tags          []string               `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryFilters) Reset() {
+	*x = QueryFilters{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryFilters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryFilters) ProtoMessage() {}
+
+func (x *QueryFilters) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryFilters.ProtoReflect.Descriptor instead.
+func (*QueryFilters) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueryFilters) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *QueryFilters) GetDecisionTypes() []string {
+	if x != nil {
+		return x.DecisionTypes
+	}
+	return nil
+}
+
+func (x *QueryFilters) GetConfidenceMin() float32 {
+	if x != nil && x.ConfidenceMin != nil {
+		return *x.ConfidenceMin
+	}
+	return 0
+}
+
+func (x *QueryFilters) GetOutcome() string {
+	if x != nil && x.Outcome != nil {
+		return *x.Outcome
+	}
+	return ""
+}
+
+func (x *QueryFilters) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *QueryFilters) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *QueryFilters) GetSessionId() string {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return ""
+}
+
+func (x *QueryFilters) GetProject() string {
+	if x != nil && x.Project != nil {
+		return *x.Project
+	}
+	return ""
+}
+
+func (x *QueryFilters) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type QueryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filters       *QueryFilters          `protobuf:"bytes,1,opt,name=filters,proto3" json:"filters,omitempty"`
+	OrderBy       string                 `protobuf:"bytes,2,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	OrderDir      string                 `protobuf:"bytes,3,opt,name=order_dir,json=orderDir,proto3" json:"order_dir,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryRequest) Reset() {
+	*x = QueryRequest{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryRequest) ProtoMessage() {}
+
+func (x *QueryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryRequest.ProtoReflect.Descriptor instead.
+func (*QueryRequest) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueryRequest) GetFilters() *QueryFilters {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *QueryRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetOrderDir() string {
+	if x != nil {
+		return x.OrderDir
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type QueryResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Decisions []*Decision            `protobuf:"bytes,1,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	// Unset when access filtering removed results, so the total is unknown.
+	Total         *int32 `protobuf:"varint,2,opt,name=total,proto3,oneof" json:"total,omitempty"`
+	HasMore       bool   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse) ProtoMessage() {}
+
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *QueryResponse) GetDecisions() []*Decision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetTotal() int32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type CheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DecisionType  string                 `protobuf:"bytes,1,opt,name=decision_type,json=decisionType,proto3" json:"decision_type,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Project       string                 `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckRequest) GetDecisionType() string {
+	if x != nil {
+		return x.DecisionType
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *CheckRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CheckResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	HasPrecedent         bool                   `protobuf:"varint,1,opt,name=has_precedent,json=hasPrecedent,proto3" json:"has_precedent,omitempty"`
+	Decisions            []*Decision            `protobuf:"bytes,2,rep,name=decisions,proto3" json:"decisions,omitempty"`
+	Conflicts            []*Conflict            `protobuf:"bytes,3,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	ConflictsUnavailable bool                   `protobuf:"varint,4,opt,name=conflicts_unavailable,json=conflictsUnavailable,proto3" json:"conflicts_unavailable,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResponse) ProtoMessage() {}
+
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckResponse) GetHasPrecedent() bool {
+	if x != nil {
+		return x.HasPrecedent
+	}
+	return false
+}
+
+func (x *CheckResponse) GetDecisions() []*Decision {
+	if x != nil {
+		return x.Decisions
+	}
+	return nil
+}
+
+func (x *CheckResponse) GetConflicts() []*Conflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+func (x *CheckResponse) GetConflictsUnavailable() bool {
+	if x != nil {
+		return x.ConflictsUnavailable
+	}
+	return false
+}
+
+type Decision struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RunId             string                 `protobuf:"bytes,2,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	AgentId           string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	OrgId             string                 `protobuf:"bytes,4,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	DecisionType      string                 `protobuf:"bytes,5,opt,name=decision_type,json=decisionType,proto3" json:"decision_type,omitempty"`
+	Outcome           string                 `protobuf:"bytes,6,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Confidence        float32                `protobuf:"fixed32,7,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Reasoning         *string                `protobuf:"bytes,8,opt,name=reasoning,proto3,oneof" json:"reasoning,omitempty"`
+	Metadata          *structpb.Struct       `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CompletenessScore float32                `protobuf:"fixed32,10,opt,name=completeness_score,json=completenessScore,proto3" json:"completeness_score,omitempty"`
+	PrecedentRef      *string                `protobuf:"bytes,11,opt,name=precedent_ref,json=precedentRef,proto3,oneof" json:"precedent_ref,omitempty"`
+	SupersedesId      *string                `protobuf:"bytes,12,opt,name=supersedes_id,json=supersedesId,proto3,oneof" json:"supersedes_id,omitempty"`
+	ContentHash       string                 `protobuf:"bytes,13,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	ValidFrom         *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidTo           *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=valid_to,json=validTo,proto3" json:"valid_to,omitempty"`
+	TransactionTime   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=transaction_time,json=transactionTime,proto3" json:"transaction_time,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SessionId         *string                `protobuf:"bytes,18,opt,name=session_id,json=sessionId,proto3,oneof" json:"session_id,omitempty"`
+	Project           *string                `protobuf:"bytes,19,opt,name=project,proto3,oneof" json:"project,omitempty"`
+	Namespace         string                 `protobuf:"bytes,20,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Decision) Reset() {
+	*x = Decision{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Decision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decision) ProtoMessage() {}
+
+func (x *Decision) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decision.ProtoReflect.Descriptor instead.
+func (*Decision) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Decision) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Decision) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Decision) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Decision) GetOrgId() string {
+	if x != nil {
+		return x.OrgId
+	}
+	return ""
+}
+
+func (x *Decision) GetDecisionType() string {
+	if x != nil {
+		return x.DecisionType
+	}
+	return ""
+}
+
+func (x *Decision) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *Decision) GetConfidence() float32 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Decision) GetReasoning() string {
+	if x != nil && x.Reasoning != nil {
+		return *x.Reasoning
+	}
+	return ""
+}
+
+func (x *Decision) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Decision) GetCompletenessScore() float32 {
+	if x != nil {
+		return x.CompletenessScore
+	}
+	return 0
+}
+
+func (x *Decision) GetPrecedentRef() string {
+	if x != nil && x.PrecedentRef != nil {
+		return *x.PrecedentRef
+	}
+	return ""
+}
+
+func (x *Decision) GetSupersedesId() string {
+	if x != nil && x.SupersedesId != nil {
+		return *x.SupersedesId
+	}
+	return ""
+}
+
+func (x *Decision) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *Decision) GetValidFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return nil
+}
+
+func (x *Decision) GetValidTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidTo
+	}
+	return nil
+}
+
+func (x *Decision) GetTransactionTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TransactionTime
+	}
+	return nil
+}
+
+func (x *Decision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Decision) GetSessionId() string {
+	if x != nil && x.SessionId != nil {
+		return *x.SessionId
+	}
+	return ""
+}
+
+func (x *Decision) GetProject() string {
+	if x != nil && x.Project != nil {
+		return *x.Project
+	}
+	return ""
+}
+
+func (x *Decision) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type Conflict struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ConflictKind  string                 `protobuf:"bytes,2,opt,name=conflict_kind,json=conflictKind,proto3" json:"conflict_kind,omitempty"`
+	DecisionAId   string                 `protobuf:"bytes,3,opt,name=decision_a_id,json=decisionAId,proto3" json:"decision_a_id,omitempty"`
+	DecisionBId   string                 `protobuf:"bytes,4,opt,name=decision_b_id,json=decisionBId,proto3" json:"decision_b_id,omitempty"`
+	AgentA        string                 `protobuf:"bytes,5,opt,name=agent_a,json=agentA,proto3" json:"agent_a,omitempty"`
+	AgentB        string                 `protobuf:"bytes,6,opt,name=agent_b,json=agentB,proto3" json:"agent_b,omitempty"`
+	DecisionType  string                 `protobuf:"bytes,7,opt,name=decision_type,json=decisionType,proto3" json:"decision_type,omitempty"`
+	OutcomeA      string                 `protobuf:"bytes,8,opt,name=outcome_a,json=outcomeA,proto3" json:"outcome_a,omitempty"`
+	OutcomeB      string                 `protobuf:"bytes,9,opt,name=outcome_b,json=outcomeB,proto3" json:"outcome_b,omitempty"`
+	Significance  *float64               `protobuf:"fixed64,10,opt,name=significance,proto3,oneof" json:"significance,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	Severity      *string                `protobuf:"bytes,12,opt,name=severity,proto3,oneof" json:"severity,omitempty"`
+	Explanation   *string                `protobuf:"bytes,13,opt,name=explanation,proto3,oneof" json:"explanation,omitempty"`
+	DetectedAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conflict) Reset() {
+	*x = Conflict{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conflict) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conflict) ProtoMessage() {}
+
+func (x *Conflict) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conflict.ProtoReflect.Descriptor instead.
+func (*Conflict) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Conflict) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Conflict) GetConflictKind() string {
+	if x != nil {
+		return x.ConflictKind
+	}
+	return ""
+}
+
+func (x *Conflict) GetDecisionAId() string {
+	if x != nil {
+		return x.DecisionAId
+	}
+	return ""
+}
+
+func (x *Conflict) GetDecisionBId() string {
+	if x != nil {
+		return x.DecisionBId
+	}
+	return ""
+}
+
+func (x *Conflict) GetAgentA() string {
+	if x != nil {
+		return x.AgentA
+	}
+	return ""
+}
+
+func (x *Conflict) GetAgentB() string {
+	if x != nil {
+		return x.AgentB
+	}
+	return ""
+}
+
+func (x *Conflict) GetDecisionType() string {
+	if x != nil {
+		return x.DecisionType
+	}
+	return ""
+}
+
+func (x *Conflict) GetOutcomeA() string {
+	if x != nil {
+		return x.OutcomeA
+	}
+	return ""
+}
+
+func (x *Conflict) GetOutcomeB() string {
+	if x != nil {
+		return x.OutcomeB
+	}
+	return ""
+}
+
+func (x *Conflict) GetSignificance() float64 {
+	if x != nil && x.Significance != nil {
+		return *x.Significance
+	}
+	return 0
+}
+
+func (x *Conflict) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Conflict) GetSeverity() string {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return ""
+}
+
+func (x *Conflict) GetExplanation() string {
+	if x != nil && x.Explanation != nil {
+		return *x.Explanation
+	}
+	return ""
+}
+
+func (x *Conflict) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after this event_id, replaying conflicts detected since, like
+	// the Last-Event-ID header of GET /v1/subscribe.
+	LastEventId   string `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscribeRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+type ConflictEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty for notifications that do not describe a single new conflict.
+	EventId      string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	ConflictId   string                 `protobuf:"bytes,2,opt,name=conflict_id,json=conflictId,proto3" json:"conflict_id,omitempty"`
+	ConflictKind string                 `protobuf:"bytes,3,opt,name=conflict_kind,json=conflictKind,proto3" json:"conflict_kind,omitempty"`
+	DecisionAId  string                 `protobuf:"bytes,4,opt,name=decision_a_id,json=decisionAId,proto3" json:"decision_a_id,omitempty"`
+	DecisionBId  string                 `protobuf:"bytes,5,opt,name=decision_b_id,json=decisionBId,proto3" json:"decision_b_id,omitempty"`
+	AgentA       string                 `protobuf:"bytes,6,opt,name=agent_a,json=agentA,proto3" json:"agent_a,omitempty"`
+	AgentB       string                 `protobuf:"bytes,7,opt,name=agent_b,json=agentB,proto3" json:"agent_b,omitempty"`
+	DecisionType string                 `protobuf:"bytes,8,opt,name=decision_type,json=decisionType,proto3" json:"decision_type,omitempty"`
+	DetectedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"`
+	// The notification JSON exactly as GET /v1/subscribe sends it.
+	Payload       string `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConflictEvent) Reset() {
+	*x = ConflictEvent{}
+	mi := &file_akashi_v1_akashi_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConflictEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConflictEvent) ProtoMessage() {}
+
+func (x *ConflictEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_akashi_v1_akashi_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConflictEvent.ProtoReflect.Descriptor instead.
+func (*ConflictEvent) Descriptor() ([]byte, []int) {
+	return file_akashi_v1_akashi_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConflictEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetConflictId() string {
+	if x != nil {
+		return x.ConflictId
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetConflictKind() string {
+	if x != nil {
+		return x.ConflictKind
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetDecisionAId() string {
+	if x != nil {
+		return x.DecisionAId
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetDecisionBId() string {
+	if x != nil {
+		return x.DecisionBId
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetAgentA() string {
+	if x != nil {
+		return x.AgentA
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetAgentB() string {
+	if x != nil {
+		return x.AgentB
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetDecisionType() string {
+	if x != nil {
+		return x.DecisionType
+	}
+	return ""
+}
+
+func (x *ConflictEvent) GetDetectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return nil
+}
+
+func (x *ConflictEvent) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+var File_akashi_v1_akashi_proto protoreflect.FileDescriptor
+
+const file_akashi_v1_akashi_proto_rawDesc = "" +
+	"\n" +
+	"\x16akashi/v1/akashi.proto\x12\takashi.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf0\x04\n" +
+	"\fTraceRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1e\n" +
+	"\btrace_id\x18\x02 \x01(\tH\x00R\atraceId\x88\x01\x01\x124\n" +
+	"\bdecision\x18\x03 \x01(\v2\x18.akashi.v1.TraceDecisionR\bdecision\x123\n" +
+	"\bmetadata\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12(\n" +
+	"\rprecedent_ref\x18\x05 \x01(\tH\x01R\fprecedentRef\x88\x01\x01\x12.\n" +
+	"\x10precedent_reason\x18\x06 \x01(\tH\x02R\x0fprecedentReason\x88\x01\x01\x12(\n" +
+	"\rsupersedes_id\x18\a \x01(\tH\x03R\fsupersedesId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"session_id\x18\b \x01(\tH\x04R\tsessionId\x88\x01\x01\x12\x19\n" +
+	"\x05model\x18\t \x01(\tH\x05R\x05model\x88\x01\x01\x12\x17\n" +
+	"\x04task\x18\n" +
+	" \x01(\tH\x06R\x04task\x88\x01\x01\x12\x1d\n" +
+	"\aproject\x18\v \x01(\tH\aR\aproject\x88\x01\x01\x12K\n" +
+	"\x12supersede_matching\x18\f \x01(\v2\x1c.akashi.v1.SupersedeMatchingR\x11supersedeMatchingB\v\n" +
+	"\t_trace_idB\x10\n" +
+	"\x0e_precedent_refB\x13\n" +
+	"\x11_precedent_reasonB\x10\n" +
+	"\x0e_supersedes_idB\r\n" +
+	"\v_session_idB\b\n" +
+	"\x06_modelB\a\n" +
+	"\x05_taskB\n" +
+	"\n" +
+	"\b_project\"\x96\x02\n" +
+	"\rTraceDecision\x12#\n" +
+	"\rdecision_type\x18\x01 \x01(\tR\fdecisionType\x12\x18\n" +
+	"\aoutcome\x18\x02 \x01(\tR\aoutcome\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x02R\n" +
+	"confidence\x12!\n" +
+	"\treasoning\x18\x04 \x01(\tH\x00R\treasoning\x88\x01\x01\x12?\n" +
+	"\falternatives\x18\x05 \x03(\v2\x1b.akashi.v1.TraceAlternativeR\falternatives\x124\n" +
+	"\bevidence\x18\x06 \x03(\v2\x18.akashi.v1.TraceEvidenceR\bevidenceB\f\n" +
+	"\n" +
+	"_reasoning\"m\n" +
+	"\x10TraceAlternative\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12.\n" +
+	"\x10rejection_reason\x18\x02 \x01(\tH\x00R\x0frejectionReason\x88\x01\x01B\x13\n" +
+	"\x11_rejection_reason\"\xbf\x01\n" +
+	"\rTraceEvidence\x12\x1f\n" +
+	"\vsource_type\x18\x01 \x01(\tR\n" +
+	"sourceType\x12\"\n" +
+	"\n" +
+	"source_uri\x18\x02 \x01(\tH\x00R\tsourceUri\x88\x01\x01\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12,\n" +
+	"\x0frelevance_score\x18\x04 \x01(\x02H\x01R\x0erelevanceScore\x88\x01\x01B\r\n" +
+	"\v_source_uriB\x12\n" +
+	"\x10_relevance_score\"'\n" +
+	"\x11SupersedeMatching\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"\x8a\x02\n" +
+	"\rTraceResponse\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1f\n" +
+	"\vdecision_id\x18\x02 \x01(\tR\n" +
+	"decisionId\x12\x1f\n" +
+	"\vevent_count\x18\x03 \x01(\x05R\n" +
+	"eventCount\x12+\n" +
+	"\x11embedding_skipped\x18\x04 \x01(\bR\x10embeddingSkipped\x12(\n" +
+	"\rsuperseded_id\x18\x05 \x01(\tH\x00R\fsupersededId\x88\x01\x01\x12&\n" +
+	"\fduplicate_of\x18\x06 \x01(\tH\x01R\vduplicateOf\x88\x01\x01B\x10\n" +
+	"\x0e_superseded_idB\x0f\n" +
+	"\r_duplicate_of\"\x8a\x03\n" +
+	"\fQueryFilters\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\tR\bagentIds\x12%\n" +
+	"\x0edecision_types\x18\x02 \x03(\tR\rdecisionTypes\x12*\n" +
+	"\x0econfidence_min\x18\x03 \x01(\x02H\x00R\rconfidenceMin\x88\x01\x01\x12\x1d\n" +
+	"\aoutcome\x18\x04 \x01(\tH\x01R\aoutcome\x88\x01\x01\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\"\n" +
+	"\n" +
+	"session_id\x18\a \x01(\tH\x02R\tsessionId\x88\x01\x01\x12\x1d\n" +
+	"\aproject\x18\b \x01(\tH\x03R\aproject\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tagsB\x11\n" +
+	"\x0f_confidence_minB\n" +
+	"\n" +
+	"\b_outcomeB\r\n" +
+	"\v_session_idB\n" +
+	"\n" +
+	"\b_project\"\xa7\x01\n" +
+	"\fQueryRequest\x121\n" +
+	"\afilters\x18\x01 \x01(\v2\x17.akashi.v1.QueryFiltersR\afilters\x12\x19\n" +
+	"\border_by\x18\x02 \x01(\tR\aorderBy\x12\x1b\n" +
+	"\torder_dir\x18\x03 \x01(\tR\borderDir\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"\x82\x01\n" +
+	"\rQueryResponse\x121\n" +
+	"\tdecisions\x18\x01 \x03(\v2\x13.akashi.v1.DecisionR\tdecisions\x12\x19\n" +
+	"\x05total\x18\x02 \x01(\x05H\x00R\x05total\x88\x01\x01\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMoreB\b\n" +
+	"\x06_total\"\x94\x01\n" +
+	"\fCheckRequest\x12#\n" +
+	"\rdecision_type\x18\x01 \x01(\tR\fdecisionType\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x18\n" +
+	"\aproject\x18\x04 \x01(\tR\aproject\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\"\xcf\x01\n" +
+	"\rCheckResponse\x12#\n" +
+	"\rhas_precedent\x18\x01 \x01(\bR\fhasPrecedent\x121\n" +
+	"\tdecisions\x18\x02 \x03(\v2\x13.akashi.v1.DecisionR\tdecisions\x121\n" +
+	"\tconflicts\x18\x03 \x03(\v2\x13.akashi.v1.ConflictR\tconflicts\x123\n" +
+	"\x15conflicts_unavailable\x18\x04 \x01(\bR\x14conflictsUnavailable\"\xe2\x06\n" +
+	"\bDecision\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06run_id\x18\x02 \x01(\tR\x05runId\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x15\n" +
+	"\x06org_id\x18\x04 \x01(\tR\x05orgId\x12#\n" +
+	"\rdecision_type\x18\x05 \x01(\tR\fdecisionType\x12\x18\n" +
+	"\aoutcome\x18\x06 \x01(\tR\aoutcome\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\a \x01(\x02R\n" +
+	"confidence\x12!\n" +
+	"\treasoning\x18\b \x01(\tH\x00R\treasoning\x88\x01\x01\x123\n" +
+	"\bmetadata\x18\t \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12-\n" +
+	"\x12completeness_score\x18\n" +
+	" \x01(\x02R\x11completenessScore\x12(\n" +
+	"\rprecedent_ref\x18\v \x01(\tH\x01R\fprecedentRef\x88\x01\x01\x12(\n" +
+	"\rsupersedes_id\x18\f \x01(\tH\x02R\fsupersedesId\x88\x01\x01\x12!\n" +
+	"\fcontent_hash\x18\r \x01(\tR\vcontentHash\x129\n" +
+	"\n" +
+	"valid_from\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tvalidFrom\x125\n" +
+	"\bvalid_to\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\avalidTo\x12E\n" +
+	"\x10transaction_time\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x0ftransactionTime\x129\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\"\n" +
+	"\n" +
+	"session_id\x18\x12 \x01(\tH\x03R\tsessionId\x88\x01\x01\x12\x1d\n" +
+	"\aproject\x18\x13 \x01(\tH\x04R\aproject\x88\x01\x01\x12\x1c\n" +
+	"\tnamespace\x18\x14 \x01(\tR\tnamespaceB\f\n" +
+	"\n" +
+	"_reasoningB\x10\n" +
+	"\x0e_precedent_refB\x10\n" +
+	"\x0e_supersedes_idB\r\n" +
+	"\v_session_idB\n" +
+	"\n" +
+	"\b_project\"\x8c\x04\n" +
+	"\bConflict\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rconflict_kind\x18\x02 \x01(\tR\fconflictKind\x12\"\n" +
+	"\rdecision_a_id\x18\x03 \x01(\tR\vdecisionAId\x12\"\n" +
+	"\rdecision_b_id\x18\x04 \x01(\tR\vdecisionBId\x12\x17\n" +
+	"\aagent_a\x18\x05 \x01(\tR\x06agentA\x12\x17\n" +
+	"\aagent_b\x18\x06 \x01(\tR\x06agentB\x12#\n" +
+	"\rdecision_type\x18\a \x01(\tR\fdecisionType\x12\x1b\n" +
+	"\toutcome_a\x18\b \x01(\tR\boutcomeA\x12\x1b\n" +
+	"\toutcome_b\x18\t \x01(\tR\boutcomeB\x12'\n" +
+	"\fsignificance\x18\n" +
+	" \x01(\x01H\x00R\fsignificance\x88\x01\x01\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12\x1f\n" +
+	"\bseverity\x18\f \x01(\tH\x01R\bseverity\x88\x01\x01\x12%\n" +
+	"\vexplanation\x18\r \x01(\tH\x02R\vexplanation\x88\x01\x01\x12;\n" +
+	"\vdetected_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"detectedAtB\x0f\n" +
+	"\r_significanceB\v\n" +
+	"\t_severityB\x0e\n" +
+	"\f_explanation\"6\n" +
+	"\x10SubscribeRequest\x12\"\n" +
+	"\rlast_event_id\x18\x01 \x01(\tR\vlastEventId\"\xe6\x02\n" +
+	"\rConflictEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1f\n" +
+	"\vconflict_id\x18\x02 \x01(\tR\n" +
+	"conflictId\x12#\n" +
+	"\rconflict_kind\x18\x03 \x01(\tR\fconflictKind\x12\"\n" +
+	"\rdecision_a_id\x18\x04 \x01(\tR\vdecisionAId\x12\"\n" +
+	"\rdecision_b_id\x18\x05 \x01(\tR\vdecisionBId\x12\x17\n" +
+	"\aagent_a\x18\x06 \x01(\tR\x06agentA\x12\x17\n" +
+	"\aagent_b\x18\a \x01(\tR\x06agentB\x12#\n" +
+	"\rdecision_type\x18\b \x01(\tR\fdecisionType\x12;\n" +
+	"\vdetected_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"detectedAt\x12\x18\n" +
+	"\apayload\x18\n" +
+	" \x01(\tR\apayload2\x82\x02\n" +
+	"\x06Akashi\x12:\n" +
+	"\x05Trace\x12\x17.akashi.v1.TraceRequest\x1a\x18.akashi.v1.TraceResponse\x12:\n" +
+	"\x05Query\x12\x17.akashi.v1.QueryRequest\x1a\x18.akashi.v1.QueryResponse\x12:\n" +
+	"\x05Check\x12\x17.akashi.v1.CheckRequest\x1a\x18.akashi.v1.CheckResponse\x12D\n" +
+	"\tSubscribe\x12\x1b.akashi.v1.SubscribeRequest\x1a\x18.akashi.v1.ConflictEvent0\x01B=Z;github.com/ashita-ai/akashi/internal/grpc/akashiv1;akashiv1b\x06proto3"
+
+var (
+	file_akashi_v1_akashi_proto_rawDescOnce sync.Once
+	file_akashi_v1_akashi_proto_rawDescData []byte
+)
+
+func file_akashi_v1_akashi_proto_rawDescGZIP() []byte {
+	file_akashi_v1_akashi_proto_rawDescOnce.Do(func() {
+		file_akashi_v1_akashi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_akashi_v1_akashi_proto_rawDesc), len(file_akashi_v1_akashi_proto_rawDesc)))
+	})
+	return file_akashi_v1_akashi_proto_rawDescData
+}
+
+var file_akashi_v1_akashi_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_akashi_v1_akashi_proto_goTypes = []any{
+	(*TraceRequest)(nil),          // 0: akashi.v1.TraceRequest
+	(*TraceDecision)(nil),         // 1: akashi.v1.TraceDecision
+	(*TraceAlternative)(nil),      // 2: akashi.v1.TraceAlternative
+	(*TraceEvidence)(nil),         // 3: akashi.v1.TraceEvidence
+	(*SupersedeMatching)(nil),     // 4: akashi.v1.SupersedeMatching
+	(*TraceResponse)(nil),         // 5: akashi.v1.TraceResponse
+	(*QueryFilters)(nil),          // 6: akashi.v1.QueryFilters
+	(*QueryRequest)(nil),          // 7: akashi.v1.QueryRequest
+	(*QueryResponse)(nil),         // 8: akashi.v1.QueryResponse
+	(*CheckRequest)(nil),          // 9: akashi.v1.CheckRequest
+	(*CheckResponse)(nil),         // 10: akashi.v1.CheckResponse
+	(*Decision)(nil),              // 11: akashi.v1.Decision
+	(*Conflict)(nil),              // 12: akashi.v1.Conflict
+	(*SubscribeRequest)(nil),      // 13: akashi.v1.SubscribeRequest
+	(*ConflictEvent)(nil),         // 14: akashi.v1.ConflictEvent
+	(*structpb.Struct)(nil),       // 15: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_akashi_v1_akashi_proto_depIdxs = []int32{
+	1,  // 0: akashi.v1.TraceRequest.decision:type_name -> akashi.v1.TraceDecision
+	15, // 1: akashi.v1.TraceRequest.metadata:type_name -> google.protobuf.Struct
+	4,  // 2: akashi.v1.TraceRequest.supersede_matching:type_name -> akashi.v1.SupersedeMatching
+	2,  // 3: akashi.v1.TraceDecision.alternatives:type_name -> akashi.v1.TraceAlternative
+	3,  // 4: akashi.v1.TraceDecision.evidence:type_name -> akashi.v1.TraceEvidence
+	16, // 5: akashi.v1.QueryFilters.from:type_name -> google.protobuf.Timestamp
+	16, // 6: akashi.v1.QueryFilters.to:type_name -> google.protobuf.Timestamp
+	6,  // 7: akashi.v1.QueryRequest.filters:type_name -> akashi.v1.QueryFilters
+	11, // 8: akashi.v1.QueryResponse.decisions:type_name -> akashi.v1.Decision
+	11, // 9: akashi.v1.CheckResponse.decisions:type_name -> akashi.v1.Decision
+	12, // 10: akashi.v1.CheckResponse.conflicts:type_name -> akashi.v1.Conflict
+	15, // 11: akashi.v1.Decision.metadata:type_name -> google.protobuf.Struct
+	16, // 12: akashi.v1.Decision.valid_from:type_name -> google.protobuf.Timestamp
+	16, // 13: akashi.v1.Decision.valid_to:type_name -> google.protobuf.Timestamp
+	16, // 14: akashi.v1.Decision.transaction_time:type_name -> google.protobuf.Timestamp
+	16, // 15: akashi.v1.Decision.created_at:type_name -> google.protobuf.Timestamp
+	16, // 16: akashi.v1.Conflict.detected_at:type_name -> google.protobuf.Timestamp
+	16, // 17: akashi.v1.ConflictEvent.detected_at:type_name -> google.protobuf.Timestamp
+	0,  // 18: akashi.v1.Akashi.Trace:input_type -> akashi.v1.TraceRequest
+	7,  // 19: akashi.v1.Akashi.Query:input_type -> akashi.v1.QueryRequest
+	9,  // 20: akashi.v1.Akashi.Check:input_type -> akashi.v1.CheckRequest
+	13, // 21: akashi.v1.Akashi.Subscribe:input_type -> akashi.v1.SubscribeRequest
+	5,  // 22: akashi.v1.Akashi.Trace:output_type -> akashi.v1.TraceResponse
+	8,  // 23: akashi.v1.Akashi.Query:output_type -> akashi.v1.QueryResponse
+	10, // 24: akashi.v1.Akashi.Check:output_type -> akashi.v1.CheckResponse
+	14, // 25: akashi.v1.Akashi.Subscribe:output_type -> akashi.v1.ConflictEvent
+	22, // [22:26] is the sub-list for method output_type
+	18, // [18:22] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_akashi_v1_akashi_proto_init() }
+func file_akashi_v1_akashi_proto_init() {
+	if File_akashi_v1_akashi_proto != nil {
+		return
+	}
+	file_akashi_v1_akashi_proto_msgTypes[0].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[1].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[2].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[3].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[5].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[6].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[8].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[11].OneofWrappers = []any{}
+	file_akashi_v1_akashi_proto_msgTypes[12].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_akashi_v1_akashi_proto_rawDesc), len(file_akashi_v1_akashi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_akashi_v1_akashi_proto_goTypes,
+		DependencyIndexes: file_akashi_v1_akashi_proto_depIdxs,
+		MessageInfos:      file_akashi_v1_akashi_proto_msgTypes,
+	}.Build()
+	File_akashi_v1_akashi_proto = out.File
+	file_akashi_v1_akashi_proto_goTypes = nil
+	file_akashi_v1_akashi_proto_depIdxs = nil
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// authenticate validates the "authorization: Bearer <jwt>" metadata of an
+// incoming RPC and returns ctx carrying the caller's claims and namespace.
+// The namespace is resolved as the HTTP auth middleware resolves it: a token
+// scoped to a namespace is confined to it, and other callers may pick one
+// with "x-akashi-namespace" metadata or get the default.
+func authenticate(ctx context.Context, jwtMgr *auth.JWTManager) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a Bearer token")
+	}
+	claims, err := jwtMgr.ValidateToken(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	var requested string
+	if ns := md.Get("x-akashi-namespace"); len(ns) > 0 {
+		requested = strings.TrimSpace(ns[0])
+	}
+	if requested != "" {
+		if err := model.ValidateNamespace(requested); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "x-akashi-namespace: %v", err)
+		}
+	}
+	ns := model.DefaultNamespace
+	switch {
+	case claims.Namespace != "":
+		if requested != "" && requested != claims.Namespace {
+			return nil, status.Errorf(codes.PermissionDenied, "token is restricted to namespace %q", claims.Namespace)
+		}
+		ns = claims.Namespace
+	case requested != "":
+		ns = requested
+	}
+
+	ctx = ctxutil.WithClaims(ctx, claims)
+	return ctxutil.WithNamespace(ctx, ns), nil
+}
+
+// unaryAuthInterceptor authenticates every unary RPC.
+func unaryAuthInterceptor(jwtMgr *auth.JWTManager) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, jwtMgr)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor authenticates every streaming RPC.
+func streamAuthInterceptor(jwtMgr *auth.JWTManager) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, _ *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), jwtMgr)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authedStream overrides a stream's context with the authenticated one.
+type authedStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
+
+// requireRole returns PermissionDenied unless the caller holds at least
+// minRole, mirroring the HTTP server's role wrappers.
+func requireRole(ctx context.Context, minRole model.AgentRole) (*auth.Claims, error) {
+	claims := ctxutil.ClaimsFromContext(ctx)
+	if claims == nil {
+		return nil, status.Error(codes.Unauthenticated, "no claims in context")
+	}
+	if !model.RoleAtLeast(claims.Role, minRole) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+	}
+	return claims, nil
+}
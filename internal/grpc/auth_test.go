@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestAuthenticate(t *testing.T) {
+	jwtMgr, err := auth.NewJWTManager("", "", time.Hour)
+	require.NoError(t, err)
+	orgID := uuid.New()
+	token, _, err := jwtMgr.IssueToken(model.Agent{ID: uuid.New(), AgentID: "planner", OrgID: orgID, Role: model.RoleAgent})
+	require.NoError(t, err)
+	scoped, _, err := jwtMgr.IssueScopedToken("admin", model.Agent{ID: uuid.New(), AgentID: "planner", OrgID: orgID, Role: model.RoleAgent}, time.Hour, "staging")
+	require.NoError(t, err)
+
+	withMD := func(kv ...string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+	}
+
+	ctx, err := authenticate(withMD("authorization", "Bearer "+token), jwtMgr)
+	require.NoError(t, err)
+	claims := ctxutil.ClaimsFromContext(ctx)
+	require.NotNil(t, claims)
+	assert.Equal(t, "planner", claims.AgentID)
+	assert.Equal(t, orgID, ctxutil.OrgIDFromContext(ctx))
+	assert.Equal(t, model.DefaultNamespace, ctxutil.NamespaceFromContext(ctx))
+
+	ctx, err = authenticate(withMD("authorization", "bearer "+token, "x-akashi-namespace", "prod"), jwtMgr)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", ctxutil.NamespaceFromContext(ctx))
+
+	ctx, err = authenticate(withMD("authorization", "Bearer "+scoped), jwtMgr)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", ctxutil.NamespaceFromContext(ctx))
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		code codes.Code
+	}{
+		"no metadata":          {context.Background(), codes.Unauthenticated},
+		"no authorization":     {withMD("x-akashi-namespace", "prod"), codes.Unauthenticated},
+		"wrong scheme":         {withMD("authorization", "ApiKey planner:secret"), codes.Unauthenticated},
+		"bad token":            {withMD("authorization", "Bearer not-a-jwt"), codes.Unauthenticated},
+		"scoped elsewhere":     {withMD("authorization", "Bearer "+scoped, "x-akashi-namespace", "prod"), codes.PermissionDenied},
+		"invalid namespace id": {withMD("authorization", "Bearer "+token, "x-akashi-namespace", "Not Valid!"), codes.InvalidArgument},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := authenticate(tc.ctx, jwtMgr)
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	_, err := requireRole(context.Background(), model.RoleReader)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := ctxutil.WithClaims(context.Background(), &auth.Claims{AgentID: "viewer", Role: model.RoleReader})
+	_, err = requireRole(ctx, model.RoleAgent)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	claims, err := requireRole(ctx, model.RoleReader)
+	require.NoError(t, err)
+	assert.Equal(t, "viewer", claims.AgentID)
+}
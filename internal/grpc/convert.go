@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ashita-ai/akashi/internal/grpc/akashiv1"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// traceRequestFromProto converts a Trace RPC request to the POST /v1/trace
+// body it mirrors. The self-reported model, task, and project become the
+// request context, as the HTTP body's context field would carry them.
+// Returns an error for malformed UUIDs; other validation is left to
+// server.ValidateTraceRequest.
+func traceRequestFromProto(in *akashiv1.TraceRequest) (model.TraceRequest, error) {
+	req := model.TraceRequest{
+		AgentID:         in.GetAgentId(),
+		TraceID:         in.TraceId,
+		PrecedentReason: in.PrecedentReason,
+	}
+	if d := in.GetDecision(); d != nil {
+		req.Decision = model.TraceDecision{
+			DecisionType: d.GetDecisionType(),
+			Outcome:      d.GetOutcome(),
+			Confidence:   d.GetConfidence(),
+			Reasoning:    d.Reasoning,
+		}
+		for _, a := range d.GetAlternatives() {
+			req.Decision.Alternatives = append(req.Decision.Alternatives, model.TraceAlternative{
+				Label:           a.GetLabel(),
+				RejectionReason: a.RejectionReason,
+			})
+		}
+		for _, e := range d.GetEvidence() {
+			req.Decision.Evidence = append(req.Decision.Evidence, model.TraceEvidence{
+				SourceType:     e.GetSourceType(),
+				SourceURI:      e.SourceUri,
+				Content:        e.GetContent(),
+				RelevanceScore: e.RelevanceScore,
+			})
+		}
+	}
+	if in.GetMetadata() != nil {
+		req.Metadata = in.GetMetadata().AsMap()
+	}
+	if m := in.GetSupersedeMatching(); m != nil {
+		req.SupersedeMatching = &model.SupersedeMatching{Keys: m.GetKeys()}
+	}
+
+	var err error
+	if req.PrecedentRef, err = parseOptionalUUID("precedent_ref", in.PrecedentRef); err != nil {
+		return model.TraceRequest{}, err
+	}
+	if req.SupersedesID, err = parseOptionalUUID("supersedes_id", in.SupersedesId); err != nil {
+		return model.TraceRequest{}, err
+	}
+
+	for key, v := range map[string]*string{"model": in.Model, "task": in.Task, "project": in.Project} {
+		if v == nil || *v == "" {
+			continue
+		}
+		if req.Context == nil {
+			req.Context = map[string]any{}
+		}
+		req.Context[key] = *v
+	}
+	return req, nil
+}
+
+// queryRequestFromProto converts a Query RPC request to the POST /v1/query
+// body it mirrors. Returns an error for a malformed session_id.
+func queryRequestFromProto(in *akashiv1.QueryRequest) (model.QueryRequest, error) {
+	req := model.QueryRequest{
+		OrderBy:  in.GetOrderBy(),
+		OrderDir: in.GetOrderDir(),
+		Limit:    int(in.GetLimit()),
+		Offset:   int(in.GetOffset()),
+	}
+	f := in.GetFilters()
+	if f == nil {
+		return req, nil
+	}
+	req.Filters = model.QueryFilters{
+		AgentIDs:      f.GetAgentIds(),
+		DecisionTypes: f.GetDecisionTypes(),
+		ConfidenceMin: f.ConfidenceMin,
+		Outcome:       f.Outcome,
+		Project:       f.Project,
+		Tags:          f.GetTags(),
+	}
+	if f.GetFrom() != nil || f.GetTo() != nil {
+		req.Filters.TimeRange = &model.TimeRange{From: timeFromProto(f.GetFrom()), To: timeFromProto(f.GetTo())}
+	}
+	var err error
+	if req.Filters.SessionID, err = parseOptionalUUID("filters.session_id", f.SessionId); err != nil {
+		return model.QueryRequest{}, err
+	}
+	return req, nil
+}
+
+// decisionToProto converts a decision for a Query or Check response.
+func decisionToProto(d model.Decision) *akashiv1.Decision {
+	out := &akashiv1.Decision{
+		Id:                d.ID.String(),
+		RunId:             d.RunID.String(),
+		AgentId:           d.AgentID,
+		OrgId:             d.OrgID.String(),
+		DecisionType:      d.DecisionType,
+		Outcome:           d.Outcome,
+		Confidence:        d.Confidence,
+		Reasoning:         d.Reasoning,
+		Metadata:          structFromMap(d.Metadata),
+		CompletenessScore: d.CompletenessScore,
+		PrecedentRef:      uuidString(d.PrecedentRef),
+		SupersedesId:      uuidString(d.SupersedesID),
+		ContentHash:       d.ContentHash,
+		ValidFrom:         timestamppb.New(d.ValidFrom),
+		TransactionTime:   timestamppb.New(d.TransactionTime),
+		CreatedAt:         timestamppb.New(d.CreatedAt),
+		SessionId:         uuidString(d.SessionID),
+		Project:           d.Project,
+		Namespace:         d.Namespace,
+	}
+	if d.ValidTo != nil {
+		out.ValidTo = timestamppb.New(*d.ValidTo)
+	}
+	return out
+}
+
+// conflictToProto converts a conflict for a Check response.
+func conflictToProto(c model.DecisionConflict) *akashiv1.Conflict {
+	return &akashiv1.Conflict{
+		Id:           c.ID.String(),
+		ConflictKind: string(c.ConflictKind),
+		DecisionAId:  c.DecisionAID.String(),
+		DecisionBId:  c.DecisionBID.String(),
+		AgentA:       c.AgentA,
+		AgentB:       c.AgentB,
+		DecisionType: c.DecisionType,
+		OutcomeA:     c.OutcomeA,
+		OutcomeB:     c.OutcomeB,
+		Significance: c.Significance,
+		Status:       c.Status,
+		Severity:     c.Severity,
+		Explanation:  c.Explanation,
+		DetectedAt:   timestamppb.New(c.DetectedAt),
+	}
+}
+
+// conflictEventFromPayload converts an akashi_conflicts notification to a
+// ConflictEvent. Notifications that do not describe a single conflict (such
+// as scorer pings) leave the conflict fields empty; payload always carries
+// the notification verbatim.
+func conflictEventFromPayload(eventID, payload string) *akashiv1.ConflictEvent {
+	out := &akashiv1.ConflictEvent{EventId: eventID, Payload: payload}
+	var p struct {
+		ConflictID   string `json:"conflict_id"`
+		ConflictKind string `json:"conflict_kind"`
+		DecisionAID  string `json:"decision_a_id"`
+		DecisionBID  string `json:"decision_b_id"`
+		AgentA       string `json:"agent_a"`
+		AgentB       string `json:"agent_b"`
+		DecisionType string `json:"decision_type"`
+		DetectedAt   string `json:"detected_at"`
+	}
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return out
+	}
+	out.ConflictId = p.ConflictID
+	out.ConflictKind = p.ConflictKind
+	out.DecisionAId = p.DecisionAID
+	out.DecisionBId = p.DecisionBID
+	out.AgentA = p.AgentA
+	out.AgentB = p.AgentB
+	out.DecisionType = p.DecisionType
+	if t, err := time.Parse(time.RFC3339Nano, p.DetectedAt); err == nil {
+		out.DetectedAt = timestamppb.New(t)
+	}
+	return out
+}
+
+func parseOptionalUUID(field string, s *string) (*uuid.UUID, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*s)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid UUID", field)
+	}
+	return &id, nil
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// structFromMap converts decision metadata to a Struct. Metadata is decoded
+// JSON, so a JSON round trip covers every value it can hold; anything that
+// fails to convert is dropped rather than failing the response.
+func structFromMap(m map[string]any) *structpb.Struct {
+	if len(m) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(b); err != nil {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ashita-ai/akashi/internal/grpc/akashiv1"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestTraceRequestFromProto(t *testing.T) {
+	precedent := uuid.New()
+	meta, err := structpb.NewStruct(map[string]any{"ticket": "OPS-1"})
+	require.NoError(t, err)
+
+	req, err := traceRequestFromProto(&akashiv1.TraceRequest{
+		AgentId: "planner",
+		Decision: &akashiv1.TraceDecision{
+			DecisionType: "architecture",
+			Outcome:      "use postgres",
+			Confidence:   0.7,
+			Reasoning:    proto.String("mature"),
+			Alternatives: []*akashiv1.TraceAlternative{{Label: "mysql", RejectionReason: proto.String("no pgvector")}},
+			Evidence:     []*akashiv1.TraceEvidence{{SourceType: "document", Content: "benchmarks"}},
+		},
+		Metadata:     meta,
+		PrecedentRef: proto.String(precedent.String()),
+		Model:        proto.String("gpt-5"),
+		Project:      proto.String("akashi"),
+		Task:         proto.String(""),
+
+		SupersedeMatching: &akashiv1.SupersedeMatching{Keys: []string{"ticket"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "planner", req.AgentID)
+	assert.Equal(t, "use postgres", req.Decision.Outcome)
+	assert.Equal(t, "mature", *req.Decision.Reasoning)
+	require.Len(t, req.Decision.Alternatives, 1)
+	assert.Equal(t, "no pgvector", *req.Decision.Alternatives[0].RejectionReason)
+	require.Len(t, req.Decision.Evidence, 1)
+	assert.Equal(t, map[string]any{"ticket": "OPS-1"}, req.Metadata)
+	assert.Equal(t, precedent, *req.PrecedentRef)
+	assert.Nil(t, req.SupersedesID)
+	require.NotNil(t, req.SupersedeMatching)
+	assert.Equal(t, []string{"ticket"}, req.SupersedeMatching.Keys)
+	assert.Equal(t, map[string]any{"model": "gpt-5", "project": "akashi"}, req.Context, "empty task is omitted")
+
+	_, err = traceRequestFromProto(&akashiv1.TraceRequest{AgentId: "planner", SupersedesId: proto.String("nope")})
+	assert.EqualError(t, err, "supersedes_id is not a valid UUID")
+}
+
+func TestQueryRequestFromProto(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	req, err := queryRequestFromProto(&akashiv1.QueryRequest{
+		Filters: &akashiv1.QueryFilters{
+			AgentIds:      []string{"planner"},
+			DecisionTypes: []string{"architecture"},
+			ConfidenceMin: proto.Float32(0.5),
+			From:          timestamppb.New(from),
+		},
+		OrderBy: "valid_from",
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"planner"}, req.Filters.AgentIDs)
+	assert.InDelta(t, 0.5, *req.Filters.ConfidenceMin, 1e-6)
+	require.NotNil(t, req.Filters.TimeRange)
+	assert.Equal(t, from, *req.Filters.TimeRange.From)
+	assert.Nil(t, req.Filters.TimeRange.To)
+	assert.Equal(t, 10, req.Limit)
+
+	req, err = queryRequestFromProto(&akashiv1.QueryRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, req.Filters.TimeRange)
+
+	_, err = queryRequestFromProto(&akashiv1.QueryRequest{Filters: &akashiv1.QueryFilters{SessionId: proto.String("x")}})
+	assert.Error(t, err)
+}
+
+func TestDecisionToProto(t *testing.T) {
+	validTo := time.Now().UTC()
+	supersedes := uuid.New()
+	d := model.Decision{
+		ID:           uuid.New(),
+		AgentID:      "planner",
+		DecisionType: "architecture",
+		Outcome:      "use postgres",
+		Confidence:   0.7,
+		Metadata:     map[string]any{"n": float64(2), "tags": []any{"a"}},
+		SupersedesID: &supersedes,
+		ValidFrom:    validTo.Add(-time.Hour),
+		ValidTo:      &validTo,
+		Namespace:    "default",
+	}
+	out := decisionToProto(d)
+	assert.Equal(t, d.ID.String(), out.GetId())
+	assert.Equal(t, supersedes.String(), out.GetSupersedesId())
+	assert.Nil(t, out.PrecedentRef)
+	assert.Equal(t, d.Metadata, out.GetMetadata().AsMap())
+	assert.Equal(t, validTo, out.GetValidTo().AsTime())
+
+	assert.Nil(t, decisionToProto(model.Decision{}).GetValidTo())
+}
+
+func TestConflictEventFromPayload(t *testing.T) {
+	ev := conflictEventFromPayload("2026-05-01T09:00:00Z",
+		`{"org_id":"x","conflict_id":"c1","conflict_kind":"cross_agent","agent_a":"a","agent_b":"b","detected_at":"2026-05-01T09:00:00Z"}`)
+	assert.Equal(t, "c1", ev.GetConflictId())
+	assert.Equal(t, "cross_agent", ev.GetConflictKind())
+	assert.Equal(t, "a", ev.GetAgentA())
+	assert.Equal(t, time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC), ev.GetDetectedAt().AsTime())
+
+	ping := `{"source":"scorer","org_id":"x"}`
+	ev = conflictEventFromPayload("", ping)
+	assert.Empty(t, ev.GetConflictId())
+	assert.Nil(t, ev.GetDetectedAt())
+	assert.Equal(t, ping, ev.GetPayload())
+}
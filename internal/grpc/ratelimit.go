@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/ratelimit"
+)
+
+// RateLimiter charges one call by the authenticated caller, returning the
+// limiter result and, when the call is denied, a non-empty message.
+// *server.Server implements it with the HTTP API's buckets, org overrides,
+// and per-agent cap, so gRPC and HTTP calls draw from the same limits.
+type RateLimiter interface {
+	RateLimit(ctx context.Context, claims *auth.Claims) (ratelimit.Result, string)
+}
+
+// rateLimit charges the call carried by ctx (already authenticated) to rl.
+// It returns the x-ratelimit-* metadata to send back, mirroring the HTTP
+// X-RateLimit-* headers, and ResourceExhausted when the call is denied.
+func rateLimit(ctx context.Context, rl RateLimiter) (metadata.MD, error) {
+	res, denied := rl.RateLimit(ctx, ctxutil.ClaimsFromContext(ctx))
+	md := metadata.MD{}
+	if res.Limit > 0 {
+		md.Set("x-ratelimit-limit", strconv.Itoa(res.Limit))
+		md.Set("x-ratelimit-remaining", strconv.Itoa(res.Remaining))
+		if !res.ResetAt.IsZero() {
+			md.Set("x-ratelimit-reset", strconv.FormatInt(res.ResetAt.Unix(), 10))
+		}
+	}
+	if denied == "" {
+		return md, nil
+	}
+	retryAfter := int64(res.RetryAfter.Seconds())
+	if retryAfter <= 0 {
+		retryAfter = 1
+	}
+	md.Set("retry-after", strconv.FormatInt(retryAfter, 10))
+	return md, status.Error(codes.ResourceExhausted, denied)
+}
+
+// unaryRateLimitInterceptor rate limits every unary RPC. It must run after
+// unaryAuthInterceptor so the caller's claims are in the context.
+func unaryRateLimitInterceptor(rl RateLimiter) grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (any, error) {
+		md, err := rateLimit(ctx, rl)
+		if len(md) > 0 {
+			_ = grpclib.SetHeader(ctx, md)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamRateLimitInterceptor charges one call per stream when it opens.
+func streamRateLimitInterceptor(rl RateLimiter) grpclib.StreamServerInterceptor {
+	return func(srv any, ss grpclib.ServerStream, _ *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		md, err := rateLimit(ss.Context(), rl)
+		if len(md) > 0 {
+			_ = ss.SetHeader(md)
+		}
+		if err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/ratelimit"
+)
+
+// stubRateLimiter allows the first `allow` calls and denies the rest,
+// recording the claims it was charged with.
+type stubRateLimiter struct {
+	allow  int
+	calls  int
+	claims []*auth.Claims
+}
+
+func (s *stubRateLimiter) RateLimit(_ context.Context, claims *auth.Claims) (ratelimit.Result, string) {
+	s.calls++
+	s.claims = append(s.claims, claims)
+	if s.calls <= s.allow {
+		return ratelimit.Result{Allowed: true, Limit: s.allow, Remaining: s.allow - s.calls}, ""
+	}
+	return ratelimit.Result{Limit: s.allow, RetryAfter: 2500 * time.Millisecond}, "agent rate limit exceeded"
+}
+
+func TestRateLimit(t *testing.T) {
+	claims := &auth.Claims{AgentID: "planner", OrgID: uuid.New(), Role: model.RoleAgent}
+	ctx := ctxutil.WithClaims(context.Background(), claims)
+	rl := &stubRateLimiter{allow: 1}
+
+	md, err := rateLimit(ctx, rl)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, md.Get("x-ratelimit-limit"))
+	assert.Equal(t, []string{"0"}, md.Get("x-ratelimit-remaining"))
+	assert.Empty(t, md.Get("retry-after"))
+
+	md, err = rateLimit(ctx, rl)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "agent rate limit exceeded", status.Convert(err).Message())
+	assert.Equal(t, []string{"2"}, md.Get("retry-after"))
+
+	require.Len(t, rl.claims, 2)
+	assert.Same(t, claims, rl.claims[0], "the authenticated caller is charged")
+}
+
+func TestUnaryRateLimitInterceptor(t *testing.T) {
+	ctx := ctxutil.WithClaims(context.Background(), &auth.Claims{AgentID: "planner", OrgID: uuid.New(), Role: model.RoleAgent})
+	interceptor := unaryRateLimitInterceptor(&stubRateLimiter{allow: 1})
+	handled := 0
+	handler := func(context.Context, any) (any, error) {
+		handled++
+		return "ok", nil
+	}
+
+	resp, err := interceptor(ctx, nil, &grpclib.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = interceptor(ctx, nil, &grpclib.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1, handled, "a denied call never reaches the handler")
+}
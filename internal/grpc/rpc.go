@@ -0,0 +1,333 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/grpc/akashiv1"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/server"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// Query pagination bounds, matching POST /v1/query.
+const (
+	defaultQueryLimit = 50
+	maxQueryLimit     = 1000
+	maxQueryOffset    = 100_000
+)
+
+// Trace records a decision (agent+). It applies the same validation, agent
+// ownership rule, org policies, and project normalization as POST /v1/trace.
+func (s *Server) Trace(ctx context.Context, in *akashiv1.TraceRequest) (*akashiv1.TraceResponse, error) {
+	claims, err := requireRole(ctx, model.RoleAgent)
+	if err != nil {
+		return nil, err
+	}
+	orgID := ctxutil.OrgIDFromContext(ctx)
+
+	req, err := traceRequestFromProto(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := server.ValidateTraceRequest(&req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !model.RoleAtLeast(claims.Role, model.RoleAdmin) && req.AgentID != claims.AgentID {
+		return nil, status.Error(codes.PermissionDenied, "can only trace for your own agent_id")
+	}
+	sessionID, err := parseOptionalUUID("session_id", in.SessionId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	autoRegAudit := &storage.MutationAuditEntry{
+		RequestID:    uuid.New().String(),
+		OrgID:        orgID,
+		ActorAgentID: claims.AgentID,
+		ActorRole:    string(claims.Role),
+		HTTPMethod:   "GRPC",
+		Endpoint:     akashiv1.Akashi_Trace_FullMethodName,
+		ResourceType: "agent",
+		ResourceID:   req.AgentID,
+	}
+	if _, err := s.decisionSvc.ResolveOrCreateAgent(ctx, orgID, req.AgentID, claims.Role, autoRegAudit); err != nil {
+		if errors.Is(err, decisions.ErrAgentNotFound) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, decisions.ErrAgentTombstoned) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, s.internalError("failed to resolve agent", err)
+	}
+
+	if err := s.decisionSvc.CheckTracePolicy(ctx, orgID, req.AgentID, req.Decision.DecisionType, len(req.Decision.Alternatives)); err != nil {
+		var exceeded *model.QuotaExceededError
+		switch {
+		case errors.Is(err, decisions.ErrTracePolicy):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.As(err, &exceeded):
+			return nil, status.Error(codes.ResourceExhausted, exceeded.Error())
+		default:
+			return nil, s.internalError("failed to check org policy", err)
+		}
+	}
+
+	if sessionID == nil {
+		if sessionID, err = s.db.GetOpenAgentSessionID(ctx, orgID, req.AgentID); err != nil {
+			return nil, s.internalError("failed to load agent session", err)
+		}
+	}
+
+	agentContext, err := s.traceAgentContext(ctx, orgID, claims.APIKeyID, req.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchKeys []string
+	if req.SupersedeMatching != nil {
+		matchKeys = req.SupersedeMatching.Keys
+	}
+	result, err := s.decisionSvc.Trace(ctx, orgID, decisions.TraceInput{
+		AgentID:         req.AgentID,
+		TraceID:         req.TraceID,
+		Metadata:        req.Metadata,
+		Decision:        req.Decision,
+		PrecedentRef:    req.PrecedentRef,
+		PrecedentReason: req.PrecedentReason,
+		SupersedesID:    req.SupersedesID,
+		SessionID:       sessionID,
+		AgentContext:    agentContext,
+		APIKeyID:        claims.APIKeyID,
+		Namespace:       ctxutil.NamespaceFromContext(ctx),
+		AuditMeta: &ctxutil.AuditMeta{
+			RequestID:    autoRegAudit.RequestID,
+			OrgID:        orgID,
+			ActorAgentID: claims.AgentID,
+			ActorRole:    string(claims.Role),
+			HTTPMethod:   "GRPC",
+			Endpoint:     akashiv1.Akashi_Trace_FullMethodName,
+		},
+		SupersedeMatchKeys: matchKeys,
+	})
+	if err != nil {
+		if errors.Is(err, decisions.ErrContextSnapshotTooLarge) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		var pgErr *pgconn.PgError
+		if req.SupersedesID != nil && (errors.Is(err, storage.ErrNotFound) || (errors.As(err, &pgErr) && pgErr.Code == "23503")) {
+			return nil, status.Error(codes.InvalidArgument, "superseded decision not found or already superseded")
+		}
+		if req.SupersedeMatching != nil && errors.Is(err, storage.ErrNotFound) {
+			// Another trace superseded the matched decision between lookup and write.
+			return nil, status.Error(codes.Aborted, "matched decision was superseded concurrently; retry the trace")
+		}
+		return nil, s.internalError("failed to create trace", err)
+	}
+
+	return &akashiv1.TraceResponse{
+		RunId:            result.RunID.String(),
+		DecisionId:       result.DecisionID.String(),
+		EventCount:       int32(result.EventCount), //nolint:gosec // bounded by model.MaxEvidenceCount plus alternatives
+		EmbeddingSkipped: result.EmbeddingSkipped,
+		SupersededId:     uuidString(result.Decision.SupersedesID),
+		DuplicateOf:      uuidString(result.DuplicateOf),
+	}, nil
+}
+
+// traceAgentContext builds agent_context for a gRPC trace: the self-reported
+// clientCtx, normalized to a canonical project as POST /v1/trace does, and
+// the server-verified transport and API key prefix.
+func (s *Server) traceAgentContext(ctx context.Context, orgID uuid.UUID, apiKeyID *uuid.UUID, clientCtx map[string]any) (map[string]any, error) {
+	if clientCtx == nil {
+		clientCtx = map[string]any{}
+	}
+	if errMsg := server.NormalizeTraceProjectForOrg(ctx, s.db, orgID, clientCtx, s.logger); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	serverCtx := map[string]any{"transport": "grpc"}
+	if apiKeyID != nil {
+		if key, err := s.db.GetAPIKeyByID(ctx, orgID, *apiKeyID); err == nil && key.Prefix != "" {
+			serverCtx["api_key_prefix"] = key.Prefix
+		}
+	}
+
+	agentContext := map[string]any{"server": serverCtx}
+	if len(clientCtx) > 0 {
+		agentContext["client"] = clientCtx
+	}
+	return agentContext, nil
+}
+
+// Query lists decisions matching structured filters (reader+), scoped to the
+// caller's namespace and filtered by access grants like POST /v1/query.
+func (s *Server) Query(ctx context.Context, in *akashiv1.QueryRequest) (*akashiv1.QueryResponse, error) {
+	claims, err := requireRole(ctx, model.RoleReader)
+	if err != nil {
+		return nil, err
+	}
+	orgID := ctxutil.OrgIDFromContext(ctx)
+
+	req, err := queryRequestFromProto(in)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	switch {
+	case req.Limit <= 0:
+		req.Limit = defaultQueryLimit
+	case req.Limit > maxQueryLimit:
+		req.Limit = maxQueryLimit
+	}
+	req.Offset = min(max(req.Offset, 0), maxQueryOffset)
+	ns := ctxutil.NamespaceFromContext(ctx)
+	req.Filters.Namespace = &ns
+
+	results, total, err := s.decisionSvc.Query(ctx, orgID, req)
+	if err != nil {
+		return nil, s.internalError("query failed", err)
+	}
+	preFilterCount := len(results)
+	results, err = authz.FilterDecisions(ctx, s.db, claims, results, s.grantCache)
+	if err != nil {
+		return nil, s.internalError("authorization check failed", err)
+	}
+
+	resp := &akashiv1.QueryResponse{Decisions: make([]*akashiv1.Decision, len(results))}
+	for i, d := range results {
+		resp.Decisions[i] = decisionToProto(d)
+	}
+	// Access filtering hides how many matches the caller may see, so the
+	// total is only reported when nothing was filtered out.
+	if len(results) < preFilterCount {
+		resp.HasMore = len(results) == req.Limit
+	} else {
+		t := int32(total) //nolint:gosec // decision counts fit in int32
+		resp.Total = &t
+		resp.HasMore = req.Offset+len(results) < total
+	}
+	return resp, nil
+}
+
+// Check looks up precedents and open conflicts for a decision type
+// (reader+), filtered by access grants like POST /v1/check.
+func (s *Server) Check(ctx context.Context, in *akashiv1.CheckRequest) (*akashiv1.CheckResponse, error) {
+	claims, err := requireRole(ctx, model.RoleReader)
+	if err != nil {
+		return nil, err
+	}
+	orgID := ctxutil.OrgIDFromContext(ctx)
+
+	if in.GetDecisionType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "decision_type is required")
+	}
+	result, err := s.decisionSvc.Check(ctx, orgID, decisions.CheckInput{
+		DecisionType: in.GetDecisionType(),
+		Query:        in.GetQuery(),
+		AgentID:      in.GetAgentId(),
+		Project:      in.GetProject(),
+		Namespace:    ctxutil.NamespaceFromContext(ctx),
+		Limit:        int(in.GetLimit()),
+	})
+	if err != nil {
+		return nil, s.internalError("check failed", err)
+	}
+	if result.Decisions, err = authz.FilterDecisions(ctx, s.db, claims, result.Decisions, s.grantCache); err != nil {
+		return nil, s.internalError("authorization check failed", err)
+	}
+	if result.Conflicts, err = authz.FilterConflicts(ctx, s.db, claims, result.Conflicts, s.grantCache); err != nil {
+		return nil, s.internalError("authorization check failed", err)
+	}
+
+	resp := &akashiv1.CheckResponse{
+		HasPrecedent:         len(result.Decisions) > 0,
+		Decisions:            make([]*akashiv1.Decision, len(result.Decisions)),
+		Conflicts:            make([]*akashiv1.Conflict, len(result.Conflicts)),
+		ConflictsUnavailable: result.ConflictsUnavailable,
+	}
+	for i, d := range result.Decisions {
+		resp.Decisions[i] = decisionToProto(d)
+	}
+	for i, c := range result.Conflicts {
+		resp.Conflicts[i] = conflictToProto(c)
+	}
+	return resp, nil
+}
+
+// Subscribe streams conflict notifications for the caller's org (reader+)
+// until the client cancels or the server stops. With last_event_id set,
+// conflicts detected after that event are replayed first, as GET
+// /v1/subscribe does for Last-Event-ID.
+func (s *Server) Subscribe(in *akashiv1.SubscribeRequest, stream akashiv1.Akashi_SubscribeServer) error {
+	ctx := stream.Context()
+	if _, err := requireRole(ctx, model.RoleReader); err != nil {
+		return err
+	}
+	if s.broker == nil {
+		return status.Error(codes.Unavailable, "subscriptions not available (LISTEN/NOTIFY not configured)")
+	}
+	orgID := ctxutil.OrgIDFromContext(ctx)
+
+	// Subscribe before replaying so nothing detected in between is lost,
+	// then skip live events the replay already covered.
+	ch := s.broker.Subscribe(orgID)
+	defer s.broker.Unsubscribe(ch)
+
+	var replayedUntil time.Time
+	if in.GetLastEventId() != "" {
+		events, cursor, ok, err := s.broker.ReplayConflicts(ctx, orgID, in.GetLastEventId())
+		if err != nil {
+			s.logger.Warn("grpc: replay after reconnect failed", "org_id", orgID, "error", err)
+		}
+		for _, event := range events {
+			id, _, data := server.ParseSSEEvent(event)
+			if err := stream.Send(conflictEventFromPayload(id, data)); err != nil {
+				return err
+			}
+		}
+		if ok {
+			replayedUntil = cursor
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.stopping:
+			return nil
+		case event, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			id, channel, data := server.ParseSSEEvent(event)
+			if channel != storage.ChannelConflicts {
+				continue
+			}
+			if !replayedUntil.IsZero() {
+				if t, err := time.Parse(time.RFC3339Nano, id); err == nil && !t.After(replayedUntil) {
+					continue
+				}
+			}
+			if err := stream.Send(conflictEventFromPayload(id, data)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// internalError logs err and returns an Internal status that does not leak
+// it, like the HTTP server's writeInternalError.
+func (s *Server) internalError(msg string, err error) error {
+	s.logger.Error(msg, "error", err, "transport", "grpc")
+	return status.Error(codes.Internal, msg)
+}
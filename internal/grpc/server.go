@@ -0,0 +1,129 @@
+// Package grpc serves Akashi's trace, query, and check operations and a
+// conflict notification stream over gRPC.
+//
+// The service (akashi.v1.Akashi, generated into akashiv1 from
+// api/proto/akashi/v1/akashi.proto) mirrors POST /v1/trace, /v1/query,
+// /v1/check, and GET /v1/subscribe. It shares the decisions service,
+// storage, and SSE broker with the HTTP server, so a decision traced over
+// one transport is immediately readable over the other.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/grpc/akashiv1"
+	"github.com/ashita-ai/akashi/internal/server"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// ServerConfig holds the dependencies of the gRPC server.
+type ServerConfig struct {
+	DB          *storage.DB
+	JWTMgr      *auth.JWTManager
+	DecisionSvc *decisions.Service
+	Broker      *server.Broker    // nil = Subscribe returns Unavailable.
+	GrantCache  *authz.GrantCache // optional cache for LoadGrantedSet
+	RateLimiter RateLimiter       // nil = RPCs are not rate limited.
+	// Creds secures connections, e.g. TLS from credentials.NewServerTLSFromFile.
+	// nil = plaintext, for deployments that terminate TLS in front of Akashi.
+	Creds  credentials.TransportCredentials
+	Logger *slog.Logger
+	Port   int
+}
+
+// Server is the Akashi gRPC server.
+type Server struct {
+	akashiv1.UnimplementedAkashiServer
+
+	db          *storage.DB
+	decisionSvc *decisions.Service
+	broker      *server.Broker
+	grantCache  *authz.GrantCache
+	logger      *slog.Logger
+	port        int
+
+	grpcServer *grpclib.Server
+	stopping   chan struct{} // closed by GracefulStop to end Subscribe streams
+	stopOnce   sync.Once
+}
+
+// New creates a gRPC server with the Akashi service registered, JWT
+// authentication applied to every RPC, and, when cfg.RateLimiter is set, each
+// RPC charged against the caller's rate limit after authentication. Call
+// Start to accept connections.
+func New(cfg ServerConfig) *Server {
+	s := &Server{
+		db:          cfg.DB,
+		decisionSvc: cfg.DecisionSvc,
+		broker:      cfg.Broker,
+		grantCache:  cfg.GrantCache,
+		logger:      cfg.Logger,
+		port:        cfg.Port,
+		stopping:    make(chan struct{}),
+	}
+	unary := []grpclib.UnaryServerInterceptor{unaryAuthInterceptor(cfg.JWTMgr)}
+	stream := []grpclib.StreamServerInterceptor{streamAuthInterceptor(cfg.JWTMgr)}
+	if cfg.RateLimiter != nil {
+		unary = append(unary, unaryRateLimitInterceptor(cfg.RateLimiter))
+		stream = append(stream, streamRateLimitInterceptor(cfg.RateLimiter))
+	}
+	opts := []grpclib.ServerOption{
+		grpclib.ChainUnaryInterceptor(unary...),
+		grpclib.ChainStreamInterceptor(stream...),
+	}
+	if cfg.Creds != nil {
+		opts = append(opts, grpclib.Creds(cfg.Creds))
+	}
+	s.grpcServer = grpclib.NewServer(opts...)
+	akashiv1.RegisterAkashiServer(s.grpcServer, s)
+	return s
+}
+
+// Start listens on the configured port and serves until GracefulStop.
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.port))
+	if err != nil {
+		return fmt.Errorf("grpc: listen: %w", err)
+	}
+	return s.Serve(lis)
+}
+
+// Serve accepts connections on lis until GracefulStop. It returns nil once
+// the server has been stopped.
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Info("grpc server starting", "addr", lis.Addr().String())
+	if err := s.grpcServer.Serve(lis); err != nil && !errors.Is(err, grpclib.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// GracefulStop stops accepting connections, ends open Subscribe streams, and
+// waits for in-flight RPCs to finish. RPCs still running when ctx is done are
+// cancelled.
+func (s *Server) GracefulStop(ctx context.Context) {
+	s.logger.Info("grpc server shutting down")
+	s.stopOnce.Do(func() { close(s.stopping) })
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-done
+	}
+}
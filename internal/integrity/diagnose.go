@@ -0,0 +1,110 @@
+package integrity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContentFields are the live decision fields covered by the content hash.
+type ContentFields struct {
+	ID           uuid.UUID
+	DecisionType string
+	Outcome      string
+	Confidence   float32
+	Reasoning    *string
+	ValidFrom    time.Time
+}
+
+// CanonicalField is one hashed input exactly as it was fed to SHA-256.
+type CanonicalField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// FieldCandidate is a plausible alternative value for one canonical field.
+// Apply rewrites a copy of the live fields; only Field and Value are reported.
+type FieldCandidate struct {
+	Field string                    `json:"field"`
+	Value string                    `json:"value"`
+	Apply func(*ContentFields) bool `json:"-"` // false skips a candidate that does not apply
+}
+
+// HashDiagnosis explains a content hash mismatch. MatchingCandidates lists
+// the field changes that would reproduce the stored hash; it is empty when
+// no candidate matches (e.g. a freeform text field was edited).
+type HashDiagnosis struct {
+	HashVersion        string           `json:"hash_version"`
+	StoredHash         string           `json:"stored_hash"`
+	RecomputedHash     string           `json:"recomputed_hash"`
+	CanonicalInput     []CanonicalField `json:"canonical_input"`
+	MatchingCandidates []FieldCandidate `json:"matching_candidates"`
+}
+
+// DiagnoseContentHash recomputes the content hash of f with the stored hash's
+// version and reports the exact canonical input. It then tries each candidate,
+// plus built-in checks for a mislabeled hash version and coarser valid_from
+// precision, and reports those whose recomputed hash equals stored. Candidates
+// are tried one at a time; combinations are not explored.
+func DiagnoseContentHash(stored string, f ContentFields, candidates ...FieldCandidate) HashDiagnosis {
+	version, precision := parseHashVersion(stored)
+	compute := computeV1Hash
+	switch {
+	case version == hashV2:
+		compute = func(id uuid.UUID, dt, o string, c float32, r *string, vf time.Time) string {
+			return hashV2Prefix + computeV2Hash(id, dt, o, c, 0, r, vf)
+		}
+	case precision > 0:
+		compute = func(id uuid.UUID, dt, o string, c float32, r *string, vf time.Time) string {
+			return roundedPrefix(precision) + computeV2Hash(id, dt, o, c, precision, r, vf)
+		}
+	}
+	hash := func(c ContentFields) string {
+		return compute(c.ID, c.DecisionType, c.Outcome, c.Confidence, c.Reasoning, c.ValidFrom.Truncate(time.Microsecond))
+	}
+	fieldsVersion := version
+	if precision > 0 {
+		fieldsVersion = hashV2
+	}
+
+	d := HashDiagnosis{
+		HashVersion:        version,
+		StoredHash:         stored,
+		RecomputedHash:     hash(f),
+		CanonicalInput:     canonicalFields(fieldsVersion, f.ID, f.DecisionType, f.Outcome, f.Confidence, precision, f.Reasoning, f.ValidFrom.Truncate(time.Microsecond)),
+		MatchingCandidates: []FieldCandidate{},
+	}
+
+	for _, precision := range []time.Duration{time.Millisecond, time.Second} {
+		vf := f.ValidFrom.Truncate(precision)
+		candidates = append(candidates, FieldCandidate{
+			Field: "valid_from",
+			Value: vf.UTC().Format(time.RFC3339Nano),
+			Apply: func(c *ContentFields) bool {
+				if c.ValidFrom.Equal(vf) {
+					return false
+				}
+				c.ValidFrom = vf
+				return true
+			},
+		})
+	}
+	for _, c := range candidates {
+		alt := f
+		if c.Apply != nil && c.Apply(&alt) && hash(alt) == stored {
+			d.MatchingCandidates = append(d.MatchingCandidates, c)
+		}
+	}
+
+	// A v2 digest stored without its prefix (or vice versa) verifies under the
+	// other algorithm; report it as a version mismatch rather than tampering.
+	vf := f.ValidFrom.Truncate(time.Microsecond)
+	otherVersion, other := hashV2, hashV2Prefix+computeV2Hash(f.ID, f.DecisionType, f.Outcome, f.Confidence, 0, f.Reasoning, vf)
+	if version != hashV1 {
+		otherVersion, other = hashV1, computeV1Hash(f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, vf)
+	}
+	if HashDigest(other) == HashDigest(stored) {
+		d.MatchingCandidates = append(d.MatchingCandidates, FieldCandidate{Field: "hash_version", Value: otherVersion})
+	}
+	return d
+}
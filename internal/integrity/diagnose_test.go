@@ -0,0 +1,80 @@
+package integrity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diagnoseFields() ContentFields {
+	reasoning := "latency budget"
+	return ContentFields{
+		ID:           uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+		DecisionType: "architecture",
+		Outcome:      "use a queue",
+		Confidence:   0.8,
+		Reasoning:    &reasoning,
+		ValidFrom:    time.Date(2026, 4, 1, 12, 0, 0, 123456000, time.UTC),
+	}
+}
+
+func TestDiagnoseContentHash_ReportsCanonicalInput(t *testing.T) {
+	f := diagnoseFields()
+	stored := ComputeContentHash(f.ID, f.DecisionType, "use a cron job", f.Confidence, f.Reasoning, f.ValidFrom)
+
+	d := DiagnoseContentHash(stored, f)
+	assert.Equal(t, "v2", d.HashVersion)
+	assert.Equal(t, stored, d.StoredHash)
+	assert.Equal(t, ComputeContentHash(f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, f.ValidFrom), d.RecomputedHash)
+	assert.Empty(t, d.MatchingCandidates, "an edited outcome cannot be pinpointed")
+
+	names := make([]string, len(d.CanonicalInput))
+	for i, c := range d.CanonicalInput {
+		names[i] = c.Name
+	}
+	assert.Equal(t, []string{"id", "decision_type", "outcome", "confidence", "valid_from", "reasoning"}, names)
+	assert.Equal(t, "use a queue", d.CanonicalInput[2].Value)
+	assert.Equal(t, "0.8000000119", d.CanonicalInput[3].Value)
+	assert.Equal(t, "2026-04-01T12:00:00.123456Z", d.CanonicalInput[4].Value)
+}
+
+func TestDiagnoseContentHash_CandidateMatches(t *testing.T) {
+	f := diagnoseFields()
+	original := f.ValidFrom.Add(-time.Hour)
+	stored := ComputeContentHash(f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, original)
+
+	d := DiagnoseContentHash(stored, f, FieldCandidate{
+		Field: "valid_from",
+		Value: original.Format(time.RFC3339Nano),
+		Apply: func(c *ContentFields) bool { c.ValidFrom = original; return true },
+	})
+	require.Len(t, d.MatchingCandidates, 1)
+	assert.Equal(t, "valid_from", d.MatchingCandidates[0].Field)
+}
+
+func TestDiagnoseContentHash_CoarserValidFromPrecision(t *testing.T) {
+	f := diagnoseFields()
+	stored := ComputeContentHash(f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, f.ValidFrom.Truncate(time.Second))
+
+	d := DiagnoseContentHash(stored, f)
+	require.Len(t, d.MatchingCandidates, 1)
+	assert.Equal(t, "valid_from", d.MatchingCandidates[0].Field)
+	assert.Equal(t, "2026-04-01T12:00:00Z", d.MatchingCandidates[0].Value)
+}
+
+func TestDiagnoseContentHash_HashVersionMismatch(t *testing.T) {
+	f := diagnoseFields()
+	// A v2 digest that lost its prefix is treated as v1 and fails verification.
+	stored := strings.TrimPrefix(ComputeContentHash(f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, f.ValidFrom), "v2:")
+	require.False(t, VerifyContentHash(stored, f.ID, f.DecisionType, f.Outcome, f.Confidence, f.Reasoning, f.ValidFrom))
+
+	d := DiagnoseContentHash(stored, f)
+	assert.Equal(t, "v1", d.HashVersion)
+	require.Len(t, d.MatchingCandidates, 1)
+	assert.Equal(t, "hash_version", d.MatchingCandidates[0].Field)
+	assert.Equal(t, "v2", d.MatchingCandidates[0].Value)
+}
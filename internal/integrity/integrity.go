@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -17,73 +18,219 @@ import (
 
 // Hash version prefixes. New hashes get v2 (length-prefixed encoding).
 // Old hashes (no prefix) are treated as v1 (pipe-delimited) for backward compatibility.
+// "v2r<N>:" marks a v2 hash whose confidence was rounded to N decimal places
+// (see ComputeRoundedContentHash).
 const (
-	hashV2Prefix = "v2:"
+	hashV2Prefix        = "v2:"
+	hashV2RoundedPrefix = "v2r"
 )
 
-// ComputeContentHash produces a versioned SHA-256 hex digest from the canonical decision fields.
-// New hashes use the v2 format (length-prefixed binary encoding) and carry a "v2:" prefix.
+// MaxConfidencePrecision is the largest decimal precision confidence can be
+// rounded to. float32 carries about 7 significant digits, so finer rounding
+// would not remove float noise.
+const MaxConfidencePrecision = 6
+
+// Hash version names reported by DiagnoseContentHash.
+const (
+	hashV1 = "v1"
+	hashV2 = "v2"
+)
+
+// HashAlgorithm is one version of the decision content-hash algorithm.
+// Compute returns the hash in its stored form, version prefix included. A
+// positive precision rounds confidence (see ComputeRoundedContentHash);
+// versions without rounded forms ignore it.
+type HashAlgorithm struct {
+	Version int
+	Name    string
+	Compute func(id uuid.UUID, decisionType, outcome string, confidence float32, precision int, reasoning *string, validFrom time.Time) string
+}
+
+// CurrentHashVersion is the algorithm version used for new hashes and
+// recorded in decisions.hash_version.
+const CurrentHashVersion = 2
+
+// HashAlgorithms is the registry of content-hash algorithms keyed by version.
+// Versions are never removed or changed: stored rows verify against the
+// version that produced them. A new algorithm gets the next version number
+// and becomes CurrentHashVersion.
+var HashAlgorithms = map[int]HashAlgorithm{
+	1: {Version: 1, Name: hashV1, Compute: func(id uuid.UUID, decisionType, outcome string, confidence float32, _ int, reasoning *string, validFrom time.Time) string {
+		return computeV1Hash(id, decisionType, outcome, confidence, reasoning, validFrom)
+	}},
+	2: {Version: 2, Name: hashV2, Compute: func(id uuid.UUID, decisionType, outcome string, confidence float32, precision int, reasoning *string, validFrom time.Time) string {
+		if precision > 0 {
+			return roundedPrefix(precision) + computeV2Hash(id, decisionType, outcome, confidence, precision, reasoning, validFrom)
+		}
+		return hashV2Prefix + computeV2Hash(id, decisionType, outcome, confidence, 0, reasoning, validFrom)
+	}},
+}
+
+// ComputeContentHash produces a versioned SHA-256 hex digest from the canonical
+// decision fields using the CurrentHashVersion algorithm. v2 hashes use a
+// length-prefixed binary encoding and carry a "v2:" prefix.
 //
 // validFrom is truncated to microsecond precision before hashing because PostgreSQL
 // stores timestamptz at microsecond resolution. Without truncation, a hash computed
 // with Go's nanosecond-precision time.Now() would never match a hash recomputed from
 // the DB-roundtripped timestamp, causing VerifyContentHash to always report "tampered."
 func ComputeContentHash(id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time) string {
-	return hashV2Prefix + computeV2Hash(id, decisionType, outcome, confidence, reasoning, validFrom.Truncate(time.Microsecond))
+	return HashAlgorithms[CurrentHashVersion].Compute(id, decisionType, outcome, confidence, 0, reasoning, validFrom.Truncate(time.Microsecond))
+}
+
+// ComputeRoundedContentHash is ComputeContentHash with confidence rounded to
+// precision decimal places and hashed in exactly that many digits, so
+// 0.87 and 0.8700001 hash identically and any client can reproduce the
+// canonical "0.87" without float32 formatting. The hash carries a "v2r<N>:"
+// prefix recording the precision for verification. precision <= 0 falls back
+// to ComputeContentHash; values above MaxConfidencePrecision are clamped.
+func ComputeRoundedContentHash(id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time, precision int) string {
+	if precision <= 0 {
+		return ComputeContentHash(id, decisionType, outcome, confidence, reasoning, validFrom)
+	}
+	precision = min(precision, MaxConfidencePrecision)
+	return HashAlgorithms[CurrentHashVersion].Compute(id, decisionType, outcome, confidence, precision, reasoning, validFrom.Truncate(time.Microsecond))
+}
+
+// RoundConfidence rounds c to precision decimal places. precision <= 0
+// returns c unchanged.
+func RoundConfidence(c float32, precision int) float32 {
+	if precision <= 0 {
+		return c
+	}
+	scale := math.Pow10(min(precision, MaxConfidencePrecision))
+	return float32(math.Round(float64(c)*scale) / scale)
+}
+
+func roundedPrefix(precision int) string {
+	return hashV2RoundedPrefix + strconv.Itoa(precision) + ":"
+}
+
+// parseHashVersion returns the version name of a stored hash and, for
+// rounded v2 hashes, the confidence precision (0 otherwise).
+func parseHashVersion(stored string) (version string, precision int) {
+	if strings.HasPrefix(stored, hashV2Prefix) {
+		return hashV2, 0
+	}
+	if rest, ok := strings.CutPrefix(stored, hashV2RoundedPrefix); ok {
+		if n, _, found := strings.Cut(rest, ":"); found {
+			if p, err := strconv.Atoi(n); err == nil && p >= 1 && p <= MaxConfidencePrecision {
+				return hashV2RoundedPrefix + n, p
+			}
+		}
+	}
+	return hashV1, 0
 }
 
-// VerifyContentHash checks whether a stored hash matches the recomputed hash.
-// It detects the hash version from the prefix and uses the appropriate algorithm:
-//   - "v2:" prefix -> length-prefixed binary encoding (current)
-//   - no prefix   -> pipe-delimited encoding (legacy v1)
+// HashVersion returns the algorithm version that produced a stored hash,
+// detected from its prefix:
+//   - "v2:" prefix   -> 2, length-prefixed binary encoding
+//   - "v2rN:" prefix -> 2, with confidence rounded to N decimal places
+//   - no prefix      -> 1, pipe-delimited encoding (legacy)
+func HashVersion(stored string) int {
+	if version, _ := parseHashVersion(stored); version == hashV1 {
+		return 1
+	}
+	return 2
+}
+
+// VerifyContentHash checks whether a stored hash matches the recomputed hash,
+// detecting the algorithm version from the hash prefix (see HashVersion).
 //
 // validFrom is truncated to microsecond precision to match ComputeContentHash behavior.
 func VerifyContentHash(stored string, id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time) bool {
-	vf := validFrom.Truncate(time.Microsecond)
-	if strings.HasPrefix(stored, hashV2Prefix) {
-		return stored == hashV2Prefix+computeV2Hash(id, decisionType, outcome, confidence, reasoning, vf)
+	return VerifyContentHashVersion(0, stored, id, decisionType, outcome, confidence, reasoning, validFrom)
+}
+
+// VerifyContentHashVersion checks a stored hash against the algorithm of the
+// given version, as recorded in decisions.hash_version. Version 0 means the
+// version is unknown and is detected from the hash prefix. An unregistered
+// version never verifies.
+func VerifyContentHashVersion(version int, stored string, id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time) bool {
+	if version == 0 {
+		version = HashVersion(stored)
+	}
+	alg, ok := HashAlgorithms[version]
+	if !ok {
+		return false
+	}
+	_, precision := parseHashVersion(stored)
+	return stored == alg.Compute(id, decisionType, outcome, confidence, precision, reasoning, validFrom.Truncate(time.Microsecond))
+}
+
+// HashDigest returns the hex digest of a stored or user-supplied content hash
+// with any version prefix removed, lowercased.
+func HashDigest(hash string) string {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if version, _ := parseHashVersion(hash); version != hashV1 {
+		_, digest, _ := strings.Cut(hash, ":")
+		return digest
+	}
+	return hash
+}
+
+// StoredHashForms returns every form in which a bare hex digest can appear in
+// decisions.content_hash: the legacy unprefixed v1 form, the "v2:" form, and
+// each "v2rN:" rounded form.
+func StoredHashForms(digest string) []string {
+	forms := []string{digest, hashV2Prefix + digest}
+	for p := 1; p <= MaxConfidencePrecision; p++ {
+		forms = append(forms, roundedPrefix(p)+digest)
 	}
-	// Legacy v1 hashes (pipe-delimited, no version prefix).
-	return stored == computeV1Hash(id, decisionType, outcome, confidence, reasoning, vf)
+	return forms
 }
 
 // computeV1Hash produces the legacy pipe-delimited SHA-256 hex digest.
 // Kept for backward compatibility with hashes created before the v2 format.
 func computeV1Hash(id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time) string {
-	r := ""
-	if reasoning != nil {
-		r = *reasoning
+	fields := canonicalFields(hashV1, id, decisionType, outcome, confidence, 0, reasoning, validFrom)
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		values[i] = f.Value
 	}
-	canonical := fmt.Sprintf("%s|%s|%s|%s|%s|%s",
-		id.String(), decisionType, outcome,
-		strconv.FormatFloat(float64(confidence), 'f', 10, 32),
-		r, validFrom.UTC().Format(time.RFC3339Nano))
-	sum := sha256.Sum256([]byte(canonical))
+	sum := sha256.Sum256([]byte(strings.Join(values, "|")))
 	return hex.EncodeToString(sum[:])
 }
 
 // computeV2Hash produces a length-prefixed SHA-256 hex digest.
 // Each field is encoded as a 4-byte big-endian length prefix followed by the field bytes.
 // This avoids delimiter collisions when freeform text fields contain pipe characters.
-func computeV2Hash(id uuid.UUID, decisionType, outcome string, confidence float32, reasoning *string, validFrom time.Time) string {
+// A positive precision rounds confidence (see ComputeRoundedContentHash).
+func computeV2Hash(id uuid.UUID, decisionType, outcome string, confidence float32, precision int, reasoning *string, validFrom time.Time) string {
 	h := sha256.New()
-	writeField := func(s string) {
+	for _, f := range canonicalFields(hashV2, id, decisionType, outcome, confidence, precision, reasoning, validFrom) {
 		var lenBuf [4]byte
-		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(s))) //nolint:gosec // field lengths are bounded by HTTP request body limits (~1MB)
+		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(f.Value))) //nolint:gosec // field lengths are bounded by HTTP request body limits (~1MB)
 		h.Write(lenBuf[:])
-		h.Write([]byte(s))
+		h.Write([]byte(f.Value))
 	}
-	writeField(id.String())
-	writeField(decisionType)
-	writeField(outcome)
-	writeField(strconv.FormatFloat(float64(confidence), 'f', 10, 32))
-	writeField(validFrom.UTC().Format(time.RFC3339Nano))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalFields returns the exact strings hashed by the given version, in
+// hashing order. v1 puts reasoning before valid_from; v2 puts it last. A
+// positive precision writes confidence rounded to exactly that many digits.
+func canonicalFields(version string, id uuid.UUID, decisionType, outcome string, confidence float32, precision int, reasoning *string, validFrom time.Time) []CanonicalField {
 	r := ""
 	if reasoning != nil {
 		r = *reasoning
 	}
-	writeField(r)
-	return hex.EncodeToString(h.Sum(nil))
+	conf := strconv.FormatFloat(float64(confidence), 'f', 10, 32)
+	if precision > 0 {
+		scale := math.Pow10(precision)
+		conf = strconv.FormatFloat(math.Round(float64(confidence)*scale)/scale, 'f', precision, 64)
+	}
+	vf := validFrom.UTC().Format(time.RFC3339Nano)
+	if version == hashV1 {
+		return []CanonicalField{
+			{"id", id.String()}, {"decision_type", decisionType}, {"outcome", outcome},
+			{"confidence", conf}, {"reasoning", r}, {"valid_from", vf},
+		}
+	}
+	return []CanonicalField{
+		{"id", id.String()}, {"decision_type", decisionType}, {"outcome", outcome},
+		{"confidence", conf}, {"valid_from", vf}, {"reasoning", r},
+	}
 }
 
 // VerifyBatchProof recomputes the Merkle root from the given leaf hashes and
@@ -32,6 +32,51 @@ func TestComputeContentHash_Deterministic(t *testing.T) {
 	}
 }
 
+func TestHashDigest_RoundTripsStoredForms(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	stored := ComputeContentHash(id, "architecture", "microservices", 0.85, nil, time.Now())
+
+	digest := HashDigest(stored)
+	assert.Len(t, digest, 64)
+	assert.Equal(t, digest, HashDigest(strings.ToUpper(digest)))
+	assert.Contains(t, StoredHashForms(digest), stored)
+
+	legacy := computeV1Hash(id, "architecture", "microservices", 0.85, nil, time.Now())
+	assert.Equal(t, legacy, HashDigest(legacy))
+	assert.Contains(t, StoredHashForms(legacy), legacy)
+}
+
+func TestComputeRoundedContentHash_AbsorbsFloatNoise(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	validFrom := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	// 0.87 and 0.870000x are different float32 values: unrounded they hash
+	// differently, rounded to 2 places they hash identically.
+	noisy := float32(0.8700001)
+	require.NotEqual(t, ComputeContentHash(id, "arch", "x", 0.87, nil, validFrom),
+		ComputeContentHash(id, "arch", "x", noisy, nil, validFrom))
+
+	h1 := ComputeRoundedContentHash(id, "arch", "x", RoundConfidence(0.87, 2), nil, validFrom, 2)
+	h2 := ComputeRoundedContentHash(id, "arch", "x", RoundConfidence(noisy, 2), nil, validFrom, 2)
+	assert.Equal(t, h1, h2)
+	assert.True(t, strings.HasPrefix(h1, "v2r2:"), "got %q", h1)
+	assert.True(t, VerifyContentHash(h1, id, "arch", "x", RoundConfidence(noisy, 2), nil, validFrom))
+	assert.False(t, VerifyContentHash(h1, id, "arch", "x", 0.88, nil, validFrom))
+
+	digest := HashDigest(h1)
+	assert.Len(t, digest, 64)
+	assert.Contains(t, StoredHashForms(digest), h1)
+}
+
+func TestComputeRoundedContentHash_ZeroPrecisionIsV2(t *testing.T) {
+	id := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	validFrom := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, ComputeContentHash(id, "arch", "x", 0.87, nil, validFrom),
+		ComputeRoundedContentHash(id, "arch", "x", 0.87, nil, validFrom, 0))
+	assert.Equal(t, float32(0.8700001), RoundConfidence(0.8700001, 0))
+}
+
 func TestComputeContentHash_NilReasoning(t *testing.T) {
 	id := uuid.MustParse("22222222-2222-2222-2222-222222222222")
 	validFrom := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
@@ -105,6 +150,51 @@ func TestVerifyContentHash_V1Legacy(t *testing.T) {
 	}
 }
 
+func TestHashAlgorithms_CurrentIsV2(t *testing.T) {
+	id := uuid.MustParse("56565656-5656-5656-5656-565656565656")
+	validFrom := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	if _, ok := HashAlgorithms[CurrentHashVersion]; !ok {
+		t.Fatalf("CurrentHashVersion %d is not registered", CurrentHashVersion)
+	}
+	hash := ComputeContentHash(id, "routing", "route_a", 0.8, nil, validFrom)
+	if got := HashVersion(hash); got != 2 {
+		t.Fatalf("new hashes should be v2, got v%d", got)
+	}
+	if got := HashVersion(ComputeRoundedContentHash(id, "routing", "route_a", 0.8, nil, validFrom, 2)); got != 2 {
+		t.Fatalf("rounded hashes should be v2, got v%d", got)
+	}
+	if !VerifyContentHashVersion(CurrentHashVersion, hash, id, "routing", "route_a", 0.8, nil, validFrom) {
+		t.Fatal("new hash should verify against the current version")
+	}
+}
+
+func TestVerifyContentHashVersion_V1StillVerifies(t *testing.T) {
+	id := uuid.MustParse("57575757-5757-5757-5757-575757575757")
+	validFrom := time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC)
+	reasoning := "recorded before v2"
+
+	v1Hash := HashAlgorithms[1].Compute(id, "routing", "route_a", 0.8, 0, &reasoning, validFrom)
+	if got := HashVersion(v1Hash); got != 1 {
+		t.Fatalf("unprefixed hash should be v1, got v%d", got)
+	}
+
+	for _, version := range []int{1, 0} {
+		if !VerifyContentHashVersion(version, v1Hash, id, "routing", "route_a", 0.8, &reasoning, validFrom) {
+			t.Fatalf("v1 hash should verify with recorded version %d", version)
+		}
+	}
+	if VerifyContentHashVersion(1, v1Hash, id, "routing", "route_b", 0.8, &reasoning, validFrom) {
+		t.Fatal("v1 hash should fail verification for different outcome")
+	}
+	if VerifyContentHashVersion(2, v1Hash, id, "routing", "route_a", 0.8, &reasoning, validFrom) {
+		t.Fatal("v1 hash should not verify against the v2 algorithm")
+	}
+	if VerifyContentHashVersion(99, v1Hash, id, "routing", "route_a", 0.8, &reasoning, validFrom) {
+		t.Fatal("unregistered version should never verify")
+	}
+}
+
 func TestV2HashAvoidsPipeCollision(t *testing.T) {
 	// Two inputs that would collide with pipe-delimited encoding but not with
 	// length-prefixed encoding. In v1, "a|b" + "|" + "c" == "a" + "|" + "b|c"
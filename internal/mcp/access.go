@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// toolMinRole is the least role that may see and call each tool, mirroring
+// the HTTP routes: writes (trace, resolve, assess, supersede) need agent+,
+// everything else is reader+. Tools missing from the map default to agent+.
+var toolMinRole = map[string]model.AgentRole{
+	"akashi_check":     model.RoleReader,
+	"akashi_query":     model.RoleReader,
+	"akashi_conflicts": model.RoleReader,
+	"akashi_stats":     model.RoleReader,
+	"akashi_why":       model.RoleReader,
+	"akashi_timeline":  model.RoleReader,
+	"akashi_trace":     model.RoleAgent,
+	"akashi_resolve":   model.RoleAgent,
+	"akashi_assess":    model.RoleAgent,
+	"akashi_supersede": model.RoleAgent,
+}
+
+// orgSettingsReader is implemented by stores with per-org settings
+// (Postgres). Lite mode has none, so no tools are disabled there.
+type orgSettingsReader interface {
+	GetOrgSettings(ctx context.Context, orgID uuid.UUID) (model.OrgSettings, error)
+}
+
+// addTool registers a tool whose handler re-checks toolAccess, so hiding a
+// tool from tools/list is never the only thing keeping a caller out.
+func (s *Server) addTool(tool mcplib.Tool, handler mcpserver.ToolHandlerFunc) {
+	s.mcpServer.AddTool(tool, func(ctx context.Context, request mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+		if err := s.toolAccess(ctx, tool.Name, s.disabledTools(ctx)); err != nil {
+			return errorResult(err.Error()), nil
+		}
+		return handler(ctx, request)
+	})
+}
+
+// filterTools is the tools/list filter: it drops tools the caller's role or
+// org settings do not permit.
+func (s *Server) filterTools(ctx context.Context, tools []mcplib.Tool) []mcplib.Tool {
+	disabled := s.disabledTools(ctx)
+	allowed := make([]mcplib.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if s.toolAccess(ctx, tool.Name, disabled) == nil {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
+
+// toolAccess returns an error explaining why the caller may not use the
+// named tool, or nil if it may. Unauthenticated calls are left to the
+// handlers, which reject them.
+func (s *Server) toolAccess(ctx context.Context, name string, disabled *model.MCPToolsPolicy) error {
+	if disabled.IsDisabled(name) {
+		return fmt.Errorf("%s is disabled for this organization", name)
+	}
+	claims := ctxutil.ClaimsFromContext(ctx)
+	if claims == nil {
+		return nil
+	}
+	minRole, ok := toolMinRole[name]
+	if !ok {
+		minRole = model.RoleAgent
+	}
+	if !model.RoleAtLeast(claims.Role, minRole) {
+		return fmt.Errorf("%s requires the %s role or higher", name, minRole)
+	}
+	return nil
+}
+
+// disabledTools returns the org's MCP tools policy, or nil when none is set
+// or the settings cannot be loaded. A failed lookup leaves tools enabled;
+// role checks still apply.
+func (s *Server) disabledTools(ctx context.Context) *model.MCPToolsPolicy {
+	reader, ok := s.db.(orgSettingsReader)
+	if !ok {
+		return nil
+	}
+	orgID := ctxutil.OrgIDFromContext(ctx)
+	settings, err := reader.GetOrgSettings(ctx, orgID)
+	if err != nil {
+		s.logger.Warn("mcp: failed to load org settings, no tools disabled", "org_id", orgID, "error", err)
+		return nil
+	}
+	return settings.Settings.MCPTools
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestToolMinRoleCoversEveryTool(t *testing.T) {
+	for _, name := range model.MCPToolNames {
+		_, ok := toolMinRole[name]
+		assert.True(t, ok, "%s has no minimum role", name)
+	}
+	assert.Len(t, toolMinRole, len(model.MCPToolNames))
+}
+
+func TestFilterTools_ByRoleAndOrgPolicy(t *testing.T) {
+	s := &Server{logger: slog.New(slog.DiscardHandler)}
+	tools := make([]mcplib.Tool, len(model.MCPToolNames))
+	for i, name := range model.MCPToolNames {
+		tools[i] = mcplib.NewTool(name)
+	}
+	names := func(ts []mcplib.Tool) []string {
+		out := make([]string, len(ts))
+		for i, tool := range ts {
+			out[i] = tool.Name
+		}
+		return out
+	}
+	ctxFor := func(role model.AgentRole) context.Context {
+		return ctxutil.WithClaims(context.Background(), &auth.Claims{AgentID: "a", OrgID: uuid.Nil, Role: role})
+	}
+
+	assert.Equal(t, []string{"akashi_check", "akashi_query", "akashi_conflicts", "akashi_stats", "akashi_why", "akashi_timeline"},
+		names(s.filterTools(ctxFor(model.RoleReader), tools)))
+	assert.Equal(t, model.MCPToolNames, names(s.filterTools(ctxFor(model.RoleAgent), tools)))
+
+	err := s.toolAccess(ctxFor(model.RoleReader), "akashi_trace", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires the agent role")
+
+	policy := &model.MCPToolsPolicy{Disabled: []string{"akashi_stats"}}
+	err = s.toolAccess(ctxFor(model.RoleAdmin), "akashi_stats", policy)
+	require.Error(t, err, "org-disabled tools are refused for every role")
+	assert.NoError(t, s.toolAccess(ctxFor(model.RoleAdmin), "akashi_check", policy))
+}
@@ -41,3 +41,7 @@ func actionNeeded(conflicts []model.DecisionConflict) bool {
 func compactResolution(r model.ConflictResolution) map[string]any {
 	return compact.Resolution(r)
 }
+
+func suggestionNote(suggestions []string) string {
+	return compact.SuggestionNote(suggestions)
+}
@@ -41,6 +41,9 @@ TOOLS:
 - akashi_resolve: resolve a conflict or mark it as a false positive (set winner or false_positive)
 - akashi_assess: record whether a prior decision turned out to be correct
 - akashi_stats: aggregate health metrics for the decision trail
+- akashi_supersede: revise one of your earlier decisions with a new outcome
+- akashi_why: explain a decision's alternatives, evidence, and whether it was superseded
+- akashi_timeline: your (or another agent's) recent decisions in order, with supersession and conflict signals
 
 CHECK BEFORE: choosing architecture/technology, starting a review or audit,
 making trade-offs, filing issues/PRs, changing existing behavior.
@@ -92,6 +95,8 @@ func (s *Server) SetAutoAssessor(a *autoassess.Assessor) {
 }
 
 // New creates and configures a new MCP server with all resources, tools, and prompts.
+// Tools are listed and callable according to the caller's role and the org's
+// mcp_tools setting (see toolAccess).
 // standardTypes controls which decision types are suggested in completeness tips.
 // Pass nil to use quality.DefaultStandardDecisionTypes.
 func New(db storage.Store, decisionSvc *decisions.Service, grantCache *authz.GrantCache, logger *slog.Logger, version string, highConfWarnThreshold float32, standardTypes map[string]bool) *Server {
@@ -114,6 +119,7 @@ func New(db storage.Store, decisionSvc *decisions.Service, grantCache *authz.Gra
 		version,
 		mcpserver.WithResourceCapabilities(true, true),
 		mcpserver.WithToolCapabilities(true),
+		mcpserver.WithToolFilter(s.filterTools),
 		mcpserver.WithPromptCapabilities(true),
 		mcpserver.WithRoots(),
 		mcpserver.WithInstructions(serverInstructions),
@@ -159,6 +159,8 @@ can learn from it, and so the decision is provable later.
 - akashi_resolve: Resolve a conflict or mark it as a false positive (set winner or false_positive)
 - akashi_assess: Record whether a past decision turned out to be correct
 - akashi_stats: Aggregate health metrics for the decision trail
+- akashi_supersede: Revise one of your earlier decisions with a new outcome
+- akashi_why: Explain a decision's alternatives, evidence, and whether it was superseded
 
 ## Decision Types
 
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// decisionReader is implemented by stores that can load a single decision
+// with its alternatives (Postgres). Lite mode has no single-decision read
+// path, so akashi_supersede is refused there.
+type decisionReader interface {
+	GetDecision(ctx context.Context, orgID, id uuid.UUID, opts storage.GetDecisionOpts) (model.Decision, error)
+}
+
+// handleSupersede records a revision of an existing decision. The revision
+// goes through the same trace path as akashi_trace with supersedes_id set, so
+// it is embedded, conflict-scored, policy-checked, and mirrored to the audit
+// sink like any other decision. Everything the caller does not restate is
+// carried forward from the original.
+func (s *Server) handleSupersede(ctx context.Context, request mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	orgID := ctxutil.OrgIDFromContext(ctx)
+	claims := ctxutil.ClaimsFromContext(ctx)
+
+	if claims == nil {
+		return errorResult("authentication required"), nil
+	}
+
+	reader, ok := s.db.(decisionReader)
+	if !ok {
+		return errorResult("akashi_supersede is not supported by this storage backend"), nil
+	}
+
+	originalStr := request.GetString("original_decision_id", "")
+	if originalStr == "" {
+		return errorResult("original_decision_id is required"), nil
+	}
+	originalID, err := uuid.Parse(originalStr)
+	if err != nil {
+		return errorResult("original_decision_id must be a valid UUID"), nil
+	}
+
+	outcome := request.GetString("outcome", "")
+	if outcome == "" {
+		return errorResult("outcome is required"), nil
+	}
+	if len(outcome) > model.MaxOutcomeLen {
+		return errorResult(fmt.Sprintf("outcome exceeds maximum length of %d bytes", model.MaxOutcomeLen)), nil
+	}
+	if _, ok := request.GetArguments()["confidence"]; !ok {
+		return errorResult("confidence is required"), nil
+	}
+	confidence := float32(request.GetFloat("confidence", 0))
+	if confidence < 0 || confidence > 1 {
+		return errorResult("confidence must be between 0 and 1"), nil
+	}
+	var reasoning *string
+	if r := request.GetString("reasoning", ""); r != "" {
+		if len(r) > model.MaxReasoningLen {
+			return errorResult(fmt.Sprintf("reasoning exceeds maximum length of %d bytes", model.MaxReasoningLen)), nil
+		}
+		reasoning = &r
+	}
+
+	original, err := reader.GetDecision(ctx, orgID, originalID, storage.GetDecisionOpts{IncludeAlts: true})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return errorResult(fmt.Sprintf("decision %s not found", originalID)), nil
+		}
+		return nil, fmt.Errorf("akashi_supersede: get original decision: %w", err)
+	}
+	if !model.RoleAtLeast(claims.Role, model.RoleAdmin) && original.AgentID != claims.AgentID {
+		return errorResult("agents can only supersede their own decisions"), nil
+	}
+	if original.ValidTo != nil {
+		return errorResult(fmt.Sprintf("decision %s has already been superseded or retracted; supersede its latest revision instead", originalID)), nil
+	}
+
+	alternatives := make([]model.TraceAlternative, 0, len(original.Alternatives))
+	for _, a := range original.Alternatives {
+		alternatives = append(alternatives, model.TraceAlternative{Label: a.Label, RejectionReason: a.RejectionReason})
+	}
+	if err := s.decisionSvc.CheckTracePolicy(ctx, orgID, original.AgentID, original.DecisionType, len(alternatives)); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	// The original's confidence bounds only carry over when they still
+	// bracket the new confidence.
+	var confidenceLow, confidenceHigh *float32
+	if original.ConfidenceLow != nil && original.ConfidenceHigh != nil &&
+		*original.ConfidenceLow <= confidence && confidence <= *original.ConfidenceHigh {
+		confidenceLow, confidenceHigh = original.ConfidenceLow, original.ConfidenceHigh
+	}
+
+	result, err := s.decisionSvc.Trace(ctx, orgID, decisions.TraceInput{
+		AgentID:         original.AgentID,
+		Metadata:        original.Metadata,
+		PrecedentRef:    original.PrecedentRef,
+		PrecedentReason: original.PrecedentReason,
+		SupersedesID:    &originalID,
+		SessionID:       original.SessionID,
+		AgentContext:    original.AgentContext,
+		ContextSnapshot: original.ContextSnapshot,
+		APIKeyID:        claims.APIKeyID,
+		Namespace:       original.Namespace,
+		AuditMeta: &ctxutil.AuditMeta{
+			RequestID:    uuid.New().String(),
+			OrgID:        orgID,
+			ActorAgentID: claims.AgentID,
+			ActorRole:    string(claims.Role),
+			HTTPMethod:   "MCP",
+			Endpoint:     "akashi_supersede",
+		},
+		Decision: model.TraceDecision{
+			DecisionType:   original.DecisionType,
+			Outcome:        outcome,
+			Confidence:     confidence,
+			ConfidenceLow:  confidenceLow,
+			ConfidenceHigh: confidenceHigh,
+			Reasoning:      reasoning,
+			Alternatives:   alternatives,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			// Lost a race with another revision between the read and the write.
+			return errorResult(fmt.Sprintf("decision %s has already been superseded; supersede its latest revision instead", originalID)), nil
+		}
+		return errorResult(fmt.Sprintf("failed to record decision: %v", err)), nil
+	}
+	decision := result.Decision
+
+	resultData, _ := json.MarshalIndent(map[string]any{
+		"decision_id":     decision.ID,
+		"superseded_id":   originalID,
+		"outcome_flipped": decision.OutcomeFlipped,
+	}, "", "  ")
+
+	return &mcplib.CallToolResult{
+		Content: []mcplib.Content{
+			mcplib.TextContent{Type: "text", Text: string(resultData)},
+		},
+	}, nil
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+const (
+	defaultTimelineLimit = 20
+	maxTimelineLimit     = 100
+)
+
+// decisionHistoryLister is implemented by stores that keep superseded
+// revisions queryable (Postgres). Lite mode only queries current decisions,
+// so akashi_timeline is refused there.
+type decisionHistoryLister interface {
+	ListDecisionHistory(ctx context.Context, orgID uuid.UUID, filters model.QueryFilters, limit int) ([]model.Decision, error)
+}
+
+// timelineEntry is one decision in an akashi_timeline response.
+type timelineEntry struct {
+	DecisionID    uuid.UUID `json:"decision_id"`
+	Type          string    `json:"type"`
+	Outcome       string    `json:"outcome"`
+	Confidence    float32   `json:"confidence"`
+	ValidFrom     time.Time `json:"valid_from"`
+	WasSuperseded bool      `json:"was_superseded"`
+	ConflictCount int       `json:"conflict_count"`
+}
+
+// timelineResult is the akashi_timeline response body.
+type timelineResult struct {
+	AgentID   string          `json:"agent_id"`
+	Decisions []timelineEntry `json:"decisions"`
+}
+
+func (s *Server) handleTimeline(ctx context.Context, request mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	orgID := ctxutil.OrgIDFromContext(ctx)
+	claims := ctxutil.ClaimsFromContext(ctx)
+
+	if claims == nil {
+		return errorResult("authentication required"), nil
+	}
+
+	lister, ok := s.db.(decisionHistoryLister)
+	if !ok {
+		return errorResult("akashi_timeline is not supported by this storage backend"), nil
+	}
+
+	agentID := request.GetString("agent_id", "")
+	if agentID == "" {
+		agentID = claims.AgentID
+	}
+	limit := request.GetInt("limit", defaultTimelineLimit)
+	if limit < 1 || limit > maxTimelineLimit {
+		return errorResult(fmt.Sprintf("limit must be between 1 and %d", maxTimelineLimit)), nil
+	}
+
+	allowed, err := authz.CanAccessAgent(ctx, s.db, claims, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("akashi_timeline: access check: %w", err)
+	}
+	if !allowed {
+		return errorResult(fmt.Sprintf("no access to agent %q", agentID)), nil
+	}
+
+	ns := ctxutil.NamespaceFromContext(ctx)
+	filters := model.QueryFilters{AgentIDs: []string{agentID}, Namespace: &ns}
+	if dt := strings.ToLower(strings.TrimSpace(request.GetString("decision_type", ""))); dt != "" {
+		filters.DecisionType = &dt
+	}
+
+	decs, err := lister.ListDecisionHistory(ctx, orgID, filters, limit)
+	if err != nil {
+		return errorResult(fmt.Sprintf("timeline failed: %v", err)), nil
+	}
+	// Storage returns the newest window first; the arc reads oldest to newest.
+	slices.Reverse(decs)
+
+	ids := make([]uuid.UUID, len(decs))
+	for i := range decs {
+		ids[i] = decs[i].ID
+	}
+	conflictCounts, err := s.db.GetConflictCountsBatch(ctx, ids, orgID)
+	if err != nil {
+		return errorResult(fmt.Sprintf("timeline failed: %v", err)), nil
+	}
+
+	res := timelineResult{AgentID: agentID, Decisions: make([]timelineEntry, len(decs))}
+	for i, d := range decs {
+		res.Decisions[i] = timelineEntry{
+			DecisionID:    d.ID,
+			Type:          d.DecisionType,
+			Outcome:       d.Outcome,
+			Confidence:    d.Confidence,
+			ValidFrom:     d.ValidFrom,
+			WasSuperseded: d.ValidTo != nil,
+			ConflictCount: conflictCounts[d.ID],
+		}
+	}
+
+	resultData, _ := json.MarshalIndent(res, "", "  ")
+	return &mcplib.CallToolResult{
+		Content: []mcplib.Content{
+			mcplib.TextContent{Type: "text", Text: string(resultData)},
+		},
+	}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -26,7 +27,7 @@ import (
 
 func (s *Server) registerTools() {
 	// akashi_check — look up precedents and active conflicts before deciding.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_check",
 			mcplib.WithDescription(`Check the black box for decision precedents before making a new one.
 
@@ -54,6 +55,11 @@ WHAT YOU GET BACK:
   mechanism that prevents agents from resurrecting losing approaches after
   a conflict has been formally resolved.
 - precedent_ref_hint: UUID to copy into akashi_trace's precedent_ref field
+- effective_confidence (per decision, when the org configures precedent
+  decay): confidence discounted for age. Decisions are then ranked by it, so
+  a stale high-confidence decision can rank below a recent moderate one.
+- suggestions (only with suggest=true): when agent_id matches no agent and
+  nothing was found, existing agent_ids that look like what you meant.
 
 decision_type is optional. When omitted the search spans all types —
 useful when you're not sure how past decisions were categorized.
@@ -92,12 +98,15 @@ regardless of whether they were tagged "architecture" or "trade_off".`),
 			mcplib.WithString("format",
 				mcplib.Description(`Response format: "concise" (default) returns summary + action_needed + compact decisions. "full" returns complete decision objects.`),
 			),
+			mcplib.WithBoolean("suggest",
+				mcplib.Description("Optional: when agent_id matches no agent and nothing is found, return similar existing agent_ids in suggestions instead of a silent empty result."),
+			),
 		),
 		s.handleCheck,
 	)
 
 	// akashi_trace — record a decision to the black box.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_trace",
 			mcplib.WithDescription(`Record a decision to the black box so there is proof of why it was made.
 
@@ -176,6 +185,16 @@ SKIP: formatting, typo fixes, running tests, reading code, asking questions.`),
 				mcplib.Min(0),
 				mcplib.Max(1),
 			),
+			mcplib.WithNumber("confidence_low",
+				mcplib.Description("Optional lower bound of your confidence interval (0.0-1.0). Must not exceed confidence. Provide together with confidence_high."),
+				mcplib.Min(0),
+				mcplib.Max(1),
+			),
+			mcplib.WithNumber("confidence_high",
+				mcplib.Description("Optional upper bound of your confidence interval (0.0-1.0). Must not be below confidence. Provide together with confidence_low."),
+				mcplib.Min(0),
+				mcplib.Max(1),
+			),
 			mcplib.WithString("reasoning",
 				mcplib.Description("Your chain of thought. Why this choice? What trade-offs did you consider?"),
 			),
@@ -229,7 +248,7 @@ SKIP: formatting, typo fixes, running tests, reading code, asking questions.`),
 	)
 
 	// akashi_query — structured or semantic query over the decision audit trail.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_query",
 			mcplib.WithDescription(`Query the decision audit trail with structured filters or free-text search.
 
@@ -257,11 +276,15 @@ EXAMPLES:
 			mcplib.WithIdempotentHintAnnotation(true),
 			mcplib.WithOpenWorldHintAnnotation(false),
 			mcplib.WithString("query",
-				mcplib.Description("Natural language search query. When provided, performs semantic/text search and ignores structured filters except confidence_min and project. When omitted, uses structured filter mode."),
+				mcplib.Description("Natural language search query. When provided, performs semantic/text search and ignores structured filters except confidence_min, project, from, and to. When omitted, uses structured filter mode."),
 			),
 			mcplib.WithString("decision_type",
 				mcplib.Description("Filter by decision type (any string, e.g. architecture, security, code_review). Case-insensitive. Ignored when query is provided."),
 			),
+			mcplib.WithArray("decision_types",
+				mcplib.Description("Filter by any of several decision types (OR). Combined with decision_type when both are given. Case-insensitive. Ignored when query is provided."),
+				mcplib.WithStringItems(),
+			),
 			mcplib.WithString("agent_id",
 				mcplib.Description("Filter by agent ID — whose decisions to look at. Ignored when query is provided."),
 			),
@@ -276,6 +299,16 @@ EXAMPLES:
 			mcplib.WithString("session_id",
 				mcplib.Description("Filter by session UUID. Ignored when query is provided."),
 			),
+			mcplib.WithString("from",
+				mcplib.Description("Only decisions made at or after this RFC 3339 timestamp (e.g. 2026-01-01T00:00:00Z). Applied in both modes."),
+			),
+			mcplib.WithString("to",
+				mcplib.Description("Only decisions made at or before this RFC 3339 timestamp. Applied in both modes."),
+			),
+			mcplib.WithNumber("recency_half_life_days",
+				mcplib.Description("Search ranking halves a decision's relevance at this age in days (default 90). 0 ranks without regard to age. Only applies when query is provided."),
+				mcplib.Min(0),
+			),
 			mcplib.WithString("tool",
 				mcplib.Description("Filter by tool name (e.g. 'claude-code', 'cursor'). Ignored when query is provided."),
 			),
@@ -310,7 +343,7 @@ EXAMPLES:
 	)
 
 	// akashi_stats — aggregate statistics about the decision trail.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_stats",
 			mcplib.WithDescription(`Get aggregate statistics about the decision audit trail.
 
@@ -325,16 +358,28 @@ ratio false_positive/(resolved+false_positive). An elevated rate signals
 LLM validator drift.
 
 Useful at the start of a session for situational awareness, or when
-reporting on the state of decision tracking.`),
+reporting on the state of decision tracking.
+
+Pass group_by to get a compact breakdown instead: one bucket per
+decision_type, agent_id, or UTC day, each with count, avg_confidence, and
+avg_completeness. Combine with since, e.g. group_by="day" and a since one
+week ago for daily decision counts.`),
 			mcplib.WithReadOnlyHintAnnotation(true),
 			mcplib.WithIdempotentHintAnnotation(true),
 			mcplib.WithOpenWorldHintAnnotation(false),
+			mcplib.WithString("group_by",
+				mcplib.Description(`Return a breakdown of current decisions grouped by "decision_type", "agent_id", or "day" (UTC) instead of the aggregate report.`),
+				mcplib.Enum(storage.StatsGroupByDecisionType, storage.StatsGroupByAgent, storage.StatsGroupByDay),
+			),
+			mcplib.WithString("since",
+				mcplib.Description("Only count decisions made at or after this RFC 3339 timestamp. Applies to both the aggregate report and the breakdown."),
+			),
 		),
 		s.handleStats,
 	)
 
 	// akashi_conflicts — list and filter conflicts.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_conflicts",
 			mcplib.WithDescription(`List detected conflicts between decisions.
 
@@ -385,7 +430,7 @@ Defaults to groups with open conflicts; pass status="all" to see everything.`),
 	)
 
 	// akashi_assess — record explicit outcome feedback for a prior decision.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_assess",
 			mcplib.WithDescription(`Record explicit outcome feedback for a prior decision.
 
@@ -420,7 +465,7 @@ After testing, the coder calls akashi_assess to mark it correct:
 	)
 
 	// akashi_resolve — resolve or mark a conflict as false positive.
-	s.mcpServer.AddTool(
+	s.addTool(
 		mcplib.NewTool("akashi_resolve",
 			mcplib.WithDescription(`Resolve a conflict or mark it as a false positive.
 
@@ -469,6 +514,134 @@ EXAMPLE: After a user says "go with approach A", call akashi_resolve with
 		),
 		s.handleResolve,
 	)
+
+	// akashi_supersede — revise a prior decision with a new outcome.
+	s.addTool(
+		mcplib.NewTool("akashi_supersede",
+			mcplib.WithDescription(`Revise a decision you recorded earlier, replacing it with a new outcome.
+
+WHEN TO USE: When a prior decision has been overtaken — new evidence, a
+changed requirement, or a mistake — and the record should show that the
+new outcome replaces the old one rather than contradicting it.
+
+The original decision is closed (it stays in the audit trail, marked as
+superseded) and a new decision is recorded with the same decision_type,
+linked to the original via supersedes_id. Metadata, alternatives,
+precedent_ref, and context carry over from the original; reasoning does
+not, so restate it if it still applies. Open conflicts involving the
+original are auto-resolved; if the revision still conflicts, a new
+conflict is detected.
+
+You can only supersede your own decisions unless you are an admin. A
+decision that has already been superseded cannot be superseded again —
+supersede its latest revision instead.
+
+WHAT YOU GET BACK:
+- decision_id: the new decision
+- superseded_id: the original decision, now closed
+- outcome_flipped: whether the new outcome reverses the original
+
+EXAMPLE: After discovering Redis is unavailable in production, call
+akashi_supersede with original_decision_id="<uuid>",
+outcome="chose in-process LRU cache for session data",
+reasoning="Redis is not provisioned in the production cluster".`),
+			mcplib.WithDestructiveHintAnnotation(false),
+			mcplib.WithIdempotentHintAnnotation(false),
+			mcplib.WithOpenWorldHintAnnotation(false),
+			mcplib.WithString("original_decision_id",
+				mcplib.Description("UUID of the decision to supersede"),
+				mcplib.Required(),
+			),
+			mcplib.WithString("outcome",
+				mcplib.Description("The new outcome, stated as a fact"),
+				mcplib.Required(),
+			),
+			mcplib.WithNumber("confidence",
+				mcplib.Description("How certain you are in the new outcome (0.0-1.0)"),
+				mcplib.Min(0),
+				mcplib.Max(1),
+				mcplib.Required(),
+			),
+			mcplib.WithString("reasoning",
+				mcplib.Description("Optional: why the original decision is being revised"),
+			),
+		),
+		s.handleSupersede,
+	)
+
+	// akashi_why — explain a decision's rationale from its stored record.
+	s.addTool(
+		mcplib.NewTool("akashi_why",
+			mcplib.WithDescription(`Explain why a decision was made, from what was recorded with it.
+
+WHEN TO USE: When a precedent from akashi_check or akashi_query matters to
+your work and you need to understand it before following or overriding
+it — what was chosen, what was passed over, what evidence backed it, and
+whether it still stands.
+
+WHAT YOU GET BACK:
+- outcome, confidence, reasoning: what was chosen and why
+- runner_up: the first alternative recorded as rejected, with its
+  rejection_reason (null when none were recorded)
+- top_evidence: the most relevant evidence items, highest relevance first
+- superseded / superseded_by / current_revision_id: whether the decision
+  was later revised, and which revision is now current
+
+You can only explain decisions by agents you have access to.
+
+EXAMPLE: Before overriding a precedent, call akashi_why with
+decision_id="<uuid>" to see what it rejected and the evidence behind it.`),
+			mcplib.WithReadOnlyHintAnnotation(true),
+			mcplib.WithDestructiveHintAnnotation(false),
+			mcplib.WithIdempotentHintAnnotation(true),
+			mcplib.WithOpenWorldHintAnnotation(false),
+			mcplib.WithString("decision_id",
+				mcplib.Description("UUID of the decision to explain"),
+				mcplib.Required(),
+			),
+			mcplib.WithNumber("evidence_limit",
+				mcplib.Description("Maximum evidence items to return, highest relevance first (default 3, max 20)"),
+				mcplib.Min(1),
+				mcplib.Max(20),
+			),
+		),
+		s.handleWhy,
+	)
+
+	// akashi_timeline — one agent's recent decision arc.
+	s.addTool(
+		mcplib.NewTool("akashi_timeline",
+			mcplib.WithDescription(`Show an agent's recent decisions in order, oldest to newest.
+
+WHEN TO USE: When resuming work and you want a quick narrative of what you
+(or another agent) recently decided. Unlike akashi_query, which returns only
+decisions that still stand, the timeline includes revisions that were later
+superseded, so you can see how thinking evolved.
+
+WHAT YOU GET BACK: for each decision, its type, outcome, confidence,
+was_superseded (true if a later revision or retraction replaced it), and
+conflict_count (how many detected conflicts it is party to).
+
+Defaults to your own decisions; pass agent_id to see another agent you have
+access to.`),
+			mcplib.WithReadOnlyHintAnnotation(true),
+			mcplib.WithDestructiveHintAnnotation(false),
+			mcplib.WithIdempotentHintAnnotation(true),
+			mcplib.WithOpenWorldHintAnnotation(false),
+			mcplib.WithString("agent_id",
+				mcplib.Description("Agent whose timeline to show (defaults to you)"),
+			),
+			mcplib.WithString("decision_type",
+				mcplib.Description("Only include decisions of this type"),
+			),
+			mcplib.WithNumber("limit",
+				mcplib.Description("Maximum decisions to return, most recent first before ordering (default 20, max 100)"),
+				mcplib.Min(1),
+				mcplib.Max(100),
+			),
+		),
+		s.handleTimeline,
+	)
 }
 
 // resolveProjectFilter returns the project filter to apply to a read operation.
@@ -538,6 +711,7 @@ func (s *Server) handleCheck(ctx context.Context, request mcplib.CallToolRequest
 		DecisionType: decisionType,
 		Query:        query,
 		AgentID:      agentID,
+		Namespace:    ctxutil.NamespaceFromContext(ctx),
 		Limit:        limit,
 	}
 	if project := s.resolveProjectFilter(ctx, request); project != nil {
@@ -562,6 +736,19 @@ func (s *Server) handleCheck(ctx context.Context, request mcplib.CallToolRequest
 		resp.HasPrecedent = len(resp.Decisions) > 0
 	}
 
+	if request.GetBool("suggest", false) && len(resp.Decisions) == 0 && agentID != "" {
+		suggestions, sErr := s.decisionSvc.SuggestAgentIDs(ctx, orgID, []string{agentID})
+		if sErr == nil {
+			suggestions, sErr = authz.FilterAgentIDs(ctx, s.db, claims, suggestions, s.grantCache)
+		}
+		if sErr != nil {
+			// Suggestions are only a hint; the check itself succeeded.
+			s.logger.Warn("akashi_check: agent_id suggestion lookup failed", "error", sErr)
+		} else {
+			resp.Suggestions = suggestions
+		}
+	}
+
 	// Populate consensus scores, outcome signals, and assessment summaries for decisions.
 	if len(resp.Decisions) > 0 {
 		ids := make([]uuid.UUID, len(resp.Decisions))
@@ -634,6 +821,9 @@ func (s *Server) handleCheck(ctx context.Context, request mcplib.CallToolRequest
 	if len(resp.PriorResolutions) > 0 {
 		summary += fmt.Sprintf(" %d prior conflict(s) for this decision type were formally resolved; winning approach(es) listed in prior_resolutions.", len(resp.PriorResolutions))
 	}
+	if len(resp.Suggestions) > 0 {
+		summary += suggestionNote(resp.Suggestions)
+	}
 
 	result := map[string]any{
 		"has_precedent":     resp.HasPrecedent,
@@ -648,6 +838,9 @@ func (s *Server) handleCheck(ctx context.Context, request mcplib.CallToolRequest
 	if resp.ConflictsUnavailable {
 		result["conflicts_unavailable"] = true
 	}
+	if len(resp.Suggestions) > 0 {
+		result["suggestions"] = resp.Suggestions
+	}
 
 	// precedent_ref_hint: the UUID of the best candidate for precedent_ref in the
 	// subsequent akashi_trace call. Emitted as a bare UUID so agents can copy it
@@ -753,6 +946,21 @@ func (s *Server) handleTrace(ctx context.Context, request mcplib.CallToolRequest
 		return errorResult(fmt.Sprintf("reasoning exceeds maximum length of %d bytes", model.MaxReasoningLen)), nil
 	}
 
+	// Optional confidence interval. Presence is checked on the raw arguments
+	// because 0 is a legitimate lower bound.
+	var confidenceLow, confidenceHigh *float32
+	if _, ok := request.GetArguments()["confidence_low"]; ok {
+		v := float32(request.GetFloat("confidence_low", 0))
+		confidenceLow = &v
+	}
+	if _, ok := request.GetArguments()["confidence_high"]; ok {
+		v := float32(request.GetFloat("confidence_high", 0))
+		confidenceHigh = &v
+	}
+	if err := model.ValidateConfidenceInterval(confidence, confidenceLow, confidenceHigh); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
 	// Non-admin callers can only trace for their own agent_id.
 	if claims != nil && !model.RoleAtLeast(claims.Role, model.RoleAdmin) && agentID != claims.AgentID {
 		return errorResult("agents can only record decisions for their own agent_id"), nil
@@ -822,6 +1030,11 @@ func (s *Server) handleTrace(ctx context.Context, request mcplib.CallToolRequest
 		}
 	}
 
+	// Same org write policy as POST /v1/trace.
+	if err := s.decisionSvc.CheckTracePolicy(ctx, orgID, agentID, decisionType, len(alternatives)); err != nil {
+		return errorResult(err.Error()), nil
+	}
+
 	// Parse precedent_ref UUID if provided. Invalid format is logged and ignored —
 	// a trace without a precedent link is better than a failed trace.
 	var precedentRef *uuid.UUID
@@ -1165,17 +1378,20 @@ func (s *Server) handleTrace(ctx context.Context, request mcplib.CallToolRequest
 		SessionID:       sessionID,
 		AgentContext:    agentContext,
 		APIKeyID:        apiKeyID,
+		Namespace:       ctxutil.NamespaceFromContext(ctx),
 		AuditMeta:       auditMeta,
 		PrecedentRef:    precedentRef,
 		PrecedentReason: precedentReason,
 		SupersedesID:    supersedesID,
 		Decision: model.TraceDecision{
-			DecisionType: decisionType,
-			Outcome:      outcome,
-			Confidence:   confidence,
-			Reasoning:    reasoningPtr,
-			Alternatives: alternatives,
-			Evidence:     evidence,
+			DecisionType:   decisionType,
+			Outcome:        outcome,
+			Confidence:     confidence,
+			ConfidenceLow:  confidenceLow,
+			ConfidenceHigh: confidenceHigh,
+			Reasoning:      reasoningPtr,
+			Alternatives:   alternatives,
+			Evidence:       evidence,
 		},
 	})
 	if err != nil {
@@ -1186,7 +1402,7 @@ func (s *Server) handleTrace(ctx context.Context, request mcplib.CallToolRequest
 	}
 
 	// Compute completeness score and missing-field hints for agent feedback.
-	completenessScore := quality.Score(model.TraceDecision{
+	completenessScore := s.decisionSvc.CompletenessScore(model.TraceDecision{
 		DecisionType: decisionType,
 		Outcome:      outcome,
 		Confidence:   confidence,
@@ -1550,20 +1766,53 @@ func (s *Server) handleQuery(ctx context.Context, request mcplib.CallToolRequest
 	format := request.GetString("format", "concise")
 
 	// Build shared filters (applied to both modes; some are ignored in semantic mode).
-	filters := model.QueryFilters{}
+	ns := ctxutil.NamespaceFromContext(ctx)
+	filters := model.QueryFilters{Namespace: &ns}
 	if confMin := float32(request.GetFloat("confidence_min", 0)); confMin > 0 {
 		filters.ConfidenceMin = &confMin
 	}
 	filters.Project = s.resolveProjectFilter(ctx, request)
+	for _, bound := range []string{"from", "to"} {
+		raw := request.GetString(bound, "")
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return errorResult(fmt.Sprintf("%s must be an RFC 3339 timestamp", bound)), nil
+		}
+		if filters.TimeRange == nil {
+			filters.TimeRange = &model.TimeRange{}
+		}
+		if bound == "from" {
+			filters.TimeRange.From = &t
+		} else {
+			filters.TimeRange.To = &t
+		}
+	}
+	if _, ok := request.GetArguments()["recency_half_life_days"]; ok {
+		halfLife := request.GetFloat("recency_half_life_days", model.DefaultRecencyHalfLifeDays)
+		if halfLife < 0 {
+			return errorResult("recency_half_life_days must be >= 0"), nil
+		}
+		filters.RecencyHalfLifeDays = &halfLife
+	}
 
 	if query != "" {
-		// Semantic/text search path. Structured filters other than confidence_min
-		// and project are intentionally ignored — the query drives discovery.
-		results, err := s.decisionSvc.Search(ctx, orgID, query, true, filters, limit)
+		// Semantic/text search path. Structured filters other than confidence_min,
+		// project, and the time window are intentionally ignored — the query
+		// drives discovery.
+		// Ranking is restricted to the caller's granted agents up front.
+		anyAgent, err := authz.ScopeFiltersToGranted(ctx, s.db, claims, &filters, s.grantCache)
 		if err != nil {
-			return errorResult(fmt.Sprintf("search failed: %v", err)), nil
+			return errorResult(fmt.Sprintf("authorization check failed: %v", err)), nil
 		}
-		if claims != nil {
+		results := []model.SearchResult{}
+		if anyAgent {
+			results, err = s.decisionSvc.Search(ctx, orgID, query, true, filters, limit)
+			if err != nil {
+				return errorResult(fmt.Sprintf("search failed: %v", err)), nil
+			}
 			results, err = authz.FilterSearchResults(ctx, s.db, claims, results, s.grantCache)
 			if err != nil {
 				return errorResult(fmt.Sprintf("authorization check failed: %v", err)), nil
@@ -1594,6 +1843,11 @@ func (s *Server) handleQuery(ctx context.Context, request mcplib.CallToolRequest
 	if dt := strings.ToLower(strings.TrimSpace(request.GetString("decision_type", ""))); dt != "" {
 		filters.DecisionType = &dt
 	}
+	for _, raw := range request.GetStringSlice("decision_types", nil) {
+		if dt := strings.ToLower(strings.TrimSpace(raw)); dt != "" {
+			filters.DecisionTypes = append(filters.DecisionTypes, dt)
+		}
+	}
 	if outcome := request.GetString("outcome", ""); outcome != "" {
 		filters.Outcome = &outcome
 	}
@@ -1945,6 +2199,9 @@ func (s *Server) resolveSingleConflict(
 		if errors.Is(err, storage.ErrWinningDecisionNotInConflict) {
 			return errorResult("winning_decision_id must be one of the two decisions in this conflict"), nil
 		}
+		if errors.Is(err, storage.ErrConflictStatusUnchanged) {
+			return errorResult(fmt.Sprintf("conflict is already %s", status)), nil
+		}
 		return errorResult(fmt.Sprintf("failed to update conflict: %v", err)), nil
 	}
 
@@ -2088,11 +2345,28 @@ func (s *Server) resolveGroup(
 	}, nil
 }
 
-func (s *Server) handleStats(ctx context.Context, _ mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+// maxStatsBuckets caps the akashi_stats breakdown so a wide group_by (many
+// agents, or day over a long window) stays small enough for an LLM context.
+const maxStatsBuckets = 100
+
+func (s *Server) handleStats(ctx context.Context, request mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
 	orgID := ctxutil.OrgIDFromContext(ctx)
 
+	var since *time.Time
+	if raw := request.GetString("since", ""); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return errorResult("since must be an RFC 3339 timestamp"), nil
+		}
+		since = &t
+	}
+
+	if groupBy := request.GetString("group_by", ""); groupBy != "" {
+		return s.handleStatsBreakdown(ctx, orgID, groupBy, since)
+	}
+
 	svc := tracehealth.New(s.db)
-	metrics, err := svc.Compute(ctx, orgID, nil, nil)
+	metrics, err := svc.Compute(ctx, orgID, since, nil)
 	if err != nil {
 		return errorResult(fmt.Sprintf("failed to compute trace health: %v", err)), nil
 	}
@@ -2119,3 +2393,48 @@ func (s *Server) handleStats(ctx context.Context, _ mcplib.CallToolRequest) (*mc
 		},
 	}, nil
 }
+
+// handleStatsBreakdown returns akashi_stats grouped by decision_type,
+// agent_id, or day. Averages are rounded to three decimals so repeated calls
+// over unchanged data return identical text.
+func (s *Server) handleStatsBreakdown(ctx context.Context, orgID uuid.UUID, groupBy string, since *time.Time) (*mcplib.CallToolResult, error) {
+	switch groupBy {
+	case storage.StatsGroupByDecisionType, storage.StatsGroupByAgent, storage.StatsGroupByDay:
+	default:
+		return errorResult(`group_by must be "decision_type", "agent_id", or "day"`), nil
+	}
+
+	buckets, err := s.db.AggregateDecisionStats(ctx, orgID, groupBy, since, maxStatsBuckets+1)
+	if err != nil {
+		return errorResult(fmt.Sprintf("failed to aggregate decision stats: %v", err)), nil
+	}
+	truncated := len(buckets) > maxStatsBuckets
+	if truncated {
+		buckets = buckets[:maxStatsBuckets]
+	}
+	for i := range buckets {
+		buckets[i].AvgConfidence = math.Round(buckets[i].AvgConfidence*1000) / 1000
+		buckets[i].AvgCompleteness = math.Round(buckets[i].AvgCompleteness*1000) / 1000
+	}
+	if buckets == nil {
+		buckets = []storage.DecisionStatsBucket{}
+	}
+
+	resp := map[string]any{
+		"group_by": groupBy,
+		"buckets":  buckets,
+	}
+	if since != nil {
+		resp["since"] = since.UTC().Format(time.RFC3339)
+	}
+	if truncated {
+		resp["truncated"] = true
+	}
+	resultData, _ := json.Marshal(resp)
+
+	return &mcplib.CallToolResult{
+		Content: []mcplib.Content{
+			mcplib.TextContent{Type: "text", Text: string(resultData)},
+		},
+	}, nil
+}
@@ -1303,6 +1303,79 @@ func TestHandleStats_NilClaimsStillWorks(t *testing.T) {
 	assert.NotNil(t, resp.TraceHealth)
 }
 
+func TestHandleStats_GroupByDay(t *testing.T) {
+	// A fresh org isolates the buckets from decisions other tests create.
+	orgID := uuid.New()
+	ctx := ctxutil.WithClaims(context.Background(), &auth.Claims{
+		AgentID: testAdminID, OrgID: orgID, Role: model.RoleAdmin,
+	})
+	agentID := "stats-day-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID, OrgID: orgID})
+	require.NoError(t, err)
+
+	day := func(n int) time.Time {
+		return time.Date(2026, 3, n, 10, 0, 0, 0, time.UTC)
+	}
+	created := []struct {
+		at         time.Time
+		confidence float32
+	}{
+		{day(1), 0.6},
+		{day(1).Add(8 * time.Hour), 0.8},
+		{day(2), 0.5},
+		{day(4), 0.9},
+	}
+	for i, c := range created {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, OrgID: orgID,
+			DecisionType: "stats_test", Outcome: fmt.Sprintf("stats decision %d", i),
+			Confidence: c.confidence, Metadata: map[string]any{},
+			ValidFrom: c.at, CreatedAt: c.at,
+		})
+		require.NoError(t, err)
+	}
+
+	call := func(args map[string]any) *mcplib.CallToolResult {
+		t.Helper()
+		result, err := testServer.handleStats(ctx, mcplib.CallToolRequest{
+			Params: mcplib.CallToolParams{Name: "akashi_stats", Arguments: args},
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := call(map[string]any{"group_by": "day"})
+	require.False(t, result.IsError, "grouped stats should succeed: %s", parseToolText(t, result))
+	var resp struct {
+		GroupBy string                        `json:"group_by"`
+		Buckets []storage.DecisionStatsBucket `json:"buckets"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.Equal(t, "day", resp.GroupBy)
+	require.Len(t, resp.Buckets, 3, "one bucket per distinct day")
+	assert.Equal(t, "2026-03-04", resp.Buckets[0].Key, "days are newest first")
+	assert.Equal(t, 1, resp.Buckets[0].Count)
+	assert.Equal(t, "2026-03-02", resp.Buckets[1].Key)
+	assert.Equal(t, "2026-03-01", resp.Buckets[2].Key)
+	assert.Equal(t, 2, resp.Buckets[2].Count)
+	assert.InDelta(t, 0.7, resp.Buckets[2].AvgConfidence, 0.001)
+
+	result = call(map[string]any{"group_by": "day", "since": "2026-03-02T00:00:00Z"})
+	require.False(t, result.IsError)
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.Len(t, resp.Buckets, 2, "since excludes earlier days")
+
+	result = call(map[string]any{"group_by": "agent_id"})
+	require.False(t, result.IsError)
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	require.Len(t, resp.Buckets, 1)
+	assert.Equal(t, agentID, resp.Buckets[0].Key)
+	assert.Equal(t, 4, resp.Buckets[0].Count)
+
+	assert.True(t, call(map[string]any{"group_by": "week"}).IsError)
+	assert.True(t, call(map[string]any{"since": "yesterday"}).IsError)
+}
+
 // ---------- resolveProjectFilter tests ----------
 
 func TestResolveProjectFilter(t *testing.T) {
@@ -1599,6 +1672,80 @@ func TestHandleTrace_InvalidAlternativesJSON(t *testing.T) {
 	require.False(t, result.IsError, "invalid alternatives JSON should be ignored: %s", parseToolText(t, result))
 }
 
+// setOrgSettings applies mutate to the default org's settings and restores
+// the previous settings when the test ends.
+func setOrgSettings(t *testing.T, mutate func(*model.OrgSettingsData)) {
+	t.Helper()
+	ctx := context.Background()
+	prev, err := testDB.GetOrgSettings(ctx, uuid.Nil)
+	require.NoError(t, err)
+	audit := storage.MutationAuditEntry{OrgID: uuid.Nil, ActorAgentID: testAdminID, Operation: "org_settings_updated", ResourceType: "org_settings"}
+	t.Cleanup(func() {
+		_ = testDB.UpsertOrgSettingsWithAudit(ctx, uuid.Nil, prev.Settings, testAdminID, audit)
+	})
+
+	settings := prev.Settings
+	mutate(&settings)
+	require.NoError(t, testDB.UpsertOrgSettingsWithAudit(ctx, uuid.Nil, settings, testAdminID, audit))
+}
+
+func TestHandleTrace_DecisionQuota(t *testing.T) {
+	ctx := adminCtx()
+	agentID := "trace-quota-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(ctx, uuid.Nil, agentID, model.RoleAdmin, nil)
+	setOrgSettings(t, func(s *model.OrgSettingsData) {
+		s.DecisionQuota = &model.DecisionQuotaPolicy{
+			AgentOverrides: map[string]model.QuotaLimits{agentID: {Daily: 1}},
+		}
+	})
+
+	trace := func(outcome string) *mcplib.CallToolResult {
+		result, err := testServer.handleTrace(ctx, traceRequest(map[string]any{
+			"agent_id":      agentID,
+			"decision_type": "quota_test",
+			"outcome":       outcome,
+			"confidence":    0.5,
+		}))
+		require.NoError(t, err)
+		return result
+	}
+
+	result := trace("within quota")
+	require.False(t, result.IsError, "first trace is within quota: %s", parseToolText(t, result))
+
+	result = trace("over quota")
+	require.True(t, result.IsError, "second trace should exceed the daily quota")
+	assert.Contains(t, parseToolText(t, result), "agent daily decision quota exceeded")
+}
+
+func TestHandleTrace_MinAlternatives(t *testing.T) {
+	ctx := adminCtx()
+	agentID := "trace-minalt-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(ctx, uuid.Nil, agentID, model.RoleAdmin, nil)
+	setOrgSettings(t, func(s *model.OrgSettingsData) {
+		s.MinAlternatives = model.MinAlternativesPolicy{"min_alt_test": 2}
+	})
+
+	args := func(alternatives string) map[string]any {
+		return map[string]any{
+			"agent_id":      agentID,
+			"decision_type": "min_alt_test",
+			"outcome":       "chose PostgreSQL",
+			"confidence":    0.8,
+			"alternatives":  alternatives,
+		}
+	}
+
+	result, err := testServer.handleTrace(ctx, traceRequest(args(`[{"label":"MySQL","rejection_reason":"weaker JSON support"}]`)))
+	require.NoError(t, err)
+	require.True(t, result.IsError, "one alternative should be rejected")
+	assert.Contains(t, parseToolText(t, result), "requires at least 2 alternatives")
+
+	result, err = testServer.handleTrace(ctx, traceRequest(args(`[{"label":"MySQL","rejection_reason":"weaker JSON support"},{"label":"SQLite","rejection_reason":"single writer"}]`)))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "two alternatives meet the policy: %s", parseToolText(t, result))
+}
+
 func TestHandleTrace_InvalidSourceURI(t *testing.T) {
 	ctx := adminCtx()
 	agentID := "trace-uri-" + uuid.New().String()[:8]
@@ -4135,3 +4282,381 @@ func TestDeriveAgentID_PreservesDotsAndUnderscores(t *testing.T) {
 	assert.True(t, enriched)
 	assert.Equal(t, "my_tool.v2", derived)
 }
+
+// ---------- handleSupersede tests ----------
+
+func supersedeRequest(args map[string]any) mcplib.CallToolRequest {
+	return mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_supersede",
+			Arguments: args,
+		},
+	}
+}
+
+func TestHandleSupersede(t *testing.T) {
+	ctx := adminCtx()
+	agentID := "supersede-" + uuid.New().String()[:8]
+	originalID := mustTrace(t, agentID, "architecture", "chose redis for session cache", 0.7)
+
+	result, err := testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": originalID,
+		"outcome":              "chose in-process LRU for session cache",
+		"confidence":           0.8,
+		"reasoning":            "redis is not provisioned in production",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "supersede should succeed: %s", parseToolText(t, result))
+
+	var resp struct {
+		DecisionID   uuid.UUID `json:"decision_id"`
+		SupersededID uuid.UUID `json:"superseded_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.Equal(t, originalID, resp.SupersededID.String())
+
+	revised, err := testDB.GetDecision(context.Background(), uuid.Nil, resp.DecisionID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, agentID, revised.AgentID)
+	assert.Equal(t, "chose in-process LRU for session cache", revised.Outcome)
+	require.NotNil(t, revised.SupersedesID)
+	assert.Equal(t, originalID, revised.SupersedesID.String())
+
+	// The original is now closed and cannot be superseded again.
+	result, err = testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": originalID,
+		"outcome":              "chose memcached",
+		"confidence":           0.5,
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, parseToolText(t, result), "already been superseded")
+}
+
+func TestHandleSupersede_CarriesOriginalFields(t *testing.T) {
+	ctx := adminCtx()
+	agentID := "supersede-carry-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(ctx, uuid.Nil, agentID, model.RoleAdmin, nil)
+	precedentID, err := uuid.Parse(mustTrace(t, agentID, "architecture", "chose postgres", 0.6))
+	require.NoError(t, err)
+
+	low, high := float32(0.5), float32(0.9)
+	rejected := "no persistence"
+	traced, err := testSvc.Trace(ctx, uuid.Nil, decisions.TraceInput{
+		AgentID:      agentID,
+		Metadata:     map[string]any{"ticket": "ARCH-7"},
+		PrecedentRef: &precedentID,
+		AgentContext: map[string]any{"client": map[string]any{"model": "test-model", "project": "carry-project"}},
+		Decision: model.TraceDecision{
+			DecisionType: "architecture", Outcome: "chose redis for sessions", Confidence: 0.7,
+			ConfidenceLow: &low, ConfidenceHigh: &high,
+			Alternatives: []model.TraceAlternative{{Label: "redis"}, {Label: "memcached", RejectionReason: &rejected}},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": traced.DecisionID.String(),
+		"outcome":              "chose in-process LRU for sessions",
+		"confidence":           0.8,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "supersede should succeed: %s", parseToolText(t, result))
+	var resp struct {
+		DecisionID uuid.UUID `json:"decision_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+
+	revised, err := testDB.GetDecision(context.Background(), uuid.Nil, resp.DecisionID, storage.GetDecisionOpts{IncludeAlts: true})
+	require.NoError(t, err)
+	assert.Equal(t, "ARCH-7", revised.Metadata["ticket"])
+	require.NotNil(t, revised.PrecedentRef)
+	assert.Equal(t, precedentID, *revised.PrecedentRef)
+	require.NotNil(t, revised.Model)
+	assert.Equal(t, "test-model", *revised.Model)
+	require.NotNil(t, revised.Project)
+	assert.Equal(t, "carry-project", *revised.Project)
+	require.NotNil(t, revised.ConfidenceLow)
+	assert.Equal(t, low, *revised.ConfidenceLow)
+	require.NotNil(t, revised.ConfidenceHigh)
+	assert.Equal(t, high, *revised.ConfidenceHigh)
+	assert.Len(t, revised.Alternatives, 2)
+
+	// Bounds that no longer bracket the new confidence are dropped.
+	result, err = testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": resp.DecisionID.String(),
+		"outcome":              "chose a CDN edge cache for sessions",
+		"confidence":           0.95,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "supersede should succeed: %s", parseToolText(t, result))
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	revised, err = testDB.GetDecision(context.Background(), uuid.Nil, resp.DecisionID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Nil(t, revised.ConfidenceLow)
+	assert.Nil(t, revised.ConfidenceHigh)
+}
+
+func TestHandleSupersede_NonOwnerRejected(t *testing.T) {
+	originalID := mustTrace(t, "supersede-owner-"+uuid.New().String()[:8], "architecture", "chose postgres", 0.7)
+
+	agentID := "supersede-other-" + uuid.New().String()[:8]
+	_, err := testDB.CreateAgent(context.Background(), model.Agent{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Name:    agentID,
+		Role:    model.RoleAgent,
+	})
+	require.NoError(t, err)
+	ctx := ctxutil.WithClaims(context.Background(), &auth.Claims{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Role:    model.RoleAgent,
+	})
+
+	result, err := testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": originalID,
+		"outcome":              "chose mysql",
+		"confidence":           0.6,
+	}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, parseToolText(t, result), "only supersede their own decisions")
+}
+
+func TestHandleSupersede_Validation(t *testing.T) {
+	ctx := adminCtx()
+	cases := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing id", map[string]any{"outcome": "x", "confidence": 0.5}, "original_decision_id is required"},
+		{"bad id", map[string]any{"original_decision_id": "nope", "outcome": "x", "confidence": 0.5}, "valid UUID"},
+		{"missing outcome", map[string]any{"original_decision_id": uuid.NewString(), "confidence": 0.5}, "outcome is required"},
+		{"missing confidence", map[string]any{"original_decision_id": uuid.NewString(), "outcome": "x"}, "confidence is required"},
+		{"unknown decision", map[string]any{"original_decision_id": uuid.NewString(), "outcome": "x", "confidence": 0.5}, "not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := testServer.handleSupersede(ctx, supersedeRequest(tc.args))
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, parseToolText(t, result), tc.want)
+		})
+	}
+}
+
+// ---------- handleWhy tests ----------
+
+func whyRequest(args map[string]any) mcplib.CallToolRequest {
+	return mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_why",
+			Arguments: args,
+		},
+	}
+}
+
+func TestHandleWhy(t *testing.T) {
+	ctx := adminCtx()
+	agentID := "why-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(ctx, uuid.Nil, agentID, model.RoleAdmin, nil)
+
+	result, err := testServer.handleTrace(ctx, traceRequest(map[string]any{
+		"agent_id":      agentID,
+		"decision_type": "architecture",
+		"outcome":       "chose postgres for the event store",
+		"confidence":    0.8,
+		"alternatives":  `[{"label":"kafka","rejection_reason":"too much operational overhead"},{"label":"sqlite"}]`,
+		"evidence": `[{"source_type":"document","content":"low","relevance_score":0.2},` +
+			`{"source_type":"document","content":"high","relevance_score":0.9},` +
+			`{"source_type":"document","content":"unscored"}]`,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "trace should succeed: %s", parseToolText(t, result))
+	var traced struct {
+		DecisionID string `json:"decision_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &traced))
+
+	result, err = testServer.handleWhy(ctx, whyRequest(map[string]any{
+		"decision_id":    traced.DecisionID,
+		"evidence_limit": 2,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "why should succeed: %s", parseToolText(t, result))
+
+	var resp whyResult
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.Equal(t, "chose postgres for the event store", resp.Outcome)
+	require.NotNil(t, resp.RunnerUp)
+	assert.Equal(t, "kafka", resp.RunnerUp.Label)
+	assert.Equal(t, 2, resp.AlternativesConsidered)
+	assert.Equal(t, 3, resp.EvidenceTotal)
+	require.Len(t, resp.TopEvidence, 2)
+	assert.Equal(t, "high", resp.TopEvidence[0].Content)
+	assert.Equal(t, "low", resp.TopEvidence[1].Content)
+	assert.False(t, resp.Superseded)
+
+	// Supersede it and ask again: the original now points at its replacement.
+	result, err = testServer.handleSupersede(ctx, supersedeRequest(map[string]any{
+		"original_decision_id": traced.DecisionID,
+		"outcome":              "chose kafka for the event store",
+		"confidence":           0.7,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "supersede should succeed: %s", parseToolText(t, result))
+	var revised struct {
+		DecisionID uuid.UUID `json:"decision_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &revised))
+
+	result, err = testServer.handleWhy(ctx, whyRequest(map[string]any{"decision_id": traced.DecisionID}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	resp = whyResult{}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.True(t, resp.Superseded)
+	require.NotNil(t, resp.SupersededBy)
+	assert.Equal(t, revised.DecisionID, *resp.SupersededBy)
+	require.NotNil(t, resp.CurrentRevisionID)
+	assert.Equal(t, revised.DecisionID, *resp.CurrentRevisionID)
+	assert.Equal(t, 2, resp.RevisionCount)
+}
+
+func TestHandleWhy_ReaderWithoutAccess(t *testing.T) {
+	decisionID := mustTrace(t, "why-target-"+uuid.New().String()[:8], "architecture", "chose grpc", 0.7)
+
+	readerAgent := "why-reader-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(adminCtx(), uuid.Nil, readerAgent, model.RoleAdmin, nil)
+	readerCtx := ctxutil.WithClaims(context.Background(), &auth.Claims{
+		AgentID: readerAgent,
+		OrgID:   uuid.Nil,
+		Role:    model.RoleReader,
+	})
+
+	// Indistinguishable from a decision that does not exist.
+	result, err := testServer.handleWhy(readerCtx, whyRequest(map[string]any{"decision_id": decisionID}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, parseToolText(t, result), "not found")
+}
+
+func TestHandleWhy_Validation(t *testing.T) {
+	ctx := adminCtx()
+	cases := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing id", map[string]any{}, "decision_id is required"},
+		{"bad id", map[string]any{"decision_id": "nope"}, "valid UUID"},
+		{"bad limit", map[string]any{"decision_id": uuid.NewString(), "evidence_limit": 50}, "evidence_limit"},
+		{"unknown decision", map[string]any{"decision_id": uuid.NewString()}, "not found"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := testServer.handleWhy(ctx, whyRequest(tc.args))
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			assert.Contains(t, parseToolText(t, result), tc.want)
+		})
+	}
+}
+
+// ---------- handleTimeline tests ----------
+
+func timelineRequest(args map[string]any) mcplib.CallToolRequest {
+	return mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_timeline",
+			Arguments: args,
+		},
+	}
+}
+
+func TestHandleTimeline(t *testing.T) {
+	agentID := "timeline-" + uuid.New().String()[:8]
+	first := mustTrace(t, agentID, "architecture", "chose rest for the public api", 0.6)
+	second := mustTrace(t, agentID, "security", "require mtls between services", 0.8)
+	third := mustTrace(t, agentID, "architecture", "chose postgres for the event store", 0.7)
+
+	agentCtx := ctxutil.WithClaims(context.Background(), &auth.Claims{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Role:    model.RoleAgent,
+	})
+	result, err := testServer.handleSupersede(agentCtx, supersedeRequest(map[string]any{
+		"original_decision_id": first,
+		"outcome":              "chose grpc for the public api",
+		"confidence":           0.7,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "supersede should succeed: %s", parseToolText(t, result))
+	var revised struct {
+		DecisionID string `json:"decision_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &revised))
+
+	// Defaults to the caller's own timeline, ordered oldest to newest.
+	result, err = testServer.handleTimeline(agentCtx, timelineRequest(map[string]any{}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "timeline should succeed: %s", parseToolText(t, result))
+	var resp timelineResult
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	assert.Equal(t, agentID, resp.AgentID)
+	require.Len(t, resp.Decisions, 4)
+
+	gotIDs := make([]string, len(resp.Decisions))
+	for i, d := range resp.Decisions {
+		gotIDs[i] = d.DecisionID.String()
+		if i > 0 {
+			assert.False(t, d.ValidFrom.Before(resp.Decisions[i-1].ValidFrom), "timeline must be ordered by valid_from")
+		}
+	}
+	assert.Equal(t, []string{first, second, third, revised.DecisionID}, gotIDs)
+	assert.True(t, resp.Decisions[0].WasSuperseded, "the superseded original must be flagged")
+	assert.Equal(t, "chose rest for the public api", resp.Decisions[0].Outcome)
+	for _, d := range resp.Decisions[1:] {
+		assert.False(t, d.WasSuperseded, "decision %s still stands", d.DecisionID)
+	}
+	assert.Equal(t, "security", resp.Decisions[1].Type)
+
+	// decision_type and limit narrow the window to the most recent matches.
+	result, err = testServer.handleTimeline(adminCtx(), timelineRequest(map[string]any{
+		"agent_id":      agentID,
+		"decision_type": "architecture",
+		"limit":         2,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError, "timeline should succeed: %s", parseToolText(t, result))
+	resp = timelineResult{}
+	require.NoError(t, json.Unmarshal([]byte(parseToolText(t, result)), &resp))
+	require.Len(t, resp.Decisions, 2)
+	assert.Equal(t, third, resp.Decisions[0].DecisionID.String())
+	assert.Equal(t, revised.DecisionID, resp.Decisions[1].DecisionID.String())
+}
+
+func TestHandleTimeline_NoAccess(t *testing.T) {
+	target := "timeline-target-" + uuid.New().String()[:8]
+	mustTrace(t, target, "architecture", "chose grpc", 0.7)
+
+	readerAgent := "timeline-reader-" + uuid.New().String()[:8]
+	_, _ = testSvc.ResolveOrCreateAgent(adminCtx(), uuid.Nil, readerAgent, model.RoleAdmin, nil)
+	readerCtx := ctxutil.WithClaims(context.Background(), &auth.Claims{
+		AgentID: readerAgent,
+		OrgID:   uuid.Nil,
+		Role:    model.RoleReader,
+	})
+
+	result, err := testServer.handleTimeline(readerCtx, timelineRequest(map[string]any{"agent_id": target}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, parseToolText(t, result), "no access")
+
+	result, err = testServer.handleTimeline(readerCtx, timelineRequest(map[string]any{"limit": 500}))
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, parseToolText(t, result), "limit must be between")
+}
@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	mcplib "github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+const (
+	defaultWhyEvidenceLimit = 3
+	maxWhyEvidenceLimit     = 20
+)
+
+// decisionExplainer is implemented by stores that can load a decision with
+// its alternatives, evidence, and revision chain (Postgres). Lite mode has no
+// revision chain, so akashi_why is refused there.
+type decisionExplainer interface {
+	GetDecision(ctx context.Context, orgID, id uuid.UUID, opts storage.GetDecisionOpts) (model.Decision, error)
+	GetDecisionRevisions(ctx context.Context, orgID, id uuid.UUID) ([]model.Decision, error)
+}
+
+func (s *Server) handleWhy(ctx context.Context, request mcplib.CallToolRequest) (*mcplib.CallToolResult, error) {
+	orgID := ctxutil.OrgIDFromContext(ctx)
+	claims := ctxutil.ClaimsFromContext(ctx)
+
+	if claims == nil {
+		return errorResult("authentication required"), nil
+	}
+
+	explainer, ok := s.db.(decisionExplainer)
+	if !ok {
+		return errorResult("akashi_why is not supported by this storage backend"), nil
+	}
+
+	idStr := request.GetString("decision_id", "")
+	if idStr == "" {
+		return errorResult("decision_id is required"), nil
+	}
+	decisionID, err := uuid.Parse(idStr)
+	if err != nil {
+		return errorResult("decision_id must be a valid UUID"), nil
+	}
+	evidenceLimit := request.GetInt("evidence_limit", defaultWhyEvidenceLimit)
+	if evidenceLimit < 1 || evidenceLimit > maxWhyEvidenceLimit {
+		return errorResult(fmt.Sprintf("evidence_limit must be between 1 and %d", maxWhyEvidenceLimit)), nil
+	}
+
+	notFound := errorResult(fmt.Sprintf("decision %s not found", decisionID))
+	d, err := explainer.GetDecision(ctx, orgID, decisionID, storage.GetDecisionOpts{
+		IncludeAlts:     true,
+		IncludeEvidence: true,
+		IncludeBlobs:    true,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return notFound, nil
+		}
+		return nil, fmt.Errorf("akashi_why: get decision: %w", err)
+	}
+	// An inaccessible decision is reported exactly like a missing one so the
+	// tool cannot be used to probe for decision IDs across grants.
+	allowed, err := authz.CanAccessAgent(ctx, s.db, claims, d.AgentID)
+	if err != nil {
+		return nil, fmt.Errorf("akashi_why: access check: %w", err)
+	}
+	if !allowed {
+		return notFound, nil
+	}
+
+	revisions, err := explainer.GetDecisionRevisions(ctx, orgID, decisionID)
+	if err != nil {
+		return nil, fmt.Errorf("akashi_why: get revisions: %w", err)
+	}
+	revisions, err = authz.FilterDecisions(ctx, s.db, claims, revisions, s.grantCache)
+	if err != nil {
+		return nil, fmt.Errorf("akashi_why: filter revisions: %w", err)
+	}
+
+	resultData, _ := json.MarshalIndent(explainDecision(d, revisions, evidenceLimit), "", "  ")
+
+	return &mcplib.CallToolResult{
+		Content: []mcplib.Content{
+			mcplib.TextContent{Type: "text", Text: string(resultData)},
+		},
+	}, nil
+}
+
+// whyAlternative is a rejected option as reported by akashi_why.
+type whyAlternative struct {
+	Label           string  `json:"label"`
+	RejectionReason *string `json:"rejection_reason,omitempty"`
+}
+
+// whyEvidence is a single piece of supporting evidence as reported by akashi_why.
+type whyEvidence struct {
+	SourceType     model.SourceType `json:"source_type"`
+	SourceURI      *string          `json:"source_uri,omitempty"`
+	Content        string           `json:"content"`
+	RelevanceScore *float32         `json:"relevance_score,omitempty"`
+}
+
+// whyResult is the akashi_why response body.
+type whyResult struct {
+	DecisionID   uuid.UUID `json:"decision_id"`
+	AgentID      string    `json:"agent_id"`
+	DecisionType string    `json:"decision_type"`
+	Outcome      string    `json:"outcome"`
+	Confidence   float32   `json:"confidence"`
+	Reasoning    *string   `json:"reasoning,omitempty"`
+
+	// RunnerUp is the first recorded alternative. Alternatives carry no score
+	// (dropped in migration 071), so there is no score gap to report.
+	RunnerUp               *whyAlternative `json:"runner_up"`
+	AlternativesConsidered int             `json:"alternatives_considered"`
+
+	TopEvidence   []whyEvidence `json:"top_evidence"`
+	EvidenceTotal int           `json:"evidence_total"`
+
+	Superseded        bool       `json:"superseded"`
+	SupersededBy      *uuid.UUID `json:"superseded_by,omitempty"`
+	CurrentRevisionID *uuid.UUID `json:"current_revision_id,omitempty"`
+	RevisionCount     int        `json:"revision_count"`
+}
+
+// explainDecision builds the akashi_why summary for d. revisions is the
+// decision's revision chain (ordered by valid_from) already filtered to what
+// the caller may see; evidenceLimit caps top_evidence.
+func explainDecision(d model.Decision, revisions []model.Decision, evidenceLimit int) whyResult {
+	res := whyResult{
+		DecisionID:             d.ID,
+		AgentID:                d.AgentID,
+		DecisionType:           d.DecisionType,
+		Outcome:                d.Outcome,
+		Confidence:             d.Confidence,
+		Reasoning:              d.Reasoning,
+		AlternativesConsidered: len(d.Alternatives),
+		TopEvidence:            []whyEvidence{},
+		EvidenceTotal:          len(d.Evidence),
+		Superseded:             d.ValidTo != nil,
+		RevisionCount:          len(revisions),
+	}
+	if len(d.Alternatives) > 0 {
+		alt := d.Alternatives[0]
+		res.RunnerUp = &whyAlternative{Label: alt.Label, RejectionReason: alt.RejectionReason}
+	}
+
+	// Highest relevance first; unscored evidence sorts last in recorded order.
+	evidence := make([]model.Evidence, len(d.Evidence))
+	copy(evidence, d.Evidence)
+	sort.SliceStable(evidence, func(i, j int) bool {
+		a, b := evidence[i].RelevanceScore, evidence[j].RelevanceScore
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a > *b
+	})
+	if len(evidence) > evidenceLimit {
+		evidence = evidence[:evidenceLimit]
+	}
+	for _, e := range evidence {
+		res.TopEvidence = append(res.TopEvidence, whyEvidence{
+			SourceType:     e.SourceType,
+			SourceURI:      e.SourceURI,
+			Content:        e.Content,
+			RelevanceScore: e.RelevanceScore,
+		})
+	}
+
+	for i := range revisions {
+		rev := revisions[i]
+		if rev.SupersedesID != nil && *rev.SupersedesID == d.ID {
+			res.SupersededBy = &rev.ID
+		}
+		if rev.ValidTo == nil {
+			res.CurrentRevisionID = &rev.ID
+		}
+	}
+	return res
+}
@@ -0,0 +1,257 @@
+// Package metrics exposes the process's OpenTelemetry metrics in Prometheus
+// text exposition format.
+//
+// The Exporter is an OTEL metric reader: registered with the meter provider
+// alongside (or instead of) the OTLP exporter, it lets a Prometheus scraper
+// collect every instrument Akashi already records — HTTP request counts and
+// latency, buffer and outbox depth, conflict counters, pool stats — without
+// a second instrumentation path.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// contentType is the Prometheus text exposition format version 0.0.4.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter collects metrics on demand and renders them for Prometheus.
+type Exporter struct {
+	reader *sdkmetric.ManualReader
+}
+
+// New creates an Exporter. Register Reader() with the meter provider before
+// instruments record anything.
+func New() *Exporter {
+	return &Exporter{reader: sdkmetric.NewManualReader()}
+}
+
+// Reader returns the OTEL reader to register with the meter provider.
+func (e *Exporter) Reader() sdkmetric.Reader {
+	return e.reader
+}
+
+// Handler serves the current metrics. When token is non-empty, requests must
+// carry "Authorization: Bearer <token>".
+func (e *Exporter) Handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", contentType)
+		if err := e.WriteTo(r.Context(), w); err != nil {
+			http.Error(w, "failed to collect metrics", http.StatusInternalServerError)
+		}
+	})
+}
+
+// WriteTo collects all metrics and writes them to w in Prometheus text format.
+func (e *Exporter) WriteTo(ctx context.Context, w io.Writer) error {
+	var rm metricdata.ResourceMetrics
+	if err := e.reader.Collect(ctx, &rm); err != nil {
+		return fmt.Errorf("metrics: collect: %w", err)
+	}
+
+	families := make(map[string]*family)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			addMetric(families, m)
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := families[name]
+		if f.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.kind)
+		for _, line := range f.samples {
+			bw.WriteString(line)
+		}
+	}
+	return bw.Flush()
+}
+
+// family is one Prometheus metric family. Instruments with the same name in
+// different instrumentation scopes are merged into one family.
+type family struct {
+	kind    string // counter, gauge, or histogram
+	help    string
+	samples []string
+}
+
+func addMetric(families map[string]*family, m metricdata.Metrics) {
+	base := sanitizeName(m.Name)
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		addSum(families, base, m.Description, data.IsMonotonic, data.DataPoints)
+	case metricdata.Sum[float64]:
+		addSum(families, base, m.Description, data.IsMonotonic, data.DataPoints)
+	case metricdata.Gauge[int64]:
+		f := getFamily(families, base, "gauge", m.Description)
+		for _, dp := range data.DataPoints {
+			f.samples = append(f.samples, sample(base, dp.Attributes, nil, float64(dp.Value)))
+		}
+	case metricdata.Gauge[float64]:
+		f := getFamily(families, base, "gauge", m.Description)
+		for _, dp := range data.DataPoints {
+			f.samples = append(f.samples, sample(base, dp.Attributes, nil, dp.Value))
+		}
+	case metricdata.Histogram[int64]:
+		addHistogram(families, base, m.Description, data.DataPoints)
+	case metricdata.Histogram[float64]:
+		addHistogram(families, base, m.Description, data.DataPoints)
+	}
+	// Exponential histograms and summaries have no direct text-format
+	// equivalent and are not recorded by Akashi; they are skipped.
+}
+
+func addSum[N int64 | float64](families map[string]*family, base, help string, monotonic bool, points []metricdata.DataPoint[N]) {
+	name, kind := base, "gauge"
+	if monotonic {
+		kind = "counter"
+		if !strings.HasSuffix(name, "_total") {
+			name += "_total"
+		}
+	}
+	f := getFamily(families, name, kind, help)
+	for _, dp := range points {
+		f.samples = append(f.samples, sample(name, dp.Attributes, nil, float64(dp.Value)))
+	}
+}
+
+func addHistogram[N int64 | float64](families map[string]*family, name, help string, points []metricdata.HistogramDataPoint[N]) {
+	f := getFamily(families, name, "histogram", help)
+	for _, dp := range points {
+		// OTEL bucket counts are per-bucket; Prometheus buckets are cumulative.
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			if i < len(dp.BucketCounts) {
+				cumulative += dp.BucketCounts[i]
+			}
+			le := [2]string{"le", formatFloat(bound)}
+			f.samples = append(f.samples, sample(name+"_bucket", dp.Attributes, &le, float64(cumulative)))
+		}
+		inf := [2]string{"le", "+Inf"}
+		f.samples = append(f.samples,
+			sample(name+"_bucket", dp.Attributes, &inf, float64(dp.Count)),
+			sample(name+"_sum", dp.Attributes, nil, float64(dp.Sum)),
+			sample(name+"_count", dp.Attributes, nil, float64(dp.Count)),
+		)
+	}
+}
+
+func getFamily(families map[string]*family, name, kind, help string) *family {
+	f, ok := families[name]
+	if !ok {
+		f = &family{kind: kind, help: help}
+		families[name] = f
+	}
+	return f
+}
+
+// sample renders one sample line. extra is an additional label (e.g. le)
+// appended after the attribute labels.
+func sample(name string, attrs attribute.Set, extra *[2]string, value float64) string {
+	var b strings.Builder
+	b.WriteString(name)
+	if attrs.Len() > 0 || extra != nil {
+		b.WriteByte('{')
+		first := true
+		writeLabel := func(k, v string) {
+			if !first {
+				b.WriteByte(',')
+			}
+			first = false
+			b.WriteString(sanitizeLabel(k))
+			b.WriteString(`="`)
+			b.WriteString(escapeLabelValue(v))
+			b.WriteByte('"')
+		}
+		iter := attrs.Iter()
+		for iter.Next() {
+			kv := iter.Attribute()
+			writeLabel(string(kv.Key), kv.Value.Emit())
+		}
+		if extra != nil {
+			writeLabel(extra[0], extra[1])
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// sanitizeName maps an OTEL instrument name (e.g. "akashi.buffer.depth") to
+// a valid Prometheus metric name ("akashi_buffer_depth").
+func sanitizeName(name string) string {
+	return sanitize(name, true)
+}
+
+// sanitizeLabel maps an attribute key (e.g. "http.method") to a valid
+// Prometheus label name ("http_method").
+func sanitizeLabel(key string) string {
+	return sanitize(key, false)
+}
+
+func sanitize(s string, allowColon bool) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, r := range s {
+		valid := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(i > 0 && r >= '0' && r <= '9') || (allowColon && r == ':')
+		if valid {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
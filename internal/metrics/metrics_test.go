@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// newTestExporter wires an Exporter into a private meter provider so tests
+// do not depend on (or disturb) the global one.
+func newTestExporter(t *testing.T) (*Exporter, metric.Meter) {
+	t.Helper()
+	exp := New()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(exp.Reader()))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	return exp, mp.Meter("akashi/test")
+}
+
+func scrape(t *testing.T, h http.Handler, token string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return rec.Code, string(body)
+}
+
+func TestHandler_ExposesMetricFamiliesAfterRequests(t *testing.T) {
+	exp, meter := newTestExporter(t)
+
+	// The same instruments the server's tracing middleware records.
+	reqCount, err := meter.Int64Counter("http.server.request_count")
+	require.NoError(t, err)
+	reqDuration, err := meter.Float64Histogram("http.server.duration", metric.WithUnit("ms"))
+	require.NoError(t, err)
+	depth := int64(0)
+	_, err = meter.Int64ObservableGauge("akashi.buffer.depth",
+		metric.WithDescription("Current number of events in the buffer"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(depth)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	app := http.NewServeMux()
+	app.HandleFunc("GET /v1/runs/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	instrumented := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		app.ServeHTTP(w, r)
+		attrs := metric.WithAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", "GET /v1/runs/{id}"),
+		)
+		reqCount.Add(r.Context(), 1, attrs)
+		reqDuration.Record(r.Context(), float64(time.Since(start).Milliseconds()), attrs)
+	})
+	srv := httptest.NewServer(instrumented)
+	defer srv.Close()
+
+	for range 3 {
+		resp, err := http.Get(srv.URL + "/v1/runs/abc")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+	depth = 7
+
+	code, body := scrape(t, exp.Handler(""), "")
+	require.Equal(t, http.StatusOK, code)
+
+	assert.Contains(t, body, "# TYPE http_server_request_count_total counter\n")
+	assert.Contains(t, body, `http_server_request_count_total{http_method="GET",http_route="GET /v1/runs/{id}"} 3`+"\n")
+
+	assert.Contains(t, body, "# TYPE http_server_duration histogram\n")
+	assert.Contains(t, body, `http_server_duration_bucket{http_method="GET",http_route="GET /v1/runs/{id}",le="+Inf"} 3`+"\n")
+	assert.Contains(t, body, `http_server_duration_count{http_method="GET",http_route="GET /v1/runs/{id}"} 3`+"\n")
+	assert.Contains(t, body, "http_server_duration_sum{")
+
+	assert.Contains(t, body, "# HELP akashi_buffer_depth Current number of events in the buffer\n")
+	assert.Contains(t, body, "# TYPE akashi_buffer_depth gauge\n")
+	assert.Contains(t, body, "akashi_buffer_depth 7\n")
+}
+
+func TestHandler_HistogramBucketsAreCumulative(t *testing.T) {
+	exp, meter := newTestExporter(t)
+	h, err := meter.Float64Histogram("akashi.search.duration",
+		metric.WithExplicitBucketBoundaries(10, 100))
+	require.NoError(t, err)
+	for _, v := range []float64{5, 50, 50, 500} {
+		h.Record(context.Background(), v)
+	}
+
+	_, body := scrape(t, exp.Handler(""), "")
+	assert.Contains(t, body, `akashi_search_duration_bucket{le="10"} 1`+"\n")
+	assert.Contains(t, body, `akashi_search_duration_bucket{le="100"} 3`+"\n")
+	assert.Contains(t, body, `akashi_search_duration_bucket{le="+Inf"} 4`+"\n")
+	assert.Contains(t, body, "akashi_search_duration_sum 605\n")
+}
+
+func TestHandler_UpDownCounterIsGauge(t *testing.T) {
+	exp, meter := newTestExporter(t)
+	c, err := meter.Int64UpDownCounter("akashi.outbox.in_flight")
+	require.NoError(t, err)
+	c.Add(context.Background(), 5)
+	c.Add(context.Background(), -2)
+
+	_, body := scrape(t, exp.Handler(""), "")
+	assert.Contains(t, body, "# TYPE akashi_outbox_in_flight gauge\n")
+	assert.Contains(t, body, "akashi_outbox_in_flight 3\n")
+	assert.NotContains(t, body, "akashi_outbox_in_flight_total")
+}
+
+func TestHandler_BearerToken(t *testing.T) {
+	exp, meter := newTestExporter(t)
+	c, err := meter.Int64Counter("akashi.conflicts.detected")
+	require.NoError(t, err)
+	c.Add(context.Background(), 1)
+	h := exp.Handler("s3cret")
+
+	code, _ := scrape(t, h, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = scrape(t, h, "wrong")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, body := scrape(t, h, "s3cret")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "akashi_conflicts_detected_total 1\n")
+}
+
+func TestSanitizeAndEscape(t *testing.T) {
+	assert.Equal(t, "akashi_pool_connections_total", sanitizeName("akashi.pool.connections.total"))
+	assert.Equal(t, "_lives", sanitizeName("9lives"))
+	assert.Equal(t, "http_status_code", sanitizeLabel("http.status_code"))
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabelValue("a\"b\\c\nd"))
+	assert.False(t, strings.Contains(escapeHelp("line1\nline2"), "\n"))
+}
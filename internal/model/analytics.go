@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // ConflictAnalytics is the response for GET /v1/conflicts/analytics.
 // It aggregates conflict data over a time period into summary stats,
@@ -56,3 +60,73 @@ type ConflictTrendPoint struct {
 	Detected int    `json:"detected"`
 	Resolved int    `json:"resolved"`
 }
+
+// DuplicatesReport is the response for GET /v1/stats/duplicates. Clusters
+// group current decisions that are near-identical in both reasoning and
+// outcome — redundant work rather than disagreement. ScannedAt is nil when
+// the org has not been scanned yet.
+type DuplicatesReport struct {
+	Threshold        float64            `json:"threshold"`
+	Floor            float64            `json:"floor"`
+	ScannedAt        *time.Time         `json:"scanned_at"`
+	DecisionsScanned int                `json:"decisions_scanned"`
+	TotalClusters    int                `json:"total_clusters"`
+	Clusters         []DuplicateCluster `json:"clusters"`
+}
+
+// DuplicateCluster is a connected group of near-duplicate decisions.
+// Representative is the member with the most near-duplicates in the cluster.
+type DuplicateCluster struct {
+	Size           int               `json:"size"`
+	MaxSimilarity  float64           `json:"max_similarity"`
+	MinSimilarity  float64           `json:"min_similarity"`
+	AgentCount     int               `json:"agent_count"`
+	Representative DuplicateMember   `json:"representative"`
+	Members        []DuplicateMember `json:"members"`
+}
+
+// DuplicateMember is one decision in a DuplicateCluster.
+type DuplicateMember struct {
+	DecisionID   uuid.UUID `json:"decision_id"`
+	AgentID      string    `json:"agent_id"`
+	DecisionType string    `json:"decision_type"`
+	Outcome      string    `json:"outcome"`
+	ValidFrom    time.Time `json:"valid_from"`
+}
+
+// ConfidenceConsensusReport is the response for GET /v1/consensus/confidence.
+// It groups current decisions of one decision type by the value of a shared
+// metadata key and reports, per entity, how confident each agent was.
+type ConfidenceConsensusReport struct {
+	DecisionType    string                      `json:"decision_type"`
+	MetadataKey     string                      `json:"metadata_key"`
+	SpreadThreshold float64                     `json:"spread_threshold"`
+	TotalEntities   int                         `json:"total_entities"`
+	Entities        []ConfidenceConsensusEntity `json:"entities"`
+}
+
+// ConfidenceConsensusEntity is the cross-agent confidence spread for one
+// metadata value. Only entities decided on by at least two agents are
+// reported. CalibrationFlag is set when the agents agree on the outcome but
+// their confidence spread (max - min) reaches the report's threshold.
+type ConfidenceConsensusEntity struct {
+	MetadataValue    string                        `json:"metadata_value"`
+	AgentCount       int                           `json:"agent_count"`
+	OutcomeAgreement bool                          `json:"outcome_agreement"`
+	ConfidenceMin    float64                       `json:"confidence_min"`
+	ConfidenceMax    float64                       `json:"confidence_max"`
+	ConfidenceMean   float64                       `json:"confidence_mean"`
+	ConfidenceStddev float64                       `json:"confidence_stddev"`
+	Spread           float64                       `json:"spread"`
+	CalibrationFlag  bool                          `json:"calibration_flag"`
+	Agents           []ConfidenceConsensusDecision `json:"agents"`
+}
+
+// ConfidenceConsensusDecision is one agent's current decision on an entity.
+type ConfidenceConsensusDecision struct {
+	DecisionID uuid.UUID `json:"decision_id"`
+	AgentID    string    `json:"agent_id"`
+	Outcome    string    `json:"outcome"`
+	Confidence float32   `json:"confidence"`
+	ValidFrom  time.Time `json:"valid_from"`
+}
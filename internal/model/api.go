@@ -29,6 +29,7 @@ const (
 	MaxPrecedentReasonLen  = 4 * 1024  // 4 KB — brief explanation of why a precedent applies
 	MaxMetricsKeys         = 50        // cap metric entries per evidence item
 	MaxMetadataBytes       = 16 * 1024 // 16 KB — serialized JSON cap for any metadata map
+	MaxSupersedeMatchKeys  = 8         // metadata keys identifying a standing decision
 )
 
 // privateIPRanges is the set of CIDR blocks considered non-public.
@@ -53,59 +54,133 @@ func init() {
 	}
 }
 
+// FieldError is one invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every invalid field of a request so a client can
+// fix them all in one round trip. Handlers return it as the details of an
+// invalid_input error; Error joins the messages for plain-text callers.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, fe := range v {
+		msgs[i] = fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add records a problem with field.
+func (v *ValidationErrors) Add(field, format string, args ...any) {
+	*v = append(*v, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// AddErr records err against field. When err is itself a ValidationErrors,
+// its entries are merged with their field names prefixed by prefix instead.
+func (v *ValidationErrors) AddErr(field, prefix string, err error) {
+	if err == nil {
+		return
+	}
+	if nested, ok := err.(ValidationErrors); ok {
+		for _, fe := range nested {
+			*v = append(*v, FieldError{Field: prefix + fe.Field, Message: fe.Message})
+		}
+		return
+	}
+	*v = append(*v, FieldError{Field: field, Message: err.Error()})
+}
+
+// Err returns v as an error, or nil when no problem was recorded.
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
 // ValidateTraceDecision checks per-field length limits on the fields that flow
-// into the embedding pipeline and Postgres TEXT columns.
+// into the embedding pipeline and Postgres TEXT columns. Every violation is
+// reported; the error is a ValidationErrors with field names relative to the
+// decision.
 func ValidateTraceDecision(d TraceDecision) error {
+	var errs ValidationErrors
 	if len(d.DecisionType) > MaxDecisionTypeLen {
-		return fmt.Errorf("decision_type exceeds maximum length of %d characters", MaxDecisionTypeLen)
+		errs.Add("decision_type", "decision_type exceeds maximum length of %d characters", MaxDecisionTypeLen)
 	}
 	if len(d.Outcome) > MaxOutcomeLen {
-		return fmt.Errorf("outcome exceeds maximum length of %d bytes", MaxOutcomeLen)
+		errs.Add("outcome", "outcome exceeds maximum length of %d bytes", MaxOutcomeLen)
 	}
 	if d.Reasoning != nil && len(*d.Reasoning) > MaxReasoningLen {
-		return fmt.Errorf("reasoning exceeds maximum length of %d bytes", MaxReasoningLen)
+		errs.Add("reasoning", "reasoning exceeds maximum length of %d bytes", MaxReasoningLen)
 	}
+	errs.AddErr("confidence_low", "", ValidateConfidenceInterval(d.Confidence, d.ConfidenceLow, d.ConfidenceHigh))
 
 	// Collection count limits.
 	if len(d.Alternatives) > MaxAlternativeCount {
-		return fmt.Errorf("alternatives count %d exceeds maximum of %d", len(d.Alternatives), MaxAlternativeCount)
+		errs.Add("alternatives", "alternatives count %d exceeds maximum of %d", len(d.Alternatives), MaxAlternativeCount)
 	}
 	if len(d.Evidence) > MaxEvidenceCount {
-		return fmt.Errorf("evidence count %d exceeds maximum of %d", len(d.Evidence), MaxEvidenceCount)
+		errs.Add("evidence", "evidence count %d exceeds maximum of %d", len(d.Evidence), MaxEvidenceCount)
 	}
 
 	// Per-alternative field limits.
 	for i, alt := range d.Alternatives {
 		if len(alt.Label) > MaxAlternativeLabelLen {
-			return fmt.Errorf("alternatives[%d].label exceeds maximum length of %d characters", i, MaxAlternativeLabelLen)
+			errs.Add(fmt.Sprintf("alternatives[%d].label", i),
+				"alternatives[%d].label exceeds maximum length of %d characters", i, MaxAlternativeLabelLen)
 		}
 		if alt.RejectionReason != nil && len(*alt.RejectionReason) > MaxRejectionReasonLen {
-			return fmt.Errorf("alternatives[%d].rejection_reason exceeds maximum length of %d bytes", i, MaxRejectionReasonLen)
+			errs.Add(fmt.Sprintf("alternatives[%d].rejection_reason", i),
+				"alternatives[%d].rejection_reason exceeds maximum length of %d bytes", i, MaxRejectionReasonLen)
 		}
 	}
 
 	// Per-evidence field limits.
 	for i, ev := range d.Evidence {
 		if len(ev.Content) > MaxEvidenceContentLen {
-			return fmt.Errorf("evidence[%d].content exceeds maximum length of %d bytes", i, MaxEvidenceContentLen)
+			errs.Add(fmt.Sprintf("evidence[%d].content", i),
+				"evidence[%d].content exceeds maximum length of %d bytes", i, MaxEvidenceContentLen)
 		}
 		if ev.SourceURI != nil {
 			if err := ValidateSourceURI(*ev.SourceURI); err != nil {
-				return fmt.Errorf("evidence[%d].source_uri: %w", i, err)
+				errs.Add(fmt.Sprintf("evidence[%d].source_uri", i), "evidence[%d].source_uri: %v", i, err)
 			}
 		}
+		metricsField := fmt.Sprintf("evidence[%d].metrics", i)
 		if ev.SourceType == string(SourceMetrics) {
 			if len(ev.Metrics) == 0 {
-				return fmt.Errorf("evidence[%d].metrics is required when source_type is \"metrics\"", i)
+				errs.Add(metricsField, "evidence[%d].metrics is required when source_type is \"metrics\"", i)
 			}
 			if len(ev.Metrics) > MaxMetricsKeys {
-				return fmt.Errorf("evidence[%d].metrics has %d keys, maximum is %d", i, len(ev.Metrics), MaxMetricsKeys)
+				errs.Add(metricsField, "evidence[%d].metrics has %d keys, maximum is %d", i, len(ev.Metrics), MaxMetricsKeys)
 			}
 		}
 		if len(ev.Metrics) > 0 && ev.SourceType != string(SourceMetrics) {
-			return fmt.Errorf("evidence[%d].metrics is only allowed when source_type is \"metrics\"", i)
+			errs.Add(metricsField, "evidence[%d].metrics is only allowed when source_type is \"metrics\"", i)
 		}
 	}
+	return errs.Err()
+}
+
+// ValidateConfidenceInterval checks an optional confidence interval against
+// its point estimate. The bounds must be supplied together and satisfy
+// 0 <= low <= confidence <= high <= 1. Omitting both is always valid.
+func ValidateConfidenceInterval(confidence float32, low, high *float32) error {
+	if low == nil && high == nil {
+		return nil
+	}
+	if low == nil || high == nil {
+		return fmt.Errorf("confidence_low and confidence_high must be provided together")
+	}
+	if *low < 0 || *high > 1 {
+		return fmt.Errorf("confidence_low and confidence_high must be between 0 and 1")
+	}
+	if *low > confidence || confidence > *high {
+		return fmt.Errorf("confidence interval must satisfy confidence_low <= confidence <= confidence_high")
+	}
 	return nil
 }
 
@@ -125,6 +200,39 @@ func ValidateMetadataSize(field string, m map[string]any) error {
 	return nil
 }
 
+// ValidateSupersedeMatching checks a trace's supersede_matching clause: at most
+// MaxSupersedeMatchKeys distinct, non-empty keys, each present in metadata, and
+// no explicit supersedes_id alongside it. Returns nil when the clause is unset.
+func ValidateSupersedeMatching(req TraceRequest) error {
+	if req.SupersedeMatching == nil {
+		return nil
+	}
+	keys := req.SupersedeMatching.Keys
+	if len(keys) == 0 {
+		return fmt.Errorf("supersede_matching.keys must not be empty")
+	}
+	if len(keys) > MaxSupersedeMatchKeys {
+		return fmt.Errorf("supersede_matching.keys exceeds maximum of %d keys", MaxSupersedeMatchKeys)
+	}
+	if req.SupersedesID != nil {
+		return fmt.Errorf("supersede_matching and supersedes_id are mutually exclusive")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			return fmt.Errorf("supersede_matching.keys must not contain empty keys")
+		}
+		if seen[k] {
+			return fmt.Errorf("supersede_matching.keys contains duplicate key %q", k)
+		}
+		seen[k] = true
+		if _, ok := req.Metadata[k]; !ok {
+			return fmt.Errorf("supersede_matching key %q is not present in metadata", k)
+		}
+	}
+	return nil
+}
+
 // ValidateSourceURI validates a source_uri in evidence.
 // source_uri is stored metadata — the server never fetches it — so the only
 // security concern is XSS if the value is rendered as a hyperlink in the UI.
@@ -187,13 +295,19 @@ type APIResponse struct {
 // ListResponse is the standard envelope for paginated list endpoints.
 // The array of items is in Data; Total is omitted when access-filtering
 // makes the DB total unreliable (i.e., some rows were hidden by grants).
+// NextCursor is set only by endpoints paged by cursor, while more rows remain.
 type ListResponse struct {
-	Data    any          `json:"data"`
-	Total   *int         `json:"total,omitempty"`
-	HasMore bool         `json:"has_more"`
-	Limit   int          `json:"limit"`
-	Offset  int          `json:"offset"`
-	Meta    ResponseMeta `json:"meta"`
+	Data       any          `json:"data"`
+	Total      *int         `json:"total,omitempty"`
+	HasMore    bool         `json:"has_more"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Meta       ResponseMeta `json:"meta"`
+
+	// Suggestions lists close matches for an unknown agent_id filter that
+	// left the list empty (POST /v1/query with suggest=true).
+	Suggestions []string `json:"suggestions,omitempty"`
 }
 
 // APIError is the standard error response envelope.
@@ -224,6 +338,7 @@ const (
 	ErrCodeConflict           = "CONFLICT"
 	ErrCodeInternalError      = "INTERNAL_ERROR"
 	ErrCodeRateLimited        = "RATE_LIMITED"
+	ErrCodeQuotaExceeded      = "QUOTA_EXCEEDED"
 	ErrCodeNotImplemented     = "NOT_IMPLEMENTED"
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
 )
@@ -265,16 +380,47 @@ type TraceRequest struct {
 	SupersedesID    *uuid.UUID     `json:"supersedes_id,omitempty"`    // decision this one explicitly replaces
 	Metadata        map[string]any `json:"metadata,omitempty"`
 	Context         map[string]any `json:"context,omitempty"` // Agent context (model, task, repo, branch).
+
+	// ContextSnapshot is an optional typed capture of the agent's runtime
+	// configuration, stored alongside the free-form context.
+	ContextSnapshot *ContextSnapshot `json:"context_snapshot,omitempty"`
+
+	// SupersedeMatching opts into "update my standing decision" semantics:
+	// the active decision with the same decision_type and the same metadata
+	// values for Keys is superseded instead of traced alongside.
+	SupersedeMatching *SupersedeMatching `json:"supersede_matching,omitempty"`
+
+	// Dedupe returns the agent's recent near-identical decision, flagged with
+	// duplicate_of, instead of recording a new one.
+	Dedupe bool `json:"dedupe,omitempty"`
+}
+
+// TraceBatchRequest is the request for POST /v1/trace/batch.
+type TraceBatchRequest struct {
+	Traces []TraceRequest `json:"traces"`
+}
+
+// SupersedeMatching names the metadata keys that identify a standing decision.
+type SupersedeMatching struct {
+	Keys []string `json:"keys"`
 }
 
 // TraceDecision is the decision portion of a trace convenience request.
 type TraceDecision struct {
-	DecisionType string             `json:"decision_type"`
-	Outcome      string             `json:"outcome"`
-	Confidence   float32            `json:"confidence"`
-	Reasoning    *string            `json:"reasoning,omitempty"`
-	Alternatives []TraceAlternative `json:"alternatives,omitempty"`
-	Evidence     []TraceEvidence    `json:"evidence,omitempty"`
+	DecisionType string  `json:"decision_type"`
+	Outcome      string  `json:"outcome"`
+	Confidence   float32 `json:"confidence"`
+	// ConfidenceScale declares the scale Confidence (and its bounds) were
+	// given on: "unit" (default, 0–1), "percent" (0–100), or "grade", in which
+	// case ConfidenceGrade carries the letter. See NormalizeConfidenceScale.
+	ConfidenceScale string `json:"confidence_scale,omitempty"`
+	ConfidenceGrade string `json:"confidence_grade,omitempty"`
+	// ConfidenceLow and ConfidenceHigh optionally bound Confidence. Both or neither.
+	ConfidenceLow  *float32           `json:"confidence_low,omitempty"`
+	ConfidenceHigh *float32           `json:"confidence_high,omitempty"`
+	Reasoning      *string            `json:"reasoning,omitempty"`
+	Alternatives   []TraceAlternative `json:"alternatives,omitempty"`
+	Evidence       []TraceEvidence    `json:"evidence,omitempty"`
 }
 
 // TraceAlternative is an alternative in a trace convenience request.
@@ -312,16 +458,25 @@ type AuthTokenRequest struct {
 	APIKey  string `json:"api_key"`
 }
 
-// AuthTokenResponse is the response for POST /auth/token.
+// AuthTokenResponse is the response for POST /auth/token and POST /auth/refresh.
+// RefreshToken is single-use: each refresh returns a replacement.
 type AuthTokenResponse struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// AuthRefreshRequest is the request body for POST /auth/refresh.
+type AuthRefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // ScopedTokenRequest is the request body for POST /auth/scoped-token.
 type ScopedTokenRequest struct {
 	AsAgentID string `json:"as_agent_id"`
 	ExpiresIn int    `json:"expires_in,omitempty"` // seconds; defaults to 300, capped at 3600
+	Namespace string `json:"namespace,omitempty"`  // pins the token to one decision namespace
 }
 
 // ScopedTokenResponse is the response for POST /auth/scoped-token.
@@ -342,6 +497,23 @@ type CreateAgentRequest struct {
 	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
+// MaxBulkAgents caps the number of agents accepted by POST /v1/agents/bulk.
+const MaxBulkAgents = 100
+
+// BulkCreateAgentsRequest is the request body for POST /v1/agents/bulk.
+type BulkCreateAgentsRequest struct {
+	Agents []CreateAgentRequest `json:"agents"`
+}
+
+// BulkItemError describes why one item of a bulk request was rejected.
+// Index is the item's zero-based position in the request array.
+type BulkItemError struct {
+	Index   int    `json:"index"`
+	AgentID string `json:"agent_id,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 // UpdateAgentRequest is the request body for PATCH /v1/agents/{agent_id}.
 type UpdateAgentRequest struct {
 	Name     *string        `json:"name,omitempty"`
@@ -353,6 +525,32 @@ type UpdateAgentTagsRequest struct {
 	Tags []string `json:"tags"`
 }
 
+// UpdateDecisionTagsRequest is the request body for PATCH /v1/decisions/{id}/tags.
+// Add is applied before Remove; a tag may not appear in both.
+type UpdateDecisionTagsRequest struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// UpdateDecisionMetadataRequest is the request body for
+// PATCH /v1/decisions/{id}/metadata. Metadata is merged into the decision's
+// existing metadata: listed keys are overwritten, others are kept.
+type UpdateDecisionMetadataRequest struct {
+	Metadata map[string]any `json:"metadata"`
+}
+
+// DecisionMetadataResponse is the response body for PATCH /v1/decisions/{id}/metadata.
+type DecisionMetadataResponse struct {
+	DecisionID uuid.UUID      `json:"decision_id"`
+	Metadata   map[string]any `json:"metadata"`
+}
+
+// DecisionTagsResponse is the response body for PATCH /v1/decisions/{id}/tags.
+type DecisionTagsResponse struct {
+	DecisionID uuid.UUID `json:"decision_id"`
+	Tags       []string  `json:"tags"`
+}
+
 // CreateGrantRequest is the request body for POST /v1/grants.
 type CreateGrantRequest struct {
 	GranteeAgentID string  `json:"grantee_agent_id"`
@@ -362,6 +560,43 @@ type CreateGrantRequest struct {
 	ExpiresAt      *string `json:"expires_at,omitempty"`
 }
 
+// SetOrgSettingRequest is the request body for PUT /v1/orgs/{org_id}/settings.
+// Value replaces the top-level settings key; JSON null removes it.
+type SetOrgSettingRequest struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// GrantBatchRequest is the JSON request body for POST /v1/grants/batch.
+type GrantBatchRequest struct {
+	Grants []CreateGrantRequest `json:"grants"`
+}
+
+// Grant batch item statuses.
+const (
+	GrantBatchCreated = "created" // A new grant was inserted.
+	GrantBatchExists  = "exists"  // An identical grant already existed; it is returned.
+	GrantBatchFailed  = "failed"  // The item was invalid or not permitted; see Error.
+)
+
+// GrantBatchResult is the outcome of one item in a grant batch. Index is the
+// item's position in the request: the grants array for JSON, the data row
+// (header excluded) for CSV.
+type GrantBatchResult struct {
+	Index  int          `json:"index"`
+	Status string       `json:"status"`
+	Grant  *AccessGrant `json:"grant,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// GrantBatchResponse is the response for POST /v1/grants/batch.
+type GrantBatchResponse struct {
+	Created  int                `json:"created"`
+	Existing int                `json:"existing"`
+	Failed   int                `json:"failed"`
+	Results  []GrantBatchResult `json:"results"`
+}
+
 // MCPInfoResponse is the response for GET /mcp/info (unauthenticated).
 type MCPInfoResponse struct {
 	Version   string      `json:"version"`
@@ -425,3 +660,17 @@ type MCPConfigSnippet struct {
 	URL    string `json:"url"`
 	Header string `json:"header"`
 }
+
+// OrgRateLimit is an org's rate limit override, applied to each agent and
+// API key in the org in place of the global AKASHI_RATE_LIMIT_* settings.
+type OrgRateLimit struct {
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+}
+
+// SetOrgRateLimitRequest is the body for PUT /v1/orgs/{org_id}/rate-limit.
+// Both fields null clears the override.
+type SetOrgRateLimitRequest struct {
+	RPS   *float64 `json:"rps"`
+	Burst *int     `json:"burst"`
+}
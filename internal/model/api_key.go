@@ -46,6 +46,15 @@ type RotateKeyResponse struct {
 	RevokedKeyID uuid.UUID        `json:"revoked_key_id"`
 }
 
+// RotateAgentKeyResponse is the response for POST /v1/agents/{agent_id}/rotate-key.
+// RevokedKeyIDs lists the managed keys that stopped minting tokens; a legacy
+// key stored on the agent row is cleared too but has no ID to report.
+type RotateAgentKeyResponse struct {
+	AgentID       string           `json:"agent_id"`
+	NewKey        APIKeyWithRawKey `json:"new_key"`
+	RevokedKeyIDs []uuid.UUID      `json:"revoked_key_ids"`
+}
+
 const (
 	// keyPrefixLen is the number of random bytes used for the key prefix (8 hex chars).
 	keyPrefixLen = 4
@@ -1,10 +1,12 @@
 package model_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -116,6 +118,42 @@ func TestValidateTraceDecision_SecondEvidenceItemFails(t *testing.T) {
 	assert.Contains(t, err.Error(), "evidence[1].source_uri")
 }
 
+func TestValidateTraceDecision_ConfidenceInterval(t *testing.T) {
+	base := model.TraceDecision{DecisionType: "architecture", Outcome: "ok", Confidence: 0.6}
+
+	cases := []struct {
+		name    string
+		low     *float32
+		high    *float32
+		wantErr string
+	}{
+		{name: "no interval", low: nil, high: nil},
+		{name: "valid interval", low: ptr(float32(0.4)), high: ptr(float32(0.8))},
+		{name: "degenerate interval", low: ptr(float32(0.6)), high: ptr(float32(0.6))},
+		{name: "full range", low: ptr(float32(0)), high: ptr(float32(1))},
+		{name: "low only", low: ptr(float32(0.4)), wantErr: "must be provided together"},
+		{name: "high only", high: ptr(float32(0.8)), wantErr: "must be provided together"},
+		{name: "low negative", low: ptr(float32(-0.1)), high: ptr(float32(0.8)), wantErr: "between 0 and 1"},
+		{name: "high above one", low: ptr(float32(0.4)), high: ptr(float32(1.1)), wantErr: "between 0 and 1"},
+		{name: "low above point", low: ptr(float32(0.7)), high: ptr(float32(0.8)), wantErr: "confidence_low <= confidence"},
+		{name: "high below point", low: ptr(float32(0.4)), high: ptr(float32(0.5)), wantErr: "confidence <= confidence_high"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := base
+			d.ConfidenceLow = tc.low
+			d.ConfidenceHigh = tc.high
+			err := model.ValidateTraceDecision(d)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
 // ---- ValidateSourceURI ---------------------------------------------------
 
 func TestValidateSourceURI_ValidHTTP(t *testing.T) {
@@ -493,3 +531,67 @@ func TestHighConfidenceWarnings_CustomThreshold(t *testing.T) {
 	require.Len(t, warnings, 1)
 	assert.Contains(t, warnings[0], "0.7")
 }
+
+// ---- ValidateSupersedeMatching --------------------------------------------
+
+func TestValidateSupersedeMatching(t *testing.T) {
+	meta := map[string]any{"entity": "sku-1", "region": "eu"}
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     model.TraceRequest
+		wantErr string
+	}{
+		{"unset", model.TraceRequest{}, ""},
+		{"valid", model.TraceRequest{Metadata: meta, SupersedeMatching: &model.SupersedeMatching{Keys: []string{"entity", "region"}}}, ""},
+		{"no keys", model.TraceRequest{Metadata: meta, SupersedeMatching: &model.SupersedeMatching{}}, "must not be empty"},
+		{"missing key", model.TraceRequest{Metadata: meta, SupersedeMatching: &model.SupersedeMatching{Keys: []string{"tenant"}}}, "not present in metadata"},
+		{"duplicate key", model.TraceRequest{Metadata: meta, SupersedeMatching: &model.SupersedeMatching{Keys: []string{"entity", "entity"}}}, "duplicate"},
+		{"with supersedes_id", model.TraceRequest{Metadata: meta, SupersedesID: &id, SupersedeMatching: &model.SupersedeMatching{Keys: []string{"entity"}}}, "mutually exclusive"},
+		{"too many keys", model.TraceRequest{Metadata: meta, SupersedeMatching: &model.SupersedeMatching{Keys: make([]string, model.MaxSupersedeMatchKeys+1)}}, "maximum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := model.ValidateSupersedeMatching(tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	var errs model.ValidationErrors
+	assert.NoError(t, errs.Err(), "empty collection must be a nil error")
+
+	errs.Add("decision.outcome", "decision.outcome is required")
+	errs.AddErr("agent_id", "", errors.New("agent_id is required"))
+	errs.AddErr("decision", "decision.", model.ValidationErrors{{Field: "reasoning", Message: "reasoning too long"}})
+	errs.AddErr("metadata", "", nil)
+
+	require.Error(t, errs.Err())
+	assert.Equal(t, model.ValidationErrors{
+		{Field: "decision.outcome", Message: "decision.outcome is required"},
+		{Field: "agent_id", Message: "agent_id is required"},
+		{Field: "decision.reasoning", Message: "reasoning too long"},
+	}, errs)
+	assert.Equal(t, "decision.outcome is required; agent_id is required; reasoning too long", errs.Error())
+}
+
+func TestValidateTraceDecision_ReportsEveryViolation(t *testing.T) {
+	err := model.ValidateTraceDecision(model.TraceDecision{
+		DecisionType: strings.Repeat("x", model.MaxDecisionTypeLen+1),
+		Outcome:      strings.Repeat("y", model.MaxOutcomeLen+1),
+		Alternatives: []model.TraceAlternative{{Label: strings.Repeat("z", model.MaxAlternativeLabelLen+1)}},
+	})
+	var verrs model.ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	require.Len(t, verrs, 3)
+	assert.Equal(t, "decision_type", verrs[0].Field)
+	assert.Equal(t, "outcome", verrs[1].Field)
+	assert.Equal(t, "alternatives[0].label", verrs[2].Field)
+}
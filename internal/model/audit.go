@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log types accepted by GET /v1/audit.
+const (
+	// AuditTypeMutation selects entries from the mutation audit log (writes).
+	AuditTypeMutation = "mutation"
+	// AuditTypeAccess selects entries from the access log. Only populated
+	// when AKASHI_ACCESS_LOG_ENABLED or AKASHI_ACCESS_LOG_REQUESTS is set.
+	AuditTypeAccess = "access"
+)
+
+// AccessResourceRequest is the resource_type of access log entries that
+// record a whole request rather than the decisions it returned. Only written
+// when AKASHI_ACCESS_LOG_REQUESTS is set.
+const AccessResourceRequest = "request"
+
+// AuditEntry is a single row from either the mutation or the access audit log.
+// Mutation entries carry Operation and ResourceID; access entries carry
+// ResourceIDs (possibly truncated) and ResultCount, and per-request access
+// entries carry Status and LatencyMs.
+type AuditEntry struct {
+	ID           int64          `json:"id"`
+	Type         string         `json:"type"`
+	OccurredAt   time.Time      `json:"occurred_at"`
+	RequestID    string         `json:"request_id"`
+	ActorAgentID string         `json:"actor_agent_id"`
+	ActorRole    string         `json:"actor_role"`
+	HTTPMethod   string         `json:"http_method"`
+	Endpoint     string         `json:"endpoint"`
+	Operation    string         `json:"operation,omitempty"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id,omitempty"`
+	ResourceIDs  []uuid.UUID    `json:"resource_ids,omitempty"`
+	ResultCount  *int           `json:"result_count,omitempty"`
+	Status       *int           `json:"status,omitempty"`
+	LatencyMs    *float64       `json:"latency_ms,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+}
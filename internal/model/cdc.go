@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Decision change operations recorded in the CDC log.
+const (
+	ChangeOpCreate  = "create"
+	ChangeOpRevise  = "revise"
+	ChangeOpArchive = "archive"
+	ChangeOpDelete  = "delete"
+)
+
+// DecisionChange is one entry of the decision change log. LSNs increase in
+// commit order, so polling with the last LSN seen never skips an entry.
+// Before and After are row images without embeddings; both are null for
+// entries whose decision was later deleted.
+type DecisionChange struct {
+	LSN        int64           `json:"lsn"`
+	OrgID      uuid.UUID       `json:"org_id"`
+	DecisionID uuid.UUID       `json:"decision_id"`
+	Operation  string          `json:"operation"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RecordedAt time.Time       `json:"recorded_at"`
+}
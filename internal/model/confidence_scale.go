@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Confidence scales accepted on trace requests. Stored confidence is always
+// on the unit scale; other scales are normalized at the API boundary.
+const (
+	ConfidenceScaleUnit    = "unit"
+	ConfidenceScalePercent = "percent"
+	ConfidenceScaleGrade   = "grade"
+)
+
+// ConfidenceOriginalKey is the metadata key under which the caller's
+// original, un-normalized confidence is preserved.
+const ConfidenceOriginalKey = "confidence_original"
+
+// confidenceGrades maps letter grades onto unit-scale confidence, from A+
+// (0.95) down in steps of 0.05. The ladder skips 0.50 between C- and D+, and
+// F is a flat floor of 0.20 rather than extending it to zero.
+var confidenceGrades = map[string]float32{
+	"A+": 0.95, "A": 0.90, "A-": 0.85,
+	"B+": 0.80, "B": 0.75, "B-": 0.70,
+	"C+": 0.65, "C": 0.60, "C-": 0.55,
+	"D+": 0.45, "D": 0.40, "D-": 0.35,
+	"F": 0.20,
+}
+
+// NormalizeConfidenceScale rewrites d's confidence fields onto the unit
+// scale according to d.ConfidenceScale and returns a record of the original
+// values for metadata, or nil when no conversion was needed. Values outside
+// the declared scale's range are rejected. After a successful call,
+// d.ConfidenceScale is "unit" (or empty), so normalizing twice is a no-op.
+func NormalizeConfidenceScale(d *TraceDecision) (map[string]any, error) {
+	switch d.ConfidenceScale {
+	case "", ConfidenceScaleUnit:
+		if d.ConfidenceGrade != "" {
+			return nil, fmt.Errorf("decision.confidence_grade requires decision.confidence_scale \"grade\"")
+		}
+		return nil, nil
+
+	case ConfidenceScalePercent:
+		if d.ConfidenceGrade != "" {
+			return nil, fmt.Errorf("decision.confidence_grade requires decision.confidence_scale \"grade\"")
+		}
+		if d.Confidence < 0 || d.Confidence > 100 {
+			return nil, fmt.Errorf("decision.confidence must be between 0 and 100 for confidence_scale \"percent\"")
+		}
+		// Percent values are divided by 100 onto the unit scale; the caller's
+		// 0–100 values are kept in original.
+		original := map[string]any{"scale": ConfidenceScalePercent, "value": d.Confidence}
+		for _, b := range []struct {
+			name string
+			v    *float32
+		}{{"confidence_low", d.ConfidenceLow}, {"confidence_high", d.ConfidenceHigh}} {
+			if b.v == nil {
+				continue
+			}
+			if *b.v < 0 || *b.v > 100 {
+				return nil, fmt.Errorf("decision.%s must be between 0 and 100 for confidence_scale \"percent\"", b.name)
+			}
+			original[strings.TrimPrefix(b.name, "confidence_")] = *b.v
+			*b.v /= 100
+		}
+		d.Confidence /= 100
+		d.ConfidenceScale = ConfidenceScaleUnit
+		return original, nil
+
+	case ConfidenceScaleGrade:
+		grade := strings.ToUpper(strings.TrimSpace(d.ConfidenceGrade))
+		if grade == "" {
+			return nil, fmt.Errorf("decision.confidence_grade is required for confidence_scale \"grade\"")
+		}
+		v, ok := confidenceGrades[grade]
+		if !ok {
+			return nil, fmt.Errorf("decision.confidence_grade %q is not a valid grade (A+ through D-, or F)", d.ConfidenceGrade)
+		}
+		if d.Confidence != 0 {
+			return nil, fmt.Errorf("decision.confidence must be omitted when confidence_scale is \"grade\"")
+		}
+		if d.ConfidenceLow != nil || d.ConfidenceHigh != nil {
+			return nil, fmt.Errorf("confidence intervals are not supported with confidence_scale \"grade\"")
+		}
+		d.Confidence = v
+		d.ConfidenceScale = ConfidenceScaleUnit
+		d.ConfidenceGrade = ""
+		return map[string]any{"scale": ConfidenceScaleGrade, "value": grade}, nil
+
+	default:
+		return nil, fmt.Errorf("decision.confidence_scale must be one of: unit, percent, grade")
+	}
+}
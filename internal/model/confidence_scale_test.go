@@ -0,0 +1,84 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestNormalizeConfidenceScale_UnitIsNoop(t *testing.T) {
+	for _, scale := range []string{"", model.ConfidenceScaleUnit} {
+		d := model.TraceDecision{Confidence: 0.7, ConfidenceScale: scale}
+		original, err := model.NormalizeConfidenceScale(&d)
+		require.NoError(t, err)
+		assert.Nil(t, original)
+		assert.InDelta(t, 0.7, d.Confidence, 1e-6)
+	}
+}
+
+func TestNormalizeConfidenceScale_Percent(t *testing.T) {
+	d := model.TraceDecision{
+		Confidence:      80,
+		ConfidenceScale: model.ConfidenceScalePercent,
+		ConfidenceLow:   ptr(float32(70)),
+		ConfidenceHigh:  ptr(float32(90)),
+	}
+	original, err := model.NormalizeConfidenceScale(&d)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, d.Confidence, 1e-6)
+	assert.InDelta(t, 0.7, *d.ConfidenceLow, 1e-6)
+	assert.InDelta(t, 0.9, *d.ConfidenceHigh, 1e-6)
+	assert.Equal(t, "percent", original["scale"])
+	assert.Equal(t, float32(80), original["value"])
+	assert.Equal(t, float32(70), original["low"])
+	assert.Equal(t, float32(90), original["high"])
+
+	// A second pass must not divide again.
+	again, err := model.NormalizeConfidenceScale(&d)
+	require.NoError(t, err)
+	assert.Nil(t, again)
+	assert.InDelta(t, 0.8, d.Confidence, 1e-6)
+}
+
+func TestNormalizeConfidenceScale_PercentOutOfRange(t *testing.T) {
+	d := model.TraceDecision{Confidence: 120, ConfidenceScale: model.ConfidenceScalePercent}
+	_, err := model.NormalizeConfidenceScale(&d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "between 0 and 100")
+
+	d = model.TraceDecision{Confidence: 50, ConfidenceScale: model.ConfidenceScalePercent, ConfidenceLow: ptr(float32(-1)), ConfidenceHigh: ptr(float32(60))}
+	_, err = model.NormalizeConfidenceScale(&d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "confidence_low")
+}
+
+func TestNormalizeConfidenceScale_Grade(t *testing.T) {
+	d := model.TraceDecision{ConfidenceScale: model.ConfidenceScaleGrade, ConfidenceGrade: " b+ "}
+	original, err := model.NormalizeConfidenceScale(&d)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, d.Confidence, 1e-6)
+	assert.Equal(t, model.ConfidenceScaleUnit, d.ConfidenceScale)
+	assert.Empty(t, d.ConfidenceGrade)
+	assert.Equal(t, map[string]any{"scale": "grade", "value": "B+"}, original)
+}
+
+func TestNormalizeConfidenceScale_Rejects(t *testing.T) {
+	cases := map[string]model.TraceDecision{
+		"unknown scale":       {Confidence: 0.5, ConfidenceScale: "likert"},
+		"missing grade":       {ConfidenceScale: model.ConfidenceScaleGrade},
+		"unknown grade":       {ConfidenceScale: model.ConfidenceScaleGrade, ConfidenceGrade: "E"},
+		"grade with value":    {Confidence: 0.5, ConfidenceScale: model.ConfidenceScaleGrade, ConfidenceGrade: "A"},
+		"grade with interval": {ConfidenceScale: model.ConfidenceScaleGrade, ConfidenceGrade: "A", ConfidenceLow: ptr(float32(0.1)), ConfidenceHigh: ptr(float32(0.9))},
+		"grade without scale": {Confidence: 0.5, ConfidenceGrade: "A"},
+		"grade with percent":  {Confidence: 50, ConfidenceScale: model.ConfidenceScalePercent, ConfidenceGrade: "A"},
+	}
+	for name, d := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := model.NormalizeConfidenceScale(&d)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConflictThreshold overrides the global conflict significance threshold for
+// one decision type within an org. When two decisions of different types are
+// scored, the lower of their effective thresholds applies.
+type ConflictThreshold struct {
+	OrgID                 uuid.UUID `json:"org_id"`
+	DecisionType          string    `json:"decision_type"`
+	SignificanceThreshold float64   `json:"significance_threshold"`
+	UpdatedBy             string    `json:"updated_by"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// SetConflictThresholdRequest is the body for
+// PUT /v1/conflict-thresholds/{decision_type}.
+type SetConflictThresholdRequest struct {
+	SignificanceThreshold float64 `json:"significance_threshold"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ContextSnapshot is a typed capture of the agent's runtime configuration at
+// decision time, kept for reproducibility audits. Unlike the free-form
+// agent_context, its fields are validated and its encoded size is capped by
+// the server (see ContextSnapshot.Truncate).
+type ContextSnapshot struct {
+	Model            string   `json:"model,omitempty"`
+	SystemPromptHash string   `json:"system_prompt_hash,omitempty"`
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	MaxTokens        *int     `json:"max_tokens,omitempty"`
+	Tools            []string `json:"tools,omitempty"`
+}
+
+const (
+	// MaxContextSnapshotFieldLen bounds every string in a snapshot, including
+	// each tool name.
+	MaxContextSnapshotFieldLen = 256
+
+	// MinContextSnapshotBytes is the smallest allowed snapshot size cap. A
+	// valid snapshot with no tools always fits within it, so truncation only
+	// ever drops tools.
+	MinContextSnapshotBytes = 1024
+)
+
+// Oversized context snapshot policies (AKASHI_CONTEXT_SNAPSHOT_OVERSIZE).
+const (
+	ContextSnapshotOversizeTruncate = "truncate"
+	ContextSnapshotOversizeReject   = "reject"
+)
+
+// Validate checks field lengths and sampling parameter ranges.
+func (s ContextSnapshot) Validate() error {
+	if len(s.Model) > MaxContextSnapshotFieldLen {
+		return fmt.Errorf("model exceeds maximum length of %d bytes", MaxContextSnapshotFieldLen)
+	}
+	if len(s.SystemPromptHash) > MaxContextSnapshotFieldLen {
+		return fmt.Errorf("system_prompt_hash exceeds maximum length of %d bytes", MaxContextSnapshotFieldLen)
+	}
+	if s.Temperature != nil && (*s.Temperature < 0 || *s.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if s.TopP != nil && (*s.TopP < 0 || *s.TopP > 1) {
+		return fmt.Errorf("top_p must be between 0 and 1")
+	}
+	if s.MaxTokens != nil && *s.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be >= 0")
+	}
+	for i, tool := range s.Tools {
+		if tool == "" {
+			return fmt.Errorf("tools[%d] must not be empty", i)
+		}
+		if len(tool) > MaxContextSnapshotFieldLen {
+			return fmt.Errorf("tools[%d] exceeds maximum length of %d bytes", i, MaxContextSnapshotFieldLen)
+		}
+	}
+	return nil
+}
+
+// Size returns the JSON-encoded size of s in bytes.
+func (s ContextSnapshot) Size() int {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// Truncate drops tools from the end of s, keeping as many as fit, until its
+// encoded size is at most maxBytes. Reports whether any tools were dropped.
+func (s *ContextSnapshot) Truncate(maxBytes int) bool {
+	if s.Size() <= maxBytes {
+		return false
+	}
+	tools := s.Tools
+	// Largest prefix of tools that fits; size grows with the prefix length.
+	keep := sort.Search(len(tools)+1, func(n int) bool {
+		trial := *s
+		trial.Tools = tools[:n]
+		return trial.Size() > maxBytes
+	}) - 1
+	s.Tools = tools[:max(keep, 0)]
+	return true
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextSnapshot_Validate(t *testing.T) {
+	temp := 0.7
+	require.NoError(t, ContextSnapshot{Model: "gpt-4o", Temperature: &temp, Tools: []string{"bash"}}.Validate())
+
+	badTemp := 2.5
+	assert.ErrorContains(t, ContextSnapshot{Temperature: &badTemp}.Validate(), "temperature")
+
+	badTopP := -0.1
+	assert.ErrorContains(t, ContextSnapshot{TopP: &badTopP}.Validate(), "top_p")
+
+	assert.ErrorContains(t, ContextSnapshot{Tools: []string{"bash", ""}}.Validate(), "tools[1]")
+
+	long := make([]byte, MaxContextSnapshotFieldLen+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	assert.ErrorContains(t, ContextSnapshot{SystemPromptHash: string(long)}.Validate(), "system_prompt_hash")
+}
+
+func TestContextSnapshot_Truncate(t *testing.T) {
+	tools := make([]string, 200)
+	for i := range tools {
+		tools[i] = fmt.Sprintf("tool_%03d", i)
+	}
+	s := ContextSnapshot{Model: "gpt-4o", SystemPromptHash: "sha256:abc", Tools: tools}
+	require.Greater(t, s.Size(), MinContextSnapshotBytes)
+
+	assert.True(t, s.Truncate(MinContextSnapshotBytes))
+	assert.LessOrEqual(t, s.Size(), MinContextSnapshotBytes)
+	assert.NotEmpty(t, s.Tools)
+	assert.Equal(t, tools[:len(s.Tools)], s.Tools, "truncation keeps a prefix of the tool list")
+
+	// Adding the next tool back would exceed the cap.
+	next := s
+	next.Tools = tools[:len(s.Tools)+1]
+	assert.Greater(t, next.Size(), MinContextSnapshotBytes)
+
+	// A snapshot that already fits is left alone.
+	small := ContextSnapshot{Model: "gpt-4o", Tools: []string{"bash"}}
+	assert.False(t, small.Truncate(MinContextSnapshotBytes))
+	assert.Equal(t, []string{"bash"}, small.Tools)
+}
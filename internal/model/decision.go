@@ -36,6 +36,16 @@ type Decision struct {
 	OutcomeEmbedding *pgvector.Vector `json:"-"` // Outcome-only embedding for semantic conflict detection.
 	Metadata         map[string]any   `json:"metadata"`
 
+	// EmbeddingModel names the model that produced Embedding and
+	// OutcomeEmbedding (migration 115). nil for decisions embedded before the
+	// column existed; see EmbeddingModelsCompatible.
+	EmbeddingModel *string `json:"embedding_model,omitempty"`
+
+	// Optional confidence interval around the point estimate (migration 104).
+	// Both are nil or both are set, with ConfidenceLow <= Confidence <= ConfidenceHigh.
+	ConfidenceLow  *float32 `json:"confidence_low,omitempty"`
+	ConfidenceHigh *float32 `json:"confidence_high,omitempty"`
+
 	// CompletenessScore (0.0-1.0) measures trace completeness at write time:
 	// whether the agent provided reasoning, alternatives, evidence, etc.
 	// It does NOT measure whether the decision was correct or adopted.
@@ -53,8 +63,19 @@ type Decision struct {
 	// Revision chain: ID of the decision this one supersedes.
 	SupersedesID *uuid.UUID `json:"supersedes_id,omitempty"`
 
+	// OutcomeFlipped is set on a revision whose outcome reverses the verdict
+	// of the decision it supersedes (see IsOutcomeFlip).
+	OutcomeFlipped bool `json:"outcome_flipped,omitempty"`
+
+	// BatchID groups decisions an agent made in a burst within one session
+	// (migration 116). nil when batching is disabled or no session was set.
+	BatchID *uuid.UUID `json:"batch_id,omitempty"`
+
 	// Tamper-evident SHA-256 content hash of canonical decision fields.
 	ContentHash string `json:"content_hash,omitempty"`
+	// HashVersion is the integrity.HashAlgorithms version that produced
+	// ContentHash (migration 128). 0 when unknown (lite mode).
+	HashVersion int `json:"hash_version,omitempty"`
 
 	// Bi-temporal columns.
 	ValidFrom       time.Time  `json:"valid_from"`
@@ -67,6 +88,12 @@ type Decision struct {
 	SessionID    *uuid.UUID     `json:"session_id,omitempty"`
 	AgentContext map[string]any `json:"agent_context,omitempty"`
 
+	// ContextSnapshot (migration 118) is the typed, size-capped runtime
+	// configuration the agent reported. ContextSnapshotTruncated is set when
+	// the server dropped tools to fit AKASHI_CONTEXT_SNAPSHOT_MAX_BYTES.
+	ContextSnapshot          *ContextSnapshot `json:"context_snapshot,omitempty"`
+	ContextSnapshotTruncated bool             `json:"context_snapshot_truncated,omitempty"`
+
 	// First-class attribution columns (migration 048/052): indexed fast-path for
 	// the three most-filtered context fields. Auto-computed from agent_context
 	// by generated columns; nil when the context fields were not provided.
@@ -77,6 +104,10 @@ type Decision struct {
 	// API key attribution: which managed key authenticated this decision.
 	APIKeyID *uuid.UUID `json:"api_key_id,omitempty"`
 
+	// Namespace partitions decisions within an org (e.g. prod vs staging).
+	// Precedent lookups and conflict detection never cross namespaces.
+	Namespace string `json:"namespace"`
+
 	// Joined data (populated by queries, not stored in decisions table).
 	Alternatives []Alternative `json:"alternatives,omitempty"`
 	Evidence     []Evidence    `json:"evidence,omitempty"`
@@ -96,10 +127,38 @@ type Decision struct {
 	PrecedentCitationCount    int          `json:"precedent_citation_count"`
 	ConflictFate              ConflictFate `json:"conflict_fate"`
 
+	// EffectiveConfidence is Confidence discounted by age under the org's
+	// precedent_decay policy. Only set on akashi_check / POST /v1/check
+	// results when a policy is configured.
+	EffectiveConfidence *float32 `json:"effective_confidence,omitempty"`
+
 	// Explicit outcome feedback (Spec 29): assessment counts from agents who
 	// observed whether this decision turned out to be correct.
 	// Populated on GET /v1/decisions/{id}; nil in list responses.
 	AssessmentSummary *AssessmentSummary `json:"assessment_summary,omitempty"`
+
+	// Flags are derived triage facets, computed at query time. Only populated
+	// when the caller opts in with include=flags; nil otherwise.
+	Flags *DecisionFlags `json:"flags,omitempty"`
+}
+
+// LowConfidenceThreshold is the confidence below which a decision is flagged
+// low_confidence. It matches the bottom of the 0.4-0.8 band agents are told
+// most decisions should fall in.
+const LowConfidenceThreshold float32 = 0.4
+
+// DecisionFlags are boolean facets derived from a decision's confidence,
+// evidence, revision chain, and conflict state. None are stored.
+type DecisionFlags struct {
+	// Contested is true when the decision is part of at least one open conflict.
+	Contested bool `json:"contested"`
+	// Revised is true when the decision supersedes another or has been superseded.
+	Revised bool `json:"revised"`
+	// LowConfidence is true when confidence is below LowConfidenceThreshold.
+	LowConfidence bool `json:"low_confidence"`
+	// WeaklyEvidenced is true when the decision has no evidence other than
+	// user_input (including when it has no evidence at all).
+	WeaklyEvidenced bool `json:"weakly_evidenced"`
 }
 
 // Alternative represents an option considered for a decision. Immutable.
@@ -138,6 +197,17 @@ type Evidence struct {
 	Embedding      *pgvector.Vector   `json:"-"`
 	Metadata       map[string]any     `json:"metadata"`
 	CreatedAt      time.Time          `json:"created_at"`
+
+	// Blob references content stored out of line in evidence_blobs. It is set
+	// only when the content was not rehydrated, in which case Content is empty.
+	Blob *EvidenceBlobRef `json:"blob,omitempty"`
+}
+
+// EvidenceBlobRef identifies large evidence content stored once per org and
+// shared by every evidence item with the same content.
+type EvidenceBlobRef struct {
+	Hash      string `json:"hash"` // hex SHA-256 of the content
+	SizeBytes int    `json:"size_bytes"`
 }
 
 // ConflictFate tracks how a decision fared in resolved conflict pairs.
@@ -157,18 +227,23 @@ type OutcomeSignals struct {
 	ConflictCount             int
 }
 
-// ConflictKind indicates whether a conflict is between agents or self-contradiction.
+// ConflictKind indicates whether a conflict is between agents, a
+// self-contradiction, or a minority drifting from an established consensus.
 type ConflictKind string
 
 const (
 	ConflictKindCrossAgent        ConflictKind = "cross_agent"
 	ConflictKindSelfContradiction ConflictKind = "self_contradiction"
+	// ConflictKindConsensusDrift: a small minority of related decisions
+	// reached a different outcome from the majority.
+	ConflictKindConsensusDrift ConflictKind = "consensus_drift"
 )
 
 // ValidConflictKinds is the set of recognized conflict_kind values.
 var ValidConflictKinds = map[ConflictKind]bool{
 	ConflictKindCrossAgent:        true,
 	ConflictKindSelfContradiction: true,
+	ConflictKindConsensusDrift:    true,
 }
 
 // ValidConflictKind reports whether k is a recognized conflict kind.
@@ -176,10 +251,36 @@ func ValidConflictKind(k string) bool {
 	return ValidConflictKinds[ConflictKind(k)]
 }
 
+// ConflictReasonCode names the signal that dominated a conflict's score.
+type ConflictReasonCode string
+
+const (
+	// ConflictReasonOutcomeOpposite: the two decisions reached different verdicts.
+	ConflictReasonOutcomeOpposite ConflictReasonCode = "outcome_opposite"
+	// ConflictReasonConfidenceDivergence: the verdicts largely agree but the
+	// decisions hold them with very different confidence.
+	ConflictReasonConfidenceDivergence ConflictReasonCode = "confidence_divergence"
+	// ConflictReasonSameInputDifferentOutcome: near-identical questions were
+	// answered differently.
+	ConflictReasonSameInputDifferentOutcome ConflictReasonCode = "same_input_different_outcome"
+)
+
+// ValidConflictReasonCodes is the set of recognized reason_code values.
+var ValidConflictReasonCodes = map[ConflictReasonCode]bool{
+	ConflictReasonOutcomeOpposite:           true,
+	ConflictReasonConfidenceDivergence:      true,
+	ConflictReasonSameInputDifferentOutcome: true,
+}
+
+// ValidConflictReasonCode reports whether c is a recognized reason code.
+func ValidConflictReasonCode(c string) bool {
+	return ValidConflictReasonCodes[ConflictReasonCode(c)]
+}
+
 // DecisionConflict represents a detected conflict between two decisions.
 type DecisionConflict struct {
 	ID                uuid.UUID    `json:"id"`
-	ConflictKind      ConflictKind `json:"conflict_kind"` // cross_agent or self_contradiction
+	ConflictKind      ConflictKind `json:"conflict_kind"` // cross_agent, self_contradiction, or consensus_drift
 	DecisionAID       uuid.UUID    `json:"decision_a_id"`
 	DecisionBID       uuid.UUID    `json:"decision_b_id"`
 	OrgID             uuid.UUID    `json:"org_id"`
@@ -243,6 +344,27 @@ type DecisionConflict struct {
 	ProjectA *string `json:"project_a,omitempty"`
 	ProjectB *string `json:"project_b,omitempty"`
 
+	// Suggestion (migration 110): LLM-written explanation and suggested
+	// resolution. Nil until the suggestion worker has processed the conflict.
+	Suggestion *ConflictSuggestion `json:"suggestion,omitempty"`
+
+	// ReasonCode (migration 117): which signal dominated the score; one of
+	// the ConflictReason* values. Nil for conflicts scored before the column
+	// existed, until they are rescored.
+	ReasonCode *string `json:"reason_code,omitempty"`
+
+	// MinorityIDs (migration 126): for consensus_drift conflicts, every
+	// decision in the dissenting minority. DecisionAID is the majority
+	// exemplar and DecisionBID the minority exemplar. Nil for other kinds.
+	MinorityIDs []uuid.UUID `json:"minority_ids,omitempty"`
+
+	// CounterpartRedacted is set by GET /v1/decisions/{id}/conflicts when the
+	// caller cannot read the other decision's agent: every field of that side
+	// except its decision ID is blanked, along with the explanation,
+	// suggestion, resolution note, and minority IDs that quote or identify
+	// it. Not persisted.
+	CounterpartRedacted bool `json:"counterpart_redacted,omitempty"`
+
 	// EarliestPossibleAt is max(decision_a.transaction_time, decision_b.transaction_time).
 	// A conflict cannot exist before both decisions exist. Used as first_detected_at
 	// when creating a new conflict group, instead of now().
@@ -250,6 +372,15 @@ type DecisionConflict struct {
 	EarliestPossibleAt *time.Time `json:"-"`
 }
 
+// ConflictSuggestion is an LLM-written triage note for a conflict: why the two
+// decisions conflict and how the conflict might be resolved. Advisory only.
+type ConflictSuggestion struct {
+	Explanation string    `json:"explanation"`
+	Resolution  string    `json:"resolution"`
+	Model       string    `json:"model"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
 // ConflictGroup is a canonical conflict cluster scoped to a semantic topic
 // within an (org, normalized-agent-pair, conflict-kind, decision-type) bucket.
 // Multiple groups may exist for the same agent pair when they disagree about
@@ -0,0 +1,206 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Payload property types accepted in EventSchema. These mirror the JSON
+// Schema primitive type names so org extensions read like ordinary schemas.
+const (
+	SchemaTypeString  = "string"
+	SchemaTypeNumber  = "number"
+	SchemaTypeInteger = "integer"
+	SchemaTypeBoolean = "boolean"
+	SchemaTypeObject  = "object"
+	SchemaTypeArray   = "array"
+)
+
+// MaxEventSchemaProperties caps the number of properties a single org-defined
+// event schema may declare.
+const MaxEventSchemaProperties = 64
+
+// EventPropertySchema constrains a single top-level payload key.
+type EventPropertySchema struct {
+	Type string `json:"type"`
+}
+
+// EventSchema is the JSON Schema subset used to validate AgentEvent payloads:
+// a set of required top-level keys plus a type constraint per declared key.
+// Keys not listed in Properties are allowed and unchecked.
+type EventSchema struct {
+	Required   []string                       `json:"required,omitempty"`
+	Properties map[string]EventPropertySchema `json:"properties,omitempty"`
+}
+
+// Validate checks that an org-supplied schema is well-formed.
+func (s EventSchema) Validate() error {
+	if len(s.Properties) > MaxEventSchemaProperties {
+		return fmt.Errorf("properties must not exceed %d entries", MaxEventSchemaProperties)
+	}
+	for _, key := range s.Required {
+		if key == "" {
+			return fmt.Errorf("required must not contain empty keys")
+		}
+	}
+	for key, prop := range s.Properties {
+		if key == "" {
+			return fmt.Errorf("properties must not contain empty keys")
+		}
+		switch prop.Type {
+		case SchemaTypeString, SchemaTypeNumber, SchemaTypeInteger,
+			SchemaTypeBoolean, SchemaTypeObject, SchemaTypeArray:
+		default:
+			return fmt.Errorf("properties.%s.type must be one of: string, number, integer, boolean, object, array", key)
+		}
+	}
+	return nil
+}
+
+// check validates a payload against the schema. The returned error names the
+// offending key as payload.<key> so callers can prefix it with the event index.
+func (s EventSchema) check(payload map[string]any) error {
+	for _, key := range s.Required {
+		if _, ok := payload[key]; !ok {
+			return fmt.Errorf("payload.%s is required", key)
+		}
+	}
+	// Iterate in sorted order so the reported error is deterministic.
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		v, ok := payload[key]
+		if !ok || v == nil {
+			continue
+		}
+		want := s.Properties[key].Type
+		if !matchesSchemaType(v, want) {
+			return fmt.Errorf("payload.%s must be of type %s", key, want)
+		}
+	}
+	return nil
+}
+
+// matchesSchemaType reports whether a value decoded by encoding/json satisfies
+// the given schema type.
+func matchesSchemaType(v any, typ string) bool {
+	switch typ {
+	case SchemaTypeString:
+		_, ok := v.(string)
+		return ok
+	case SchemaTypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case SchemaTypeInteger:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case SchemaTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case SchemaTypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	case SchemaTypeArray:
+		_, ok := v.([]any)
+		return ok
+	default:
+		return false
+	}
+}
+
+// builtinEventSchemas are the payload contracts for the known event types.
+// They are deliberately minimal: only keys that downstream consumers of the
+// event stream rely on are required.
+var builtinEventSchemas = map[EventType]EventSchema{
+	EventDecisionStarted: {
+		Properties: map[string]EventPropertySchema{
+			"decision_type": {Type: SchemaTypeString},
+		},
+	},
+	EventDecisionMade: {
+		Required: []string{"outcome"},
+		Properties: map[string]EventPropertySchema{
+			"outcome":       {Type: SchemaTypeString},
+			"decision_type": {Type: SchemaTypeString},
+			"confidence":    {Type: SchemaTypeNumber},
+			"reasoning":     {Type: SchemaTypeString},
+		},
+	},
+	EventDecisionRevised: {
+		Required: []string{"decision_id"},
+		Properties: map[string]EventPropertySchema{
+			"decision_id": {Type: SchemaTypeString},
+		},
+	},
+	EventDecisionSuperseded: {
+		Required: []string{"decision_id"},
+		Properties: map[string]EventPropertySchema{
+			"decision_id": {Type: SchemaTypeString},
+		},
+	},
+	EventDecisionRetracted: {
+		Required: []string{"decision_id"},
+		Properties: map[string]EventPropertySchema{
+			"decision_id": {Type: SchemaTypeString},
+			"reason":      {Type: SchemaTypeString},
+		},
+	},
+	EventDecisionErased: {
+		Required: []string{"decision_id"},
+		Properties: map[string]EventPropertySchema{
+			"decision_id": {Type: SchemaTypeString},
+		},
+	},
+	EventToolCallStarted: {
+		Required: []string{"tool"},
+		Properties: map[string]EventPropertySchema{
+			"tool": {Type: SchemaTypeString},
+		},
+	},
+	EventToolCallCompleted: {
+		Required: []string{"tool"},
+		Properties: map[string]EventPropertySchema{
+			"tool": {Type: SchemaTypeString},
+		},
+	},
+	EventAgentHandoff: {
+		Required: []string{"to_agent_id"},
+		Properties: map[string]EventPropertySchema{
+			"to_agent_id": {Type: SchemaTypeString},
+		},
+	},
+	EventConflictDetected: {
+		Properties: map[string]EventPropertySchema{
+			"conflict_id": {Type: SchemaTypeString},
+		},
+	},
+}
+
+// BuiltinEventSchema returns the built-in payload schema for an event type.
+// The run lifecycle events and unknown types have no built-in schema.
+func BuiltinEventSchema(t EventType) (EventSchema, bool) {
+	s, ok := builtinEventSchemas[t]
+	return s, ok
+}
+
+// ValidateEventPayload checks an event payload against the built-in schema for
+// its type and, if present, the org's extension schema for the same type. Both
+// must pass: org schemas can add constraints but cannot relax built-in ones.
+// Event types with no schema of either kind are accepted as-is.
+func ValidateEventPayload(t EventType, payload map[string]any, orgSchemas map[EventType]EventSchema) error {
+	if s, ok := builtinEventSchemas[t]; ok {
+		if err := s.check(payload); err != nil {
+			return err
+		}
+	}
+	if s, ok := orgSchemas[t]; ok {
+		if err := s.check(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEventPayload_Builtin(t *testing.T) {
+	t.Run("decision made requires outcome", func(t *testing.T) {
+		err := ValidateEventPayload(EventDecisionMade, map[string]any{"reasoning": "x"}, nil)
+		require.Error(t, err)
+		assert.Equal(t, "payload.outcome is required", err.Error())
+	})
+
+	t.Run("decision made outcome must be string", func(t *testing.T) {
+		err := ValidateEventPayload(EventDecisionMade, map[string]any{"outcome": 42.0}, nil)
+		require.Error(t, err)
+		assert.Equal(t, "payload.outcome must be of type string", err.Error())
+	})
+
+	t.Run("decision made valid", func(t *testing.T) {
+		assert.NoError(t, ValidateEventPayload(EventDecisionMade, map[string]any{
+			"outcome":    "approved",
+			"confidence": 0.8,
+			"extra":      []any{"unchecked"},
+		}, nil))
+	})
+
+	t.Run("null optional property is accepted", func(t *testing.T) {
+		assert.NoError(t, ValidateEventPayload(EventDecisionMade, map[string]any{
+			"outcome":    "approved",
+			"confidence": nil,
+		}, nil))
+	})
+
+	t.Run("run lifecycle has no schema", func(t *testing.T) {
+		_, ok := BuiltinEventSchema(EventAgentRunStarted)
+		assert.False(t, ok)
+		assert.NoError(t, ValidateEventPayload(EventAgentRunStarted, nil, nil))
+	})
+
+	t.Run("unknown type is accepted", func(t *testing.T) {
+		assert.NoError(t, ValidateEventPayload("CustomThing", map[string]any{"a": 1.0}, nil))
+	})
+}
+
+func TestValidateEventPayload_OrgExtensions(t *testing.T) {
+	org := map[EventType]EventSchema{
+		EventDecisionMade: {
+			Required:   []string{"ticket"},
+			Properties: map[string]EventPropertySchema{"ticket": {Type: SchemaTypeInteger}},
+		},
+		"DeployApproved": {Required: []string{"env"}},
+	}
+
+	t.Run("built-in still applies", func(t *testing.T) {
+		err := ValidateEventPayload(EventDecisionMade, map[string]any{"ticket": 7.0}, org)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "payload.outcome")
+	})
+
+	t.Run("org constraint applies", func(t *testing.T) {
+		err := ValidateEventPayload(EventDecisionMade, map[string]any{"outcome": "ok"}, org)
+		require.Error(t, err)
+		assert.Equal(t, "payload.ticket is required", err.Error())
+	})
+
+	t.Run("integer rejects fractional", func(t *testing.T) {
+		err := ValidateEventPayload(EventDecisionMade, map[string]any{"outcome": "ok", "ticket": 1.5}, org)
+		require.Error(t, err)
+		assert.Equal(t, "payload.ticket must be of type integer", err.Error())
+	})
+
+	t.Run("custom type", func(t *testing.T) {
+		assert.Error(t, ValidateEventPayload("DeployApproved", map[string]any{}, org))
+		assert.NoError(t, ValidateEventPayload("DeployApproved", map[string]any{"env": "prod"}, org))
+	})
+}
+
+func TestEventSchema_Validate(t *testing.T) {
+	assert.NoError(t, EventSchema{
+		Required:   []string{"a"},
+		Properties: map[string]EventPropertySchema{"a": {Type: SchemaTypeObject}, "b": {Type: SchemaTypeArray}},
+	}.Validate())
+
+	assert.Error(t, EventSchema{Required: []string{""}}.Validate())
+	assert.Error(t, EventSchema{Properties: map[string]EventPropertySchema{"a": {Type: "date"}}}.Validate())
+	assert.Error(t, EventSchema{Properties: map[string]EventPropertySchema{"": {Type: SchemaTypeString}}}.Validate())
+
+	tooMany := make(map[string]EventPropertySchema, MaxEventSchemaProperties+1)
+	for i := 0; i <= MaxEventSchemaProperties; i++ {
+		tooMany[string(rune('a'+i%26))+string(rune('A'+i/26))] = EventPropertySchema{Type: SchemaTypeString}
+	}
+	assert.Error(t, EventSchema{Properties: tooMany}.Validate())
+}
@@ -0,0 +1,33 @@
+package model
+
+import "fmt"
+
+// DefaultNamespace is the namespace used when a request does not name one.
+// Every decision written before namespaces existed belongs to it.
+const DefaultNamespace = "default"
+
+// MaxNamespaceLen is the maximum length of a namespace name.
+const MaxNamespaceLen = 64
+
+// ValidateNamespace checks that a namespace name conforms to the allowed
+// format: 1-64 characters of lowercase ASCII letters, digits, hyphens, and
+// underscores, starting with a letter or digit.
+func ValidateNamespace(ns string) error {
+	if len(ns) == 0 {
+		return fmt.Errorf("namespace must not be empty")
+	}
+	if len(ns) > MaxNamespaceLen {
+		return fmt.Errorf("namespace must be at most %d characters", MaxNamespaceLen)
+	}
+	for i := 0; i < len(ns); i++ {
+		c := ns[i]
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+		if i == 0 && !isAlnum {
+			return fmt.Errorf("namespace must start with a lowercase letter or digit")
+		}
+		if !isAlnum && c != '-' && c != '_' {
+			return fmt.Errorf("namespace contains invalid character at position %d: %q", i, c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	valid := []string{"default", "prod", "staging-eu", "team_a", "0day", strings.Repeat("a", model.MaxNamespaceLen)}
+	for _, ns := range valid {
+		assert.NoError(t, model.ValidateNamespace(ns), ns)
+	}
+
+	invalid := []string{"", "Prod", "-prod", "_prod", "prod env", "prod/eu", "prod.eu", strings.Repeat("a", model.MaxNamespaceLen+1)}
+	for _, ns := range invalid {
+		assert.Error(t, model.ValidateNamespace(ns), ns)
+	}
+}
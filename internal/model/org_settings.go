@@ -2,6 +2,9 @@ package model
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -60,9 +63,427 @@ func (p *ConflictResolutionPolicy) Validate() error {
 	return nil
 }
 
+// QuotaLimits caps decision writes per UTC calendar day and month.
+// Zero means unlimited.
+type QuotaLimits struct {
+	Daily   int64 `json:"daily,omitempty"`
+	Monthly int64 `json:"monthly,omitempty"`
+}
+
+func (l QuotaLimits) validate(field string) error {
+	if l.Daily < 0 {
+		return fmt.Errorf("%s.daily must be >= 0 (0 = unlimited)", field)
+	}
+	if l.Monthly < 0 {
+		return fmt.Errorf("%s.monthly must be >= 0 (0 = unlimited)", field)
+	}
+	return nil
+}
+
+// DecisionQuotaPolicy bounds the total number of decisions written, as a
+// guardrail against runaway or compromised agents. Rate limiting bounds
+// request rate; quotas bound total volume.
+type DecisionQuotaPolicy struct {
+	// PerAgent applies to every agent without an override.
+	PerAgent QuotaLimits `json:"per_agent"`
+	// Org caps the sum across all agents in the org.
+	Org QuotaLimits `json:"org"`
+	// AgentOverrides replaces PerAgent for specific agent IDs.
+	AgentOverrides map[string]QuotaLimits `json:"agent_overrides,omitempty"`
+}
+
+// Validate checks that the policy is well-formed.
+func (p *DecisionQuotaPolicy) Validate() error {
+	if err := p.PerAgent.validate("per_agent"); err != nil {
+		return err
+	}
+	if err := p.Org.validate("org"); err != nil {
+		return err
+	}
+	for agentID, l := range p.AgentOverrides {
+		if err := ValidateAgentID(agentID); err != nil {
+			return fmt.Errorf("agent_overrides: %w", err)
+		}
+		if err := l.validate("agent_overrides." + agentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LimitsForAgent returns the effective per-agent limits for agentID.
+func (p *DecisionQuotaPolicy) LimitsForAgent(agentID string) QuotaLimits {
+	if l, ok := p.AgentOverrides[agentID]; ok {
+		return l
+	}
+	return p.PerAgent
+}
+
+// DecisionUsage is the decision count for the current UTC day and month.
+type DecisionUsage struct {
+	AgentDaily   int64 `json:"agent_daily"`
+	AgentMonthly int64 `json:"agent_monthly"`
+	OrgDaily     int64 `json:"org_daily"`
+	OrgMonthly   int64 `json:"org_monthly"`
+}
+
+// AgentQuotaStatus reports an agent's current usage against its effective
+// limits, as shown by GET /v1/agents/{agent_id}/stats.
+type AgentQuotaStatus struct {
+	Usage     DecisionUsage `json:"usage"`
+	Limits    QuotaLimits   `json:"limits"`
+	OrgLimits QuotaLimits   `json:"org_limits"`
+}
+
+// QuotaExceededError describes the first quota a write would exceed.
+type QuotaExceededError struct {
+	Scope  string `json:"scope"`  // "agent" or "org"
+	Period string `json:"period"` // "daily" or "monthly"
+	Limit  int64  `json:"limit"`
+	Used   int64  `json:"used"`
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s %s decision quota exceeded (%d of %d used)", e.Scope, e.Period, e.Used, e.Limit)
+}
+
+// Check returns a QuotaExceededError if writing one more decision for agentID
+// would exceed any configured limit, or nil if the write is allowed. Agent
+// limits are checked before org limits, daily before monthly.
+func (p *DecisionQuotaPolicy) Check(agentID string, u DecisionUsage) *QuotaExceededError {
+	agent := p.LimitsForAgent(agentID)
+	for _, c := range []struct {
+		scope, period string
+		limit, used   int64
+	}{
+		{"agent", "daily", agent.Daily, u.AgentDaily},
+		{"agent", "monthly", agent.Monthly, u.AgentMonthly},
+		{"org", "daily", p.Org.Daily, u.OrgDaily},
+		{"org", "monthly", p.Org.Monthly, u.OrgMonthly},
+	} {
+		if c.limit > 0 && c.used >= c.limit {
+			return &QuotaExceededError{Scope: c.scope, Period: c.period, Limit: c.limit, Used: c.used}
+		}
+	}
+	return nil
+}
+
+// ReviewRoute sends review flags on decisions by agents carrying Tag to one
+// of Reviewers.
+type ReviewRoute struct {
+	Tag       string   `json:"tag"`
+	Reviewers []string `json:"reviewers"`
+}
+
+// ReviewRoutingPolicy assigns flagged decisions to reviewer agents based on
+// the producing agent's tags. Routes are tried in order; the first route
+// whose tag the agent carries wins. DefaultReviewers applies when no route
+// matches.
+type ReviewRoutingPolicy struct {
+	Routes           []ReviewRoute `json:"routes"`
+	DefaultReviewers []string      `json:"default_reviewers,omitempty"`
+}
+
+// Validate checks that the policy is well-formed.
+func (p *ReviewRoutingPolicy) Validate() error {
+	for i, r := range p.Routes {
+		if r.Tag == "" {
+			return fmt.Errorf("routes[%d].tag is required", i)
+		}
+		if len(r.Reviewers) == 0 {
+			return fmt.Errorf("routes[%d].reviewers must not be empty", i)
+		}
+		for _, id := range r.Reviewers {
+			if err := ValidateAgentID(id); err != nil {
+				return fmt.Errorf("routes[%d].reviewers: %w", i, err)
+			}
+		}
+	}
+	for _, id := range p.DefaultReviewers {
+		if err := ValidateAgentID(id); err != nil {
+			return fmt.Errorf("default_reviewers: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReviewerFor returns the reviewer for a decision made by agentID carrying
+// tags, or "" if no route applies. An agent is never assigned to review its
+// own decision; the next reviewer on the matching route is used instead.
+func (p *ReviewRoutingPolicy) ReviewerFor(agentID string, tags []string) string {
+	pick := func(reviewers []string) string {
+		for _, r := range reviewers {
+			if r != agentID {
+				return r
+			}
+		}
+		return ""
+	}
+	for _, route := range p.Routes {
+		if !slices.Contains(tags, route.Tag) {
+			continue
+		}
+		if r := pick(route.Reviewers); r != "" {
+			return r
+		}
+	}
+	return pick(p.DefaultReviewers)
+}
+
+// RunReviewGatePolicy blocks marking a run completed while decisions in it
+// have pending review flags. Agents and DecisionTypes narrow the gate; empty
+// means every agent or every decision type respectively. Failing a run is
+// never blocked.
+type RunReviewGatePolicy struct {
+	Agents        []string `json:"agents,omitempty"`
+	DecisionTypes []string `json:"decision_types,omitempty"`
+}
+
+// Validate checks that the policy is well-formed.
+func (p *RunReviewGatePolicy) Validate() error {
+	for _, id := range p.Agents {
+		if err := ValidateAgentID(id); err != nil {
+			return fmt.Errorf("agents: %w", err)
+		}
+	}
+	for i, dt := range p.DecisionTypes {
+		if strings.TrimSpace(dt) == "" {
+			return fmt.Errorf("decision_types[%d] must not be empty", i)
+		}
+	}
+	return nil
+}
+
+// AppliesToAgent reports whether runs by agentID are gated.
+func (p *RunReviewGatePolicy) AppliesToAgent(agentID string) bool {
+	return len(p.Agents) == 0 || slices.Contains(p.Agents, agentID)
+}
+
+// PrecedentDecayPolicy discounts old precedents when ranking akashi_check
+// results: effective_confidence = confidence * 0.5^(age / half-life).
+// Half-lives are in days; DecisionTypes overrides DefaultHalfLifeDays per
+// decision type. A half-life of 0 means that type does not decay.
+type PrecedentDecayPolicy struct {
+	DefaultHalfLifeDays float64            `json:"default_half_life_days,omitempty"`
+	DecisionTypes       map[string]float64 `json:"decision_types,omitempty"`
+}
+
+// Validate checks that the policy is well-formed.
+func (p *PrecedentDecayPolicy) Validate() error {
+	if p.DefaultHalfLifeDays < 0 {
+		return fmt.Errorf("default_half_life_days must be >= 0")
+	}
+	for dt, days := range p.DecisionTypes {
+		if strings.TrimSpace(dt) == "" {
+			return fmt.Errorf("decision_types keys must not be empty")
+		}
+		if days < 0 {
+			return fmt.Errorf("decision_types[%q] must be >= 0", dt)
+		}
+	}
+	return nil
+}
+
+// HalfLife returns the decay half-life for decisionType, or 0 for no decay.
+func (p *PrecedentDecayPolicy) HalfLife(decisionType string) time.Duration {
+	days, ok := p.DecisionTypes[decisionType]
+	if !ok {
+		days = p.DefaultHalfLifeDays
+	}
+	return time.Duration(days * float64(24*time.Hour))
+}
+
+// EffectiveConfidence returns confidence decayed by the age of a decision of
+// decisionType. Non-positive ages and types without a half-life are returned
+// unchanged.
+func (p *PrecedentDecayPolicy) EffectiveConfidence(confidence float32, decisionType string, age time.Duration) float32 {
+	halfLife := p.HalfLife(decisionType)
+	if halfLife <= 0 || age <= 0 {
+		return confidence
+	}
+	return float32(float64(confidence) * math.Exp2(-age.Hours()/halfLife.Hours()))
+}
+
+// MinAlternativesPolicy maps a decision type to the minimum number of
+// alternatives a trace of that type must record. Types not listed, and
+// types mapped to 0, have no minimum.
+type MinAlternativesPolicy map[string]int
+
+// Validate checks that the policy is well-formed.
+func (p MinAlternativesPolicy) Validate() error {
+	for dt, n := range p {
+		if strings.TrimSpace(dt) == "" {
+			return fmt.Errorf("keys must be non-empty decision types")
+		}
+		if n < 0 {
+			return fmt.Errorf("%q must be >= 0", dt)
+		}
+	}
+	return nil
+}
+
+// Check returns an error when a decision of decisionType recording got
+// alternatives falls short of the configured minimum.
+func (p MinAlternativesPolicy) Check(decisionType string, got int) error {
+	if required := p[decisionType]; got < required {
+		return fmt.Errorf("decision_type %q requires at least %d alternatives, got %d", decisionType, required, got)
+	}
+	return nil
+}
+
+// IncludeNone, as the only include value, requests the lean response with
+// no optional data, overriding any configured default.
+const IncludeNone = "none"
+
+// Read endpoints whose optional includes can be defaulted per org.
+const (
+	IncludeEndpointGetDecision     = "get_decision"     // GET /v1/decisions/{id}
+	IncludeEndpointDecisionsRecent = "decisions_recent" // GET /v1/decisions/recent
+	IncludeEndpointQuery           = "query"            // POST /v1/query
+)
+
+// IncludeOptions lists the include values each endpoint accepts.
+var IncludeOptions = map[string][]string{
+	IncludeEndpointGetDecision:     {"alternatives", "evidence"},
+	IncludeEndpointDecisionsRecent: {"evidence", "flags"},
+	IncludeEndpointQuery:           {"alternatives", "evidence", "flags"},
+}
+
+// DefaultIncludesPolicy sets the includes a read endpoint applies when the
+// request does not specify any. A nil list keeps the built-in default;
+// ["none"] makes the lean response the default. Requests always win,
+// including an explicit include=none.
+type DefaultIncludesPolicy struct {
+	GetDecision     []string `json:"get_decision,omitempty"`
+	DecisionsRecent []string `json:"decisions_recent,omitempty"`
+	Query           []string `json:"query,omitempty"`
+}
+
+// Validate checks that every list holds only values its endpoint accepts.
+func (p *DefaultIncludesPolicy) Validate() error {
+	for _, endpoint := range []string{IncludeEndpointGetDecision, IncludeEndpointDecisionsRecent, IncludeEndpointQuery} {
+		values := p.For(endpoint)
+		for _, v := range values {
+			if v == IncludeNone {
+				if len(values) > 1 {
+					return fmt.Errorf("%s: %q cannot be combined with other values", endpoint, IncludeNone)
+				}
+				continue
+			}
+			if !slices.Contains(IncludeOptions[endpoint], v) {
+				return fmt.Errorf("%s: unknown include %q (must be one of %s)",
+					endpoint, v, strings.Join(IncludeOptions[endpoint], ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// For returns the configured default for endpoint, or nil when unset.
+func (p *DefaultIncludesPolicy) For(endpoint string) []string {
+	switch endpoint {
+	case IncludeEndpointGetDecision:
+		return p.GetDecision
+	case IncludeEndpointDecisionsRecent:
+		return p.DecisionsRecent
+	case IncludeEndpointQuery:
+		return p.Query
+	}
+	return nil
+}
+
+// MCPToolNames lists the tools the MCP server exposes, in registration order.
+var MCPToolNames = []string{
+	"akashi_check", "akashi_trace", "akashi_query", "akashi_conflicts",
+	"akashi_resolve", "akashi_assess", "akashi_stats", "akashi_supersede",
+	"akashi_why", "akashi_timeline",
+}
+
+// MCPToolsPolicy hides MCP tools from every agent in the org. Disabled tools
+// are left out of tools/list and refused if called anyway.
+type MCPToolsPolicy struct {
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// Validate checks that every disabled tool is a known MCP tool.
+func (p *MCPToolsPolicy) Validate() error {
+	for _, name := range p.Disabled {
+		if !slices.Contains(MCPToolNames, name) {
+			return fmt.Errorf("disabled: unknown tool %q (must be one of %s)", name, strings.Join(MCPToolNames, ", "))
+		}
+	}
+	return nil
+}
+
+// IsDisabled reports whether the org disabled the named tool.
+func (p *MCPToolsPolicy) IsDisabled(name string) bool {
+	return p != nil && slices.Contains(p.Disabled, name)
+}
+
+// ConfigOverridesPolicy overrides process-wide configuration for one org.
+// Nil fields fall back to the server's environment configuration.
+type ConfigOverridesPolicy struct {
+	// ConflictSignificanceThreshold replaces AKASHI_CONFLICT_SIGNIFICANCE_THRESHOLD
+	// for the org's decisions. Per-decision-type thresholds still take precedence.
+	ConflictSignificanceThreshold *float64 `json:"conflict_significance_threshold,omitempty"`
+	// EventRetentionDays replaces AKASHI_EVENT_RETENTION for the org's raw
+	// events. 0 keeps them forever.
+	EventRetentionDays *int `json:"event_retention_days,omitempty"`
+}
+
+// Validate checks that the overrides are in range.
+func (p *ConfigOverridesPolicy) Validate() error {
+	if t := p.ConflictSignificanceThreshold; t != nil && (*t <= 0 || *t > 1) {
+		return fmt.Errorf("conflict_significance_threshold must be in (0, 1]")
+	}
+	if d := p.EventRetentionDays; d != nil && *d < 0 {
+		return fmt.Errorf("event_retention_days must be >= 0")
+	}
+	return nil
+}
+
+// SignificanceThreshold returns the org's conflict significance threshold,
+// or global when the org does not override it.
+func (p *ConfigOverridesPolicy) SignificanceThreshold(global float64) float64 {
+	if p == nil || p.ConflictSignificanceThreshold == nil {
+		return global
+	}
+	return *p.ConflictSignificanceThreshold
+}
+
+// OrgSettingConfigOverrides is the org_settings key holding a
+// ConfigOverridesPolicy.
+const OrgSettingConfigOverrides = "config_overrides"
+
 // OrgSettingsData is the JSONB payload stored in org_settings.settings.
 type OrgSettingsData struct {
 	ConflictResolution *ConflictResolutionPolicy `json:"conflict_resolution,omitempty"`
+	// EventSchemas extends the built-in event payload schemas. Applied only
+	// when AKASHI_EVENT_SCHEMA_VALIDATION is enabled.
+	EventSchemas map[EventType]EventSchema `json:"event_schemas,omitempty"`
+	// DecisionQuota caps decision writes per agent and per org. Nil = unlimited.
+	DecisionQuota *DecisionQuotaPolicy `json:"decision_quota,omitempty"`
+	// ReviewRouting assigns review flags to reviewers by agent tag.
+	// Nil = flags are unassigned.
+	ReviewRouting *ReviewRoutingPolicy `json:"review_routing,omitempty"`
+	// RunReviewGate requires pending review flags to be cleared before a
+	// run can complete. Nil = runs complete regardless of flags.
+	RunReviewGate *RunReviewGatePolicy `json:"run_review_gate,omitempty"`
+	// PrecedentDecay ranks check precedents by time-decayed confidence.
+	// Nil = precedents keep their raw confidence.
+	PrecedentDecay *PrecedentDecayPolicy `json:"precedent_decay,omitempty"`
+	// DefaultIncludes sets per-endpoint includes for requests that specify
+	// none. Nil = built-in defaults.
+	DefaultIncludes *DefaultIncludesPolicy `json:"default_includes,omitempty"`
+	// MCPTools disables MCP tools for the org. Nil = every tool the
+	// caller's role permits.
+	MCPTools *MCPToolsPolicy `json:"mcp_tools,omitempty"`
+	// MinAlternatives rejects traces of the listed decision types that
+	// record fewer alternatives than required. Nil = no minimum.
+	MinAlternatives MinAlternativesPolicy `json:"min_alternatives,omitempty"`
+	// ConfigOverrides replaces selected environment settings for the org.
+	// Nil = global configuration.
+	ConfigOverrides *ConfigOverridesPolicy `json:"config_overrides,omitempty"`
 }
 
 // OrgSettings is a row from the org_settings table.
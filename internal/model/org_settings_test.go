@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -71,3 +72,167 @@ func TestSeverityRank(t *testing.T) {
 	assert.Equal(t, 0, SeverityRank("unknown"))
 	assert.Equal(t, 0, SeverityRank(""))
 }
+
+func TestDecisionQuotaPolicy_Validate(t *testing.T) {
+	valid := DecisionQuotaPolicy{
+		PerAgent:       QuotaLimits{Daily: 100, Monthly: 1000},
+		Org:            QuotaLimits{Monthly: 10000},
+		AgentOverrides: map[string]QuotaLimits{"batch-importer": {Daily: 5000}},
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, (&DecisionQuotaPolicy{}).Validate(), "zero policy is unlimited")
+
+	t.Run("negative per-agent limit", func(t *testing.T) {
+		p := valid
+		p.PerAgent.Daily = -1
+		assert.Error(t, p.Validate())
+	})
+
+	t.Run("negative org limit", func(t *testing.T) {
+		p := valid
+		p.Org.Monthly = -5
+		assert.Error(t, p.Validate())
+	})
+
+	t.Run("invalid override agent id", func(t *testing.T) {
+		p := valid
+		p.AgentOverrides = map[string]QuotaLimits{"bad agent!": {Daily: 1}}
+		assert.Error(t, p.Validate())
+	})
+}
+
+func TestDecisionQuotaPolicy_Check(t *testing.T) {
+	p := DecisionQuotaPolicy{
+		PerAgent:       QuotaLimits{Daily: 10, Monthly: 100},
+		Org:            QuotaLimits{Daily: 50},
+		AgentOverrides: map[string]QuotaLimits{"vip": {}},
+	}
+
+	assert.Nil(t, p.Check("a", DecisionUsage{AgentDaily: 9, AgentMonthly: 99, OrgDaily: 49}))
+
+	exceeded := p.Check("a", DecisionUsage{AgentDaily: 10, AgentMonthly: 100})
+	if assert.NotNil(t, exceeded) {
+		assert.Equal(t, "agent", exceeded.Scope)
+		assert.Equal(t, "daily", exceeded.Period, "daily is checked before monthly")
+		assert.Equal(t, int64(10), exceeded.Limit)
+	}
+
+	exceeded = p.Check("a", DecisionUsage{AgentDaily: 1, AgentMonthly: 100})
+	if assert.NotNil(t, exceeded) {
+		assert.Equal(t, "monthly", exceeded.Period)
+	}
+
+	// An override with zero limits makes the agent unlimited, but the org cap still applies.
+	assert.Nil(t, p.Check("vip", DecisionUsage{AgentDaily: 1000, AgentMonthly: 1000, OrgDaily: 49}))
+	exceeded = p.Check("vip", DecisionUsage{OrgDaily: 50})
+	if assert.NotNil(t, exceeded) {
+		assert.Equal(t, "org", exceeded.Scope)
+		assert.Contains(t, exceeded.Error(), "50 of 50")
+	}
+}
+
+func TestReviewRoutingPolicy_Validate(t *testing.T) {
+	valid := ReviewRoutingPolicy{
+		Routes:           []ReviewRoute{{Tag: "payments", Reviewers: []string{"payments-lead"}}},
+		DefaultReviewers: []string{"oncall"},
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, (&ReviewRoutingPolicy{}).Validate(), "empty policy is valid")
+
+	assert.Error(t, (&ReviewRoutingPolicy{Routes: []ReviewRoute{{Reviewers: []string{"a"}}}}).Validate(), "missing tag")
+	assert.Error(t, (&ReviewRoutingPolicy{Routes: []ReviewRoute{{Tag: "t"}}}).Validate(), "no reviewers")
+	assert.Error(t, (&ReviewRoutingPolicy{Routes: []ReviewRoute{{Tag: "t", Reviewers: []string{"bad id"}}}}).Validate())
+	assert.Error(t, (&ReviewRoutingPolicy{DefaultReviewers: []string{""}}).Validate())
+}
+
+func TestReviewRoutingPolicy_ReviewerFor(t *testing.T) {
+	p := ReviewRoutingPolicy{
+		Routes: []ReviewRoute{
+			{Tag: "payments", Reviewers: []string{"payments-lead", "payments-backup"}},
+			{Tag: "infra", Reviewers: []string{"sre-lead"}},
+		},
+		DefaultReviewers: []string{"oncall"},
+	}
+
+	assert.Equal(t, "payments-lead", p.ReviewerFor("billing-bot", []string{"infra", "payments"}), "first matching route wins")
+	assert.Equal(t, "sre-lead", p.ReviewerFor("deploy-bot", []string{"infra"}))
+	assert.Equal(t, "payments-backup", p.ReviewerFor("payments-lead", []string{"payments"}), "never self-assigned")
+	assert.Equal(t, "oncall", p.ReviewerFor("misc-bot", []string{"unrouted"}))
+	assert.Equal(t, "oncall", p.ReviewerFor("sre-lead", []string{"infra"}), "falls through when route has only the agent itself")
+	assert.Equal(t, "", (&ReviewRoutingPolicy{}).ReviewerFor("a", []string{"payments"}))
+}
+
+func TestRunReviewGatePolicy(t *testing.T) {
+	assert.NoError(t, (&RunReviewGatePolicy{}).Validate(), "empty policy gates everything")
+	assert.NoError(t, (&RunReviewGatePolicy{Agents: []string{"planner"}, DecisionTypes: []string{"deploy"}}).Validate())
+	assert.Error(t, (&RunReviewGatePolicy{Agents: []string{"bad id"}}).Validate())
+	assert.Error(t, (&RunReviewGatePolicy{DecisionTypes: []string{" "}}).Validate())
+
+	assert.True(t, (&RunReviewGatePolicy{}).AppliesToAgent("anyone"))
+	scoped := RunReviewGatePolicy{Agents: []string{"planner"}}
+	assert.True(t, scoped.AppliesToAgent("planner"))
+	assert.False(t, scoped.AppliesToAgent("coder"))
+}
+
+func TestPrecedentDecayPolicy(t *testing.T) {
+	p := PrecedentDecayPolicy{DefaultHalfLifeDays: 10, DecisionTypes: map[string]float64{"security": 0, "architecture": 90}}
+	assert.NoError(t, p.Validate())
+	assert.Error(t, (&PrecedentDecayPolicy{DefaultHalfLifeDays: -1}).Validate())
+	assert.Error(t, (&PrecedentDecayPolicy{DecisionTypes: map[string]float64{"x": -2}}).Validate())
+
+	day := 24 * time.Hour
+	assert.InDelta(t, 0.4, p.EffectiveConfidence(0.8, "other", 10*day), 0.0001, "default half-life applies")
+	assert.InDelta(t, 0.4, p.EffectiveConfidence(0.8, "architecture", 90*day), 0.0001, "per-type override")
+	assert.InDelta(t, 0.8, p.EffectiveConfidence(0.8, "security", 1000*day), 0.0001, "zero half-life disables decay")
+	assert.InDelta(t, 0.8, p.EffectiveConfidence(0.8, "other", -day), 0.0001, "future timestamps are not boosted")
+}
+
+func TestMinAlternativesPolicy(t *testing.T) {
+	p := MinAlternativesPolicy{"architecture": 2}
+	assert.NoError(t, p.Validate())
+	assert.Error(t, MinAlternativesPolicy{"x": -1}.Validate())
+	assert.Error(t, MinAlternativesPolicy{" ": 1}.Validate())
+
+	assert.NoError(t, p.Check("architecture", 2), "minimum met")
+	assert.NoError(t, p.Check("architecture", 3))
+	assert.ErrorContains(t, p.Check("architecture", 1), "requires at least 2 alternatives, got 1")
+	assert.NoError(t, p.Check("code_review", 0), "unlisted types have no minimum")
+	assert.NoError(t, MinAlternativesPolicy(nil).Check("architecture", 0), "nil policy is off")
+}
+
+func TestDefaultIncludesPolicy_Validate(t *testing.T) {
+	valid := DefaultIncludesPolicy{
+		GetDecision:     []string{"evidence"},
+		DecisionsRecent: []string{IncludeNone},
+		Query:           []string{"alternatives", "flags"},
+	}
+	assert.NoError(t, valid.Validate())
+
+	assert.Error(t, (&DefaultIncludesPolicy{GetDecision: []string{"flags"}}).Validate(), "flags not offered by get_decision")
+	assert.Error(t, (&DefaultIncludesPolicy{Query: []string{"none", "evidence"}}).Validate(), "none must stand alone")
+	assert.Nil(t, (&DefaultIncludesPolicy{}).For(IncludeEndpointQuery))
+}
+
+func TestMCPToolsPolicy(t *testing.T) {
+	p := &MCPToolsPolicy{Disabled: []string{"akashi_assess"}}
+	assert.NoError(t, p.Validate())
+	assert.True(t, p.IsDisabled("akashi_assess"))
+	assert.False(t, p.IsDisabled("akashi_check"))
+	assert.False(t, (*MCPToolsPolicy)(nil).IsDisabled("akashi_assess"), "nil policy disables nothing")
+
+	assert.Error(t, (&MCPToolsPolicy{Disabled: []string{"akashi_delete"}}).Validate())
+}
+
+func TestConfigOverridesPolicy(t *testing.T) {
+	threshold, days := 0.5, 7
+	p := &ConfigOverridesPolicy{ConflictSignificanceThreshold: &threshold, EventRetentionDays: &days}
+	assert.NoError(t, p.Validate())
+	assert.InDelta(t, 0.5, p.SignificanceThreshold(0.3), 1e-9)
+	assert.InDelta(t, 0.3, (&ConfigOverridesPolicy{}).SignificanceThreshold(0.3), 1e-9, "unset falls back to global")
+	assert.InDelta(t, 0.3, (*ConfigOverridesPolicy)(nil).SignificanceThreshold(0.3), 1e-9, "nil policy falls back to global")
+
+	zero, over, negative := 0.0, 1.5, -1
+	assert.Error(t, (&ConfigOverridesPolicy{ConflictSignificanceThreshold: &zero}).Validate())
+	assert.Error(t, (&ConfigOverridesPolicy{ConflictSignificanceThreshold: &over}).Validate())
+	assert.Error(t, (&ConfigOverridesPolicy{EventRetentionDays: &negative}).Validate())
+}
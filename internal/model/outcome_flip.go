@@ -0,0 +1,169 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// OutcomePolarity is the coarse direction of a decision outcome: whether it
+// lets the thing under decision go ahead or stops it.
+type OutcomePolarity int
+
+const (
+	OutcomePolarityUnknown OutcomePolarity = iota
+	OutcomePolarityPositive
+	OutcomePolarityNegative
+)
+
+// positiveOutcomeWords and negativeOutcomeWords are the verdict words that
+// determine an outcome's polarity. Matching is on whole lowercase words.
+// ambiguousVerdictWords also name things ("Use Go", "ship the tarball"), so
+// they only count when they are the whole outcome ("Yes", "No.", "Ship").
+var (
+	positiveOutcomeWords = map[string]struct{}{
+		"approve": {}, "approved": {}, "approves": {},
+		"accept": {}, "accepted": {}, "accepts": {},
+		"allow": {}, "allowed": {}, "allows": {},
+		"grant": {}, "granted": {}, "grants": {},
+		"permit": {}, "permitted": {},
+		"proceed": {}, "merged": {}, "shipped": {},
+		"enable": {}, "enabled": {},
+	}
+	negativeOutcomeWords = map[string]struct{}{
+		"deny": {}, "denied": {}, "denies": {},
+		"reject": {}, "rejected": {}, "rejects": {},
+		"block": {}, "blocked": {}, "blocks": {},
+		"decline": {}, "declined": {}, "declines": {},
+		"refuse": {}, "refused": {},
+		"revoke": {}, "revoked": {},
+		"disallow": {}, "disallowed": {},
+		"forbid": {}, "forbidden": {},
+		"halt": {}, "abort": {}, "aborted": {},
+		"veto": {}, "vetoed": {},
+		"disable": {}, "disabled": {},
+	}
+	ambiguousVerdictWords = map[string]OutcomePolarity{
+		"yes": OutcomePolarityPositive, "go": OutcomePolarityPositive,
+		"ship": OutcomePolarityPositive, "merge": OutcomePolarityPositive,
+		"no": OutcomePolarityNegative,
+	}
+	negationWords = map[string]struct{}{
+		"not": {}, "don't": {}, "dont": {}, "never": {}, "cannot": {}, "won't": {},
+	}
+)
+
+// ClassifyOutcomePolarity returns the polarity of a free-text outcome from
+// the verdict words it contains. A verdict word directly preceded by a
+// negation ("do not approve") counts toward the opposite polarity. Outcomes
+// with no verdict words, or with verdict words of both polarities, are
+// OutcomePolarityUnknown.
+func ClassifyOutcomePolarity(outcome string) OutcomePolarity {
+	words := strings.FieldsFunc(strings.ToLower(outcome), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	if len(words) == 1 {
+		if p, ok := ambiguousVerdictWords[words[0]]; ok {
+			return p
+		}
+	}
+
+	var positive, negative bool
+	for i, w := range words {
+		_, pos := positiveOutcomeWords[w]
+		_, neg := negativeOutcomeWords[w]
+		if !pos && !neg {
+			continue
+		}
+		if i > 0 {
+			if _, negated := negationWords[words[i-1]]; negated {
+				pos, neg = neg, pos
+			}
+		}
+		positive = positive || pos
+		negative = negative || neg
+	}
+
+	switch {
+	case positive && !negative:
+		return OutcomePolarityPositive
+	case negative && !positive:
+		return OutcomePolarityNegative
+	default:
+		return OutcomePolarityUnknown
+	}
+}
+
+// IsOutcomeFlip reports whether revising previous to revised reverses the
+// decision (e.g. approve → deny). Both outcomes must have a known polarity;
+// rewording, confidence tweaks, and outcomes without a clear verdict are
+// never flips.
+func IsOutcomeFlip(previous, revised string) bool {
+	p := ClassifyOutcomePolarity(previous)
+	r := ClassifyOutcomePolarity(revised)
+	return p != OutcomePolarityUnknown && r != OutcomePolarityUnknown && p != r
+}
+
+// Default flip-flop detection settings: three reversals within a day.
+const (
+	DefaultFlipFlopMinFlips = 3
+	DefaultFlipFlopWindow   = 24 * time.Hour
+)
+
+// FlipFlop is a revision chain whose outcome reversed repeatedly within a
+// short window (approve → deny → approve), a sign of an unstable agent that
+// a raw revision count does not reveal.
+type FlipFlop struct {
+	DecisionID    uuid.UUID  `json:"decision_id"` // Current head of the chain.
+	AgentID       string     `json:"agent_id"`
+	DecisionType  string     `json:"decision_type"`
+	FlipCount     int        `json:"flip_count"` // Flips inside the densest window.
+	FirstFlipAt   time.Time  `json:"first_flip_at"`
+	LastFlipAt    time.Time  `json:"last_flip_at"`
+	RevisionCount int        `json:"revision_count"`
+	Revisions     []Decision `json:"revisions,omitempty"`
+}
+
+// DetectFlipFlop scans a revision chain ordered by valid_from ascending for
+// outcome flips and reports the densest run of flips that fits inside window.
+// It returns false when fewer than minFlips flips fall within any window.
+func DetectFlipFlop(chain []Decision, window time.Duration, minFlips int) (FlipFlop, bool) {
+	if len(chain) < 2 || minFlips <= 0 {
+		return FlipFlop{}, false
+	}
+
+	var flips []time.Time
+	for i := 1; i < len(chain); i++ {
+		if IsOutcomeFlip(chain[i-1].Outcome, chain[i].Outcome) {
+			flips = append(flips, chain[i].ValidFrom)
+		}
+	}
+
+	// Two-pointer sweep: flips are already in chronological order.
+	best, bestStart := 0, 0
+	start := 0
+	for end := range flips {
+		for flips[end].Sub(flips[start]) > window {
+			start++
+		}
+		if n := end - start + 1; n > best {
+			best, bestStart = n, start
+		}
+	}
+	if best < minFlips {
+		return FlipFlop{}, false
+	}
+
+	head := chain[len(chain)-1]
+	return FlipFlop{
+		DecisionID:    head.ID,
+		AgentID:       head.AgentID,
+		DecisionType:  head.DecisionType,
+		FlipCount:     best,
+		FirstFlipAt:   flips[bestStart],
+		LastFlipAt:    flips[bestStart+best-1],
+		RevisionCount: len(chain),
+	}, true
+}
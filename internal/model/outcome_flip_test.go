@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyOutcomePolarity(t *testing.T) {
+	tests := []struct {
+		outcome string
+		want    OutcomePolarity
+	}{
+		{"approve PR #42", OutcomePolarityPositive},
+		{"Approved: merge after CI", OutcomePolarityPositive},
+		{"deny the refund request", OutcomePolarityNegative},
+		{"REJECTED — missing tests", OutcomePolarityNegative},
+		{"do not approve", OutcomePolarityNegative},
+		{"never block releases on lint", OutcomePolarityPositive},
+		{"use Redis for session storage", OutcomePolarityUnknown},
+		{"approve part, reject the rest", OutcomePolarityUnknown},
+		{"Yes", OutcomePolarityPositive},
+		{"No.", OutcomePolarityNegative},
+		{"Use Go for the API", OutcomePolarityUnknown},
+		{"no new dependencies", OutcomePolarityUnknown},
+		{"", OutcomePolarityUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ClassifyOutcomePolarity(tt.outcome), tt.outcome)
+	}
+}
+
+func TestIsOutcomeFlip(t *testing.T) {
+	assert.True(t, IsOutcomeFlip("approve the deploy", "deny the deploy"))
+	assert.True(t, IsOutcomeFlip("reject", "accept with changes"))
+	assert.False(t, IsOutcomeFlip("approve the deploy", "approved, deploy Friday"), "same verdict reworded")
+	assert.False(t, IsOutcomeFlip("use Redis", "use Memcached"), "no verdict words")
+	assert.False(t, IsOutcomeFlip("approve", "use Memcached"), "revised verdict unknown")
+	assert.False(t, IsOutcomeFlip("Use Go for the API", "Use Rust, not Go"), "language names are not verdicts")
+	assert.True(t, IsOutcomeFlip("yes", "no"))
+}
+
+func TestDetectFlipFlop(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	chain := func(outcomes []string, gaps ...time.Duration) []Decision {
+		decs := make([]Decision, len(outcomes))
+		at := base
+		for i, o := range outcomes {
+			if i > 0 {
+				at = at.Add(gaps[i-1])
+			}
+			decs[i] = Decision{ID: uuid.New(), AgentID: "agent-a", DecisionType: "code_review", Outcome: o, ValidFrom: at}
+		}
+		return decs
+	}
+
+	t.Run("flags rapid reversals", func(t *testing.T) {
+		c := chain([]string{"approve", "deny", "approve", "deny"}, time.Minute, time.Minute, time.Minute)
+		ff, ok := DetectFlipFlop(c, time.Hour, 3)
+		require.True(t, ok)
+		assert.Equal(t, 3, ff.FlipCount)
+		assert.Equal(t, c[3].ID, ff.DecisionID)
+		assert.Equal(t, "agent-a", ff.AgentID)
+		assert.Equal(t, c[1].ValidFrom, ff.FirstFlipAt)
+		assert.Equal(t, c[3].ValidFrom, ff.LastFlipAt)
+		assert.Equal(t, 4, ff.RevisionCount)
+	})
+
+	t.Run("flips spread beyond the window", func(t *testing.T) {
+		c := chain([]string{"approve", "deny", "approve", "deny"}, time.Minute, 2*time.Hour, 2*time.Hour)
+		_, ok := DetectFlipFlop(c, time.Hour, 2)
+		assert.False(t, ok)
+		ff, ok := DetectFlipFlop(c, 3*time.Hour, 2)
+		require.True(t, ok)
+		assert.Equal(t, 2, ff.FlipCount)
+	})
+
+	t.Run("rewording is not a flip", func(t *testing.T) {
+		c := chain([]string{"approve", "approved, ship it", "approve with nits"}, time.Minute, time.Minute)
+		_, ok := DetectFlipFlop(c, time.Hour, 1)
+		assert.False(t, ok)
+	})
+
+	t.Run("single decision", func(t *testing.T) {
+		_, ok := DetectFlipFlop(chain([]string{"approve"}), time.Hour, 1)
+		assert.False(t, ok)
+	})
+}
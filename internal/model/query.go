@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,16 +11,114 @@ import (
 
 // QueryFilters defines the filter parameters for structured decision queries.
 type QueryFilters struct {
-	AgentIDs      []string   `json:"agent_id,omitempty"`
-	RunID         *uuid.UUID `json:"run_id,omitempty"`
-	DecisionType  *string    `json:"decision_type,omitempty"`
-	ConfidenceMin *float32   `json:"confidence_min,omitempty"`
-	Outcome       *string    `json:"outcome,omitempty"`
-	TimeRange     *TimeRange `json:"time_range,omitempty"`
-	SessionID     *uuid.UUID `json:"session_id,omitempty"`
-	Tool          *string    `json:"tool,omitempty"`
-	Model         *string    `json:"model,omitempty"`
-	Project       *string    `json:"project,omitempty"`
+	AgentIDs []string `json:"agent_id,omitempty"`
+	// AgentRoles keeps only decisions by agents currently holding one of these
+	// roles. Handlers resolve it to agent IDs and fold them into AgentIDs
+	// before querying; storage does not read it.
+	AgentRoles []AgentRole `json:"agent_roles,omitempty"`
+	RunID      *uuid.UUID  `json:"run_id,omitempty"`
+	// DecisionType is a deprecated single-type alias for DecisionTypes. When
+	// both are set it is matched alongside them.
+	DecisionType *string `json:"decision_type,omitempty"`
+	// DecisionTypes keeps only decisions whose type is any one of these.
+	DecisionTypes []string `json:"decision_types,omitempty"`
+	ConfidenceMin *float32 `json:"confidence_min,omitempty"`
+	// MinConfidenceWidth keeps only decisions whose recorded confidence
+	// interval (confidence_high - confidence_low) is at least this wide.
+	// Decisions without an interval never match.
+	MinConfidenceWidth *float32 `json:"min_confidence_width,omitempty"`
+	Outcome            *string  `json:"outcome,omitempty"`
+	// OutcomeFlipped keeps only revisions that reversed the verdict of the
+	// decision they superseded (true), or excludes them (false).
+	OutcomeFlipped *bool      `json:"outcome_flipped,omitempty"`
+	TimeRange      *TimeRange `json:"time_range,omitempty"`
+	SessionID      *uuid.UUID `json:"session_id,omitempty"`
+	// BatchID keeps only decisions in one batch window (see Decision.BatchID).
+	BatchID *uuid.UUID `json:"batch_id,omitempty"`
+	Tool    *string    `json:"tool,omitempty"`
+	Model   *string    `json:"model,omitempty"`
+	Project *string    `json:"project,omitempty"`
+	// ContextFilters keeps only decisions whose agent_context contains every
+	// key/value pair. Dotted keys address nested objects, so "client.task"
+	// matches {"client": {"task": ...}}. Values match as JSON strings.
+	ContextFilters map[string]string `json:"agent_context,omitempty"`
+	// TemperatureMin and TemperatureMax bound the context_snapshot temperature
+	// (inclusive). Decisions without a recorded temperature never match.
+	TemperatureMin *float64 `json:"temperature_min,omitempty"`
+	TemperatureMax *float64 `json:"temperature_max,omitempty"`
+	// HasEvidence and HasAlternatives keep only decisions with at least one
+	// evidence record or alternative (true), or with none (false).
+	HasEvidence     *bool `json:"has_evidence,omitempty"`
+	HasAlternatives *bool `json:"has_alternatives,omitempty"`
+	// Tags keeps only decisions carrying at least one of these decision tags
+	// (see PATCH /v1/decisions/{id}/tags). Agent tags are not consulted.
+	Tags []string `json:"tags,omitempty"`
+	// Namespace scopes the query to one decision namespace. It is never read
+	// from request bodies; handlers set it from the caller's resolved namespace.
+	Namespace *string `json:"-"`
+	// RecencyHalfLifeDays sets the age at which search ranking halves a
+	// decision's relevance. nil uses DefaultRecencyHalfLifeDays; 0 disables
+	// recency decay. Only search consults it, and it is never read from
+	// request bodies; handlers copy it from SearchRequest.
+	RecencyHalfLifeDays *float64 `json:"-"`
+}
+
+// DefaultRecencyHalfLifeDays is the search ranking recency half-life used
+// when a search does not set one.
+const DefaultRecencyHalfLifeDays = 90.0
+
+// RecencyHalfLife returns the search recency half-life in days, applying the
+// default. A result <= 0 means no decay.
+func (f QueryFilters) RecencyHalfLife() float64 {
+	if f.RecencyHalfLifeDays == nil {
+		return DefaultRecencyHalfLifeDays
+	}
+	return *f.RecencyHalfLifeDays
+}
+
+// DecisionTypeList returns the decision types a query matches: DecisionTypes
+// with the deprecated DecisionType appended, de-duplicated and with empty
+// entries dropped. An empty result means no type filter.
+func (f QueryFilters) DecisionTypeList() []string {
+	types := f.DecisionTypes
+	if f.DecisionType != nil {
+		types = append(types[:len(types):len(types)], *f.DecisionType)
+	}
+	var out []string
+	seen := make(map[string]bool, len(types))
+	for _, t := range types {
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// MaxContextFilters caps the number of agent_context filters in one query.
+const MaxContextFilters = 10
+
+var contextFilterKeyRe = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// ValidateContextFilters checks that a ContextFilters map has at most
+// MaxContextFilters entries and that every key is a dotted path of
+// letters, digits, hyphens, and underscores.
+func ValidateContextFilters(filters map[string]string) error {
+	if len(filters) > MaxContextFilters {
+		return fmt.Errorf("agent_context: at most %d filters are allowed", MaxContextFilters)
+	}
+	for key := range filters {
+		if len(key) > 128 || !contextFilterKeyRe.MatchString(key) {
+			return fmt.Errorf("agent_context: invalid key %q: use dot-separated letters, digits, '-' or '_'", key)
+		}
+	}
+	return nil
+}
+
+// ContextFilterPath splits a ContextFilters key into its object path.
+func ContextFilterPath(key string) []string {
+	return strings.Split(key, ".")
 }
 
 // TimeRange defines a time range for queries.
@@ -29,12 +130,15 @@ type TimeRange struct {
 // QueryRequest is the request body for POST /v1/query.
 type QueryRequest struct {
 	Filters  QueryFilters `json:"filters"`
-	Include  []string     `json:"include,omitempty"`
+	Include  []string     `json:"include,omitempty"` // "alternatives", "evidence", "flags"
 	OrderBy  string       `json:"order_by,omitempty"`
 	OrderDir string       `json:"order_dir,omitempty"`
 	Limit    int          `json:"limit,omitempty"`
 	Offset   int          `json:"offset,omitempty"`
 	TraceID  *string      `json:"trace_id,omitempty"` // Filter by OTEL trace ID (matches agent_runs.trace_id).
+	// Suggest returns close matches for unknown filters.agent_ids in the
+	// response's suggestions when the query finds nothing.
+	Suggest bool `json:"suggest,omitempty"`
 }
 
 // TemporalQueryRequest is the request body for POST /v1/query/temporal.
@@ -44,12 +148,26 @@ type TemporalQueryRequest struct {
 	Limit   int          `json:"limit,omitempty"`
 }
 
+// TemporalDiffRequest is the request body for POST /v1/query/temporal/diff.
+type TemporalDiffRequest struct {
+	AgentID string    `json:"agent_id"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
 // SearchRequest is the request body for POST /v1/search.
 type SearchRequest struct {
 	Query    string       `json:"query"`
 	Semantic bool         `json:"semantic"`
 	Filters  QueryFilters `json:"filters,omitempty"`
 	Limit    int          `json:"limit,omitempty"`
+	// TimeRange restricts results to decisions whose valid_from falls in the
+	// window. Shorthand for filters.time_range; set one or the other.
+	TimeRange *TimeRange `json:"time_range,omitempty"`
+	// RecencyHalfLifeDays tunes how fast ranking decays with age: a decision
+	// this many days old scores half what an identical new one does. Omitted
+	// uses DefaultRecencyHalfLifeDays; 0 ranks without regard to age.
+	RecencyHalfLifeDays *float64 `json:"recency_half_life_days,omitempty"`
 }
 
 // SearchResult wraps a decision with its similarity score.
@@ -57,6 +175,10 @@ type SearchResult struct {
 	Decision        Decision `json:"decision"`
 	SimilarityScore float32  `json:"similarity_score"`
 	QdrantRank      int      `json:"qdrant_rank,omitempty"` // 1-based position in Qdrant's ANN results; 0 for text-fallback results.
+	// Highlights are snippets of the decision's reasoning and outcome with
+	// matched query terms wrapped in <b></b>. Set only by text search, and
+	// only when a term matched those fields.
+	Highlights []string `json:"highlights,omitempty"`
 }
 
 // TimelineBucket represents a single time period in the decision timeline summary.
@@ -88,6 +210,29 @@ type TimelineResponse struct {
 	Projects    []string         `json:"projects"`
 }
 
+// Bucket counts accepted by GET /v1/agents/{agent_id}/confidence-histogram.
+const (
+	DefaultConfidenceHistogramBuckets = 10
+	MaxConfidenceHistogramBuckets     = 100
+)
+
+// ConfidenceBucket is one bucket of a confidence histogram: the decisions
+// whose confidence falls in [Low, High). The last bucket also holds
+// confidence 1.0.
+type ConfidenceBucket struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// ConfidenceHistogramResponse is the response for
+// GET /v1/agents/{agent_id}/confidence-histogram.
+type ConfidenceHistogramResponse struct {
+	AgentID string             `json:"agent_id"`
+	Total   int                `json:"total"`
+	Buckets []ConfidenceBucket `json:"buckets"`
+}
+
 // CheckRequest is the request body for POST /v1/check.
 // It supports a lightweight precedent lookup before making a decision.
 type CheckRequest struct {
@@ -97,6 +242,9 @@ type CheckRequest struct {
 	Project      string `json:"project,omitempty"`
 	Limit        int    `json:"limit,omitempty"`
 	Format       string `json:"format,omitempty"` // "full" (default) or "concise"
+	// Suggest fills CheckResponse.Suggestions when an unknown agent_id
+	// leaves the check with no decisions.
+	Suggest bool `json:"suggest,omitempty"`
 }
 
 // ConflictResolution summarises a resolved conflict for use in akashi_check responses.
@@ -130,4 +278,8 @@ type CheckResponse struct {
 	// (losing_outcome / losing_agent). Use winning_decision_id as precedent_ref
 	// in akashi_trace to build on the validated approach.
 	PriorResolutions []ConflictResolution `json:"prior_resolutions,omitempty"`
+	// Suggestions lists existing agent_ids similar to a requested agent_id
+	// that does not exist. Only set when the caller asked with suggest=true
+	// and no decisions were found.
+	Suggestions []string `json:"suggestions,omitempty"`
 }
@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestValidateContextFilters(t *testing.T) {
+	assert.NoError(t, model.ValidateContextFilters(nil))
+	assert.NoError(t, model.ValidateContextFilters(map[string]string{"tool": "mcp", "client.task": "review", "server.api-key_id": ""}))
+
+	for _, key := range []string{"", ".task", "client.", "client..task", "client task", "client'task", "$.task"} {
+		assert.Error(t, model.ValidateContextFilters(map[string]string{key: "x"}), key)
+	}
+
+	tooMany := make(map[string]string)
+	for i := range model.MaxContextFilters + 1 {
+		tooMany[fmt.Sprintf("k%d", i)] = "v"
+	}
+	assert.Error(t, model.ValidateContextFilters(tooMany))
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Re-embedding job states.
+const (
+	ReembedStatusPending   = "pending"   // Created, awaiting confirmation.
+	ReembedStatusRunning   = "running"   // Confirmed; resumable until completed.
+	ReembedStatusCompleted = "completed" // Every current decision was visited.
+)
+
+// ReembedJob tracks regeneration of an org's embeddings after an embedding
+// provider or model change. CursorID is the last decision re-embedded, in id
+// order; a resumed run continues after it.
+type ReembedJob struct {
+	ID                uuid.UUID  `json:"id"`
+	OrgID             uuid.UUID  `json:"org_id"`
+	FromModel         string     `json:"from_model"`
+	ToModel           string     `json:"to_model"`
+	ConfirmationToken string     `json:"confirmation_token"`
+	Status            string     `json:"status"`
+	CursorID          *uuid.UUID `json:"cursor_id,omitempty"`
+	Total             int        `json:"total"`
+	Processed         int        `json:"processed"`
+	Failed            int        `json:"failed"`
+	CreatedBy         string     `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+}
+
+// EmbeddingModelsCompatible reports whether vectors tagged with models a and b
+// may be compared. Vectors from different models live in unrelated spaces, so
+// their cosine similarity is meaningless. An untagged (nil or empty) side is
+// treated as compatible: it predates model tracking and is assumed to come
+// from the current model until a re-embedding job tags it.
+func EmbeddingModelsCompatible(a, b *string) bool {
+	if a == nil || b == nil || *a == "" || *b == "" {
+		return true
+	}
+	return *a == *b
+}
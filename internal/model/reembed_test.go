@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddingModelsCompatible(t *testing.T) {
+	small, large, empty := "text-embedding-3-small", "text-embedding-3-large", ""
+
+	assert.True(t, EmbeddingModelsCompatible(&small, &small))
+	assert.False(t, EmbeddingModelsCompatible(&small, &large))
+	assert.True(t, EmbeddingModelsCompatible(nil, &large), "untagged vectors predate model tracking")
+	assert.True(t, EmbeddingModelsCompatible(&small, nil))
+	assert.True(t, EmbeddingModelsCompatible(&empty, &large))
+	assert.True(t, EmbeddingModelsCompatible(nil, nil))
+}
@@ -18,6 +18,44 @@ type TraceResponse struct {
 	OriginalConfidence float32  `json:"original_confidence,omitempty"`
 	StoredConfidence   float32  `json:"stored_confidence,omitempty"`
 	ConfidenceReasons  []string `json:"confidence_reasons,omitempty"`
+
+	// SupersededID is the decision this trace replaced, either explicitly via
+	// supersedes_id or by supersede_matching. Decision is the stored revision,
+	// returned only for supersede_matching requests.
+	SupersededID *uuid.UUID `json:"superseded_id,omitempty"`
+	Decision     *Decision  `json:"decision,omitempty"`
+
+	// DuplicateOf is set when a dedupe trace matched an existing decision; no
+	// decision was recorded and RunID and DecisionID identify the existing one.
+	DuplicateOf *uuid.UUID `json:"duplicate_of,omitempty"`
+}
+
+// TraceBatchResult identifies the run and decision created for one trace in
+// a batch. Index is the trace's position in the request.
+type TraceBatchResult struct {
+	Index      int       `json:"index"`
+	RunID      uuid.UUID `json:"run_id"`
+	DecisionID uuid.UUID `json:"decision_id"`
+}
+
+// ImportDecisionsResult is the response for POST /v1/import/decisions.
+// Counts are per decision, so a full_history line contributes one per
+// revision. Errors holds at most a fixed number of entries; ErrorsTruncated
+// is set when more lines failed than were reported.
+type ImportDecisionsResult struct {
+	Inserted        int               `json:"inserted"`
+	Skipped         int               `json:"skipped"`
+	Failed          int               `json:"failed"`
+	Errors          []ImportLineError `json:"errors"`
+	ErrorsTruncated bool              `json:"errors_truncated,omitempty"`
+}
+
+// ImportLineError describes a decision that could not be imported. Line is
+// 1-based; DecisionID is nil when the line could not be parsed.
+type ImportLineError struct {
+	Line       int        `json:"line"`
+	DecisionID *uuid.UUID `json:"decision_id,omitempty"`
+	Error      string     `json:"error"`
 }
 
 // TemporalQueryResponse is the response for POST /v1/query/temporal.
@@ -26,6 +64,29 @@ type TemporalQueryResponse struct {
 	Decisions []Decision `json:"decisions"`
 }
 
+// TemporalDiffResponse is the response for POST /v1/query/temporal/diff. It
+// compares the agent's decisions valid at From with those valid at To,
+// matching decisions across revisions of the same chain. Decisions valid at
+// both points are omitted. Truncated is set when either point held more
+// decisions than one snapshot returns, in which case the diff is partial.
+type TemporalDiffResponse struct {
+	AgentID    string               `json:"agent_id"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	Added      []Decision           `json:"added"`
+	Superseded []Decision           `json:"superseded"`
+	Changed    []TemporalDiffChange `json:"changed"`
+	Truncated  bool                 `json:"truncated"`
+}
+
+// TemporalDiffChange pairs the revision of a decision valid at the start of a
+// temporal diff with the revision of the same chain valid at its end.
+type TemporalDiffChange struct {
+	Before         Decision `json:"before"`
+	After          Decision `json:"after"`
+	OutcomeChanged bool     `json:"outcome_changed"`
+}
+
 // DecisionRevisionsResponse is the response for GET /v1/decisions/{id}/revisions.
 type DecisionRevisionsResponse struct {
 	DecisionID uuid.UUID  `json:"decision_id"`
@@ -43,6 +104,10 @@ type VerifyDecisionResponse struct {
 	Message     string    `json:"message,omitempty"`
 	RetractedAt string    `json:"retracted_at,omitempty"`
 
+	// HashDiagnosis (an integrity.HashDiagnosis) is set only when the hash
+	// does not verify, to show which canonical input diverged.
+	HashDiagnosis any `json:"hash_diagnosis,omitempty"`
+
 	// Erasure-specific fields.
 	OriginalHash string     `json:"original_hash,omitempty"`
 	ErasedAt     *time.Time `json:"erased_at,omitempty"`
@@ -72,6 +137,15 @@ type SessionViewResponse struct {
 	Summary       *SessionViewSummary `json:"summary,omitempty"`
 }
 
+// BatchViewResponse is the response for GET /v1/decisions/batches/{batch_id}.
+// The summary has the same shape as a session's.
+type BatchViewResponse struct {
+	BatchID       uuid.UUID           `json:"batch_id"`
+	Decisions     []Decision          `json:"decisions"`
+	DecisionCount int                 `json:"decision_count"`
+	Summary       *SessionViewSummary `json:"summary,omitempty"`
+}
+
 // EraseDecisionResponse is the response for POST /v1/decisions/{id}/erase.
 type EraseDecisionResponse struct {
 	DecisionID         uuid.UUID  `json:"decision_id"`
@@ -96,6 +170,12 @@ type CreateAgentResponse struct {
 	RawKey string                `json:"raw_key,omitempty"`
 }
 
+// BulkCreateAgentsResponse is the response for POST /v1/agents/bulk.
+// Entries are in request order.
+type BulkCreateAgentsResponse struct {
+	Agents []CreateAgentResponse `json:"agents"`
+}
+
 // AgentStatsResponse is the response for GET /v1/agents/{agent_id}/stats.
 type AgentStatsResponse struct {
 	AgentID string `json:"agent_id"`
@@ -106,6 +186,9 @@ type AgentStatsResponse struct {
 type DeleteAgentResponse struct {
 	AgentID string `json:"agent_id"`
 	Deleted any    `json:"deleted"`
+	// Tombstoned is true when soft delete is enabled and the agent's rows
+	// were marked deleted rather than removed.
+	Tombstoned bool `json:"tombstoned,omitempty"`
 }
 
 // UsageByKey is a single API key's usage in the usage response.
@@ -149,6 +232,18 @@ type PurgeResponse struct {
 	WouldDelete any  `json:"would_delete,omitempty"`
 }
 
+// FlushResponse is the response for POST /v1/admin/flush.
+type FlushResponse struct {
+	EventsFlushed       int   `json:"events_flushed"`
+	AuditEntriesFlushed int   `json:"audit_entries_flushed"`
+	BufferRemaining     int   `json:"buffer_remaining"`
+	OutboxEnabled       bool  `json:"outbox_enabled"`
+	OutboxProcessed     int   `json:"outbox_processed"`
+	OutboxPending       int   `json:"outbox_pending"`
+	Complete            bool  `json:"complete"`
+	DurationMs          int64 `json:"duration_ms"`
+}
+
 // DecisionProofResponse is the response for GET /v1/integrity/proof/{id}.
 type DecisionProofResponse struct {
 	DecisionID  uuid.UUID `json:"decision_id"`
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a decision was flagged for review. Low-confidence and conflict
+// flags are raised by database triggers; manual flags come from the API.
+const (
+	ReviewReasonLowConfidence = "low_confidence"
+	ReviewReasonConflict      = "conflict"
+	ReviewReasonManual        = "manual"
+)
+
+// ReviewFlag is a decision's review state. A decision has at most one flag;
+// ReviewedAt is nil while the flag is pending.
+type ReviewFlag struct {
+	DecisionID uuid.UUID  `json:"decision_id"`
+	OrgID      uuid.UUID  `json:"org_id"`
+	Reason     string     `json:"reason"`
+	Note       *string    `json:"note,omitempty"`
+	FlaggedBy  *string    `json:"flagged_by,omitempty"`
+	FlaggedAt  time.Time  `json:"flagged_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy *string    `json:"reviewed_by,omitempty"`
+	ReviewNote *string    `json:"review_note,omitempty"`
+	// AssignedReviewer is the reviewer agent chosen by the org's review
+	// routing policy, if one matched.
+	AssignedReviewer *string `json:"assigned_reviewer,omitempty"`
+}
+
+// ReviewQueueItem is a pending review flag with enough of its decision to
+// triage without a second lookup. Overdue is relative to the SLA the queue
+// was requested with.
+type ReviewQueueItem struct {
+	ReviewFlag
+	AgentID      string  `json:"agent_id"`
+	DecisionType string  `json:"decision_type"`
+	Outcome      string  `json:"outcome"`
+	Confidence   float32 `json:"confidence"`
+	AgeSeconds   int64   `json:"age_seconds"`
+	Overdue      bool    `json:"overdue"`
+}
+
+// FlagDecisionRequest is the body for POST /v1/decisions/{id}/flag.
+type FlagDecisionRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+// ReviewDecisionRequest is the body for POST /v1/decisions/{id}/review.
+type ReviewDecisionRequest struct {
+	Note *string `json:"note,omitempty"`
+}
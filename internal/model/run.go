@@ -19,12 +19,13 @@ const (
 	RunStatusRunning   RunStatus = "running"
 	RunStatusCompleted RunStatus = "completed"
 	RunStatusFailed    RunStatus = "failed"
+	RunStatusAbandoned RunStatus = "abandoned" // Closed by the idle-run sweeper.
 )
 
 // IsTerminal reports whether the status represents an end state
 // from which no further transitions are allowed.
 func (s RunStatus) IsTerminal() bool {
-	return s == RunStatusCompleted || s == RunStatusFailed
+	return s == RunStatusCompleted || s == RunStatusFailed || s == RunStatusAbandoned
 }
 
 // ValidateTransition checks whether moving from the current status to next
@@ -32,10 +33,11 @@ func (s RunStatus) IsTerminal() bool {
 //
 //	running → completed
 //	running → failed
+//	running → abandoned
 //
-// Terminal states (completed, failed) reject all further transitions.
+// Terminal states (completed, failed, abandoned) reject all further transitions.
 func (s RunStatus) ValidateTransition(next RunStatus) error {
-	if s == RunStatusRunning && (next == RunStatusCompleted || next == RunStatusFailed) {
+	if s == RunStatusRunning && next.IsTerminal() {
 		return nil
 	}
 	return fmt.Errorf("invalid run status transition: %q → %q", s, next)
@@ -17,6 +17,7 @@ func TestRunStatus_IsTerminal(t *testing.T) {
 		{RunStatusRunning, false},
 		{RunStatusCompleted, true},
 		{RunStatusFailed, true},
+		{RunStatusAbandoned, true},
 	}
 	for _, tt := range tests {
 		t.Run(string(tt.status), func(t *testing.T) {
@@ -31,11 +32,13 @@ func TestRunStatus_ValidateTransition(t *testing.T) {
 			"running → completed should be allowed")
 		require.NoError(t, RunStatusRunning.ValidateTransition(RunStatusFailed),
 			"running → failed should be allowed")
+		require.NoError(t, RunStatusRunning.ValidateTransition(RunStatusAbandoned),
+			"running → abandoned should be allowed")
 	})
 
 	t.Run("terminal states reject all transitions", func(t *testing.T) {
-		terminals := []RunStatus{RunStatusCompleted, RunStatusFailed}
-		targets := []RunStatus{RunStatusRunning, RunStatusCompleted, RunStatusFailed}
+		terminals := []RunStatus{RunStatusCompleted, RunStatusFailed, RunStatusAbandoned}
+		targets := []RunStatus{RunStatusRunning, RunStatusCompleted, RunStatusFailed, RunStatusAbandoned}
 
 		for _, from := range terminals {
 			for _, to := range targets {
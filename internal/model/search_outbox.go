@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadOutboxEntry is a search outbox entry that exhausted its sync attempts
+// (AKASHI_OUTBOX_MAX_ATTEMPTS) and was moved to search_outbox_dead. It is no
+// longer retried until an admin requeues it.
+type DeadOutboxEntry struct {
+	OutboxID   int64     `json:"outbox_id"`
+	DecisionID uuid.UUID `json:"decision_id"`
+	OrgID      uuid.UUID `json:"org_id"`
+	Operation  string    `json:"operation"`
+	Attempts   int       `json:"attempts"`
+	LastError  *string   `json:"last_error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeadAt     time.Time `json:"dead_at"`
+}
+
+// RequeueDeadOutboxRequest is the request body for
+// POST /v1/admin/search-outbox/dead/requeue. Exactly one of OutboxIDs or All
+// must be set.
+type RequeueDeadOutboxRequest struct {
+	OutboxIDs []int64 `json:"outbox_ids,omitempty"`
+	All       bool    `json:"all,omitempty"`
+}
+
+// RequeueDeadOutboxResponse is the response for
+// POST /v1/admin/search-outbox/dead/requeue.
+type RequeueDeadOutboxResponse struct {
+	Requeued int `json:"requeued"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AgentSession is a server-side session opened for an agent. While it is
+// open, traces from the agent that omit X-Akashi-Session join it.
+type AgentSession struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	OrgID     uuid.UUID  `json:"org_id"`
+	AgentID   string     `json:"agent_id"`
+	StartedAt time.Time  `json:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// StartSessionRequest is the body for POST /v1/sessions. AgentID defaults
+// to the caller's agent.
+type StartSessionRequest struct {
+	AgentID string `json:"agent_id,omitempty"`
+}
+
+// SessionSummary describes one session of an agent's current decisions, as
+// listed by GET /v1/agents/{agent_id}/sessions. StartedAt and EndedAt are the
+// earliest and latest valid_from in the session.
+type SessionSummary struct {
+	SessionID     uuid.UUID `json:"session_id"`
+	DecisionCount int       `json:"decision_count"`
+	StartedAt     time.Time `json:"started_at"`
+	EndedAt       time.Time `json:"ended_at"`
+	DecisionTypes []string  `json:"decision_types"`
+}
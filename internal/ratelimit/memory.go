@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"context"
+	"hash/fnv"
 	"math"
 	"sync"
 	"time"
@@ -13,17 +14,28 @@ type bucket struct {
 	lastAccess time.Time
 }
 
+// numShards is the number of independently locked bucket maps. Every
+// authenticated request touches the limiter at least once, so a single lock
+// would serialize all traffic through it.
+const numShards = 16
+
+// shard is one lock-guarded slice of the bucket map.
+type shard struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
 // MemoryLimiter implements Limiter using an in-memory token bucket per key.
 //
 // Each key gets an independent bucket with a configurable refill rate
-// (tokens per second) and burst capacity (maximum tokens). A background
-// goroutine evicts stale entries every minute to bound memory.
+// (tokens per second) and burst capacity (maximum tokens). Buckets are spread
+// across shards by key hash so unrelated keys do not contend for a lock. A
+// background goroutine evicts stale entries every minute to bound memory.
 type MemoryLimiter struct {
 	rate  float64 // tokens added per second
 	burst float64 // maximum tokens (bucket capacity)
 
-	mu      sync.Mutex
-	buckets map[string]*bucket
+	shards [numShards]shard
 
 	stopOnce sync.Once
 	done     chan struct{}
@@ -37,10 +49,12 @@ type MemoryLimiter struct {
 // Call Close to stop it.
 func NewMemoryLimiter(rate float64, burst int) *MemoryLimiter {
 	m := &MemoryLimiter{
-		rate:    rate,
-		burst:   float64(burst),
-		buckets: make(map[string]*bucket),
-		done:    make(chan struct{}),
+		rate:  rate,
+		burst: float64(burst),
+		done:  make(chan struct{}),
+	}
+	for i := range m.shards {
+		m.shards[i].buckets = make(map[string]*bucket)
 	}
 	go m.cleanup()
 	return m
@@ -49,17 +63,36 @@ func NewMemoryLimiter(rate float64, burst int) *MemoryLimiter {
 // Allow consumes one token from the bucket for key. The returned Result
 // includes the bucket state so callers can populate rate limit headers.
 func (m *MemoryLimiter) Allow(_ context.Context, key string) (Result, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	return m.allow(key, m.rate, m.burst), nil
+}
+
+// AllowLimit is Allow with limit in place of the limiter's default. A bucket
+// holding more tokens than limit.Burst (e.g. after the limit was lowered) is
+// clamped to it.
+func (m *MemoryLimiter) AllowLimit(_ context.Context, key string, limit Limit) (Result, error) {
+	return m.allow(key, limit.RPS, float64(limit.Burst)), nil
+}
+
+// shardFor returns the shard holding key's bucket.
+func (m *MemoryLimiter) shardFor(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &m.shards[h.Sum32()%numShards]
+}
+
+func (m *MemoryLimiter) allow(key string, rate, burst float64) Result {
+	s := m.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	now := time.Now()
-	burstInt := int(m.burst)
+	burstInt := int(burst)
 
-	b, ok := m.buckets[key]
+	b, ok := s.buckets[key]
 	if !ok {
 		// First request for this key: start with a full bucket minus one token.
-		remaining := m.burst - 1
-		m.buckets[key] = &bucket{
+		remaining := burst - 1
+		s.buckets[key] = &bucket{
 			tokens:     remaining,
 			lastAccess: now,
 		}
@@ -67,15 +100,15 @@ func (m *MemoryLimiter) Allow(_ context.Context, key string) (Result, error) {
 			Allowed:   true,
 			Limit:     burstInt,
 			Remaining: int(remaining),
-			ResetAt:   m.resetAt(now, remaining),
-		}, nil
+			ResetAt:   resetAt(now, remaining, rate, burst),
+		}
 	}
 
 	// Refill tokens based on elapsed time.
 	elapsed := now.Sub(b.lastAccess).Seconds()
-	b.tokens += elapsed * m.rate
-	if b.tokens > m.burst {
-		b.tokens = m.burst
+	b.tokens += elapsed * rate
+	if b.tokens > burst {
+		b.tokens = burst
 	}
 	b.lastAccess = now
 
@@ -83,31 +116,31 @@ func (m *MemoryLimiter) Allow(_ context.Context, key string) (Result, error) {
 		// Denied — compute when the next token arrives for Retry-After,
 		// and when the bucket will be full for X-RateLimit-Reset.
 		deficit := 1 - b.tokens
-		retryAfter := time.Duration(math.Ceil(deficit/m.rate) * float64(time.Second))
+		retryAfter := time.Duration(math.Ceil(deficit/rate) * float64(time.Second))
 		return Result{
 			Allowed:    false,
 			Limit:      burstInt,
 			Remaining:  0,
-			ResetAt:    m.resetAt(now, b.tokens),
+			ResetAt:    resetAt(now, b.tokens, rate, burst),
 			RetryAfter: retryAfter,
-		}, nil
+		}
 	}
 	b.tokens--
 	return Result{
 		Allowed:   true,
 		Limit:     burstInt,
 		Remaining: int(b.tokens),
-		ResetAt:   m.resetAt(now, b.tokens),
-	}, nil
+		ResetAt:   resetAt(now, b.tokens, rate, burst),
+	}
 }
 
-// resetAt computes when the bucket will be full given the current token count.
-func (m *MemoryLimiter) resetAt(now time.Time, tokens float64) time.Time {
-	if tokens >= m.burst {
+// resetAt computes when a bucket will be full given its current token count.
+func resetAt(now time.Time, tokens, rate, burst float64) time.Time {
+	if tokens >= burst {
 		return time.Time{} // already full
 	}
-	deficit := m.burst - tokens
-	seconds := deficit / m.rate
+	deficit := burst - tokens
+	seconds := deficit / rate
 	return now.Add(time.Duration(math.Ceil(seconds) * float64(time.Second)))
 }
 
@@ -135,13 +168,15 @@ func (m *MemoryLimiter) cleanup() {
 }
 
 func (m *MemoryLimiter) evictStale() {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	cutoff := time.Now().Add(-staleThreshold)
-	for key, b := range m.buckets {
-		if b.lastAccess.Before(cutoff) {
-			delete(m.buckets, key)
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.Lock()
+		for key, b := range s.buckets {
+			if b.lastAccess.Before(cutoff) {
+				delete(s.buckets, key)
+			}
 		}
+		s.mu.Unlock()
 	}
 }
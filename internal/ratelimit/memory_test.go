@@ -2,6 +2,7 @@ package ratelimit
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -89,6 +90,28 @@ func TestMemoryLimiterIndependentKeys(t *testing.T) {
 	assert.True(t, res.Allowed, "first request for 'b' should succeed")
 }
 
+func TestMemoryLimiterAllowLimitOverridesDefault(t *testing.T) {
+	m := NewMemoryLimiter(10, 5) // default burst 5
+	defer closeLimiter(t, m)
+
+	ctx := context.Background()
+	limit := Limit{RPS: 1, Burst: 2}
+	for i := 0; i < 2; i++ {
+		res, err := m.AllowLimit(ctx, "k1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed, "expected Allowed for request %d", i)
+		assert.Equal(t, 2, res.Limit, "Limit should equal the override burst")
+	}
+	res, err := m.AllowLimit(ctx, "k1", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed, "expected denial after override burst exhausted")
+
+	// Other keys still get the default.
+	res, err = m.Allow(ctx, "k2")
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Limit)
+}
+
 func TestMemoryLimiterConcurrent(t *testing.T) {
 	m := NewMemoryLimiter(100, 50)
 	defer closeLimiter(t, m)
@@ -135,15 +158,16 @@ func TestMemoryLimiterEvictStale(t *testing.T) {
 	_, _ = m.Allow(ctx, "stale")
 
 	// Manually backdate the bucket.
-	m.mu.Lock()
-	m.buckets["stale"].lastAccess = time.Now().Add(-15 * time.Minute)
-	m.mu.Unlock()
+	s := m.shardFor("stale")
+	s.mu.Lock()
+	s.buckets["stale"].lastAccess = time.Now().Add(-15 * time.Minute)
+	s.mu.Unlock()
 
 	m.evictStale()
 
-	m.mu.Lock()
-	_, exists := m.buckets["stale"]
-	m.mu.Unlock()
+	s.mu.Lock()
+	_, exists := s.buckets["stale"]
+	s.mu.Unlock()
 
 	assert.False(t, exists, "expected stale bucket to be evicted")
 }
@@ -157,9 +181,10 @@ func TestMemoryLimiterEvictKeepsRecent(t *testing.T) {
 
 	m.evictStale()
 
-	m.mu.Lock()
-	_, exists := m.buckets["recent"]
-	m.mu.Unlock()
+	s := m.shardFor("recent")
+	s.mu.Lock()
+	_, exists := s.buckets["recent"]
+	s.mu.Unlock()
 
 	assert.True(t, exists, "expected recent bucket to survive eviction")
 }
@@ -192,9 +217,10 @@ func TestMemoryLimiterTokensCapAtBurst(t *testing.T) {
 	_, _ = m.Allow(ctx, "k1")
 
 	// Backdate so a large refill would be computed.
-	m.mu.Lock()
-	m.buckets["k1"].lastAccess = time.Now().Add(-1 * time.Hour)
-	m.mu.Unlock()
+	s := m.shardFor("k1")
+	s.mu.Lock()
+	s.buckets["k1"].lastAccess = time.Now().Add(-1 * time.Hour)
+	s.mu.Unlock()
 
 	// After refill, should be capped at burst (3). Consume 3 -> ok, 4th -> denied.
 	for i := 0; i < 3; i++ {
@@ -239,3 +265,34 @@ func TestMemoryLimiterResultHeaders(t *testing.T) {
 	assert.Equal(t, 0, res.Remaining)
 	assert.True(t, res.ResetAt.After(time.Now()), "ResetAt should be in the future")
 }
+
+func TestMemoryLimiterEvictStaleAcrossShards(t *testing.T) {
+	m := NewMemoryLimiter(10, 5)
+	defer closeLimiter(t, m)
+
+	ctx := context.Background()
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("org:o:agent:agent-%d", i)
+		_, _ = m.Allow(ctx, keys[i])
+	}
+	// Backdate every other key; they land in many different shards.
+	for i, key := range keys {
+		if i%2 == 0 {
+			s := m.shardFor(key)
+			s.mu.Lock()
+			s.buckets[key].lastAccess = time.Now().Add(-15 * time.Minute)
+			s.mu.Unlock()
+		}
+	}
+
+	m.evictStale()
+
+	for i, key := range keys {
+		s := m.shardFor(key)
+		s.mu.Lock()
+		_, exists := s.buckets[key]
+		s.mu.Unlock()
+		assert.Equal(t, i%2 != 0, exists, "key %s", key)
+	}
+}
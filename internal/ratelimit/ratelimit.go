@@ -46,6 +46,21 @@ type Limiter interface {
 	Close() error
 }
 
+// Limit is a token bucket configuration: sustained requests per second and
+// bucket capacity.
+type Limit struct {
+	RPS   float64
+	Burst int
+}
+
+// LimitOverrider is implemented by limiters that can apply a per-request
+// limit instead of their configured default, e.g. a tenant's own limit.
+// Buckets are still keyed by key alone; callers keep keys for different
+// limits distinct.
+type LimitOverrider interface {
+	AllowLimit(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
 // NoopLimiter permits every request. Used when rate limiting is disabled.
 type NoopLimiter struct{}
 
@@ -132,9 +132,16 @@ func (s *LocalSearcher) loadCandidates(ctx context.Context, orgID uuid.UUID, exc
 		}
 		q += ")"
 	}
-	if filters.DecisionType != nil {
-		q += ` AND decision_type = ?`
-		args = append(args, *filters.DecisionType)
+	if types := filters.DecisionTypeList(); len(types) > 0 {
+		q += ` AND decision_type IN (`
+		for i, t := range types {
+			if i > 0 {
+				q += ","
+			}
+			q += "?"
+			args = append(args, t)
+		}
+		q += ")"
 	}
 	if filters.ConfidenceMin != nil {
 		q += ` AND confidence >= ?`
@@ -51,29 +51,50 @@ type OutboxWorker struct {
 	logger       *slog.Logger
 	pollInterval time.Duration
 	batchSize    int
+	maxAttempts  int // failed attempts before an entry moves to search_outbox_dead
+
+	deadLettered metric.Int64Counter // entries moved to search_outbox_dead
 
 	started     atomic.Bool
 	cancelLoop  context.CancelFunc
 	done        chan struct{}
-	once        sync.Once // guards close(done)
-	drainOnce   sync.Once // guards Drain to prevent double-drain panics
+	once        sync.Once  // guards close(done)
+	drainOnce   sync.Once  // guards Drain to prevent double-drain panics
+	batchMu     sync.Mutex // serializes processBatchCount (poll loop vs FlushBacklog)
 	lastCleanup time.Time
 	drainCh     chan context.Context // carries the drain context to pollLoop for the final poll
 }
 
-// NewOutboxWorker creates a new outbox worker.
+// NewOutboxWorker creates a new outbox worker. Entries are dead-lettered
+// after maxOutboxAttempts failures unless WithMaxAttempts overrides it.
 func NewOutboxWorker(pool *pgxpool.Pool, index *QdrantIndex, logger *slog.Logger, pollInterval time.Duration, batchSize int) *OutboxWorker {
+	meter := telemetry.Meter("akashi/outbox")
+	deadLettered, _ := meter.Int64Counter("akashi.outbox.dead_lettered",
+		metric.WithDescription("Search outbox entries moved to search_outbox_dead after exhausting their attempts"),
+	)
 	return &OutboxWorker{
 		pool:         pool,
 		index:        index,
 		logger:       logger,
 		pollInterval: pollInterval,
 		batchSize:    batchSize,
+		maxAttempts:  maxOutboxAttempts,
+		deadLettered: deadLettered,
 		done:         make(chan struct{}),
 		drainCh:      make(chan context.Context, 1),
 	}
 }
 
+// WithMaxAttempts sets how many failed attempts an entry gets before it is
+// moved to search_outbox_dead and no longer retried. Values below 1 are
+// ignored. Must be called before Start.
+func (w *OutboxWorker) WithMaxAttempts(n int) *OutboxWorker {
+	if n > 0 {
+		w.maxAttempts = n
+	}
+	return w
+}
+
 // Start begins the background poll loop. It is safe to call only once;
 // subsequent calls are no-ops and log a warning.
 func (w *OutboxWorker) Start(ctx context.Context) {
@@ -148,22 +169,47 @@ func (w *OutboxWorker) pollLoop(ctx context.Context) {
 }
 
 // drainOutbox processes batches in a loop until the outbox is empty or ctx expires.
-func (w *OutboxWorker) drainOutbox(ctx context.Context) {
+// Returns the total number of entries processed.
+func (w *OutboxWorker) drainOutbox(ctx context.Context) int {
+	total := 0
 	for {
 		if ctx.Err() != nil {
 			w.logger.Warn("search outbox: drain deadline exceeded, remaining entries will sync on next startup")
-			return
+			return total
 		}
 		remaining := w.processBatchCount(ctx)
 		if remaining == 0 {
-			return
+			return total
 		}
+		total += remaining
 		w.logger.Info("search outbox: drain batch processed", "processed", remaining)
 	}
 }
 
-// maxOutboxAttempts must match the partial index predicate in migration 023
-// (WHERE attempts < 10). Changing this value requires a new migration.
+// FlushBacklog synchronously processes pending outbox entries until none are
+// eligible or ctx expires, using the same loop as the shutdown drain but
+// leaving the poll loop running. Returns the number of entries processed and
+// the number still pending afterwards (backing off after a failure, or left
+// over when ctx expired).
+func (w *OutboxWorker) FlushBacklog(ctx context.Context) (processed, pending int, err error) {
+	processed = w.drainOutbox(ctx)
+	if w.pool == nil {
+		return processed, 0, nil
+	}
+
+	// Count with a fresh deadline so an expired flush still reports the backlog.
+	countCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := w.pool.QueryRow(countCtx,
+		`SELECT count(*) FROM search_outbox WHERE attempts < $1`,
+		w.maxAttempts,
+	).Scan(&pending); err != nil {
+		return processed, 0, fmt.Errorf("search outbox: count pending: %w", err)
+	}
+	return processed, pending, nil
+}
+
+// maxOutboxAttempts is the default attempt cap (AKASHI_OUTBOX_MAX_ATTEMPTS).
 const maxOutboxAttempts = 10
 
 // processBatch processes a single batch of outbox entries.
@@ -176,6 +222,11 @@ func (w *OutboxWorker) processBatch(ctx context.Context) {
 // processed (0 when the outbox is empty or on error). Used by drainOutbox to
 // know when to stop looping.
 func (w *OutboxWorker) processBatchCount(ctx context.Context) int {
+	// Serialize batches: FlushBacklog may run concurrently with the poll loop,
+	// and lastCleanup is not otherwise synchronized.
+	w.batchMu.Lock()
+	defer w.batchMu.Unlock()
+
 	if w.pool == nil {
 		w.logger.Warn("search outbox: skipping batch, pool is nil")
 		return 0
@@ -201,7 +252,7 @@ func (w *OutboxWorker) processBatchCount(ctx context.Context) int {
 		 ORDER BY created_at ASC
 		 LIMIT $2
 		 FOR UPDATE SKIP LOCKED`,
-		maxOutboxAttempts, w.batchSize,
+		w.maxAttempts, w.batchSize,
 	)
 	if err != nil {
 		w.logger.Error("search outbox: select pending", "error", err)
@@ -256,7 +307,8 @@ func (w *OutboxWorker) processBatchCount(ctx context.Context) int {
 		w.processDeletes(ctx, deletes)
 	}
 
-	// Periodically clean up dead-letter entries (attempts >= max, older than 7 days).
+	// Periodically sweep exhausted entries that were not moved when they
+	// failed (e.g. after AKASHI_OUTBOX_MAX_ATTEMPTS was lowered).
 	if time.Since(w.lastCleanup) > time.Hour {
 		w.cleanupDeadLetters(ctx)
 		w.lastCleanup = time.Now()
@@ -265,85 +317,75 @@ func (w *OutboxWorker) processBatchCount(ctx context.Context) int {
 	return len(entries)
 }
 
-// cleanupDeadLetters archives exhausted outbox entries (attempts >= max, older
-// than 7 days) into search_outbox_dead_letters and removes them from the main
-// outbox table.
-//
-// Concurrency safety: FOR UPDATE SKIP LOCKED in the archive CTE ensures that
-// concurrent OutboxWorker instances operating on different entries never
-// conflict. The DELETE targets only the rows archived by this transaction
-// (via RETURNING ids) to avoid cross-transaction contention (#75).
-func (w *OutboxWorker) cleanupDeadLetters(ctx context.Context) {
-	tx, err := w.pool.Begin(ctx)
-	if err != nil {
-		w.logger.Error("search outbox: begin dead-letter cleanup tx failed", "error", err)
-		return
-	}
-	defer func() { _ = tx.Rollback(ctx) }()
-
-	// Archive candidates and capture their IDs in one statement.
-	rows, err := tx.Query(ctx,
-		`WITH candidates AS (
-		    SELECT id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
-		    FROM search_outbox
-		    WHERE attempts >= $1
-		      AND (locked_until IS NULL OR locked_until < now())
-		      AND created_at < now() - interval '7 days'
-		    FOR UPDATE SKIP LOCKED
-		),
-		archived AS (
-		    INSERT INTO search_outbox_dead_letters (
-		        outbox_id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
-		    )
-		    SELECT id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
-		    FROM candidates
-		    ON CONFLICT (outbox_id) DO NOTHING
-		    RETURNING outbox_id
-		)
-		SELECT outbox_id FROM archived`,
-		maxOutboxAttempts,
+// deadLetterSQL moves the search_outbox rows chosen by the candidates CTE
+// into search_outbox_dead and appends a copy to the immutable
+// search_outbox_dead_letters archive, all in one statement. The %s
+// placeholder is the candidates WHERE clause; $1 is always the attempt cap.
+const deadLetterSQL = `
+	WITH candidates AS (
+	    SELECT id FROM search_outbox
+	    WHERE attempts >= $1 AND %s
+	    FOR UPDATE SKIP LOCKED
+	),
+	dead AS (
+	    DELETE FROM search_outbox o
+	    USING candidates c
+	    WHERE o.id = c.id
+	    RETURNING o.id, o.decision_id, o.org_id, o.operation, o.attempts, o.last_error, o.created_at, o.locked_until
+	),
+	archived AS (
+	    INSERT INTO search_outbox_dead_letters (
+	        outbox_id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
+	    )
+	    SELECT id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
+	    FROM dead
+	    ON CONFLICT (outbox_id) DO NOTHING
 	)
-	if err != nil {
-		w.logger.Error("search outbox: archive dead-letters failed", "error", err)
-		return
-	}
+	INSERT INTO search_outbox_dead (outbox_id, decision_id, org_id, operation, attempts, last_error, created_at)
+	SELECT id, decision_id, org_id, operation, attempts, last_error, created_at
+	FROM dead
+	ON CONFLICT (outbox_id) DO NOTHING
+	RETURNING outbox_id, decision_id, org_id, operation, attempts`
+
+// deadLetterEntries moves the given entries to search_outbox_dead if they
+// have reached the attempt cap. Called right after failEntries increments
+// attempts, so an exhausted entry is never selected again.
+func (w *OutboxWorker) deadLetterEntries(ctx context.Context, ids []int64) {
+	w.moveToDead(ctx, fmt.Sprintf(deadLetterSQL, "id = ANY($2)"), w.maxAttempts, ids)
+}
 
-	var archivedIDs []int64
-	for rows.Next() {
-		var id int64
-		if scanErr := rows.Scan(&id); scanErr != nil {
-			w.logger.Error("search outbox: scan archived id", "error", scanErr)
-			rows.Close()
-			return
-		}
-		archivedIDs = append(archivedIDs, id)
-	}
-	if err := rows.Err(); err != nil {
-		w.logger.Error("search outbox: rows error after archive scan", "error", err)
-		return
-	}
+// cleanupDeadLetters moves any exhausted entries still in search_outbox to
+// search_outbox_dead. These are entries whose move failed when they were
+// last retried, or that became exhausted because AKASHI_OUTBOX_MAX_ATTEMPTS
+// was lowered. Entries still locked by an in-flight batch are left for the
+// next sweep.
+func (w *OutboxWorker) cleanupDeadLetters(ctx context.Context) {
+	w.moveToDead(ctx, fmt.Sprintf(deadLetterSQL, "(locked_until IS NULL OR locked_until < now())"), w.maxAttempts)
+}
 
-	if len(archivedIDs) == 0 {
+// moveToDead runs a deadLetterSQL query, logs each dead-lettered entry, and
+// records the count on the akashi.outbox.dead_lettered counter.
+func (w *OutboxWorker) moveToDead(ctx context.Context, query string, args ...any) {
+	rows, err := w.pool.Query(ctx, query, args...)
+	if err != nil {
+		w.logger.Error("search outbox: move entries to dead-letter table", "error", err)
 		return
 	}
-
-	// Delete only the rows we just archived — no re-query of the WHERE clause.
-	tag, err := tx.Exec(ctx,
-		`DELETE FROM search_outbox WHERE id = ANY($1)`,
-		archivedIDs,
-	)
+	entries, err := scanOutboxEntries(rows)
 	if err != nil {
-		w.logger.Error("search outbox: delete archived dead-letters failed", "error", err)
+		w.logger.Error("search outbox: scan dead-lettered entries", "error", err)
 		return
 	}
-
-	if err := tx.Commit(ctx); err != nil {
-		w.logger.Error("search outbox: commit dead-letter cleanup failed", "error", err)
-		return
+	for _, e := range entries {
+		w.logger.Warn("search outbox: dead-letter entry",
+			"outbox_id", e.ID,
+			"decision_id", e.DecisionID,
+			"operation", e.Operation,
+			"attempts", e.Attempts,
+		)
 	}
-
-	if tag.RowsAffected() > 0 {
-		w.logger.Info("search outbox: archived and cleaned dead-letter entries", "deleted", tag.RowsAffected())
+	if len(entries) > 0 && w.deadLettered != nil {
+		w.deadLettered.Add(ctx, int64(len(entries)))
 	}
 }
 
@@ -402,10 +444,10 @@ func (w *OutboxWorker) processUpserts(ctx context.Context, entries []outboxEntry
 	if len(pendingEntries) > 0 {
 		// Entries with no current embedding (or whose decision row is not visible yet).
 		// Defer with incrementing attempts so we eventually dead-letter if backfill never runs.
-		// 30-minute backoff gives backfill time; after 10 cycles we surface for ops investigation.
+		// 30-minute backoff gives backfill time; on the last attempt we dead-letter for ops investigation.
 		var toDefer, toFail []outboxEntry
 		for _, e := range pendingEntries {
-			if e.Attempts >= maxOutboxAttempts-1 {
+			if e.Attempts >= w.maxAttempts-1 {
 				toFail = append(toFail, e)
 			} else {
 				toDefer = append(toDefer, e)
@@ -449,7 +491,7 @@ func (w *OutboxWorker) succeedEntries(ctx context.Context, entries []outboxEntry
 }
 
 // deferPendingEntries defers entries, incrementing attempts and using a 30-minute
-// backoff. Entries that exceed maxOutboxAttempts are routed to failEntries instead.
+// backoff. Entries on their last attempt are routed to failEntries instead.
 // This prevents infinite defer loops when backfill never runs (e.g. noop embedder).
 func (w *OutboxWorker) deferPendingEntries(ctx context.Context, entries []outboxEntry, errMsg string) {
 	ids := make([]int64, len(entries))
@@ -487,17 +529,16 @@ func (w *OutboxWorker) failEntries(ctx context.Context, entries []outboxEntry, e
 		w.logger.Error("search outbox: update failed entries", "error", err)
 	}
 
-	// Log dead-letter entries (attempts >= maxOutboxAttempts after increment).
+	// Move entries that just used their last attempt to search_outbox_dead.
+	var exhausted []int64
 	for _, e := range entries {
-		if e.Attempts+1 >= maxOutboxAttempts {
-			w.logger.Warn("search outbox: dead-letter entry",
-				"outbox_id", e.ID,
-				"decision_id", e.DecisionID,
-				"operation", e.Operation,
-				"attempts", e.Attempts+1,
-			)
+		if e.Attempts+1 >= w.maxAttempts {
+			exhausted = append(exhausted, e.ID)
 		}
 	}
+	if len(exhausted) > 0 {
+		w.deadLetterEntries(ctx, exhausted)
+	}
 }
 
 func (w *OutboxWorker) fetchDecisionsForIndex(ctx context.Context, ids, orgIDs []uuid.UUID) ([]DecisionForIndex, error) {
@@ -247,11 +247,26 @@ func getOutboxEntry(ctx context.Context, t *testing.T, id int64) (attempts int,
 	return
 }
 
-// cleanOutbox removes all entries from search_outbox to ensure test isolation.
+// deadOutboxEntryExists checks if an entry with the given outbox ID was
+// moved to search_outbox_dead.
+func deadOutboxEntryExists(ctx context.Context, t *testing.T, id int64) bool {
+	t.Helper()
+	var exists bool
+	err := testPool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM search_outbox_dead WHERE outbox_id = $1)`, id,
+	).Scan(&exists)
+	require.NoError(t, err)
+	return exists
+}
+
+// cleanOutbox removes all entries from search_outbox and search_outbox_dead
+// to ensure test isolation.
 func cleanOutbox(ctx context.Context, t *testing.T) {
 	t.Helper()
 	_, err := testPool.Exec(ctx, `DELETE FROM search_outbox`)
 	require.NoError(t, err)
+	_, err = testPool.Exec(ctx, `DELETE FROM search_outbox_dead`)
+	require.NoError(t, err)
 }
 
 // newTestWorker creates an OutboxWorker with the test pool and nil index.
@@ -515,25 +530,42 @@ func TestCleanupDeadLetters(t *testing.T) {
 	decID2 := uuid.New()
 	decID3 := uuid.New()
 
-	// Old dead-letter entry: max attempts, created 8 days ago. Should be cleaned.
+	// Old dead-letter entry: max attempts, created 8 days ago. Should be moved.
 	id1 := insertOutboxEntryOld(ctx, t, decID1, orgID, "upsert", maxOutboxAttempts, 8*24*time.Hour)
 
-	// Recent dead-letter entry: max attempts, created 1 day ago. Should NOT be cleaned
-	// (less than 7 days old).
+	// Recent dead-letter entry: max attempts, created 1 day ago. Should also be
+	// moved; age no longer matters.
 	id2 := insertOutboxEntryOld(ctx, t, decID2, orgID, "upsert", maxOutboxAttempts, 1*24*time.Hour)
 
-	// Old entry but below max attempts: created 8 days ago, 5 attempts. Should NOT be cleaned.
+	// Old entry but below max attempts: created 8 days ago, 5 attempts. Should NOT be moved.
 	id3 := insertOutboxEntryOld(ctx, t, decID3, orgID, "upsert", 5, 8*24*time.Hour)
 
 	w := newTestWorker()
 	w.cleanupDeadLetters(ctx)
 
 	assert.False(t, outboxEntryExists(ctx, t, id1),
-		"old dead-letter entry (max attempts, >7 days) should be removed")
-	assert.True(t, outboxEntryExists(ctx, t, id2),
-		"recent dead-letter entry (max attempts, <7 days) should be kept")
+		"old dead-letter entry should be removed from the outbox")
+	assert.True(t, deadOutboxEntryExists(ctx, t, id1))
+	assert.False(t, outboxEntryExists(ctx, t, id2),
+		"recent dead-letter entry should be removed from the outbox")
+	assert.True(t, deadOutboxEntryExists(ctx, t, id2))
 	assert.True(t, outboxEntryExists(ctx, t, id3),
 		"old entry with low attempts should be kept")
+	assert.False(t, deadOutboxEntryExists(ctx, t, id3))
+}
+
+func TestCleanupDeadLetters_LoweredMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	cleanOutbox(ctx, t)
+
+	// Five attempts is below the default cap but exhausted under a cap of 3.
+	id := insertOutboxEntry(ctx, t, uuid.New(), defaultOrgID, "upsert", 5)
+
+	w := newTestWorker().WithMaxAttempts(3)
+	w.cleanupDeadLetters(ctx)
+
+	assert.False(t, outboxEntryExists(ctx, t, id))
+	assert.True(t, deadOutboxEntryExists(ctx, t, id))
 }
 
 func TestCleanupDeadLetters_NoEntries(t *testing.T) {
@@ -831,9 +863,10 @@ func TestDeferPendingEntries_MultipleEntries(t *testing.T) {
 	assert.Equal(t, "backfill pending", *lastErr2)
 }
 
-func TestFailEntries_DeadLetterLogging(t *testing.T) {
-	// When an entry's attempts + 1 >= maxOutboxAttempts, it becomes a dead-letter.
-	// This test verifies the entry is still updated correctly even at the threshold.
+func TestFailEntries_MovesToDeadLetter(t *testing.T) {
+	// When an entry's attempts + 1 >= maxOutboxAttempts, it becomes a dead-letter:
+	// it leaves search_outbox and lands in search_outbox_dead with its last
+	// error, and a copy is archived in search_outbox_dead_letters.
 	ctx := context.Background()
 	cleanOutbox(ctx, t)
 
@@ -845,14 +878,50 @@ func TestFailEntries_DeadLetterLogging(t *testing.T) {
 		{ID: id, DecisionID: decID, OrgID: defaultOrgID, Operation: "upsert", Attempts: maxOutboxAttempts - 1},
 	}, "final failure")
 
-	attempts, lastErr, lockedUntil := getOutboxEntry(ctx, t, id)
+	assert.False(t, outboxEntryExists(ctx, t, id), "dead-lettered entry should leave the outbox")
+
+	var attempts int
+	var lastErr *string
+	err := testPool.QueryRow(ctx,
+		`SELECT attempts, last_error FROM search_outbox_dead WHERE outbox_id = $1`, id,
+	).Scan(&attempts, &lastErr)
+	require.NoError(t, err)
 	assert.Equal(t, maxOutboxAttempts, attempts, "should reach max attempts")
 	require.NotNil(t, lastErr)
 	assert.Equal(t, "final failure", *lastErr)
-	require.NotNil(t, lockedUntil)
-	// At max attempts, backoff = LEAST(2^10, 300) = 300 seconds = 5 minutes.
-	assert.True(t, lockedUntil.After(time.Now().Add(4*time.Minute)),
-		"dead-letter entry should have max backoff (~5 min)")
+
+	var archived bool
+	err = testPool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM search_outbox_dead_letters WHERE outbox_id = $1)`, id,
+	).Scan(&archived)
+	require.NoError(t, err)
+	assert.True(t, archived, "dead-lettered entry should be archived")
+}
+
+func TestFailEntries_RepeatedFailuresReachDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	cleanOutbox(ctx, t)
+
+	decID := uuid.New()
+	id := insertOutboxEntry(ctx, t, decID, defaultOrgID, "upsert", 0)
+
+	const maxAttempts = 3
+	w := newTestWorker().WithMaxAttempts(maxAttempts)
+	for i := range maxAttempts {
+		require.True(t, outboxEntryExists(ctx, t, id), "entry should still be retried before attempt %d", i+1)
+		attempts, _, _ := getOutboxEntry(ctx, t, id)
+		w.failEntries(ctx, []outboxEntry{
+			{ID: id, DecisionID: decID, OrgID: defaultOrgID, Operation: "upsert", Attempts: attempts},
+		}, fmt.Sprintf("qdrant unavailable (%d)", i+1))
+	}
+
+	assert.False(t, outboxEntryExists(ctx, t, id), "entry should stop being retried after the cap")
+	var lastErr string
+	err := testPool.QueryRow(ctx,
+		`SELECT last_error FROM search_outbox_dead WHERE outbox_id = $1`, id,
+	).Scan(&lastErr)
+	require.NoError(t, err)
+	assert.Equal(t, "qdrant unavailable (3)", lastErr)
 }
 
 func TestCleanupDeadLetters_LockedEntryNotCleaned(t *testing.T) {
@@ -1008,8 +1077,15 @@ func TestProcessBatch_WithIndex_PendingMaxAttempts(t *testing.T) {
 	defer cancel()
 	w.processBatch(batchCtx)
 
-	// At max-1 attempts, the pending entry should be failed (not deferred).
-	attempts, lastErr, _ := getOutboxEntry(ctx, t, id)
+	// At max-1 attempts, the pending entry should be failed (not deferred),
+	// which dead-letters it.
+	assert.False(t, outboxEntryExists(ctx, t, id))
+	var attempts int
+	var lastErr *string
+	err := testPool.QueryRow(ctx,
+		`SELECT attempts, last_error FROM search_outbox_dead WHERE outbox_id = $1`, id,
+	).Scan(&attempts, &lastErr)
+	require.NoError(t, err)
 	assert.Equal(t, maxOutboxAttempts, attempts)
 	require.NotNil(t, lastErr)
 	assert.Contains(t, *lastErr, "not ready after max defer cycles")
@@ -1055,9 +1131,10 @@ func TestProcessBatch_TriggersCleanup(t *testing.T) {
 	defer cancel()
 	w.processBatch(batchCtx)
 
-	// The dead-letter entry should have been cleaned up.
+	// The dead-letter entry should have been moved out of the outbox.
 	assert.False(t, outboxEntryExists(ctx, t, deadLetterID),
 		"old dead-letter entry should be cleaned during processBatch")
+	assert.True(t, deadOutboxEntryExists(ctx, t, deadLetterID))
 }
 
 func TestOutboxWorker_FullCycleWithIndex(t *testing.T) {
@@ -170,6 +170,37 @@ func (q *QdrantIndex) EnsureCollection(ctx context.Context) error {
 	return nil
 }
 
+// CollectionDims returns the vector size of the existing collection, or 0 if
+// the collection does not exist.
+func (q *QdrantIndex) CollectionDims(ctx context.Context) (uint64, error) {
+	exists, err := q.client.CollectionExists(ctx, q.collection)
+	if err != nil {
+		return 0, fmt.Errorf("search: check collection exists: %w", err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	info, err := q.client.GetCollectionInfo(ctx, q.collection)
+	if err != nil {
+		return 0, fmt.Errorf("search: get collection %q: %w", q.collection, err)
+	}
+	return info.GetConfig().GetParams().GetVectorsConfig().GetParams().GetSize(), nil
+}
+
+// Dims returns the vector size this index creates collections with.
+func (q *QdrantIndex) Dims() uint64 { return q.dims }
+
+// RecreateCollection drops the collection and creates it again with the
+// configured dims. Every point in every org is lost; callers must re-sync
+// decisions afterwards (re-embedding queues each one through the outbox).
+func (q *QdrantIndex) RecreateCollection(ctx context.Context) error {
+	if err := q.client.DeleteCollection(ctx, q.collection); err != nil {
+		return fmt.Errorf("search: delete collection %q: %w", q.collection, err)
+	}
+	q.logger.Warn("qdrant: dropped collection for recreation", "collection", q.collection)
+	return q.EnsureCollection(ctx)
+}
+
 // FindSimilar returns decision IDs with embeddings similar to the given embedding
 // within an org. Used internally for conflict detection and consensus scoring.
 // excludeID is stripped from results in Go (simpler than a Qdrant filter for one ID).
@@ -247,8 +278,10 @@ func (q *QdrantIndex) Search(ctx context.Context, orgID uuid.UUID, embedding []f
 		must = append(must, qdrant.NewMatchKeywords("agent_id", filters.AgentIDs...))
 	}
 
-	if filters.DecisionType != nil {
-		must = append(must, qdrant.NewMatch("decision_type", *filters.DecisionType))
+	if types := filters.DecisionTypeList(); len(types) == 1 {
+		must = append(must, qdrant.NewMatch("decision_type", types[0]))
+	} else if len(types) > 1 {
+		must = append(must, qdrant.NewMatchKeywords("decision_type", types...))
 	}
 
 	if filters.ConfidenceMin != nil {
@@ -166,6 +166,29 @@ func TestReScoreTruncatesAtLimit(t *testing.T) {
 	assert.Equal(t, id1, scored[0].Decision.ID)
 }
 
+func TestReScore_RecencyHalfLife(t *testing.T) {
+	now := time.Now()
+	oldStrong, recentWeak := uuid.New(), uuid.New()
+	decisions := map[uuid.UUID]model.Decision{
+		oldStrong:  {ID: oldStrong, ValidFrom: now.Add(-365 * 24 * time.Hour)},
+		recentWeak: {ID: recentWeak, ValidFrom: now},
+	}
+	results := []Result{
+		{DecisionID: oldStrong, Score: 0.9},
+		{DecisionID: recentWeak, Score: 0.5},
+	}
+
+	noDecay, aggressive := 0.0, 1.0
+	scored := ReScore(results, decisions, 10, &ReScoreOpts{RecencyHalfLifeDays: &noDecay})
+	require.Len(t, scored, 2)
+	assert.Equal(t, oldStrong, scored[0].Decision.ID, "without decay the stronger match wins")
+	assert.InDelta(t, 0.9*0.575, scored[0].SimilarityScore, 0.001)
+
+	scored = ReScore(results, decisions, 10, &ReScoreOpts{RecencyHalfLifeDays: &aggressive})
+	require.Len(t, scored, 2)
+	assert.Equal(t, recentWeak, scored[0].Decision.ID, "aggressive decay favors the recent match")
+}
+
 func TestBuildQdrantFilter(t *testing.T) {
 	// This test verifies the filter building logic by checking that the correct
 	// number and types of conditions are generated for various QueryFilters inputs.
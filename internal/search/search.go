@@ -54,6 +54,9 @@ type ReScoreOpts struct {
 	Percentiles *OrgPercentiles // When non-nil, citation scores use empirical percentile normalization.
 	Metrics     *ReScoreMetrics // When non-nil, per-signal contribution histograms are recorded.
 	Ctx         context.Context // Required when Metrics is non-nil.
+	// RecencyHalfLifeDays overrides the 90-day recency half-life; 0 disables
+	// recency decay. nil keeps the default.
+	RecencyHalfLifeDays *float64
 }
 
 // ReScore adjusts raw similarity scores with outcome signals and recency weighting,
@@ -92,6 +95,10 @@ type ReScoreOpts struct {
 func ReScore(results []Result, decisions map[uuid.UUID]model.Decision, limit int, opts *ReScoreOpts) []model.SearchResult {
 	now := time.Now()
 	scored := make([]model.SearchResult, 0, len(results))
+	halfLife := model.DefaultRecencyHalfLifeDays
+	if opts != nil && opts.RecencyHalfLifeDays != nil {
+		halfLife = *opts.RecencyHalfLifeDays
+	}
 
 	for _, r := range results {
 		d, ok := decisions[r.DecisionID]
@@ -145,8 +152,11 @@ func ReScore(results []Result, decisions map[uuid.UUID]model.Decision, limit int
 			opts.Metrics.Record(opts.Ctx, assessmentContrib, 0.25*citationScore, 0.15*stabilityScore, 0.10*agreementScore, conflictContrib)
 		}
 
-		ageDays := math.Max(0, now.Sub(d.ValidFrom).Hours()/24.0)
-		recencyDecay := 1.0 / (1.0 + ageDays/90.0)
+		recencyDecay := 1.0
+		if halfLife > 0 {
+			ageDays := math.Max(0, now.Sub(d.ValidFrom).Hours()/24.0)
+			recencyDecay = 1.0 / (1.0 + ageDays/halfLife)
+		}
 		// Completeness removed: field-filling quality ≠ decision correctness.
 		relevance := float64(r.Score) * (0.5 + 0.5*outcomeWeight) * recencyDecay
 
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+const (
+	// accessLogBatchSize triggers an early flush once this many entries are
+	// buffered, so a busy server does not wait for the next tick.
+	accessLogBatchSize = 500
+	// accessLogMaxBuffered bounds memory when Postgres is slow or down.
+	// Entries beyond it are dropped (and counted) instead of blocking requests.
+	accessLogMaxBuffered = 50_000
+	// accessLogFlushTimeout bounds each batch write.
+	accessLogFlushTimeout = 10 * time.Second
+)
+
+// AccessLogConfig controls the optional access log. The zero value disables
+// it. Enabled records which decisions each read returned; Requests records
+// every authenticated request on any route. Both write to access_audit_log.
+type AccessLogConfig struct {
+	Enabled       bool
+	SampleRate    float64       // Fraction of decision reads recorded (0.0–1.0).
+	MaxIDs        int           // Max resource IDs stored per entry; the full count is kept separately.
+	Requests      bool          // Record every authenticated request (method, path, status, latency).
+	FlushInterval time.Duration // How often buffered entries are written. Zero = 1s.
+}
+
+// accessLogger records access into access_audit_log. Entries are buffered
+// and written in batches, like the event buffer, so requests never wait on
+// an insert. A failed batch is retried on the next flush while there is
+// room; recording is best-effort beyond that and never fails a request.
+type accessLogger struct {
+	insert        func(ctx context.Context, entries []storage.AccessAuditEntry) error
+	reads         bool
+	requests      bool
+	sampleRate    float64
+	maxIDs        int
+	flushInterval time.Duration
+	logger        *slog.Logger
+
+	mu      sync.Mutex
+	entries []storage.AccessAuditEntry
+	flushMu sync.Mutex // serializes flushes so retried batches keep their order
+	dropped atomic.Int64
+
+	flushCh  chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newAccessLogger returns nil when the access log is disabled; all methods
+// are nil-safe so handlers can call record unconditionally. The flush loop
+// runs until close.
+func newAccessLogger(cfg AccessLogConfig, db *storage.DB, logger *slog.Logger) *accessLogger {
+	if (!cfg.Enabled && !cfg.Requests) || db == nil {
+		return nil
+	}
+	a := newAccessLoggerWith(cfg, db.InsertAccessAuditBatch, logger)
+	go a.flushLoop()
+	return a
+}
+
+// newAccessLoggerWith builds a logger around insert without starting the
+// flush loop.
+func newAccessLoggerWith(cfg AccessLogConfig, insert func(context.Context, []storage.AccessAuditEntry) error, logger *slog.Logger) *accessLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	maxIDs := cfg.MaxIDs
+	if maxIDs <= 0 {
+		maxIDs = 100
+	}
+	interval := cfg.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &accessLogger{
+		insert:        insert,
+		reads:         cfg.Enabled,
+		requests:      cfg.Requests,
+		sampleRate:    cfg.SampleRate,
+		maxIDs:        maxIDs,
+		flushInterval: interval,
+		logger:        logger,
+		flushCh:       make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// sampled reports whether the current read should be recorded.
+func (a *accessLogger) sampled() bool {
+	if a.sampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < a.sampleRate
+}
+
+// record buffers a decision-read entry. Safe to call on a nil receiver.
+func (a *accessLogger) record(entry storage.AccessAuditEntry) {
+	if a == nil || !a.reads || !a.sampled() {
+		return
+	}
+	entry.ResultCount = len(entry.ResourceIDs)
+	if len(entry.ResourceIDs) > a.maxIDs {
+		entry.ResourceIDs = entry.ResourceIDs[:a.maxIDs]
+	}
+	a.add(entry)
+}
+
+// add buffers an entry. It never blocks on I/O.
+func (a *accessLogger) add(e storage.AccessAuditEntry) {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+	a.mu.Lock()
+	if len(a.entries) >= accessLogMaxBuffered {
+		a.mu.Unlock()
+		if n := a.dropped.Add(1); n == 1 || n%1000 == 0 {
+			a.logger.Warn("access log: dropping entries, buffer full", "dropped_total", n)
+		}
+		return
+	}
+	a.entries = append(a.entries, e)
+	full := len(a.entries) >= accessLogBatchSize
+	a.mu.Unlock()
+
+	if full {
+		select {
+		case a.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (a *accessLogger) flushLoop() {
+	defer close(a.done)
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+		case <-a.flushCh:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), accessLogFlushTimeout)
+		if err := a.flush(ctx); err != nil {
+			a.logger.Warn("access log: flush failed", "error", err)
+		}
+		cancel()
+	}
+}
+
+// flush writes everything buffered so far. On failure the batch goes back to
+// the front of the buffer, up to its capacity, for the next attempt.
+func (a *accessLogger) flush(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	a.flushMu.Lock()
+	defer a.flushMu.Unlock()
+
+	a.mu.Lock()
+	batch := a.entries
+	a.entries = nil
+	a.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := a.insert(ctx, batch)
+	if err == nil {
+		return nil
+	}
+	a.mu.Lock()
+	keep := min(len(batch), accessLogMaxBuffered-len(a.entries))
+	a.entries = append(batch[:keep:keep], a.entries...)
+	a.mu.Unlock()
+	if lost := len(batch) - keep; lost > 0 {
+		a.dropped.Add(int64(lost))
+	}
+	return err
+}
+
+// close stops the flush loop and writes what remains. Safe to call more than
+// once and on a nil receiver.
+func (a *accessLogger) close(ctx context.Context) error {
+	if a == nil {
+		return nil
+	}
+	a.stopOnce.Do(func() {
+		close(a.stop)
+		<-a.done
+	})
+	return a.flush(ctx)
+}
+
+// accessLogMiddleware records every authenticated request when per-request
+// logging is on. It runs just inside authMiddleware, where claims are set;
+// unauthenticated requests (health checks, login, rejected tokens) are not
+// recorded.
+func accessLogMiddleware(a *accessLogger, next http.Handler) http.Handler {
+	if a == nil || !a.requests {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := ClaimsFromContext(r.Context())
+		if claims == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		status := sw.statusCode
+		latencyMs := float64(time.Since(start).Microseconds()) / 1000
+		a.add(storage.AccessAuditEntry{
+			OccurredAt:   start.UTC(),
+			RequestID:    RequestIDFromContext(r.Context()),
+			OrgID:        claims.OrgID,
+			ActorAgentID: claims.AgentID,
+			ActorRole:    string(claims.Role),
+			HTTPMethod:   r.Method,
+			Endpoint:     r.URL.Path,
+			ResourceType: model.AccessResourceRequest,
+			Status:       &status,
+			LatencyMs:    &latencyMs,
+		})
+	})
+}
+
+// recordAccess logs that the caller read the given decisions. No-op unless
+// the access log is enabled.
+func (h *Handlers) recordAccess(r *http.Request, orgID uuid.UUID, resourceType string, ids []uuid.UUID, metadata map[string]any) {
+	if h.accessLog == nil {
+		return
+	}
+	claims := ClaimsFromContext(r.Context())
+	actorID := "unknown"
+	actorRole := "unknown"
+	if claims != nil {
+		actorID = claims.AgentID
+		actorRole = string(claims.Role)
+	}
+	h.accessLog.record(storage.AccessAuditEntry{
+		RequestID:    RequestIDFromContext(r.Context()),
+		OrgID:        orgID,
+		ActorAgentID: actorID,
+		ActorRole:    actorRole,
+		HTTPMethod:   r.Method,
+		Endpoint:     r.URL.Path,
+		ResourceType: resourceType,
+		ResourceIDs:  ids,
+		Metadata:     metadata,
+	})
+}
+
+// decisionIDs extracts IDs from a decision slice for access logging.
+func decisionIDs(decisions []model.Decision) []uuid.UUID {
+	ids := make([]uuid.UUID, len(decisions))
+	for i := range decisions {
+		ids[i] = decisions[i].ID
+	}
+	return ids
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/auth"
+	"github.com/ashita-ai/akashi/internal/ctxutil"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// capturingAccessLogger returns an accessLogger whose batches are collected in
+// memory instead of going to Postgres. The flush loop is not started; tests
+// call flush directly.
+func capturingAccessLogger(cfg AccessLogConfig) (*accessLogger, func() []storage.AccessAuditEntry) {
+	var (
+		mu      sync.Mutex
+		entries []storage.AccessAuditEntry
+	)
+	a := newAccessLoggerWith(cfg, func(_ context.Context, batch []storage.AccessAuditEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, batch...)
+		return nil
+	}, testLogger())
+	return a, func() []storage.AccessAuditEntry {
+		_ = a.flush(context.Background())
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]storage.AccessAuditEntry(nil), entries...)
+	}
+}
+
+func TestAccessLogger_DisabledIsNil(t *testing.T) {
+	assert.Nil(t, newAccessLogger(AccessLogConfig{}, nil, nil))
+
+	// Methods are nil-safe so handlers can call them unconditionally.
+	var a *accessLogger
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/query"})
+	require.NoError(t, a.flush(context.Background()))
+	require.NoError(t, a.close(context.Background()))
+}
+
+func TestAccessLogger_TruncatesIDsAndKeepsFullCount(t *testing.T) {
+	a, collected := capturingAccessLogger(AccessLogConfig{Enabled: true, SampleRate: 1, MaxIDs: 2})
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/query", ResourceType: "decision", ResourceIDs: ids})
+
+	entries := collected()
+	require.Len(t, entries, 1)
+	assert.Equal(t, ids[:2], entries[0].ResourceIDs)
+	assert.Equal(t, 4, entries[0].ResultCount)
+	assert.False(t, entries[0].OccurredAt.IsZero(), "occurred_at is stamped when buffered")
+}
+
+func TestAccessLogger_ZeroSampleRateRecordsNothing(t *testing.T) {
+	a, collected := capturingAccessLogger(AccessLogConfig{Enabled: true, SampleRate: 0})
+
+	for range 50 {
+		a.record(storage.AccessAuditEntry{Endpoint: "/v1/query", ResourceIDs: []uuid.UUID{uuid.New()}})
+	}
+	assert.Empty(t, collected())
+}
+
+func TestAccessLogger_FailedFlushKeepsEntries(t *testing.T) {
+	fail := true
+	var written []storage.AccessAuditEntry
+	a := newAccessLoggerWith(AccessLogConfig{Enabled: true, SampleRate: 1}, func(_ context.Context, batch []storage.AccessAuditEntry) error {
+		if fail {
+			return errors.New("db down")
+		}
+		written = append(written, batch...)
+		return nil
+	}, testLogger())
+
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/first"})
+	require.Error(t, a.flush(context.Background()))
+
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/second"})
+	fail = false
+	require.NoError(t, a.flush(context.Background()))
+
+	require.Len(t, written, 2)
+	assert.Equal(t, "/v1/first", written[0].Endpoint, "retried batch keeps its place ahead of newer entries")
+	assert.Equal(t, "/v1/second", written[1].Endpoint)
+}
+
+func TestAccessLogger_DropsWhenBufferFull(t *testing.T) {
+	a, _ := capturingAccessLogger(AccessLogConfig{Enabled: true, SampleRate: 1})
+
+	a.entries = make([]storage.AccessAuditEntry, accessLogMaxBuffered)
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/query"})
+
+	assert.Len(t, a.entries, accessLogMaxBuffered)
+	assert.Equal(t, int64(1), a.dropped.Load())
+}
+
+func TestAccessLogger_CloseFlushesRemaining(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		written int
+	)
+	a := newAccessLoggerWith(AccessLogConfig{Enabled: true, SampleRate: 1}, func(_ context.Context, batch []storage.AccessAuditEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written += len(batch)
+		return nil
+	}, testLogger())
+	go a.flushLoop()
+
+	a.record(storage.AccessAuditEntry{Endpoint: "/v1/query"})
+	require.NoError(t, a.close(context.Background()))
+	require.NoError(t, a.close(context.Background()), "close is idempotent")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, written)
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	claims := &auth.Claims{AgentID: "alice", Role: model.RoleAgent, OrgID: uuid.New()}
+	serve := func(h http.Handler, withClaims bool) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/decisions/recent", nil)
+		if withClaims {
+			req = req.WithContext(ctxutil.WithClaims(req.Context(), claims))
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("records authenticated requests", func(t *testing.T) {
+		a, collected := capturingAccessLogger(AccessLogConfig{Requests: true})
+		serve(accessLogMiddleware(a, inner), true)
+		serve(accessLogMiddleware(a, inner), false)
+
+		entries := collected()
+		require.Len(t, entries, 1, "unauthenticated requests are not recorded")
+		e := entries[0]
+		assert.Equal(t, claims.OrgID, e.OrgID)
+		assert.Equal(t, "alice", e.ActorAgentID)
+		assert.Equal(t, string(model.RoleAgent), e.ActorRole)
+		assert.Equal(t, http.MethodGet, e.HTTPMethod)
+		assert.Equal(t, "/v1/decisions/recent", e.Endpoint)
+		assert.Equal(t, model.AccessResourceRequest, e.ResourceType)
+		require.NotNil(t, e.Status)
+		assert.Equal(t, http.StatusTeapot, *e.Status)
+		require.NotNil(t, e.LatencyMs)
+		assert.GreaterOrEqual(t, *e.LatencyMs, 0.0)
+	})
+
+	t.Run("reads-only logger leaves requests alone", func(t *testing.T) {
+		a, collected := capturingAccessLogger(AccessLogConfig{Enabled: true, SampleRate: 1})
+		serve(accessLogMiddleware(a, inner), true)
+		assert.Empty(t, collected())
+	})
+}
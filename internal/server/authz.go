@@ -3,6 +3,8 @@ package server
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/ashita-ai/akashi/internal/auth"
 	"github.com/ashita-ai/akashi/internal/authz"
 	"github.com/ashita-ai/akashi/internal/model"
@@ -29,6 +31,11 @@ func filterConflictsByAccess(ctx context.Context, db *storage.DB, claims *auth.C
 	return authz.FilterConflicts(ctx, db, claims, conflicts, cache)
 }
 
+// redactConflictCounterparts delegates to the shared authz package.
+func redactConflictCounterparts(ctx context.Context, db *storage.DB, claims *auth.Claims, decisionID uuid.UUID, conflicts []model.DecisionConflict, cache *authz.GrantCache) ([]model.DecisionConflict, error) {
+	return authz.RedactConflictCounterparts(ctx, db, claims, decisionID, conflicts, cache)
+}
+
 // filterConflictGroupsByAccess delegates to the shared authz package.
 func filterConflictGroupsByAccess(ctx context.Context, db *storage.DB, claims *auth.Claims, groups []model.ConflictGroup, cache *authz.GrantCache) ([]model.ConflictGroup, error) {
 	return authz.FilterConflictGroups(ctx, db, claims, groups, cache)
@@ -38,3 +45,7 @@ func filterConflictGroupsByAccess(ctx context.Context, db *storage.DB, claims *a
 func filterLineageByAccess(ctx context.Context, db *storage.DB, claims *auth.Claims, lineage storage.DecisionLineage, cache *authz.GrantCache) (storage.DecisionLineage, error) {
 	return authz.FilterLineage(ctx, db, claims, lineage, cache)
 }
+
+func filterAncestryByAccess(ctx context.Context, db *storage.DB, claims *auth.Claims, health storage.DecisionAncestryHealth, cache *authz.GrantCache) (storage.DecisionAncestryHealth, error) {
+	return authz.FilterAncestry(ctx, db, claims, health, cache)
+}
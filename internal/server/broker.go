@@ -14,10 +14,20 @@ import (
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/metric"
 
+	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/storage"
 	"github.com/ashita-ai/akashi/internal/telemetry"
 )
 
+// maxReplayEvents caps how many missed conflict events one reconnect replays.
+const maxReplayEvents = 1000
+
+// conflictFeed loads conflicts detected after a cursor for SSE replay.
+// Implemented by *storage.DB.
+type conflictFeed interface {
+	NewConflictsSinceByOrg(ctx context.Context, orgID uuid.UUID, since time.Time, limit int) ([]model.DecisionConflict, error)
+}
+
 // subscriber tracks an SSE subscriber's channel and org scope.
 type subscriber struct {
 	orgID uuid.UUID
@@ -28,6 +38,7 @@ type subscriber struct {
 // and sends each payload only to subscribers in the matching org.
 type Broker struct {
 	db     *storage.DB
+	feed   conflictFeed // nil = reconnects are not replayed.
 	logger *slog.Logger
 
 	mu          sync.RWMutex
@@ -42,12 +53,16 @@ func NewBroker(db *storage.DB, logger *slog.Logger) *Broker {
 	dropped, _ := meter.Int64Counter("akashi.broker.dropped_events",
 		metric.WithDescription("Events dropped due to unparseable org_id or slow subscribers"),
 	)
-	return &Broker{
+	b := &Broker{
 		db:            db,
 		logger:        logger,
 		subscribers:   make(map[chan []byte]subscriber),
 		droppedEvents: dropped,
 	}
+	if db != nil {
+		b.feed = db
+	}
+	return b
 }
 
 // Start begins listening on the decisions and conflicts channels.
@@ -132,8 +147,13 @@ func (b *Broker) Start(ctx context.Context) {
 				"channel", channel)
 		}
 
-		// Format as SSE event.
-		event := formatSSE(channel, payload)
+		// Format as SSE event. Conflict events carry their detected_at as the
+		// event ID so a reconnecting client can resume with Last-Event-ID.
+		var id string
+		if channel == storage.ChannelConflicts {
+			id = conflictEventID(payload)
+		}
+		event := formatSSEEvent(id, channel, payload)
 		b.broadcastToOrg(event, orgID, ok)
 	}
 }
@@ -241,11 +261,126 @@ func extractOrgID(payload string) (uuid.UUID, bool) {
 	return id, true
 }
 
+// ReplayConflicts returns the SSE events for conflicts detected in orgID after
+// lastEventID, the ID of the last conflict event a reconnecting client saw,
+// oldest first. It also returns the cursor of the last replayed event (or the
+// parsed lastEventID when nothing was missed) so the caller can skip live
+// events it already replayed. ok is false when lastEventID is not a conflict
+// event ID or replay is unavailable; the client then resumes from live events.
+func (b *Broker) ReplayConflicts(ctx context.Context, orgID uuid.UUID, lastEventID string) (events [][]byte, cursor time.Time, ok bool, err error) {
+	since, parsed := parseConflictEventID(lastEventID)
+	if !parsed || b.feed == nil {
+		return nil, time.Time{}, false, nil
+	}
+	missed, err := b.feed.NewConflictsSinceByOrg(ctx, orgID, since, maxReplayEvents)
+	if err != nil {
+		return nil, time.Time{}, false, err
+	}
+	cursor = since
+	for _, c := range missed {
+		payload, err := ConflictEventPayload(c)
+		if err != nil {
+			return nil, time.Time{}, false, err
+		}
+		events = append(events, formatSSEEvent(formatConflictEventID(c.DetectedAt), storage.ChannelConflicts, string(payload)))
+		if c.DetectedAt.After(cursor) {
+			cursor = c.DetectedAt
+		}
+	}
+	return events, cursor, true, nil
+}
+
+// ConflictEventPayload builds the akashi_conflicts notification payload for a
+// newly detected conflict. detected_at doubles as the SSE event ID.
+func ConflictEventPayload(c model.DecisionConflict) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"org_id":        c.OrgID,
+		"conflict_id":   c.ID,
+		"conflict_kind": c.ConflictKind,
+		"decision_a_id": c.DecisionAID,
+		"decision_b_id": c.DecisionBID,
+		"agent_a":       c.AgentA,
+		"agent_b":       c.AgentB,
+		"decision_type": c.DecisionType,
+		"detected_at":   formatConflictEventID(c.DetectedAt),
+	})
+}
+
+// conflictEventID returns the SSE event ID for a conflict notification: its
+// detected_at, normalized. Payloads without one (e.g. scorer pings) get no ID.
+func conflictEventID(payload string) string {
+	var p struct {
+		DetectedAt string `json:"detected_at"`
+	}
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return ""
+	}
+	t, ok := parseConflictEventID(p.DetectedAt)
+	if !ok {
+		return ""
+	}
+	return formatConflictEventID(t)
+}
+
+func formatConflictEventID(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseConflictEventID(id string) (time.Time, bool) {
+	if id == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, id)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// sseEventID returns the value of an SSE event's "id:" field, if any.
+func sseEventID(event []byte) string {
+	for _, line := range bytes.Split(event, []byte("\n")) {
+		if id, ok := bytes.CutPrefix(line, []byte("id: ")); ok {
+			return string(id)
+		}
+	}
+	return ""
+}
+
+// ParseSSEEvent splits an event delivered by Subscribe or ReplayConflicts
+// into its ID (empty when absent), event type (the notification channel),
+// and data, for transports that relay broker events without SSE framing.
+func ParseSSEEvent(event []byte) (id, eventType, data string) {
+	var dataLines []string
+	for _, line := range strings.Split(strings.TrimRight(string(event), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			dataLines = append(dataLines, strings.TrimPrefix(line, "data: "))
+		}
+	}
+	return id, eventType, strings.Join(dataLines, "\n")
+}
+
 // formatSSE formats a notification as a Server-Sent Events message.
 // Per the SSE spec, each line in a multi-line data field must be
 // prefixed with "data: " to avoid desynchronizing the client parser.
 func formatSSE(eventType, data string) []byte {
+	return formatSSEEvent("", eventType, data)
+}
+
+// formatSSEEvent is formatSSE with an optional event ID. Clients echo the
+// last ID they received in the Last-Event-ID header when reconnecting.
+func formatSSEEvent(id, eventType, data string) []byte {
 	var buf bytes.Buffer
+	if id != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(id)
+		buf.WriteByte('\n')
+	}
 	buf.WriteString("event: ")
 	buf.WriteString(eventType)
 	buf.WriteByte('\n')
@@ -1,13 +1,23 @@
 package server
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
 )
 
 // testLogger returns a logger for tests that discards output.
@@ -532,6 +542,18 @@ func TestFormatSSEEmptyData(t *testing.T) {
 	}
 }
 
+func TestParseSSEEvent(t *testing.T) {
+	id, eventType, data := ParseSSEEvent(formatSSEEvent("2026-05-01T09:00:00Z", "akashi_conflicts", "line1\nline2"))
+	if id != "2026-05-01T09:00:00Z" || eventType != "akashi_conflicts" || data != "line1\nline2" {
+		t.Errorf("ParseSSEEvent: got (%q, %q, %q)", id, eventType, data)
+	}
+
+	id, eventType, data = ParseSSEEvent(formatSSE("akashi_decisions", `{"id":"123"}`))
+	if id != "" || eventType != "akashi_decisions" || data != `{"id":"123"}` {
+		t.Errorf("ParseSSEEvent without ID: got (%q, %q, %q)", id, eventType, data)
+	}
+}
+
 // TestNewBroker exercises the actual NewBroker constructor, which initializes
 // the OTel metrics counter. We pass nil for db since we only test
 // construction — Start is not called.
@@ -594,3 +616,165 @@ func TestNewBrokerDroppedEventsMetric(t *testing.T) {
 // listenWithRetry calls b.db.Listen which requires a real storage.DB.
 // The listenWithRetry code path is exercised via integration tests that use
 // the full server setup.
+
+// fakeConflictFeed serves NewConflictsSinceByOrg from memory.
+type fakeConflictFeed struct {
+	mu        sync.Mutex
+	conflicts []model.DecisionConflict
+}
+
+func (f *fakeConflictFeed) NewConflictsSinceByOrg(_ context.Context, orgID uuid.UUID, since time.Time, limit int) ([]model.DecisionConflict, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []model.DecisionConflict
+	for _, c := range f.conflicts {
+		if c.OrgID == orgID && c.DetectedAt.After(since) && len(out) < limit {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func TestConflictEventID(t *testing.T) {
+	detected := time.Date(2026, 5, 1, 10, 0, 0, 123456789, time.FixedZone("x", 3600))
+	payload, err := ConflictEventPayload(model.DecisionConflict{OrgID: uuid.New(), DetectedAt: detected})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := conflictEventID(string(payload)), "2026-05-01T09:00:00.123456789Z"; got != want {
+		t.Errorf("conflictEventID: got %q, want %q", got, want)
+	}
+	if got := conflictEventID(`{"source":"scorer","org_id":"x"}`); got != "" {
+		t.Errorf("scorer ping should have no event ID, got %q", got)
+	}
+
+	event := formatSSEEvent("2026-05-01T09:00:00Z", "akashi_conflicts", `{"a":1}`)
+	want := "id: 2026-05-01T09:00:00Z\nevent: akashi_conflicts\ndata: {\"a\":1}\n\n"
+	if string(event) != want {
+		t.Errorf("formatSSEEvent: got %q, want %q", event, want)
+	}
+	if got := sseEventID(event); got != "2026-05-01T09:00:00Z" {
+		t.Errorf("sseEventID: got %q", got)
+	}
+}
+
+// TestSSEReconnectWithLastEventID simulates a client that drops its
+// connection, misses conflicts, and reconnects with Last-Event-ID.
+func TestSSEReconnectWithLastEventID(t *testing.T) {
+	orgID := uuid.Nil // No auth middleware: the handler sees the zero org.
+	base := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+	conflict := func(i int) model.DecisionConflict {
+		return model.DecisionConflict{
+			ID: uuid.New(), OrgID: orgID, ConflictKind: model.ConflictKindCrossAgent,
+			DecisionType: fmt.Sprintf("type-%d", i), DetectedAt: base.Add(time.Duration(i) * time.Second),
+		}
+	}
+	feed := &fakeConflictFeed{}
+	broker := &Broker{
+		subscribers: make(map[chan []byte]subscriber),
+		feed:        feed,
+		logger:      testLogger(),
+	}
+	h := &Handlers{broker: broker, logger: testLogger()}
+	srv := httptest.NewServer(http.HandlerFunc(h.HandleSubscribe))
+	defer srv.Close()
+
+	publish := func(c model.DecisionConflict) {
+		payload, err := ConflictEventPayload(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		broker.broadcastToOrg(formatSSEEvent(conflictEventID(string(payload)), storage.ChannelConflicts, string(payload)), orgID, true)
+	}
+	connect := func(lastEventID string) (*bufio.Reader, func()) {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		waitForSubscribers(t, broker, 1)
+		return bufio.NewReader(resp.Body), func() { _ = resp.Body.Close() }
+	}
+	// readEvent returns the id and decision_type of the next SSE event.
+	readEvent := func(r *bufio.Reader) (id, decisionType string) {
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read event: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "" && decisionType != "":
+				return id, decisionType
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				var p struct {
+					DecisionType string `json:"decision_type"`
+				}
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &p); err != nil {
+					t.Fatalf("decode data: %v", err)
+				}
+				decisionType = p.DecisionType
+			}
+		}
+	}
+
+	// First connection sees conflict 1 live, then drops.
+	r, disconnect := connect("")
+	c1 := conflict(1)
+	feed.mu.Lock()
+	feed.conflicts = append(feed.conflicts, c1)
+	feed.mu.Unlock()
+	publish(c1)
+	lastID, got := readEvent(r)
+	if got != "type-1" || lastID == "" {
+		t.Fatalf("live event: got id=%q type=%q", lastID, got)
+	}
+	disconnect()
+	waitForSubscribers(t, broker, 0)
+
+	// Conflicts 2 and 3 are detected while the client is away.
+	feed.mu.Lock()
+	feed.conflicts = append(feed.conflicts, conflict(2), conflict(3))
+	feed.mu.Unlock()
+
+	r, disconnect = connect(lastID)
+	defer disconnect()
+	for _, want := range []string{"type-2", "type-3"} {
+		if _, got := readEvent(r); got != want {
+			t.Fatalf("replay: got %q, want %q", got, want)
+		}
+	}
+
+	// A late live notification for an already-replayed conflict is skipped;
+	// newer ones stream through.
+	publish(conflict(3))
+	publish(conflict(4))
+	if _, got := readEvent(r); got != "type-4" {
+		t.Fatalf("live after replay: got %q, want type-4", got)
+	}
+}
+
+func waitForSubscribers(t *testing.T, b *Broker, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		b.mu.RLock()
+		count := len(b.subscribers)
+		b.mu.RUnlock()
+		if count == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d subscribers (have %d)", n, count)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// decisionTypesTTL bounds how stale GET /v1/decision-types can be. The
+// underlying query groups every current decision in the org, so repeated
+// dashboard polls should not each pay for a full scan.
+const decisionTypesTTL = 30 * time.Second
+
+// decisionTypesLookup loads the decision types visible to a set of agents;
+// nil agentIDs means every agent in the org.
+type decisionTypesLookup func(ctx context.Context, orgID uuid.UUID, agentIDs []string) ([]storage.DecisionTypeSummary, error)
+
+// decisionTypesKey identifies one cached result: an org plus the caller's
+// visible agent set, canonicalized as a sorted, joined list ("*" for all).
+type decisionTypesKey struct {
+	orgID  uuid.UUID
+	agents string
+}
+
+// decisionTypesCache caches ListDecisionTypes results per org and visible
+// agent set.
+type decisionTypesCache struct {
+	lookup decisionTypesLookup
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[decisionTypesKey]decisionTypesEntry
+}
+
+type decisionTypesEntry struct {
+	types     []storage.DecisionTypeSummary
+	expiresAt time.Time
+}
+
+func newDecisionTypesCache(lookup decisionTypesLookup, ttl time.Duration) *decisionTypesCache {
+	return &decisionTypesCache{
+		lookup:  lookup,
+		ttl:     ttl,
+		entries: make(map[decisionTypesKey]decisionTypesEntry),
+	}
+}
+
+// get returns the decision types for the key, loading them on a miss or expiry.
+func (c *decisionTypesCache) get(ctx context.Context, key decisionTypesKey, agentIDs []string) ([]storage.DecisionTypeSummary, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	now := time.Now()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.types, nil
+	}
+
+	types, err := c.lookup(ctx, key.orgID, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	// Each distinct grant set gets its own entry; drop expired ones as we go
+	// so the map stays bounded by the number of recently active callers.
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = decisionTypesEntry{types: types, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return types, nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -422,3 +423,135 @@ func TestGrantEndpoints_UnauthenticatedDenied(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// Batch grants: per-item results, dedup, and CSV import
+// ---------------------------------------------------------------------------
+
+type grantBatchEnvelope struct {
+	Data model.GrantBatchResponse `json:"data"`
+}
+
+func TestGrantBatch_PartialFailureAndDedup(t *testing.T) {
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	grantee := "batch-grantee-" + suffix
+	createAgent(testSrv.URL, adminToken, grantee, "Batch Grantee", "reader", grantee+"-key")
+
+	owner := "test-agent"
+	other := "admin"
+	req := model.GrantBatchRequest{Grants: []model.CreateGrantRequest{
+		{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &owner, Permission: "read"},
+		{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &other, Permission: "read"},
+		{GranteeAgentID: "no-such-agent-" + suffix, ResourceType: "agent_traces", ResourceID: &owner, Permission: "read"},
+		{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &owner, Permission: "write"},
+		{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &owner, Permission: "read"},
+	}}
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants/batch", adminToken, req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var out grantBatchEnvelope
+	require.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, 2, out.Data.Created)
+	assert.Equal(t, 1, out.Data.Existing)
+	assert.Equal(t, 2, out.Data.Failed)
+	require.Len(t, out.Data.Results, 5)
+
+	statuses := make([]string, len(out.Data.Results))
+	for i, r := range out.Data.Results {
+		assert.Equal(t, i, r.Index)
+		statuses[i] = r.Status
+	}
+	assert.Equal(t, []string{"created", "created", "failed", "failed", "exists"}, statuses)
+	assert.Equal(t, "grantee agent not found", out.Data.Results[2].Error)
+	assert.Equal(t, "invalid permission", out.Data.Results[3].Error)
+	require.NotNil(t, out.Data.Results[4].Grant)
+	assert.Equal(t, out.Data.Results[0].Grant.ID, out.Data.Results[4].Grant.ID,
+		"a duplicate within the batch resolves to the grant created earlier in it")
+
+	// Re-submitting the batch creates nothing new.
+	resp, err = authedRequest("POST", testSrv.URL+"/v1/grants/batch", adminToken, req)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, 0, out.Data.Created)
+	assert.Equal(t, 3, out.Data.Existing)
+
+	// The admin's grants are reviewable by grantor.
+	resp, err = authedRequest("GET", testSrv.URL+"/v1/grants?grantor_agent_id=admin", adminToken, nil)
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var list struct {
+		Data []model.AccessGrant `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &list))
+	ids := make(map[string]bool, len(list.Data))
+	for _, g := range list.Data {
+		ids[g.ID.String()] = true
+	}
+	assert.True(t, ids[out.Data.Results[0].Grant.ID.String()])
+	assert.True(t, ids[out.Data.Results[1].Grant.ID.String()])
+}
+
+func TestGrantBatch_AgentCanOnlyGrantOwnTraces(t *testing.T) {
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	grantee := "batch-own-grantee-" + suffix
+	createAgent(testSrv.URL, adminToken, grantee, "Batch Own Grantee", "reader", grantee+"-key")
+
+	own := "test-agent"
+	other := "admin"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants/batch", agentToken, model.GrantBatchRequest{
+		Grants: []model.CreateGrantRequest{
+			{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &own, Permission: "read"},
+			{GranteeAgentID: grantee, ResourceType: "agent_traces", ResourceID: &other, Permission: "read"},
+		},
+	})
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var out grantBatchEnvelope
+	require.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, "created", out.Data.Results[0].Status)
+	assert.Equal(t, "failed", out.Data.Results[1].Status)
+	assert.Equal(t, "can only grant access to your own traces", out.Data.Results[1].Error)
+}
+
+func TestGrantBatch_CSV(t *testing.T) {
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	grantee := "csv-grantee-" + suffix
+	createAgent(testSrv.URL, adminToken, grantee, "CSV Grantee", "reader", grantee+"-key")
+
+	csvBody := "grantee_agent_id,resource_type,resource_id,permission,expires_at\n" +
+		grantee + ",agent_traces,test-agent,read,\n" +
+		grantee + ",agent_traces,admin\n" +
+		grantee + ",agent_traces,admin,read,2099-01-01T00:00:00Z\n"
+
+	req, err := http.NewRequest("POST", testSrv.URL+"/v1/grants/batch", strings.NewReader(csvBody))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	var out grantBatchEnvelope
+	require.NoError(t, json.Unmarshal(body, &out))
+	assert.Equal(t, 2, out.Data.Created)
+	assert.Equal(t, 1, out.Data.Failed)
+	require.Len(t, out.Data.Results, 3)
+	assert.Equal(t, "failed", out.Data.Results[1].Status)
+	assert.Contains(t, out.Data.Results[1].Error, "line 3")
+	require.NotNil(t, out.Data.Results[2].Grant)
+	require.NotNil(t, out.Data.Results[2].Grant.ExpiresAt)
+	assert.Equal(t, 2099, out.Data.Results[2].Grant.ExpiresAt.Year())
+}
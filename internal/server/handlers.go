@@ -38,6 +38,7 @@ type Handlers struct {
 	maxRequestBodyBytes     int64
 	openapiSpec             []byte
 	enableDestructiveDelete bool
+	softDelete              bool
 	retentionInterval       time.Duration
 	// decisionHooks are fired asynchronously after decision lifecycle events.
 	// Nil or empty slice means no hooks registered.
@@ -49,6 +50,9 @@ type Handlers struct {
 	// signupLimiter enforces a tight per-IP rate limit on POST /auth/signup.
 	// Set by server.New when signup is enabled; nil otherwise.
 	signupLimiter ratelimit.Limiter
+	// orgRateLimits caches per-org rate limit overrides for the rate limit
+	// middleware. Set by server.New; invalidated when an override changes.
+	orgRateLimits *orgRateLimits
 	// trustProxy controls whether to read client IP from X-Forwarded-For.
 	trustProxy bool
 	// resolutionRecorder records conflict resolution events for OTel metrics.
@@ -57,12 +61,37 @@ type Handlers struct {
 	// conflictValidator classifies relationships between decision pairs.
 	// Nil-safe: eval endpoint returns 501 when not configured.
 	conflictValidator conflicts.Validator
+	// conflictRescorer re-runs conflict scoring for the admin rescore endpoint.
+	// Nil-safe: rescore endpoint returns 501 when not configured.
+	conflictRescorer ConflictRescorer
+	// outbox works off the search outbox backlog for POST /v1/admin/flush.
+	// Nil when Qdrant is not configured.
+	outbox OutboxFlusher
 	// highConfidenceWarnThreshold triggers a response warning when confidence
 	// exceeds this value and no evidence items are provided (default 0.85).
 	highConfidenceWarnThreshold float32
 	// exportPageSize is the batch size used by HandleExportDecisions when
 	// streaming NDJSON via keyset pagination. Validated at config load (1–10000).
 	exportPageSize int
+	// traceBatchMax caps the number of traces accepted by HandleTraceBatch.
+	traceBatchMax int
+	// decisionTypes caches GET /v1/decision-types per org and grant set.
+	decisionTypes *decisionTypesCache
+	// eventSchemaValidation enables per-event-type payload validation in
+	// HandleAppendEvents (built-in schemas plus org extensions).
+	eventSchemaValidation bool
+	// accessLog records decision reads and, optionally, every request.
+	// Nil when disabled.
+	accessLog *accessLogger
+	// reviewSLA is the default age after which a pending review flag is
+	// overdue in GET /v1/review-queue.
+	reviewSLA time.Duration
+	// embeddingModel names the configured embedding model; POST
+	// /v1/admin/reembed only migrates to it.
+	embeddingModel string
+	// vectorCollection is resized by POST /v1/admin/reembed when the
+	// embedding size changes. Nil when Qdrant is not configured.
+	vectorCollection VectorCollection
 }
 
 // HandlersDeps holds all dependencies for constructing Handlers.
@@ -80,14 +109,23 @@ type HandlersDeps struct {
 	MaxRequestBodyBytes         int64
 	OpenAPISpec                 []byte
 	EnableDestructiveDelete     bool
+	SoftDelete                  bool
 	RetentionInterval           time.Duration
 	DecisionHooks               []DecisionHook
 	AutoTrace                   bool
 	TrustProxy                  bool
 	ResolutionRecorder          conflicts.ResolutionRecorder
 	ConflictValidator           conflicts.Validator
+	ConflictRescorer            ConflictRescorer
+	Outbox                      OutboxFlusher
 	HighConfidenceWarnThreshold float32
 	ExportPageSize              int
+	TraceBatchMax               int
+	EventSchemaValidation       bool
+	AccessLog                   AccessLogConfig
+	ReviewSLA                   time.Duration
+	EmbeddingModel              string
+	VectorCollection            VectorCollection
 }
 
 // NewHandlers creates a new Handlers with all dependencies.
@@ -106,6 +144,7 @@ func NewHandlers(d HandlersDeps) *Handlers {
 		maxRequestBodyBytes:         d.MaxRequestBodyBytes,
 		openapiSpec:                 d.OpenAPISpec,
 		enableDestructiveDelete:     d.EnableDestructiveDelete,
+		softDelete:                  d.SoftDelete,
 		retentionInterval:           d.RetentionInterval,
 		decisionHooks:               d.DecisionHooks,
 		hookChecks:                  newHookCheckStore(),
@@ -113,8 +152,17 @@ func NewHandlers(d HandlersDeps) *Handlers {
 		trustProxy:                  d.TrustProxy,
 		resolutionRecorder:          d.ResolutionRecorder,
 		conflictValidator:           d.ConflictValidator,
+		conflictRescorer:            d.ConflictRescorer,
+		outbox:                      d.Outbox,
 		highConfidenceWarnThreshold: d.HighConfidenceWarnThreshold,
 		exportPageSize:              exportPageSizeOrDefault(d.ExportPageSize),
+		traceBatchMax:               traceBatchMaxOrDefault(d.TraceBatchMax),
+		decisionTypes:               newDecisionTypesCache(d.DB.ListDecisionTypes, decisionTypesTTL),
+		eventSchemaValidation:       d.EventSchemaValidation,
+		accessLog:                   newAccessLogger(d.AccessLog, d.DB, d.Logger),
+		reviewSLA:                   reviewSLAOrDefault(d.ReviewSLA),
+		embeddingModel:              d.EmbeddingModel,
+		vectorCollection:            d.VectorCollection,
 	}
 }
 
@@ -130,6 +178,15 @@ func exportPageSizeOrDefault(n int) int {
 	return n
 }
 
+// traceBatchMaxOrDefault returns the batch cap for POST /v1/trace/batch,
+// falling back to the documented default of 100 when unset.
+func traceBatchMaxOrDefault(n int) int {
+	if n <= 0 {
+		return 100
+	}
+	return n
+}
+
 // HandleAuthToken handles POST /auth/token.
 // Checks managed api_keys table first, falls back to agents.api_key_hash.
 func (h *Handlers) HandleAuthToken(w http.ResponseWriter, r *http.Request) {
@@ -156,6 +213,7 @@ func (h *Handlers) HandleAuthToken(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			continue
 		}
+		upgradeAPIKeyHash(r.Context(), h.db, req.APIKey, k.KeyHash, k.OrgID, &k.ID, agent.ID)
 		matched = &agent
 		kid := k.ID
 		matchedKeyID = &kid
@@ -184,6 +242,7 @@ func (h *Handlers) HandleAuthToken(w http.ResponseWriter, r *http.Request) {
 			if verr != nil || !valid {
 				continue
 			}
+			upgradeAPIKeyHash(r.Context(), h.db, req.APIKey, *a.APIKeyHash, a.OrgID, nil, a.ID)
 			matched = a
 			break
 		}
@@ -202,6 +261,22 @@ func (h *Handlers) HandleAuthToken(w http.ResponseWriter, r *http.Request) {
 		h.writeInternalError(w, r, "failed to issue token", err)
 		return
 	}
+	refresh, refreshID, refreshExp, err := h.jwtMgr.IssueRefreshToken(*matched)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to issue token", err)
+		return
+	}
+	if err := h.db.CreateRefreshToken(r.Context(), storage.RefreshToken{
+		ID:        refreshID,
+		OrgID:     matched.OrgID,
+		AgentID:   matched.AgentID,
+		TokenHash: auth.HashRefreshToken(refresh),
+		APIKeyID:  matchedKeyID,
+		ExpiresAt: refreshExp,
+	}); err != nil {
+		h.writeInternalError(w, r, "failed to issue token", err)
+		return
+	}
 
 	// Audit: record successful token issuance. Best-effort — failure to
 	// audit must not block the token response.
@@ -221,8 +296,92 @@ func (h *Handlers) HandleAuthToken(w http.ResponseWriter, r *http.Request) {
 	}
 
 	writeJSON(w, r, http.StatusOK, model.AuthTokenResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExp,
+	})
+}
+
+// HandleAuthRefresh handles POST /auth/refresh (no auth required).
+// Exchanges a refresh token for a new access token and a replacement refresh
+// token. The presented token is revoked, so each refresh token works once.
+// The agent is reloaded, so role changes take effect and a deleted agent
+// cannot refresh.
+func (h *Handlers) HandleAuthRefresh(w http.ResponseWriter, r *http.Request) {
+	var req model.AuthRefreshRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if req.RefreshToken == "" {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "refresh_token is required")
+		return
+	}
+
+	claims, err := h.jwtMgr.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		writeError(w, r, http.StatusUnauthorized, model.ErrCodeUnauthorized, "invalid or expired refresh token")
+		return
+	}
+	agent, err := h.db.GetAgentByAgentID(r.Context(), claims.OrgID, claims.AgentID)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusUnauthorized, model.ErrCodeUnauthorized, "invalid or expired refresh token")
+			return
+		}
+		h.writeInternalError(w, r, "failed to refresh token", err)
+		return
+	}
+	if agent.ID.String() != claims.Subject {
+		// The agent was deleted and recreated under the same agent_id.
+		writeError(w, r, http.StatusUnauthorized, model.ErrCodeUnauthorized, "invalid or expired refresh token")
+		return
+	}
+
+	token, expiresAt, err := h.jwtMgr.IssueToken(agent)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to refresh token", err)
+		return
+	}
+	refresh, refreshID, refreshExp, err := h.jwtMgr.IssueRefreshToken(agent)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to refresh token", err)
+		return
+	}
+	err = h.db.RotateRefreshToken(r.Context(), agent.OrgID, uuid.MustParse(claims.ID),
+		auth.HashRefreshToken(req.RefreshToken), storage.RefreshToken{
+			ID:        refreshID,
+			OrgID:     agent.OrgID,
+			AgentID:   agent.AgentID,
+			TokenHash: auth.HashRefreshToken(refresh),
+			ExpiresAt: refreshExp,
+		})
+	if errors.Is(err, storage.ErrRefreshTokenInvalid) {
+		writeError(w, r, http.StatusUnauthorized, model.ErrCodeUnauthorized, "invalid or expired refresh token")
+		return
+	}
+	if err != nil {
+		h.writeInternalError(w, r, "failed to refresh token", err)
+		return
+	}
+
+	if auditErr := h.recordMutationAuditBestEffort(r, agent.OrgID,
+		"token_refreshed", "auth_token", agent.AgentID, nil, nil, map[string]any{
+			"ip":         r.RemoteAddr,
+			"user_agent": r.UserAgent(),
+			"token_exp":  expiresAt,
+		},
+	); auditErr != nil {
+		h.logger.Error("failed to audit token refresh",
+			"agent_id", agent.AgentID, "org_id", agent.OrgID, "error", auditErr)
+	}
+
+	writeJSON(w, r, http.StatusOK, model.AuthTokenResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExp,
 	})
 }
 
@@ -255,6 +414,22 @@ func (h *Handlers) HandleScopedToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Namespace != "" {
+		if err := model.ValidateNamespace(req.Namespace); err != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+			return
+		}
+	}
+	// A caller pinned to a namespace cannot mint a token for a different one.
+	if claims.Namespace != "" {
+		if req.Namespace != "" && req.Namespace != claims.Namespace {
+			writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+				"cannot issue scoped token outside your own namespace")
+			return
+		}
+		req.Namespace = claims.Namespace
+	}
+
 	ttl := 5 * time.Minute
 	if req.ExpiresIn > 0 {
 		ttl = time.Duration(req.ExpiresIn) * time.Second
@@ -281,7 +456,7 @@ func (h *Handlers) HandleScopedToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, expiresAt, err := h.jwtMgr.IssueScopedToken(claims.AgentID, target, ttl)
+	token, expiresAt, err := h.jwtMgr.IssueScopedToken(claims.AgentID, target, ttl, req.Namespace)
 	if err != nil {
 		h.writeInternalError(w, r, "failed to issue scoped token", err)
 		return
@@ -302,6 +477,7 @@ func (h *Handlers) HandleScopedToken(w http.ResponseWriter, r *http.Request) {
 			"as_role":     string(target.Role),
 			"ttl_seconds": int(ttl.Seconds()),
 			"token_exp":   expiresAt,
+			"namespace":   req.Namespace,
 		},
 	); auditErr != nil {
 		h.logger.Error("failed to audit scoped token issuance",
@@ -317,6 +493,8 @@ func (h *Handlers) HandleScopedToken(w http.ResponseWriter, r *http.Request) {
 }
 
 // HandleSubscribe handles GET /v1/subscribe (SSE).
+// Conflict events carry an "id:" field; a client reconnecting with
+// Last-Event-ID first receives the conflicts it missed, then the live stream.
 func (h *Handlers) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
 	if h.broker == nil {
 		h.logger.Error("SSE not available",
@@ -350,6 +528,26 @@ func (h *Handlers) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
 	ch := h.broker.Subscribe(orgID)
 	defer h.broker.Unsubscribe(ch)
 
+	// A reconnecting client sends the ID of the last conflict event it saw.
+	// Subscribe before replaying so nothing detected in between is lost, then
+	// skip live conflict events the replay already covered.
+	var replayedUntil time.Time
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		events, cursor, ok, err := h.broker.ReplayConflicts(r.Context(), orgID, lastID)
+		if err != nil {
+			h.logger.Warn("sse: replay after reconnect failed", "org_id", orgID, "error", err)
+		}
+		for _, event := range events {
+			if _, err := w.Write(event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+		if ok {
+			replayedUntil = cursor
+		}
+	}
+
 	keepalive := time.NewTicker(15 * time.Second)
 	defer keepalive.Stop()
 
@@ -367,6 +565,11 @@ func (h *Handlers) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				return
 			}
+			if !replayedUntil.IsZero() {
+				if t, ok := parseConflictEventID(sseEventID(event)); ok && !t.After(replayedUntil) {
+					continue
+				}
+			}
 			if _, err := w.Write(event); err != nil {
 				return
 			}
@@ -657,19 +860,34 @@ func computePagination(returned, preFilter, limit, offset, dbTotal int) (total *
 
 // writeListJSON writes a standard list response envelope (data array + pagination metadata).
 func writeListJSON(w http.ResponseWriter, r *http.Request, items any, total *int, hasMore bool, limit, offset int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(model.ListResponse{
+	writeListResponse(w, r, model.ListResponse{
 		Data:    items,
 		Total:   total,
 		HasMore: hasMore,
 		Limit:   limit,
 		Offset:  offset,
-		Meta: model.ResponseMeta{
-			RequestID: RequestIDFromContext(r.Context()),
-			Timestamp: time.Now().UTC(),
-		},
-	}); err != nil {
+	})
+}
+
+// writeCursorListJSON writes a list response envelope for cursor-paged
+// endpoints. nextCursor is empty on the last page; total is not reported.
+func writeCursorListJSON(w http.ResponseWriter, r *http.Request, items any, nextCursor string, limit int) {
+	writeListResponse(w, r, model.ListResponse{
+		Data:       items,
+		HasMore:    nextCursor != "",
+		Limit:      limit,
+		NextCursor: nextCursor,
+	})
+}
+
+func writeListResponse(w http.ResponseWriter, r *http.Request, resp model.ListResponse) {
+	resp.Meta = model.ResponseMeta{
+		RequestID: RequestIDFromContext(r.Context()),
+		Timestamp: time.Now().UTC(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		slog.Warn("failed to encode list JSON response",
 			"error", err,
 			"request_id", RequestIDFromContext(r.Context()))
@@ -0,0 +1,113 @@
+//go:build integration
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/server"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/service/embedding"
+	"github.com/ashita-ai/akashi/internal/service/trace"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// accessLogServer returns a test server with the per-request access log
+// enabled. It shares the JWT manager with testSrv so existing tokens work.
+// The flush interval is long so tests control when entries are written.
+func accessLogServer(t *testing.T) (*server.Server, *httptest.Server) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	embedder := embedding.NewNoopProvider(1024)
+	decisionSvc := decisions.New(testDB, embedder, nil, logger, nil)
+	buf := trace.NewBuffer(testDB, logger, 1000, 50*time.Millisecond, nil)
+
+	srv := server.New(server.ServerConfig{
+		DB:                  testDB,
+		JWTMgr:              testJWTMgr,
+		DecisionSvc:         decisionSvc,
+		Buffer:              buf,
+		Logger:              logger,
+		ReadTimeout:         30 * time.Second,
+		WriteTimeout:        30 * time.Second,
+		Version:             "test",
+		MaxRequestBodyBytes: 1 * 1024 * 1024,
+		AccessLog:           server.AccessLogConfig{Requests: true, FlushInterval: time.Hour},
+	})
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		_ = srv.Shutdown(context.Background())
+	})
+	return srv, ts
+}
+
+func TestAccessLog_RequestProducesAccessAuditRow(t *testing.T) {
+	srv, ts := accessLogServer(t)
+	ctx := context.Background()
+	since := time.Now().Add(-time.Second)
+
+	resp, err := authedRequest("POST", ts.URL+"/v1/query", agentToken, model.QueryRequest{Limit: 5})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	requestID := resp.Header.Get("X-Request-ID")
+
+	agentID := "test-agent"
+	request := model.AccessResourceRequest
+	filters := storage.AuditFilters{Type: model.AuditTypeAccess, ActorAgentID: &agentID, ResourceType: &request, From: &since}
+
+	// Nothing is written until the buffer flushes.
+	_, total, err := testDB.ListAuditEntries(ctx, uuid.Nil, filters, 50, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	require.NoError(t, srv.FlushAccessLog(ctx))
+
+	entries, total, err := testDB.ListAuditEntries(ctx, uuid.Nil, filters, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	e := entries[0]
+	assert.Equal(t, "test-agent", e.ActorAgentID)
+	assert.Equal(t, http.MethodPost, e.HTTPMethod)
+	assert.Equal(t, "/v1/query", e.Endpoint)
+	require.NotNil(t, e.Status)
+	assert.Equal(t, http.StatusOK, *e.Status)
+	require.NotNil(t, e.LatencyMs)
+	assert.GreaterOrEqual(t, *e.LatencyMs, 0.0)
+	assert.Equal(t, requestID, e.RequestID)
+
+	// Investigators read the same rows through the audit API; that request is itself recorded.
+	listResp, err := authedRequest("GET", ts.URL+"/v1/audit?type=access&resource_type=request&actor_agent_id=test-agent&from="+url.QueryEscape(since.UTC().Format(time.RFC3339Nano)), adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = listResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, listResp.StatusCode)
+	data, _ := io.ReadAll(listResp.Body)
+	var listed struct {
+		Data []model.AuditEntry `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &listed))
+	require.Len(t, listed.Data, 1)
+	assert.Equal(t, "/v1/query", listed.Data[0].Endpoint)
+
+	require.NoError(t, srv.FlushAccessLog(ctx))
+	adminID := "admin"
+	_, total, err = testDB.ListAuditEntries(ctx, uuid.Nil, storage.AuditFilters{Type: model.AuditTypeAccess, ActorAgentID: &adminID, ResourceType: &request, From: &since}, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
@@ -1,10 +1,14 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 
@@ -17,6 +21,10 @@ import (
 // The api_key field is optional: if omitted, a managed-format key is generated
 // server-side and returned once in the response. If provided, the caller-supplied
 // key is stored. Either way, the credential lives in api_keys (not agents.api_key_hash).
+//
+// Supports Idempotency-Key. A replay returns the original agent and key
+// metadata but never raw_key: the generated secret is not persisted in the
+// idempotency table, so a caller that lost it must rotate the key.
 func (h *Handlers) HandleCreateAgent(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
@@ -27,67 +35,20 @@ func (h *Handlers) HandleCreateAgent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.AgentID == "" || req.Name == "" {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "agent_id and name are required")
-		return
-	}
-	if err := model.ValidateAgentID(req.AgentID); err != nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+	if verr := validateCreateAgentRequest(claims.Role, &req); verr != nil {
+		writeError(w, r, verr.status, verr.code, verr.message)
 		return
 	}
-	if model.IsReservedAgentID(req.AgentID) {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			"agent_id \""+req.AgentID+"\" is reserved and cannot be used")
-		return
-	}
-
-	if req.Role == "" {
-		req.Role = model.RoleAgent
-	}
 
-	// Validate role is known and caller outranks the requested role.
-	if model.RoleRank(req.Role) == 0 {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			"invalid role: must be one of platform_admin, org_owner, admin, agent, reader")
+	idem, proceed := h.beginIdempotentWrite(w, r, orgID, claims.AgentID, "POST:/v1/agents", req)
+	if !proceed {
 		return
 	}
-	if model.RoleRank(claims.Role) <= model.RoleRank(req.Role) {
-		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
-			"cannot create agent with role equal to or higher than your own")
-		return
-	}
-
-	// Validate tags if provided.
-	for _, tag := range req.Tags {
-		if err := model.ValidateTag(tag); err != nil {
-			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-			return
-		}
-	}
-
-	// Determine the raw key value and whether to expose it in the response.
-	// Server-generates a managed-format key when none is supplied by the caller.
-	rawKey := req.APIKey
-	showRawKey := false
-	var prefix string
-	if rawKey == "" {
-		var genErr error
-		rawKey, prefix, genErr = model.GenerateRawKey()
-		if genErr != nil {
-			h.writeInternalError(w, r, "failed to generate api key", genErr)
-			return
-		}
-		showRawKey = true
-	} else {
-		// Best-effort prefix extraction for managed-format keys.
-		if p, _, err := model.ParseRawKey(rawKey); err == nil {
-			prefix = p
-		}
-	}
 
-	hash, err := auth.HashAPIKey(rawKey)
+	rawKey, prefix, hash, showRawKey, err := resolveAgentKey(req.APIKey)
 	if err != nil {
-		h.writeInternalError(w, r, "failed to hash api key", err)
+		h.clearIdempotentWrite(r, orgID, idem)
+		h.writeInternalError(w, r, "failed to mint api key", err)
 		return
 	}
 
@@ -115,8 +76,13 @@ func (h *Handlers) HandleCreateAgent(w http.ResponseWriter, r *http.Request) {
 		keyAudit,
 	)
 	if err != nil {
+		h.clearIdempotentWrite(r, orgID, idem)
 		if isDuplicateKeyError(err) {
-			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, "agent_id already exists")
+			msg := "agent_id already exists"
+			if tombstoned, tErr := h.db.IsAgentTombstoned(r.Context(), orgID, req.AgentID); tErr == nil && tombstoned {
+				msg = "agent_id belongs to a deleted agent and cannot be reused until its data is purged"
+			}
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, msg)
 			return
 		}
 		h.writeInternalError(w, r, "failed to create agent", err)
@@ -130,12 +96,215 @@ func (h *Handlers) HandleCreateAgent(w http.ResponseWriter, r *http.Request) {
 			Prefix: apiKey.Prefix,
 		},
 	}
+	h.completeIdempotentWriteBestEffort(r, orgID, idem, http.StatusCreated, resp)
 	if showRawKey {
 		resp.RawKey = rawKey
 	}
 	writeJSON(w, r, http.StatusCreated, resp)
 }
 
+// agentValidationError is a rejected CreateAgentRequest with the HTTP status
+// and error code the single-agent endpoint responds with.
+type agentValidationError struct {
+	status  int
+	code    string
+	message string
+}
+
+// validateCreateAgentRequest checks a create request against the caller's
+// role and defaults an empty role to agent. Shared by the single and bulk
+// create endpoints so both enforce identical rules.
+func validateCreateAgentRequest(callerRole model.AgentRole, req *model.CreateAgentRequest) *agentValidationError {
+	invalid := func(msg string) *agentValidationError {
+		return &agentValidationError{status: http.StatusBadRequest, code: model.ErrCodeInvalidInput, message: msg}
+	}
+
+	if req.AgentID == "" || req.Name == "" {
+		return invalid("agent_id and name are required")
+	}
+	if err := model.ValidateAgentID(req.AgentID); err != nil {
+		return invalid(err.Error())
+	}
+	if model.IsReservedAgentID(req.AgentID) {
+		return invalid("agent_id \"" + req.AgentID + "\" is reserved and cannot be used")
+	}
+
+	if req.Role == "" {
+		req.Role = model.RoleAgent
+	}
+
+	// Validate role is known and caller outranks the requested role.
+	if model.RoleRank(req.Role) == 0 {
+		return invalid("invalid role: must be one of platform_admin, org_owner, admin, agent, reader")
+	}
+	if model.RoleRank(callerRole) <= model.RoleRank(req.Role) {
+		return &agentValidationError{
+			status:  http.StatusForbidden,
+			code:    model.ErrCodeForbidden,
+			message: "cannot create agent with role equal to or higher than your own",
+		}
+	}
+
+	// Validate tags if provided.
+	for _, tag := range req.Tags {
+		if err := model.ValidateTag(tag); err != nil {
+			return invalid(err.Error())
+		}
+	}
+	return nil
+}
+
+// resolveAgentKey determines the raw key value and whether to expose it in the
+// response. Server-generates a managed-format key when none is supplied by the
+// caller; otherwise the caller-supplied key is hashed as-is.
+func resolveAgentKey(supplied string) (rawKey, prefix, hash string, showRawKey bool, err error) {
+	rawKey = supplied
+	if rawKey == "" {
+		rawKey, prefix, err = model.GenerateRawKey()
+		if err != nil {
+			return "", "", "", false, err
+		}
+		showRawKey = true
+	} else if p, _, perr := model.ParseRawKey(rawKey); perr == nil {
+		// Best-effort prefix extraction for managed-format keys.
+		prefix = p
+	}
+
+	hash, err = auth.HashAPIKey(rawKey)
+	if err != nil {
+		return "", "", "", false, err
+	}
+	return rawKey, prefix, hash, showRawKey, nil
+}
+
+// HandleBulkCreateAgents handles POST /v1/agents/bulk (admin-only).
+// Every agent is validated up front; if any item is invalid (including
+// duplicates within the batch or agent_ids that already exist) nothing is
+// created and the response lists every rejected item under details.errors.
+// Otherwise all agents and their keys are created in one transaction.
+// Generated keys are returned once, in request order.
+func (h *Handlers) HandleBulkCreateAgents(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.BulkCreateAgentsRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if len(req.Agents) == 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "agents must contain at least one agent")
+		return
+	}
+	if len(req.Agents) > model.MaxBulkAgents {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("agents exceeds maximum batch size of %d", model.MaxBulkAgents))
+		return
+	}
+
+	var itemErrs []model.BulkItemError
+	firstIndex := make(map[string]int, len(req.Agents))
+	for i := range req.Agents {
+		item := &req.Agents[i]
+		if verr := validateCreateAgentRequest(claims.Role, item); verr != nil {
+			itemErrs = append(itemErrs, model.BulkItemError{
+				Index: i, AgentID: item.AgentID, Code: verr.code, Message: verr.message,
+			})
+			continue
+		}
+		if prev, dup := firstIndex[item.AgentID]; dup {
+			itemErrs = append(itemErrs, model.BulkItemError{
+				Index: i, AgentID: item.AgentID, Code: model.ErrCodeConflict,
+				Message: fmt.Sprintf("agent_id duplicates item %d in this batch", prev),
+			})
+			continue
+		}
+		firstIndex[item.AgentID] = i
+	}
+
+	ids := make([]string, 0, len(firstIndex))
+	for id := range firstIndex {
+		ids = append(ids, id)
+	}
+	existing, err := h.db.ExistingAgentIDs(r.Context(), orgID, ids)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to check existing agents", err)
+		return
+	}
+	for _, id := range existing {
+		itemErrs = append(itemErrs, model.BulkItemError{
+			Index: firstIndex[id], AgentID: id, Code: model.ErrCodeConflict, Message: "agent_id already exists",
+		})
+	}
+
+	if len(itemErrs) > 0 {
+		sort.Slice(itemErrs, func(a, b int) bool { return itemErrs[a].Index < itemErrs[b].Index })
+		writeErrorDetails(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("%d of %d agents failed validation; none were created", len(itemErrs), len(req.Agents)),
+			map[string]any{"errors": itemErrs})
+		return
+	}
+
+	items := make([]storage.NewAgentWithKey, len(req.Agents))
+	rawKeys := make([]string, len(req.Agents))
+	for i, a := range req.Agents {
+		rawKey, prefix, hash, showRawKey, err := resolveAgentKey(a.APIKey)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to mint api key", err)
+			return
+		}
+		if showRawKey {
+			rawKeys[i] = rawKey
+		}
+		items[i] = storage.NewAgentWithKey{
+			Agent: model.Agent{
+				AgentID:  a.AgentID,
+				OrgID:    orgID,
+				Name:     a.Name,
+				Role:     a.Role,
+				Tags:     a.Tags,
+				Metadata: a.Metadata,
+			},
+			Key: model.APIKey{
+				Prefix:    prefix,
+				KeyHash:   hash,
+				AgentID:   a.AgentID,
+				OrgID:     orgID,
+				Label:     "default",
+				CreatedBy: claims.AgentID,
+			},
+			AgentAudit: h.buildAuditEntry(r, orgID, "create_agent", "agent", "", nil, nil,
+				map[string]any{"bulk": true}),
+			KeyAudit: h.buildAuditEntry(r, orgID, "create_api_key", "api_key", "", nil, nil,
+				map[string]any{"bulk": true}),
+		}
+	}
+
+	created, err := h.db.CreateAgentsAndKeysTx(r.Context(), items)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			// Lost a race with a concurrent create between the pre-check and the insert.
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, "one or more agent_ids already exist")
+			return
+		}
+		h.writeInternalError(w, r, "failed to create agents", err)
+		return
+	}
+
+	resp := model.BulkCreateAgentsResponse{Agents: make([]model.CreateAgentResponse, len(created))}
+	for i, c := range created {
+		resp.Agents[i] = model.CreateAgentResponse{
+			Agent: c.Agent,
+			APIKey: model.CreateAgentAPIKeyInfo{
+				ID:     c.Key.ID,
+				Prefix: c.Key.Prefix,
+			},
+			RawKey: rawKeys[i],
+		}
+	}
+	writeJSON(w, r, http.StatusCreated, resp)
+}
+
 // HandleListAgents handles GET /v1/agents (admin-only).
 // Supports ?include=stats to enrich each agent with decision_count and last_decision_at.
 func (h *Handlers) HandleListAgents(w http.ResponseWriter, r *http.Request) {
@@ -192,7 +361,7 @@ var validGrantPermissions = map[string]bool{
 	string(model.PermissionRead): true,
 }
 
-// HandleCreateGrant handles POST /v1/grants.
+// HandleCreateGrant handles POST /v1/grants. Supports Idempotency-Key.
 func (h *Handlers) HandleCreateGrant(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
@@ -203,55 +372,91 @@ func (h *Handlers) HandleCreateGrant(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate resource_type and permission against known constants.
-	if !validGrantResourceTypes[req.ResourceType] {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid resource_type")
+	// Get grantor agent.
+	grantor, err := h.db.GetAgentByAgentID(r.Context(), orgID, claims.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get grantor", err)
 		return
 	}
-	if !validGrantPermissions[req.Permission] {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid permission")
+
+	grant, reqErr := h.resolveGrantRequest(r.Context(), claims, orgID, grantor, req)
+	if reqErr != nil {
+		writeError(w, r, reqErr.status, reqErr.code, reqErr.message)
 		return
 	}
 
-	// Get grantor agent.
-	grantor, err := h.db.GetAgentByAgentID(r.Context(), orgID, claims.AgentID)
+	idem, proceed := h.beginIdempotentWrite(w, r, orgID, claims.AgentID, "POST:/v1/grants", req)
+	if !proceed {
+		return
+	}
+
+	audit := h.buildAuditEntry(r, orgID, "create_grant", "access_grant", "", nil, nil, nil)
+	grant, err = h.db.CreateGrantWithAudit(r.Context(), grant, audit)
 	if err != nil {
-		h.writeInternalError(w, r, "failed to get grantor", err)
+		h.clearIdempotentWrite(r, orgID, idem)
+		if isDuplicateKeyError(err) {
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, "grant already exists")
+			return
+		}
+		h.writeInternalError(w, r, "failed to create grant", err)
 		return
 	}
 
+	// Invalidate the grantee's cached access set so the new grant takes effect immediately.
+	if h.grantCache != nil {
+		h.grantCache.Invalidate(orgID.String() + ":" + grant.GranteeID.String())
+	}
+
+	h.completeIdempotentWriteBestEffort(r, orgID, idem, http.StatusCreated, grant)
+	writeJSON(w, r, http.StatusCreated, grant)
+}
+
+// grantRequestError is why a CreateGrantRequest cannot be granted, with the
+// status and error code HandleCreateGrant responds with.
+type grantRequestError struct {
+	status  int
+	code    string
+	message string
+}
+
+// resolveGrantRequest validates req and resolves it into a grant issued by
+// grantor on behalf of claims. Shared by the single and batch grant handlers.
+func (h *Handlers) resolveGrantRequest(ctx context.Context, claims *auth.Claims, orgID uuid.UUID, grantor model.Agent, req model.CreateGrantRequest) (model.AccessGrant, *grantRequestError) {
+	// Validate resource_type and permission against known constants.
+	if !validGrantResourceTypes[req.ResourceType] {
+		return model.AccessGrant{}, &grantRequestError{http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid resource_type"}
+	}
+	if !validGrantPermissions[req.Permission] {
+		return model.AccessGrant{}, &grantRequestError{http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid permission"}
+	}
+
 	// Only admins and the owner of the resource can grant access.
 	// Non-admin agents can only create grants for agent_traces on their own traces.
 	if !model.RoleAtLeast(claims.Role, model.RoleAdmin) {
 		if req.ResourceType != string(model.ResourceAgentTraces) {
-			writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "agents can only grant access to their own traces")
-			return
+			return model.AccessGrant{}, &grantRequestError{http.StatusForbidden, model.ErrCodeForbidden, "agents can only grant access to their own traces"}
 		}
 		if req.ResourceID == nil || *req.ResourceID != claims.AgentID {
-			writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "can only grant access to your own traces")
-			return
+			return model.AccessGrant{}, &grantRequestError{http.StatusForbidden, model.ErrCodeForbidden, "can only grant access to your own traces"}
 		}
 	}
 
 	// Get grantee agent.
-	grantee, err := h.db.GetAgentByAgentID(r.Context(), orgID, req.GranteeAgentID)
+	grantee, err := h.db.GetAgentByAgentID(ctx, orgID, req.GranteeAgentID)
 	if err != nil {
-		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "grantee agent not found")
-		return
+		return model.AccessGrant{}, &grantRequestError{http.StatusNotFound, model.ErrCodeNotFound, "grantee agent not found"}
 	}
 
 	var expiresAt *time.Time
 	if req.ExpiresAt != nil {
 		t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
 		if err != nil {
-			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid expires_at format")
-			return
+			return model.AccessGrant{}, &grantRequestError{http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid expires_at format"}
 		}
 		expiresAt = &t
 	}
 
-	audit := h.buildAuditEntry(r, orgID, "create_grant", "access_grant", "", nil, nil, nil)
-	grant, err := h.db.CreateGrantWithAudit(r.Context(), model.AccessGrant{
+	return model.AccessGrant{
 		OrgID:        orgID,
 		GrantorID:    grantor.ID,
 		GranteeID:    grantee.ID,
@@ -259,22 +464,7 @@ func (h *Handlers) HandleCreateGrant(w http.ResponseWriter, r *http.Request) {
 		ResourceID:   req.ResourceID,
 		Permission:   req.Permission,
 		ExpiresAt:    expiresAt,
-	}, audit)
-	if err != nil {
-		if isDuplicateKeyError(err) {
-			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, "grant already exists")
-			return
-		}
-		h.writeInternalError(w, r, "failed to create grant", err)
-		return
-	}
-
-	// Invalidate the grantee's cached access set so the new grant takes effect immediately.
-	if h.grantCache != nil {
-		h.grantCache.Invalidate(orgID.String() + ":" + grantee.ID.String())
-	}
-
-	writeJSON(w, r, http.StatusCreated, grant)
+	}, nil
 }
 
 // HandleDeleteGrant handles DELETE /v1/grants/{grant_id}.
@@ -324,6 +514,27 @@ func (h *Handlers) HandleListGrants(w http.ResponseWriter, r *http.Request) {
 	limit := queryLimit(r, 50)
 	offset := queryOffset(r)
 
+	// ?grantor_agent_id= reviews everything one agent has granted, unpaginated.
+	if grantorAgentID := r.URL.Query().Get("grantor_agent_id"); grantorAgentID != "" {
+		grantor, err := h.db.GetAgentByAgentID(r.Context(), orgID, grantorAgentID)
+		if err != nil {
+			if isNotFoundError(err) {
+				writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "grantor agent not found")
+				return
+			}
+			h.writeInternalError(w, r, "failed to get grantor", err)
+			return
+		}
+		grants, err := h.db.ListGrantsByGrantor(r.Context(), orgID, grantor.ID)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to list grants", err)
+			return
+		}
+		total := len(grants)
+		writeListJSON(w, r, grants, &total, false, total, 0)
+		return
+	}
+
 	grants, total, err := h.db.ListGrants(r.Context(), orgID, limit, offset)
 	if err != nil {
 		h.writeInternalError(w, r, "failed to list grants", err)
@@ -406,9 +617,11 @@ func (h *Handlers) HandleUpdateAgent(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, r, http.StatusOK, agent)
 }
 
-// HandleAgentStats handles GET /v1/agents/{agent_id}/stats (admin-only).
-// Returns aggregate decision statistics for a specific agent.
+// HandleAgentStats handles GET /v1/agents/{agent_id}/stats (reader+).
+// Returns aggregate decision statistics for a specific agent. Non-admin
+// callers need a grant on the agent (or to be the agent).
 func (h *Handlers) HandleAgentStats(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
 	agentID := r.PathValue("agent_id")
 	if err := model.ValidateAgentID(agentID); err != nil {
@@ -416,6 +629,16 @@ func (h *Handlers) HandleAgentStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ok, err := canAccessAgent(r.Context(), h.db, claims, agentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this agent's stats")
+		return
+	}
+
 	// Verify the agent exists.
 	if _, err := h.db.GetAgentByAgentID(r.Context(), orgID, agentID); err != nil {
 		if isNotFoundError(err) {
@@ -432,6 +655,22 @@ func (h *Handlers) HandleAgentStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	usage, err := h.db.GetDecisionUsage(r.Context(), orgID, agentID, time.Now())
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get decision usage", err)
+		return
+	}
+	settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get org settings", err)
+		return
+	}
+	stats.Quota = &model.AgentQuotaStatus{Usage: usage}
+	if p := settings.Settings.DecisionQuota; p != nil {
+		stats.Quota.Limits = p.LimitsForAgent(agentID)
+		stats.Quota.OrgLimits = p.Org
+	}
+
 	writeJSON(w, r, http.StatusOK, model.AgentStatsResponse{
 		AgentID: agentID,
 		Stats:   stats,
@@ -439,9 +678,12 @@ func (h *Handlers) HandleAgentStats(w http.ResponseWriter, r *http.Request) {
 }
 
 // HandleDeleteAgent handles DELETE /v1/agents/{agent_id} (admin-only).
-// Deletes all data associated with the agent (GDPR right to erasure).
+// With AKASHI_SOFT_DELETE the agent and its data are tombstoned and purged
+// later by the retention worker. Otherwise, when destructive delete is
+// enabled, all data associated with the agent is deleted (GDPR right to
+// erasure).
 func (h *Handlers) HandleDeleteAgent(w http.ResponseWriter, r *http.Request) {
-	if !h.enableDestructiveDelete {
+	if !h.softDelete && !h.enableDestructiveDelete {
 		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
 			"destructive delete is disabled; set AKASHI_ENABLE_DESTRUCTIVE_DELETE=true to enable")
 		return
@@ -461,6 +703,11 @@ func (h *Handlers) HandleDeleteAgent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.softDelete {
+		h.tombstoneAgent(w, r, orgID, agentID)
+		return
+	}
+
 	// Block deletion if an active legal hold covers this agent's decisions.
 	holdActive, err := h.db.ActiveHoldsExistForAgent(r.Context(), orgID, agentID)
 	if err != nil {
@@ -498,6 +745,9 @@ func (h *Handlers) HandleDeleteAgent(w http.ResponseWriter, r *http.Request) {
 			"evidence":     result.Evidence,
 			"claims":       result.Claims,
 			"events":       result.Events,
+
+			"external_precedent_refs_cleared":  result.ExternalPrecedentRefsCleared,
+			"external_supersedes_refs_cleared": result.ExternalSupersedesRefsCleared,
 		}
 		_ = h.db.CompleteDeletionLog(r.Context(), orgID, logID, countMap)
 	}
@@ -508,6 +758,65 @@ func (h *Handlers) HandleDeleteAgent(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// tombstoneAgent soft-deletes an agent for HandleDeleteAgent. Tombstoning
+// removes nothing, so legal holds do not block it; they block the later purge.
+func (h *Handlers) tombstoneAgent(w http.ResponseWriter, r *http.Request, orgID uuid.UUID, agentID string) {
+	audit := h.buildAuditEntry(r, orgID, "tombstone_agent", "agent", agentID,
+		map[string]any{"agent_id": agentID}, nil, nil)
+	result, err := h.db.TombstoneAgentData(r.Context(), orgID, agentID, &audit)
+	if err != nil {
+		if errors.Is(err, storage.ErrAgentNotFound) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "agent not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to tombstone agent", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, model.DeleteAgentResponse{
+		AgentID:    agentID,
+		Deleted:    result,
+		Tombstoned: true,
+	})
+}
+
+// agentDeleteImpactResponse is the body of GET /v1/agents/{agent_id}/delete-impact.
+type agentDeleteImpactResponse struct {
+	storage.AgentDeleteImpact
+	// LegalHold is true when an active hold would block the delete.
+	LegalHold bool `json:"legal_hold"`
+}
+
+// HandleAgentDeleteImpact handles GET /v1/agents/{agent_id}/delete-impact (admin-only).
+// Reports what DELETE /v1/agents/{agent_id} would remove, and how many other
+// agents' decisions cite this agent's decisions as precedent or supersede
+// them, so admins can see referential fallout before a GDPR delete.
+func (h *Handlers) HandleAgentDeleteImpact(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	agentID := r.PathValue("agent_id")
+	if err := model.ValidateAgentID(agentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	impact, err := h.db.GetAgentDeleteImpact(r.Context(), orgID, agentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAgentNotFound) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "agent not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to compute delete impact", err)
+		return
+	}
+	holdActive, err := h.db.ActiveHoldsExistForAgent(r.Context(), orgID, agentID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to check legal holds", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, agentDeleteImpactResponse{AgentDeleteImpact: impact, LegalHold: holdActive})
+}
+
 // HandleUpdateAgentTags handles PATCH /v1/agents/{agent_id}/tags (admin-only).
 func (h *Handlers) HandleUpdateAgentTags(w http.ResponseWriter, r *http.Request) {
 	orgID := OrgIDFromContext(r.Context())
@@ -587,3 +896,180 @@ func isForeignKeyViolation(err error) bool {
 func isNotFoundError(err error) bool {
 	return errors.Is(err, storage.ErrNotFound) || errors.Is(err, pgx.ErrNoRows)
 }
+
+// OutboxFlusher synchronously works off the search outbox backlog.
+// Implemented by *search.OutboxWorker.
+type OutboxFlusher interface {
+	FlushBacklog(ctx context.Context) (processed, pending int, err error)
+}
+
+// adminFlushTimeout bounds POST /v1/admin/flush. It stays below the default
+// AKASHI_WRITE_TIMEOUT (30s) so the response is written before the server
+// gives up on the connection.
+const adminFlushTimeout = 25 * time.Second
+
+// HandleAdminFlush handles POST /v1/admin/flush (admin-only).
+// Forces a synchronous flush of the event buffer and works off the search
+// outbox backlog without shutting down, giving operators a durability
+// checkpoint before planned maintenance. Reuses the shutdown drain loops but
+// leaves the background workers running. Both stages always run; complete is
+// false if either did not finish within the deadline.
+func (h *Handlers) HandleAdminFlush(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(r.Context(), adminFlushTimeout)
+	defer cancel()
+
+	var resp model.FlushResponse
+	resp.Complete = true
+
+	if h.buffer != nil {
+		events, audits, err := h.buffer.Checkpoint(ctx)
+		resp.EventsFlushed = events
+		resp.AuditEntriesFlushed = audits
+		resp.BufferRemaining = h.buffer.Len()
+		if err != nil {
+			h.logger.Warn("admin flush: event buffer flush incomplete",
+				"error", err, "remaining", resp.BufferRemaining)
+			resp.Complete = false
+		}
+	}
+
+	if h.outbox != nil {
+		resp.OutboxEnabled = true
+		processed, pending, err := h.outbox.FlushBacklog(ctx)
+		resp.OutboxProcessed = processed
+		resp.OutboxPending = pending
+		if err != nil {
+			h.writeInternalError(w, r, "failed to flush search outbox", err)
+			return
+		}
+		if ctx.Err() != nil {
+			resp.Complete = false
+		}
+	}
+	resp.DurationMs = time.Since(start).Milliseconds()
+
+	h.logger.Info("admin flush completed",
+		"events_flushed", resp.EventsFlushed,
+		"audit_entries_flushed", resp.AuditEntriesFlushed,
+		"buffer_remaining", resp.BufferRemaining,
+		"outbox_processed", resp.OutboxProcessed,
+		"outbox_pending", resp.OutboxPending,
+		"complete", resp.Complete,
+		"duration_ms", resp.DurationMs,
+		"request_id", RequestIDFromContext(r.Context()))
+
+	if auditErr := h.recordMutationAuditBestEffort(r, orgID,
+		"admin_flush", "event_buffer", "global", nil, resp, nil,
+	); auditErr != nil {
+		h.logger.Error("failed to audit admin flush", "error", auditErr)
+	}
+
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+// maxRequeueDeadOutboxIDs caps outbox_ids in one requeue request.
+const maxRequeueDeadOutboxIDs = 1000
+
+// HandleListDeadOutbox handles GET /v1/admin/search-outbox/dead (admin-only).
+// Lists search outbox entries that exhausted AKASHI_OUTBOX_MAX_ATTEMPTS and
+// are no longer retried, with the last sync error for each.
+func (h *Handlers) HandleListDeadOutbox(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	limit := queryLimit(r, 50)
+	offset := queryOffset(r)
+
+	entries, total, err := h.db.ListDeadOutbox(r.Context(), orgID, limit, offset)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list dead outbox entries", err)
+		return
+	}
+
+	writeListJSON(w, r, entries, &total, offset+len(entries) < total, limit, offset)
+}
+
+// HandleRequeueDeadOutbox handles POST /v1/admin/search-outbox/dead/requeue (admin-only).
+// Moves dead-lettered entries back into the search outbox with their
+// attempts reset, typically after fixing the cause of a Qdrant sync failure.
+func (h *Handlers) HandleRequeueDeadOutbox(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.RequeueDeadOutboxRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if req.All == (len(req.OutboxIDs) > 0) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "exactly one of outbox_ids or all is required")
+		return
+	}
+	if len(req.OutboxIDs) > maxRequeueDeadOutboxIDs {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("outbox_ids must contain at most %d entries", maxRequeueDeadOutboxIDs))
+		return
+	}
+
+	var ids []int64
+	if !req.All {
+		ids = req.OutboxIDs
+	}
+	audit := h.buildAuditEntry(r, orgID, "requeue_dead_outbox", "search_outbox", "dead", req, nil, nil)
+	requeued, err := h.db.RequeueDeadOutboxWithAudit(r.Context(), orgID, ids, audit)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to requeue dead outbox entries", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, model.RequeueDeadOutboxResponse{Requeued: requeued})
+}
+
+// HandleListAudit handles GET /v1/audit (admin-only).
+// Lists mutation audit entries by default; type=access lists the access log,
+// which is only populated when AKASHI_ACCESS_LOG_ENABLED or
+// AKASHI_ACCESS_LOG_REQUESTS is set.
+// Optional filters: actor_agent_id, resource_id, resource_type, from, to (RFC3339).
+func (h *Handlers) HandleListAudit(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	filters := storage.AuditFilters{Type: model.AuditTypeMutation}
+	switch t := q.Get("type"); t {
+	case "", model.AuditTypeMutation:
+	case model.AuditTypeAccess:
+		filters.Type = t
+	default:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "type must be 'mutation' or 'access'")
+		return
+	}
+	if v := q.Get("actor_agent_id"); v != "" {
+		filters.ActorAgentID = &v
+	}
+	if v := q.Get("resource_id"); v != "" {
+		filters.ResourceID = &v
+	}
+	if v := q.Get("resource_type"); v != "" {
+		filters.ResourceType = &v
+	}
+	var err error
+	if filters.From, err = queryTime(r, "from"); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if filters.To, err = queryTime(r, "to"); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	limit := queryLimit(r, 50)
+	offset := queryOffset(r)
+
+	entries, total, err := h.db.ListAuditEntries(r.Context(), orgID, filters, limit, offset)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list audit entries", err)
+		return
+	}
+
+	writeListJSON(w, r, entries, &total, offset+len(entries) < total, limit, offset)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// decisionChangesResponse is the body of GET /v1/cdc/decisions.
+type decisionChangesResponse struct {
+	Changes []model.DecisionChange `json:"changes"`
+	// NextLSN is the cursor for the next poll: the last LSN returned, or
+	// after_lsn when nothing new was returned.
+	NextLSN int64 `json:"next_lsn"`
+	// CompactedLSN is the highest LSN removed by compaction.
+	CompactedLSN int64 `json:"compacted_lsn"`
+	// Truncated is true when after_lsn is below CompactedLSN, so entries
+	// between the cursor and the retained log may have been missed.
+	Truncated bool `json:"truncated"`
+}
+
+// HandleDecisionChanges handles GET /v1/cdc/decisions.
+// Returns decision change log entries with LSN greater than ?after_lsn= (default
+// 0), oldest first. Consumers pass next_lsn back as after_lsn to poll
+// incrementally; unlike NOTIFY, entries committed while a consumer is away
+// are still returned.
+func (h *Handlers) HandleDecisionChanges(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	var afterLSN int64
+	if v := r.URL.Query().Get("after_lsn"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "after_lsn must be a non-negative integer")
+			return
+		}
+		afterLSN = n
+	}
+	limit := queryLimit(r, 100)
+
+	page, err := h.db.ListDecisionChanges(r.Context(), orgID, afterLSN, limit)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list decision changes", err)
+		return
+	}
+
+	resp := decisionChangesResponse{
+		Changes:      page.Changes,
+		NextLSN:      afterLSN,
+		CompactedLSN: page.CompactedLSN,
+		Truncated:    afterLSN < page.CompactedLSN,
+	}
+	if n := len(page.Changes); n > 0 {
+		resp.NextLSN = page.Changes[n-1].LSN
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
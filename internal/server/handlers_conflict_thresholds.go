@@ -0,0 +1,80 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// HandleListConflictThresholds handles GET /v1/conflict-thresholds (admin-only).
+// Decision types without an override use AKASHI_CONFLICT_SIGNIFICANCE_THRESHOLD.
+func (h *Handlers) HandleListConflictThresholds(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	thresholds, err := h.db.ListConflictThresholds(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list conflict thresholds", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, thresholds)
+}
+
+// HandleSetConflictThreshold handles PUT /v1/conflict-thresholds/{decision_type}
+// (admin-only). The new threshold applies to conflicts scored from then on;
+// existing conflicts are not rescored.
+func (h *Handlers) HandleSetConflictThreshold(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	decisionType := strings.TrimSpace(r.PathValue("decision_type"))
+	if decisionType == "" {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "decision_type is required")
+		return
+	}
+
+	var req model.SetConflictThresholdRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if req.SignificanceThreshold <= 0 || req.SignificanceThreshold > 1 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			"significance_threshold must be greater than 0 and at most 1")
+		return
+	}
+
+	audit := h.buildAuditEntry(r, orgID, "set_conflict_threshold", "conflict_threshold", decisionType, nil, nil, nil)
+	ct, err := h.db.UpsertConflictThreshold(r.Context(), model.ConflictThreshold{
+		OrgID:                 orgID,
+		DecisionType:          decisionType,
+		SignificanceThreshold: req.SignificanceThreshold,
+		UpdatedBy:             claims.AgentID,
+	}, audit)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to set conflict threshold", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, ct)
+}
+
+// HandleDeleteConflictThreshold handles DELETE
+// /v1/conflict-thresholds/{decision_type} (admin-only), reverting the type to
+// the global threshold.
+func (h *Handlers) HandleDeleteConflictThreshold(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	decisionType := r.PathValue("decision_type")
+
+	audit := h.buildAuditEntry(r, orgID, "delete_conflict_threshold", "conflict_threshold", decisionType, nil, nil, nil)
+	if err := h.db.DeleteConflictThreshold(r.Context(), orgID, decisionType, audit); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "conflict threshold not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to delete conflict threshold", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/ashita-ai/akashi/internal/auth"
 	"github.com/ashita-ai/akashi/internal/conflicts"
 	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/service/decisions"
@@ -26,6 +30,32 @@ func (h *Handlers) HandleListConflicts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	limit := queryLimit(r, 25)
+
+	// Presence of the cursor parameter, even empty for the first page,
+	// selects keyset paging. Without it the offset path is kept for
+	// existing clients.
+	if r.URL.Query().Has("cursor") {
+		cursor, err := decodeConflictCursor(r.URL.Query().Get("cursor"))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid cursor")
+			return
+		}
+		conflicts, next, err := h.db.ListConflictsCursor(r.Context(), orgID, filters, cursor, limit)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to list conflicts", err)
+			return
+		}
+		// The cursor comes from the last row before access filtering, so
+		// hidden rows shorten a page without breaking the next one.
+		conflicts, err = filterConflictsByAccess(r.Context(), h.db, claims, conflicts, h.grantCache)
+		if err != nil {
+			h.writeInternalError(w, r, "authorization check failed", err)
+			return
+		}
+		writeCursorListJSON(w, r, conflicts, encodeConflictCursor(next), limit)
+		return
+	}
+
 	offset := queryOffset(r)
 
 	total, err := h.db.CountConflicts(r.Context(), orgID, filters)
@@ -117,8 +147,19 @@ var validResolutionStatuses = map[string]bool{
 	"false_positive": true,
 }
 
+// canActOnConflict reports whether the caller may change a conflict's status:
+// admins may act on any conflict, other roles only on conflicts where one
+// side was decided by their own agent.
+func canActOnConflict(claims *auth.Claims, c *model.DecisionConflict) bool {
+	if model.RoleAtLeast(claims.Role, model.RoleAdmin) {
+		return true
+	}
+	return c.AgentA == claims.AgentID || c.AgentB == claims.AgentID
+}
+
 // HandlePatchConflict handles PATCH /v1/conflicts/{id}.
-// Transitions a conflict to a new lifecycle state.
+// Transitions a conflict to a new lifecycle state. Moving a conflict to the
+// status it already has is rejected with 409.
 func (h *Handlers) HandlePatchConflict(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
@@ -148,19 +189,29 @@ func (h *Handlers) HandlePatchConflict(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	existing, err := h.db.GetConflict(r.Context(), id, orgID)
+	if err != nil || existing == nil {
+		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "conflict not found")
+		return
+	}
+	if !canActOnConflict(claims, existing) {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+			"can only update conflicts involving your own agent")
+		return
+	}
+	if existing.Status == req.Status {
+		writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+			fmt.Sprintf("conflict is already %s", req.Status))
+		return
+	}
+
 	// If a winner is declared, validate it belongs to this conflict before
 	// touching the DB (avoids a silent no-op or cross-conflict winner reference).
-	if req.WinningDecisionID != nil {
-		conflict, cErr := h.db.GetConflict(r.Context(), id, orgID)
-		if cErr != nil || conflict == nil {
-			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "conflict not found")
-			return
-		}
-		if *req.WinningDecisionID != conflict.DecisionAID && *req.WinningDecisionID != conflict.DecisionBID {
-			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-				"winning_decision_id must be one of the two decisions in this conflict")
-			return
-		}
+	if req.WinningDecisionID != nil &&
+		*req.WinningDecisionID != existing.DecisionAID && *req.WinningDecisionID != existing.DecisionBID {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			"winning_decision_id must be one of the two decisions in this conflict")
+		return
 	}
 
 	resolvedBy := claims.ActorID()
@@ -182,6 +233,12 @@ func (h *Handlers) HandlePatchConflict(w http.ResponseWriter, r *http.Request) {
 				"winning_decision_id must be one of the two decisions in this conflict")
 			return
 		}
+		if errors.Is(err, storage.ErrConflictStatusUnchanged) {
+			// A concurrent update got there first.
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+				fmt.Sprintf("conflict is already %s", req.Status))
+			return
+		}
 		h.writeInternalError(w, r, "failed to update conflict", err)
 		return
 	}
@@ -317,6 +374,11 @@ func (h *Handlers) HandleAdjudicateConflict(w http.ResponseWriter, r *http.Reque
 		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "conflict not found")
 		return
 	}
+	if !canActOnConflict(claims, conflict) {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+			"can only adjudicate conflicts involving your own agent")
+		return
+	}
 
 	// Validate winning_decision_id: if provided, must be one of the two conflict sides.
 	if req.WinningDecisionID != nil {
@@ -336,6 +398,10 @@ func (h *Handlers) HandleAdjudicateConflict(w http.ResponseWriter, r *http.Reque
 			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
 			return
 		}
+		if errors.Is(err, decisions.ErrAgentTombstoned) {
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, err.Error())
+			return
+		}
 		h.writeInternalError(w, r, "failed to resolve agent", err)
 		return
 	}
@@ -358,6 +424,7 @@ func (h *Handlers) HandleAdjudicateConflict(w http.ResponseWriter, r *http.Reque
 			Reasoning:    req.Reasoning,
 		},
 		APIKeyID:  claims.APIKeyID,
+		Namespace: NamespaceFromContext(r.Context()),
 		AuditMeta: h.buildAuditMeta(r, orgID),
 	}, storage.AdjudicateConflictInTraceParams{
 		ConflictID:        id,
@@ -593,7 +660,9 @@ func (h *Handlers) HandleConflictAnalytics(w http.ResponseWriter, r *http.Reques
 
 // HandleDecisionConflicts handles GET /v1/decisions/{id}/conflicts.
 // Returns conflicts involving a specific decision (as A or B side), paginated.
-// Accepts ?limit, ?offset, and ?status query parameters.
+// Accepts ?limit, ?offset, and ?status query parameters. The caller must be
+// able to read the decision; conflicts whose other side belongs to an agent
+// the caller cannot read are returned with that side redacted.
 func (h *Handlers) HandleDecisionConflicts(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
@@ -604,6 +673,29 @@ func (h *Handlers) HandleDecisionConflicts(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	d, err := h.db.GetDecision(r.Context(), orgID, decisionID, storage.GetDecisionOpts{})
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision", err)
+		return
+	}
+	if !inRequestNamespace(r, d) {
+		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+		return
+	}
+	ok, err := canAccessAgent(r.Context(), h.db, claims, d.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this decision")
+		return
+	}
+
 	limit := queryLimit(r, 50)
 	if limit > 200 {
 		limit = 200
@@ -627,15 +719,13 @@ func (h *Handlers) HandleDecisionConflicts(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	preFilterCount := len(conflicts)
-	conflicts, err = filterConflictsByAccess(r.Context(), h.db, claims, conflicts, h.grantCache)
+	conflicts, err = redactConflictCounterparts(r.Context(), h.db, claims, decisionID, conflicts, h.grantCache)
 	if err != nil {
 		h.writeInternalError(w, r, "authorization check failed", err)
 		return
 	}
 
-	ptotal, hasMore := computePagination(len(conflicts), preFilterCount, limit, offset, total)
-	writeListJSON(w, r, conflicts, ptotal, hasMore, limit, offset)
+	writeListJSON(w, r, conflicts, &total, offset+len(conflicts) < total, limit, offset)
 }
 
 // executeCascadeResolution auto-resolves related conflicts in the same group
@@ -672,8 +762,43 @@ func (h *Handlers) executeCascadeResolution(r *http.Request, orgID uuid.UUID, co
 	}
 }
 
+// encodeConflictCursor renders a keyset position as the opaque next_cursor
+// token. A nil cursor (last page) encodes as "".
+func encodeConflictCursor(c *storage.ConflictCursor) string {
+	if c == nil {
+		return ""
+	}
+	raw := c.DetectedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeConflictCursor parses a token produced by encodeConflictCursor. An
+// empty token means the first page and yields a nil cursor.
+func decodeConflictCursor(token string) (*storage.ConflictCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("malformed cursor")
+	}
+	detectedAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, err
+	}
+	cursorID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.ConflictCursor{DetectedAt: detectedAt, ID: cursorID}, nil
+}
+
 // parseConflictFilters extracts conflict filter parameters from the request query string.
-// Returns an error if conflict_kind is present but not a recognized value.
+// Returns an error if conflict_kind or reason_code is present but not a recognized value.
 func parseConflictFilters(r *http.Request) (storage.ConflictFilters, error) {
 	filters := storage.ConflictFilters{}
 	if dt := r.URL.Query().Get("decision_type"); dt != "" {
@@ -694,6 +819,12 @@ func parseConflictFilters(r *http.Request) (storage.ConflictFilters, error) {
 	if cat := r.URL.Query().Get("category"); cat != "" {
 		filters.Category = &cat
 	}
+	if rc := r.URL.Query().Get("reason_code"); rc != "" {
+		if !model.ValidConflictReasonCode(rc) {
+			return filters, errInvalidReasonCode(rc)
+		}
+		filters.ReasonCode = &rc
+	}
 	if st := r.URL.Query().Get("status"); st != "" {
 		filters.Status = &st
 	}
@@ -707,3 +838,7 @@ func parseConflictFilters(r *http.Request) (storage.ConflictFilters, error) {
 func errInvalidConflictKind(got string) error {
 	return errors.New("invalid conflict_kind " + got + "; valid values are cross_agent, self_contradiction")
 }
+
+func errInvalidReasonCode(got string) error {
+	return errors.New("invalid reason_code " + got + "; valid values are outcome_opposite, confidence_divergence, same_input_different_outcome")
+}
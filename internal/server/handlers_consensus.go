@@ -0,0 +1,163 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// defaultConsensusSpreadThreshold is the confidence spread at which agents
+// agreeing on an outcome are flagged as miscalibrated.
+const defaultConsensusSpreadThreshold = 0.3
+
+// HandleConfidenceConsensus handles GET /v1/consensus/confidence.
+// For each value of metadata_key on current decisions of decision_type, it
+// reports the agents that decided on it, their outcomes, and the spread of
+// their confidence. Entities where agents agree on the outcome but their
+// confidence spread reaches spread_threshold are flagged as a calibration
+// red flag. Only agents the caller may read are considered.
+func (h *Handlers) HandleConfidenceConsensus(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	decisionType := q.Get("decision_type")
+	metadataKey := q.Get("metadata_key")
+	if decisionType == "" || metadataKey == "" {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "decision_type and metadata_key are required")
+		return
+	}
+	var metadataValue *string
+	if q.Has("metadata_value") {
+		v := q.Get("metadata_value")
+		metadataValue = &v
+	}
+	threshold := defaultConsensusSpreadThreshold
+	if v := q.Get("spread_threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 1 {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "spread_threshold must be a number between 0 and 1")
+			return
+		}
+		threshold = t
+	}
+	limit := queryLimit(r, 50)
+
+	// Restrict the aggregate to readable agents up front so other agents'
+	// confidence never leaks through the spread statistics.
+	granted, err := authz.LoadGrantedSet(r.Context(), h.db, claims, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	var agentIDs []string
+	if granted != nil {
+		agentIDs = make([]string, 0, len(granted))
+		for id := range granted {
+			agentIDs = append(agentIDs, id)
+		}
+		slices.Sort(agentIDs)
+	}
+
+	rows, err := h.db.ListConsensusDecisions(r.Context(), orgID, NamespaceFromContext(r.Context()),
+		decisionType, metadataKey, metadataValue, agentIDs)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to load consensus decisions", err)
+		return
+	}
+
+	entities := buildConfidenceConsensus(rows, threshold)
+	report := model.ConfidenceConsensusReport{
+		DecisionType:    decisionType,
+		MetadataKey:     metadataKey,
+		SpreadThreshold: threshold,
+		TotalEntities:   len(entities),
+		Entities:        entities,
+	}
+	if len(report.Entities) > limit {
+		report.Entities = report.Entities[:limit]
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// buildConfidenceConsensus aggregates per-agent decisions (ordered by
+// metadata value) into per-entity confidence statistics. Entities decided on
+// by fewer than two agents are dropped. The result lists flagged entities
+// first, then by descending spread.
+func buildConfidenceConsensus(rows []storage.ConsensusDecision, threshold float64) []model.ConfidenceConsensusEntity {
+	entities := []model.ConfidenceConsensusEntity{}
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].MetadataValue == rows[start].MetadataValue {
+			end++
+		}
+		if group := rows[start:end]; len(group) >= 2 {
+			entities = append(entities, consensusEntity(group, threshold))
+		}
+		start = end
+	}
+	slices.SortStableFunc(entities, func(a, b model.ConfidenceConsensusEntity) int {
+		if a.CalibrationFlag != b.CalibrationFlag {
+			if a.CalibrationFlag {
+				return -1
+			}
+			return 1
+		}
+		switch {
+		case a.Spread > b.Spread:
+			return -1
+		case a.Spread < b.Spread:
+			return 1
+		}
+		return strings.Compare(a.MetadataValue, b.MetadataValue)
+	})
+	return entities
+}
+
+// consensusEntity computes the statistics for one entity's decisions, one
+// per agent. Outcomes agree when they match after trimming and case folding.
+func consensusEntity(group []storage.ConsensusDecision, threshold float64) model.ConfidenceConsensusEntity {
+	e := model.ConfidenceConsensusEntity{
+		MetadataValue:    group[0].MetadataValue,
+		AgentCount:       len(group),
+		OutcomeAgreement: true,
+		ConfidenceMin:    math.Inf(1),
+		ConfidenceMax:    math.Inf(-1),
+		Agents:           make([]model.ConfidenceConsensusDecision, 0, len(group)),
+	}
+	outcome := strings.ToLower(strings.TrimSpace(group[0].Outcome))
+	var sum float64
+	for _, d := range group {
+		c := float64(d.Confidence)
+		sum += c
+		e.ConfidenceMin = min(e.ConfidenceMin, c)
+		e.ConfidenceMax = max(e.ConfidenceMax, c)
+		if strings.ToLower(strings.TrimSpace(d.Outcome)) != outcome {
+			e.OutcomeAgreement = false
+		}
+		e.Agents = append(e.Agents, model.ConfidenceConsensusDecision{
+			DecisionID: d.DecisionID,
+			AgentID:    d.AgentID,
+			Outcome:    d.Outcome,
+			Confidence: d.Confidence,
+			ValidFrom:  d.ValidFrom,
+		})
+	}
+	n := float64(len(group))
+	e.ConfidenceMean = sum / n
+	var variance float64
+	for _, d := range group {
+		diff := float64(d.Confidence) - e.ConfidenceMean
+		variance += diff * diff
+	}
+	e.ConfidenceStddev = math.Sqrt(variance / n)
+	e.Spread = e.ConfidenceMax - e.ConfidenceMin
+	e.CalibrationFlag = e.OutcomeAgreement && e.Spread >= threshold
+	return e
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+func TestBuildConfidenceConsensus(t *testing.T) {
+	row := func(value, agent, outcome string, confidence float32) storage.ConsensusDecision {
+		return storage.ConsensusDecision{
+			MetadataValue: value, DecisionID: uuid.New(), AgentID: agent,
+			Outcome: outcome, Confidence: confidence,
+		}
+	}
+	rows := []storage.ConsensusDecision{
+		// Agree on outcome, wildly different confidence: red flag.
+		row("svc-a", "planner", "Use Postgres", 0.95),
+		row("svc-a", "reviewer", " use postgres", 0.35),
+		// Disagree on outcome: spread alone is not a calibration flag.
+		row("svc-b", "planner", "Use Redis", 0.9),
+		row("svc-b", "reviewer", "Use Memcached", 0.2),
+		// Agree with similar confidence.
+		row("svc-c", "planner", "Ship", 0.8),
+		row("svc-c", "reviewer", "Ship", 0.7),
+		row("svc-c", "coder", "Ship", 0.75),
+		// Single agent: not a cross-agent entity.
+		row("svc-d", "planner", "Ship", 0.5),
+	}
+
+	got := buildConfidenceConsensus(rows, 0.3)
+	require.Len(t, got, 3)
+
+	a := got[0]
+	assert.Equal(t, "svc-a", a.MetadataValue, "flagged entities come first")
+	assert.True(t, a.OutcomeAgreement)
+	assert.True(t, a.CalibrationFlag)
+	assert.InDelta(t, 0.35, a.ConfidenceMin, 1e-6)
+	assert.InDelta(t, 0.95, a.ConfidenceMax, 1e-6)
+	assert.InDelta(t, 0.65, a.ConfidenceMean, 1e-6)
+	assert.InDelta(t, 0.3, a.ConfidenceStddev, 1e-6)
+	assert.Len(t, a.Agents, 2)
+
+	b := got[1]
+	assert.Equal(t, "svc-b", b.MetadataValue, "then by descending spread")
+	assert.False(t, b.OutcomeAgreement)
+	assert.False(t, b.CalibrationFlag)
+
+	c := got[2]
+	assert.Equal(t, "svc-c", c.MetadataValue)
+	assert.Equal(t, 3, c.AgentCount)
+	assert.True(t, c.OutcomeAgreement)
+	assert.False(t, c.CalibrationFlag)
+	assert.InDelta(t, 0.1, c.Spread, 1e-6)
+
+	assert.Empty(t, buildConfidenceConsensus(nil, 0.3))
+}
@@ -453,6 +453,43 @@ func TestHandlersCritical_DeleteAgentDisabledByConfig(t *testing.T) {
 	assert.Contains(t, string(body), "destructive delete is disabled")
 }
 
+func TestHandlersCritical_DeleteAgentSoftDelete(t *testing.T) {
+	ts := criticalTestServer(t, func(cfg *server.ServerConfig) {
+		cfg.EnableDestructiveDelete = false
+		cfg.SoftDelete = true
+		cfg.SignupEnabled = true
+		cfg.SignupRateLimiter = ratelimit.NewMemoryLimiter(100, 100)
+	})
+	token := signupAndGetTokenCritical(t, ts.URL,
+		"SoftDelete Org "+uuid.NewString()[:4],
+		"softdel-admin",
+		"softdel-"+uuid.NewString()[:6]+"@test.com")
+
+	agentID := "softdel-agent-" + uuid.NewString()[:8]
+	createAgent(ts.URL, token, agentID, "Soft Delete Agent", "agent", "softdel-key-"+uuid.NewString()[:8])
+	traceDecisionCritical(t, ts.URL, token, agentID, "soft-delete-test", "will be tombstoned")
+
+	resp, err := authedRequest("DELETE", ts.URL+"/v1/agents/"+agentID, token, nil)
+	require.NoError(t, err)
+	var result struct {
+		Data model.DeleteAgentResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, result.Data.Tombstoned)
+
+	resp, err = authedRequest("GET", ts.URL+"/v1/agents/"+agentID, token, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "tombstoned agent should not be readable")
+
+	resp, err = authedRequest("DELETE", ts.URL+"/v1/agents/"+agentID, token, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "tombstoning twice reports not found")
+}
+
 // ===========================================================================
 // 10. DeleteAgent — creates deletion_log entries
 // ===========================================================================
@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +20,7 @@ import (
 	"github.com/ashita-ai/akashi/internal/integrity"
 	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/service/duplicates"
 	"github.com/ashita-ai/akashi/internal/service/quality"
 	"github.com/ashita-ai/akashi/internal/service/tracehealth"
 	"github.com/ashita-ai/akashi/internal/storage"
@@ -34,52 +37,8 @@ func (h *Handlers) HandleTrace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := model.ValidateAgentID(req.AgentID); err != nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-		return
-	}
-	if req.Decision.DecisionType == "" {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "decision.decision_type is required")
-		return
-	}
-	if req.Decision.Outcome == "" {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "decision.outcome is required")
-		return
-	}
-	if req.Decision.Confidence < 0 || req.Decision.Confidence > 1 {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "decision.confidence must be between 0 and 1")
-		return
-	}
-	if err := model.ValidateTraceDecision(req.Decision); err != nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-		return
-	}
-	if err := model.ValidateMetadataSize("metadata", req.Metadata); err != nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-		return
-	}
-	if err := model.ValidateMetadataSize("context", req.Context); err != nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-		return
-	}
-	if req.PrecedentReason != nil && len(*req.PrecedentReason) > model.MaxPrecedentReasonLen {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			fmt.Sprintf("precedent_reason exceeds maximum length of %d bytes", model.MaxPrecedentReasonLen))
-		return
-	}
-	if req.PrecedentReason != nil && req.PrecedentRef == nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			"precedent_reason requires precedent_ref to be set")
-		return
-	}
-	if req.SupersedesID != nil && *req.SupersedesID == uuid.Nil {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			"supersedes_id must be a valid non-nil UUID")
-		return
-	}
-	if req.SupersedesID != nil && req.PrecedentRef != nil && *req.SupersedesID == *req.PrecedentRef {
-		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
-			"supersedes_id and precedent_ref cannot reference the same decision")
+	if err := ValidateTraceRequest(&req); err != nil {
+		writeValidationError(w, r, err)
 		return
 	}
 
@@ -98,11 +57,28 @@ func (h *Handlers) HandleTrace(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
 			return
 		}
+		if errors.Is(err, decisions.ErrAgentTombstoned) {
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict, err.Error())
+			return
+		}
 		h.writeInternalError(w, r, "failed to resolve agent", err)
 		return
 	}
 
-	// Session from header.
+	if err := h.decisionSvc.CheckTracePolicy(r.Context(), orgID, req.AgentID, req.Decision.DecisionType, len(req.Decision.Alternatives)); err != nil {
+		var exceeded *model.QuotaExceededError
+		switch {
+		case errors.Is(err, decisions.ErrTracePolicy):
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		case errors.As(err, &exceeded):
+			writeQuotaExceeded(w, r, req.AgentID, exceeded)
+		default:
+			h.writeInternalError(w, r, "failed to check org policy", err)
+		}
+		return
+	}
+
+	// Session from header, else the agent's open session (POST /v1/sessions).
 	var sessionID *uuid.UUID
 	sessionHeader := ""
 	if sh := r.Header.Get("X-Akashi-Session"); sh != "" {
@@ -110,6 +86,12 @@ func (h *Handlers) HandleTrace(w http.ResponseWriter, r *http.Request) {
 		if sid, parseErr := uuid.Parse(sh); parseErr == nil {
 			sessionID = &sid
 		}
+	} else {
+		sessionID, err = h.db.GetOpenAgentSessionID(r.Context(), orgID, req.AgentID)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to load agent session", err)
+			return
+		}
 	}
 
 	// Build agent context concurrently with the idempotency check — they're independent.
@@ -155,43 +137,61 @@ func (h *Handlers) HandleTrace(w http.ResponseWriter, r *http.Request) {
 		SupersedesID:    req.SupersedesID,
 		SessionID:       sessionID,
 		AgentContext:    agentContext,
+		ContextSnapshot: req.ContextSnapshot,
 		APIKeyID:        claims.APIKeyID,
+		Namespace:       NamespaceFromContext(r.Context()),
 		AuditMeta:       h.buildAuditMeta(r, orgID),
+
+		SupersedeMatchKeys: supersedeMatchKeys(req.SupersedeMatching),
+		Dedupe:             req.Dedupe,
 	})
 	if err != nil {
 		h.clearIdempotentWrite(r, orgID, idem)
+		if errors.Is(err, decisions.ErrContextSnapshotTooLarge) {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+			return
+		}
 		if req.SupersedesID != nil && (errors.Is(err, storage.ErrNotFound) || isForeignKeyViolation(err)) {
 			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
 				"superseded decision not found or already superseded")
 			return
 		}
+		if req.SupersedeMatching != nil && errors.Is(err, storage.ErrNotFound) {
+			// Another trace superseded the matched decision between lookup and write.
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+				"matched decision was superseded concurrently; retry the trace")
+			return
+		}
 		h.writeInternalError(w, r, "failed to create trace", err)
 		return
 	}
 
-	// Fire OnDecisionTraced hooks asynchronously. Hook failures are logged
-	// but never fail the request — the decision is already durably stored.
-	if len(h.decisionHooks) > 0 {
-		decision := result.Decision
-		hooks := h.decisionHooks
-		logger := h.logger
-		go func() {
-			hookCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-			for _, hook := range hooks {
-				if err := hook.OnDecisionTraced(hookCtx, decision); err != nil {
-					logger.Warn("event hook OnDecisionTraced failed", "error", err)
-				}
-			}
-		}()
+	if result.DuplicateOf != nil {
+		// Nothing was recorded, so no hooks fire and the status is 200.
+		resp := model.TraceResponse{
+			RunID:       result.RunID,
+			DecisionID:  result.DecisionID,
+			DuplicateOf: result.DuplicateOf,
+		}
+		h.completeIdempotentWriteBestEffort(r, orgID, idem, http.StatusOK, resp)
+		writeJSON(w, r, http.StatusOK, resp)
+		return
 	}
 
+	h.fireDecisionTraced(result.Decision)
+
 	resp := model.TraceResponse{
 		RunID:            result.RunID,
 		DecisionID:       result.DecisionID,
 		EventCount:       result.EventCount,
 		EmbeddingSkipped: result.EmbeddingSkipped,
 	}
+	if result.Decision.SupersedesID != nil {
+		resp.SupersededID = result.Decision.SupersedesID
+		if req.SupersedeMatching != nil {
+			resp.Decision = &result.Decision
+		}
+	}
 	if warnings := model.HighConfidenceWarnings(req.Decision.Confidence, len(req.Decision.Evidence), h.highConfidenceWarnThreshold); len(warnings) > 0 {
 		resp.Warnings = warnings
 	}
@@ -210,6 +210,78 @@ func (h *Handlers) HandleTrace(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, r, http.StatusCreated, resp)
 }
 
+// ValidateTraceRequest checks a trace request body, normalizing percent or
+// grade confidence onto the unit scale in place (the original value is kept
+// in metadata). Every problem found is reported at once as a
+// model.ValidationErrors whose messages are safe to show to the caller.
+// The gRPC Trace RPC applies the same checks.
+func ValidateTraceRequest(req *model.TraceRequest) error {
+	var errs model.ValidationErrors
+	errs.AddErr("agent_id", "", model.ValidateAgentID(req.AgentID))
+	if req.Decision.DecisionType == "" {
+		errs.Add("decision.decision_type", "decision.decision_type is required")
+	}
+	if req.Decision.Outcome == "" {
+		errs.Add("decision.outcome", "decision.outcome is required")
+	}
+	if original, err := model.NormalizeConfidenceScale(&req.Decision); err != nil {
+		errs.AddErr("decision.confidence", "", err)
+	} else {
+		if original != nil {
+			if req.Metadata == nil {
+				req.Metadata = map[string]any{}
+			}
+			req.Metadata[model.ConfidenceOriginalKey] = original
+		}
+		if req.Decision.Confidence < 0 || req.Decision.Confidence > 1 {
+			errs.Add("decision.confidence", "decision.confidence must be between 0 and 1")
+		}
+	}
+	errs.AddErr("decision", "decision.", model.ValidateTraceDecision(req.Decision))
+	errs.AddErr("metadata", "", model.ValidateMetadataSize("metadata", req.Metadata))
+	errs.AddErr("context", "", model.ValidateMetadataSize("context", req.Context))
+	if req.ContextSnapshot != nil {
+		if err := req.ContextSnapshot.Validate(); err != nil {
+			errs.Add("context_snapshot", "context_snapshot: %v", err)
+		}
+	}
+	if req.PrecedentReason != nil && len(*req.PrecedentReason) > model.MaxPrecedentReasonLen {
+		errs.Add("precedent_reason", "precedent_reason exceeds maximum length of %d bytes", model.MaxPrecedentReasonLen)
+	}
+	if req.PrecedentReason != nil && req.PrecedentRef == nil {
+		errs.Add("precedent_reason", "precedent_reason requires precedent_ref to be set")
+	}
+	if req.SupersedesID != nil && *req.SupersedesID == uuid.Nil {
+		errs.Add("supersedes_id", "supersedes_id must be a valid non-nil UUID")
+	}
+	if req.SupersedesID != nil && req.PrecedentRef != nil && *req.SupersedesID == *req.PrecedentRef {
+		errs.Add("supersedes_id", "supersedes_id and precedent_ref cannot reference the same decision")
+	}
+	errs.AddErr("supersede_matching", "", model.ValidateSupersedeMatching(*req))
+	return errs.Err()
+}
+
+// fireDecisionTraced runs OnDecisionTraced hooks asynchronously. Hook failures
+// are logged but never fail the request — the decisions are already durably stored.
+func (h *Handlers) fireDecisionTraced(decisions ...model.Decision) {
+	if len(h.decisionHooks) == 0 || len(decisions) == 0 {
+		return
+	}
+	hooks := h.decisionHooks
+	logger := h.logger
+	go func() {
+		hookCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, decision := range decisions {
+			for _, hook := range hooks {
+				if err := hook.OnDecisionTraced(hookCtx, decision); err != nil {
+					logger.Warn("event hook OnDecisionTraced failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
 // buildTraceAgentContext constructs the namespaced agent_context map for a
 // trace request. It merges three sources:
 //   - "client": caller-supplied context from the request body (self-reported).
@@ -276,53 +348,7 @@ func (h *Handlers) buildTraceAgentContext(
 
 	// Normalize project name: resolve workspace aliases, repo_url parsing,
 	// and server-inferred values to a canonical project name.
-	if errMsg := normalizeTraceProject(
-		clientCtx,
-		"", // HTTP handler has no server-inferred project (can't run git on client's machine)
-		func(project string) string {
-			canonical, err := h.db.ResolveProjectAlias(r.Context(), orgID, project)
-			if err != nil {
-				return ""
-			}
-			return canonical
-		},
-		func(project string) bool {
-			exists, err := h.db.ProjectExists(r.Context(), orgID, project)
-			if err != nil {
-				// DB error — fail closed. Reject the project rather than
-				// silently accepting an unvalidated name.
-				h.logger.Error("project existence check failed", "project", project, "error", err)
-				return false
-			}
-			if exists {
-				return true
-			}
-			// First-ever project in this org — accept to bootstrap.
-			hasProjects, hpErr := h.db.HasAnyProjects(r.Context(), orgID)
-			if hpErr != nil {
-				h.logger.Error("has-any-projects check failed", "error", hpErr)
-				return false
-			}
-			return !hasProjects
-		},
-		func() bool {
-			hasProjects, err := h.db.HasAnyProjects(r.Context(), orgID)
-			if err != nil {
-				h.logger.Error("has-any-projects check failed", "error", err)
-				return false // fail open for bootstrapping
-			}
-			return hasProjects
-		},
-		func() []string {
-			projects, err := h.db.DistinctProjects(r.Context(), orgID)
-			if err != nil {
-				h.logger.Warn("distinct projects lookup failed (suggestions suppressed)", "error", err)
-				return nil
-			}
-			return projects
-		},
-		h.logger,
-	); errMsg != "" {
+	if errMsg := NormalizeTraceProjectForOrg(r.Context(), h.db, orgID, clientCtx, h.logger); errMsg != "" {
 		return nil, errMsg
 	}
 
@@ -337,7 +363,10 @@ func (h *Handlers) buildTraceAgentContext(
 }
 
 // HandleGetDecision handles GET /v1/decisions/{id} (reader+).
-// Returns a single decision by UUID with alternatives and evidence.
+// Returns a single decision by UUID. Alternatives and evidence are included
+// by default; see resolveIncludes for include= and org default_includes.
+// Evidence content stored out of line is rehydrated unless include_blobs=false,
+// which returns blob references instead.
 func (h *Handlers) HandleGetDecision(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
 	orgID := OrgIDFromContext(r.Context())
@@ -348,9 +377,21 @@ func (h *Handlers) HandleGetDecision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	includeBlobs := true
+	if v := r.URL.Query().Get("include_blobs"); v != "" {
+		includeBlobs, err = strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "include_blobs must be true or false")
+			return
+		}
+	}
+
+	requested, explicit := parseIncludeParam(r)
+	include := h.resolveIncludes(r, orgID, model.IncludeEndpointGetDecision, requested, explicit)
 	d, err := h.db.GetDecision(r.Context(), orgID, id, storage.GetDecisionOpts{
-		IncludeAlts:     true,
-		IncludeEvidence: true,
+		IncludeAlts:     slices.Contains(include, "alternatives"),
+		IncludeEvidence: slices.Contains(include, "evidence"),
+		IncludeBlobs:    includeBlobs,
 	})
 	if err != nil {
 		if isNotFoundError(err) {
@@ -360,6 +401,10 @@ func (h *Handlers) HandleGetDecision(w http.ResponseWriter, r *http.Request) {
 		h.writeInternalError(w, r, "failed to get decision", err)
 		return
 	}
+	if !inRequestNamespace(r, d) {
+		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+		return
+	}
 
 	ok, err := canAccessAgent(r.Context(), h.db, claims, d.AgentID)
 	if err != nil {
@@ -410,9 +455,39 @@ func (h *Handlers) HandleGetDecision(w http.ResponseWriter, r *http.Request) {
 		d.AssessmentSummary = &summary
 	}
 
+	h.recordAccess(r, orgID, "decision", []uuid.UUID{d.ID}, nil)
 	writeJSON(w, r, http.StatusOK, d)
 }
 
+// validateAgentRoles checks a filters.agent_roles value.
+func validateAgentRoles(roles []model.AgentRole) error {
+	for _, role := range roles {
+		if model.RoleRank(role) == 0 {
+			return fmt.Errorf("filters.agent_roles: invalid role %q: must be one of platform_admin, org_owner, admin, agent, reader", role)
+		}
+	}
+	return nil
+}
+
+// applyAgentRoleFilter resolves f.AgentRoles to the agents currently holding
+// those roles and narrows f.AgentIDs to them, so storage filters with its
+// usual agent_id = ANY(...) condition. Returns false when no agent can match;
+// the query then has no results and need not run.
+func (h *Handlers) applyAgentRoleFilter(ctx context.Context, orgID uuid.UUID, f *model.QueryFilters) (bool, error) {
+	if len(f.AgentRoles) == 0 {
+		return true, nil
+	}
+	ids, err := h.db.ListAgentIDsByRoles(ctx, orgID, f.AgentRoles)
+	if err != nil {
+		return false, err
+	}
+	if len(f.AgentIDs) > 0 {
+		ids = slices.DeleteFunc(ids, func(id string) bool { return !slices.Contains(f.AgentIDs, id) })
+	}
+	f.AgentIDs = ids
+	return len(ids) > 0, nil
+}
+
 // HandleQuery handles POST /v1/query.
 func (h *Handlers) HandleQuery(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
@@ -423,6 +498,73 @@ func (h *Handlers) HandleQuery(w http.ResponseWriter, r *http.Request) {
 		handleDecodeError(w, r, err)
 		return
 	}
+	if !normalizeQueryRequest(w, r, &req) {
+		return
+	}
+	// Captured before agent_roles narrows the filter to role members.
+	requestedAgentIDs := req.Filters.AgentIDs
+	anyAgent, err := h.applyAgentRoleFilter(r.Context(), orgID, &req.Filters)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to resolve agent roles", err)
+		return
+	}
+	if !anyAgent {
+		total := 0
+		writeListJSON(w, r, []model.Decision{}, &total, false, req.Limit, req.Offset)
+		return
+	}
+
+	ns := NamespaceFromContext(r.Context())
+	req.Filters.Namespace = &ns
+	// An omitted include applies the endpoint default; [] or ["none"] is lean.
+	req.Include = h.resolveIncludes(r, orgID, model.IncludeEndpointQuery, req.Include, req.Include != nil)
+
+	decisions, total, err := h.decisionSvc.Query(r.Context(), orgID, req)
+	if err != nil {
+		h.writeInternalError(w, r, "query failed", err)
+		return
+	}
+
+	preFilterCount := len(decisions)
+	decisions, err = filterDecisionsByAccess(r.Context(), h.db, claims, decisions, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+
+	h.recordAccess(r, orgID, "decision", decisionIDs(decisions), nil)
+	ptotal, hasMore := computePagination(len(decisions), preFilterCount, req.Limit, req.Offset, total)
+	resp := model.ListResponse{Data: decisions, Total: ptotal, HasMore: hasMore, Limit: req.Limit, Offset: req.Offset}
+	if req.Suggest && len(decisions) == 0 {
+		resp.Suggestions = h.suggestAgentIDs(r, claims, orgID, requestedAgentIDs)
+	}
+	writeListResponse(w, r, resp)
+}
+
+// suggestAgentIDs returns existing agent_ids the caller may read that look
+// like misspellings of agentIDs. Suggestions are only a hint, so a lookup
+// failure is logged and yields none rather than failing the request.
+func (h *Handlers) suggestAgentIDs(r *http.Request, claims *auth.Claims, orgID uuid.UUID, agentIDs []string) []string {
+	if len(agentIDs) == 0 {
+		return nil
+	}
+	suggestions, err := h.decisionSvc.SuggestAgentIDs(r.Context(), orgID, agentIDs)
+	if err == nil {
+		suggestions, err = authz.FilterAgentIDs(r.Context(), h.db, claims, suggestions, h.grantCache)
+	}
+	if err != nil {
+		h.logger.Warn("agent_id suggestion lookup failed",
+			"error", err,
+			"request_id", RequestIDFromContext(r.Context()))
+		return nil
+	}
+	return suggestions
+}
+
+// normalizeQueryRequest clamps pagination and validates filters for a
+// /v1/query body, writing a 400 listing every invalid filter and returning
+// false on invalid input.
+func normalizeQueryRequest(w http.ResponseWriter, r *http.Request, req *model.QueryRequest) bool {
 	if req.Limit <= 0 {
 		req.Limit = 50
 	} else if req.Limit > maxQueryLimit {
@@ -434,22 +576,69 @@ func (h *Handlers) HandleQuery(w http.ResponseWriter, r *http.Request) {
 	if req.Offset > maxQueryOffset {
 		req.Offset = maxQueryOffset
 	}
+	var errs model.ValidationErrors
+	if width := req.Filters.MinConfidenceWidth; width != nil && (*width < 0 || *width > 1) {
+		errs.Add("filters.min_confidence_width", "filters.min_confidence_width must be between 0 and 1")
+	}
+	if lo, hi := req.Filters.TemperatureMin, req.Filters.TemperatureMax; lo != nil && hi != nil && *lo > *hi {
+		errs.Add("filters.temperature_min", "filters.temperature_min must not exceed filters.temperature_max")
+	}
+	errs.AddErr("filters.agent_roles", "", validateAgentRoles(req.Filters.AgentRoles))
+	if err := model.ValidateContextFilters(req.Filters.ContextFilters); err != nil {
+		errs.Add("filters.agent_context", "filters.%v", err)
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs)
+		return false
+	}
+	return true
+}
 
-	decisions, total, err := h.decisionSvc.Query(r.Context(), orgID, req)
-	if err != nil {
-		h.writeInternalError(w, r, "query failed", err)
+// HandleExplainQuery handles POST /v1/admin/query/explain (admin-only).
+// Accepts the same body as POST /v1/query, builds the identical SQL, and
+// returns its EXPLAIN (FORMAT JSON) plan without executing the query.
+func (h *Handlers) HandleExplainQuery(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.QueryRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if !normalizeQueryRequest(w, r, &req) {
 		return
 	}
 
-	preFilterCount := len(decisions)
-	decisions, err = filterDecisionsByAccess(r.Context(), h.db, claims, decisions, h.grantCache)
+	type explainResponse struct {
+		SQL  string          `json:"sql,omitempty"`
+		Plan json.RawMessage `json:"plan"`
+		// Note explains why no plan was produced, when /v1/query would
+		// short-circuit before reaching the database.
+		Note string `json:"note,omitempty"`
+	}
+
+	anyAgent, err := h.applyAgentRoleFilter(r.Context(), orgID, &req.Filters)
 	if err != nil {
-		h.writeInternalError(w, r, "authorization check failed", err)
+		h.writeInternalError(w, r, "failed to resolve agent roles", err)
+		return
+	}
+	if !anyAgent {
+		writeJSON(w, r, http.StatusOK, explainResponse{
+			Plan: json.RawMessage("null"),
+			Note: "no agents match filters.agent_roles; /v1/query returns an empty result without querying",
+		})
 		return
 	}
 
-	ptotal, hasMore := computePagination(len(decisions), preFilterCount, req.Limit, req.Offset, total)
-	writeListJSON(w, r, decisions, ptotal, hasMore, req.Limit, req.Offset)
+	ns := NamespaceFromContext(r.Context())
+	req.Filters.Namespace = &ns
+
+	sql, plan, err := h.db.ExplainQueryDecisions(r.Context(), orgID, req)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to explain query", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, explainResponse{SQL: sql, Plan: plan})
 }
 
 // HandleTemporalQuery handles POST /v1/query/temporal.
@@ -469,6 +658,22 @@ func (h *Handlers) HandleTemporalQuery(w http.ResponseWriter, r *http.Request) {
 		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "as_of must not be in the future")
 		return
 	}
+	if err := validateAgentRoles(req.Filters.AgentRoles); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	anyAgent, err := h.applyAgentRoleFilter(r.Context(), orgID, &req.Filters)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to resolve agent roles", err)
+		return
+	}
+	if !anyAgent {
+		writeJSON(w, r, http.StatusOK, model.TemporalQueryResponse{AsOf: req.AsOf, Decisions: []model.Decision{}})
+		return
+	}
+
+	ns := NamespaceFromContext(r.Context())
+	req.Filters.Namespace = &ns
 
 	decisions, err := h.decisionSvc.QueryTemporal(r.Context(), orgID, req)
 	if err != nil {
@@ -482,12 +687,151 @@ func (h *Handlers) HandleTemporalQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordAccess(r, orgID, "decision", decisionIDs(decisions), nil)
 	writeJSON(w, r, http.StatusOK, model.TemporalQueryResponse{
 		AsOf:      req.AsOf,
 		Decisions: decisions,
 	})
 }
 
+// temporalDiffSnapshotLimit is the most decisions read for each side of a
+// temporal diff (the temporal query cap).
+const temporalDiffSnapshotLimit = 1000
+
+// HandleTemporalDiff handles POST /v1/query/temporal/diff. It runs the
+// temporal query for the agent at from and at to and reports decisions added,
+// superseded, and revised in between.
+func (h *Handlers) HandleTemporalDiff(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.TemporalDiffRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if err := model.ValidateAgentID(req.AgentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if req.From.IsZero() || req.To.IsZero() {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "from and to are required")
+		return
+	}
+	if !req.From.Before(req.To) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "from must be before to")
+		return
+	}
+	// Same clock-skew tolerance as HandleTemporalQuery.
+	if req.To.After(time.Now().Add(time.Minute)) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "to must not be in the future")
+		return
+	}
+
+	ns := NamespaceFromContext(r.Context())
+	snapshot := func(asOf time.Time) ([]model.Decision, error) {
+		decisions, err := h.decisionSvc.QueryTemporal(r.Context(), orgID, model.TemporalQueryRequest{
+			AsOf:    asOf,
+			Filters: model.QueryFilters{AgentIDs: []string{req.AgentID}, Namespace: &ns},
+			Limit:   temporalDiffSnapshotLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return filterDecisionsByAccess(r.Context(), h.db, claims, decisions, h.grantCache)
+	}
+	before, err := snapshot(req.From)
+	if err != nil {
+		h.writeInternalError(w, r, "temporal diff failed", err)
+		return
+	}
+	after, err := snapshot(req.To)
+	if err != nil {
+		h.writeInternalError(w, r, "temporal diff failed", err)
+		return
+	}
+
+	resp, err := diffTemporalSnapshots(before, after, func(id uuid.UUID) ([]uuid.UUID, error) {
+		return h.db.GetRevisionChainIDs(r.Context(), id, orgID)
+	})
+	if err != nil {
+		h.writeInternalError(w, r, "temporal diff failed", err)
+		return
+	}
+	resp.AgentID = req.AgentID
+	resp.From = req.From
+	resp.To = req.To
+	resp.Truncated = len(before) >= temporalDiffSnapshotLimit || len(after) >= temporalDiffSnapshotLimit
+
+	ids := decisionIDs(resp.Added)
+	ids = append(ids, decisionIDs(resp.Superseded)...)
+	for _, c := range resp.Changed {
+		ids = append(ids, c.Before.ID, c.After.ID)
+	}
+	h.recordAccess(r, orgID, "decision", ids, nil)
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+// diffTemporalSnapshots compares the decisions valid at two points in time.
+// A decision valid only at the later point is paired with a decision valid
+// only at the earlier point from the same revision chain, as reported by
+// chainIDs; paired decisions are changed, unpaired ones added or superseded.
+// chainIDs is only called for decisions that differ between the snapshots.
+func diffTemporalSnapshots(before, after []model.Decision, chainIDs func(uuid.UUID) ([]uuid.UUID, error)) (model.TemporalDiffResponse, error) {
+	resp := model.TemporalDiffResponse{
+		Added:      []model.Decision{},
+		Superseded: []model.Decision{},
+		Changed:    []model.TemporalDiffChange{},
+	}
+	inAfter := make(map[uuid.UUID]bool, len(after))
+	for _, d := range after {
+		inAfter[d.ID] = true
+	}
+	inBefore := make(map[uuid.UUID]bool, len(before))
+	onlyBefore := make(map[uuid.UUID]model.Decision)
+	for _, d := range before {
+		inBefore[d.ID] = true
+		if !inAfter[d.ID] {
+			onlyBefore[d.ID] = d
+		}
+	}
+
+	for _, d := range after {
+		if inBefore[d.ID] {
+			continue
+		}
+		chain, err := chainIDs(d.ID)
+		if err != nil {
+			return model.TemporalDiffResponse{}, fmt.Errorf("revision chain for %s: %w", d.ID, err)
+		}
+		var prior *model.Decision
+		for _, id := range chain {
+			if p, ok := onlyBefore[id]; ok {
+				prior = &p
+				delete(onlyBefore, id)
+				break
+			}
+		}
+		if prior == nil {
+			resp.Added = append(resp.Added, d)
+			continue
+		}
+		resp.Changed = append(resp.Changed, model.TemporalDiffChange{
+			Before:         *prior,
+			After:          d,
+			OutcomeChanged: prior.Outcome != d.Outcome,
+		})
+	}
+
+	// Keep the earlier snapshot's order for decisions no longer valid.
+	for _, d := range before {
+		if _, ok := onlyBefore[d.ID]; ok {
+			resp.Superseded = append(resp.Superseded, d)
+		}
+	}
+	return resp, nil
+}
+
 // HandleAgentHistory handles GET /v1/agents/{agent_id}/history.
 func (h *Handlers) HandleAgentHistory(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
@@ -527,10 +871,90 @@ func (h *Handlers) HandleAgentHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordAccess(r, orgID, "decision", decisionIDs(decisions), nil)
 	ptotal := total
 	writeListJSON(w, r, decisions, &ptotal, offset+len(decisions) < total, limit, offset)
 }
 
+// HandleAgentFlipFlops handles GET /v1/agents/{agent_id}/flip-flops.
+// Lists the agent's revision chains that reversed their outcome at least
+// ?min_flips= times (default 3) within ?window= (default 24h), scanning
+// chains revised since ?since= (default 30 days ago). Up to ?limit= chains
+// (default 100) are scanned, most recently revised first.
+func (h *Handlers) HandleAgentFlipFlops(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	agentID := r.PathValue("agent_id")
+	if err := model.ValidateAgentID(agentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	ok, err := canAccessAgent(r.Context(), h.db, claims, agentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this agent's history")
+		return
+	}
+
+	window := model.DefaultFlipFlopWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		window, err = time.ParseDuration(v)
+		if err != nil || window <= 0 {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				"window must be a positive duration (e.g. 24h, 90m)")
+			return
+		}
+	}
+	minFlips := queryInt(r, "min_flips", model.DefaultFlipFlopMinFlips)
+	if minFlips < 1 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "min_flips must be >= 1")
+		return
+	}
+	since, err := queryTime(r, "since")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if since == nil {
+		d := time.Now().UTC().AddDate(0, 0, -30)
+		since = &d
+	}
+	limit := queryLimit(r, 100)
+
+	heads, err := h.db.ListRevisedDecisionHeads(r.Context(), orgID, agentID, *since, limit)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list revised decisions", err)
+		return
+	}
+	chains, err := h.db.GetDecisionRevisionsBatch(r.Context(), orgID, heads)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get revisions", err)
+		return
+	}
+
+	flagged := []model.FlipFlop{}
+	for _, id := range heads {
+		ff, ok := model.DetectFlipFlop(chains[id], window, minFlips)
+		if !ok {
+			continue
+		}
+		// A chain may include revisions by agents the caller cannot see.
+		ff.Revisions, err = filterDecisionsByAccess(r.Context(), h.db, claims, chains[id], h.grantCache)
+		if err != nil {
+			h.writeInternalError(w, r, "authorization check failed", err)
+			return
+		}
+		flagged = append(flagged, ff)
+	}
+
+	total := len(flagged)
+	writeListJSON(w, r, flagged, &total, false, limit, 0)
+}
+
 // HandleSearch handles POST /v1/search.
 func (h *Handlers) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	claims := ClaimsFromContext(r.Context())
@@ -550,6 +974,44 @@ func (h *Handlers) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	if req.Limit <= 0 || req.Limit > 1000 {
 		req.Limit = 100
 	}
+	if req.TimeRange != nil {
+		if req.Filters.TimeRange != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "set time_range or filters.time_range, not both")
+			return
+		}
+		req.Filters.TimeRange = req.TimeRange
+	}
+	if tr := req.Filters.TimeRange; tr != nil && tr.From != nil && tr.To != nil && tr.To.Before(*tr.From) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "time_range.to must not be before time_range.from")
+		return
+	}
+	if hl := req.RecencyHalfLifeDays; hl != nil && *hl < 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "recency_half_life_days must be >= 0")
+		return
+	}
+	req.Filters.RecencyHalfLifeDays = req.RecencyHalfLifeDays
+	if err := validateAgentRoles(req.Filters.AgentRoles); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	anyAgent, err := h.applyAgentRoleFilter(r.Context(), orgID, &req.Filters)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to resolve agent roles", err)
+		return
+	}
+	if anyAgent {
+		// Rank only within the caller's granted agents, not the whole org.
+		anyAgent, err = authz.ScopeFiltersToGranted(r.Context(), h.db, claims, &req.Filters, h.grantCache)
+		if err != nil {
+			h.writeInternalError(w, r, "authorization check failed", err)
+			return
+		}
+	}
+	if !anyAgent {
+		total := 0
+		writeListJSON(w, r, []model.SearchResult{}, &total, false, 0, 0)
+		return
+	}
 
 	// Detect whether Qdrant is reachable before the search. If the searcher is
 	// absent or unhealthy, the service falls back to text search — we signal this
@@ -559,6 +1021,9 @@ func (h *Handlers) HandleSearch(w http.ResponseWriter, r *http.Request) {
 		searchBackend = "text"
 	}
 
+	ns := NamespaceFromContext(r.Context())
+	req.Filters.Namespace = &ns
+
 	results, err := h.decisionSvc.Search(r.Context(), orgID, req.Query, req.Semantic, req.Filters, req.Limit)
 	if err != nil {
 		h.writeInternalError(w, r, "search failed", err)
@@ -571,6 +1036,12 @@ func (h *Handlers) HandleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]uuid.UUID, len(results))
+	for i := range results {
+		ids[i] = results[i].Decision.ID
+	}
+	h.recordAccess(r, orgID, "decision", ids, nil)
+
 	total := len(results)
 	w.Header().Set("X-Search-Backend", searchBackend)
 	writeListJSON(w, r, results, &total, false, len(results), 0)
@@ -614,6 +1085,7 @@ func (h *Handlers) HandleCheck(w http.ResponseWriter, r *http.Request) {
 		Query:        req.Query,
 		AgentID:      req.AgentID,
 		Project:      req.Project,
+		Namespace:    NamespaceFromContext(r.Context()),
 		Limit:        req.Limit,
 	})
 	if err != nil {
@@ -646,6 +1118,10 @@ func (h *Handlers) HandleCheck(w http.ResponseWriter, r *http.Request) {
 		resp.Conflicts = filteredConflicts
 	}
 	resp.HasPrecedent = len(resp.Decisions) > 0
+	h.recordAccess(r, orgID, "decision", decisionIDs(resp.Decisions), nil)
+	if req.Suggest && len(resp.Decisions) == 0 && req.AgentID != "" {
+		resp.Suggestions = h.suggestAgentIDs(r, claims, orgID, []string{req.AgentID})
+	}
 
 	// Concise format: compact the response using the same logic as the MCP layer.
 	if req.Format == "concise" {
@@ -664,7 +1140,8 @@ func (h *Handlers) HandleDecisionsRecent(w http.ResponseWriter, r *http.Request)
 	limit := queryLimit(r, 10)
 	offset := queryOffset(r)
 
-	filters := model.QueryFilters{}
+	ns := NamespaceFromContext(r.Context())
+	filters := model.QueryFilters{Namespace: &ns}
 	if agentID := r.URL.Query().Get("agent_id"); agentID != "" {
 		filters.AgentIDs = []string{agentID}
 	}
@@ -672,7 +1149,12 @@ func (h *Handlers) HandleDecisionsRecent(w http.ResponseWriter, r *http.Request)
 		filters.DecisionType = &dt
 	}
 
-	decisions, total, err := h.decisionSvc.Recent(r.Context(), orgID, filters, limit, offset)
+	// Optional include=flags,evidence (comma-separated). Alternatives are
+	// always included; unknown values are ignored.
+	requested, explicit := parseIncludeParam(r)
+	include := h.resolveIncludes(r, orgID, model.IncludeEndpointDecisionsRecent, requested, explicit)
+
+	decisions, total, err := h.decisionSvc.Recent(r.Context(), orgID, filters, limit, offset, include...)
 	if err != nil {
 		h.writeInternalError(w, r, "query failed", err)
 		return
@@ -685,6 +1167,7 @@ func (h *Handlers) HandleDecisionsRecent(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	h.recordAccess(r, orgID, "decision", decisionIDs(decisions), nil)
 	ptotal, hasMore := computePagination(len(decisions), preFilterCount, limit, offset, total)
 	writeListJSON(w, r, decisions, ptotal, hasMore, limit, offset)
 }
@@ -706,6 +1189,14 @@ func (h *Handlers) HandleDecisionRevisions(w http.ResponseWriter, r *http.Reques
 		h.writeInternalError(w, r, "failed to get revisions", err)
 		return
 	}
+	for _, d := range revisions {
+		if d.ID == id && !inRequestNamespace(r, d) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+	}
+	ns := NamespaceFromContext(r.Context())
+	revisions = slices.DeleteFunc(revisions, func(d model.Decision) bool { return d.Namespace != ns })
 
 	revisions, err = filterDecisionsByAccess(r.Context(), h.db, claims, revisions, h.grantCache)
 	if err != nil {
@@ -713,6 +1204,7 @@ func (h *Handlers) HandleDecisionRevisions(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	h.recordAccess(r, orgID, "decision", decisionIDs(revisions), nil)
 	writeJSON(w, r, http.StatusOK, model.DecisionRevisionsResponse{
 		DecisionID: id,
 		Revisions:  revisions,
@@ -760,9 +1252,12 @@ func (h *Handlers) HandleVerifyDecision(w http.ResponseWriter, r *http.Request)
 			resp.Verified = &verified
 			resp.Message = "this decision was created before content hashing was enabled"
 		} else {
-			valid := integrity.VerifyContentHash(d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+			valid := integrity.VerifyContentHashVersion(d.HashVersion, d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
 			resp.Verified = &valid
 			resp.ContentHash = d.ContentHash
+			if !valid {
+				resp.HashDiagnosis = diagnoseContentHash(d)
+			}
 		}
 	case d.ContentHash == "":
 		resp.Status = "no_hash"
@@ -771,23 +1266,27 @@ func (h *Handlers) HandleVerifyDecision(w http.ResponseWriter, r *http.Request)
 		erasure, erasureErr := h.db.GetDecisionErasure(r.Context(), orgID, id)
 		switch {
 		case erasureErr == nil:
-			valid := integrity.VerifyContentHash(d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+			valid := integrity.VerifyContentHashVersion(d.HashVersion, d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
 			resp.Status = "erased"
 			resp.Valid = &valid
 			resp.ContentHash = d.ContentHash
 			resp.OriginalHash = erasure.OriginalHash
 			resp.ErasedAt = &erasure.ErasedAt
 			resp.ErasedBy = erasure.ErasedBy
+			if !valid {
+				resp.HashDiagnosis = diagnoseContentHash(d)
+			}
 		case !isNotFoundError(erasureErr):
 			h.writeInternalError(w, r, "failed to check erasure status", erasureErr)
 			return
 		default:
-			valid := integrity.VerifyContentHash(d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+			valid := integrity.VerifyContentHashVersion(d.HashVersion, d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
 			resp.Valid = &valid
 			if valid {
 				resp.Status = "verified"
 			} else {
 				resp.Status = "tampered"
+				resp.HashDiagnosis = diagnoseContentHash(d)
 			}
 			resp.ContentHash = d.ContentHash
 		}
@@ -796,6 +1295,111 @@ func (h *Handlers) HandleVerifyDecision(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, r, http.StatusOK, resp)
 }
 
+// diagnoseContentHash explains why d's content hash does not verify. Besides
+// the built-in checks, it tries the decision's other timestamps as valid_from,
+// since a backfill or migration that rewrote valid_from is the most common
+// non-malicious cause of drift.
+func diagnoseContentHash(d model.Decision) integrity.HashDiagnosis {
+	fields := integrity.ContentFields{
+		ID:           d.ID,
+		DecisionType: d.DecisionType,
+		Outcome:      d.Outcome,
+		Confidence:   d.Confidence,
+		Reasoning:    d.Reasoning,
+		ValidFrom:    d.ValidFrom,
+	}
+	var candidates []integrity.FieldCandidate
+	for i, ts := range []time.Time{d.TransactionTime, d.CreatedAt} {
+		if ts.IsZero() || (i == 1 && ts.Equal(d.TransactionTime)) {
+			continue
+		}
+		candidates = append(candidates, integrity.FieldCandidate{
+			Field: "valid_from",
+			Value: ts.UTC().Format(time.RFC3339Nano),
+			Apply: func(c *integrity.ContentFields) bool {
+				if c.ValidFrom.Equal(ts) {
+					return false
+				}
+				c.ValidFrom = ts
+				return true
+			},
+		})
+	}
+	return integrity.DiagnoseContentHash(d.ContentHash, fields, candidates...)
+}
+
+// minContentHashPrefix is the shortest accepted hash prefix. Shorter prefixes
+// match too much of the org to be a useful lookup.
+const minContentHashPrefix = 8
+
+// HandleDecisionsByHash handles GET /v1/decisions/by-hash.
+// Looks up decisions by content hash: ?hash= for an exact match on a full
+// SHA-256 digest, or ?prefix= for a partial one. Either may carry the "v2:"
+// version prefix. Revised and retracted decisions are included, since an
+// external proof may reference any version.
+func (h *Handlers) HandleDecisionsByHash(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	hash, prefix := q.Get("hash"), q.Get("prefix")
+	if (hash == "") == (prefix == "") {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "exactly one of hash or prefix is required")
+		return
+	}
+	isPrefix := prefix != ""
+	digest := integrity.HashDigest(hash)
+	if isPrefix {
+		digest = integrity.HashDigest(prefix)
+	}
+	if !isHexString(digest) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "content hash must be hexadecimal")
+		return
+	}
+	switch {
+	case !isPrefix && len(digest) != 64:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "hash must be a 64-character SHA-256 digest")
+		return
+	case isPrefix && (len(digest) < minContentHashPrefix || len(digest) > 64):
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("prefix must be %d to 64 hex characters", minContentHashPrefix))
+		return
+	}
+
+	limit := queryLimit(r, 50)
+	offset := queryOffset(r)
+
+	decs, total, err := h.db.FindDecisionsByContentHash(r.Context(), orgID, NamespaceFromContext(r.Context()), digest, isPrefix, limit, offset)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to look up decisions by hash", err)
+		return
+	}
+
+	preFilterCount := len(decs)
+	decs, err = filterDecisionsByAccess(r.Context(), h.db, claims, decs, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	h.recordAccess(r, orgID, "decision", decisionIDs(decs), nil)
+
+	ptotal, hasMore := computePagination(len(decs), preFilterCount, limit, offset, total)
+	writeListJSON(w, r, decs, ptotal, hasMore, limit, offset)
+}
+
+// isHexString reports whether s is non-empty and contains only lowercase hex digits.
+func isHexString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // HandleListIntegrityViolations handles GET /v1/integrity/violations.
 // Returns recent integrity violations for the caller's organization, ordered
 // newest-first. This exposes the durable audit trail written by the background
@@ -850,7 +1454,105 @@ func (h *Handlers) HandleTraceHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, r, http.StatusOK, metrics)
+	writeJSON(w, r, http.StatusOK, metrics)
+}
+
+// HandleDuplicatesReport handles GET /v1/stats/duplicates.
+// Returns clusters of near-duplicate current decisions from the org's most
+// recent duplicate scan. Optional query params: threshold (defaults to the
+// scan floor; must be between the floor and 1) and limit (clusters returned).
+func (h *Handlers) HandleDuplicatesReport(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	scan, err := h.db.GetDuplicateScan(r.Context(), orgID)
+	if err != nil {
+		if !isNotFoundError(err) {
+			h.writeInternalError(w, r, "failed to get duplicate scan", err)
+			return
+		}
+		writeJSON(w, r, http.StatusOK, model.DuplicatesReport{Clusters: []model.DuplicateCluster{}})
+		return
+	}
+
+	threshold := scan.Floor
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		threshold, err = strconv.ParseFloat(v, 64)
+		if err != nil || threshold < scan.Floor || threshold > 1 {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				fmt.Sprintf("threshold must be a number between the scan floor (%g) and 1", scan.Floor))
+			return
+		}
+	}
+	limit := queryLimit(r, 50)
+
+	pairs, err := h.db.ListDuplicatePairs(r.Context(), orgID, threshold)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list duplicate pairs", err)
+		return
+	}
+	groups := duplicates.Cluster(pairs)
+
+	report := model.DuplicatesReport{
+		Threshold:        threshold,
+		Floor:            scan.Floor,
+		ScannedAt:        &scan.ScannedAt,
+		DecisionsScanned: scan.DecisionsScanned,
+		TotalClusters:    len(groups),
+		Clusters:         make([]model.DuplicateCluster, 0, min(limit, len(groups))),
+	}
+	if len(groups) > limit {
+		groups = groups[:limit]
+	}
+
+	var ids []uuid.UUID
+	for _, g := range groups {
+		ids = append(ids, g.IDs...)
+	}
+	decs, err := h.db.GetDecisionsByIDs(r.Context(), orgID, ids)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to load duplicate decisions", err)
+		return
+	}
+
+	for _, g := range groups {
+		cluster := model.DuplicateCluster{
+			MaxSimilarity: g.MaxSimilarity,
+			MinSimilarity: g.MinSimilarity,
+			Members:       make([]model.DuplicateMember, 0, len(g.IDs)),
+		}
+		agents := make(map[string]struct{})
+		for _, id := range g.IDs {
+			d, ok := decs[id]
+			if !ok {
+				continue
+			}
+			m := model.DuplicateMember{
+				DecisionID:   d.ID,
+				AgentID:      d.AgentID,
+				DecisionType: d.DecisionType,
+				Outcome:      d.Outcome,
+				ValidFrom:    d.ValidFrom,
+			}
+			if id == g.Representative {
+				cluster.Representative = m
+			}
+			cluster.Members = append(cluster.Members, m)
+			agents[d.AgentID] = struct{}{}
+		}
+		// A member revised between listing and loading leaves a pair behind;
+		// skip clusters that no longer have a pair's worth of members.
+		if len(cluster.Members) < 2 {
+			continue
+		}
+		if cluster.Representative.DecisionID == uuid.Nil {
+			cluster.Representative = cluster.Members[0]
+		}
+		cluster.Size = len(cluster.Members)
+		cluster.AgentCount = len(agents)
+		report.Clusters = append(report.Clusters, cluster)
+	}
+
+	writeJSON(w, r, http.StatusOK, report)
 }
 
 // HandleSessionView handles GET /v1/sessions/{session_id}.
@@ -886,7 +1588,59 @@ func (h *Handlers) HandleSessionView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Compute summary: use min/max of valid_from to avoid ordering edge cases
+	writeJSON(w, r, http.StatusOK, model.SessionViewResponse{
+		SessionID:     sid,
+		Decisions:     decs,
+		DecisionCount: len(decs),
+		Summary:       summarizeDecisions(decs),
+	})
+}
+
+// HandleBatchView handles GET /v1/decisions/batches/{batch_id}.
+// Returns all decisions grouped into one burst, with summary statistics.
+func (h *Handlers) HandleBatchView(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	bid, err := parsePathUUID(r, "batch_id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid batch_id")
+		return
+	}
+
+	decs, err := h.db.GetBatchDecisions(r.Context(), orgID, bid)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get batch decisions", err)
+		return
+	}
+
+	decs, err = filterDecisionsByAccess(r.Context(), h.db, claims, decs, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+
+	if len(decs) == 0 {
+		writeJSON(w, r, http.StatusOK, model.BatchViewResponse{
+			BatchID:       bid,
+			Decisions:     []model.Decision{},
+			DecisionCount: 0,
+		})
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, model.BatchViewResponse{
+		BatchID:       bid,
+		Decisions:     decs,
+		DecisionCount: len(decs),
+		Summary:       summarizeDecisions(decs),
+	})
+}
+
+// summarizeDecisions computes timeline statistics for a non-empty set of
+// decisions, shared by the session and batch views.
+func summarizeDecisions(decs []model.Decision) *model.SessionViewSummary {
+	// Use min/max of valid_from to avoid ordering edge cases
 	// (multiple decisions can share the same valid_from in revision chains).
 	startedAt := decs[0].ValidFrom
 	endedAt := decs[0].ValidFrom
@@ -911,18 +1665,13 @@ func (h *Handlers) HandleSessionView(w http.ResponseWriter, r *http.Request) {
 	}
 	avgConfidence := totalConf / float64(len(decs))
 
-	writeJSON(w, r, http.StatusOK, model.SessionViewResponse{
-		SessionID:     sid,
-		Decisions:     decs,
-		DecisionCount: len(decs),
-		Summary: &model.SessionViewSummary{
-			StartedAt:     startedAt,
-			EndedAt:       endedAt,
-			DurationSecs:  duration,
-			DecisionTypes: decisionTypes,
-			AvgConfidence: avgConfidence,
-		},
-	})
+	return &model.SessionViewSummary{
+		StartedAt:     startedAt,
+		EndedAt:       endedAt,
+		DurationSecs:  duration,
+		DecisionTypes: decisionTypes,
+		AvgConfidence: avgConfidence,
+	}
 }
 
 // HandleRetractDecision handles DELETE /v1/decisions/{id}.
@@ -1122,6 +1871,86 @@ func (h *Handlers) HandleDecisionTimeline(w http.ResponseWriter, r *http.Request
 	})
 }
 
+// HandleConfidenceHistogram handles GET /v1/agents/{agent_id}/confidence-histogram.
+// Returns the distribution of the agent's current decisions by confidence in
+// ?buckets= equal-width buckets (default 10, max 100).
+func (h *Handlers) HandleConfidenceHistogram(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	agentID := r.PathValue("agent_id")
+	if err := model.ValidateAgentID(agentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	ok, err := canAccessAgent(r.Context(), h.db, claims, agentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this agent's history")
+		return
+	}
+
+	buckets := queryInt(r, "buckets", model.DefaultConfidenceHistogramBuckets)
+	if buckets < 1 || buckets > model.MaxConfidenceHistogramBuckets {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("buckets must be between 1 and %d", model.MaxConfidenceHistogramBuckets))
+		return
+	}
+
+	histogram, err := h.db.GetConfidenceHistogram(r.Context(), orgID, agentID, buckets)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get confidence histogram", err)
+		return
+	}
+	total := 0
+	for _, b := range histogram {
+		total += b.Count
+	}
+
+	writeJSON(w, r, http.StatusOK, model.ConfidenceHistogramResponse{
+		AgentID: agentID,
+		Total:   total,
+		Buckets: histogram,
+	})
+}
+
+// HandleListDecisionTypes handles GET /v1/decision-types. It lists each
+// decision type in use with its count and most recent decision. Non-admin
+// callers only see types from agents they can read. Results are cached
+// briefly, so a new type may take up to decisionTypesTTL to appear.
+func (h *Handlers) HandleListDecisionTypes(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	granted, err := authz.LoadGrantedSet(r.Context(), h.db, claims, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	key := decisionTypesKey{orgID: orgID, agents: "*"}
+	var agentIDs []string
+	if granted != nil {
+		agentIDs = make([]string, 0, len(granted))
+		for id, ok := range granted {
+			if ok {
+				agentIDs = append(agentIDs, id)
+			}
+		}
+		slices.Sort(agentIDs)
+		key.agents = strings.Join(agentIDs, "\n")
+	}
+
+	types, err := h.decisionTypes.get(r.Context(), key, agentIDs)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list decision types", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, types)
+}
+
 // HandleDecisionFacets returns distinct decision types and projects for filter dropdowns.
 func (h *Handlers) HandleDecisionFacets(w http.ResponseWriter, r *http.Request) {
 	orgID := OrgIDFromContext(r.Context())
@@ -1193,6 +2022,100 @@ func (h *Handlers) HandleGetDecisionLineage(w http.ResponseWriter, r *http.Reque
 	writeJSON(w, r, http.StatusOK, lineage)
 }
 
+// HandleDecisionCitedBy handles GET /v1/decisions/{id}/cited-by (reader+).
+// Lists the active decisions that cite this one as their precedent, newest
+// first. Citing decisions from agents the caller cannot access are omitted.
+func (h *Handlers) HandleDecisionCitedBy(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	id, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision ID")
+		return
+	}
+
+	d, err := h.db.GetDecision(r.Context(), orgID, id, storage.GetDecisionOpts{})
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision", err)
+		return
+	}
+	if !inRequestNamespace(r, d) {
+		writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+		return
+	}
+	ok, err := canAccessAgent(r.Context(), h.db, claims, d.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this decision")
+		return
+	}
+
+	limit := queryLimit(r, 50)
+	if limit > 200 {
+		limit = 200
+	}
+
+	// Fetch one extra row to report has_more without a separate count.
+	citing, err := h.db.FindDecisionsCitingPrecedent(r.Context(), orgID, d.Namespace, id, limit+1)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to find citing decisions", err)
+		return
+	}
+	hasMore := len(citing) > limit
+	if hasMore {
+		citing = citing[:limit]
+	}
+
+	citing, err = filterDecisionsByAccess(r.Context(), h.db, claims, citing, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+
+	h.recordAccess(r, orgID, "decision", decisionIDs(citing), nil)
+	writeListJSON(w, r, citing, nil, hasMore, limit, 0)
+}
+
+// HandleGetDecisionAncestryHealth handles GET /v1/decisions/{id}/ancestry-health (reader+).
+// Walks the precedent chain and reports which cited ancestors have since been
+// superseded, expired, or contested, with a rolled-up stale_ancestry flag.
+func (h *Handlers) HandleGetDecisionAncestryHealth(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	id, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision ID")
+		return
+	}
+
+	health, err := h.db.GetDecisionAncestry(r.Context(), id, orgID)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision ancestry", err)
+		return
+	}
+
+	health, err = filterAncestryByAccess(r.Context(), h.db, claims, health, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, health)
+}
+
 // HandlePatchDecision handles PATCH /v1/decisions/{id} (admin-only).
 // Currently supports updating the project field on a decision.
 func (h *Handlers) HandlePatchDecision(w http.ResponseWriter, r *http.Request) {
@@ -1248,3 +2171,143 @@ func (h *Handlers) HandlePatchDecision(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, r, http.StatusOK, decision)
 }
+
+// HandleUpdateDecisionMetadata handles PATCH /v1/decisions/{id}/metadata
+// (admin-only). It merges fields into the decision's metadata in place, for
+// corrections (a tool version, a ticket link) that do not warrant a revision.
+func (h *Handlers) HandleUpdateDecisionMetadata(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	id, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision id")
+		return
+	}
+
+	var req model.UpdateDecisionMetadataRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if len(req.Metadata) == 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "metadata must contain at least one key")
+		return
+	}
+	if err := model.ValidateMetadataSize("metadata", req.Metadata); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	claims := ClaimsFromContext(r.Context())
+	audit := h.buildAuditEntry(r, orgID,
+		"decision_metadata_updated", "decision", id.String(),
+		nil, nil,
+		map[string]any{"updated_by": claims.ActorID(), "patch": req.Metadata},
+	)
+
+	merged, err := h.db.UpdateDecisionMetadata(r.Context(), orgID, id, req.Metadata, audit)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		if errors.Is(err, storage.ErrDecisionSuperseded) {
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+				"decision has been superseded; update the current revision instead")
+			return
+		}
+		h.writeInternalError(w, r, "failed to update decision metadata", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, model.DecisionMetadataResponse{DecisionID: id, Metadata: merged})
+}
+
+// inRequestNamespace reports whether d belongs to the request's namespace.
+// Reads by decision ID answer 404 for decisions in other namespaces, as if
+// they did not exist, matching list and search, which never return them.
+func inRequestNamespace(r *http.Request, d model.Decision) bool {
+	return d.Namespace == NamespaceFromContext(r.Context())
+}
+
+// supersedeMatchKeys returns the keys of an optional supersede_matching clause.
+func supersedeMatchKeys(m *model.SupersedeMatching) []string {
+	if m == nil {
+		return nil
+	}
+	return m.Keys
+}
+
+// HandleUpdateDecisionTags handles PATCH /v1/decisions/{id}/tags. It adds and
+// removes decision-level tags, which are independent of the agent's tags.
+// The caller must be able to read the decision's agent.
+func (h *Handlers) HandleUpdateDecisionTags(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	decisionID, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision ID")
+		return
+	}
+
+	var req model.UpdateDecisionTagsRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "add or remove is required")
+		return
+	}
+	adding := make(map[string]struct{}, len(req.Add))
+	for _, tag := range req.Add {
+		if err := model.ValidateTag(tag); err != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+			return
+		}
+		adding[tag] = struct{}{}
+	}
+	for _, tag := range req.Remove {
+		if _, ok := adding[tag]; ok {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				fmt.Sprintf("tag %q is in both add and remove", tag))
+			return
+		}
+	}
+
+	d, err := h.db.GetDecision(r.Context(), orgID, decisionID, storage.GetDecisionOpts{CurrentOnly: true})
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision", err)
+		return
+	}
+	ok, err := canAccessAgent(r.Context(), h.db, claims, d.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this decision")
+		return
+	}
+
+	if err := h.db.AddDecisionTags(r.Context(), orgID, decisionID, req.Add); err != nil {
+		h.writeInternalError(w, r, "failed to add decision tags", err)
+		return
+	}
+	if err := h.db.RemoveDecisionTags(r.Context(), orgID, decisionID, req.Remove); err != nil {
+		h.writeInternalError(w, r, "failed to remove decision tags", err)
+		return
+	}
+	tags, err := h.db.ListDecisionTags(r.Context(), orgID, decisionID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list decision tags", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, model.DecisionTagsResponse{DecisionID: decisionID, Tags: tags})
+}
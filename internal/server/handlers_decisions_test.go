@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+func TestDiffTemporalSnapshots(t *testing.T) {
+	dec := func(outcome string) model.Decision {
+		return model.Decision{ID: uuid.New(), DecisionType: "architecture", Outcome: outcome}
+	}
+	unchanged := dec("keep")
+	revised := dec("use redis")
+	revision := dec("use memcached")
+	restated := dec("use kafka")
+	restatement := dec("use kafka")
+	retracted := dec("drop")
+	added := dec("new")
+
+	chains := map[uuid.UUID][]uuid.UUID{
+		revision.ID:    {revised.ID},
+		restatement.ID: {restated.ID},
+		added.ID:       nil,
+	}
+	var looked []uuid.UUID
+	chainIDs := func(id uuid.UUID) ([]uuid.UUID, error) {
+		looked = append(looked, id)
+		return chains[id], nil
+	}
+
+	before := []model.Decision{unchanged, revised, restated, retracted}
+	after := []model.Decision{added, restatement, revision, unchanged}
+	resp, err := diffTemporalSnapshots(before, after, chainIDs)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Added, 1)
+	assert.Equal(t, added.ID, resp.Added[0].ID)
+	require.Len(t, resp.Superseded, 1)
+	assert.Equal(t, retracted.ID, resp.Superseded[0].ID)
+	require.Len(t, resp.Changed, 2)
+	assert.Equal(t, restated.ID, resp.Changed[0].Before.ID)
+	assert.Equal(t, restatement.ID, resp.Changed[0].After.ID)
+	assert.False(t, resp.Changed[0].OutcomeChanged)
+	assert.Equal(t, revised.ID, resp.Changed[1].Before.ID)
+	assert.Equal(t, revision.ID, resp.Changed[1].After.ID)
+	assert.True(t, resp.Changed[1].OutcomeChanged)
+
+	assert.NotContains(t, looked, unchanged.ID, "decisions valid at both points need no chain lookup")
+}
+
+func TestDiffTemporalSnapshots_Empty(t *testing.T) {
+	resp, err := diffTemporalSnapshots(nil, nil, func(uuid.UUID) ([]uuid.UUID, error) {
+		t.Fatal("chainIDs should not be called")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Added)
+	assert.NotNil(t, resp.Superseded)
+	assert.NotNil(t, resp.Changed)
+}
+
+func TestDiffTemporalSnapshots_ChainError(t *testing.T) {
+	_, err := diffTemporalSnapshots(nil, []model.Decision{{ID: uuid.New()}}, func(uuid.UUID) ([]uuid.UUID, error) {
+		return nil, errors.New("boom")
+	})
+	require.Error(t, err)
+}
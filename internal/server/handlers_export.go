@@ -1,24 +1,113 @@
 package server
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+
 	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/storage"
 )
 
+// Export modes accepted by GET /v1/export/decisions?export_mode=.
+const (
+	// exportModeHeads emits only the current head of each revision chain.
+	exportModeHeads = "heads"
+	// exportModeFullHistory emits each head with its prior revisions nested.
+	exportModeFullHistory = "full_history"
+)
+
+// Trailers announced on every export response and set once streaming ends.
+// Clients compare the count against the lines they received to detect a
+// truncated download that otherwise looks like a clean EOF.
+const (
+	exportCountTrailer    = "X-Akashi-Exported-Count"
+	exportCompleteTrailer = "X-Akashi-Export-Complete"
+)
+
+// Formats accepted by GET /v1/export/conflicts?format= and, with parquet,
+// GET /v1/export/decisions?format=.
+const (
+	exportFormatNDJSON  = "ndjson"
+	exportFormatCSV     = "csv"
+	exportFormatParquet = "parquet"
+)
+
+// parquetContentType is the media type registered for Apache Parquet.
+const parquetContentType = "application/vnd.apache.parquet"
+
+// conflictCSVHeader names the columns of a CSV conflict export, in the order
+// written by conflictCSVRow.
+var conflictCSVHeader = []string{
+	"id", "conflict_kind", "status", "detected_at", "decision_type", "severity", "category",
+	"significance", "outcome_divergence", "topic_similarity",
+	"decision_a_id", "agent_a", "outcome_a", "decision_b_id", "agent_b", "outcome_b",
+	"winning_decision_id", "resolved_by", "resolved_at", "resolution_note",
+}
+
+// exportHistoryRecord is one NDJSON line in full_history mode: the current
+// decision plus its superseded revisions, oldest first.
+type exportHistoryRecord struct {
+	model.Decision
+	Revisions []model.Decision `json:"revisions"`
+}
+
 // HandleExportDecisions handles GET /v1/export/decisions (admin-only).
 // Streams decisions as NDJSON (one JSON object per line), including
 // alternatives and evidence for each decision. Uses cursor-based
 // pagination to avoid loading all results into memory.
+//
+// format=csv or format=parquet (or an Accept header naming text/csv or
+// application/vnd.apache.parquet) instead flattens each decision into the
+// scalar columns of decisionExportRow. Parquet writes one row group per
+// page, so memory stays bounded by the page size in every format.
+//
+// export_mode=heads (default) emits only current decisions. export_mode=
+// full_history additionally nests each decision's prior revisions inline,
+// which only NDJSON can represent.
+//
+// The X-Akashi-Exported-Count and X-Akashi-Export-Complete trailers are
+// written after the last row; Export-Complete is "false" when the stream
+// ended early.
 func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request) {
 	orgID := OrgIDFromContext(r.Context())
 	q := r.URL.Query()
 
-	filters := model.QueryFilters{}
+	mode := q.Get("export_mode")
+	switch mode {
+	case "":
+		mode = exportModeHeads
+	case exportModeHeads, exportModeFullHistory:
+	default:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "export_mode must be 'heads' or 'full_history'")
+		return
+	}
+
+	format := q.Get("format")
+	switch format {
+	case "":
+		format = negotiateExportFormat(r.Header.Get("Accept"))
+	case exportFormatNDJSON, exportFormatCSV, exportFormatParquet:
+	default:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "format must be 'ndjson', 'csv', or 'parquet'")
+		return
+	}
+	if format != exportFormatNDJSON && mode == exportModeFullHistory {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "export_mode=full_history is only available as ndjson")
+		return
+	}
+
+	ns := NamespaceFromContext(r.Context())
+	filters := model.QueryFilters{Namespace: &ns}
 	if agentID := q.Get("agent_id"); agentID != "" {
 		filters.AgentIDs = []string{agentID}
 	}
@@ -42,11 +131,23 @@ func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Filename with timestamp.
-	filename := fmt.Sprintf("akashi-export-%s.ndjson", time.Now().UTC().Format("20060102-150405"))
+	filename := fmt.Sprintf("akashi-export-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
 
-	w.Header().Set("Content-Type", "application/x-ndjson")
+	var out decisionExportWriter
+	switch format {
+	case exportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		out = newCSVDecisionExport(w)
+	case exportFormatParquet:
+		w.Header().Set("Content-Type", parquetContentType)
+		out = newParquetDecisionExport(w)
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		out = &ndjsonDecisionExport{encoder: json.NewEncoder(w), fullHistory: mode == exportModeFullHistory}
+	}
 	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
 	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Trailer", exportCountTrailer+", "+exportCompleteTrailer)
 
 	// Stream in pages using keyset (cursor-based) pagination to avoid O(offset)
 	// degradation. Each page uses (valid_from, id) > (last_seen) instead of OFFSET,
@@ -57,15 +158,30 @@ func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request)
 	// smaller pages suit memory-constrained deployments. Bounds (1–10000) are
 	// enforced at config load time.
 	pageSize := h.exportPageSize
-	encoder := json.NewEncoder(w)
 	flusher, _ := w.(http.Flusher)
 	var cursor *storage.ExportCursor
+	exported := 0
+	complete := false
+	defer func() {
+		// Trailer values must be set on the header map after the body has
+		// been written; net/http sends them once the handler returns.
+		if w.Header().Get("Trailer") == "" {
+			return
+		}
+		w.Header().Set(exportCountTrailer, strconv.Itoa(exported))
+		w.Header().Set(exportCompleteTrailer, strconv.FormatBool(complete))
+	}()
 
 	for {
 		decisions, err := h.db.ExportDecisionsCursor(r.Context(), orgID, filters, cursor, pageSize)
+		var history map[uuid.UUID][]model.Decision
+		if err == nil && mode == exportModeFullHistory {
+			history, err = h.loadExportHistory(r, orgID, decisions)
+		}
 		if err != nil {
 			if cursor == nil {
 				// Headers not yet sent — we can still return a proper error response.
+				w.Header().Del("Trailer")
 				h.writeInternalError(w, r, "export failed", err)
 			} else {
 				h.logger.Error("export failed mid-stream",
@@ -73,14 +189,7 @@ func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request)
 					"method", r.Method,
 					"path", r.URL.Path,
 					"request_id", RequestIDFromContext(r.Context()))
-				// Headers already sent — write an error sentinel as the last NDJSON line
-				// so consumers can detect the truncation instead of silently accepting
-				// a partial export as complete.
-				_ = encoder.Encode(map[string]any{
-					"__error":  true,
-					"message":  "export terminated due to internal error",
-					"exported": cursor != nil,
-				})
+				out.abort(exported)
 				if flusher != nil {
 					flusher.Flush()
 				}
@@ -89,9 +198,13 @@ func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request)
 		}
 
 		for _, d := range decisions {
-			if err := encoder.Encode(d); err != nil {
+			if err := out.write(d, history[d.ID]); err != nil {
 				return // Client disconnected.
 			}
+			exported++
+		}
+		if err := out.endPage(); err != nil {
+			return // Client disconnected.
 		}
 
 		if flusher != nil {
@@ -106,4 +219,445 @@ func (h *Handlers) HandleExportDecisions(w http.ResponseWriter, r *http.Request)
 		last := decisions[len(decisions)-1]
 		cursor = &storage.ExportCursor{ValidFrom: last.ValidFrom, ID: last.ID}
 	}
+	if err := out.finish(); err != nil {
+		return // Client disconnected.
+	}
+	complete = true
+}
+
+// negotiateExportFormat picks the decision export format from an Accept
+// header when no format parameter is given. NDJSON is the default.
+func negotiateExportFormat(accept string) string {
+	switch {
+	case strings.Contains(accept, parquetContentType):
+		return exportFormatParquet
+	case strings.Contains(accept, "text/csv"):
+		return exportFormatCSV
+	default:
+		return exportFormatNDJSON
+	}
+}
+
+// decisionExportWriter encodes a decision export stream in one format.
+type decisionExportWriter interface {
+	// write emits one exported decision; history is its revision chain in
+	// full_history mode.
+	write(d model.Decision, history []model.Decision) error
+	// endPage pushes everything written for the current page to the client.
+	endPage() error
+	// abort marks a stream that failed after the first page, where the
+	// format allows it.
+	abort(exported int)
+	// finish completes the stream after the last page.
+	finish() error
+}
+
+// ndjsonDecisionExport writes one JSON object per line.
+type ndjsonDecisionExport struct {
+	encoder     *json.Encoder
+	fullHistory bool
+}
+
+func (e *ndjsonDecisionExport) write(d model.Decision, history []model.Decision) error {
+	if e.fullHistory {
+		return e.encoder.Encode(exportHistoryRecord{Decision: d, Revisions: priorRevisions(d.ID, history)})
+	}
+	return e.encoder.Encode(d)
+}
+
+func (e *ndjsonDecisionExport) endPage() error { return nil }
+
+// abort writes an error sentinel as the last NDJSON line so consumers can
+// detect the truncation instead of silently accepting a partial export as
+// complete.
+func (e *ndjsonDecisionExport) abort(exported int) {
+	_ = e.encoder.Encode(map[string]any{
+		"__error":  true,
+		"message":  "export terminated due to internal error",
+		"exported": exported,
+	})
+}
+
+func (e *ndjsonDecisionExport) finish() error { return nil }
+
+// csvDecisionExport writes a header row and then one row per decision. A
+// failed CSV stream is only detectable via the trailers.
+type csvDecisionExport struct {
+	w *csv.Writer
+}
+
+func newCSVDecisionExport(w http.ResponseWriter) *csvDecisionExport {
+	e := &csvDecisionExport{w: csv.NewWriter(w)}
+	// Buffered until the first page is flushed, so an error on the first
+	// page can still replace the body with a JSON error response.
+	_ = e.w.Write(decisionExportHeader)
+	return e
+}
+
+func (e *csvDecisionExport) write(d model.Decision, _ []model.Decision) error {
+	row := newDecisionExportRow(&d)
+	return e.w.Write(row.csvRecord())
+}
+
+func (e *csvDecisionExport) endPage() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvDecisionExport) abort(int) { e.w.Flush() }
+
+func (e *csvDecisionExport) finish() error { return e.endPage() }
+
+// parquetDecisionExport writes one row group per page. The writer emits
+// nothing until the first row group is flushed, so nothing reaches the
+// client before the first query succeeds, and an aborted stream never gets
+// its footer, which makes it unreadable rather than silently short.
+type parquetDecisionExport struct {
+	pw   *parquet.GenericWriter[decisionExportRow]
+	rows []decisionExportRow
+}
+
+func newParquetDecisionExport(w io.Writer) *parquetDecisionExport {
+	return &parquetDecisionExport{pw: parquet.NewGenericWriter[decisionExportRow](w)}
+}
+
+func (e *parquetDecisionExport) write(d model.Decision, _ []model.Decision) error {
+	e.rows = append(e.rows, newDecisionExportRow(&d))
+	return nil
+}
+
+func (e *parquetDecisionExport) endPage() error {
+	if len(e.rows) == 0 {
+		return nil
+	}
+	_, err := e.pw.Write(e.rows)
+	e.rows = e.rows[:0]
+	if err != nil {
+		return err
+	}
+	return e.pw.Flush()
+}
+
+func (e *parquetDecisionExport) abort(int) {}
+
+func (e *parquetDecisionExport) finish() error {
+	if err := e.endPage(); err != nil {
+		return err
+	}
+	return e.pw.Close()
+}
+
+// decisionExportRow is one row of the flat CSV and Parquet decision exports.
+// Field order is the stable column order, and the parquet tags name the
+// columns in both formats; pointer fields are optional (empty in CSV).
+// Alternatives and evidence are reduced to counts and metadata is embedded
+// as JSON text.
+type decisionExportRow struct {
+	ID                string     `parquet:"id"`
+	RunID             string     `parquet:"run_id"`
+	AgentID           string     `parquet:"agent_id"`
+	Namespace         string     `parquet:"namespace"`
+	DecisionType      string     `parquet:"decision_type"`
+	Outcome           string     `parquet:"outcome"`
+	Confidence        float32    `parquet:"confidence"`
+	ConfidenceLow     *float32   `parquet:"confidence_low,optional"`
+	ConfidenceHigh    *float32   `parquet:"confidence_high,optional"`
+	Reasoning         *string    `parquet:"reasoning,optional"`
+	CompletenessScore float32    `parquet:"completeness_score"`
+	OutcomeScore      *float32   `parquet:"outcome_score,optional"`
+	PrecedentRef      *string    `parquet:"precedent_ref,optional"`
+	SupersedesID      *string    `parquet:"supersedes_id,optional"`
+	SessionID         *string    `parquet:"session_id,optional"`
+	Tool              *string    `parquet:"tool,optional"`
+	Model             *string    `parquet:"model,optional"`
+	Project           *string    `parquet:"project,optional"`
+	ContentHash       string     `parquet:"content_hash"`
+	ValidFrom         time.Time  `parquet:"valid_from,timestamp(microsecond)"`
+	ValidTo           *time.Time `parquet:"valid_to,optional,timestamp(microsecond)"`
+	TransactionTime   time.Time  `parquet:"transaction_time,timestamp(microsecond)"`
+	CreatedAt         time.Time  `parquet:"created_at,timestamp(microsecond)"`
+	AlternativeCount  int64      `parquet:"alternative_count"`
+	EvidenceCount     int64      `parquet:"evidence_count"`
+	Metadata          *string    `parquet:"metadata,optional"`
+}
+
+func newDecisionExportRow(d *model.Decision) decisionExportRow {
+	row := decisionExportRow{
+		ID:                d.ID.String(),
+		RunID:             d.RunID.String(),
+		AgentID:           d.AgentID,
+		Namespace:         d.Namespace,
+		DecisionType:      d.DecisionType,
+		Outcome:           d.Outcome,
+		Confidence:        d.Confidence,
+		ConfidenceLow:     d.ConfidenceLow,
+		ConfidenceHigh:    d.ConfidenceHigh,
+		Reasoning:         d.Reasoning,
+		CompletenessScore: d.CompletenessScore,
+		OutcomeScore:      d.OutcomeScore,
+		PrecedentRef:      optionalUUID(d.PrecedentRef),
+		SupersedesID:      optionalUUID(d.SupersedesID),
+		SessionID:         optionalUUID(d.SessionID),
+		Tool:              d.Tool,
+		Model:             d.Model,
+		Project:           d.Project,
+		ContentHash:       d.ContentHash,
+		ValidFrom:         d.ValidFrom,
+		ValidTo:           d.ValidTo,
+		TransactionTime:   d.TransactionTime,
+		CreatedAt:         d.CreatedAt,
+		AlternativeCount:  int64(len(d.Alternatives)),
+		EvidenceCount:     int64(len(d.Evidence)),
+	}
+	if len(d.Metadata) > 0 {
+		if b, err := json.Marshal(d.Metadata); err == nil {
+			m := string(b)
+			row.Metadata = &m
+		}
+	}
+	return row
+}
+
+func optionalUUID(p *uuid.UUID) *string {
+	if p == nil {
+		return nil
+	}
+	s := p.String()
+	return &s
+}
+
+// decisionExportHeader is the CSV header: the parquet column names of
+// decisionExportRow, in field order.
+var decisionExportHeader = func() []string {
+	t := reflect.TypeFor[decisionExportRow]()
+	header := make([]string, t.NumField())
+	for i := range header {
+		header[i], _, _ = strings.Cut(t.Field(i).Tag.Get("parquet"), ",")
+	}
+	return header
+}()
+
+// csvRecord renders the row as CSV cells in decisionExportHeader order.
+func (r *decisionExportRow) csvRecord() []string {
+	v := reflect.ValueOf(r).Elem()
+	record := make([]string, v.NumField())
+	for i := range record {
+		f := v.Field(i)
+		if f.Kind() == reflect.Pointer {
+			if f.IsNil() {
+				continue
+			}
+			f = f.Elem()
+		}
+		record[i] = csvCell(f.Interface())
+	}
+	return record
+}
+
+// csvCell renders a decisionExportRow field. Timestamps keep their
+// microsecond precision; absent values are empty cells.
+func csvCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// loadExportHistory fetches revision chains for the decisions in one export
+// page. Only decisions that supersede something can have history, so the rest
+// skip the chain walk entirely.
+func (h *Handlers) loadExportHistory(r *http.Request, orgID uuid.UUID, decisions []model.Decision) (map[uuid.UUID][]model.Decision, error) {
+	var ids []uuid.UUID
+	for _, d := range decisions {
+		if d.SupersedesID != nil {
+			ids = append(ids, d.ID)
+		}
+	}
+	return h.db.GetDecisionRevisionsBatch(r.Context(), orgID, ids)
+}
+
+// priorRevisions returns the chain minus the head itself, preserving the
+// chronological order from GetDecisionRevisions. Never nil, so the JSON
+// field is always an array.
+func priorRevisions(headID uuid.UUID, chain []model.Decision) []model.Decision {
+	prior := make([]model.Decision, 0, len(chain))
+	for _, rev := range chain {
+		if rev.ID != headID {
+			prior = append(prior, rev)
+		}
+	}
+	return prior
+}
+
+// HandleExportConflicts handles GET /v1/export/conflicts. Streams conflicts
+// detected in [from, to) oldest first, as NDJSON (default) or CSV with
+// format=csv, using keyset pagination on (detected_at, id). Accepts the same
+// filters as GET /v1/conflicts, and like it drops conflicts the caller has
+// no access to.
+//
+// The X-Akashi-Exported-Count and X-Akashi-Export-Complete trailers work as
+// for the decision export. A failed NDJSON stream also ends with an error
+// sentinel line; a failed CSV stream is only detectable via the trailers.
+func (h *Handlers) HandleExportConflicts(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	format := q.Get("format")
+	switch format {
+	case "":
+		format = exportFormatNDJSON
+	case exportFormatNDJSON, exportFormatCSV:
+	default:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "format must be 'ndjson' or 'csv'")
+		return
+	}
+
+	filters, err := parseConflictFilters(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if filters.DetectedFrom, err = queryTime(r, "from"); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if filters.DetectedTo, err = queryTime(r, "to"); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("akashi-conflicts-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Trailer", exportCountTrailer+", "+exportCompleteTrailer)
+
+	pageSize := h.exportPageSize
+	encoder := json.NewEncoder(w)
+	csvWriter := csv.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	var cursor *storage.ConflictExportCursor
+	exported := 0
+	complete := false
+	defer func() {
+		if w.Header().Get("Trailer") == "" {
+			return
+		}
+		w.Header().Set(exportCountTrailer, strconv.Itoa(exported))
+		w.Header().Set(exportCompleteTrailer, strconv.FormatBool(complete))
+	}()
+
+	if format == exportFormatCSV {
+		if err := csvWriter.Write(conflictCSVHeader); err != nil {
+			return // Client disconnected.
+		}
+	}
+
+	for {
+		page, err := h.db.ExportConflictsCursor(r.Context(), orgID, filters, cursor, pageSize)
+		var visible []model.DecisionConflict
+		if err == nil {
+			visible, err = filterConflictsByAccess(r.Context(), h.db, claims, page, h.grantCache)
+		}
+		if err != nil {
+			if cursor == nil {
+				// Nothing but (for CSV) the buffered header has been written.
+				w.Header().Del("Trailer")
+				h.writeInternalError(w, r, "conflict export failed", err)
+			} else {
+				h.logger.Error("conflict export failed mid-stream",
+					"error", err,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", RequestIDFromContext(r.Context()))
+				if format == exportFormatNDJSON {
+					_ = encoder.Encode(map[string]any{
+						"__error":  true,
+						"message":  "export terminated due to internal error",
+						"exported": exported,
+					})
+				}
+				csvWriter.Flush()
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return
+		}
+
+		for _, c := range visible {
+			if format == exportFormatCSV {
+				err = csvWriter.Write(conflictCSVRow(c))
+			} else {
+				err = encoder.Encode(c)
+			}
+			if err != nil {
+				return // Client disconnected.
+			}
+			exported++
+		}
+
+		csvWriter.Flush()
+		if csvWriter.Error() != nil {
+			return // Client disconnected.
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		if len(page) < pageSize {
+			break // Last page.
+		}
+
+		// Advance past the last row fetched, including rows filtered out by authz.
+		last := page[len(page)-1]
+		cursor = &storage.ConflictExportCursor{DetectedAt: last.DetectedAt, ID: last.ID}
+	}
+	complete = true
+}
+
+// conflictCSVRow flattens a conflict into the columns of conflictCSVHeader.
+// Absent optional values are empty cells.
+func conflictCSVRow(c model.DecisionConflict) []string {
+	str := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return *p
+	}
+	num := func(p *float64) string {
+		if p == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*p, 'f', -1, 64)
+	}
+	var winner, resolvedAt string
+	if c.WinningDecisionID != nil {
+		winner = c.WinningDecisionID.String()
+	}
+	if c.ResolvedAt != nil {
+		resolvedAt = c.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		c.ID.String(), string(c.ConflictKind), c.Status, c.DetectedAt.UTC().Format(time.RFC3339), c.DecisionType,
+		str(c.Severity), str(c.Category),
+		num(c.Significance), num(c.OutcomeDivergence), num(c.TopicSimilarity),
+		c.DecisionAID.String(), c.AgentA, c.OutcomeA, c.DecisionBID.String(), c.AgentB, c.OutcomeB,
+		winner, str(c.ResolvedBy), resolvedAt, str(c.ResolutionNote),
+	}
 }
@@ -1,6 +1,19 @@
 package server
 
-import "testing"
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
 
 // TestExportPageSizeOrDefault documents the fallback semantics for Handlers
 // constructed with an unset ExportPageSize. Config.Validate enforces the
@@ -46,3 +59,202 @@ func TestNewHandlers_ExportPageSizePropagates(t *testing.T) {
 		}
 	})
 }
+
+// TestConflictCSVRow checks that rows line up with the header and that
+// absent optional fields become empty cells.
+func TestConflictCSVRow(t *testing.T) {
+	sig := 0.75
+	note := "kept A, see ADR-7"
+	c := model.DecisionConflict{
+		ID:             uuid.New(),
+		ConflictKind:   model.ConflictKindCrossAgent,
+		Status:         "resolved",
+		DetectedAt:     time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC),
+		DecisionType:   "architecture",
+		Significance:   &sig,
+		AgentA:         "planner",
+		OutcomeA:       "use Redis, not Memcached",
+		ResolutionNote: &note,
+	}
+	row := conflictCSVRow(c)
+	if len(row) != len(conflictCSVHeader) {
+		t.Fatalf("row has %d cells, header has %d", len(row), len(conflictCSVHeader))
+	}
+	got := make(map[string]string, len(row))
+	for i, name := range conflictCSVHeader {
+		got[name] = row[i]
+	}
+	for col, want := range map[string]string{
+		"id":                  c.ID.String(),
+		"detected_at":         "2026-07-01T12:00:00Z",
+		"significance":        "0.75",
+		"outcome_divergence":  "",
+		"outcome_a":           "use Redis, not Memcached",
+		"winning_decision_id": "",
+		"resolution_note":     note,
+	} {
+		if got[col] != want {
+			t.Errorf("%s = %q, want %q", col, got[col], want)
+		}
+	}
+}
+
+func TestParseImportLine(t *testing.T) {
+	headID, prevID := uuid.New(), uuid.New()
+	validFrom := "2026-01-02T03:04:05Z"
+
+	t.Run("blank line yields nothing", func(t *testing.T) {
+		got, err := parseImportLine([]byte("   "))
+		if err != nil || got != nil {
+			t.Fatalf("parseImportLine(blank) = %v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("full_history line flattens oldest first", func(t *testing.T) {
+		line := `{"id":"` + headID.String() + `","agent_id":"a","decision_type":"architecture","outcome":"new","confidence":0.8,` +
+			`"valid_from":"` + validFrom + `","supersedes_id":"` + prevID.String() + `",` +
+			`"revisions":[{"id":"` + prevID.String() + `","agent_id":"a","decision_type":"architecture","outcome":"old","confidence":0.5,` +
+			`"valid_from":"` + validFrom + `","valid_to":"` + validFrom + `"}]}`
+		got, err := parseImportLine([]byte(line))
+		if err != nil {
+			t.Fatalf("parseImportLine: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != prevID || got[1].ID != headID {
+			t.Fatalf("got %d decisions in wrong order: %+v", len(got), got)
+		}
+		if got[1].SupersedesID == nil || *got[1].SupersedesID != prevID {
+			t.Fatalf("supersedes_id not preserved: %v", got[1].SupersedesID)
+		}
+	})
+
+	cases := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"invalid json", `{"id":`, "invalid JSON"},
+		{"error sentinel", `{"__error":true,"message":"boom","exported":3}`, "did not complete"},
+		{"missing id", `{"agent_id":"a","decision_type":"t","outcome":"o","confidence":0.5,"valid_from":"` + validFrom + `"}`, "id is required"},
+		{"bad confidence", `{"id":"` + headID.String() + `","agent_id":"a","decision_type":"t","outcome":"o","confidence":2,"valid_from":"` + validFrom + `"}`, "confidence"},
+		{"missing valid_from", `{"id":"` + headID.String() + `","agent_id":"a","decision_type":"t","outcome":"o","confidence":0.5}`, "valid_from is required"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseImportLine([]byte(tc.line))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("parseImportLine(%s) error = %v, want containing %q", tc.line, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateExportFormat(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", exportFormatNDJSON},
+		{"*/*", exportFormatNDJSON},
+		{"application/x-ndjson", exportFormatNDJSON},
+		{"text/csv", exportFormatCSV},
+		{"text/csv;q=0.9, */*;q=0.1", exportFormatCSV},
+		{"application/vnd.apache.parquet", exportFormatParquet},
+	}
+	for _, tc := range cases {
+		if got := negotiateExportFormat(tc.accept); got != tc.want {
+			t.Fatalf("negotiateExportFormat(%q) = %q, want %q", tc.accept, got, tc.want)
+		}
+	}
+}
+
+// TestDecisionExportParquetRoundTrip writes decisions through the Parquet
+// export and reads the file back with the library's reader, checking the
+// schema a consumer sees and that optional fields survive as nulls.
+func TestDecisionExportParquetRoundTrip(t *testing.T) {
+	reasoning := "because"
+	low, high := float32(0.5), float32(0.9)
+	validTo := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	prev := uuid.New()
+	full := model.Decision{
+		ID:                uuid.New(),
+		RunID:             uuid.New(),
+		AgentID:           "agent",
+		DecisionType:      "architecture",
+		Outcome:           "use postgres",
+		Confidence:        0.7,
+		ConfidenceLow:     &low,
+		ConfidenceHigh:    &high,
+		Reasoning:         &reasoning,
+		SupersedesID:      &prev,
+		Metadata:          map[string]any{"k": "v"},
+		ValidFrom:         time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ValidTo:           &validTo,
+		TransactionTime:   time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC),
+		CreatedAt:         time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC),
+		Alternatives:      []model.Alternative{{Label: "sqlite"}},
+		CompletenessScore: 0.4,
+	}
+	bare := full
+	bare.ID = uuid.New()
+	bare.Reasoning, bare.ValidTo, bare.Metadata = nil, nil, nil
+
+	var buf bytes.Buffer
+	e := newParquetDecisionExport(&buf)
+	require.NoError(t, e.write(full, nil))
+	require.NoError(t, e.endPage())
+	require.NoError(t, e.write(bare, nil))
+	require.NoError(t, e.finish())
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Len(t, f.RowGroups(), 2, "one row group per page")
+
+	fields := f.Schema().Fields()
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name()
+	}
+	assert.Equal(t, decisionExportHeader, names, "CSV header and Parquet columns share one order")
+	byName := map[string]parquet.Field{}
+	for _, field := range fields {
+		byName[field.Name()] = field
+	}
+	assert.True(t, byName["reasoning"].Optional())
+	assert.True(t, byName["id"].Required())
+	assert.Equal(t, "TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)", byName["valid_from"].Type().LogicalType().String())
+	assert.Equal(t, "STRING", byName["id"].Type().LogicalType().String())
+
+	rows, err := parquet.Read[decisionExportRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, newDecisionExportRow(&full), rows[0])
+	assert.Equal(t, full.ID.String(), rows[0].ID)
+	require.NotNil(t, rows[0].Metadata)
+	assert.JSONEq(t, `{"k":"v"}`, *rows[0].Metadata)
+	assert.True(t, validTo.Equal(*rows[0].ValidTo))
+	assert.Nil(t, rows[1].Reasoning)
+	assert.Nil(t, rows[1].ValidTo)
+	assert.Nil(t, rows[1].Metadata)
+}
+
+func TestDecisionExportCSVRecord(t *testing.T) {
+	low := float32(0.5)
+	d := model.Decision{
+		ID:            uuid.New(),
+		Confidence:    0.7,
+		ConfidenceLow: &low,
+		ValidFrom:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Alternatives:  []model.Alternative{{Label: "a"}, {Label: "b"}},
+	}
+	row := newDecisionExportRow(&d)
+	record := row.csvRecord()
+	require.Len(t, record, len(decisionExportHeader))
+	col := func(name string) string { return record[slices.Index(decisionExportHeader, name)] }
+
+	assert.Equal(t, d.ID.String(), col("id"))
+	assert.Equal(t, "0.7", col("confidence"))
+	assert.Equal(t, "0.5", col("confidence_low"))
+	assert.Empty(t, col("confidence_high"), "absent optional values are empty cells")
+	assert.Equal(t, "2026-01-01T00:00:00Z", col("valid_from"))
+	assert.Equal(t, "2", col("alternative_count"))
+}
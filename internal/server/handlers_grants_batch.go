@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// maxGrantBatchSize caps the number of grants in one POST /v1/grants/batch.
+const maxGrantBatchSize = 1000
+
+// grantCSVColumns are the columns accepted in a text/csv grant batch.
+// grantee_agent_id, resource_type, and permission are required; an empty
+// resource_id or expires_at cell means the field is unset.
+var grantCSVColumns = []string{"grantee_agent_id", "resource_type", "resource_id", "permission", "expires_at"}
+
+// HandleCreateGrantsBatch handles POST /v1/grants/batch. The body is either a
+// JSON GrantBatchRequest or, with Content-Type text/csv, a header row naming
+// grantCSVColumns followed by one grant per row.
+//
+// Each item is validated exactly as POST /v1/grants validates a single grant.
+// Items that fail are reported and skipped; the rest are inserted in one
+// transaction. Items matching an existing grant are reported as "exists"
+// rather than inserted again.
+func (h *Handlers) HandleCreateGrantsBatch(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	var reqs []model.CreateGrantRequest
+	var rowErrs map[int]string
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+		var err error
+		reqs, rowErrs, err = parseGrantCSV(http.MaxBytesReader(w, r.Body, h.maxRequestBodyBytes))
+		if err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				writeError(w, r, http.StatusRequestEntityTooLarge, model.ErrCodeInvalidInput, "request body too large")
+				return
+			}
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+			return
+		}
+	} else {
+		var req model.GrantBatchRequest
+		if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+			handleDecodeError(w, r, err)
+			return
+		}
+		reqs = req.Grants
+	}
+	if len(reqs) == 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "grants must contain at least one grant")
+		return
+	}
+	if len(reqs) > maxGrantBatchSize {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("grants exceeds maximum batch size of %d", maxGrantBatchSize))
+		return
+	}
+
+	grantor, err := h.db.GetAgentByAgentID(r.Context(), orgID, claims.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get grantor", err)
+		return
+	}
+
+	resp := model.GrantBatchResponse{Results: make([]model.GrantBatchResult, len(reqs))}
+	var pending []model.AccessGrant
+	var pendingIdx []int
+	for i, req := range reqs {
+		resp.Results[i] = model.GrantBatchResult{Index: i, Status: model.GrantBatchFailed}
+		if msg, ok := rowErrs[i]; ok {
+			resp.Results[i].Error = msg
+			continue
+		}
+		grant, reqErr := h.resolveGrantRequest(r.Context(), claims, orgID, grantor, req)
+		if reqErr != nil {
+			resp.Results[i].Error = reqErr.message
+			continue
+		}
+		pending = append(pending, grant)
+		pendingIdx = append(pendingIdx, i)
+	}
+
+	if len(pending) > 0 {
+		audit := h.buildAuditEntry(r, orgID, "create_grant", "access_grant", "", nil, nil, nil)
+		stored, created, err := h.db.CreateGrantsBatchWithAudit(r.Context(), pending, audit)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to create grants", err)
+			return
+		}
+		for j, i := range pendingIdx {
+			resp.Results[i].Grant = &stored[j]
+			resp.Results[i].Status = model.GrantBatchExists
+			if created[j] {
+				resp.Results[i].Status = model.GrantBatchCreated
+				// Invalidate the grantee's cached access set so the new grant takes effect immediately.
+				if h.grantCache != nil {
+					h.grantCache.Invalidate(orgID.String() + ":" + stored[j].GranteeID.String())
+				}
+			}
+		}
+	}
+
+	for _, res := range resp.Results {
+		switch res.Status {
+		case model.GrantBatchCreated:
+			resp.Created++
+		case model.GrantBatchExists:
+			resp.Existing++
+		default:
+			resp.Failed++
+		}
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+// parseGrantCSV parses a grant batch CSV. The header row names the columns,
+// in any order, from grantCSVColumns. A data row that cannot be turned into
+// a request (wrong number of cells) yields a zero request and an entry in
+// rowErrs keyed by its data-row index, so one bad row does not reject the
+// batch. Malformed CSV or an invalid header is an error.
+func parseGrantCSV(body io.Reader) ([]model.CreateGrantRequest, map[int]string, error) {
+	cr := csv.NewReader(body)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(grantCSVColumns, name) {
+			return nil, nil, fmt.Errorf("invalid csv header: unknown column %q", name)
+		}
+		if _, dup := col[name]; dup {
+			return nil, nil, fmt.Errorf("invalid csv header: duplicate column %q", name)
+		}
+		col[name] = i
+	}
+	for _, required := range []string{"grantee_agent_id", "resource_type", "permission"} {
+		if _, ok := col[required]; !ok {
+			return nil, nil, fmt.Errorf("invalid csv header: missing column %q", required)
+		}
+	}
+
+	var reqs []model.CreateGrantRequest
+	rowErrs := make(map[int]string)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		idx := len(reqs)
+		reqs = append(reqs, model.CreateGrantRequest{})
+		if len(record) != len(header) {
+			line, _ := cr.FieldPos(0)
+			rowErrs[idx] = fmt.Sprintf("line %d: expected %d columns, got %d", line, len(header), len(record))
+			continue
+		}
+		cell := func(name string) string {
+			if i, ok := col[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		optional := func(name string) *string {
+			if v := cell(name); v != "" {
+				return &v
+			}
+			return nil
+		}
+		reqs[idx] = model.CreateGrantRequest{
+			GranteeAgentID: cell("grantee_agent_id"),
+			ResourceType:   cell("resource_type"),
+			ResourceID:     optional("resource_id"),
+			Permission:     cell("permission"),
+			ExpiresAt:      optional("expires_at"),
+		}
+	}
+	return reqs, rowErrs, nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGrantCSV(t *testing.T) {
+	body := "permission,grantee_agent_id,resource_type,resource_id,expires_at\n" +
+		"read,reader-1,agent_traces,agent-a,\n" +
+		"read,reader-2,agent_traces\n" +
+		"\n" +
+		" read , reader-3 ,agent_traces,,2099-01-01T00:00:00Z\n"
+
+	reqs, rowErrs, err := parseGrantCSV(strings.NewReader(body))
+	require.NoError(t, err)
+	require.Len(t, reqs, 3)
+
+	assert.Equal(t, "reader-1", reqs[0].GranteeAgentID)
+	require.NotNil(t, reqs[0].ResourceID)
+	assert.Equal(t, "agent-a", *reqs[0].ResourceID)
+	assert.Nil(t, reqs[0].ExpiresAt, "empty cell leaves expires_at unset")
+
+	assert.Equal(t, map[int]string{1: "line 3: expected 5 columns, got 3"}, rowErrs)
+
+	assert.Equal(t, "reader-3", reqs[2].GranteeAgentID)
+	assert.Equal(t, "read", reqs[2].Permission)
+	assert.Nil(t, reqs[2].ResourceID)
+	require.NotNil(t, reqs[2].ExpiresAt)
+	assert.Equal(t, "2099-01-01T00:00:00Z", *reqs[2].ExpiresAt)
+}
+
+func TestParseGrantCSV_OptionalColumnsMayBeOmitted(t *testing.T) {
+	reqs, rowErrs, err := parseGrantCSV(strings.NewReader("grantee_agent_id,resource_type,permission\nr,agent_traces,read\n"))
+	require.NoError(t, err)
+	assert.Empty(t, rowErrs)
+	require.Len(t, reqs, 1)
+	assert.Nil(t, reqs[0].ResourceID)
+}
+
+func TestParseGrantCSV_InvalidHeader(t *testing.T) {
+	for name, header := range map[string]string{
+		"unknown column":   "grantee_agent_id,resource_type,permission,role",
+		"duplicate column": "grantee_agent_id,resource_type,permission,permission",
+		"missing column":   "grantee_agent_id,resource_type",
+	} {
+		_, _, err := parseGrantCSV(strings.NewReader(header + "\n"))
+		assert.Error(t, err, name)
+	}
+}
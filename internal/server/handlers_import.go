@@ -0,0 +1,184 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+const (
+	// importBatchSize is how many decisions are written per COPY transaction.
+	importBatchSize = 500
+	// maxImportErrors caps the per-line errors echoed in an import response.
+	maxImportErrors = 1000
+)
+
+// importLine is one NDJSON line of a decision export, in either export mode:
+// a bare decision (heads) or a decision with its prior revisions nested
+// (full_history). Error is the sentinel an export writes when it fails
+// mid-stream.
+type importLine struct {
+	model.Decision
+	Revisions []model.Decision `json:"revisions"`
+	Error     bool             `json:"__error"`
+}
+
+// importBatch accumulates decisions until they are flushed to storage,
+// remembering the input line each came from.
+type importBatch struct {
+	decisions []model.Decision
+	lines     []int
+}
+
+// HandleImportDecisions handles POST /v1/import/decisions (admin-only).
+// Reads NDJSON as written by GET /v1/export/decisions and inserts every
+// decision into the caller's org, preserving ids and temporal history.
+// Decisions are written in batches, each in its own transaction, so a
+// request that fails part-way leaves earlier batches committed; re-posting
+// the same stream is safe because already-imported decisions are skipped.
+//
+// Responds 200 when every decision was inserted or skipped, and 207 with
+// per-line errors when any failed.
+func (h *Handlers) HandleImportDecisions(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+
+	result := model.ImportDecisionsResult{Errors: []model.ImportLineError{}}
+	fail := func(line int, decisionID *uuid.UUID, msg string) {
+		result.Failed++
+		if len(result.Errors) >= maxImportErrors {
+			result.ErrorsTruncated = true
+			return
+		}
+		result.Errors = append(result.Errors, model.ImportLineError{Line: line, DecisionID: decisionID, Error: msg})
+	}
+
+	var batch importBatch
+	flush := func() error {
+		if len(batch.decisions) == 0 {
+			return nil
+		}
+		audit := h.buildAuditEntry(r, orgID, "", "decision", "", nil, nil, nil)
+		results, err := h.db.ImportDecisions(r.Context(), orgID, batch.decisions, audit)
+		if err != nil {
+			return err
+		}
+		for i, res := range results {
+			switch res.Status {
+			case storage.ImportInserted:
+				result.Inserted++
+			case storage.ImportSkipped:
+				result.Skipped++
+			case storage.ImportFailed:
+				id := batch.decisions[i].ID
+				fail(batch.lines[i], &id, res.Err.Error())
+			}
+		}
+		batch = importBatch{}
+		return nil
+	}
+
+	// Each line, not the whole body, is bounded by the request size limit:
+	// an export of any length can be imported.
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), int(h.maxRequestBodyBytes))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		decisions, err := parseImportLine(scanner.Bytes())
+		if err != nil {
+			fail(lineNo, nil, err.Error())
+			continue
+		}
+		for _, d := range decisions {
+			batch.decisions = append(batch.decisions, d)
+			batch.lines = append(batch.lines, lineNo)
+		}
+		// Flush only on line boundaries so a head and its revisions always
+		// land in the same transaction.
+		if len(batch.decisions) >= importBatchSize {
+			if err := flush(); err != nil {
+				h.writeInternalError(w, r, "import failed", err)
+				return
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// The stream cannot be resynchronized, so stop here. Decisions read
+		// so far are still written.
+		msg := "failed to read request body; import stopped"
+		if errors.Is(err, bufio.ErrTooLong) {
+			msg = fmt.Sprintf("line exceeds maximum length of %d bytes; import stopped", h.maxRequestBodyBytes)
+		}
+		fail(lineNo+1, nil, msg)
+	}
+	if err := flush(); err != nil {
+		h.writeInternalError(w, r, "import failed", err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	writeJSON(w, r, status, result)
+}
+
+// parseImportLine decodes one NDJSON line into the decisions it carries,
+// oldest revision first. Blank lines yield nothing.
+func parseImportLine(raw []byte) ([]model.Decision, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var line importLine
+	if err := json.Unmarshal(raw, &line); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if line.Error {
+		return nil, errors.New("export error sentinel: the source export did not complete")
+	}
+	decisions := append(line.Revisions, line.Decision)
+	for _, d := range decisions {
+		if err := validateImportedDecision(d); err != nil {
+			if d.ID != uuid.Nil {
+				return nil, fmt.Errorf("decision %s: %w", d.ID, err)
+			}
+			return nil, err
+		}
+	}
+	return decisions, nil
+}
+
+// validateImportedDecision checks the fields an import cannot default.
+func validateImportedDecision(d model.Decision) error {
+	if d.ID == uuid.Nil {
+		return errors.New("id is required")
+	}
+	if err := model.ValidateAgentID(d.AgentID); err != nil {
+		return err
+	}
+	if d.DecisionType == "" {
+		return errors.New("decision_type is required")
+	}
+	if d.Outcome == "" {
+		return errors.New("outcome is required")
+	}
+	if d.Confidence < 0 || d.Confidence > 1 {
+		return errors.New("confidence must be between 0 and 1")
+	}
+	if d.ValidFrom.IsZero() {
+		return errors.New("valid_from is required")
+	}
+	if d.ValidTo != nil && d.ValidTo.Before(d.ValidFrom) {
+		return errors.New("valid_to must not be before valid_from")
+	}
+	return nil
+}
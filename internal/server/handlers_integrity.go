@@ -6,12 +6,15 @@ import (
 
 	"github.com/ashita-ai/akashi/internal/integrity"
 	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
 )
 
 // HandleGetDecisionProof handles GET /v1/integrity/proof/{id}.
 // Returns a Merkle inclusion proof for a specific decision, allowing external
 // auditors to verify that a decision is part of the tamper-evident audit trail
-// without reconstructing the entire batch.
+// without reconstructing the entire batch. Verified reports whether the
+// returned path reconstructs the stored root, the same check an external
+// verifier performs with integrity.VerifyMerkleProof.
 func (h *Handlers) HandleGetDecisionProof(w http.ResponseWriter, r *http.Request) {
 	orgID := OrgIDFromContext(r.Context())
 
@@ -21,6 +24,27 @@ func (h *Handlers) HandleGetDecisionProof(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// The proof exposes the decision's content hash, so it is subject to the
+	// same agent access check as GET /v1/verify/{id}.
+	d, err := h.db.GetDecision(r.Context(), orgID, decisionID, storage.GetDecisionOpts{})
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision", err)
+		return
+	}
+	ok, err := canAccessAgent(r.Context(), h.db, ClaimsFromContext(r.Context()), d.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this decision")
+		return
+	}
+
 	// 1. Find the proof batch that covers this decision.
 	proof, contentHash, err := h.db.FindProofForDecision(r.Context(), orgID, decisionID)
 	if err != nil {
@@ -73,6 +97,6 @@ func (h *Handlers) HandleGetDecisionProof(w http.ResponseWriter, r *http.Request
 		BatchStart:  proof.BatchStart,
 		BatchEnd:    proof.BatchEnd,
 		ProofPath:   steps,
-		Verified:    rootHash == proof.RootHash,
+		Verified:    integrity.VerifyMerkleProof(contentHash, steps, proof.RootHash),
 	})
 }
@@ -206,6 +206,75 @@ func (h *Handlers) HandleRotateKey(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// HandleRotateAgentKey handles POST /v1/agents/{agent_id}/rotate-key (admin or self).
+// Revokes every active key of the agent and issues a single new one, returning
+// the raw key exactly once. The agent and its decisions are untouched. Keys
+// only gate token minting, so tokens already issued stay valid until expiry.
+func (h *Handlers) HandleRotateAgentKey(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	agentID := r.PathValue("agent_id")
+	if err := model.ValidateAgentID(agentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	// A scoped token is short-lived by design; letting it mint a permanent
+	// credential would defeat that.
+	if claims.ScopedBy != "" {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "scoped tokens cannot rotate keys")
+		return
+	}
+	if claims.AgentID != agentID && !model.RoleAtLeast(claims.Role, model.RoleAdmin) {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "only admins can rotate another agent's key")
+		return
+	}
+
+	rawKey, prefix, err := model.GenerateRawKey()
+	if err != nil {
+		h.writeInternalError(w, r, "failed to generate api key", err)
+		return
+	}
+
+	hash, err := auth.HashAPIKey(rawKey)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to hash api key", err)
+		return
+	}
+
+	newKey := model.APIKey{
+		Prefix:    prefix,
+		KeyHash:   hash,
+		AgentID:   agentID,
+		OrgID:     orgID,
+		Label:     "rotated",
+		CreatedBy: claims.AgentID,
+	}
+
+	audit := h.buildAuditEntry(r, orgID, "rotate_agent_key", "agent", agentID, nil, nil, nil)
+	created, revoked, err := h.db.RotateAgentAPIKeyWithAudit(r.Context(), orgID, agentID, newKey, audit)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "agent not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to rotate agent key", err)
+		return
+	}
+	if revoked == nil {
+		revoked = []uuid.UUID{}
+	}
+
+	writeJSON(w, r, http.StatusOK, model.RotateAgentKeyResponse{
+		AgentID: agentID,
+		NewKey: model.APIKeyWithRawKey{
+			APIKey: created,
+			RawKey: rawKey,
+		},
+		RevokedKeyIDs: revoked,
+	})
+}
+
 // HandleGetUsage handles GET /v1/usage (admin-only).
 // Returns decision counts grouped by API key for billing/metering.
 // Accepts ?period=YYYY-MM query parameter (defaults to current month).
@@ -1,8 +1,13 @@
 package server
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
+
 	"github.com/ashita-ai/akashi/internal/model"
 )
 
@@ -30,11 +35,9 @@ func (h *Handlers) HandleSetOrgSettings(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.ConflictResolution != nil {
-		if err := req.ConflictResolution.Validate(); err != nil {
-			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
-			return
-		}
+	if err := validateOrgSettings(&req); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
 	}
 
 	updatedBy := claims.ActorID()
@@ -58,3 +61,203 @@ func (h *Handlers) HandleSetOrgSettings(w http.ResponseWriter, r *http.Request)
 	}
 	writeJSON(w, r, http.StatusOK, settings.Settings)
 }
+
+// validateOrgSettings checks every policy set in s.
+func validateOrgSettings(s *model.OrgSettingsData) error {
+	if s.ConflictResolution != nil {
+		if err := s.ConflictResolution.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.DecisionQuota != nil {
+		if err := s.DecisionQuota.Validate(); err != nil {
+			return fmt.Errorf("decision_quota: %w", err)
+		}
+	}
+	if s.ReviewRouting != nil {
+		if err := s.ReviewRouting.Validate(); err != nil {
+			return fmt.Errorf("review_routing: %w", err)
+		}
+	}
+	if s.PrecedentDecay != nil {
+		if err := s.PrecedentDecay.Validate(); err != nil {
+			return fmt.Errorf("precedent_decay: %w", err)
+		}
+	}
+	if s.DefaultIncludes != nil {
+		if err := s.DefaultIncludes.Validate(); err != nil {
+			return fmt.Errorf("default_includes: %w", err)
+		}
+	}
+	if s.MCPTools != nil {
+		if err := s.MCPTools.Validate(); err != nil {
+			return fmt.Errorf("mcp_tools: %w", err)
+		}
+	}
+	if s.RunReviewGate != nil {
+		if err := s.RunReviewGate.Validate(); err != nil {
+			return fmt.Errorf("run_review_gate: %w", err)
+		}
+	}
+	if s.ConfigOverrides != nil {
+		if err := s.ConfigOverrides.Validate(); err != nil {
+			return fmt.Errorf("config_overrides: %w", err)
+		}
+	}
+	if err := s.MinAlternatives.Validate(); err != nil {
+		return fmt.Errorf("min_alternatives: %w", err)
+	}
+	for eventType, schema := range s.EventSchemas {
+		if eventType == "" {
+			return fmt.Errorf("event_schemas keys must be non-empty event types")
+		}
+		if err := schema.Validate(); err != nil {
+			return fmt.Errorf("event_schemas.%s: %w", eventType, err)
+		}
+	}
+	return nil
+}
+
+// settingsOrgFromPath parses {org_id} and checks the caller may administer
+// that org: platform admins any org, admins only their own. It writes the
+// error response and returns false otherwise.
+func settingsOrgFromPath(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	orgID, err := parsePathUUID(r, "org_id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid org_id")
+		return uuid.Nil, false
+	}
+	claims := ClaimsFromContext(r.Context())
+	if claims.Role != model.RolePlatformAdmin && orgID != OrgIDFromContext(r.Context()) {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "can only manage your own organization's settings")
+		return uuid.Nil, false
+	}
+	return orgID, true
+}
+
+// HandleGetOrgSettingsByID handles GET /v1/orgs/{org_id}/settings.
+// Returns the org's settings row, including who last changed it.
+// Requires admin for the caller's own org, platform_admin for any other.
+func (h *Handlers) HandleGetOrgSettingsByID(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := settingsOrgFromPath(w, r)
+	if !ok {
+		return
+	}
+	settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to get org settings", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, settings)
+}
+
+// HandleSetOrgSetting handles PUT /v1/orgs/{org_id}/settings.
+// Replaces one top-level settings key, leaving the others untouched; a null
+// value removes it. The value is validated as PUT /v1/org/settings would
+// validate it. Requires admin for the caller's own org, platform_admin for
+// any other.
+func (h *Handlers) HandleSetOrgSetting(w http.ResponseWriter, r *http.Request) {
+	orgID, ok := settingsOrgFromPath(w, r)
+	if !ok {
+		return
+	}
+	claims := ClaimsFromContext(r.Context())
+
+	var req model.SetOrgSettingRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if req.Key == "" {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "key is required")
+		return
+	}
+
+	// Decode the key alone into the settings document: unknown keys and
+	// mistyped values are rejected the same way a full PUT rejects them.
+	probeBody, err := json.Marshal(map[string]json.RawMessage{req.Key: req.Value})
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid value")
+		return
+	}
+	var probe model.OrgSettingsData
+	dec := json.NewDecoder(bytes.NewReader(probeBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&probe); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, fmt.Sprintf("invalid setting %q: %v", req.Key, err))
+		return
+	}
+	if err := validateOrgSettings(&probe); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	updatedBy := claims.ActorID()
+	audit := h.buildAuditEntry(r, orgID,
+		"org_setting_updated", "org_settings", orgID.String(),
+		nil, nil, // before/after populated inside the transaction
+		map[string]any{"updated_by": updatedBy, "key": req.Key},
+	)
+	if err := h.db.SetOrgSetting(r.Context(), orgID, req.Key, req.Value, updatedBy, audit); err != nil {
+		if isForeignKeyViolation(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "organization not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to update org setting", err)
+		return
+	}
+
+	settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to read org settings after update", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, settings)
+}
+
+// HandleSetOrgRateLimit handles PUT /v1/orgs/{org_id}/rate-limit.
+// Sets the org's rate limit override, applied to each of its agents and API
+// keys in place of AKASHI_RATE_LIMIT_RPS/BURST. Null rps and burst clear it.
+// Requires platform_admin. Other instances pick up the change within
+// orgRateLimitTTL.
+func (h *Handlers) HandleSetOrgRateLimit(w http.ResponseWriter, r *http.Request) {
+	orgID, err := parsePathUUID(r, "org_id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid org_id")
+		return
+	}
+
+	var req model.SetOrgRateLimitRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+
+	var limit *model.OrgRateLimit
+	switch {
+	case req.RPS == nil && req.Burst == nil:
+	case req.RPS == nil || req.Burst == nil:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "rps and burst must be set or cleared together")
+		return
+	case *req.RPS <= 0 || *req.Burst <= 0:
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "rps and burst must be positive")
+		return
+	default:
+		limit = &model.OrgRateLimit{RPS: *req.RPS, Burst: *req.Burst}
+	}
+
+	if err := h.db.SetOrgRateLimit(r.Context(), orgID, limit); err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "organization not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to set org rate limit", err)
+		return
+	}
+	h.orgRateLimits.invalidate(orgID)
+
+	writeJSON(w, r, http.StatusOK, map[string]any{
+		"org_id":     orgID,
+		"rate_limit": limit,
+	})
+}
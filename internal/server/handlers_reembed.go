@@ -0,0 +1,309 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// VectorCollection is the search index collection whose vector size must
+// match the embedding model. Implemented by *search.QdrantIndex.
+type VectorCollection interface {
+	// CollectionDims returns the live collection's vector size (0 if absent).
+	CollectionDims(ctx context.Context) (uint64, error)
+	// Dims returns the vector size the index is configured for.
+	Dims() uint64
+	// RecreateCollection drops and recreates the collection with Dims.
+	RecreateCollection(ctx context.Context) error
+	// DeleteByOrg removes every point belonging to one org.
+	DeleteByOrg(ctx context.Context, orgID uuid.UUID) error
+}
+
+// errSearchCollectionResize is returned when the search collection's vector
+// size no longer matches the embedding model and the caller is not allowed
+// to recreate it.
+var errSearchCollectionResize = errors.New("search collection vector size does not match the embedding model")
+
+const (
+	defaultReembedBatchSize = 50
+	maxReembedBatchSize     = 500
+	defaultReembedLimit     = 10_000
+	maxReembedLimit         = 100_000
+)
+
+// reembedProgress is one NDJSON line emitted by HandleReembed while a job
+// runs. The final line has Done=true when the job completed, or Error set if
+// the run was cut short; otherwise the run hit its limit and can be resumed.
+type reembedProgress struct {
+	JobID     string `json:"job_id"`
+	Batch     int    `json:"batch"`
+	Scanned   int    `json:"scanned"`
+	Processed int    `json:"processed"`
+	Failed    int    `json:"failed"`
+	Done      bool   `json:"done"`
+	Error     string `json:"error,omitempty"`
+}
+
+// newConfirmationToken returns a random token the caller must echo back to
+// run a re-embedding job.
+func newConfirmationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HandleReembed handles POST /v1/admin/reembed (admin+).
+// Regenerates embeddings, outcome embeddings, and claims for every current
+// decision in the caller's org after an embedding model change.
+//
+// Without ?confirm= the call only plans: it creates (or returns) the org's
+// open job for from_model → to_model and responds 202 with the job and its
+// confirmation_token. Repeating the call with confirm=<token> runs the job
+// in batches of batch_size up to limit decisions, streaming NDJSON progress.
+// A run that stops at limit, or is interrupted, resumes from the job's
+// cursor on the next confirmed call.
+//
+// to_model must be the embedding model the server is configured with. Before
+// the first batch the org's points are cleared from the search collection and
+// are re-indexed as the job runs. If the collection's vector size differs from
+// the embedding size, it must be recreated, which drops every org's points;
+// only a platform_admin may do that, and other callers get 409 until one has.
+func (h *Handlers) HandleReembed(w http.ResponseWriter, r *http.Request) {
+	if h.decisionSvc == nil || !h.decisionSvc.EmbeddingAvailable() {
+		writeError(w, r, http.StatusNotImplemented, model.ErrCodeNotImplemented,
+			"no embedding provider configured")
+		return
+	}
+
+	orgID := OrgIDFromContext(r.Context())
+	claims := ClaimsFromContext(r.Context())
+	q := r.URL.Query()
+
+	fromModel, toModel := q.Get("from_model"), q.Get("to_model")
+	if fromModel == "" || toModel == "" {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "from_model and to_model are required")
+		return
+	}
+	if fromModel == toModel {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "from_model and to_model must differ")
+		return
+	}
+	if h.embeddingModel != "" && toModel != h.embeddingModel {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			"to_model must be the configured embedding model ("+h.embeddingModel+"); switch the provider and restart first")
+		return
+	}
+
+	batchSize := queryInt(r, "batch_size", defaultReembedBatchSize)
+	if batchSize < 1 || batchSize > maxReembedBatchSize {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "batch_size must be between 1 and 500")
+		return
+	}
+	limit := queryInt(r, "limit", defaultReembedLimit)
+	if limit < 1 || limit > maxReembedLimit {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "limit must be between 1 and 100000")
+		return
+	}
+
+	job, err := h.db.GetOpenReembedJob(r.Context(), orgID)
+	switch {
+	case err == nil:
+		if job.FromModel != fromModel || job.ToModel != toModel {
+			writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+				"another re-embedding job is open for "+job.FromModel+" → "+job.ToModel)
+			return
+		}
+	case errors.Is(err, storage.ErrNotFound):
+		if q.Get("confirm") != "" {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				"no open re-embedding job; call without confirm to create one")
+			return
+		}
+		token, err := newConfirmationToken()
+		if err != nil {
+			h.writeInternalError(w, r, "failed to generate confirmation token", err)
+			return
+		}
+		job, err = h.db.CreateReembedJob(r.Context(), orgID, fromModel, toModel, token, claims.ActorID())
+		if err != nil {
+			if h.db.IsDuplicateKey(err) {
+				writeError(w, r, http.StatusConflict, model.ErrCodeConflict, "a re-embedding job was created concurrently")
+				return
+			}
+			h.writeInternalError(w, r, "failed to create re-embedding job", err)
+			return
+		}
+		h.logger.Info("reembed: job created", "org_id", orgID, "job_id", job.ID,
+			"from_model", fromModel, "to_model", toModel, "total", job.Total)
+	default:
+		h.writeInternalError(w, r, "failed to load re-embedding job", err)
+		return
+	}
+
+	confirm := q.Get("confirm")
+	if confirm == "" {
+		writeJSON(w, r, http.StatusAccepted, job)
+		return
+	}
+	if confirm != job.ConfirmationToken {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "confirmation token does not match the open job")
+		return
+	}
+
+	if job.Status == model.ReembedStatusPending && h.vectorCollection != nil {
+		want := uint64(h.decisionSvc.EmbeddingDimensions())
+		if err := h.prepareSearchCollection(r.Context(), orgID, claims.Role, want); err != nil {
+			if errors.Is(err, errSearchCollectionResize) {
+				writeError(w, r, http.StatusConflict, model.ErrCodeConflict,
+					"the search collection must be recreated for the new vector size; a platform_admin must run the first re-embed")
+				return
+			}
+			h.writeInternalError(w, r, "failed to prepare search collection", err)
+			return
+		}
+	}
+
+	h.runReembedJob(w, r, job, batchSize, limit)
+}
+
+// prepareSearchCollection readies the search collection for one org's
+// re-embed. When the vector size still matches, only that org's points are
+// cleared; the job re-indexes them through the search outbox. Recreating the
+// collection for a new vector size wipes every tenant's points, so it is
+// reserved for platform_admin.
+func (h *Handlers) prepareSearchCollection(ctx context.Context, orgID uuid.UUID, role model.AgentRole, want uint64) error {
+	live, err := h.vectorCollection.CollectionDims(ctx)
+	if err != nil {
+		return err
+	}
+	if live == want {
+		return h.vectorCollection.DeleteByOrg(ctx, orgID)
+	}
+	if role != model.RolePlatformAdmin {
+		return errSearchCollectionResize
+	}
+	if h.vectorCollection.Dims() != want {
+		return errors.New("search index dims do not match the embedding dimensions; fix AKASHI_EMBEDDING_DIMENSIONS")
+	}
+	h.logger.Warn("reembed: recreating search collection for new vector size", "from_dims", live, "to_dims", want, "org_id", orgID)
+	return h.vectorCollection.RecreateCollection(ctx)
+}
+
+// runReembedJob re-embeds the job's remaining decisions, streaming progress
+// and persisting the cursor after every batch so the job can be resumed.
+func (h *Handlers) runReembedJob(w http.ResponseWriter, r *http.Request, job model.ReembedJob, batchSize, limit int) {
+	orgID := job.OrgID
+	start := time.Now()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	emit := func(p reembedProgress) {
+		_ = encoder.Encode(p)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	progress := reembedProgress{JobID: job.ID.String()}
+	cursor := job.CursorID
+	for progress.Scanned < limit {
+		decs, err := h.db.FindDecisionsForReembed(r.Context(), orgID, cursor, min(batchSize, limit-progress.Scanned))
+		if err != nil {
+			h.logger.Error("reembed: fetch batch failed", "error", err, "org_id", orgID, "job_id", job.ID,
+				"request_id", RequestIDFromContext(r.Context()))
+			progress.Error = "reembed terminated due to internal error"
+			break
+		}
+		if len(decs) == 0 {
+			if _, err := h.db.AdvanceReembedJob(r.Context(), orgID, job.ID, nil, 0, 0, true); err != nil {
+				h.logger.Error("reembed: complete job failed", "error", err, "org_id", orgID, "job_id", job.ID)
+				progress.Error = "reembed terminated due to internal error"
+				break
+			}
+			progress.Done = true
+			break
+		}
+
+		ids := make([]uuid.UUID, len(decs))
+		for i, d := range decs {
+			ids[i] = d.ID
+		}
+		if err := h.db.DeleteClaimsForDecisions(r.Context(), orgID, ids); err != nil {
+			h.logger.Error("reembed: clear claims failed", "error", err, "org_id", orgID, "job_id", job.ID)
+			progress.Error = "reembed terminated due to internal error"
+			break
+		}
+		n, err := h.decisionSvc.ReembedDecisions(r.Context(), decs)
+		if err != nil {
+			// Whole-batch failures (provider down, context cancelled) leave the
+			// cursor in place so the batch is retried on resume.
+			progress.Error = "reembed interrupted: " + err.Error()
+			break
+		}
+
+		last := decs[len(decs)-1].ID
+		if _, err := h.db.AdvanceReembedJob(r.Context(), orgID, job.ID, &last, n, len(decs)-n, false); err != nil {
+			h.logger.Error("reembed: save progress failed", "error", err, "org_id", orgID, "job_id", job.ID)
+			progress.Error = "reembed terminated due to internal error"
+			break
+		}
+		cursor = &last
+		progress.Batch++
+		progress.Scanned += len(decs)
+		progress.Processed += n
+		progress.Failed += len(decs) - n
+		emit(progress)
+	}
+	emit(progress)
+
+	h.logger.Info("reembed: run finished",
+		"org_id", orgID,
+		"job_id", job.ID,
+		"batches", progress.Batch,
+		"processed", progress.Processed,
+		"failed", progress.Failed,
+		"done", progress.Done,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", RequestIDFromContext(r.Context()))
+
+	if auditErr := h.recordMutationAuditBestEffort(r, orgID,
+		"decisions_reembedded", "reembed_job", job.ID.String(), nil,
+		map[string]any{"processed": progress.Processed, "failed": progress.Failed, "done": progress.Done},
+		map[string]any{
+			"from_model": job.FromModel,
+			"to_model":   job.ToModel,
+			"batch_size": batchSize,
+			"limit":      limit,
+		},
+	); auditErr != nil {
+		h.logger.Error("failed to audit reembed run", "org_id", orgID, "error", auditErr)
+	}
+}
+
+// HandleGetReembedJob handles GET /v1/admin/reembed (admin+).
+// Returns the org's most recent re-embedding job and its progress.
+func (h *Handlers) HandleGetReembedJob(w http.ResponseWriter, r *http.Request) {
+	orgID := OrgIDFromContext(r.Context())
+	job, err := h.db.GetLatestReembedJob(r.Context(), orgID)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "no re-embedding job")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get re-embedding job", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, job)
+}
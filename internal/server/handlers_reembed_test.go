@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// fakeVectorCollection records which destructive operations were requested.
+type fakeVectorCollection struct {
+	live, dims  uint64
+	recreated   bool
+	deletedOrgs []uuid.UUID
+}
+
+func (f *fakeVectorCollection) CollectionDims(context.Context) (uint64, error) { return f.live, nil }
+func (f *fakeVectorCollection) Dims() uint64                                   { return f.dims }
+func (f *fakeVectorCollection) RecreateCollection(context.Context) error {
+	f.recreated = true
+	f.live = f.dims
+	return nil
+}
+
+func (f *fakeVectorCollection) DeleteByOrg(_ context.Context, orgID uuid.UUID) error {
+	f.deletedOrgs = append(f.deletedOrgs, orgID)
+	return nil
+}
+
+func TestPrepareSearchCollection_SameDimsClearsOnlyCallerOrg(t *testing.T) {
+	coll := &fakeVectorCollection{live: 1024, dims: 1024}
+	h := &Handlers{vectorCollection: coll, logger: testLogger()}
+	orgID := uuid.New()
+
+	require.NoError(t, h.prepareSearchCollection(context.Background(), orgID, model.RoleAdmin, 1024))
+	assert.False(t, coll.recreated)
+	assert.Equal(t, []uuid.UUID{orgID}, coll.deletedOrgs)
+}
+
+func TestPrepareSearchCollection_ResizeRequiresPlatformAdmin(t *testing.T) {
+	for _, role := range []model.AgentRole{model.RoleAdmin, model.RoleOrgOwner} {
+		coll := &fakeVectorCollection{live: 768, dims: 1024}
+		h := &Handlers{vectorCollection: coll, logger: testLogger()}
+
+		err := h.prepareSearchCollection(context.Background(), uuid.New(), role, 1024)
+		require.ErrorIs(t, err, errSearchCollectionResize, "role %s", role)
+		assert.False(t, coll.recreated, "role %s must not drop the shared collection", role)
+		assert.Empty(t, coll.deletedOrgs)
+	}
+
+	coll := &fakeVectorCollection{live: 768, dims: 1024}
+	h := &Handlers{vectorCollection: coll, logger: testLogger()}
+	require.NoError(t, h.prepareSearchCollection(context.Background(), uuid.New(), model.RolePlatformAdmin, 1024))
+	assert.True(t, coll.recreated)
+}
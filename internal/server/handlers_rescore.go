@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// ConflictRescorer re-runs conflict scoring over existing decisions.
+// Implemented by *conflicts.Scorer.
+type ConflictRescorer interface {
+	RescoreDecisions(ctx context.Context, refs []storage.DecisionRef) (int, error)
+}
+
+const (
+	defaultRescoreBatchSize = 100
+	maxRescoreBatchSize     = 1000
+	defaultRescoreLimit     = 10_000
+	maxRescoreLimit         = 100_000
+)
+
+// rescoreProgress is one NDJSON line emitted by HandleRescoreConflicts.
+// Intermediate lines report progress after each batch; the final line has
+// Done=true (or Error set if the run was cut short).
+type rescoreProgress struct {
+	Batch     int    `json:"batch"`
+	Scanned   int    `json:"scanned"`
+	Processed int    `json:"processed"`
+	Done      bool   `json:"done"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HandleRescoreConflicts handles POST /v1/admin/conflicts/rescore.
+// Re-runs the conflict scorer over current decisions in the caller's org,
+// optionally filtered by decision_type and a valid_from window (from/to).
+// Decisions are processed in batches of batch_size (default 100, max 1000) up
+// to limit decisions (default 10000, max 100000). Progress is streamed as
+// NDJSON, one line per batch plus a final summary line. Rescoring also
+// backfills fields older conflicts lack, such as reason_code.
+//
+// Rewrites scored_conflicts, so it requires AKASHI_ENABLE_DESTRUCTIVE_DELETE.
+func (h *Handlers) HandleRescoreConflicts(w http.ResponseWriter, r *http.Request) {
+	if !h.enableDestructiveDelete {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+			"conflict rescore is disabled; set AKASHI_ENABLE_DESTRUCTIVE_DELETE=true to enable")
+		return
+	}
+	if h.conflictRescorer == nil {
+		writeError(w, r, http.StatusNotImplemented, model.ErrCodeNotImplemented,
+			"no conflict scorer configured")
+		return
+	}
+
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	filters := model.QueryFilters{}
+	if dt := q.Get("decision_type"); dt != "" {
+		filters.DecisionType = &dt
+	}
+	from, err := queryTime(r, "from")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	to, err := queryTime(r, "to")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "to must not be before from")
+		return
+	}
+	if from != nil || to != nil {
+		filters.TimeRange = &model.TimeRange{From: from, To: to}
+	}
+
+	batchSize := queryInt(r, "batch_size", defaultRescoreBatchSize)
+	if batchSize < 1 || batchSize > maxRescoreBatchSize {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "batch_size must be between 1 and 1000")
+		return
+	}
+	limit := queryInt(r, "limit", defaultRescoreLimit)
+	if limit < 1 || limit > maxRescoreLimit {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "limit must be between 1 and 100000")
+		return
+	}
+
+	start := time.Now()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	emit := func(p rescoreProgress) {
+		_ = encoder.Encode(p)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	var progress rescoreProgress
+	var cursor *storage.ExportCursor
+	exhausted := false
+	for progress.Scanned < limit {
+		pageSize := min(batchSize, limit-progress.Scanned)
+		refs, next, err := h.db.FindDecisionsForRescore(r.Context(), orgID, filters, cursor, pageSize)
+		if err != nil {
+			h.logger.Error("conflict rescore: fetch batch failed",
+				"error", err, "org_id", orgID, "batch", progress.Batch+1,
+				"request_id", RequestIDFromContext(r.Context()))
+			progress.Error = "rescore terminated due to internal error"
+			break
+		}
+		if len(refs) == 0 {
+			exhausted = true
+			break
+		}
+
+		n, err := h.conflictRescorer.RescoreDecisions(r.Context(), refs)
+		progress.Batch++
+		progress.Scanned += len(refs)
+		progress.Processed += n
+		if err != nil {
+			// Context cancellation (client disconnect or timeout) is the only
+			// error RescoreDecisions returns; per-decision failures are logged.
+			progress.Error = "rescore interrupted: " + err.Error()
+			break
+		}
+		emit(progress)
+
+		if next == nil {
+			exhausted = true
+			break
+		}
+		cursor = next
+	}
+
+	progress.Done = progress.Error == ""
+	progress.Truncated = progress.Done && !exhausted
+	emit(progress)
+
+	h.logger.Info("conflict rescore finished",
+		"org_id", orgID,
+		"batches", progress.Batch,
+		"scanned", progress.Scanned,
+		"processed", progress.Processed,
+		"truncated", progress.Truncated,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"request_id", RequestIDFromContext(r.Context()))
+
+	if auditErr := h.recordMutationAuditBestEffort(r, orgID,
+		"conflicts_rescored", "scored_conflicts", orgID.String(), nil,
+		map[string]any{"scanned": progress.Scanned, "processed": progress.Processed, "done": progress.Done},
+		map[string]any{
+			"decision_type": q.Get("decision_type"),
+			"from":          q.Get("from"),
+			"to":            q.Get("to"),
+			"batch_size":    batchSize,
+			"limit":         limit,
+		},
+	); auditErr != nil {
+		h.logger.Error("failed to audit conflict rescore", "org_id", orgID, "error", auditErr)
+	}
+}
@@ -0,0 +1,213 @@
+//go:build integration
+
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/server"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/service/embedding"
+	"github.com/ashita-ai/akashi/internal/service/trace"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// recordingRescorer is a ConflictRescorer that records every batch it is
+// handed and reports each decision as processed.
+type recordingRescorer struct {
+	mu      sync.Mutex
+	batches [][]storage.DecisionRef
+}
+
+func (r *recordingRescorer) RescoreDecisions(_ context.Context, refs []storage.DecisionRef) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]storage.DecisionRef(nil), refs...))
+	return len(refs), nil
+}
+
+func (r *recordingRescorer) ids() []uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []uuid.UUID
+	for _, b := range r.batches {
+		for _, ref := range b {
+			ids = append(ids, ref.ID)
+		}
+	}
+	return ids
+}
+
+// rescoreServer returns a test server with destructive operations enabled
+// and rescorer wired in. It shares the JWT manager with testSrv so existing
+// tokens work.
+func rescoreServer(t *testing.T, rescorer server.ConflictRescorer) *httptest.Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	embedder := embedding.NewNoopProvider(1024)
+	decisionSvc := decisions.New(testDB, embedder, nil, logger, nil)
+	buf := trace.NewBuffer(testDB, logger, 1000, 50*time.Millisecond, nil)
+
+	srv := server.New(server.ServerConfig{
+		DB:                      testDB,
+		JWTMgr:                  testJWTMgr,
+		DecisionSvc:             decisionSvc,
+		Buffer:                  buf,
+		Logger:                  logger,
+		ReadTimeout:             30 * time.Second,
+		WriteTimeout:            30 * time.Second,
+		Version:                 "test",
+		MaxRequestBodyBytes:     1 * 1024 * 1024,
+		EnableDestructiveDelete: true,
+		ConflictRescorer:        rescorer,
+	})
+
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		_ = srv.Shutdown(context.Background())
+	})
+	return ts
+}
+
+// traceForRescore traces a decision of decisionType through testSrv and,
+// when embedded is set, backfills both embeddings so the decision is
+// eligible for rescoring.
+func traceForRescore(t *testing.T, decisionType, outcome string, embedded bool) uuid.UUID {
+	t.Helper()
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: decisionType,
+			Outcome:      outcome,
+			Confidence:   0.8,
+		},
+	})
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var result struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	id := result.Data.DecisionID
+
+	if embedded {
+		vec := make([]float32, 1024)
+		for i := range vec {
+			vec[i] = 0.01
+		}
+		emb := pgvector.NewVector(vec)
+		ctx := context.Background()
+		require.NoError(t, testDB.BackfillEmbedding(ctx, id, uuid.Nil, emb, ""))
+		require.NoError(t, testDB.BackfillOutcomeEmbedding(ctx, id, uuid.Nil, emb))
+	}
+	return id
+}
+
+// readRescoreProgress posts to the rescore endpoint and decodes every NDJSON
+// progress line in the response.
+func readRescoreProgress(t *testing.T, url string) []map[string]any {
+	t.Helper()
+	resp, err := authedRequest("POST", url, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestHandleRescoreConflicts_StreamsProgress(t *testing.T) {
+	decisionType := "rescore-" + uuid.NewString()[:8]
+	var want []uuid.UUID
+	for _, outcome := range []string{"use postgres", "use redis", "use kafka", "use nats", "use sqlite"} {
+		want = append(want, traceForRescore(t, decisionType, outcome, true))
+	}
+	// Not embedded, so FindDecisionsForRescore must skip it.
+	skipped := traceForRescore(t, decisionType, "use mongo", false)
+
+	rescorer := &recordingRescorer{}
+	ts := rescoreServer(t, rescorer)
+
+	lines := readRescoreProgress(t, ts.URL+"/v1/admin/conflicts/rescore?decision_type="+decisionType+"&batch_size=2")
+
+	// Three batches (2, 2, 1) followed by the summary line.
+	require.Len(t, lines, 4)
+	for i, scanned := range []float64{2, 4, 5} {
+		assert.Equal(t, float64(i+1), lines[i]["batch"], "line %d", i)
+		assert.Equal(t, scanned, lines[i]["scanned"], "line %d", i)
+		assert.Equal(t, scanned, lines[i]["processed"], "line %d", i)
+		assert.Equal(t, false, lines[i]["done"], "line %d", i)
+	}
+	final := lines[3]
+	assert.Equal(t, true, final["done"])
+	assert.Equal(t, float64(3), final["batch"])
+	assert.Equal(t, float64(5), final["scanned"])
+	assert.Equal(t, float64(5), final["processed"])
+	assert.NotContains(t, final, "truncated")
+	assert.NotContains(t, final, "error")
+
+	got := rescorer.ids()
+	assert.ElementsMatch(t, want, got)
+	assert.NotContains(t, got, skipped)
+}
+
+func TestHandleRescoreConflicts_LimitTruncates(t *testing.T) {
+	decisionType := "rescore-" + uuid.NewString()[:8]
+	for _, outcome := range []string{"use postgres", "use redis", "use kafka", "use nats"} {
+		traceForRescore(t, decisionType, outcome, true)
+	}
+
+	rescorer := &recordingRescorer{}
+	ts := rescoreServer(t, rescorer)
+
+	lines := readRescoreProgress(t, ts.URL+"/v1/admin/conflicts/rescore?decision_type="+decisionType+"&batch_size=2&limit=3")
+
+	require.Len(t, lines, 3)
+	final := lines[2]
+	assert.Equal(t, true, final["done"])
+	assert.Equal(t, true, final["truncated"])
+	assert.Equal(t, float64(3), final["scanned"])
+	assert.Equal(t, float64(3), final["processed"])
+	assert.Len(t, rescorer.ids(), 3)
+}
+
+func TestHandleRescoreConflicts_InvalidParams(t *testing.T) {
+	ts := rescoreServer(t, &recordingRescorer{})
+
+	for _, query := range []string{"batch_size=0", "batch_size=1001", "limit=0", "limit=100001"} {
+		resp, err := authedRequest("POST", ts.URL+"/v1/admin/conflicts/rescore?"+query, adminToken, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
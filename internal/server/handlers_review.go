@@ -0,0 +1,265 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/authz"
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// defaultReviewSLA applies when no SLA is configured.
+const defaultReviewSLA = 24 * time.Hour
+
+func reviewSLAOrDefault(d time.Duration) time.Duration {
+	if d <= 0 {
+		return defaultReviewSLA
+	}
+	return d
+}
+
+// routeReviewFlags assigns reviewers to the org's pending flags that have not
+// been routed yet, using the review_routing org setting. Write paths that
+// raise or depend on flags call it; flags raised by database triggers are
+// routed by the review background loop.
+func (h *Handlers) routeReviewFlags(ctx context.Context, orgID uuid.UUID) error {
+	settings, err := h.db.GetOrgSettings(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	_, err = h.db.RouteReviewFlags(ctx, orgID, settings.Settings.ReviewRouting)
+	return err
+}
+
+// HandleReviewQueue handles GET /v1/review-queue.
+// Lists decisions flagged for review that have not been reviewed, oldest
+// first. ?overdue=true restricts the list to flags older than the SLA, which
+// defaults to AKASHI_REVIEW_SLA and can be overridden with ?sla=.
+// ?assigned_to=me restricts it to flags routed to the caller; admins may
+// pass another reviewer's agent ID.
+func (h *Handlers) HandleReviewQueue(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	q := r.URL.Query()
+
+	filters := storage.ReviewQueueFilters{SLA: h.reviewSLA}
+	if v := q.Get("sla"); v != "" {
+		sla, err := time.ParseDuration(v)
+		if err != nil || sla <= 0 {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				"sla must be a positive duration (e.g. 24h, 90m)")
+			return
+		}
+		filters.SLA = sla
+	}
+	if v := q.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "overdue must be true or false")
+			return
+		}
+		filters.OverdueOnly = overdue
+	}
+	if v := q.Get("reason"); v != "" {
+		switch v {
+		case model.ReviewReasonLowConfidence, model.ReviewReasonConflict, model.ReviewReasonManual:
+			filters.Reason = v
+		default:
+			writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+				"reason must be one of: low_confidence, conflict, manual")
+			return
+		}
+	}
+	if v := q.Get("assigned_to"); v != "" {
+		switch {
+		case v == "me":
+			filters.AssignedTo = claims.AgentID
+		case v == claims.AgentID || model.RoleAtLeast(claims.Role, model.RoleAdmin):
+			filters.AssignedTo = v
+		default:
+			writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+				"only admins can list another reviewer's assignments")
+			return
+		}
+	}
+	limit := queryLimit(r, 50)
+	offset := queryOffset(r)
+
+	items, total, err := h.db.ListReviewQueue(r.Context(), orgID, filters, limit, offset)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list review queue", err)
+		return
+	}
+
+	preFilterCount := len(items)
+	granted, err := authz.LoadGrantedSet(r.Context(), h.db, claims, h.grantCache)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if granted != nil {
+		filtered := make([]model.ReviewQueueItem, 0, len(items))
+		for _, it := range items {
+			if granted[it.AgentID] {
+				filtered = append(filtered, it)
+			}
+		}
+		items = filtered
+	}
+	if items == nil {
+		items = []model.ReviewQueueItem{}
+	}
+
+	ptotal, hasMore := computePagination(len(items), preFilterCount, limit, offset, total)
+	writeListJSON(w, r, items, ptotal, hasMore, limit, offset)
+}
+
+// HandleFlagDecision handles POST /v1/decisions/{id}/flag (writer+).
+// Flags an active decision for review. Flagging a decision that is already
+// pending review keeps its original flagged_at, so the SLA clock is not reset.
+func (h *Handlers) HandleFlagDecision(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	decisionID, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision ID")
+		return
+	}
+
+	d, err := h.db.GetDecision(r.Context(), orgID, decisionID, storage.GetDecisionOpts{CurrentOnly: true})
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get decision", err)
+		return
+	}
+	ok, err := canAccessAgent(r.Context(), h.db, claims, d.AgentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this decision")
+		return
+	}
+
+	var req model.FlagDecisionRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+			handleDecodeError(w, r, err)
+			return
+		}
+	}
+
+	flag, err := h.db.FlagDecisionForReview(r.Context(), orgID, decisionID, claims.ActorID(), req.Note)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "decision not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to flag decision", err)
+		return
+	}
+
+	if flag.AssignedReviewer == nil {
+		if err := h.routeReviewFlags(r.Context(), orgID); err != nil {
+			h.writeInternalError(w, r, "failed to route review flags", err)
+			return
+		}
+		if flag, err = h.db.GetReviewFlag(r.Context(), orgID, decisionID); err != nil {
+			h.writeInternalError(w, r, "failed to read review flag", err)
+			return
+		}
+	}
+
+	writeJSON(w, r, http.StatusOK, flag)
+}
+
+// HandleReviewDecision handles POST /v1/decisions/{id}/review (admin+).
+// Marks a pending review flag as reviewed, removing it from the review queue.
+func (h *Handlers) HandleReviewDecision(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	decisionID, err := parsePathUUID(r, "id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid decision ID")
+		return
+	}
+
+	var req model.ReviewDecisionRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+			handleDecodeError(w, r, err)
+			return
+		}
+	}
+
+	flag, err := h.db.MarkDecisionReviewed(r.Context(), orgID, decisionID, claims.ActorID(), req.Note)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "no pending review for this decision")
+			return
+		}
+		h.writeInternalError(w, r, "failed to mark decision reviewed", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, flag)
+}
+
+// maxGateReviewItems caps the pending flags listed in a run review gate
+// rejection; the total is always reported.
+const maxGateReviewItems = 100
+
+// enforceRunReviewGate writes a 409 and returns false when the org's
+// run_review_gate policy forbids completing run because decisions in it still
+// have pending review flags. Orgs without a policy pay only the settings lookup.
+func (h *Handlers) enforceRunReviewGate(w http.ResponseWriter, r *http.Request, orgID uuid.UUID, run model.AgentRun) bool {
+	settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to load run review gate", err)
+		return false
+	}
+	policy := settings.Settings.RunReviewGate
+	if policy == nil || !policy.AppliesToAgent(run.AgentID) {
+		return true
+	}
+
+	// Route first so the rejection tells the caller who owns each flag. A
+	// routing failure only costs the assignments, not the gate decision.
+	if err := h.routeReviewFlags(r.Context(), orgID); err != nil {
+		h.logger.Warn("run review gate: route review flags failed", "error", err, "org_id", orgID)
+	}
+
+	pending, total, err := h.db.ListReviewQueue(r.Context(), orgID, storage.ReviewQueueFilters{
+		SLA:           h.reviewSLA,
+		RunID:         &run.ID,
+		DecisionTypes: policy.DecisionTypes,
+	}, maxGateReviewItems, 0)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to check pending reviews", err)
+		return false
+	}
+	if total == 0 {
+		return true
+	}
+
+	writeErrorDetails(w, r, http.StatusConflict, model.ErrCodeConflict,
+		fmt.Sprintf("run has %d flagged decision(s) awaiting review", total),
+		map[string]any{
+			"run_id":          run.ID,
+			"pending_reviews": pending,
+			"total":           total,
+		})
+	return false
+}
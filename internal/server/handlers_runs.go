@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"sync"
 	"sync/atomic"
@@ -156,6 +157,23 @@ func (h *Handlers) HandleAppendEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.eventSchemaValidation {
+		// Reject before the idempotency reservation and buffering so a
+		// malformed batch never reaches the event log.
+		settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+		if err != nil {
+			h.writeInternalError(w, r, "failed to load org event schemas", err)
+			return
+		}
+		for i, e := range req.Events {
+			if err := model.ValidateEventPayload(e.EventType, e.Payload, settings.Settings.EventSchemas); err != nil {
+				writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+					fmt.Sprintf("events[%d] (%s): %s", i, e.EventType, err))
+				return
+			}
+		}
+	}
+
 	idem, proceed := h.beginIdempotentWrite(w, r, orgID, run.AgentID, appendEventsEndpoint(runID), req)
 	if !proceed {
 		return
@@ -270,6 +288,13 @@ func (h *Handlers) HandleCompleteRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Re-completing a finalized run is an idempotent no-op, so only running
+	// runs are gated.
+	if status == model.RunStatusCompleted && run.Status == model.RunStatusRunning &&
+		!h.enforceRunReviewGate(w, r, orgID, run) {
+		return
+	}
+
 	idem, proceed := h.beginIdempotentWrite(w, r, orgID, run.AgentID, completeRunEndpoint(runID), req)
 	if !proceed {
 		return
@@ -643,7 +668,7 @@ func (h *Handlers) buildSingleEnrichment(
 	if d.ContentHash == "" {
 		entry.Integrity = enrichmentIntegrity{Status: "no_hash"}
 	} else {
-		valid := integrity.VerifyContentHash(d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+		valid := integrity.VerifyContentHashVersion(d.HashVersion, d.ContentHash, d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
 		status := "tampered"
 		if valid {
 			status = "verified"
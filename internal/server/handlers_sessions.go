@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// HandleStartSession handles POST /v1/sessions. It opens a session bound to
+// the agent (the caller's own unless an admin names another), closing any
+// session the agent already had open. Until the session is closed, traces
+// from the agent without an X-Akashi-Session header join it.
+func (h *Handlers) HandleStartSession(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.StartSessionRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+			handleDecodeError(w, r, err)
+			return
+		}
+	}
+	if req.AgentID == "" {
+		req.AgentID = claims.AgentID
+	}
+	if !model.RoleAtLeast(claims.Role, model.RoleAdmin) && req.AgentID != claims.AgentID {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "can only start sessions for your own agent_id")
+		return
+	}
+
+	session, err := h.db.StartAgentSession(r.Context(), orgID, req.AgentID)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "agent not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to start session", err)
+		return
+	}
+	writeJSON(w, r, http.StatusCreated, session)
+}
+
+// HandleCloseSession handles POST /v1/sessions/{session_id}/close. Later
+// traces from the agent no longer default to the session; an explicit
+// X-Akashi-Session header can still name it.
+func (h *Handlers) HandleCloseSession(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	sid, err := parsePathUUID(r, "session_id")
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "invalid session_id")
+		return
+	}
+
+	session, err := h.db.GetAgentSession(r.Context(), orgID, sid)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "session not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to get session", err)
+		return
+	}
+	if !model.RoleAtLeast(claims.Role, model.RoleAdmin) && session.AgentID != claims.AgentID {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "can only close your own agent's sessions")
+		return
+	}
+
+	session, err = h.db.CloseAgentSession(r.Context(), orgID, sid)
+	if err != nil {
+		if isNotFoundError(err) {
+			writeError(w, r, http.StatusNotFound, model.ErrCodeNotFound, "session not found")
+			return
+		}
+		h.writeInternalError(w, r, "failed to close session", err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, session)
+}
+
+// HandleAgentSessions handles GET /v1/agents/{agent_id}/sessions. It lists
+// the sessions the agent's current decisions were traced in, most recently
+// active first, with ?limit= and ?offset= pagination.
+func (h *Handlers) HandleAgentSessions(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+	agentID := r.PathValue("agent_id")
+	if err := model.ValidateAgentID(agentID); err != nil {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	ok, err := canAccessAgent(r.Context(), h.db, claims, agentID)
+	if err != nil {
+		h.writeInternalError(w, r, "authorization check failed", err)
+		return
+	}
+	if !ok {
+		writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden, "no access to this agent's history")
+		return
+	}
+
+	limit := queryLimit(r, 50)
+	offset := queryOffset(r)
+	sessions, total, err := h.db.ListSessions(r.Context(), orgID, agentID, limit, offset)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to list sessions", err)
+		return
+	}
+	writeListJSON(w, r, sessions, &total, offset+len(sessions) < total, limit, offset)
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/service/decisions"
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+// HandleTraceBatch handles POST /v1/trace/batch. Every trace is validated
+// before anything is written, then all of them are recorded in one
+// transaction: the batch commits in full or not at all. Errors attributable
+// to a single trace name it by its index in the request.
+func (h *Handlers) HandleTraceBatch(w http.ResponseWriter, r *http.Request) {
+	claims := ClaimsFromContext(r.Context())
+	orgID := OrgIDFromContext(r.Context())
+
+	var req model.TraceBatchRequest
+	if err := decodeJSON(w, r, &req, h.maxRequestBodyBytes); err != nil {
+		handleDecodeError(w, r, err)
+		return
+	}
+	if len(req.Traces) == 0 {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, "traces must contain at least one trace")
+		return
+	}
+	if len(req.Traces) > h.traceBatchMax {
+		writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput,
+			fmt.Sprintf("traces exceeds maximum batch size of %d", h.traceBatchMax))
+		return
+	}
+
+	isAdmin := model.RoleAtLeast(claims.Role, model.RoleAdmin)
+	for i := range req.Traces {
+		if err := ValidateTraceRequest(&req.Traces[i]); err != nil {
+			writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, i, err.Error())
+			return
+		}
+		if !isAdmin && req.Traces[i].AgentID != claims.AgentID {
+			writeTraceBatchError(w, r, http.StatusForbidden, model.ErrCodeForbidden, i, "can only trace for your own agent_id")
+			return
+		}
+	}
+
+	settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to load org settings", err)
+		return
+	}
+	for i, t := range req.Traces {
+		if err := settings.Settings.MinAlternatives.Check(t.Decision.DecisionType, len(t.Decision.Alternatives)); err != nil {
+			writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, i, err.Error())
+			return
+		}
+	}
+
+	// Resolve each distinct agent once, auto-registering for admin+ callers as
+	// HandleTrace does. Registration happens before the batch transaction, so
+	// an agent created here survives a batch that later rolls back.
+	agents := make(map[string]model.Agent)
+	agentCounts := make(map[string]int64)
+	var agentOrder []string
+	for i, t := range req.Traces {
+		agentCounts[t.AgentID]++
+		if _, ok := agents[t.AgentID]; ok {
+			continue
+		}
+		autoRegAudit := h.buildAuditEntry(r, orgID, "", "agent", t.AgentID, nil, nil, nil)
+		agent, err := h.decisionSvc.ResolveOrCreateAgent(r.Context(), orgID, t.AgentID, claims.Role, &autoRegAudit)
+		if err != nil {
+			if errors.Is(err, decisions.ErrAgentNotFound) {
+				writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, i, err.Error())
+				return
+			}
+			if errors.Is(err, decisions.ErrAgentTombstoned) {
+				writeTraceBatchError(w, r, http.StatusConflict, model.ErrCodeConflict, i, err.Error())
+				return
+			}
+			h.writeInternalError(w, r, "failed to resolve agent", err)
+			return
+		}
+		agents[t.AgentID] = agent
+		agentOrder = append(agentOrder, t.AgentID)
+	}
+	for _, agentID := range agentOrder {
+		if !h.enforceBatchDecisionQuota(w, r, orgID, agentID, settings.Settings.DecisionQuota, agentCounts[agentID], int64(len(req.Traces))) {
+			return
+		}
+	}
+
+	// Session from header (applies to every trace), else each agent's open session.
+	var headerSessionID *uuid.UUID
+	sessionHeader := r.Header.Get("X-Akashi-Session")
+	sessions := make(map[string]*uuid.UUID)
+	if sessionHeader != "" {
+		if sid, parseErr := uuid.Parse(sessionHeader); parseErr == nil {
+			headerSessionID = &sid
+		}
+	} else {
+		for _, agentID := range agentOrder {
+			sid, err := h.db.GetOpenAgentSessionID(r.Context(), orgID, agentID)
+			if err != nil {
+				h.writeInternalError(w, r, "failed to load agent session", err)
+				return
+			}
+			sessions[agentID] = sid
+		}
+	}
+
+	idemPayload := struct {
+		Request       model.TraceBatchRequest `json:"request"`
+		SessionHeader string                  `json:"session_header,omitempty"`
+		UserAgent     string                  `json:"user_agent,omitempty"`
+	}{
+		Request:       req,
+		SessionHeader: sessionHeader,
+		UserAgent:     r.Header.Get("User-Agent"),
+	}
+	idem, proceed := h.beginIdempotentWrite(w, r, orgID, claims.AgentID, "POST:/v1/trace/batch", idemPayload)
+	if !proceed {
+		return
+	}
+
+	inputs := make([]decisions.TraceInput, len(req.Traces))
+	for i, t := range req.Traces {
+		agentContext, projectErr := h.buildTraceAgentContext(r, orgID, claims, t, agents[t.AgentID])
+		if agentContext == nil {
+			h.clearIdempotentWrite(r, orgID, idem)
+			writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, i, projectErr)
+			return
+		}
+		sessionID := headerSessionID
+		if sessionHeader == "" {
+			sessionID = sessions[t.AgentID]
+		}
+		inputs[i] = decisions.TraceInput{
+			AgentID:         t.AgentID,
+			TraceID:         t.TraceID,
+			Metadata:        t.Metadata,
+			Decision:        t.Decision,
+			PrecedentRef:    t.PrecedentRef,
+			PrecedentReason: t.PrecedentReason,
+			SupersedesID:    t.SupersedesID,
+			SessionID:       sessionID,
+			AgentContext:    agentContext,
+			ContextSnapshot: t.ContextSnapshot,
+			APIKeyID:        claims.APIKeyID,
+			Namespace:       NamespaceFromContext(r.Context()),
+			AuditMeta:       h.buildAuditMeta(r, orgID),
+
+			SupersedeMatchKeys: supersedeMatchKeys(t.SupersedeMatching),
+		}
+	}
+
+	results, err := h.decisionSvc.TraceBatch(r.Context(), orgID, inputs)
+	if err != nil {
+		h.clearIdempotentWrite(r, orgID, idem)
+		var batchErr *storage.TraceBatchError
+		if errors.As(err, &batchErr) {
+			t := req.Traces[batchErr.Index]
+			switch {
+			case errors.Is(err, decisions.ErrContextSnapshotTooLarge):
+				writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, batchErr.Index, batchErr.Err.Error())
+				return
+			case t.SupersedesID != nil && (errors.Is(err, storage.ErrNotFound) || isForeignKeyViolation(err)):
+				writeTraceBatchError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, batchErr.Index,
+					"superseded decision not found or already superseded")
+				return
+			case t.SupersedeMatching != nil && errors.Is(err, storage.ErrNotFound):
+				writeTraceBatchError(w, r, http.StatusConflict, model.ErrCodeConflict, batchErr.Index,
+					"matched decision was superseded concurrently; retry the batch")
+				return
+			}
+		}
+		h.writeInternalError(w, r, "failed to create trace batch", err)
+		return
+	}
+
+	traced := make([]model.Decision, len(results))
+	resp := make([]model.TraceBatchResult, len(results))
+	for i, result := range results {
+		traced[i] = result.Decision
+		resp[i] = model.TraceBatchResult{
+			Index:      i,
+			RunID:      result.RunID,
+			DecisionID: result.DecisionID,
+		}
+	}
+	h.fireDecisionTraced(traced...)
+
+	h.completeIdempotentWriteBestEffort(r, orgID, idem, http.StatusCreated, resp)
+	writeJSON(w, r, http.StatusCreated, resp)
+}
+
+// writeTraceBatchError writes an error for the trace at index, prefixing the
+// message with its position and echoing the index in the error details.
+func writeTraceBatchError(w http.ResponseWriter, r *http.Request, status int, code string, index int, message string) {
+	writeErrorDetails(w, r, status, code, fmt.Sprintf("traces[%d]: %s", index, message), map[string]any{
+		"index": index,
+	})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// builtinIncludes is what each read endpoint includes when neither the
+// request nor the org's default_includes setting says otherwise.
+var builtinIncludes = map[string][]string{
+	model.IncludeEndpointGetDecision:     {"alternatives", "evidence"},
+	model.IncludeEndpointDecisionsRecent: nil,
+	model.IncludeEndpointQuery:           nil,
+}
+
+// parseIncludeParam splits a comma-separated ?include= value. explicit is
+// false when the parameter is absent, so the endpoint default applies.
+func parseIncludeParam(r *http.Request) (values []string, explicit bool) {
+	if !r.URL.Query().Has("include") {
+		return nil, false
+	}
+	for _, v := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, true
+}
+
+// resolveIncludes returns the includes to apply for endpoint. An explicit
+// request wins (include=none yields nothing); otherwise the org's
+// default_includes setting applies, then the built-in default. Values the
+// endpoint does not accept are dropped. Every read path that supports
+// includes resolves them here so configured defaults apply consistently.
+//
+// The settings lookup only happens for requests without an explicit include,
+// and a failed lookup falls back to the built-in default rather than failing
+// the read.
+func (h *Handlers) resolveIncludes(r *http.Request, orgID uuid.UUID, endpoint string, requested []string, explicit bool) []string {
+	values := requested
+	if !explicit {
+		values = builtinIncludes[endpoint]
+		settings, err := h.db.GetOrgSettings(r.Context(), orgID)
+		if err != nil {
+			h.logger.Warn("default includes: failed to load org settings, using built-in default",
+				"org_id", orgID, "endpoint", endpoint, "error", err)
+		} else if policy := settings.Settings.DefaultIncludes; policy != nil {
+			if configured := policy.For(endpoint); configured != nil {
+				values = configured
+			}
+		}
+	}
+
+	resolved := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == model.IncludeNone {
+			return nil
+		}
+		if slices.Contains(model.IncludeOptions[endpoint], v) && !slices.Contains(resolved, v) {
+			resolved = append(resolved, v)
+		}
+	}
+	return resolved
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -91,6 +92,36 @@ func OrgIDFromContext(ctx context.Context) uuid.UUID {
 	return ctxutil.OrgIDFromContext(ctx)
 }
 
+// NamespaceFromContext returns the decision namespace resolved for the request.
+// Delegates to ctxutil so MCP tools can use the same accessor.
+func NamespaceFromContext(ctx context.Context) string {
+	return ctxutil.NamespaceFromContext(ctx)
+}
+
+// resolveNamespace picks the decision namespace for an authenticated request.
+// A namespace claim in the token is authoritative: the X-Akashi-Namespace
+// header may repeat it but cannot override it (403). Otherwise the header
+// selects the namespace, defaulting to model.DefaultNamespace. The returned
+// status is only meaningful when err is non-nil.
+func resolveNamespace(claims *auth.Claims, header string) (string, int, error) {
+	header = strings.TrimSpace(header)
+	if header != "" {
+		if err := model.ValidateNamespace(header); err != nil {
+			return "", http.StatusBadRequest, fmt.Errorf("X-Akashi-Namespace: %w", err)
+		}
+	}
+	if claims.Namespace != "" {
+		if header != "" && header != claims.Namespace {
+			return "", http.StatusForbidden, fmt.Errorf("token is restricted to namespace %q", claims.Namespace)
+		}
+		return claims.Namespace, 0, nil
+	}
+	if header != "" {
+		return header, 0, nil
+	}
+	return model.DefaultNamespace, 0, nil
+}
+
 // requestIDMiddleware assigns a unique request ID to each request.
 // Client-supplied IDs are accepted if they are reasonable length (≤128 chars)
 // and contain only printable ASCII. Otherwise, a fresh UUID is generated.
@@ -381,7 +412,18 @@ func authMiddleware(jwtMgr *auth.JWTManager, db *storage.DB, next http.Handler)
 			return
 		}
 
+		ns, status, err := resolveNamespace(claims, r.Header.Get("X-Akashi-Namespace"))
+		if err != nil {
+			code := model.ErrCodeInvalidInput
+			if status == http.StatusForbidden {
+				code = model.ErrCodeForbidden
+			}
+			writeError(w, r, status, code, err.Error())
+			return
+		}
+
 		ctx := ctxutil.WithClaims(r.Context(), claims)
+		ctx = ctxutil.WithNamespace(ctx, ns)
 
 		// Update last_seen (agent) and last_used_at (key) asynchronously.
 		// Best-effort — uses a bounded channel to prevent unbounded goroutine
@@ -472,6 +514,7 @@ func verifyAPIKey(ctx context.Context, db *storage.DB, credential, orgHeader str
 		if err != nil {
 			continue
 		}
+		upgradeAPIKeyHash(ctx, db, apiKey, k.KeyHash, k.OrgID, &k.ID, agent.ID)
 		claims := &auth.Claims{
 			AgentID:  agent.AgentID,
 			OrgID:    agent.OrgID,
@@ -513,6 +556,7 @@ func verifyAPIKey(ctx context.Context, db *storage.DB, credential, orgHeader str
 		if verr != nil || !valid {
 			continue
 		}
+		upgradeAPIKeyHash(ctx, db, apiKey, *a.APIKeyHash, a.OrgID, nil, a.ID)
 		claims := &auth.Claims{
 			AgentID: a.AgentID,
 			OrgID:   a.OrgID,
@@ -529,6 +573,30 @@ func verifyAPIKey(ctx context.Context, db *storage.DB, credential, orgHeader str
 	return nil, fmt.Errorf("invalid credentials")
 }
 
+// upgradeAPIKeyHash re-hashes a just-verified API key when its stored hash
+// predates the current hash algorithm or cost, so raising the cost upgrades
+// credentials as agents authenticate. keyID selects a managed key; nil means
+// the legacy agents.api_key_hash of agentUUID. Failures are logged and the
+// old hash stays valid.
+func upgradeAPIKeyHash(ctx context.Context, db *storage.DB, apiKey, oldHash string, orgID uuid.UUID, keyID *uuid.UUID, agentUUID uuid.UUID) {
+	if !auth.NeedsRehash(oldHash) {
+		return
+	}
+	newHash, err := auth.HashAPIKey(apiKey)
+	if err != nil {
+		slog.Warn("failed to re-hash api key", "org_id", orgID, "error", err)
+		return
+	}
+	if keyID != nil {
+		err = db.UpdateAPIKeyHash(ctx, orgID, *keyID, oldHash, newHash)
+	} else {
+		err = db.UpdateAgentAPIKeyHash(ctx, orgID, agentUUID, oldHash, newHash)
+	}
+	if err != nil {
+		slog.Warn("failed to store re-hashed api key", "org_id", orgID, "error", err)
+	}
+}
+
 // requireRole returns middleware that enforces a minimum role level.
 // Uses the role hierarchy: admin > agent > reader.
 func requireRole(minRole model.AgentRole) func(http.Handler) http.Handler {
@@ -567,10 +635,16 @@ func writeJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
 
 // writeError writes a JSON error response with the standard envelope.
 func writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	writeErrorDetails(w, r, status, code, message, nil)
+}
+
+// writeErrorDetails is writeError with a structured details payload for
+// errors the client can act on (e.g. which quota was exceeded).
+func writeErrorDetails(w http.ResponseWriter, r *http.Request, status int, code, message string, details any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(model.APIError{
-		Error: model.ErrorDetail{Code: code, Message: message},
+		Error: model.ErrorDetail{Code: code, Message: message, Details: details},
 		Meta: model.ResponseMeta{
 			RequestID: RequestIDFromContext(r.Context()),
 			Timestamp: time.Now().UTC(),
@@ -582,6 +656,18 @@ func writeError(w http.ResponseWriter, r *http.Request, status int, code, messag
 	}
 }
 
+// writeValidationError writes a 400 invalid_input response for err. When err
+// is a model.ValidationErrors, every field problem is listed in details so
+// the client can fix them together.
+func writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var verrs model.ValidationErrors
+	if errors.As(err, &verrs) {
+		writeErrorDetails(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error(), verrs)
+		return
+	}
+	writeError(w, r, http.StatusBadRequest, model.ErrCodeInvalidInput, err.Error())
+}
+
 // writeInternalError logs the underlying error and writes a generic 500 response.
 // This ensures every internal server error is visible in server logs for debugging,
 // without leaking internal details to the client.
@@ -659,14 +745,102 @@ func retryAfterSeconds(res ratelimit.Result) string {
 	return "1"
 }
 
+// claimsRateLimiter applies the rate limit policy for authenticated callers:
+// per-API-key (or per-agent) buckets within the org, the org's own limit
+// (resolved through orgLimits) in place of the global default when the
+// limiter supports overrides, and, when agentLimit has a positive RPS, a cap
+// on each agent across all of its API keys. Platform admins bypass it. On
+// limiter error the call is permitted (fail-open); on org limit lookup error
+// the global default applies. Shared by the HTTP middleware and gRPC.
+type claimsRateLimiter struct {
+	limiter      ratelimit.Limiter
+	orgLimits    *orgRateLimits
+	agentLimiter ratelimit.LimitOverrider // nil when there is no per-agent cap
+	agentLimit   ratelimit.Limit
+	logger       *slog.Logger
+}
+
+func newClaimsRateLimiter(limiter ratelimit.Limiter, orgLimits *orgRateLimits, agentLimit ratelimit.Limit, logger *slog.Logger) *claimsRateLimiter {
+	agentLimiter, _ := limiter.(ratelimit.LimitOverrider)
+	if agentLimit.RPS <= 0 {
+		agentLimiter = nil
+	}
+	return &claimsRateLimiter{
+		limiter:      limiter,
+		orgLimits:    orgLimits,
+		agentLimiter: agentLimiter,
+		agentLimit:   agentLimit,
+		logger:       logger,
+	}
+}
+
+// allow charges one call to claims' buckets. It returns the result to report
+// to the caller and, when the call is denied, a non-empty message.
+func (rl *claimsRateLimiter) allow(ctx context.Context, claims *auth.Claims) (ratelimit.Result, string) {
+	if claims.Role == model.RolePlatformAdmin {
+		return ratelimit.Result{Allowed: true}, ""
+	}
+
+	// Use per-key rate limiting when a managed API key is identified,
+	// otherwise fall back to per-agent limiting.
+	var key string
+	if claims.APIKeyID != nil {
+		key = "org:" + claims.OrgID.String() + ":key:" + claims.APIKeyID.String()
+	} else {
+		key = "org:" + claims.OrgID.String() + ":agent:" + claims.AgentID
+	}
+	override, lookupErr := rl.orgLimits.get(ctx, claims.OrgID)
+	if lookupErr != nil {
+		rl.logger.Warn("org rate limit lookup failed, using global default",
+			"error", lookupErr,
+			"org_id", claims.OrgID,
+			"request_id", RequestIDFromContext(ctx))
+	}
+	var res ratelimit.Result
+	var err error
+	if o, ok := rl.limiter.(ratelimit.LimitOverrider); ok && override != nil {
+		res, err = o.AllowLimit(ctx, key, ratelimit.Limit{RPS: override.RPS, Burst: override.Burst})
+	} else {
+		res, err = rl.limiter.Allow(ctx, key)
+	}
+	if err != nil {
+		// Fail-open: a broken limiter should not block all traffic.
+		rl.logger.Warn("rate limiter error, permitting request",
+			"error", err,
+			"key", key,
+			"request_id", RequestIDFromContext(ctx))
+		return ratelimit.Result{Allowed: true}, ""
+	}
+	if !res.Allowed {
+		return res, "rate limit exceeded"
+	}
+
+	// The per-agent cap uses its own key: buckets are keyed by key alone,
+	// and the per-key bucket above may already be "org:<id>:agent:<id>"
+	// under a different limit.
+	if rl.agentLimiter != nil {
+		agentKey := "org:" + claims.OrgID.String() + ":agent-cap:" + claims.AgentID
+		agentRes, err := rl.agentLimiter.AllowLimit(ctx, agentKey, rl.agentLimit)
+		if err != nil {
+			rl.logger.Warn("rate limiter error, permitting request",
+				"error", err,
+				"key", agentKey,
+				"request_id", RequestIDFromContext(ctx))
+		} else if !agentRes.Allowed {
+			return agentRes, "agent rate limit exceeded"
+		}
+	}
+	return res, ""
+}
+
 // rateLimitMiddleware enforces per-key rate limiting on all requests.
-// Unauthenticated paths use IP-based keys; authenticated paths use
-// per-agent or per-API-key keys. Platform admins bypass rate limiting.
-// On limiter error, the request is permitted (fail-open).
+// Unauthenticated paths use IP-based keys; authenticated paths go through
+// the claimsRateLimiter built from limiter, orgLimits and agentLimit.
 //
 // All responses (both allowed and denied) include X-RateLimit-* headers
 // so clients can implement proactive throttling.
-func rateLimitMiddleware(limiter ratelimit.Limiter, logger *slog.Logger, trustProxy bool, next http.Handler) http.Handler {
+func rateLimitMiddleware(limiter ratelimit.Limiter, orgLimits *orgRateLimits, agentLimit ratelimit.Limit, logger *slog.Logger, trustProxy bool, next http.Handler) http.Handler {
+	rl := newClaimsRateLimiter(limiter, orgLimits, agentLimit, logger)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		claims := ctxutil.ClaimsFromContext(r.Context())
 		if claims == nil {
@@ -686,75 +860,145 @@ func rateLimitMiddleware(limiter ratelimit.Limiter, logger *slog.Logger, trustPr
 			return
 		}
 
-		// Platform admins bypass rate limiting.
-		if claims.Role == model.RolePlatformAdmin {
-			next.ServeHTTP(w, r)
-			return
-		}
-
-		// Use per-key rate limiting when a managed API key is identified,
-		// otherwise fall back to per-agent limiting.
-		var key string
-		if claims.APIKeyID != nil {
-			key = "org:" + claims.OrgID.String() + ":key:" + claims.APIKeyID.String()
-		} else {
-			key = "org:" + claims.OrgID.String() + ":agent:" + claims.AgentID
-		}
-		res, err := limiter.Allow(r.Context(), key)
-		if err != nil {
-			// Fail-open: a broken limiter should not block all traffic.
-			logger.Warn("rate limiter error, permitting request",
-				"error", err,
-				"key", key,
-				"request_id", RequestIDFromContext(r.Context()))
-			next.ServeHTTP(w, r)
-			return
-		}
+		res, denied := rl.allow(r.Context(), claims)
 		setRateLimitHeaders(w, res)
-		if !res.Allowed {
+		if denied != "" {
 			w.Header().Set("Retry-After", retryAfterSeconds(res))
-			writeError(w, r, http.StatusTooManyRequests, model.ErrCodeRateLimited, "rate limit exceeded")
+			writeError(w, r, http.StatusTooManyRequests, model.ErrCodeRateLimited, denied)
 			return
 		}
-
 		next.ServeHTTP(w, r)
 	})
 }
 
-// corsMiddleware handles CORS preflight requests and sets response headers.
-// Only origins listed in allowedOrigins are reflected. A single entry of "*"
-// permits any origin (suitable for development or APIs using only bearer tokens).
+// CORSPolicy is the cross-origin policy for one origin. Origin "*" applies to
+// any origin without its own policy. Empty Methods or Headers fall back to the
+// defaults used for AKASHI_CORS_ALLOWED_ORIGINS entries.
+type CORSPolicy struct {
+	Origin           string
+	Methods          []string
+	Headers          []string
+	AllowCredentials bool
+}
+
+const (
+	corsDefaultMethods = "GET, POST, DELETE, PATCH, OPTIONS"
+	corsDefaultHeaders = "Authorization, Content-Type, X-Request-ID, Idempotency-Key, X-Akashi-Session, X-Akashi-Org-ID, X-Akashi-Namespace"
+)
+
+// resolvedCORSPolicy is a CORSPolicy with its response headers pre-rendered.
+// methods is nil for default policies, which advertise the default methods
+// but do not restrict them.
+type resolvedCORSPolicy struct {
+	methods          map[string]bool
+	allowMethods     string
+	allowHeaders     string
+	allowCredentials bool
+}
+
+func resolveCORSPolicy(p CORSPolicy) *resolvedCORSPolicy {
+	rp := &resolvedCORSPolicy{
+		allowMethods:     corsDefaultMethods,
+		allowHeaders:     corsDefaultHeaders,
+		allowCredentials: p.AllowCredentials,
+	}
+	if len(p.Methods) > 0 {
+		methods := make([]string, 0, len(p.Methods)+1)
+		for _, m := range p.Methods {
+			methods = append(methods, strings.ToUpper(m))
+		}
+		if !slices.Contains(methods, http.MethodOptions) {
+			methods = append(methods, http.MethodOptions)
+		}
+		rp.allowMethods = strings.Join(methods, ", ")
+		rp.methods = make(map[string]bool, len(methods)+1)
+		for _, m := range methods {
+			rp.methods[m] = true
+		}
+		if rp.methods[http.MethodGet] {
+			rp.methods[http.MethodHead] = true
+		}
+	}
+	if len(p.Headers) > 0 {
+		rp.allowHeaders = strings.Join(p.Headers, ", ")
+	}
+	return rp
+}
+
+// corsMiddleware handles CORS for a flat origin allowlist. A single entry of
+// "*" permits any origin (suitable for development or APIs using only bearer
+// tokens). It is shorthand for corsPolicyMiddleware with default policies.
 func corsMiddleware(allowedOrigins []string, next http.Handler) http.Handler {
-	// Pre-compute a set for O(1) lookup.
-	originSet := make(map[string]bool, len(allowedOrigins))
-	allowAll := false
+	return corsPolicyMiddleware(allowedOrigins, nil, next)
+}
+
+// corsPolicyMiddleware handles CORS preflight requests and sets response
+// headers from per-origin policies. Flat allowedOrigins entries get the default
+// policy; a structured policy for the same origin takes precedence. Only
+// matched origins are reflected.
+//
+// Explicit method lists are enforced, not just advertised: a preflight for a
+// disallowed method gets no CORS headers, and a cross-origin request that
+// skips preflight (e.g. a simple form POST) with a disallowed method is
+// rejected with 403.
+func corsPolicyMiddleware(allowedOrigins []string, policies []CORSPolicy, next http.Handler) http.Handler {
+	byOrigin := make(map[string]*resolvedCORSPolicy, len(allowedOrigins)+len(policies))
 	for _, o := range allowedOrigins {
-		if o == "*" {
-			allowAll = true
-			break
-		}
-		originSet[o] = true
+		byOrigin[o] = resolveCORSPolicy(CORSPolicy{Origin: o})
 	}
+	for _, p := range policies {
+		byOrigin[p.Origin] = resolveCORSPolicy(p)
+	}
+	wildcard := byOrigin["*"]
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
 		// Always set Vary: Origin so caches know the response varies by origin,
 		// even when the request origin doesn't match the allowlist.
 		w.Header().Set("Vary", "Origin")
-		if origin != "" && (allowAll || originSet[origin]) {
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Request-ID, Idempotency-Key, X-Akashi-Session, X-Akashi-Org-ID")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, PATCH, OPTIONS")
-			w.Header().Set("Access-Control-Max-Age", "86400")
+
+		var policy *resolvedCORSPolicy
+		if origin != "" {
+			if policy = byOrigin[origin]; policy == nil {
+				policy = wildcard
+			}
 		}
+
 		if r.Method == http.MethodOptions {
+			reqMethod := r.Header.Get("Access-Control-Request-Method")
+			if policy != nil && (reqMethod == "" || policy.allows(strings.ToUpper(reqMethod))) {
+				setCORSHeaders(w, origin, policy)
+			}
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
+
+		if policy != nil {
+			if !policy.allows(r.Method) {
+				writeError(w, r, http.StatusForbidden, model.ErrCodeForbidden,
+					fmt.Sprintf("method %s is not allowed for origin %s", r.Method, origin))
+				return
+			}
+			setCORSHeaders(w, origin, policy)
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+func (p *resolvedCORSPolicy) allows(method string) bool {
+	return p.methods == nil || p.methods[method]
+}
+
+func setCORSHeaders(w http.ResponseWriter, origin string, p *resolvedCORSPolicy) {
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Headers", p.allowHeaders)
+	w.Header().Set("Access-Control-Allow-Methods", p.allowMethods)
+	w.Header().Set("Access-Control-Max-Age", "86400")
+	if p.allowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
 // securityHeadersMiddleware adds standard security response headers.
 func securityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -845,6 +1089,92 @@ func (g *gzipResponseWriter) WriteHeader(statusCode int) {
 	g.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap returns the underlying ResponseWriter so http.ResponseController can
+// reach the connection (e.g. to adjust write deadlines on gzipped streams).
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// RouteTimeout overrides the server's read and/or write timeout for one mux
+// route pattern, e.g. "GET /v1/export/decisions". A nil duration keeps the
+// global value; zero removes the deadline entirely.
+type RouteTimeout struct {
+	Route        string
+	ReadTimeout  *time.Duration
+	WriteTimeout *time.Duration
+}
+
+// defaultRouteTimeouts are applied before operator overrides. Streaming
+// endpoints can legitimately run far past the global WriteTimeout, so their
+// write deadline is removed (the import stream also loses its read
+// deadline); token issuance is a small, fast request and gets a tighter
+// bound than the global default.
+func defaultRouteTimeouts() []RouteTimeout {
+	unlimited := time.Duration(0)
+	tokenTimeout := 10 * time.Second
+	return []RouteTimeout{
+		{Route: "GET /v1/export/decisions", WriteTimeout: &unlimited},
+		{Route: "POST /v1/import/decisions", ReadTimeout: &unlimited, WriteTimeout: &unlimited},
+		{Route: "GET /v1/export/conflicts", WriteTimeout: &unlimited},
+		{Route: "POST /v1/admin/conflicts/rescore", WriteTimeout: &unlimited},
+		{Route: "POST /v1/admin/reembed", WriteTimeout: &unlimited},
+		{Route: "POST /auth/token", ReadTimeout: &tokenTimeout, WriteTimeout: &tokenTimeout},
+	}
+}
+
+// resolveRouteTimeouts merges overrides onto the defaults, keyed by route.
+// An override replaces only the timeouts it sets.
+func resolveRouteTimeouts(overrides []RouteTimeout) map[string]RouteTimeout {
+	out := make(map[string]RouteTimeout)
+	for _, rt := range append(defaultRouteTimeouts(), overrides...) {
+		merged := out[rt.Route]
+		merged.Route = rt.Route
+		if rt.ReadTimeout != nil {
+			merged.ReadTimeout = rt.ReadTimeout
+		}
+		if rt.WriteTimeout != nil {
+			merged.WriteTimeout = rt.WriteTimeout
+		}
+		out[rt.Route] = merged
+	}
+	return out
+}
+
+// routeTimeoutMiddleware resets the connection's read/write deadlines for
+// routes with a timeout override. The route is resolved with mux.Handler so
+// overrides are keyed by the same pattern the route was registered with.
+// Deadlines are relative to when the handler starts; routes without an
+// override keep the http.Server's global ReadTimeout/WriteTimeout.
+func routeTimeoutMiddleware(mux *http.ServeMux, timeouts map[string]RouteTimeout, logger *slog.Logger, next http.Handler) http.Handler {
+	if len(timeouts) == 0 {
+		return next
+	}
+	deadline := func(now time.Time, d time.Duration) time.Time {
+		if d == 0 {
+			return time.Time{} // Zero time clears the deadline.
+		}
+		return now.Add(d)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if rt, ok := timeouts[pattern]; ok {
+			rc := http.NewResponseController(w)
+			now := time.Now()
+			if rt.ReadTimeout != nil {
+				if err := rc.SetReadDeadline(deadline(now, *rt.ReadTimeout)); err != nil {
+					logger.Debug("route timeout: set read deadline failed", "route", pattern, "error", err)
+				}
+			}
+			if rt.WriteTimeout != nil {
+				if err := rc.SetWriteDeadline(deadline(now, *rt.WriteTimeout)); err != nil {
+					logger.Debug("route timeout: set write deadline failed", "route", pattern, "error", err)
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // errBodyTooLarge is returned by decodeJSON when the request body exceeds maxBytes.
 // Callers must respond with 413 Request Entity Too Large, not 400 Bad Request.
 var errBodyTooLarge = errors.New("request body too large")
@@ -397,6 +397,101 @@ func TestCorsMiddleware(t *testing.T) {
 	})
 }
 
+func TestCorsPolicyMiddleware(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := corsPolicyMiddleware(
+		[]string{"https://app.example.com", "https://partner.example.com"},
+		[]CORSPolicy{
+			{Origin: "https://partner.example.com", Methods: []string{"get"}, Headers: []string{"Authorization"}},
+			{Origin: "https://console.example.com", AllowCredentials: true},
+		},
+		inner,
+	)
+	preflight := func(origin, method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("OPTIONS", "/v1/trace", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("flat-list origin gets default policy", func(t *testing.T) {
+		rec := preflight("https://app.example.com", "POST")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, corsDefaultMethods, rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, corsDefaultHeaders, rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("structured policy overrides flat entry", func(t *testing.T) {
+		rec := preflight("https://partner.example.com", "GET")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://partner.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, OPTIONS", rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization", rec.Header().Get("Access-Control-Allow-Headers"))
+	})
+
+	t.Run("preflight for disallowed method gets no CORS headers", func(t *testing.T) {
+		rec := preflight("https://partner.example.com", "POST")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("simple request with disallowed method is rejected", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/v1/trace", nil)
+		req.Header.Set("Origin", "https://partner.example.com")
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("allowed method passes through", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/v1/decisions/recent", nil)
+		req.Header.Set("Origin", "https://partner.example.com")
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://partner.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("credentials only where the policy allows them", func(t *testing.T) {
+		rec := preflight("https://console.example.com", "PATCH")
+		assert.Equal(t, "https://console.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("unlisted origin gets nothing", func(t *testing.T) {
+		rec := preflight("https://evil.example.com", "GET")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("exact policy wins over wildcard", func(t *testing.T) {
+		h := corsPolicyMiddleware([]string{"*"}, []CORSPolicy{
+			{Origin: "https://partner.example.com", Methods: []string{"GET"}},
+		}, inner)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest("OPTIONS", "/v1/trace", nil)
+		req.Header.Set("Origin", "https://partner.example.com")
+		req.Header.Set("Access-Control-Request-Method", "DELETE")
+		h.ServeHTTP(rec, req)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest("OPTIONS", "/v1/trace", nil)
+		req.Header.Set("Origin", "https://other.example.com")
+		req.Header.Set("Access-Control-Request-Method", "DELETE")
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, "https://other.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
 // --- securityHeadersMiddleware ---
 
 func TestSecurityHeadersMiddleware(t *testing.T) {
@@ -1016,7 +1111,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	// Simulate 3 rapid requests from the same IP.
 	for i := range 3 {
@@ -1050,7 +1145,7 @@ func TestRateLimitMiddleware_DifferentIPs(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	// First request from IP A should succeed.
 	rec1 := httptest.NewRecorder()
@@ -1084,7 +1179,7 @@ func TestRateLimitMiddleware_PlatformAdminBypass(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	claims := &auth.Claims{
 		AgentID: "superadmin",
@@ -1112,7 +1207,7 @@ func TestRateLimitMiddleware_AuthenticatedPerAgent(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	orgID := uuid.New()
 	claimsA := &auth.Claims{AgentID: "agent-a", Role: model.RoleAgent, OrgID: orgID}
@@ -1149,7 +1244,7 @@ func TestRateLimitMiddleware_AuthenticatedPerAPIKey(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	orgID := uuid.New()
 	keyID := uuid.New()
@@ -1185,7 +1280,7 @@ func TestRateLimitMiddleware_XForwardedFor(t *testing.T) {
 	})
 
 	// With trustProxy=true, rate limit key uses XFF client IP.
-	handler := rateLimitMiddleware(limiter, logger, true, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, true, inner)
 
 	// First request from client IP via XFF: allowed.
 	rec := httptest.NewRecorder()
@@ -1204,6 +1299,159 @@ func TestRateLimitMiddleware_XForwardedFor(t *testing.T) {
 	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
 }
 
+func TestRateLimitMiddleware_PerAgentCap(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter(100, 100) // per-key default, never the binding limit here
+	defer func() { _ = limiter.Close() }()
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{RPS: 0.01, Burst: 2}, quietLogger(), false, inner)
+
+	orgID := uuid.New()
+	call := func(agentID string, apiKeyID uuid.UUID) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/v1/decisions", nil)
+		claims := &auth.Claims{AgentID: agentID, OrgID: orgID, Role: model.RoleAgent, APIKeyID: &apiKeyID}
+		req = req.WithContext(ctxutil.WithClaims(req.Context(), claims))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Agent A spreads requests across two API keys; the cap spans both.
+	keyA1, keyA2 := uuid.New(), uuid.New()
+	assert.Equal(t, http.StatusOK, call("agent-a", keyA1).Code)
+	assert.Equal(t, http.StatusOK, call("agent-a", keyA2).Code)
+	rec := call("agent-a", keyA1)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Agent B in the same org is not throttled by agent A's exhaustion.
+	keyB := uuid.New()
+	assert.Equal(t, http.StatusOK, call("agent-b", keyB).Code)
+	assert.Equal(t, http.StatusOK, call("agent-b", keyB).Code)
+}
+
+func TestServerRateLimit_SharesHTTPBuckets(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter(0.01, 2)
+	defer func() { _ = limiter.Close() }()
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, quietLogger(), false, inner)
+	srv := &Server{claimsLimiter: newClaimsRateLimiter(limiter, nil, ratelimit.Limit{}, quietLogger())}
+
+	claims := &auth.Claims{AgentID: "agent-a", OrgID: uuid.New(), Role: model.RoleAgent}
+	httpCall := func() int {
+		req := httptest.NewRequest("GET", "/v1/decisions", nil)
+		req = req.WithContext(ctxutil.WithClaims(req.Context(), claims))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, httpCall())
+	res, denied := srv.RateLimit(context.Background(), claims)
+	assert.Empty(t, denied, "second call of a burst of 2 is allowed")
+	assert.Equal(t, 2, res.Limit)
+
+	// Both transports drew from the same bucket, so both are now denied.
+	assert.Equal(t, http.StatusTooManyRequests, httpCall())
+	res, denied = srv.RateLimit(context.Background(), claims)
+	assert.Equal(t, "rate limit exceeded", denied)
+	assert.False(t, res.Allowed)
+
+	admin := &auth.Claims{AgentID: "admin", OrgID: claims.OrgID, Role: model.RolePlatformAdmin}
+	_, denied = srv.RateLimit(context.Background(), admin)
+	assert.Empty(t, denied, "platform admins bypass rate limiting")
+
+	_, denied = (&Server{}).RateLimit(context.Background(), claims)
+	assert.Empty(t, denied, "no limiter configured allows every call")
+}
+
+func TestRateLimitMiddleware_PerOrgLimits(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter(100, 100) // global default
+	defer func() { _ = limiter.Close() }()
+
+	orgA, orgB, orgC := uuid.New(), uuid.New(), uuid.New()
+	lookups := 0
+	limits := newOrgRateLimits(func(_ context.Context, orgID uuid.UUID) (*model.OrgRateLimit, error) {
+		lookups++
+		switch orgID {
+		case orgA:
+			return &model.OrgRateLimit{RPS: 0.01, Burst: 1}, nil
+		case orgB:
+			return &model.OrgRateLimit{RPS: 0.01, Burst: 3}, nil
+		}
+		return nil, nil
+	}, time.Minute)
+
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := rateLimitMiddleware(limiter, limits, ratelimit.Limit{}, quietLogger(), false, inner)
+
+	call := func(orgID uuid.UUID) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/v1/decisions", nil)
+		claims := &auth.Claims{AgentID: "same-agent", OrgID: orgID, Role: model.RoleAgent}
+		req = req.WithContext(ctxutil.WithClaims(req.Context(), claims))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Org A allows one request, then throttles.
+	assert.Equal(t, http.StatusOK, call(orgA).Code)
+	rec := call(orgA)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Limit"))
+
+	// Org B is unaffected by org A's exhaustion and allows three.
+	for i := range 3 {
+		assert.Equal(t, http.StatusOK, call(orgB).Code, "org B request %d", i+1)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, call(orgB).Code)
+
+	// Org C has no override and gets the global default.
+	rec = call(orgC)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get("X-RateLimit-Limit"))
+
+	// Each org is looked up once; later requests hit the cache.
+	assert.Equal(t, 3, lookups)
+
+	// Invalidation forces a reload on the next request.
+	limits.invalidate(orgC)
+	call(orgC)
+	assert.Equal(t, 4, lookups)
+}
+
+func TestRateLimitMiddleware_OrgLimitLookupErrorUsesDefault(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter(10, 5)
+	defer func() { _ = limiter.Close() }()
+
+	limits := newOrgRateLimits(func(context.Context, uuid.UUID) (*model.OrgRateLimit, error) {
+		return nil, fmt.Errorf("db down")
+	}, time.Minute)
+	inner := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := rateLimitMiddleware(limiter, limits, ratelimit.Limit{}, quietLogger(), false, inner)
+
+	req := httptest.NewRequest("GET", "/v1/decisions", nil)
+	claims := &auth.Claims{AgentID: "agent", OrgID: uuid.New(), Role: model.RoleAgent}
+	req = req.WithContext(ctxutil.WithClaims(req.Context(), claims))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get("X-RateLimit-Limit"))
+}
+
 func TestRateLimitMiddleware_HeadersOnAllowedResponse(t *testing.T) {
 	limiter := ratelimit.NewMemoryLimiter(10, 5) // burst 5
 	defer func() { _ = limiter.Close() }()
@@ -1213,7 +1461,7 @@ func TestRateLimitMiddleware_HeadersOnAllowedResponse(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	// First request — should be allowed with headers present.
 	rec := httptest.NewRecorder()
@@ -1236,7 +1484,7 @@ func TestRateLimitMiddleware_HeadersOnDeniedResponse(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	// Exhaust the burst.
 	rec := httptest.NewRecorder()
@@ -1267,7 +1515,7 @@ func TestRateLimitMiddleware_HeadersOnAuthenticatedResponse(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	claims := &auth.Claims{
 		AgentID: "header-test-agent",
@@ -1294,7 +1542,7 @@ func TestRateLimitMiddleware_NoHeadersForPlatformAdmin(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	handler := rateLimitMiddleware(limiter, logger, false, inner)
+	handler := rateLimitMiddleware(limiter, nil, ratelimit.Limit{}, logger, false, inner)
 
 	claims := &auth.Claims{
 		AgentID: "admin",
@@ -1686,3 +1934,120 @@ func TestHandleSubscribe_WithBroker(t *testing.T) {
 	assert.Contains(t, body, "event: akashi_decisions")
 	assert.Contains(t, body, `"id":"test-123"`)
 }
+
+func TestResolveNamespace(t *testing.T) {
+	unpinned := &auth.Claims{AgentID: "a"}
+	pinned := &auth.Claims{AgentID: "a", Namespace: "prod"}
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		header     string
+		want       string
+		wantStatus int
+	}{
+		{name: "default when absent", claims: unpinned, want: model.DefaultNamespace},
+		{name: "header selects", claims: unpinned, header: "staging", want: "staging"},
+		{name: "header trimmed", claims: unpinned, header: " staging ", want: "staging"},
+		{name: "invalid header", claims: unpinned, header: "Staging", wantStatus: http.StatusBadRequest},
+		{name: "claim wins when header absent", claims: pinned, want: "prod"},
+		{name: "header may repeat claim", claims: pinned, header: "prod", want: "prod"},
+		{name: "header cannot override claim", claims: pinned, header: "staging", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns, status, err := resolveNamespace(tt.claims, tt.header)
+			if tt.wantStatus != 0 {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantStatus, status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ns)
+		})
+	}
+}
+
+func TestNamespaceFromContext_Default(t *testing.T) {
+	assert.Equal(t, model.DefaultNamespace, NamespaceFromContext(context.Background()))
+	ctx := ctxutil.WithNamespace(context.Background(), "staging")
+	assert.Equal(t, "staging", NamespaceFromContext(ctx))
+}
+
+// --- routeTimeoutMiddleware ---
+
+func TestRouteTimeoutMiddleware_StreamOutlivesGlobalWriteTimeout(t *testing.T) {
+	const lines = 5
+	stream := func(w http.ResponseWriter, r *http.Request) {
+		flusher, _ := w.(http.Flusher)
+		for i := range lines {
+			// Stop at the first failed write so a truncated stream ends promptly.
+			if _, err := fmt.Fprintf(w, "{\"line\":%d}\n", i); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	newServer := func(timeouts map[string]RouteTimeout) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /stream", stream)
+		srv := httptest.NewUnstartedServer(routeTimeoutMiddleware(mux, timeouts, quietLogger(), mux))
+		// The stream takes ~100ms; the global write timeout would cut it off.
+		srv.Config.WriteTimeout = 40 * time.Millisecond
+		srv.Start()
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	readLines := func(srv *httptest.Server) (int, error) {
+		resp, err := http.Get(srv.URL + "/stream")
+		if err != nil {
+			return 0, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		return bytes.Count(body, []byte("\n")), err
+	}
+
+	t.Run("override disables write deadline", func(t *testing.T) {
+		unlimited := time.Duration(0)
+		srv := newServer(map[string]RouteTimeout{"GET /stream": {Route: "GET /stream", WriteTimeout: &unlimited}})
+		n, err := readLines(srv)
+		require.NoError(t, err)
+		assert.Equal(t, lines, n, "stream should complete past the global write timeout")
+	})
+
+	t.Run("global write timeout truncates without override", func(t *testing.T) {
+		srv := newServer(map[string]RouteTimeout{})
+		n, err := readLines(srv)
+		assert.True(t, err != nil || n < lines, "expected truncated stream, got %d lines", n)
+	})
+}
+
+func TestResolveRouteTimeouts(t *testing.T) {
+	write := 2 * time.Minute
+	resolved := resolveRouteTimeouts([]RouteTimeout{
+		{Route: "POST /auth/token", WriteTimeout: &write},
+		{Route: "GET /v1/decisions/{id}", WriteTimeout: &write},
+	})
+
+	// Overrides replace only the fields they set.
+	token := resolved["POST /auth/token"]
+	require.NotNil(t, token.ReadTimeout)
+	assert.Equal(t, 10*time.Second, *token.ReadTimeout, "default read timeout should survive")
+	require.NotNil(t, token.WriteTimeout)
+	assert.Equal(t, write, *token.WriteTimeout)
+
+	// Defaults for streaming routes remove the write deadline.
+	export := resolved["GET /v1/export/decisions"]
+	require.NotNil(t, export.WriteTimeout)
+	assert.Zero(t, *export.WriteTimeout)
+	assert.Nil(t, export.ReadTimeout)
+
+	// New routes are added as-is.
+	require.Contains(t, resolved, "GET /v1/decisions/{id}")
+	assert.Nil(t, resolved["GET /v1/decisions/{id}"].ReadTimeout)
+}
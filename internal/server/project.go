@@ -1,12 +1,16 @@
 package server
 
 import (
+	"context"
 	"log/slog"
 	"net/url"
 	"path"
 	"strings"
 
+	"github.com/google/uuid"
+
 	"github.com/ashita-ai/akashi/internal/projectsuggest"
+	"github.com/ashita-ai/akashi/internal/storage"
 )
 
 // repoNameFromURL extracts a repository name from a git remote URL.
@@ -38,6 +42,61 @@ func repoNameFromURL(rawURL string) string {
 	return strings.TrimSuffix(name, ".git")
 }
 
+// NormalizeTraceProjectForOrg runs normalizeTraceProject for a trace recorded
+// over a remote transport (POST /v1/trace or the gRPC Trace RPC), checking
+// aliases and known projects in orgID. It returns "" on success and the
+// rejection message otherwise.
+func NormalizeTraceProjectForOrg(ctx context.Context, db *storage.DB, orgID uuid.UUID, clientCtx map[string]any, logger *slog.Logger) string {
+	return normalizeTraceProject(
+
+		clientCtx,
+		"", // no server-inferred project: the server can't run git on the client's machine
+		func(project string) string {
+			canonical, err := db.ResolveProjectAlias(ctx, orgID, project)
+			if err != nil {
+				return ""
+			}
+			return canonical
+		},
+		func(project string) bool {
+			exists, err := db.ProjectExists(ctx, orgID, project)
+			if err != nil {
+				// DB error — fail closed. Reject the project rather than
+				// silently accepting an unvalidated name.
+				logger.Error("project existence check failed", "project", project, "error", err)
+				return false
+			}
+			if exists {
+				return true
+			}
+			// First-ever project in this org — accept to bootstrap.
+			hasProjects, hpErr := db.HasAnyProjects(ctx, orgID)
+			if hpErr != nil {
+				logger.Error("has-any-projects check failed", "error", hpErr)
+				return false
+			}
+			return !hasProjects
+		},
+		func() bool {
+			hasProjects, err := db.HasAnyProjects(ctx, orgID)
+			if err != nil {
+				logger.Error("has-any-projects check failed", "error", err)
+				return false // fail open for bootstrapping
+			}
+			return hasProjects
+		},
+		func() []string {
+			projects, err := db.DistinctProjects(ctx, orgID)
+			if err != nil {
+				logger.Warn("distinct projects lookup failed (suggestions suppressed)", "error", err)
+				return nil
+			}
+			return projects
+		},
+		logger,
+	)
+}
+
 // normalizeTraceProject examines the client-supplied context for a trace
 // request and resolves the canonical project name. It modifies clientCtx
 // in place when normalization finds a better name, and returns an error
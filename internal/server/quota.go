@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// quotaResetAt returns the start of the next UTC period after now.
+func quotaResetAt(period string, now time.Time) time.Time {
+	now = now.UTC()
+	if period == "monthly" {
+		return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// enforceBatchDecisionQuota writes a 429 and returns false when the org's
+// decision_quota policy forbids a request that writes agentCount decisions
+// for agentID and orgCount decisions in total. The write is allowed only if
+// every decision fits within the remaining quota. A nil policy allows every
+// write without touching the database.
+//
+// Enforcement is a soft limit: usage is read before the write, so concurrent
+// in-flight traces can overshoot by at most the number of concurrent requests.
+// Single traces go through decisions.Service.CheckTracePolicy instead.
+func (h *Handlers) enforceBatchDecisionQuota(w http.ResponseWriter, r *http.Request, orgID uuid.UUID, agentID string, policy *model.DecisionQuotaPolicy, agentCount, orgCount int64) bool {
+	if policy == nil {
+		return true
+	}
+
+	now := time.Now()
+	usage, err := h.db.GetDecisionUsage(r.Context(), orgID, agentID, now)
+	if err != nil {
+		h.writeInternalError(w, r, "failed to load decision usage", err)
+		return false
+	}
+	// Check asks whether one more decision fits, so count the other
+	// decisions in this request as already used.
+	usage.AgentDaily += agentCount - 1
+	usage.AgentMonthly += agentCount - 1
+	usage.OrgDaily += orgCount - 1
+	usage.OrgMonthly += orgCount - 1
+	exceeded := policy.Check(agentID, usage)
+	if exceeded == nil {
+		return true
+	}
+	writeQuotaExceeded(w, r, agentID, exceeded)
+	return false
+}
+
+// writeQuotaExceeded writes the 429 for an exhausted decision quota, with a
+// Retry-After pointing at the start of the next period.
+func writeQuotaExceeded(w http.ResponseWriter, r *http.Request, agentID string, exceeded *model.QuotaExceededError) {
+	resetAt := quotaResetAt(exceeded.Period, time.Now())
+	w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(resetAt).Seconds())+1))
+	writeErrorDetails(w, r, http.StatusTooManyRequests, model.ErrCodeQuotaExceeded, exceeded.Error(), map[string]any{
+		"scope":     exceeded.Scope,
+		"period":    exceeded.Period,
+		"limit":     exceeded.Limit,
+		"used":      exceeded.Used,
+		"agent_id":  agentID,
+		"resets_at": resetAt,
+	})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// orgRateLimitTTL bounds how long another instance can keep enforcing an
+// org's previous limit after it changes.
+const orgRateLimitTTL = 30 * time.Second
+
+// orgRateLimitLookup loads an org's rate limit override; nil means the org
+// uses the global default.
+type orgRateLimitLookup func(ctx context.Context, orgID uuid.UUID) (*model.OrgRateLimit, error)
+
+// orgRateLimits caches per-org rate limit overrides, including the absence of
+// one, so the rate limit middleware does not query storage on every request.
+// A nil *orgRateLimits resolves every org to the global default.
+type orgRateLimits struct {
+	lookup orgRateLimitLookup
+	ttl    time.Duration
+
+	mu      sync.RWMutex
+	entries map[uuid.UUID]orgRateLimitEntry
+}
+
+type orgRateLimitEntry struct {
+	limit     *model.OrgRateLimit
+	expiresAt time.Time
+}
+
+func newOrgRateLimits(lookup orgRateLimitLookup, ttl time.Duration) *orgRateLimits {
+	return &orgRateLimits{
+		lookup:  lookup,
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]orgRateLimitEntry),
+	}
+}
+
+// get returns the org's override, loading it on a miss or expiry.
+func (c *orgRateLimits) get(ctx context.Context, orgID uuid.UUID) (*model.OrgRateLimit, error) {
+	if c == nil {
+		return nil, nil
+	}
+	c.mu.RLock()
+	entry, ok := c.entries[orgID]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.limit, nil
+	}
+
+	limit, err := c.lookup(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[orgID] = orgRateLimitEntry{limit: limit, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return limit, nil
+}
+
+// invalidate drops the cached entry so this instance picks up a changed
+// limit on the org's next request.
+func (c *orgRateLimits) invalidate(orgID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, orgID)
+	c.mu.Unlock()
+}
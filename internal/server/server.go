@@ -27,6 +27,10 @@ type Server struct {
 	handler    http.Handler
 	handlers   *Handlers
 	logger     *slog.Logger
+
+	// claimsLimiter applies the HTTP rate limit policy to other transports
+	// (see RateLimit). Nil when rate limiting is disabled.
+	claimsLimiter *claimsRateLimiter
 }
 
 // Handler returns the root HTTP handler for use in tests.
@@ -57,15 +61,22 @@ type ServerConfig struct {
 	MCPServer   *mcpserver.MCPServer
 	RateLimiter ratelimit.Limiter
 
+	// RateLimitPerAgent caps each agent across all of its API keys. Zero RPS
+	// disables the cap.
+	RateLimitPerAgent ratelimit.Limit
+
 	// HTTP server settings.
 	Port                    int
 	ReadTimeout             time.Duration
 	WriteTimeout            time.Duration
+	RouteTimeouts           []RouteTimeout // Per-route overrides of ReadTimeout/WriteTimeout, merged onto built-in defaults.
 	Version                 string
 	MaxRequestBodyBytes     int64
-	CORSAllowedOrigins      []string // Allowed origins for CORS; ["*"] permits all.
-	TrustProxy              bool     // When true, use X-Forwarded-For for rate limit client IP.
+	CORSAllowedOrigins      []string     // Allowed origins for CORS; ["*"] permits all.
+	CORSPolicies            []CORSPolicy // Per-origin CORS policies; override CORSAllowedOrigins entries.
+	TrustProxy              bool         // When true, use X-Forwarded-For for rate limit client IP.
 	EnableDestructiveDelete bool
+	SoftDelete              bool          // DELETE /v1/agents/{agent_id} tombstones instead of removing rows.
 	RetentionInterval       time.Duration // How often the background retention worker runs (default 24h).
 
 	// Optional embedded assets.
@@ -97,6 +108,12 @@ type ServerConfig struct {
 	// Conflict validator for the eval endpoint. Nil = eval returns 501.
 	ConflictValidator conflicts.Validator
 
+	// Conflict rescorer for the admin rescore endpoint. Nil = rescore returns 501.
+	ConflictRescorer ConflictRescorer
+
+	// Search outbox for POST /v1/admin/flush. Nil = outbox stage skipped.
+	Outbox OutboxFlusher
+
 	// Trace quality warnings.
 	HighConfidenceWarnThreshold float32
 
@@ -104,6 +121,29 @@ type ServerConfig struct {
 	// Page size for GET /v1/export/decisions NDJSON pagination. Zero = use
 	// the handler's default (100). Validated at config load (1–10000).
 	ExportPageSize int
+
+	// Maximum traces per POST /v1/trace/batch request. Zero = 100.
+	TraceBatchMax int
+
+	// Event payload validation against built-in and org event schemas.
+	EventSchemaValidation bool
+
+	// Access log (access transparency, per-request compliance). Disabled by default.
+	AccessLog AccessLogConfig
+
+	// Default SLA for GET /v1/review-queue. Zero = 24h.
+	ReviewSLA time.Duration
+
+	// Embedding model migration for POST /v1/admin/reembed. An empty
+	// EmbeddingModel accepts any to_model; a nil VectorCollection skips the
+	// search collection size check.
+	EmbeddingModel   string
+	VectorCollection VectorCollection
+
+	// Prometheus scrape handler for GET /metrics. Nil = endpoint not served.
+	// The handler enforces its own bearer token; /metrics is outside the
+	// agent auth prefixes.
+	MetricsHandler http.Handler
 }
 
 // New creates a new HTTP server with all routes configured.
@@ -121,21 +161,34 @@ func New(cfg ServerConfig) *Server {
 		MaxRequestBodyBytes:         cfg.MaxRequestBodyBytes,
 		OpenAPISpec:                 cfg.OpenAPISpec,
 		EnableDestructiveDelete:     cfg.EnableDestructiveDelete,
+		SoftDelete:                  cfg.SoftDelete,
 		RetentionInterval:           cfg.RetentionInterval,
 		DecisionHooks:               cfg.DecisionHooks,
 		AutoTrace:                   cfg.AutoTrace,
 		TrustProxy:                  cfg.TrustProxy,
 		ResolutionRecorder:          cfg.ResolutionRecorder,
 		ConflictValidator:           cfg.ConflictValidator,
+		ConflictRescorer:            cfg.ConflictRescorer,
+		Outbox:                      cfg.Outbox,
 		HighConfidenceWarnThreshold: cfg.HighConfidenceWarnThreshold,
 		ExportPageSize:              cfg.ExportPageSize,
+		TraceBatchMax:               cfg.TraceBatchMax,
+		EventSchemaValidation:       cfg.EventSchemaValidation,
+		AccessLog:                   cfg.AccessLog,
+		ReviewSLA:                   cfg.ReviewSLA,
+		EmbeddingModel:              cfg.EmbeddingModel,
+		VectorCollection:            cfg.VectorCollection,
 	})
 
+	if cfg.DB != nil {
+		h.orgRateLimits = newOrgRateLimits(cfg.DB.GetOrgRateLimit, orgRateLimitTTL)
+	}
+
 	mux := http.NewServeMux()
 
 	// Auth endpoints (no auth required).
 	mux.Handle("POST /auth/token", http.HandlerFunc(h.HandleAuthToken))
-	mux.Handle("POST /auth/refresh", http.HandlerFunc(h.HandleAuthToken))
+	mux.Handle("POST /auth/refresh", http.HandlerFunc(h.HandleAuthRefresh))
 
 	// Self-serve signup (no auth required, gated by config flag).
 	if cfg.SignupEnabled {
@@ -151,15 +204,18 @@ func New(cfg ServerConfig) *Server {
 	adminOnly := requireRole(model.RoleAdmin)
 	mux.Handle("POST /v1/auth/scoped-token", adminOnly(http.HandlerFunc(h.HandleScopedToken)))
 	mux.Handle("POST /v1/agents", adminOnly(http.HandlerFunc(h.HandleCreateAgent)))
+	mux.Handle("POST /v1/agents/bulk", adminOnly(http.HandlerFunc(h.HandleBulkCreateAgents)))
 	mux.Handle("GET /v1/agents", adminOnly(http.HandlerFunc(h.HandleListAgents)))
 	mux.Handle("GET /v1/agents/{agent_id}", adminOnly(http.HandlerFunc(h.HandleGetAgent)))
 	mux.Handle("PATCH /v1/agents/{agent_id}", adminOnly(http.HandlerFunc(h.HandleUpdateAgent)))
-	mux.Handle("GET /v1/agents/{agent_id}/stats", adminOnly(http.HandlerFunc(h.HandleAgentStats)))
 	mux.Handle("PATCH /v1/agents/{agent_id}/tags", adminOnly(http.HandlerFunc(h.HandleUpdateAgentTags)))
+	mux.Handle("GET /v1/agents/{agent_id}/delete-impact", adminOnly(http.HandlerFunc(h.HandleAgentDeleteImpact)))
 	mux.Handle("DELETE /v1/agents/{agent_id}", adminOnly(http.HandlerFunc(h.HandleDeleteAgent)))
 	mux.Handle("PATCH /v1/decisions/{id}", adminOnly(http.HandlerFunc(h.HandlePatchDecision)))
+	mux.Handle("PATCH /v1/decisions/{id}/metadata", adminOnly(http.HandlerFunc(h.HandleUpdateDecisionMetadata)))
 	mux.Handle("DELETE /v1/decisions/{id}", adminOnly(http.HandlerFunc(h.HandleRetractDecision)))
 	mux.Handle("GET /v1/export/decisions", adminOnly(http.HandlerFunc(h.HandleExportDecisions)))
+	mux.Handle("POST /v1/import/decisions", adminOnly(http.HandlerFunc(h.HandleImportDecisions)))
 
 	// GDPR erasure (org_owner+ — stronger than admin because erasure is irreversible).
 	orgOwnerOnly := requireRole(model.RoleOrgOwner)
@@ -180,14 +236,21 @@ func New(cfg ServerConfig) *Server {
 	mux.Handle("POST /v1/runs/{run_id}/events", writeRole(http.HandlerFunc(h.HandleAppendEvents)))
 	mux.Handle("POST /v1/runs/{run_id}/complete", writeRole(http.HandlerFunc(h.HandleCompleteRun)))
 	mux.Handle("POST /v1/trace", writeRole(http.HandlerFunc(h.HandleTrace)))
+	mux.Handle("POST /v1/trace/batch", writeRole(http.HandlerFunc(h.HandleTraceBatch)))
 
 	// Query endpoints (reader+).
 	readRole := requireRole(model.RoleReader)
 	mux.Handle("GET /v1/decisions/{id}", readRole(http.HandlerFunc(h.HandleGetDecision)))
 	mux.Handle("POST /v1/query", readRole(http.HandlerFunc(h.HandleQuery)))
 	mux.Handle("POST /v1/query/temporal", readRole(http.HandlerFunc(h.HandleTemporalQuery)))
+	mux.Handle("POST /v1/query/temporal/diff", readRole(http.HandlerFunc(h.HandleTemporalDiff)))
 	mux.Handle("GET /v1/runs/{run_id}", readRole(http.HandlerFunc(h.HandleGetRun)))
+	mux.Handle("GET /v1/agents/{agent_id}/stats", readRole(http.HandlerFunc(h.HandleAgentStats)))
 	mux.Handle("GET /v1/agents/{agent_id}/history", readRole(http.HandlerFunc(h.HandleAgentHistory)))
+	mux.Handle("GET /v1/agents/{agent_id}/flip-flops", readRole(http.HandlerFunc(h.HandleAgentFlipFlops)))
+	mux.Handle("GET /v1/agents/{agent_id}/confidence-histogram", readRole(http.HandlerFunc(h.HandleConfidenceHistogram)))
+	mux.Handle("GET /v1/agents/{agent_id}/sessions", readRole(http.HandlerFunc(h.HandleAgentSessions)))
+	mux.Handle("POST /v1/agents/{agent_id}/rotate-key", readRole(http.HandlerFunc(h.HandleRotateAgentKey)))
 
 	// Search endpoint (reader+).
 	mux.Handle("POST /v1/search", readRole(http.HandlerFunc(h.HandleSearch)))
@@ -201,8 +264,12 @@ func New(cfg ServerConfig) *Server {
 	// Decision timeline summary (reader+).
 	mux.Handle("GET /v1/decisions/timeline", readRole(http.HandlerFunc(h.HandleDecisionTimeline)))
 
+	// Decision lookup by content hash or hash prefix (reader+).
+	mux.Handle("GET /v1/decisions/by-hash", readRole(http.HandlerFunc(h.HandleDecisionsByHash)))
+
 	// Decision facets — distinct types & projects for filter dropdowns (reader+).
 	mux.Handle("GET /v1/decisions/facets", readRole(http.HandlerFunc(h.HandleDecisionFacets)))
+	mux.Handle("GET /v1/decision-types", readRole(http.HandlerFunc(h.HandleListDecisionTypes)))
 
 	// Decision revision history (reader+).
 	mux.Handle("GET /v1/decisions/{id}/revisions", readRole(http.HandlerFunc(h.HandleDecisionRevisions)))
@@ -212,16 +279,42 @@ func New(cfg ServerConfig) *Server {
 
 	// Decision lineage: precedent chain visualization (reader+).
 	mux.Handle("GET /v1/decisions/{id}/lineage", readRole(http.HandlerFunc(h.HandleGetDecisionLineage)))
+	mux.Handle("GET /v1/decisions/{id}/ancestry-health", readRole(http.HandlerFunc(h.HandleGetDecisionAncestryHealth)))
+	mux.Handle("GET /v1/decisions/{id}/cited-by", readRole(http.HandlerFunc(h.HandleDecisionCitedBy)))
 
 	// Decision assessments: explicit outcome feedback (spec 29 / ADR-020 Tier 2).
 	mux.Handle("POST /v1/decisions/{id}/assess", writeRole(http.HandlerFunc(h.HandleAssessDecision)))
 	mux.Handle("GET /v1/decisions/{id}/assessments", readRole(http.HandlerFunc(h.HandleListAssessments)))
 
-	// Session view (reader+).
+	// Decision review (writer+ to flag, admin+ to mark reviewed, reader+ for the queue).
+	mux.Handle("POST /v1/decisions/{id}/flag", writeRole(http.HandlerFunc(h.HandleFlagDecision)))
+	mux.Handle("POST /v1/decisions/{id}/review", adminOnly(http.HandlerFunc(h.HandleReviewDecision)))
+	mux.Handle("GET /v1/review-queue", readRole(http.HandlerFunc(h.HandleReviewQueue)))
+
+	// Decision tags (writer+; caller must be able to read the decision's agent).
+	mux.Handle("PATCH /v1/decisions/{id}/tags", writeRole(http.HandlerFunc(h.HandleUpdateDecisionTags)))
+
+	// Sessions (writer+ to start and close, reader+ to view).
+	mux.Handle("POST /v1/sessions", writeRole(http.HandlerFunc(h.HandleStartSession)))
+	mux.Handle("POST /v1/sessions/{session_id}/close", writeRole(http.HandlerFunc(h.HandleCloseSession)))
 	mux.Handle("GET /v1/sessions/{session_id}", readRole(http.HandlerFunc(h.HandleSessionView)))
 
-	// Trace health (admin-only).
+	// Decision change log (admin-only: entries carry full row images and
+	// bypass per-agent access filtering).
+	mux.Handle("GET /v1/cdc/decisions", adminOnly(http.HandlerFunc(h.HandleDecisionChanges)))
+
+	// Decision batch view (reader+).
+	mux.Handle("GET /v1/decisions/batches/{batch_id}", readRole(http.HandlerFunc(h.HandleBatchView)))
+
+	// Trace health and on-demand flush (admin-only).
 	mux.Handle("GET /v1/trace-health", adminOnly(http.HandlerFunc(h.HandleTraceHealth)))
+	mux.Handle("GET /v1/stats/duplicates", adminOnly(http.HandlerFunc(h.HandleDuplicatesReport)))
+	mux.Handle("POST /v1/admin/flush", adminOnly(http.HandlerFunc(h.HandleAdminFlush)))
+	mux.Handle("GET /v1/admin/search-outbox/dead", adminOnly(http.HandlerFunc(h.HandleListDeadOutbox)))
+	mux.Handle("POST /v1/admin/search-outbox/dead/requeue", adminOnly(http.HandlerFunc(h.HandleRequeueDeadOutbox)))
+
+	// Audit log query: mutations, or read access when the access log is enabled (admin-only).
+	mux.Handle("GET /v1/audit", adminOnly(http.HandlerFunc(h.HandleListAudit)))
 
 	// Integrity verification (reader+) and violations (admin-only).
 	mux.Handle("GET /v1/verify/{id}", readRole(http.HandlerFunc(h.HandleVerifyDecision)))
@@ -234,11 +327,14 @@ func New(cfg ServerConfig) *Server {
 	// Access control (admin for list, agent+ can grant access to own traces).
 	mux.Handle("GET /v1/grants", adminOnly(http.HandlerFunc(h.HandleListGrants)))
 	mux.Handle("POST /v1/grants", writeRole(http.HandlerFunc(h.HandleCreateGrant)))
+	mux.Handle("POST /v1/grants/batch", writeRole(http.HandlerFunc(h.HandleCreateGrantsBatch)))
 	mux.Handle("DELETE /v1/grants/{grant_id}", writeRole(http.HandlerFunc(h.HandleDeleteGrant)))
 
 	// Conflicts (reader+ for list/detail/analytics, agent+ for adjudicate/patch/resolve).
 	mux.Handle("GET /v1/conflicts/analytics", readRole(http.HandlerFunc(h.HandleConflictAnalytics)))
+	mux.Handle("GET /v1/consensus/confidence", readRole(http.HandlerFunc(h.HandleConfidenceConsensus)))
 	mux.Handle("GET /v1/conflicts", readRole(http.HandlerFunc(h.HandleListConflicts)))
+	mux.Handle("GET /v1/export/conflicts", readRole(http.HandlerFunc(h.HandleExportConflicts)))
 	mux.Handle("GET /v1/conflicts/{id}", readRole(http.HandlerFunc(h.HandleGetConflict)))
 	mux.Handle("GET /v1/conflict-groups", readRole(http.HandlerFunc(h.HandleListConflictGroups)))
 	mux.Handle("PATCH /v1/conflict-groups/{id}/resolve", writeRole(http.HandlerFunc(h.HandleResolveConflictGroup)))
@@ -253,6 +349,10 @@ func New(cfg ServerConfig) *Server {
 	mux.Handle("DELETE /v1/admin/conflicts/{id}/label", adminOnly(http.HandlerFunc(h.HandleDeleteConflictLabel)))
 	mux.Handle("GET /v1/admin/conflict-labels", adminOnly(http.HandlerFunc(h.HandleListConflictLabels)))
 	mux.Handle("POST /v1/admin/scorer-eval", adminOnly(http.HandlerFunc(h.HandleScorerEval)))
+	mux.Handle("POST /v1/admin/conflicts/rescore", adminOnly(http.HandlerFunc(h.HandleRescoreConflicts)))
+	mux.Handle("POST /v1/admin/reembed", adminOnly(http.HandlerFunc(h.HandleReembed)))
+	mux.Handle("POST /v1/admin/query/explain", adminOnly(http.HandlerFunc(h.HandleExplainQuery)))
+	mux.Handle("GET /v1/admin/reembed", adminOnly(http.HandlerFunc(h.HandleGetReembedJob)))
 
 	// Retention policy and legal holds (admin for writes, reader+ for GET).
 	mux.Handle("GET /v1/retention", readRole(http.HandlerFunc(h.HandleGetRetention)))
@@ -265,7 +365,16 @@ func New(cfg ServerConfig) *Server {
 	mux.Handle("GET /v1/org/settings", readRole(http.HandlerFunc(h.HandleGetOrgSettings)))
 	mux.Handle("PUT /v1/org/settings", adminOnly(http.HandlerFunc(h.HandleSetOrgSettings)))
 
+	// Per-org rate limits (platform admin only: org admins must not raise their own ceiling).
+	platformAdminOnly := requireRole(model.RolePlatformAdmin)
+	mux.Handle("PUT /v1/orgs/{org_id}/rate-limit", platformAdminOnly(http.HandlerFunc(h.HandleSetOrgRateLimit)))
+	mux.Handle("GET /v1/orgs/{org_id}/settings", adminOnly(http.HandlerFunc(h.HandleGetOrgSettingsByID)))
+	mux.Handle("PUT /v1/orgs/{org_id}/settings", adminOnly(http.HandlerFunc(h.HandleSetOrgSetting)))
+
 	// Project links (admin-only).
+	mux.Handle("GET /v1/conflict-thresholds", adminOnly(http.HandlerFunc(h.HandleListConflictThresholds)))
+	mux.Handle("PUT /v1/conflict-thresholds/{decision_type}", adminOnly(http.HandlerFunc(h.HandleSetConflictThreshold)))
+	mux.Handle("DELETE /v1/conflict-thresholds/{decision_type}", adminOnly(http.HandlerFunc(h.HandleDeleteConflictThreshold)))
 	mux.Handle("POST /v1/project-links", adminOnly(http.HandlerFunc(h.HandleCreateProjectLink)))
 	mux.Handle("GET /v1/project-links", adminOnly(http.HandlerFunc(h.HandleListProjectLinks)))
 	mux.Handle("DELETE /v1/project-links/{id}", adminOnly(http.HandlerFunc(h.HandleDeleteProjectLink)))
@@ -287,6 +396,11 @@ func New(cfg ServerConfig) *Server {
 	mux.HandleFunc("GET /health", h.HandleHealth)
 	mux.HandleFunc("GET /readyz", h.HandleReadyz)
 
+	// Prometheus metrics (optional bearer token, enforced by the handler).
+	if cfg.MetricsHandler != nil {
+		mux.Handle("GET /metrics", cfg.MetricsHandler)
+	}
+
 	// MCP info (no auth) — lets clients confirm connectivity and discover auth schemes.
 	mux.HandleFunc("GET /mcp/info", h.HandleMCPInfo)
 
@@ -316,20 +430,24 @@ func New(cfg ServerConfig) *Server {
 	}
 
 	// Middleware chain (outermost executes first):
-	// request ID → security headers → CORS → tracing → logging → baggage → auth → recovery → rateLimit → handler.
+	// route timeouts → request ID → security headers → CORS → tracing → logging → baggage → auth → access log → gzip → recovery → rateLimit → handler.
 	var handler http.Handler = mux
+	var claimsLimiter *claimsRateLimiter
 	if cfg.RateLimiter != nil {
-		handler = rateLimitMiddleware(cfg.RateLimiter, cfg.Logger, cfg.TrustProxy, handler)
+		claimsLimiter = newClaimsRateLimiter(cfg.RateLimiter, h.orgRateLimits, cfg.RateLimitPerAgent, cfg.Logger)
+		handler = rateLimitMiddleware(cfg.RateLimiter, h.orgRateLimits, cfg.RateLimitPerAgent, cfg.Logger, cfg.TrustProxy, handler)
 	}
 	handler = recoveryMiddleware(cfg.Logger, handler)
 	handler = gzipMiddleware(handler)
+	handler = accessLogMiddleware(h.accessLog, handler)
 	handler = authMiddleware(cfg.JWTMgr, cfg.DB, handler)
 	handler = baggageMiddleware(handler)
 	handler = loggingMiddleware(cfg.Logger, handler)
 	handler = tracingMiddleware(handler)
-	handler = corsMiddleware(cfg.CORSAllowedOrigins, handler)
+	handler = corsPolicyMiddleware(cfg.CORSAllowedOrigins, cfg.CORSPolicies, handler)
 	handler = securityHeadersMiddleware(handler)
 	handler = requestIDMiddleware(handler)
+	handler = routeTimeoutMiddleware(mux, resolveRouteTimeouts(cfg.RouteTimeouts), cfg.Logger, handler)
 
 	// Enterprise/plugin middlewares — applied outermost (index 0 = first-registered = outermost).
 	// Iterate in reverse so that Middlewares[0] ends up as the true outermost layer.
@@ -350,9 +468,10 @@ func New(cfg ServerConfig) *Server {
 			WriteTimeout: cfg.WriteTimeout,
 			IdleTimeout:  2 * cfg.ReadTimeout, // Prevent accumulation of idle connections.
 		},
-		handler:  handler,
-		handlers: h,
-		logger:   cfg.Logger,
+		handler:       handler,
+		handlers:      h,
+		logger:        cfg.Logger,
+		claimsLimiter: claimsLimiter,
 	}
 }
 
@@ -361,6 +480,18 @@ func (s *Server) Handlers() *Handlers {
 	return s.handlers
 }
 
+// RateLimit charges one call by claims against the same buckets, org
+// overrides and per-agent cap the HTTP API uses, so other transports (gRPC)
+// cannot be used to sidestep them. It returns the limiter result and, when the
+// call is denied, a non-empty message. Always allows when rate limiting is
+// disabled.
+func (s *Server) RateLimit(ctx context.Context, claims *auth.Claims) (ratelimit.Result, string) {
+	if s.claimsLimiter == nil || claims == nil {
+		return ratelimit.Result{Allowed: true}, ""
+	}
+	return s.claimsLimiter.allow(ctx, claims)
+}
+
 // Start begins serving HTTP requests.
 func (s *Server) Start() error {
 	s.logger.Info("http server starting", "addr", s.httpServer.Addr)
@@ -370,7 +501,18 @@ func (s *Server) Start() error {
 // Shutdown gracefully shuts down the HTTP server.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("http server shutting down")
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+	// Write access-log entries still buffered from the drained requests.
+	if logErr := s.handlers.accessLog.close(ctx); logErr != nil {
+		s.logger.Warn("access log: final flush failed", "error", logErr)
+	}
+	return err
+}
+
+// FlushAccessLog writes buffered access_audit_log entries now instead of
+// waiting for the next flush tick. No-op when the access log is disabled.
+func (s *Server) FlushAccessLog(ctx context.Context) error {
+	return s.handlers.accessLog.flush(ctx)
 }
 
 // CloseSignupLimiter releases the signup rate limiter's resources (cleanup goroutine).
@@ -5,12 +5,17 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -19,11 +24,22 @@ import (
 	mcpclient "github.com/mark3labs/mcp-go/client"
 	mcptransport "github.com/mark3labs/mcp-go/client/transport"
 	mcplib "github.com/mark3labs/mcp-go/mcp"
+	"github.com/parquet-go/parquet-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/ashita-ai/akashi/internal/auth"
+	akashigrpc "github.com/ashita-ai/akashi/internal/grpc"
+	"github.com/ashita-ai/akashi/internal/grpc/akashiv1"
+	"github.com/ashita-ai/akashi/internal/integrity"
 	"github.com/ashita-ai/akashi/internal/mcp"
 	"github.com/ashita-ai/akashi/internal/model"
 	"github.com/ashita-ai/akashi/internal/server"
@@ -38,6 +54,8 @@ var (
 	testSrv       *httptest.Server
 	testDB        *storage.DB   // exposed so tests can seed data not reachable via HTTP
 	testBuf       *trace.Buffer // exposed so tests can flush the buffer before seeding conflicts
+	testJWTMgr    *auth.JWTManager
+	testDecisions *decisions.Service // shared with transports other than HTTP (gRPC)
 	testcontainer testcontainers.Container
 	adminToken    string
 	agentToken    string
@@ -77,8 +95,10 @@ func TestMain(m *testing.M) {
 	testDB = db
 
 	jwtMgr, _ := auth.NewJWTManager("", "", 24*time.Hour)
+	testJWTMgr = jwtMgr
 	embedder := embedding.NewNoopProvider(1024)
 	decisionSvc := decisions.New(db, embedder, nil, logger, nil)
+	testDecisions = decisionSvc
 	buf := trace.NewBuffer(db, logger, 1000, 50*time.Millisecond, nil)
 	buf.Start(ctx)
 	testBuf = buf
@@ -459,6 +479,129 @@ func TestHandleAppendEvents_IdempotencyPayloadMismatch(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
 }
 
+func TestHandleAppendEvents_SchemaValidation(t *testing.T) {
+	logger := testutil.TestLogger()
+	buf := trace.NewBuffer(testDB, logger, 1000, 50*time.Millisecond, nil)
+	srv := server.New(server.ServerConfig{
+		DB:                    testDB,
+		JWTMgr:                testJWTMgr,
+		DecisionSvc:           testDecisions,
+		Buffer:                buf,
+		Logger:                logger,
+		ReadTimeout:           30 * time.Second,
+		WriteTimeout:          30 * time.Second,
+		Version:               "test",
+		MaxRequestBodyBytes:   1 * 1024 * 1024,
+		EventSchemaValidation: true,
+	})
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		_ = srv.Shutdown(context.Background())
+	})
+
+	resp, err := authedRequest("POST", ts.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(data))
+	require.NoError(t, json.Unmarshal(data, &runResult))
+	eventsURL := ts.URL + "/v1/runs/" + runResult.Data.ID.String() + "/events"
+
+	appendEvents := func(t *testing.T, url string, events ...model.EventInput) (int, model.APIError) {
+		t.Helper()
+		resp, err := authedRequest("POST", url, agentToken, model.AppendEventsRequest{Events: events})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var errResp model.APIError
+		if resp.StatusCode != http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		}
+		return resp.StatusCode, errResp
+	}
+
+	t.Run("missing required key is rejected", func(t *testing.T) {
+		status, errResp := appendEvents(t, eventsURL,
+			model.EventInput{EventType: model.EventDecisionStarted, Payload: map[string]any{"decision_type": "test"}},
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"confidence": 0.9}},
+		)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+		assert.Equal(t, "events[1] (DecisionMade): payload.outcome is required", errResp.Error.Message)
+	})
+
+	t.Run("wrong property type is rejected", func(t *testing.T) {
+		status, errResp := appendEvents(t, eventsURL,
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved", "confidence": "high"}},
+		)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "events[0] (DecisionMade): payload.confidence must be of type number", errResp.Error.Message)
+	})
+
+	t.Run("valid payloads are accepted", func(t *testing.T) {
+		status, _ := appendEvents(t, eventsURL,
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved", "confidence": 0.9}},
+		)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("validation off accepts malformed payloads", func(t *testing.T) {
+		url := testSrv.URL + "/v1/runs/" + runResult.Data.ID.String() + "/events"
+		status, _ := appendEvents(t, url,
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"confidence": "high"}},
+		)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("org schema extends built-ins", func(t *testing.T) {
+		prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		})
+
+		settings := prev.Settings
+		settings.EventSchemas = map[model.EventType]model.EventSchema{
+			model.EventDecisionMade: {Required: []string{"ticket"}},
+		}
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		status, errResp := appendEvents(t, eventsURL,
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved"}},
+		)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "events[0] (DecisionMade): payload.ticket is required", errResp.Error.Message)
+
+		status, _ = appendEvents(t, eventsURL,
+			model.EventInput{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved", "ticket": "OPS-1"}},
+		)
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("invalid org schema is rejected", func(t *testing.T) {
+		prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
+		require.NoError(t, err)
+		bad := prev.Settings
+		bad.EventSchemas = map[model.EventType]model.EventSchema{
+			model.EventDecisionMade: {Properties: map[string]model.EventPropertySchema{"ticket": {Type: "uuid"}}},
+		}
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, bad)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
 func TestTraceConvenience(t *testing.T) {
 	reasoning := "test reasoning"
 	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
@@ -589,7 +732,7 @@ func TestMCPListTools(t *testing.T) {
 
 	toolsResult, err := c.ListTools(ctx, mcplib.ListToolsRequest{})
 	require.NoError(t, err)
-	assert.Len(t, toolsResult.Tools, 7)
+	assert.Len(t, toolsResult.Tools, 10)
 
 	toolNames := make(map[string]bool)
 	for _, tool := range toolsResult.Tools {
@@ -602,6 +745,44 @@ func TestMCPListTools(t *testing.T) {
 	assert.True(t, toolNames["akashi_resolve"], "expected akashi_resolve tool")
 	assert.True(t, toolNames["akashi_stats"], "expected akashi_stats tool")
 	assert.True(t, toolNames["akashi_assess"], "expected akashi_assess tool")
+	assert.True(t, toolNames["akashi_supersede"], "expected akashi_supersede tool")
+	assert.True(t, toolNames["akashi_why"], "expected akashi_why tool")
+	assert.True(t, toolNames["akashi_timeline"], "expected akashi_timeline tool")
+
+	t.Run("reader sees only read tools", func(t *testing.T) {
+		createAgent(testSrv.URL, adminToken, "mcp-reader", "MCP Reader", "reader", "mcp-reader-key")
+		rc := newMCPClient(t, getToken(testSrv.URL, "mcp-reader", "mcp-reader-key"))
+		defer func() { _ = rc.Close() }()
+
+		_, err := rc.Initialize(ctx, mcplib.InitializeRequest{
+			Params: mcplib.InitializeParams{
+				ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+			},
+		})
+		require.NoError(t, err)
+
+		readerTools, err := rc.ListTools(ctx, mcplib.ListToolsRequest{})
+		require.NoError(t, err)
+		readerNames := make([]string, 0, len(readerTools.Tools))
+		for _, tool := range readerTools.Tools {
+			readerNames = append(readerNames, tool.Name)
+		}
+		assert.ElementsMatch(t, []string{"akashi_check", "akashi_query", "akashi_conflicts", "akashi_stats", "akashi_why", "akashi_timeline"}, readerNames)
+
+		// Hidden tools are still refused when called directly.
+		result, err := rc.CallTool(ctx, mcplib.CallToolRequest{
+			Params: mcplib.CallToolParams{
+				Name: "akashi_trace",
+				Arguments: map[string]any{
+					"decision_type": "mcp_test",
+					"outcome":       "reader write",
+					"confidence":    0.5,
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, "reader must not be able to trace")
+	})
 }
 
 func TestMCPListResources(t *testing.T) {
@@ -992,6 +1173,86 @@ func TestDecisionsRecentEndpoint(t *testing.T) {
 	}
 }
 
+// TestDecisionNamespace_ReadsByIDAreScoped checks that reads addressing a
+// decision directly answer 404 outside the decision's namespace, like list
+// and search, which never return it.
+func TestDecisionNamespace_ReadsByIDAreScoped(t *testing.T) {
+	staging := map[string]string{"X-Akashi-Namespace": "staging"}
+	decisionType := "ns_scoped_" + uuid.NewString()[:8]
+
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID:  "test-agent",
+		Decision: model.TraceDecision{DecisionType: decisionType, Outcome: "staging only", Confidence: 0.6},
+	}, staging)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var traced struct {
+		Data model.TraceResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&traced))
+	_ = resp.Body.Close()
+	id := traced.Data.DecisionID.String()
+
+	get := func(path string, headers map[string]string) *http.Response {
+		t.Helper()
+		resp, err := authedRequestWithHeaders("GET", testSrv.URL+path, adminToken, nil, headers)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp = get("/v1/decisions/"+id, staging)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got struct {
+		Data model.Decision `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	assert.Equal(t, "staging", got.Data.Namespace)
+	digest := got.Data.ContentHash[strings.LastIndex(got.Data.ContentHash, ":")+1:]
+
+	for _, path := range []string{
+		"/v1/decisions/" + id,
+		"/v1/decisions/" + id + "/revisions",
+		"/v1/decisions/" + id + "/cited-by",
+		"/v1/decisions/" + id + "/conflicts",
+	} {
+		t.Run(path, func(t *testing.T) {
+			assert.Equal(t, http.StatusNotFound, get(path, nil).StatusCode, "other namespace")
+			assert.Equal(t, http.StatusOK, get(path, staging).StatusCode, "own namespace")
+		})
+	}
+
+	byHash := func(headers map[string]string) []model.Decision {
+		t.Helper()
+		resp := get("/v1/decisions/by-hash?hash="+digest, headers)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []model.Decision `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Data
+	}
+	t.Run("by-hash", func(t *testing.T) {
+		assert.Empty(t, byHash(nil))
+		found := byHash(staging)
+		require.Len(t, found, 1)
+		assert.Equal(t, traced.Data.DecisionID, found[0].ID)
+	})
+
+	export := func(headers map[string]string) string {
+		t.Helper()
+		resp := get("/v1/export/decisions?decision_type="+decisionType, headers)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	t.Run("export", func(t *testing.T) {
+		assert.NotContains(t, export(nil), id)
+		assert.Contains(t, export(staging), id)
+	})
+}
+
 func TestSSESubscribeNoBroker(t *testing.T) {
 	// When broker is nil (no LISTEN/NOTIFY configured), SSE returns 503.
 	resp, err := authedRequest("GET", testSrv.URL+"/v1/subscribe", adminToken, nil)
@@ -1027,6 +1288,10 @@ func TestExportDecisions(t *testing.T) {
 			assert.NotEmpty(t, d.ID, "decision should have an ID")
 			assert.Equal(t, "test-agent", d.AgentID, "export should only contain requested agent")
 		}
+
+		// Trailers are only populated once the body has been fully read.
+		assert.Equal(t, "true", resp.Trailer.Get("X-Akashi-Export-Complete"))
+		assert.Equal(t, strconv.Itoa(len(lines)), resp.Trailer.Get("X-Akashi-Exported-Count"))
 	})
 
 	t.Run("non-admin cannot export", func(t *testing.T) {
@@ -1503,6 +1768,35 @@ func TestHandleTrace_MissingOutcome(t *testing.T) {
 	assert.Contains(t, errResp.Error.Message, "outcome")
 }
 
+func TestHandleTrace_ReportsAllValidationErrors(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID:  "test-agent",
+			Decision: model.TraceDecision{Confidence: 1.5},
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var errResp struct {
+		Error struct {
+			Code    string             `json:"code"`
+			Message string             `json:"message"`
+			Details []model.FieldError `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	fields := make([]string, len(errResp.Error.Details))
+	for i, d := range errResp.Error.Details {
+		fields[i] = d.Field
+		assert.NotEmpty(t, d.Message)
+	}
+	assert.ElementsMatch(t, []string{"decision.decision_type", "decision.outcome", "decision.confidence"}, fields)
+	assert.Contains(t, errResp.Error.Message, "decision.decision_type is required")
+	assert.Contains(t, errResp.Error.Message, "decision.confidence must be between 0 and 1")
+}
+
 func TestHandleTrace_InvalidConfidence(t *testing.T) {
 	t.Run("negative confidence", func(t *testing.T) {
 		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
@@ -1703,764 +1997,871 @@ func TestHandleTrace_IdempotencyPayloadMismatch(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
 }
 
-func TestHandleQuery_EmptyResult(t *testing.T) {
-	agentID := "nonexistent-agent-xxx"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-		model.QueryRequest{
-			Filters: model.QueryFilters{
-				AgentIDs: []string{agentID},
-			},
-			Limit: 10,
-		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data []json.RawMessage `json:"data"`
-	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
-	require.NoError(t, err)
-	// Verify the array is present and empty (not null).
-	assert.NotNil(t, result.Data, "decisions should be an empty array, not null")
-	assert.Len(t, result.Data, 0)
-}
+func TestHandleCreateAgent_IdempotencyReplay(t *testing.T) {
+	key := "agent-idem-" + uuid.NewString()
+	agentID := "idem-agent-" + uuid.NewString()[:8]
+	body := model.CreateAgentRequest{AgentID: agentID, Name: "Idempotent Agent", Role: model.RoleAgent}
 
-func TestHandleQuery_LimitBounds(t *testing.T) {
-	t.Run("limit zero uses default", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-			model.QueryRequest{
-				Filters: model.QueryFilters{
-					AgentIDs: []string{"test-agent"},
-				},
-				Limit: 0,
-			})
+	create := func(body model.CreateAgentRequest) (*http.Response, model.CreateAgentResponse) {
+		resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/agents", adminToken, body, map[string]string{
+			"Idempotency-Key": key,
+		})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
 		var result struct {
-			Limit int `json:"limit"`
+			Data model.CreateAgentResponse `json:"data"`
 		}
 		data, _ := io.ReadAll(resp.Body)
 		_ = json.Unmarshal(data, &result)
-		assert.Equal(t, 50, result.Limit, "limit should be normalized in the handler")
-	})
+		return resp, result.Data
+	}
 
-	t.Run("limit above max is clamped", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-			model.QueryRequest{
-				Filters: model.QueryFilters{
-					AgentIDs: []string{"test-agent"},
-				},
-				Limit: 9999,
-			})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp1, created := create(body)
+	require.Equal(t, http.StatusCreated, resp1.StatusCode)
+	require.NotEmpty(t, created.RawKey, "server-generated key is returned on first create")
 
-		var result struct {
-			Limit int `json:"limit"`
-		}
-		data, _ := io.ReadAll(resp.Body)
-		_ = json.Unmarshal(data, &result)
-		assert.Equal(t, 1000, result.Limit, "limit should be clamped in the handler")
-	})
+	// A retry replays the original agent instead of failing with
+	// "agent_id already exists".
+	resp2, replayed := create(body)
+	require.Equal(t, http.StatusCreated, resp2.StatusCode)
+	assert.Equal(t, created.Agent.ID, replayed.Agent.ID)
+	assert.Equal(t, created.APIKey.ID, replayed.APIKey.ID)
+	assert.Empty(t, replayed.RawKey, "raw key is never stored for replay")
 
-	t.Run("offset above max is clamped", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-			model.QueryRequest{
-				Filters: model.QueryFilters{
-					AgentIDs: []string{"test-agent"},
-				},
-				Limit:  10,
-				Offset: 999999999,
-			})
+	mismatch := body
+	mismatch.Name = "Different Name"
+	resp3, _ := create(mismatch)
+	assert.Equal(t, http.StatusConflict, resp3.StatusCode)
+}
+
+func TestHandleCreateGrant_IdempotencyReplay(t *testing.T) {
+	grantee := "idem-grantee-" + uuid.NewString()[:8]
+	createAgent(testSrv.URL, adminToken, grantee, "Idempotent Grantee", "reader", grantee+"-key")
+
+	key := "grant-idem-" + uuid.NewString()
+	resourceID := "test-agent"
+	body := model.CreateGrantRequest{
+		GranteeAgentID: grantee,
+		ResourceType:   "agent_traces",
+		ResourceID:     &resourceID,
+		Permission:     "read",
+	}
+
+	create := func(body model.CreateGrantRequest) (*http.Response, model.AccessGrant) {
+		resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/grants", adminToken, body, map[string]string{
+			"Idempotency-Key": key,
+		})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
 		var result struct {
-			Offset int `json:"offset"`
+			Data model.AccessGrant `json:"data"`
 		}
 		data, _ := io.ReadAll(resp.Body)
 		_ = json.Unmarshal(data, &result)
-		assert.Equal(t, 100_000, result.Offset, "offset should be clamped in the handler")
-	})
-}
+		return resp, result.Data
+	}
 
-func TestHandleSearch_EmptyQuery(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
-		model.SearchRequest{
-			Query: "",
-			Limit: 5,
-		})
+	resp1, created := create(body)
+	require.Equal(t, http.StatusCreated, resp1.StatusCode)
+	require.NotEqual(t, uuid.Nil, created.ID)
+
+	resp2, replayed := create(body)
+	require.Equal(t, http.StatusCreated, resp2.StatusCode)
+	assert.Equal(t, created.ID, replayed.ID)
+	assert.Equal(t, created.GranteeID, replayed.GranteeID)
+
+	expires := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	mismatch := body
+	mismatch.ExpiresAt = &expires
+	resp3, _ := create(mismatch)
+	assert.Equal(t, http.StatusConflict, resp3.StatusCode)
+
+	// Exactly one grant exists for the grantee.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?grantor_agent_id=admin", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-
-	var errResp model.APIError
+	var list struct {
+		Data []model.AccessGrant `json:"data"`
+	}
 	data, _ := io.ReadAll(resp.Body)
-	_ = json.Unmarshal(data, &errResp)
-	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	assert.Contains(t, errResp.Error.Message, "query")
+	require.NoError(t, json.Unmarshal(data, &list))
+	count := 0
+	for _, g := range list.Data {
+		if g.GranteeID == created.GranteeID {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
 }
 
-func TestHandleSearch_LimitClamp(t *testing.T) {
-	t.Run("limit zero defaults to 100", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
-			model.SearchRequest{
-				Query: "test decision",
-				Limit: 0,
-			})
+func TestAgentIDSuggestions(t *testing.T) {
+	createAgent(testSrv.URL, adminToken, "underwriting-agent", "Underwriting Agent", "agent", "underwriting-key")
+	underwritingToken := getToken(testSrv.URL, "underwriting-agent", "underwriting-key")
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", underwritingToken, model.TraceRequest{
+		AgentID:  "underwriting-agent",
+		Decision: model.TraceDecision{DecisionType: "risk_assessment", Outcome: "approve policy", Confidence: 0.7},
+	})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.NoError(t, testBuf.FlushNow(context.Background()))
+
+	query := func(token string, suggest bool) model.ListResponse {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", token, model.QueryRequest{
+			Filters: model.QueryFilters{AgentIDs: []string{"underwritng-agent"}},
+			Suggest: suggest,
+		})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		// Should succeed (handler defaults limit to 100 when <= 0).
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result model.ListResponse
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		return result
+	}
+
+	t.Run("query suggests the misspelled agent", func(t *testing.T) {
+		result := query(adminToken, true)
+		assert.Empty(t, result.Data)
+		require.NotEmpty(t, result.Suggestions)
+		assert.Equal(t, "underwriting-agent", result.Suggestions[0])
 	})
 
-	t.Run("limit above max defaults to 100", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
-			model.SearchRequest{
-				Query: "test decision",
-				Limit: 9999,
-			})
+	t.Run("query without suggest stays silent", func(t *testing.T) {
+		assert.Empty(t, query(adminToken, false).Suggestions)
+	})
+
+	t.Run("suggestions respect access grants", func(t *testing.T) {
+		assert.NotContains(t, query(agentToken, true).Suggestions, "underwriting-agent")
+	})
+
+	t.Run("check suggests the misspelled agent", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken, model.CheckRequest{
+			DecisionType: "risk_assessment",
+			AgentID:      "underwritng-agent",
+			Suggest:      true,
+			Format:       "concise",
+		})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		// Should succeed (handler clamps limit to 100 when > 1000).
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data struct {
+				Summary     string   `json:"summary"`
+				Suggestions []string `json:"suggestions"`
+			} `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		require.NotEmpty(t, result.Data.Suggestions)
+		assert.Equal(t, "underwriting-agent", result.Data.Suggestions[0])
+		assert.Contains(t, result.Data.Summary, "did you mean underwriting-agent")
 	})
 }
 
-func TestHandleCheck_MissingDecisionType(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
-		model.CheckRequest{
-			DecisionType: "",
-			Limit:        5,
+func TestListDecisionTypes(t *testing.T) {
+	decisionType := "types_list_" + uuid.NewString()[:8]
+	for i := range 2 {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, model.TraceRequest{
+			AgentID:  "test-agent",
+			Decision: model.TraceDecision{DecisionType: decisionType, Outcome: fmt.Sprintf("outcome %d", i), Confidence: 0.6},
+			Context:  map[string]any{"project": "test-project"},
 		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-
-	var errResp model.APIError
-	data, _ := io.ReadAll(resp.Body)
-	_ = json.Unmarshal(data, &errResp)
-	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	assert.Contains(t, errResp.Error.Message, "decision_type")
-}
-
-func TestHandleListConflicts_Pagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=1", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	// Parse the flat list envelope: data is the array, pagination fields are top-level.
-	var result struct {
-		Data    []json.RawMessage `json:"data"`
-		Limit   int               `json:"limit"`
-		Offset  int               `json:"offset"`
-		HasMore bool              `json:"has_more"`
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
 	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
-	require.NoError(t, err)
-
-	// Verify pagination fields are present at the top level.
-	assert.NotNil(t, result.Data, "response data should be an array")
-	assert.Equal(t, 1, result.Limit, "limit should be 1")
-	assert.Equal(t, 0, result.Offset, "offset should default to 0")
-}
-
-func TestHandleDecisionRevisions_InvalidID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-	var errResp model.APIError
-	data, _ := io.ReadAll(resp.Body)
-	_ = json.Unmarshal(data, &errResp)
-	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	assert.Contains(t, errResp.Error.Message, "invalid decision ID")
-}
+	listTypes := func(token string) map[string]storage.DecisionTypeSummary {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decision-types", token, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []storage.DecisionTypeSummary `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		byType := make(map[string]storage.DecisionTypeSummary, len(result.Data))
+		for _, dt := range result.Data {
+			byType[dt.DecisionType] = dt
+		}
+		return byType
+	}
 
-func TestHandleVerifyDecision_NotFound(t *testing.T) {
-	randomID := uuid.New().String()
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+randomID, adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	got, ok := listTypes(adminToken)[decisionType]
+	require.True(t, ok, "admin should see the new decision type")
+	assert.Equal(t, 2, got.Count)
+	assert.False(t, got.LastSeen.IsZero())
 
-	var errResp model.APIError
-	data, _ := io.ReadAll(resp.Body)
-	_ = json.Unmarshal(data, &errResp)
-	assert.Equal(t, model.ErrCodeNotFound, errResp.Error.Code)
+	// A reader with no grants or shared tags sees only its own types.
+	readerID := "types-reader-" + uuid.NewString()[:8]
+	createAgent(testSrv.URL, adminToken, readerID, "Types Reader", "reader", readerID+"-key")
+	readerToken := getToken(testSrv.URL, readerID, readerID+"-key")
+	_, ok = listTypes(readerToken)[decisionType]
+	assert.False(t, ok, "reader without a grant must not see test-agent's decision types")
 }
 
-func TestHandleVerifyDecision_Active(t *testing.T) {
-	// Trace a decision so it gets a content hash.
-	dt := "verify_active_" + uuid.NewString()[:8]
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
+func TestHandleTraceBatch(t *testing.T) {
+	decisionType := "trace_batch_" + uuid.NewString()[:8]
+	traces := make([]model.TraceRequest, 3)
+	for i := range traces {
+		traces[i] = model.TraceRequest{
 			AgentID: "test-agent",
 			Decision: model.TraceDecision{
-				DecisionType: dt,
-				Outcome:      "active decision for verify test",
-				Confidence:   0.9,
-				Reasoning:    ptrStr("testing verify on active decision"),
+				DecisionType: decisionType,
+				Outcome:      fmt.Sprintf("batched outcome %d", i),
+				Confidence:   0.7,
 			},
 			Context: map[string]any{"project": "test-project"},
-		})
-	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
-
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
+		}
 	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
-	decisionID := traceResult.Data.DecisionID
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace/batch", agentToken, model.TraceBatchRequest{Traces: traces})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result map[string]any
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	data, ok := result["data"].(map[string]any)
-	require.True(t, ok, "expected data wrapper in response")
-	assert.Equal(t, "verified", data["status"])
-	assert.Equal(t, true, data["valid"])
-	assert.NotEmpty(t, data["content_hash"])
-	assert.Nil(t, data["retracted_at"], "active decision must not have retracted_at")
+	var created struct {
+		Data []model.TraceBatchResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	require.Len(t, created.Data, 3)
+	seen := map[uuid.UUID]bool{}
+	for i, r := range created.Data {
+		assert.Equal(t, i, r.Index)
+		assert.NotEqual(t, uuid.Nil, r.RunID)
+		assert.False(t, seen[r.DecisionID], "decision IDs must be distinct")
+		seen[r.DecisionID] = true
+
+		d, err := testDB.GetDecision(context.Background(), uuid.Nil, r.DecisionID, storage.GetDecisionOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("batched outcome %d", i), d.Outcome)
+	}
 }
 
-func TestHandleVerifyDecision_Retracted(t *testing.T) {
-	// Trace a decision, retract it, then verify it.
-	dt := "verify_retracted_" + uuid.NewString()[:8]
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: dt,
-				Outcome:      "will be retracted then verified",
-				Confidence:   0.75,
-				Reasoning:    ptrStr("testing verify on retracted decision"),
+func TestHandleTraceBatch_ValidationNamesIndex(t *testing.T) {
+	decisionType := "trace_batch_invalid_" + uuid.NewString()[:8]
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace/batch", agentToken, model.TraceBatchRequest{
+		Traces: []model.TraceRequest{
+			{AgentID: "test-agent", Decision: model.TraceDecision{DecisionType: decisionType, Outcome: "ok", Confidence: 0.5}},
+			{AgentID: "test-agent", Decision: model.TraceDecision{DecisionType: decisionType, Confidence: 0.5}},
+		},
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var apiErr model.APIError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+	assert.Contains(t, apiErr.Error.Message, "traces[1]")
+	assert.Contains(t, apiErr.Error.Message, "decision.outcome is required")
+	details, ok := apiErr.Error.Details.(map[string]any)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, details["index"])
+}
+
+func TestHandleTraceBatch_RollsBackOnFailure(t *testing.T) {
+	decisionType := "trace_batch_rollback_" + uuid.NewString()[:8]
+	missing := uuid.New()
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace/batch", agentToken, model.TraceBatchRequest{
+		Traces: []model.TraceRequest{
+			{
+				AgentID:  "test-agent",
+				Decision: model.TraceDecision{DecisionType: decisionType, Outcome: "first", Confidence: 0.5},
+				Context:  map[string]any{"project": "test-project"},
 			},
-			Context: map[string]any{"project": "test-project"},
-		})
+			{
+				AgentID:      "test-agent",
+				Decision:     model.TraceDecision{DecisionType: decisionType, Outcome: "second", Confidence: 0.5},
+				SupersedesID: &missing,
+				Context:      map[string]any{"project": "test-project"},
+			},
+		},
+	})
 	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
-	decisionID := traceResult.Data.DecisionID
+	var apiErr model.APIError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+	assert.Contains(t, apiErr.Error.Message, "traces[1]")
 
-	// Retract the decision.
-	retractResp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
-		map[string]string{"reason": "verify retraction test"})
-	require.NoError(t, err)
-	defer func() { _ = retractResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, retractResp.StatusCode)
+	// The first trace must not have been committed.
+	var count int
+	require.NoError(t, testDB.Pool().QueryRow(context.Background(),
+		`SELECT count(*) FROM decisions WHERE decision_type = $1`, decisionType).Scan(&count))
+	assert.Zero(t, count)
+}
 
-	// Verify the retracted decision.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
+func TestHandleTraceBatch_ExceedsMax(t *testing.T) {
+	traces := make([]model.TraceRequest, 101)
+	for i := range traces {
+		traces[i] = model.TraceRequest{
+			AgentID:  "test-agent",
+			Decision: model.TraceDecision{DecisionType: "trace_batch_max", Outcome: "x", Confidence: 0.5},
+		}
+	}
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace/batch", agentToken, model.TraceBatchRequest{Traces: traces})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result map[string]any
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-
-	data, ok := result["data"].(map[string]any)
-	require.True(t, ok, "expected data wrapper in response")
-	assert.Equal(t, "retracted", data["status"])
-	assert.NotEmpty(t, data["retracted_at"], "retracted decision must have retracted_at")
-	assert.Equal(t, true, data["verified"], "hash should still verify for retracted decision")
-	assert.NotEmpty(t, data["content_hash"])
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	// Confirm retracted_at parses as a valid timestamp.
-	_, parseErr := time.Parse(time.RFC3339Nano, data["retracted_at"].(string))
-	assert.NoError(t, parseErr, "retracted_at must be a valid RFC3339Nano timestamp")
+func importRequest(t *testing.T, token string, body []byte) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest("POST", testSrv.URL+"/v1/import/decisions", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
 }
 
-func TestHandleSessionView_EmptySession(t *testing.T) {
-	randomID := uuid.New().String()
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+randomID, adminToken, nil)
+func TestHandleImportDecisions(t *testing.T) {
+	agentID := "import-" + uuid.NewString()[:8]
+	validFrom := time.Now().UTC().Add(-time.Hour).Truncate(time.Microsecond)
+	validTo := validFrom.Add(10 * time.Minute)
+	runID := uuid.New()
+	prev := model.Decision{
+		ID: uuid.New(), RunID: runID, AgentID: agentID, DecisionType: "architecture",
+		Outcome: "chose kafka", Confidence: 0.5, ValidFrom: validFrom, ValidTo: &validTo,
+	}
+	head := model.Decision{
+		ID: uuid.New(), RunID: runID, AgentID: agentID, DecisionType: "architecture",
+		Outcome: "chose postgres", Confidence: 0.8, ValidFrom: validTo, SupersedesID: &prev.ID,
+		Alternatives: []model.Alternative{{Label: "kafka"}},
+	}
+	headLine, err := json.Marshal(struct {
+		model.Decision
+		Revisions []model.Decision `json:"revisions"`
+	}{head, []model.Decision{prev}})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body := bytes.Join([][]byte{headLine, []byte(`{"id":`), {}}, []byte("\n"))
 
+	resp := importRequest(t, adminToken, body)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode)
 	var result struct {
-		Data struct {
-			SessionID     string            `json:"session_id"`
-			Decisions     []json.RawMessage `json:"decisions"`
-			DecisionCount int               `json:"decision_count"`
-		} `json:"data"`
+		Data model.ImportDecisionsResult `json:"data"`
 	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 2, result.Data.Inserted)
+	assert.Equal(t, 1, result.Data.Failed)
+	require.Len(t, result.Data.Errors, 1)
+	assert.Equal(t, 2, result.Data.Errors[0].Line)
+
+	got, err := testDB.GetDecision(context.Background(), uuid.Nil, head.ID, storage.GetDecisionOpts{IncludeAlts: true})
 	require.NoError(t, err)
-	assert.Equal(t, randomID, result.Data.SessionID)
-	assert.NotNil(t, result.Data.Decisions, "decisions should be an empty array, not null")
-	assert.Len(t, result.Data.Decisions, 0)
-	assert.Equal(t, 0, result.Data.DecisionCount)
+	require.NotNil(t, got.SupersedesID)
+	assert.Equal(t, prev.ID, *got.SupersedesID)
+	assert.True(t, validTo.Equal(got.ValidFrom))
+	require.Len(t, got.Alternatives, 1)
+	assert.Equal(t, integrity.ComputeContentHash(head.ID, head.DecisionType, head.Outcome, head.Confidence, nil, head.ValidFrom), got.ContentHash)
+
+	// Posting the same line again skips everything.
+	resp2 := importRequest(t, adminToken, headLine)
+	defer func() { _ = resp2.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp2.StatusCode)
+	result.Data = model.ImportDecisionsResult{}
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&result))
+	assert.Equal(t, 0, result.Data.Inserted)
+	assert.Equal(t, 2, result.Data.Skipped)
 }
 
-func TestHandleCreateGrant_Valid(t *testing.T) {
-	// Create a dedicated agent for this test to grant access to.
-	granteeID := fmt.Sprintf("grant-target-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Grant Target", "reader", "grant-target-key")
+func TestHandleImportDecisions_RequiresAdmin(t *testing.T) {
+	resp := importRequest(t, agentToken, []byte("{}\n"))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
 
-	resourceID := "test-agent"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &resourceID,
-			Permission:     "read",
+func TestHandleQuery_EmptyResult(t *testing.T) {
+	agentID := "nonexistent-agent-xxx"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+		model.QueryRequest{
+			Filters: model.QueryFilters{
+				AgentIDs: []string{agentID},
+			},
+			Limit: 10,
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			ID           string `json:"id"`
-			ResourceType string `json:"resource_type"`
-			Permission   string `json:"permission"`
-		} `json:"data"`
+		Data []json.RawMessage `json:"data"`
 	}
 	data, _ := io.ReadAll(resp.Body)
 	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-	assert.NotEmpty(t, result.Data.ID, "grant should have an ID")
-	assert.Equal(t, "agent_traces", result.Data.ResourceType)
-	assert.Equal(t, "read", result.Data.Permission)
+	// Verify the array is present and empty (not null).
+	assert.NotNil(t, result.Data, "decisions should be an empty array, not null")
+	assert.Len(t, result.Data, 0)
 }
 
-func TestHandleCreateAndListKeys(t *testing.T) {
-	// Create a dedicated agent for this test to own the key.
-	agentID := fmt.Sprintf("key-agent-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, agentID, "Key Agent", "agent", "key-agent-init")
+func TestHandleQuery_LimitBounds(t *testing.T) {
+	t.Run("limit zero uses default", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+			model.QueryRequest{
+				Filters: model.QueryFilters{
+					AgentIDs: []string{"test-agent"},
+				},
+				Limit: 0,
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// Create key
-	req := model.CreateKeyRequest{AgentID: agentID, Label: "test-key"}
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		var result struct {
+			Limit int `json:"limit"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(data, &result)
+		assert.Equal(t, 50, result.Limit, "limit should be normalized in the handler")
+	})
 
-	var created struct {
-		Data model.APIKeyWithRawKey `json:"data"`
-	}
-	data, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(data, &created))
-	assert.NotEmpty(t, created.Data.RawKey)
-	assert.NotEqual(t, uuid.Nil, created.Data.ID)
+	t.Run("limit above max is clamped", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+			model.QueryRequest{
+				Filters: model.QueryFilters{
+					AgentIDs: []string{"test-agent"},
+				},
+				Limit: 9999,
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// List keys and ensure the created key's prefix appears (raw key not returned)
-	resp2, err := authedRequest("GET", testSrv.URL+"/v1/keys", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+		var result struct {
+			Limit int `json:"limit"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(data, &result)
+		assert.Equal(t, 1000, result.Limit, "limit should be clamped in the handler")
+	})
 
-	var list struct {
-		Data []model.APIKey `json:"data"`
-	}
-	listData, _ := io.ReadAll(resp2.Body)
-	require.NoError(t, json.Unmarshal(listData, &list))
-	found := false
-	for _, k := range list.Data {
-		if k.ID == created.Data.ID {
-			found = true
-			break
+	t.Run("offset above max is clamped", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+			model.QueryRequest{
+				Filters: model.QueryFilters{
+					AgentIDs: []string{"test-agent"},
+				},
+				Limit:  10,
+				Offset: 999999999,
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Offset int `json:"offset"`
 		}
-	}
-	assert.True(t, found, "created key should appear in list")
+		data, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(data, &result)
+		assert.Equal(t, 100_000, result.Offset, "offset should be clamped in the handler")
+	})
 }
 
-func TestHandleRevokeKey_ValidAndRevokedKeyReturns401(t *testing.T) {
-	// Create agent + key
-	tagent := fmt.Sprintf("revoke-agent-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, tagent, "Revoke Agent", "agent", "revoke-init-key")
-
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, model.CreateKeyRequest{AgentID: tagent, Label: "revoke-test"})
+func TestHandleSearch_EmptyQuery(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+		model.SearchRequest{
+			Query: "",
+			Limit: 5,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var created struct {
-		Data model.APIKeyWithRawKey `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-	// Verify the raw key works before revocation: get a token successfully.
-	pre := getToken(testSrv.URL, tagent, created.Data.RawKey)
-	assert.NotEmpty(t, pre)
+	var errResp model.APIError
+	data, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(data, &errResp)
+	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, "query")
+}
 
-	// Revoke the key.
-	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/"+created.Data.ID.String(), nil)
-	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
-	respDel, err := http.DefaultClient.Do(reqDel)
-	require.NoError(t, err)
-	defer func() { _ = respDel.Body.Close() }()
-	assert.Equal(t, http.StatusNoContent, respDel.StatusCode)
+func TestHandleSearch_LimitClamp(t *testing.T) {
+	t.Run("limit zero defaults to 100", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+			model.SearchRequest{
+				Query: "test decision",
+				Limit: 0,
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		// Should succeed (handler defaults limit to 100 when <= 0).
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
 
-	// After revocation, requesting a NEW token with the old key must return 401.
-	// (JWTs already issued are stateless and stay valid until expiry.)
-	authBody, _ := json.Marshal(model.AuthTokenRequest{AgentID: tagent, APIKey: created.Data.RawKey})
-	respNew, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(authBody))
-	require.NoError(t, err)
-	defer func() { _ = respNew.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, respNew.StatusCode, "revoked key must not issue new tokens")
+	t.Run("limit above max defaults to 100", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+			model.SearchRequest{
+				Query: "test decision",
+				Limit: 9999,
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		// Should succeed (handler clamps limit to 100 when > 1000).
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
 }
 
-func TestHandleRevokeKey_NotFound(t *testing.T) {
-	// Attempt to revoke a random UUID
-	randID := uuid.New()
-	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/"+randID.String(), nil)
-	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
-	respDel, err := http.DefaultClient.Do(reqDel)
-	require.NoError(t, err)
-	defer func() { _ = respDel.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, respDel.StatusCode)
-}
+func TestHandleSearch_RankingParams(t *testing.T) {
+	now := time.Now().UTC()
+	earlier := now.Add(-time.Hour)
+	negative, zero := -1.0, 0.0
 
-func TestHandleRotateKey_Valid(t *testing.T) {
-	// Create agent + key
-	rAgent := fmt.Sprintf("rotate-agent-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, rAgent, "Rotate Agent", "agent", "rotate-init-key")
+	for name, tc := range map[string]struct {
+		req    model.SearchRequest
+		status int
+	}{
+		"no decay": {model.SearchRequest{Query: "test decision", RecencyHalfLifeDays: &zero}, http.StatusOK},
+		"window":   {model.SearchRequest{Query: "test decision", TimeRange: &model.TimeRange{From: &earlier}}, http.StatusOK},
+		"negative half-life": {
+			model.SearchRequest{Query: "test decision", RecencyHalfLifeDays: &negative}, http.StatusBadRequest,
+		},
+		"inverted window": {
+			model.SearchRequest{Query: "test decision", TimeRange: &model.TimeRange{From: &now, To: &earlier}}, http.StatusBadRequest,
+		},
+		"both windows": {
+			model.SearchRequest{
+				Query:     "test decision",
+				TimeRange: &model.TimeRange{From: &earlier},
+				Filters:   model.QueryFilters{TimeRange: &model.TimeRange{From: &earlier}},
+			}, http.StatusBadRequest,
+		},
+	} {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken, tc.req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, tc.status, resp.StatusCode, name)
+	}
+}
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, model.CreateKeyRequest{AgentID: rAgent, Label: "rotate-test"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+// TestHandleSearch_ScopedToGrantedAgents proves search ranks only within the
+// caller's granted agents: a limit-1 search still finds the caller's own
+// decision even when an ungranted agent has many better-matching ones.
+func TestHandleSearch_ScopedToGrantedAgents(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	term := "grantscope" + suffix
+	otherAgent := "ungranted-" + suffix
 
-	var created struct {
-		Data model.APIKeyWithRawKey `json:"data"`
+	trace := func(token, agentID, outcome string) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", token, model.TraceRequest{
+			AgentID: agentID,
+			Decision: model.TraceDecision{
+				DecisionType: "search_scope",
+				Outcome:      outcome,
+				Confidence:   0.9,
+			},
+		})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &created))
+	for i := range 5 {
+		trace(adminToken, otherAgent, fmt.Sprintf("%s %s %s option %d", term, term, term, i))
+	}
+	trace(agentToken, "test-agent", term+" own decision")
 
-	// Rotate
-	respRot, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+created.Data.ID.String()+"/rotate", adminToken, nil)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+		model.SearchRequest{Query: term, Limit: 1})
 	require.NoError(t, err)
-	defer func() { _ = respRot.Body.Close() }()
-	require.Equal(t, http.StatusOK, respRot.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var rot struct {
-		Data model.RotateKeyResponse `json:"data"`
+	var result struct {
+		Data []model.SearchResult `json:"data"`
 	}
-	rotBody, _ := io.ReadAll(respRot.Body)
-	require.NoError(t, json.Unmarshal(rotBody, &rot))
-	assert.NotEmpty(t, rot.Data.NewKey.RawKey)
-	assert.Equal(t, created.Data.ID, rot.Data.RevokedKeyID)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Data, 1, "ungranted matches must not crowd out the caller's own decision")
+	assert.Equal(t, "test-agent", result.Data[0].Decision.AgentID)
 
-	// After rotation, the OLD raw key must no longer issue new tokens.
-	oldAuthBody, _ := json.Marshal(model.AuthTokenRequest{AgentID: rAgent, APIKey: created.Data.RawKey})
-	respOld, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(oldAuthBody))
+	t.Run("explicit filter on an ungranted agent returns nothing", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken, model.SearchRequest{
+			Query:   term,
+			Filters: model.QueryFilters{AgentIDs: []string{otherAgent}},
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []model.SearchResult `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Empty(t, result.Data)
+	})
+}
+
+func TestHandleCheck_MissingDecisionType(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
+		model.CheckRequest{
+			DecisionType: "",
+			Limit:        5,
+		})
 	require.NoError(t, err)
-	defer func() { _ = respOld.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, respOld.StatusCode, "rotated (revoked) key must not issue new tokens")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-	// New raw key should obtain a fresh token.
-	newToken := getToken(testSrv.URL, rAgent, rot.Data.NewKey.RawKey)
-	assert.NotEmpty(t, newToken)
+	var errResp model.APIError
+	data, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(data, &errResp)
+	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, "decision_type")
 }
 
-func TestHandleUpdateAgentTags_Dedup(t *testing.T) {
-	// Create a dedicated agent for tag updates.
-	tagAgentID := fmt.Sprintf("tag-agent-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, tagAgentID, "Tag Agent", "agent", "tag-agent-key")
-
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+tagAgentID+"/tags", adminToken,
-		model.UpdateAgentTagsRequest{
-			Tags: []string{"a", "b", "a"},
-		})
+func TestHandleListConflicts_Pagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=1", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
+	// Parse the flat list envelope: data is the array, pagination fields are top-level.
 	var result struct {
-		Data struct {
-			Tags []string `json:"tags"`
-		} `json:"data"`
+		Data    []json.RawMessage `json:"data"`
+		Limit   int               `json:"limit"`
+		Offset  int               `json:"offset"`
+		HasMore bool              `json:"has_more"`
 	}
 	data, _ := io.ReadAll(resp.Body)
 	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-	// The handler deduplicates tags while preserving order.
-	assert.Equal(t, []string{"a", "b"}, result.Data.Tags, "duplicate tags should be removed")
-}
 
-func TestRequestIDMiddleware_ClientProvided(t *testing.T) {
-	clientReqID := "my-custom-request-id-12345"
-	req, err := http.NewRequest("GET", testSrv.URL+"/health", nil)
-	require.NoError(t, err)
-	req.Header.Set("X-Request-ID", clientReqID)
+	// Verify pagination fields are present at the top level.
+	assert.NotNil(t, result.Data, "response data should be an array")
+	assert.Equal(t, 1, result.Limit, "limit should be 1")
+	assert.Equal(t, 0, result.Offset, "offset should default to 0")
+}
 
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleDecisionRevisions_InvalidID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	assert.Equal(t, clientReqID, resp.Header.Get("X-Request-ID"),
-		"response should echo back the client-provided X-Request-ID")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var errResp model.APIError
+	data, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(data, &errResp)
+	assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, "invalid decision ID")
 }
 
-func TestHandleTemporalQuery_ZeroAsOf(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken,
-		model.TemporalQueryRequest{
-			AsOf:  time.Time{},
-			Limit: 10,
-		})
+func TestHandleVerifyDecision_NotFound(t *testing.T) {
+	randomID := uuid.New().String()
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+randomID, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// A zero AsOf is technically valid (will match nothing since all
-	// transaction_time values are after epoch). The handler should
-	// return 200 with empty results rather than erroring.
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 
-	var result struct {
-		Data struct {
-			Decisions []json.RawMessage `json:"decisions"`
-		} `json:"data"`
-	}
+	var errResp model.APIError
 	data, _ := io.ReadAll(resp.Body)
-	_ = json.Unmarshal(data, &result)
-	assert.NotNil(t, result.Data.Decisions)
-	assert.Len(t, result.Data.Decisions, 0, "zero AsOf should return no decisions")
+	_ = json.Unmarshal(data, &errResp)
+	assert.Equal(t, model.ErrCodeNotFound, errResp.Error.Code)
 }
 
-func TestHandleQuery_PaginationResponse(t *testing.T) {
-	// Ensure there is at least one decision.
-	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+func TestHandleVerifyDecision_Active(t *testing.T) {
+	// Trace a decision so it gets a content hash.
+	dt := "verify_active_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
 		model.TraceRequest{
 			AgentID: "test-agent",
 			Decision: model.TraceDecision{
-				DecisionType: "pagination_test",
-				Outcome:      "verifying pagination fields",
-				Confidence:   0.7,
+				DecisionType: dt,
+				Outcome:      "active decision for verify test",
+				Confidence:   0.9,
+				Reasoning:    ptrStr("testing verify on active decision"),
 			},
 			Context: map[string]any{"project": "test-project"},
 		})
 	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-	dt := "pagination_test"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-		model.QueryRequest{
-			Filters: model.QueryFilters{
-				AgentIDs:     []string{"test-agent"},
-				DecisionType: &dt,
-			},
-			Limit:  5,
-			Offset: 0,
-		})
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var result struct {
-		Data    []json.RawMessage `json:"data"`
-		Total   *int              `json:"total"`
-		Limit   int               `json:"limit"`
-		Offset  int               `json:"offset"`
-		HasMore bool              `json:"has_more"`
-	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
-	require.NoError(t, err)
+	var result map[string]any
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
 
-	assert.Equal(t, 5, result.Limit, "response should include the requested limit")
-	assert.Equal(t, 0, result.Offset, "response should include the requested offset")
-	// total is present when no access filtering reduces the result set.
-	assert.NotNil(t, result.Total, "total should be present for non-filtered results")
-	assert.GreaterOrEqual(t, *result.Total, 1, "total should be at least 1")
+	data, ok := result["data"].(map[string]any)
+	require.True(t, ok, "expected data wrapper in response")
+	assert.Equal(t, "verified", data["status"])
+	assert.Equal(t, true, data["valid"])
+	assert.NotEmpty(t, data["content_hash"])
+	assert.Nil(t, data["retracted_at"], "active decision must not have retracted_at")
 }
 
-func TestHandleCreateRun_Valid(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var result struct {
-		Data model.AgentRun `json:"data"`
-	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
-	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, result.Data.ID, "run should have a non-nil UUID")
-	assert.Equal(t, "test-agent", result.Data.AgentID)
-	assert.Equal(t, model.RunStatusRunning, result.Data.Status, "new run should have status 'running'")
-}
+func TestHandleGetDecisionProof(t *testing.T) {
+	ctx := context.Background()
+	batchStart := time.Now().Add(-time.Second)
 
-func TestHandleTemporalQuery_Valid(t *testing.T) {
-	// First, create a decision so there is at least one result.
-	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
 		model.TraceRequest{
 			AgentID: "test-agent",
 			Decision: model.TraceDecision{
-				DecisionType: "temporal_test",
-				Outcome:      "temporal query validation",
-				Confidence:   0.85,
+				DecisionType: "proof_" + uuid.NewString()[:8],
+				Outcome:      "decision covered by an inclusion proof",
+				Confidence:   0.8,
 			},
 			Context: map[string]any{"project": "test-project"},
 		})
 	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	var traced struct {
+		Data model.TraceResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(traceResp.Body).Decode(&traced))
+	decisionID := traced.Data.DecisionID
 
-	// Query with AsOf set to now — includes all decisions committed before this moment.
-	// The trace above may still be in the buffer, but prior test runs create enough
-	// committed decisions that the assertion below is reliably satisfied.
-	asOf := time.Now()
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken,
-		model.TemporalQueryRequest{
-			AsOf: asOf,
-			Filters: model.QueryFilters{
-				AgentIDs: []string{"test-agent"},
-			},
-			Limit: 10,
-		})
+	time.Sleep(10 * time.Millisecond)
+	batchEnd := time.Now()
+	leaves, err := testDB.GetDecisionHashesForBatch(ctx, uuid.Nil, batchStart, batchEnd)
+	require.NoError(t, err)
+	root, err := integrity.BuildMerkleRoot(leaves)
+	require.NoError(t, err)
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, storage.IntegrityProof{
+		OrgID:         uuid.Nil,
+		BatchStart:    batchStart,
+		BatchEnd:      batchEnd,
+		DecisionCount: len(leaves),
+		RootHash:      root,
+		CreatedAt:     time.Now(),
+	}))
+
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/integrity/proof/"+decisionID.String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
 		Data struct {
-			AsOf      string            `json:"as_of"`
-			Decisions []json.RawMessage `json:"decisions"`
+			DecisionID  uuid.UUID                   `json:"decision_id"`
+			ContentHash string                      `json:"content_hash"`
+			RootHash    string                      `json:"root_hash"`
+			ProofPath   []integrity.MerkleProofStep `json:"proof_path"`
+			Verified    bool                        `json:"verified"`
 		} `json:"data"`
 	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, decisionID, result.Data.DecisionID)
+	assert.Equal(t, root, result.Data.RootHash)
+	assert.True(t, result.Data.Verified)
+	// An external verifier needs only the hash, the path, and the root.
+	assert.True(t, integrity.VerifyMerkleProof(result.Data.ContentHash, result.Data.ProofPath, root))
+
+	// A reader without a grant on test-agent cannot fetch the proof.
+	readerID := "proof-reader-" + uuid.NewString()[:8]
+	createAgent(testSrv.URL, adminToken, readerID, "Proof Reader", "reader", readerID+"-key")
+	readerToken := getToken(testSrv.URL, readerID, readerID+"-key")
+	denied, err := authedRequest("GET", testSrv.URL+"/v1/integrity/proof/"+decisionID.String(), readerToken, nil)
 	require.NoError(t, err)
+	defer func() { _ = denied.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, denied.StatusCode)
+}
 
-	// Verify the response structure includes as_of; decisions may be empty if the
-	// buffer hasn't flushed, but the endpoint must return a valid response.
-	assert.NotEmpty(t, result.Data.AsOf, "response should include as_of timestamp")
+func TestHandleGetDecisionProof_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/integrity/proof/"+uuid.NewString(), adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleSearch_ValidQuery(t *testing.T) {
-	// Ensure there is a decision to find.
-	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+func TestHandleVerifyDecision_Retracted(t *testing.T) {
+	// Trace a decision, retract it, then verify it.
+	dt := "verify_retracted_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
 		model.TraceRequest{
 			AgentID: "test-agent",
 			Decision: model.TraceDecision{
-				DecisionType: "search_validation",
-				Outcome:      "validated search response structure",
-				Confidence:   0.9,
+				DecisionType: dt,
+				Outcome:      "will be retracted then verified",
+				Confidence:   0.75,
+				Reasoning:    ptrStr("testing verify on retracted decision"),
 			},
 			Context: map[string]any{"project": "test-project"},
 		})
 	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
-		model.SearchRequest{
-			Query: "validated search response",
-			Limit: 10,
-		})
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+
+	// Retract the decision.
+	retractResp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
+		map[string]string{"reason": "verify retraction test"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = retractResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, retractResp.StatusCode)
 
-	var result struct {
-		Data  []json.RawMessage `json:"data"`
-		Total int               `json:"total"`
-	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
+	// Verify the retracted decision.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
 	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// Verify response structure: results array and total count are present.
-	assert.NotNil(t, result.Data, "results should be an array, not null")
-	assert.GreaterOrEqual(t, result.Total, 0, "total should be non-negative")
-}
+	var result map[string]any
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
 
-func TestHandleSessionView_WithDecisions(t *testing.T) {
-	// Create a decision with a specific session_id via the X-Akashi-Session header.
-	sessionID := uuid.New()
-	body, _ := json.Marshal(model.TraceRequest{
-		AgentID: "test-agent",
-		Decision: model.TraceDecision{
-			DecisionType: "session_view_test",
-			Outcome:      "decision within session",
-			Confidence:   0.75,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
+	data, ok := result["data"].(map[string]any)
+	require.True(t, ok, "expected data wrapper in response")
+	assert.Equal(t, "retracted", data["status"])
+	assert.NotEmpty(t, data["retracted_at"], "retracted decision must have retracted_at")
+	assert.Equal(t, true, data["verified"], "hash should still verify for retracted decision")
+	assert.NotEmpty(t, data["content_hash"])
 
-	req, err := http.NewRequest("POST", testSrv.URL+"/v1/trace", bytes.NewReader(body))
-	require.NoError(t, err)
-	req.Header.Set("Authorization", "Bearer "+agentToken)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Akashi-Session", sessionID.String())
+	// Confirm retracted_at parses as a valid timestamp.
+	_, parseErr := time.Parse(time.RFC3339Nano, data["retracted_at"].(string))
+	assert.NoError(t, parseErr, "retracted_at must be a valid RFC3339Nano timestamp")
+}
 
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleSessionView_EmptySession(t *testing.T) {
+	randomID := uuid.New().String()
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+randomID, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	// Retrieve the session view.
-	resp2, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+sessionID.String(), agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
 		Data struct {
 			SessionID     string            `json:"session_id"`
 			Decisions     []json.RawMessage `json:"decisions"`
 			DecisionCount int               `json:"decision_count"`
-			Summary       *struct {
-				StartedAt     string         `json:"started_at"`
-				EndedAt       string         `json:"ended_at"`
-				DurationSecs  float64        `json:"duration_secs"`
-				DecisionTypes map[string]int `json:"decision_types"`
-				AvgConfidence float64        `json:"avg_confidence"`
-			} `json:"summary"`
 		} `json:"data"`
 	}
-	data, _ := io.ReadAll(resp2.Body)
+	data, _ := io.ReadAll(resp.Body)
 	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-
-	assert.Equal(t, sessionID.String(), result.Data.SessionID)
-	assert.GreaterOrEqual(t, result.Data.DecisionCount, 1, "should have at least one decision in session")
-	assert.Len(t, result.Data.Decisions, result.Data.DecisionCount, "decisions array length should match decision_count")
-
-	// When there are decisions, summary should be present.
-	require.NotNil(t, result.Data.Summary, "summary should be present when session has decisions")
-	assert.NotEmpty(t, result.Data.Summary.StartedAt, "summary should have started_at")
-	assert.NotEmpty(t, result.Data.Summary.EndedAt, "summary should have ended_at")
-	assert.Contains(t, result.Data.Summary.DecisionTypes, "session_view_test",
-		"decision_types should contain the traced decision type")
-	assert.InDelta(t, 0.75, result.Data.Summary.AvgConfidence, 0.01,
-		"avg_confidence should reflect the traced decision's confidence")
+	assert.Equal(t, randomID, result.Data.SessionID)
+	assert.NotNil(t, result.Data.Decisions, "decisions should be an empty array, not null")
+	assert.Len(t, result.Data.Decisions, 0)
+	assert.Equal(t, 0, result.Data.DecisionCount)
 }
 
-func TestHandleDeleteGrant(t *testing.T) {
-	// Create a dedicated grantee agent.
-	granteeID := fmt.Sprintf("del-grantee-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Delete Grantee", "reader", "del-grantee-key")
+func TestHandleCreateGrant_Valid(t *testing.T) {
+	// Create a dedicated agent for this test to grant access to.
+	granteeID := fmt.Sprintf("grant-target-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Grant Target", "reader", "grant-target-key")
 
-	// Create a grant.
 	resourceID := "test-agent"
 	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
 		model.CreateGrantRequest{
@@ -2471,858 +2872,691 @@ func TestHandleDeleteGrant(t *testing.T) {
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	// Extract the grant ID from the response.
-	var createResult struct {
+	var result struct {
 		Data struct {
-			ID string `json:"id"`
+			ID           string `json:"id"`
+			ResourceType string `json:"resource_type"`
+			Permission   string `json:"permission"`
 		} `json:"data"`
 	}
 	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &createResult)
-	require.NoError(t, err)
-	grantID := createResult.Data.ID
-	require.NotEmpty(t, grantID, "grant should have an ID")
-
-	// Delete the grant.
-	resp2, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+grantID, adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusNoContent, resp2.StatusCode,
-		"DELETE grant should return 204 No Content")
-
-	// Verify the grant is gone by trying to delete it again.
-	resp3, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+grantID, adminToken, nil)
+	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-	defer func() { _ = resp3.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp3.StatusCode,
-		"second DELETE should return 404 since grant was already deleted")
+	assert.NotEmpty(t, result.Data.ID, "grant should have an ID")
+	assert.Equal(t, "agent_traces", result.Data.ResourceType)
+	assert.Equal(t, "read", result.Data.Permission)
 }
 
-func TestHandleCompleteRun(t *testing.T) {
-	// Create a run.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleCreateAndListKeys(t *testing.T) {
+	// Create a dedicated agent for this test to own the key.
+	agentID := fmt.Sprintf("key-agent-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, agentID, "Key Agent", "agent", "key-agent-init")
+
+	// Create key
+	req := model.CreateKeyRequest{AgentID: agentID, Label: "test-key"}
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
+	var created struct {
+		Data model.APIKeyWithRawKey `json:"data"`
 	}
 	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &runResult)
-	require.NoError(t, err)
-	runID := runResult.Data.ID
+	require.NoError(t, json.Unmarshal(data, &created))
+	assert.NotEmpty(t, created.Data.RawKey)
+	assert.NotEqual(t, uuid.Nil, created.Data.ID)
 
-	t.Run("complete with default status", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken,
-			model.CompleteRunRequest{})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// List keys and ensure the created key's prefix appears (raw key not returned)
+	resp2, err := authedRequest("GET", testSrv.URL+"/v1/keys", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
 
-		var result struct {
-			Data model.AgentRun `json:"data"`
+	var list struct {
+		Data []model.APIKey `json:"data"`
+	}
+	listData, _ := io.ReadAll(resp2.Body)
+	require.NoError(t, json.Unmarshal(listData, &list))
+	found := false
+	for _, k := range list.Data {
+		if k.ID == created.Data.ID {
+			found = true
+			break
 		}
-		data, _ := io.ReadAll(resp.Body)
-		err = json.Unmarshal(data, &result)
-		require.NoError(t, err)
-		assert.Equal(t, model.RunStatusCompleted, result.Data.Status,
-			"default completion status should be 'completed'")
-		assert.NotNil(t, result.Data.CompletedAt, "completed run should have completed_at set")
-	})
-
-	t.Run("complete with failed status", func(t *testing.T) {
-		// Create another run to test failure status.
-		resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-			model.CreateRunRequest{AgentID: "test-agent"})
-		require.NoError(t, err)
-		defer func() { _ = resp2.Body.Close() }()
-		require.Equal(t, http.StatusCreated, resp2.StatusCode)
+	}
+	assert.True(t, found, "created key should appear in list")
+}
 
-		var runResult2 struct {
-			Data model.AgentRun `json:"data"`
-		}
-		data2, _ := io.ReadAll(resp2.Body)
-		err = json.Unmarshal(data2, &runResult2)
-		require.NoError(t, err)
-		runID2 := runResult2.Data.ID
+func TestHandleRevokeKey_ValidAndRevokedKeyReturns401(t *testing.T) {
+	// Create agent + key
+	tagent := fmt.Sprintf("revoke-agent-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, tagent, "Revoke Agent", "agent", "revoke-init-key")
 
-		resp3, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID2.String()+"/complete", agentToken,
-			model.CompleteRunRequest{Status: "failed"})
-		require.NoError(t, err)
-		defer func() { _ = resp3.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp3.StatusCode)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, model.CreateKeyRequest{AgentID: tagent, Label: "revoke-test"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-		var result struct {
-			Data model.AgentRun `json:"data"`
-		}
-		data3, _ := io.ReadAll(resp3.Body)
-		err = json.Unmarshal(data3, &result)
-		require.NoError(t, err)
-		assert.Equal(t, model.RunStatusFailed, result.Data.Status,
-			"run should have status 'failed'")
-	})
+	var created struct {
+		Data model.APIKeyWithRawKey `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &created))
 
-	t.Run("complete with invalid status", func(t *testing.T) {
-		// Create yet another run.
-		resp4, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-			model.CreateRunRequest{AgentID: "test-agent"})
-		require.NoError(t, err)
-		defer func() { _ = resp4.Body.Close() }()
-		require.Equal(t, http.StatusCreated, resp4.StatusCode)
+	// Verify the raw key works before revocation: get a token successfully.
+	pre := getToken(testSrv.URL, tagent, created.Data.RawKey)
+	assert.NotEmpty(t, pre)
 
-		var runResult3 struct {
-			Data model.AgentRun `json:"data"`
-		}
-		data4, _ := io.ReadAll(resp4.Body)
-		err = json.Unmarshal(data4, &runResult3)
-		require.NoError(t, err)
+	// Revoke the key.
+	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/"+created.Data.ID.String(), nil)
+	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
+	respDel, err := http.DefaultClient.Do(reqDel)
+	require.NoError(t, err)
+	defer func() { _ = respDel.Body.Close() }()
+	assert.Equal(t, http.StatusNoContent, respDel.StatusCode)
 
-		resp5, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult3.Data.ID.String()+"/complete", agentToken,
-			model.CompleteRunRequest{Status: "invalid_status"})
-		require.NoError(t, err)
-		defer func() { _ = resp5.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp5.StatusCode,
-			"invalid status should return 400")
-	})
+	// After revocation, requesting a NEW token with the old key must return 401.
+	// (JWTs already issued are stateless and stay valid until expiry.)
+	authBody, _ := json.Marshal(model.AuthTokenRequest{AgentID: tagent, APIKey: created.Data.RawKey})
+	respNew, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(authBody))
+	require.NoError(t, err)
+	defer func() { _ = respNew.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, respNew.StatusCode, "revoked key must not issue new tokens")
+}
 
-	t.Run("complete nonexistent run", func(t *testing.T) {
-		fakeRunID := uuid.New().String()
-		resp6, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+fakeRunID+"/complete", agentToken,
-			model.CompleteRunRequest{})
-		require.NoError(t, err)
-		defer func() { _ = resp6.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp6.StatusCode,
-			"completing a nonexistent run should return 404")
-	})
+func TestHandleRevokeKey_NotFound(t *testing.T) {
+	// Attempt to revoke a random UUID
+	randID := uuid.New()
+	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/"+randID.String(), nil)
+	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
+	respDel, err := http.DefaultClient.Do(reqDel)
+	require.NoError(t, err)
+	defer func() { _ = respDel.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, respDel.StatusCode)
 }
 
-func TestHandleHealth_ResponseFields(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/health")
+func TestHandleRotateKey_Valid(t *testing.T) {
+	// Create agent + key
+	rAgent := fmt.Sprintf("rotate-agent-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, rAgent, "Rotate Agent", "agent", "rotate-init-key")
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, model.CreateKeyRequest{AgentID: rAgent, Label: "rotate-test"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	var result struct {
-		Data model.HealthResponse `json:"data"`
+	var created struct {
+		Data model.APIKeyWithRawKey `json:"data"`
 	}
-	data, _ := io.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &result)
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &created))
+
+	// Rotate
+	respRot, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+created.Data.ID.String()+"/rotate", adminToken, nil)
 	require.NoError(t, err)
+	defer func() { _ = respRot.Body.Close() }()
+	require.Equal(t, http.StatusOK, respRot.StatusCode)
 
-	assert.Equal(t, "healthy", result.Data.Status)
-	assert.Equal(t, "test", result.Data.Version, "version should match the configured test version")
-	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0), "uptime should be non-negative")
-	assert.Equal(t, "connected", result.Data.Postgres, "postgres should be connected")
-	assert.Equal(t, "ok", result.Data.BufferStatus, "buffer status should be ok under low load")
-	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0, "buffer depth should be non-negative")
-}
+	var rot struct {
+		Data model.RotateKeyResponse `json:"data"`
+	}
+	rotBody, _ := io.ReadAll(respRot.Body)
+	require.NoError(t, json.Unmarshal(rotBody, &rot))
+	assert.NotEmpty(t, rot.Data.NewKey.RawKey)
+	assert.Equal(t, created.Data.ID, rot.Data.RevokedKeyID)
 
-func TestSecurityHeaders_AllRequired(t *testing.T) {
-	// Verify security headers on an authenticated endpoint as well.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent", agentToken, nil)
+	// After rotation, the OLD raw key must no longer issue new tokens.
+	oldAuthBody, _ := json.Marshal(model.AuthTokenRequest{AgentID: rAgent, APIKey: created.Data.RawKey})
+	respOld, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(oldAuthBody))
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = respOld.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, respOld.StatusCode, "rotated (revoked) key must not issue new tokens")
 
-	// All security headers should be present on authenticated endpoints too.
-	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"),
-		"X-Content-Type-Options should be set on authenticated endpoints")
-	assert.Contains(t, resp.Header.Get("Strict-Transport-Security"), "max-age=63072000",
-		"HSTS should be present on authenticated endpoints")
-	assert.Contains(t, resp.Header.Get("Content-Security-Policy"), "default-src",
-		"CSP should be present on authenticated endpoints")
-	assert.Contains(t, resp.Header.Get("Permissions-Policy"), "camera=()",
-		"Permissions-Policy should be present on authenticated endpoints")
+	// New raw key should obtain a fresh token.
+	newToken := getToken(testSrv.URL, rAgent, rot.Data.NewKey.RawKey)
+	assert.NotEmpty(t, newToken)
 }
 
-// ---- HandleGetDecision ---------------------------------------------------
+func TestHandleRotateAgentKey(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "rotate-self-" + suffix
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	oldToken := getToken(testSrv.URL, agentID, agentID+"-key")
+	otherID := "rotate-other-" + suffix
+	createAgent(testSrv.URL, adminToken, otherID, otherID, "agent", otherID+"-key")
+	otherToken := getToken(testSrv.URL, otherID, otherID+"-key")
 
-func TestHandleGetDecision(t *testing.T) {
-	// Trace a decision to get a valid ID.
-	dt := "get_decision_test_" + uuid.NewString()[:8]
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: dt,
-				Outcome:      "retrieved ok",
-				Confidence:   0.8,
-				Reasoning:    ptrStr("for get decision test"),
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID, OrgID: uuid.Nil})
 	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
-
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	data, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(data, &traceResult))
-	decisionID := traceResult.Data.DecisionID
-	require.NotEqual(t, uuid.Nil, decisionID)
-
-	t.Run("happy path returns decision", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var result struct {
-			Data model.Decision `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		assert.Equal(t, decisionID, result.Data.ID)
-		assert.Equal(t, dt, result.Data.DecisionType)
-		assert.Equal(t, "retrieved ok", result.Data.Outcome)
-	})
-
-	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", agentToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
-
-	t.Run("nonexistent ID returns 404", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), agentToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-	})
-}
-
-// ---- HandleTraceHealth (exercises tracehealth.New + tracehealth.Compute) -
-
-func TestHandleTraceHealth(t *testing.T) {
-	t.Run("returns valid metrics structure", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var result struct {
-			Data struct {
-				Status       string `json:"status"`
-				Completeness *struct {
-					TotalDecisions int `json:"total_decisions"`
-				} `json:"completeness"`
-				Gaps []string `json:"gaps"`
-			} `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		assert.Contains(t, []string{"healthy", "needs_attention", "insufficient_data"}, result.Data.Status)
-		assert.NotNil(t, result.Data.Completeness)
-		assert.NotNil(t, result.Data.Gaps)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: uuid.Nil,
+		DecisionType: "architecture", Outcome: "chose key rotation", Confidence: 0.8, Metadata: map[string]any{},
 	})
+	require.NoError(t, err)
 
-	t.Run("requires authentication", func(t *testing.T) {
-		req, _ := http.NewRequest("GET", testSrv.URL+"/v1/trace-health", nil)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-	})
-}
+	// A non-admin agent cannot rotate someone else's key.
+	forbidden, err := authedRequest("POST", testSrv.URL+"/v1/agents/"+agentID+"/rotate-key", otherToken, nil)
+	require.NoError(t, err)
+	_ = forbidden.Body.Close()
+	assert.Equal(t, http.StatusForbidden, forbidden.StatusCode)
 
-// ---- HandlePatchConflict -------------------------------------------------
+	// The agent rotates its own key.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents/"+agentID+"/rotate-key", oldToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var rot struct {
+		Data model.RotateAgentKeyResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rot))
+	assert.Equal(t, agentID, rot.Data.AgentID)
+	assert.NotEmpty(t, rot.Data.NewKey.RawKey)
+	assert.Len(t, rot.Data.RevokedKeyIDs, 1)
 
-func TestHandlePatchConflict(t *testing.T) {
-	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken,
-			model.ConflictStatusUpdate{Status: "false_positive"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
+	// The old key can no longer mint tokens; the new one can.
+	oldAuthBody, _ := json.Marshal(model.AuthTokenRequest{AgentID: agentID, APIKey: agentID + "-key"})
+	respOld, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(oldAuthBody))
+	require.NoError(t, err)
+	_ = respOld.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, respOld.StatusCode, "rotated key must not issue new tokens")
+	assert.NotEmpty(t, getToken(testSrv.URL, agentID, rot.Data.NewKey.RawKey))
 
-	t.Run("invalid status returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
-			model.ConflictStatusUpdate{Status: "invalid_status"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	// Tokens minted before the rotation stay valid until they expire.
+	still, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+agentID+"/sessions", oldToken, nil)
+	require.NoError(t, err)
+	_ = still.Body.Close()
+	assert.Equal(t, http.StatusOK, still.StatusCode)
 
-		var errResp model.APIError
-		body, _ := io.ReadAll(resp.Body)
-		_ = json.Unmarshal(body, &errResp)
-		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	})
+	// The agent and its decisions persist.
+	agent, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+	require.NoError(t, err)
+	assert.Equal(t, agentID, agent.AgentID)
+	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, agentID, got.AgentID)
 
-	t.Run("nonexistent conflict returns 404", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
-			model.ConflictStatusUpdate{Status: "false_positive"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-	})
+	// Admins may rotate any agent's key; unknown agents are 404.
+	adminResp, err := authedRequest("POST", testSrv.URL+"/v1/agents/"+otherID+"/rotate-key", adminToken, nil)
+	require.NoError(t, err)
+	_ = adminResp.Body.Close()
+	assert.Equal(t, http.StatusOK, adminResp.StatusCode)
+	missing, err := authedRequest("POST", testSrv.URL+"/v1/agents/no-such-agent-"+suffix+"/rotate-key", adminToken, nil)
+	require.NoError(t, err)
+	_ = missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
 }
 
-// ---- HandleAdjudicateConflict --------------------------------------------
-
-func TestHandleAdjudicateConflict(t *testing.T) {
-	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate", adminToken,
-			map[string]any{"outcome": "agent-a is correct"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
-
-	t.Run("missing outcome returns 400", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
-			map[string]any{"outcome": ""})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+func TestHandleUpdateAgentTags_Dedup(t *testing.T) {
+	// Create a dedicated agent for tag updates.
+	tagAgentID := fmt.Sprintf("tag-agent-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, tagAgentID, "Tag Agent", "agent", "tag-agent-key")
 
-		var errResp model.APIError
-		body, _ := io.ReadAll(resp.Body)
-		_ = json.Unmarshal(body, &errResp)
-		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	})
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+tagAgentID+"/tags", adminToken,
+		model.UpdateAgentTagsRequest{
+			Tags: []string{"a", "b", "a"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	t.Run("nonexistent conflict returns 404", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
-			map[string]any{"outcome": "agent-a is correct"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-	})
+	var result struct {
+		Data struct {
+			Tags []string `json:"tags"`
+		} `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
+	require.NoError(t, err)
+	// The handler deduplicates tags while preserving order.
+	assert.Equal(t, []string{"a", "b"}, result.Data.Tags, "duplicate tags should be removed")
 }
 
-// ---- MCP: akashi_conflicts -----------------------------------------------
-
-func TestMCPConflictsTool(t *testing.T) {
-	c := newMCPClient(t, agentToken)
-	defer func() { _ = c.Close() }()
+func TestRequestIDMiddleware_ClientProvided(t *testing.T) {
+	clientReqID := "my-custom-request-id-12345"
+	req, err := http.NewRequest("GET", testSrv.URL+"/health", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Request-ID", clientReqID)
 
-	ctx := context.Background()
-	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
-		Params: mcplib.InitializeParams{
-			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
-		},
-	})
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, clientReqID, resp.Header.Get("X-Request-ID"),
+		"response should echo back the client-provided X-Request-ID")
+}
 
-	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{
-			Name:      "akashi_conflicts",
-			Arguments: map[string]any{"limit": 10},
-		},
-	})
+func TestHandleTemporalQuery_ZeroAsOf(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken,
+		model.TemporalQueryRequest{
+			AsOf:  time.Time{},
+			Limit: 10,
+		})
 	require.NoError(t, err)
-	require.False(t, result.IsError, "akashi_conflicts returned error: %v", result.Content)
+	defer func() { _ = resp.Body.Close() }()
+	// A zero AsOf is technically valid (will match nothing since all
+	// transaction_time values are after epoch). The handler should
+	// return 200 with empty results rather than erroring.
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// Response must include a "conflicts" array (may be empty).
-	var resp struct {
-		Conflicts []any `json:"conflicts"`
-		Total     int   `json:"total"`
-	}
-	for _, content := range result.Content {
-		if tc, ok := content.(mcplib.TextContent); ok {
-			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp))
-			break
-		}
+	var result struct {
+		Data struct {
+			Decisions []json.RawMessage `json:"decisions"`
+		} `json:"data"`
 	}
-	assert.NotNil(t, resp.Conflicts, "conflicts field should be present")
+	data, _ := io.ReadAll(resp.Body)
+	_ = json.Unmarshal(data, &result)
+	assert.NotNil(t, result.Data.Decisions)
+	assert.Len(t, result.Data.Decisions, 0, "zero AsOf should return no decisions")
 }
 
-func TestMCPConflictsTool_WithStatusFilter(t *testing.T) {
-	c := newMCPClient(t, agentToken)
-	defer func() { _ = c.Close() }()
+func TestHandleQuery_PaginationResponse(t *testing.T) {
+	// Ensure there is at least one decision.
+	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "pagination_test",
+				Outcome:      "verifying pagination fields",
+				Confidence:   0.7,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
 
-	ctx := context.Background()
-	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
-		Params: mcplib.InitializeParams{
-			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
-		},
-	})
+	dt := "pagination_test"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+		model.QueryRequest{
+			Filters: model.QueryFilters{
+				AgentIDs:     []string{"test-agent"},
+				DecisionType: &dt,
+			},
+			Limit:  5,
+			Offset: 0,
+		})
 	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{
-			Name:      "akashi_conflicts",
-			Arguments: map[string]any{"status": "resolved", "limit": 5},
-		},
-	})
+	var result struct {
+		Data    []json.RawMessage `json:"data"`
+		Total   *int              `json:"total"`
+		Limit   int               `json:"limit"`
+		Offset  int               `json:"offset"`
+		HasMore bool              `json:"has_more"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-	require.False(t, result.IsError, "akashi_conflicts with status filter returned error: %v", result.Content)
-}
 
-// ---- MCP: akashi_assess --------------------------------------------------
+	assert.Equal(t, 5, result.Limit, "response should include the requested limit")
+	assert.Equal(t, 0, result.Offset, "response should include the requested offset")
+	// total is present when no access filtering reduces the result set.
+	assert.NotNil(t, result.Total, "total should be present for non-filtered results")
+	assert.GreaterOrEqual(t, *result.Total, 1, "total should be at least 1")
+}
 
-func TestMCPAssessTool(t *testing.T) {
-	c := newMCPClient(t, agentToken)
-	defer func() { _ = c.Close() }()
+func TestHandleCreateRun_Valid(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	ctx := context.Background()
-	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
-		Params: mcplib.InitializeParams{
-			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
-		},
-	})
+	var result struct {
+		Data model.AgentRun `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, result.Data.ID, "run should have a non-nil UUID")
+	assert.Equal(t, "test-agent", result.Data.AgentID)
+	assert.Equal(t, model.RunStatusRunning, result.Data.Status, "new run should have status 'running'")
+}
 
-	// Trace a decision to assess.
-	traceResult, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{
-			Name: "akashi_trace",
-			Arguments: map[string]any{
-				"agent_id":      "test-agent",
-				"decision_type": "assess_tool_test",
-				"outcome":       "chose the right approach",
-				"confidence":    0.9,
-				"project":       "test-project",
+func TestHandleTemporalQuery_Valid(t *testing.T) {
+	// First, create a decision so there is at least one result.
+	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "temporal_test",
+				Outcome:      "temporal query validation",
+				Confidence:   0.85,
 			},
-		},
-	})
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
-	require.False(t, traceResult.IsError, "trace returned error: %v", traceResult.Content)
-
-	var traceResp struct {
-		DecisionID string `json:"decision_id"`
-	}
-	for _, content := range traceResult.Content {
-		if tc, ok := content.(mcplib.TextContent); ok {
-			require.NoError(t, json.Unmarshal([]byte(tc.Text), &traceResp))
-			break
-		}
-	}
-	require.NotEmpty(t, traceResp.DecisionID)
 
-	t.Run("valid assessment is recorded", func(t *testing.T) {
-		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-			Params: mcplib.CallToolParams{
-				Name: "akashi_assess",
-				Arguments: map[string]any{
-					"decision_id": traceResp.DecisionID,
-					"outcome":     "correct",
-					"notes":       "verified in production",
-				},
+	// Query with AsOf set to now — includes all decisions committed before this moment.
+	// The trace above may still be in the buffer, but prior test runs create enough
+	// committed decisions that the assertion below is reliably satisfied.
+	asOf := time.Now()
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken,
+		model.TemporalQueryRequest{
+			AsOf: asOf,
+			Filters: model.QueryFilters{
+				AgentIDs: []string{"test-agent"},
 			},
+			Limit: 10,
 		})
-		require.NoError(t, err)
-		require.False(t, result.IsError, "akashi_assess returned error: %v", result.Content)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-		var assessResp struct {
-			AssessmentID string `json:"assessment_id"`
-			DecisionID   string `json:"decision_id"`
-			Outcome      string `json:"outcome"`
-		}
-		for _, content := range result.Content {
-			if tc, ok := content.(mcplib.TextContent); ok {
-				require.NoError(t, json.Unmarshal([]byte(tc.Text), &assessResp))
-				break
-			}
-		}
-		assert.NotEmpty(t, assessResp.AssessmentID)
-		assert.Equal(t, traceResp.DecisionID, assessResp.DecisionID)
-		assert.Equal(t, "correct", assessResp.Outcome)
-	})
+	var result struct {
+		Data struct {
+			AsOf      string            `json:"as_of"`
+			Decisions []json.RawMessage `json:"decisions"`
+		} `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
+	require.NoError(t, err)
 
-	t.Run("missing decision_id returns tool error", func(t *testing.T) {
-		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-			Params: mcplib.CallToolParams{
-				Name:      "akashi_assess",
-				Arguments: map[string]any{"outcome": "correct"},
+	// Verify the response structure includes as_of; decisions may be empty if the
+	// buffer hasn't flushed, but the endpoint must return a valid response.
+	assert.NotEmpty(t, result.Data.AsOf, "response should include as_of timestamp")
+}
+
+func TestHandleSearch_ValidQuery(t *testing.T) {
+	// Ensure there is a decision to find.
+	_, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "search_validation",
+				Outcome:      "validated search response structure",
+				Confidence:   0.9,
 			},
+			Context: map[string]any{"project": "test-project"},
 		})
-		require.NoError(t, err)
-		assert.True(t, result.IsError, "missing decision_id should produce tool error")
-	})
+	require.NoError(t, err)
 
-	t.Run("invalid outcome returns tool error", func(t *testing.T) {
-		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-			Params: mcplib.CallToolParams{
-				Name: "akashi_assess",
-				Arguments: map[string]any{
-					"decision_id": traceResp.DecisionID,
-					"outcome":     "definitely_not_a_valid_outcome",
-				},
-			},
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+		model.SearchRequest{
+			Query: "validated search response",
+			Limit: 10,
 		})
-		require.NoError(t, err)
-		assert.True(t, result.IsError, "invalid outcome should produce tool error")
-	})
-}
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-// ---- MCP: akashi_stats ---------------------------------------------------
+	var result struct {
+		Data  []json.RawMessage `json:"data"`
+		Total int               `json:"total"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
+	require.NoError(t, err)
 
-func TestMCPStatsTool(t *testing.T) {
-	c := newMCPClient(t, agentToken)
-	defer func() { _ = c.Close() }()
+	// Verify response structure: results array and total count are present.
+	assert.NotNil(t, result.Data, "results should be an array, not null")
+	assert.GreaterOrEqual(t, result.Total, 0, "total should be non-negative")
+}
 
-	ctx := context.Background()
-	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
-		Params: mcplib.InitializeParams{
-			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+func TestHandleSessionView_WithDecisions(t *testing.T) {
+	// Create a decision with a specific session_id via the X-Akashi-Session header.
+	sessionID := uuid.New()
+	body, _ := json.Marshal(model.TraceRequest{
+		AgentID: "test-agent",
+		Decision: model.TraceDecision{
+			DecisionType: "session_view_test",
+			Outcome:      "decision within session",
+			Confidence:   0.75,
 		},
+		Context: map[string]any{"project": "test-project"},
 	})
+
+	req, err := http.NewRequest("POST", testSrv.URL+"/v1/trace", bytes.NewReader(body))
 	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Akashi-Session", sessionID.String())
 
-	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{
-			Name:      "akashi_stats",
-			Arguments: map[string]any{},
-		},
-	})
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	require.False(t, result.IsError, "akashi_stats returned error: %v", result.Content)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	var statsResp struct {
-		TraceHealth struct {
-			Status string `json:"status"`
-		} `json:"trace_health"`
-		Agents            int `json:"agents"`
-		FalsePositiveRate struct {
-			Resolved      int     `json:"resolved"`
-			FalsePositive int     `json:"false_positive"`
-			Rate          float64 `json:"rate"`
-		} `json:"false_positive_rate"`
-	}
-	for _, content := range result.Content {
-		if tc, ok := content.(mcplib.TextContent); ok {
-			require.NoError(t, json.Unmarshal([]byte(tc.Text), &statsResp))
-			break
-		}
-	}
-	assert.Contains(t, []string{"healthy", "needs_attention", "insufficient_data"}, statsResp.TraceHealth.Status)
-	assert.GreaterOrEqual(t, statsResp.Agents, 0)
-	assert.GreaterOrEqual(t, statsResp.FalsePositiveRate.Rate, 0.0)
-	assert.LessOrEqual(t, statsResp.FalsePositiveRate.Rate, 1.0)
-}
-
-// ---- MCP: akashi_trace with idempotency_key (exercises mcpTraceHash) -----
-
-func TestMCPTraceIdempotencyKey(t *testing.T) {
-	c := newMCPClient(t, agentToken)
-	defer func() { _ = c.Close() }()
-
-	ctx := context.Background()
-	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
-		Params: mcplib.InitializeParams{
-			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
-		},
-	})
+	// Retrieve the session view.
+	resp2, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+sessionID.String(), agentToken, nil)
 	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
 
-	idemKey := "mcp-idem-" + uuid.NewString()
-	args := map[string]any{
-		"agent_id":        "test-agent",
-		"decision_type":   "mcp_idempotency_test",
-		"outcome":         "chose postgres",
-		"confidence":      0.85,
-		"idempotency_key": idemKey,
-		"project":         "test-project",
+	var result struct {
+		Data struct {
+			SessionID     string            `json:"session_id"`
+			Decisions     []json.RawMessage `json:"decisions"`
+			DecisionCount int               `json:"decision_count"`
+			Summary       *struct {
+				StartedAt     string         `json:"started_at"`
+				EndedAt       string         `json:"ended_at"`
+				DurationSecs  float64        `json:"duration_secs"`
+				DecisionTypes map[string]int `json:"decision_types"`
+				AvgConfidence float64        `json:"avg_confidence"`
+			} `json:"summary"`
+		} `json:"data"`
 	}
-
-	// First call — should record and return a decision_id.
-	r1, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{Name: "akashi_trace", Arguments: args},
-	})
+	data, _ := io.ReadAll(resp2.Body)
+	err = json.Unmarshal(data, &result)
 	require.NoError(t, err)
-	require.False(t, r1.IsError, "first trace with idempotency_key failed: %v", r1.Content)
-
-	var resp1 struct {
-		DecisionID string `json:"decision_id"`
-		Status     string `json:"status"`
-	}
-	for _, content := range r1.Content {
-		if tc, ok := content.(mcplib.TextContent); ok {
-			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp1))
-			break
-		}
-	}
-	require.NotEmpty(t, resp1.DecisionID)
-	assert.Equal(t, "recorded", resp1.Status)
 
-	// Second call with the same key and payload — must replay the original decision_id.
-	r2, err := c.CallTool(ctx, mcplib.CallToolRequest{
-		Params: mcplib.CallToolParams{Name: "akashi_trace", Arguments: args},
-	})
-	require.NoError(t, err)
-	require.False(t, r2.IsError, "idempotency replay failed: %v", r2.Content)
+	assert.Equal(t, sessionID.String(), result.Data.SessionID)
+	assert.GreaterOrEqual(t, result.Data.DecisionCount, 1, "should have at least one decision in session")
+	assert.Len(t, result.Data.Decisions, result.Data.DecisionCount, "decisions array length should match decision_count")
 
-	var resp2 struct {
-		DecisionID string `json:"decision_id"`
-	}
-	for _, content := range r2.Content {
-		if tc, ok := content.(mcplib.TextContent); ok {
-			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp2))
-			break
-		}
-	}
-	assert.Equal(t, resp1.DecisionID, resp2.DecisionID, "idempotency replay must return the same decision_id")
+	// When there are decisions, summary should be present.
+	require.NotNil(t, result.Data.Summary, "summary should be present when session has decisions")
+	assert.NotEmpty(t, result.Data.Summary.StartedAt, "summary should have started_at")
+	assert.NotEmpty(t, result.Data.Summary.EndedAt, "summary should have ended_at")
+	assert.Contains(t, result.Data.Summary.DecisionTypes, "session_view_test",
+		"decision_types should contain the traced decision type")
+	assert.InDelta(t, 0.75, result.Data.Summary.AvgConfidence, 0.01,
+		"avg_confidence should reflect the traced decision's confidence")
 }
 
-// ptrStr is a test convenience helper for *string literals.
-func ptrStr(s string) *string { return &s }
-
-// ---- Spec-34: winning_decision_id on PATCH /v1/conflicts/{id} ------------
-
-// seedConflict traces two decisions via HTTP and inserts a scored conflict
-// referencing them directly via testDB. Returns the two decision IDs and the
-// conflict ID so callers can drive conflict resolution scenarios.
-func seedConflict(t *testing.T) (decisionAID, decisionBID, conflictID uuid.UUID) {
-	t.Helper()
-	orgID := uuid.Nil // default org from SeedAdmin
+func TestHandleDeleteGrant(t *testing.T) {
+	// Create a dedicated grantee agent.
+	granteeID := fmt.Sprintf("del-grantee-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Delete Grantee", "reader", "del-grantee-key")
 
-	traceDecision := func(outcome string, confidence float32) uuid.UUID {
-		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-			AgentID: "admin",
-			Decision: model.TraceDecision{
-				DecisionType: "architecture",
-				Outcome:      outcome,
-				Confidence:   confidence,
-			},
-			Context: map[string]any{"project": "test-project"},
+	// Create a grant.
+	resourceID := "test-agent"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &resourceID,
+			Permission:     "read",
 		})
-		require.NoError(t, tErr)
-		defer func() { _ = resp.Body.Close() }()
-		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode, "trace %q", outcome)
-		var result struct {
-			Data struct {
-				DecisionID uuid.UUID `json:"decision_id"`
-			} `json:"data"`
-		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &result))
-		require.NotEqual(t, uuid.Nil, result.Data.DecisionID)
-		return result.Data.DecisionID
-	}
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	decisionAID = traceDecision("spec-34 side A: use Redis", 0.9)
-	decisionBID = traceDecision("spec-34 side B: use Memcached", 0.8)
+	// Extract the grant ID from the response.
+	var createResult struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &createResult)
+	require.NoError(t, err)
+	grantID := createResult.Data.ID
+	require.NotEmpty(t, grantID, "grant should have an ID")
 
-	// Flush the event buffer so the decisions are committed before we insert
-	// the conflict (which has FKs to decision_a_id and decision_b_id).
-	require.NoError(t, testBuf.FlushNow(context.Background()))
+	// Delete the grant.
+	resp2, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+grantID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusNoContent, resp2.StatusCode,
+		"DELETE grant should return 204 No Content")
 
-	// Insert scored conflict directly — embedding pipeline won't produce one
-	// with the noop embedder (zero vectors have undefined cosine similarity).
-	// InsertScoredConflict ignores c.ID; the database auto-generates it.
-	// Capture the returned UUID so callers can reference the actual row.
-	c := model.DecisionConflict{
-		OrgID:         orgID,
-		ConflictKind:  model.ConflictKindCrossAgent,
-		DecisionAID:   decisionAID,
-		DecisionBID:   decisionBID,
-		AgentA:        "admin",
-		AgentB:        "admin",
-		DecisionTypeA: "architecture",
-		DecisionTypeB: "architecture",
-		OutcomeA:      "spec-34 side A: use Redis",
-		OutcomeB:      "spec-34 side B: use Memcached",
-		Status:        "open",
-	}
-	var err error
-	conflictID, err = testDB.InsertScoredConflict(context.Background(), c)
+	// Verify the grant is gone by trying to delete it again.
+	resp3, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+grantID, adminToken, nil)
 	require.NoError(t, err)
-	return decisionAID, decisionBID, conflictID
+	defer func() { _ = resp3.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp3.StatusCode,
+		"second DELETE should return 404 since grant was already deleted")
 }
 
-func TestHandlePatchConflict_WinningDecisionID(t *testing.T) {
-	decisionAID, decisionBID, conflictID := seedConflict(t)
+func TestHandleCompleteRun(t *testing.T) {
+	// Create a run.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	t.Run("winning_decision_id without resolved status returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
-			model.ConflictStatusUpdate{Status: "false_positive", WinningDecisionID: &decisionAID})
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &runResult)
+	require.NoError(t, err)
+	runID := runResult.Data.ID
+
+	t.Run("complete with default status", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken,
+			model.CompleteRunRequest{})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		var errResp model.APIError
-		body, _ := io.ReadAll(resp.Body)
-		_ = json.Unmarshal(body, &errResp)
-		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
-	})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	t.Run("winning_decision_id not in conflict returns 400", func(t *testing.T) {
-		stranger := uuid.New()
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
-			model.ConflictStatusUpdate{Status: "resolved", WinningDecisionID: &stranger})
+		var result struct {
+			Data model.AgentRun `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		err = json.Unmarshal(data, &result)
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		var errResp model.APIError
-		body, _ := io.ReadAll(resp.Body)
-		_ = json.Unmarshal(body, &errResp)
-		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+		assert.Equal(t, model.RunStatusCompleted, result.Data.Status,
+			"default completion status should be 'completed'")
+		assert.NotNil(t, result.Data.CompletedAt, "completed run should have completed_at set")
 	})
 
-	t.Run("resolved with winning_decision_id persists winner", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
-			model.ConflictStatusUpdate{Status: "resolved", WinningDecisionID: &decisionAID})
+	t.Run("complete with failed status", func(t *testing.T) {
+		// Create another run to test failure status.
+		resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+			model.CreateRunRequest{AgentID: "test-agent"})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var envelope struct {
-			Data struct {
-				WinningDecisionID *uuid.UUID `json:"winning_decision_id"`
-				Status            string     `json:"status"`
-			} `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &envelope))
-		result := envelope.Data
-		assert.Equal(t, "resolved", result.Status)
-		require.NotNil(t, result.WinningDecisionID)
-		assert.Equal(t, decisionAID, *result.WinningDecisionID)
-	})
+		defer func() { _ = resp2.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp2.StatusCode)
 
-	t.Run("resolved without winning_decision_id leaves winner nil", func(t *testing.T) {
-		_, _, noWinnerConflict := seedConflict(t)
+		var runResult2 struct {
+			Data model.AgentRun `json:"data"`
+		}
+		data2, _ := io.ReadAll(resp2.Body)
+		err = json.Unmarshal(data2, &runResult2)
+		require.NoError(t, err)
+		runID2 := runResult2.Data.ID
 
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+noWinnerConflict.String(), adminToken,
-			model.ConflictStatusUpdate{Status: "resolved"})
+		resp3, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID2.String()+"/complete", agentToken,
+			model.CompleteRunRequest{Status: "failed"})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		defer func() { _ = resp3.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp3.StatusCode)
 
-		var envelope struct {
-			Data struct {
-				WinningDecisionID *uuid.UUID `json:"winning_decision_id"`
-				Status            string     `json:"status"`
-			} `json:"data"`
+		var result struct {
+			Data model.AgentRun `json:"data"`
 		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &envelope))
-		result := envelope.Data
-		assert.Equal(t, "resolved", result.Status)
-		assert.Nil(t, result.WinningDecisionID, "no winner declared → field must be absent/null")
-	})
-
-	// Silence unused variable warning: decisionBID is set by seedConflict
-	// and exists to make conflict-side validation meaningful.
-	_ = decisionBID
-}
-
-// ---- HandlePatchDecision --------------------------------------------------
-
-func TestHandlePatchDecision(t *testing.T) {
-	// Trace a decision to patch.
-	dt := "patch_test_" + uuid.NewString()[:8]
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: dt,
-				Outcome:      "will be patched",
-				Confidence:   0.7,
-				Reasoning:    ptrStr("patch test"),
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
-	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
-
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
-	decisionID := traceResult.Data.DecisionID
-	require.NotEqual(t, uuid.Nil, decisionID)
-
-	t.Run("non-admin gets 403", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken,
-			map[string]string{"project": "new-project"})
+		data3, _ := io.ReadAll(resp3.Body)
+		err = json.Unmarshal(data3, &result)
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, model.RunStatusFailed, result.Data.Status,
+			"run should have status 'failed'")
 	})
 
-	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken,
-			map[string]string{"project": "new-project"})
+	t.Run("complete with invalid status", func(t *testing.T) {
+		// Create yet another run.
+		resp4, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+			model.CreateRunRequest{AgentID: "test-agent"})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
+		defer func() { _ = resp4.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp4.StatusCode)
 
-	t.Run("missing project field returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
-			map[string]string{})
+		var runResult3 struct {
+			Data model.AgentRun `json:"data"`
+		}
+		data4, _ := io.ReadAll(resp4.Body)
+		err = json.Unmarshal(data4, &runResult3)
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
 
-	t.Run("empty project returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
-			map[string]string{"project": ""})
+		resp5, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult3.Data.ID.String()+"/complete", agentToken,
+			model.CompleteRunRequest{Status: "invalid_status"})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		defer func() { _ = resp5.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp5.StatusCode,
+			"invalid status should return 400")
 	})
 
-	t.Run("nonexistent ID returns 404", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken,
-			map[string]string{"project": "new-project"})
+	t.Run("complete nonexistent run", func(t *testing.T) {
+		fakeRunID := uuid.New().String()
+		resp6, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+fakeRunID+"/complete", agentToken,
+			model.CompleteRunRequest{})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		defer func() { _ = resp6.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp6.StatusCode,
+			"completing a nonexistent run should return 404")
 	})
+}
 
-	t.Run("successful project update", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
-			map[string]string{"project": "new-project"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestHandleHealth_ResponseFields(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/health")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-		var result struct {
-			Data model.Decision `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		assert.Equal(t, decisionID, result.Data.ID)
-		require.NotNil(t, result.Data.Project)
-		assert.Equal(t, "new-project", *result.Data.Project)
-	})
+	var result struct {
+		Data model.HealthResponse `json:"data"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	err = json.Unmarshal(data, &result)
+	require.NoError(t, err)
 
-	t.Run("GET confirms project updated", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "healthy", result.Data.Status)
+	assert.Equal(t, "test", result.Data.Version, "version should match the configured test version")
+	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0), "uptime should be non-negative")
+	assert.Equal(t, "connected", result.Data.Postgres, "postgres should be connected")
+	assert.Equal(t, "ok", result.Data.BufferStatus, "buffer status should be ok under low load")
+	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0, "buffer depth should be non-negative")
+}
 
-		var result struct {
-			Data model.Decision `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		require.NotNil(t, result.Data.Project)
-		assert.Equal(t, "new-project", *result.Data.Project)
-	})
+func TestSecurityHeaders_AllRequired(t *testing.T) {
+	// Verify security headers on an authenticated endpoint as well.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// All security headers should be present on authenticated endpoints too.
+	assert.Equal(t, "nosniff", resp.Header.Get("X-Content-Type-Options"),
+		"X-Content-Type-Options should be set on authenticated endpoints")
+	assert.Contains(t, resp.Header.Get("Strict-Transport-Security"), "max-age=63072000",
+		"HSTS should be present on authenticated endpoints")
+	assert.Contains(t, resp.Header.Get("Content-Security-Policy"), "default-src",
+		"CSP should be present on authenticated endpoints")
+	assert.Contains(t, resp.Header.Get("Permissions-Policy"), "camera=()",
+		"Permissions-Policy should be present on authenticated endpoints")
 }
 
-// ---- HandleRetractDecision ------------------------------------------------
+// ---- HandleGetDecision ---------------------------------------------------
 
-func TestHandleRetractDecision(t *testing.T) {
-	// Trace a decision to retract.
-	dt := "retract_test_" + uuid.NewString()[:8]
+func TestHandleGetDecision(t *testing.T) {
+	// Trace a decision to get a valid ID.
+	dt := "get_decision_test_" + uuid.NewString()[:8]
 	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
 		model.TraceRequest{
 			AgentID: "test-agent",
 			Decision: model.TraceDecision{
 				DecisionType: dt,
-				Outcome:      "will be retracted",
-				Confidence:   0.85,
-				Reasoning:    ptrStr("retraction test"),
+				Outcome:      "retrieved ok",
+				Confidence:   0.8,
+				Reasoning:    ptrStr("for get decision test"),
 			},
 			Context: map[string]any{"project": "test-project"},
 		})
@@ -3333,178 +3567,207 @@ func TestHandleRetractDecision(t *testing.T) {
 	var traceResult struct {
 		Data struct {
 			DecisionID uuid.UUID `json:"decision_id"`
-			RunID      uuid.UUID `json:"run_id"`
 		} `json:"data"`
 	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	data, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(data, &traceResult))
 	decisionID := traceResult.Data.DecisionID
-	runID := traceResult.Data.RunID
 	require.NotEqual(t, uuid.Nil, decisionID)
-	require.NotEqual(t, uuid.Nil, runID)
 
-	t.Run("non-admin gets 403", func(t *testing.T) {
-		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+	t.Run("happy path returns decision", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, decisionID, result.Data.ID)
+		assert.Equal(t, dt, result.Data.DecisionType)
+		assert.Equal(t, "retrieved ok", result.Data.Outcome)
 	})
 
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", agentToken, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
 	t.Run("nonexistent ID returns 404", func(t *testing.T) {
-		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), agentToken, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
+}
 
-	t.Run("successful retraction with reason", func(t *testing.T) {
-		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
-			map[string]string{"reason": "inaccurate assessment"})
+// ---- HandleTraceHealth (exercises tracehealth.New + tracehealth.Compute) -
+
+func TestHandleTraceHealth(t *testing.T) {
+	t.Run("returns valid metrics structure", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 		var result struct {
-			Data model.Decision `json:"data"`
+			Data struct {
+				Status       string `json:"status"`
+				Completeness *struct {
+					TotalDecisions int `json:"total_decisions"`
+				} `json:"completeness"`
+				Gaps []string `json:"gaps"`
+			} `json:"data"`
 		}
 		body, _ := io.ReadAll(resp.Body)
 		require.NoError(t, json.Unmarshal(body, &result))
-		assert.Equal(t, decisionID, result.Data.ID)
-		assert.NotNil(t, result.Data.ValidTo, "valid_to must be set after retraction")
+		assert.Contains(t, []string{"healthy", "needs_attention", "insufficient_data"}, result.Data.Status)
+		assert.NotNil(t, result.Data.Completeness)
+		assert.NotNil(t, result.Data.Gaps)
 	})
 
-	t.Run("retracted decision hidden from current-only GET", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+	t.Run("requires authentication", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", testSrv.URL+"/v1/trace-health", nil)
+		resp, err := http.DefaultClient.Do(req)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		// HandleGetDecision does not use CurrentOnly by default, so the decision
-		// is still retrievable. But it should have valid_to set.
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		var result struct {
-			Data model.Decision `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		assert.NotNil(t, result.Data.ValidTo, "retracted decision must have valid_to set")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 	})
+}
 
-	t.Run("DecisionRetracted event recorded", func(t *testing.T) {
-		// Query events for the run that contained the retracted decision.
-		orgID := uuid.Nil // default org from SeedAdmin
-		events, err := testDB.GetEventsByRun(context.Background(), orgID, runID, 100)
+// ---- HandlePatchConflict -------------------------------------------------
+
+func TestHandlePatchConflict(t *testing.T) {
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken,
+			model.ConflictStatusUpdate{Status: "false_positive"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid status returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
+			model.ConflictStatusUpdate{Status: "invalid_status"})
 		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-		var found bool
-		for _, ev := range events {
-			if ev.EventType == model.EventDecisionRetracted {
-				found = true
-				assert.Equal(t, decisionID.String(), ev.Payload["decision_id"])
-				assert.Equal(t, "inaccurate assessment", ev.Payload["reason"])
-				assert.NotEmpty(t, ev.Payload["retracted_by"])
-				break
-			}
-		}
-		assert.True(t, found, "expected a DecisionRetracted event in the run's event log")
+		var errResp model.APIError
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &errResp)
+		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
 	})
 
-	t.Run("double retract returns 404", func(t *testing.T) {
-		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
+	t.Run("nonexistent conflict returns 404", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
+			model.ConflictStatusUpdate{Status: "false_positive"})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 }
 
-// ---- HandleResolveConflictGroup ------------------------------------------
+func TestHandlePatchConflict_Transitions(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
+	patch := func(status string) int {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
+			model.ConflictStatusUpdate{Status: status, ResolutionNote: ptrStr("reviewed")})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
 
-// seedConflictGroup creates two decisions and two open conflicts sharing the
-// same conflict group. Returns the group ID, two conflict IDs, and the two
-// decision IDs (A1, B1 for the first pair; A2, B2 for the second). Agent
-// names are "alpha" (side A) and "beta" (side B).
-func seedConflictGroup(t *testing.T) (groupID uuid.UUID, conflictIDs [2]uuid.UUID, decisionIDs [4]uuid.UUID) {
-	t.Helper()
-	orgID := uuid.Nil
+	assert.Equal(t, http.StatusOK, patch("resolved"), "open -> resolved")
+	assert.Equal(t, http.StatusConflict, patch("resolved"), "resolved -> resolved is rejected")
 
-	traceDecision := func(agentID, outcome string, confidence float32) uuid.UUID {
-		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-			AgentID: agentID,
-			Decision: model.TraceDecision{
-				DecisionType: "architecture",
-				Outcome:      outcome,
-				Confidence:   confidence,
-			},
-			Context: map[string]any{"project": "test-project"},
+	got, err := testDB.GetConflict(context.Background(), conflictID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", got.Status)
+	require.NotNil(t, got.ResolvedBy)
+	assert.Equal(t, "admin", *got.ResolvedBy)
+	assert.NotNil(t, got.ResolvedAt)
+
+	// A reviewer may still correct a resolution to false_positive, and back.
+	assert.Equal(t, http.StatusOK, patch("false_positive"), "resolved -> false_positive")
+	assert.Equal(t, http.StatusConflict, patch("false_positive"), "false_positive -> false_positive is rejected")
+}
+
+func TestHandlePatchConflict_OwnAgentOnly(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	ownerID := "conflict-owner-" + suffix
+	createAgent(testSrv.URL, adminToken, ownerID, ownerID, "agent", ownerID+"-key")
+	ownerToken := getToken(testSrv.URL, ownerID, ownerID+"-key")
+	outsiderID := "conflict-outsider-" + suffix
+	createAgent(testSrv.URL, adminToken, outsiderID, outsiderID, "agent", outsiderID+"-key")
+	outsiderToken := getToken(testSrv.URL, outsiderID, outsiderID+"-key")
+
+	trace := func(outcome string) uuid.UUID {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", ownerToken, model.TraceRequest{
+			AgentID:  ownerID,
+			Decision: model.TraceDecision{DecisionType: "architecture", Outcome: outcome, Confidence: 0.8},
 		})
-		require.NoError(t, tErr)
+		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
 		var result struct {
 			Data struct {
 				DecisionID uuid.UUID `json:"decision_id"`
 			} `json:"data"`
 		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &result))
-		require.NotEqual(t, uuid.Nil, result.Data.DecisionID)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
 		return result.Data.DecisionID
 	}
-
-	// Create 4 decisions: two per side
-	decisionIDs[0] = traceDecision("alpha", "use Redis for caching", 0.9)
-	decisionIDs[1] = traceDecision("beta", "use Memcached for caching", 0.8)
-	decisionIDs[2] = traceDecision("alpha", "Redis cluster mode", 0.85)
-	decisionIDs[3] = traceDecision("beta", "Memcached with mcrouter", 0.75)
-
+	decisionAID := trace("owner side A: use Postgres")
+	decisionBID := trace("owner side B: use MySQL")
 	require.NoError(t, testBuf.FlushNow(context.Background()))
 
-	// Insert two conflicts — InsertScoredConflict auto-creates and links the group.
-	for i, pair := range [][2]int{{0, 1}, {2, 3}} {
-		c := model.DecisionConflict{
-			OrgID:         orgID,
-			ConflictKind:  model.ConflictKindCrossAgent,
-			DecisionAID:   decisionIDs[pair[0]],
-			DecisionBID:   decisionIDs[pair[1]],
-			AgentA:        "alpha",
-			AgentB:        "beta",
-			DecisionTypeA: "architecture",
-			DecisionTypeB: "architecture",
-			OutcomeA:      "alpha outcome",
-			OutcomeB:      "beta outcome",
-			Status:        "open",
-		}
-		var err error
-		conflictIDs[i], err = testDB.InsertScoredConflict(context.Background(), c)
-		require.NoError(t, err)
-	}
+	conflictID, err := testDB.InsertScoredConflict(context.Background(), model.DecisionConflict{
+		OrgID:         uuid.Nil,
+		ConflictKind:  model.ConflictKindSelfContradiction,
+		DecisionAID:   decisionAID,
+		DecisionBID:   decisionBID,
+		AgentA:        ownerID,
+		AgentB:        ownerID,
+		DecisionTypeA: "architecture",
+		DecisionTypeB: "architecture",
+		OutcomeA:      "owner side A: use Postgres",
+		OutcomeB:      "owner side B: use MySQL",
+		Status:        "open",
+	})
+	require.NoError(t, err)
 
-	// Retrieve the group_id that was auto-created.
-	conflict, err := testDB.GetConflict(context.Background(), conflictIDs[0], orgID)
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), outsiderToken,
+		model.ConflictStatusUpdate{Status: "resolved"})
 	require.NoError(t, err)
-	require.NotNil(t, conflict.GroupID, "InsertScoredConflict must set group_id")
-	groupID = *conflict.GroupID
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 
-	return groupID, conflictIDs, decisionIDs
+	resp, err = authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), ownerToken,
+		model.ConflictStatusUpdate{Status: "resolved", WinningDecisionID: &decisionAID})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleResolveConflictGroup(t *testing.T) {
+// ---- HandleAdjudicateConflict --------------------------------------------
+
+func TestHandleAdjudicateConflict(t *testing.T) {
 	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved"})
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate", adminToken,
+			map[string]any{"outcome": "agent-a is correct"})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	})
 
-	t.Run("invalid status returns 400", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "acknowledged"})
+	t.Run("missing outcome returns 400", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
+			map[string]any{"outcome": ""})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
@@ -3515,1740 +3778,3276 @@ func TestHandleResolveConflictGroup(t *testing.T) {
 		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
 	})
 
-	t.Run("winning_agent with false_positive returns 400", func(t *testing.T) {
-		agent := "alpha"
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "false_positive", WinningAgent: &agent})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
-
-	t.Run("nonexistent group returns 404", func(t *testing.T) {
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved"})
+	t.Run("nonexistent conflict returns 404", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
+			map[string]any{"outcome": "agent-a is correct"})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
+}
 
-	t.Run("resolved without winning_agent resolves all open conflicts", func(t *testing.T) {
-		groupID, _, _ := seedConflictGroup(t)
-		note := "all caching decisions settled"
+// ---- MCP: akashi_conflicts -----------------------------------------------
 
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved", ResolutionNote: &note})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
+func TestMCPConflictsTool(t *testing.T) {
+	c := newMCPClient(t, agentToken)
+	defer func() { _ = c.Close() }()
 
-		var envelope struct {
-			Data model.ConflictGroupResolveResult `json:"data"`
-		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &envelope))
-		assert.Equal(t, groupID, envelope.Data.GroupID)
-		assert.Equal(t, "resolved", envelope.Data.Status)
-		assert.Equal(t, 2, envelope.Data.Resolved, "both open conflicts should be resolved")
+	ctx := context.Background()
+	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
+		Params: mcplib.InitializeParams{
+			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+		},
 	})
+	require.NoError(t, err)
 
-	t.Run("resolved with winning_agent sets winning_decision_id", func(t *testing.T) {
-		groupID, conflictIDs, _ := seedConflictGroup(t)
-		agent := "alpha"
-		note := "alpha is authoritative"
-
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved", WinningAgent: &agent, ResolutionNote: &note})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
-
-		var envelope struct {
-			Data model.ConflictGroupResolveResult `json:"data"`
-		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &envelope))
-		assert.Equal(t, 2, envelope.Data.Resolved)
+	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_conflicts",
+			Arguments: map[string]any{"limit": 10},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "akashi_conflicts returned error: %v", result.Content)
 
-		// Verify winning_decision_id was set on each conflict.
-		for _, cid := range conflictIDs {
-			conflict, cErr := testDB.GetConflict(context.Background(), cid, uuid.Nil)
-			require.NoError(t, cErr)
-			require.NotNil(t, conflict)
-			assert.Equal(t, "resolved", conflict.Status)
-			require.NotNil(t, conflict.WinningDecisionID, "winning_decision_id should be set")
+	// Response must include a "conflicts" array (may be empty).
+	var resp struct {
+		Conflicts []any `json:"conflicts"`
+		Total     int   `json:"total"`
+	}
+	for _, content := range result.Content {
+		if tc, ok := content.(mcplib.TextContent); ok {
+			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp))
+			break
 		}
-	})
+	}
+	assert.NotNil(t, resp.Conflicts, "conflicts field should be present")
+}
 
-	t.Run("false_positive resolves without winner", func(t *testing.T) {
-		groupID, conflictIDs, _ := seedConflictGroup(t)
+func TestMCPConflictsTool_WithStatusFilter(t *testing.T) {
+	c := newMCPClient(t, agentToken)
+	defer func() { _ = c.Close() }()
 
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "false_positive"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
+	ctx := context.Background()
+	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
+		Params: mcplib.InitializeParams{
+			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+		},
+	})
+	require.NoError(t, err)
 
-		var envelope struct {
-			Data model.ConflictGroupResolveResult `json:"data"`
-		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &envelope))
-		assert.Equal(t, "false_positive", envelope.Data.Status)
-		assert.Equal(t, 2, envelope.Data.Resolved)
-
-		for _, cid := range conflictIDs {
-			conflict, cErr := testDB.GetConflict(context.Background(), cid, uuid.Nil)
-			require.NoError(t, cErr)
-			require.NotNil(t, conflict)
-			assert.Equal(t, "false_positive", conflict.Status)
-			assert.Nil(t, conflict.WinningDecisionID, "false_positive should not set winner")
-		}
+	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_conflicts",
+			Arguments: map[string]any{"status": "resolved", "limit": 5},
+		},
 	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "akashi_conflicts with status filter returned error: %v", result.Content)
+}
 
-	t.Run("idempotent re-resolve returns 0 affected", func(t *testing.T) {
-		groupID, _, _ := seedConflictGroup(t)
-
-		// Resolve first time.
-		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
+// ---- MCP: akashi_assess --------------------------------------------------
 
-		// Resolve second time — no open conflicts remain.
-		resp2, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
-			model.ConflictGroupResolveRequest{Status: "resolved"})
-		require.NoError(t, err)
-		defer func() { _ = resp2.Body.Close() }()
-		require.Equal(t, http.StatusOK, resp2.StatusCode)
+func TestMCPAssessTool(t *testing.T) {
+	c := newMCPClient(t, agentToken)
+	defer func() { _ = c.Close() }()
 
-		var envelope struct {
-			Data model.ConflictGroupResolveResult `json:"data"`
-		}
-		b, _ := io.ReadAll(resp2.Body)
-		require.NoError(t, json.Unmarshal(b, &envelope))
-		assert.Equal(t, 0, envelope.Data.Resolved, "no open conflicts to resolve on second call")
+	ctx := context.Background()
+	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
+		Params: mcplib.InitializeParams{
+			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+		},
 	})
-}
-
-// ---- HandleEraseDecision (GDPR tombstone erasure) -------------------------
+	require.NoError(t, err)
 
-func TestHandleEraseDecision(t *testing.T) {
-	// Trace a decision with alternatives and evidence to erase.
-	dt := "erasure_test_" + uuid.NewString()[:8]
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: dt,
-				Outcome:      "sensitive PII outcome",
-				Confidence:   0.85,
-				Reasoning:    ptrStr("contains PII reasoning"),
-				Alternatives: []model.TraceAlternative{
-					{Label: "alt with PII", RejectionReason: ptrStr("PII rejection reason")},
-				},
-				Evidence: []model.TraceEvidence{
-					{SourceType: "document", Content: "PII evidence content", SourceURI: ptrStr("https://example.com/pii")},
-				},
+	// Trace a decision to assess.
+	traceResult, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name: "akashi_trace",
+			Arguments: map[string]any{
+				"agent_id":      "test-agent",
+				"decision_type": "assess_tool_test",
+				"outcome":       "chose the right approach",
+				"confidence":    0.9,
+				"project":       "test-project",
 			},
-			Context: map[string]any{"project": "test-project"},
-		})
+		},
+	})
 	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	require.False(t, traceResult.IsError, "trace returned error: %v", traceResult.Content)
 
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-			RunID      uuid.UUID `json:"run_id"`
-		} `json:"data"`
+	var traceResp struct {
+		DecisionID string `json:"decision_id"`
 	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
-	decisionID := traceResult.Data.DecisionID
-	runID := traceResult.Data.RunID
-	require.NotEqual(t, uuid.Nil, decisionID)
+	for _, content := range traceResult.Content {
+		if tc, ok := content.(mcplib.TextContent); ok {
+			require.NoError(t, json.Unmarshal([]byte(tc.Text), &traceResp))
+			break
+		}
+	}
+	require.NotEmpty(t, traceResp.DecisionID)
 
-	t.Run("pre-erasure verify returns verified", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
+	t.Run("valid assessment is recorded", func(t *testing.T) {
+		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+			Params: mcplib.CallToolParams{
+				Name: "akashi_assess",
+				Arguments: map[string]any{
+					"decision_id": traceResp.DecisionID,
+					"outcome":     "correct",
+					"notes":       "verified in production",
+				},
+			},
+		})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-		var result struct {
-			Data map[string]any `json:"data"`
+		require.False(t, result.IsError, "akashi_assess returned error: %v", result.Content)
+
+		var assessResp struct {
+			AssessmentID string `json:"assessment_id"`
+			DecisionID   string `json:"decision_id"`
+			Outcome      string `json:"outcome"`
 		}
-		data, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(data, &result))
-		assert.Equal(t, "verified", result.Data["status"])
+		for _, content := range result.Content {
+			if tc, ok := content.(mcplib.TextContent); ok {
+				require.NoError(t, json.Unmarshal([]byte(tc.Text), &assessResp))
+				break
+			}
+		}
+		assert.NotEmpty(t, assessResp.AssessmentID)
+		assert.Equal(t, traceResp.DecisionID, assessResp.DecisionID)
+		assert.Equal(t, "correct", assessResp.Outcome)
 	})
 
-	t.Run("agent role gets 403", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", agentToken,
-			map[string]string{"reason": "GDPR request"})
+	t.Run("missing decision_id returns tool error", func(t *testing.T) {
+		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+			Params: mcplib.CallToolParams{
+				Name:      "akashi_assess",
+				Arguments: map[string]any{"outcome": "correct"},
+			},
+		})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.True(t, result.IsError, "missing decision_id should produce tool error")
 	})
 
-	t.Run("admin role gets 403", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", adminToken,
-			map[string]string{"reason": "GDPR request"})
+	t.Run("invalid outcome returns tool error", func(t *testing.T) {
+		result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+			Params: mcplib.CallToolParams{
+				Name: "akashi_assess",
+				Arguments: map[string]any{
+					"decision_id": traceResp.DecisionID,
+					"outcome":     "definitely_not_a_valid_outcome",
+				},
+			},
+		})
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.True(t, result.IsError, "invalid outcome should produce tool error")
 	})
+}
 
-	t.Run("invalid UUID returns 400", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/erase", orgOwnerToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
+// ---- MCP: akashi_stats ---------------------------------------------------
 
-	t.Run("nonexistent ID returns 404", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/erase", orgOwnerToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+func TestMCPStatsTool(t *testing.T) {
+	c := newMCPClient(t, agentToken)
+	defer func() { _ = c.Close() }()
+
+	ctx := context.Background()
+	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
+		Params: mcplib.InitializeParams{
+			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+		},
 	})
+	require.NoError(t, err)
 
-	t.Run("successful erasure", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
-			map[string]string{"reason": "GDPR data subject request #42"})
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	result, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{
+			Name:      "akashi_stats",
+			Arguments: map[string]any{},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "akashi_stats returned error: %v", result.Content)
 
-		var result struct {
-			Data map[string]any `json:"data"`
+	var statsResp struct {
+		TraceHealth struct {
+			Status string `json:"status"`
+		} `json:"trace_health"`
+		Agents            int `json:"agents"`
+		FalsePositiveRate struct {
+			Resolved      int     `json:"resolved"`
+			FalsePositive int     `json:"false_positive"`
+			Rate          float64 `json:"rate"`
+		} `json:"false_positive_rate"`
+	}
+	for _, content := range result.Content {
+		if tc, ok := content.(mcplib.TextContent); ok {
+			require.NoError(t, json.Unmarshal([]byte(tc.Text), &statsResp))
+			break
 		}
-		data, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(data, &result))
-		assert.Equal(t, decisionID.String(), result.Data["decision_id"])
-		assert.NotEmpty(t, result.Data["erased_at"])
-		assert.NotEmpty(t, result.Data["original_hash"])
-		assert.NotEmpty(t, result.Data["erased_hash"])
-		assert.NotEqual(t, result.Data["original_hash"], result.Data["erased_hash"])
+	}
+	assert.Contains(t, []string{"healthy", "needs_attention", "insufficient_data"}, statsResp.TraceHealth.Status)
+	assert.GreaterOrEqual(t, statsResp.Agents, 0)
+	assert.GreaterOrEqual(t, statsResp.FalsePositiveRate.Rate, 0.0)
+	assert.LessOrEqual(t, statsResp.FalsePositiveRate.Rate, 1.0)
+}
+
+// ---- MCP: akashi_trace with idempotency_key (exercises mcpTraceHash) -----
+
+func TestMCPTraceIdempotencyKey(t *testing.T) {
+	c := newMCPClient(t, agentToken)
+	defer func() { _ = c.Close() }()
+
+	ctx := context.Background()
+	_, err := c.Initialize(ctx, mcplib.InitializeRequest{
+		Params: mcplib.InitializeParams{
+			ClientInfo: mcplib.Implementation{Name: "test-client", Version: "1.0"},
+		},
 	})
+	require.NoError(t, err)
+
+	idemKey := "mcp-idem-" + uuid.NewString()
+	args := map[string]any{
+		"agent_id":        "test-agent",
+		"decision_type":   "mcp_idempotency_test",
+		"outcome":         "chose postgres",
+		"confidence":      0.85,
+		"idempotency_key": idemKey,
+		"project":         "test-project",
+	}
+
+	// First call — should record and return a decision_id.
+	r1, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{Name: "akashi_trace", Arguments: args},
+	})
+	require.NoError(t, err)
+	require.False(t, r1.IsError, "first trace with idempotency_key failed: %v", r1.Content)
+
+	var resp1 struct {
+		DecisionID string `json:"decision_id"`
+		Status     string `json:"status"`
+	}
+	for _, content := range r1.Content {
+		if tc, ok := content.(mcplib.TextContent); ok {
+			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp1))
+			break
+		}
+	}
+	require.NotEmpty(t, resp1.DecisionID)
+	assert.Equal(t, "recorded", resp1.Status)
+
+	// Second call with the same key and payload — must replay the original decision_id.
+	r2, err := c.CallTool(ctx, mcplib.CallToolRequest{
+		Params: mcplib.CallToolParams{Name: "akashi_trace", Arguments: args},
+	})
+	require.NoError(t, err)
+	require.False(t, r2.IsError, "idempotency replay failed: %v", r2.Content)
+
+	var resp2 struct {
+		DecisionID string `json:"decision_id"`
+	}
+	for _, content := range r2.Content {
+		if tc, ok := content.(mcplib.TextContent); ok {
+			require.NoError(t, json.Unmarshal([]byte(tc.Text), &resp2))
+			break
+		}
+	}
+	assert.Equal(t, resp1.DecisionID, resp2.DecisionID, "idempotency replay must return the same decision_id")
+}
+
+// ptrStr is a test convenience helper for *string literals.
+func ptrStr(s string) *string { return &s }
+
+// ---- Spec-34: winning_decision_id on PATCH /v1/conflicts/{id} ------------
+
+// seedConflict traces two decisions via HTTP and inserts a scored conflict
+// referencing them directly via testDB. Returns the two decision IDs and the
+// conflict ID so callers can drive conflict resolution scenarios.
+func seedConflict(t *testing.T) (decisionAID, decisionBID, conflictID uuid.UUID) {
+	t.Helper()
+	orgID := uuid.Nil // default org from SeedAdmin
+
+	traceDecision := func(outcome string, confidence float32) uuid.UUID {
+		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      outcome,
+				Confidence:   confidence,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+		require.NoError(t, tErr)
+		defer func() { _ = resp.Body.Close() }()
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode, "trace %q", outcome)
+		var result struct {
+			Data struct {
+				DecisionID uuid.UUID `json:"decision_id"`
+			} `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &result))
+		require.NotEqual(t, uuid.Nil, result.Data.DecisionID)
+		return result.Data.DecisionID
+	}
+
+	decisionAID = traceDecision("spec-34 side A: use Redis", 0.9)
+	decisionBID = traceDecision("spec-34 side B: use Memcached", 0.8)
+
+	// Flush the event buffer so the decisions are committed before we insert
+	// the conflict (which has FKs to decision_a_id and decision_b_id).
+	require.NoError(t, testBuf.FlushNow(context.Background()))
+
+	// Insert scored conflict directly — embedding pipeline won't produce one
+	// with the noop embedder (zero vectors have undefined cosine similarity).
+	// InsertScoredConflict ignores c.ID; the database auto-generates it.
+	// Capture the returned UUID so callers can reference the actual row.
+	c := model.DecisionConflict{
+		OrgID:         orgID,
+		ConflictKind:  model.ConflictKindCrossAgent,
+		DecisionAID:   decisionAID,
+		DecisionBID:   decisionBID,
+		AgentA:        "admin",
+		AgentB:        "admin",
+		DecisionTypeA: "architecture",
+		DecisionTypeB: "architecture",
+		OutcomeA:      "spec-34 side A: use Redis",
+		OutcomeB:      "spec-34 side B: use Memcached",
+		Status:        "open",
+	}
+	var err error
+	conflictID, err = testDB.InsertScoredConflict(context.Background(), c)
+	require.NoError(t, err)
+	return decisionAID, decisionBID, conflictID
+}
+
+func TestExportConflicts(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
+	from := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	t.Run("NDJSON", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/conflicts?status=open&from="+from, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(resp.Body)
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+		found := false
+		for _, line := range lines {
+			var c model.DecisionConflict
+			require.NoError(t, json.Unmarshal(line, &c))
+			assert.Equal(t, "open", c.Status)
+			if c.ID == conflictID {
+				found = true
+				assert.Equal(t, "spec-34 side A: use Redis", c.OutcomeA)
+			}
+		}
+		assert.True(t, found, "seeded conflict should be exported")
+		assert.Equal(t, "true", resp.Trailer.Get("X-Akashi-Export-Complete"))
+		assert.Equal(t, strconv.Itoa(len(lines)), resp.Trailer.Get("X-Akashi-Exported-Count"))
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/conflicts?format=csv&from="+from, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/csv")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.NotEmpty(t, records)
+		assert.Equal(t, "id", records[0][0])
+		found := false
+		for _, rec := range records[1:] {
+			if rec[0] == conflictID.String() {
+				found = true
+			}
+		}
+		assert.True(t, found, "seeded conflict should be exported")
+	})
+
+	t.Run("window excludes conflict", func(t *testing.T) {
+		to := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/conflicts?to="+to, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		assert.NotContains(t, string(body), conflictID.String())
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/conflicts?format=xml", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestHandlePatchConflict_WinningDecisionID(t *testing.T) {
+	decisionAID, decisionBID, conflictID := seedConflict(t)
+
+	t.Run("winning_decision_id without resolved status returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
+			model.ConflictStatusUpdate{Status: "false_positive", WinningDecisionID: &decisionAID})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp model.APIError
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &errResp)
+		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	})
+
+	t.Run("winning_decision_id not in conflict returns 400", func(t *testing.T) {
+		stranger := uuid.New()
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
+			model.ConflictStatusUpdate{Status: "resolved", WinningDecisionID: &stranger})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp model.APIError
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &errResp)
+		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	})
+
+	t.Run("resolved with winning_decision_id persists winner", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
+			model.ConflictStatusUpdate{Status: "resolved", WinningDecisionID: &decisionAID})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var envelope struct {
+			Data struct {
+				WinningDecisionID *uuid.UUID `json:"winning_decision_id"`
+				Status            string     `json:"status"`
+			} `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &envelope))
+		result := envelope.Data
+		assert.Equal(t, "resolved", result.Status)
+		require.NotNil(t, result.WinningDecisionID)
+		assert.Equal(t, decisionAID, *result.WinningDecisionID)
+	})
+
+	t.Run("resolved without winning_decision_id leaves winner nil", func(t *testing.T) {
+		_, _, noWinnerConflict := seedConflict(t)
+
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+noWinnerConflict.String(), adminToken,
+			model.ConflictStatusUpdate{Status: "resolved"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var envelope struct {
+			Data struct {
+				WinningDecisionID *uuid.UUID `json:"winning_decision_id"`
+				Status            string     `json:"status"`
+			} `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &envelope))
+		result := envelope.Data
+		assert.Equal(t, "resolved", result.Status)
+		assert.Nil(t, result.WinningDecisionID, "no winner declared → field must be absent/null")
+	})
+
+	// Silence unused variable warning: decisionBID is set by seedConflict
+	// and exists to make conflict-side validation meaningful.
+	_ = decisionBID
+}
+
+// ---- HandlePatchDecision --------------------------------------------------
+
+func TestHandlePatchDecision(t *testing.T) {
+	// Trace a decision to patch.
+	dt := "patch_test_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: dt,
+				Outcome:      "will be patched",
+				Confidence:   0.7,
+				Reasoning:    ptrStr("patch test"),
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+	require.NotEqual(t, uuid.Nil, decisionID)
+
+	t.Run("non-admin gets 403", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken,
+			map[string]string{"project": "new-project"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken,
+			map[string]string{"project": "new-project"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("missing project field returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
+			map[string]string{})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("empty project returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
+			map[string]string{"project": ""})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("nonexistent ID returns 404", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken,
+			map[string]string{"project": "new-project"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successful project update", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
+			map[string]string{"project": "new-project"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, decisionID, result.Data.ID)
+		require.NotNil(t, result.Data.Project)
+		assert.Equal(t, "new-project", *result.Data.Project)
+	})
+
+	t.Run("GET confirms project updated", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		require.NotNil(t, result.Data.Project)
+		assert.Equal(t, "new-project", *result.Data.Project)
+	})
+}
+
+// ---- HandleRetractDecision ------------------------------------------------
+
+func TestHandleRetractDecision(t *testing.T) {
+	// Trace a decision to retract.
+	dt := "retract_test_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: dt,
+				Outcome:      "will be retracted",
+				Confidence:   0.85,
+				Reasoning:    ptrStr("retraction test"),
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+			RunID      uuid.UUID `json:"run_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+	runID := traceResult.Data.RunID
+	require.NotEqual(t, uuid.Nil, decisionID)
+	require.NotEqual(t, uuid.Nil, runID)
+
+	t.Run("non-admin gets 403", func(t *testing.T) {
+		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("nonexistent ID returns 404", func(t *testing.T) {
+		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successful retraction with reason", func(t *testing.T) {
+		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken,
+			map[string]string{"reason": "inaccurate assessment"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, decisionID, result.Data.ID)
+		assert.NotNil(t, result.Data.ValidTo, "valid_to must be set after retraction")
+	})
+
+	t.Run("retracted decision hidden from current-only GET", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		// HandleGetDecision does not use CurrentOnly by default, so the decision
+		// is still retrievable. But it should have valid_to set.
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.NotNil(t, result.Data.ValidTo, "retracted decision must have valid_to set")
+	})
+
+	t.Run("DecisionRetracted event recorded", func(t *testing.T) {
+		// Query events for the run that contained the retracted decision.
+		orgID := uuid.Nil // default org from SeedAdmin
+		events, err := testDB.GetEventsByRun(context.Background(), orgID, runID, 100)
+		require.NoError(t, err)
+
+		var found bool
+		for _, ev := range events {
+			if ev.EventType == model.EventDecisionRetracted {
+				found = true
+				assert.Equal(t, decisionID.String(), ev.Payload["decision_id"])
+				assert.Equal(t, "inaccurate assessment", ev.Payload["reason"])
+				assert.NotEmpty(t, ev.Payload["retracted_by"])
+				break
+			}
+		}
+		assert.True(t, found, "expected a DecisionRetracted event in the run's event log")
+	})
+
+	t.Run("double retract returns 404", func(t *testing.T) {
+		resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+// ---- HandleResolveConflictGroup ------------------------------------------
+
+// seedConflictGroup creates two decisions and two open conflicts sharing the
+// same conflict group. Returns the group ID, two conflict IDs, and the two
+// decision IDs (A1, B1 for the first pair; A2, B2 for the second). Agent
+// names are "alpha" (side A) and "beta" (side B).
+func seedConflictGroup(t *testing.T) (groupID uuid.UUID, conflictIDs [2]uuid.UUID, decisionIDs [4]uuid.UUID) {
+	t.Helper()
+	orgID := uuid.Nil
+
+	traceDecision := func(agentID, outcome string, confidence float32) uuid.UUID {
+		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: agentID,
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      outcome,
+				Confidence:   confidence,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+		require.NoError(t, tErr)
+		defer func() { _ = resp.Body.Close() }()
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+		var result struct {
+			Data struct {
+				DecisionID uuid.UUID `json:"decision_id"`
+			} `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &result))
+		require.NotEqual(t, uuid.Nil, result.Data.DecisionID)
+		return result.Data.DecisionID
+	}
+
+	// Create 4 decisions: two per side
+	decisionIDs[0] = traceDecision("alpha", "use Redis for caching", 0.9)
+	decisionIDs[1] = traceDecision("beta", "use Memcached for caching", 0.8)
+	decisionIDs[2] = traceDecision("alpha", "Redis cluster mode", 0.85)
+	decisionIDs[3] = traceDecision("beta", "Memcached with mcrouter", 0.75)
+
+	require.NoError(t, testBuf.FlushNow(context.Background()))
+
+	// Insert two conflicts — InsertScoredConflict auto-creates and links the group.
+	for i, pair := range [][2]int{{0, 1}, {2, 3}} {
+		c := model.DecisionConflict{
+			OrgID:         orgID,
+			ConflictKind:  model.ConflictKindCrossAgent,
+			DecisionAID:   decisionIDs[pair[0]],
+			DecisionBID:   decisionIDs[pair[1]],
+			AgentA:        "alpha",
+			AgentB:        "beta",
+			DecisionTypeA: "architecture",
+			DecisionTypeB: "architecture",
+			OutcomeA:      "alpha outcome",
+			OutcomeB:      "beta outcome",
+			Status:        "open",
+		}
+		var err error
+		conflictIDs[i], err = testDB.InsertScoredConflict(context.Background(), c)
+		require.NoError(t, err)
+	}
+
+	// Retrieve the group_id that was auto-created.
+	conflict, err := testDB.GetConflict(context.Background(), conflictIDs[0], orgID)
+	require.NoError(t, err)
+	require.NotNil(t, conflict.GroupID, "InsertScoredConflict must set group_id")
+	groupID = *conflict.GroupID
+
+	return groupID, conflictIDs, decisionIDs
+}
+
+func TestHandleResolveConflictGroup(t *testing.T) {
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid status returns 400", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "acknowledged"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var errResp model.APIError
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &errResp)
+		assert.Equal(t, model.ErrCodeInvalidInput, errResp.Error.Code)
+	})
+
+	t.Run("winning_agent with false_positive returns 400", func(t *testing.T) {
+		agent := "alpha"
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "false_positive", WinningAgent: &agent})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("nonexistent group returns 404", func(t *testing.T) {
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("resolved without winning_agent resolves all open conflicts", func(t *testing.T) {
+		groupID, _, _ := seedConflictGroup(t)
+		note := "all caching decisions settled"
+
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved", ResolutionNote: &note})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var envelope struct {
+			Data model.ConflictGroupResolveResult `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &envelope))
+		assert.Equal(t, groupID, envelope.Data.GroupID)
+		assert.Equal(t, "resolved", envelope.Data.Status)
+		assert.Equal(t, 2, envelope.Data.Resolved, "both open conflicts should be resolved")
+	})
+
+	t.Run("resolved with winning_agent sets winning_decision_id", func(t *testing.T) {
+		groupID, conflictIDs, _ := seedConflictGroup(t)
+		agent := "alpha"
+		note := "alpha is authoritative"
+
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved", WinningAgent: &agent, ResolutionNote: &note})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var envelope struct {
+			Data model.ConflictGroupResolveResult `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &envelope))
+		assert.Equal(t, 2, envelope.Data.Resolved)
+
+		// Verify winning_decision_id was set on each conflict.
+		for _, cid := range conflictIDs {
+			conflict, cErr := testDB.GetConflict(context.Background(), cid, uuid.Nil)
+			require.NoError(t, cErr)
+			require.NotNil(t, conflict)
+			assert.Equal(t, "resolved", conflict.Status)
+			require.NotNil(t, conflict.WinningDecisionID, "winning_decision_id should be set")
+		}
+	})
+
+	t.Run("false_positive resolves without winner", func(t *testing.T) {
+		groupID, conflictIDs, _ := seedConflictGroup(t)
+
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "false_positive"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var envelope struct {
+			Data model.ConflictGroupResolveResult `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &envelope))
+		assert.Equal(t, "false_positive", envelope.Data.Status)
+		assert.Equal(t, 2, envelope.Data.Resolved)
+
+		for _, cid := range conflictIDs {
+			conflict, cErr := testDB.GetConflict(context.Background(), cid, uuid.Nil)
+			require.NoError(t, cErr)
+			require.NotNil(t, conflict)
+			assert.Equal(t, "false_positive", conflict.Status)
+			assert.Nil(t, conflict.WinningDecisionID, "false_positive should not set winner")
+		}
+	})
+
+	t.Run("idempotent re-resolve returns 0 affected", func(t *testing.T) {
+		groupID, _, _ := seedConflictGroup(t)
+
+		// Resolve first time.
+		resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// Resolve second time — no open conflicts remain.
+		resp2, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+groupID.String()+"/resolve", adminToken,
+			model.ConflictGroupResolveRequest{Status: "resolved"})
+		require.NoError(t, err)
+		defer func() { _ = resp2.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp2.StatusCode)
+
+		var envelope struct {
+			Data model.ConflictGroupResolveResult `json:"data"`
+		}
+		b, _ := io.ReadAll(resp2.Body)
+		require.NoError(t, json.Unmarshal(b, &envelope))
+		assert.Equal(t, 0, envelope.Data.Resolved, "no open conflicts to resolve on second call")
+	})
+}
+
+// ---- HandleEraseDecision (GDPR tombstone erasure) -------------------------
+
+func TestHandleEraseDecision(t *testing.T) {
+	// Trace a decision with alternatives and evidence to erase.
+	dt := "erasure_test_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: dt,
+				Outcome:      "sensitive PII outcome",
+				Confidence:   0.85,
+				Reasoning:    ptrStr("contains PII reasoning"),
+				Alternatives: []model.TraceAlternative{
+					{Label: "alt with PII", RejectionReason: ptrStr("PII rejection reason")},
+				},
+				Evidence: []model.TraceEvidence{
+					{SourceType: "document", Content: "PII evidence content", SourceURI: ptrStr("https://example.com/pii")},
+				},
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+			RunID      uuid.UUID `json:"run_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+	runID := traceResult.Data.RunID
+	require.NotEqual(t, uuid.Nil, decisionID)
+
+	t.Run("pre-erasure verify returns verified", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data map[string]any `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "verified", result.Data["status"])
+	})
+
+	t.Run("agent role gets 403", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", agentToken,
+			map[string]string{"reason": "GDPR request"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("admin role gets 403", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", adminToken,
+			map[string]string{"reason": "GDPR request"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("invalid UUID returns 400", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/erase", orgOwnerToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("nonexistent ID returns 404", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/erase", orgOwnerToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successful erasure", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
+			map[string]string{"reason": "GDPR data subject request #42"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data map[string]any `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, decisionID.String(), result.Data["decision_id"])
+		assert.NotEmpty(t, result.Data["erased_at"])
+		assert.NotEmpty(t, result.Data["original_hash"])
+		assert.NotEmpty(t, result.Data["erased_hash"])
+		assert.NotEqual(t, result.Data["original_hash"], result.Data["erased_hash"])
+	})
+
+	t.Run("decision still retrievable but scrubbed", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "[erased]", result.Data.Outcome)
+		assert.NotNil(t, result.Data.Reasoning)
+		assert.Equal(t, "[erased]", *result.Data.Reasoning)
+		assert.Nil(t, result.Data.ValidTo, "erasure must NOT set valid_to")
+	})
+
+	t.Run("verify returns erased status", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data map[string]any `json:"data"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(data, &result))
+		assert.Equal(t, "erased", result.Data["status"])
+		assert.Equal(t, true, result.Data["valid"])
+		assert.NotEmpty(t, result.Data["original_hash"])
+		assert.NotEmpty(t, result.Data["erased_at"])
+		assert.NotEmpty(t, result.Data["erased_by"])
+	})
+
+	t.Run("double erasure returns 409", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
+			map[string]string{"reason": "second attempt"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("DecisionErased event recorded", func(t *testing.T) {
+		orgID := uuid.Nil // default org from SeedAdmin
+		events, err := testDB.GetEventsByRun(context.Background(), orgID, runID, 100)
+		require.NoError(t, err)
+
+		var found bool
+		for _, ev := range events {
+			if ev.EventType == model.EventDecisionErased {
+				found = true
+				assert.Equal(t, decisionID.String(), ev.Payload["decision_id"])
+				assert.NotEmpty(t, ev.Payload["erased_by"])
+				assert.NotEmpty(t, ev.Payload["original_hash"])
+				break
+			}
+		}
+		assert.True(t, found, "expected a DecisionErased event in the run's event log")
+	})
+}
+
+func TestConflictAnalytics(t *testing.T) {
+	orgID := uuid.Nil
+
+	// Trace four decisions so we can create conflicts referencing them.
+	traceDecision := func(agentID, outcome string, confidence float32) uuid.UUID {
+		t.Helper()
+		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: agentID,
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      outcome,
+				Confidence:   confidence,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+		require.NoError(t, tErr)
+		defer func() { _ = resp.Body.Close() }()
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+		var result struct {
+			Data struct {
+				DecisionID uuid.UUID `json:"decision_id"`
+			} `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &result))
+		return result.Data.DecisionID
+	}
+
+	d1 := traceDecision("alpha", "analytics-test: use Postgres", 0.9)
+	d2 := traceDecision("beta", "analytics-test: use MySQL", 0.8)
+	d3 := traceDecision("alpha", "analytics-test: use Redis", 0.7)
+	d4 := traceDecision("gamma", "analytics-test: use Memcached", 0.6)
+	require.NoError(t, testBuf.FlushNow(context.Background()))
+
+	now := time.Now().UTC()
+	threeDaysAgo := now.Add(-3 * 24 * time.Hour)
+	fiveDaysAgo := now.Add(-5 * 24 * time.Hour)
+
+	severity := func(s string) *string { return &s }
+
+	// Insert two conflicts with different agents, severities, and dates.
+	c1 := model.DecisionConflict{
+		OrgID:         orgID,
+		ConflictKind:  model.ConflictKindCrossAgent,
+		DecisionAID:   d1,
+		DecisionBID:   d2,
+		AgentA:        "alpha",
+		AgentB:        "beta",
+		DecisionTypeA: "architecture",
+		DecisionTypeB: "architecture",
+		OutcomeA:      "analytics-test: use Postgres",
+		OutcomeB:      "analytics-test: use MySQL",
+		Status:        "open",
+		Severity:      severity("high"),
+	}
+	id1, err := testDB.InsertScoredConflict(context.Background(), c1)
+	require.NoError(t, err)
+
+	c2 := model.DecisionConflict{
+		OrgID:         orgID,
+		ConflictKind:  model.ConflictKindCrossAgent,
+		DecisionAID:   d3,
+		DecisionBID:   d4,
+		AgentA:        "alpha",
+		AgentB:        "gamma",
+		DecisionTypeA: "architecture",
+		DecisionTypeB: "architecture",
+		OutcomeA:      "analytics-test: use Redis",
+		OutcomeB:      "analytics-test: use Memcached",
+		Status:        "resolved",
+		Severity:      severity("medium"),
+	}
+	id2, err := testDB.InsertScoredConflict(context.Background(), c2)
+	require.NoError(t, err)
+
+	// Backdate detected_at and set resolved_at for the second conflict.
+	_, err = testDB.Pool().Exec(context.Background(),
+		`UPDATE scored_conflicts SET detected_at = $1 WHERE id = $2`, fiveDaysAgo, id1)
+	require.NoError(t, err)
+	_, err = testDB.Pool().Exec(context.Background(),
+		`UPDATE scored_conflicts SET detected_at = $1, resolved_at = $2, resolved_by = 'test', status = 'resolved' WHERE id = $3`,
+		threeDaysAgo, now, id2)
+	require.NoError(t, err)
+
+	t.Run("default period returns analytics", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ConflictAnalytics `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		analytics := result.Data
+
+		assert.False(t, analytics.Period.Start.IsZero())
+		assert.False(t, analytics.Period.End.IsZero())
+		// Both conflicts are within the default 7d window.
+		assert.GreaterOrEqual(t, analytics.Summary.TotalDetected, 2)
+		assert.GreaterOrEqual(t, analytics.Summary.TotalResolved, 1)
+		assert.NotNil(t, analytics.ByAgentPair)
+		assert.NotNil(t, analytics.BySeverity)
+		assert.NotNil(t, analytics.ByDecisionType)
+		assert.NotNil(t, analytics.Trend)
+		// Trend should have 7 entries for a 7d period (one per day).
+		assert.Len(t, analytics.Trend, 7)
+	})
+
+	t.Run("explicit from/to", func(t *testing.T) {
+		from := fiveDaysAgo.Add(-1 * time.Hour).Format(time.RFC3339)
+		to := now.Add(1 * time.Hour).Format(time.RFC3339)
+		resp, err := authedRequest("GET",
+			testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("agent_id filter", func(t *testing.T) {
+		resp, err := authedRequest("GET",
+			testSrv.URL+"/v1/conflicts/analytics?agent_id=alpha", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data model.ConflictAnalytics `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		// alpha appears in both conflicts.
+		assert.GreaterOrEqual(t, result.Data.Summary.TotalDetected, 2)
+	})
+
+	t.Run("invalid period returns 400", func(t *testing.T) {
+		resp, err := authedRequest("GET",
+			testSrv.URL+"/v1/conflicts/analytics?period=999d", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("from after to returns 400", func(t *testing.T) {
+		from := now.Format(time.RFC3339)
+		to := fiveDaysAgo.Format(time.RFC3339)
+		resp, err := authedRequest("GET",
+			testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+// ---------------------------------------------------------------------------
+// Retention & Legal Hold endpoint tests (issue #265)
+// ---------------------------------------------------------------------------
+
+func TestHandleGetRetention_Default(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			RetentionDays         *int            `json:"retention_days"`
+			RetentionExcludeTypes []string        `json:"retention_exclude_types"`
+			LastRun               *time.Time      `json:"last_run"`
+			LastRunDeleted        *int            `json:"last_run_deleted"`
+			NextRun               *time.Time      `json:"next_run"`
+			Holds                 json.RawMessage `json:"holds"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+
+	// Default org has no retention policy set, so retention_days should be nil.
+	assert.Nil(t, result.Data.RetentionDays, "default org should have nil retention_days")
+	assert.Nil(t, result.Data.LastRun, "no prior run expected")
+}
+
+func TestHandleGetRetention_ForbiddenUnauthenticated(t *testing.T) {
+	// Unauthenticated request should be rejected.
+	resp, err := http.Get(testSrv.URL + "/v1/retention")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestHandleSetRetention_Valid(t *testing.T) {
+	days := 30
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days":          days,
+		"retention_exclude_types": []string{"security", "compliance"},
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			RetentionDays         *int     `json:"retention_days"`
+			RetentionExcludeTypes []string `json:"retention_exclude_types"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotNil(t, result.Data.RetentionDays)
+	assert.Equal(t, 30, *result.Data.RetentionDays)
+	assert.Equal(t, []string{"security", "compliance"}, result.Data.RetentionExcludeTypes)
+
+	// Clean up: reset to nil so we don't affect other tests.
+	resetResp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days":          nil,
+		"retention_exclude_types": nil,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resetResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resetResp.StatusCode)
+}
+
+func TestHandleSetRetention_InvalidDays(t *testing.T) {
+	// Zero days should fail validation (must be >= 1).
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days": 0,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Negative days should also fail.
+	resp2, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days": -5,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
+}
+
+func TestHandleSetRetention_ForbiddenForAgent(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", agentToken, map[string]any{
+		"retention_days": 60,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleCreateHold_Valid(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	to := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "Legal review Q1",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			ID        string `json:"id"`
+			Reason    string `json:"reason"`
+			CreatedBy string `json:"created_by"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.NotEmpty(t, result.Data.ID, "hold should have an ID")
+	assert.Equal(t, "Legal review Q1", result.Data.Reason)
+	assert.NotEmpty(t, result.Data.CreatedBy)
+}
+
+func TestHandleCreateHold_MissingReason(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).UTC()
+	to := time.Now().Add(24 * time.Hour).UTC()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateHold_MissingTimeRange(t *testing.T) {
+	// Missing both from and to.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "Missing times",
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateHold_ToBeforeFrom(t *testing.T) {
+	from := time.Now().Add(24 * time.Hour).UTC()
+	to := time.Now().Add(-24 * time.Hour).UTC()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "Backwards range",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateHold_ForbiddenForAgent(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).UTC()
+	to := time.Now().Add(24 * time.Hour).UTC()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", agentToken, map[string]any{
+		"reason": "Should fail",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleReleaseHold_Valid(t *testing.T) {
+	from := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	to := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+
+	// Create a hold to release.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "To be released",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var createResult struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &createResult))
+	holdID := createResult.Data.ID
+	require.NotEmpty(t, holdID)
+
+	// Release the hold.
+	resp2, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusNoContent, resp2.StatusCode)
+
+	// Releasing the same hold again should return 404.
+	resp3, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp3.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp3.StatusCode)
+}
+
+func TestHandleReleaseHold_NotFound(t *testing.T) {
+	unknownID := uuid.New().String()
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+unknownID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleReleaseHold_InvalidID(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/not-a-uuid", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandlePurge_DryRun(t *testing.T) {
+	before := time.Now().Add(-365 * 24 * time.Hour).UTC()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken, map[string]any{
+		"before":  before.Format(time.RFC3339),
+		"dry_run": true,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			DryRun      bool `json:"dry_run"`
+			WouldDelete struct {
+				Decisions    int64 `json:"decisions"`
+				Alternatives int64 `json:"alternatives"`
+				Evidence     int64 `json:"evidence"`
+				Claims       int64 `json:"claims"`
+				Events       int64 `json:"events"`
+			} `json:"would_delete"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.True(t, result.Data.DryRun, "response should indicate dry_run=true")
+	// Count should be >= 0 (we don't care about exact value, just valid response).
+	assert.GreaterOrEqual(t, result.Data.WouldDelete.Decisions, int64(0))
+}
+
+func TestHandlePurge_MissingBefore(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken, map[string]any{
+		"dry_run": true,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandlePurge_ForbiddenForAgent(t *testing.T) {
+	before := time.Now().Add(-365 * 24 * time.Hour).UTC()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", agentToken, map[string]any{
+		"before":  before.Format(time.RFC3339),
+		"dry_run": true,
+	})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleGetRetention_ShowsHoldsAfterCreate(t *testing.T) {
+	from := time.Now().Add(-12 * time.Hour).UTC().Truncate(time.Second)
+	to := time.Now().Add(12 * time.Hour).UTC().Truncate(time.Second)
+
+	// Create a hold.
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason": "Visible in GET",
+		"from":   from.Format(time.RFC3339),
+		"to":     to.Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	defer func() { _ = createResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	createBody, _ := io.ReadAll(createResp.Body)
+	require.NoError(t, json.Unmarshal(createBody, &created))
+	holdID := created.Data.ID
+
+	// GET /v1/retention should include the hold we just created.
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = getResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var getResult struct {
+		Data struct {
+			Holds []struct {
+				ID     string `json:"id"`
+				Reason string `json:"reason"`
+			} `json:"holds"`
+		} `json:"data"`
+	}
+	getBody, _ := io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(getBody, &getResult))
+
+	found := false
+	for _, h := range getResult.Data.Holds {
+		if h.ID == holdID {
+			found = true
+			assert.Equal(t, "Visible in GET", h.Reason)
+			break
+		}
+	}
+	assert.True(t, found, "newly created hold should appear in GET /v1/retention holds list")
+}
+
+// ---------------------------------------------------------------------------
+// HandleCreateAgent tests
+// ---------------------------------------------------------------------------
+
+func TestHandleCreateAgent_HappyPath(t *testing.T) {
+	agentID := "create-agent-" + uuid.New().String()[:8]
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{
+			AgentID: agentID,
+			Name:    "Test Create Agent",
+			Role:    model.RoleAgent,
+			APIKey:  "test-key-" + agentID,
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Agent  model.Agent `json:"agent"`
+			APIKey struct {
+				ID     uuid.UUID `json:"id"`
+				Prefix string    `json:"prefix"`
+			} `json:"api_key"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, agentID, result.Data.Agent.AgentID)
+	assert.Equal(t, "Test Create Agent", result.Data.Agent.Name)
+	assert.Equal(t, model.RoleAgent, result.Data.Agent.Role)
+}
+
+func TestHandleCreateAgent_ServerGeneratedKey(t *testing.T) {
+	agentID := "create-srvkey-" + uuid.New().String()[:8]
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{
+			AgentID: agentID,
+			Name:    "Server Key Agent",
+			// No APIKey — server generates one.
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			RawKey string `json:"raw_key"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.NotEmpty(t, result.Data.RawKey, "server-generated key should be returned once")
+}
+
+func TestHandleBulkCreateAgents(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents/bulk", adminToken,
+		model.BulkCreateAgentsRequest{Agents: []model.CreateAgentRequest{
+			{AgentID: "bulk-a-" + suffix, Name: "Bulk A"},
+			{AgentID: "bulk-b-" + suffix, Name: "Bulk B", Role: model.RoleReader},
+		}})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data model.BulkCreateAgentsResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Data.Agents, 2)
+	assert.Equal(t, "bulk-a-"+suffix, result.Data.Agents[0].Agent.AgentID)
+	assert.Equal(t, model.RoleReader, result.Data.Agents[1].Agent.Role)
+	for _, a := range result.Data.Agents {
+		assert.NotEmpty(t, a.RawKey, "generated keys should be returned once")
+		assert.NotEqual(t, uuid.Nil, a.APIKey.ID)
+	}
+}
+
+func TestHandleBulkCreateAgents_AggregatesErrors(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	okID := "bulk-ok-" + suffix
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents/bulk", adminToken,
+		model.BulkCreateAgentsRequest{Agents: []model.CreateAgentRequest{
+			{AgentID: okID, Name: "Fine"},
+			{AgentID: "bulk-noname-" + suffix},
+			{AgentID: okID, Name: "Duplicate"},
+			{AgentID: "test-agent", Name: "Existing"},
+		}})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Error struct {
+			Details struct {
+				Errors []model.BulkItemError `json:"errors"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	errs := result.Error.Details.Errors
+	require.Len(t, errs, 3)
+	assert.Equal(t, 1, errs[0].Index)
+	assert.Equal(t, model.ErrCodeInvalidInput, errs[0].Code)
+	assert.Equal(t, 2, errs[1].Index)
+	assert.Equal(t, model.ErrCodeConflict, errs[1].Code)
+	assert.Equal(t, 3, errs[2].Index)
+	assert.Equal(t, model.ErrCodeConflict, errs[2].Code)
+
+	// Nothing from the batch may have been created.
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+okID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = getResp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
+}
+
+func TestHandleBulkCreateAgents_MaxBatchSize(t *testing.T) {
+	agents := make([]model.CreateAgentRequest, model.MaxBulkAgents+1)
+	for i := range agents {
+		agents[i] = model.CreateAgentRequest{AgentID: fmt.Sprintf("bulk-max-%d", i), Name: "x"}
+	}
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents/bulk", adminToken,
+		model.BulkCreateAgentsRequest{Agents: agents})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateAgent_MissingFields(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.CreateAgentRequest
+	}{
+		{name: "missing agent_id", req: model.CreateAgentRequest{Name: "Agent"}},
+		{name: "missing name", req: model.CreateAgentRequest{AgentID: "some-id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, tt.req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandleCreateAgent_DuplicateAgentID(t *testing.T) {
+	agentID := "dup-agent-" + uuid.New().String()[:8]
+	// Create first.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: agentID, Name: "First", APIKey: "key-" + agentID})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Create duplicate.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: agentID, Name: "Second", APIKey: "key2-" + agentID})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
+}
+
+func TestHandleCreateAgent_InvalidRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{
+			AgentID: "invalid-role-agent",
+			Name:    "Bad Role",
+			Role:    "superadmin",
+			APIKey:  "key-invalid-role",
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateAgent_CannotEscalateRole(t *testing.T) {
+	// Admin (rank 3) cannot create platform_admin (rank 5).
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{
+			AgentID: "escalation-attempt",
+			Name:    "Escalation",
+			Role:    model.RolePlatformAdmin,
+			APIKey:  "key-escalation",
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleCreateAgent_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", agentToken,
+		model.CreateAgentRequest{AgentID: "agent-attempt", Name: "Attempt", APIKey: "k"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHandleCreateAgent_ReservedID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: "admin", Name: "Sneaky Admin", APIKey: "k"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleCreateAgent_WithTags(t *testing.T) {
+	agentID := "tagged-agent-" + uuid.New().String()[:8]
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{
+			AgentID: agentID,
+			Name:    "Tagged Agent",
+			Tags:    []string{"backend", "production"},
+			APIKey:  "key-" + agentID,
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Agent model.Agent `json:"agent"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, []string{"backend", "production"}, result.Data.Agent.Tags)
+}
+
+// ---------------------------------------------------------------------------
+// HandleListAgents tests
+// ---------------------------------------------------------------------------
+
+func TestHandleListAgents_HappyPath(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data  []model.Agent `json:"data"`
+		Total *int          `json:"total"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Greater(t, len(result.Data), 0)
+	require.NotNil(t, result.Total)
+	assert.Greater(t, *result.Total, 0)
+}
+
+func TestHandleListAgents_WithStats(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?include=stats", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data []struct {
+			AgentID       string `json:"agent_id"`
+			DecisionCount int    `json:"decision_count"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Greater(t, len(result.Data), 0)
+}
+
+func TestHandleListAgents_Pagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?limit=1&offset=0", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data    []model.Agent `json:"data"`
+		Total   *int          `json:"total"`
+		HasMore bool          `json:"has_more"`
+		Limit   int           `json:"limit"`
+		Offset  int           `json:"offset"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Len(t, result.Data, 1)
+	assert.Equal(t, 1, result.Limit)
+	assert.Equal(t, 0, result.Offset)
+	assert.True(t, result.HasMore, "there are multiple agents so has_more should be true")
+}
+
+func TestHandleListAgents_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+// ---------------------------------------------------------------------------
+// HandleGetAgent tests
+// ---------------------------------------------------------------------------
+
+func TestHandleGetAgent_HappyPath(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data model.Agent `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "test-agent", result.Data.AgentID)
+	assert.Equal(t, "Test Agent", result.Data.Name)
+}
+
+func TestHandleGetAgent_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleGetAgent_InvalidAgentID(t *testing.T) {
+	// Agent IDs must match a pattern; try one with invalid characters.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/INVALID%20AGENT!", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleGetAgent_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+// ---------------------------------------------------------------------------
+// HandleUpdateAgent tests
+// ---------------------------------------------------------------------------
+
+func TestHandleUpdateAgent_UpdateName(t *testing.T) {
+	// Create a dedicated agent to update.
+	agentID := "update-name-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, "Original Name", "agent", "key-"+agentID)
+
+	newName := "Updated Name"
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+agentID, adminToken,
+		model.UpdateAgentRequest{Name: &newName})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data model.Agent `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "Updated Name", result.Data.Name)
+}
+
+func TestHandleUpdateAgent_UpdateMetadata(t *testing.T) {
+	agentID := "update-meta-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, "Meta Agent", "agent", "key-"+agentID)
+
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+agentID, adminToken,
+		model.UpdateAgentRequest{Metadata: map[string]any{"team": "platform"}})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data model.Agent `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "platform", result.Data.Metadata["team"])
+}
+
+func TestHandleUpdateAgent_NoFieldsProvided(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		model.UpdateAgentRequest{})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleUpdateAgent_EmptyName(t *testing.T) {
+	empty := ""
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		model.UpdateAgentRequest{Name: &empty})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleUpdateAgent_NotFound(t *testing.T) {
+	newName := "Some Name"
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken,
+		model.UpdateAgentRequest{Name: &newName})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleUpdateAgent_ForbiddenForAgentRole(t *testing.T) {
+	newName := "Hacked Name"
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", agentToken,
+		model.UpdateAgentRequest{Name: &newName})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+// ---------------------------------------------------------------------------
+// HandleAgentStats tests
+// ---------------------------------------------------------------------------
 
-	t.Run("decision still retrievable but scrubbed", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestHandleAgentStats_HappyPath(t *testing.T) {
+	// test-agent has decisions from earlier test runs.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/stats", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-		var result struct {
-			Data model.Decision `json:"data"`
-		}
-		data, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(data, &result))
-		assert.Equal(t, "[erased]", result.Data.Outcome)
-		assert.NotNil(t, result.Data.Reasoning)
-		assert.Equal(t, "[erased]", *result.Data.Reasoning)
-		assert.Nil(t, result.Data.ValidTo, "erasure must NOT set valid_to")
-	})
+	var result struct {
+		Data struct {
+			AgentID string `json:"agent_id"`
+			Stats   struct {
+				DecisionCount int `json:"decision_count"`
+			} `json:"stats"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "test-agent", result.Data.AgentID)
+	assert.GreaterOrEqual(t, result.Data.Stats.DecisionCount, 0)
+}
 
-	t.Run("verify returns erased status", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+decisionID.String(), adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestHandleAgentStats_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent-xyz/stats", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-		var result struct {
-			Data map[string]any `json:"data"`
-		}
-		data, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(data, &result))
-		assert.Equal(t, "erased", result.Data["status"])
-		assert.Equal(t, true, result.Data["valid"])
-		assert.NotEmpty(t, result.Data["original_hash"])
-		assert.NotEmpty(t, result.Data["erased_at"])
-		assert.NotEmpty(t, result.Data["erased_by"])
-	})
+func TestHandleAgentStats_InvalidAgentID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/INVALID%20AGENT!/stats", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	t.Run("double erasure returns 409", func(t *testing.T) {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
-			map[string]string{"reason": "second attempt"})
+func TestHandleAgentStats_GrantEnforcement(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	readerID, targetID := "stats-reader-"+suffix, "stats-target-"+suffix
+	createAgent(testSrv.URL, adminToken, readerID, "Stats Reader", "agent", readerID+"-key")
+	createAgent(testSrv.URL, adminToken, targetID, "Stats Target", "agent", targetID+"-key")
+	token := getToken(testSrv.URL, readerID, readerID+"-key")
+
+	status := func(agentID string) int {
+		t.Helper()
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+agentID+"/stats", token, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusConflict, resp.StatusCode)
-	})
+		return resp.StatusCode
+	}
 
-	t.Run("DecisionErased event recorded", func(t *testing.T) {
-		orgID := uuid.Nil // default org from SeedAdmin
-		events, err := testDB.GetEventsByRun(context.Background(), orgID, runID, 100)
-		require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status(readerID), "an agent can read its own stats")
+	assert.Equal(t, http.StatusForbidden, status(targetID), "another agent's stats need a grant")
 
-		var found bool
-		for _, ev := range events {
-			if ev.EventType == model.EventDecisionErased {
-				found = true
-				assert.Equal(t, decisionID.String(), ev.Payload["decision_id"])
-				assert.NotEmpty(t, ev.Payload["erased_by"])
-				assert.NotEmpty(t, ev.Payload["original_hash"])
-				break
-			}
-		}
-		assert.True(t, found, "expected a DecisionErased event in the run's event log")
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken, model.CreateGrantRequest{
+		GranteeAgentID: readerID, ResourceType: "agent_traces", ResourceID: &targetID, Permission: "read",
 	})
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, status(targetID))
 }
 
-func TestConflictAnalytics(t *testing.T) {
-	orgID := uuid.Nil
+// ---------------------------------------------------------------------------
+// HandleListAssessments tests
+// ---------------------------------------------------------------------------
 
-	// Trace four decisions so we can create conflicts referencing them.
-	traceDecision := func(agentID, outcome string, confidence float32) uuid.UUID {
-		t.Helper()
-		resp, tErr := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-			AgentID: agentID,
+func TestHandleListAssessments_HappyPath(t *testing.T) {
+	// Create a decision via trace, then assess it, then list assessments.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
 			Decision: model.TraceDecision{
-				DecisionType: "architecture",
-				Outcome:      outcome,
-				Confidence:   confidence,
+				DecisionType: "assessment_list_test",
+				Outcome:      "approved",
+				Confidence:   0.85,
 			},
 			Context: map[string]any{"project": "test-project"},
 		})
-		require.NoError(t, tErr)
-		defer func() { _ = resp.Body.Close() }()
-		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
-		var result struct {
-			Data struct {
-				DecisionID uuid.UUID `json:"decision_id"`
-			} `json:"data"`
-		}
-		b, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(b, &result))
-		return result.Data.DecisionID
-	}
-
-	d1 := traceDecision("alpha", "analytics-test: use Postgres", 0.9)
-	d2 := traceDecision("beta", "analytics-test: use MySQL", 0.8)
-	d3 := traceDecision("alpha", "analytics-test: use Redis", 0.7)
-	d4 := traceDecision("gamma", "analytics-test: use Memcached", 0.6)
-	require.NoError(t, testBuf.FlushNow(context.Background()))
-
-	now := time.Now().UTC()
-	threeDaysAgo := now.Add(-3 * 24 * time.Hour)
-	fiveDaysAgo := now.Add(-5 * 24 * time.Hour)
-
-	severity := func(s string) *string { return &s }
-
-	// Insert two conflicts with different agents, severities, and dates.
-	c1 := model.DecisionConflict{
-		OrgID:         orgID,
-		ConflictKind:  model.ConflictKindCrossAgent,
-		DecisionAID:   d1,
-		DecisionBID:   d2,
-		AgentA:        "alpha",
-		AgentB:        "beta",
-		DecisionTypeA: "architecture",
-		DecisionTypeB: "architecture",
-		OutcomeA:      "analytics-test: use Postgres",
-		OutcomeB:      "analytics-test: use MySQL",
-		Status:        "open",
-		Severity:      severity("high"),
-	}
-	id1, err := testDB.InsertScoredConflict(context.Background(), c1)
 	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-	c2 := model.DecisionConflict{
-		OrgID:         orgID,
-		ConflictKind:  model.ConflictKindCrossAgent,
-		DecisionAID:   d3,
-		DecisionBID:   d4,
-		AgentA:        "alpha",
-		AgentB:        "gamma",
-		DecisionTypeA: "architecture",
-		DecisionTypeB: "architecture",
-		OutcomeA:      "analytics-test: use Redis",
-		OutcomeB:      "analytics-test: use Memcached",
-		Status:        "resolved",
-		Severity:      severity("medium"),
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
 	}
-	id2, err := testDB.InsertScoredConflict(context.Background(), c2)
-	require.NoError(t, err)
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
 
-	// Backdate detected_at and set resolved_at for the second conflict.
-	_, err = testDB.Pool().Exec(context.Background(),
-		`UPDATE scored_conflicts SET detected_at = $1 WHERE id = $2`, fiveDaysAgo, id1)
-	require.NoError(t, err)
-	_, err = testDB.Pool().Exec(context.Background(),
-		`UPDATE scored_conflicts SET detected_at = $1, resolved_at = $2, resolved_by = 'test', status = 'resolved' WHERE id = $3`,
-		threeDaysAgo, now, id2)
+	// Assess the decision.
+	assessResp, err := authedRequest("POST",
+		testSrv.URL+"/v1/decisions/"+decisionID.String()+"/assess", agentToken,
+		model.AssessRequest{Outcome: model.AssessmentCorrect})
 	require.NoError(t, err)
+	defer func() { _ = assessResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, assessResp.StatusCode)
 
-	t.Run("default period returns analytics", func(t *testing.T) {
-		resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// List assessments.
+	listResp, err := authedRequest("GET",
+		testSrv.URL+"/v1/decisions/"+decisionID.String()+"/assessments", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = listResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, listResp.StatusCode)
 
-		var result struct {
-			Data model.ConflictAnalytics `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		analytics := result.Data
+	var listResult struct {
+		Data struct {
+			DecisionID  uuid.UUID                  `json:"decision_id"`
+			Assessments []model.DecisionAssessment `json:"assessments"`
+			Count       int                        `json:"count"`
+			Summary     model.AssessmentSummary    `json:"summary"`
+		} `json:"data"`
+	}
+	listBody, _ := io.ReadAll(listResp.Body)
+	require.NoError(t, json.Unmarshal(listBody, &listResult))
+	assert.Equal(t, decisionID, listResult.Data.DecisionID)
+	assert.GreaterOrEqual(t, listResult.Data.Count, 1)
+	assert.GreaterOrEqual(t, len(listResult.Data.Assessments), 1)
+	assert.GreaterOrEqual(t, listResult.Data.Summary.Correct, 1)
+}
 
-		assert.False(t, analytics.Period.Start.IsZero())
-		assert.False(t, analytics.Period.End.IsZero())
-		// Both conflicts are within the default 7d window.
-		assert.GreaterOrEqual(t, analytics.Summary.TotalDetected, 2)
-		assert.GreaterOrEqual(t, analytics.Summary.TotalResolved, 1)
-		assert.NotNil(t, analytics.ByAgentPair)
-		assert.NotNil(t, analytics.BySeverity)
-		assert.NotNil(t, analytics.ByDecisionType)
-		assert.NotNil(t, analytics.Trend)
-		// Trend should have 7 entries for a 7d period (one per day).
-		assert.Len(t, analytics.Trend, 7)
-	})
+func TestHandleListAssessments_DecisionNotFound(t *testing.T) {
+	fakeID := uuid.New()
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/decisions/"+fakeID.String()+"/assessments", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	t.Run("explicit from/to", func(t *testing.T) {
-		from := fiveDaysAgo.Add(-1 * time.Hour).Format(time.RFC3339)
-		to := now.Add(1 * time.Hour).Format(time.RFC3339)
-		resp, err := authedRequest("GET",
-			testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
-	})
+func TestHandleListAssessments_InvalidDecisionID(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/decisions/not-a-uuid/assessments", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	t.Run("agent_id filter", func(t *testing.T) {
-		resp, err := authedRequest("GET",
-			testSrv.URL+"/v1/conflicts/analytics?agent_id=alpha", adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+func TestHandleListAssessments_EmptyList(t *testing.T) {
+	// Create a decision but do NOT assess it.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "assessment_empty_test",
+				Outcome:      "denied",
+				Confidence:   0.5,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-		var result struct {
-			Data model.ConflictAnalytics `json:"data"`
-		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		// alpha appears in both conflicts.
-		assert.GreaterOrEqual(t, result.Data.Summary.TotalDetected, 2)
-	})
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
 
-	t.Run("invalid period returns 400", func(t *testing.T) {
-		resp, err := authedRequest("GET",
-			testSrv.URL+"/v1/conflicts/analytics?period=999d", adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
+	listResp, err := authedRequest("GET",
+		testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assessments", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = listResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, listResp.StatusCode)
 
-	t.Run("from after to returns 400", func(t *testing.T) {
-		from := now.Format(time.RFC3339)
-		to := fiveDaysAgo.Format(time.RFC3339)
-		resp, err := authedRequest("GET",
-			testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	})
+	var listResult struct {
+		Data struct {
+			Count       int                        `json:"count"`
+			Assessments []model.DecisionAssessment `json:"assessments"`
+		} `json:"data"`
+	}
+	listBody, _ := io.ReadAll(listResp.Body)
+	require.NoError(t, json.Unmarshal(listBody, &listResult))
+	assert.Equal(t, 0, listResult.Data.Count)
+	assert.Empty(t, listResult.Data.Assessments)
 }
 
 // ---------------------------------------------------------------------------
-// Retention & Legal Hold endpoint tests (issue #265)
+// HandleListConflictGroups tests
 // ---------------------------------------------------------------------------
 
-func TestHandleGetRetention_Default(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleListConflictGroups_HappyPath(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			RetentionDays         *int            `json:"retention_days"`
-			RetentionExcludeTypes []string        `json:"retention_exclude_types"`
-			LastRun               *time.Time      `json:"last_run"`
-			LastRunDeleted        *int            `json:"last_run_deleted"`
-			NextRun               *time.Time      `json:"next_run"`
-			Holds                 json.RawMessage `json:"holds"`
-		} `json:"data"`
+		Data    []model.ConflictGroup `json:"data"`
+		Total   *int                  `json:"total"`
+		HasMore bool                  `json:"has_more"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(body, &result))
-
-	// Default org has no retention policy set, so retention_days should be nil.
-	assert.Nil(t, result.Data.RetentionDays, "default org should have nil retention_days")
-	assert.Nil(t, result.Data.LastRun, "no prior run expected")
+	// Should not be nil; handler ensures JSON array.
+	assert.NotNil(t, result.Data)
 }
 
-func TestHandleGetRetention_ForbiddenUnauthenticated(t *testing.T) {
-	// Unauthenticated request should be rejected.
-	resp, err := http.Get(testSrv.URL + "/v1/retention")
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+func TestHandleListConflictGroups_WithFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "status=open", query: "?status=open"},
+		{name: "decision_type filter", query: "?decision_type=architecture"},
+		{name: "agent_id filter", query: "?agent_id=alpha"},
+		{name: "conflict_kind filter", query: "?conflict_kind=cross_agent"},
+		{name: "combined filters", query: "?status=open&decision_type=architecture&limit=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups"+tt.query, adminToken, nil)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
 }
 
-func TestHandleSetRetention_Valid(t *testing.T) {
-	days := 30
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days":          days,
-		"retention_exclude_types": []string{"security", "compliance"},
-	})
+func TestHandleListConflictGroups_Pagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?limit=1&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			RetentionDays         *int     `json:"retention_days"`
-			RetentionExcludeTypes []string `json:"retention_exclude_types"`
-		} `json:"data"`
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(body, &result))
-	require.NotNil(t, result.Data.RetentionDays)
-	assert.Equal(t, 30, *result.Data.RetentionDays)
-	assert.Equal(t, []string{"security", "compliance"}, result.Data.RetentionExcludeTypes)
+	assert.Equal(t, 1, result.Limit)
+	assert.Equal(t, 0, result.Offset)
+}
 
-	// Clean up: reset to nil so we don't affect other tests.
-	resetResp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days":          nil,
-		"retention_exclude_types": nil,
-	})
+func TestHandleListConflictGroups_UnauthenticatedReturns401(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/v1/conflict-groups")
 	require.NoError(t, err)
-	defer func() { _ = resetResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resetResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleSetRetention_InvalidDays(t *testing.T) {
-	// Zero days should fail validation (must be >= 1).
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days": 0,
-	})
+// ---------------------------------------------------------------------------
+// HandleConflictAnalytics additional edge-case tests
+// ---------------------------------------------------------------------------
+
+func TestHandleConflictAnalytics_ValidPeriods(t *testing.T) {
+	for _, period := range []string{"7d", "30d", "90d"} {
+		t.Run("period="+period, func(t *testing.T) {
+			resp, err := authedRequest("GET",
+				testSrv.URL+"/v1/conflicts/analytics?period="+period, adminToken, nil)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandleConflictAnalytics_UnauthenticatedReturns401(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/v1/conflicts/analytics")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
 
-	// Negative days should also fail.
-	resp2, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days": -5,
-	})
+func TestHandleConflictAnalytics_ConflictKindFilter(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/conflicts/analytics?conflict_kind=cross_agent", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleSetRetention_ForbiddenForAgent(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", agentToken, map[string]any{
-		"retention_days": 60,
-	})
+func TestHandleConflictAnalytics_DecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/conflicts/analytics?decision_type=architecture", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateHold_Valid(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
-	to := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+// ---------------------------------------------------------------------------
+// HandleAgentHistory tests
+// ---------------------------------------------------------------------------
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "Legal review Q1",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
+func TestHandleAgentHistory_HappyPath(t *testing.T) {
+	// Create a fresh decision so the agent definitely has history.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "history_test",
+				Outcome:      "approved",
+				Confidence:   0.8,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	_ = traceResp.Body.Close()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			ID        string `json:"id"`
-			Reason    string `json:"reason"`
-			CreatedBy string `json:"created_by"`
-		} `json:"data"`
+		Data  []model.Decision `json:"data"`
+		Total *int             `json:"total"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(body, &result))
-	assert.NotEmpty(t, result.Data.ID, "hold should have an ID")
-	assert.Equal(t, "Legal review Q1", result.Data.Reason)
-	assert.NotEmpty(t, result.Data.CreatedBy)
+	assert.Greater(t, len(result.Data), 0)
+	require.NotNil(t, result.Total)
+	assert.Greater(t, *result.Total, 0)
 }
 
-func TestHandleCreateHold_MissingReason(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).UTC()
-	to := time.Now().Add(24 * time.Hour).UTC()
-
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
+func TestHandleAgentHistory_Pagination(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history?limit=1&offset=0", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data   []model.Decision `json:"data"`
+		Limit  int              `json:"limit"`
+		Offset int              `json:"offset"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.LessOrEqual(t, len(result.Data), 1)
+	assert.Equal(t, 1, result.Limit)
+	assert.Equal(t, 0, result.Offset)
 }
 
-func TestHandleCreateHold_MissingTimeRange(t *testing.T) {
-	// Missing both from and to.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "Missing times",
-	})
+func TestHandleAgentHistory_WithTimeRange(t *testing.T) {
+	now := time.Now().UTC()
+	from := now.Add(-24 * time.Hour).Format(time.RFC3339)
+	to := now.Add(1 * time.Hour).Format(time.RFC3339)
+
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history?from="+from+"&to="+to,
+		agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateHold_ToBeforeFrom(t *testing.T) {
-	from := time.Now().Add(24 * time.Hour).UTC()
-	to := time.Now().Add(-24 * time.Hour).UTC()
-
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "Backwards range",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
+func TestHandleAgentHistory_InvalidAgentID(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/INVALID%20AGENT!/history", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateHold_ForbiddenForAgent(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).UTC()
-	to := time.Now().Add(24 * time.Hour).UTC()
+func TestHandleAgentHistory_ForbiddenForUnrelatedAgent(t *testing.T) {
+	// Create a second agent and try to access a different agent's history
+	// without a grant. The agent role can access its own history.
+	otherAgentID := "history-other-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Agent", "agent", "key-"+otherAgentID)
+	otherToken := getToken(testSrv.URL, otherAgentID, "key-"+otherAgentID)
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", agentToken, map[string]any{
-		"reason": "Should fail",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history", otherToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleReleaseHold_Valid(t *testing.T) {
-	from := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
-	to := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
-
-	// Create a hold to release.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "To be released",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
+func TestHandleAgentHistory_AdminCanViewAnyAgent(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var createResult struct {
-		Data struct {
-			ID string `json:"id"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &createResult))
-	holdID := createResult.Data.ID
-	require.NotEmpty(t, holdID)
-
-	// Release the hold.
-	resp2, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdID, adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusNoContent, resp2.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-	// Releasing the same hold again should return 404.
-	resp3, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdID, adminToken, nil)
+func TestHandleAgentHistory_InvalidTimeFormat(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/test-agent/history?from=not-a-time", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp3.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp3.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleReleaseHold_NotFound(t *testing.T) {
-	unknownID := uuid.New().String()
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+unknownID, adminToken, nil)
+// ---------------------------------------------------------------------------
+// HandleValidatePair tests
+// ---------------------------------------------------------------------------
+
+func TestHandleValidatePair_NoValidatorReturns501(t *testing.T) {
+	// The test server has no conflict validator configured, so it should return 501.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", adminToken,
+		map[string]any{
+			"outcome_a": "use Postgres",
+			"outcome_b": "use MySQL",
+			"type_a":    "architecture",
+			"type_b":    "architecture",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
 }
 
-func TestHandleReleaseHold_InvalidID(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/not-a-uuid", adminToken, nil)
+func TestHandleValidatePair_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", agentToken,
+		map[string]any{
+			"outcome_a": "use Postgres",
+			"outcome_b": "use MySQL",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandlePurge_DryRun(t *testing.T) {
-	before := time.Now().Add(-365 * 24 * time.Hour).UTC()
+// ---------------------------------------------------------------------------
+// HandleMCPInfo tests
+// ---------------------------------------------------------------------------
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken, map[string]any{
-		"before":  before.Format(time.RFC3339),
-		"dry_run": true,
-	})
+func TestHandleMCPInfo_HappyPath(t *testing.T) {
+	// /mcp/info requires auth because /mcp is an authenticated prefix.
+	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			DryRun      bool `json:"dry_run"`
-			WouldDelete struct {
-				Decisions    int64 `json:"decisions"`
-				Alternatives int64 `json:"alternatives"`
-				Evidence     int64 `json:"evidence"`
-				Claims       int64 `json:"claims"`
-				Events       int64 `json:"events"`
-			} `json:"would_delete"`
-		} `json:"data"`
+		Data model.MCPInfoResponse `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(body, &result))
-	assert.True(t, result.Data.DryRun, "response should indicate dry_run=true")
-	// Count should be >= 0 (we don't care about exact value, just valid response).
-	assert.GreaterOrEqual(t, result.Data.WouldDelete.Decisions, int64(0))
+	assert.Equal(t, "test", result.Data.Version)
+	assert.Equal(t, "streamable-http", result.Data.Transport)
+	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
+	assert.Contains(t, result.Data.Auth.Schemes, "Bearer")
+	assert.Equal(t, "ApiKey", result.Data.Auth.Preferred)
+	assert.NotEmpty(t, result.Data.Auth.Note)
 }
 
-func TestHandlePurge_MissingBefore(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken, map[string]any{
-		"dry_run": true,
-	})
+func TestHandleMCPInfo_UnauthenticatedReturns401(t *testing.T) {
+	// /mcp/info is under the /mcp authenticated prefix.
+	resp, err := http.Get(testSrv.URL + "/mcp/info")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandlePurge_ForbiddenForAgent(t *testing.T) {
-	before := time.Now().Add(-365 * 24 * time.Hour).UTC()
-
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", agentToken, map[string]any{
-		"before":  before.Format(time.RFC3339),
-		"dry_run": true,
-	})
+func TestHandleMCPInfo_ResponseStructure(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
 
-func TestHandleGetRetention_ShowsHoldsAfterCreate(t *testing.T) {
-	from := time.Now().Add(-12 * time.Hour).UTC().Truncate(time.Second)
-	to := time.Now().Add(12 * time.Hour).UTC().Truncate(time.Second)
+	body, _ := io.ReadAll(resp.Body)
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(body, &raw))
+	data, ok := raw["data"].(map[string]any)
+	require.True(t, ok, "response should have a data field")
+	assert.Contains(t, data, "version")
+	assert.Contains(t, data, "transport")
+	assert.Contains(t, data, "auth")
 
-	// Create a hold.
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason": "Visible in GET",
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
-	})
-	require.NoError(t, err)
-	defer func() { _ = createResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	authMap, ok := data["auth"].(map[string]any)
+	require.True(t, ok, "auth should be an object")
+	assert.Contains(t, authMap, "schemes")
+	assert.Contains(t, authMap, "preferred")
+	assert.Contains(t, authMap, "note")
+}
 
-	var created struct {
-		Data struct {
-			ID string `json:"id"`
-		} `json:"data"`
-	}
-	createBody, _ := io.ReadAll(createResp.Body)
-	require.NoError(t, json.Unmarshal(createBody, &created))
-	holdID := created.Data.ID
+// ---- Coverage push: conflict handlers with real data ----
 
-	// GET /v1/retention should include the hold we just created.
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleGetConflict_WithRealConflict(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
+
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var getResult struct {
-		Data struct {
-			Holds []struct {
-				ID     string `json:"id"`
-				Reason string `json:"reason"`
-			} `json:"holds"`
-		} `json:"data"`
+	var result struct {
+		Data map[string]any `json:"data"`
 	}
-	getBody, _ := io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(getBody, &getResult))
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
 
-	found := false
-	for _, h := range getResult.Data.Holds {
-		if h.ID == holdID {
-			found = true
-			assert.Equal(t, "Visible in GET", h.Reason)
-			break
-		}
-	}
-	assert.True(t, found, "newly created hold should appear in GET /v1/retention holds list")
+	assert.Equal(t, conflictID.String(), result.Data["id"])
+	assert.Equal(t, "open", result.Data["status"])
+	// computeRecommendation is invoked for open conflicts. It may or may not
+	// produce a recommendation depending on signal strength (same agent, similar
+	// confidence means insufficient signal), but the code path is exercised.
 }
 
-// ---------------------------------------------------------------------------
-// HandleCreateAgent tests
-// ---------------------------------------------------------------------------
+func TestHandleGetConflict_FalsePositiveConflict(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
 
-func TestHandleCreateAgent_HappyPath(t *testing.T) {
-	agentID := "create-agent-" + uuid.New().String()[:8]
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{
-			AgentID: agentID,
-			Name:    "Test Create Agent",
-			Role:    model.RoleAgent,
-			APIKey:  "test-key-" + agentID,
-		})
+	// Mark the conflict as false_positive.
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
+		map[string]any{"status": "false_positive"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var result struct {
-		Data struct {
-			Agent  model.Agent `json:"agent"`
-			APIKey struct {
-				ID     uuid.UUID `json:"id"`
-				Prefix string    `json:"prefix"`
-			} `json:"api_key"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, agentID, result.Data.Agent.AgentID)
-	assert.Equal(t, "Test Create Agent", result.Data.Agent.Name)
-	assert.Equal(t, model.RoleAgent, result.Data.Agent.Role)
+	// GET should return 200 without computing a recommendation for false_positive conflicts.
+	resp, err = authedRequest("GET", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_ServerGeneratedKey(t *testing.T) {
-	agentID := "create-srvkey-" + uuid.New().String()[:8]
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{
-			AgentID: agentID,
-			Name:    "Server Key Agent",
-			// No APIKey — server generates one.
+func TestHandleAdjudicateConflict_SuccessfulResolution(t *testing.T) {
+	decisionAID, _, conflictID := seedConflict(t)
+
+	resp, err := authedRequest("POST",
+		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
+		adminToken,
+		map[string]any{
+			"outcome":             "Use Redis for caching layer",
+			"reasoning":           "Redis has better support for our use case",
+			"winning_decision_id": decisionAID.String(),
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			RawKey string `json:"raw_key"`
-		} `json:"data"`
+		Data map[string]any `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.NotEmpty(t, result.Data.RawKey, "server-generated key should be returned once")
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "resolved", result.Data["status"])
 }
 
-func TestHandleCreateAgent_MissingFields(t *testing.T) {
-	tests := []struct {
-		name string
-		req  model.CreateAgentRequest
-	}{
-		{name: "missing agent_id", req: model.CreateAgentRequest{Name: "Agent"}},
-		{name: "missing name", req: model.CreateAgentRequest{AgentID: "some-id"}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, tt.req)
-			require.NoError(t, err)
-			defer func() { _ = resp.Body.Close() }()
-			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-		})
-	}
-}
+func TestHandleAdjudicateConflict_InvalidWinningDecisionID(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
 
-func TestHandleCreateAgent_DuplicateAgentID(t *testing.T) {
-	agentID := "dup-agent-" + uuid.New().String()[:8]
-	// Create first.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: agentID, Name: "First", APIKey: "key-" + agentID})
+	resp, err := authedRequest("POST",
+		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
+		adminToken,
+		map[string]any{
+			"outcome":             "Use Redis",
+			"winning_decision_id": uuid.New().String(),
+		})
 	require.NoError(t, err)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 
-	// Create duplicate.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: agentID, Name: "Second", APIKey: "key2-" + agentID})
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "winning_decision_id must be one of the two decisions")
 }
 
-func TestHandleCreateAgent_InvalidRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{
-			AgentID: "invalid-role-agent",
-			Name:    "Bad Role",
-			Role:    "superadmin",
-			APIKey:  "key-invalid-role",
-		})
+func TestHandleAdjudicateConflict_EmptyOutcome(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
+
+	resp, err := authedRequest("POST",
+		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
+		adminToken,
+		map[string]any{"outcome": ""})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_CannotEscalateRole(t *testing.T) {
-	// Admin (rank 3) cannot create platform_admin (rank 5).
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{
-			AgentID: "escalation-attempt",
-			Name:    "Escalation",
-			Role:    model.RolePlatformAdmin,
-			APIKey:  "key-escalation",
+func TestHandleAdjudicateConflict_WithCustomDecisionType(t *testing.T) {
+	_, _, conflictID := seedConflict(t)
+
+	resp, err := authedRequest("POST",
+		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
+		adminToken,
+		map[string]any{
+			"outcome":       "Chose Redis for performance reasons",
+			"decision_type": "trade_off",
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", agentToken,
-		model.CreateAgentRequest{AgentID: "agent-attempt", Name: "Attempt", APIKey: "k"})
+// ---- Coverage push: admin handler deeper paths ----
+
+func TestHandleCreateGrant_ValidGrant(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		map[string]any{
+			"grantee_agent_id": "test-agent",
+			"resource_type":    "agent_traces",
+			"resource_id":      "admin",
+			"permission":       "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_ReservedID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: "admin", Name: "Sneaky Admin", APIKey: "k"})
+func TestHandleCreateGrant_InvalidResourceType(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		map[string]any{
+			"grantee_agent_id": "test-agent",
+			"resource_type":    "invalid_type",
+			"resource_id":      "admin",
+			"permission":       "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_WithTags(t *testing.T) {
-	agentID := "tagged-agent-" + uuid.New().String()[:8]
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{
-			AgentID: agentID,
-			Name:    "Tagged Agent",
-			Tags:    []string{"backend", "production"},
-			APIKey:  "key-" + agentID,
+func TestHandleCreateGrant_InvalidPermission(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		map[string]any{
+			"grantee_agent_id": "test-agent",
+			"resource_type":    "agent_traces",
+			"resource_id":      "admin",
+			"permission":       "write",
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Agent model.Agent `json:"agent"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, []string{"backend", "production"}, result.Data.Agent.Tags)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleListAgents tests
-// ---------------------------------------------------------------------------
-
-func TestHandleListAgents_HappyPath(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents", adminToken, nil)
+func TestHandleCreateGrant_NonexistentGrantee(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		map[string]any{
+			"grantee_agent_id": "nonexistent-agent-xyz",
+			"resource_type":    "agent_traces",
+			"resource_id":      "admin",
+			"permission":       "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data  []model.Agent `json:"data"`
-		Total *int          `json:"total"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Greater(t, len(result.Data), 0)
-	require.NotNil(t, result.Total)
-	assert.Greater(t, *result.Total, 0)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleListAgents_WithStats(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?include=stats", adminToken, nil)
+func TestHandleDeleteGrant_NonexistentGrant(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data []struct {
-			AgentID       string `json:"agent_id"`
-			DecisionCount int    `json:"decision_count"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Greater(t, len(result.Data), 0)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleListAgents_Pagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?limit=1&offset=0", adminToken, nil)
+func TestHandleUpdateAgent_MetadataUpdate(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		map[string]any{
+			"metadata": map[string]any{"team": "backend", "env": "production"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data    []model.Agent `json:"data"`
-		Total   *int          `json:"total"`
-		HasMore bool          `json:"has_more"`
-		Limit   int           `json:"limit"`
-		Offset  int           `json:"offset"`
+		Data map[string]any `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Len(t, result.Data, 1)
-	assert.Equal(t, 1, result.Limit)
-	assert.Equal(t, 0, result.Offset)
-	assert.True(t, result.HasMore, "there are multiple agents so has_more should be true")
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	metadata, ok := result.Data["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "backend", metadata["team"])
 }
 
-func TestHandleListAgents_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents", agentToken, nil)
+func TestHandleUpdateAgent_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/does-not-exist", adminToken,
+		map[string]any{"name": "new name"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleGetAgent tests
-// ---------------------------------------------------------------------------
-
-func TestHandleGetAgent_HappyPath(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
+func TestHandleUpdateAgentTags_EmptyTags(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
+		map[string]any{"tags": []string{}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data model.Agent `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "test-agent", result.Data.AgentID)
-	assert.Equal(t, "Test Agent", result.Data.Name)
 }
 
-func TestHandleGetAgent_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken, nil)
+func TestHandleUpdateAgentTags_InvalidChars(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
+		map[string]any{"tags": []string{"valid-tag", "INVALID:TAG"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleGetAgent_InvalidAgentID(t *testing.T) {
-	// Agent IDs must match a pattern; try one with invalid characters.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/INVALID%20AGENT!", adminToken, nil)
+func TestHandleUpdateAgentTags_ValidMultiple(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
+		map[string]any{"tags": []string{"backend", "production", "team-alpha"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data map[string]any `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	tags, ok := result.Data["tags"].([]any)
+	require.True(t, ok)
+	assert.Len(t, tags, 3)
 }
 
-func TestHandleGetAgent_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", agentToken, nil)
+func TestHandleUpdateAgentTags_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent/tags", adminToken,
+		map[string]any{"tags": []string{"tag1"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleUpdateAgent tests
-// ---------------------------------------------------------------------------
-
-func TestHandleUpdateAgent_UpdateName(t *testing.T) {
-	// Create a dedicated agent to update.
-	agentID := "update-name-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, agentID, "Original Name", "agent", "key-"+agentID)
+// ---- Coverage push: health deeper assertions ----
 
-	newName := "Updated Name"
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+agentID, adminToken,
-		model.UpdateAgentRequest{Name: &newName})
+func TestHandleHealth_Fields(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/health")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data model.Agent `json:"data"`
+		Data model.HealthResponse `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "Updated Name", result.Data.Name)
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+
+	assert.Equal(t, "healthy", result.Data.Status)
+	assert.Equal(t, "connected", result.Data.Postgres)
+	assert.NotEmpty(t, result.Data.Version)
+	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
+	assert.Equal(t, "ok", result.Data.BufferStatus)
 }
 
-func TestHandleUpdateAgent_UpdateMetadata(t *testing.T) {
-	agentID := "update-meta-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, agentID, "Meta Agent", "agent", "key-"+agentID)
+// ---- Coverage push: scoped token deeper paths ----
 
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/"+agentID, adminToken,
-		model.UpdateAgentRequest{Metadata: map[string]any{"team": "platform"}})
+func TestHandleScopedToken_ValidRequest(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{
+			"as_agent_id": "test-agent",
+			"expires_in":  3600,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data model.Agent `json:"data"`
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "platform", result.Data.Metadata["team"])
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.NotEmpty(t, result.Data.Token, "scoped token should be returned")
 }
 
-func TestHandleUpdateAgent_NoFieldsProvided(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		model.UpdateAgentRequest{})
+func TestHandleScopedToken_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{
+			"as_agent_id": "nonexistent-agent-123",
+			"expires_in":  3600,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_EmptyName(t *testing.T) {
-	empty := ""
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		model.UpdateAgentRequest{Name: &empty})
+// ---- Coverage push: run handlers deeper paths ----
+
+func TestHandleCreateRun_WithMetadata(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"metadata": map[string]any{"source": "test", "env": "ci"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_NotFound(t *testing.T) {
-	newName := "Some Name"
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken,
-		model.UpdateAgentRequest{Name: &newName})
+func TestHandleGetRun_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+uuid.New().String(), agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_ForbiddenForAgentRole(t *testing.T) {
-	newName := "Hacked Name"
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", agentToken,
-		model.UpdateAgentRequest{Name: &newName})
+func TestHandleCompleteRun_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.New().String()+"/complete", agentToken,
+		map[string]any{"status": "completed"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleAgentStats tests
-// ---------------------------------------------------------------------------
-
-func TestHandleAgentStats_HappyPath(t *testing.T) {
-	// test-agent has decisions from earlier test runs.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/stats", adminToken, nil)
+func TestHandleCompleteRun_InvalidStatus(t *testing.T) {
+	// Create a run first.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		map[string]any{"agent_id": "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
+	var runResult struct {
 		Data struct {
-			AgentID string `json:"agent_id"`
-			Stats   struct {
-				DecisionCount int `json:"decision_count"`
-			} `json:"stats"`
+			ID string `json:"id"`
 		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "test-agent", result.Data.AgentID)
-	assert.GreaterOrEqual(t, result.Data.Stats.DecisionCount, 0)
-}
-
-func TestHandleAgentStats_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent-xyz/stats", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	b, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "run creation body: %s", string(b))
+	require.NoError(t, json.Unmarshal(b, &runResult))
+	require.NotEmpty(t, runResult.Data.ID)
 
-func TestHandleAgentStats_InvalidAgentID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/INVALID%20AGENT!/stats", adminToken, nil)
+	resp, err = authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID+"/complete", agentToken,
+		map[string]any{"status": "invalid_status"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAgentStats_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/stats", agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
-
-// ---------------------------------------------------------------------------
-// HandleListAssessments tests
-// ---------------------------------------------------------------------------
-
-func TestHandleListAssessments_HappyPath(t *testing.T) {
-	// Create a decision via trace, then assess it, then list assessments.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: "assessment_list_test",
-				Outcome:      "approved",
-				Confidence:   0.85,
+func TestHandleAppendEvents_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.New().String()+"/events", agentToken,
+		map[string]any{
+			"events": []map[string]any{
+				{"type": "test_event", "data": map[string]any{"key": "value"}},
 			},
-			Context: map[string]any{"project": "test-project"},
 		})
 	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
-	decisionID := traceResult.Data.DecisionID
+// ---- Coverage push: retention deeper paths ----
 
-	// Assess the decision.
-	assessResp, err := authedRequest("POST",
-		testSrv.URL+"/v1/decisions/"+decisionID.String()+"/assess", agentToken,
-		model.AssessRequest{Outcome: model.AssessmentCorrect})
+func TestHandleGetRetention_Valid(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = assessResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, assessResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-	// List assessments.
-	listResp, err := authedRequest("GET",
-		testSrv.URL+"/v1/decisions/"+decisionID.String()+"/assessments", agentToken, nil)
+func TestHandleSetRetention_ValidDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{"retention_days": 90})
 	require.NoError(t, err)
-	defer func() { _ = listResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, listResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-	var listResult struct {
-		Data struct {
-			DecisionID  uuid.UUID                  `json:"decision_id"`
-			Assessments []model.DecisionAssessment `json:"assessments"`
-			Count       int                        `json:"count"`
-			Summary     model.AssessmentSummary    `json:"summary"`
-		} `json:"data"`
-	}
-	listBody, _ := io.ReadAll(listResp.Body)
-	require.NoError(t, json.Unmarshal(listBody, &listResult))
-	assert.Equal(t, decisionID, listResult.Data.DecisionID)
-	assert.GreaterOrEqual(t, listResult.Data.Count, 1)
-	assert.GreaterOrEqual(t, len(listResult.Data.Assessments), 1)
-	assert.GreaterOrEqual(t, listResult.Data.Summary.Correct, 1)
+func TestHandleSetRetention_NegativeDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{"retention_days": -1})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleListAssessments_DecisionNotFound(t *testing.T) {
-	fakeID := uuid.New()
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/decisions/"+fakeID.String()+"/assessments", agentToken, nil)
+// ---- Coverage push: key management deeper paths ----
+
+func TestHandleRotateKey_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+uuid.New().String()+"/rotate", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleListAssessments_InvalidDecisionID(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/decisions/not-a-uuid/assessments", agentToken, nil)
+func TestHandleCreateKey_ForAgent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"label":    "coverage-test-key",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
 }
 
-func TestHandleListAssessments_EmptyList(t *testing.T) {
-	// Create a decision but do NOT assess it.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+func TestHandleListKeys_ForAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?agent_id=test-agent", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// ---- Coverage push: trace with session header ----
+
+func TestHandleTrace_WithSessionHeader(t *testing.T) {
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken,
 		model.TraceRequest{
-			AgentID: "test-agent",
+			AgentID: "admin",
 			Decision: model.TraceDecision{
-				DecisionType: "assessment_empty_test",
-				Outcome:      "denied",
-				Confidence:   0.5,
+				DecisionType: "implementation",
+				Outcome:      "use structured logging everywhere",
+				Confidence:   0.9,
 			},
 			Context: map[string]any{"project": "test-project"},
-		})
+		},
+		map[string]string{"X-Session-ID": "test-session-abc"})
 	require.NoError(t, err)
-	defer func() { _ = traceResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+}
 
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+// ---- Coverage push: assessment deeper paths ----
 
-	listResp, err := authedRequest("GET",
-		testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assessments", agentToken, nil)
+func TestHandleListAssessments_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assessments", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = listResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, listResp.StatusCode)
-
-	var listResult struct {
-		Data struct {
-			Count       int                        `json:"count"`
-			Assessments []model.DecisionAssessment `json:"assessments"`
-		} `json:"data"`
-	}
-	listBody, _ := io.ReadAll(listResp.Body)
-	require.NoError(t, json.Unmarshal(listBody, &listResult))
-	assert.Equal(t, 0, listResult.Data.Count)
-	assert.Empty(t, listResult.Data.Assessments)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleListConflictGroups tests
-// ---------------------------------------------------------------------------
+// ---- Coverage push: validate-pair (integration - nil validator returns 501) ----
 
-func TestHandleListConflictGroups_HappyPath(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", adminToken, nil)
+func TestHandleValidatePair_NoValidator(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", adminToken,
+		map[string]any{
+			"outcome_a": "Use Redis",
+			"outcome_b": "Use Memcached",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data    []model.ConflictGroup `json:"data"`
-		Total   *int                  `json:"total"`
-		HasMore bool                  `json:"has_more"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	// Should not be nil; handler ensures JSON array.
-	assert.NotNil(t, result.Data)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
 }
 
-func TestHandleListConflictGroups_WithFilters(t *testing.T) {
-	tests := []struct {
-		name  string
-		query string
-	}{
-		{name: "status=open", query: "?status=open"},
-		{name: "decision_type filter", query: "?decision_type=architecture"},
-		{name: "agent_id filter", query: "?agent_id=alpha"},
-		{name: "conflict_kind filter", query: "?conflict_kind=cross_agent"},
-		{name: "combined filters", query: "?status=open&decision_type=architecture&limit=5"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups"+tt.query, adminToken, nil)
-			require.NoError(t, err)
-			defer func() { _ = resp.Body.Close() }()
-			assert.Equal(t, http.StatusOK, resp.StatusCode)
-		})
-	}
+func TestHandleConflictEval_NoValidator(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/eval", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
 }
 
-func TestHandleListConflictGroups_Pagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?limit=1&offset=0", adminToken, nil)
+func TestHandleAdminFlush(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/flush", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Limit  int `json:"limit"`
-		Offset int `json:"offset"`
+		Data model.FlushResponse `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, 1, result.Limit)
-	assert.Equal(t, 0, result.Offset)
-}
+	data, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.True(t, result.Data.Complete)
+	assert.False(t, result.Data.OutboxEnabled, "test server has no outbox worker")
 
-func TestHandleListConflictGroups_UnauthenticatedReturns401(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/v1/conflict-groups")
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/admin/flush", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-}
-
-// ---------------------------------------------------------------------------
-// HandleConflictAnalytics additional edge-case tests
-// ---------------------------------------------------------------------------
-
-func TestHandleConflictAnalytics_ValidPeriods(t *testing.T) {
-	for _, period := range []string{"7d", "30d", "90d"} {
-		t.Run("period="+period, func(t *testing.T) {
-			resp, err := authedRequest("GET",
-				testSrv.URL+"/v1/conflicts/analytics?period="+period, adminToken, nil)
-			require.NoError(t, err)
-			defer func() { _ = resp.Body.Close() }()
-			assert.Equal(t, http.StatusOK, resp.StatusCode)
-		})
-	}
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp2.StatusCode)
 }
 
-func TestHandleConflictAnalytics_UnauthenticatedReturns401(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/v1/conflicts/analytics")
+func TestHandleRescoreConflicts_NoScorer(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/rescore", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_ConflictKindFilter(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/conflicts/analytics?conflict_kind=cross_agent", adminToken, nil)
+func TestHandleRescoreConflicts_RequiresAdmin(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/rescore", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_DecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/conflicts/analytics?decision_type=architecture", adminToken, nil)
+// ---- Coverage push: grant listing ----
+
+func TestHandleListGrants_ForAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?agent_id=test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleAgentHistory tests
-// ---------------------------------------------------------------------------
-
-func TestHandleAgentHistory_HappyPath(t *testing.T) {
-	// Create a fresh decision so the agent definitely has history.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: "history_test",
-				Outcome:      "approved",
-				Confidence:   0.8,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
-	require.NoError(t, err)
-	_ = traceResp.Body.Close()
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+// ---- Coverage push: decision conflicts & revisions ----
 
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history", agentToken, nil)
+func TestHandleDecisionConflicts_Valid(t *testing.T) {
+	// Trace a decision to get a valid ID.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: "architecture",
+			Outcome:      "use gRPC for internal services",
+			Confidence:   0.85,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data  []model.Decision `json:"data"`
-		Total *int             `json:"total"`
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Greater(t, len(result.Data), 0)
-	require.NotNil(t, result.Total)
-	assert.Greater(t, *result.Total, 0)
-}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-func TestHandleAgentHistory_Pagination(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history?limit=1&offset=0", agentToken, nil)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/conflicts", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data   []model.Decision `json:"data"`
-		Limit  int              `json:"limit"`
-		Offset int              `json:"offset"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.LessOrEqual(t, len(result.Data), 1)
-	assert.Equal(t, 1, result.Limit)
-	assert.Equal(t, 0, result.Offset)
 }
 
-func TestHandleAgentHistory_WithTimeRange(t *testing.T) {
-	now := time.Now().UTC()
-	from := now.Add(-24 * time.Hour).Format(time.RFC3339)
-	to := now.Add(1 * time.Hour).Format(time.RFC3339)
+func TestHandleDecisionRevisions_Valid(t *testing.T) {
+	// Trace a decision.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: "architecture",
+			Outcome:      "use REST for public API",
+			Confidence:   0.8,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history?from="+from+"&to="+to,
-		agentToken, nil)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/revisions", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAgentHistory_InvalidAgentID(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/INVALID%20AGENT!/history", agentToken, nil)
+func TestHandleDecisionRevisions_BadUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAgentHistory_ForbiddenForUnrelatedAgent(t *testing.T) {
-	// Create a second agent and try to access a different agent's history
-	// without a grant. The agent role can access its own history.
-	otherAgentID := "history-other-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Agent", "agent", "key-"+otherAgentID)
-	otherToken := getToken(testSrv.URL, otherAgentID, "key-"+otherAgentID)
+// ---- Coverage push: temporal query ----
 
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history", otherToken, nil)
+func TestHandleTemporalQuery_FutureAsOf(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
+		map[string]any{"as_of": time.Now().Add(24 * time.Hour).Format(time.RFC3339)})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAgentHistory_AdminCanViewAnyAgent(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history", adminToken, nil)
+func TestHandleTemporalQuery_PastAsOf(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
+		map[string]any{"as_of": time.Now().Add(-1 * time.Hour).Format(time.RFC3339)})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAgentHistory_InvalidTimeFormat(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/test-agent/history?from=not-a-time", agentToken, nil)
+// ---- Coverage push: export handler ----
+
+func TestHandleExportDecisions_NDJSON(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
 }
 
-// ---------------------------------------------------------------------------
-// HandleValidatePair tests
-// ---------------------------------------------------------------------------
-
-func TestHandleValidatePair_NoValidatorReturns501(t *testing.T) {
-	// The test server has no conflict validator configured, so it should return 501.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", adminToken,
-		map[string]any{
-			"outcome_a": "use Postgres",
-			"outcome_b": "use MySQL",
-			"type_a":    "architecture",
-			"type_b":    "architecture",
-		})
+func TestHandleExportDecisions_WithTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?decision_type=architecture", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleValidatePair_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", agentToken,
+// ---- Coverage push: purge deeper paths ----
+
+func TestHandlePurge_DryRunBasic(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
 		map[string]any{
-			"outcome_a": "use Postgres",
-			"outcome_b": "use MySQL",
+			"before":  time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"dry_run": true,
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---------------------------------------------------------------------------
-// HandleMCPInfo tests
-// ---------------------------------------------------------------------------
-
-func TestHandleMCPInfo_HappyPath(t *testing.T) {
-	// /mcp/info requires auth because /mcp is an authenticated prefix.
-	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
+func TestHandlePurge_MissingBeforeField(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
+		map[string]any{"dry_run": true})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data model.MCPInfoResponse `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "test", result.Data.Version)
-	assert.Equal(t, "streamable-http", result.Data.Transport)
-	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
-	assert.Contains(t, result.Data.Auth.Schemes, "Bearer")
-	assert.Equal(t, "ApiKey", result.Data.Auth.Preferred)
-	assert.NotEmpty(t, result.Data.Auth.Note)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleMCPInfo_UnauthenticatedReturns401(t *testing.T) {
-	// /mcp/info is under the /mcp authenticated prefix.
-	resp, err := http.Get(testSrv.URL + "/mcp/info")
+func TestHandlePurge_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
+		map[string]any{
+			"before":        time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"decision_type": "architecture",
+			"dry_run":       true,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleMCPInfo_ResponseStructure(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
+func TestHandlePurge_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
+		map[string]any{
+			"before":   time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"agent_id": "test-agent",
+			"dry_run":  true,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-
-	body, _ := io.ReadAll(resp.Body)
-	var raw map[string]any
-	require.NoError(t, json.Unmarshal(body, &raw))
-	data, ok := raw["data"].(map[string]any)
-	require.True(t, ok, "response should have a data field")
-	assert.Contains(t, data, "version")
-	assert.Contains(t, data, "transport")
-	assert.Contains(t, data, "auth")
-
-	authMap, ok := data["auth"].(map[string]any)
-	require.True(t, ok, "auth should be an object")
-	assert.Contains(t, authMap, "schemes")
-	assert.Contains(t, authMap, "preferred")
-	assert.Contains(t, authMap, "note")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: conflict handlers with real data ----
+// ---- Coverage push: key management deeper paths ----
 
-func TestHandleGetConflict_WithRealConflict(t *testing.T) {
-	_, _, conflictID := seedConflict(t)
+func TestHandleCreateKey_MissingAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		map[string]any{"name": "orphan-key"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken, nil)
+func TestHandleGetUsage_ForAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?agent_id=test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data map[string]any `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-
-	assert.Equal(t, conflictID.String(), result.Data["id"])
-	assert.Equal(t, "open", result.Data["status"])
-	// computeRecommendation is invoked for open conflicts. It may or may not
-	// produce a recommendation depending on signal strength (same agent, similar
-	// confidence means insufficient signal), but the code path is exercised.
 }
 
-func TestHandleGetConflict_FalsePositiveConflict(t *testing.T) {
-	_, _, conflictID := seedConflict(t)
+// ---- Coverage push: assess decision deeper paths ----
 
-	// Mark the conflict as false_positive.
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken,
-		map[string]any{"status": "false_positive"})
+func TestHandleAssessDecision_InvalidDecisionID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/assess", adminToken,
+		map[string]any{"outcome": "correct"})
 	require.NoError(t, err)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	// GET should return 200 without computing a recommendation for false_positive conflicts.
-	resp, err = authedRequest("GET", testSrv.URL+"/v1/conflicts/"+conflictID.String(), adminToken, nil)
+func TestHandleAssessDecision_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assess", adminToken,
+		map[string]any{"outcome": "correct"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleAdjudicateConflict_SuccessfulResolution(t *testing.T) {
-	decisionAID, _, conflictID := seedConflict(t)
+func TestHandleAssessDecision_InvalidOutcome(t *testing.T) {
+	// Trace a decision first.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: "architecture",
+			Outcome:      "use DynamoDB for sessions",
+			Confidence:   0.7,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-	resp, err := authedRequest("POST",
-		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
-		adminToken,
-		map[string]any{
-			"outcome":             "Use Redis for caching layer",
-			"reasoning":           "Redis has better support for our use case",
-			"winning_decision_id": decisionAID.String(),
-		})
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", adminToken,
+		map[string]any{"outcome": "invalid_outcome_value"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data map[string]any `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "resolved", result.Data["status"])
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAdjudicateConflict_InvalidWinningDecisionID(t *testing.T) {
-	_, _, conflictID := seedConflict(t)
+// ---- Coverage push: assess decision authorization ----
 
-	resp, err := authedRequest("POST",
-		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
-		adminToken,
-		map[string]any{
-			"outcome":             "Use Redis",
-			"winning_decision_id": uuid.New().String(),
-		})
+func TestHandleAssessDecision_ForbiddenWithoutGrant(t *testing.T) {
+	// Create an agent that has no grants to admin's decisions.
+	agentID := fmt.Sprintf("assess-no-grant-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, agentID, "Assess No Grant", "agent", agentID+"-key")
+	token := getToken(testSrv.URL, agentID, agentID+"-key")
+
+	// Create a decision as admin.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: "assess-authz-test",
+			Outcome:      "test outcome",
+			Confidence:   0.7,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "winning_decision_id must be one of the two decisions")
-}
-
-func TestHandleAdjudicateConflict_EmptyOutcome(t *testing.T) {
-	_, _, conflictID := seedConflict(t)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-	resp, err := authedRequest("POST",
-		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
-		adminToken,
-		map[string]any{"outcome": ""})
+	// Agent without grant tries to assess admin's decision — should be forbidden.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", token,
+		map[string]any{"outcome": "correct"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleAdjudicateConflict_WithCustomDecisionType(t *testing.T) {
-	_, _, conflictID := seedConflict(t)
+func TestHandleAssessDecision_OwnDecisionSucceeds(t *testing.T) {
+	// Agent traces a decision, then assesses it — should succeed.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, model.TraceRequest{
+		AgentID: "test-agent",
+		Decision: model.TraceDecision{
+			DecisionType: "assess-own-test",
+			Outcome:      "test outcome",
+			Confidence:   0.8,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-	resp, err := authedRequest("POST",
-		testSrv.URL+"/v1/conflicts/"+conflictID.String()+"/adjudicate",
-		adminToken,
-		map[string]any{
-			"outcome":       "Chose Redis for performance reasons",
-			"decision_type": "trade_off",
-		})
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", agentToken,
+		map[string]any{"outcome": "correct"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: admin handler deeper paths ----
+func TestHandleAssessDecision_SucceedsWithGrant(t *testing.T) {
+	// Create a dedicated agent that will receive a grant.
+	granteeID := fmt.Sprintf("assess-grantee-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Assess Grantee", "agent", granteeID+"-key")
+	granteeToken := getToken(testSrv.URL, granteeID, granteeID+"-key")
 
-func TestHandleCreateGrant_ValidGrant(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		map[string]any{
-			"grantee_agent_id": "test-agent",
-			"resource_type":    "agent_traces",
-			"resource_id":      "admin",
-			"permission":       "read",
-		})
+	// Create a decision as admin.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+		AgentID: "admin",
+		Decision: model.TraceDecision{
+			DecisionType: "assess-grant-test",
+			Outcome:      "test outcome",
+			Confidence:   0.6,
+		},
+		Context: map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
-}
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
 
-func TestHandleCreateGrant_InvalidResourceType(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		map[string]any{
-			"grantee_agent_id": "test-agent",
-			"resource_type":    "invalid_type",
-			"resource_id":      "admin",
-			"permission":       "read",
+	// Grant the agent access to admin's traces.
+	agentIDStr := "admin"
+	grantResp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &agentIDStr,
+			Permission:     "read",
 		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	defer func() { _ = grantResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, grantResp.StatusCode)
 
-func TestHandleCreateGrant_InvalidPermission(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		map[string]any{
-			"grantee_agent_id": "test-agent",
-			"resource_type":    "agent_traces",
-			"resource_id":      "admin",
-			"permission":       "write",
-		})
+	// Agent with grant assesses admin's decision — should succeed.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", granteeToken,
+		map[string]any{"outcome": "incorrect", "notes": "decision was wrong"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateGrant_NonexistentGrantee(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		map[string]any{
-			"grantee_agent_id": "nonexistent-agent-xyz",
-			"resource_type":    "agent_traces",
-			"resource_id":      "admin",
-			"permission":       "read",
-		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+// ---- Coverage push: check endpoint ----
 
-func TestHandleDeleteGrant_NonexistentGrant(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.New().String(), adminToken, nil)
+func TestHandleCheck_MissingDecisionTypeField(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
+		map[string]any{"query": "test"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_MetadataUpdate(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		map[string]any{
-			"metadata": map[string]any{"team": "backend", "env": "production"},
-		})
+func TestHandleCheck_ValidQuery(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
+		map[string]any{"query": "caching strategy", "decision_type": "architecture"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -5258,3728 +7057,3726 @@ func TestHandleUpdateAgent_MetadataUpdate(t *testing.T) {
 	}
 	b, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(b, &result))
-	metadata, ok := result.Data["metadata"].(map[string]any)
-	require.True(t, ok)
-	assert.Equal(t, "backend", metadata["team"])
+	_, hasDecisions := result.Data["decisions"]
+	assert.True(t, hasDecisions, "check should return decisions field")
 }
 
-func TestHandleUpdateAgent_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/does-not-exist", adminToken,
-		map[string]any{"name": "new name"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+// ---- Coverage push: conflict list filters ----
 
-func TestHandleUpdateAgentTags_EmptyTags(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
-		map[string]any{"tags": []string{}})
+func TestHandleListConflicts_WithSeverityFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?severity=high", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleUpdateAgentTags_InvalidChars(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
-		map[string]any{"tags": []string{"valid-tag", "INVALID:TAG"}})
+func TestHandleListConflicts_WithStatusFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?status=open", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleUpdateAgentTags_ValidMultiple(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
-		map[string]any{"tags": []string{"backend", "production", "team-alpha"}})
+func TestHandleListConflicts_WithConflictKindFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?conflict_kind=cross_agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data map[string]any `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	tags, ok := result.Data["tags"].([]any)
-	require.True(t, ok)
-	assert.Len(t, tags, 3)
 }
 
-func TestHandleUpdateAgentTags_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent/tags", adminToken,
-		map[string]any{"tags": []string{"tag1"}})
+func TestHandleListConflicts_InvalidConflictKind(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?conflict_kind=factaul", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: health deeper assertions ----
-
-func TestHandleHealth_Fields(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/health")
+func TestHandleListConflicts_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?decision_type=architecture", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data model.HealthResponse `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-
-	assert.Equal(t, "healthy", result.Data.Status)
-	assert.Equal(t, "connected", result.Data.Postgres)
-	assert.NotEmpty(t, result.Data.Version)
-	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
-	assert.Equal(t, "ok", result.Data.BufferStatus)
 }
 
-// ---- Coverage push: scoped token deeper paths ----
-
-func TestHandleScopedToken_ValidRequest(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{
-			"as_agent_id": "test-agent",
-			"expires_in":  3600,
-		})
+func TestHandleListConflicts_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?agent=admin", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Token string `json:"token"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.NotEmpty(t, result.Data.Token, "scoped token should be returned")
-}
-
-func TestHandleScopedToken_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{
-			"as_agent_id": "nonexistent-agent-123",
-			"expires_in":  3600,
-		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---- Coverage push: run handlers deeper paths ----
-
-func TestHandleCreateRun_WithMetadata(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"metadata": map[string]any{"source": "test", "env": "ci"},
-		})
+func TestHandleListConflicts_WithPagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=5&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleGetRun_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+uuid.New().String(), agentToken, nil)
+// ---- Coverage push: conflict analytics ----
+
+func TestHandleConflictAnalytics_ValidPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=7d", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.New().String()+"/complete", agentToken,
-		map[string]any{"status": "completed"})
+func TestHandleConflictAnalytics_InvalidPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=invalid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_InvalidStatus(t *testing.T) {
-	// Create a run first.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		map[string]any{"agent_id": "test-agent"})
-	require.NoError(t, err)
-	var runResult struct {
-		Data struct {
-			ID string `json:"id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode, "run creation body: %s", string(b))
-	require.NoError(t, json.Unmarshal(b, &runResult))
-	require.NotEmpty(t, runResult.Data.ID)
+// ---- Coverage push: agent history with time range ----
 
-	resp, err = authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID+"/complete", agentToken,
-		map[string]any{"status": "invalid_status"})
+func TestHandleAgentHistory_TimeRangeFilter(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	to := time.Now().Format(time.RFC3339)
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/agents/admin/history?from="+from+"&to="+to,
+		adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAppendEvents_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.New().String()+"/events", agentToken,
-		map[string]any{
-			"events": []map[string]any{
-				{"type": "test_event", "data": map[string]any{"key": "value"}},
-			},
-		})
+// ---- Coverage push: decisions recent ----
+
+func TestHandleDecisionsRecent_WithDecisionType(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?decision_type=architecture", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: retention deeper paths ----
+// ---- Coverage push: session view ----
 
-func TestHandleGetRetention_Valid(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleSessionView_NewSessionUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleSetRetention_ValidDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{"retention_days": 90})
+// ---- Coverage push: API key authentication (covers verifyAPIKey) ----
+
+func TestAPIKeyAuth_Valid(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
+	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleSetRetention_NegativeDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{"retention_days": -1})
+func TestAPIKeyAuth_InvalidFormat(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
+	req.Header.Set("Authorization", "ApiKey no-colon-here")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-// ---- Coverage push: key management deeper paths ----
-
-func TestHandleRotateKey_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+uuid.New().String()+"/rotate", adminToken, nil)
+func TestAPIKeyAuth_WrongKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
+	req.Header.Set("Authorization", "ApiKey admin:wrong-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleCreateKey_ForAgent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"label":    "coverage-test-key",
-		})
+func TestAPIKeyAuth_NonexistentAgent(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
+	req.Header.Set("Authorization", "ApiKey nonexistent:some-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleListKeys_ForAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?agent_id=test-agent", adminToken, nil)
+// ---- Coverage push: query with various filters ----
+
+func TestHandleQuery_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
+		map[string]any{"filters": map[string]any{"agent_id": []string{"admin"}}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: trace with session header ----
-
-func TestHandleTrace_WithSessionHeader(t *testing.T) {
-	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken,
+func TestHandleQuery_WithAgentRolesFilter(t *testing.T) {
+	dt := "agent_roles_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
 		model.TraceRequest{
-			AgentID: "admin",
-			Decision: model.TraceDecision{
-				DecisionType: "implementation",
-				Outcome:      "use structured logging everywhere",
-				Confidence:   0.9,
-			},
-			Context: map[string]any{"project": "test-project"},
-		},
-		map[string]string{"X-Session-ID": "test-session-abc"})
+			AgentID:  "test-agent",
+			Decision: model.TraceDecision{DecisionType: dt, Outcome: "traced by an agent-role agent", Confidence: 0.7},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
-}
+	_ = traceResp.Body.Close()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-// ---- Coverage push: assessment deeper paths ----
+	query := func(filters model.QueryFilters) (int, []model.Decision) {
+		t.Helper()
+		filters.DecisionType = &dt
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken, model.QueryRequest{Filters: filters})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var result struct {
+			Data []model.Decision `json:"data"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Data
+	}
 
-func TestHandleListAssessments_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assessments", agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	status, decs := query(model.QueryFilters{AgentRoles: []model.AgentRole{model.RoleAgent}})
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, decs, 1)
+	assert.Equal(t, "test-agent", decs[0].AgentID)
+
+	status, decs = query(model.QueryFilters{AgentRoles: []model.AgentRole{model.RoleReader}})
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, decs)
+
+	// Roles intersect with an explicit agent_id filter.
+	status, decs = query(model.QueryFilters{AgentIDs: []string{"admin"}, AgentRoles: []model.AgentRole{model.RoleAgent}})
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, decs)
+
+	status, _ = query(model.QueryFilters{AgentRoles: []model.AgentRole{"superuser"}})
+	assert.Equal(t, http.StatusBadRequest, status)
 }
 
-// ---- Coverage push: validate-pair (integration - nil validator returns 501) ----
+func TestExplainQuery(t *testing.T) {
+	dt := "architecture"
+	body := model.QueryRequest{Filters: model.QueryFilters{DecisionType: &dt}, Limit: 10}
 
-func TestHandleValidatePair_NoValidator(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/validate-pair", adminToken,
-		map[string]any{
-			"outcome_a": "Use Redis",
-			"outcome_b": "Use Memcached",
-		})
+	t.Run("admin gets plan", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/query/explain", adminToken, body)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result struct {
+			Data struct {
+				SQL  string           `json:"sql"`
+				Plan []map[string]any `json:"plan"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Contains(t, result.Data.SQL, "FROM decisions")
+		require.Len(t, result.Data.Plan, 1)
+		assert.Contains(t, result.Data.Plan[0], "Plan", "EXPLAIN JSON output has a top-level Plan node")
+		assert.NotContains(t, result.Data.Plan[0], "Execution Time", "the query must not be executed")
+	})
+
+	t.Run("non-admin forbidden", func(t *testing.T) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/query/explain", agentToken, body)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestHandleQuery_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
+		map[string]any{"filters": map[string]any{"decision_type": "architecture"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleConflictEval_NoValidator(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/conflicts/eval", adminToken, nil)
+func TestHandleQuery_WithTimeRange(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
+		map[string]any{
+			"filters": map[string]any{
+				"time_range": map[string]any{
+					"from": time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+					"to":   time.Now().Format(time.RFC3339),
+				},
+			},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: grant listing ----
-
-func TestHandleListGrants_ForAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?agent_id=test-agent", adminToken, nil)
+func TestHandleQuery_WithPagination(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
+		map[string]any{"limit": 2, "offset": 0})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: decision conflicts & revisions ----
-
-func TestHandleDecisionConflicts_Valid(t *testing.T) {
-	// Trace a decision to get a valid ID.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-		AgentID: "admin",
-		Decision: model.TraceDecision{
-			DecisionType: "architecture",
-			Outcome:      "use gRPC for internal services",
-			Confidence:   0.85,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+// ---- Coverage push: trace with alternatives and evidence ----
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/conflicts", adminToken, nil)
+func TestHandleTrace_WithAlternatives(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				DecisionType: "trade_off",
+				Outcome:      "use connection pooling",
+				Confidence:   0.85,
+				Alternatives: []model.TraceAlternative{
+					{Label: "no pooling"}, {Label: "pgbouncer"},
+				},
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
 }
 
-func TestHandleDecisionRevisions_Valid(t *testing.T) {
-	// Trace a decision.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-		AgentID: "admin",
-		Decision: model.TraceDecision{
-			DecisionType: "architecture",
-			Outcome:      "use REST for public API",
-			Confidence:   0.8,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+// ---- Coverage push: retract and erase ----
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/revisions", adminToken, nil)
+func TestHandleRetractDecision_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/retract", adminToken,
+		map[string]any{"reason": "test retraction"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleDecisionRevisions_BadUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", adminToken, nil)
+func TestHandleEraseDecision_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), orgOwnerToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---- Coverage push: temporal query ----
+// ---- Coverage push: get single decision ----
 
-func TestHandleTemporalQuery_FutureAsOf(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
-		map[string]any{"as_of": time.Now().Add(24 * time.Hour).Format(time.RFC3339)})
+func TestHandleGetDecision_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleTemporalQuery_PastAsOf(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
-		map[string]any{"as_of": time.Now().Add(-1 * time.Hour).Format(time.RFC3339)})
+func TestHandleGetDecision_InvalidID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: export handler ----
+// ---- Coverage push: search ----
 
-func TestHandleExportDecisions_NDJSON(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions", adminToken, nil)
+func TestHandleSearch_BlankQuery(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken,
+		map[string]any{"query": ""})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_WithTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?decision_type=architecture", adminToken, nil)
+func TestHandleSearch_TextQuery(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken,
+		map[string]any{"query": "database caching"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: purge deeper paths ----
+// ---- Coverage push: conflict groups ----
 
-func TestHandlePurge_DryRunBasic(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{
-			"before":  time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"dry_run": true,
-		})
+func TestHandleListConflictGroups_Valid(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandlePurge_MissingBeforeField(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{"dry_run": true})
+func TestHandleListConflictGroups_WithPagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?limit=5&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandlePurge_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{
-			"before":        time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"decision_type": "architecture",
-			"dry_run":       true,
-		})
+// ---- Coverage push: agent stats ----
+
+func TestHandleAgentStats_Valid(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/admin/stats", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandlePurge_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{
-			"before":   time.Now().Add(-365 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"agent_id": "test-agent",
-			"dry_run":  true,
-		})
+func TestHandleAgentStats_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent/stats", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ---- Coverage push: key management deeper paths ----
+// ---- Coverage push: trace missing fields ----
 
-func TestHandleCreateKey_MissingAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		map[string]any{"name": "orphan-key"})
+func TestHandleTrace_NoDecisionType(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				Outcome:    "some outcome",
+				Confidence: 0.9,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleGetUsage_ForAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?agent_id=test-agent", adminToken, nil)
+func TestHandleTrace_NoOutcome(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Confidence:   0.9,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: assess decision deeper paths ----
-
-func TestHandleAssessDecision_InvalidDecisionID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/assess", adminToken,
-		map[string]any{"outcome": "correct"})
+func TestHandleTrace_MissingAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      "some outcome",
+				Confidence:   0.9,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAssessDecision_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assess", adminToken,
-		map[string]any{"outcome": "correct"})
+// ===========================================================================
+// Coverage push: HandlePurge — real purge (non-dry-run) path
+// ===========================================================================
+
+func TestHandlePurge_RealPurge(t *testing.T) {
+	// Real purge with a very old cutoff so nothing is actually deleted,
+	// but the non-dry-run code path (StartDeletionLog, BatchDeleteDecisions,
+	// CompleteDeletionLog) is fully exercised.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
+		map[string]any{
+			"before":  time.Now().Add(-10 * 365 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"dry_run": false,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-func TestHandleAssessDecision_InvalidOutcome(t *testing.T) {
-	// Trace a decision first.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-		AgentID: "admin",
-		Decision: model.TraceDecision{
-			DecisionType: "architecture",
-			Outcome:      "use DynamoDB for sessions",
-			Confidence:   0.7,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var traceResult struct {
+	var result struct {
 		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
+			DryRun  bool           `json:"dry_run"`
+			Deleted map[string]any `json:"deleted"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.False(t, result.Data.DryRun, "should be a real purge, not dry_run")
+	assert.NotNil(t, result.Data.Deleted, "deleted counts should be present")
+}
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", adminToken,
-		map[string]any{"outcome": "invalid_outcome_value"})
+func TestHandlePurge_RealPurgeWithFilters(t *testing.T) {
+	// Real purge with both decision_type and agent_id filters — exercises criteria map building.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
+		map[string]any{
+			"before":        time.Now().Add(-10 * 365 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"decision_type": "architecture",
+			"agent_id":      "test-agent",
+			"dry_run":       false,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-// ---- Coverage push: assess decision authorization ----
-
-func TestHandleAssessDecision_ForbiddenWithoutGrant(t *testing.T) {
-	// Create an agent that has no grants to admin's decisions.
-	agentID := fmt.Sprintf("assess-no-grant-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, agentID, "Assess No Grant", "agent", agentID+"-key")
-	token := getToken(testSrv.URL, agentID, agentID+"-key")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// Create a decision as admin.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-		AgentID: "admin",
-		Decision: model.TraceDecision{
-			DecisionType: "assess-authz-test",
-			Outcome:      "test outcome",
-			Confidence:   0.7,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var traceResult struct {
+	var result struct {
 		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
+			DryRun  bool           `json:"dry_run"`
+			Deleted map[string]any `json:"deleted"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.False(t, result.Data.DryRun)
+}
 
-	// Agent without grant tries to assess admin's decision — should be forbidden.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", token,
-		map[string]any{"outcome": "correct"})
+func TestHandlePurge_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/retention/purge",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAssessDecision_OwnDecisionSucceeds(t *testing.T) {
-	// Agent traces a decision, then assesses it — should succeed.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, model.TraceRequest{
-		AgentID: "test-agent",
-		Decision: model.TraceDecision{
-			DecisionType: "assess-own-test",
-			Outcome:      "test outcome",
-			Confidence:   0.8,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
+// ===========================================================================
+// Coverage push: HandleCreateKey — deeper validation paths
+// ===========================================================================
+
+func TestHandleCreateKey_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "INVALID AGENT!!!", Label: "test"})
 	require.NoError(t, err)
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", agentToken,
-		map[string]any{"outcome": "correct"})
+func TestHandleCreateKey_InvalidLabel(t *testing.T) {
+	longLabel := ""
+	for i := 0; i < 300; i++ {
+		longLabel += "x"
+	}
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "test-agent", Label: longLabel})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAssessDecision_SucceedsWithGrant(t *testing.T) {
-	// Create a dedicated agent that will receive a grant.
-	granteeID := fmt.Sprintf("assess-grantee-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Assess Grantee", "agent", granteeID+"-key")
-	granteeToken := getToken(testSrv.URL, granteeID, granteeID+"-key")
-
-	// Create a decision as admin.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
-		AgentID: "admin",
-		Decision: model.TraceDecision{
-			DecisionType: "assess-grant-test",
-			Outcome:      "test outcome",
-			Confidence:   0.6,
-		},
-		Context: map[string]any{"project": "test-project"},
-	})
+func TestHandleCreateKey_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "does-not-exist-agent", Label: "test"})
 	require.NoError(t, err)
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	// Grant the agent access to admin's traces.
-	agentIDStr := "admin"
-	grantResp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &agentIDStr,
-			Permission:     "read",
-		})
+func TestHandleCreateKey_WithExpiration(t *testing.T) {
+	futureTime := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "test-agent", Label: "expiring-key", ExpiresAt: &futureTime})
 	require.NoError(t, err)
-	defer func() { _ = grantResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, grantResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
 
-	// Agent with grant assesses admin's decision — should succeed.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assess", granteeToken,
-		map[string]any{"outcome": "incorrect", "notes": "decision was wrong"})
+func TestHandleCreateKey_ExpiredExpiresAt(t *testing.T) {
+	pastTime := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "test-agent", Label: "past-key", ExpiresAt: &pastTime})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: check endpoint ----
-
-func TestHandleCheck_MissingDecisionTypeField(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
-		map[string]any{"query": "test"})
+func TestHandleCreateKey_InvalidExpiresAtFormat(t *testing.T) {
+	badFormat := "not-a-date"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: "test-agent", Label: "bad-exp", ExpiresAt: &badFormat})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCheck_ValidQuery(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
-		map[string]any{"query": "caching strategy", "decision_type": "architecture"})
+func TestHandleCreateKey_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", agentToken,
+		model.CreateKeyRequest{AgentID: "test-agent", Label: "denied"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data map[string]any `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	_, hasDecisions := result.Data["decisions"]
-	assert.True(t, hasDecisions, "check should return decisions field")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ---- Coverage push: conflict list filters ----
+// ===========================================================================
+// Coverage push: HandleRotateKey — deeper paths
+// ===========================================================================
 
-func TestHandleListConflicts_WithSeverityFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?severity=high", adminToken, nil)
+func TestHandleRotateKey_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/not-a-uuid/rotate", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleListConflicts_WithStatusFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?status=open", adminToken, nil)
+func TestHandleRotateKey_AlreadyRevoked(t *testing.T) {
+	agentID := fmt.Sprintf("rotate-revoked-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, agentID, "Rotate Revoked", "agent", "rotate-revoked-key")
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
+		model.CreateKeyRequest{AgentID: agentID, Label: "to-revoke-then-rotate"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var created struct {
+		Data model.APIKeyWithRawKey `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, json.Unmarshal(body, &created))
+	keyID := created.Data.ID.String()
+
+	revokeResp, err := authedRequest("DELETE", testSrv.URL+"/v1/keys/"+keyID, adminToken, nil)
+	require.NoError(t, err)
+	_ = revokeResp.Body.Close()
+	require.Equal(t, http.StatusNoContent, revokeResp.StatusCode)
+
+	rotResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID+"/rotate", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = rotResp.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, rotResp.StatusCode)
 }
 
-func TestHandleListConflicts_WithConflictKindFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?conflict_kind=cross_agent", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleGetUsage — period parsing paths
+// ===========================================================================
+
+func TestHandleGetUsage_WithValidPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Period         string `json:"period"`
+			TotalDecisions int    `json:"total_decisions"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "2025-01", result.Data.Period)
 }
 
-func TestHandleListConflicts_InvalidConflictKind(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?conflict_kind=factaul", adminToken, nil)
+func TestHandleGetUsage_InvalidPeriodFormat(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=not-a-period", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleListConflicts_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?decision_type=architecture", adminToken, nil)
+func TestHandleGetUsage_DefaultPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Period  string `json:"period"`
+			ByKey   []any  `json:"by_key"`
+			ByAgent []any  `json:"by_agent"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.NotEmpty(t, result.Data.Period, "default period should be set")
 }
 
-func TestHandleListConflicts_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?agent=admin", adminToken, nil)
+func TestHandleGetUsage_ForbiddenForAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleListConflicts_WithPagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=5&offset=0", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleCreateGrant — deeper validation paths
+// ===========================================================================
+
+func TestHandleCreateGrant_AgentRoleSelfGrant(t *testing.T) {
+	granteeID := fmt.Sprintf("grant-self-target-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Self Grant Target", "reader", "self-grant-key")
+
+	resourceID := "test-agent"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &resourceID,
+			Permission:     "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-// ---- Coverage push: conflict analytics ----
+func TestHandleCreateGrant_AgentRoleCantGrantOthersTraces(t *testing.T) {
+	granteeID := fmt.Sprintf("grant-other-target-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Other Grant Target", "reader", "other-grant-key")
 
-func TestHandleConflictAnalytics_ValidPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=7d", adminToken, nil)
+	otherAgent := "admin"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &otherAgent,
+			Permission:     "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_InvalidPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=invalid", adminToken, nil)
+func TestHandleCreateGrant_WithExpiresAt(t *testing.T) {
+	granteeID := fmt.Sprintf("grant-exp-target-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Exp Grant Target", "reader", "exp-grant-key")
+
+	resourceID := "test-agent"
+	futureTime := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &resourceID,
+			Permission:     "read",
+			ExpiresAt:      &futureTime,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-// ---- Coverage push: agent history with time range ----
-
-func TestHandleAgentHistory_TimeRangeFilter(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
-	to := time.Now().Format(time.RFC3339)
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/agents/admin/history?from="+from+"&to="+to,
-		adminToken, nil)
+func TestHandleCreateGrant_InvalidExpiresAtFormat(t *testing.T) {
+	badFormat := "not-a-date"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: "test-agent",
+			ResourceType:   "agent_traces",
+			Permission:     "read",
+			ExpiresAt:      &badFormat,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: decisions recent ----
+func TestHandleCreateGrant_DuplicateGrant(t *testing.T) {
+	granteeID := fmt.Sprintf("grant-dup-target-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Dup Grant Target", "reader", "dup-grant-key")
+
+	resourceID := "test-agent"
+	grantReq := model.CreateGrantRequest{
+		GranteeAgentID: granteeID,
+		ResourceType:   "agent_traces",
+		ResourceID:     &resourceID,
+		Permission:     "read",
+	}
 
-func TestHandleDecisionsRecent_WithDecisionType(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?decision_type=architecture", adminToken, nil)
+	resp1, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken, grantReq)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
-
-// ---- Coverage push: session view ----
+	_ = resp1.Body.Close()
+	require.Equal(t, http.StatusCreated, resp1.StatusCode)
 
-func TestHandleSessionView_NewSessionUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.New().String(), adminToken, nil)
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken, grantReq)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
 }
 
-// ---- Coverage push: API key authentication (covers verifyAPIKey) ----
+// ===========================================================================
+// Coverage push: HandleDeleteGrant — deeper paths
+// ===========================================================================
 
-func TestAPIKeyAuth_Valid(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
-	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleDeleteGrant_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestAPIKeyAuth_InvalidFormat(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
-	req.Header.Set("Authorization", "ApiKey no-colon-here")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-}
+func TestHandleDeleteGrant_AgentRoleCanDeleteOwnGrant(t *testing.T) {
+	granteeID := fmt.Sprintf("grant-del-self-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, granteeID, "Del Self Target", "reader", "del-self-key")
 
-func TestAPIKeyAuth_WrongKey(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
-	req.Header.Set("Authorization", "ApiKey admin:wrong-key")
-	resp, err := http.DefaultClient.Do(req)
+	resourceID := "test-agent"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
+		model.CreateGrantRequest{
+			GranteeAgentID: granteeID,
+			ResourceType:   "agent_traces",
+			ResourceID:     &resourceID,
+			Permission:     "read",
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-}
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, json.Unmarshal(body, &created))
 
-func TestAPIKeyAuth_NonexistentAgent(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/agents", nil)
-	req.Header.Set("Authorization", "ApiKey nonexistent:some-key")
-	resp, err := http.DefaultClient.Do(req)
+	delResp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+created.Data.ID, agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	defer func() { _ = delResp.Body.Close() }()
+	assert.Equal(t, http.StatusNoContent, delResp.StatusCode)
 }
 
-// ---- Coverage push: query with various filters ----
+// ===========================================================================
+// Coverage push: HandleExportDecisions — filter paths
+// ===========================================================================
 
-func TestHandleQuery_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
-		map[string]any{"filters": map[string]any{"agent_id": []string{"admin"}}})
+func TestHandleExportDecisions_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?agent_id=test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
 }
 
-func TestHandleQuery_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
-		map[string]any{"filters": map[string]any{"decision_type": "architecture"}})
+func TestHandleExportDecisions_WithFromFilter(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?from="+from, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleQuery_WithTimeRange(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
-		map[string]any{
-			"filters": map[string]any{
-				"time_range": map[string]any{
-					"from": time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
-					"to":   time.Now().Format(time.RFC3339),
-				},
-			},
-		})
+func TestHandleExportDecisions_WithToFilter(t *testing.T) {
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleQuery_WithPagination(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken,
-		map[string]any{"limit": 2, "offset": 0})
+func TestHandleExportDecisions_WithFromAndToFilter(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ---- Coverage push: trace with alternatives and evidence ----
-
-func TestHandleTrace_WithAlternatives(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "admin",
-			Decision: model.TraceDecision{
-				DecisionType: "trade_off",
-				Outcome:      "use connection pooling",
-				Confidence:   0.85,
-				Alternatives: []model.TraceAlternative{
-					{Label: "no pooling"}, {Label: "pgbouncer"},
-				},
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleExportDecisions_InvalidFromFormat(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?from=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: retract and erase ----
-
-func TestHandleRetractDecision_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/retract", adminToken,
-		map[string]any{"reason": "test retraction"})
+func TestHandleExportDecisions_InvalidToFormat(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?to=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleEraseDecision_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), orgOwnerToken, nil)
+func TestHandleExportDecisions_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ---- Coverage push: get single decision ----
+// ===========================================================================
+// Coverage push: HandleHookSessionStart — with valid input
+// ===========================================================================
 
-func TestHandleGetDecision_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
+// NOTE: TestHandleHookSessionStart_ValidInput was removed because hook
+// endpoints are guarded by localhostOnly middleware which rejects TCP
+// connections from httptest. Full coverage lives in handlers_hooks_test.go
+// which tests the handler directly without the middleware guard.
+
+// ===========================================================================
+// Coverage push: HandleGetConflict — not found, invalid ID
+// ===========================================================================
+
+func TestHandleGetConflict_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleGetDecision_InvalidID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
+func TestHandleGetConflict_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: search ----
+// ===========================================================================
+// Coverage push: HandleGetRun — access denied, invalid ID, full response
+// ===========================================================================
 
-func TestHandleSearch_BlankQuery(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken,
-		map[string]any{"query": ""})
+func TestHandleGetRun_AccessDenied(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
+		model.CreateRunRequest{AgentID: "admin"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+
+	readerID := fmt.Sprintf("reader-run-%d", time.Now().UnixNano())
+	createAgent(testSrv.URL, adminToken, readerID, "Reader Run", "reader", "reader-run-key")
+	readerToken := getToken(testSrv.URL, readerID, "reader-run-key")
+
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), readerToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = getResp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, getResp.StatusCode)
 }
 
-func TestHandleSearch_TextQuery(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken,
-		map[string]any{"query": "database caching"})
+func TestHandleGetRun_InvalidRunID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/not-a-uuid", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ---- Coverage push: conflict groups ----
+func TestHandleGetRun_WithDecisionsAndEvents(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-func TestHandleListConflictGroups_Valid(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", adminToken, nil)
+	evResp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/events", agentToken,
+		model.AppendEventsRequest{
+			Events: []model.EventInput{
+				{EventType: model.EventToolCallStarted, Payload: map[string]any{"tool": "grep"}},
+				{EventType: model.EventToolCallCompleted, Payload: map[string]any{"result": "ok"}},
+			},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	_ = evResp.Body.Close()
 
-func TestHandleListConflictGroups_WithPagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?limit=5&offset=0", adminToken, nil)
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer flushCancel()
+	_ = testBuf.FlushNow(flushCtx)
+
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runID.String(), agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = getResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Run       model.AgentRun    `json:"run"`
+			Events    []json.RawMessage `json:"events"`
+			Decisions []json.RawMessage `json:"decisions"`
+		} `json:"data"`
+	}
+	data, _ := io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.GreaterOrEqual(t, len(result.Data.Events), 1, "should have at least one event")
+	// decisions may be nil/empty when no decisions exist for the run; the key path exercised is that GetRun returns 200 with the decisions field
 }
 
-// ---- Coverage push: agent stats ----
+// ===========================================================================
+// Coverage push: HandleCreateRun — validation and RBAC paths
+// ===========================================================================
 
-func TestHandleAgentStats_Valid(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/admin/stats", adminToken, nil)
+func TestHandleCreateRun_InvalidAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
+		model.CreateRunRequest{AgentID: "INVALID ID!!!"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAgentStats_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent/stats", adminToken, nil)
+func TestHandleCreateRun_AgentCannotCreateForOthers(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "admin"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ---- Coverage push: trace missing fields ----
-
-func TestHandleTrace_NoDecisionType(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "admin",
-			Decision: model.TraceDecision{
-				Outcome:    "some outcome",
-				Confidence: 0.9,
-			},
-			Context: map[string]any{"project": "test-project"},
+func TestHandleCreateRun_WithTraceIDAndMetadata(t *testing.T) {
+	traceID := "my-trace-id-123"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{
+			AgentID:  "test-agent",
+			TraceID:  &traceID,
+			Metadata: map[string]any{"branch": "main", "task": "deploy"},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-func TestHandleTrace_NoOutcome(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "admin",
-			Decision: model.TraceDecision{
-				DecisionType: "architecture",
-				Confidence:   0.9,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleCreateRun_WithIdempotencyKey(t *testing.T) {
+	idemKey := "run-idem-" + uuid.NewString()
+	runReq := model.CreateRunRequest{AgentID: "test-agent"}
+
+	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs", agentToken, runReq,
+		map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	var result1 struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body1, _ := io.ReadAll(resp1.Body)
+	_ = resp1.Body.Close()
+	require.Equal(t, http.StatusCreated, resp1.StatusCode)
+	require.NoError(t, json.Unmarshal(body1, &result1))
 
-func TestHandleTrace_MissingAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			Decision: model.TraceDecision{
-				DecisionType: "architecture",
-				Outcome:      "some outcome",
-				Confidence:   0.9,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs", agentToken, runReq,
+		map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var result2 struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	_ = resp2.Body.Close()
+	require.NoError(t, json.Unmarshal(body2, &result2))
+	assert.Equal(t, result1.Data.ID, result2.Data.ID, "idempotent replay should return same run ID")
 }
 
 // ===========================================================================
-// Coverage push: HandlePurge — real purge (non-dry-run) path
+// Coverage push: HandleAppendEvents — validation and RBAC paths
 // ===========================================================================
 
-func TestHandlePurge_RealPurge(t *testing.T) {
-	// Real purge with a very old cutoff so nothing is actually deleted,
-	// but the non-dry-run code path (StartDeletionLog, BatchDeleteDecisions,
-	// CompleteDeletionLog) is fully exercised.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{
-			"before":  time.Now().Add(-10 * 365 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"dry_run": false,
-		})
+func TestHandleAppendEvents_EmptyEventsArray(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			DryRun  bool           `json:"dry_run"`
-			Deleted map[string]any `json:"deleted"`
-		} `json:"data"`
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.False(t, result.Data.DryRun, "should be a real purge, not dry_run")
-	assert.NotNil(t, result.Data.Deleted, "deleted counts should be present")
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+
+	evResp, err := authedRequest("POST",
+		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
+		model.AppendEventsRequest{Events: []model.EventInput{}})
+	require.NoError(t, err)
+	defer func() { _ = evResp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, evResp.StatusCode)
 }
 
-func TestHandlePurge_RealPurgeWithFilters(t *testing.T) {
-	// Real purge with both decision_type and agent_id filters — exercises criteria map building.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/purge", adminToken,
-		map[string]any{
-			"before":        time.Now().Add(-10 * 365 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"decision_type": "architecture",
-			"agent_id":      "test-agent",
-			"dry_run":       false,
+func TestHandleAppendEvents_InvalidRunID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/events", agentToken,
+		model.AppendEventsRequest{
+			Events: []model.EventInput{
+				{EventType: model.EventDecisionStarted, Payload: map[string]any{"x": 1}},
+			},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	var result struct {
-		Data struct {
-			DryRun  bool           `json:"dry_run"`
-			Deleted map[string]any `json:"deleted"`
-		} `json:"data"`
+func TestHandleAppendEvents_NotYourRun(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
+		model.CreateRunRequest{AgentID: "admin"})
+	require.NoError(t, err)
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.False(t, result.Data.DryRun)
-}
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
 
-func TestHandlePurge_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/retention/purge",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	evResp, err := authedRequest("POST",
+		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
+		model.AppendEventsRequest{
+			Events: []model.EventInput{
+				{EventType: model.EventDecisionStarted, Payload: map[string]any{"x": 1}},
+			},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	defer func() { _ = evResp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, evResp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateKey — deeper validation paths
+// Coverage push: HandleCompleteRun — RBAC and idempotency paths
 // ===========================================================================
 
-func TestHandleCreateKey_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "INVALID AGENT!!!", Label: "test"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-func TestHandleCreateKey_InvalidLabel(t *testing.T) {
-	longLabel := ""
-	for i := 0; i < 300; i++ {
-		longLabel += "x"
-	}
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "test-agent", Label: longLabel})
+func TestHandleCompleteRun_InvalidRunID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/complete", agentToken,
+		model.CompleteRunRequest{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateKey_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "does-not-exist-agent", Label: "test"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
-
-func TestHandleCreateKey_WithExpiration(t *testing.T) {
-	futureTime := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "test-agent", Label: "expiring-key", ExpiresAt: &futureTime})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-}
-
-func TestHandleCreateKey_ExpiredExpiresAt(t *testing.T) {
-	pastTime := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "test-agent", Label: "past-key", ExpiresAt: &pastTime})
+func TestHandleCompleteRun_NotYourRun(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
+		model.CreateRunRequest{AgentID: "admin"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
 
-func TestHandleCreateKey_InvalidExpiresAtFormat(t *testing.T) {
-	badFormat := "not-a-date"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: "test-agent", Label: "bad-exp", ExpiresAt: &badFormat})
+	complResp, err := authedRequest("POST",
+		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
+		model.CompleteRunRequest{})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	defer func() { _ = complResp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, complResp.StatusCode)
 }
 
-func TestHandleCreateKey_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", agentToken,
-		model.CreateKeyRequest{AgentID: "test-agent", Label: "denied"})
+func TestHandleCompleteRun_WithIdempotencyKey(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-// ===========================================================================
-// Coverage push: HandleRotateKey — deeper paths
-// ===========================================================================
+	idemKey := "complete-idem-" + uuid.NewString()
+	complReq := model.CompleteRunRequest{Status: "completed"}
 
-func TestHandleRotateKey_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/not-a-uuid/rotate", adminToken, nil)
+	resp1, err := authedRequestWithHeaders("POST",
+		testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken, complReq,
+		map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	_ = resp1.Body.Close()
+	require.Equal(t, http.StatusOK, resp1.StatusCode)
 
-func TestHandleRotateKey_AlreadyRevoked(t *testing.T) {
-	agentID := fmt.Sprintf("rotate-revoked-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, agentID, "Rotate Revoked", "agent", "rotate-revoked-key")
+	resp2, err := authedRequestWithHeaders("POST",
+		testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken, complReq,
+		map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+}
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		model.CreateKeyRequest{AgentID: agentID, Label: "to-revoke-then-rotate"})
+func TestHandleCompleteRun_WithMetadata(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	var created struct {
-		Data model.APIKeyWithRawKey `json:"data"`
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	_ = resp.Body.Close()
-	require.NoError(t, json.Unmarshal(body, &created))
-	keyID := created.Data.ID.String()
-
-	revokeResp, err := authedRequest("DELETE", testSrv.URL+"/v1/keys/"+keyID, adminToken, nil)
-	require.NoError(t, err)
-	_ = revokeResp.Body.Close()
-	require.Equal(t, http.StatusNoContent, revokeResp.StatusCode)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &runResult))
 
-	rotResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID+"/rotate", adminToken, nil)
+	complResp, err := authedRequest("POST",
+		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
+		model.CompleteRunRequest{Status: "completed", Metadata: map[string]any{"result": "success", "count": 42}})
 	require.NoError(t, err)
-	defer func() { _ = rotResp.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, rotResp.StatusCode)
+	defer func() { _ = complResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, complResp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetUsage — period parsing paths
+// Coverage push: HandleSetRetention — exclude types, invalid JSON, zero days
 // ===========================================================================
 
-func TestHandleGetUsage_WithValidPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
+func TestHandleSetRetention_WithExcludeTypes(t *testing.T) {
+	days := 90
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{
+			"retention_days":          days,
+			"retention_exclude_types": []string{"architecture", "security"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
 		Data struct {
-			Period         string `json:"period"`
-			TotalDecisions int    `json:"total_decisions"`
+			RetentionDays         int      `json:"retention_days"`
+			RetentionExcludeTypes []string `json:"retention_exclude_types"`
 		} `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "2025-01", result.Data.Period)
+	assert.Equal(t, days, result.Data.RetentionDays)
 }
 
-func TestHandleGetUsage_InvalidPeriodFormat(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=not-a-period", adminToken, nil)
+func TestHandleSetRetention_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("PUT", testSrv.URL+"/v1/retention",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleGetUsage_DefaultPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Period  string `json:"period"`
-			ByKey   []any  `json:"by_key"`
-			ByAgent []any  `json:"by_agent"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.NotEmpty(t, result.Data.Period, "default period should be set")
-}
-
-func TestHandleGetUsage_ForbiddenForAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage", agentToken, nil)
+func TestHandleSetRetention_ZeroDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{"retention_days": 0})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateGrant — deeper validation paths
+// Coverage push: HandleHealth — detailed field verification
 // ===========================================================================
 
-func TestHandleCreateGrant_AgentRoleSelfGrant(t *testing.T) {
-	granteeID := fmt.Sprintf("grant-self-target-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Self Grant Target", "reader", "self-grant-key")
-
-	resourceID := "test-agent"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &resourceID,
-			Permission:     "read",
-		})
+func TestHandleHealth_DetailedFields(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/health")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-func TestHandleCreateGrant_AgentRoleCantGrantOthersTraces(t *testing.T) {
-	granteeID := fmt.Sprintf("grant-other-target-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Other Grant Target", "reader", "other-grant-key")
+	var result struct {
+		Data model.HealthResponse `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
 
-	otherAgent := "admin"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &otherAgent,
-			Permission:     "read",
-		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "healthy", result.Data.Status)
+	assert.Equal(t, "test", result.Data.Version)
+	assert.Equal(t, "connected", result.Data.Postgres)
+	assert.Equal(t, "ok", result.Data.BufferStatus)
+	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
+	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0)
+	assert.Empty(t, result.Data.Qdrant)
+	assert.Empty(t, result.Data.SSEBroker)
 }
 
-func TestHandleCreateGrant_WithExpiresAt(t *testing.T) {
-	granteeID := fmt.Sprintf("grant-exp-target-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Exp Grant Target", "reader", "exp-grant-key")
-
-	resourceID := "test-agent"
-	futureTime := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &resourceID,
-			Permission:     "read",
-			ExpiresAt:      &futureTime,
-		})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-}
+// ===========================================================================
+// Coverage push: HandleRevokeKey — invalid UUID
+// ===========================================================================
 
-func TestHandleCreateGrant_InvalidExpiresAtFormat(t *testing.T) {
-	badFormat := "not-a-date"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: "test-agent",
-			ResourceType:   "agent_traces",
-			Permission:     "read",
-			ExpiresAt:      &badFormat,
-		})
+func TestHandleRevokeKey_InvalidUUID(t *testing.T) {
+	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/not-a-uuid", nil)
+	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(reqDel)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateGrant_DuplicateGrant(t *testing.T) {
-	granteeID := fmt.Sprintf("grant-dup-target-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Dup Grant Target", "reader", "dup-grant-key")
-
-	resourceID := "test-agent"
-	grantReq := model.CreateGrantRequest{
-		GranteeAgentID: granteeID,
-		ResourceType:   "agent_traces",
-		ResourceID:     &resourceID,
-		Permission:     "read",
-	}
-
-	resp1, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken, grantReq)
-	require.NoError(t, err)
-	_ = resp1.Body.Close()
-	require.Equal(t, http.StatusCreated, resp1.StatusCode)
-
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/grants", adminToken, grantReq)
-	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
-}
-
 // ===========================================================================
-// Coverage push: HandleDeleteGrant — deeper paths
+// Coverage push: verifyAPIKey — various credential formats via ApiKey auth
 // ===========================================================================
 
-func TestHandleDeleteGrant_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/not-a-uuid", adminToken, nil)
+func TestAPIKeyAuth_ValidCredential(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleDeleteGrant_AgentRoleCanDeleteOwnGrant(t *testing.T) {
-	granteeID := fmt.Sprintf("grant-del-self-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, granteeID, "Del Self Target", "reader", "del-self-key")
-
-	resourceID := "test-agent"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
-		model.CreateGrantRequest{
-			GranteeAgentID: granteeID,
-			ResourceType:   "agent_traces",
-			ResourceID:     &resourceID,
-			Permission:     "read",
-		})
-	require.NoError(t, err)
-	var created struct {
-		Data struct {
-			ID string `json:"id"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.NoError(t, json.Unmarshal(body, &created))
-
-	delResp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+created.Data.ID, agentToken, nil)
+func TestAPIKeyAuth_InvalidFormat_NoColon(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey noColonHere")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	defer func() { _ = delResp.Body.Close() }()
-	assert.Equal(t, http.StatusNoContent, delResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleExportDecisions — filter paths
-// ===========================================================================
-
-func TestHandleExportDecisions_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?agent_id=test-agent", adminToken, nil)
+func TestAPIKeyAuth_InvalidFormat_EmptyAgentID(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey :some-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_WithFromFilter(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?from="+from, adminToken, nil)
+func TestAPIKeyAuth_InvalidFormat_EmptyKey(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey admin:")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_WithToFilter(t *testing.T) {
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?to="+to, adminToken, nil)
+func TestAPIKeyAuth_WithOrgHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
+	req.Header.Set("X-Akashi-Org-ID", uuid.Nil.String())
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_WithFromAndToFilter(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339)
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
+func TestAPIKeyAuth_InvalidOrgHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
+	req.Header.Set("X-Akashi-Org-ID", "not-a-uuid")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_InvalidFromFormat(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?from=not-a-date", adminToken, nil)
+func TestAPIKeyAuth_WrongOrgHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
+	req.Header.Set("X-Akashi-Org-ID", uuid.New().String())
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_InvalidToFormat(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?to=not-a-date", adminToken, nil)
+func TestAuthToken_WrongAPIKey(t *testing.T) {
+	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "admin", APIKey: "definitely-wrong-key"})
+	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions", agentToken, nil)
+func TestAuthToken_NonexistentAgent(t *testing.T) {
+	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "ghost-agent", APIKey: "some-key"})
+	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleHookSessionStart — with valid input
-// ===========================================================================
-
-// NOTE: TestHandleHookSessionStart_ValidInput was removed because hook
-// endpoints are guarded by localhostOnly middleware which rejects TCP
-// connections from httptest. Full coverage lives in handlers_hooks_test.go
-// which tests the handler directly without the middleware guard.
-
-// ===========================================================================
-// Coverage push: HandleGetConflict — not found, invalid ID
+// Coverage push: HandleGetRetention — verify full response shape
 // ===========================================================================
 
-func TestHandleGetConflict_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken, nil)
+func TestHandleGetRetention_FullResponseShape(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-func TestHandleGetConflict_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var result struct {
+		Data struct {
+			RetentionDays         int    `json:"retention_days"`
+			RetentionExcludeTypes []any  `json:"retention_exclude_types"`
+			LastRun               *any   `json:"last_run"`
+			LastRunDeleted        *any   `json:"last_run_deleted"`
+			NextRun               string `json:"next_run"`
+			Holds                 []any  `json:"holds"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.GreaterOrEqual(t, result.Data.RetentionDays, 0)
+	assert.NotNil(t, result.Data.Holds, "holds should be an array, not nil")
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRun — access denied, invalid ID, full response
+// Coverage push: scoped token — delegation chain, role escalation, TTL capping
 // ===========================================================================
 
-func TestHandleGetRun_AccessDenied(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
-		model.CreateRunRequest{AgentID: "admin"})
+func TestHandleScopedToken_ScopedTokenCannotDelegate(t *testing.T) {
+	// First, get a scoped token for test-agent (admin -> test-agent).
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{"as_agent_id": "test-agent"})
 	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	readerID := fmt.Sprintf("reader-run-%d", time.Now().UnixNano())
-	createAgent(testSrv.URL, adminToken, readerID, "Reader Run", "reader", "reader-run-key")
-	readerToken := getToken(testSrv.URL, readerID, "reader-run-key")
+	var result struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	scopedToken := result.Data.Token
+	require.NotEmpty(t, scopedToken)
 
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), readerToken, nil)
+	// Now try to use the scoped token to issue another scoped token — should be denied.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", scopedToken,
+		map[string]any{"as_agent_id": "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, getResp.StatusCode)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp2.StatusCode, "scoped tokens cannot issue further scoped tokens")
 }
 
-func TestHandleGetRun_InvalidRunID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/not-a-uuid", agentToken, nil)
+func TestHandleScopedToken_CannotImpersonateHigherRole(t *testing.T) {
+	// Admin (rank 3) tries to get a scoped token for org_owner (rank 4) — should be denied.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{"as_agent_id": "test-org-owner"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "cannot impersonate agent with higher or equal role")
 }
 
-func TestHandleGetRun_WithDecisionsAndEvents(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleScopedToken_MissingAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{})
 	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	evResp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/events", agentToken,
-		model.AppendEventsRequest{
-			Events: []model.EventInput{
-				{EventType: model.EventToolCallStarted, Payload: map[string]any{"tool": "grep"}},
-				{EventType: model.EventToolCallCompleted, Payload: map[string]any{"result": "ok"}},
-			},
-		})
+func TestHandleScopedToken_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{"as_agent_id": "INVALID AGENT!!!"})
 	require.NoError(t, err)
-	_ = evResp.Body.Close()
-
-	flushCtx, flushCancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer flushCancel()
-	_ = testBuf.FlushNow(flushCtx)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runID.String(), agentToken, nil)
+func TestHandleScopedToken_OverlyLongTTLIsCapped(t *testing.T) {
+	// Request 48 hours — the handler caps at MaxScopedTokenTTL (1 hour).
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{"as_agent_id": "test-agent", "expires_in": 172800})
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, getResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
 		Data struct {
-			Run       model.AgentRun    `json:"run"`
-			Events    []json.RawMessage `json:"events"`
-			Decisions []json.RawMessage `json:"decisions"`
+			Token     string    `json:"token"`
+			ExpiresAt time.Time `json:"expires_at"`
 		} `json:"data"`
 	}
-	data, _ := io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(data, &result))
-	assert.GreaterOrEqual(t, len(result.Data.Events), 1, "should have at least one event")
-	// decisions may be nil/empty when no decisions exist for the run; the key path exercised is that GetRun returns 200 with the decisions field
-}
-
-// ===========================================================================
-// Coverage push: HandleCreateRun — validation and RBAC paths
-// ===========================================================================
-
-func TestHandleCreateRun_InvalidAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
-		model.CreateRunRequest{AgentID: "INVALID ID!!!"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.NotEmpty(t, result.Data.Token)
+	// Expiry should be within ~1 hour (with some tolerance), not 48 hours.
+	assert.True(t, result.Data.ExpiresAt.Before(time.Now().Add(2*time.Hour)),
+		"TTL should be capped at MaxScopedTokenTTL (1 hour)")
 }
 
-func TestHandleCreateRun_AgentCannotCreateForOthers(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "admin"})
+func TestHandleScopedToken_ForbiddenForAgentRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", agentToken,
+		map[string]any{"as_agent_id": "test-agent"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
+	// Agent role (rank 2) shouldn't have access to the adminOnly-gated endpoint.
 	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleCreateRun_WithTraceIDAndMetadata(t *testing.T) {
-	traceID := "my-trace-id-123"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{
-			AgentID:  "test-agent",
-			TraceID:  &traceID,
-			Metadata: map[string]any{"branch": "main", "task": "deploy"},
-		})
+// ===========================================================================
+// Coverage push: auth middleware — unsupported scheme, malformed header
+// ===========================================================================
+
+func TestAuthMiddleware_UnsupportedScheme(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/conflicts", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "unsupported authorization scheme")
 }
 
-func TestHandleCreateRun_WithIdempotencyKey(t *testing.T) {
-	idemKey := "run-idem-" + uuid.NewString()
-	runReq := model.CreateRunRequest{AgentID: "test-agent"}
-
-	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs", agentToken, runReq,
-		map[string]string{"Idempotency-Key": idemKey})
-	require.NoError(t, err)
-	var result1 struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body1, _ := io.ReadAll(resp1.Body)
-	_ = resp1.Body.Close()
-	require.Equal(t, http.StatusCreated, resp1.StatusCode)
-	require.NoError(t, json.Unmarshal(body1, &result1))
-
-	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs", agentToken, runReq,
-		map[string]string{"Idempotency-Key": idemKey})
+func TestAuthMiddleware_MalformedHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/conflicts", nil)
+	req.Header.Set("Authorization", "BearerTokenWithNoSpace")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	var result2 struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body2, _ := io.ReadAll(resp2.Body)
-	_ = resp2.Body.Close()
-	require.NoError(t, json.Unmarshal(body2, &result2))
-	assert.Equal(t, result1.Data.ID, result2.Data.ID, "idempotent replay should return same run ID")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "invalid authorization format")
 }
 
 // ===========================================================================
-// Coverage push: HandleAppendEvents — validation and RBAC paths
+// Coverage push: HandleTrace — agent-only, forbidden for other agent
 // ===========================================================================
 
-func TestHandleAppendEvents_EmptyEventsArray(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
-	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-
-	evResp, err := authedRequest("POST",
-		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
-		model.AppendEventsRequest{Events: []model.EventInput{}})
+func TestHandleTrace_AgentCannotTraceForOther(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "test",
+			"outcome":       "should fail",
+			"confidence":    0.5,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = evResp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, evResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode,
+		"agent-role caller should not be able to trace for another agent")
 }
 
-func TestHandleAppendEvents_InvalidRunID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/events", agentToken,
-		model.AppendEventsRequest{
-			Events: []model.EventInput{
-				{EventType: model.EventDecisionStarted, Payload: map[string]any{"x": 1}},
-			},
-		})
+// ===========================================================================
+// Coverage push: HandleTemporalQuery — far future rejection
+// ===========================================================================
+
+func TestHandleTemporalQuery_FarFuture(t *testing.T) {
+	futureTime := time.Now().Add(24 * time.Hour)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken, map[string]any{
+		"as_of": futureTime.Format(time.RFC3339),
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "as_of must not be in the future")
 }
 
-func TestHandleAppendEvents_NotYourRun(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
-		model.CreateRunRequest{AgentID: "admin"})
-	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
+// ===========================================================================
+// Coverage push: HandleSessionView — invalid UUID
+// ===========================================================================
 
-	evResp, err := authedRequest("POST",
-		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
-		model.AppendEventsRequest{
-			Events: []model.EventInput{
-				{EventType: model.EventDecisionStarted, Payload: map[string]any{"x": 1}},
-			},
-		})
+func TestHandleSessionView_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = evResp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, evResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "invalid session_id")
 }
 
 // ===========================================================================
-// Coverage push: HandleCompleteRun — RBAC and idempotency paths
+// Coverage push: HandleDeleteAgent — legal hold blocking, disabled flag
 // ===========================================================================
 
-func TestHandleCompleteRun_InvalidRunID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/complete", agentToken,
-		model.CompleteRunRequest{})
+func TestHandleDeleteAgent_BlockedByLegalHold(t *testing.T) {
+	// Create a temporary agent to attempt deletion on.
+	createAgent(testSrv.URL, adminToken, "hold-victim", "Hold Victim", "agent", "hold-victim-key")
+
+	// Create a trace for this agent so the hold has something to cover.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "hold-victim",
+		"decision": map[string]any{
+			"decision_type": "test",
+			"outcome":       "test outcome for hold",
+			"confidence":    0.9,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	_ = traceResp.Body.Close()
 
-func TestHandleCompleteRun_NotYourRun(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
-		model.CreateRunRequest{AgentID: "admin"})
+	// Create a legal hold covering this agent.
+	holdResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason":    "litigation hold for test",
+		"from":      time.Now().Add(-time.Hour).Format(time.RFC3339),
+		"to":        time.Now().Add(time.Hour).Format(time.RFC3339),
+		"agent_ids": []string{"hold-victim"},
+	})
 	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
+	defer func() { _ = holdResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, holdResp.StatusCode)
 
-	complResp, err := authedRequest("POST",
-		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
-		model.CompleteRunRequest{})
+	// Now try to delete — should be blocked by legal hold.
+	deleteResp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/hold-victim", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = complResp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, complResp.StatusCode)
+	defer func() { _ = deleteResp.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, deleteResp.StatusCode)
+	b, _ := io.ReadAll(deleteResp.Body)
+	assert.Contains(t, string(b), "legal hold")
 }
 
-func TestHandleCompleteRun_WithIdempotencyKey(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleDeleteAgent_CannotDeleteSeedAdmin(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/admin", adminToken, nil)
 	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
-
-	idemKey := "complete-idem-" + uuid.NewString()
-	complReq := model.CompleteRunRequest{Status: "completed"}
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "cannot delete the admin agent")
+}
 
-	resp1, err := authedRequestWithHeaders("POST",
-		testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken, complReq,
-		map[string]string{"Idempotency-Key": idemKey})
+func TestHandleDeleteAgent_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/INVALID AGENT!!!", adminToken, nil)
 	require.NoError(t, err)
-	_ = resp1.Body.Close()
-	require.Equal(t, http.StatusOK, resp1.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	resp2, err := authedRequestWithHeaders("POST",
-		testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken, complReq,
-		map[string]string{"Idempotency-Key": idemKey})
+// ===========================================================================
+// Coverage push: HandleUpdateAgent — invalid agent_id format, not found
+// ===========================================================================
+
+func TestHandleUpdateAgent_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!", adminToken,
+		map[string]any{"name": "new-name"})
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_WithMetadata(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleUpdateAgent_NothingToUpdate(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		map[string]any{})
 	require.NoError(t, err)
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-	require.NoError(t, json.Unmarshal(body, &runResult))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "at least one of name or metadata is required")
+}
 
-	complResp, err := authedRequest("POST",
-		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
-		model.CompleteRunRequest{Status: "completed", Metadata: map[string]any{"result": "success", "count": 42}})
+func TestHandleUpdateAgent_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken,
+		map[string]any{"name": "new-name"})
 	require.NoError(t, err)
-	defer func() { _ = complResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, complResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSetRetention — exclude types, invalid JSON, zero days
+// Coverage push: HandleUpdateAgentTags — missing tags, invalid format
 // ===========================================================================
 
-func TestHandleSetRetention_WithExcludeTypes(t *testing.T) {
-	days := 90
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{
-			"retention_days":          days,
-			"retention_exclude_types": []string{"architecture", "security"},
-		})
+func TestHandleUpdateAgentTags_MissingTagsField(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
+		map[string]any{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			RetentionDays         int      `json:"retention_days"`
-			RetentionExcludeTypes []string `json:"retention_exclude_types"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, days, result.Data.RetentionDays)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "tags field is required")
 }
 
-func TestHandleSetRetention_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("PUT", testSrv.URL+"/v1/retention",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleUpdateAgentTags_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!/tags", adminToken,
+		map[string]any{"tags": []string{"foo"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleSetRetention_ZeroDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{"retention_days": 0})
+func TestHandleUpdateAgentTags_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz/tags", adminToken,
+		map[string]any{"tags": []string{"foo"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleHealth — detailed field verification
+// Coverage push: HandleEraseDecision — legal hold blocks erasure
 // ===========================================================================
 
-func TestHandleHealth_DetailedFields(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/health")
+func TestHandleEraseDecision_BlockedByLegalHold(t *testing.T) {
+	// Create a trace to erase.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "test-agent",
+		"decision": map[string]any{
+			"decision_type": "erasure-hold-test",
+			"outcome":       "test outcome for erasure hold",
+			"confidence":    0.9,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data model.HealthResponse `json:"data"`
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
+	decisionID := traceResult.Data.DecisionID
 
-	assert.Equal(t, "healthy", result.Data.Status)
-	assert.Equal(t, "test", result.Data.Version)
-	assert.Equal(t, "connected", result.Data.Postgres)
-	assert.Equal(t, "ok", result.Data.BufferStatus)
-	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
-	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0)
-	assert.Empty(t, result.Data.Qdrant)
-	assert.Empty(t, result.Data.SSEBroker)
+	// Create a hold covering this decision type and agent.
+	holdResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason":         "litigation hold for erasure test",
+		"from":           time.Now().Add(-time.Hour).Format(time.RFC3339),
+		"to":             time.Now().Add(time.Hour).Format(time.RFC3339),
+		"decision_types": []string{"erasure-hold-test"},
+		"agent_ids":      []string{"test-agent"},
+	})
+	require.NoError(t, err)
+	_ = holdResp.Body.Close()
+	require.Equal(t, http.StatusCreated, holdResp.StatusCode)
+
+	// Attempt erasure — should be blocked.
+	eraseResp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
+		map[string]any{"reason": "test erasure"})
+	require.NoError(t, err)
+	defer func() { _ = eraseResp.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, eraseResp.StatusCode)
+	eraseBody, _ := io.ReadAll(eraseResp.Body)
+	assert.Contains(t, string(eraseBody), "legal hold")
 }
 
 // ===========================================================================
-// Coverage push: HandleRevokeKey — invalid UUID
+// Coverage push: HandleVerifyDecision — invalid UUID
 // ===========================================================================
 
-func TestHandleRevokeKey_InvalidUUID(t *testing.T) {
-	reqDel, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/keys/not-a-uuid", nil)
-	reqDel.Header.Set("Authorization", "Bearer "+adminToken)
-	resp, err := http.DefaultClient.Do(reqDel)
+func TestHandleVerifyDecision_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: verifyAPIKey — various credential formats via ApiKey auth
+// Coverage push: HandleListAssessments — access denied for reader without grant
 // ===========================================================================
 
-func TestAPIKeyAuth_ValidCredential(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleListAssessments_AccessDeniedForReader(t *testing.T) {
+	// Create a reader agent.
+	createAgent(testSrv.URL, adminToken, "reader-assess-test", "Reader Assess Test", "reader", "reader-assess-key")
+	readerToken := getToken(testSrv.URL, "reader-assess-test", "reader-assess-key")
+
+	// Create a trace as admin.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "reader-assess-test",
+			"outcome":       "test",
+			"confidence":    0.5,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &traceResult))
+
+	// Reader tries to list assessments for admin's decision — should be forbidden.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assessments", readerToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestAPIKeyAuth_InvalidFormat_NoColon(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey noColonHere")
-	resp, err := http.DefaultClient.Do(req)
+// ===========================================================================
+// Coverage push: HandleCheck — with project and agent_id filters, query field
+// ===========================================================================
+
+func TestHandleCheck_WithProjectAndAgentFilters(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken, map[string]any{
+		"decision_type": "test",
+		"project":       "test-project",
+		"agent_id":      "test-agent",
+		"limit":         5,
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestAPIKeyAuth_InvalidFormat_EmptyAgentID(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey :some-key")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleCheck_WithQueryField(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken, map[string]any{
+		"decision_type": "test",
+		"query":         "how should we handle caching?",
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestAPIKeyAuth_InvalidFormat_EmptyKey(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey admin:")
-	resp, err := http.DefaultClient.Do(req)
+// ===========================================================================
+// Coverage push: HandleQuery — negative offset clamped
+// ===========================================================================
+
+func TestHandleQuery_NegativeOffset(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken, map[string]any{
+		"offset": -5,
+		"limit":  10,
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "negative offset should be clamped to 0, not rejected")
 }
 
-func TestAPIKeyAuth_WithOrgHeader(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
-	req.Header.Set("X-Akashi-Org-ID", uuid.Nil.String())
-	resp, err := http.DefaultClient.Do(req)
+// ===========================================================================
+// Coverage push: HandleCreateHold — with decision_types and agent_ids
+// ===========================================================================
+
+func TestHandleCreateHold_WithDecisionTypesAndAgentIDs(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
+		"reason":         "scoped hold test",
+		"from":           time.Now().Add(-time.Hour).Format(time.RFC3339),
+		"to":             time.Now().Add(time.Hour).Format(time.RFC3339),
+		"decision_types": []string{"architecture", "security"},
+		"agent_ids":      []string{"test-agent", "admin"},
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			DecisionTypes []string `json:"decision_types"`
+			AgentIDs      []string `json:"agent_ids"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.ElementsMatch(t, []string{"architecture", "security"}, result.Data.DecisionTypes)
+	assert.ElementsMatch(t, []string{"test-agent", "admin"}, result.Data.AgentIDs)
 }
 
-func TestAPIKeyAuth_InvalidOrgHeader(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
-	req.Header.Set("X-Akashi-Org-ID", "not-a-uuid")
-	resp, err := http.DefaultClient.Do(req)
+// ===========================================================================
+// Coverage push: HandleSetRetention — retention_days < 1 rejected
+// ===========================================================================
+
+func TestHandleSetRetention_InvalidRetentionDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days": 0,
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "retention_days must be")
 }
 
-func TestAPIKeyAuth_WrongOrgHeader(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey admin:test-admin-key")
-	req.Header.Set("X-Akashi-Org-ID", uuid.New().String())
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleSetRetention_ValidUpdate(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
+		"retention_days":          90,
+		"retention_exclude_types": []string{"audit"},
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestAuthToken_WrongAPIKey(t *testing.T) {
-	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "admin", APIKey: "definitely-wrong-key"})
-	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+// ===========================================================================
+// Coverage push: HandleGetRetention — wraps retention + holds in one response
+// ===========================================================================
+
+func TestHandleGetRetention_ReturnsDataEnvelope(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var raw map[string]json.RawMessage
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &raw))
+	assert.Contains(t, raw, "data", "response must include data envelope")
 }
 
-func TestAuthToken_NonexistentAgent(t *testing.T) {
-	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "ghost-agent", APIKey: "some-key"})
-	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+// ===========================================================================
+// Coverage push: HandleDecisionConflicts — pagination
+// ===========================================================================
+
+func TestHandleDecisionConflicts_WithPagination(t *testing.T) {
+	// Create a decision to query conflicts for.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "conflict-page-test",
+			"outcome":       "test outcome",
+			"confidence":    0.9,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	tb, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(tb, &traceResult))
+
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/conflicts?limit=5&offset=0",
+		adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRetention — verify full response shape
+// Coverage push: HandleGetRun — reader without grant cannot access
 // ===========================================================================
 
-func TestHandleGetRetention_FullResponseShape(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleGetRun_ReaderCannotAccessWithoutGrant(t *testing.T) {
+	// Create a run as test-agent.
+	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		map[string]any{"agent_id": "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
+	var runResult struct {
 		Data struct {
-			RetentionDays         int    `json:"retention_days"`
-			RetentionExcludeTypes []any  `json:"retention_exclude_types"`
-			LastRun               *any   `json:"last_run"`
-			LastRunDeleted        *any   `json:"last_run_deleted"`
-			NextRun               string `json:"next_run"`
-			Holds                 []any  `json:"holds"`
+			ID uuid.UUID `json:"id"`
 		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.GreaterOrEqual(t, result.Data.RetentionDays, 0)
-	assert.NotNil(t, result.Data.Holds, "holds should be an array, not nil")
+	rb, _ := io.ReadAll(runResp.Body)
+	_ = runResp.Body.Close()
+	require.Equal(t, http.StatusCreated, runResp.StatusCode)
+	require.NoError(t, json.Unmarshal(rb, &runResult))
+
+	// Create a reader agent with no grant.
+	createAgent(testSrv.URL, adminToken, "reader-run-test", "Reader Run Test", "reader", "reader-run-key")
+	readerToken := getToken(testSrv.URL, "reader-run-test", "reader-run-key")
+
+	// Reader tries to access the run — should be denied.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), readerToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: scoped token — delegation chain, role escalation, TTL capping
+// Coverage push: HandleRotateKey — already revoked key returns 409
 // ===========================================================================
 
-func TestHandleScopedToken_ScopedTokenCannotDelegate(t *testing.T) {
-	// First, get a scoped token for test-agent (admin -> test-agent).
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{"as_agent_id": "test-agent"})
+func TestHandleRotateKey_AlreadyRevokedReturns409(t *testing.T) {
+	// Create a key, revoke it, then attempt rotate.
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, map[string]any{
+		"agent_id": "test-agent",
+		"label":    "revoke-test-key",
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
+	var createResult struct {
 		Data struct {
-			Token string `json:"token"`
+			ID uuid.UUID `json:"id"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	scopedToken := result.Data.Token
-	require.NotEmpty(t, scopedToken)
+	cb, _ := io.ReadAll(createResp.Body)
+	_ = createResp.Body.Close()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	require.NoError(t, json.Unmarshal(cb, &createResult))
+	keyID := createResult.Data.ID
 
-	// Now try to use the scoped token to issue another scoped token — should be denied.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", scopedToken,
-		map[string]any{"as_agent_id": "test-agent"})
+	// Revoke it.
+	revokeResp, err := authedRequest("DELETE", testSrv.URL+"/v1/keys/"+keyID.String(), adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp2.StatusCode, "scoped tokens cannot issue further scoped tokens")
-}
+	_ = revokeResp.Body.Close()
+	require.Equal(t, http.StatusNoContent, revokeResp.StatusCode)
 
-func TestHandleScopedToken_CannotImpersonateHigherRole(t *testing.T) {
-	// Admin (rank 3) tries to get a scoped token for org_owner (rank 4) — should be denied.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{"as_agent_id": "test-org-owner"})
+	// Attempt rotation — should fail with 409 Conflict.
+	rotateResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID.String()+"/rotate", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "cannot impersonate agent with higher or equal role")
+	defer func() { _ = rotateResp.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, rotateResp.StatusCode)
+	rb, _ := io.ReadAll(rotateResp.Body)
+	assert.Contains(t, string(rb), "already revoked")
 }
 
-func TestHandleScopedToken_MissingAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+// ===========================================================================
+// Coverage push: HandleCreateAgent — reserved agent_id, role escalation
+// ===========================================================================
 
-func TestHandleScopedToken_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{"as_agent_id": "INVALID AGENT!!!"})
+func TestHandleCreateAgent_ReservedAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
+		"agent_id": "system",
+		"name":     "System",
+		"role":     "agent",
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-func TestHandleScopedToken_OverlyLongTTLIsCapped(t *testing.T) {
-	// Request 48 hours — the handler caps at MaxScopedTokenTTL (1 hour).
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{"as_agent_id": "test-agent", "expires_in": 172800})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Token     string    `json:"token"`
-			ExpiresAt time.Time `json:"expires_at"`
-		} `json:"data"`
-	}
 	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.NotEmpty(t, result.Data.Token)
-	// Expiry should be within ~1 hour (with some tolerance), not 48 hours.
-	assert.True(t, result.Data.ExpiresAt.Before(time.Now().Add(2*time.Hour)),
-		"TTL should be capped at MaxScopedTokenTTL (1 hour)")
+	assert.Contains(t, string(b), "reserved")
 }
 
-func TestHandleScopedToken_ForbiddenForAgentRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", agentToken,
-		map[string]any{"as_agent_id": "test-agent"})
+func TestHandleCreateAgent_CannotCreateHigherRole(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
+		"agent_id": "new-admin-attempt",
+		"name":     "New Admin",
+		"role":     "admin",
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Agent role (rank 2) shouldn't have access to the adminOnly-gated endpoint.
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode,
+		"admin cannot create agent with role equal to own")
 }
 
 // ===========================================================================
-// Coverage push: auth middleware — unsupported scheme, malformed header
+// Coverage push: HandleRetractDecision — invalid UUID
 // ===========================================================================
 
-func TestAuthMiddleware_UnsupportedScheme(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/conflicts", nil)
-	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleRetractDecision_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "unsupported authorization scheme")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestAuthMiddleware_MalformedHeader(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/conflicts", nil)
-	req.Header.Set("Authorization", "BearerTokenWithNoSpace")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleRetractDecision_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "invalid authorization format")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleTrace — agent-only, forbidden for other agent
+// Coverage push: HandleGetDecision — forbidden for reader without grant
 // ===========================================================================
 
-func TestHandleTrace_AgentCannotTraceForOther(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, map[string]any{
+func TestHandleGetDecision_ForbiddenForReader(t *testing.T) {
+	// Create a trace as admin.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
 		"agent_id": "admin",
 		"decision": map[string]any{
-			"decision_type": "test",
-			"outcome":       "should fail",
+			"decision_type": "reader-access-test",
+			"outcome":       "test",
 			"confidence":    0.5,
 		},
 		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode,
-		"agent-role caller should not be able to trace for another agent")
-}
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	tb, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(tb, &traceResult))
 
-// ===========================================================================
-// Coverage push: HandleTemporalQuery — far future rejection
-// ===========================================================================
+	// Create a reader agent without any grant.
+	createAgent(testSrv.URL, adminToken, "reader-getdec-test", "Reader GetDec Test", "reader", "reader-getdec-key")
+	readerToken := getToken(testSrv.URL, "reader-getdec-test", "reader-getdec-key")
 
-func TestHandleTemporalQuery_FarFuture(t *testing.T) {
-	futureTime := time.Now().Add(24 * time.Hour)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken, map[string]any{
-		"as_of": futureTime.Format(time.RFC3339),
-	})
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String(), readerToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "as_of must not be in the future")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSessionView — invalid UUID
+// Coverage push: HandleDecisionRevisions — invalid UUID
 // ===========================================================================
 
-func TestHandleSessionView_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/not-a-uuid", adminToken, nil)
+func TestHandleDecisionRevisions_InvalidDecisionUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "invalid session_id")
 }
 
 // ===========================================================================
-// Coverage push: HandleDeleteAgent — legal hold blocking, disabled flag
-// ===========================================================================
-
-func TestHandleDeleteAgent_BlockedByLegalHold(t *testing.T) {
-	// Create a temporary agent to attempt deletion on.
-	createAgent(testSrv.URL, adminToken, "hold-victim", "Hold Victim", "agent", "hold-victim-key")
-
-	// Create a trace for this agent so the hold has something to cover.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "hold-victim",
-		"decision": map[string]any{
-			"decision_type": "test",
-			"outcome":       "test outcome for hold",
-			"confidence":    0.9,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	_ = traceResp.Body.Close()
-
-	// Create a legal hold covering this agent.
-	holdResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason":    "litigation hold for test",
-		"from":      time.Now().Add(-time.Hour).Format(time.RFC3339),
-		"to":        time.Now().Add(time.Hour).Format(time.RFC3339),
-		"agent_ids": []string{"hold-victim"},
-	})
-	require.NoError(t, err)
-	defer func() { _ = holdResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, holdResp.StatusCode)
-
-	// Now try to delete — should be blocked by legal hold.
-	deleteResp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/hold-victim", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = deleteResp.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, deleteResp.StatusCode)
-	b, _ := io.ReadAll(deleteResp.Body)
-	assert.Contains(t, string(b), "legal hold")
-}
+// Coverage push: HandlePatchConflict — invalid UUID, invalid status, winning_decision_id guard
+// ===========================================================================
 
-func TestHandleDeleteAgent_CannotDeleteSeedAdmin(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/admin", adminToken, nil)
+func TestHandlePatchConflict_BadUUID(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken,
+		map[string]any{"status": "resolved"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "cannot delete the admin agent")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleDeleteAgent_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/INVALID AGENT!!!", adminToken, nil)
+func TestHandlePatchConflict_BadStatus(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
+		map[string]any{"status": "invalid_status"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleUpdateAgent — invalid agent_id format, not found
-// ===========================================================================
-
-func TestHandleUpdateAgent_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!", adminToken,
-		map[string]any{"name": "new-name"})
+func TestHandlePatchConflict_WinnerOnNonResolvedStatus(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
+		map[string]any{"status": "false_positive", "winning_decision_id": uuid.New().String()})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "winning_decision_id can only be set when status is")
 }
 
-func TestHandleUpdateAgent_NothingToUpdate(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		map[string]any{})
+// ===========================================================================
+// Coverage push: HandleAdjudicateConflict — invalid UUID, missing outcome
+// ===========================================================================
+
+func TestHandleAdjudicateConflict_BadUUID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate", adminToken,
+		map[string]any{"outcome": "test"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "at least one of name or metadata is required")
 }
 
-func TestHandleUpdateAgent_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz", adminToken,
-		map[string]any{"name": "new-name"})
+func TestHandleAdjudicateConflict_NonexistentConflict(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
+		map[string]any{"outcome": "test"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleUpdateAgentTags — missing tags, invalid format
+// Coverage push: HandleResolveConflictGroup — invalid UUID, invalid status, winning_agent guard
 // ===========================================================================
 
-func TestHandleUpdateAgentTags_MissingTagsField(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
-		map[string]any{})
+func TestHandleResolveConflictGroup_BadUUID(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve", adminToken,
+		map[string]any{"status": "resolved"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "tags field is required")
 }
 
-func TestHandleUpdateAgentTags_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!/tags", adminToken,
-		map[string]any{"tags": []string{"foo"}})
+func TestHandleResolveConflictGroup_AcknowledgedNotAllowed(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
+		map[string]any{"status": "acknowledged"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "status must be one of: resolved, false_positive")
 }
 
-func TestHandleUpdateAgentTags_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent-agent-xyz/tags", adminToken,
-		map[string]any{"tags": []string{"foo"}})
+func TestHandleResolveConflictGroup_WinningAgentOnFalsePositive(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
+		map[string]any{"status": "false_positive", "winning_agent": "admin"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "winning_agent can only be set when status is")
 }
 
 // ===========================================================================
-// Coverage push: HandleEraseDecision — legal hold blocks erasure
+// Coverage push: HandleCreateKey — with valid expires_at
 // ===========================================================================
 
-func TestHandleEraseDecision_BlockedByLegalHold(t *testing.T) {
-	// Create a trace to erase.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "test-agent",
-		"decision": map[string]any{
-			"decision_type": "erasure-hold-test",
-			"outcome":       "test outcome for erasure hold",
-			"confidence":    0.9,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var traceResult struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
-	decisionID := traceResult.Data.DecisionID
-
-	// Create a hold covering this decision type and agent.
-	holdResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason":         "litigation hold for erasure test",
-		"from":           time.Now().Add(-time.Hour).Format(time.RFC3339),
-		"to":             time.Now().Add(time.Hour).Format(time.RFC3339),
-		"decision_types": []string{"erasure-hold-test"},
-		"agent_ids":      []string{"test-agent"},
+func TestHandleCreateKey_ValidExpiresAt(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, map[string]any{
+		"agent_id":   "test-agent",
+		"label":      "expiring-key-cov",
+		"expires_at": expiresAt,
 	})
 	require.NoError(t, err)
-	_ = holdResp.Body.Close()
-	require.Equal(t, http.StatusCreated, holdResp.StatusCode)
-
-	// Attempt erasure — should be blocked.
-	eraseResp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/erase", orgOwnerToken,
-		map[string]any{"reason": "test erasure"})
-	require.NoError(t, err)
-	defer func() { _ = eraseResp.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, eraseResp.StatusCode)
-	eraseBody, _ := io.ReadAll(eraseResp.Body)
-	assert.Contains(t, string(eraseBody), "legal hold")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleVerifyDecision — invalid UUID
+// Coverage push: HandleConflictAnalytics — from after to
 // ===========================================================================
 
-func TestHandleVerifyDecision_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/not-a-uuid", adminToken, nil)
+func TestHandleConflictAnalytics_FromAfterToRange(t *testing.T) {
+	from := time.Now().Format(time.RFC3339)
+	to := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(b), "from must be before to")
 }
 
 // ===========================================================================
-// Coverage push: HandleListAssessments — access denied for reader without grant
+// Coverage push: HandleGetUsage — with explicit period
 // ===========================================================================
 
-func TestHandleListAssessments_AccessDeniedForReader(t *testing.T) {
-	// Create a reader agent.
-	createAgent(testSrv.URL, adminToken, "reader-assess-test", "Reader Assess Test", "reader", "reader-assess-key")
-	readerToken := getToken(testSrv.URL, "reader-assess-test", "reader-assess-key")
-
-	// Create a trace as admin.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "reader-assess-test",
-			"outcome":       "test",
-			"confidence":    0.5,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleGetUsage_SpecificPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
 	require.NoError(t, err)
-	var traceResult struct {
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
 		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
+			Period         string `json:"period"`
+			TotalDecisions int    `json:"total_decisions"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &traceResult))
-
-	// Reader tries to list assessments for admin's decision — should be forbidden.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/assessments", readerToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "2025-01", result.Data.Period)
 }
 
 // ===========================================================================
-// Coverage push: HandleCheck — with project and agent_id filters, query field
+// Coverage push: HandleAgentHistory — invalid time formats
 // ===========================================================================
 
-func TestHandleCheck_WithProjectAndAgentFilters(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken, map[string]any{
-		"decision_type": "test",
-		"project":       "test-project",
-		"agent_id":      "test-agent",
-		"limit":         5,
-	})
+func TestHandleAgentHistory_BadFromTime(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history?from=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCheck_WithQueryField(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken, map[string]any{
-		"decision_type": "test",
-		"query":         "how should we handle caching?",
-	})
+func TestHandleAgentHistory_BadToTime(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history?to=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleQuery — negative offset clamped
+// Coverage push: buildTraceAgentContext — with User-Agent header
 // ===========================================================================
 
-func TestHandleQuery_NegativeOffset(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", adminToken, map[string]any{
-		"offset": -5,
-		"limit":  10,
+func TestHandleTrace_WithAkashiUserAgent(t *testing.T) {
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "ua-test",
+			"outcome":       "test outcome with UA",
+			"confidence":    0.8,
+		},
+		"context": map[string]any{
+			"custom_key": "custom_value",
+			"project":    "test-project",
+		},
+	}, map[string]string{
+		"User-Agent": "akashi-go/0.1.0",
 	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode, "negative offset should be clamped to 0, not rejected")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateHold — with decision_types and agent_ids
+// Coverage push: HandleTrace — admin tracing for another agent (as_agent_id flow)
 // ===========================================================================
 
-func TestHandleCreateHold_WithDecisionTypesAndAgentIDs(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken, map[string]any{
-		"reason":         "scoped hold test",
-		"from":           time.Now().Add(-time.Hour).Format(time.RFC3339),
-		"to":             time.Now().Add(time.Hour).Format(time.RFC3339),
-		"decision_types": []string{"architecture", "security"},
-		"agent_ids":      []string{"test-agent", "admin"},
+func TestHandleTrace_AdminTracesForAnotherAgent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "test-agent",
+		"decision": map[string]any{
+			"decision_type": "admin-proxy-test",
+			"outcome":       "admin tracing for test-agent",
+			"confidence":    0.7,
+		},
+		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			DecisionTypes []string `json:"decision_types"`
-			AgentIDs      []string `json:"agent_ids"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.ElementsMatch(t, []string{"architecture", "security"}, result.Data.DecisionTypes)
-	assert.ElementsMatch(t, []string{"test-agent", "admin"}, result.Data.AgentIDs)
 }
 
 // ===========================================================================
-// Coverage push: HandleSetRetention — retention_days < 1 rejected
+// Coverage push: HandleTrace — with precedent_ref and trace_id
 // ===========================================================================
 
-func TestHandleSetRetention_InvalidRetentionDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days": 0,
+func TestHandleTrace_WithPrecedentRefAndTraceID(t *testing.T) {
+	// First create a decision to reference.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "precedent-test",
+			"outcome":       "original decision",
+			"confidence":    0.9,
+		},
+		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "retention_days must be")
-}
+	var result struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &result))
 
-func TestHandleSetRetention_ValidUpdate(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken, map[string]any{
-		"retention_days":          90,
-		"retention_exclude_types": []string{"audit"},
+	// Now trace referencing it.
+	traceID := uuid.New().String()
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id":      "admin",
+		"trace_id":      traceID,
+		"precedent_ref": result.Data.DecisionID.String(),
+		"decision": map[string]any{
+			"decision_type": "precedent-test",
+			"outcome":       "follows original",
+			"confidence":    0.8,
+			"reasoning":     "Building on prior decision",
+		},
+		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRetention — wraps retention + holds in one response
+// Coverage push: HandleTrace — with session header
 // ===========================================================================
 
-func TestHandleGetRetention_ReturnsDataEnvelope(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleTrace_WithSessionHeaderAndContext(t *testing.T) {
+	sessionID := uuid.New().String()
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "session-cov-test",
+			"outcome":       "test with session",
+			"confidence":    0.6,
+		},
+		"context": map[string]any{
+			"workspace": "/tmp/test",
+			"project":   "test-project",
+		},
+	}, map[string]string{
+		"X-Akashi-Session": sessionID,
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var raw map[string]json.RawMessage
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &raw))
-	assert.Contains(t, raw, "data", "response must include data envelope")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionConflicts — pagination
+// Coverage push: HandleVerifyDecision — verified (with content hash)
 // ===========================================================================
 
-func TestHandleDecisionConflicts_WithPagination(t *testing.T) {
-	// Create a decision to query conflicts for.
+func TestHandleVerifyDecision_VerifiedDecision(t *testing.T) {
+	// Create a trace, then verify it.
 	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
 		"agent_id": "admin",
 		"decision": map[string]any{
-			"decision_type": "conflict-page-test",
-			"outcome":       "test outcome",
-			"confidence":    0.9,
+			"decision_type": "verify-test",
+			"outcome":       "decision to verify",
+			"confidence":    0.95,
+			"reasoning":     "test reasoning for verification",
 		},
 		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
-	var traceResult struct {
+	var result struct {
 		Data struct {
 			DecisionID uuid.UUID `json:"decision_id"`
 		} `json:"data"`
 	}
-	tb, _ := io.ReadAll(traceResp.Body)
+	b, _ := io.ReadAll(traceResp.Body)
 	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(tb, &traceResult))
+	require.NoError(t, json.Unmarshal(b, &result))
 
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String()+"/conflicts?limit=5&offset=0",
-		adminToken, nil)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+result.Data.DecisionID.String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
-
-// ===========================================================================
-// Coverage push: HandleGetRun — reader without grant cannot access
-// ===========================================================================
 
-func TestHandleGetRun_ReaderCannotAccessWithoutGrant(t *testing.T) {
-	// Create a run as test-agent.
-	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		map[string]any{"agent_id": "test-agent"})
-	require.NoError(t, err)
-	var runResult struct {
+	var verifyResult struct {
 		Data struct {
-			ID uuid.UUID `json:"id"`
+			Status string `json:"status"`
+			Valid  bool   `json:"valid"`
 		} `json:"data"`
 	}
-	rb, _ := io.ReadAll(runResp.Body)
-	_ = runResp.Body.Close()
-	require.Equal(t, http.StatusCreated, runResp.StatusCode)
-	require.NoError(t, json.Unmarshal(rb, &runResult))
-
-	// Create a reader agent with no grant.
-	createAgent(testSrv.URL, adminToken, "reader-run-test", "Reader Run Test", "reader", "reader-run-key")
-	readerToken := getToken(testSrv.URL, "reader-run-test", "reader-run-key")
-
-	// Reader tries to access the run — should be denied.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), readerToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	vb, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(vb, &verifyResult))
+	assert.Equal(t, "verified", verifyResult.Data.Status)
+	assert.True(t, verifyResult.Data.Valid)
 }
 
 // ===========================================================================
-// Coverage push: HandleRotateKey — already revoked key returns 409
+// Coverage push: HandleVerifyDecision — retracted decision
 // ===========================================================================
 
-func TestHandleRotateKey_AlreadyRevokedReturns409(t *testing.T) {
-	// Create a key, revoke it, then attempt rotate.
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, map[string]any{
-		"agent_id": "test-agent",
-		"label":    "revoke-test-key",
+func TestHandleVerifyDecision_RetractedDecision(t *testing.T) {
+	// Create, retract, then verify.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "retract-verify-test",
+			"outcome":       "will be retracted",
+			"confidence":    0.8,
+		},
+		"context": map[string]any{"project": "test-project"},
 	})
 	require.NoError(t, err)
-	var createResult struct {
+	var result struct {
 		Data struct {
-			ID uuid.UUID `json:"id"`
+			DecisionID uuid.UUID `json:"decision_id"`
 		} `json:"data"`
 	}
-	cb, _ := io.ReadAll(createResp.Body)
-	_ = createResp.Body.Close()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
-	require.NoError(t, json.Unmarshal(cb, &createResult))
-	keyID := createResult.Data.ID
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &result))
 
-	// Revoke it.
-	revokeResp, err := authedRequest("DELETE", testSrv.URL+"/v1/keys/"+keyID.String(), adminToken, nil)
+	// Retract it.
+	retractResp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+result.Data.DecisionID.String(), adminToken,
+		map[string]any{"reason": "testing verification of retracted"})
 	require.NoError(t, err)
-	_ = revokeResp.Body.Close()
-	require.Equal(t, http.StatusNoContent, revokeResp.StatusCode)
+	_ = retractResp.Body.Close()
 
-	// Attempt rotation — should fail with 409 Conflict.
-	rotateResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID.String()+"/rotate", adminToken, nil)
+	// Verify.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+result.Data.DecisionID.String(), adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = rotateResp.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, rotateResp.StatusCode)
-	rb, _ := io.ReadAll(rotateResp.Body)
-	assert.Contains(t, string(rb), "already revoked")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var verifyResult struct {
+		Data struct {
+			Status string `json:"status"`
+		} `json:"data"`
+	}
+	vb, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(vb, &verifyResult))
+	assert.Equal(t, "retracted", verifyResult.Data.Status)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateAgent — reserved agent_id, role escalation
+// Coverage push: HandleSessionView — with multiple decisions from same session
 // ===========================================================================
 
-func TestHandleCreateAgent_ReservedAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
-		"agent_id": "system",
-		"name":     "System",
-		"role":     "agent",
-	})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "reserved")
-}
+func TestHandleSessionView_WithMultipleDecisionsSameSession(t *testing.T) {
+	sessionID := uuid.New().String()
 
-func TestHandleCreateAgent_CannotCreateHigherRole(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
-		"agent_id": "new-admin-attempt",
-		"name":     "New Admin",
-		"role":     "admin",
-	})
+	// Create two traces in the same session.
+	for i := 0; i < 2; i++ {
+		resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+			"agent_id": "admin",
+			"decision": map[string]any{
+				"decision_type": "session-multi-test",
+				"outcome":       fmt.Sprintf("decision %d in session", i+1),
+				"confidence":    0.7,
+			},
+			"context": map[string]any{"project": "test-project"},
+		}, map[string]string{
+			"X-Akashi-Session": sessionID,
+		})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	// Query session view.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+sessionID, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode,
-		"admin cannot create agent with role equal to own")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			DecisionCount int `json:"decision_count"`
+			Summary       struct {
+				DurationSecs  float64 `json:"duration_secs"`
+				AvgConfidence float64 `json:"avg_confidence"`
+			} `json:"summary"`
+		} `json:"data"`
+	}
+	rb, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(rb, &result))
+	assert.Equal(t, 2, result.Data.DecisionCount, "should have 2 decisions in session")
+	assert.InDelta(t, 0.7, result.Data.Summary.AvgConfidence, 0.01, "avg confidence should be ~0.7")
 }
 
 // ===========================================================================
-// Coverage push: HandleRetractDecision — invalid UUID
+// Coverage push: HandleGetRun — includes events and decisions
 // ===========================================================================
 
-func TestHandleRetractDecision_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
+func TestHandleGetRun_IncludesEventsAndDecisions(t *testing.T) {
+	// Create a run.
+	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		map[string]any{"agent_id": "test-agent"})
+	require.NoError(t, err)
+	var runResult struct {
+		Data struct {
+			ID uuid.UUID `json:"id"`
+		} `json:"data"`
+	}
+	rb, _ := io.ReadAll(runResp.Body)
+	_ = runResp.Body.Close()
+	require.Equal(t, http.StatusCreated, runResp.StatusCode)
+	require.NoError(t, json.Unmarshal(rb, &runResult))
+
+	// Append events.
+	eventsResp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
+		model.AppendEventsRequest{
+			Events: []model.EventInput{
+				{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved"}},
+			},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	_ = eventsResp.Body.Close()
 
-func TestHandleRetractDecision_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
+	// Get the run — verify it returns run, events, and decisions arrays.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var raw map[string]json.RawMessage
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &raw))
+	assert.Contains(t, raw, "data")
 }
 
 // ===========================================================================
-// Coverage push: HandleGetDecision — forbidden for reader without grant
+// Coverage push: HandleCompleteRun — with metadata and "failed" status
 // ===========================================================================
 
-func TestHandleGetDecision_ForbiddenForReader(t *testing.T) {
-	// Create a trace as admin.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "reader-access-test",
-			"outcome":       "test",
-			"confidence":    0.5,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleCompleteRun_FailedStatus(t *testing.T) {
+	// Create a run.
+	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		map[string]any{"agent_id": "test-agent"})
 	require.NoError(t, err)
-	var traceResult struct {
+	var runResult struct {
 		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
+			ID uuid.UUID `json:"id"`
 		} `json:"data"`
 	}
-	tb, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(tb, &traceResult))
-
-	// Create a reader agent without any grant.
-	createAgent(testSrv.URL, adminToken, "reader-getdec-test", "Reader GetDec Test", "reader", "reader-getdec-key")
-	readerToken := getToken(testSrv.URL, "reader-getdec-test", "reader-getdec-key")
+	rb, _ := io.ReadAll(runResp.Body)
+	_ = runResp.Body.Close()
+	require.Equal(t, http.StatusCreated, runResp.StatusCode)
+	require.NoError(t, json.Unmarshal(rb, &runResult))
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResult.Data.DecisionID.String(), readerToken, nil)
+	// Complete with failed status.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
+		map[string]any{
+			"status":   "failed",
+			"metadata": map[string]any{"error": "out of memory"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionRevisions — invalid UUID
+// Coverage push: HandleHealth — full response shape with buffer
 // ===========================================================================
 
-func TestHandleDecisionRevisions_InvalidDecisionUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", adminToken, nil)
+func TestHandleHealth_FullResponseShape(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/health")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			Status       string `json:"status"`
+			Version      string `json:"version"`
+			Postgres     string `json:"postgres"`
+			BufferDepth  int    `json:"buffer_depth"`
+			BufferStatus string `json:"buffer_status"`
+			Uptime       int64  `json:"uptime"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "healthy", result.Data.Status)
+	assert.Equal(t, "connected", result.Data.Postgres)
+	assert.Equal(t, "test", result.Data.Version)
+	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
+	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0)
+	assert.Equal(t, "ok", result.Data.BufferStatus)
 }
 
 // ===========================================================================
-// Coverage push: HandlePatchConflict — invalid UUID, invalid status, winning_decision_id guard
+// Coverage push: HandleMCPInfo — response shape
 // ===========================================================================
 
-func TestHandlePatchConflict_BadUUID(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", adminToken,
-		map[string]any{"status": "resolved"})
+func TestHandleMCPInfo_ResponseShape(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-func TestHandlePatchConflict_BadStatus(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
-		map[string]any{"status": "invalid_status"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var result struct {
+		Data struct {
+			Version   string `json:"version"`
+			Transport string `json:"transport"`
+			Auth      struct {
+				Schemes   []string `json:"schemes"`
+				Preferred string   `json:"preferred"`
+			} `json:"auth"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "test", result.Data.Version)
+	assert.Equal(t, "streamable-http", result.Data.Transport)
+	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
+	assert.Contains(t, result.Data.Auth.Schemes, "Bearer")
 }
 
-func TestHandlePatchConflict_WinnerOnNonResolvedStatus(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken,
-		map[string]any{"status": "false_positive", "winning_decision_id": uuid.New().String()})
+// ===========================================================================
+// Coverage push: HandleConfig — response shape
+// ===========================================================================
+
+func TestHandleConfig_ResponseShape(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/config")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			SearchEnabled bool `json:"search_enabled"`
+		} `json:"data"`
+	}
 	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "winning_decision_id can only be set when status is")
+	require.NoError(t, json.Unmarshal(b, &result))
+	// With noop embedder, search should not be available.
+	assert.False(t, result.Data.SearchEnabled)
 }
 
 // ===========================================================================
-// Coverage push: HandleAdjudicateConflict — invalid UUID, missing outcome
+// Coverage push: HandleDecisionsRecent — with agent_id and decision_type filters
 // ===========================================================================
 
-func TestHandleAdjudicateConflict_BadUUID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate", adminToken,
-		map[string]any{"outcome": "test"})
+func TestHandleDecisionsRecent_WithFilters(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?agent_id=admin&decision_type=test&limit=5", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAdjudicateConflict_NonexistentConflict(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.New().String()+"/adjudicate", adminToken,
-		map[string]any{"outcome": "test"})
+// ===========================================================================
+// Coverage push: HandleExportDecisions — with from+to time range
+// ===========================================================================
+
+func TestHandleExportDecisions_WithTimeRange(t *testing.T) {
+	from := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
+	to := time.Now().Add(time.Hour).Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
 }
 
 // ===========================================================================
-// Coverage push: HandleResolveConflictGroup — invalid UUID, invalid status, winning_agent guard
+// Coverage push: HandleTraceHealth
 // ===========================================================================
 
-func TestHandleResolveConflictGroup_BadUUID(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve", adminToken,
-		map[string]any{"status": "resolved"})
+func TestHandleTraceHealth_ReturnsMetrics(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleResolveConflictGroup_AcknowledgedNotAllowed(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
-		map[string]any{"status": "acknowledged"})
+// ===========================================================================
+// Coverage push: HandleRetractDecision — with reason body
+// ===========================================================================
+
+func TestHandleRetractDecision_WithReason(t *testing.T) {
+	// Create a decision.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "retract-reason-test",
+			"outcome":       "will retract with reason",
+			"confidence":    0.8,
+		},
+		"context": map[string]any{"project": "test-project"},
+	})
+	require.NoError(t, err)
+	var result struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(b, &result))
+
+	// Retract with reason.
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+result.Data.DecisionID.String(), adminToken,
+		map[string]any{"reason": "no longer valid"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "status must be one of: resolved, false_positive")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleResolveConflictGroup_WinningAgentOnFalsePositive(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+uuid.New().String()+"/resolve", adminToken,
-		map[string]any{"status": "false_positive", "winning_agent": "admin"})
+// ===========================================================================
+// Coverage push: HandleSearch — with semantic flag
+// ===========================================================================
+
+func TestHandleSearch_WithSemanticFlag(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken, map[string]any{
+		"query":    "caching strategy",
+		"semantic": true,
+		"limit":    10,
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "winning_agent can only be set when status is")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	// With noop embedder, backend should be "text".
+	assert.Equal(t, "text", resp.Header.Get("X-Search-Backend"))
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateKey — with valid expires_at
+// Coverage push: HandleListGrants
 // ===========================================================================
 
-func TestHandleCreateKey_ValidExpiresAt(t *testing.T) {
-	expiresAt := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken, map[string]any{
-		"agent_id":   "test-agent",
-		"label":      "expiring-key-cov",
-		"expires_at": expiresAt,
-	})
+func TestHandleListGrants_AdminAccess(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?limit=10&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleConflictAnalytics — from after to
+// Coverage push: HandleCreateAgent — with tags and metadata
 // ===========================================================================
 
-func TestHandleConflictAnalytics_FromAfterToRange(t *testing.T) {
-	from := time.Now().Format(time.RFC3339)
-	to := time.Now().Add(-time.Hour).Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+func TestHandleCreateAgent_WithTagsAndMetadata(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
+		"agent_id": "tagged-agent-cov",
+		"name":     "Tagged Agent",
+		"role":     "agent",
+		"tags":     []string{"backend", "python"},
+		"metadata": map[string]any{"team": "infra"},
+	})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-	b, _ := io.ReadAll(resp.Body)
-	assert.Contains(t, string(b), "from must be before to")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetUsage — with explicit period
+// Coverage push: HandleGetAgent — valid agent
 // ===========================================================================
 
-func TestHandleGetUsage_SpecificPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
+func TestHandleGetAgent_ValidAgent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
 		Data struct {
-			Period         string `json:"period"`
-			TotalDecisions int    `json:"total_decisions"`
+			AgentID string `json:"agent_id"`
+			Name    string `json:"name"`
 		} `json:"data"`
 	}
 	b, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "2025-01", result.Data.Period)
+	assert.Equal(t, "test-agent", result.Data.AgentID)
 }
 
 // ===========================================================================
-// Coverage push: HandleAgentHistory — invalid time formats
+// Coverage push: HandleUpdateAgent — valid update with metadata
 // ===========================================================================
 
-func TestHandleAgentHistory_BadFromTime(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history?from=not-a-date", adminToken, nil)
+func TestHandleUpdateAgent_ValidMetadataUpdate(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		map[string]any{"metadata": map[string]any{"updated": true}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAgentHistory_BadToTime(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history?to=not-a-date", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleTrace — deeper branch coverage
+// ===========================================================================
+
+func TestHandleTrace_ConfidenceAboveOne(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "use redis",
+				"confidence":    1.5, // > 1 is invalid
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: buildTraceAgentContext — with User-Agent header
-// ===========================================================================
+func TestHandleTrace_ConfidenceBelowZero(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "use redis",
+				"confidence":    -0.1,
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleTrace_WithAkashiUserAgent(t *testing.T) {
-	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "ua-test",
-			"outcome":       "test outcome with UA",
-			"confidence":    0.8,
-		},
-		"context": map[string]any{
-			"custom_key": "custom_value",
-			"project":    "test-project",
-		},
-	}, map[string]string{
-		"User-Agent": "akashi-go/0.1.0",
-	})
+func TestHandleTrace_AgentCantTraceForOther(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "admin", // agent role can't trace for another agent
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "use redis",
+				"confidence":    0.8,
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleTrace — admin tracing for another agent (as_agent_id flow)
-// ===========================================================================
+func TestHandleTrace_BadAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		map[string]any{
+			"agent_id": "INVALID AGENT!!!",
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "use redis",
+				"confidence":    0.8,
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleTrace_AdminTracesForAnotherAgent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "test-agent",
-		"decision": map[string]any{
-			"decision_type": "admin-proxy-test",
-			"outcome":       "admin tracing for test-agent",
-			"confidence":    0.7,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleTrace_SessionHeaderUUID(t *testing.T) {
+	sessionID := uuid.NewString()
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "traced with session",
+				"confidence":    0.7,
+			},
+			"context": map[string]any{"project": "test-project"},
+		}, map[string]string{
+			"X-Akashi-Session": sessionID,
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleTrace — with precedent_ref and trace_id
-// ===========================================================================
-
-func TestHandleTrace_WithPrecedentRefAndTraceID(t *testing.T) {
-	// First create a decision to reference.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
+func TestHandleTrace_WithIdempotencyKey(t *testing.T) {
+	idemKey := "trace-idem-" + uuid.NewString()
+	traceReq := map[string]any{
+		"agent_id": "test-agent",
 		"decision": map[string]any{
-			"decision_type": "precedent-test",
-			"outcome":       "original decision",
+			"decision_type": "implementation",
+			"outcome":       "idempotent trace",
 			"confidence":    0.9,
 		},
 		"context": map[string]any{"project": "test-project"},
-	})
+	}
+
+	// First call.
+	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		traceReq, map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	var result struct {
+	defer func() { _ = resp1.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp1.StatusCode)
+	body1, _ := io.ReadAll(resp1.Body)
+
+	// Replay with same key — should return same response.
+	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		traceReq, map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	body2, _ := io.ReadAll(resp2.Body)
+
+	// Both should succeed and return same decision_id.
+	var r1, r2 struct {
 		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
+			DecisionID string `json:"decision_id"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &result))
+	require.NoError(t, json.Unmarshal(body1, &r1))
+	require.NoError(t, json.Unmarshal(body2, &r2))
+	assert.Equal(t, r1.Data.DecisionID, r2.Data.DecisionID,
+		"replayed idempotent trace should return same decision_id")
+}
 
-	// Now trace referencing it.
-	traceID := uuid.New().String()
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id":      "admin",
-		"trace_id":      traceID,
-		"precedent_ref": result.Data.DecisionID.String(),
-		"decision": map[string]any{
-			"decision_type": "precedent-test",
-			"outcome":       "follows original",
-			"confidence":    0.8,
-			"reasoning":     "Building on prior decision",
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleTrace_IdempotencyPayloadMismatchCov(t *testing.T) {
+	idemKey := "trace-idem-mismatch-cov-" + uuid.NewString()
+
+	// First call.
+	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "first payload",
+				"confidence":    0.9,
+			},
+			"context": map[string]any{"project": "test-project"},
+		}, map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
-}
+	defer func() { _ = resp1.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp1.StatusCode)
+	_, _ = io.ReadAll(resp1.Body)
 
-// ===========================================================================
-// Coverage push: HandleTrace — with session header
-// ===========================================================================
+	// Second call with different payload — should get 409.
+	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "different payload",
+				"confidence":    0.5,
+			},
+			"context": map[string]any{"project": "test-project"},
+		}, map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
+}
 
-func TestHandleTrace_WithSessionHeaderAndContext(t *testing.T) {
-	sessionID := uuid.New().String()
-	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "session-cov-test",
-			"outcome":       "test with session",
-			"confidence":    0.6,
-		},
-		"context": map[string]any{
-			"workspace": "/tmp/test",
-			"project":   "test-project",
-		},
-	}, map[string]string{
-		"X-Akashi-Session": sessionID,
-	})
+func TestHandleTrace_WithContext(t *testing.T) {
+	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "traced with context",
+				"confidence":    0.7,
+			},
+			"context": map[string]any{
+				"project": "test-project",
+				"tool":    "claude-code",
+			},
+		}, map[string]string{
+			"User-Agent": "akashi-go/0.1.0",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
+func TestHandleTrace_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/trace",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 // ===========================================================================
-// Coverage push: HandleVerifyDecision — verified (with content hash)
+// Coverage push: HandleCreateGrant — deeper branch coverage (55.3% → higher)
 // ===========================================================================
 
-func TestHandleVerifyDecision_VerifiedDecision(t *testing.T) {
-	// Create a trace, then verify it.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "verify-test",
-			"outcome":       "decision to verify",
-			"confidence":    0.95,
-			"reasoning":     "test reasoning for verification",
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleCreateGrant_AgentRoleNonTraceResource(t *testing.T) {
+	// Agent role trying to grant non-agent_traces resource type — should fail.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
+		map[string]any{
+			"grantee_agent_id": "admin",
+			"resource_type":    "invalid_type",
+			"permission":       "read",
+		})
 	require.NoError(t, err)
-	var result struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &result))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+result.Data.DecisionID.String(), adminToken, nil)
+func TestHandleCreateGrant_AgentRoleGrantOtherTracesNoResourceID(t *testing.T) {
+	// Agent trying to grant agent_traces but with nil resource_id — must fail
+	// because agents can only grant their own traces.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
+		map[string]any{
+			"grantee_agent_id": "admin",
+			"resource_type":    "agent_traces",
+			"permission":       "read",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var verifyResult struct {
-		Data struct {
-			Status string `json:"status"`
-			Valid  bool   `json:"valid"`
-		} `json:"data"`
-	}
-	vb, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(vb, &verifyResult))
-	assert.Equal(t, "verified", verifyResult.Data.Status)
-	assert.True(t, verifyResult.Data.Valid)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleVerifyDecision — retracted decision
+// Coverage push: HandleExportDecisions — additional filter branches (56.2% → higher)
 // ===========================================================================
 
-func TestHandleVerifyDecision_RetractedDecision(t *testing.T) {
-	// Create, retract, then verify.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "retract-verify-test",
-			"outcome":       "will be retracted",
-			"confidence":    0.8,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
-	require.NoError(t, err)
-	var result struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &result))
-
-	// Retract it.
-	retractResp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+result.Data.DecisionID.String(), adminToken,
-		map[string]any{"reason": "testing verification of retracted"})
-	require.NoError(t, err)
-	_ = retractResp.Body.Close()
-
-	// Verify.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+result.Data.DecisionID.String(), adminToken, nil)
+func TestHandleExportDecisions_WithToFilterOnly(t *testing.T) {
+	// Exercise the "to without from" path (filters.TimeRange == nil check).
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/export/decisions?to="+time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339),
+		adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+}
 
-	var verifyResult struct {
-		Data struct {
-			Status string `json:"status"`
-		} `json:"data"`
-	}
-	vb, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(vb, &verifyResult))
-	assert.Equal(t, "retracted", verifyResult.Data.Status)
+func TestHandleExportDecisions_ForbiddenForAgentWithDetail(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSessionView — with multiple decisions from same session
+// Coverage push: HandleGetRun — branches with invalid run ID format
 // ===========================================================================
 
-func TestHandleSessionView_WithMultipleDecisionsSameSession(t *testing.T) {
-	sessionID := uuid.New().String()
+func TestHandleGetRun_ValidRunWithDetails(t *testing.T) {
+	// Create a run, append events, trace a decision, then get run details.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	// Create two traces in the same session.
-	for i := 0; i < 2; i++ {
-		resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-			"agent_id": "admin",
-			"decision": map[string]any{
-				"decision_type": "session-multi-test",
-				"outcome":       fmt.Sprintf("decision %d in session", i+1),
-				"confidence":    0.7,
-			},
-			"context": map[string]any{"project": "test-project"},
-		}, map[string]string{
-			"X-Akashi-Session": sessionID,
-		})
-		require.NoError(t, err)
-		_ = resp.Body.Close()
-		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-	// Query session view.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+sessionID, adminToken, nil)
+	// Get the run as the owning agent — exercises the canAccessAgent path.
+	resp2, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runID.String(), agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
 
-	var result struct {
+	var getResult struct {
 		Data struct {
-			DecisionCount int `json:"decision_count"`
-			Summary       struct {
-				DurationSecs  float64 `json:"duration_secs"`
-				AvgConfidence float64 `json:"avg_confidence"`
-			} `json:"summary"`
+			Run       model.AgentRun  `json:"run"`
+			Events    json.RawMessage `json:"events"`
+			Decisions json.RawMessage `json:"decisions"`
 		} `json:"data"`
 	}
-	rb, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(rb, &result))
-	assert.Equal(t, 2, result.Data.DecisionCount, "should have 2 decisions in session")
-	assert.InDelta(t, 0.7, result.Data.Summary.AvgConfidence, 0.01, "avg confidence should be ~0.7")
+	body2, _ := io.ReadAll(resp2.Body)
+	require.NoError(t, json.Unmarshal(body2, &getResult))
+	assert.Equal(t, runID, getResult.Data.Run.ID)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRun — includes events and decisions
+// Coverage push: HandleCompleteRun — various status paths
 // ===========================================================================
 
-func TestHandleGetRun_IncludesEventsAndDecisions(t *testing.T) {
+func TestHandleCompleteRun_StatusCompleted(t *testing.T) {
 	// Create a run.
-	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		map[string]any{"agent_id": "test-agent"})
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
 	var runResult struct {
-		Data struct {
-			ID uuid.UUID `json:"id"`
-		} `json:"data"`
+		Data model.AgentRun `json:"data"`
 	}
-	rb, _ := io.ReadAll(runResp.Body)
-	_ = runResp.Body.Close()
-	require.Equal(t, http.StatusCreated, runResp.StatusCode)
-	require.NoError(t, json.Unmarshal(rb, &runResult))
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-	// Append events.
-	eventsResp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/events", agentToken,
-		model.AppendEventsRequest{
-			Events: []model.EventInput{
-				{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "approved"}},
-			},
-		})
+	// Complete with explicit "completed" status.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, map[string]any{"status": "completed"})
 	require.NoError(t, err)
-	_ = eventsResp.Body.Close()
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+}
 
-	// Get the run — verify it returns run, events, and decisions arrays.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String(), agentToken, nil)
+func TestHandleCompleteRun_StatusFailed(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	var raw map[string]json.RawMessage
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &raw))
-	assert.Contains(t, raw, "data")
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
+
+	// Complete with "failed" status.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, map[string]any{"status": "failed"})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+}
+
+func TestHandleCompleteRun_InvalidStatusCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
+
+	// Complete with invalid status.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, map[string]any{"status": "invalid_status"})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleCompleteRun — with metadata and "failed" status
-// ===========================================================================
-
-func TestHandleCompleteRun_FailedStatus(t *testing.T) {
-	// Create a run.
-	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		map[string]any{"agent_id": "test-agent"})
+func TestHandleCompleteRun_NotYourRunCov(t *testing.T) {
+	// Create a run as admin.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
+		model.CreateRunRequest{AgentID: "admin"})
 	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
 	var runResult struct {
-		Data struct {
-			ID uuid.UUID `json:"id"`
-		} `json:"data"`
+		Data model.AgentRun `json:"data"`
 	}
-	rb, _ := io.ReadAll(runResp.Body)
-	_ = runResp.Body.Close()
-	require.Equal(t, http.StatusCreated, runResp.StatusCode)
-	require.NoError(t, json.Unmarshal(rb, &runResult))
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-	// Complete with failed status.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"/complete", agentToken,
-		map[string]any{
-			"status":   "failed",
-			"metadata": map[string]any{"error": "out of memory"},
-		})
+	// Try to complete as agent — should fail.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, map[string]any{"status": "completed"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp2.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleHealth — full response shape with buffer
-// ===========================================================================
-
-func TestHandleHealth_FullResponseShape(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/health")
+func TestHandleCompleteRun_NonexistentRun(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.NewString()+"/complete",
+		agentToken, map[string]any{"status": "completed"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Status       string `json:"status"`
-			Version      string `json:"version"`
-			Postgres     string `json:"postgres"`
-			BufferDepth  int    `json:"buffer_depth"`
-			BufferStatus string `json:"buffer_status"`
-			Uptime       int64  `json:"uptime"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "healthy", result.Data.Status)
-	assert.Equal(t, "connected", result.Data.Postgres)
-	assert.Equal(t, "test", result.Data.Version)
-	assert.GreaterOrEqual(t, result.Data.Uptime, int64(0))
-	assert.GreaterOrEqual(t, result.Data.BufferDepth, 0)
-	assert.Equal(t, "ok", result.Data.BufferStatus)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleMCPInfo — response shape
-// ===========================================================================
+func TestHandleCompleteRun_InvalidRunIDCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/complete",
+		agentToken, map[string]any{"status": "completed"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleMCPInfo_ResponseShape(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
+func TestHandleCompleteRun_WithIdempotency(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-	var result struct {
-		Data struct {
-			Version   string `json:"version"`
-			Transport string `json:"transport"`
-			Auth      struct {
-				Schemes   []string `json:"schemes"`
-				Preferred string   `json:"preferred"`
-			} `json:"auth"`
-		} `json:"data"`
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "test", result.Data.Version)
-	assert.Equal(t, "streamable-http", result.Data.Transport)
-	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
-	assert.Contains(t, result.Data.Auth.Schemes, "Bearer")
-}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-// ===========================================================================
-// Coverage push: HandleConfig — response shape
-// ===========================================================================
+	idemKey := "complete-idem-" + uuid.NewString()
+	completeReq := map[string]any{"status": "completed"}
 
-func TestHandleConfig_ResponseShape(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/config")
+	resp1, err := authedRequestWithHeaders("POST",
+		testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, completeReq, map[string]string{"Idempotency-Key": idemKey})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp1.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp1.StatusCode)
+	_, _ = io.ReadAll(resp1.Body)
 
-	var result struct {
-		Data struct {
-			SearchEnabled bool `json:"search_enabled"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	// With noop embedder, search should not be available.
-	assert.False(t, result.Data.SearchEnabled)
+	// Replay.
+	resp2, err := authedRequestWithHeaders("POST",
+		testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
+		agentToken, completeReq, map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionsRecent — with agent_id and decision_type filters
+// Coverage push: HandleCreateRun — validation and auth branches
 // ===========================================================================
 
-func TestHandleDecisionsRecent_WithFilters(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?agent_id=admin&decision_type=test&limit=5", adminToken, nil)
+func TestHandleCreateRun_InvalidAgentIDCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "INVALID AGENT!!!"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleExportDecisions — with from+to time range
-// ===========================================================================
-
-func TestHandleExportDecisions_WithTimeRange(t *testing.T) {
-	from := time.Now().Add(-24 * time.Hour).Format(time.RFC3339)
-	to := time.Now().Add(time.Hour).Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
+func TestHandleCreateRun_AgentCantCreateForOther(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "admin"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleTraceHealth
-// ===========================================================================
+func TestHandleCreateRun_WithIdempotency(t *testing.T) {
+	idemKey := "run-idem-" + uuid.NewString()
+	createReq := model.CreateRunRequest{AgentID: "test-agent"}
 
-func TestHandleTraceHealth_ReturnsMetrics(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
+	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs",
+		agentToken, createReq, map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp1.Body.Close() }()
+	assert.Equal(t, http.StatusCreated, resp1.StatusCode)
+	body1, _ := io.ReadAll(resp1.Body)
+
+	// Replay with same key — should return same response.
+	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs",
+		agentToken, createReq, map[string]string{"Idempotency-Key": idemKey})
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	body2, _ := io.ReadAll(resp2.Body)
+
+	var r1, r2 struct {
+		Data model.AgentRun `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body1, &r1))
+	require.NoError(t, json.Unmarshal(body2, &r2))
+	assert.Equal(t, r1.Data.ID, r2.Data.ID,
+		"replayed idempotent run create should return same run_id")
+}
+
+func TestHandleCreateRun_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/runs",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleRetractDecision — with reason body
+// Coverage push: HandleAppendEvents — auth and validation branches
 // ===========================================================================
 
-func TestHandleRetractDecision_WithReason(t *testing.T) {
-	// Create a decision.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "retract-reason-test",
-			"outcome":       "will retract with reason",
-			"confidence":    0.8,
-		},
-		"context": map[string]any{"project": "test-project"},
-	})
+func TestHandleAppendEvents_InvalidJSON(t *testing.T) {
+	// Create a run first.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	var result struct {
-		Data struct {
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var runResult struct {
+		Data model.AgentRun `json:"data"`
 	}
-	b, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(b, &result))
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &runResult))
+	runID := runResult.Data.ID
 
-	// Retract with reason.
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+result.Data.DecisionID.String(), adminToken,
-		map[string]any{"reason": "no longer valid"})
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/events",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+agentToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp2, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSearch — with semantic flag
+// Coverage push: HandleGetConflict — additional branches
 // ===========================================================================
 
-func TestHandleSearch_WithSemanticFlag(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", adminToken, map[string]any{
-		"query":    "caching strategy",
-		"semantic": true,
-		"limit":    10,
-	})
+func TestHandleGetConflict_ForbiddenForAgentRole(t *testing.T) {
+	// Agent role should still be able to see conflicts if they have read access.
+	// Create a conflict first by tracing two contradictory decisions.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	// With noop embedder, backend should be "text".
-	assert.Equal(t, "text", resp.Header.Get("X-Search-Backend"))
+	// Non-existent conflict → 404 (not 403), which exercises the handleGetConflict path.
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleListGrants
+// Coverage push: Retention endpoints — hold creation and release branches
 // ===========================================================================
 
-func TestHandleListGrants_AdminAccess(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?limit=10&offset=0", adminToken, nil)
+func TestHandleCreateHold_MissingReasonCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+		map[string]any{
+			"from": time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
+			"to":   time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleCreateAgent — with tags and metadata
-// ===========================================================================
-
-func TestHandleCreateAgent_WithTagsAndMetadata(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken, map[string]any{
-		"agent_id": "tagged-agent-cov",
-		"name":     "Tagged Agent",
-		"role":     "agent",
-		"tags":     []string{"backend", "python"},
-		"metadata": map[string]any{"team": "infra"},
-	})
+func TestHandleCreateHold_MissingDates(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+		map[string]any{
+			"reason": "litigation",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleGetAgent — valid agent
-// ===========================================================================
+func TestHandleCreateHold_ToBeforeFromCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+		map[string]any{
+			"reason": "litigation",
+			"from":   time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+			"to":     time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleGetAgent_ValidAgent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
+func TestHandleCreateHold_ValidHold(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+		map[string]any{
+			"reason":         "litigation hold for Q1",
+			"from":           time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"to":             time.Now().Add(90 * 24 * time.Hour).UTC().Format(time.RFC3339),
+			"decision_types": []string{"architecture"},
+			"agent_ids":      []string{"test-agent"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 
 	var result struct {
 		Data struct {
-			AgentID string `json:"agent_id"`
-			Name    string `json:"name"`
+			ID     string `json:"id"`
+			Reason string `json:"reason"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "test-agent", result.Data.AgentID)
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "litigation hold for Q1", result.Data.Reason)
 }
 
-// ===========================================================================
-// Coverage push: HandleUpdateAgent — valid update with metadata
-// ===========================================================================
+func TestHandleReleaseHold_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/not-a-uuid", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleUpdateAgent_ValidMetadataUpdate(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		map[string]any{"metadata": map[string]any{"updated": true}})
+func TestHandleReleaseHold_NotFoundCov(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+uuid.NewString(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandleReleaseHold_ValidRelease(t *testing.T) {
+	// Create a hold first.
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+		map[string]any{
+			"reason": "release test hold",
+			"from":   time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
+			"to":     time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+		})
+	require.NoError(t, err)
+	defer func() { _ = createResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var holdResult struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(createResp.Body)
+	require.NoError(t, json.Unmarshal(body, &holdResult))
+
+	// Release it.
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdResult.Data.ID, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleTrace — deeper branch coverage
+// Coverage push: HandleSetRetention — validation and update paths
 // ===========================================================================
 
-func TestHandleTrace_ConfidenceAboveOne(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "use redis",
-				"confidence":    1.5, // > 1 is invalid
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+func TestHandleSetRetention_InvalidRetentionDaysCov(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{"retention_days": 0})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_ConfidenceBelowZero(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "use redis",
-				"confidence":    -0.1,
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+func TestHandleSetRetention_NegativeRetentionDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{"retention_days": -1})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_AgentCantTraceForOther(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+func TestHandleSetRetention_ValidUpdateCov(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
 		map[string]any{
-			"agent_id": "admin", // agent role can't trace for another agent
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "use redis",
-				"confidence":    0.8,
-			},
-			"context": map[string]any{"project": "test-project"},
+			"retention_days":          90,
+			"retention_exclude_types": []string{"compliance"},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleTrace_BadAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "INVALID AGENT!!!",
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "use redis",
-				"confidence":    0.8,
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+func TestHandleSetRetention_InvalidJSONCov(t *testing.T) {
+	req, _ := http.NewRequest("PUT", testSrv.URL+"/v1/retention",
+		bytes.NewReader([]byte("not valid json")))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_SessionHeaderUUID(t *testing.T) {
-	sessionID := uuid.NewString()
-	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "traced with session",
-				"confidence":    0.7,
-			},
-			"context": map[string]any{"project": "test-project"},
-		}, map[string]string{
-			"X-Akashi-Session": sessionID,
-		})
+// ===========================================================================
+// Coverage push: HandleGetRetention — success path
+// ===========================================================================
+
+func TestHandleGetRetention_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleTrace_WithIdempotencyKey(t *testing.T) {
-	idemKey := "trace-idem-" + uuid.NewString()
-	traceReq := map[string]any{
-		"agent_id": "test-agent",
-		"decision": map[string]any{
-			"decision_type": "implementation",
-			"outcome":       "idempotent trace",
-			"confidence":    0.9,
-		},
-		"context": map[string]any{"project": "test-project"},
-	}
-
-	// First call.
-	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		traceReq, map[string]string{"Idempotency-Key": idemKey})
-	require.NoError(t, err)
-	defer func() { _ = resp1.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp1.StatusCode)
-	body1, _ := io.ReadAll(resp1.Body)
+// ===========================================================================
+// Coverage push: HandleListKeys — pagination
+// ===========================================================================
 
-	// Replay with same key — should return same response.
-	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		traceReq, map[string]string{"Idempotency-Key": idemKey})
+func TestHandleListKeys_WithPagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?limit=10&offset=0", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	body2, _ := io.ReadAll(resp2.Body)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	// Both should succeed and return same decision_id.
-	var r1, r2 struct {
-		Data struct {
-			DecisionID string `json:"decision_id"`
-		} `json:"data"`
+	var result struct {
+		Data    []json.RawMessage `json:"data"`
+		Total   *int              `json:"total"`
+		Limit   int               `json:"limit"`
+		Offset  int               `json:"offset"`
+		HasMore bool              `json:"has_more"`
 	}
-	require.NoError(t, json.Unmarshal(body1, &r1))
-	require.NoError(t, json.Unmarshal(body2, &r2))
-	assert.Equal(t, r1.Data.DecisionID, r2.Data.DecisionID,
-		"replayed idempotent trace should return same decision_id")
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, 0, result.Offset)
 }
 
-func TestHandleTrace_IdempotencyPayloadMismatchCov(t *testing.T) {
-	idemKey := "trace-idem-mismatch-cov-" + uuid.NewString()
+// ===========================================================================
+// ===========================================================================
+// Coverage push: HandlePatchConflict — patch various fields
+// ===========================================================================
 
-	// First call.
-	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "first payload",
-				"confidence":    0.9,
-			},
-			"context": map[string]any{"project": "test-project"},
-		}, map[string]string{"Idempotency-Key": idemKey})
+func TestHandlePatchConflict_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", agentToken,
+		map[string]any{"status": "false_positive"})
 	require.NoError(t, err)
-	defer func() { _ = resp1.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp1.StatusCode)
-	_, _ = io.ReadAll(resp1.Body)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	// Second call with different payload — should get 409.
-	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "different payload",
-				"confidence":    0.5,
-			},
-			"context": map[string]any{"project": "test-project"},
-		}, map[string]string{"Idempotency-Key": idemKey})
+func TestHandlePatchConflict_NotFound(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), agentToken,
+		map[string]any{"status": "false_positive"})
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleTrace_WithContext(t *testing.T) {
-	resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", agentToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "traced with context",
-				"confidence":    0.7,
-			},
-			"context": map[string]any{
-				"project": "test-project",
-				"tool":    "claude-code",
-			},
-		}, map[string]string{
-			"User-Agent": "akashi-go/0.1.0",
-		})
+// ===========================================================================
+// Coverage push: HandleAdjudicateConflict — validation paths
+// ===========================================================================
+
+func TestHandleAdjudicateConflict_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate",
+		adminToken, map[string]any{"outcome": "Use approach A", "reasoning": "more robust"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/trace",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+agentToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleAdjudicateConflict_NotFound(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.NewString()+"/adjudicate",
+		adminToken, map[string]any{"outcome": "Use approach A", "reasoning": "more robust"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateGrant — deeper branch coverage (55.3% → higher)
+// Coverage push: HandleDeleteGrant — validation
 // ===========================================================================
 
-func TestHandleCreateGrant_AgentRoleNonTraceResource(t *testing.T) {
-	// Agent role trying to grant non-agent_traces resource type — should fail.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
-		map[string]any{
-			"grantee_agent_id": "admin",
-			"resource_type":    "invalid_type",
-			"permission":       "read",
-		})
+func TestHandleDeleteGrant_InvalidUUIDCov(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateGrant_AgentRoleGrantOtherTracesNoResourceID(t *testing.T) {
-	// Agent trying to grant agent_traces but with nil resource_id — must fail
-	// because agents can only grant their own traces.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/grants", agentToken,
-		map[string]any{
-			"grantee_agent_id": "admin",
-			"resource_type":    "agent_traces",
-			"permission":       "read",
-		})
+func TestHandleDeleteGrant_NotFound(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.NewString(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleExportDecisions — additional filter branches (56.2% → higher)
+// Coverage push: HandleListGrants — success path
 // ===========================================================================
 
-func TestHandleExportDecisions_WithToFilterOnly(t *testing.T) {
-	// Exercise the "to without from" path (filters.TimeRange == nil check).
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/export/decisions?to="+time.Now().Add(24*time.Hour).UTC().Format(time.RFC3339),
-		adminToken, nil)
+func TestHandleListGrants_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
 }
 
-func TestHandleExportDecisions_ForbiddenForAgentWithDetail(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions", agentToken, nil)
+// ===========================================================================
+// Coverage push: HandleDecisionsRecent — success path
+// ===========================================================================
+
+func TestHandleDecisionsRecent_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRun — branches with invalid run ID format
+// Coverage push: HandleConfig — success path
 // ===========================================================================
 
-func TestHandleGetRun_ValidRunWithDetails(t *testing.T) {
-	// Create a run, append events, trace a decision, then get run details.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleConfig_Success(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/config")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
+	var result struct {
+		Data struct {
+			SearchEnabled bool `json:"search_enabled"`
+		} `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+	require.NoError(t, json.Unmarshal(body, &result))
+}
 
-	// Get the run as the owning agent — exercises the canAccessAgent path.
-	resp2, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+runID.String(), agentToken, nil)
+// ===========================================================================
+// Coverage push: HandleMCPInfo — success path
+// ===========================================================================
+
+func TestHandleMCPInfo_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var getResult struct {
+	var result struct {
 		Data struct {
-			Run       model.AgentRun  `json:"run"`
-			Events    json.RawMessage `json:"events"`
-			Decisions json.RawMessage `json:"decisions"`
+			Version   string `json:"version"`
+			Transport string `json:"transport"`
+			Auth      struct {
+				Schemes   []string `json:"schemes"`
+				Preferred string   `json:"preferred"`
+			} `json:"auth"`
 		} `json:"data"`
 	}
-	body2, _ := io.ReadAll(resp2.Body)
-	require.NoError(t, json.Unmarshal(body2, &getResult))
-	assert.Equal(t, runID, getResult.Data.Run.ID)
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	assert.Equal(t, "test", result.Data.Version)
+	assert.Equal(t, "streamable-http", result.Data.Transport)
+	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
 }
 
 // ===========================================================================
-// Coverage push: HandleCompleteRun — various status paths
+// Coverage push: HandleRetractDecision — validation paths
 // ===========================================================================
 
-func TestHandleCompleteRun_StatusCompleted(t *testing.T) {
-	// Create a run.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleRetractDecision_InvalidUUIDCov(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	// Complete with explicit "completed" status.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, map[string]any{"status": "completed"})
+func TestHandleRetractDecision_NotFound(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.NewString(), adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_StatusFailed(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+// ===========================================================================
+// Coverage push: HandleEraseDecision — validation paths
+// ===========================================================================
+
+func TestHandleEraseDecision_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/erase", orgOwnerToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+// ===========================================================================
+// Coverage push: HandleDecisionRevisions — validation
+// ===========================================================================
 
-	// Complete with "failed" status.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, map[string]any{"status": "failed"})
+func TestHandleDecisionRevisions_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_InvalidStatusCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleDecisionRevisions_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/revisions", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	// Returns empty list or 200 when decision exists but has no revisions.
+	// Depends on implementation, but exercises the handler path.
+	status := resp.StatusCode
+	assert.True(t, status == http.StatusOK || status == http.StatusNotFound)
+}
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+// ===========================================================================
+// Coverage push: HandleDecisionConflicts — validation
+// ===========================================================================
 
-	// Complete with invalid status.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, map[string]any{"status": "invalid_status"})
+func TestHandleDecisionConflicts_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/conflicts", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_NotYourRunCov(t *testing.T) {
-	// Create a run as admin.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", adminToken,
-		model.CreateRunRequest{AgentID: "admin"})
+// ===========================================================================
+// Coverage push: HandleAssessDecision — validation
+// ===========================================================================
+
+func TestHandleAssessDecision_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/assess", agentToken,
+		map[string]any{"outcome_status": "good", "explanation": "works well"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+// ===========================================================================
+// Coverage push: HandleVerifyDecision — validation
+// ===========================================================================
 
-	// Try to complete as agent — should fail.
-	resp2, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, map[string]any{"status": "completed"})
+func TestHandleVerifyDecision_InvalidUUIDCov(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/not-a-uuid", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_NonexistentRun(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.NewString()+"/complete",
-		agentToken, map[string]any{"status": "completed"})
+func TestHandleVerifyDecision_NotFoundCov(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+uuid.NewString(), agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_InvalidRunIDCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/not-a-uuid/complete",
-		agentToken, map[string]any{"status": "completed"})
+// ===========================================================================
+// Coverage push: HandleConflictAnalytics — success path
+// ===========================================================================
+
+func TestHandleConflictAnalytics_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCompleteRun_WithIdempotency(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+// ===========================================================================
+// Coverage push: HandleListConflictGroups — success path
+// ===========================================================================
+
+func TestHandleListConflictGroups_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
-
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-	idemKey := "complete-idem-" + uuid.NewString()
-	completeReq := map[string]any{"status": "completed"}
+// ===========================================================================
+// Coverage push: HandleResolveConflictGroup — validation
+// ===========================================================================
 
-	resp1, err := authedRequestWithHeaders("POST",
-		testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, completeReq, map[string]string{"Idempotency-Key": idemKey})
+func TestHandleResolveConflictGroup_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve",
+		agentToken, map[string]any{"resolution": "accepted_a"})
 	require.NoError(t, err)
-	defer func() { _ = resp1.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp1.StatusCode)
-	_, _ = io.ReadAll(resp1.Body)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	// Replay.
-	resp2, err := authedRequestWithHeaders("POST",
-		testSrv.URL+"/v1/runs/"+runID.String()+"/complete",
-		agentToken, completeReq, map[string]string{"Idempotency-Key": idemKey})
+// ===========================================================================
+// Coverage push: HandleTraceHealth — success path
+// ===========================================================================
+
+func TestHandleTraceHealth_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateRun — validation and auth branches
+// Coverage push: HandleScopedToken — validation paths
 // ===========================================================================
 
-func TestHandleCreateRun_InvalidAgentIDCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "INVALID AGENT!!!"})
+func TestHandleScopedToken_MissingAgentIDCov(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
+		map[string]any{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateRun_AgentCantCreateForOther(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "admin"})
+// ===========================================================================
+// Coverage push: HandleAgentHistory — success and validation paths
+// ===========================================================================
+
+func TestHandleAgentHistory_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateRun_WithIdempotency(t *testing.T) {
-	idemKey := "run-idem-" + uuid.NewString()
-	createReq := model.CreateRunRequest{AgentID: "test-agent"}
+// ===========================================================================
+// Coverage push: HandleQuery — validation paths
+// ===========================================================================
 
-	resp1, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs",
-		agentToken, createReq, map[string]string{"Idempotency-Key": idemKey})
+func TestHandleQuery_EmptyBody(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken, map[string]any{})
 	require.NoError(t, err)
-	defer func() { _ = resp1.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp1.StatusCode)
-	body1, _ := io.ReadAll(resp1.Body)
+	defer func() { _ = resp.Body.Close() }()
+	// Empty query should still return a valid response (possibly empty results).
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-	// Replay with same key — should return same response.
-	resp2, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/runs",
-		agentToken, createReq, map[string]string{"Idempotency-Key": idemKey})
+func TestHandleQuery_WithFilters(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
+		map[string]any{
+			"filters": map[string]any{
+				"agent_id":      []string{"test-agent"},
+				"decision_type": "architecture",
+			},
+			"limit": 5,
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	body2, _ := io.ReadAll(resp2.Body)
-
-	var r1, r2 struct {
-		Data model.AgentRun `json:"data"`
-	}
-	require.NoError(t, json.Unmarshal(body1, &r1))
-	require.NoError(t, json.Unmarshal(body2, &r2))
-	assert.Equal(t, r1.Data.ID, r2.Data.ID,
-		"replayed idempotent run create should return same run_id")
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateRun_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/runs",
+func TestHandleQuery_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/query",
 		bytes.NewReader([]byte("not valid json")))
 	req.Header.Set("Authorization", "Bearer "+agentToken)
 	req.Header.Set("Content-Type", "application/json")
@@ -8990,1251 +10787,1471 @@ func TestHandleCreateRun_InvalidJSON(t *testing.T) {
 }
 
 // ===========================================================================
-// Coverage push: HandleAppendEvents — auth and validation branches
+// Coverage push: HandleTemporalQuery — validation paths
 // ===========================================================================
 
-func TestHandleAppendEvents_InvalidJSON(t *testing.T) {
-	// Create a run first.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleTemporalQuery_EmptyBody(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken, map[string]any{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	// Depends on implementation: either 200 with empty results or 400 for missing fields.
+	status := resp.StatusCode
+	assert.True(t, status == http.StatusOK || status == http.StatusBadRequest)
+}
 
-	var runResult struct {
-		Data model.AgentRun `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &runResult))
-	runID := runResult.Data.ID
+// ===========================================================================
+// Coverage push: HandleCheck — validation paths
+// ===========================================================================
 
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/events",
+func TestHandleCheck_ValidRequest(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
+		map[string]any{
+			"decision_type": "architecture",
+			"query":         "use redis for caching",
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandleCheck_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/check",
 		bytes.NewReader([]byte("not valid json")))
 	req.Header.Set("Authorization", "Bearer "+agentToken)
 	req.Header.Set("Content-Type", "application/json")
-	resp2, err := http.DefaultClient.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	defer func() { _ = resp2.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetConflict — additional branches
+// Coverage push: HandleSearch — basic path
 // ===========================================================================
 
-func TestHandleGetConflict_ForbiddenForAgentRole(t *testing.T) {
-	// Agent role should still be able to see conflicts if they have read access.
-	// Create a conflict first by tracing two contradictory decisions.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), agentToken, nil)
+func TestHandleSearch_ValidRequest(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+		map[string]any{
+			"query": "redis",
+			"limit": 5,
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	// Search may return 200 (with text fallback) or 501 depending on searcher config.
+	status := resp.StatusCode
+	assert.True(t, status == http.StatusOK || status == http.StatusNotImplemented)
+}
+
+// ===========================================================================
+// Coverage push: HandleAgentStats — success path
+// ===========================================================================
+
+func TestHandleAgentStats_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/stats", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandleAgentStats_NotFoundCov(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent/stats", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Non-existent conflict → 404 (not 403), which exercises the handleGetConflict path.
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: Retention endpoints — hold creation and release branches
+// Coverage push: HandleSessionView — validation and success paths
+// ===========================================================================
+
+func TestHandleSessionView_InvalidSessionID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/not-a-uuid", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandleSessionView_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.NewString(), agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	// No decisions for this session — may return 404 or 200 with empty data.
+	status := resp.StatusCode
+	assert.True(t, status == http.StatusOK || status == http.StatusNotFound)
+}
+
+// ===========================================================================
+// Coverage push: HandleListAssessments — validation
 // ===========================================================================
 
-func TestHandleCreateHold_MissingReasonCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
-		map[string]any{
-			"from": time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
-			"to":   time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
-		})
+func TestHandleListAssessments_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/assessments", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateHold_MissingDates(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+// ===========================================================================
+// Coverage push: HandleUpdateAgentTags — success path
+// ===========================================================================
+
+func TestHandleUpdateAgentTags_ValidUpdate(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
 		map[string]any{
-			"reason": "litigation",
+			"tags": []string{"go", "backend"},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCreateHold_ToBeforeFromCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
+func TestHandleUpdateAgentTags_NonexistentAgentCov(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent/tags", adminToken,
 		map[string]any{
-			"reason": "litigation",
-			"from":   time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
-			"to":     time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
+			"tags": []string{"go"},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleCreateHold_ValidHold(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
-		map[string]any{
-			"reason":         "litigation hold for Q1",
-			"from":           time.Now().Add(-30 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"to":             time.Now().Add(90 * 24 * time.Hour).UTC().Format(time.RFC3339),
-			"decision_types": []string{"architecture"},
-			"agent_ids":      []string{"test-agent"},
-		})
+// ===========================================================================
+// Coverage push: HandleDeleteAgent — validation path
+// ===========================================================================
+
+func TestHandleDeleteAgent_NonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/nonexistent-del-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	var result struct {
-		Data struct {
-			ID     string `json:"id"`
-			Reason string `json:"reason"`
+// ===========================================================================
+// Coverage push: HandleRotateKey — success path
+// ===========================================================================
+
+func TestHandleRotateKey_Success(t *testing.T) {
+	// Create a throwaway agent with a key.
+	createAgent(testSrv.URL, adminToken, "rotate-agent", "Rotate Agent", "agent", "rotate-agent-key")
+
+	// List keys to find the key we just created.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?limit=100", adminToken, nil)
+	require.NoError(t, err)
+	var keysResp struct {
+		Data []struct {
+			ID      uuid.UUID `json:"id"`
+			AgentID string    `json:"agent_id"`
 		} `json:"data"`
 	}
 	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "litigation hold for Q1", result.Data.Reason)
-}
-
-func TestHandleReleaseHold_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/not-a-uuid", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	_ = resp.Body.Close()
+	require.NoError(t, json.Unmarshal(body, &keysResp))
 
-func TestHandleReleaseHold_NotFoundCov(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+uuid.NewString(), adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	var keyID uuid.UUID
+	for _, k := range keysResp.Data {
+		if k.AgentID == "rotate-agent" {
+			keyID = k.ID
+			break
+		}
+	}
+	require.NotEqual(t, uuid.Nil, keyID, "should find key for rotate-agent")
 
-func TestHandleReleaseHold_ValidRelease(t *testing.T) {
-	// Create a hold first.
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/retention/hold", adminToken,
-		map[string]any{
-			"reason": "release test hold",
-			"from":   time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
-			"to":     time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
-		})
+	// Rotate the key.
+	rotResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID.String()+"/rotate", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = createResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	defer func() { _ = rotResp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, rotResp.StatusCode)
 
-	var holdResult struct {
+	var rotResult struct {
 		Data struct {
-			ID string `json:"id"`
+			RevokedKeyID uuid.UUID `json:"revoked_key_id"`
+			NewKey       struct {
+				ID string `json:"id"`
+			} `json:"new_key"`
 		} `json:"data"`
 	}
-	body, _ := io.ReadAll(createResp.Body)
-	require.NoError(t, json.Unmarshal(body, &holdResult))
+	rotBody, _ := io.ReadAll(rotResp.Body)
+	require.NoError(t, json.Unmarshal(rotBody, &rotResult))
+	assert.Equal(t, keyID, rotResult.Data.RevokedKeyID)
+}
 
-	// Release it.
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+holdResult.Data.ID, adminToken, nil)
+func TestHandleRotateKey_NotFound(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+uuid.NewString()+"/rotate", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSetRetention — validation and update paths
+// Coverage push: HandleUpdateAgent — validation paths
 // ===========================================================================
 
-func TestHandleSetRetention_InvalidRetentionDaysCov(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{"retention_days": 0})
+func TestHandleUpdateAgent_NameOnly(t *testing.T) {
+	name := "Updated Agent Name"
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		map[string]any{"name": name})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleSetRetention_NegativeRetentionDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{"retention_days": -1})
+func TestHandleUpdateAgent_MetadataOnly(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		map[string]any{"metadata": map[string]any{"team": "backend"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleSetRetention_ValidUpdateCov(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
-		map[string]any{
-			"retention_days":          90,
-			"retention_exclude_types": []string{"compliance"},
-		})
+func TestHandleUpdateAgent_InvalidAgentIDChars(t *testing.T) {
+	// Agent IDs with spaces or special chars should be rejected by ValidateAgentID.
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/bad%20agent%21", adminToken,
+		map[string]any{"name": "test"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleSetRetention_InvalidJSONCov(t *testing.T) {
-	req, _ := http.NewRequest("PUT", testSrv.URL+"/v1/retention",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleCreateAgent_EqualRoleForbidden(t *testing.T) {
+	// Admin trying to create another admin should be forbidden.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: "peer-admin", Name: "Peer Admin", Role: model.RoleAdmin, APIKey: "pa-key"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleGetRetention — success path
-// ===========================================================================
-
-func TestHandleGetRetention_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", agentToken, nil)
+func TestHandleCreateAgent_DuplicateID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: "test-agent", Name: "Duplicate", Role: model.RoleAgent, APIKey: "dup-key"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleListKeys — pagination
-// ===========================================================================
-
-func TestHandleListKeys_WithPagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?limit=10&offset=0", adminToken, nil)
+func TestHandleListConflictGroups_FalsePositiveStatus(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?status=false_positive", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data    []json.RawMessage `json:"data"`
-		Total   *int              `json:"total"`
-		Limit   int               `json:"limit"`
-		Offset  int               `json:"offset"`
-		HasMore bool              `json:"has_more"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, 10, result.Limit)
-	assert.Equal(t, 0, result.Offset)
 }
 
-// ===========================================================================
-// ===========================================================================
-// Coverage push: HandlePatchConflict — patch various fields
-// ===========================================================================
-
-func TestHandlePatchConflict_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/not-a-uuid", agentToken,
-		map[string]any{"status": "false_positive"})
+func TestHandleListConflictGroups_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?agent_id=test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandlePatchConflict_NotFound(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), agentToken,
-		map[string]any{"status": "false_positive"})
+func TestHandleListConflictGroups_WithConflictKindFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?conflict_kind=cross_agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleAdjudicateConflict — validation paths
-// ===========================================================================
-
-func TestHandleAdjudicateConflict_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/not-a-uuid/adjudicate",
-		adminToken, map[string]any{"outcome": "Use approach A", "reasoning": "more robust"})
+func TestHandleListConflictGroups_InvalidConflictKind(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?conflict_kind=direct", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAdjudicateConflict_NotFound(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.NewString()+"/adjudicate",
-		adminToken, map[string]any{"outcome": "Use approach A", "reasoning": "more robust"})
+// ===========================================================================
+// Coverage push: HandleConflictAnalytics — filter and period paths
+// ===========================================================================
+
+func TestHandleConflictAnalytics_DefaultPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleDeleteGrant — validation
-// ===========================================================================
-
-func TestHandleDeleteGrant_InvalidUUIDCov(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/not-a-uuid", adminToken, nil)
+func TestHandleConflictAnalytics_30dPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=30d", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleDeleteGrant_NotFound(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.NewString(), adminToken, nil)
+func TestHandleConflictAnalytics_90dPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=90d", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleListGrants — success path
-// ===========================================================================
-
-func TestHandleListGrants_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants", adminToken, nil)
+func TestHandleConflictAnalytics_FromToRange(t *testing.T) {
+	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleDecisionsRecent — success path
-// ===========================================================================
+func TestHandleConflictAnalytics_ExceedsMaxRange(t *testing.T) {
+	from := time.Now().Add(-400 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-func TestHandleDecisionsRecent_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent", agentToken, nil)
+func TestHandleConflictAnalytics_WithAgentFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?agent_id=test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleConfig — success path
-// ===========================================================================
-
-func TestHandleConfig_Success(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/config")
+func TestHandleConflictAnalytics_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?decision_type=architecture", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			SearchEnabled bool `json:"search_enabled"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
 }
 
-// ===========================================================================
-// Coverage push: HandleMCPInfo — success path
-// ===========================================================================
-
-func TestHandleMCPInfo_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
+func TestHandleConflictAnalytics_WithConflictKindFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?conflict_kind=self_contradiction", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			Version   string `json:"version"`
-			Transport string `json:"transport"`
-			Auth      struct {
-				Schemes   []string `json:"schemes"`
-				Preferred string   `json:"preferred"`
-			} `json:"auth"`
-		} `json:"data"`
-	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	assert.Equal(t, "test", result.Data.Version)
-	assert.Equal(t, "streamable-http", result.Data.Transport)
-	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
 }
 
-// ===========================================================================
-// Coverage push: HandleRetractDecision — validation paths
-// ===========================================================================
-
-func TestHandleRetractDecision_InvalidUUIDCov(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
+func TestHandleConflictAnalytics_InvalidConflictKind(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?conflict_kind=direct", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleRetractDecision_NotFound(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/decisions/"+uuid.NewString(), adminToken, nil)
+func TestHandleGetUsage_ValidPastPeriod(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleEraseDecision — validation paths
-// ===========================================================================
-
-func TestHandleEraseDecision_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/erase", orgOwnerToken, nil)
+func TestHandleAppendEvents_NonexistentRun(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.NewString()+"/events", agentToken,
+		model.AppendEventsRequest{Events: []model.EventInput{
+			{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "x"}},
+		}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionRevisions — validation
+// Coverage push: HandleGetRun — bad UUID
 // ===========================================================================
 
-func TestHandleDecisionRevisions_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/revisions", agentToken, nil)
+func TestHandleGetRun_BadUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/not-a-uuid", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleDecisionRevisions_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/revisions", agentToken, nil)
+func TestHandleGetRun_NotFound(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+uuid.NewString(), agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Returns empty list or 200 when decision exists but has no revisions.
-	// Depends on implementation, but exercises the handler path.
-	status := resp.StatusCode
-	assert.True(t, status == http.StatusOK || status == http.StatusNotFound)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionConflicts — validation
+// Coverage push: HandlePatchConflict — resolved + winner
 // ===========================================================================
 
-func TestHandleDecisionConflicts_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/conflicts", agentToken, nil)
+func TestHandlePatchConflict_WinnerOnNonResolved(t *testing.T) {
+	winnerID := uuid.NewString()
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), adminToken,
+		map[string]any{"status": "false_positive", "winning_decision_id": winnerID})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
+	// winning_decision_id is only valid when status is "resolved".
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAssessDecision — validation
+// Coverage push: HandleDecisionConflicts — paths
 // ===========================================================================
 
-func TestHandleAssessDecision_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/not-a-uuid/assess", agentToken,
-		map[string]any{"outcome_status": "good", "explanation": "works well"})
+func TestHandleDecisionConflicts_BadUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/conflicts", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleVerifyDecision — validation
-// ===========================================================================
-
-func TestHandleVerifyDecision_InvalidUUIDCov(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/not-a-uuid", agentToken, nil)
+func TestHandleDecisionConflicts_NonexistentDecision(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/conflicts", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleVerifyDecision_NotFoundCov(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+uuid.NewString(), agentToken, nil)
+func TestHandleDecisionConflicts_WithStatusFilter(t *testing.T) {
+	decisionAID, _, _ := seedConflict(t)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionAID.String()+"/conflicts?status=resolved", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data []model.DecisionConflict `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Empty(t, result.Data, "the seeded conflict is open")
 }
 
-// ===========================================================================
-// Coverage push: HandleConflictAnalytics — success path
-// ===========================================================================
+func TestHandleDecisionConflicts_ReturnsBothOutcomes(t *testing.T) {
+	decisionAID, decisionBID, conflictID := seedConflict(t)
 
-func TestHandleConflictAnalytics_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", agentToken, nil)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionBID.String()+"/conflicts", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data []model.DecisionConflict `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Data, 1)
+	c := result.Data[0]
+	assert.Equal(t, conflictID, c.ID)
+	assert.Equal(t, decisionAID, c.DecisionAID)
+	assert.Equal(t, "spec-34 side A: use Redis", c.OutcomeA)
+	assert.Equal(t, "spec-34 side B: use Memcached", c.OutcomeB)
+	assert.False(t, c.CounterpartRedacted)
 }
 
-// ===========================================================================
-// Coverage push: HandleListConflictGroups — success path
-// ===========================================================================
+func TestHandleDecisionConflicts_ForbiddenDecision(t *testing.T) {
+	decisionAID, _, _ := seedConflict(t)
 
-func TestHandleListConflictGroups_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups", agentToken, nil)
+	// A fresh agent holds no grant on admin's decisions.
+	agentID := "conflicts-outsider-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	token := getToken(testSrv.URL, agentID, agentID+"-key")
+
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionAID.String()+"/conflicts", token, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleResolveConflictGroup — validation
+// Coverage push: HandleListAssessments — paths
 // ===========================================================================
 
-func TestHandleResolveConflictGroup_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflict-groups/not-a-uuid/resolve",
-		agentToken, map[string]any{"resolution": "accepted_a"})
+func TestHandleListAssessments_BadUUIDCov(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/assessments", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleTraceHealth — success path
-// ===========================================================================
-
-func TestHandleTraceHealth_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/trace-health", adminToken, nil)
+func TestHandleListAssessments_NonexistentDecisionCov(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/assessments", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleScopedToken — validation paths
+// Coverage push: HandleListGrants — pagination
 // ===========================================================================
 
-func TestHandleScopedToken_MissingAgentIDCov(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/auth/scoped-token", adminToken,
-		map[string]any{})
+func TestHandleListGrants_WithPagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?limit=10&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAgentHistory — success and validation paths
+// Coverage push: HandleRetention — more paths
 // ===========================================================================
 
-func TestHandleAgentHistory_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/history", agentToken, nil)
+func TestHandleGetRetention_DefaultPolicy(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			RetentionDays *int `json:"retention_days"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
 }
 
 // ===========================================================================
-// Coverage push: HandleQuery — validation paths
+// Coverage push: HandleAdjudicateConflict — outcome validation
 // ===========================================================================
 
-func TestHandleQuery_EmptyBody(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken, map[string]any{})
+func TestHandleAdjudicateConflict_MissingOutcome(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.NewString()+"/adjudicate",
+		adminToken, map[string]any{"reasoning": "some reasoning"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Empty query should still return a valid response (possibly empty results).
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleQuery_WithFilters(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query", agentToken,
-		map[string]any{
-			"filters": map[string]any{
-				"agent_id":      []string{"test-agent"},
-				"decision_type": "architecture",
-			},
-			"limit": 5,
-		})
+// ===========================================================================
+// Coverage push: HandleGetAgent — paths
+// ===========================================================================
+
+func TestHandleGetAgent_Success(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
 
-func TestHandleQuery_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/query",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+agentToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var result struct {
+		Data struct {
+			AgentID string `json:"agent_id"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "test-agent", result.Data.AgentID)
 }
 
 // ===========================================================================
-// Coverage push: HandleTemporalQuery — validation paths
+// Coverage push: HandleOpenAPISpec
 // ===========================================================================
 
-func TestHandleTemporalQuery_EmptyBody(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", agentToken, map[string]any{})
+func TestHandleOpenAPISpec_ReturnsYAML(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/openapi.yaml", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Depends on implementation: either 200 with empty results or 400 for missing fields.
-	status := resp.StatusCode
-	assert.True(t, status == http.StatusOK || status == http.StatusBadRequest)
+	// May return 200 (with spec) or 404 (no spec embedded in test build).
+	assert.Contains(t, []int{http.StatusOK, http.StatusNotFound}, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCheck — validation paths
+// Coverage push: HandleListAgents — paths
 // ===========================================================================
 
-func TestHandleCheck_ValidRequest(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", agentToken,
-		map[string]any{
-			"decision_type": "architecture",
-			"query":         "use redis for caching",
-		})
+func TestHandleListAgents_WithPagination(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?limit=5&offset=0", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleCheck_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/check",
-		bytes.NewReader([]byte("not valid json")))
-	req.Header.Set("Authorization", "Bearer "+agentToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleListAgents_WithRoleFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?role=agent", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleSearch — basic path
-// ===========================================================================
-
-func TestHandleSearch_ValidRequest(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/search", agentToken,
+func TestHandleTemporalQuery_ValidQuery(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
 		map[string]any{
-			"query": "redis",
-			"limit": 5,
+			"as_of": time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339),
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Search may return 200 (with text fallback) or 501 depending on searcher config.
-	status := resp.StatusCode
-	assert.True(t, status == http.StatusOK || status == http.StatusNotImplemented)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAgentStats — success path
+// Coverage push: HandleSubscribe — nil broker
 // ===========================================================================
 
-func TestHandleAgentStats_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent/stats", adminToken, nil)
+func TestHandleSubscribe_NilBroker(t *testing.T) {
+	// The test server has no broker configured, so /v1/subscribe should return 503.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/subscribe", agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
 }
 
-func TestHandleAgentStats_NotFoundCov(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-agent/stats", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleScopedToken — paths
+// ===========================================================================
+
+func TestHandleScopedToken_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/auth/scoped-token", strings.NewReader("bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleSessionView — validation and success paths
-// ===========================================================================
+func TestHandleExportDecisions_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?decision_type=architecture", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-func TestHandleSessionView_InvalidSessionID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/not-a-uuid", agentToken, nil)
+func TestHandleExportDecisions_InvalidFromDate(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleSessionView_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.NewString(), agentToken, nil)
+func TestHandleExportDecisions_InvalidToDate(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?to=not-a-date", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// No decisions for this session — may return 404 or 200 with empty data.
-	status := resp.StatusCode
-	assert.True(t, status == http.StatusOK || status == http.StatusNotFound)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleListAssessments — validation
-// ===========================================================================
+func TestHandleExportDecisions_WithFromAndTo(t *testing.T) {
+	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
 
-func TestHandleListAssessments_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/assessments", agentToken, nil)
+func TestHandleExportDecisions_ToOnly(t *testing.T) {
+	to := time.Now().UTC().Format(time.RFC3339)
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?to="+to, adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleUpdateAgentTags — success path
+// Coverage push: HandleCreateKey — with expires_at
 // ===========================================================================
 
-func TestHandleUpdateAgentTags_ValidUpdate(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent/tags", adminToken,
+func TestHandleCreateKey_WithLabel(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
 		map[string]any{
-			"tags": []string{"go", "backend"},
+			"agent_id": "test-agent",
+			"label":    "ci-key",
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-func TestHandleUpdateAgentTags_NonexistentAgentCov(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/nonexistent/tags", adminToken,
-		map[string]any{
-			"tags": []string{"go"},
-		})
+// ===========================================================================
+// Coverage push: HandleAuthToken — invalid credentials
+// ===========================================================================
+
+func TestHandleAuthToken_WrongAPIKey(t *testing.T) {
+	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "test-agent", APIKey: "wrong-key"})
+	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
-// ===========================================================================
-// Coverage push: HandleDeleteAgent — validation path
-// ===========================================================================
+func TestHandleAuthToken_NonexistentAgent(t *testing.T) {
+	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "nonexistent-auth-agent", APIKey: "some-key"})
+	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
 
-func TestHandleDeleteAgent_NonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("DELETE", testSrv.URL+"/v1/agents/nonexistent-del-agent", adminToken, nil)
+func TestHandleAuthToken_MissingFields(t *testing.T) {
+	body, _ := json.Marshal(model.AuthTokenRequest{})
+	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	// Empty credentials are treated as invalid auth, not a validation error.
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleRotateKey — success path
+// Coverage push: HandleTemporalQuery — agent_ids and decision_type filters
 // ===========================================================================
 
-func TestHandleRotateKey_Success(t *testing.T) {
-	// Create a throwaway agent with a key.
-	createAgent(testSrv.URL, adminToken, "rotate-agent", "Rotate Agent", "agent", "rotate-agent-key")
+func TestHandleTemporalQuery_WithAgentIDsFilter(t *testing.T) {
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "temporal-agent-filter-test",
+				Outcome:      "use agent filter",
+				Confidence:   0.8,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
+	require.NoError(t, err)
+	_ = traceResp.Body.Close()
 
-	// List keys to find the key we just created.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/keys?limit=100", adminToken, nil)
+	pastTime := time.Now().Add(-1 * time.Second)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
+		model.TemporalQueryRequest{
+			AsOf: pastTime,
+			Filters: model.QueryFilters{
+				AgentIDs: []string{"test-agent"},
+			},
+		})
 	require.NoError(t, err)
-	var keysResp struct {
-		Data []struct {
-			ID      uuid.UUID `json:"id"`
-			AgentID string    `json:"agent_id"`
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data struct {
+			AsOf      time.Time        `json:"as_of"`
+			Decisions []model.Decision `json:"decisions"`
 		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	_ = resp.Body.Close()
-	require.NoError(t, json.Unmarshal(body, &keysResp))
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.False(t, result.Data.AsOf.IsZero())
+}
 
-	var keyID uuid.UUID
-	for _, k := range keysResp.Data {
-		if k.AgentID == "rotate-agent" {
-			keyID = k.ID
-			break
+func TestHandleTemporalDiff(t *testing.T) {
+	agentID := "temporal-diff-" + uuid.New().String()[:8]
+	trace := func(outcome string, supersedes *uuid.UUID) uuid.UUID {
+		t.Helper()
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: agentID,
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      outcome,
+				Confidence:   0.8,
+			},
+			SupersedesID: supersedes,
+		})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var out struct {
+			Data model.TraceResponse `json:"data"`
 		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return out.Data.DecisionID
 	}
-	require.NotEqual(t, uuid.Nil, keyID, "should find key for rotate-agent")
 
-	// Rotate the key.
-	rotResp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+keyID.String()+"/rotate", adminToken, nil)
+	unchanged := trace("keep the monolith", nil)
+	revised := trace("use redis for caching", nil)
+	time.Sleep(20 * time.Millisecond)
+	from := time.Now()
+	time.Sleep(20 * time.Millisecond)
+
+	revision := trace("use memcached for caching", &revised)
+	added := trace("adopt feature flags", nil)
+	time.Sleep(20 * time.Millisecond)
+	to := time.Now()
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal/diff", adminToken,
+		model.TemporalDiffRequest{AgentID: agentID, From: from, To: to})
 	require.NoError(t, err)
-	defer func() { _ = rotResp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, rotResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var rotResult struct {
-		Data struct {
-			RevokedKeyID uuid.UUID `json:"revoked_key_id"`
-			NewKey       struct {
-				ID string `json:"id"`
-			} `json:"new_key"`
-		} `json:"data"`
+	var result struct {
+		Data model.TemporalDiffResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	diff := result.Data
+
+	require.Len(t, diff.Changed, 1, "the revised decision belongs in changed")
+	assert.Equal(t, revised, diff.Changed[0].Before.ID)
+	assert.Equal(t, revision, diff.Changed[0].After.ID)
+	assert.True(t, diff.Changed[0].OutcomeChanged)
+
+	require.Len(t, diff.Added, 1, "the revision must not also appear as added")
+	assert.Equal(t, added, diff.Added[0].ID)
+	assert.Empty(t, diff.Superseded)
+	assert.False(t, diff.Truncated)
+
+	for _, d := range diff.Added {
+		assert.NotEqual(t, unchanged, d.ID)
 	}
-	rotBody, _ := io.ReadAll(rotResp.Body)
-	require.NoError(t, json.Unmarshal(rotBody, &rotResult))
-	assert.Equal(t, keyID, rotResult.Data.RevokedKeyID)
 }
 
-func TestHandleRotateKey_NotFound(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys/"+uuid.NewString()+"/rotate", adminToken, nil)
+func TestHandleTemporalDiff_Validation(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name string
+		body model.TemporalDiffRequest
+	}{
+		{"missing agent", model.TemporalDiffRequest{From: now.Add(-time.Hour), To: now}},
+		{"missing from", model.TemporalDiffRequest{AgentID: "test-agent", To: now}},
+		{"from after to", model.TemporalDiffRequest{AgentID: "test-agent", From: now, To: now.Add(-time.Hour)}},
+		{"future to", model.TemporalDiffRequest{AgentID: "test-agent", From: now, To: now.Add(time.Hour)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal/diff", adminToken, tc.body)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandleTemporalQuery_WithDecisionTypeFilter(t *testing.T) {
+	pastTime := time.Now().Add(-1 * time.Second)
+	dt := "temporal-agent-filter-test"
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
+		model.TemporalQueryRequest{
+			AsOf: pastTime,
+			Filters: model.QueryFilters{
+				DecisionType: &dt,
+			},
+		})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHandleTemporalQuery_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/query/temporal", strings.NewReader("{bad json"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleUpdateAgent — validation paths
+// Coverage push: HandleTrace — forbidden, agent auto-registration, validation
 // ===========================================================================
 
-func TestHandleUpdateAgent_NameOnly(t *testing.T) {
-	name := "Updated Agent Name"
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		map[string]any{"name": name})
+func TestHandleTrace_NonexistentAgentForNonAdmin(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "nonexistent-agent-xyz",
+			Decision: model.TraceDecision{
+				DecisionType: "test",
+				Outcome:      "test",
+				Confidence:   0.5,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_MetadataOnly(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		map[string]any{"metadata": map[string]any{"team": "backend"}})
+func TestHandleTrace_AdminTracesNonexistentAgent(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "auto-reg-agent-" + uuid.New().String()[:8],
+			Decision: model.TraceDecision{
+				DecisionType: "auto-reg-test",
+				Outcome:      "auto registered",
+				Confidence:   0.7,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
 }
 
-func TestHandleUpdateAgent_InvalidAgentIDChars(t *testing.T) {
-	// Agent IDs with spaces or special chars should be rejected by ValidateAgentID.
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/bad%20agent%21", adminToken,
-		map[string]any{"name": "test"})
+func TestHandleTrace_DecisionTypeOverMaxLen(t *testing.T) {
+	longType := strings.Repeat("x", 300)
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: longType,
+				Outcome:      "chosen",
+				Confidence:   0.5,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleCreateAgent_EqualRoleForbidden(t *testing.T) {
-	// Admin trying to create another admin should be forbidden.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: "peer-admin", Name: "Peer Admin", Role: model.RoleAdmin, APIKey: "pa-key"})
+// ===========================================================================
+// Coverage push: HandleGetRun — reader forbidden without grant
+// ===========================================================================
+
+func TestHandleGetRun_ReaderForbiddenWithoutGrant(t *testing.T) {
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
+	defer func() { _ = createResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
 
-func TestHandleCreateAgent_DuplicateID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: "test-agent", Name: "Duplicate", Role: model.RoleAgent, APIKey: "dup-key"})
+	var run struct {
+		Data model.AgentRun `json:"data"`
+	}
+	b, _ := io.ReadAll(createResp.Body)
+	require.NoError(t, json.Unmarshal(b, &run))
+
+	readerID := "reader-run-forbidden-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, readerID, "Reader No Access", "reader", readerID+"-key")
+	rToken := getToken(testSrv.URL, readerID, readerID+"-key")
+
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+run.Data.ID.String(), rToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleListConflictGroups_FalsePositiveStatus(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?status=false_positive", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleAppendEvents — agent cannot append to other's run
+// ===========================================================================
+
+func TestHandleAppendEvents_AgentCannotAppendToOtherAgentRun(t *testing.T) {
+	otherAgentID := "other-run-agent-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Agent", "agent", otherAgentID+"-key")
+	otherToken := getToken(testSrv.URL, otherAgentID, otherAgentID+"-key")
+
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", otherToken,
+		model.CreateRunRequest{AgentID: otherAgentID})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	defer func() { _ = createResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
 
-func TestHandleListConflictGroups_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?agent_id=test-agent", adminToken, nil)
+	var run struct {
+		Data model.AgentRun `json:"data"`
+	}
+	b, _ := io.ReadAll(createResp.Body)
+	require.NoError(t, json.Unmarshal(b, &run))
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+run.Data.ID.String()+"/events", agentToken,
+		model.AppendEventsRequest{
+			Events: []model.EventInput{{EventType: "test_event"}},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-func TestHandleListConflictGroups_WithConflictKindFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?conflict_kind=cross_agent", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleCompleteRun — agent cannot complete other's run
+// ===========================================================================
+
+func TestHandleCompleteRun_AgentCannotCompleteOtherAgentRun(t *testing.T) {
+	otherAgentID := "other-complete-agent-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Complete Agent", "agent", otherAgentID+"-key")
+	otherToken := getToken(testSrv.URL, otherAgentID, otherAgentID+"-key")
+
+	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", otherToken,
+		model.CreateRunRequest{AgentID: otherAgentID})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	defer func() { _ = createResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
 
-func TestHandleListConflictGroups_InvalidConflictKind(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflict-groups?conflict_kind=direct", adminToken, nil)
+	var run struct {
+		Data model.AgentRun `json:"data"`
+	}
+	b, _ := io.ReadAll(createResp.Body)
+	require.NoError(t, json.Unmarshal(b, &run))
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+run.Data.ID.String()+"/complete", agentToken,
+		model.CompleteRunRequest{Status: "completed"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleConflictAnalytics — filter and period paths
+// Coverage push: HandleSetRetention — exclude types without days
 // ===========================================================================
 
-func TestHandleConflictAnalytics_DefaultPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics", adminToken, nil)
+func TestHandleSetRetention_WithExcludeTypesAndNilDays(t *testing.T) {
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+		map[string]any{
+			"retention_exclude_types": []string{"security_audit", "compliance"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_30dPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=30d", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleGetRetention — response includes holds
+// ===========================================================================
+
+func TestHandleGetRetention_ResponseIncludesHolds(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Data map[string]any `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	_, hasHolds := result.Data["holds"]
+	assert.True(t, hasHolds, "response should include holds field")
+	_, hasRetentionDays := result.Data["retention_days"]
+	assert.True(t, hasRetentionDays, "response should include retention_days field")
 }
 
-func TestHandleConflictAnalytics_90dPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?period=90d", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleSignup — missing fields, invalid formats
+// ===========================================================================
+
+func TestSignup_MissingAgentID(t *testing.T) {
+	ts := signupServer(t)
+	body, _ := json.Marshal(model.SignupRequest{
+		OrgName: "Missing AgentID Org",
+		Email:   "missing-agent@test.com",
+	})
+	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_FromToRange(t *testing.T) {
-	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+func TestSignup_MissingEmail(t *testing.T) {
+	ts := signupServer(t)
+	body, _ := json.Marshal(model.SignupRequest{
+		OrgName: "Missing Email Org",
+		AgentID: "agentx",
+	})
+	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_ExceedsMaxRange(t *testing.T) {
-	from := time.Now().Add(-400 * 24 * time.Hour).UTC().Format(time.RFC3339)
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?from="+from+"&to="+to, adminToken, nil)
+func TestSignup_OrgNameOnlySpecialChars(t *testing.T) {
+	ts := signupServer(t)
+	body, _ := json.Marshal(model.SignupRequest{
+		OrgName: "!!!---",
+		AgentID: "slug-test-agent",
+		Email:   "slugtest@test.com",
+	})
+	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_WithAgentFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?agent_id=test-agent", adminToken, nil)
+func TestSignup_InvalidJSON(t *testing.T) {
+	ts := signupServer(t)
+	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", strings.NewReader("{bad json"))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?decision_type=architecture", adminToken, nil)
+func TestSignup_InvalidAgentIDFormat(t *testing.T) {
+	ts := signupServer(t)
+	body, _ := json.Marshal(model.SignupRequest{
+		OrgName: "Invalid AgentID Org",
+		AgentID: "INVALID AGENT ID!!",
+		Email:   "invalidagent@test.com",
+	})
+	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_WithConflictKindFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?conflict_kind=self_contradiction", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleCheck — empty body, malformed JSON, with query/project
+// ===========================================================================
+
+func TestHandleCheck_EmptyBody(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken,
+		model.CheckRequest{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleConflictAnalytics_InvalidConflictKind(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/analytics?conflict_kind=direct", adminToken, nil)
+func TestHandleCheck_MalformedJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/check", strings.NewReader("{bad json"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleGetUsage_ValidPastPeriod(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/usage?period=2025-01", adminToken, nil)
+func TestHandleCheck_WithQueryAndProject(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken,
+		model.CheckRequest{
+			DecisionType: "implementation",
+			Query:        "test check query",
+			Project:      "test-project",
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleAppendEvents_NonexistentRun(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+uuid.NewString()+"/events", agentToken,
-		model.AppendEventsRequest{Events: []model.EventInput{
-			{EventType: model.EventDecisionMade, Payload: map[string]any{"outcome": "x"}},
-		}})
+// ===========================================================================
+// Coverage push: HandleSearch — malformed JSON
+// ===========================================================================
+
+func TestHandleSearch_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/search", strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRun — bad UUID
+// Coverage push: HandleUpdateAgent — invalid agent_id, with metadata
 // ===========================================================================
 
-func TestHandleGetRun_BadUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/not-a-uuid", agentToken, nil)
+func TestHandleUpdateAgent_InvalidAgentID(t *testing.T) {
+	name := "updated name"
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID AGENT!!", adminToken,
+		model.UpdateAgentRequest{Name: &name})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleGetRun_NotFound(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+uuid.NewString(), agentToken, nil)
+func TestHandleUpdateAgent_WithMetadata(t *testing.T) {
+	meta := map[string]any{"team": "backend"}
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
+		model.UpdateAgentRequest{Metadata: meta})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandlePatchConflict — resolved + winner
+// Coverage push: HandleDecisionRevisions — nonexistent decision
 // ===========================================================================
 
-func TestHandlePatchConflict_WinnerOnNonResolved(t *testing.T) {
-	winnerID := uuid.NewString()
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+uuid.NewString(), adminToken,
-		map[string]any{"status": "false_positive", "winning_decision_id": winnerID})
+func TestHandleDecisionRevisions_NonexistentDecision(t *testing.T) {
+	fakeID := uuid.New().String()
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+fakeID+"/revisions", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// winning_decision_id is only valid when status is "resolved".
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data struct {
+			Count int `json:"count"`
+		} `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, 0, result.Data.Count)
 }
 
 // ===========================================================================
-// Coverage push: HandleDecisionConflicts — paths
+// Coverage push: HandleVerifyDecision — nonexistent
 // ===========================================================================
 
-func TestHandleDecisionConflicts_BadUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/conflicts", adminToken, nil)
+func TestHandleVerifyDecision_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleDecisionConflicts_NonexistentDecision(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/conflicts", adminToken, nil)
+// ===========================================================================
+// Coverage push: HandleDecisionsRecent — with filters
+// ===========================================================================
+
+func TestHandleDecisionsRecent_WithDecisionTypeFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?decision_type=nonexistent_type", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleDecisionConflicts_WithStatusFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/conflicts?status=open", adminToken, nil)
+func TestHandleDecisionsRecent_WithBothFilters(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?agent_id=test-agent&decision_type=implementation", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleListAssessments — paths
+// Coverage push: HandlePatchConflict — invalid JSON, missing/invalid status
 // ===========================================================================
 
-func TestHandleListAssessments_BadUUIDCov(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid/assessments", adminToken, nil)
+func TestHandlePatchConflict_InvalidJSON(t *testing.T) {
+	fakeID := uuid.New().String()
+	req, _ := http.NewRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleListAssessments_NonexistentDecisionCov(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.NewString()+"/assessments", adminToken, nil)
+func TestHandlePatchConflict_MissingStatus(t *testing.T) {
+	fakeID := uuid.New().String()
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, adminToken,
+		map[string]any{})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestHandlePatchConflict_InvalidStatus(t *testing.T) {
+	fakeID := uuid.New().String()
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, adminToken,
+		map[string]any{"status": "invalid_status"})
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleListGrants — pagination
+// Coverage push: HandleAdjudicateConflict — invalid JSON, nonexistent
 // ===========================================================================
 
-func TestHandleListGrants_WithPagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/grants?limit=10&offset=0", adminToken, nil)
+func TestHandleAdjudicateConflict_InvalidJSON(t *testing.T) {
+	fakeID := uuid.New().String()
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/conflicts/"+fakeID+"/adjudicate", strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleRetention — more paths
+// Coverage push: HandleGetConflict — nonexistent
 // ===========================================================================
 
-func TestHandleGetRetention_DefaultPolicy(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+func TestHandleGetConflict_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
-	var result struct {
-		Data struct {
-			RetentionDays *int `json:"retention_days"`
-		} `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAdjudicateConflict — outcome validation
+// Coverage push: HandleResolveConflictGroup — invalid JSON
 // ===========================================================================
 
-func TestHandleAdjudicateConflict_MissingOutcome(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/conflicts/"+uuid.NewString()+"/adjudicate",
-		adminToken, map[string]any{"reasoning": "some reasoning"})
+func TestHandleResolveConflictGroup_InvalidJSON(t *testing.T) {
+	fakeID := uuid.New().String()
+	req, _ := http.NewRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+fakeID+"/resolve", strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetAgent — paths
+// Coverage push: HandleSessionView — nonexistent session
 // ===========================================================================
 
-func TestHandleGetAgent_Success(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/test-agent", adminToken, nil)
+func TestHandleSessionView_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
-
 	var result struct {
 		Data struct {
-			AgentID string `json:"agent_id"`
+			DecisionCount int `json:"decision_count"`
 		} `json:"data"`
 	}
 	b, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "test-agent", result.Data.AgentID)
+	assert.Equal(t, 0, result.Data.DecisionCount)
 }
 
 // ===========================================================================
-// Coverage push: HandleOpenAPISpec
+// Coverage push: HandleUpdateAgentTags — invalid agent_id
 // ===========================================================================
 
-func TestHandleOpenAPISpec_ReturnsYAML(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/openapi.yaml", agentToken, nil)
+func TestHandleUpdateAgentTags_InvalidAgentID(t *testing.T) {
+	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!/tags", adminToken,
+		map[string]any{"tags": []string{"tag1"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// May return 200 (with spec) or 404 (no spec embedded in test build).
-	assert.Contains(t, []int{http.StatusOK, http.StatusNotFound}, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleListAgents — paths
+// Coverage push: HandleCreateAgent — invalid JSON, missing/invalid agent_id
 // ===========================================================================
 
-func TestHandleListAgents_WithPagination(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?limit=5&offset=0", adminToken, nil)
+func TestHandleCreateAgent_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/agents", strings.NewReader("{bad json"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleListAgents_WithRoleFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents?role=agent", adminToken, nil)
+func TestHandleCreateAgent_MissingAgentID(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{Name: "No ID", Role: model.RoleAgent})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTemporalQuery_ValidQuery(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
-		map[string]any{
-			"as_of": time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339),
-		})
+func TestHandleCreateAgent_InvalidAgentIDFormat(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
+		model.CreateAgentRequest{AgentID: "INVALID ID!", Name: "Bad", Role: model.RoleAgent})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleSubscribe — nil broker
+// Coverage push: HandleDecisionConflicts — nonexistent decision
 // ===========================================================================
 
-func TestHandleSubscribe_NilBroker(t *testing.T) {
-	// The test server has no broker configured, so /v1/subscribe should return 503.
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/subscribe", agentToken, nil)
+func TestHandleDecisionConflicts_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/conflicts", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleScopedToken — paths
+// Coverage push: HandleListConflicts — combined filters
 // ===========================================================================
 
-func TestHandleScopedToken_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/auth/scoped-token", strings.NewReader("bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.NotEqual(t, http.StatusOK, resp.StatusCode)
-}
-
-func TestHandleExportDecisions_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?decision_type=architecture", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
-
-func TestHandleExportDecisions_InvalidFromDate(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from=not-a-date", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-func TestHandleExportDecisions_InvalidToDate(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?to=not-a-date", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-func TestHandleExportDecisions_WithFromAndTo(t *testing.T) {
-	from := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?from="+from+"&to="+to, adminToken, nil)
+func TestHandleListConflicts_WithProjectFilter(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?project=my-project", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleExportDecisions_ToOnly(t *testing.T) {
-	to := time.Now().UTC().Format(time.RFC3339)
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?to="+to, adminToken, nil)
+func TestHandleListConflicts_CombinedFilters(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/conflicts?status=open&severity=high&decision_type=implementation&agent_id=test-agent&project=my-project&limit=10&offset=0",
+		adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCreateKey — with expires_at
-// ===========================================================================
-
-func TestHandleCreateKey_WithLabel(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/keys", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"label":    "ci-key",
-		})
+// Coverage push: HandleCreateRun — agent cannot create for others, invalid
+// ===========================================================================
+
+func TestHandleCreateRun_AgentCannotCreateForOther(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "some-other-agent"})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAuthToken — invalid credentials
+// ===========================================================================
+// Coverage push: HandleGetDecision — invalid UUID, nonexistent
 // ===========================================================================
 
-func TestHandleAuthToken_WrongAPIKey(t *testing.T) {
-	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "test-agent", APIKey: "wrong-key"})
-	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+func TestHandleGetDecision_InvalidUUID(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleAuthToken_NonexistentAgent(t *testing.T) {
-	body, _ := json.Marshal(model.AuthTokenRequest{AgentID: "nonexistent-auth-agent", APIKey: "some-key"})
-	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+func TestHandleGetDecision_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleAuthToken_MissingFields(t *testing.T) {
-	body, _ := json.Marshal(model.AuthTokenRequest{})
-	resp, err := http.Post(testSrv.URL+"/auth/token", "application/json", bytes.NewReader(body))
+// ===========================================================================
+// Coverage push: HandleDeleteGrant — nonexistent
+// ===========================================================================
+
+func TestHandleDeleteGrant_Nonexistent(t *testing.T) {
+	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.New().String(), nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	// Empty credentials are treated as invalid auth, not a validation error.
-	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleTemporalQuery — agent_ids and decision_type filters
+// Coverage push: HandleMCPInfo — returns expected fields
 // ===========================================================================
 
-func TestHandleTemporalQuery_WithAgentIDsFilter(t *testing.T) {
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: "temporal-agent-filter-test",
-				Outcome:      "use agent filter",
-				Confidence:   0.8,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleMCPInfo(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
 	require.NoError(t, err)
-	_ = traceResp.Body.Close()
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
-	pastTime := time.Now().Add(-1 * time.Second)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
-		model.TemporalQueryRequest{
-			AsOf: pastTime,
-			Filters: model.QueryFilters{
-				AgentIDs: []string{"test-agent"},
-			},
-		})
+	var result struct {
+		Data model.MCPInfoResponse `json:"data"`
+	}
+	b, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, "streamable-http", result.Data.Transport)
+	assert.Equal(t, "ApiKey", result.Data.Auth.Preferred)
+	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
+}
+
+// ===========================================================================
+// Coverage push: HandleConfig — returns expected fields
+// ===========================================================================
+
+func TestHandleConfig(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/config")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var result struct {
-		Data struct {
-			AsOf      time.Time        `json:"as_of"`
-			Decisions []model.Decision `json:"decisions"`
-		} `json:"data"`
+		Data map[string]bool `json:"data"`
 	}
 	b, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(b, &result))
-	assert.False(t, result.Data.AsOf.IsZero())
+	_, hasSearchEnabled := result.Data["search_enabled"]
+	assert.True(t, hasSearchEnabled)
 }
 
-func TestHandleTemporalQuery_WithDecisionTypeFilter(t *testing.T) {
-	pastTime := time.Now().Add(-1 * time.Second)
-	dt := "temporal-agent-filter-test"
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/query/temporal", adminToken,
-		model.TemporalQueryRequest{
-			AsOf: pastTime,
-			Filters: model.QueryFilters{
-				DecisionType: &dt,
-			},
-		})
+// ===========================================================================
+// Coverage push: API key auth via ApiKey header
+// ===========================================================================
+
+func TestAPIKeyAuth_ValidCredentials(t *testing.T) {
+	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
+	req.Header.Set("Authorization", "ApiKey test-agent:test-agent-key")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
-func TestHandleTemporalQuery_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/query/temporal", strings.NewReader("{bad json"))
+// ===========================================================================
+// Coverage push: HandleListAssessments — nonexistent decision
+// ===========================================================================
+
+func TestHandleListAssessments_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assessments", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// ===========================================================================
+// Coverage push: HandleCreateKey — invalid JSON, missing agent_id
+// ===========================================================================
+
+func TestHandleCreateKey_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/keys", strings.NewReader("{bad json"))
 	req.Header.Set("Authorization", "Bearer "+adminToken)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := http.DefaultClient.Do(req)
@@ -10244,761 +12261,1076 @@ func TestHandleTemporalQuery_InvalidJSON(t *testing.T) {
 }
 
 // ===========================================================================
-// Coverage push: HandleTrace — forbidden, agent auto-registration, validation
+// Coverage push: HandleDeleteAgent — invalid agent_id, nonexistent
 // ===========================================================================
 
-func TestHandleTrace_NonexistentAgentForNonAdmin(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "nonexistent-agent-xyz",
-			Decision: model.TraceDecision{
-				DecisionType: "test",
-				Outcome:      "test",
-				Confidence:   0.5,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleDeleteAgent_InvalidAgentID(t *testing.T) {
+	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/agents/INVALID!!!", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_AdminTracesNonexistentAgent(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "auto-reg-agent-" + uuid.New().String()[:8],
-			Decision: model.TraceDecision{
-				DecisionType: "auto-reg-test",
-				Outcome:      "auto registered",
-				Confidence:   0.7,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleDeleteAgent_Nonexistent(t *testing.T) {
+	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/agents/nonexistent-delete-agent", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
-func TestHandleTrace_DecisionTypeOverMaxLen(t *testing.T) {
-	longType := strings.Repeat("x", 300)
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: longType,
-				Outcome:      "chosen",
-				Confidence:   0.5,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+// ===========================================================================
+// Coverage push: HandleCreateHold — invalid JSON
+// ===========================================================================
+
+func TestHandleCreateHold_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/retention/hold", strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleGetRun — reader forbidden without grant
+// Coverage push: HandleReleaseHold — nonexistent
 // ===========================================================================
 
-func TestHandleGetRun_ReaderForbiddenWithoutGrant(t *testing.T) {
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+func TestHandleReleaseHold_Nonexistent(t *testing.T) {
+	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+uuid.New().String(), nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	defer func() { _ = createResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
-
-	var run struct {
-		Data model.AgentRun `json:"data"`
-	}
-	b, _ := io.ReadAll(createResp.Body)
-	require.NoError(t, json.Unmarshal(b, &run))
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	readerID := "reader-run-forbidden-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, readerID, "Reader No Access", "reader", readerID+"-key")
-	rToken := getToken(testSrv.URL, readerID, readerID+"-key")
+// ===========================================================================
+// Coverage push: HandleListConflictGroups — conflict_kind filter
+// ===========================================================================
 
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/runs/"+run.Data.ID.String(), rToken, nil)
+func TestHandleListConflictGroups_WithConflictKind(t *testing.T) {
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/conflict-groups?conflict_kind=cross_agent",
+		adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleAppendEvents — agent cannot append to other's run
+// Coverage push: HandleGetAgent — nonexistent
 // ===========================================================================
 
-func TestHandleAppendEvents_AgentCannotAppendToOtherAgentRun(t *testing.T) {
-	otherAgentID := "other-run-agent-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Agent", "agent", otherAgentID+"-key")
-	otherToken := getToken(testSrv.URL, otherAgentID, otherAgentID+"-key")
+func TestHandleGetAgent_Nonexistent(t *testing.T) {
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-get-agent", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
 
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", otherToken,
-		model.CreateRunRequest{AgentID: otherAgentID})
+// ===========================================================================
+// Coverage push: HandleCreateGrant — invalid JSON
+// ===========================================================================
+
+func TestHandleCreateGrant_InvalidJSON(t *testing.T) {
+	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/grants", strings.NewReader("{bad"))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
-	defer func() { _ = createResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
 
-	var run struct {
-		Data model.AgentRun `json:"data"`
-	}
-	b, _ := io.ReadAll(createResp.Body)
-	require.NoError(t, json.Unmarshal(b, &run))
+// ===========================================================================
+// Coverage push: HandleOpenAPISpec — not configured
+// ===========================================================================
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+run.Data.ID.String()+"/events", agentToken,
-		model.AppendEventsRequest{
-			Events: []model.EventInput{{EventType: "test_event"}},
-		})
+func TestHandleOpenAPISpec_NotConfigured(t *testing.T) {
+	resp, err := http.Get(testSrv.URL + "/openapi.yaml")
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
 // ===========================================================================
-// Coverage push: HandleCompleteRun — agent cannot complete other's run
+// X-Model header: server-inferred model in agent_context
 // ===========================================================================
 
-func TestHandleCompleteRun_AgentCannotCompleteOtherAgentRun(t *testing.T) {
-	otherAgentID := "other-complete-agent-" + uuid.New().String()[:8]
-	createAgent(testSrv.URL, adminToken, otherAgentID, "Other Complete Agent", "agent", otherAgentID+"-key")
-	otherToken := getToken(testSrv.URL, otherAgentID, otherAgentID+"-key")
+func TestHandleTrace_XModelHeader(t *testing.T) {
+	// POST trace with X-Model header, no explicit model in body.
+	traceResp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "x-model-test",
+			"outcome":       "test model header inference",
+			"confidence":    0.8,
+		},
+		"context": map[string]any{"project": "test-project"},
+	}, map[string]string{
+		"X-Model": "claude-opus-4-6",
+	})
+	require.NoError(t, err)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-	createResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", otherToken,
-		model.CreateRunRequest{AgentID: otherAgentID})
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+	require.NotEqual(t, uuid.Nil, decisionID)
+
+	// GET the decision and verify the model was persisted from the header.
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = createResp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	defer func() { _ = getResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
 
-	var run struct {
-		Data model.AgentRun `json:"data"`
+	var result struct {
+		Data model.Decision `json:"data"`
 	}
-	b, _ := io.ReadAll(createResp.Body)
-	require.NoError(t, json.Unmarshal(b, &run))
+	body, _ := io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotNil(t, result.Data.Model, "model should be populated from X-Model header")
+	assert.Equal(t, "claude-opus-4-6", *result.Data.Model)
+}
 
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+run.Data.ID.String()+"/complete", agentToken,
-		model.CompleteRunRequest{Status: "completed"})
+func TestHandleTrace_ExplicitModelTakesPriorityOverXModelHeader(t *testing.T) {
+	// POST trace with both explicit model in body and X-Model header.
+	traceResp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
+		"agent_id": "admin",
+		"decision": map[string]any{
+			"decision_type": "model-priority-test",
+			"outcome":       "explicit model should win over header",
+			"confidence":    0.8,
+		},
+		"context": map[string]any{
+			"model":   "gpt-4o",
+			"project": "test-project",
+		},
+	}, map[string]string{
+		"X-Model": "claude-opus-4-6",
+	})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+
+	var traceResult struct {
+		Data struct {
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	traceBody, _ := io.ReadAll(traceResp.Body)
+	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	decisionID := traceResult.Data.DecisionID
+	require.NotEqual(t, uuid.Nil, decisionID)
+
+	// GET the decision and verify the explicit model won over the header.
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = getResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var result struct {
+		Data model.Decision `json:"data"`
+	}
+	body, _ := io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotNil(t, result.Data.Model, "model should be populated")
+	assert.Equal(t, "gpt-4o", *result.Data.Model, "explicit model in body must take priority over X-Model header")
 }
 
 // ===========================================================================
-// Coverage push: HandleSetRetention — exclude types without days
+// Project normalization: workspace names resolved to canonical repo names
 // ===========================================================================
 
-func TestHandleSetRetention_WithExcludeTypesAndNilDays(t *testing.T) {
-	resp, err := authedRequest("PUT", testSrv.URL+"/v1/retention", adminToken,
+func TestHandleTrace_ProjectNormalizedFromRepoURL(t *testing.T) {
+	// When the client sends a workspace name as the project but also includes
+	// a repo_url in context, the server should normalize to the canonical repo name.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
 		map[string]any{
-			"retention_exclude_types": []string{"security_audit", "compliance"},
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "traced with workspace name and repo_url",
+				"confidence":    0.7,
+			},
+			"context": map[string]any{
+				"project":  "cairo-v1",
+				"repo_url": "https://github.com/ashita-ai/akashi.git",
+			},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleGetRetention — response includes holds
-// ===========================================================================
+	var traceResp struct {
+		Data struct {
+			DecisionID string `json:"decision_id"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &traceResp))
+	require.NotEmpty(t, traceResp.Data.DecisionID)
 
-func TestHandleGetRetention_ResponseIncludesHolds(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/retention", adminToken, nil)
+	// Fetch the decision and verify the project was normalized.
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResp.Data.DecisionID, adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	defer func() { _ = getResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
 
 	var result struct {
-		Data map[string]any `json:"data"`
+		Data model.Decision `json:"data"`
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	_, hasHolds := result.Data["holds"]
-	assert.True(t, hasHolds, "response should include holds field")
-	_, hasRetentionDays := result.Data["retention_days"]
-	assert.True(t, hasRetentionDays, "response should include retention_days field")
+	body, _ = io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotNil(t, result.Data.Project, "project should be set")
+	assert.Equal(t, "akashi", *result.Data.Project, "workspace name should be normalized to repo name via repo_url")
 }
 
-// ===========================================================================
-// Coverage push: HandleSignup — missing fields, invalid formats
-// ===========================================================================
-
-func TestSignup_MissingAgentID(t *testing.T) {
-	ts := signupServer(t)
-	body, _ := json.Marshal(model.SignupRequest{
-		OrgName: "Missing AgentID Org",
-		Email:   "missing-agent@test.com",
-	})
-	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
+func TestHandleTrace_ProjectUnchangedWhenCanonical(t *testing.T) {
+	// When the client sends a proper project name, it should be preserved as-is.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "traced with canonical project",
+				"confidence":    0.7,
+			},
+			"context": map[string]any{
+				"project": "akashi",
+			},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-func TestSignup_MissingEmail(t *testing.T) {
-	ts := signupServer(t)
-	body, _ := json.Marshal(model.SignupRequest{
-		OrgName: "Missing Email Org",
-		AgentID: "agentx",
-	})
-	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	var traceResp struct {
+		Data struct {
+			DecisionID string `json:"decision_id"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	require.NoError(t, json.Unmarshal(body, &traceResp))
+	require.NotEmpty(t, traceResp.Data.DecisionID)
 
-func TestSignup_OrgNameOnlySpecialChars(t *testing.T) {
-	ts := signupServer(t)
-	body, _ := json.Marshal(model.SignupRequest{
-		OrgName: "!!!---",
-		AgentID: "slug-test-agent",
-		Email:   "slugtest@test.com",
-	})
-	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResp.Data.DecisionID, adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	defer func() { _ = getResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var result struct {
+		Data model.Decision `json:"data"`
+	}
+	body, _ = io.ReadAll(getResp.Body)
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotNil(t, result.Data.Project)
+	assert.Equal(t, "akashi", *result.Data.Project)
 }
 
-func TestSignup_InvalidJSON(t *testing.T) {
-	ts := signupServer(t)
-	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", strings.NewReader("{bad json"))
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+func TestHandleTrace_WorkspaceNamesSameRepoGetSameProject(t *testing.T) {
+	// Two decisions traced with different workspace names but the same repo_url
+	// should both resolve to the same canonical project name, making them
+	// eligible for conflict comparison.
+	repoURL := "https://github.com/ashita-ai/akashi.git"
+	workspaceNames := []string{"dubai", "bamako"}
+	var decisionIDs []string
+
+	for _, ws := range workspaceNames {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+			map[string]any{
+				"agent_id": "test-agent",
+				"decision": map[string]any{
+					"decision_type": "architecture",
+					"outcome":       "decision from workspace " + ws,
+					"confidence":    0.7,
+				},
+				"context": map[string]any{
+					"project":  ws,
+					"repo_url": repoURL,
+				},
+			})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var traceResp struct {
+			Data struct {
+				DecisionID string `json:"decision_id"`
+			} `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &traceResp))
+		require.NotEmpty(t, traceResp.Data.DecisionID)
+		decisionIDs = append(decisionIDs, traceResp.Data.DecisionID)
+	}
+
+	// Verify both decisions have the same canonical project name.
+	for i, id := range decisionIDs {
+		getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+id, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = getResp.Body.Close() }()
+		require.Equal(t, http.StatusOK, getResp.StatusCode)
 
-func TestSignup_InvalidAgentIDFormat(t *testing.T) {
-	ts := signupServer(t)
-	body, _ := json.Marshal(model.SignupRequest{
-		OrgName: "Invalid AgentID Org",
-		AgentID: "INVALID AGENT ID!!",
-		Email:   "invalidagent@test.com",
-	})
-	resp, err := http.Post(ts.URL+"/auth/signup", "application/json", bytes.NewReader(body))
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(getResp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		require.NotNil(t, result.Data.Project, "decision %d should have project set", i)
+		assert.Equal(t, "akashi", *result.Data.Project,
+			"workspace %q should be normalized to 'akashi' via repo_url", workspaceNames[i])
+	}
 }
 
 // ===========================================================================
-// Coverage push: HandleCheck — empty body, malformed JSON, with query/project
+// High-confidence warning in trace response (#468)
 // ===========================================================================
 
-func TestHandleCheck_EmptyBody(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken,
-		model.CheckRequest{})
+func TestHandleTrace_HighConfNoEvidence_ReturnsWarning(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "chose Postgres for high-confidence-warning test",
+				"confidence":    0.9,
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-func TestHandleCheck_MalformedJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/check", strings.NewReader("{bad json"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var body struct {
+		Data struct {
+			Warnings []string `json:"warnings"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Data.Warnings, 1, "expected one warning for high confidence without evidence")
+	assert.Contains(t, body.Data.Warnings[0], "high confidence")
 }
 
-func TestHandleCheck_WithQueryAndProject(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/check", adminToken,
-		model.CheckRequest{
-			DecisionType: "implementation",
-			Query:        "test check query",
-			Project:      "test-project",
+func TestHandleTrace_HighConfWithEvidence_NoWarning(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "architecture",
+				"outcome":       "chose Postgres with evidence",
+				"confidence":    0.9,
+				"evidence": []map[string]any{
+					{"source_type": "document", "content": "benchmark results showing 10x throughput"},
+				},
+			},
+			"context": map[string]any{"project": "test-project"},
 		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleSearch — malformed JSON
-// ===========================================================================
+	body, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(body), "warnings", "no warning expected when evidence is present")
+}
 
-func TestHandleSearch_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/search", strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+func TestHandleTrace_LowConfNoEvidence_NoWarning(t *testing.T) {
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
+		map[string]any{
+			"agent_id": "test-agent",
+			"decision": map[string]any{
+				"decision_type": "implementation",
+				"outcome":       "low confidence decision",
+				"confidence":    0.7,
+			},
+			"context": map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(body), "warnings", "no warning expected for confidence below threshold")
 }
 
 // ===========================================================================
-// Coverage push: HandleUpdateAgent — invalid agent_id, with metadata
+// HandleGetRun — enrichments via ?include=enrichments
 // ===========================================================================
 
-func TestHandleUpdateAgent_InvalidAgentID(t *testing.T) {
-	name := "updated name"
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID AGENT!!", adminToken,
-		model.UpdateAgentRequest{Name: &name})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-func TestHandleUpdateAgent_WithMetadata(t *testing.T) {
-	meta := map[string]any{"team": "backend"}
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/test-agent", adminToken,
-		model.UpdateAgentRequest{Metadata: meta})
+func TestHandleGetRun_EnrichmentsBasic(t *testing.T) {
+	// Trace a decision (auto-creates a run).
+	reasoning := "integration test reasoning"
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "enrichment_test",
+				Outcome:      "chose enrichments",
+				Confidence:   0.85,
+				Reasoning:    &reasoning,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleDecisionRevisions — nonexistent decision
-// ===========================================================================
+	var traceResult struct {
+		Data struct {
+			RunID      uuid.UUID `json:"run_id"`
+			DecisionID uuid.UUID `json:"decision_id"`
+		} `json:"data"`
+	}
+	tb, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(tb, &traceResult))
+	runID := traceResult.Data.RunID
 
-func TestHandleDecisionRevisions_NonexistentDecision(t *testing.T) {
-	fakeID := uuid.New().String()
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+fakeID+"/revisions", adminToken, nil)
+	// GET with enrichments.
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/runs/"+runID.String()+"?include=enrichments",
+		agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
 	var result struct {
 		Data struct {
-			Count int `json:"count"`
+			Run                 json.RawMessage            `json:"run"`
+			Decisions           []json.RawMessage          `json:"decisions"`
+			DecisionEnrichments map[string]json.RawMessage `json:"decision_enrichments"`
 		} `json:"data"`
 	}
 	b, _ := io.ReadAll(resp.Body)
 	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, 0, result.Data.Count)
-}
-
-// ===========================================================================
-// Coverage push: HandleVerifyDecision — nonexistent
-// ===========================================================================
-
-func TestHandleVerifyDecision_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/verify/"+uuid.New().String(), adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
-
-// ===========================================================================
-// Coverage push: HandleDecisionsRecent — with filters
-// ===========================================================================
-
-func TestHandleDecisionsRecent_WithDecisionTypeFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?decision_type=nonexistent_type", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
-
-func TestHandleDecisionsRecent_WithBothFilters(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent?agent_id=test-agent&decision_type=implementation", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
 
-// ===========================================================================
-// Coverage push: HandlePatchConflict — invalid JSON, missing/invalid status
-// ===========================================================================
+	// Must have at least one decision and a matching enrichment.
+	require.GreaterOrEqual(t, len(result.Data.Decisions), 1, "run should contain at least one decision")
+	require.NotNil(t, result.Data.DecisionEnrichments, "enrichments map must be present")
 
-func TestHandlePatchConflict_InvalidJSON(t *testing.T) {
-	fakeID := uuid.New().String()
-	req, _ := http.NewRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	// Find our decision in the enrichments.
+	decID := traceResult.Data.DecisionID.String()
+	rawEnrichment, ok := result.Data.DecisionEnrichments[decID]
+	require.True(t, ok, "enrichment must exist for traced decision %s", decID)
 
-func TestHandlePatchConflict_MissingStatus(t *testing.T) {
-	fakeID := uuid.New().String()
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, adminToken,
-		map[string]any{})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	// Parse and validate the enrichment structure.
+	var enrichment struct {
+		Revisions struct {
+			Items    []json.RawMessage `json:"items"`
+			Count    int               `json:"count"`
+			Degraded bool              `json:"degraded"`
+		} `json:"revisions"`
+		Lineage struct {
+			DecisionID string `json:"decision_id"`
+		} `json:"lineage"`
+		Conflicts struct {
+			Items   []json.RawMessage `json:"items"`
+			Count   int               `json:"count"`
+			HasMore bool              `json:"has_more"`
+		} `json:"conflicts"`
+		Integrity struct {
+			Status      string `json:"status"`
+			ContentHash string `json:"content_hash"`
+		} `json:"integrity"`
+	}
+	require.NoError(t, json.Unmarshal(rawEnrichment, &enrichment))
 
-func TestHandlePatchConflict_InvalidStatus(t *testing.T) {
-	fakeID := uuid.New().String()
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/conflicts/"+fakeID, adminToken,
-		map[string]any{"status": "invalid_status"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	// Revisions: at least the decision itself.
+	assert.GreaterOrEqual(t, enrichment.Revisions.Count, 1, "revision chain should include at least the original decision")
+	assert.False(t, enrichment.Revisions.Degraded, "revisions should not be degraded")
 
-// ===========================================================================
-// Coverage push: HandleAdjudicateConflict — invalid JSON, nonexistent
-// ===========================================================================
+	// Lineage: decision_id should match.
+	assert.Equal(t, decID, enrichment.Lineage.DecisionID)
 
-func TestHandleAdjudicateConflict_InvalidJSON(t *testing.T) {
-	fakeID := uuid.New().String()
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/conflicts/"+fakeID+"/adjudicate", strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	// Conflicts: no overflow expected for a single fresh decision.
+	assert.False(t, enrichment.Conflicts.HasMore)
 
-// ===========================================================================
-// Coverage push: HandleGetConflict — nonexistent
-// ===========================================================================
+	// Integrity: decision was traced with content hash.
+	assert.Contains(t, []string{"verified", "no_hash"}, enrichment.Integrity.Status,
+		"integrity status should be verified or no_hash")
+	if enrichment.Integrity.Status == "verified" {
+		assert.NotEmpty(t, enrichment.Integrity.ContentHash,
+			"verified status must include the content_hash")
+	}
+}
 
-func TestHandleGetConflict_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts/"+uuid.New().String(), adminToken, nil)
+func TestHandleGetRun_NoEnrichmentsWithoutParam(t *testing.T) {
+	// Trace a decision to get a run.
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "no_enrichment_test",
+				Outcome:      "should not enrich",
+				Confidence:   0.5,
+			},
+			Context: map[string]any{"project": "test-project"},
+		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleResolveConflictGroup — invalid JSON
-// ===========================================================================
+	var traceResult struct {
+		Data struct {
+			RunID uuid.UUID `json:"run_id"`
+		} `json:"data"`
+	}
+	tb, _ := io.ReadAll(traceResp.Body)
+	_ = traceResp.Body.Close()
+	require.NoError(t, json.Unmarshal(tb, &traceResult))
 
-func TestHandleResolveConflictGroup_InvalidJSON(t *testing.T) {
-	fakeID := uuid.New().String()
-	req, _ := http.NewRequest("PATCH", testSrv.URL+"/v1/conflict-groups/"+fakeID+"/resolve", strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	// GET without ?include=enrichments.
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/runs/"+traceResult.Data.RunID.String(),
+		agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleSessionView — nonexistent session
-// ===========================================================================
+	b, _ := io.ReadAll(resp.Body)
+	// The response should NOT contain decision_enrichments.
+	assert.NotContains(t, string(b), "decision_enrichments",
+		"enrichments should not appear without ?include=enrichments")
+}
 
-func TestHandleSessionView_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/sessions/"+uuid.New().String(), adminToken, nil)
+func TestHandleGetRun_EnrichmentsEmptyRun(t *testing.T) {
+	// Create a run with no decisions.
+	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
+		model.CreateRunRequest{AgentID: "test-agent"})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	var result struct {
+	require.Equal(t, http.StatusCreated, runResp.StatusCode)
+
+	var runResult struct {
 		Data struct {
-			DecisionCount int `json:"decision_count"`
+			ID uuid.UUID `json:"id"`
 		} `json:"data"`
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, 0, result.Data.DecisionCount)
-}
-
-// ===========================================================================
-// Coverage push: HandleUpdateAgentTags — invalid agent_id
-// ===========================================================================
+	rb, _ := io.ReadAll(runResp.Body)
+	_ = runResp.Body.Close()
+	require.NoError(t, json.Unmarshal(rb, &runResult))
 
-func TestHandleUpdateAgentTags_InvalidAgentID(t *testing.T) {
-	resp, err := authedRequest("PATCH", testSrv.URL+"/v1/agents/INVALID!!!/tags", adminToken,
-		map[string]any{"tags": []string{"tag1"}})
+	// GET with ?include=enrichments on a run with zero decisions.
+	resp, err := authedRequest("GET",
+		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"?include=enrichments",
+		agentToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	b, _ := io.ReadAll(resp.Body)
+	// No enrichments when there are no decisions.
+	assert.NotContains(t, string(b), "decision_enrichments",
+		"enrichments should not appear when run has no decisions")
 }
 
-// ===========================================================================
-// Coverage push: HandleCreateAgent — invalid JSON, missing/invalid agent_id
-// ===========================================================================
+// ---- HandleSetOrgSettings audit trail ------------------------------------
 
-func TestHandleCreateAgent_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/agents", strings.NewReader("{bad json"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+func TestHandleSetOrgSettings_AuditTrail(t *testing.T) {
+	orgID := uuid.Nil // default org from SeedAdmin
 
-func TestHandleCreateAgent_MissingAgentID(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{Name: "No ID", Role: model.RoleAgent})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	// Count existing audit entries so we can assert on the delta.
+	var baseline int
+	require.NoError(t, testDB.Pool().QueryRow(context.Background(),
+		`SELECT count(*) FROM mutation_audit_log
+		 WHERE operation = 'org_settings_updated'
+		   AND resource_type = 'org_settings'
+		   AND org_id = $1`, orgID,
+	).Scan(&baseline))
 
-func TestHandleCreateAgent_InvalidAgentIDFormat(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/agents", adminToken,
-		model.CreateAgentRequest{AgentID: "INVALID ID!", Name: "Bad", Role: model.RoleAgent})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	t.Run("upsert writes mutation audit entry", func(t *testing.T) {
+		settings := model.OrgSettingsData{
+			ConflictResolution: &model.ConflictResolutionPolicy{
+				AutoResolveAfterDays:   7,
+				AutoResolveWinner:      model.WinnerRecency,
+				AutoResolveMaxSeverity: "medium",
+			},
+		}
 
-// ===========================================================================
-// Coverage push: HandleDecisionConflicts — nonexistent decision
-// ===========================================================================
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-func TestHandleDecisionConflicts_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/conflicts", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+		// Verify a mutation audit row was written for this operation.
+		var count int
+		err = testDB.Pool().QueryRow(context.Background(),
+			`SELECT count(*) FROM mutation_audit_log
+			 WHERE operation = 'org_settings_updated'
+			   AND resource_type = 'org_settings'
+			   AND org_id = $1`, orgID,
+		).Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, baseline+1, count, "expected one new audit entry for org_settings_updated")
+	})
+
+	t.Run("second upsert captures before-state", func(t *testing.T) {
+		// Second write — should capture the first settings as before_data.
+		second := model.OrgSettingsData{
+			ConflictResolution: &model.ConflictResolutionPolicy{
+				AutoResolveAfterDays:   14,
+				AutoResolveWinner:      model.WinnerConfidence,
+				AutoResolveMaxSeverity: "high",
+			},
+		}
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, second)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// Find the most recent audit entry and verify before_data is non-null.
+		var beforeData, afterData []byte
+		err = testDB.Pool().QueryRow(context.Background(),
+			`SELECT before_data, after_data FROM mutation_audit_log
+			 WHERE operation = 'org_settings_updated'
+			   AND resource_type = 'org_settings'
+			   AND org_id = $1
+			 ORDER BY occurred_at DESC LIMIT 1`, orgID,
+		).Scan(&beforeData, &afterData)
+		require.NoError(t, err)
+		assert.NotNil(t, beforeData, "second upsert should capture before_data")
+		assert.NotNil(t, afterData, "second upsert should capture after_data")
+
+		// Verify the before_data contains the previous settings.
+		// PostgreSQL jsonb canonical form uses spaces after colons.
+		assert.Contains(t, string(beforeData), `"auto_resolve_after_days": 7`,
+			"before_data should reflect the first settings write")
+		// Verify the after_data contains the new settings.
+		assert.Contains(t, string(afterData), `"auto_resolve_after_days": 14`,
+			"after_data should reflect the second settings write")
+	})
 }
 
-// ===========================================================================
-// Coverage push: HandleListConflicts — combined filters
-// ===========================================================================
+func TestHandleExportDecisions_FullHistory(t *testing.T) {
+	decisionType := "export_history_" + uuid.New().String()[:8]
+
+	trace := func(outcome string, supersedes *uuid.UUID) uuid.UUID {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID:      "admin",
+			SupersedesID: supersedes,
+			Decision: model.TraceDecision{
+				DecisionType: decisionType,
+				Outcome:      outcome,
+				Confidence:   0.7,
+			},
+		})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode, "trace %q", outcome)
+		var result struct {
+			Data struct {
+				DecisionID uuid.UUID `json:"decision_id"`
+			} `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(b, &result))
+		return result.Data.DecisionID
+	}
 
-func TestHandleListConflicts_WithProjectFilter(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?project=my-project", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	v1 := trace("v1", nil)
+	v2 := trace("v2", &v1)
+	v3 := trace("v3", &v2)
+	standalone := trace("standalone", nil)
+	require.NoError(t, testBuf.FlushNow(context.Background()))
 
-func TestHandleListConflicts_CombinedFilters(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/conflicts?status=open&severity=high&decision_type=implementation&agent_id=test-agent&project=my-project&limit=10&offset=0",
-		adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-}
+	export := func(mode string) [][]byte {
+		url := testSrv.URL + "/v1/export/decisions?decision_type=" + decisionType
+		if mode != "" {
+			url += "&export_mode=" + mode
+		}
+		resp, err := authedRequest("GET", url, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		return bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+	}
+
+	t.Run("heads is the default and omits history", func(t *testing.T) {
+		for _, mode := range []string{"", "heads"} {
+			lines := export(mode)
+			require.Len(t, lines, 2, "mode %q: only chain heads are exported", mode)
+			for _, line := range lines {
+				var raw map[string]any
+				require.NoError(t, json.Unmarshal(line, &raw))
+				assert.NotContains(t, raw, "revisions")
+				assert.NotEqual(t, v1.String(), raw["id"])
+				assert.NotEqual(t, v2.String(), raw["id"])
+			}
+		}
+	})
 
-// ===========================================================================
-// Coverage push: HandleCreateRun — agent cannot create for others, invalid
-// ===========================================================================
+	t.Run("full_history nests prior revisions oldest first", func(t *testing.T) {
+		lines := export("full_history")
+		require.Len(t, lines, 2)
 
-func TestHandleCreateRun_AgentCannotCreateForOther(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "some-other-agent"})
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
+		byID := map[uuid.UUID]exportHistoryRecordJSON{}
+		for _, line := range lines {
+			var rec exportHistoryRecordJSON
+			require.NoError(t, json.Unmarshal(line, &rec))
+			require.NotNil(t, rec.Revisions, "revisions is always an array")
+			byID[rec.ID] = rec
+		}
 
-// ===========================================================================
-// ===========================================================================
-// Coverage push: HandleGetDecision — invalid UUID, nonexistent
-// ===========================================================================
+		head := byID[v3]
+		assert.Equal(t, "v3", head.Outcome)
+		require.Len(t, head.Revisions, 2)
+		assert.Equal(t, v1, head.Revisions[0].ID)
+		assert.Equal(t, v2, head.Revisions[1].ID)
 
-func TestHandleGetDecision_InvalidUUID(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/not-a-uuid", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+		assert.Empty(t, byID[standalone].Revisions)
+	})
 
-func TestHandleGetDecision_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String(), adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	t.Run("invalid mode is rejected", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?export_mode=everything", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
 }
 
-// ===========================================================================
-// Coverage push: HandleDeleteGrant — nonexistent
-// ===========================================================================
+func TestHandleExportDecisions_CSVAndParquet(t *testing.T) {
+	decisionType := "export_formats_" + uuid.New().String()[:8]
+	reasoning := "reasoning, with a comma"
+	for i := range 3 {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				DecisionType: decisionType,
+				Outcome:      fmt.Sprintf("outcome %d", i),
+				Confidence:   0.25 * float32(i+1),
+				Reasoning:    &reasoning,
+				Alternatives: []model.TraceAlternative{{Label: "other"}},
+			},
+		})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Contains(t, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted}, resp.StatusCode)
+	}
+	require.NoError(t, testBuf.FlushNow(context.Background()))
 
-func TestHandleDeleteGrant_Nonexistent(t *testing.T) {
-	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/grants/"+uuid.New().String(), nil)
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	export := func(query, accept string) *http.Response {
+		req, err := http.NewRequest("GET", testSrv.URL+"/v1/export/decisions?decision_type="+decisionType+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+adminToken)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
 
-// ===========================================================================
-// Coverage push: HandleMCPInfo — returns expected fields
-// ===========================================================================
+	// The NDJSON export is the reference for the flattened formats.
+	byID := map[string]model.Decision{}
+	ndjson := export("", "")
+	require.Equal(t, http.StatusOK, ndjson.StatusCode)
+	body, _ := io.ReadAll(ndjson.Body)
+	for _, line := range bytes.Split(bytes.TrimSpace(body), []byte("\n")) {
+		var d model.Decision
+		require.NoError(t, json.Unmarshal(line, &d))
+		byID[d.ID.String()] = d
+	}
+	require.Len(t, byID, 3)
 
-func TestHandleMCPInfo(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/mcp/info", agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Run("CSV", func(t *testing.T) {
+		resp := export("&format=csv", "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), ".csv")
 
-	var result struct {
-		Data model.MCPInfoResponse `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	assert.Equal(t, "streamable-http", result.Data.Transport)
-	assert.Equal(t, "ApiKey", result.Data.Auth.Preferred)
-	assert.Contains(t, result.Data.Auth.Schemes, "ApiKey")
-}
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4, "header plus one row per decision")
+		header := records[0]
+		assert.Equal(t, []string{"id", "run_id", "agent_id", "namespace", "decision_type", "outcome", "confidence"}, header[:7])
+		assert.Len(t, header, 26)
+		col := func(name string) int { return slices.Index(header, name) }
+		for _, rec := range records[1:] {
+			d, ok := byID[rec[col("id")]]
+			require.True(t, ok, "unexpected id %s", rec[0])
+			assert.Equal(t, d.Outcome, rec[col("outcome")])
+			assert.Equal(t, "reasoning, with a comma", rec[col("reasoning")])
+			assert.Equal(t, strconv.FormatFloat(float64(d.Confidence), 'f', -1, 32), rec[col("confidence")])
+			assert.Equal(t, "1", rec[col("alternative_count")])
+			assert.Empty(t, rec[col("supersedes_id")])
+		}
+		assert.Equal(t, "true", resp.Trailer.Get("X-Akashi-Export-Complete"))
+		assert.Equal(t, "3", resp.Trailer.Get("X-Akashi-Exported-Count"))
+	})
 
-// ===========================================================================
-// Coverage push: HandleConfig — returns expected fields
-// ===========================================================================
+	t.Run("Parquet via Accept", func(t *testing.T) {
+		resp := export("", "application/vnd.apache.parquet")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/vnd.apache.parquet", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), ".parquet")
 
-func TestHandleConfig(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/config")
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		// A consumer reads only the columns it names, by name.
+		type exportedDecision struct {
+			ID               string     `parquet:"id"`
+			RunID            string     `parquet:"run_id"`
+			Outcome          string     `parquet:"outcome"`
+			Confidence       float32    `parquet:"confidence"`
+			Reasoning        *string    `parquet:"reasoning,optional"`
+			ContentHash      string     `parquet:"content_hash"`
+			ValidFrom        time.Time  `parquet:"valid_from,timestamp(microsecond)"`
+			ValidTo          *time.Time `parquet:"valid_to,optional,timestamp(microsecond)"`
+			AlternativeCount int64      `parquet:"alternative_count"`
+		}
+		rows, err := parquet.Read[exportedDecision](bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		for _, row := range rows {
+			d, ok := byID[row.ID]
+			require.True(t, ok)
+			assert.Equal(t, d.RunID.String(), row.RunID)
+			assert.Equal(t, d.Outcome, row.Outcome)
+			assert.Equal(t, d.Confidence, row.Confidence)
+			require.NotNil(t, row.Reasoning)
+			assert.Equal(t, *d.Reasoning, *row.Reasoning)
+			assert.Equal(t, d.ContentHash, row.ContentHash)
+			assert.True(t, d.ValidFrom.Equal(row.ValidFrom))
+			assert.Nil(t, row.ValidTo)
+			assert.Equal(t, int64(1), row.AlternativeCount)
+		}
+		assert.Equal(t, "true", resp.Trailer.Get("X-Akashi-Export-Complete"))
+	})
 
-	var result struct {
-		Data map[string]bool `json:"data"`
-	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
-	_, hasSearchEnabled := result.Data["search_enabled"]
-	assert.True(t, hasSearchEnabled)
-}
+	t.Run("empty Parquet export is a valid file", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/export/decisions?format=parquet&agent_id=nonexistent", adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		data, _ := io.ReadAll(resp.Body)
+		f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		assert.Len(t, f.Schema().Fields(), 26)
+		assert.Zero(t, f.NumRows())
+	})
 
-// ===========================================================================
-// Coverage push: API key auth via ApiKey header
-// ===========================================================================
+	t.Run("invalid combinations", func(t *testing.T) {
+		for _, q := range []string{"&format=xml", "&format=csv&export_mode=full_history", "&format=parquet&export_mode=full_history"} {
+			resp := export(q, "")
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, q)
+		}
+	})
+}
 
-func TestAPIKeyAuth_ValidCredentials(t *testing.T) {
-	req, _ := http.NewRequest("GET", testSrv.URL+"/v1/decisions/recent", nil)
-	req.Header.Set("Authorization", "ApiKey test-agent:test-agent-key")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+// exportHistoryRecordJSON mirrors the full_history NDJSON line shape.
+type exportHistoryRecordJSON struct {
+	model.Decision
+	Revisions []model.Decision `json:"revisions"`
 }
 
-// ===========================================================================
-// Coverage push: HandleListAssessments — nonexistent decision
-// ===========================================================================
+func TestHandleTrace_DecisionQuota(t *testing.T) {
+	agentID := "quota-" + uuid.New().String()[:8]
 
-func TestHandleListAssessments_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/assessments", adminToken, nil)
+	// Restore the org's settings afterwards; PUT replaces the whole document.
+	prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
-
-// ===========================================================================
-// Coverage push: HandleCreateKey — invalid JSON, missing agent_id
-// ===========================================================================
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	})
 
-func TestHandleCreateKey_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/keys", strings.NewReader("{bad json"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	settings := prev.Settings
+	settings.DecisionQuota = &model.DecisionQuotaPolicy{
+		AgentOverrides: map[string]model.QuotaLimits{agentID: {Daily: 2}},
+	}
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleDeleteAgent — invalid agent_id, nonexistent
-// ===========================================================================
+	trace := func(outcome string) *http.Response {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: agentID,
+			Decision: model.TraceDecision{
+				DecisionType: "quota_test",
+				Outcome:      outcome,
+				Confidence:   0.5,
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
 
-func TestHandleDeleteAgent_InvalidAgentID(t *testing.T) {
-	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/agents/INVALID!!!", nil)
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
+	for i := range 2 {
+		resp := trace(fmt.Sprintf("within quota %d", i))
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "trace %d should be within quota", i)
+	}
+
+	resp = trace("over quota")
 	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	var apiErr struct {
+		Error struct {
+			Code    string         `json:"code"`
+			Details map[string]any `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+	assert.Equal(t, model.ErrCodeQuotaExceeded, apiErr.Error.Code)
+	assert.Equal(t, "agent", apiErr.Error.Details["scope"])
+	assert.Equal(t, "daily", apiErr.Error.Details["period"])
 
-func TestHandleDeleteAgent_Nonexistent(t *testing.T) {
-	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/agents/nonexistent-delete-agent", nil)
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	resp, err := http.DefaultClient.Do(req)
+	// Usage is visible to admins via agent stats.
+	statsResp, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+agentID+"/stats", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	defer func() { _ = statsResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, statsResp.StatusCode)
+	var stats struct {
+		Data struct {
+			Stats struct {
+				Quota model.AgentQuotaStatus `json:"quota"`
+			} `json:"stats"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(statsResp.Body).Decode(&stats))
+	assert.Equal(t, int64(2), stats.Data.Stats.Quota.Usage.AgentDaily)
+	assert.Equal(t, int64(2), stats.Data.Stats.Quota.Limits.Daily)
+
+	t.Run("invalid policy is rejected", func(t *testing.T) {
+		bad := prev.Settings
+		bad.DecisionQuota = &model.DecisionQuotaPolicy{PerAgent: model.QuotaLimits{Daily: -1}}
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, bad)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
 }
 
-// ===========================================================================
-// Coverage push: HandleCreateHold — invalid JSON
-// ===========================================================================
+func TestHandleTrace_MinAlternatives(t *testing.T) {
+	decisionType := "min_alts_" + uuid.New().String()[:8]
 
-func TestHandleCreateHold_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/retention/hold", strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
-
-// ===========================================================================
-// Coverage push: HandleReleaseHold — nonexistent
-// ===========================================================================
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	})
 
-func TestHandleReleaseHold_Nonexistent(t *testing.T) {
-	req, _ := http.NewRequest("DELETE", testSrv.URL+"/v1/retention/hold/"+uuid.New().String(), nil)
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	resp, err := http.DefaultClient.Do(req)
+	settings := prev.Settings
+	settings.MinAlternatives = model.MinAlternativesPolicy{decisionType: 2}
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-// ===========================================================================
-// Coverage push: HandleListConflictGroups — conflict_kind filter
-// ===========================================================================
+	trace := func(decisionType string, alternatives int) int {
+		alts := make([]model.TraceAlternative, alternatives)
+		for i := range alts {
+			alts[i] = model.TraceAlternative{Label: fmt.Sprintf("option %d", i)}
+		}
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID: "admin",
+			Decision: model.TraceDecision{
+				DecisionType: decisionType,
+				Outcome:      fmt.Sprintf("chose with %d alternatives", alternatives),
+				Confidence:   0.7,
+				Alternatives: alts,
+			},
+		})
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
 
-func TestHandleListConflictGroups_WithConflictKind(t *testing.T) {
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/conflict-groups?conflict_kind=cross_agent",
-		adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, http.StatusBadRequest, trace(decisionType, 1), "below the minimum is rejected")
+	assert.Equal(t, http.StatusCreated, trace(decisionType, 2), "meeting the minimum is accepted")
+	assert.Equal(t, http.StatusCreated, trace("unpolicied_"+decisionType, 0), "other types are unaffected")
+
+	t.Run("invalid policy is rejected", func(t *testing.T) {
+		bad := prev.Settings
+		bad.MinAlternatives = model.MinAlternativesPolicy{decisionType: -1}
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, bad)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
 }
 
-// ===========================================================================
-// Coverage push: HandleGetAgent — nonexistent
-// ===========================================================================
+func TestTraceSupersedeMatching(t *testing.T) {
+	decisionType := "standing_" + uuid.New().String()[:8]
 
-func TestHandleGetAgent_Nonexistent(t *testing.T) {
-	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/nonexistent-get-agent", adminToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
+	type traceResult struct {
+		DecisionID   uuid.UUID       `json:"decision_id"`
+		SupersededID *uuid.UUID      `json:"superseded_id"`
+		Decision     *model.Decision `json:"decision"`
+	}
+	trace := func(entity, outcome string, keys []string) (int, traceResult) {
+		req := model.TraceRequest{
+			AgentID:  "admin",
+			Metadata: map[string]any{"entity": entity},
+			Decision: model.TraceDecision{DecisionType: decisionType, Outcome: outcome, Confidence: 0.7},
+		}
+		if keys != nil {
+			req.SupersedeMatching = &model.SupersedeMatching{Keys: keys}
+		}
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, req)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var result struct {
+			Data traceResult `json:"data"`
+		}
+		b, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(b, &result)
+		return resp.StatusCode, result.Data
+	}
 
-// ===========================================================================
-// Coverage push: HandleCreateGrant — invalid JSON
-// ===========================================================================
+	status, first := trace("sku-1", "price 10", []string{"entity"})
+	require.Equal(t, http.StatusCreated, status)
+	assert.Nil(t, first.SupersededID, "nothing to supersede on the first trace")
 
-func TestHandleCreateGrant_InvalidJSON(t *testing.T) {
-	req, _ := http.NewRequest("POST", testSrv.URL+"/v1/grants", strings.NewReader("{bad"))
-	req.Header.Set("Authorization", "Bearer "+adminToken)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
-}
+	status, other := trace("sku-2", "price 20", []string{"entity"})
+	require.Equal(t, http.StatusCreated, status)
+	assert.Nil(t, other.SupersededID, "different entity is a separate standing decision")
 
-// ===========================================================================
-// Coverage push: HandleOpenAPISpec — not configured
-// ===========================================================================
+	status, second := trace("sku-1", "price 12", []string{"entity"})
+	require.Equal(t, http.StatusCreated, status)
+	require.NotNil(t, second.SupersededID)
+	assert.Equal(t, first.DecisionID, *second.SupersededID)
+	require.NotNil(t, second.Decision)
+	assert.Equal(t, second.DecisionID, second.Decision.ID)
+	assert.Equal(t, "price 12", second.Decision.Outcome)
 
-func TestHandleOpenAPISpec_NotConfigured(t *testing.T) {
-	resp, err := http.Get(testSrv.URL + "/openapi.yaml")
+	prior, err := testDB.GetDecision(context.Background(), uuid.Nil, first.DecisionID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
-}
-
-// ===========================================================================
-// X-Model header: server-inferred model in agent_context
-// ===========================================================================
+	assert.NotNil(t, prior.ValidTo, "matched decision is invalidated")
 
-func TestHandleTrace_XModelHeader(t *testing.T) {
-	// POST trace with X-Model header, no explicit model in body.
-	traceResp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "x-model-test",
-			"outcome":       "test model header inference",
-			"confidence":    0.8,
-		},
-		"context": map[string]any{"project": "test-project"},
-	}, map[string]string{
-		"X-Model": "claude-opus-4-6",
+	t.Run("key missing from metadata is rejected", func(t *testing.T) {
+		status, _ := trace("sku-1", "price 13", []string{"tenant"})
+		assert.Equal(t, http.StatusBadRequest, status)
 	})
+}
+
+func TestReviewQueue_FlagAndReview(t *testing.T) {
+	dt := "review_queue_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: dt,
+				Outcome:      "confident decision flagged by hand",
+				Confidence:   0.9,
+			},
+		})
 	require.NoError(t, err)
 	defer func() { _ = traceResp.Body.Close() }()
 	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
@@ -11011,523 +13343,999 @@ func TestHandleTrace_XModelHeader(t *testing.T) {
 	traceBody, _ := io.ReadAll(traceResp.Body)
 	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
 	decisionID := traceResult.Data.DecisionID
-	require.NotEqual(t, uuid.Nil, decisionID)
 
-	// GET the decision and verify the model was persisted from the header.
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
+	inQueue := func(query string) bool {
+		t.Helper()
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/review-queue?limit=1000&"+query, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []model.ReviewQueueItem `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		for _, it := range result.Data {
+			if it.DecisionID == decisionID {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.False(t, inQueue("reason=manual"), "confident decision should not be flagged automatically")
+
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/flag", agentToken,
+		model.FlagDecisionRequest{Note: ptrStr("looks off")})
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var result struct {
-		Data model.Decision `json:"data"`
-	}
-	body, _ := io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	require.NotNil(t, result.Data.Model, "model should be populated from X-Model header")
-	assert.Equal(t, "claude-opus-4-6", *result.Data.Model)
+	assert.True(t, inQueue("reason=manual"))
+	assert.False(t, inQueue("overdue=true&sla=1h"), "a fresh flag is not overdue")
+
+	// Agents cannot close reviews.
+	resp2, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/review", agentToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp2.Body.Close() }()
+	assert.Equal(t, http.StatusForbidden, resp2.StatusCode)
+
+	resp3, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/review", adminToken,
+		model.ReviewDecisionRequest{Note: ptrStr("fine as is")})
+	require.NoError(t, err)
+	defer func() { _ = resp3.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp3.StatusCode)
+	assert.False(t, inQueue("reason=manual"))
+
+	// Nothing left to review.
+	resp4, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/review", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp4.Body.Close() }()
+	assert.Equal(t, http.StatusNotFound, resp4.StatusCode)
 }
 
-func TestHandleTrace_ExplicitModelTakesPriorityOverXModelHeader(t *testing.T) {
-	// POST trace with both explicit model in body and X-Model header.
-	traceResp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", adminToken, map[string]any{
-		"agent_id": "admin",
-		"decision": map[string]any{
-			"decision_type": "model-priority-test",
-			"outcome":       "explicit model should win over header",
-			"confidence":    0.8,
-		},
-		"context": map[string]any{
-			"model":   "gpt-4o",
-			"project": "test-project",
-		},
-	}, map[string]string{
-		"X-Model": "claude-opus-4-6",
+func TestReviewQueue_RoutingAssignsReviewer(t *testing.T) {
+	prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
 	})
+
+	settings := prev.Settings
+	settings.ReviewRouting = &model.ReviewRoutingPolicy{Routes: []model.ReviewRoute{{Tag: "payments"}}}
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "route without reviewers is rejected")
+
+	settings.ReviewRouting = &model.ReviewRoutingPolicy{DefaultReviewers: []string{"admin"}}
+	resp, err = authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: "review_routing_" + uuid.NewString()[:8],
+				Outcome:      "routed to the default reviewer",
+				Confidence:   0.9,
+			},
+		})
 	require.NoError(t, err)
 	defer func() { _ = traceResp.Body.Close() }()
 	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
-
 	var traceResult struct {
 		Data struct {
 			DecisionID uuid.UUID `json:"decision_id"`
 		} `json:"data"`
 	}
-	traceBody, _ := io.ReadAll(traceResp.Body)
-	require.NoError(t, json.Unmarshal(traceBody, &traceResult))
+	require.NoError(t, json.NewDecoder(traceResp.Body).Decode(&traceResult))
 	decisionID := traceResult.Data.DecisionID
-	require.NotEqual(t, uuid.Nil, decisionID)
 
-	// GET the decision and verify the explicit model won over the header.
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
+	flagResp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/flag", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	defer func() { _ = flagResp.Body.Close() }()
+	require.Equal(t, http.StatusOK, flagResp.StatusCode)
+	var flagResult struct {
+		Data model.ReviewFlag `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(flagResp.Body).Decode(&flagResult))
+	require.NotNil(t, flagResult.Data.AssignedReviewer)
+	assert.Equal(t, "admin", *flagResult.Data.AssignedReviewer)
 
-	var result struct {
-		Data model.Decision `json:"data"`
+	inQueue := func(token, query string) bool {
+		t.Helper()
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/review-queue?limit=1000&"+query, token, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []model.ReviewQueueItem `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return slices.ContainsFunc(result.Data, func(it model.ReviewQueueItem) bool { return it.DecisionID == decisionID })
 	}
-	body, _ := io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	require.NotNil(t, result.Data.Model, "model should be populated")
-	assert.Equal(t, "gpt-4o", *result.Data.Model, "explicit model in body must take priority over X-Model header")
+
+	assert.True(t, inQueue(adminToken, "assigned_to=me"))
+	assert.False(t, inQueue(agentToken, "assigned_to=me"))
+
+	// Only admins can list someone else's assignments.
+	resp, err = authedRequest("GET", testSrv.URL+"/v1/review-queue?assigned_to=admin", agentToken, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
 
-// ===========================================================================
-// Project normalization: workspace names resolved to canonical repo names
-// ===========================================================================
+func TestCompleteRun_ReviewGate(t *testing.T) {
+	prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	})
 
-func TestHandleTrace_ProjectNormalizedFromRepoURL(t *testing.T) {
-	// When the client sends a workspace name as the project but also includes
-	// a repo_url in context, the server should normalize to the canonical repo name.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "traced with workspace name and repo_url",
-				"confidence":    0.7,
-			},
-			"context": map[string]any{
-				"project":  "cairo-v1",
-				"repo_url": "https://github.com/ashita-ai/akashi.git",
-			},
-		})
+	gatedType := "run_gate_" + uuid.NewString()[:8]
+	settings := prev.Settings
+	settings.RunReviewGate = &model.RunReviewGatePolicy{DecisionTypes: []string{gatedType}}
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var traceResp struct {
-		Data struct {
-			DecisionID string `json:"decision_id"`
-		} `json:"data"`
+	// newRunWithFlaggedDecision starts a run holding one flagged decision of
+	// decisionType and returns the run and decision IDs.
+	newRunWithFlaggedDecision := func(decisionType string) (uuid.UUID, uuid.UUID) {
+		t.Helper()
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken, model.CreateRunRequest{AgentID: "test-agent"})
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var runResult struct {
+			Data model.AgentRun `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&runResult))
+
+		d, err := testDB.CreateDecision(context.Background(), model.Decision{
+			RunID: runResult.Data.ID, AgentID: "test-agent", OrgID: uuid.Nil,
+			DecisionType: decisionType, Outcome: "needs a second look",
+			Confidence: 0.9, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+
+		flagResp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+d.ID.String()+"/flag", agentToken, nil)
+		require.NoError(t, err)
+		_ = flagResp.Body.Close()
+		require.Equal(t, http.StatusOK, flagResp.StatusCode)
+		return runResult.Data.ID, d.ID
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &traceResp))
-	require.NotEmpty(t, traceResp.Data.DecisionID)
+	complete := func(runID uuid.UUID, status string) *http.Response {
+		t.Helper()
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/runs/"+runID.String()+"/complete", agentToken,
+			model.CompleteRunRequest{Status: status})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("blocked until flags are reviewed", func(t *testing.T) {
+		runID, decisionID := newRunWithFlaggedDecision(gatedType)
+
+		resp := complete(runID, "completed")
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Details struct {
+					Total          int                     `json:"total"`
+					PendingReviews []model.ReviewQueueItem `json:"pending_reviews"`
+				} `json:"details"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiErr))
+		assert.Equal(t, model.ErrCodeConflict, apiErr.Error.Code)
+		assert.Equal(t, 1, apiErr.Error.Details.Total)
+		require.Len(t, apiErr.Error.Details.PendingReviews, 1)
+		assert.Equal(t, decisionID, apiErr.Error.Details.PendingReviews[0].DecisionID)
 
-	// Fetch the decision and verify the project was normalized.
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResp.Data.DecisionID, adminToken, nil)
+		reviewResp, err := authedRequest("POST", testSrv.URL+"/v1/decisions/"+decisionID.String()+"/review", adminToken, nil)
+		require.NoError(t, err)
+		_ = reviewResp.Body.Close()
+		require.Equal(t, http.StatusOK, reviewResp.StatusCode)
+
+		assert.Equal(t, http.StatusOK, complete(runID, "completed").StatusCode)
+	})
+
+	t.Run("ungated decision types do not block", func(t *testing.T) {
+		runID, _ := newRunWithFlaggedDecision("run_gate_other_" + uuid.NewString()[:8])
+		assert.Equal(t, http.StatusOK, complete(runID, "completed").StatusCode)
+	})
+
+	t.Run("failing a run is never blocked", func(t *testing.T) {
+		runID, _ := newRunWithFlaggedDecision(gatedType)
+		assert.Equal(t, http.StatusOK, complete(runID, "failed").StatusCode)
+	})
+}
+
+func TestReembed_RequiresEmbeddingProvider(t *testing.T) {
+	// The test server runs with the noop embedding provider.
+	resp, err := authedRequest("POST", testSrv.URL+"/v1/admin/reembed?from_model=a&to_model=b", adminToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = getResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, getResp.StatusCode)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
 
-	var result struct {
-		Data model.Decision `json:"data"`
+	resp, err = authedRequest("POST", testSrv.URL+"/v1/admin/reembed?from_model=a&to_model=b", agentToken, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestReviewQueue_InvalidParams(t *testing.T) {
+	for _, query := range []string{"sla=soon", "sla=-1h", "overdue=maybe", "reason=vibes"} {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/review-queue?"+query, adminToken, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
 	}
-	body, _ = io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	require.NotNil(t, result.Data.Project, "project should be set")
-	assert.Equal(t, "akashi", *result.Data.Project, "workspace name should be normalized to repo name via repo_url")
 }
 
-func TestHandleTrace_ProjectUnchangedWhenCanonical(t *testing.T) {
-	// When the client sends a proper project name, it should be preserved as-is.
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "traced with canonical project",
-				"confidence":    0.7,
-			},
-			"context": map[string]any{
-				"project": "akashi",
+func TestDecisionsByHash(t *testing.T) {
+	dt := "by_hash_" + uuid.NewString()[:8]
+	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
+		model.TraceRequest{
+			AgentID: "test-agent",
+			Decision: model.TraceDecision{
+				DecisionType: dt,
+				Outcome:      "decision looked up by content hash",
+				Confidence:   0.8,
 			},
 		})
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	defer func() { _ = traceResp.Body.Close() }()
+	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
 
-	var traceResp struct {
+	var traceResult struct {
 		Data struct {
-			DecisionID string `json:"decision_id"`
+			DecisionID uuid.UUID `json:"decision_id"`
 		} `json:"data"`
 	}
-	body, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(body, &traceResp))
-	require.NotEmpty(t, traceResp.Data.DecisionID)
+	require.NoError(t, json.NewDecoder(traceResp.Body).Decode(&traceResult))
+	decisionID := traceResult.Data.DecisionID
 
-	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+traceResp.Data.DecisionID, adminToken, nil)
+	getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = getResp.Body.Close() }()
-	require.Equal(t, http.StatusOK, getResp.StatusCode)
-
-	var result struct {
+	var getResult struct {
 		Data model.Decision `json:"data"`
 	}
-	body, _ = io.ReadAll(getResp.Body)
-	require.NoError(t, json.Unmarshal(body, &result))
-	require.NotNil(t, result.Data.Project)
-	assert.Equal(t, "akashi", *result.Data.Project)
-}
-
-func TestHandleTrace_WorkspaceNamesSameRepoGetSameProject(t *testing.T) {
-	// Two decisions traced with different workspace names but the same repo_url
-	// should both resolve to the same canonical project name, making them
-	// eligible for conflict comparison.
-	repoURL := "https://github.com/ashita-ai/akashi.git"
-	workspaceNames := []string{"dubai", "bamako"}
-	var decisionIDs []string
+	require.NoError(t, json.NewDecoder(getResp.Body).Decode(&getResult))
+	contentHash := getResult.Data.ContentHash
+	require.True(t, strings.HasPrefix(contentHash, "v2:"))
+	digest := strings.TrimPrefix(contentHash, "v2:")
 
-	for _, ws := range workspaceNames {
-		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-			map[string]any{
-				"agent_id": "test-agent",
-				"decision": map[string]any{
-					"decision_type": "architecture",
-					"outcome":       "decision from workspace " + ws,
-					"confidence":    0.7,
-				},
-				"context": map[string]any{
-					"project":  ws,
-					"repo_url": repoURL,
-				},
-			})
+	lookup := func(query string) []model.Decision {
+		t.Helper()
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/by-hash?"+query, adminToken, nil)
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
-		require.Equal(t, http.StatusCreated, resp.StatusCode)
-
-		var traceResp struct {
-			Data struct {
-				DecisionID string `json:"decision_id"`
-			} `json:"data"`
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data []model.Decision `json:"data"`
 		}
-		body, _ := io.ReadAll(resp.Body)
-		require.NoError(t, json.Unmarshal(body, &traceResp))
-		require.NotEmpty(t, traceResp.Data.DecisionID)
-		decisionIDs = append(decisionIDs, traceResp.Data.DecisionID)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Data
+	}
+
+	for _, query := range []string{
+		"hash=" + contentHash,
+		"hash=" + strings.ToUpper(digest),
+		"prefix=" + digest[:12],
+		"prefix=v2:" + digest[:12],
+	} {
+		found := lookup(query)
+		assert.True(t, slices.ContainsFunc(found, func(d model.Decision) bool { return d.ID == decisionID }), query)
+	}
+
+	// A full hash that matches nothing returns an empty list.
+	assert.Empty(t, lookup("hash="+strings.Repeat("0", 64)))
+
+	for _, query := range []string{
+		"",
+		"hash=" + digest + "&prefix=" + digest[:8],
+		"hash=" + digest[:20],
+		"prefix=" + digest[:4],
+		"prefix=zzzzzzzz",
+	} {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/by-hash?"+query, adminToken, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
 	}
+}
 
-	// Verify both decisions have the same canonical project name.
-	for i, id := range decisionIDs {
-		getResp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+id, adminToken, nil)
-		require.NoError(t, err)
-		defer func() { _ = getResp.Body.Close() }()
-		require.Equal(t, http.StatusOK, getResp.StatusCode)
+func TestGetDecision_DefaultIncludes(t *testing.T) {
+	prev, err := testDB.GetOrgSettings(context.Background(), uuid.Nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, prev.Settings)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	})
+
+	_, d, err := testDB.CreateTraceTx(context.Background(), storage.CreateTraceParams{
+		AgentID: "test-agent",
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "default_includes", Outcome: "chose Postgres", Confidence: 0.8,
+		},
+		Alternatives: []model.Alternative{{Label: "MySQL"}},
+	})
+	require.NoError(t, err)
 
+	getAlternatives := func(query string) []model.Alternative {
+		t.Helper()
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+d.ID.String()+query, adminToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
 		var result struct {
 			Data model.Decision `json:"data"`
 		}
-		body, _ := io.ReadAll(getResp.Body)
-		require.NoError(t, json.Unmarshal(body, &result))
-		require.NotNil(t, result.Data.Project, "decision %d should have project set", i)
-		assert.Equal(t, "akashi", *result.Data.Project,
-			"workspace %q should be normalized to 'akashi' via repo_url", workspaceNames[i])
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Data.Alternatives
 	}
-}
 
-// ===========================================================================
-// High-confidence warning in trace response (#468)
-// ===========================================================================
+	assert.Len(t, getAlternatives(""), 1, "built-in default includes alternatives")
+	assert.Empty(t, getAlternatives("?include=none"))
 
-func TestHandleTrace_HighConfNoEvidence_ReturnsWarning(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "chose Postgres for high-confidence-warning test",
-				"confidence":    0.9,
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+	settings := prev.Settings
+	settings.DefaultIncludes = &model.DefaultIncludesPolicy{GetDecision: []string{model.IncludeNone}}
+	resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	var body struct {
-		Data struct {
-			Warnings []string `json:"warnings"`
-		} `json:"data"`
-	}
-	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
-	require.Len(t, body.Data.Warnings, 1, "expected one warning for high confidence without evidence")
-	assert.Contains(t, body.Data.Warnings[0], "high confidence")
+	assert.Empty(t, getAlternatives(""), "org default is lean")
+	assert.Len(t, getAlternatives("?include=alternatives"), 1, "request overrides org default")
+
+	settings.DefaultIncludes = &model.DefaultIncludesPolicy{Query: []string{"reasoning"}}
+	resp, err = authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func TestHandleTrace_HighConfWithEvidence_NoWarning(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "architecture",
-				"outcome":       "chose Postgres with evidence",
-				"confidence":    0.9,
-				"evidence": []map[string]any{
-					{"source_type": "document", "content": "benchmark results showing 10x throughput"},
-				},
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+func TestDuplicatesReport(t *testing.T) {
+	// An agent cannot read the report.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/stats/duplicates", agentToken, nil)
 	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 
-	body, _ := io.ReadAll(resp.Body)
-	assert.NotContains(t, string(body), "warnings", "no warning expected when evidence is present")
-}
+	scan, err := testDB.RefreshDuplicatePairs(context.Background(), uuid.Nil, 0.9, 10, 5000)
+	require.NoError(t, err)
 
-func TestHandleTrace_LowConfNoEvidence_NoWarning(t *testing.T) {
-	resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken,
-		map[string]any{
-			"agent_id": "test-agent",
-			"decision": map[string]any{
-				"decision_type": "implementation",
-				"outcome":       "low confidence decision",
-				"confidence":    0.7,
-			},
-			"context": map[string]any{"project": "test-project"},
-		})
+	resp, err = authedRequest("GET", testSrv.URL+"/v1/stats/duplicates?threshold=0.95", adminToken, nil)
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
 
-	body, _ := io.ReadAll(resp.Body)
-	assert.NotContains(t, string(body), "warnings", "no warning expected for confidence below threshold")
+	var result struct {
+		Data model.DuplicatesReport `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.InDelta(t, 0.95, result.Data.Threshold, 1e-9)
+	assert.InDelta(t, 0.9, result.Data.Floor, 1e-6)
+	require.NotNil(t, result.Data.ScannedAt)
+	assert.Equal(t, scan.DecisionsScanned, result.Data.DecisionsScanned)
+	for _, c := range result.Data.Clusters {
+		assert.GreaterOrEqual(t, c.MinSimilarity, 0.95)
+		assert.Equal(t, c.Size, len(c.Members))
+	}
+
+	// Thresholds below the scan floor cannot be answered from stored pairs.
+	resp2, err := authedRequest("GET", testSrv.URL+"/v1/stats/duplicates?threshold=0.5", adminToken, nil)
+	require.NoError(t, err)
+	_ = resp2.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp2.StatusCode)
 }
 
-// ===========================================================================
-// HandleGetRun — enrichments via ?include=enrichments
-// ===========================================================================
+func TestConfidenceConsensus(t *testing.T) {
+	suffix := uuid.New().String()[:8]
+	decisionType := "consensus_" + suffix
+	agents := []string{"consensus-a-" + suffix, "consensus-b-" + suffix}
+	for _, agentID := range agents {
+		createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	}
 
-func TestHandleGetRun_EnrichmentsBasic(t *testing.T) {
-	// Trace a decision (auto-creates a run).
-	reasoning := "integration test reasoning"
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: "enrichment_test",
-				Outcome:      "chose enrichments",
-				Confidence:   0.85,
-				Reasoning:    &reasoning,
-			},
-			Context: map[string]any{"project": "test-project"},
+	trace := func(agentID, service, outcome string, confidence float32) {
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", adminToken, model.TraceRequest{
+			AgentID:  agentID,
+			Metadata: map[string]any{"service": service},
+			Decision: model.TraceDecision{DecisionType: decisionType, Outcome: outcome, Confidence: confidence},
 		})
-	require.NoError(t, err)
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
-
-	var traceResult struct {
-		Data struct {
-			RunID      uuid.UUID `json:"run_id"`
-			DecisionID uuid.UUID `json:"decision_id"`
-		} `json:"data"`
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
 	}
-	tb, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(tb, &traceResult))
-	runID := traceResult.Data.RunID
+	trace(agents[0], "billing", "use postgres", 0.95)
+	trace(agents[1], "billing", "Use Postgres", 0.3)
+	trace(agents[0], "search", "use elastic", 0.8)
+	trace(agents[1], "search", "use meilisearch", 0.75)
 
-	// GET with enrichments.
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/runs/"+runID.String()+"?include=enrichments",
-		agentToken, nil)
-	require.NoError(t, err)
-	defer func() { _ = resp.Body.Close() }()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
+	get := func(token, query string) (int, model.ConfidenceConsensusReport) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/consensus/confidence?"+query, token, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var result struct {
+			Data model.ConfidenceConsensusReport `json:"data"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		}
+		return resp.StatusCode, result.Data
+	}
+
+	status, report := get(adminToken, "decision_type="+decisionType+"&metadata_key=service")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, report.Entities, 2)
+	assert.Equal(t, "billing", report.Entities[0].MetadataValue)
+	assert.True(t, report.Entities[0].CalibrationFlag)
+	assert.Equal(t, 2, report.Entities[0].AgentCount)
+	assert.False(t, report.Entities[1].OutcomeAgreement)
+	assert.False(t, report.Entities[1].CalibrationFlag)
+
+	status, report = get(adminToken, "decision_type="+decisionType+"&metadata_key=service&metadata_value=search")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, report.Entities, 1)
+	assert.Equal(t, "search", report.Entities[0].MetadataValue)
+
+	// An agent without grants sees only its own decisions: no cross-agent entities.
+	agentTok := getToken(testSrv.URL, agents[0], agents[0]+"-key")
+	status, report = get(agentTok, "decision_type="+decisionType+"&metadata_key=service")
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, report.Entities)
+
+	status, _ = get(adminToken, "decision_type="+decisionType)
+	assert.Equal(t, http.StatusBadRequest, status, "metadata_key is required")
+	status, _ = get(adminToken, "decision_type="+decisionType+"&metadata_key=service&spread_threshold=2")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestAgentDefaultSession(t *testing.T) {
+	agentID := "session-agent-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	token := getToken(testSrv.URL, agentID, agentID+"-key")
 
-	var result struct {
-		Data struct {
-			Run                 json.RawMessage            `json:"run"`
-			Decisions           []json.RawMessage          `json:"decisions"`
-			DecisionEnrichments map[string]json.RawMessage `json:"decision_enrichments"`
-		} `json:"data"`
+	trace := func(headers map[string]string) model.Decision {
+		resp, err := authedRequestWithHeaders("POST", testSrv.URL+"/v1/trace", token, model.TraceRequest{
+			AgentID:  agentID,
+			Decision: model.TraceDecision{DecisionType: "session_default", Outcome: "chose " + uuid.New().String()[:8], Confidence: 0.7},
+		}, headers)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var result struct {
+			Data model.TraceResponse `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		d, err := testDB.GetDecision(context.Background(), uuid.Nil, result.Data.DecisionID, storage.GetDecisionOpts{})
+		require.NoError(t, err)
+		return d
+	}
+	sessionCall := func(method, url string, body any) (int, model.AgentSession) {
+		resp, err := authedRequest(method, url, token, body)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var result struct {
+			Data model.AgentSession `json:"data"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Data
 	}
-	b, _ := io.ReadAll(resp.Body)
-	require.NoError(t, json.Unmarshal(b, &result))
 
-	// Must have at least one decision and a matching enrichment.
-	require.GreaterOrEqual(t, len(result.Data.Decisions), 1, "run should contain at least one decision")
-	require.NotNil(t, result.Data.DecisionEnrichments, "enrichments map must be present")
+	assert.Nil(t, trace(nil).SessionID, "no session before one is started")
 
-	// Find our decision in the enrichments.
-	decID := traceResult.Data.DecisionID.String()
-	rawEnrichment, ok := result.Data.DecisionEnrichments[decID]
-	require.True(t, ok, "enrichment must exist for traced decision %s", decID)
+	status, session := sessionCall("POST", testSrv.URL+"/v1/sessions", nil)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, agentID, session.AgentID)
+	assert.Nil(t, session.ClosedAt)
 
-	// Parse and validate the enrichment structure.
-	var enrichment struct {
-		Revisions struct {
-			Items    []json.RawMessage `json:"items"`
-			Count    int               `json:"count"`
-			Degraded bool              `json:"degraded"`
-		} `json:"revisions"`
-		Lineage struct {
-			DecisionID string `json:"decision_id"`
-		} `json:"lineage"`
-		Conflicts struct {
-			Items   []json.RawMessage `json:"items"`
-			Count   int               `json:"count"`
-			HasMore bool              `json:"has_more"`
-		} `json:"conflicts"`
-		Integrity struct {
-			Status      string `json:"status"`
-			ContentHash string `json:"content_hash"`
-		} `json:"integrity"`
+	d := trace(nil)
+	require.NotNil(t, d.SessionID)
+	assert.Equal(t, session.SessionID, *d.SessionID, "traces default to the open session")
+
+	override := uuid.New()
+	d = trace(map[string]string{"X-Akashi-Session": override.String()})
+	require.NotNil(t, d.SessionID)
+	assert.Equal(t, override, *d.SessionID, "the header overrides the default session")
+
+	status, _ = sessionCall("POST", testSrv.URL+"/v1/sessions", map[string]any{"agent_id": "admin"})
+	assert.Equal(t, http.StatusForbidden, status, "agents cannot start sessions for others")
+
+	status, closed := sessionCall("POST", testSrv.URL+"/v1/sessions/"+session.SessionID.String()+"/close", nil)
+	require.Equal(t, http.StatusOK, status)
+	assert.NotNil(t, closed.ClosedAt)
+	assert.Nil(t, trace(nil).SessionID, "closed sessions are no longer the default")
+
+	t.Run("starting a new session replaces the open one", func(t *testing.T) {
+		_, first := sessionCall("POST", testSrv.URL+"/v1/sessions", nil)
+		_, second := sessionCall("POST", testSrv.URL+"/v1/sessions", nil)
+		require.NotEqual(t, first.SessionID, second.SessionID)
+		d := trace(nil)
+		require.NotNil(t, d.SessionID)
+		assert.Equal(t, second.SessionID, *d.SessionID)
+
+		prev, err := testDB.GetAgentSession(context.Background(), uuid.Nil, first.SessionID)
+		require.NoError(t, err)
+		assert.NotNil(t, prev.ClosedAt)
+	})
+}
+
+func TestHandleAgentSessions(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "sessions-agent-" + suffix
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	outsiderID := "sessions-outsider-" + suffix
+	createAgent(testSrv.URL, adminToken, outsiderID, outsiderID, "agent", outsiderID+"-key")
+	outsiderToken := getToken(testSrv.URL, outsiderID, outsiderID+"-key")
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID, OrgID: uuid.Nil})
+	require.NoError(t, err)
+	sessionID := uuid.New()
+	for _, dt := range []string{"architecture", "security"} {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, OrgID: uuid.Nil, SessionID: &sessionID,
+			DecisionType: dt, Outcome: "chose " + dt, Confidence: 0.8, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
 	}
-	require.NoError(t, json.Unmarshal(rawEnrichment, &enrichment))
 
-	// Revisions: at least the decision itself.
-	assert.GreaterOrEqual(t, enrichment.Revisions.Count, 1, "revision chain should include at least the original decision")
-	assert.False(t, enrichment.Revisions.Degraded, "revisions should not be degraded")
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+agentID+"/sessions", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var result struct {
+		Data  []model.SessionSummary `json:"data"`
+		Total *int                   `json:"total"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, sessionID, result.Data[0].SessionID)
+	assert.Equal(t, 2, result.Data[0].DecisionCount)
+	require.NotNil(t, result.Total)
+	assert.Equal(t, 1, *result.Total)
 
-	// Lineage: decision_id should match.
-	assert.Equal(t, decID, enrichment.Lineage.DecisionID)
+	forbidden, err := authedRequest("GET", testSrv.URL+"/v1/agents/"+agentID+"/sessions", outsiderToken, nil)
+	require.NoError(t, err)
+	_ = forbidden.Body.Close()
+	assert.Equal(t, http.StatusForbidden, forbidden.StatusCode)
+}
 
-	// Conflicts: no overflow expected for a single fresh decision.
-	assert.False(t, enrichment.Conflicts.HasMore)
+func TestHandleListConflicts_CursorPaging(t *testing.T) {
+	seedConflict(t)
+	seedConflict(t)
+	seedConflict(t)
 
-	// Integrity: decision was traced with content hash.
-	assert.Contains(t, []string{"verified", "no_hash"}, enrichment.Integrity.Status,
-		"integrity status should be verified or no_hash")
-	if enrichment.Integrity.Status == "verified" {
-		assert.NotEmpty(t, enrichment.Integrity.ContentHash,
-			"verified status must include the content_hash")
+	seen := make(map[uuid.UUID]bool)
+	cursor := ""
+	pages := 0
+	for {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=2&cursor="+url.QueryEscape(cursor), adminToken, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result struct {
+			Data       []model.DecisionConflict `json:"data"`
+			Total      *int                     `json:"total"`
+			HasMore    bool                     `json:"has_more"`
+			NextCursor string                   `json:"next_cursor"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		_ = resp.Body.Close()
+		pages++
+
+		assert.Nil(t, result.Total, "cursor pages do not report a total")
+		assert.Equal(t, result.NextCursor != "", result.HasMore)
+		for _, c := range result.Data {
+			assert.False(t, seen[c.ID], "conflict %s returned twice", c.ID)
+			seen[c.ID] = true
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+		require.Less(t, pages, 10000, "pagination did not terminate")
+	}
+	assert.GreaterOrEqual(t, len(seen), 3)
+	assert.GreaterOrEqual(t, pages, 2)
+
+	// Every conflict in the offset listing is covered by the cursor walk.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?limit=1000", adminToken, nil)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	var all struct {
+		Data []model.DecisionConflict `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&all))
+	for _, c := range all.Data {
+		assert.True(t, seen[c.ID], "conflict %s missing from cursor pages", c.ID)
 	}
+
+	bad, err := authedRequest("GET", testSrv.URL+"/v1/conflicts?cursor=not-a-cursor", adminToken, nil)
+	require.NoError(t, err)
+	_ = bad.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, bad.StatusCode)
 }
 
-func TestHandleGetRun_NoEnrichmentsWithoutParam(t *testing.T) {
-	// Trace a decision to get a run.
-	traceResp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken,
-		model.TraceRequest{
-			AgentID: "test-agent",
-			Decision: model.TraceDecision{
-				DecisionType: "no_enrichment_test",
-				Outcome:      "should not enrich",
-				Confidence:   0.5,
-			},
-			Context: map[string]any{"project": "test-project"},
-		})
+func TestHandleUpdateDecisionTags(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	ownerID := "dtags-owner-" + suffix
+	createAgent(testSrv.URL, adminToken, ownerID, ownerID, "agent", ownerID+"-key")
+	ownerToken := getToken(testSrv.URL, ownerID, ownerID+"-key")
+	otherID := "dtags-other-" + suffix
+	createAgent(testSrv.URL, adminToken, otherID, otherID, "agent", otherID+"-key")
+	otherToken := getToken(testSrv.URL, otherID, otherID+"-key")
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: ownerID, OrgID: uuid.Nil})
 	require.NoError(t, err)
-	require.Equal(t, http.StatusCreated, traceResp.StatusCode)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: ownerID, OrgID: uuid.Nil,
+		DecisionType: "architecture", Outcome: "chose decision tags", Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	tagsURL := testSrv.URL + "/v1/decisions/" + d.ID.String() + "/tags"
 
-	var traceResult struct {
-		Data struct {
-			RunID uuid.UUID `json:"run_id"`
-		} `json:"data"`
+	patch := func(token string, body any) *http.Response {
+		t.Helper()
+		resp, err := authedRequest("PATCH", tagsURL, token, body)
+		require.NoError(t, err)
+		return resp
 	}
-	tb, _ := io.ReadAll(traceResp.Body)
-	_ = traceResp.Body.Close()
-	require.NoError(t, json.Unmarshal(tb, &traceResult))
 
-	// GET without ?include=enrichments.
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/runs/"+traceResult.Data.RunID.String(),
-		agentToken, nil)
-	require.NoError(t, err)
+	// The owner adds tags.
+	resp := patch(ownerToken, model.UpdateDecisionTagsRequest{Add: []string{"q3-launch", "postmortem", "postmortem"}})
 	defer func() { _ = resp.Body.Close() }()
 	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var out struct {
+		Data model.DecisionTagsResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, d.ID, out.Data.DecisionID)
+	assert.Equal(t, []string{"postmortem", "q3-launch"}, out.Data.Tags)
 
-	b, _ := io.ReadAll(resp.Body)
-	// The response should NOT contain decision_enrichments.
-	assert.NotContains(t, string(b), "decision_enrichments",
-		"enrichments should not appear without ?include=enrichments")
-}
+	// Add and remove in one call.
+	resp2 := patch(ownerToken, model.UpdateDecisionTagsRequest{Add: []string{"shipped"}, Remove: []string{"q3-launch"}})
+	defer func() { _ = resp2.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp2.StatusCode)
+	require.NoError(t, json.NewDecoder(resp2.Body).Decode(&out))
+	assert.Equal(t, []string{"postmortem", "shipped"}, out.Data.Tags)
 
-func TestHandleGetRun_EnrichmentsEmptyRun(t *testing.T) {
-	// Create a run with no decisions.
-	runResp, err := authedRequest("POST", testSrv.URL+"/v1/runs", agentToken,
-		model.CreateRunRequest{AgentID: "test-agent"})
+	// Tagging does not touch the agent's own tags.
+	agent, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, ownerID)
 	require.NoError(t, err)
-	require.Equal(t, http.StatusCreated, runResp.StatusCode)
+	assert.Empty(t, agent.Tags)
 
-	var runResult struct {
-		Data struct {
-			ID uuid.UUID `json:"id"`
-		} `json:"data"`
+	// An agent that cannot read the decision's agent is forbidden.
+	forbidden := patch(otherToken, model.UpdateDecisionTagsRequest{Add: []string{"sneaky"}})
+	_ = forbidden.Body.Close()
+	assert.Equal(t, http.StatusForbidden, forbidden.StatusCode)
+
+	// Invalid input.
+	for _, body := range []model.UpdateDecisionTagsRequest{
+		{},
+		{Add: []string{"Bad Tag"}},
+		{Add: []string{"dup"}, Remove: []string{"dup"}},
+	} {
+		bad := patch(ownerToken, body)
+		_ = bad.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, bad.StatusCode, "body %+v", body)
 	}
-	rb, _ := io.ReadAll(runResp.Body)
-	_ = runResp.Body.Close()
-	require.NoError(t, json.Unmarshal(rb, &runResult))
 
-	// GET with ?include=enrichments on a run with zero decisions.
-	resp, err := authedRequest("GET",
-		testSrv.URL+"/v1/runs/"+runResult.Data.ID.String()+"?include=enrichments",
-		agentToken, nil)
+	// Unknown decision.
+	missing, err := authedRequest("PATCH", testSrv.URL+"/v1/decisions/"+uuid.New().String()+"/tags", adminToken,
+		model.UpdateDecisionTagsRequest{Add: []string{"x"}})
+	require.NoError(t, err)
+	_ = missing.Body.Close()
+	assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+}
+
+func TestHandleUpdateDecisionMetadata(t *testing.T) {
+	ctx := context.Background()
+	agentID := "meta-http-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "agent", agentID+"-key")
+	agentToken := getToken(testSrv.URL, agentID, agentID+"-key")
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID, OrgID: uuid.Nil})
+	require.NoError(t, err)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: uuid.Nil,
+		DecisionType: "architecture", Outcome: "chose metadata patches", Confidence: 0.8,
+		Metadata: map[string]any{"tool_version": "1.0.0", "ticket": "ENG-7"},
+	})
+	require.NoError(t, err)
+	metaURL := testSrv.URL + "/v1/decisions/" + d.ID.String() + "/metadata"
+
+	resp, err := authedRequest("PATCH", metaURL, adminToken,
+		model.UpdateDecisionMetadataRequest{Metadata: map[string]any{"tool_version": "1.0.1"}})
 	require.NoError(t, err)
 	defer func() { _ = resp.Body.Close() }()
 	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var out struct {
+		Data model.DecisionMetadataResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	assert.Equal(t, map[string]any{"tool_version": "1.0.1", "ticket": "ENG-7"}, out.Data.Metadata)
 
-	b, _ := io.ReadAll(resp.Body)
-	// No enrichments when there are no decisions.
-	assert.NotContains(t, string(b), "decision_enrichments",
-		"enrichments should not appear when run has no decisions")
+	// Admin-only.
+	forbidden, err := authedRequest("PATCH", metaURL, agentToken,
+		model.UpdateDecisionMetadataRequest{Metadata: map[string]any{"ticket": "ENG-8"}})
+	require.NoError(t, err)
+	_ = forbidden.Body.Close()
+	assert.Equal(t, http.StatusForbidden, forbidden.StatusCode)
+
+	empty, err := authedRequest("PATCH", metaURL, adminToken, model.UpdateDecisionMetadataRequest{})
+	require.NoError(t, err)
+	_ = empty.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, empty.StatusCode)
+
+	// Superseded decisions are part of the chain and cannot be patched.
+	require.NoError(t, testDB.RetractDecision(ctx, uuid.Nil, d.ID, "replaced", "admin", nil))
+	conflict, err := authedRequest("PATCH", metaURL, adminToken,
+		model.UpdateDecisionMetadataRequest{Metadata: map[string]any{"ticket": "ENG-8"}})
+	require.NoError(t, err)
+	_ = conflict.Body.Close()
+	assert.Equal(t, http.StatusConflict, conflict.StatusCode)
 }
 
-// ---- HandleSetOrgSettings audit trail ------------------------------------
+func TestHandleAuthRefresh(t *testing.T) {
+	agentID := "refresh-http-" + uuid.New().String()[:8]
+	createAgent(testSrv.URL, adminToken, agentID, agentID, "reader", agentID+"-key")
 
-func TestHandleSetOrgSettings_AuditTrail(t *testing.T) {
-	orgID := uuid.Nil // default org from SeedAdmin
+	post := func(path string, body any) (int, model.AuthTokenResponse) {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		resp, err := http.Post(testSrv.URL+path, "application/json", bytes.NewReader(raw))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		var out struct {
+			Data model.AuthTokenResponse `json:"data"`
+		}
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.StatusCode, out.Data
+	}
 
-	// Count existing audit entries so we can assert on the delta.
-	var baseline int
-	require.NoError(t, testDB.Pool().QueryRow(context.Background(),
-		`SELECT count(*) FROM mutation_audit_log
-		 WHERE operation = 'org_settings_updated'
-		   AND resource_type = 'org_settings'
-		   AND org_id = $1`, orgID,
-	).Scan(&baseline))
+	code, issued := post("/auth/token", model.AuthTokenRequest{AgentID: agentID, APIKey: agentID + "-key"})
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, issued.RefreshToken)
+	assert.True(t, issued.RefreshExpiresAt.After(issued.ExpiresAt))
 
-	t.Run("upsert writes mutation audit entry", func(t *testing.T) {
-		settings := model.OrgSettingsData{
-			ConflictResolution: &model.ConflictResolutionPolicy{
-				AutoResolveAfterDays:   7,
-				AutoResolveWinner:      model.WinnerRecency,
-				AutoResolveMaxSeverity: "medium",
-			},
+	// A refresh token cannot authenticate API requests.
+	resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/recent", issued.RefreshToken, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	code, refreshed := post("/auth/refresh", model.AuthRefreshRequest{RefreshToken: issued.RefreshToken})
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, refreshed.Token)
+	assert.NotEqual(t, issued.RefreshToken, refreshed.RefreshToken, "refresh rotates the refresh token")
+
+	resp, err = authedRequest("GET", testSrv.URL+"/v1/decisions/recent", refreshed.Token, nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The rotated-out token is revoked.
+	code, _ = post("/auth/refresh", model.AuthRefreshRequest{RefreshToken: issued.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	// Access tokens cannot be exchanged.
+	code, _ = post("/auth/refresh", model.AuthRefreshRequest{RefreshToken: refreshed.Token})
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = post("/auth/refresh", model.AuthRefreshRequest{})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// The replacement still works.
+	code, _ = post("/auth/refresh", model.AuthRefreshRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestOrgSettingsByID(t *testing.T) {
+	settingsURL := testSrv.URL + "/v1/orgs/" + uuid.Nil.String() + "/settings"
+	t.Cleanup(func() {
+		resp, err := authedRequest("PUT", settingsURL, adminToken,
+			model.SetOrgSettingRequest{Key: model.OrgSettingConfigOverrides, Value: json.RawMessage(`null`)})
+		if err == nil {
+			_ = resp.Body.Close()
 		}
+	})
 
-		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, settings)
+	t.Run("set and get one key", func(t *testing.T) {
+		resp, err := authedRequest("PUT", settingsURL, adminToken, model.SetOrgSettingRequest{
+			Key:   model.OrgSettingConfigOverrides,
+			Value: json.RawMessage(`{"conflict_significance_threshold": 0.45, "event_retention_days": 30}`),
+		})
 		require.NoError(t, err)
 		defer func() { _ = resp.Body.Close() }()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		// Verify a mutation audit row was written for this operation.
-		var count int
-		err = testDB.Pool().QueryRow(context.Background(),
-			`SELECT count(*) FROM mutation_audit_log
-			 WHERE operation = 'org_settings_updated'
-			   AND resource_type = 'org_settings'
-			   AND org_id = $1`, orgID,
-		).Scan(&count)
+		resp2, err := authedRequest("GET", settingsURL, adminToken, nil)
 		require.NoError(t, err)
-		assert.Equal(t, baseline+1, count, "expected one new audit entry for org_settings_updated")
+		defer func() { _ = resp2.Body.Close() }()
+		require.Equal(t, http.StatusOK, resp2.StatusCode)
+		var result struct {
+			Data model.OrgSettings `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp2.Body).Decode(&result))
+		assert.Equal(t, uuid.Nil, result.Data.OrgID)
+		assert.Equal(t, "admin", result.Data.UpdatedBy)
+		require.NotNil(t, result.Data.Settings.ConfigOverrides)
+		assert.InDelta(t, 0.45, result.Data.Settings.ConfigOverrides.SignificanceThreshold(0.3), 1e-9)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		for name, req := range map[string]model.SetOrgSettingRequest{
+			"unknown key":       {Key: "no_such_setting", Value: json.RawMessage(`1`)},
+			"out of range":      {Key: model.OrgSettingConfigOverrides, Value: json.RawMessage(`{"conflict_significance_threshold": 2}`)},
+			"wrong type":        {Key: model.OrgSettingConfigOverrides, Value: json.RawMessage(`"high"`)},
+			"unknown subfield":  {Key: model.OrgSettingConfigOverrides, Value: json.RawMessage(`{"embedding_model": "x"}`)},
+			"missing key field": {Value: json.RawMessage(`{}`)},
+		} {
+			resp, err := authedRequest("PUT", settingsURL, adminToken, req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, name)
+		}
 	})
 
-	t.Run("second upsert captures before-state", func(t *testing.T) {
-		// Second write — should capture the first settings as before_data.
-		second := model.OrgSettingsData{
-			ConflictResolution: &model.ConflictResolutionPolicy{
-				AutoResolveAfterDays:   14,
-				AutoResolveWinner:      model.WinnerConfidence,
-				AutoResolveMaxSeverity: "high",
-			},
-		}
-		resp, err := authedRequest("PUT", testSrv.URL+"/v1/org/settings", adminToken, second)
+	t.Run("admins cannot manage other orgs", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/orgs/"+uuid.New().String()+"/settings", adminToken, nil)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("non-admins are rejected", func(t *testing.T) {
+		resp, err := authedRequest("GET", settingsURL, agentToken, nil)
 		require.NoError(t, err)
 		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+// TestGRPCSharesStorageWithHTTP traces over gRPC and reads the decision back
+// over HTTP, proving both transports write to the same store.
+func TestGRPCSharesStorageWithHTTP(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	gs := akashigrpc.New(akashigrpc.ServerConfig{
+		DB:          testDB,
+		JWTMgr:      testJWTMgr,
+		DecisionSvc: testDecisions,
+		Logger:      testutil.TestLogger(),
+	})
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(func() { gs.GracefulStop(context.Background()) })
+
+	conn, err := grpclib.NewClient(lis.Addr().String(), grpclib.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := akashiv1.NewAkashiClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+agentToken)
+
+	outcome := "grpc-trace-" + uuid.NewString()
+	traced, err := client.Trace(ctx, &akashiv1.TraceRequest{
+		AgentId: "test-agent",
+		Decision: &akashiv1.TraceDecision{
+			DecisionType: "architecture",
+			Outcome:      outcome,
+			Confidence:   0.6,
+			Reasoning:    proto.String("recorded over gRPC"),
+		},
+		Project: proto.String("test-project"),
+	})
+	require.NoError(t, err)
+	decisionID, err := uuid.Parse(traced.GetDecisionId())
+	require.NoError(t, err)
+
+	t.Run("decision is readable over HTTP", func(t *testing.T) {
+		resp, err := authedRequest("GET", testSrv.URL+"/v1/decisions/"+decisionID.String(), agentToken, nil)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		// Find the most recent audit entry and verify before_data is non-null.
-		var beforeData, afterData []byte
-		err = testDB.Pool().QueryRow(context.Background(),
-			`SELECT before_data, after_data FROM mutation_audit_log
-			 WHERE operation = 'org_settings_updated'
-			   AND resource_type = 'org_settings'
-			   AND org_id = $1
-			 ORDER BY occurred_at DESC LIMIT 1`, orgID,
-		).Scan(&beforeData, &afterData)
+		var result struct {
+			Data model.Decision `json:"data"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		require.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, outcome, result.Data.Outcome)
+		assert.Equal(t, "test-agent", result.Data.AgentID)
+		require.NotNil(t, result.Data.Project)
+		assert.Equal(t, "test-project", *result.Data.Project)
+		serverCtx, _ := result.Data.AgentContext["server"].(map[string]any)
+		assert.Equal(t, "grpc", serverCtx["transport"])
+	})
+
+	t.Run("HTTP trace is readable over gRPC", func(t *testing.T) {
+		httpOutcome := "http-trace-" + uuid.NewString()
+		resp, err := authedRequest("POST", testSrv.URL+"/v1/trace", agentToken, model.TraceRequest{
+			AgentID:  "test-agent",
+			Decision: model.TraceDecision{DecisionType: "architecture", Outcome: httpOutcome, Confidence: 0.5},
+			Context:  map[string]any{"project": "test-project"},
+		})
 		require.NoError(t, err)
-		assert.NotNil(t, beforeData, "second upsert should capture before_data")
-		assert.NotNil(t, afterData, "second upsert should capture after_data")
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
 
-		// Verify the before_data contains the previous settings.
-		// PostgreSQL jsonb canonical form uses spaces after colons.
-		assert.Contains(t, string(beforeData), `"auto_resolve_after_days": 7`,
-			"before_data should reflect the first settings write")
-		// Verify the after_data contains the new settings.
-		assert.Contains(t, string(afterData), `"auto_resolve_after_days": 14`,
-			"after_data should reflect the second settings write")
+		found, err := client.Query(ctx, &akashiv1.QueryRequest{
+			Filters: &akashiv1.QueryFilters{AgentIds: []string{"test-agent"}, Outcome: proto.String(httpOutcome)},
+		})
+		require.NoError(t, err)
+		require.Len(t, found.GetDecisions(), 1)
+		assert.Equal(t, httpOutcome, found.GetDecisions()[0].GetOutcome())
+	})
+
+	t.Run("supersede_matching updates the standing decision", func(t *testing.T) {
+		meta, err := structpb.NewStruct(map[string]any{"component": "grpc-" + uuid.NewString()})
+		require.NoError(t, err)
+		trace := func(outcome string) *akashiv1.TraceResponse {
+			t.Helper()
+			resp, err := client.Trace(ctx, &akashiv1.TraceRequest{
+				AgentId:           "test-agent",
+				Decision:          &akashiv1.TraceDecision{DecisionType: "architecture", Outcome: outcome, Confidence: 0.6},
+				Metadata:          meta,
+				Project:           proto.String("test-project"),
+				SupersedeMatching: &akashiv1.SupersedeMatching{Keys: []string{"component"}},
+			})
+			require.NoError(t, err)
+			return resp
+		}
+
+		first := trace("standing-" + uuid.NewString())
+		assert.Nil(t, first.SupersededId, "nothing to supersede yet")
+		second := trace("standing-" + uuid.NewString())
+		require.NotNil(t, second.SupersededId)
+		assert.Equal(t, first.GetDecisionId(), second.GetSupersededId())
+
+		_, err = client.Trace(ctx, &akashiv1.TraceRequest{
+			AgentId:           "test-agent",
+			Decision:          &akashiv1.TraceDecision{DecisionType: "architecture", Outcome: "x", Confidence: 0.5},
+			Project:           proto.String("test-project"),
+			SupersedeMatching: &akashiv1.SupersedeMatching{Keys: []string{"component"}},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), "match keys must be present in metadata")
+	})
+
+	t.Run("agents cannot trace for other agents", func(t *testing.T) {
+		_, err := client.Trace(ctx, &akashiv1.TraceRequest{
+			AgentId:  "someone-else",
+			Decision: &akashiv1.TraceDecision{DecisionType: "architecture", Outcome: "x", Confidence: 0.5},
+			Project:  proto.String("test-project"),
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("calls without a token are rejected", func(t *testing.T) {
+		_, err := client.Check(context.Background(), &akashiv1.CheckRequest{DecisionType: "architecture"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("subscribe without a broker is unavailable", func(t *testing.T) {
+		stream, err := client.Subscribe(ctx, &akashiv1.SubscribeRequest{})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unavailable, status.Code(err))
 	})
 }
@@ -0,0 +1,105 @@
+// Package conflictsuggest pre-explains detected conflicts with an LLM: why the
+// two decisions conflict and a suggested resolution. Suggestions are advisory
+// and written asynchronously, so a slow or unavailable LLM never delays
+// conflict detection itself.
+package conflictsuggest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/conflicts"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// Store is the subset of storage.DB used by the suggestion worker.
+type Store interface {
+	ConflictsPendingSuggestion(ctx context.Context, limit int) ([]model.DecisionConflict, error)
+	SetConflictSuggestion(ctx context.Context, orgID, id uuid.UUID, s *model.ConflictSuggestion) error
+}
+
+// Service generates suggestions for open conflicts that lack one.
+type Service struct {
+	db        Store
+	suggester conflicts.Suggester
+	perRun    int
+	logger    *slog.Logger
+}
+
+// New creates a suggestion service. ratePerMinute caps LLM calls; since
+// RunOnce is driven once per interval, the cap becomes a per-run budget of
+// ceil(ratePerMinute * interval / 1m), at least one.
+func New(db Store, suggester conflicts.Suggester, ratePerMinute int, interval time.Duration, logger *slog.Logger) *Service {
+	perRun := int(math.Ceil(float64(ratePerMinute) * interval.Minutes()))
+	return &Service{db: db, suggester: suggester, perRun: max(1, perRun), logger: logger}
+}
+
+// RunOnce writes suggestions for up to the per-run budget of open conflicts,
+// newest first. A conflict whose LLM reply cannot be parsed is marked as
+// processed without a suggestion; a failed LLM request stops the run and
+// leaves the conflict pending for the next one.
+func (s *Service) RunOnce(ctx context.Context) error {
+	pending, err := s.db.ConflictsPendingSuggestion(ctx, s.perRun)
+	if err != nil {
+		return fmt.Errorf("conflictsuggest: list pending: %w", err)
+	}
+
+	var written int
+	for _, c := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		sug, err := s.suggester.Suggest(ctx, suggestInput(c))
+		if err != nil {
+			var perr *conflicts.SuggestionParseError
+			if !errors.As(err, &perr) {
+				return fmt.Errorf("conflictsuggest: conflict %s: %w", c.ID, err)
+			}
+			s.logger.Warn("conflictsuggest: unusable LLM reply, skipping conflict",
+				"conflict_id", c.ID, "error", err)
+			if err := s.db.SetConflictSuggestion(ctx, c.OrgID, c.ID, nil); err != nil {
+				return fmt.Errorf("conflictsuggest: mark conflict %s: %w", c.ID, err)
+			}
+			continue
+		}
+		if err := s.db.SetConflictSuggestion(ctx, c.OrgID, c.ID, &model.ConflictSuggestion{
+			Explanation: sug.Explanation,
+			Resolution:  sug.Resolution,
+			Model:       s.suggester.Model(),
+		}); err != nil {
+			return fmt.Errorf("conflictsuggest: save conflict %s: %w", c.ID, err)
+		}
+		written++
+	}
+
+	if written > 0 {
+		s.logger.Info("conflictsuggest: suggestions written", "count", written)
+	}
+	return nil
+}
+
+func suggestInput(c model.DecisionConflict) conflicts.SuggestInput {
+	in := conflicts.SuggestInput{
+		DecisionType: c.DecisionType,
+		AgentA:       c.AgentA,
+		AgentB:       c.AgentB,
+		OutcomeA:     c.OutcomeA,
+		OutcomeB:     c.OutcomeB,
+	}
+	if c.ReasoningA != nil {
+		in.ReasoningA = *c.ReasoningA
+	}
+	if c.ReasoningB != nil {
+		in.ReasoningB = *c.ReasoningB
+	}
+	if c.Explanation != nil {
+		in.Explanation = *c.Explanation
+	}
+	return in
+}
@@ -0,0 +1,99 @@
+package conflictsuggest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/conflicts"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+type fakeStore struct {
+	pending  []model.DecisionConflict
+	limit    int
+	saved    map[uuid.UUID]*model.ConflictSuggestion
+	savedIDs []uuid.UUID
+}
+
+func (f *fakeStore) ConflictsPendingSuggestion(_ context.Context, limit int) ([]model.DecisionConflict, error) {
+	f.limit = limit
+	return f.pending[:min(limit, len(f.pending))], nil
+}
+
+func (f *fakeStore) SetConflictSuggestion(_ context.Context, _, id uuid.UUID, s *model.ConflictSuggestion) error {
+	if f.saved == nil {
+		f.saved = map[uuid.UUID]*model.ConflictSuggestion{}
+	}
+	f.saved[id] = s
+	f.savedIDs = append(f.savedIDs, id)
+	return nil
+}
+
+type fakeSuggester struct {
+	errs  map[string]error
+	calls int
+}
+
+func (f *fakeSuggester) Model() string { return "fake-model" }
+
+func (f *fakeSuggester) Suggest(_ context.Context, in conflicts.SuggestInput) (conflicts.Suggestion, error) {
+	f.calls++
+	if err := f.errs[in.OutcomeA]; err != nil {
+		return conflicts.Suggestion{}, err
+	}
+	return conflicts.Suggestion{Explanation: "why " + in.OutcomeA, Resolution: "prefer " + in.OutcomeB}, nil
+}
+
+func newConflict(outcomeA string) model.DecisionConflict {
+	return model.DecisionConflict{ID: uuid.New(), OrgID: uuid.New(), OutcomeA: outcomeA, OutcomeB: "b"}
+}
+
+func discardLogger() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
+
+func TestNew_PerRunBudget(t *testing.T) {
+	assert.Equal(t, 5, New(nil, nil, 10, 30*time.Second, discardLogger()).perRun)
+	assert.Equal(t, 1, New(nil, nil, 1, 10*time.Second, discardLogger()).perRun)
+	assert.Equal(t, 20, New(nil, nil, 10, 2*time.Minute, discardLogger()).perRun)
+}
+
+func TestRunOnce_WritesSuggestions(t *testing.T) {
+	store := &fakeStore{pending: []model.DecisionConflict{newConflict("a1"), newConflict("a2"), newConflict("a3")}}
+	svc := New(store, &fakeSuggester{}, 4, time.Minute/2, discardLogger())
+
+	require.NoError(t, svc.RunOnce(context.Background()))
+	assert.Equal(t, 2, store.limit, "budget caps how many conflicts are fetched")
+	require.Len(t, store.savedIDs, 2)
+	got := store.saved[store.pending[0].ID]
+	require.NotNil(t, got)
+	assert.Equal(t, "why a1", got.Explanation)
+	assert.Equal(t, "prefer b", got.Resolution)
+	assert.Equal(t, "fake-model", got.Model)
+}
+
+func TestRunOnce_ParseErrorMarksProcessed(t *testing.T) {
+	store := &fakeStore{pending: []model.DecisionConflict{newConflict("junk"), newConflict("ok")}}
+	sug := &fakeSuggester{errs: map[string]error{"junk": &conflicts.SuggestionParseError{Err: errors.New("bad json")}}}
+
+	require.NoError(t, New(store, sug, 60, time.Minute, discardLogger()).RunOnce(context.Background()))
+	require.Len(t, store.savedIDs, 2)
+	assert.Nil(t, store.saved[store.pending[0].ID], "unparseable reply is recorded without a suggestion")
+	assert.NotNil(t, store.saved[store.pending[1].ID])
+}
+
+func TestRunOnce_RequestErrorLeavesPending(t *testing.T) {
+	store := &fakeStore{pending: []model.DecisionConflict{newConflict("down"), newConflict("ok")}}
+	sug := &fakeSuggester{errs: map[string]error{"down": errors.New("connection refused")}}
+
+	err := New(store, sug, 60, time.Minute, discardLogger()).RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Empty(t, store.savedIDs, "nothing is written when the LLM is unreachable")
+	assert.Equal(t, 1, sug.calls, "run stops at the first request failure")
+}
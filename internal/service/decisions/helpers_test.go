@@ -1017,6 +1017,16 @@ func (m *mockAgentStore) CreateAgentWithAudit(_ context.Context, agent model.Age
 
 func (m *mockAgentStore) IsDuplicateKey(_ error) bool { return m.isDup }
 
+// tombstoneAgentStore answers IsAgentTombstoned with a fixed value.
+type tombstoneAgentStore struct {
+	mockAgentStore
+	tombstoned bool
+}
+
+func (m *tombstoneAgentStore) IsAgentTombstoned(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+	return m.tombstoned, nil
+}
+
 func TestResolveOrCreateAgent_DBLookupFailure(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -1065,6 +1075,59 @@ func TestResolveOrCreateAgent_DuplicateKeyRace(t *testing.T) {
 	assert.Equal(t, "", agent.AgentID, "should return zero-value agent on dup key race")
 }
 
+func TestResolveOrCreateAgent_TombstonedAgentID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, role := range []model.AgentRole{model.RoleAgent, model.RoleAdmin} {
+		ms := &tombstoneAgentStore{
+			mockAgentStore: mockAgentStore{getAgentErr: storage.ErrNotFound},
+			tombstoned:     true,
+		}
+		svc := &Service{db: ms, logger: testLogger()}
+
+		_, err := svc.ResolveOrCreateAgent(ctx, uuid.Nil, "agent-x", role, nil)
+		require.ErrorIs(t, err, ErrAgentTombstoned, "role %s", role)
+		assert.NotErrorIs(t, err, ErrAgentNotFound)
+	}
+}
+
+func TestResolveOrCreateAgent_DuplicateKeyOnTombstone(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// The agent was tombstoned between the lookup and the insert: the
+	// duplicate key is the retained row, not a concurrent registration.
+	calls := 0
+	ms := &sequencedTombstoneStore{
+		mockAgentStore: mockAgentStore{
+			getAgentErr:    storage.ErrNotFound,
+			createAgentErr: fmt.Errorf("unique constraint violation"),
+			isDup:          true,
+		},
+		answers: []bool{false, true},
+		calls:   &calls,
+	}
+	svc := &Service{db: ms, logger: testLogger()}
+
+	_, err := svc.ResolveOrCreateAgent(ctx, uuid.Nil, "agent-x", model.RoleAdmin, nil)
+	require.ErrorIs(t, err, ErrAgentTombstoned)
+	assert.Equal(t, 2, calls)
+}
+
+// sequencedTombstoneStore answers IsAgentTombstoned from a fixed sequence.
+type sequencedTombstoneStore struct {
+	mockAgentStore
+	answers []bool
+	calls   *int
+}
+
+func (m *sequencedTombstoneStore) IsAgentTombstoned(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+	ans := m.answers[*m.calls]
+	*m.calls++
+	return ans, nil
+}
+
 func TestResolveOrCreateAgent_WithAudit(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -1524,7 +1587,7 @@ func (m *backfillBatchStore) FindUnembeddedDecisions(_ context.Context, _ int) (
 	return m.findUnembedded, m.findUnembeddedErr
 }
 
-func (m *backfillBatchStore) BackfillEmbedding(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ pgvector.Vector) error {
+func (m *backfillBatchStore) BackfillEmbedding(_ context.Context, _ uuid.UUID, _ uuid.UUID, _ pgvector.Vector, _ string) error {
 	m.backfillCalls++
 	return m.backfillErr
 }
@@ -1627,6 +1690,43 @@ func TestBackfillOutcomeEmbeddings_FindError(t *testing.T) {
 	assert.Contains(t, err.Error(), "find")
 }
 
+func TestReembedDecisions_NoProvider(t *testing.T) {
+	t.Parallel()
+	ms := &backfillBatchStore{}
+	svc := New(ms, embedding.NewNoopProvider(3), nil, testLogger(), nil)
+
+	_, err := svc.ReembedDecisions(context.Background(), []storage.UnembeddedDecision{{ID: uuid.New()}})
+	assert.ErrorIs(t, err, embedding.ErrNoProvider)
+	assert.Equal(t, 0, ms.backfillCalls)
+}
+
+func TestReembedDecisions_OverwritesBothEmbeddingsAndClaims(t *testing.T) {
+	t.Parallel()
+	ms := &backfillBatchStore{}
+	svc := New(ms, fakeEmbedder{dims: 3}, nil, testLogger(), nil)
+
+	count, err := svc.ReembedDecisions(context.Background(), []storage.UnembeddedDecision{
+		{ID: uuid.New(), OrgID: uuid.Nil, DecisionType: "arch", Outcome: "First claim sentence. Second claim sentence."},
+		{ID: uuid.New(), OrgID: uuid.Nil, DecisionType: "sec", Outcome: "Chose mTLS."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 4, ms.backfillCalls, "embedding and outcome embedding per decision")
+	assert.Positive(t, ms.insertClaimsCalls)
+}
+
+func TestReembedDecisions_WriteErrorSkips(t *testing.T) {
+	t.Parallel()
+	ms := &backfillBatchStore{backfillErr: fmt.Errorf("write error")}
+	svc := New(ms, fakeEmbedder{dims: 3}, nil, testLogger(), nil)
+
+	count, err := svc.ReembedDecisions(context.Background(), []storage.UnembeddedDecision{
+		{ID: uuid.New(), OrgID: uuid.Nil, DecisionType: "arch", Outcome: "chose Go"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
 // ---------------------------------------------------------------------------
 // Check — unit tests via mock store
 // ---------------------------------------------------------------------------
@@ -2006,6 +2106,91 @@ func TestHydrateAndReScore_WithSignalsAndAssessments(t *testing.T) {
 	assert.Equal(t, decID, results[0].Decision.ID)
 }
 
+// namedEmbedder is a mockEmbedder that reports a model name.
+type namedEmbedder struct {
+	mockEmbedder
+	name string
+}
+
+func (n *namedEmbedder) ModelName() string { return n.name }
+
+func TestHydrateAndReScore_DropsCrossModelNeighbors(t *testing.T) {
+	t.Parallel()
+	sameID, oldID, untaggedID := uuid.New(), uuid.New(), uuid.New()
+	ms := &hydrateStore{decisions: map[uuid.UUID]model.Decision{
+		sameID:     {ID: sameID, EmbeddingModel: strPtr("model-b")},
+		oldID:      {ID: oldID, EmbeddingModel: strPtr("model-a")},
+		untaggedID: {ID: untaggedID},
+	}}
+	srch := &mockSearcherForHydrate{results: []search.Result{
+		{DecisionID: oldID, Score: 0.99, QdrantRank: 0},
+		{DecisionID: sameID, Score: 0.9, QdrantRank: 1},
+		{DecisionID: untaggedID, Score: 0.8, QdrantRank: 2},
+	}}
+	svc := New(ms, &namedEmbedder{mockEmbedder{dims: 3}, "model-b"}, srch, testLogger(), nil)
+
+	results, err := svc.Search(context.Background(), uuid.Nil, "test", true, model.QueryFilters{}, 10)
+	require.NoError(t, err)
+	got := make([]uuid.UUID, len(results))
+	for i, r := range results {
+		got[i] = r.Decision.ID
+	}
+	assert.ElementsMatch(t, []uuid.UUID{sameID, untaggedID}, got, "model-a neighbors must not be scored against a model-b query")
+}
+
+// servingEmbedder reports a primary model name but serves every call from a
+// fallback model, like a FallbackProvider whose primary is down.
+type servingEmbedder struct {
+	namedEmbedder
+	served string
+}
+
+func (e *servingEmbedder) EmbedWithModel(ctx context.Context, text string) (pgvector.Vector, string, error) {
+	v, err := e.Embed(ctx, text)
+	return v, e.served, err
+}
+
+func (e *servingEmbedder) EmbedBatchWithModel(ctx context.Context, texts []string) ([]pgvector.Vector, string, error) {
+	v, err := e.EmbedBatch(ctx, texts)
+	return v, e.served, err
+}
+
+func TestHydrateAndReScore_UsesServingModel(t *testing.T) {
+	t.Parallel()
+	primaryID, fallbackID := uuid.New(), uuid.New()
+	ms := &hydrateStore{decisions: map[uuid.UUID]model.Decision{
+		primaryID:  {ID: primaryID, EmbeddingModel: strPtr("model-b")},
+		fallbackID: {ID: fallbackID, EmbeddingModel: strPtr("model-a")},
+	}}
+	srch := &mockSearcherForHydrate{results: []search.Result{
+		{DecisionID: primaryID, Score: 0.99, QdrantRank: 0},
+		{DecisionID: fallbackID, Score: 0.9, QdrantRank: 1},
+	}}
+	emb := &servingEmbedder{namedEmbedder{mockEmbedder{dims: 3}, "model-b"}, "model-a"}
+	svc := New(ms, emb, srch, testLogger(), nil)
+
+	results, err := svc.Search(context.Background(), uuid.Nil, "test", true, model.QueryFilters{}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, fallbackID, results[0].Decision.ID, "neighbors are filtered by the model that embedded the query")
+}
+
+func TestHydrateAndReScore_OnlyCrossModelFallsBackToText(t *testing.T) {
+	t.Parallel()
+	oldID, textID := uuid.New(), uuid.New()
+	ms := &hydrateStore{
+		decisions:     map[uuid.UUID]model.Decision{oldID: {ID: oldID, EmbeddingModel: strPtr("model-a")}},
+		searchResults: []model.SearchResult{{Decision: model.Decision{ID: textID}}},
+	}
+	srch := &mockSearcherForHydrate{results: []search.Result{{DecisionID: oldID, Score: 0.99}}}
+	svc := New(ms, &namedEmbedder{mockEmbedder{dims: 3}, "model-b"}, srch, testLogger(), nil)
+
+	results, err := svc.Search(context.Background(), uuid.Nil, "test", true, model.QueryFilters{}, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, textID, results[0].Decision.ID, "with no same-model neighbors, search uses text")
+}
+
 // ---------------------------------------------------------------------------
 // ConsensusScoresBatch — additional edge cases
 // ---------------------------------------------------------------------------
@@ -2134,6 +2319,68 @@ func TestAdjudicateConflictWithTrace_TxError(t *testing.T) {
 	assert.Contains(t, err.Error(), "trace+adjudicate")
 }
 
+// ---------------------------------------------------------------------------
+// TraceBatch — mock tests
+// ---------------------------------------------------------------------------
+
+// batchStore extends mockStore for TraceBatch, echoing one run and decision
+// per params entry.
+type batchStore struct {
+	mockStore
+	batchErr error
+	got      []storage.CreateTraceParams
+}
+
+func (m *batchStore) CreateTracesTx(_ context.Context, params []storage.CreateTraceParams) ([]model.AgentRun, []model.Decision, error) {
+	m.got = params
+	if m.batchErr != nil {
+		return nil, nil, m.batchErr
+	}
+	runs := make([]model.AgentRun, len(params))
+	decs := make([]model.Decision, len(params))
+	for i, p := range params {
+		runs[i] = model.AgentRun{ID: uuid.New()}
+		decs[i] = model.Decision{ID: uuid.New(), Outcome: p.Decision.Outcome}
+	}
+	return runs, decs, nil
+}
+
+func (m *batchStore) Notify(_ context.Context, _, _ string) error { return nil }
+
+func TestTraceBatch_PreservesOrder(t *testing.T) {
+	t.Parallel()
+	ms := &batchStore{}
+	svc := New(ms, fakeEmbedder{dims: 3}, nil, testLogger(), nil)
+
+	inputs := []TraceInput{
+		{AgentID: "a", Decision: model.TraceDecision{DecisionType: "t", Outcome: "first", Confidence: 0.5}},
+		{AgentID: "b", Decision: model.TraceDecision{DecisionType: "t", Outcome: "second", Confidence: 0.5}},
+	}
+	results, err := svc.TraceBatch(context.Background(), uuid.Nil, inputs)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Len(t, ms.got, 2)
+	assert.Equal(t, "first", results[0].Decision.Outcome)
+	assert.Equal(t, "second", results[1].Decision.Outcome)
+	assert.Equal(t, "b", ms.got[1].AgentID)
+}
+
+func TestTraceBatch_TxErrorKeepsIndex(t *testing.T) {
+	t.Parallel()
+	ms := &batchStore{batchErr: &storage.TraceBatchError{Index: 1, Err: storage.ErrNotFound}}
+	svc := New(ms, fakeEmbedder{dims: 3}, nil, testLogger(), nil)
+
+	_, err := svc.TraceBatch(context.Background(), uuid.Nil, []TraceInput{
+		{AgentID: "a", Decision: model.TraceDecision{DecisionType: "t", Outcome: "x", Confidence: 0.5}},
+		{AgentID: "a", Decision: model.TraceDecision{DecisionType: "t", Outcome: "y", Confidence: 0.5}},
+	})
+	require.Error(t, err)
+	var batchErr *storage.TraceBatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 1, batchErr.Index)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
 // ---------------------------------------------------------------------------
 // postTraceAsync — Trace with mock store for coverage
 // ---------------------------------------------------------------------------
@@ -2530,3 +2777,89 @@ func TestBootstrapMetadata_WrongTypeNested(t *testing.T) {
 	bootstrapMetadata(input)
 	assert.Nil(t, input.Metadata, "wrong type should silently skip")
 }
+
+// staticOrgSettings is an OrgSettingsReader returning fixed settings.
+type staticOrgSettings struct{ data model.OrgSettingsData }
+
+func (s staticOrgSettings) GetOrgSettings(_ context.Context, orgID uuid.UUID) (model.OrgSettings, error) {
+	return model.OrgSettings{OrgID: orgID, Settings: s.data}, nil
+}
+
+func TestApplyPrecedentDecay_RanksByEffectiveConfidence(t *testing.T) {
+	now := time.Now()
+	oldConfident := model.Decision{ID: uuid.New(), DecisionType: "architecture", Confidence: 0.9, ValidFrom: now.Add(-60 * 24 * time.Hour)}
+	recentModerate := model.Decision{ID: uuid.New(), DecisionType: "architecture", Confidence: 0.6, ValidFrom: now.Add(-24 * time.Hour)}
+	decs := []model.Decision{oldConfident, recentModerate}
+
+	svc := &Service{logger: testLogger()}
+	svc.SetOrgSettingsReader(staticOrgSettings{data: model.OrgSettingsData{
+		PrecedentDecay: &model.PrecedentDecayPolicy{DecisionTypes: map[string]float64{"architecture": 30}},
+	}})
+	svc.applyPrecedentDecay(context.Background(), uuid.New(), decs, now)
+
+	require.NotNil(t, decs[0].EffectiveConfidence)
+	require.NotNil(t, decs[1].EffectiveConfidence)
+	assert.Equal(t, recentModerate.ID, decs[0].ID, "recent moderate decision outranks a stale confident one")
+	assert.InDelta(t, 0.225, *decs[1].EffectiveConfidence, 0.001, "two half-lives quarter the confidence")
+	assert.InDelta(t, 0.9, decs[1].Confidence, 0.0001, "raw confidence is preserved")
+}
+
+func TestApplyPrecedentDecay_NoPolicy(t *testing.T) {
+	now := time.Now()
+	decs := []model.Decision{
+		{ID: uuid.New(), Confidence: 0.5, ValidFrom: now.Add(-time.Hour)},
+		{ID: uuid.New(), Confidence: 0.9, ValidFrom: now.Add(-365 * 24 * time.Hour)},
+	}
+	first := decs[0].ID
+
+	svc := &Service{logger: testLogger()}
+	svc.SetOrgSettingsReader(staticOrgSettings{})
+	svc.applyPrecedentDecay(context.Background(), uuid.New(), decs, now)
+
+	assert.Equal(t, first, decs[0].ID, "order is unchanged without a policy")
+	assert.Nil(t, decs[0].EffectiveConfidence)
+	assert.Nil(t, decs[1].EffectiveConfidence)
+}
+
+func TestCapContextSnapshot(t *testing.T) {
+	tools := make([]string, 300)
+	for i := range tools {
+		tools[i] = fmt.Sprintf("tool_%03d", i)
+	}
+	snapshot := &model.ContextSnapshot{Model: "gpt-4o", Tools: tools}
+
+	t.Run("uncapped", func(t *testing.T) {
+		svc := &Service{}
+		got, truncated, err := svc.capContextSnapshot(snapshot)
+		require.NoError(t, err)
+		assert.False(t, truncated)
+		assert.Same(t, snapshot, got)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetContextSnapshotLimit(model.MinContextSnapshotBytes, model.ContextSnapshotOversizeTruncate)
+		got, truncated, err := svc.capContextSnapshot(snapshot)
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		assert.LessOrEqual(t, got.Size(), model.MinContextSnapshotBytes)
+		assert.Len(t, snapshot.Tools, 300, "caller's snapshot must not be modified")
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetContextSnapshotLimit(model.MinContextSnapshotBytes, model.ContextSnapshotOversizeReject)
+		_, _, err := svc.capContextSnapshot(snapshot)
+		assert.ErrorIs(t, err, ErrContextSnapshotTooLarge)
+	})
+
+	t.Run("fits", func(t *testing.T) {
+		svc := &Service{}
+		svc.SetContextSnapshotLimit(model.MinContextSnapshotBytes, model.ContextSnapshotOversizeReject)
+		small := &model.ContextSnapshot{Model: "gpt-4o"}
+		got, truncated, err := svc.capContextSnapshot(small)
+		require.NoError(t, err)
+		assert.False(t, truncated)
+		assert.Same(t, small, got)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"log/slog"
 	"math"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -37,6 +38,10 @@ import (
 // ErrEmbeddingDimMismatch is returned when an embedding vector has the wrong number of dimensions.
 var ErrEmbeddingDimMismatch = errors.New("embedding dimension mismatch")
 
+// ErrContextSnapshotTooLarge is returned by Trace when a context snapshot
+// exceeds the configured size cap and the oversize policy is "reject".
+var ErrContextSnapshotTooLarge = errors.New("context_snapshot too large")
+
 // ConflictScorer scores semantic conflicts for new decisions.
 type ConflictScorer interface {
 	ScoreForDecision(ctx context.Context, decisionID, orgID uuid.UUID)
@@ -59,7 +64,20 @@ type Service struct {
 	percentileCache *search.PercentileCache // nil = use log fallback in ReScore.
 	rescoreMetrics  *search.ReScoreMetrics  // nil = skip signal contribution recording.
 	standardTypes   map[string]bool         // nil = use quality.DefaultStandardDecisionTypes.
+	completeness    *quality.Weights        // nil = use quality.DefaultWeights.
 	autoAssessor    AutoAssessor            // nil = skip auto-assessment.
+	orgSettings     OrgSettingsReader       // nil = no per-org precedent decay in Check.
+	batchWindow     time.Duration           // 0 = traced decisions are not batched.
+	confPrecision   int                     // 0 = confidence is stored exactly.
+	revisionReader  RevisionReader          // nil = no flip-flop detection.
+	flipFlopMin     int
+	flipFlopWindow  time.Duration
+	dupFinder       DuplicateFinder // nil = Dedupe traces are always recorded.
+	dupThreshold    float64
+
+	auditSink               AuditSink // nil = decisions are not mirrored.
+	contextSnapshotMaxBytes int       // 0 = context snapshots are not size-capped.
+	contextSnapshotReject   bool      // true = reject oversized snapshots instead of truncating.
 
 	// asyncWg tracks in-flight post-trace goroutines (claim generation,
 	// conflict scoring) so Shutdown can wait for them before closing the DB.
@@ -89,6 +107,85 @@ type AutoAssessor interface {
 // supersession, citation, and conflict resolution signals.
 func (s *Service) SetAutoAssessor(a AutoAssessor) { s.autoAssessor = a }
 
+// OrgSettingsReader loads an org's settings. Implemented by *storage.DB.
+type OrgSettingsReader interface {
+	GetOrgSettings(ctx context.Context, orgID uuid.UUID) (model.OrgSettings, error)
+}
+
+// SetOrgSettingsReader enables org-level policies that Check honors, such as
+// precedent_decay.
+func (s *Service) SetOrgSettingsReader(r OrgSettingsReader) { s.orgSettings = r }
+
+// AuditSink mirrors written decisions to an external append-only store.
+// Implemented by *auditsink.Writer.
+type AuditSink interface {
+	// Blocking reports whether Mirror must run inside the trace transaction.
+	Blocking() bool
+	Mirror(ctx context.Context, d model.Decision) error
+}
+
+// SetAuditSink mirrors every traced decision to sink. A blocking sink is
+// written before the trace commits, so its failure fails the trace; other
+// sinks are handed the decision after commit.
+func (s *Service) SetAuditSink(sink AuditSink) { s.auditSink = sink }
+
+// SetBatchWindow groups decisions an agent traces within d of each other in
+// the same session under one batch_id. Zero disables batching.
+func (s *Service) SetBatchWindow(d time.Duration) { s.batchWindow = d }
+
+// SetConfidencePrecision rounds traced confidence to precision decimal places
+// before it is stored and hashed, recording the precision in the content
+// hash version. Zero keeps confidence exact.
+func (s *Service) SetConfidencePrecision(precision int) { s.confPrecision = precision }
+
+// RevisionReader loads a decision's revision chain. Implemented by *storage.DB.
+type RevisionReader interface {
+	GetDecisionRevisions(ctx context.Context, orgID, id uuid.UUID) ([]model.Decision, error)
+}
+
+// SetFlipFlopDetection alerts subscribers when a revision chain reverses its
+// outcome at least minFlips times within window. Zero minFlips disables it.
+func (s *Service) SetFlipFlopDetection(r RevisionReader, minFlips int, window time.Duration) {
+	s.revisionReader = r
+	s.flipFlopMin = minFlips
+	s.flipFlopWindow = window
+}
+
+// DuplicateFinder looks up an agent's recent near-identical decision.
+// Implemented by *storage.DB.
+type DuplicateFinder interface {
+	FindRecentDuplicate(ctx context.Context, orgID uuid.UUID, agentID string, d model.Decision, threshold float64) (*storage.DuplicateMatch, error)
+}
+
+// SetDuplicateDetection lets traces that set TraceInput.Dedupe return an
+// existing decision of the agent whose decision and outcome similarity both
+// reach threshold instead of recording a new one.
+func (s *Service) SetDuplicateDetection(f DuplicateFinder, threshold float64) {
+	s.dupFinder = f
+	s.dupThreshold = threshold
+}
+
+// SetContextSnapshotLimit caps the encoded size of trace context snapshots.
+// Oversized snapshots are truncated, dropping trailing tools, unless policy is
+// model.ContextSnapshotOversizeReject. A zero maxBytes disables the cap.
+func (s *Service) SetContextSnapshotLimit(maxBytes int, policy string) {
+	s.contextSnapshotMaxBytes = maxBytes
+	s.contextSnapshotReject = policy == model.ContextSnapshotOversizeReject
+}
+
+// SetCompletenessWeights sets the factor weights used to compute decision
+// completeness scores at trace time and during backfill.
+func (s *Service) SetCompletenessWeights(w quality.Weights) { s.completeness = &w }
+
+// CompletenessScore scores d with the configured completeness weights.
+func (s *Service) CompletenessScore(d model.TraceDecision, hasPrecedentRef bool) float32 {
+	w := quality.DefaultWeights
+	if s.completeness != nil {
+		w = *s.completeness
+	}
+	return quality.ScoreWithWeights(d, hasPrecedentRef, w)
+}
+
 // AssessConflictResolution delegates to the auto-assessor to record outcome
 // assessments for conflict winners and losers. No-op when auto-assessor is nil.
 func (s *Service) AssessConflictResolution(ctx context.Context, orgID, winnerID, loserID uuid.UUID) {
@@ -216,10 +313,22 @@ type TraceInput struct {
 	Decision        model.TraceDecision
 	PrecedentRef    *uuid.UUID
 	PrecedentReason *string
-	SupersedesID    *uuid.UUID     // Decision this one explicitly replaces.
-	SessionID       *uuid.UUID     // MCP session or X-Akashi-Session header.
-	AgentContext    map[string]any // Merged server-extracted + client-supplied context.
-	APIKeyID        *uuid.UUID     // Managed API key that authenticated this request.
+	SupersedesID    *uuid.UUID             // Decision this one explicitly replaces.
+	SessionID       *uuid.UUID             // MCP session or X-Akashi-Session header.
+	AgentContext    map[string]any         // Merged server-extracted + client-supplied context.
+	ContextSnapshot *model.ContextSnapshot // Typed runtime configuration; size-capped by Trace.
+	APIKeyID        *uuid.UUID             // Managed API key that authenticated this request.
+	Namespace       string                 // Decision namespace; empty means model.DefaultNamespace.
+
+	// SupersedeMatchKeys, when set and SupersedesID is nil, supersedes the most
+	// recent active decision of the same type whose metadata matches this
+	// trace's values for every listed key. No match means a plain insert.
+	SupersedeMatchKeys []string
+
+	// Dedupe, when set, returns the agent's recent near-identical decision
+	// (see SetDuplicateDetection) instead of recording this one. Traces that
+	// supersede a decision are always recorded.
+	Dedupe bool
 
 	// AuditMeta, when non-nil, causes the trace to include a mutation audit
 	// record inside the same transaction. This closes the gap where mutations
@@ -239,6 +348,51 @@ type TraceResult struct {
 	// returned an error. Conflict detection and semantic search may be degraded
 	// for this decision.
 	EmbeddingSkipped bool
+	// DuplicateOf is set when a Dedupe trace matched an existing decision.
+	// Nothing was recorded: DecisionID and RunID identify the existing
+	// decision and Decision is empty.
+	DuplicateOf *uuid.UUID
+}
+
+// decisionUsageReader is implemented by stores that track per-org decision
+// usage (Postgres). Lite mode has no org settings, so no policy is enforced.
+type decisionUsageReader interface {
+	OrgSettingsReader
+	GetDecisionUsage(ctx context.Context, orgID uuid.UUID, agentID string, now time.Time) (model.DecisionUsage, error)
+}
+
+// ErrTracePolicy is returned by CheckTracePolicy when a trace does not meet
+// the org's min_alternatives requirement.
+var ErrTracePolicy = errors.New("trace rejected by org policy")
+
+// CheckTracePolicy applies the org's write policies to a single trace by
+// agentID: min_alternatives first, then decision_quota. A min_alternatives
+// violation wraps ErrTracePolicy; an exhausted quota is returned as a
+// *model.QuotaExceededError. HTTP, gRPC, and MCP all call this before Trace.
+func (s *Service) CheckTracePolicy(ctx context.Context, orgID uuid.UUID, agentID, decisionType string, alternatives int) error {
+	store, ok := s.db.(decisionUsageReader)
+	if !ok {
+		return nil
+	}
+	settings, err := store.GetOrgSettings(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("load org settings: %w", err)
+	}
+	if err := settings.Settings.MinAlternatives.Check(decisionType, alternatives); err != nil {
+		return fmt.Errorf("%w: %w", ErrTracePolicy, err)
+	}
+	policy := settings.Settings.DecisionQuota
+	if policy == nil {
+		return nil
+	}
+	usage, err := store.GetDecisionUsage(ctx, orgID, agentID, time.Now())
+	if err != nil {
+		return fmt.Errorf("load decision usage: %w", err)
+	}
+	if exceeded := policy.Check(agentID, usage); exceeded != nil {
+		return exceeded
+	}
+	return nil
 }
 
 // Trace records a complete decision with its alternatives and evidence.
@@ -249,6 +403,15 @@ func (s *Service) Trace(ctx context.Context, orgID uuid.UUID, input TraceInput)
 	if err != nil {
 		return TraceResult{}, err
 	}
+	if input.Dedupe {
+		if dup := s.findDuplicate(ctx, orgID, params); dup != nil {
+			return TraceResult{
+				RunID:       dup.RunID,
+				DecisionID:  dup.DecisionID,
+				DuplicateOf: &dup.DecisionID,
+			}, nil
+		}
+	}
 
 	var run model.AgentRun
 	var decision model.Decision
@@ -261,6 +424,9 @@ func (s *Service) Trace(ctx context.Context, orgID uuid.UUID, input TraceInput)
 		return TraceResult{}, fmt.Errorf("trace: %w", err)
 	}
 
+	// prepareTrace may have resolved SupersedesID from SupersedeMatchKeys.
+	input.SupersedesID = params.Decision.SupersedesID
+	s.mirrorAfterCommit(ctx, decision)
 	s.postTraceAsync(ctx, orgID, input, decision)
 	return TraceResult{
 		RunID:            run.ID,
@@ -271,6 +437,64 @@ func (s *Service) Trace(ctx context.Context, orgID uuid.UUID, input TraceInput)
 	}, nil
 }
 
+// findDuplicate returns the agent's near-identical recent decision for a
+// prepared trace, or nil when there is none or duplicate detection does not
+// apply. Lookup errors are logged and treated as no match so the trace is
+// still recorded.
+func (s *Service) findDuplicate(ctx context.Context, orgID uuid.UUID, params storage.CreateTraceParams) *storage.DuplicateMatch {
+	if s.dupFinder == nil || params.Decision.Embedding == nil || params.Decision.SupersedesID != nil {
+		return nil
+	}
+	dup, err := s.dupFinder.FindRecentDuplicate(ctx, orgID, params.AgentID, params.Decision, s.dupThreshold)
+	if err != nil {
+		s.logger.Warn("trace: duplicate lookup failed, recording decision",
+			"agent_id", params.AgentID, "error", err)
+		return nil
+	}
+	return dup
+}
+
+// TraceBatch records several decisions atomically: every trace is prepared
+// (embeddings, quality scores), then all are written in one transaction so
+// either all commit or none do. Results are returned in input order. A failure
+// attributable to one trace is wrapped in a *storage.TraceBatchError.
+func (s *Service) TraceBatch(ctx context.Context, orgID uuid.UUID, inputs []TraceInput) ([]TraceResult, error) {
+	params := make([]storage.CreateTraceParams, len(inputs))
+	for i, input := range inputs {
+		p, err := s.prepareTrace(ctx, orgID, input)
+		if err != nil {
+			return nil, &storage.TraceBatchError{Index: i, Err: err}
+		}
+		params[i] = p
+	}
+
+	var runs []model.AgentRun
+	var decs []model.Decision
+	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
+		var txErr error
+		runs, decs, txErr = s.db.CreateTracesTx(ctx, params)
+		return txErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("trace batch: %w", err)
+	}
+
+	results := make([]TraceResult, len(inputs))
+	for i, input := range inputs {
+		input.SupersedesID = params[i].Decision.SupersedesID
+		s.mirrorAfterCommit(ctx, decs[i])
+		s.postTraceAsync(ctx, orgID, input, decs[i])
+		results[i] = TraceResult{
+			RunID:            runs[i].ID,
+			DecisionID:       decs[i].ID,
+			EventCount:       len(params[i].Alternatives) + len(params[i].Evidence) + 1,
+			Decision:         decs[i],
+			EmbeddingSkipped: decs[i].Embedding == nil,
+		}
+	}
+	return results, nil
+}
+
 // AdjudicateConflictWithTrace creates an adjudication decision trace AND resolves a
 // conflict in a single atomic transaction. This prevents the failure mode where
 // an adjudication decision is created but the conflict remains unresolved due to
@@ -292,6 +516,9 @@ func (s *Service) AdjudicateConflictWithTrace(ctx context.Context, orgID uuid.UU
 		return TraceResult{}, fmt.Errorf("trace+adjudicate: %w", err)
 	}
 
+	// prepareTrace may have resolved SupersedesID from SupersedeMatchKeys.
+	input.SupersedesID = params.Decision.SupersedesID
+	s.mirrorAfterCommit(ctx, decision)
 	s.postTraceAsync(ctx, orgID, input, decision)
 	return TraceResult{
 		RunID:            run.ID,
@@ -332,6 +559,26 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 		input.Decision.DecisionType = suggested
 	}
 
+	// 0c. Resolve supersede_matching against the normalized decision type so
+	// aliases ("refactoring" vs "refactor") find the same standing decision.
+	if len(input.SupersedeMatchKeys) > 0 && input.SupersedesID == nil {
+		match := make(map[string]any, len(input.SupersedeMatchKeys))
+		for _, k := range input.SupersedeMatchKeys {
+			match[k] = input.Metadata[k]
+		}
+		id, err := s.db.FindSupersedeMatch(ctx, orgID, input.AgentID, input.Namespace, input.Decision.DecisionType, match)
+		if err != nil {
+			return storage.CreateTraceParams{}, fmt.Errorf("trace: resolve supersede_matching: %w", err)
+		}
+		input.SupersedesID = id
+	}
+
+	// 0d. Enforce the context snapshot size cap before any expensive work.
+	snapshot, snapshotTruncated, err := s.capContextSnapshot(input.ContextSnapshot)
+	if err != nil {
+		return storage.CreateTraceParams{}, err
+	}
+
 	// 0a. Set OTEL span attributes for trace correlation.
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
@@ -348,13 +595,14 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 		embText += " " + *input.Decision.Reasoning
 	}
 	var decisionEmb, outcomeEmb *pgvector.Vector
+	var decisionEmbModel string
 	var decEmbErr error
 	var embWg sync.WaitGroup
 	embWg.Add(2)
 	go func() {
 		defer embWg.Done()
 		embStart := time.Now()
-		emb, err := s.embedder.Embed(ctx, embText)
+		emb, modelName, err := embedding.EmbedWithModel(ctx, s.embedder, embText)
 		if err != nil {
 			s.logger.Warn("trace: decision embedding failed, continuing without", "error", err)
 			return
@@ -365,6 +613,7 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 		}
 		s.embeddingDuration.Record(ctx, float64(time.Since(embStart).Milliseconds()))
 		decisionEmb = &emb
+		decisionEmbModel = modelName
 	}()
 	go func() {
 		defer embWg.Done()
@@ -377,6 +626,10 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 	if decEmbErr != nil {
 		return storage.CreateTraceParams{}, decEmbErr
 	}
+	var embeddingModel *string
+	if decisionEmb != nil {
+		embeddingModel = embeddingModelTag(decisionEmbModel)
+	}
 	if decisionEmb == nil {
 		s.embeddingSkips.Add(ctx, 1)
 		s.logger.Warn("trace: decision stored without embedding — semantic search and conflict detection degraded",
@@ -386,7 +639,7 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 	}
 
 	// 2. Compute quality score.
-	qualityScore := quality.Score(input.Decision, input.PrecedentRef != nil)
+	qualityScore := s.CompletenessScore(input.Decision, input.PrecedentRef != nil)
 
 	// 2a. Adjust confidence based on evidence, alternatives, and reasoning.
 	// This deflates self-reported confidence that isn't supported by substance.
@@ -407,6 +660,12 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 		}
 		input.Metadata["original_confidence"] = confAdj.Original
 		input.Metadata["confidence_adjustment_reasons"] = confAdj.Reasons
+		// Deflation can push the point estimate below the agent's lower bound.
+		// Widen the interval downward so the stored bounds still bracket it.
+		if input.Decision.ConfidenceLow != nil && *input.Decision.ConfidenceLow > confAdj.Adjusted {
+			low := confAdj.Adjusted
+			input.Decision.ConfidenceLow = &low
+		}
 	}
 
 	// 2b. Bootstrap metadata from agent_context when agent-supplied metadata
@@ -499,23 +758,78 @@ func (s *Service) prepareTrace(ctx context.Context, orgID uuid.UUID, input Trace
 			DecisionType:      input.Decision.DecisionType,
 			Outcome:           input.Decision.Outcome,
 			Confidence:        input.Decision.Confidence,
+			ConfidenceLow:     input.Decision.ConfidenceLow,
+			ConfidenceHigh:    input.Decision.ConfidenceHigh,
 			Reasoning:         input.Decision.Reasoning,
 			Embedding:         decisionEmb,
 			OutcomeEmbedding:  outcomeEmb,
+			EmbeddingModel:    embeddingModel,
 			CompletenessScore: qualityScore,
 			PrecedentRef:      input.PrecedentRef,
 			PrecedentReason:   input.PrecedentReason,
 			SupersedesID:      input.SupersedesID,
 			APIKeyID:          input.APIKeyID,
+			Namespace:         input.Namespace,
+
+			ContextSnapshot:          snapshot,
+			ContextSnapshotTruncated: snapshotTruncated,
 		},
 		Alternatives: alts,
 		Evidence:     evs,
 		SessionID:    input.SessionID,
 		AgentContext: input.AgentContext,
+		BatchWindow:  s.batchWindow,
 		AuditEntry:   auditEntry,
+		BeforeCommit: s.mirrorBeforeCommit(),
+
+		ConfidencePrecision: s.confPrecision,
 	}, nil
 }
 
+// mirrorBeforeCommit returns the in-transaction hook for a blocking audit
+// sink, or nil when there is none.
+func (s *Service) mirrorBeforeCommit() func(context.Context, model.Decision) error {
+	if s.auditSink == nil || !s.auditSink.Blocking() {
+		return nil
+	}
+	return func(ctx context.Context, d model.Decision) error {
+		if err := s.auditSink.Mirror(ctx, d); err != nil {
+			return fmt.Errorf("audit sink: %w", err)
+		}
+		return nil
+	}
+}
+
+// mirrorAfterCommit hands a committed decision to a non-blocking audit sink.
+func (s *Service) mirrorAfterCommit(ctx context.Context, d model.Decision) {
+	if s.auditSink == nil || s.auditSink.Blocking() {
+		return
+	}
+	if err := s.auditSink.Mirror(ctx, d); err != nil {
+		s.logger.Error("audit sink: mirror decision failed", "decision_id", d.ID, "error", err)
+	}
+}
+
+// capContextSnapshot applies the configured size cap to a trace's context
+// snapshot, returning the snapshot to store and whether it was truncated. The
+// caller's snapshot is never modified.
+func (s *Service) capContextSnapshot(snapshot *model.ContextSnapshot) (*model.ContextSnapshot, bool, error) {
+	if snapshot == nil || s.contextSnapshotMaxBytes <= 0 {
+		return snapshot, false, nil
+	}
+	size := snapshot.Size()
+	if size <= s.contextSnapshotMaxBytes {
+		return snapshot, false, nil
+	}
+	if s.contextSnapshotReject {
+		return nil, false, fmt.Errorf("%w: %d bytes exceeds maximum of %d",
+			ErrContextSnapshotTooLarge, size, s.contextSnapshotMaxBytes)
+	}
+	capped := *snapshot
+	capped.Truncate(s.contextSnapshotMaxBytes)
+	return &capped, true, nil
+}
+
 // postTraceAsync handles post-commit work: subscriber notification and
 // asynchronous claim generation + conflict scoring. All operations are
 // non-fatal — the trace is already committed.
@@ -533,6 +847,30 @@ func (s *Service) postTraceAsync(ctx context.Context, orgID uuid.UUID, input Tra
 		s.logger.Error("trace: notify subscribers", "error", err)
 	}
 
+	// An agent reversing its own verdict is the highest-signal revision for
+	// oversight; alert subscribers separately so they need not diff outcomes.
+	if decision.OutcomeFlipped && input.SupersedesID != nil {
+		s.notifyOutcomeFlip(ctx, orgID, input, decision)
+
+		// A single flip can be a legitimate correction; repeated flips in a
+		// short window are a pattern. Walking the chain is a recursive query,
+		// so it runs off the request path.
+		if s.revisionReader != nil && s.flipFlopMin > 0 {
+			s.asyncWg.Add(1)
+			go func() {
+				defer s.asyncWg.Done()
+				defer func() {
+					if rec := recover(); rec != nil {
+						s.logger.Error("trace: flip-flop detection panicked", "panic", rec, "decision_id", decision.ID)
+					}
+				}()
+				detectCtx, cancel := context.WithTimeout(s.shutdownCtx, 10*time.Second)
+				defer cancel()
+				s.detectFlipFlop(detectCtx, orgID, decision)
+			}()
+		}
+	}
+
 	// Generate claim-level embeddings for fine-grained conflict detection.
 	// Must complete BEFORE conflict scoring so the scorer can use claims.
 	if decision.Embedding != nil {
@@ -611,12 +949,73 @@ func (s *Service) postTraceAsync(ctx context.Context, orgID uuid.UUID, input Tra
 	}
 }
 
+// notifyOutcomeFlip publishes an outcome_flipped event on the decisions
+// channel. Non-fatal: the revision and its flag are already committed.
+func (s *Service) notifyOutcomeFlip(ctx context.Context, orgID uuid.UUID, input TraceInput, decision model.Decision) {
+	s.logger.Info("trace: revision flipped outcome",
+		"decision_id", decision.ID, "superseded_id", *input.SupersedesID,
+		"agent_id", input.AgentID, "org_id", orgID)
+	payload, err := json.Marshal(map[string]any{
+		"source":        "outcome_flipped",
+		"decision_id":   decision.ID,
+		"superseded_id": *input.SupersedesID,
+		"agent_id":      input.AgentID,
+		"org_id":        orgID,
+		"decision_type": decision.DecisionType,
+		"outcome":       decision.Outcome,
+	})
+	if err != nil {
+		s.logger.Error("trace: marshal outcome flip payload", "error", err)
+		return
+	}
+	if err := s.db.Notify(ctx, storage.ChannelDecisions, string(payload)); err != nil {
+		s.logger.Error("trace: notify outcome flip", "decision_id", decision.ID, "error", err)
+	}
+}
+
+// detectFlipFlop scans the revision chain ending at decision and publishes a
+// flip_flop event on the decisions channel when it reverses its outcome too
+// often within the configured window. Non-fatal.
+func (s *Service) detectFlipFlop(ctx context.Context, orgID uuid.UUID, decision model.Decision) {
+	chain, err := s.revisionReader.GetDecisionRevisions(ctx, orgID, decision.ID)
+	if err != nil {
+		s.logger.Warn("trace: flip-flop detection: load revisions", "decision_id", decision.ID, "error", err)
+		return
+	}
+	ff, ok := model.DetectFlipFlop(chain, s.flipFlopWindow, s.flipFlopMin)
+	if !ok {
+		return
+	}
+
+	s.logger.Info("trace: revision chain flip-flopping",
+		"decision_id", decision.ID, "agent_id", decision.AgentID,
+		"org_id", orgID, "flip_count", ff.FlipCount)
+	payload, err := json.Marshal(map[string]any{
+		"source":        "flip_flop",
+		"decision_id":   decision.ID,
+		"agent_id":      decision.AgentID,
+		"org_id":        orgID,
+		"decision_type": decision.DecisionType,
+		"flip_count":    ff.FlipCount,
+		"first_flip_at": ff.FirstFlipAt,
+		"last_flip_at":  ff.LastFlipAt,
+	})
+	if err != nil {
+		s.logger.Error("trace: marshal flip-flop payload", "error", err)
+		return
+	}
+	if err := s.db.Notify(ctx, storage.ChannelDecisions, string(payload)); err != nil {
+		s.logger.Error("trace: notify flip-flop", "decision_id", decision.ID, "error", err)
+	}
+}
+
 // CheckInput holds the parameters for a precedent check.
 type CheckInput struct {
 	DecisionType string
 	Query        string
 	AgentID      string
 	Project      string
+	Namespace    string
 	Limit        int
 }
 
@@ -639,6 +1038,9 @@ func (s *Service) Check(ctx context.Context, orgID uuid.UUID, input CheckInput)
 	if input.Project != "" {
 		filters.Project = &input.Project
 	}
+	if input.Namespace != "" {
+		filters.Namespace = &input.Namespace
+	}
 
 	// Run the three independent lookups concurrently.
 	var (
@@ -725,6 +1127,8 @@ func (s *Service) Check(ctx context.Context, orgID uuid.UUID, input CheckInput)
 		return model.CheckResponse{}, searchErr
 	}
 
+	s.applyPrecedentDecay(ctx, orgID, decisions, time.Now())
+
 	resp := model.CheckResponse{
 		HasPrecedent:         len(decisions) > 0,
 		Decisions:            decisions,
@@ -736,6 +1140,59 @@ func (s *Service) Check(ctx context.Context, orgID uuid.UUID, input CheckInput)
 	return resp, nil
 }
 
+// maxAgentSuggestions caps the close matches SuggestAgentIDs returns per
+// unknown agent_id.
+const maxAgentSuggestions = 3
+
+// SuggestAgentIDs returns existing agent_ids that look like misspellings of
+// the given ones, for turning an empty check or query result caused by a
+// wrong agent_id into a hint. agent_ids that exist contribute nothing; the
+// result is deduplicated, in input order.
+func (s *Service) SuggestAgentIDs(ctx context.Context, orgID uuid.UUID, agentIDs []string) ([]string, error) {
+	var suggestions []string
+	seen := map[string]bool{}
+	for _, agentID := range agentIDs {
+		ids, err := s.db.SuggestAgentIDs(ctx, orgID, agentID, maxAgentSuggestions)
+		if err != nil {
+			return nil, fmt.Errorf("suggest agent ids: %w", err)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				suggestions = append(suggestions, id)
+			}
+		}
+	}
+	return suggestions, nil
+}
+
+// applyPrecedentDecay sets EffectiveConfidence on each decision per the org's
+// precedent_decay policy and re-ranks them by it, highest first. Ties keep
+// their retrieval order. Without a policy the decisions are left untouched; a
+// settings lookup failure is logged and treated the same way.
+func (s *Service) applyPrecedentDecay(ctx context.Context, orgID uuid.UUID, decisions []model.Decision, now time.Time) {
+	if s.orgSettings == nil || len(decisions) == 0 {
+		return
+	}
+	settings, err := s.orgSettings.GetOrgSettings(ctx, orgID)
+	if err != nil {
+		s.logger.Warn("check: load precedent decay policy", "org_id", orgID, "error", err)
+		return
+	}
+	policy := settings.Settings.PrecedentDecay
+	if policy == nil {
+		return
+	}
+	for i := range decisions {
+		d := &decisions[i]
+		eff := policy.EffectiveConfidence(d.Confidence, d.DecisionType, now.Sub(d.ValidFrom))
+		d.EffectiveConfidence = &eff
+	}
+	sort.SliceStable(decisions, func(i, j int) bool {
+		return *decisions[i].EffectiveConfidence > *decisions[j].EffectiveConfidence
+	})
+}
+
 // Search performs semantic or text-based search over decisions.
 // Fallback chain: Qdrant (semantic) → ILIKE text search (keyword).
 // When semantic is true and Qdrant is healthy, it queries Qdrant and hydrates
@@ -745,7 +1202,7 @@ func (s *Service) Search(ctx context.Context, orgID uuid.UUID, query string, sem
 	if semantic && s.searcher != nil {
 		if err := s.searcher.Healthy(ctx); err == nil {
 			embStart := time.Now()
-			queryEmb, err := s.embedder.Embed(ctx, query)
+			queryEmb, queryModel, err := embedding.EmbedWithModel(ctx, s.embedder, query)
 			if err != nil {
 				s.logger.Warn("search: embedding failed, falling back to text", "error", err)
 			} else if !isZeroVector(queryEmb) {
@@ -757,7 +1214,18 @@ func (s *Service) Search(ctx context.Context, orgID uuid.UUID, query string, sem
 				case err != nil:
 					s.logger.Warn("search: qdrant query failed, falling back to text", "error", err)
 				case len(results) > 0:
-					return s.hydrateAndReScore(ctx, orgID, results, limit)
+					hydrated, err := s.hydrateAndReScore(ctx, orgID, results, embeddingModelTag(queryModel), limit, filters.RecencyHalfLifeDays)
+					if err != nil {
+						return nil, err
+					}
+					// Qdrant points carry no namespace, so scope after hydration.
+					if filters.Namespace != nil {
+						hydrated = filterSearchResultsByNamespace(hydrated, *filters.Namespace)
+					}
+					if len(hydrated) > 0 {
+						return hydrated, nil
+					}
+					s.logger.Debug("search: no usable qdrant results, falling back to text")
 				default:
 					s.logger.Debug("search: qdrant returned no results, falling back to text")
 				}
@@ -771,8 +1239,11 @@ func (s *Service) Search(ctx context.Context, orgID uuid.UUID, query string, sem
 }
 
 // hydrateAndReScore fetches full decisions from Postgres, enriches them with outcome signals,
-// and applies completeness+outcome+recency re-scoring (spec 36).
-func (s *Service) hydrateAndReScore(ctx context.Context, orgID uuid.UUID, results []search.Result, limit int) ([]model.SearchResult, error) {
+// and applies completeness+outcome+recency re-scoring (spec 36). queryModel
+// is the model that embedded the query; results from other models are
+// dropped. A non-nil recencyHalfLifeDays replaces the default recency
+// half-life.
+func (s *Service) hydrateAndReScore(ctx context.Context, orgID uuid.UUID, results []search.Result, queryModel *string, limit int, recencyHalfLifeDays *float64) ([]model.SearchResult, error) {
 	if len(results) == 0 {
 		return []model.SearchResult{}, nil
 	}
@@ -787,6 +1258,30 @@ func (s *Service) hydrateAndReScore(ctx context.Context, orgID uuid.UUID, result
 		return nil, fmt.Errorf("search: hydrate decisions: %w", err)
 	}
 
+	// Qdrant points carry no embedding model either. Mid-migration the
+	// neighbours may come from the previous model's vector space, where the
+	// query vector's similarity scores mean nothing, so drop them.
+	var crossModel int
+	for id, d := range decisions {
+		if !model.EmbeddingModelsCompatible(queryModel, d.EmbeddingModel) {
+			delete(decisions, id)
+			crossModel++
+		}
+	}
+	if len(decisions) == 0 {
+		if crossModel > 0 {
+			s.logger.Warn("search: no same-model neighbors, skipping cross-model results",
+				"embedding_model", *queryModel, "cross_model_results", crossModel)
+		}
+		return []model.SearchResult{}, nil
+	}
+	if crossModel > 0 {
+		ids = ids[:0]
+		for id := range decisions {
+			ids = append(ids, id)
+		}
+	}
+
 	// Enrich with outcome signals (3 batched SQL queries, no N+1).
 	signals, err := s.db.GetDecisionOutcomeSignalsBatch(ctx, ids, orgID)
 	if err != nil {
@@ -818,10 +1313,11 @@ func (s *Service) hydrateAndReScore(ctx context.Context, orgID uuid.UUID, result
 		}
 	}
 
-	// Build ReScore options: percentile normalization + signal contribution metrics.
+	// Build ReScore options: percentile normalization, signal contribution
+	// metrics, and the caller's recency half-life.
 	var opts *search.ReScoreOpts
-	if s.percentileCache != nil || s.rescoreMetrics != nil {
-		opts = &search.ReScoreOpts{Ctx: ctx}
+	if s.percentileCache != nil || s.rescoreMetrics != nil || recencyHalfLifeDays != nil {
+		opts = &search.ReScoreOpts{Ctx: ctx, RecencyHalfLifeDays: recencyHalfLifeDays}
 		if s.percentileCache != nil {
 			opts.Percentiles = s.percentileCache.Get(orgID)
 		}
@@ -833,6 +1329,26 @@ func (s *Service) hydrateAndReScore(ctx context.Context, orgID uuid.UUID, result
 	return search.ReScore(results, decisions, limit, opts), nil
 }
 
+// filterSearchResultsByNamespace drops results outside the given namespace.
+func filterSearchResultsByNamespace(results []model.SearchResult, ns string) []model.SearchResult {
+	kept := results[:0]
+	for _, r := range results {
+		if r.Decision.Namespace == ns {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// embeddingModelTag returns the model name to record alongside a vector, or
+// nil when the provider did not report one.
+func embeddingModelTag(name string) *string {
+	if name == "" || name == "unknown" {
+		return nil
+	}
+	return &name
+}
+
 // validateEmbeddingDims checks that the vector has the expected number of dimensions.
 func (s *Service) validateEmbeddingDims(v pgvector.Vector) error {
 	expected := s.embedder.Dimensions()
@@ -866,10 +1382,12 @@ func (s *Service) QueryTemporal(ctx context.Context, orgID uuid.UUID, req model.
 }
 
 // Recent returns recent decisions with optional filters and pagination.
-func (s *Service) Recent(ctx context.Context, orgID uuid.UUID, filters model.QueryFilters, limit, offset int) ([]model.Decision, int, error) {
+// Alternatives are always loaded; extra relations (e.g. "evidence", "flags")
+// can be requested via include.
+func (s *Service) Recent(ctx context.Context, orgID uuid.UUID, filters model.QueryFilters, limit, offset int, include ...string) ([]model.Decision, int, error) {
 	return s.db.QueryDecisions(ctx, orgID, model.QueryRequest{
 		Filters:  filters,
-		Include:  []string{"alternatives"},
+		Include:  append([]string{"alternatives"}, include...),
 		OrderBy:  "valid_from",
 		OrderDir: "desc",
 		Limit:    limit,
@@ -1065,10 +1583,40 @@ func (s *Service) SemanticSearchAvailable() bool {
 // can match either the service-level or storage-level sentinel.
 var ErrAgentNotFound = fmt.Errorf("agent_id not found in this organization: %w", storage.ErrAgentNotFound)
 
+// ErrAgentTombstoned indicates the agent_id belongs to a deleted agent whose
+// data has not been purged yet. Traces under it are rejected for every caller
+// role: auto-registering would collide with the retained agents row, and the
+// eventual purge would delete any decisions written under the agent_id.
+var ErrAgentTombstoned = fmt.Errorf("agent_id belongs to a deleted agent and cannot be reused until its data is purged: %w", storage.ErrAgentTombstoned)
+
+// agentTombstoneChecker is implemented by stores that soft-delete agents
+// (Postgres). Lite mode hard-deletes, so there is nothing to check.
+type agentTombstoneChecker interface {
+	IsAgentTombstoned(ctx context.Context, orgID uuid.UUID, agentID string) (bool, error)
+}
+
+// checkAgentTombstone returns ErrAgentTombstoned when agentID names a
+// tombstoned agent in the org.
+func (s *Service) checkAgentTombstone(ctx context.Context, orgID uuid.UUID, agentID string) error {
+	store, ok := s.db.(agentTombstoneChecker)
+	if !ok {
+		return nil
+	}
+	tombstoned, err := store.IsAgentTombstoned(ctx, orgID, agentID)
+	if err != nil {
+		return err
+	}
+	if tombstoned {
+		return ErrAgentTombstoned
+	}
+	return nil
+}
+
 // ResolveOrCreateAgent looks up an agent by agent_id within an org. If the
 // agent does not exist and the caller has admin+ privileges, it auto-registers
 // a trace-only agent (role=agent, no API key). Non-admin callers receive
-// ErrAgentNotFound.
+// ErrAgentNotFound. An agent_id that belongs to a tombstoned agent returns
+// ErrAgentTombstoned regardless of the caller's role.
 //
 // Returns the resolved or newly created agent so callers can avoid a second
 // round-trip to fetch agent metadata (e.g. display name for context enrichment).
@@ -1091,6 +1639,9 @@ func (s *Service) ResolveOrCreateAgent(ctx context.Context, orgID uuid.UUID, age
 	if !errors.Is(err, storage.ErrNotFound) {
 		return model.Agent{}, err
 	}
+	if err := s.checkAgentTombstone(ctx, orgID, agentID); err != nil {
+		return model.Agent{}, err
+	}
 
 	// Non-admin callers cannot auto-register agents.
 	if !model.RoleAtLeast(callerRole, model.RoleAdmin) {
@@ -1126,7 +1677,12 @@ func (s *Service) ResolveOrCreateAgent(ctx context.Context, orgID uuid.UUID, age
 		// duplicate key constraint as success. Return zero agent; the caller
 		// will skip any Name-based enrichment, which is acceptable for this
 		// rare race condition.
+		// The agent may also have been tombstoned in the meantime, in
+		// which case the duplicate is the retained tombstoned row.
 		if s.isDuplicateKey(createErr) {
+			if err := s.checkAgentTombstone(ctx, orgID, agentID); err != nil {
+				return model.Agent{}, err
+			}
 			return model.Agent{}, nil
 		}
 		return model.Agent{}, fmt.Errorf("auto-register agent: %w", createErr)
@@ -1147,22 +1703,95 @@ func (s *Service) BackfillEmbeddings(ctx context.Context, batchSize int) (int, e
 	return s.backfillBatch(ctx, batchSize, backfillSpec{
 		find:  s.db.FindUnembeddedDecisions,
 		text:  embeddingText,
-		write: s.db.BackfillEmbedding,
+		write: s.backfillEmbedding,
 		label: "backfill: embedded decisions",
 	})
 }
 
+// backfillEmbedding writes a decision embedding tagged with the model that
+// produced it.
+func (s *Service) backfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector, modelName string) error {
+	var name string
+	if tag := embeddingModelTag(modelName); tag != nil {
+		name = *tag
+	}
+	return s.db.BackfillEmbedding(ctx, id, orgID, emb, name)
+}
+
 // BackfillOutcomeEmbeddings populates outcome_embedding for decisions that have
 // embedding but no outcome_embedding (Option B). Returns the number backfilled.
 func (s *Service) BackfillOutcomeEmbeddings(ctx context.Context, batchSize int) (int, error) {
 	return s.backfillBatch(ctx, batchSize, backfillSpec{
-		find:  s.db.FindDecisionsMissingOutcomeEmbedding,
-		text:  func(d storage.UnembeddedDecision) string { return d.Outcome },
-		write: s.db.BackfillOutcomeEmbedding,
+		find: s.db.FindDecisionsMissingOutcomeEmbedding,
+		text: func(d storage.UnembeddedDecision) string { return d.Outcome },
+		write: func(ctx context.Context, id, orgID uuid.UUID, vec pgvector.Vector, _ string) error {
+			return s.db.BackfillOutcomeEmbedding(ctx, id, orgID, vec)
+		},
 		label: "backfill: outcome embeddings",
 	})
 }
 
+// EmbeddingDimensions returns the dimensionality of the configured embedding
+// provider's vectors.
+func (s *Service) EmbeddingDimensions() int { return s.embedder.Dimensions() }
+
+// EmbeddingAvailable reports whether a real (non-noop) embedding provider is
+// configured.
+func (s *Service) EmbeddingAvailable() bool {
+	_, isNoop := s.embedder.(*embedding.NoopProvider)
+	return !isNoop
+}
+
+// ReembedDecisions regenerates the embedding, outcome embedding, and claims of
+// decs with the configured provider, overwriting existing vectors. Used when
+// migrating to a new embedding model, whose vectors are incompatible with the
+// old ones. Claims are only generated where none exist, so callers replacing
+// claims delete them first. Returns embedding.ErrNoProvider if no real
+// provider is configured, and the number of decisions re-embedded otherwise.
+func (s *Service) ReembedDecisions(ctx context.Context, decs []storage.UnembeddedDecision) (int, error) {
+	if !s.EmbeddingAvailable() {
+		return 0, embedding.ErrNoProvider
+	}
+	if len(decs) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, 0, 2*len(decs))
+	for _, d := range decs {
+		texts = append(texts, embeddingText(d), d.Outcome)
+	}
+	vecs, modelName, err := embedding.EmbedBatchWithModel(ctx, s.embedder, texts)
+	if err != nil {
+		return 0, fmt.Errorf("reembed: embed batch: %w", err)
+	}
+	if len(vecs) != len(texts) {
+		return 0, fmt.Errorf("reembed: embed batch returned %d vectors for %d inputs", len(vecs), len(texts))
+	}
+
+	var reembedded int
+	for i, d := range decs {
+		emb, outcomeEmb := vecs[2*i], vecs[2*i+1]
+		if err := s.validateEmbeddingDims(emb); err != nil {
+			s.logger.Warn("reembed: dimension mismatch, skipping", "decision_id", d.ID, "error", err)
+			continue
+		}
+		if err := s.backfillEmbedding(ctx, d.ID, d.OrgID, emb, modelName); err != nil {
+			s.logger.Warn("reembed: update embedding failed", "decision_id", d.ID, "error", err)
+			continue
+		}
+		if err := s.db.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, outcomeEmb); err != nil {
+			s.logger.Warn("reembed: update outcome embedding failed", "decision_id", d.ID, "error", err)
+			continue
+		}
+		if err := s.generateClaims(ctx, d.ID, d.OrgID, d.Outcome); err != nil {
+			// The claims backfill retries decisions left without claims.
+			s.logger.Warn("reembed: generate claims failed", "decision_id", d.ID, "error", err)
+		}
+		reembedded++
+	}
+	return reembedded, nil
+}
+
 // embeddingText builds the canonical embedding input for a decision (same
 // format used by prepareTrace).
 func embeddingText(d storage.UnembeddedDecision) string {
@@ -1177,7 +1806,7 @@ func embeddingText(d storage.UnembeddedDecision) string {
 type backfillSpec struct {
 	find  func(ctx context.Context, limit int) ([]storage.UnembeddedDecision, error)
 	text  func(d storage.UnembeddedDecision) string
-	write func(ctx context.Context, id uuid.UUID, orgID uuid.UUID, vec pgvector.Vector) error
+	write func(ctx context.Context, id uuid.UUID, orgID uuid.UUID, vec pgvector.Vector, modelName string) error
 	label string
 }
 
@@ -1202,7 +1831,7 @@ func (s *Service) backfillBatch(ctx context.Context, batchSize int, spec backfil
 		texts[i] = spec.text(d)
 	}
 
-	vecs, err := s.embedder.EmbedBatch(ctx, texts)
+	vecs, modelName, err := embedding.EmbedBatchWithModel(ctx, s.embedder, texts)
 	if err != nil {
 		return 0, fmt.Errorf("%s: embed batch: %w", spec.label, err)
 	}
@@ -1213,7 +1842,7 @@ func (s *Service) backfillBatch(ctx context.Context, batchSize int, spec backfil
 			s.logger.Warn(spec.label+": dimension mismatch, skipping", "decision_id", d.ID, "error", err)
 			continue
 		}
-		if err := spec.write(ctx, d.ID, d.OrgID, vecs[i]); err != nil {
+		if err := spec.write(ctx, d.ID, d.OrgID, vecs[i], modelName); err != nil {
 			s.logger.Warn(spec.label+": update failed", "decision_id", d.ID, "error", err)
 			continue
 		}
@@ -1360,6 +1989,62 @@ func (s *Service) BackfillClaims(ctx context.Context, batchSize int) (int, error
 	return backfilled, nil
 }
 
+// BackfillCompletenessScores computes completeness_score for active decisions
+// stored without one, using the configured weights. It pages through every
+// unscored decision batchSize at a time; decisions whose recomputed score is
+// still zero are left as they are. Returns the number of decisions updated.
+func (s *Service) BackfillCompletenessScores(ctx context.Context, batchSize int) (int, error) {
+	var (
+		after      uuid.UUID
+		backfilled int
+	)
+	for {
+		decs, err := s.db.FindUnscoredDecisions(ctx, after, batchSize)
+		if err != nil {
+			return backfilled, fmt.Errorf("backfill completeness: find: %w", err)
+		}
+		for _, d := range decs {
+			if err := ctx.Err(); err != nil {
+				return backfilled, err
+			}
+			after = d.ID
+			score := s.CompletenessScore(unscoredTraceDecision(d), d.HasPrecedentRef)
+			if score == 0 {
+				continue
+			}
+			if err := s.db.BackfillCompletenessScore(ctx, d.ID, d.OrgID, score); err != nil {
+				s.logger.Warn("backfill completeness: update failed", "decision_id", d.ID, "error", err)
+				continue
+			}
+			backfilled++
+		}
+		if len(decs) == 0 || len(decs) < batchSize {
+			break
+		}
+	}
+
+	if backfilled > 0 {
+		s.logger.Info("backfill: completeness scores", "count", backfilled)
+	}
+	return backfilled, nil
+}
+
+// unscoredTraceDecision rebuilds the scored fields of a stored decision as a
+// TraceDecision. Only rejection reasons and the evidence count matter to the
+// alternatives and evidence factors, so other fields are left empty.
+func unscoredTraceDecision(d storage.UnscoredDecision) model.TraceDecision {
+	td := model.TraceDecision{
+		Outcome:    d.Outcome,
+		Confidence: d.Confidence,
+		Reasoning:  d.Reasoning,
+		Evidence:   make([]model.TraceEvidence, d.EvidenceCount),
+	}
+	for _, reason := range d.RejectionReasons {
+		td.Alternatives = append(td.Alternatives, model.TraceAlternative{RejectionReason: &reason})
+	}
+	return td
+}
+
 // RetryFailedClaimEmbeddings re-attempts claim embedding generation for decisions
 // that failed previously and are eligible for retry (exponential backoff, capped
 // at maxAttempts). On success, clears the failure state and triggers conflict
@@ -1271,6 +1271,54 @@ func TestDrainAsync_CompletesImmediatelyWhenIdle(t *testing.T) {
 	require.NoError(t, err, "DrainAsync should return immediately when no goroutines are in flight")
 }
 
+func TestTrace_DedupeReturnsNearIdenticalDecision(t *testing.T) {
+	ctx := context.Background()
+	agentID := "trace-dedupe-" + uuid.New().String()[:8]
+	createAgent(t, agentID)
+
+	// mockEmbedder gives every text the same vector, so similarity hinges on
+	// the agent/type scoping and the outcome comparison.
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	svc := decisions.New(testDB, &mockEmbedder{dims: 1024}, nil, logger, nil)
+	svc.SetDuplicateDetection(testDB, 0.97)
+
+	trace := func(agent, outcome string, dedupe bool) decisions.TraceResult {
+		t.Helper()
+		res, err := svc.Trace(ctx, uuid.Nil, decisions.TraceInput{
+			AgentID: agent,
+			Decision: model.TraceDecision{
+				DecisionType: "architecture",
+				Outcome:      outcome,
+				Confidence:   0.8,
+			},
+			Dedupe: dedupe,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	first := trace(agentID, "Use PostgreSQL for the job queue", false)
+	require.Nil(t, first.DuplicateOf)
+
+	second := trace(agentID, "use PostgreSQL for the job queue ", true)
+	require.NotNil(t, second.DuplicateOf, "near-identical outcome should be reported as a duplicate")
+	assert.Equal(t, first.DecisionID, *second.DuplicateOf)
+	assert.Equal(t, first.DecisionID, second.DecisionID)
+	assert.Equal(t, first.RunID, second.RunID)
+
+	// Without dedupe the same trace is recorded as a new decision.
+	third := trace(agentID, "use PostgreSQL for the job queue ", false)
+	assert.Nil(t, third.DuplicateOf)
+	assert.NotEqual(t, first.DecisionID, third.DecisionID)
+
+	// Another agent's decisions are never matched.
+	otherAgent := agentID + "-b"
+	createAgent(t, otherAgent)
+	other := trace(otherAgent, "Use PostgreSQL for the job queue", true)
+	assert.Nil(t, other.DuplicateOf)
+	assert.NotEqual(t, first.DecisionID, other.DecisionID)
+}
+
 func TestTrace_SupersedesID_InvalidatesOldDecision(t *testing.T) {
 	ctx := context.Background()
 	agentID := "supersede-" + uuid.New().String()[:8]
@@ -0,0 +1,147 @@
+// Package duplicates finds near-duplicate decisions across an org's corpus:
+// decisions that repeat each other's reasoning and outcome, which usually
+// means redundant work or copy-pasted reasoning. This is distinct from
+// conflict detection, which looks for disagreement rather than redundancy.
+package duplicates
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+const (
+	// neighbors is how many nearest decisions each decision is compared with.
+	neighbors = 10
+	// maxDecisions caps how many of an org's most recent decisions one scan covers.
+	maxDecisions = 5000
+)
+
+// Service runs the periodic near-duplicate scan across all orgs.
+type Service struct {
+	db     *storage.DB
+	floor  float64
+	logger *slog.Logger
+}
+
+// New creates a duplicate scan service. floor is the minimum similarity a
+// pair must reach to be stored; reports can only use thresholds at or above it.
+func New(db *storage.DB, floor float64, logger *slog.Logger) *Service {
+	return &Service{db: db, floor: floor, logger: logger}
+}
+
+// RunOnce rescans every org, replacing its stored near-duplicate pairs.
+// A failing org is logged and skipped.
+func (s *Service) RunOnce(ctx context.Context) error {
+	orgIDs, err := s.db.ListOrganizationIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("duplicates: list orgs: %w", err)
+	}
+	for _, orgID := range orgIDs {
+		scan, err := s.db.RefreshDuplicatePairs(ctx, orgID, s.floor, neighbors, maxDecisions)
+		if err != nil {
+			s.logger.Warn("duplicates: org scan failed", "org_id", orgID, "error", err)
+			continue
+		}
+		if scan.PairsFound > 0 {
+			s.logger.Info("duplicates: org scan complete",
+				"org_id", orgID, "decisions_scanned", scan.DecisionsScanned, "pairs", scan.PairsFound)
+		}
+	}
+	return nil
+}
+
+// Group is a cluster of near-duplicate decision IDs: the connected
+// components of the pair graph. Representative is the member with the most
+// pairs in the group (ties broken by smallest ID); MinSimilarity and
+// MaxSimilarity span the pairs' decision similarities.
+type Group struct {
+	IDs            []uuid.UUID
+	Representative uuid.UUID
+	MaxSimilarity  float64
+	MinSimilarity  float64
+}
+
+// Cluster groups pairs into connected components. Groups are ordered by size
+// descending, then by MaxSimilarity descending; IDs within a group are sorted.
+func Cluster(pairs []storage.DuplicatePair) []Group {
+	parent := make(map[uuid.UUID]uuid.UUID)
+	var find func(uuid.UUID) uuid.UUID
+	find = func(id uuid.UUID) uuid.UUID {
+		p, ok := parent[id]
+		if !ok {
+			parent[id] = id
+			return id
+		}
+		if p == id {
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+
+	degree := make(map[uuid.UUID]int)
+	for _, p := range pairs {
+		ra, rb := find(p.DecisionA), find(p.DecisionB)
+		if ra != rb {
+			parent[ra] = rb
+		}
+		degree[p.DecisionA]++
+		degree[p.DecisionB]++
+	}
+
+	byRoot := make(map[uuid.UUID]*Group)
+	for id := range parent {
+		root := find(id)
+		g, ok := byRoot[root]
+		if !ok {
+			g = &Group{}
+			byRoot[root] = g
+		}
+		g.IDs = append(g.IDs, id)
+	}
+	for _, p := range pairs {
+		g := byRoot[find(p.DecisionA)]
+		if g.MaxSimilarity == 0 || p.Similarity > g.MaxSimilarity {
+			g.MaxSimilarity = p.Similarity
+		}
+		if g.MinSimilarity == 0 || p.Similarity < g.MinSimilarity {
+			g.MinSimilarity = p.Similarity
+		}
+	}
+
+	groups := make([]Group, 0, len(byRoot))
+	for _, g := range byRoot {
+		slices.SortFunc(g.IDs, compareUUID)
+		g.Representative = g.IDs[0]
+		for _, id := range g.IDs[1:] {
+			if degree[id] > degree[g.Representative] {
+				g.Representative = id
+			}
+		}
+		groups = append(groups, *g)
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		if len(a.IDs) != len(b.IDs) {
+			return len(b.IDs) - len(a.IDs)
+		}
+		if a.MaxSimilarity != b.MaxSimilarity {
+			if a.MaxSimilarity > b.MaxSimilarity {
+				return -1
+			}
+			return 1
+		}
+		return compareUUID(a.Representative, b.Representative)
+	})
+	return groups
+}
+
+func compareUUID(a, b uuid.UUID) int {
+	return slices.Compare(a[:], b[:])
+}
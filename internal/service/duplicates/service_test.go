@@ -0,0 +1,37 @@
+package duplicates
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+func TestCluster(t *testing.T) {
+	ids := make([]uuid.UUID, 6)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	pair := func(a, b int, sim float64) storage.DuplicatePair {
+		return storage.DuplicatePair{DecisionA: ids[a], DecisionB: ids[b], Similarity: sim, OutcomeSimilarity: 1}
+	}
+
+	// 0-1-2 form a chain through 1; 3-4 are a separate pair; 5 is a loner.
+	groups := Cluster([]storage.DuplicatePair{
+		pair(0, 1, 0.95),
+		pair(1, 2, 0.92),
+		pair(3, 4, 0.99),
+	})
+
+	require.Len(t, groups, 2)
+	assert.ElementsMatch(t, []uuid.UUID{ids[0], ids[1], ids[2]}, groups[0].IDs, "largest group first")
+	assert.Equal(t, ids[1], groups[0].Representative, "member with the most near-duplicates")
+	assert.InDelta(t, 0.95, groups[0].MaxSimilarity, 1e-9)
+	assert.InDelta(t, 0.92, groups[0].MinSimilarity, 1e-9)
+	assert.ElementsMatch(t, []uuid.UUID{ids[3], ids[4]}, groups[1].IDs)
+
+	assert.Empty(t, Cluster(nil))
+}
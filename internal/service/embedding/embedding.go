@@ -259,6 +259,36 @@ func ProviderModelName(p Provider) string {
 	return "unknown"
 }
 
+// ModelReporter is an optional interface for providers whose model can vary
+// from call to call, such as a FallbackProvider whose backends run different
+// models. The methods return the name of the model that produced the vectors
+// alongside them, so callers can tag stored vectors correctly.
+type ModelReporter interface {
+	EmbedWithModel(ctx context.Context, text string) (pgvector.Vector, string, error)
+	EmbedBatchWithModel(ctx context.Context, texts []string) ([]pgvector.Vector, string, error)
+}
+
+// EmbedWithModel embeds text with p and returns the name of the model that
+// produced the vector. Providers that do not implement ModelReporter report
+// ProviderModelName.
+func EmbedWithModel(ctx context.Context, p Provider, text string) (pgvector.Vector, string, error) {
+	if mr, ok := p.(ModelReporter); ok {
+		return mr.EmbedWithModel(ctx, text)
+	}
+	vec, err := p.Embed(ctx, text)
+	return vec, ProviderModelName(p), err
+}
+
+// EmbedBatchWithModel is the batch form of EmbedWithModel. Every vector in the
+// result comes from the same model.
+func EmbedBatchWithModel(ctx context.Context, p Provider, texts []string) ([]pgvector.Vector, string, error) {
+	if mr, ok := p.(ModelReporter); ok {
+		return mr.EmbedBatchWithModel(ctx, texts)
+	}
+	vecs, err := p.EmbedBatch(ctx, texts)
+	return vecs, ProviderModelName(p), err
+}
+
 // NoopProvider returns zero vectors. Used when no API key is configured.
 type NoopProvider struct {
 	dims int
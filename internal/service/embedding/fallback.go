@@ -0,0 +1,172 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/ashita-ai/akashi/internal/telemetry"
+)
+
+// Backend is a named provider in a FallbackProvider chain. Name identifies
+// the backend in logs and metrics (e.g. "ollama", "openai").
+type Backend struct {
+	Name     string
+	Provider Provider
+}
+
+// FallbackProvider tries an ordered list of providers, moving on to the next
+// one when a call errors or exceeds the per-backend timeout. The first
+// backend that succeeds serves the call.
+//
+// Every backend must produce vectors of the same dimensionality. Vectors from
+// different models are not comparable, so FallbackProvider implements
+// ModelReporter: callers that store vectors record the model of the backend
+// that actually served the call, and cross-model comparisons are skipped.
+type FallbackProvider struct {
+	backends []Backend
+	timeout  time.Duration
+	logger   *slog.Logger
+
+	served metric.Int64Counter // calls served, by backend
+	failed metric.Int64Counter // backend attempts that errored or timed out
+
+	// onServe, when set, is called with the name of the backend that served
+	// each successful call. Used by tests.
+	onServe func(backend string)
+}
+
+// NewFallbackProvider creates a provider that tries backends in order.
+// timeout bounds each backend attempt; zero means attempts are bounded only
+// by the caller's context. Returns an error if backends is empty or their
+// dimensions differ.
+func NewFallbackProvider(backends []Backend, timeout time.Duration, logger *slog.Logger) (*FallbackProvider, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("embedding: fallback chain requires at least one provider")
+	}
+	dims := backends[0].Provider.Dimensions()
+	for _, b := range backends[1:] {
+		if d := b.Provider.Dimensions(); d != dims {
+			return nil, fmt.Errorf("embedding: fallback provider %q has %d dimensions, want %d", b.Name, d, dims)
+		}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	meter := telemetry.Meter("akashi/embedding")
+	served, _ := meter.Int64Counter("akashi.embedding.served",
+		metric.WithDescription("Embedding calls served, by backend"),
+	)
+	failed, _ := meter.Int64Counter("akashi.embedding.backend_failures",
+		metric.WithDescription("Embedding backend attempts that errored or timed out, by backend"),
+	)
+	return &FallbackProvider{
+		backends: backends,
+		timeout:  timeout,
+		logger:   logger,
+		served:   served,
+		failed:   failed,
+	}, nil
+}
+
+// Dimensions returns the embedding vector size shared by every backend.
+func (p *FallbackProvider) Dimensions() int {
+	return p.backends[0].Provider.Dimensions()
+}
+
+// ModelName returns the primary backend's model name, which is what
+// threshold profiles are calibrated against. Use EmbedWithModel to learn
+// which model served a particular call.
+func (p *FallbackProvider) ModelName() string {
+	return ProviderModelName(p.backends[0].Provider)
+}
+
+// Embed generates a single embedding from the first backend that succeeds.
+func (p *FallbackProvider) Embed(ctx context.Context, text string) (pgvector.Vector, error) {
+	vec, _, err := p.EmbedWithModel(ctx, text)
+	return vec, err
+}
+
+// EmbedBatch generates embeddings for multiple texts from the first backend
+// that succeeds. The whole batch is served by a single backend.
+func (p *FallbackProvider) EmbedBatch(ctx context.Context, texts []string) ([]pgvector.Vector, error) {
+	vecs, _, err := p.EmbedBatchWithModel(ctx, texts)
+	return vecs, err
+}
+
+// EmbedWithModel is Embed that also returns the model name of the backend
+// that served the call.
+func (p *FallbackProvider) EmbedWithModel(ctx context.Context, text string) (pgvector.Vector, string, error) {
+	var vec pgvector.Vector
+	served, err := p.try(ctx, func(ctx context.Context, b Provider) error {
+		v, err := b.Embed(ctx, text)
+		if err == nil {
+			vec = v
+		}
+		return err
+	})
+	if err != nil {
+		return pgvector.Vector{}, "", err
+	}
+	return vec, ProviderModelName(served), nil
+}
+
+// EmbedBatchWithModel is EmbedBatch that also returns the model name of the
+// backend that served the batch.
+func (p *FallbackProvider) EmbedBatchWithModel(ctx context.Context, texts []string) ([]pgvector.Vector, string, error) {
+	if len(texts) == 0 {
+		return nil, p.ModelName(), nil
+	}
+	var vecs []pgvector.Vector
+	served, err := p.try(ctx, func(ctx context.Context, b Provider) error {
+		v, err := b.EmbedBatch(ctx, texts)
+		if err == nil {
+			vecs = v
+		}
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return vecs, ProviderModelName(served), nil
+}
+
+// try runs call against each backend in order until one succeeds and returns
+// the provider that served it. It stops early if the caller's context is
+// done, since no later backend can succeed.
+func (p *FallbackProvider) try(ctx context.Context, call func(context.Context, Provider) error) (Provider, error) {
+	errs := make([]error, 0, len(p.backends))
+	for _, b := range p.backends {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, p.timeout)
+		}
+		err := call(attemptCtx, b.Provider)
+		cancel()
+
+		backendAttr := metric.WithAttributes(attribute.String("backend", b.Name))
+		if err == nil {
+			p.served.Add(ctx, 1, backendAttr)
+			if p.onServe != nil {
+				p.onServe(b.Name)
+			}
+			return b.Provider, nil
+		}
+		p.failed.Add(ctx, 1, backendAttr)
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("embedding: %w", errors.Join(errs...))
+		}
+		if !errors.Is(err, ErrNoProvider) {
+			p.logger.Warn("embedding: backend failed, trying next", "backend", b.Name, "error", err)
+		}
+	}
+	return nil, fmt.Errorf("embedding: all providers failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,195 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider returns a fixed vector, an error, or blocks until the context
+// is done, and counts its calls.
+type stubProvider struct {
+	dims  int
+	value float32
+	err   error
+	block bool
+	calls int
+}
+
+func (s *stubProvider) Embed(ctx context.Context, _ string) (pgvector.Vector, error) {
+	vecs, err := s.EmbedBatch(ctx, []string{""})
+	if err != nil {
+		return pgvector.Vector{}, err
+	}
+	return vecs[0], nil
+}
+
+func (s *stubProvider) EmbedBatch(ctx context.Context, texts []string) ([]pgvector.Vector, error) {
+	s.calls++
+	if s.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := make([]pgvector.Vector, len(texts))
+	for i := range texts {
+		vec := make([]float32, s.dims)
+		vec[0] = s.value
+		out[i] = pgvector.NewVector(vec)
+	}
+	return out, nil
+}
+
+func (s *stubProvider) Dimensions() int { return s.dims }
+
+func TestFallbackProvider_FailingPrimaryUsesSecondary(t *testing.T) {
+	primary := &stubProvider{dims: 4, err: errors.New("connection refused")}
+	secondary := &stubProvider{dims: 4, value: 2}
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: primary},
+		{Name: "openai", Provider: secondary},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	var served []string
+	p.onServe = func(backend string) { served = append(served, backend) }
+
+	vec, err := p.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, float32(2), vec.Slice()[0])
+
+	vecs, err := p.EmbedBatch(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	require.Len(t, vecs, 2)
+	assert.Equal(t, float32(2), vecs[1].Slice()[0])
+
+	assert.Equal(t, []string{"openai", "openai"}, served)
+	assert.Equal(t, 2, primary.calls)
+	assert.Equal(t, 2, secondary.calls)
+}
+
+func TestFallbackProvider_PrimaryServesWhenHealthy(t *testing.T) {
+	primary := &stubProvider{dims: 4, value: 1}
+	secondary := &stubProvider{dims: 4, value: 2}
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: primary},
+		{Name: "openai", Provider: secondary},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	var served []string
+	p.onServe = func(backend string) { served = append(served, backend) }
+
+	vec, err := p.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, float32(1), vec.Slice()[0])
+	assert.Equal(t, []string{"ollama"}, served)
+	assert.Equal(t, 0, secondary.calls, "secondary must not be called when primary succeeds")
+}
+
+func TestFallbackProvider_TimeoutFallsThrough(t *testing.T) {
+	primary := &stubProvider{dims: 4, block: true}
+	secondary := &stubProvider{dims: 4, value: 2}
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: primary},
+		{Name: "openai", Provider: secondary},
+	}, 20*time.Millisecond, nil)
+	require.NoError(t, err)
+
+	vec, err := p.Embed(context.Background(), "hello")
+	require.NoError(t, err)
+	assert.Equal(t, float32(2), vec.Slice()[0])
+}
+
+func TestFallbackProvider_AllFail(t *testing.T) {
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: &stubProvider{dims: 4, err: errors.New("down")}},
+		{Name: "noop", Provider: NewNoopProvider(4)},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	_, err = p.Embed(context.Background(), "hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ollama: down")
+	assert.ErrorIs(t, err, ErrNoProvider)
+}
+
+func TestFallbackProvider_CanceledContextStops(t *testing.T) {
+	secondary := &stubProvider{dims: 4, value: 2}
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: &stubProvider{dims: 4, block: true}},
+		{Name: "openai", Provider: secondary},
+	}, 0, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = p.Embed(ctx, "hello")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, secondary.calls)
+}
+
+func TestNewFallbackProvider_Validation(t *testing.T) {
+	_, err := NewFallbackProvider(nil, time.Second, nil)
+	require.Error(t, err)
+
+	_, err = NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: &stubProvider{dims: 1024}},
+		{Name: "openai", Provider: &stubProvider{dims: 1536}},
+	}, time.Second, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "openai")
+}
+
+func TestFallbackProvider_ModelNameIsPrimary(t *testing.T) {
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: NewOllamaProvider("", "mxbai-embed-large", 4)},
+		{Name: "noop", Provider: NewNoopProvider(4)},
+	}, time.Second, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "mxbai-embed-large", p.ModelName())
+	assert.Equal(t, 4, p.Dimensions())
+}
+
+// namedStub is a stubProvider that reports a model name.
+type namedStub struct {
+	stubProvider
+	model string
+}
+
+func (s *namedStub) ModelName() string { return s.model }
+
+func TestFallbackProvider_ReportsServingModel(t *testing.T) {
+	primary := &namedStub{stubProvider: stubProvider{dims: 4, value: 1}, model: "mxbai-embed-large"}
+	secondary := &namedStub{stubProvider: stubProvider{dims: 4, value: 2}, model: "text-embedding-3-small"}
+	p, err := NewFallbackProvider([]Backend{
+		{Name: "ollama", Provider: primary},
+		{Name: "openai", Provider: secondary},
+	}, time.Second, nil)
+	require.NoError(t, err)
+
+	_, modelName, err := EmbedWithModel(context.Background(), p, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "mxbai-embed-large", modelName)
+
+	primary.err = errors.New("connection refused")
+	_, modelName, err = EmbedWithModel(context.Background(), p, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-3-small", modelName, "a call served by the fallback reports the fallback's model")
+	_, modelName, err = EmbedBatchWithModel(context.Background(), p, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-3-small", modelName)
+	assert.Equal(t, "mxbai-embed-large", p.ModelName(), "ModelName stays the primary's")
+
+	// Providers without ModelReporter report their static model name.
+	_, modelName, err = EmbedWithModel(context.Background(), secondary, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-3-small", modelName)
+}
@@ -201,8 +201,59 @@ func ExpectationFor(decisionType string) TypeExpectation {
 	return TypeExpectation{ExpectedMin: 0.40}
 }
 
-// Score computes a completeness score (0.0-1.0) for a trace decision.
-// Higher scores indicate more complete traces (more fields populated).
+// Weights sets the maximum contribution of each completeness factor. Tiered
+// factors award a fixed fraction of their weight per tier, and the total is
+// divided by the sum of the weights, so weights need not add up to 1.
+type Weights struct {
+	Confidence   float32 `json:"confidence"`
+	Reasoning    float32 `json:"reasoning"`
+	Alternatives float32 `json:"alternatives"`
+	Evidence     float32 `json:"evidence"`
+	Outcome      float32 `json:"outcome"`
+	Precedent    float32 `json:"precedent"`
+}
+
+// DefaultWeights are the built-in factor weights. They sum to 1.0.
+var DefaultWeights = Weights{
+	Confidence:   0.15,
+	Reasoning:    0.30,
+	Alternatives: 0.20,
+	Evidence:     0.15,
+	Outcome:      0.10,
+	Precedent:    0.10,
+}
+
+// BuildWeights returns DefaultWeights with the factors named in overrides
+// replaced. Keys are the Weights JSON names; unknown keys are ignored. If
+// overrides is nil or empty, returns DefaultWeights.
+func BuildWeights(overrides map[string]float64) Weights {
+	w := DefaultWeights
+	for k, v := range overrides {
+		switch k {
+		case "confidence":
+			w.Confidence = float32(v)
+		case "reasoning":
+			w.Reasoning = float32(v)
+		case "alternatives":
+			w.Alternatives = float32(v)
+		case "evidence":
+			w.Evidence = float32(v)
+		case "outcome":
+			w.Outcome = float32(v)
+		case "precedent":
+			w.Precedent = float32(v)
+		}
+	}
+	return w
+}
+
+func (w Weights) sum() float32 {
+	return w.Confidence + w.Reasoning + w.Alternatives + w.Evidence + w.Outcome + w.Precedent
+}
+
+// Score computes a completeness score (0.0-1.0) for a trace decision using
+// DefaultWeights. Higher scores indicate more complete traces (more fields
+// populated).
 //
 // Scoring is UNIFORM — every decision type uses the same weights. Per-type
 // differentiation happens via tips (profile-aware) and health thresholds
@@ -222,48 +273,60 @@ func ExpectationFor(decisionType string) TypeExpectation {
 // The former "standard decision type" factor (0.10) was removed; its weight
 // was redistributed to reasoning (+0.05) and outcome (+0.05).
 func Score(d model.TraceDecision, hasPrecedentRef bool) float32 {
+	return ScoreWithWeights(d, hasPrecedentRef, DefaultWeights)
+}
+
+// ScoreWithWeights computes a completeness score (0.0-1.0) with each factor's
+// maximum contribution taken from w. Returns 0 if every weight is zero.
+func ScoreWithWeights(d model.TraceDecision, hasPrecedentRef bool, w Weights) float32 {
+	total := w.sum()
+	if total <= 0 {
+		return 0
+	}
+
 	var score float32
 
-	// Factor 1: Confidence is present and reasonable (0.15).
+	// Factor 1: Confidence is present and reasonable.
 	// Extreme values (exactly 0 or 1) are often defaults, so we reward mid-range.
-	// Strict inequality: exactly 0.05 and 0.95 fall to edge tier (0.10).
-	score += confidenceFactor(d.Confidence)
+	// Strict inequality: exactly 0.05 and 0.95 fall to the edge tier.
+	score += w.Confidence * confidenceFactor(d.Confidence)
 
-	// Factor 2: Reasoning is substantive (up to 0.30).
-	score += reasoningFactor(d.Reasoning)
+	// Factor 2: Reasoning is substantive.
+	score += w.Reasoning * reasoningFactor(d.Reasoning)
 
-	// Factor 3: Alternatives with substantive rejection reasons (up to 0.20).
-	score += alternativesFactor(d.Alternatives)
+	// Factor 3: Alternatives with substantive rejection reasons.
+	score += w.Alternatives * alternativesFactor(d.Alternatives)
 
-	// Factor 4: Evidence provided (up to 0.15).
-	score += evidenceFactor(d.Evidence)
+	// Factor 4: Evidence provided.
+	score += w.Evidence * evidenceFactor(d.Evidence)
 
-	// Factor 5: Outcome is substantive but concise (up to 0.10).
+	// Factor 5: Outcome is substantive but concise.
 	// Concise decision statements (20-300 chars) get full credit.
 	// Longer outcomes are penalized — they tend to be change logs, not decisions.
-	score += outcomeFactor(d.Outcome)
+	score += w.Outcome * outcomeFactor(d.Outcome)
 
-	// Factor 6: Precedent reference links this decision to a prior one (0.10).
+	// Factor 6: Precedent reference links this decision to a prior one.
 	if hasPrecedentRef {
-		score += 0.10
+		score += w.Precedent
 	}
 
-	return score
+	return min(score/total, 1)
 }
 
-// confidenceFactor returns the confidence contribution (0, 0.10, or 0.15).
+// confidenceFactor returns the fraction of the confidence weight earned
+// (0, 2/3, or 1 — i.e. 0, 0.10, or 0.15 at the default weight).
 func confidenceFactor(confidence float32) float32 {
 	if confidence > 0.05 && confidence < 0.95 {
-		return 0.15
+		return 1
 	}
 	if confidence > 0 && confidence < 1 {
-		return 0.10
+		return 2.0 / 3
 	}
 	return 0
 }
 
-// reasoningFactor returns the reasoning contribution (0, 0.12, 0.24, or 0.30).
-// Tiers are 40%, 80%, and 100% of the 0.30 weight.
+// reasoningFactor returns the fraction of the reasoning weight earned
+// (0, 0.4, 0.8, or 1 — i.e. 0, 0.12, 0.24, or 0.30 at the default weight).
 func reasoningFactor(reasoning *string) float32 {
 	if reasoning == nil {
 		return 0
@@ -271,41 +334,44 @@ func reasoningFactor(reasoning *string) float32 {
 	reasoningLen := len(strings.TrimSpace(*reasoning))
 	switch {
 	case reasoningLen > 100:
-		return 0.30
+		return 1
 	case reasoningLen > 50:
-		return 0.24
+		return 0.8
 	case reasoningLen > 20:
-		return 0.12
+		return 0.4
 	}
 	return 0
 }
 
-// alternativesFactor returns the alternatives contribution (0, 0.10, 0.15, or 0.20).
+// alternativesFactor returns the fraction of the alternatives weight earned
+// (0, 0.5, 0.75, or 1 — i.e. 0, 0.10, 0.15, or 0.20 at the default weight).
 func alternativesFactor(alts []model.TraceAlternative) float32 {
 	substantiveAlts := countSubstantiveRejections(alts)
 	switch {
 	case substantiveAlts >= 3:
-		return 0.20
+		return 1
 	case substantiveAlts >= 2:
-		return 0.15
+		return 0.75
 	case substantiveAlts >= 1:
-		return 0.10
+		return 0.5
 	}
 	return 0
 }
 
-// evidenceFactor returns the evidence contribution (0, 0.10, or 0.15).
+// evidenceFactor returns the fraction of the evidence weight earned
+// (0, 2/3, or 1 — i.e. 0, 0.10, or 0.15 at the default weight).
 func evidenceFactor(evidence []model.TraceEvidence) float32 {
 	if len(evidence) >= 2 {
-		return 0.15
+		return 1
 	}
 	if len(evidence) >= 1 {
-		return 0.10
+		return 2.0 / 3
 	}
 	return 0
 }
 
-// outcomeFactor returns the outcome contribution (0, 0.04, 0.07, or 0.10).
+// outcomeFactor returns the fraction of the outcome weight earned
+// (0, 0.4, 0.7, or 1 — i.e. 0, 0.04, 0.07, or 0.10 at the default weight).
 // Concise decision statements (20-300 chars) get full credit. Longer outcomes
 // are penalized because they tend to be commit-log rewrites rather than
 // decision statements.
@@ -315,11 +381,11 @@ func outcomeFactor(outcome string) float32 {
 	case n <= 20:
 		return 0
 	case n <= 300:
-		return 0.10
+		return 1
 	case n <= 500:
-		return 0.07
+		return 0.7
 	default:
-		return 0.04
+		return 0.4
 	}
 }
 
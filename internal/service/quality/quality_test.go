@@ -430,6 +430,89 @@ func TestScore_ThreeFactorsCombined(t *testing.T) {
 	assert.InDelta(t, float32(0.60), Score(d, false), 0.001)
 }
 
+func TestScore_MonotonicInRichness(t *testing.T) {
+	// Each step adds substance to the previous decision; the score must
+	// never drop and must rise when a new tier is reached.
+	steps := []model.TraceDecision{
+		{Outcome: "use redis"},
+		{Outcome: "use redis for the session cache layer"},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.99},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 30))},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 101))},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 101)),
+			Evidence:  []model.TraceEvidence{{SourceType: "document", Content: "a"}}},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 101)),
+			Evidence:  []model.TraceEvidence{{SourceType: "document", Content: "a"}, {SourceType: "document", Content: "b"}}},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 101)),
+			Evidence:  []model.TraceEvidence{{SourceType: "document", Content: "a"}, {SourceType: "document", Content: "b"}},
+			Alternatives: []model.TraceAlternative{
+				{Label: "memcached", RejectionReason: strPtr("no persistence across restarts")},
+			}},
+		{Outcome: "use redis for the session cache layer", Confidence: 0.70,
+			Reasoning: strPtr(repeat('r', 101)),
+			Evidence:  []model.TraceEvidence{{SourceType: "document", Content: "a"}, {SourceType: "document", Content: "b"}},
+			Alternatives: []model.TraceAlternative{
+				{Label: "memcached", RejectionReason: strPtr("no persistence across restarts")},
+				{Label: "postgres", RejectionReason: strPtr("too slow for per-request lookups")},
+				{Label: "in-process", RejectionReason: strPtr("sessions must survive deploys")},
+			}},
+	}
+
+	for _, w := range []Weights{DefaultWeights, {Confidence: 1, Reasoning: 1, Alternatives: 1, Evidence: 1, Outcome: 1, Precedent: 1}} {
+		prev := float32(-1)
+		for i, d := range steps {
+			got := ScoreWithWeights(d, false, w)
+			assert.Greater(t, got, prev, "step %d should score higher than step %d with weights %+v", i, i-1, w)
+			assert.GreaterOrEqual(t, got, float32(0))
+			assert.LessOrEqual(t, got, float32(1))
+			prev = got
+		}
+		assert.Greater(t, ScoreWithWeights(steps[len(steps)-1], true, w), prev, "precedent_ref should raise the score")
+	}
+}
+
+func TestScoreWithWeights_DefaultsMatchScore(t *testing.T) {
+	d := model.TraceDecision{
+		Outcome:    "chose latency over throughput for user-facing endpoint",
+		Confidence: 0.99,
+		Reasoning:  strPtr(repeat('r', 60)),
+		Evidence:   []model.TraceEvidence{{SourceType: "document", Content: "a"}},
+	}
+	assert.Equal(t, Score(d, true), ScoreWithWeights(d, true, DefaultWeights))
+}
+
+func TestScoreWithWeights_Normalized(t *testing.T) {
+	// Only reasoning counts: half-tier reasoning scores its tier fraction,
+	// regardless of the absolute weight.
+	d := model.TraceDecision{Reasoning: strPtr(repeat('r', 60)), Confidence: 0.5}
+	assert.InDelta(t, float32(0.8), ScoreWithWeights(d, false, Weights{Reasoning: 5}), 0.001)
+
+	// Doubling every weight leaves the score unchanged.
+	w := DefaultWeights
+	w2 := Weights{
+		Confidence: 2 * w.Confidence, Reasoning: 2 * w.Reasoning, Alternatives: 2 * w.Alternatives,
+		Evidence: 2 * w.Evidence, Outcome: 2 * w.Outcome, Precedent: 2 * w.Precedent,
+	}
+	assert.InDelta(t, ScoreWithWeights(d, false, w), ScoreWithWeights(d, false, w2), 0.001)
+
+	assert.Equal(t, float32(0), ScoreWithWeights(d, true, Weights{}), "all-zero weights score 0")
+}
+
+func TestBuildWeights(t *testing.T) {
+	assert.Equal(t, DefaultWeights, BuildWeights(nil))
+
+	w := BuildWeights(map[string]float64{"evidence": 0.4, "precedent": 0})
+	assert.InDelta(t, float32(0.4), w.Evidence, 0.0001)
+	assert.Zero(t, w.Precedent)
+	assert.Equal(t, DefaultWeights.Reasoning, w.Reasoning, "unnamed factors keep their default")
+}
+
 // ---------------------------------------------------------------------------
 // Uniform scoring: score is independent of decision type.
 // This is a critical invariant — changing the formula per type would break
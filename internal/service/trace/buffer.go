@@ -323,18 +323,35 @@ func (b *Buffer) FlushNow(ctx context.Context) error {
 	return b.flushUntilEmpty(ctx)
 }
 
+// Checkpoint is FlushNow with accounting: it blocks until the buffer is empty
+// or ctx expires and reports how many events and audit entries were written.
+// Unlike Drain, the flush loop keeps running and appends are accepted
+// throughout, so entries appended during the call may be included.
+func (b *Buffer) Checkpoint(ctx context.Context) (events, audits int, err error) {
+	return b.flushUntilEmptyCounted(ctx)
+}
+
 // flushUntilEmpty retries flushes until the buffer is empty or ctx expires.
 // This is used during shutdown so we don't exit after a single transient write failure.
 func (b *Buffer) flushUntilEmpty(ctx context.Context) error {
+	_, _, err := b.flushUntilEmptyCounted(ctx)
+	return err
+}
+
+// flushUntilEmptyCounted implements flushUntilEmpty, returning the number of
+// events and audit entries flushed across all batches.
+func (b *Buffer) flushUntilEmptyCounted(ctx context.Context) (events, audits int, _ error) {
 	const maxBackoff = 2 * time.Second
 	backoff := 50 * time.Millisecond
 
 	for {
-		flushed, err := b.flushOnce(ctx)
+		nEvents, nAudits, err := b.flushOnceCounted(ctx)
 		if err == nil {
-			if !flushed {
-				return nil // nothing left to flush
+			if nEvents == 0 && nAudits == 0 {
+				return events, audits, nil // nothing left to flush
 			}
+			events += nEvents
+			audits += nAudits
 			// Flushed at least one batch; continue immediately in case more are queued.
 			backoff = 50 * time.Millisecond
 			continue
@@ -342,7 +359,7 @@ func (b *Buffer) flushUntilEmpty(ctx context.Context) error {
 
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("trace: flush incomplete before deadline: %w", ctx.Err())
+			return events, audits, fmt.Errorf("trace: flush incomplete before deadline: %w", ctx.Err())
 		case <-time.After(backoff):
 		}
 		if backoff < maxBackoff {
@@ -361,6 +378,13 @@ func (b *Buffer) flushUntilEmpty(ctx context.Context) error {
 // flushed in a single transaction — guaranteeing that event appends never
 // persist without their audit trail.
 func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
+	events, audits, err := b.flushOnceCounted(ctx)
+	return events > 0 || audits > 0, err
+}
+
+// flushOnceCounted implements flushOnce, returning the number of events and
+// audit entries written by this batch (both zero when nothing was flushed).
+func (b *Buffer) flushOnceCounted(ctx context.Context) (int, int, error) {
 	// Serialize flushes so that concurrent callers (flushLoop ticker vs
 	// FlushNow from a request handler) cannot both snapshot the same prefix,
 	// flush it, and then double-trim — which would silently drop entries
@@ -371,7 +395,7 @@ func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
 	b.mu.Lock()
 	if len(b.events) == 0 && len(b.audits) == 0 {
 		b.mu.Unlock()
-		return false, nil
+		return 0, 0, nil
 	}
 
 	// Orphaned audit entries (no events): flush them in a single transaction
@@ -384,7 +408,7 @@ func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
 
 		if err := b.db.InsertMutationAuditBatch(ctx, orphanedAudits); err != nil {
 			b.logger.Error("trace: orphaned audit flush failed", "error", err)
-			return false, err
+			return 0, 0, err
 		}
 
 		b.mu.Lock()
@@ -396,7 +420,7 @@ func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
 		b.mu.Unlock()
 
 		b.logger.Info("trace: orphaned audits flushed", "audit_entries", len(orphanedAudits))
-		return true, nil
+		return 0, len(orphanedAudits), nil
 	}
 	// Keep events in memory until the flush succeeds.
 	// This avoids data loss on transient flush failures.
@@ -416,7 +440,7 @@ func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
 
 	if err != nil {
 		b.logger.Error("trace: flush failed", "error", err, "batch_size", len(batch))
-		return false, err
+		return 0, 0, err
 	}
 
 	// Advance WAL checkpoint BEFORE trimming the in-memory buffer. This
@@ -461,7 +485,7 @@ func (b *Buffer) flushOnce(ctx context.Context) (bool, error) {
 		"audit_entries", len(auditBatch),
 		"flush_duration_ms", duration.Milliseconds(),
 	)
-	return true, nil
+	return len(batch), len(auditBatch), nil
 }
 
 // Drain signals the background flush loop to stop, waits for it to complete
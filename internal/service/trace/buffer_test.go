@@ -930,6 +930,47 @@ func TestBuffer_FlushUntilEmptyWithRetry(t *testing.T) {
 	require.NoError(t, buf.Drain(drainCtx))
 }
 
+func TestBuffer_CheckpointReportsCounts(t *testing.T) {
+	run := createTestRun(t)
+
+	buf := NewBuffer(testDB, testLogger(), 1000, 10*time.Minute, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	buf.Start(ctx)
+
+	_, err := buf.Append(context.Background(), run.ID, run.AgentID, run.OrgID, makeEventInputs(4))
+	require.NoError(t, err)
+	buf.BufferAudit(storage.MutationAuditEntry{
+		RequestID:    "test-req-checkpoint",
+		OrgID:        run.OrgID,
+		ActorAgentID: run.AgentID,
+		ActorRole:    "agent",
+		HTTPMethod:   "POST",
+		Endpoint:     "/v1/runs/" + run.ID.String() + "/events",
+		Operation:    "append_events",
+		ResourceType: "agent_run",
+		ResourceID:   run.ID.String(),
+	})
+
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	events, audits, err := buf.Checkpoint(flushCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, events)
+	assert.Equal(t, 1, audits)
+	assert.Equal(t, 0, buf.Len())
+
+	// Nothing left: a second checkpoint is a no-op.
+	events, audits, err = buf.Checkpoint(flushCtx)
+	require.NoError(t, err)
+	assert.Zero(t, events)
+	assert.Zero(t, audits)
+
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	require.NoError(t, buf.Drain(drainCtx))
+}
+
 func TestBuffer_FlushLoopFallbackContext(t *testing.T) {
 	// Tests the fallback path in flushLoop where ctx is cancelled directly
 	// without going through Drain (no drain context sent via channel).
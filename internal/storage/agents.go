@@ -121,66 +121,169 @@ func (db *DB) CreateAgentAndKeyTx(
 	key model.APIKey,
 	agentAudit, keyAudit MutationAuditEntry,
 ) (model.Agent, model.APIKey, error) {
-	if agent.ID == uuid.Nil {
-		agent.ID = uuid.New()
+	created, err := db.CreateAgentsAndKeysTx(ctx, []NewAgentWithKey{{
+		Agent:      agent,
+		Key:        key,
+		AgentAudit: agentAudit,
+		KeyAudit:   keyAudit,
+	}})
+	if err != nil {
+		return model.Agent{}, model.APIKey{}, err
 	}
+	return created[0].Agent, created[0].Key, nil
+}
+
+// NewAgentWithKey is one agent plus its initial API key, together with the
+// audit entries to record for each, as consumed by CreateAgentsAndKeysTx.
+type NewAgentWithKey struct {
+	Agent      model.Agent
+	Key        model.APIKey
+	AgentAudit MutationAuditEntry
+	KeyAudit   MutationAuditEntry
+}
+
+// CreateAgentsAndKeysTx inserts every agent and its initial API key in a
+// single transaction: either all of them are created or none are. The
+// returned slice is in input order with IDs and timestamps populated.
+// A duplicate agent_id anywhere in the batch rolls back the whole batch and
+// surfaces as a unique-violation error.
+func (db *DB) CreateAgentsAndKeysTx(ctx context.Context, items []NewAgentWithKey) ([]NewAgentWithKey, error) {
 	now := time.Now().UTC()
-	if agent.CreatedAt.IsZero() {
-		agent.CreatedAt = now
+	out := make([]NewAgentWithKey, len(items))
+	for i, item := range items {
+		prepareNewAgentWithKey(&item, now)
+		out[i] = item
 	}
-	agent.UpdatedAt = now
-	if agent.Metadata == nil {
-		agent.Metadata = map[string]any{}
+
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for i := range out {
+			if err := insertAgentAndKeyTx(ctx, tx, &out[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if agent.Tags == nil {
-		agent.Tags = []string{}
+	return out, nil
+}
+
+// prepareNewAgentWithKey fills in IDs, timestamps and empty collections so
+// the inserted rows match what CreateAgent writes.
+func prepareNewAgentWithKey(item *NewAgentWithKey, now time.Time) {
+	if item.Agent.ID == uuid.Nil {
+		item.Agent.ID = uuid.New()
+	}
+	if item.Agent.CreatedAt.IsZero() {
+		item.Agent.CreatedAt = now
+	}
+	item.Agent.UpdatedAt = now
+	if item.Agent.Metadata == nil {
+		item.Agent.Metadata = map[string]any{}
+	}
+	if item.Agent.Tags == nil {
+		item.Agent.Tags = []string{}
 	}
 	// Credentials live in api_keys — never write the legacy column for new agents.
-	agent.APIKeyHash = nil
+	item.Agent.APIKeyHash = nil
 
-	if key.ID == uuid.Nil {
-		key.ID = uuid.New()
+	if item.Key.ID == uuid.Nil {
+		item.Key.ID = uuid.New()
 	}
-	if key.CreatedAt.IsZero() {
-		key.CreatedAt = now
+	if item.Key.CreatedAt.IsZero() {
+		item.Key.CreatedAt = now
 	}
+}
 
-	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
-		if _, err := tx.Exec(ctx,
-			`INSERT INTO agents (id, agent_id, org_id, name, role, api_key_hash, email, tags, metadata, created_at, updated_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
-			agent.ID, agent.AgentID, agent.OrgID, agent.Name, string(agent.Role),
-			agent.APIKeyHash, agent.Email, agent.Tags, agent.Metadata, agent.CreatedAt, agent.UpdatedAt,
-		); err != nil {
-			return fmt.Errorf("storage: create agent: %w", err)
-		}
+func insertAgentAndKeyTx(ctx context.Context, tx pgx.Tx, item *NewAgentWithKey) error {
+	agent, key := item.Agent, item.Key
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO agents (id, agent_id, org_id, name, role, api_key_hash, email, tags, metadata, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		agent.ID, agent.AgentID, agent.OrgID, agent.Name, string(agent.Role),
+		agent.APIKeyHash, agent.Email, agent.Tags, agent.Metadata, agent.CreatedAt, agent.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("storage: create agent: %w", err)
+	}
 
-		agentAudit.ResourceID = agent.AgentID
-		agentAudit.AfterData = agent
-		if err := InsertMutationAuditTx(ctx, tx, agentAudit); err != nil {
-			return fmt.Errorf("storage: audit in create agent+key tx: %w", err)
-		}
+	item.AgentAudit.ResourceID = agent.AgentID
+	item.AgentAudit.AfterData = agent
+	if err := InsertMutationAuditTx(ctx, tx, item.AgentAudit); err != nil {
+		return fmt.Errorf("storage: audit in create agent+key tx: %w", err)
+	}
 
-		if _, err := tx.Exec(ctx,
-			`INSERT INTO api_keys (id, prefix, key_hash, agent_id, org_id, label, created_by, created_at, expires_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
-			key.ID, key.Prefix, key.KeyHash, key.AgentID, key.OrgID,
-			key.Label, key.CreatedBy, key.CreatedAt, key.ExpiresAt,
-		); err != nil {
-			return fmt.Errorf("storage: create api key in agent+key tx: %w", err)
-		}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO api_keys (id, prefix, key_hash, agent_id, org_id, label, created_by, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		key.ID, key.Prefix, key.KeyHash, key.AgentID, key.OrgID,
+		key.Label, key.CreatedBy, key.CreatedAt, key.ExpiresAt,
+	); err != nil {
+		return fmt.Errorf("storage: create api key in agent+key tx: %w", err)
+	}
+
+	item.KeyAudit.ResourceID = key.ID.String()
+	item.KeyAudit.AfterData = key
+	if err := InsertMutationAuditTx(ctx, tx, item.KeyAudit); err != nil {
+		return fmt.Errorf("storage: audit api key in create agent+key tx: %w", err)
+	}
+	return nil
+}
+
+// ExistingAgentIDs returns the subset of agentIDs that already exist in the
+// org. Used to report per-item conflicts before attempting a batch insert.
+func (db *DB) ExistingAgentIDs(ctx context.Context, orgID uuid.UUID, agentIDs []string) ([]string, error) {
+	if len(agentIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT agent_id FROM agents WHERE org_id = $1 AND agent_id = ANY($2) ORDER BY agent_id`,
+		orgID, agentIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: existing agent ids: %w", err)
+	}
+	defer rows.Close()
 
-		keyAudit.ResourceID = key.ID.String()
-		keyAudit.AfterData = key
-		if err := InsertMutationAuditTx(ctx, tx, keyAudit); err != nil {
-			return fmt.Errorf("storage: audit api key in create agent+key tx: %w", err)
+	var existing []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan existing agent id: %w", err)
 		}
-		return nil
-	})
+		existing = append(existing, id)
+	}
+	return existing, rows.Err()
+}
+
+// SuggestAgentIDs returns up to limit agent_ids in the org that look like
+// misspellings of agentID, most similar first, by pg_trgm trigram
+// similarity. Returns nil when agentID itself exists, so callers can ask
+// unconditionally after an empty result.
+func (db *DB) SuggestAgentIDs(ctx context.Context, orgID uuid.UUID, agentID string, limit int) ([]string, error) {
+	rows, err := db.readPool().Query(ctx,
+		`SELECT agent_id FROM agents
+		 WHERE org_id = $1 AND deleted_at IS NULL
+		   AND NOT EXISTS (SELECT 1 FROM agents WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL)
+		   AND similarity(agent_id, $2) >= $3
+		 ORDER BY similarity(agent_id, $2) DESC, agent_id
+		 LIMIT $4`,
+		orgID, agentID, AgentSuggestionMinSimilarity, limit,
+	)
 	if err != nil {
-		return model.Agent{}, model.APIKey{}, err
+		return nil, fmt.Errorf("storage: suggest agent ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan suggested agent id: %w", err)
+		}
+		ids = append(ids, id)
 	}
-	return agent, key, nil
+	return ids, rows.Err()
 }
 
 // GetAgentsByAgentIDGlobal returns all agents with the given agent_id across all orgs.
@@ -189,7 +292,7 @@ func (db *DB) CreateAgentAndKeyTx(
 // preventing cross-tenant confusion when agent_ids collide across orgs.
 func (db *DB) GetAgentsByAgentIDGlobal(ctx context.Context, agentID string) ([]model.Agent, error) {
 	rows, err := db.pool.Query(ctx,
-		`SELECT `+agentCols+` FROM agents WHERE agent_id = $1 ORDER BY created_at ASC`, agentID,
+		`SELECT `+agentCols+` FROM agents WHERE agent_id = $1 AND deleted_at IS NULL ORDER BY created_at ASC`, agentID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("storage: get agents by agent_id: %w", err)
@@ -209,7 +312,7 @@ func (db *DB) GetAgentsByAgentIDGlobal(ctx context.Context, agentID string) ([]m
 // GetAgentByAgentID retrieves an agent by agent_id within an org.
 func (db *DB) GetAgentByAgentID(ctx context.Context, orgID uuid.UUID, agentID string) (model.Agent, error) {
 	row := db.pool.QueryRow(ctx,
-		`SELECT `+agentCols+` FROM agents WHERE org_id = $1 AND agent_id = $2`, orgID, agentID,
+		`SELECT `+agentCols+` FROM agents WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL`, orgID, agentID,
 	)
 	a, err := scanOneAgent(row)
 	if err != nil {
@@ -225,7 +328,7 @@ func (db *DB) GetAgentByAgentID(ctx context.Context, orgID uuid.UUID, agentID st
 // defense-in-depth tenant isolation.
 func (db *DB) GetAgentByID(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (model.Agent, error) {
 	row := db.pool.QueryRow(ctx,
-		`SELECT `+agentCols+` FROM agents WHERE id = $1 AND org_id = $2`, id, orgID,
+		`SELECT `+agentCols+` FROM agents WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID,
 	)
 	a, err := scanOneAgent(row)
 	if err != nil {
@@ -242,7 +345,7 @@ func (db *DB) GetAgentByID(ctx context.Context, id uuid.UUID, orgID uuid.UUID) (
 func (db *DB) ListAgents(ctx context.Context, orgID uuid.UUID, limit, offset int) ([]model.Agent, error) {
 	limit, offset = clampPagination(limit, offset, 200, 1000)
 	rows, err := db.pool.Query(ctx,
-		`SELECT `+agentCols+` FROM agents WHERE org_id = $1 ORDER BY created_at ASC LIMIT $2 OFFSET $3`,
+		`SELECT `+agentCols+` FROM agents WHERE org_id = $1 AND deleted_at IS NULL ORDER BY created_at ASC LIMIT $2 OFFSET $3`,
 		orgID, limit, offset,
 	)
 	if err != nil {
@@ -256,7 +359,7 @@ func (db *DB) ListAgents(ctx context.Context, orgID uuid.UUID, limit, offset int
 // CountAgents returns the number of registered agents in an org.
 func (db *DB) CountAgents(ctx context.Context, orgID uuid.UUID) (int, error) {
 	var count int
-	err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM agents WHERE org_id = $1`, orgID).Scan(&count)
+	err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM agents WHERE org_id = $1 AND deleted_at IS NULL`, orgID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("storage: count agents: %w", err)
 	}
@@ -266,7 +369,7 @@ func (db *DB) CountAgents(ctx context.Context, orgID uuid.UUID) (int, error) {
 // CountAgentsGlobal returns the total number of agents across all organizations.
 func (db *DB) CountAgentsGlobal(ctx context.Context) (int, error) {
 	var count int
-	err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM agents`).Scan(&count)
+	err := db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM agents WHERE deleted_at IS NULL`).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("storage: count agents global: %w", err)
 	}
@@ -278,7 +381,7 @@ func (db *DB) CountAgentsGlobal(ctx context.Context) (int, error) {
 // this efficient even for large agent populations.
 func (db *DB) ListAgentIDsBySharedTags(ctx context.Context, orgID uuid.UUID, tags []string) ([]string, error) {
 	rows, err := db.pool.Query(ctx,
-		`SELECT agent_id FROM agents WHERE org_id = $1 AND tags && $2`,
+		`SELECT agent_id FROM agents WHERE org_id = $1 AND tags && $2 AND deleted_at IS NULL`,
 		orgID, tags,
 	)
 	if err != nil {
@@ -297,6 +400,33 @@ func (db *DB) ListAgentIDsBySharedTags(ctx context.Context, orgID uuid.UUID, tag
 	return ids, rows.Err()
 }
 
+// ListAgentIDsByRoles returns agent_ids within the org whose role is one of
+// roles.
+func (db *DB) ListAgentIDsByRoles(ctx context.Context, orgID uuid.UUID, roles []model.AgentRole) ([]string, error) {
+	roleStrs := make([]string, len(roles))
+	for i, r := range roles {
+		roleStrs[i] = string(r)
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT agent_id FROM agents WHERE org_id = $1 AND role = ANY($2) AND deleted_at IS NULL`,
+		orgID, roleStrs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list agents by roles: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan agent id by role: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // UpdateAgent performs a partial update of an agent's name and/or metadata.
 // Only non-nil fields are applied (COALESCE pattern). Returns the updated agent.
 func (db *DB) UpdateAgent(ctx context.Context, orgID uuid.UUID, agentID string, name *string, metadata map[string]any) (model.Agent, error) {
@@ -359,7 +489,8 @@ func (db *DB) UpdateAgentWithAudit(ctx context.Context, orgID uuid.UUID, agentID
 	return a, nil
 }
 
-// AgentStats holds aggregate statistics for a single agent.
+// AgentStats holds aggregate statistics for a single agent. Unless noted, the
+// fields describe active decisions: current revisions (valid_to IS NULL).
 type AgentStats struct {
 	DecisionCount   int            `json:"decision_count"`
 	AvgConfidence   float64        `json:"avg_confidence"`
@@ -367,25 +498,50 @@ type AgentStats struct {
 	LastDecision    *time.Time     `json:"last_decision,omitempty"`
 	LowCompleteness int            `json:"low_completeness_count"` // completeness_score < 0.5
 	TypeBreakdown   map[string]int `json:"decision_types"`
+
+	TotalDecisions        int        `json:"total_decisions"`          // every revision, active or superseded
+	RevisedCount          int        `json:"revised_count"`            // revisions superseded by a later one
+	DistinctSessions      int        `json:"distinct_sessions"`        // across every revision
+	DistinctDecisionTypes int        `json:"distinct_decision_types"`  // keys of TypeBreakdown
+	OpenConflicts         int        `json:"open_conflicts"`           // open scored conflicts the agent is party to
+	LastActiveAt          *time.Time `json:"last_active_at,omitempty"` // latest of last_seen and the newest revision
+
+	// Quota is filled in by the stats handler from decision_usage and the
+	// org's decision_quota settings.
+	Quota *model.AgentQuotaStatus `json:"quota,omitempty"`
 }
 
 // GetAgentStats returns aggregate decision statistics for a specific agent.
+// Tombstoned decisions are excluded.
 func (db *DB) GetAgentStats(ctx context.Context, orgID uuid.UUID, agentID string) (AgentStats, error) {
 	var s AgentStats
-	err := db.pool.QueryRow(ctx, `
-		SELECT count(*), COALESCE(avg(confidence), 0),
-		       min(created_at), max(created_at),
-		       count(*) FILTER (WHERE completeness_score < 0.5)
+	err := db.readPool().QueryRow(ctx, `
+		SELECT count(*) FILTER (WHERE valid_to IS NULL),
+		       COALESCE(avg(confidence) FILTER (WHERE valid_to IS NULL), 0),
+		       min(created_at) FILTER (WHERE valid_to IS NULL),
+		       max(created_at) FILTER (WHERE valid_to IS NULL),
+		       count(*) FILTER (WHERE valid_to IS NULL AND completeness_score < 0.5),
+		       count(*),
+		       count(*) FILTER (WHERE valid_to IS NOT NULL),
+		       count(DISTINCT session_id),
+		       count(DISTINCT decision_type) FILTER (WHERE valid_to IS NULL),
+		       (SELECT count(*) FROM scored_conflicts sc
+		        WHERE sc.org_id = $1 AND sc.status = 'open'
+		          AND (sc.agent_a = $2 OR sc.agent_b = $2)),
+		       GREATEST(max(created_at),
+		                (SELECT last_seen FROM agents WHERE org_id = $1 AND agent_id = $2))
 		FROM decisions
-		WHERE org_id = $1 AND agent_id = $2 AND valid_to IS NULL`,
+		WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL`,
 		orgID, agentID,
-	).Scan(&s.DecisionCount, &s.AvgConfidence, &s.FirstDecision, &s.LastDecision, &s.LowCompleteness)
+	).Scan(&s.DecisionCount, &s.AvgConfidence, &s.FirstDecision, &s.LastDecision, &s.LowCompleteness,
+		&s.TotalDecisions, &s.RevisedCount, &s.DistinctSessions, &s.DistinctDecisionTypes,
+		&s.OpenConflicts, &s.LastActiveAt)
 	if err != nil {
 		return s, fmt.Errorf("storage: agent stats: %w", err)
 	}
 
 	// Decision type breakdown.
-	rows, err := db.pool.Query(ctx, `
+	rows, err := db.readPool().Query(ctx, `
 		SELECT decision_type, count(*)
 		FROM decisions
 		WHERE org_id = $1 AND agent_id = $2 AND valid_to IS NULL
@@ -418,7 +574,7 @@ type AgentListStat struct {
 
 // GetAgentListStats returns decision count and last decision time per agent in an org.
 func (db *DB) GetAgentListStats(ctx context.Context, orgID uuid.UUID) (map[string]AgentListStat, error) {
-	rows, err := db.pool.Query(ctx, `
+	rows, err := db.readPool().Query(ctx, `
 		SELECT agent_id, count(*), max(created_at)
 		FROM decisions
 		WHERE org_id = $1 AND valid_to IS NULL
@@ -517,3 +673,28 @@ func (db *DB) TouchLastSeen(ctx context.Context, orgID uuid.UUID, agentID string
 	}
 	return nil
 }
+
+// GetDecisionUsage returns decision write counts for the UTC day and month
+// containing now, for the given agent and for the whole org. Counts come from
+// the decision_usage rollup maintained by a trigger on decisions.
+func (db *DB) GetDecisionUsage(ctx context.Context, orgID uuid.UUID, agentID string, now time.Time) (model.DecisionUsage, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var u model.DecisionUsage
+	err := db.pool.QueryRow(ctx, `
+		SELECT COALESCE(sum(decision_count) FILTER (WHERE agent_id = $2 AND period = 'day'), 0),
+		       COALESCE(sum(decision_count) FILTER (WHERE agent_id = $2 AND period = 'month'), 0),
+		       COALESCE(sum(decision_count) FILTER (WHERE period = 'day'), 0),
+		       COALESCE(sum(decision_count) FILTER (WHERE period = 'month'), 0)
+		FROM decision_usage
+		WHERE org_id = $1
+		  AND ((period = 'day' AND period_start = $3) OR (period = 'month' AND period_start = $4))`,
+		orgID, agentID, day, month,
+	).Scan(&u.AgentDaily, &u.AgentMonthly, &u.OrgDaily, &u.OrgMonthly)
+	if err != nil {
+		return u, fmt.Errorf("storage: decision usage: %w", err)
+	}
+	return u, nil
+}
@@ -44,6 +44,15 @@ func (db *DB) CreateAlternativesBatch(ctx context.Context, alts []model.Alternat
 	return nil
 }
 
+// alternativesByDecisionsSQL loads alternatives for a set of decisions ($1)
+// scoped to an org ($2). Shared by GetAlternativesByDecisions and the
+// pipelined include path in QueryDecisions.
+const alternativesByDecisionsSQL = `SELECT a.id, a.decision_id, a.label, a.rejection_reason, a.metadata, a.created_at
+		 FROM alternatives a
+		 WHERE a.decision_id = ANY($1)
+		   AND a.decision_id IN (SELECT id FROM decisions WHERE org_id = $2)
+		 ORDER BY a.created_at`
+
 // GetAlternativesByDecisions retrieves all alternatives for a set of decision IDs in a single query.
 // Results are returned as a map from decision ID to its alternatives.
 // orgID provides defense-in-depth tenant isolation via a subquery against the decisions table,
@@ -53,18 +62,16 @@ func (db *DB) GetAlternativesByDecisions(ctx context.Context, decisionIDs []uuid
 		return nil, nil
 	}
 
-	rows, err := db.pool.Query(ctx,
-		`SELECT a.id, a.decision_id, a.label, a.rejection_reason, a.metadata, a.created_at
-		 FROM alternatives a
-		 WHERE a.decision_id = ANY($1)
-		   AND a.decision_id IN (SELECT id FROM decisions WHERE org_id = $2)
-		 ORDER BY a.created_at`, decisionIDs, orgID,
-	)
+	rows, err := db.pool.Query(ctx, alternativesByDecisionsSQL, decisionIDs, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("storage: get alternatives batch: %w", err)
 	}
 	defer rows.Close()
+	return scanAlternativesByDecision(rows)
+}
 
+// scanAlternativesByDecision groups alternativesByDecisionsSQL rows by decision ID.
+func scanAlternativesByDecision(rows pgx.Rows) (map[uuid.UUID][]model.Alternative, error) {
 	result := make(map[uuid.UUID][]model.Alternative)
 	for rows.Next() {
 		var a model.Alternative
@@ -276,6 +276,82 @@ func (db *DB) RotateAPIKeyWithAudit(ctx context.Context, orgID uuid.UUID, oldKey
 	return newKey, nil
 }
 
+// RotateAgentAPIKeyWithAudit replaces every credential an agent can mint
+// tokens with: it revokes the agent's active managed keys, clears any legacy
+// agents.api_key_hash, and inserts newKey, all in one transaction. The agent
+// row itself is untouched, so its identity and decision history persist.
+// Returns the new key and the IDs of the keys it revoked, or ErrNotFound if
+// the agent does not exist in orgID or has been tombstoned.
+func (db *DB) RotateAgentAPIKeyWithAudit(ctx context.Context, orgID uuid.UUID, agentID string, newKey model.APIKey, audit MutationAuditEntry) (model.APIKey, []uuid.UUID, error) {
+	if newKey.ID == uuid.Nil {
+		newKey.ID = uuid.New()
+	}
+	if newKey.CreatedAt.IsZero() {
+		newKey.CreatedAt = time.Now().UTC()
+	}
+
+	var revoked []uuid.UUID
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Lock the agent row so concurrent rotations serialize.
+		var agentUUID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`SELECT id FROM agents WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL FOR UPDATE`,
+			orgID, agentID,
+		).Scan(&agentUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("storage: agent %s: %w", agentID, ErrNotFound)
+			}
+			return fmt.Errorf("storage: lock agent for key rotation: %w", err)
+		}
+
+		rows, err := tx.Query(ctx,
+			`UPDATE api_keys SET revoked_at = now()
+			 WHERE org_id = $1 AND agent_id = $2 AND revoked_at IS NULL
+			 RETURNING id`,
+			orgID, agentID,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: revoke agent keys during rotation: %w", err)
+		}
+		revoked, err = pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return fmt.Errorf("storage: revoke agent keys during rotation: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx,
+			`UPDATE agents SET api_key_hash = NULL, updated_at = now() WHERE id = $1`,
+			agentUUID,
+		); err != nil {
+			return fmt.Errorf("storage: clear legacy agent key during rotation: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO api_keys (id, prefix, key_hash, agent_id, org_id, label, created_by, created_at, expires_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			newKey.ID, newKey.Prefix, newKey.KeyHash, newKey.AgentID, newKey.OrgID,
+			newKey.Label, newKey.CreatedBy, newKey.CreatedAt, newKey.ExpiresAt,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: create new key during rotation: %w", err)
+		}
+
+		audit.ResourceID = agentID
+		audit.AfterData = map[string]any{
+			"new_key_id":      newKey.ID,
+			"revoked_key_ids": revoked,
+		}
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit in rotate agent key tx: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.APIKey{}, nil, err
+	}
+	return newKey, revoked, nil
+}
+
 // TouchAPIKeyLastUsed updates the last_used_at timestamp for an API key.
 // Called from the auth middleware on successful authentication via a managed key.
 // Uses a fire-and-forget pattern — callers should not block on the result.
@@ -290,6 +366,32 @@ func (db *DB) TouchAPIKeyLastUsed(ctx context.Context, orgID, keyID uuid.UUID) e
 	return nil
 }
 
+// UpdateAPIKeyHash replaces a managed key's hash after the key was re-hashed
+// with current parameters. The update only applies while the stored hash is
+// still oldHash, so a concurrent rotation or upgrade is never overwritten.
+func (db *DB) UpdateAPIKeyHash(ctx context.Context, orgID, keyID uuid.UUID, oldHash, newHash string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE api_keys SET key_hash = $1 WHERE id = $2 AND org_id = $3 AND key_hash = $4`,
+		newHash, keyID, orgID, oldHash,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: update api key hash: %w", err)
+	}
+	return nil
+}
+
+// UpdateAgentAPIKeyHash is UpdateAPIKeyHash for a legacy agents.api_key_hash.
+func (db *DB) UpdateAgentAPIKeyHash(ctx context.Context, orgID, id uuid.UUID, oldHash, newHash string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE agents SET api_key_hash = $1 WHERE id = $2 AND org_id = $3 AND api_key_hash = $4`,
+		newHash, id, orgID, oldHash,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: update agent api key hash: %w", err)
+	}
+	return nil
+}
+
 // MigrateAgentKeysToAPIKeys copies api_key_hash from agents to api_keys for
 // agents that still have a legacy key. Idempotent: skips agents that already
 // have at least one entry in api_keys. NULLs out agents.api_key_hash after copy.
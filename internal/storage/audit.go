@@ -6,9 +6,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ashita-ai/akashi/internal/model"
 )
 
 // Design note: mutation_audit_log and deletion_audit_log intentionally lack a
@@ -101,3 +106,170 @@ func (db *DB) InsertMutationAuditBatch(ctx context.Context, entries []MutationAu
 		return nil
 	})
 }
+
+// AccessAuditEntry is a normalized access event written to access_audit_log.
+// Decision reads carry ResourceIDs, which may be truncated by the caller;
+// ResultCount is always the full count. Per-request entries (ResourceType
+// model.AccessResourceRequest) carry Status and LatencyMs instead.
+type AccessAuditEntry struct {
+	OccurredAt   time.Time // Zero = now.
+	RequestID    string
+	OrgID        uuid.UUID
+	ActorAgentID string
+	ActorRole    string
+	HTTPMethod   string
+	Endpoint     string
+	ResourceType string
+	ResourceIDs  []uuid.UUID
+	ResultCount  int
+	Status       *int
+	LatencyMs    *float64
+	Metadata     map[string]any
+}
+
+// InsertAccessAudit appends an access event to access_audit_log.
+func (db *DB) InsertAccessAudit(ctx context.Context, e AccessAuditEntry) error {
+	return db.InsertAccessAuditBatch(ctx, []AccessAuditEntry{e})
+}
+
+// InsertAccessAuditBatch appends access events to access_audit_log using COPY.
+func (db *DB) InsertAccessAuditBatch(ctx context.Context, entries []AccessAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	columns := []string{
+		"occurred_at", "request_id", "org_id", "actor_agent_id", "actor_role", "http_method",
+		"endpoint", "resource_type", "resource_ids", "result_count", "status", "latency_ms", "metadata",
+	}
+	now := time.Now().UTC()
+	rows := make([][]any, len(entries))
+	for i, e := range entries {
+		if e.OccurredAt.IsZero() {
+			e.OccurredAt = now
+		}
+		if e.ResourceIDs == nil {
+			e.ResourceIDs = []uuid.UUID{}
+		}
+		if e.Metadata == nil {
+			e.Metadata = map[string]any{}
+		}
+		rows[i] = []any{
+			e.OccurredAt, e.RequestID, e.OrgID, e.ActorAgentID, e.ActorRole, e.HTTPMethod,
+			e.Endpoint, e.ResourceType, e.ResourceIDs, e.ResultCount, e.Status, e.LatencyMs, e.Metadata,
+		}
+	}
+
+	if _, err := db.pool.CopyFrom(ctx, pgx.Identifier{"access_audit_log"}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("storage: copy access audit: %w", err)
+	}
+	return nil
+}
+
+// AuditFilters holds optional filters for ListAuditEntries.
+type AuditFilters struct {
+	Type         string // model.AuditTypeMutation (default) or model.AuditTypeAccess
+	ActorAgentID *string
+	ResourceID   *string // exact resource_id for mutations; membership in resource_ids for access
+	ResourceType *string
+	From         *time.Time
+	To           *time.Time
+}
+
+// ListAuditEntries returns audit entries for an org, newest first, from the
+// mutation log or the access log depending on filters.Type. The second return
+// value is the total number of matching entries.
+func (db *DB) ListAuditEntries(ctx context.Context, orgID uuid.UUID, filters AuditFilters, limit, offset int) ([]model.AuditEntry, int, error) {
+	access := filters.Type == model.AuditTypeAccess
+
+	conditions := []string{"org_id = $1"}
+	args := []any{orgID}
+	argIdx := 2
+
+	if filters.ActorAgentID != nil {
+		conditions = append(conditions, fmt.Sprintf("actor_agent_id = $%d", argIdx))
+		args = append(args, *filters.ActorAgentID)
+		argIdx++
+	}
+	if filters.ResourceID != nil {
+		if access {
+			id, err := uuid.Parse(*filters.ResourceID)
+			if err != nil {
+				// Access entries only record UUIDs; a non-UUID can never match.
+				return []model.AuditEntry{}, 0, nil
+			}
+			conditions = append(conditions, fmt.Sprintf("resource_ids @> ARRAY[$%d::uuid]", argIdx))
+			args = append(args, id)
+		} else {
+			conditions = append(conditions, fmt.Sprintf("resource_id = $%d", argIdx))
+			args = append(args, *filters.ResourceID)
+		}
+		argIdx++
+	}
+	if filters.ResourceType != nil {
+		conditions = append(conditions, fmt.Sprintf("resource_type = $%d", argIdx))
+		args = append(args, *filters.ResourceType)
+		argIdx++
+	}
+	if filters.From != nil {
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", argIdx))
+		args = append(args, *filters.From)
+		argIdx++
+	}
+	if filters.To != nil {
+		conditions = append(conditions, fmt.Sprintf("occurred_at <= $%d", argIdx))
+		args = append(args, *filters.To)
+		argIdx++
+	}
+
+	var cols, table string
+	if access {
+		cols = "id, occurred_at, request_id, actor_agent_id, actor_role, http_method, endpoint, resource_type, resource_ids, result_count, status, latency_ms, metadata"
+		table = "access_audit_log"
+	} else {
+		cols = "id, occurred_at, request_id, actor_agent_id, actor_role, http_method, endpoint, resource_type, operation, resource_id, metadata"
+		table = "mutation_audit_log"
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %s, COUNT(*) OVER() FROM %s WHERE %s ORDER BY occurred_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		cols, table, strings.Join(conditions, " AND "), argIdx, argIdx+1,
+	)
+	args = append(args, limit, offset)
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	var total int
+	for rows.Next() {
+		var (
+			e        model.AuditEntry
+			metaJSON []byte
+		)
+		dest := []any{&e.ID, &e.OccurredAt, &e.RequestID, &e.ActorAgentID, &e.ActorRole, &e.HTTPMethod, &e.Endpoint, &e.ResourceType}
+		if access {
+			var count int
+			dest = append(dest, &e.ResourceIDs, &count, &e.Status, &e.LatencyMs)
+			e.ResultCount = &count
+			e.Type = model.AuditTypeAccess
+		} else {
+			dest = append(dest, &e.Operation, &e.ResourceID)
+			e.Type = model.AuditTypeMutation
+		}
+		dest = append(dest, &metaJSON, &total)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, fmt.Errorf("storage: scan audit entry: %w", err)
+		}
+		if len(metaJSON) > 0 {
+			if err := json.Unmarshal(metaJSON, &e.Metadata); err != nil {
+				return nil, 0, fmt.Errorf("storage: unmarshal audit metadata: %w", err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
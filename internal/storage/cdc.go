@@ -0,0 +1,137 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// cdcSequencerLockKey serializes LSN assignment for decision_cdc.
+const cdcSequencerLockKey int64 = 9021002
+
+// DecisionChangePage is a batch of change log entries after a cursor.
+type DecisionChangePage struct {
+	Changes []model.DecisionChange
+	// CompactedLSN is the highest LSN removed by compaction. A cursor below
+	// it may have missed entries.
+	CompactedLSN int64
+}
+
+// SequenceDecisionChanges assigns LSNs to committed change log entries that
+// do not have one yet, in insertion order. Only one sequencer runs at a
+// time; if another holds the lock this returns 0 immediately, since anything
+// it numbers will sort after every LSN already visible.
+func (db *DB) SequenceDecisionChanges(ctx context.Context) (int64, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("storage: begin cdc sequence tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock($1)`, cdcSequencerLockKey).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("storage: acquire cdc sequencer lock: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	tag, err := tx.Exec(ctx,
+		`WITH pending AS (
+		     SELECT id, nextval('decision_cdc_lsn_seq') AS lsn
+		     FROM (SELECT id FROM decision_cdc WHERE lsn IS NULL ORDER BY id) p
+		 )
+		 UPDATE decision_cdc c SET lsn = pending.lsn
+		 FROM pending WHERE c.id = pending.id`)
+	if err != nil {
+		return 0, fmt.Errorf("storage: sequence decision changes: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("storage: commit cdc sequence tx: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListDecisionChanges sequences pending entries and returns up to limit
+// entries for the org with LSN greater than afterLSN, in LSN order.
+func (db *DB) ListDecisionChanges(ctx context.Context, orgID uuid.UUID, afterLSN int64, limit int) (DecisionChangePage, error) {
+	if _, err := db.SequenceDecisionChanges(ctx); err != nil {
+		return DecisionChangePage{}, err
+	}
+
+	var page DecisionChangePage
+	if err := db.pool.QueryRow(ctx,
+		`SELECT compacted_lsn FROM decision_cdc_state`,
+	).Scan(&page.CompactedLSN); err != nil {
+		return DecisionChangePage{}, fmt.Errorf("storage: get cdc compaction state: %w", err)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`SELECT lsn, org_id, decision_id, operation, before, after, recorded_at
+		 FROM decision_cdc
+		 WHERE org_id = $1 AND lsn > $2
+		 ORDER BY lsn ASC
+		 LIMIT $3`,
+		orgID, afterLSN, limit,
+	)
+	if err != nil {
+		return DecisionChangePage{}, fmt.Errorf("storage: list decision changes: %w", err)
+	}
+	defer rows.Close()
+
+	page.Changes = []model.DecisionChange{}
+	for rows.Next() {
+		var c model.DecisionChange
+		if err := rows.Scan(&c.LSN, &c.OrgID, &c.DecisionID, &c.Operation, &c.Before, &c.After, &c.RecordedAt); err != nil {
+			return DecisionChangePage{}, fmt.Errorf("storage: scan decision change: %w", err)
+		}
+		page.Changes = append(page.Changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return DecisionChangePage{}, fmt.Errorf("storage: list decision changes: %w", err)
+	}
+	return page, nil
+}
+
+// CompactDecisionChanges deletes sequenced change log entries up to the
+// newest one recorded before cutoff, and advances the compaction watermark.
+// Returns the number of entries deleted.
+func (db *DB) CompactDecisionChanges(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("storage: begin cdc compaction tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var through *int64
+	if err := tx.QueryRow(ctx,
+		`SELECT max(lsn) FROM decision_cdc WHERE lsn IS NOT NULL AND recorded_at < $1`,
+		cutoff,
+	).Scan(&through); err != nil {
+		return 0, fmt.Errorf("storage: find cdc compaction point: %w", err)
+	}
+	if through == nil {
+		return 0, nil
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM decision_cdc WHERE lsn <= $1`, *through)
+	if err != nil {
+		return 0, fmt.Errorf("storage: compact decision changes: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE decision_cdc_state SET compacted_lsn = GREATEST(compacted_lsn, $1)`,
+		*through,
+	); err != nil {
+		return 0, fmt.Errorf("storage: update cdc compaction state: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("storage: commit cdc compaction tx: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -173,3 +173,68 @@ func (db *DB) HasClaimsForDecision(ctx context.Context, decisionID, orgID uuid.U
 	}
 	return exists, nil
 }
+
+// ClaimOverlapQuery bounds claim-overlap candidate retrieval for one decision.
+type ClaimOverlapQuery struct {
+	DecisionType string
+	Namespace    string
+	// Projects scopes candidates the same way as Qdrant candidate search: nil
+	// matches only decisions without a project.
+	Projects []string
+	// Categories lists the claim categories that participate. Uncategorized
+	// claims always participate.
+	Categories []string
+	// Limit is the number of nearest claims retrieved per source claim.
+	Limit int
+	// MinSimilarity is the cosine similarity a neighbor claim must reach.
+	MinSimilarity float64
+}
+
+// FindClaimOverlapDecisionIDs returns the active decisions, other than
+// decisionID, whose claims are among the q.Limit nearest neighbors of any of
+// decisionID's claims with cosine similarity at least q.MinSimilarity. Only
+// decisions of the same org, decision type, namespace, and project scope are
+// considered. Each source claim is one index probe, so the cost is bounded by
+// (claims per decision) x q.Limit regardless of corpus size.
+func (db *DB) FindClaimOverlapDecisionIDs(ctx context.Context, decisionID, orgID uuid.UUID, q ClaimOverlapQuery) ([]uuid.UUID, error) {
+	if q.Limit <= 0 {
+		return nil, nil
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT DISTINCT n.decision_id
+		 FROM decision_claims src
+		 CROSS JOIN LATERAL (
+		     SELECT c.decision_id, c.embedding <=> src.embedding AS distance
+		     FROM decision_claims c
+		     JOIN decisions d ON d.id = c.decision_id AND d.org_id = c.org_id
+		     WHERE c.org_id = $2
+		       AND c.decision_id <> $1
+		       AND c.embedding IS NOT NULL
+		       AND (c.category IS NULL OR c.category = ANY($5))
+		       AND d.valid_to IS NULL
+		       AND d.decision_type = $3
+		       AND d.namespace = $4
+		       AND (($6::text[] IS NULL AND d.project IS NULL) OR d.project = ANY($6))
+		     ORDER BY c.embedding <=> src.embedding
+		     LIMIT $7
+		 ) n
+		 WHERE src.decision_id = $1 AND src.org_id = $2
+		   AND src.embedding IS NOT NULL
+		   AND (src.category IS NULL OR src.category = ANY($5))
+		   AND n.distance <= $8`,
+		decisionID, orgID, q.DecisionType, q.Namespace, q.Categories, q.Projects, q.Limit, 1-q.MinSimilarity)
+	if err != nil {
+		return nil, fmt.Errorf("storage: find claim overlap decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan claim overlap decision: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
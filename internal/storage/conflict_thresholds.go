@@ -0,0 +1,94 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// UpsertConflictThreshold creates or replaces the significance threshold
+// override for ct.DecisionType and inserts an audit entry atomically.
+func (db *DB) UpsertConflictThreshold(ctx context.Context, ct model.ConflictThreshold, audit MutationAuditEntry) (model.ConflictThreshold, error) {
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO conflict_thresholds (org_id, decision_type, significance_threshold, updated_by)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (org_id, decision_type) DO UPDATE
+			 SET significance_threshold = EXCLUDED.significance_threshold,
+			     updated_by = EXCLUDED.updated_by,
+			     updated_at = now()
+			 RETURNING updated_at`,
+			ct.OrgID, ct.DecisionType, ct.SignificanceThreshold, ct.UpdatedBy,
+		).Scan(&ct.UpdatedAt); err != nil {
+			return fmt.Errorf("storage: upsert conflict threshold: %w", err)
+		}
+
+		audit.ResourceID = ct.DecisionType
+		audit.AfterData = ct
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit in upsert conflict threshold tx: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.ConflictThreshold{}, err
+	}
+	return ct, nil
+}
+
+// DeleteConflictThreshold removes the override for decisionType, reverting it
+// to the global threshold, and inserts an audit entry atomically. Returns
+// ErrNotFound when no override exists.
+func (db *DB) DeleteConflictThreshold(ctx context.Context, orgID uuid.UUID, decisionType string, audit MutationAuditEntry) error {
+	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`DELETE FROM conflict_thresholds WHERE org_id = $1 AND decision_type = $2`,
+			orgID, decisionType,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: delete conflict threshold: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("storage: conflict threshold %q: %w", decisionType, ErrNotFound)
+		}
+
+		audit.ResourceID = decisionType
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit in delete conflict threshold tx: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListConflictThresholds returns every threshold override in an org, ordered
+// by decision type.
+func (db *DB) ListConflictThresholds(ctx context.Context, orgID uuid.UUID) ([]model.ConflictThreshold, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT org_id, decision_type, significance_threshold, updated_by, updated_at
+		 FROM conflict_thresholds WHERE org_id = $1
+		 ORDER BY decision_type`, orgID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list conflict thresholds: %w", err)
+	}
+	defer rows.Close()
+
+	thresholds := make([]model.ConflictThreshold, 0)
+	for rows.Next() {
+		var ct model.ConflictThreshold
+		if err := rows.Scan(&ct.OrgID, &ct.DecisionType, &ct.SignificanceThreshold, &ct.UpdatedBy, &ct.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("storage: scan conflict threshold: %w", err)
+		}
+		thresholds = append(thresholds, ct)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: iterate conflict thresholds: %w", err)
+	}
+	return thresholds, nil
+}
@@ -74,6 +74,11 @@ func conflictWhere(filters ConflictFilters, argOffset int) (string, []any) {
 		args = append(args, *filters.Category)
 		argOffset++
 	}
+	if filters.ReasonCode != nil {
+		clause += fmt.Sprintf(" AND sc.reason_code = $%d", argOffset)
+		args = append(args, *filters.ReasonCode)
+		argOffset++
+	}
 	if filters.DecisionID != nil {
 		clause += fmt.Sprintf(" AND (sc.decision_a_id = $%d OR sc.decision_b_id = $%d)", argOffset, argOffset)
 		args = append(args, *filters.DecisionID)
@@ -89,6 +94,16 @@ func conflictWhere(filters ConflictFilters, argOffset int) (string, []any) {
 		// Only one value is appended — do not append a second.
 		clause += fmt.Sprintf(" AND (sc.project_a = $%d OR sc.project_b = $%d)", argOffset, argOffset)
 		args = append(args, *filters.Project)
+		argOffset++
+	}
+	if filters.DetectedFrom != nil {
+		clause += fmt.Sprintf(" AND sc.detected_at >= $%d", argOffset)
+		args = append(args, *filters.DetectedFrom)
+		argOffset++
+	}
+	if filters.DetectedTo != nil {
+		clause += fmt.Sprintf(" AND sc.detected_at < $%d", argOffset)
+		args = append(args, *filters.DetectedTo)
 		argOffset++ //nolint:ineffassign // keep argOffset consistent so future additions don't miscount
 	}
 	return clause, args
@@ -190,6 +205,86 @@ func (db *DB) ListConflicts(ctx context.Context, orgID uuid.UUID, filters Confli
 	return scanConflictRows(rows)
 }
 
+// ConflictCursor holds the keyset position for newest-first conflict listing:
+// the (detected_at, id) of the last row on the previous page.
+type ConflictCursor struct {
+	DetectedAt time.Time
+	ID         uuid.UUID
+}
+
+// ListConflictsCursor returns a page of conflicts using keyset pagination on
+// (detected_at, id), newest first, matching ListConflicts' order. Pass a nil
+// cursor for the first page. The returned cursor positions the next page and
+// is nil on the last one. Unlike offset paging, rows detected while a client
+// pages through sort ahead of the cursor, so pages never skip or repeat a row.
+func (db *DB) ListConflictsCursor(ctx context.Context, orgID uuid.UUID, filters ConflictFilters, cursor *ConflictCursor, limit int) ([]model.DecisionConflict, *ConflictCursor, error) {
+	limit, _ = clampPagination(limit, 0, 50, 1000)
+
+	query := conflictSelectBase + ` WHERE sc.org_id = $1`
+	args := []any{orgID}
+
+	suffix, extra := conflictWhere(filters, 2)
+	query += suffix
+	args = append(args, extra...)
+
+	if cursor != nil {
+		idx := len(args) + 1
+		query += fmt.Sprintf(" AND (sc.detected_at, sc.id) < ($%d, $%d)", idx, idx+1)
+		args = append(args, cursor.DetectedAt, cursor.ID)
+	}
+	// Fetch one extra row to learn whether another page exists.
+	query += fmt.Sprintf(" ORDER BY sc.detected_at DESC, sc.id DESC LIMIT %d", limit+1)
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: list conflicts cursor: %w", err)
+	}
+	defer rows.Close()
+
+	conflicts, err := scanConflictRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(conflicts) <= limit {
+		return conflicts, nil, nil
+	}
+	conflicts = conflicts[:limit]
+	last := conflicts[limit-1]
+	return conflicts, &ConflictCursor{DetectedAt: last.DetectedAt, ID: last.ID}, nil
+}
+
+// ConflictExportCursor holds the keyset position for conflict export pagination.
+type ConflictExportCursor struct {
+	DetectedAt time.Time
+	ID         uuid.UUID
+}
+
+// ExportConflictsCursor returns a page of conflicts using keyset pagination
+// on (detected_at, id), oldest first. Pass a nil cursor for the first page.
+func (db *DB) ExportConflictsCursor(ctx context.Context, orgID uuid.UUID, filters ConflictFilters, cursor *ConflictExportCursor, limit int) ([]model.DecisionConflict, error) {
+	query := conflictSelectBase + ` WHERE sc.org_id = $1`
+	args := []any{orgID}
+
+	suffix, extra := conflictWhere(filters, 2)
+	query += suffix
+	args = append(args, extra...)
+
+	if cursor != nil {
+		idx := len(args) + 1
+		query += fmt.Sprintf(" AND (sc.detected_at, sc.id) > ($%d, $%d)", idx, idx+1)
+		args = append(args, cursor.DetectedAt, cursor.ID)
+	}
+	query += fmt.Sprintf(" ORDER BY sc.detected_at ASC, sc.id ASC LIMIT %d", limit)
+
+	rows, err := db.readPool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: export conflicts cursor: %w", err)
+	}
+	defer rows.Close()
+
+	return scanConflictRows(rows)
+}
+
 // conflictSelectBase is the common SELECT+JOIN clause for all conflict queries.
 const conflictSelectBase = `SELECT sc.id, sc.conflict_kind, sc.decision_a_id, sc.decision_b_id, sc.org_id,
 		 sc.agent_a, sc.agent_b,
@@ -203,6 +298,8 @@ const conflictSelectBase = `SELECT sc.id, sc.conflict_kind, sc.decision_a_id, sc
 		 sc.claim_text_a, sc.claim_text_b,
 		 sc.reopens_resolution_id,
 		 sc.project_a, sc.project_b,
+		 sc.reason_code, sc.minority_ids,
+		 sc.suggestion_explanation, sc.suggested_resolution, sc.suggestion_model, sc.suggested_at,
 		 da.run_id, db.run_id, da.confidence, db.confidence, da.reasoning, db.reasoning, da.valid_from, db.valid_from
 		 FROM scored_conflicts sc
 		 LEFT JOIN decisions da ON da.id = sc.decision_a_id
@@ -216,6 +313,8 @@ func scanConflictRows(rows pgx.Rows) ([]model.DecisionConflict, error) {
 		var confA, confB float32
 		var reasonA, reasonB *string
 		var validA, validB time.Time
+		var sugExpl, sugRes, sugModel *string
+		var sugAt *time.Time
 		if err := rows.Scan(
 			&c.ID, &c.ConflictKind, &c.DecisionAID, &c.DecisionBID, &c.OrgID, &c.AgentA, &c.AgentB,
 			&c.DecisionTypeA, &c.DecisionTypeB, &c.OutcomeA, &c.OutcomeB,
@@ -228,6 +327,8 @@ func scanConflictRows(rows pgx.Rows) ([]model.DecisionConflict, error) {
 			&c.ClaimTextA, &c.ClaimTextB,
 			&c.ReopensResolutionID,
 			&c.ProjectA, &c.ProjectB,
+			&c.ReasonCode, &c.MinorityIDs,
+			&sugExpl, &sugRes, &sugModel, &sugAt,
 			&runA, &runB, &confA, &confB, &reasonA, &reasonB, &validA, &validB,
 		); err != nil {
 			return nil, fmt.Errorf("storage: scan conflict: %w", err)
@@ -237,6 +338,16 @@ func scanConflictRows(rows pgx.Rows) ([]model.DecisionConflict, error) {
 		c.ReasoningA, c.ReasoningB = reasonA, reasonB
 		c.DecidedAtA, c.DecidedAtB = validA, validB
 		c.DecisionType = c.DecisionTypeA
+		if sugAt != nil && sugExpl != nil && sugRes != nil {
+			c.Suggestion = &model.ConflictSuggestion{
+				Explanation: *sugExpl,
+				Resolution:  *sugRes,
+				GeneratedAt: *sugAt,
+			}
+			if sugModel != nil {
+				c.Suggestion.Model = *sugModel
+			}
+		}
 		conflicts = append(conflicts, c)
 	}
 	return conflicts, rows.Err()
@@ -334,6 +445,40 @@ func (db *DB) NewConflictsSinceByOrg(ctx context.Context, orgID uuid.UUID, since
 	return scanConflictRows(rows)
 }
 
+// ConflictsPendingSuggestion returns up to limit open conflicts, newest first,
+// that the suggestion worker has not processed yet. Runs across all orgs.
+func (db *DB) ConflictsPendingSuggestion(ctx context.Context, limit int) ([]model.DecisionConflict, error) {
+	rows, err := db.pool.Query(ctx,
+		conflictSelectBase+` WHERE sc.status = 'open' AND sc.suggested_at IS NULL
+		 ORDER BY sc.detected_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list conflicts pending suggestion: %w", err)
+	}
+	defer rows.Close()
+
+	return scanConflictRows(rows)
+}
+
+// SetConflictSuggestion records the suggestion for a conflict. A nil
+// suggestion marks the conflict as processed without one, so the worker does
+// not retry a pair the model cannot answer. Conflicts that already have a
+// suggestion are left unchanged.
+func (db *DB) SetConflictSuggestion(ctx context.Context, orgID, id uuid.UUID, s *model.ConflictSuggestion) error {
+	var expl, res, mdl *string
+	if s != nil {
+		expl, res, mdl = &s.Explanation, &s.Resolution, &s.Model
+	}
+	_, err := db.pool.Exec(ctx,
+		`UPDATE scored_conflicts
+		 SET suggestion_explanation = $3, suggested_resolution = $4, suggestion_model = $5, suggested_at = now()
+		 WHERE id = $1 AND org_id = $2 AND suggested_at IS NULL`,
+		id, orgID, expl, res, mdl)
+	if err != nil {
+		return fmt.Errorf("storage: set conflict suggestion: %w", err)
+	}
+	return nil
+}
+
 // GetResolvedConflictsByType returns recently resolved conflicts with a declared
 // winner for the given decision type. Used by akashi_check to surface prior
 // resolutions so agents avoid resurrecting the losing approach.
@@ -417,6 +562,9 @@ func (db *DB) UpdateConflictStatusWithAudit(ctx context.Context, id, orgID uuid.
 			id, orgID).Scan(&oldStatus, &decisionAID, &decisionBID); scanErr != nil {
 			return fmt.Errorf("storage: conflict: %w", ErrNotFound)
 		}
+		if oldStatus == status {
+			return fmt.Errorf("storage: conflict %s: %w", status, ErrConflictStatusUnchanged)
+		}
 
 		// Validate winning_decision_id belongs to this conflict.
 		if winningDecisionID != nil && status == "resolved" {
@@ -497,8 +645,8 @@ func (db *DB) UpdateConflictStatusWithAudit(ctx context.Context, id, orgID uuid.
 // creates a new group atomically via CTE (used by callers without embeddings).
 //
 // Canonical pair ordering (decision_a_id < decision_b_id by bytes) is applied
-// before the insert; the UNIQUE constraint on (decision_a_id, decision_b_id)
-// prevents true duplicate rows.
+// before the insert; the UNIQUE constraint on (decision_a_id, decision_b_id,
+// conflict_kind) prevents true duplicate rows.
 //
 // Returns the scored_conflicts row UUID (the ID field on c is ignored).
 func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict) (uuid.UUID, error) {
@@ -543,7 +691,7 @@ func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict
 			topicSim, outcomeDiv, sig, method, c.Explanation,
 			c.Category, c.Severity, c.Relationship, c.ConfidenceWeight, c.TemporalDecay,
 			claimTextA, claimTextB, *c.GroupID, c.ReopensResolutionID,
-			projectA, projectB,
+			projectA, projectB, c.ReasonCode,
 		)
 	}
 
@@ -578,7 +726,7 @@ func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict
 		     SELECT id, org_id, resolved_by, resolved_at, resolution_note, winning_decision_id
 		     FROM scored_conflicts
 		     WHERE decision_a_id = $1 AND decision_b_id = $2
-		       AND org_id = $3
+		       AND org_id = $3 AND conflict_kind = $4
 		       AND status = 'resolved' AND resolved_by IS NOT NULL
 		 ), existing AS (
 		     SELECT id FROM conflict_groups
@@ -607,13 +755,13 @@ func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict
 		      topic_similarity, outcome_divergence, significance, scoring_method, explanation,
 		      category, severity, relationship, confidence_weight, temporal_decay,
 		      claim_text_a, claim_text_b, group_id, reopens_resolution_id,
-		      project_a, project_b)
+		      project_a, project_b, reason_code)
 		 SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
 		        $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
 		        $21, $22, grp.id, $24,
-		        $26, $27
+		        $26, $27, $28
 		 FROM grp
-		 ON CONFLICT (decision_a_id, decision_b_id) DO UPDATE SET
+		 ON CONFLICT (decision_a_id, decision_b_id, conflict_kind) DO UPDATE SET
 		     topic_similarity    = EXCLUDED.topic_similarity,
 		     outcome_divergence  = EXCLUDED.outcome_divergence,
 		     significance        = EXCLUDED.significance,
@@ -630,6 +778,7 @@ func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict
 		     reopens_resolution_id = EXCLUDED.reopens_resolution_id,
 		     project_a           = EXCLUDED.project_a,
 		     project_b           = EXCLUDED.project_b,
+		     reason_code         = EXCLUDED.reason_code,
 		     detected_at         = now(),
 		     status              = CASE WHEN scored_conflicts.status = 'resolved' THEN 'open'
 		                                ELSE scored_conflicts.status END,
@@ -647,7 +796,7 @@ func (db *DB) InsertScoredConflict(ctx context.Context, c model.DecisionConflict
 		topicSim, outcomeDiv, sig, method, c.Explanation,
 		c.Category, c.Severity, c.Relationship, c.ConfidenceWeight, c.TemporalDecay,
 		claimTextA, claimTextB, topicLabel, c.ReopensResolutionID, firstDetected,
-		projectA, projectB,
+		projectA, projectB, c.ReasonCode,
 	).Scan(&id)
 	if err != nil {
 		return uuid.Nil, err
@@ -668,7 +817,7 @@ func (db *DB) insertScoredConflictWithGroup(
 	claimTextA, claimTextB *string,
 	groupID uuid.UUID,
 	reopensResolutionID *uuid.UUID,
-	projectA, projectB *string,
+	projectA, projectB, reasonCode *string,
 ) (uuid.UUID, error) {
 	var id uuid.UUID
 	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
@@ -689,9 +838,9 @@ func (db *DB) insertScoredConflictWithGroup(
 			 SELECT id, org_id, resolved_by, resolved_at, resolution_note, winning_decision_id
 			 FROM scored_conflicts
 			 WHERE decision_a_id = $1 AND decision_b_id = $2
-			   AND org_id = $3
+			   AND org_id = $3 AND conflict_kind = $4
 			   AND status = 'resolved' AND resolved_by IS NOT NULL`,
-			da, dbID, orgID,
+			da, dbID, orgID, string(conflictKind),
 		); err != nil {
 			return fmt.Errorf("storage: archive conflict resolution: %w", err)
 		}
@@ -703,11 +852,11 @@ func (db *DB) insertScoredConflictWithGroup(
 		      topic_similarity, outcome_divergence, significance, scoring_method, explanation,
 		      category, severity, relationship, confidence_weight, temporal_decay,
 		      claim_text_a, claim_text_b, group_id, reopens_resolution_id,
-		      project_a, project_b)
+		      project_a, project_b, reason_code)
 		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
 		         $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
-		         $21, $22, $23, $24, $25, $26)
-		 ON CONFLICT (decision_a_id, decision_b_id) DO UPDATE SET
+		         $21, $22, $23, $24, $25, $26, $27)
+		 ON CONFLICT (decision_a_id, decision_b_id, conflict_kind) DO UPDATE SET
 		     topic_similarity    = EXCLUDED.topic_similarity,
 		     outcome_divergence  = EXCLUDED.outcome_divergence,
 		     significance        = EXCLUDED.significance,
@@ -724,6 +873,7 @@ func (db *DB) insertScoredConflictWithGroup(
 		     reopens_resolution_id = EXCLUDED.reopens_resolution_id,
 		     project_a           = EXCLUDED.project_a,
 		     project_b           = EXCLUDED.project_b,
+		     reason_code         = EXCLUDED.reason_code,
 		     detected_at         = now(),
 		     status              = CASE WHEN scored_conflicts.status = 'resolved' THEN 'open'
 		                                ELSE scored_conflicts.status END,
@@ -741,7 +891,7 @@ func (db *DB) insertScoredConflictWithGroup(
 			topicSim, outcomeDiv, sig, method, explanation,
 			category, severity, relationship, confWeight, tempDecay,
 			claimTextA, claimTextB, groupID, reopensResolutionID,
-			projectA, projectB,
+			projectA, projectB, reasonCode,
 		).Scan(&id)
 		if err != nil {
 			return fmt.Errorf("storage: insert scored conflict: %w", err)
@@ -0,0 +1,60 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxConsensusRows caps the decisions one confidence consensus query scans.
+const maxConsensusRows = 10000
+
+// ConsensusDecision is one agent's current decision on the entity named by a
+// metadata value, as returned by ListConsensusDecisions.
+type ConsensusDecision struct {
+	MetadataValue string
+	DecisionID    uuid.UUID
+	AgentID       string
+	Outcome       string
+	Confidence    float32
+	ValidFrom     time.Time
+}
+
+// ListConsensusDecisions returns, for every value of metadata key metadataKey
+// on current decisions of decisionType in the namespace, each agent's most
+// recent decision, ordered by metadata value then agent. metadataValue, when
+// non-nil, restricts the result to one entity. agentIDs, when non-nil,
+// restricts the agents considered; callers pass the caller's granted set so
+// unreadable agents never enter the aggregate.
+func (db *DB) ListConsensusDecisions(ctx context.Context, orgID uuid.UUID, namespace, decisionType, metadataKey string, metadataValue *string, agentIDs []string) ([]ConsensusDecision, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT DISTINCT ON (metadata->>$4, agent_id)
+		        metadata->>$4, id, agent_id, outcome, confidence, valid_from
+		 FROM decisions
+		 WHERE org_id = $1 AND namespace = $2 AND decision_type = $3
+		   AND valid_to IS NULL AND metadata->>$4 IS NOT NULL
+		   AND ($5::text IS NULL OR metadata->>$4 = $5)
+		   AND ($6::text[] IS NULL OR agent_id = ANY($6))
+		 ORDER BY metadata->>$4, agent_id, valid_from DESC, id DESC
+		 LIMIT $7`,
+		orgID, namespace, decisionType, metadataKey, metadataValue, agentIDs, maxConsensusRows,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list consensus decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ConsensusDecision
+	for rows.Next() {
+		var d ConsensusDecision
+		if err := rows.Scan(&d.MetadataValue, &d.DecisionID, &d.AgentID, &d.Outcome, &d.Confidence, &d.ValidFrom); err != nil {
+			return nil, fmt.Errorf("storage: scan consensus decision: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
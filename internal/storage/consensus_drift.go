@@ -0,0 +1,63 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// UpsertConsensusDriftConflict records a consensus_drift conflict. c.DecisionAID
+// is the majority exemplar and c.DecisionBID the minority exemplar; the pair
+// is stored as given, not canonicalized, so the sides keep their meaning.
+// c.MinorityIDs lists every decision in the minority.
+//
+// Re-detecting the same exemplar pair refreshes the scores, explanation, and
+// minority set. Status is left alone: a drift someone resolved or marked a
+// false positive stays that way.
+//
+// Returns the scored_conflicts row UUID and whether the row is new.
+func (db *DB) UpsertConsensusDriftConflict(ctx context.Context, c model.DecisionConflict) (uuid.UUID, bool, error) {
+	topicSim, outcomeDiv, sig := 0.0, 0.0, 0.0
+	if c.TopicSimilarity != nil {
+		topicSim = *c.TopicSimilarity
+	}
+	if c.OutcomeDivergence != nil {
+		outcomeDiv = *c.OutcomeDivergence
+	}
+	if c.Significance != nil {
+		sig = *c.Significance
+	}
+
+	var id uuid.UUID
+	var inserted bool
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO scored_conflicts
+		     (decision_a_id, decision_b_id, org_id, conflict_kind,
+		      agent_a, agent_b, decision_type_a, decision_type_b, outcome_a, outcome_b,
+		      topic_similarity, outcome_divergence, significance, scoring_method, explanation,
+		      severity, project_a, project_b, minority_ids)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+		         $11, $12, $13, 'embedding', $14, $15, $16, $17, $18)
+		 ON CONFLICT (decision_a_id, decision_b_id, conflict_kind) DO UPDATE SET
+		     topic_similarity   = EXCLUDED.topic_similarity,
+		     outcome_divergence = EXCLUDED.outcome_divergence,
+		     significance       = EXCLUDED.significance,
+		     explanation        = EXCLUDED.explanation,
+		     minority_ids       = EXCLUDED.minority_ids,
+		     detected_at        = now()
+		 RETURNING id, (xmax = 0)`,
+		c.DecisionAID, c.DecisionBID, c.OrgID, string(model.ConflictKindConsensusDrift),
+		c.AgentA, c.AgentB, c.DecisionTypeA, c.DecisionTypeB, c.OutcomeA, c.OutcomeB,
+		topicSim, outcomeDiv, sig, c.Explanation,
+		c.Severity, c.ProjectA, c.ProjectB, c.MinorityIDs,
+	).Scan(&id, &inserted)
+	if err != nil {
+		return uuid.Nil, false, fmt.Errorf("storage: upsert consensus drift conflict: %w", err)
+	}
+	return id, inserted, nil
+}
@@ -0,0 +1,72 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AddDecisionTags attaches tags to a decision in the org. Tags already on the
+// decision are left as they are. A decision outside the org gains no tags;
+// callers resolve the decision first to report not-found.
+func (db *DB) AddDecisionTags(ctx context.Context, orgID, decisionID uuid.UUID, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO decision_tags (decision_id, org_id, tag)
+		 SELECT d.id, d.org_id, t.tag
+		 FROM decisions d, unnest($3::text[]) AS t(tag)
+		 WHERE d.id = $1 AND d.org_id = $2
+		 ON CONFLICT (decision_id, tag) DO NOTHING`,
+		decisionID, orgID, tags,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: add decision tags: %w", err)
+	}
+	return nil
+}
+
+// RemoveDecisionTags detaches tags from a decision. Tags not on the decision
+// are ignored.
+func (db *DB) RemoveDecisionTags(ctx context.Context, orgID, decisionID uuid.UUID, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := db.pool.Exec(ctx,
+		`DELETE FROM decision_tags WHERE org_id = $1 AND decision_id = $2 AND tag = ANY($3)`,
+		orgID, decisionID, tags,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: remove decision tags: %w", err)
+	}
+	return nil
+}
+
+// ListDecisionTags returns a decision's tags in alphabetical order.
+func (db *DB) ListDecisionTags(ctx context.Context, orgID, decisionID uuid.UUID) ([]string, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT tag FROM decision_tags WHERE org_id = $1 AND decision_id = $2 ORDER BY tag`,
+		orgID, decisionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list decision tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("storage: scan decision tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: list decision tags: %w", err)
+	}
+	return tags, nil
+}
@@ -4,11 +4,16 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,19 +24,20 @@ import (
 	"github.com/ashita-ai/akashi/internal/search"
 )
 
-// decisionCols is the SELECT column list for the standard 25-column decision query.
+// decisionCols is the SELECT column list for the standard 34-column decision query.
 // Every function that scans into model.Decision via scanOneDecision must SELECT
 // exactly these columns in this order.
 const decisionCols = `id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 	metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-	valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project`
+	valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project,
+	confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version`
 
 // pgxRowScanner is satisfied by both pgx.Row (single-row) and pgx.Rows (multi-row).
 type pgxRowScanner interface {
 	Scan(dest ...any) error
 }
 
-// scanOneDecision scans the 25-column decisionCols from a single row.
+// scanOneDecision scans the 34-column decisionCols from a single row.
 func scanOneDecision(row pgxRowScanner) (model.Decision, error) {
 	var d model.Decision
 	if err := row.Scan(
@@ -41,6 +47,7 @@ func scanOneDecision(row pgxRowScanner) (model.Decision, error) {
 		&d.ValidFrom, &d.ValidTo, &d.TransactionTime, &d.CreatedAt,
 		&d.SessionID, &d.AgentContext, &d.APIKeyID,
 		&d.Tool, &d.Model, &d.Project,
+		&d.ConfidenceLow, &d.ConfidenceHigh, &d.Namespace, &d.OutcomeFlipped, &d.EmbeddingModel, &d.BatchID, &d.ContextSnapshot, &d.ContextSnapshotTruncated, &d.HashVersion,
 	); err != nil {
 		return model.Decision{}, fmt.Errorf("storage: scan decision: %w", err)
 	}
@@ -79,6 +86,9 @@ func (db *DB) CreateDecision(ctx context.Context, d model.Decision) (model.Decis
 	if d.CreatedAt.IsZero() {
 		d.CreatedAt = now
 	}
+	if d.Namespace == "" {
+		d.Namespace = model.DefaultNamespace
+	}
 	if d.Metadata == nil {
 		d.Metadata = map[string]any{}
 	}
@@ -87,18 +97,21 @@ func (db *DB) CreateDecision(ctx context.Context, d model.Decision) (model.Decis
 	}
 
 	d.ContentHash = integrity.ComputeContentHash(d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+	d.HashVersion = integrity.CurrentHashVersion
 
 	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
 		_, err := tx.Exec(ctx,
 			`INSERT INTO decisions (id, run_id, agent_id, org_id, decision_type, outcome, confidence,
 			 reasoning, embedding, outcome_embedding, metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-			 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`,
+			 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id,
+			 confidence_low, confidence_high, namespace, embedding_model, hash_version)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29)`,
 			d.ID, d.RunID, d.AgentID, d.OrgID, d.DecisionType, d.Outcome, d.Confidence,
 			d.Reasoning, d.Embedding, d.OutcomeEmbedding, d.Metadata, d.CompletenessScore, d.OutcomeScore, d.PrecedentRef,
 			d.PrecedentReason, d.SupersedesID, d.ContentHash,
 			d.ValidFrom, d.ValidTo, d.TransactionTime, d.CreatedAt,
 			d.SessionID, d.AgentContext, d.APIKeyID,
+			d.ConfidenceLow, d.ConfidenceHigh, d.Namespace, d.EmbeddingModel, d.HashVersion,
 		)
 		if err != nil {
 			return fmt.Errorf("storage: create decision: %w", err)
@@ -124,12 +137,13 @@ func (db *DB) CreateDecision(ctx context.Context, d model.Decision) (model.Decis
 type GetDecisionOpts struct {
 	IncludeAlts     bool // Load alternatives.
 	IncludeEvidence bool // Load evidence.
+	IncludeBlobs    bool // Rehydrate evidence content stored in evidence_blobs; see GetEvidenceByDecision.
 	CurrentOnly     bool // If true, return only if the decision has not been superseded (valid_to IS NULL).
 }
 
 // GetDecision retrieves a decision by ID with configurable includes and filtering.
 func (db *DB) GetDecision(ctx context.Context, orgID, id uuid.UUID, opts GetDecisionOpts) (model.Decision, error) {
-	query := `SELECT ` + decisionCols + ` FROM decisions WHERE id = $1 AND org_id = $2`
+	query := `SELECT ` + decisionCols + ` FROM decisions WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`
 	if opts.CurrentOnly {
 		query += ` AND valid_to IS NULL`
 	}
@@ -151,7 +165,7 @@ func (db *DB) GetDecision(ctx context.Context, orgID, id uuid.UUID, opts GetDeci
 	}
 
 	if opts.IncludeEvidence {
-		ev, err := db.GetEvidenceByDecision(ctx, id, orgID)
+		ev, err := db.GetEvidenceByDecision(ctx, id, orgID, opts.IncludeBlobs)
 		if err != nil {
 			return model.Decision{}, err
 		}
@@ -161,9 +175,44 @@ func (db *DB) GetDecision(ctx context.Context, orgID, id uuid.UUID, opts GetDeci
 	return d, nil
 }
 
+// FindSupersedeMatch returns the ID of the most recent active decision by
+// agentID in the org and namespace with the given decision type whose metadata
+// contains every key/value pair in match (JSONB containment). Only the tracing
+// agent's own decisions match, so one agent cannot supersede another's.
+// Returns nil when none matches.
+func (db *DB) FindSupersedeMatch(ctx context.Context, orgID uuid.UUID, agentID, namespace, decisionType string, match map[string]any) (*uuid.UUID, error) {
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	var id uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`SELECT id FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND namespace = $3 AND decision_type = $4
+		   AND metadata @> $5 AND valid_to IS NULL
+		 ORDER BY valid_from DESC, id DESC
+		 LIMIT 1`,
+		orgID, agentID, namespace, decisionType, match,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: find supersede match: %w", err)
+	}
+	return &id, nil
+}
+
+// SetConfidencePrecision makes ReviseDecision round confidence the way
+// CreateTraceParams.ConfidencePrecision does for traces. Zero stores
+// confidence exactly. Call it before the DB is shared.
+func (db *DB) SetConfidencePrecision(precision int) {
+	db.confPrecision = precision
+}
+
 // ReviseDecision invalidates an existing decision by setting valid_to
-// and creates a new decision with the revised data. When audit is non-nil,
-// a mutation audit entry recording the revision is inserted in the same transaction.
+// and creates a new decision with the revised data. Confidence is rounded
+// and hashed per SetConfidencePrecision. When audit is non-nil, a mutation
+// audit entry recording the revision is inserted in the same transaction.
 func (db *DB) ReviseDecision(ctx context.Context, originalID uuid.UUID, revised model.Decision, audit *MutationAuditEntry) (model.Decision, error) {
 	now := time.Now().UTC()
 
@@ -173,37 +222,58 @@ func (db *DB) ReviseDecision(ctx context.Context, originalID uuid.UUID, revised
 	revised.TransactionTime = now
 	revised.CreatedAt = now
 	revised.SupersedesID = &originalID
+	if revised.Namespace == "" {
+		revised.Namespace = model.DefaultNamespace
+	}
 	if revised.Metadata == nil {
 		revised.Metadata = map[string]any{}
 	}
-	revised.ContentHash = integrity.ComputeContentHash(revised.ID, revised.DecisionType, revised.Outcome, revised.Confidence, revised.Reasoning, revised.ValidFrom)
+	if db.confPrecision > 0 {
+		revised.Confidence = integrity.RoundConfidence(revised.Confidence, db.confPrecision)
+		if revised.ConfidenceLow != nil {
+			low := integrity.RoundConfidence(*revised.ConfidenceLow, db.confPrecision)
+			revised.ConfidenceLow = &low
+		}
+		if revised.ConfidenceHigh != nil {
+			high := integrity.RoundConfidence(*revised.ConfidenceHigh, db.confPrecision)
+			revised.ConfidenceHigh = &high
+		}
+	}
+	revised.ContentHash = integrity.ComputeRoundedContentHash(revised.ID, revised.DecisionType, revised.Outcome, revised.Confidence, revised.Reasoning, revised.ValidFrom, db.confPrecision)
+	revised.HashVersion = integrity.CurrentHashVersion
 	if revised.AgentContext == nil {
 		revised.AgentContext = map[string]any{}
 	}
 
 	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
 		// Invalidate original decision, scoped by org_id for tenant isolation.
-		tag, err := tx.Exec(ctx,
-			`UPDATE decisions SET valid_to = $1 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL`,
+		var previousOutcome string
+		err := tx.QueryRow(ctx,
+			`UPDATE decisions SET valid_to = $1 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL
+			 RETURNING outcome`,
 			now, originalID, revised.OrgID,
-		)
+		).Scan(&previousOutcome)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("storage: original decision %s (or already revised): %w", originalID, ErrNotFound)
+		}
 		if err != nil {
 			return fmt.Errorf("storage: invalidate decision: %w", err)
 		}
-		if tag.RowsAffected() == 0 {
-			return fmt.Errorf("storage: original decision %s (or already revised): %w", originalID, ErrNotFound)
-		}
+		revised.OutcomeFlipped = model.IsOutcomeFlip(previousOutcome, revised.Outcome)
 
 		_, err = tx.Exec(ctx,
 			`INSERT INTO decisions (id, run_id, agent_id, org_id, decision_type, outcome, confidence,
 			 reasoning, embedding, outcome_embedding, metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-			 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`,
+			 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id,
+			 confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33)`,
 			revised.ID, revised.RunID, revised.AgentID, revised.OrgID, revised.DecisionType, revised.Outcome,
 			revised.Confidence, revised.Reasoning, revised.Embedding, revised.OutcomeEmbedding, revised.Metadata,
 			revised.CompletenessScore, revised.OutcomeScore, revised.PrecedentRef, revised.PrecedentReason, revised.SupersedesID, revised.ContentHash,
 			revised.ValidFrom, revised.ValidTo, revised.TransactionTime, revised.CreatedAt,
 			revised.SessionID, revised.AgentContext, revised.APIKeyID,
+			revised.ConfidenceLow, revised.ConfidenceHigh, revised.Namespace, revised.OutcomeFlipped, revised.EmbeddingModel, revised.BatchID,
+			revised.ContextSnapshot, revised.ContextSnapshotTruncated, revised.HashVersion,
 		)
 		if err != nil {
 			return fmt.Errorf("storage: insert revised decision: %w", err)
@@ -235,6 +305,7 @@ func (db *DB) ReviseDecision(ctx context.Context, originalID uuid.UUID, revised
 				"superseded_by":           revised.ID.String(),
 				"valid_to":                now,
 				"conflicts_auto_resolved": autoResolved,
+				"outcome_flipped":         revised.OutcomeFlipped,
 			}
 			if err := InsertMutationAuditTx(ctx, tx, *audit); err != nil {
 				return fmt.Errorf("storage: audit in revision tx: %w", err)
@@ -340,12 +411,14 @@ type DecisionErasureResult struct {
 // Within a single transaction it:
 //  1. Activates the immutability trigger bypass via SET LOCAL
 //  2. Scrubs outcome/reasoning to "[erased]" and recomputes the content hash
-//  3. Scrubs alternatives (label, rejection_reason) and evidence (content, source_uri)
+//  3. Scrubs alternatives (label, rejection_reason) and evidence (content, source_uri),
+//     deleting evidence blobs no other evidence shares
 //  4. Scrubs claims (claim_text derived from reasoning contains PII)
 //  5. Nulls out embeddings (contain semantic PII)
-//  6. Inserts a decision_erasures row with the original hash
-//  7. Records a DecisionErased event and mutation audit entry
-//  8. Queues a search index deletion
+//  6. Redacts outcome/reasoning in the decision's decision_cdc row images
+//  7. Inserts a decision_erasures row with the original hash
+//  8. Records a DecisionErased event and mutation audit entry
+//  9. Queues a search index deletion
 //
 // Does NOT set valid_to — the decision remains "active" but scrubbed.
 func (db *DB) EraseDecision(
@@ -405,10 +478,10 @@ func (db *DB) EraseDecision(
 		// Scrub the decision row.
 		_, err = tx.Exec(ctx,
 			`UPDATE decisions
-		 SET outcome = $1, reasoning = $2, content_hash = $3,
+		 SET outcome = $1, reasoning = $2, content_hash = $3, hash_version = $4,
 		     embedding = NULL, outcome_embedding = NULL
-		 WHERE id = $4 AND org_id = $5`,
-			ErasedSentinel, ErasedSentinel, newHash, decisionID, orgID,
+		 WHERE id = $5 AND org_id = $6`,
+			ErasedSentinel, ErasedSentinel, newHash, integrity.CurrentHashVersion, decisionID, orgID,
 		)
 		if err != nil {
 			return fmt.Errorf("storage: scrub decision: %w", err)
@@ -426,16 +499,41 @@ func (db *DB) EraseDecision(
 			return fmt.Errorf("storage: scrub alternatives: %w", err)
 		}
 
-		// Scrub evidence.
+		// Scrub evidence. Blob content is shared by hash across decisions, so
+		// the erased evidence drops its reference and the blob itself is
+		// deleted only once nothing else references it.
+		var blobHashes []string
+		err = tx.QueryRow(ctx,
+			`SELECT COALESCE(array_agg(DISTINCT blob_hash) FILTER (WHERE blob_hash IS NOT NULL), '{}')
+		 FROM evidence WHERE decision_id = $1 AND org_id = $2`,
+			decisionID, orgID,
+		).Scan(&blobHashes)
+		if err != nil {
+			return fmt.Errorf("storage: find evidence blobs: %w", err)
+		}
 		evTag, err := tx.Exec(ctx,
 			`UPDATE evidence
-		 SET content = $1, source_uri = NULL, embedding = NULL
+		 SET content = $1, source_uri = NULL, embedding = NULL, blob_hash = NULL
 		 WHERE decision_id = $2 AND org_id = $3`,
 			ErasedSentinel, decisionID, orgID,
 		)
 		if err != nil {
 			return fmt.Errorf("storage: scrub evidence: %w", err)
 		}
+		if len(blobHashes) > 0 {
+			_, err = tx.Exec(ctx,
+				`DELETE FROM evidence_blobs b
+			 WHERE b.org_id = $1 AND b.content_hash = ANY($2)
+			   AND NOT EXISTS (
+			       SELECT 1 FROM evidence e
+			       WHERE e.org_id = b.org_id AND e.blob_hash = b.content_hash
+			   )`,
+				orgID, blobHashes,
+			)
+			if err != nil {
+				return fmt.Errorf("storage: delete erased evidence blobs: %w", err)
+			}
+		}
 
 		// Scrub claims (claim_text is derived from reasoning and may contain PII).
 		claimTag, err := tx.Exec(ctx,
@@ -448,6 +546,30 @@ func (db *DB) EraseDecision(
 			return fmt.Errorf("storage: scrub claims: %w", err)
 		}
 
+		// Redact the CDC row images. Erasure is an in-place UPDATE, so the
+		// CDC delete trigger never fires and the images recorded at create,
+		// revise, and archive time still hold the original text. The
+		// decision appears as the after image of its own entries and as the
+		// before image of its successor's revise entry.
+		_, err = tx.Exec(ctx,
+			`UPDATE decision_cdc
+		 SET before = CASE WHEN before->>'id' = $1::text
+		                   THEN before || jsonb_build_object('outcome', $3::text, 'reasoning', $3::text,
+		                                                     'content_hash', $4::text, 'hash_version', $5::int)
+		                   ELSE before END,
+		     after = CASE WHEN after->>'id' = $1::text
+		                  THEN after || jsonb_build_object('outcome', $3::text, 'reasoning', $3::text,
+		                                                   'content_hash', $4::text, 'hash_version', $5::int)
+		                  ELSE after END
+		 WHERE org_id = $2
+		   AND (decision_id = $1
+		        OR decision_id IN (SELECT id FROM decisions WHERE supersedes_id = $1 AND org_id = $2))`,
+			decisionID, orgID, ErasedSentinel, newHash, integrity.CurrentHashVersion,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: redact decision cdc: %w", err)
+		}
+
 		// Queue search index deletion.
 		if err := queueSearchOutbox(ctx, tx, decisionID, orgID, "delete"); err != nil {
 			return fmt.Errorf("storage: queue search outbox delete in erasure: %w", err)
@@ -550,6 +672,55 @@ func (db *DB) GetDecisionErasure(ctx context.Context, orgID, decisionID uuid.UUI
 // Only returns active decisions (valid_to IS NULL). Use QueryDecisionsTemporal for
 // point-in-time queries that include superseded decisions.
 func (db *DB) QueryDecisions(ctx context.Context, orgID uuid.UUID, req model.QueryRequest) ([]model.Decision, int, error) {
+	selectQuery, args := queryDecisionsSQL(orgID, req)
+
+	rows, err := db.readPool().Query(ctx, selectQuery, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: query decisions: %w", err)
+	}
+	defer rows.Close()
+
+	decisions, total, err := scanDecisionsWithTotal(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Optionally load related data in batch (avoids N+1 queries).
+	includeAlts := containsStr(req.Include, "alternatives")
+	includeEvidence := containsStr(req.Include, "evidence")
+	includeFlags := containsStr(req.Include, "flags")
+	if (includeAlts || includeEvidence || includeFlags) && len(decisions) > 0 {
+		if err := db.loadDecisionIncludes(ctx, orgID, decisions, includeAlts, includeEvidence, includeFlags); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return decisions, total, nil
+}
+
+// ExplainQueryDecisions returns the PostgreSQL plan for the main select that
+// QueryDecisions would run for req, as EXPLAIN (FORMAT JSON) output. The
+// query itself is never executed: plain EXPLAIN only plans, and it runs in a
+// read-only transaction that is always rolled back. Include loaders are not
+// part of the plan.
+func (db *DB) ExplainQueryDecisions(ctx context.Context, orgID uuid.UUID, req model.QueryRequest) (string, json.RawMessage, error) {
+	selectQuery, args := queryDecisionsSQL(orgID, req)
+
+	tx, err := db.readPool().BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return "", nil, fmt.Errorf("storage: explain query decisions: begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var plan json.RawMessage
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+selectQuery, args...).Scan(&plan); err != nil {
+		return "", nil, fmt.Errorf("storage: explain query decisions: %w", err)
+	}
+	return selectQuery, plan, nil
+}
+
+// queryDecisionsSQL builds the paginated select used by QueryDecisions.
+func queryDecisionsSQL(orgID uuid.UUID, req model.QueryRequest) (string, []any) {
 	where, args := buildDecisionWhereClause(orgID, req.Filters, 1, true)
 
 	// Filter by OTEL trace_id via agent_runs join.
@@ -558,74 +729,118 @@ func (db *DB) QueryDecisions(ctx context.Context, orgID uuid.UUID, req model.Que
 		where += fmt.Sprintf(" AND run_id IN (SELECT id FROM agent_runs WHERE trace_id = $%d AND org_id = $1)", len(args))
 	}
 
-	// Build order clause.
-	orderBy := "valid_from"
-	if req.OrderBy != "" {
-		switch req.OrderBy {
-		case "confidence", "valid_from", "decision_type", "outcome", "completeness_score", "outcome_score", "quality_score", "agent_id", "project":
-			// quality_score accepted as deprecated alias; maps to the renamed column.
-			if req.OrderBy == "quality_score" {
-				orderBy = "completeness_score"
-			} else {
-				orderBy = req.OrderBy
-			}
-		}
-	}
 	orderDir := "DESC"
 	if strings.EqualFold(req.OrderDir, "asc") {
 		orderDir = "ASC"
 	}
 
+	// Build order clause. Conflict-based orderings join open conflict counts
+	// and break ties by recency in the same direction.
+	from := "decisions"
+	orderClause := "valid_from " + orderDir
+	switch req.OrderBy {
+	case "confidence", "valid_from", "decision_type", "outcome", "completeness_score", "outcome_score", "agent_id", "project":
+		orderClause = req.OrderBy + " " + orderDir
+	case "quality_score":
+		// Deprecated alias; maps to the renamed column.
+		orderClause = "completeness_score " + orderDir
+	case "conflict_count":
+		from += openConflictCountsJoin
+		orderClause = fmt.Sprintf("COALESCE(oc.open_conflicts, 0) %s, valid_from %s", orderDir, orderDir)
+	case "triage":
+		from += openConflictCountsJoin
+		orderClause = fmt.Sprintf("%s %s, valid_from %s", triageScoreSQL, orderDir, orderDir)
+	}
+
 	limit, offset := clampPagination(req.Limit, req.Offset, 50, 1000)
 
 	// Use COUNT(*) OVER() window function to get the total count alongside data
 	// rows in a single query, eliminating a separate COUNT(*) table scan.
 	selectQuery := fmt.Sprintf(
-		`SELECT %s, COUNT(*) OVER() FROM decisions%s ORDER BY %s %s LIMIT %d OFFSET %d`,
-		decisionCols, where, orderBy, orderDir, limit, offset,
+		`SELECT %s, COUNT(*) OVER() FROM %s%s ORDER BY %s LIMIT %d OFFSET %d`,
+		decisionCols, from, where, orderClause, limit, offset,
 	)
+	return selectQuery, args
+}
 
-	rows, err := db.pool.Query(ctx, selectQuery, args...)
-	if err != nil {
-		return nil, 0, fmt.Errorf("storage: query decisions: %w", err)
-	}
-	defer rows.Close()
-
-	decisions, total, err := scanDecisionsWithTotal(rows)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// Optionally load related data in batch (avoids N+1 queries).
-	includeAlts := containsStr(req.Include, "alternatives")
-	includeEvidence := containsStr(req.Include, "evidence")
-	if (includeAlts || includeEvidence) && len(decisions) > 0 {
-		ids := make([]uuid.UUID, len(decisions))
-		for i := range decisions {
-			ids[i] = decisions[i].ID
-		}
-
+// openConflictCountsJoin attaches each decision's number of open scored
+// conflicts as oc.open_conflicts (NULL when it has none). Aggregating the
+// org's open conflicts once is cheaper than a correlated count per candidate
+// row, since open conflicts are few relative to decisions. $1 is the org ID,
+// as in every buildDecisionWhereClause result.
+const openConflictCountsJoin = ` LEFT JOIN (
+		SELECT conflict_decision_id, COUNT(*) AS open_conflicts
+		FROM (
+			SELECT decision_a_id AS conflict_decision_id FROM scored_conflicts WHERE org_id = $1 AND status = 'open'
+			UNION ALL
+			SELECT decision_b_id FROM scored_conflicts WHERE org_id = $1 AND status = 'open'
+		) c
+		GROUP BY conflict_decision_id
+	) oc ON oc.conflict_decision_id = decisions.id`
+
+// triageScoreSQL blends contention and recency for order_by=triage:
+// (1 + open conflicts) / (1 + age in days). One open conflict weighs the
+// same as one day of recency, so a contested decision from yesterday ranks
+// alongside an uncontested one from today.
+const triageScoreSQL = `(1 + COALESCE(oc.open_conflicts, 0))::float8 /
+	(1 + GREATEST(EXTRACT(EPOCH FROM now() - valid_from), 0) / 86400.0)`
+
+// loadDecisionIncludes attaches alternatives, evidence, and/or derived flags
+// to decisions in place. The queries are pipelined in a single pgx batch, so
+// the include path costs one network round trip after the main select instead
+// of one per relation. Results are identical to GetAlternativesByDecisions,
+// GetEvidenceByDecisions, and GetDecisionFlagsBatch, which share the same SQL
+// and scan code.
+func (db *DB) loadDecisionIncludes(ctx context.Context, orgID uuid.UUID, decisions []model.Decision, includeAlts, includeEvidence, includeFlags bool) error {
+	ids := make([]uuid.UUID, len(decisions))
+	for i := range decisions {
+		ids[i] = decisions[i].ID
+	}
+
+	var (
+		altsMap  map[uuid.UUID][]model.Alternative
+		evsMap   map[uuid.UUID][]model.Evidence
+		flagsMap map[uuid.UUID]model.DecisionFlags
+	)
+	batch := &pgx.Batch{}
+	if includeAlts {
+		batch.Queue(alternativesByDecisionsSQL, ids, orgID).Query(func(rows pgx.Rows) error {
+			var err error
+			altsMap, err = scanAlternativesByDecision(rows)
+			return err
+		})
+	}
+	if includeEvidence {
+		batch.Queue(evidenceByDecisionsSQL, ids, orgID).Query(func(rows pgx.Rows) error {
+			var err error
+			evsMap, err = scanEvidenceByDecision(rows)
+			return err
+		})
+	}
+	if includeFlags {
+		batch.Queue(decisionFlagsSQL, ids, orgID, model.LowConfidenceThreshold).Query(func(rows pgx.Rows) error {
+			var err error
+			flagsMap, err = scanDecisionFlags(rows)
+			return err
+		})
+	}
+	if err := db.readPool().SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("storage: load decision includes: %w", err)
+	}
+
+	for i := range decisions {
 		if includeAlts {
-			altsMap, err := db.GetAlternativesByDecisions(ctx, ids, orgID)
-			if err != nil {
-				return nil, 0, err
-			}
-			for i := range decisions {
-				decisions[i].Alternatives = altsMap[decisions[i].ID]
-			}
+			decisions[i].Alternatives = altsMap[decisions[i].ID]
 		}
 		if includeEvidence {
-			evsMap, err := db.GetEvidenceByDecisions(ctx, ids, orgID)
-			if err != nil {
-				return nil, 0, err
-			}
-			for i := range decisions {
-				decisions[i].Evidence = evsMap[decisions[i].ID]
-			}
+			decisions[i].Evidence = evsMap[decisions[i].ID]
+		}
+		if includeFlags {
+			f := flagsMap[decisions[i].ID]
+			decisions[i].Flags = &f
 		}
 	}
-
-	return decisions, total, nil
+	return nil
 }
 
 // QueryDecisionsTemporal executes a bi-temporal point-in-time query.
@@ -654,7 +869,7 @@ func (db *DB) QueryDecisionsTemporal(ctx context.Context, orgID uuid.UUID, req m
 
 	query := `SELECT ` + decisionCols + ` FROM decisions` + where + ` ORDER BY valid_from DESC` + limitClause
 
-	rows, err := db.pool.Query(ctx, query, args...)
+	rows, err := db.readPool().Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("storage: temporal query: %w", err)
 	}
@@ -735,17 +950,42 @@ func (db *DB) searchByFTS(ctx context.Context, orgID uuid.UUID, query string, fi
 		`SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		 metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
 		 valid_from, valid_to, transaction_time, created_at, session_id, agent_context,
-		 api_key_id, tool, model, project,
+		 api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version,
 		 ts_rank(search_vector, websearch_to_tsquery('english', $%d))
 		   * (0.5 + 0.2 * COALESCE(completeness_score, 0) + 0.3 * COALESCE(outcome_score, 0))
-		   * (1.0 / (1.0 + EXTRACT(EPOCH FROM (NOW() - valid_from)) / 86400.0 / 90.0))
-		   AS relevance
+		   * %s
+		   AS relevance,
+		 ts_headline('english', COALESCE(reasoning, '') || ' ' || outcome, websearch_to_tsquery('english', $%d))
 		 FROM decisions%s
 		 ORDER BY relevance DESC
-		 LIMIT %d`, qp, where, limit,
+		 LIMIT %d`, qp, recencyDecaySQL(filters, &args), qp, where, limit,
 	)
 
-	return db.execSearchQuery(ctx, sql, args)
+	results, err := db.execSearchQuery(ctx, sql, args)
+	if err != nil {
+		return nil, err
+	}
+	// ts_headline returns the start of the text, unmarked, when the row
+	// matched only on decision_type.
+	for i := range results {
+		if h := results[i].Highlights; len(h) > 0 && !strings.Contains(h[0], "<b>") {
+			results[i].Highlights = nil
+		}
+	}
+	return results, nil
+}
+
+// recencyDecaySQL returns the search ranking's recency factor for
+// filters.RecencyHalfLife(), appending the half-life to args. The factor is
+// 1 for a decision made now and 0.5 at the half-life; with decay disabled it
+// is the constant 1.
+func recencyDecaySQL(filters model.QueryFilters, args *[]any) string {
+	halfLife := filters.RecencyHalfLife()
+	if halfLife <= 0 {
+		return "1.0"
+	}
+	*args = append(*args, halfLife)
+	return fmt.Sprintf(`(1.0 / (1.0 + EXTRACT(EPOCH FROM (NOW() - valid_from)) / 86400.0 / $%d::float8))`, len(*args))
 }
 
 // searchByILIKE uses OR-any-term ILIKE matching as a fallback when FTS returns nothing.
@@ -764,7 +1004,9 @@ func (db *DB) searchByILIKE(ctx context.Context, orgID uuid.UUID, query string,
 	// OR across all terms: any word matching any field qualifies the row.
 	// Uses ILIKE instead of LOWER()+LIKE so PostgreSQL can use pg_trgm GIN indexes.
 	var termClauses []string
+	terms := make([]*regexp.Regexp, 0, len(words))
 	for _, word := range words {
+		terms = append(terms, regexp.MustCompile("(?i)"+regexp.QuoteMeta(word)))
 		escaped := strings.NewReplacer("%", `\%`, "_", `\_`).Replace(word)
 		args = append(args, "%"+escaped+"%")
 		p := len(args)
@@ -778,21 +1020,66 @@ func (db *DB) searchByILIKE(ctx context.Context, orgID uuid.UUID, query string,
 		`SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		 metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
 		 valid_from, valid_to, transaction_time, created_at, session_id, agent_context,
-		 api_key_id, tool, model, project,
+		 api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version,
 		 (0.5 + 0.2 * COALESCE(completeness_score, 0) + 0.3 * COALESCE(outcome_score, 0))
-		   * (1.0 / (1.0 + EXTRACT(EPOCH FROM (NOW() - valid_from)) / 86400.0 / 90.0))
-		   AS relevance
+		   * %s
+		   AS relevance,
+		 NULL::text
 		 FROM decisions%s
 		 ORDER BY relevance DESC
-		 LIMIT %d`, where, limit,
+		 LIMIT %d`, recencyDecaySQL(filters, &args), where, limit,
 	)
 
-	return db.execSearchQuery(ctx, sql, args)
+	results, err := db.execSearchQuery(ctx, sql, args)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		d := results[i].Decision
+		text := d.Outcome
+		if d.Reasoning != nil {
+			text = *d.Reasoning + " " + d.Outcome
+		}
+		if h := substringHighlight(text, terms); h != "" {
+			results[i].Highlights = []string{h}
+		}
+	}
+	return results, nil
+}
+
+// highlightContext is how many bytes of text substringHighlight keeps on
+// each side of the match.
+const highlightContext = 60
+
+// substringHighlight returns a window of text around the earliest match of
+// any term, with the match wrapped in <b></b> as ts_headline does. It
+// returns "" when no term matches.
+func substringHighlight(text string, terms []*regexp.Regexp) string {
+	var match []int
+	for _, re := range terms {
+		if loc := re.FindStringIndex(text); loc != nil && (match == nil || loc[0] < match[0]) {
+			match = loc
+		}
+	}
+	if match == nil {
+		return ""
+	}
+	start := max(0, match[0]-highlightContext)
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	end := min(len(text), match[1]+highlightContext)
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	return text[start:match[0]] + "<b>" + text[match[0]:match[1]] + "</b>" + text[match[1]:end]
 }
 
 // execSearchQuery runs a search SQL and scans results into SearchResult structs.
+// The SQL selects the decision columns followed by relevance and a nullable
+// highlight snippet.
 func (db *DB) execSearchQuery(ctx context.Context, sql string, args []any) ([]model.SearchResult, error) {
-	rows, err := db.pool.Query(ctx, sql, args...)
+	rows, err := db.readPool().Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("storage: text search decisions: %w", err)
 	}
@@ -802,6 +1089,7 @@ func (db *DB) execSearchQuery(ctx context.Context, sql string, args []any) ([]mo
 	for rows.Next() {
 		var d model.Decision
 		var relevance float32
+		var highlight *string
 		if err := rows.Scan(
 			&d.ID, &d.RunID, &d.AgentID, &d.OrgID, &d.DecisionType, &d.Outcome, &d.Confidence,
 			&d.Reasoning, &d.Metadata, &d.CompletenessScore, &d.OutcomeScore, &d.PrecedentRef,
@@ -809,11 +1097,16 @@ func (db *DB) execSearchQuery(ctx context.Context, sql string, args []any) ([]mo
 			&d.ValidFrom, &d.ValidTo, &d.TransactionTime, &d.CreatedAt,
 			&d.SessionID, &d.AgentContext, &d.APIKeyID,
 			&d.Tool, &d.Model, &d.Project,
-			&relevance,
+			&d.ConfidenceLow, &d.ConfidenceHigh, &d.Namespace, &d.OutcomeFlipped, &d.EmbeddingModel, &d.BatchID, &d.ContextSnapshot, &d.ContextSnapshotTruncated, &d.HashVersion,
+			&relevance, &highlight,
 		); err != nil {
 			return nil, fmt.Errorf("storage: scan text search result: %w", err)
 		}
-		results = append(results, model.SearchResult{Decision: d, SimilarityScore: relevance})
+		r := model.SearchResult{Decision: d, SimilarityScore: relevance}
+		if highlight != nil && *highlight != "" {
+			r.Highlights = []string{*highlight}
+		}
+		results = append(results, r)
 	}
 	return results, rows.Err()
 }
@@ -848,6 +1141,67 @@ func (db *DB) GetDecisionsByAgent(ctx context.Context, orgID uuid.UUID, agentID
 	return scanDecisionsWithTotal(rows)
 }
 
+// ListDecisionHistory returns the most recent decisions matching filters by
+// valid_from, newest first. Unlike QueryDecisions it includes revisions that
+// have since been superseded or retracted (valid_to set), so callers can show
+// how an agent's decisions evolved.
+func (db *DB) ListDecisionHistory(ctx context.Context, orgID uuid.UUID, filters model.QueryFilters, limit int) ([]model.Decision, error) {
+	limit, _ = clampPagination(limit, 0, 20, 1000)
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, false)
+	query := fmt.Sprintf(
+		`SELECT %s FROM decisions%s ORDER BY valid_from DESC, id DESC LIMIT %d`,
+		decisionCols, where, limit,
+	)
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list decision history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDecisions(rows)
+}
+
+// FindDecisionsByContentHash returns decisions in the org and namespace whose
+// content hash matches digest, a lowercase hex SHA-256 without version prefix.
+// With prefix=true, digest is matched as a prefix. Both legacy v1 and "v2:"
+// stored hashes match. Revised and retracted decisions are included: a
+// published proof may reference any version. An empty namespace means
+// model.DefaultNamespace.
+func (db *DB) FindDecisionsByContentHash(ctx context.Context, orgID uuid.UUID, namespace, digest string, prefix bool, limit, offset int) ([]model.Decision, int, error) {
+	limit, offset = clampPagination(limit, offset, 50, 1000)
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+
+	forms := integrity.StoredHashForms(digest)
+	cond := `content_hash = ANY($3)`
+	args := []any{orgID, namespace, forms}
+	if prefix {
+		patterns := make([]string, len(forms))
+		for i, f := range forms {
+			patterns[i] = f + "%"
+		}
+		cond = `content_hash LIKE ANY($3)`
+		args = []any{orgID, namespace, patterns}
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %s, COUNT(*) OVER() FROM decisions
+		 WHERE org_id = $1 AND namespace = $2 AND content_hash IS NOT NULL AND deleted_at IS NULL AND %s
+		 ORDER BY valid_from DESC, id LIMIT %d OFFSET %d`,
+		decisionCols, cond, limit, offset,
+	)
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: find decisions by content hash: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDecisionsWithTotal(rows)
+}
+
 func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx int, currentOnly bool) (string, []any) {
 	var conditions []string
 	var args []any
@@ -858,8 +1212,19 @@ func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx
 	args = append(args, orgID)
 	idx++
 
+	// Tombstoning closes valid_to, so current-only queries already skip
+	// tombstoned decisions; history and temporal queries need the check.
 	if currentOnly {
 		conditions = append(conditions, "valid_to IS NULL")
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	// Namespace is a second isolation scope beneath org_id.
+	if f.Namespace != nil {
+		conditions = append(conditions, fmt.Sprintf("namespace = $%d", idx))
+		args = append(args, *f.Namespace)
+		idx++
 	}
 
 	if len(f.AgentIDs) > 0 {
@@ -872,9 +1237,9 @@ func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx
 		args = append(args, *f.RunID)
 		idx++
 	}
-	if f.DecisionType != nil {
-		conditions = append(conditions, fmt.Sprintf("decision_type = $%d", idx))
-		args = append(args, *f.DecisionType)
+	if types := f.DecisionTypeList(); len(types) > 0 {
+		conditions = append(conditions, fmt.Sprintf("decision_type = ANY($%d)", idx))
+		args = append(args, types)
 		idx++
 	}
 	if f.ConfidenceMin != nil {
@@ -882,6 +1247,16 @@ func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx
 		args = append(args, *f.ConfidenceMin)
 		idx++
 	}
+	if f.MinConfidenceWidth != nil {
+		conditions = append(conditions, fmt.Sprintf("confidence_high - confidence_low >= $%d", idx))
+		args = append(args, *f.MinConfidenceWidth)
+		idx++
+	}
+	if f.OutcomeFlipped != nil {
+		conditions = append(conditions, fmt.Sprintf("outcome_flipped = $%d", idx))
+		args = append(args, *f.OutcomeFlipped)
+		idx++
+	}
 	if f.Outcome != nil {
 		conditions = append(conditions, fmt.Sprintf("outcome = $%d", idx))
 		args = append(args, *f.Outcome)
@@ -904,6 +1279,36 @@ func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx
 		args = append(args, *f.SessionID)
 		idx++
 	}
+	if f.BatchID != nil {
+		conditions = append(conditions, fmt.Sprintf("batch_id = $%d", idx))
+		args = append(args, *f.BatchID)
+		idx++
+	}
+	if f.TemperatureMin != nil {
+		conditions = append(conditions, fmt.Sprintf("(context_snapshot->>'temperature')::float8 >= $%d", idx))
+		args = append(args, *f.TemperatureMin)
+		idx++
+	}
+	if f.TemperatureMax != nil {
+		conditions = append(conditions, fmt.Sprintf("(context_snapshot->>'temperature')::float8 <= $%d", idx))
+		args = append(args, *f.TemperatureMax)
+		idx++
+	}
+	// EXISTS rather than a join so a decision with many rows is returned once.
+	if f.HasEvidence != nil {
+		conditions = append(conditions, existsCondition(*f.HasEvidence,
+			"SELECT 1 FROM evidence ev WHERE ev.decision_id = decisions.id"))
+	}
+	if f.HasAlternatives != nil {
+		conditions = append(conditions, existsCondition(*f.HasAlternatives,
+			"SELECT 1 FROM alternatives alt WHERE alt.decision_id = decisions.id"))
+	}
+	if len(f.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM decision_tags dt WHERE dt.decision_id = decisions.id AND dt.tag = ANY($%d))", idx))
+		args = append(args, f.Tags)
+		idx++
+	}
 	if f.Tool != nil {
 		conditions = append(conditions, fmt.Sprintf("tool = $%d", idx))
 		args = append(args, *f.Tool)
@@ -917,12 +1322,39 @@ func buildDecisionWhereClause(orgID uuid.UUID, f model.QueryFilters, startArgIdx
 	if f.Project != nil {
 		conditions = append(conditions, fmt.Sprintf("project = $%d", idx))
 		args = append(args, *f.Project)
-		idx++ //nolint:ineffassign // keep idx consistent so future additions don't miscount
+		idx++
+	}
+	// Sorted so the same filters always produce the same SQL text.
+	for _, key := range slices.Sorted(maps.Keys(f.ContextFilters)) {
+		conditions = append(conditions, fmt.Sprintf("agent_context @> $%d::jsonb", idx))
+		args = append(args, contextContainment(key, f.ContextFilters[key]))
+		idx++
 	}
 
 	return " WHERE " + strings.Join(conditions, " AND "), args
 }
 
+// contextContainment renders the JSON object an agent_context must contain
+// for a dotted ContextFilters key to equal value: "client.task" and "x"
+// yield {"client":{"task":"x"}}.
+func contextContainment(key, value string) string {
+	var obj any = value
+	path := model.ContextFilterPath(key)
+	for i := len(path) - 1; i >= 0; i-- {
+		obj = map[string]any{path[i]: obj}
+	}
+	b, _ := json.Marshal(obj) // nested string maps always marshal
+	return string(b)
+}
+
+// existsCondition renders "EXISTS (subquery)", or "NOT EXISTS" when want is false.
+func existsCondition(want bool, subquery string) string {
+	if want {
+		return "EXISTS (" + subquery + ")"
+	}
+	return "NOT EXISTS (" + subquery + ")"
+}
+
 // ExportDecisionsCursor returns a page of decisions using keyset pagination on
 // (valid_from, id). This avoids the O(offset) scan cost of OFFSET-based pagination,
 // making it suitable for streaming large exports. Pass a nil cursor for the first page.
@@ -940,7 +1372,7 @@ func (db *DB) ExportDecisionsCursor(ctx context.Context, orgID uuid.UUID, filter
 		decisionCols, where, limit,
 	)
 
-	rows, err := db.pool.Query(ctx, query, args...)
+	rows, err := db.readPool().Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("storage: export decisions cursor: %w", err)
 	}
@@ -975,6 +1407,53 @@ func (db *DB) ExportDecisionsCursor(ctx context.Context, orgID uuid.UUID, filter
 	return decisions, nil
 }
 
+// FindDecisionsForRescore returns a page of current, fully embedded decisions
+// in an org matching filters, ordered by (valid_from, id) for keyset pagination.
+// Unlike FindEmbeddedDecisionIDs it ignores conflict_scored_at, so already-scored
+// decisions are included. Pass a nil cursor for the first page; the returned
+// cursor is nil once the final page has been read.
+func (db *DB) FindDecisionsForRescore(ctx context.Context, orgID uuid.UUID, filters model.QueryFilters, cursor *ExportCursor, limit int) ([]DecisionRef, *ExportCursor, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+	where += " AND embedding IS NOT NULL AND outcome_embedding IS NOT NULL"
+
+	if cursor != nil {
+		idx := len(args) + 1
+		where += fmt.Sprintf(" AND (valid_from, id) > ($%d, $%d)", idx, idx+1)
+		args = append(args, cursor.ValidFrom, cursor.ID)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, org_id, valid_from FROM decisions%s ORDER BY valid_from ASC, id ASC LIMIT %d`,
+		where, limit,
+	)
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("storage: find decisions for rescore: %w", err)
+	}
+	defer rows.Close()
+
+	var refs []DecisionRef
+	var next ExportCursor
+	for rows.Next() {
+		var r DecisionRef
+		if err := rows.Scan(&r.ID, &r.OrgID, &next.ValidFrom); err != nil {
+			return nil, nil, fmt.Errorf("storage: scan rescore decision ref: %w", err)
+		}
+		next.ID = r.ID
+		refs = append(refs, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("storage: find decisions for rescore: %w", err)
+	}
+	if len(refs) < limit {
+		return refs, nil, nil
+	}
+	return refs, &next, nil
+}
+
 // ExportCursor holds the keyset cursor position for cursor-based export pagination.
 type ExportCursor struct {
 	ValidFrom time.Time
@@ -1226,6 +1705,7 @@ func scanDecisionsWithTotal(rows pgx.Rows) ([]model.Decision, int, error) {
 			&d.ValidFrom, &d.ValidTo, &d.TransactionTime, &d.CreatedAt,
 			&d.SessionID, &d.AgentContext, &d.APIKeyID,
 			&d.Tool, &d.Model, &d.Project,
+			&d.ConfidenceLow, &d.ConfidenceHigh, &d.Namespace, &d.OutcomeFlipped, &d.EmbeddingModel, &d.BatchID, &d.ContextSnapshot, &d.ContextSnapshotTruncated, &d.HashVersion,
 			&total,
 		); err != nil {
 			return nil, 0, fmt.Errorf("storage: scan decision with total: %w", err)
@@ -1268,78 +1748,135 @@ func (db *DB) GetDecisionsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid
 	return result, nil
 }
 
-// GetDecisionRevisions returns the full revision chain for a decision, walking
-// both backwards (via supersedes_id) and forwards (via decisions that reference
-// this one's id as their supersedes_id). Results are ordered by valid_from ASC.
-//
-// The CTE is split into two separate recursive queries (forward_chain and
-// backward_chain) then UNIONed, because PostgreSQL only treats the last
-// branch of a UNION as the recursive term. A single CTE with three branches
-// would only recurse on the last (forward) branch, truncating deep backward chains.
-// Each recursive branch has a LIMIT 100 safety cap to prevent infinite loops from
-// circular supersedes_id references.
-func (db *DB) GetDecisionRevisions(ctx context.Context, orgID, id uuid.UUID) ([]model.Decision, error) {
-	query := `
+// FindDecisionsCitingPrecedent returns active decisions within an org and
+// namespace whose precedent_ref points at precedentID, newest first. This is
+// the reverse of the precedent link: the decisions a precedent went on to
+// inform. An empty namespace means model.DefaultNamespace.
+func (db *DB) FindDecisionsCitingPrecedent(ctx context.Context, orgID uuid.UUID, namespace string, precedentID uuid.UUID, limit int) ([]model.Decision, error) {
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+decisionCols+`
+		 FROM decisions
+		 WHERE precedent_ref = $1 AND org_id = $2 AND namespace = $3 AND valid_to IS NULL
+		 ORDER BY valid_from DESC
+		 LIMIT $4`,
+		precedentID, orgID, namespace, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: find decisions citing precedent: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDecisions(rows)
+}
+
+// decisionRevisionsSQL walks the revision chain of decision $1 in org $2.
+// Shared by GetDecisionRevisions and GetDecisionRevisionsBatch.
+const decisionRevisionsSQL = `
 	WITH RECURSIVE
 	forward_chain AS (
 		-- Anchor: the target decision.
 		SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		       metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, 0 AS depth
+		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version, 0 AS depth
 		FROM decisions
-		WHERE id = $1 AND org_id = $2
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 
 		UNION ALL
 
 		-- Walk forward: find decisions that supersede the current one.
 		SELECT d.id, d.run_id, d.agent_id, d.org_id, d.decision_type, d.outcome, d.confidence, d.reasoning,
 		       d.metadata, d.completeness_score, d.outcome_score, d.precedent_ref, d.precedent_reason, d.supersedes_id, d.content_hash,
-		       d.valid_from, d.valid_to, d.transaction_time, d.created_at, d.session_id, d.agent_context, d.api_key_id, d.tool, d.model, d.project, fc.depth + 1
+		       d.valid_from, d.valid_to, d.transaction_time, d.created_at, d.session_id, d.agent_context, d.api_key_id, d.tool, d.model, d.project, d.confidence_low, d.confidence_high, d.namespace, d.outcome_flipped, d.embedding_model, d.batch_id, d.context_snapshot, d.context_snapshot_truncated, d.hash_version, fc.depth + 1
 		FROM decisions d
 		INNER JOIN forward_chain fc ON d.supersedes_id = fc.id
-		WHERE d.org_id = $2 AND fc.depth < 100
+		WHERE d.org_id = $2 AND d.deleted_at IS NULL AND fc.depth < 100
 	),
 	backward_chain AS (
 		-- Anchor: the target decision.
 		SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		       metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, 0 AS depth
+		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version, 0 AS depth
 		FROM decisions
-		WHERE id = $1 AND org_id = $2
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 
 		UNION ALL
 
 		-- Walk backward: follow supersedes_id links.
 		SELECT d.id, d.run_id, d.agent_id, d.org_id, d.decision_type, d.outcome, d.confidence, d.reasoning,
 		       d.metadata, d.completeness_score, d.outcome_score, d.precedent_ref, d.precedent_reason, d.supersedes_id, d.content_hash,
-		       d.valid_from, d.valid_to, d.transaction_time, d.created_at, d.session_id, d.agent_context, d.api_key_id, d.tool, d.model, d.project, bc.depth + 1
+		       d.valid_from, d.valid_to, d.transaction_time, d.created_at, d.session_id, d.agent_context, d.api_key_id, d.tool, d.model, d.project, d.confidence_low, d.confidence_high, d.namespace, d.outcome_flipped, d.embedding_model, d.batch_id, d.context_snapshot, d.context_snapshot_truncated, d.hash_version, bc.depth + 1
 		FROM decisions d
 		INNER JOIN backward_chain bc ON bc.supersedes_id = d.id
-		WHERE d.org_id = $2 AND bc.depth < 100
+		WHERE d.org_id = $2 AND d.deleted_at IS NULL AND bc.depth < 100
 	),
 	all_revisions AS (
 		SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		       metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project
+		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version
 		FROM forward_chain
 		UNION
 		SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 		       metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project
+		       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version
 		FROM backward_chain
 	)
 	SELECT DISTINCT ON (id) id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
 	       metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-	       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project
+	       valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id, tool, model, project, confidence_low, confidence_high, namespace, outcome_flipped, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version
 	FROM all_revisions
 	ORDER BY id, valid_from ASC`
 
-	rows, err := db.pool.Query(ctx, query, id, orgID)
+// GetDecisionRevisions returns the full revision chain for a decision, walking
+// both backwards (via supersedes_id) and forwards (via decisions that reference
+// this one's id as their supersedes_id). Results are ordered by valid_from ASC.
+//
+// The CTE is split into two separate recursive queries (forward_chain and
+// backward_chain) then UNIONed, because PostgreSQL only treats the last
+// branch of a UNION as the recursive term. A single CTE with three branches
+// would only recurse on the last (forward) branch, truncating deep backward chains.
+// Each recursive branch has a LIMIT 100 safety cap to prevent infinite loops from
+// circular supersedes_id references.
+func (db *DB) GetDecisionRevisions(ctx context.Context, orgID, id uuid.UUID) ([]model.Decision, error) {
+	rows, err := db.pool.Query(ctx, decisionRevisionsSQL, id, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("storage: get decision revisions: %w", err)
 	}
 	defer rows.Close()
+	return scanDecisionRevisions(rows)
+}
+
+// GetDecisionRevisionsBatch returns the revision chain for each of the given
+// decisions, keyed by the input ID. It runs the same query as
+// GetDecisionRevisions once per ID, pipelined in a single pgx batch so the
+// whole set costs one network round trip.
+func (db *DB) GetDecisionRevisionsBatch(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID][]model.Decision, error) {
+	result := make(map[uuid.UUID][]model.Decision, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
 
+	batch := &pgx.Batch{}
+	for _, id := range ids {
+		batch.Queue(decisionRevisionsSQL, id, orgID).Query(func(rows pgx.Rows) error {
+			revisions, err := scanDecisionRevisions(rows)
+			if err != nil {
+				return err
+			}
+			result[id] = revisions
+			return nil
+		})
+	}
+	if err := db.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("storage: get decision revisions batch: %w", err)
+	}
+	return result, nil
+}
+
+// scanDecisionRevisions scans decisionRevisionsSQL rows into chronological order.
+func scanDecisionRevisions(rows pgx.Rows) ([]model.Decision, error) {
 	decisions, err := scanDecisions(rows)
 	if err != nil {
 		return nil, err
@@ -1425,6 +1962,34 @@ func (db *DB) GetRevisionDepth(ctx context.Context, id, orgID uuid.UUID) (int, e
 	return depth, nil
 }
 
+// ListRevisedDecisionHeads returns the IDs of an agent's current decisions
+// that revise an earlier one and were recorded at or after since, newest
+// first. These are the chain heads worth scanning for flip-flops.
+func (db *DB) ListRevisedDecisionHeads(ctx context.Context, orgID uuid.UUID, agentID string, since time.Time, limit int) ([]uuid.UUID, error) {
+	rows, err := db.pool.Query(ctx, `
+		SELECT id FROM decisions
+		WHERE org_id = $1 AND agent_id = $2 AND valid_from >= $3
+		  AND valid_to IS NULL AND supersedes_id IS NOT NULL
+		ORDER BY valid_from DESC
+		LIMIT $4`,
+		orgID, agentID, since, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list revised decision heads: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan revised decision head: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // sortDecisionsByValidFrom sorts a slice of decisions by valid_from ascending.
 func sortDecisionsByValidFrom(decisions []model.Decision) {
 	sort.Slice(decisions, func(i, j int) bool {
@@ -1471,13 +2036,16 @@ func (db *DB) FindUnembeddedDecisions(ctx context.Context, limit int) ([]Unembed
 	return results, rows.Err()
 }
 
-// BackfillEmbedding updates a decision's embedding and queues a search outbox
-// entry so the outbox worker syncs it to Qdrant. Both writes are atomic.
-func (db *DB) BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector) error {
+// BackfillEmbedding updates a decision's embedding, tags it with the model
+// that produced it, and queues a search outbox entry so the outbox worker
+// syncs it to Qdrant. Both writes are atomic. An empty embeddingModel leaves
+// the vector untagged.
+func (db *DB) BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector, embeddingModel string) error {
 	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
 		tag, err := tx.Exec(ctx,
-			`UPDATE decisions SET embedding = $1 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL`,
-			emb, id, orgID)
+			`UPDATE decisions SET embedding = $1, embedding_model = NULLIF($4, '')
+			 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL`,
+			emb, id, orgID, embeddingModel)
 		if err != nil {
 			return fmt.Errorf("storage: update embedding: %w", err)
 		}
@@ -1538,6 +2106,63 @@ func (db *DB) BackfillOutcomeEmbedding(ctx context.Context, id, orgID uuid.UUID,
 	return nil
 }
 
+// FindUnscoredDecisions returns active decisions with a zero or NULL
+// completeness_score and an ID greater than after, in ID order, so callers
+// can page past decisions whose recomputed score is still zero.
+// SECURITY: Intentionally global — background backfill across all orgs. Each
+// returned row includes OrgID for downstream scoping (BackfillCompletenessScore).
+func (db *DB) FindUnscoredDecisions(ctx context.Context, after uuid.UUID, limit int) ([]UnscoredDecision, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT d.id, d.org_id, d.outcome, d.confidence, d.reasoning, d.precedent_ref IS NOT NULL,
+		        COALESCE((SELECT array_agg(a.rejection_reason) FROM alternatives a
+		                  WHERE a.decision_id = d.id AND a.rejection_reason IS NOT NULL), '{}'),
+		        (SELECT COUNT(*) FROM evidence e WHERE e.decision_id = d.id)
+		 FROM decisions d
+		 WHERE d.valid_to IS NULL AND COALESCE(d.completeness_score, 0) = 0 AND d.id > $1
+		 ORDER BY d.id
+		 LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("storage: find unscored decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var results []UnscoredDecision
+	for rows.Next() {
+		var d UnscoredDecision
+		if err := rows.Scan(&d.ID, &d.OrgID, &d.Outcome, &d.Confidence, &d.Reasoning,
+			&d.HasPrecedentRef, &d.RejectionReasons, &d.EvidenceCount); err != nil {
+			return nil, fmt.Errorf("storage: scan unscored decision: %w", err)
+		}
+		results = append(results, d)
+	}
+	return results, rows.Err()
+}
+
+// BackfillCompletenessScore sets an active decision's completeness_score and
+// queues an outbox upsert so the search index payload picks it up.
+func (db *DB) BackfillCompletenessScore(ctx context.Context, id, orgID uuid.UUID, score float32) error {
+	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE decisions SET completeness_score = $1
+			 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL`,
+			score, id, orgID)
+		if err != nil {
+			return fmt.Errorf("storage: update completeness score: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil // Decision was revised or deleted — skip silently.
+		}
+
+		if err := queueSearchOutbox(ctx, tx, id, orgID, "upsert"); err != nil {
+			return fmt.Errorf("storage: queue completeness backfill outbox: %w", err)
+		}
+		return nil
+	})
+}
+
 // PgCandidateFinder implements search.CandidateFinder using a Postgres sequential scan.
 // This is the "no-Qdrant" fallback: acceptable for small deployments (<100k decisions)
 // where latency from a sequential scan is tolerable. At scale, use QdrantIndex instead.
@@ -1558,6 +2183,10 @@ func NewPgCandidateFinder(db *DB) *PgCandidateFinder {
 // project matches only decisions with that exact project value; a nil project matches only
 // decisions with no project set. This mirrors the Qdrant path and prevents cross-project
 // conflict contamination.
+//
+// Candidates are restricted to vectors from the same embedding model as the
+// excluded (source) decision, so a partially completed re-embedding job never
+// compares vectors across models. Untagged rows on either side still match.
 func (f *PgCandidateFinder) FindSimilar(ctx context.Context, orgID uuid.UUID, embedding []float32, excludeID uuid.UUID, projects []string, limit int) ([]search.Result, error) {
 	if limit <= 0 {
 		limit = 50
@@ -1571,6 +2200,7 @@ func (f *PgCandidateFinder) FindSimilar(ctx context.Context, orgID uuid.UUID, em
 		q = `SELECT id, 1 - (embedding <=> $3) AS score
 		     FROM decisions
 		     WHERE org_id = $1 AND id != $2 AND embedding IS NOT NULL AND outcome_embedding IS NOT NULL AND valid_to IS NULL
+		       AND ` + sameEmbeddingModelCond + `
 		       AND project IS NULL
 		     ORDER BY embedding <=> $3
 		     LIMIT $4`
@@ -1579,6 +2209,7 @@ func (f *PgCandidateFinder) FindSimilar(ctx context.Context, orgID uuid.UUID, em
 		q = `SELECT id, 1 - (embedding <=> $3) AS score
 		     FROM decisions
 		     WHERE org_id = $1 AND id != $2 AND embedding IS NOT NULL AND outcome_embedding IS NOT NULL AND valid_to IS NULL
+		       AND ` + sameEmbeddingModelCond + `
 		       AND project = $5
 		     ORDER BY embedding <=> $3
 		     LIMIT $4`
@@ -1587,6 +2218,7 @@ func (f *PgCandidateFinder) FindSimilar(ctx context.Context, orgID uuid.UUID, em
 		q = `SELECT id, 1 - (embedding <=> $3) AS score
 		     FROM decisions
 		     WHERE org_id = $1 AND id != $2 AND embedding IS NOT NULL AND outcome_embedding IS NOT NULL AND valid_to IS NULL
+		       AND ` + sameEmbeddingModelCond + `
 		       AND project = ANY($5)
 		     ORDER BY embedding <=> $3
 		     LIMIT $4`
@@ -1611,6 +2243,12 @@ func (f *PgCandidateFinder) FindSimilar(ctx context.Context, orgID uuid.UUID, em
 	return results, rows.Err()
 }
 
+// sameEmbeddingModelCond limits a candidate scan to rows whose embedding_model
+// is compatible with that of the source decision $2 (model.EmbeddingModelsCompatible).
+const sameEmbeddingModelCond = `(embedding_model IS NULL OR embedding_model = COALESCE(
+		       (SELECT src.embedding_model FROM decisions src WHERE src.id = $2 AND src.org_id = $1 AND src.valid_to IS NULL),
+		       embedding_model))`
+
 // GetDecisionEmbeddings returns (embedding, outcome_embedding) pairs for a batch of decisions.
 // Used by consensus scoring to prepare Qdrant queries and pairwise cosine comparisons.
 // Only decisions with both embeddings populated are included in the result.
@@ -1693,6 +2331,54 @@ func (db *DB) GetConflictCountsBatch(ctx context.Context, ids []uuid.UUID, orgID
 	return result, rows.Err()
 }
 
+// decisionFlagsSQL derives model.DecisionFlags for a set of decisions ($1)
+// scoped to an org ($2), with $3 as the low-confidence threshold. Shared by
+// GetDecisionFlagsBatch and the pipelined include path in QueryDecisions.
+const decisionFlagsSQL = `
+		WITH batch AS (SELECT unnest($1::uuid[]) AS id)
+		SELECT d.id,
+		       EXISTS (SELECT 1 FROM scored_conflicts sc
+		               WHERE sc.org_id = $2 AND sc.status = 'open'
+		                 AND (sc.decision_a_id = d.id OR sc.decision_b_id = d.id)) AS contested,
+		       (d.supersedes_id IS NOT NULL
+		        OR EXISTS (SELECT 1 FROM decisions s
+		                   WHERE s.org_id = $2 AND s.supersedes_id = d.id)) AS revised,
+		       d.confidence < $3 AS low_confidence,
+		       NOT EXISTS (SELECT 1 FROM evidence e
+		                   WHERE e.org_id = $2 AND e.decision_id = d.id
+		                     AND e.source_type <> 'user_input') AS weakly_evidenced
+		FROM batch b
+		JOIN decisions d ON d.id = b.id AND d.org_id = $2`
+
+// GetDecisionFlagsBatch returns derived triage flags for a batch of decisions.
+// Decisions not found in the org are absent from the returned map.
+func (db *DB) GetDecisionFlagsBatch(ctx context.Context, ids []uuid.UUID, orgID uuid.UUID) (map[uuid.UUID]model.DecisionFlags, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]model.DecisionFlags{}, nil
+	}
+
+	rows, err := db.pool.Query(ctx, decisionFlagsSQL, ids, orgID, model.LowConfidenceThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("storage: decision flags batch: %w", err)
+	}
+	defer rows.Close()
+	return scanDecisionFlags(rows)
+}
+
+// scanDecisionFlags collects decisionFlagsSQL rows keyed by decision ID.
+func scanDecisionFlags(rows pgx.Rows) (map[uuid.UUID]model.DecisionFlags, error) {
+	result := make(map[uuid.UUID]model.DecisionFlags)
+	for rows.Next() {
+		var id uuid.UUID
+		var f model.DecisionFlags
+		if err := rows.Scan(&id, &f.Contested, &f.Revised, &f.LowConfidence, &f.WeaklyEvidenced); err != nil {
+			return nil, fmt.Errorf("storage: scan decision flags: %w", err)
+		}
+		result[id] = f
+	}
+	return result, rows.Err()
+}
+
 // FindEmbeddedDecisionIDs returns IDs of current decisions that have both
 // embedding and outcome_embedding populated but have NOT yet been scored for
 // conflicts (conflict_scored_at IS NULL). Used by conflict scoring backfill
@@ -1816,7 +2502,7 @@ func (db *DB) GetDecisionQualityStats(ctx context.Context, orgID uuid.UUID, from
 		args = append(args, *to)
 		q += fmt.Sprintf(" AND valid_from < $%d", len(args))
 	}
-	err := db.pool.QueryRow(ctx, q, args...).Scan(
+	err := db.readPool().QueryRow(ctx, q, args...).Scan(
 		&s.Total, &s.AvgCompleteness, &s.BelowHalf, &s.BelowThird, &s.WithReasoning, &s.WithAlternatives)
 	if err != nil {
 		return s, fmt.Errorf("storage: decision quality stats: %w", err)
@@ -1842,7 +2528,7 @@ func (db *DB) GetDecisionTypeDistribution(ctx context.Context, orgID uuid.UUID,
 	}
 	q += ` GROUP BY decision_type ORDER BY count(*) DESC`
 
-	rows, err := db.pool.Query(ctx, q, args...)
+	rows, err := db.readPool().Query(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("storage: decision type distribution: %w", err)
 	}
@@ -1862,6 +2548,133 @@ func (db *DB) GetDecisionTypeDistribution(ctx context.Context, orgID uuid.UUID,
 	return result, nil
 }
 
+// statsGroupKeys maps a StatsGroupBy value to the SQL expression it groups by
+// and the order its buckets are returned in. Days are newest first so a
+// limit keeps the most recent ones.
+var statsGroupKeys = map[string]struct{ expr, order string }{
+	StatsGroupByDecisionType: {"decision_type", "count(*) DESC, 1"},
+	StatsGroupByAgent:        {"agent_id", "count(*) DESC, 1"},
+	StatsGroupByDay:          {"to_char(date_trunc('day', valid_from AT TIME ZONE 'UTC'), 'YYYY-MM-DD')", "1 DESC"},
+}
+
+// AggregateDecisionStats returns the count, average confidence, and average
+// completeness of current decisions grouped by decision_type, agent_id, or
+// UTC day of valid_from. When since is non-nil, only decisions with
+// valid_from >= since are included.
+func (db *DB) AggregateDecisionStats(ctx context.Context, orgID uuid.UUID, groupBy string, since *time.Time, limit int) ([]DecisionStatsBucket, error) {
+	key, ok := statsGroupKeys[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("storage: aggregate decision stats: unknown group_by %q", groupBy)
+	}
+	q := `SELECT ` + key.expr + `, count(*), COALESCE(avg(confidence), 0), COALESCE(avg(completeness_score), 0)
+		 FROM decisions
+		 WHERE org_id = $1 AND valid_to IS NULL`
+	args := []any{orgID}
+	if since != nil {
+		args = append(args, *since)
+		q += fmt.Sprintf(" AND valid_from >= $%d", len(args))
+	}
+	args = append(args, limit)
+	q += fmt.Sprintf(" GROUP BY 1 ORDER BY %s LIMIT $%d", key.order, len(args))
+
+	rows, err := db.readPool().Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: aggregate decision stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []DecisionStatsBucket
+	for rows.Next() {
+		var b DecisionStatsBucket
+		if err := rows.Scan(&b.Key, &b.Count, &b.AvgConfidence, &b.AvgCompleteness); err != nil {
+			return nil, fmt.Errorf("storage: scan decision stats bucket: %w", err)
+		}
+		result = append(result, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: iterate decision stats: %w", err)
+	}
+	return result, nil
+}
+
+// GetConfidenceHistogram counts an agent's current decisions by confidence
+// into buckets equal-width buckets spanning [0, 1]. Every bucket is returned,
+// empty ones with a zero count; confidence 1.0 is counted in the last bucket.
+func (db *DB) GetConfidenceHistogram(ctx context.Context, orgID uuid.UUID, agentID string, buckets int) ([]model.ConfidenceBucket, error) {
+	if buckets < 1 {
+		return nil, fmt.Errorf("storage: confidence histogram: buckets must be positive, got %d", buckets)
+	}
+	// width_bucket puts 1.0 in overflow bucket buckets+1; LEAST folds it
+	// into the last real bucket.
+	rows, err := db.pool.Query(ctx,
+		`SELECT LEAST(width_bucket(confidence::float8, 0, 1, $3), $3) AS bucket, count(*)
+		 FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND valid_to IS NULL
+		 GROUP BY bucket`,
+		orgID, agentID, buckets)
+	if err != nil {
+		return nil, fmt.Errorf("storage: confidence histogram: %w", err)
+	}
+	defer rows.Close()
+
+	width := 1.0 / float64(buckets)
+	result := make([]model.ConfidenceBucket, buckets)
+	for i := range result {
+		result[i] = model.ConfidenceBucket{Low: float64(i) * width, High: float64(i+1) * width}
+	}
+	result[buckets-1].High = 1
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("storage: scan confidence bucket: %w", err)
+		}
+		// Buckets are 1-based; 0 would mean a negative confidence, which
+		// validation rejects.
+		if bucket >= 1 && bucket <= buckets {
+			result[bucket-1].Count = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: iterate confidence histogram: %w", err)
+	}
+	return result, nil
+}
+
+// ListDecisionTypes returns every decision_type with current decisions in an
+// org, with its count and most recent valid_from, ordered by type. A non-nil
+// agentIDs restricts the scan to decisions by those agents; an empty non-nil
+// slice yields no types.
+func (db *DB) ListDecisionTypes(ctx context.Context, orgID uuid.UUID, agentIDs []string) ([]DecisionTypeSummary, error) {
+	q := `SELECT decision_type, count(*), max(valid_from)
+		 FROM decisions
+		 WHERE org_id = $1 AND valid_to IS NULL`
+	args := []any{orgID}
+	if agentIDs != nil {
+		args = append(args, agentIDs)
+		q += ` AND agent_id = ANY($2)`
+	}
+	q += ` GROUP BY decision_type ORDER BY decision_type`
+
+	rows, err := db.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list decision types: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]DecisionTypeSummary, 0)
+	for rows.Next() {
+		var dt DecisionTypeSummary
+		if err := rows.Scan(&dt.DecisionType, &dt.Count, &dt.LastSeen); err != nil {
+			return nil, fmt.Errorf("storage: scan decision type: %w", err)
+		}
+		result = append(result, dt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: iterate decision types: %w", err)
+	}
+	return result, nil
+}
+
 // GetCompletenessByDecisionType returns per-type average completeness for current
 // decisions in an org. Results are ordered by average completeness ascending so
 // the weakest decision types surface first. When from/to are non-nil, only
@@ -1908,12 +2721,13 @@ func (db *DB) GetDecisionForScoring(ctx context.Context, id, orgID uuid.UUID) (m
 	var d model.Decision
 	err := db.pool.QueryRow(ctx,
 		`SELECT id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
-		 valid_from, embedding, outcome_embedding, session_id, agent_context, project, transaction_time
+		 valid_from, embedding, outcome_embedding, session_id, agent_context, project, transaction_time, namespace
 		 FROM decisions WHERE id = $1 AND org_id = $2 AND valid_to IS NULL`,
 		id, orgID,
 	).Scan(
 		&d.ID, &d.RunID, &d.AgentID, &d.OrgID, &d.DecisionType, &d.Outcome, &d.Confidence, &d.Reasoning,
 		&d.ValidFrom, &d.Embedding, &d.OutcomeEmbedding, &d.SessionID, &d.AgentContext, &d.Project, &d.TransactionTime,
+		&d.Namespace,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -2301,6 +3115,94 @@ func (db *DB) GetDecisionLineageBatch(ctx context.Context, ids []uuid.UUID, orgI
 	return result, nil
 }
 
+// maxAncestryDepth bounds the precedent chain walk in GetDecisionAncestry.
+const maxAncestryDepth = 50
+
+// GetDecisionAncestry walks the precedent_ref chain upward from a decision and
+// annotates each ancestor with whether it has since been superseded, expired
+// without replacement, or drawn into an open conflict. The walk stops at
+// maxAncestryDepth hops and guards against citation cycles.
+func (db *DB) GetDecisionAncestry(ctx context.Context, id, orgID uuid.UUID) (DecisionAncestryHealth, error) {
+	result := DecisionAncestryHealth{DecisionID: id, Ancestors: []AncestryEntry{}}
+
+	var exists bool
+	if err := db.pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM decisions WHERE id = $1 AND org_id = $2)`,
+		id, orgID).Scan(&exists); err != nil {
+		return result, fmt.Errorf("storage: ancestry lookup: %w", err)
+	}
+	if !exists {
+		return result, fmt.Errorf("storage: decision %s: %w", id, ErrNotFound)
+	}
+
+	// The recursive term fetches one hop past the limit so truncation is
+	// detectable without a second query.
+	rows, err := db.pool.Query(ctx,
+		`WITH RECURSIVE chain AS (
+		   SELECT d.precedent_ref AS id, 1 AS depth, ARRAY[d.id] AS path
+		   FROM decisions d
+		   WHERE d.id = $1 AND d.org_id = $2 AND d.precedent_ref IS NOT NULL
+		   UNION ALL
+		   SELECT p.precedent_ref, c.depth + 1, c.path || p.id
+		   FROM chain c
+		   JOIN decisions p ON p.id = c.id AND p.org_id = $2
+		   WHERE p.precedent_ref IS NOT NULL
+		     AND c.depth <= $3
+		     AND NOT (p.precedent_ref = ANY(c.path || p.id))
+		 )
+		 SELECT c.depth,
+		        d.id, d.run_id, d.agent_id, d.decision_type, d.outcome, d.confidence,
+		        d.project, d.created_at, d.valid_from, d.valid_to,
+		        s.id, oc.n
+		 FROM chain c
+		 JOIN decisions d ON d.id = c.id AND d.org_id = $2
+		 LEFT JOIN LATERAL (
+		   SELECT s.id FROM decisions s
+		   WHERE s.supersedes_id = d.id AND s.org_id = $2
+		   ORDER BY s.valid_from
+		   LIMIT 1
+		 ) s ON true
+		 CROSS JOIN LATERAL (
+		   SELECT count(*)::int AS n FROM scored_conflicts sc
+		   WHERE sc.org_id = $2 AND sc.status = 'open'
+		     AND (sc.decision_a_id = d.id OR sc.decision_b_id = d.id)
+		 ) oc
+		 ORDER BY c.depth`,
+		id, orgID, maxAncestryDepth)
+	if err != nil {
+		return result, fmt.Errorf("storage: ancestry query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a AncestryEntry
+		var successor *uuid.UUID
+		if err := rows.Scan(
+			&a.Depth,
+			&a.ID, &a.RunID, &a.AgentID, &a.DecisionType, &a.Outcome, &a.Confidence,
+			&a.Project, &a.CreatedAt, &a.ValidFrom, &a.ValidTo,
+			&successor, &a.OpenConflicts,
+		); err != nil {
+			return result, fmt.Errorf("storage: scan ancestry entry: %w", err)
+		}
+		if a.Depth > maxAncestryDepth {
+			result.Truncated = true
+			continue
+		}
+		a.Superseded = successor != nil
+		a.SupersededBy = successor
+		a.Expired = a.ValidTo != nil && successor == nil
+		a.Contested = a.OpenConflicts > 0
+		result.Ancestors = append(result.Ancestors, a)
+	}
+	if err := rows.Err(); err != nil {
+		return result, fmt.Errorf("storage: ancestry rows: %w", err)
+	}
+
+	result.Summarize()
+	return result, nil
+}
+
 // UpdateDecisionProject updates the project field on a decision by setting the
 // client.project key in agent_context JSONB. The project column is GENERATED
 // ALWAYS from agent_context, so this is the only correct way to change it.
@@ -2374,3 +3276,53 @@ func (db *DB) UpdateDecisionProject(ctx context.Context, orgID, decisionID uuid.
 	}
 	return prev, nil
 }
+
+// UpdateDecisionMetadata merges patch into a decision's metadata in place
+// (metadata || patch) without creating a revision: keys in patch overwrite,
+// all other keys are kept. outcome and content_hash are untouched, so the
+// decision's integrity proof still verifies. Returns the merged metadata.
+// Returns ErrNotFound if the decision does not exist in the org and
+// ErrDecisionSuperseded if it is no longer the current revision.
+func (db *DB) UpdateDecisionMetadata(ctx context.Context, orgID, decisionID uuid.UUID, patch map[string]any, audit MutationAuditEntry) (map[string]any, error) {
+	var merged map[string]any
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var before map[string]any
+		var validTo *time.Time
+		err := tx.QueryRow(ctx,
+			`SELECT metadata, valid_to FROM decisions WHERE id = $1 AND org_id = $2 FOR UPDATE`,
+			decisionID, orgID,
+		).Scan(&before, &validTo)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("storage: decision %s: %w", decisionID, ErrNotFound)
+			}
+			return fmt.Errorf("storage: fetch decision for metadata update: %w", err)
+		}
+		if validTo != nil {
+			return fmt.Errorf("storage: decision %s: %w", decisionID, ErrDecisionSuperseded)
+		}
+
+		if err := tx.QueryRow(ctx,
+			`UPDATE decisions SET metadata = metadata || $1::jsonb
+			 WHERE id = $2 AND org_id = $3
+			 RETURNING metadata`,
+			patch, decisionID, orgID,
+		).Scan(&merged); err != nil {
+			return fmt.Errorf("storage: update decision metadata: %w", err)
+		}
+
+		audit.Operation = "decision_metadata_updated"
+		audit.ResourceType = "decision"
+		audit.ResourceID = decisionID.String()
+		audit.BeforeData = map[string]any{"metadata": before}
+		audit.AfterData = map[string]any{"metadata": merged}
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit in metadata update tx: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
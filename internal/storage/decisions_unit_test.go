@@ -3,9 +3,11 @@
 package storage
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -24,9 +26,10 @@ func TestBuildDecisionWhereClause_CurrentOnly(t *testing.T) {
 		assert.Equal(t, orgID, args[0])
 	})
 
-	t.Run("currentOnly=false omits valid_to IS NULL", func(t *testing.T) {
+	t.Run("currentOnly=false omits valid_to IS NULL but excludes tombstones", func(t *testing.T) {
 		where, args := buildDecisionWhereClause(orgID, model.QueryFilters{}, 1, false)
 		assert.NotContains(t, where, "valid_to IS NULL")
+		assert.Contains(t, where, "deleted_at IS NULL")
 		require.Len(t, args, 1)
 		assert.Equal(t, orgID, args[0])
 	})
@@ -46,6 +49,24 @@ func TestBuildDecisionWhereClause_ToolFilter(t *testing.T) {
 	assert.Equal(t, "claude-code", args[1])
 }
 
+func TestBuildDecisionWhereClause_DecisionTypes(t *testing.T) {
+	orgID := uuid.New()
+	legacy := "security"
+	filters := model.QueryFilters{
+		DecisionTypes: []string{"architecture", "security"},
+		DecisionType:  &legacy,
+	}
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+
+	// The deprecated single type folds into the list instead of adding a
+	// second, ANDed condition.
+	assert.Contains(t, where, "decision_type = ANY($2)")
+	assert.Equal(t, 1, strings.Count(where, "decision_type"))
+	require.Len(t, args, 2)
+	assert.Equal(t, []string{"architecture", "security"}, args[1])
+}
+
 func TestBuildDecisionWhereClause_ModelFilter(t *testing.T) {
 	orgID := uuid.New()
 	model_ := "claude-opus-4-6"
@@ -74,6 +95,31 @@ func TestBuildDecisionWhereClause_RepoFilter(t *testing.T) {
 	assert.Equal(t, "ashita-ai/akashi", args[1])
 }
 
+func TestBuildDecisionWhereClause_MinConfidenceWidthFilter(t *testing.T) {
+	orgID := uuid.New()
+	width := float32(0.3)
+	filters := model.QueryFilters{MinConfidenceWidth: &width}
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+
+	// NULL bounds make the difference NULL, so decisions without an interval never match.
+	assert.Contains(t, where, "confidence_high - confidence_low >= $2")
+	require.Len(t, args, 2)
+	assert.Equal(t, float32(0.3), args[1])
+}
+
+func TestBuildDecisionWhereClause_OutcomeFlippedFilter(t *testing.T) {
+	orgID := uuid.New()
+	flipped := true
+	filters := model.QueryFilters{OutcomeFlipped: &flipped}
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+
+	assert.Contains(t, where, "outcome_flipped = $2")
+	require.Len(t, args, 2)
+	assert.Equal(t, true, args[1])
+}
+
 func TestBuildDecisionWhereClause_AllFilters(t *testing.T) {
 	orgID := uuid.New()
 	runID := uuid.New()
@@ -108,7 +154,7 @@ func TestBuildDecisionWhereClause_AllFilters(t *testing.T) {
 	assert.Contains(t, where, "valid_to IS NULL")
 	assert.Contains(t, where, "agent_id = ANY($2)")
 	assert.Contains(t, where, "run_id = $3")
-	assert.Contains(t, where, "decision_type = $4")
+	assert.Contains(t, where, "decision_type = ANY($4)")
 	assert.Contains(t, where, "confidence >= $5")
 	assert.Contains(t, where, "outcome = $6")
 	assert.Contains(t, where, "session_id = $7")
@@ -238,9 +284,9 @@ func TestBuildDecisionWhereClause_DecisionTypeFilter(t *testing.T) {
 
 	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
 
-	assert.Contains(t, where, "decision_type = $2")
+	assert.Contains(t, where, "decision_type = ANY($2)")
 	require.Len(t, args, 2)
-	assert.Equal(t, "library-choice", args[1])
+	assert.Equal(t, []string{"library-choice"}, args[1])
 }
 
 func TestBuildDecisionWhereClause_AgentIDsFilter(t *testing.T) {
@@ -253,6 +299,30 @@ func TestBuildDecisionWhereClause_AgentIDsFilter(t *testing.T) {
 	require.Len(t, args, 2)
 }
 
+func TestBuildDecisionWhereClause_EvidenceAndAlternativesFilters(t *testing.T) {
+	orgID := uuid.New()
+	yes, no := true, false
+	filters := model.QueryFilters{HasEvidence: &no, HasAlternatives: &yes}
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+
+	assert.Contains(t, where, "NOT EXISTS (SELECT 1 FROM evidence ev WHERE ev.decision_id = decisions.id)")
+	assert.Contains(t, where, " EXISTS (SELECT 1 FROM alternatives alt WHERE alt.decision_id = decisions.id)")
+	assert.NotContains(t, where, "NOT EXISTS (SELECT 1 FROM alternatives")
+	require.Len(t, args, 1, "tri-state filters add no bind parameters")
+}
+
+func TestBuildDecisionWhereClause_TagsFilter(t *testing.T) {
+	orgID := uuid.New()
+	filters := model.QueryFilters{Tags: []string{"postmortem", "q3-launch"}}
+
+	where, args := buildDecisionWhereClause(orgID, filters, 1, true)
+
+	assert.Contains(t, where, "EXISTS (SELECT 1 FROM decision_tags dt WHERE dt.decision_id = decisions.id AND dt.tag = ANY($2))")
+	require.Len(t, args, 2)
+	assert.Equal(t, []string{"postmortem", "q3-launch"}, args[1])
+}
+
 func TestBuildDecisionWhereClause_EmptyFilters(t *testing.T) {
 	orgID := uuid.New()
 	filters := model.QueryFilters{}
@@ -271,3 +341,56 @@ func TestContainsStr(t *testing.T) {
 	assert.False(t, containsStr(nil, "a"))
 	assert.False(t, containsStr([]string{}, "a"))
 }
+
+func TestQueryDecisionsSQL_ConflictOrdering(t *testing.T) {
+	orgID := uuid.New()
+
+	t.Run("plain columns do not join conflicts", func(t *testing.T) {
+		q, _ := queryDecisionsSQL(orgID, model.QueryRequest{OrderBy: "confidence", OrderDir: "asc"})
+		assert.NotContains(t, q, "scored_conflicts")
+		assert.Contains(t, q, "ORDER BY confidence ASC")
+	})
+
+	t.Run("conflict_count joins open conflict counts", func(t *testing.T) {
+		q, args := queryDecisionsSQL(orgID, model.QueryRequest{OrderBy: "conflict_count"})
+		assert.Contains(t, q, "LEFT JOIN")
+		assert.Contains(t, q, "status = 'open'")
+		assert.Contains(t, q, "ORDER BY COALESCE(oc.open_conflicts, 0) DESC, valid_from DESC")
+		assert.Equal(t, orgID, args[0], "the join reuses the org ID at $1")
+	})
+
+	t.Run("triage blends conflicts and recency", func(t *testing.T) {
+		q, _ := queryDecisionsSQL(orgID, model.QueryRequest{OrderBy: "triage"})
+		assert.Contains(t, q, "LEFT JOIN")
+		assert.Contains(t, q, triageScoreSQL+" DESC, valid_from DESC")
+	})
+
+	t.Run("unknown order_by falls back to valid_from", func(t *testing.T) {
+		q, _ := queryDecisionsSQL(orgID, model.QueryRequest{OrderBy: "id; DROP TABLE decisions"})
+		assert.Contains(t, q, "ORDER BY valid_from DESC")
+	})
+}
+
+func TestSubstringHighlight(t *testing.T) {
+	terms := []*regexp.Regexp{
+		regexp.MustCompile("(?i)" + regexp.QuoteMeta("redis")),
+		regexp.MustCompile("(?i)" + regexp.QuoteMeta("cache")),
+	}
+
+	t.Run("wraps the earliest match case-insensitively", func(t *testing.T) {
+		got := substringHighlight("Use a Cache backed by Redis", terms)
+		assert.Equal(t, "Use a <b>Cache</b> backed by Redis", got)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		assert.Empty(t, substringHighlight("use postgres", terms))
+	})
+
+	t.Run("windows long text on rune boundaries", func(t *testing.T) {
+		text := strings.Repeat("é", 100) + " redis " + strings.Repeat("ü", 100)
+		got := substringHighlight(text, terms)
+		assert.Contains(t, got, "<b>redis</b>")
+		assert.Less(t, len(got), len(text))
+		assert.True(t, utf8.ValidString(got))
+	})
+}
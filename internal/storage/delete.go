@@ -28,6 +28,10 @@ type DeleteAgentResult struct {
 	Grants              int64 `json:"grants"`
 	APIKeys             int64 `json:"api_keys"`
 	Agents              int64 `json:"agents"`
+	// Other agents' decisions whose reference to a deleted decision was
+	// cleared. Each cleared reference is archived in deletion_audit_log.
+	ExternalPrecedentRefsCleared  int64 `json:"external_precedent_refs_cleared"`
+	ExternalSupersedesRefsCleared int64 `json:"external_supersedes_refs_cleared"`
 }
 
 // DeleteAgentData removes all data associated with an agent within an org in a single
@@ -51,10 +55,14 @@ func (db *DB) DeleteAgentData(ctx context.Context, orgID uuid.UUID, agentID stri
 		}
 
 		// 1. Delete evidence (via decision_id for this agent's decisions within the org).
+		// Blob content is copied into the archive; the blob itself is left for
+		// the orphan sweep since other evidence may share it.
 		_, err = tx.Exec(ctx,
 			`INSERT INTO deletion_audit_log (org_id, agent_id, table_name, record_id, record_data)
-		 SELECT $1, $2, 'evidence', e.id::text, to_jsonb(e)
+		 SELECT $1, $2, 'evidence', e.id::text,
+		        to_jsonb(e) || jsonb_build_object('content', COALESCE(b.content, e.content))
 		 FROM evidence e
+		 LEFT JOIN evidence_blobs b ON b.org_id = e.org_id AND b.content_hash = e.blob_hash
 		 WHERE e.decision_id IN (
 		     SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2
 		 )`,
@@ -104,16 +112,50 @@ func (db *DB) DeleteAgentData(ctx context.Context, orgID uuid.UUID, agentID stri
 			return fmt.Errorf("storage: clear precedent refs: %w", err)
 		}
 
-		// Also clear precedent_ref from OTHER agents that reference this agent's decisions.
+		// Also clear precedent_ref from OTHER agents that reference this agent's
+		// decisions, archiving each cleared reference so the referencing
+		// decision's lost link stays traceable.
 		_, err = tx.Exec(ctx,
+			`INSERT INTO deletion_audit_log (org_id, agent_id, table_name, record_id, record_data)
+		 SELECT $1, $2, 'decision_references', d.id::text,
+		        jsonb_build_object('decision_id', d.id, 'agent_id', d.agent_id,
+		                           'field', 'precedent_ref', 'referenced_decision_id', d.precedent_ref)
+		 FROM decisions d
+		 WHERE d.org_id = $1 AND d.agent_id <> $2
+		   AND d.precedent_ref IN (SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2)`,
+			orgID, agentID,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: archive external precedent refs: %w", err)
+		}
+
+		tag, err = tx.Exec(ctx,
 			`UPDATE decisions SET precedent_ref = NULL
 		 WHERE org_id = $1 AND precedent_ref IN (SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2)`,
 			orgID, agentID)
 		if err != nil {
 			return fmt.Errorf("storage: clear external precedent refs: %w", err)
 		}
+		result.ExternalPrecedentRefsCleared = tag.RowsAffected()
+
+		// Also clear supersedes_id from decisions that reference this agent's
+		// decisions. The agent's own revisions are deleted below; only other
+		// agents' references are archived and counted.
+		tag, err = tx.Exec(ctx,
+			`INSERT INTO deletion_audit_log (org_id, agent_id, table_name, record_id, record_data)
+		 SELECT $1, $2, 'decision_references', d.id::text,
+		        jsonb_build_object('decision_id', d.id, 'agent_id', d.agent_id,
+		                           'field', 'supersedes_id', 'referenced_decision_id', d.supersedes_id)
+		 FROM decisions d
+		 WHERE d.org_id = $1 AND d.agent_id <> $2
+		   AND d.supersedes_id IN (SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2)`,
+			orgID, agentID,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: archive external supersedes refs: %w", err)
+		}
+		result.ExternalSupersedesRefsCleared = tag.RowsAffected()
 
-		// Also clear supersedes_id from OTHER agents that reference this agent's decisions.
 		_, err = tx.Exec(ctx,
 			`UPDATE decisions SET supersedes_id = NULL
 		 WHERE org_id = $1 AND supersedes_id IN (SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2)`,
@@ -405,3 +447,66 @@ func (db *DB) DeleteAgentData(ctx context.Context, orgID uuid.UUID, agentID stri
 	}
 	return result, nil
 }
+
+// AgentDeleteImpact reports what DeleteAgentData would remove or detach for
+// an agent, without changing anything.
+type AgentDeleteImpact struct {
+	AgentID   string `json:"agent_id"`
+	Decisions int64  `json:"decisions"`
+	// Other agents' decisions citing one of the agent's decisions as
+	// precedent_ref or supersedes_id. Deletion clears these references.
+	ExternalPrecedentRefs  int64 `json:"external_precedent_refs"`
+	ExternalSupersedesRefs int64 `json:"external_supersedes_refs"`
+	// Distinct external decisions with either reference, and their agents.
+	ReferencingDecisions int64    `json:"referencing_decisions"`
+	ReferencingAgents    []string `json:"referencing_agents"`
+	// Scored conflicts pairing the agent's decisions with other agents'
+	// decisions. Deletion removes them, along with their resolutions.
+	ExternalConflicts int64 `json:"external_conflicts"`
+}
+
+// GetAgentDeleteImpact counts the agent's decisions and the references other
+// agents' decisions hold to them. Returns ErrAgentNotFound if the agent does
+// not exist in the org.
+func (db *DB) GetAgentDeleteImpact(ctx context.Context, orgID uuid.UUID, agentID string) (AgentDeleteImpact, error) {
+	impact := AgentDeleteImpact{AgentID: agentID, ReferencingAgents: []string{}}
+
+	var exists bool
+	if err := db.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM agents WHERE org_id = $1 AND agent_id = $2)`,
+		orgID, agentID,
+	).Scan(&exists); err != nil {
+		return AgentDeleteImpact{}, fmt.Errorf("storage: lookup agent: %w", err)
+	}
+	if !exists {
+		return AgentDeleteImpact{}, fmt.Errorf("%w: %s", ErrAgentNotFound, agentID)
+	}
+
+	err := db.pool.QueryRow(ctx,
+		`WITH own AS (
+		     SELECT id FROM decisions WHERE org_id = $1 AND agent_id = $2
+		 ), refs AS (
+		     SELECT d.id, d.agent_id,
+		            d.precedent_ref IN (SELECT id FROM own) AS cites_precedent,
+		            d.supersedes_id IN (SELECT id FROM own) AS cites_supersedes
+		     FROM decisions d
+		     WHERE d.org_id = $1 AND d.agent_id <> $2
+		       AND (d.precedent_ref IN (SELECT id FROM own) OR d.supersedes_id IN (SELECT id FROM own))
+		 )
+		 SELECT
+		     (SELECT count(*) FROM own),
+		     (SELECT count(*) FROM refs WHERE cites_precedent),
+		     (SELECT count(*) FROM refs WHERE cites_supersedes),
+		     (SELECT count(*) FROM refs),
+		     COALESCE((SELECT array_agg(DISTINCT agent_id ORDER BY agent_id) FROM refs), '{}'),
+		     (SELECT count(*) FROM scored_conflicts sc
+		      WHERE sc.org_id = $1
+		        AND (sc.decision_a_id IN (SELECT id FROM own)) <> (sc.decision_b_id IN (SELECT id FROM own)))`,
+		orgID, agentID,
+	).Scan(&impact.Decisions, &impact.ExternalPrecedentRefs, &impact.ExternalSupersedesRefs,
+		&impact.ReferencingDecisions, &impact.ReferencingAgents, &impact.ExternalConflicts)
+	if err != nil {
+		return AgentDeleteImpact{}, fmt.Errorf("storage: get agent delete impact: %w", err)
+	}
+	return impact, nil
+}
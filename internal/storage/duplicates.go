@@ -0,0 +1,215 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// duplicateRecentLimit is how many of an agent's most recent decisions
+// FindRecentDuplicate compares a new trace against.
+const duplicateRecentLimit = 50
+
+// DuplicatePair is two current decisions the duplicate scan found to be
+// near-identical. DecisionA < DecisionB.
+type DuplicatePair struct {
+	DecisionA         uuid.UUID
+	DecisionB         uuid.UUID
+	Similarity        float64 // Cosine similarity of the decision embeddings.
+	OutcomeSimilarity float64 // 1 for identical outcome text, else outcome embedding similarity.
+}
+
+// DuplicateScan records the most recent duplicate scan of an org.
+type DuplicateScan struct {
+	Floor            float64
+	DecisionsScanned int
+	PairsFound       int
+	ScannedAt        time.Time
+}
+
+// RefreshDuplicatePairs replaces the org's near-duplicate pairs. For each of
+// the org's maxDecisions most recent current decisions with an embedding, the
+// nearest neighbours nearest current decisions in the same namespace are
+// compared; pairs whose similarity and outcome similarity both reach floor
+// are stored. Returns the scan summary.
+func (db *DB) RefreshDuplicatePairs(ctx context.Context, orgID uuid.UUID, floor float64, neighbors, maxDecisions int) (DuplicateScan, error) {
+	scan := DuplicateScan{Floor: floor}
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM decision_duplicate_pairs WHERE org_id = $1`, orgID,
+		); err != nil {
+			return fmt.Errorf("storage: clear duplicate pairs: %w", err)
+		}
+
+		if err := tx.QueryRow(ctx,
+			`SELECT count(*) FROM (
+			   SELECT 1 FROM decisions
+			   WHERE org_id = $1 AND valid_to IS NULL AND embedding IS NOT NULL
+			   LIMIT $2
+			 ) s`,
+			orgID, maxDecisions,
+		).Scan(&scan.DecisionsScanned); err != nil {
+			return fmt.Errorf("storage: count duplicate scan candidates: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx,
+			`WITH candidates AS (
+			   SELECT id, namespace, outcome, embedding, outcome_embedding
+			   FROM decisions
+			   WHERE org_id = $1 AND valid_to IS NULL AND embedding IS NOT NULL
+			   ORDER BY valid_from DESC
+			   LIMIT $4
+			 )
+			 INSERT INTO decision_duplicate_pairs (org_id, decision_a, decision_b, similarity, outcome_similarity)
+			 SELECT $1, LEAST(c.id, n.id), GREATEST(c.id, n.id), n.similarity, n.outcome_similarity
+			 FROM candidates c
+			 CROSS JOIN LATERAL (
+			   SELECT o.id,
+			          1 - (o.embedding <=> c.embedding) AS similarity,
+			          CASE
+			            WHEN lower(btrim(o.outcome)) = lower(btrim(c.outcome)) THEN 1.0
+			            WHEN o.outcome_embedding IS NOT NULL AND c.outcome_embedding IS NOT NULL
+			              THEN 1 - (o.outcome_embedding <=> c.outcome_embedding)
+			            ELSE 0
+			          END AS outcome_similarity
+			   FROM decisions o
+			   WHERE o.org_id = $1 AND o.valid_to IS NULL AND o.embedding IS NOT NULL
+			     AND o.namespace = c.namespace AND o.id <> c.id
+			   ORDER BY o.embedding <=> c.embedding
+			   LIMIT $3
+			 ) n
+			 WHERE n.similarity >= $2 AND n.outcome_similarity >= $2
+			 ON CONFLICT (decision_a, decision_b) DO NOTHING`,
+			orgID, floor, neighbors, maxDecisions,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: insert duplicate pairs: %w", err)
+		}
+		scan.PairsFound = int(tag.RowsAffected())
+
+		if err := tx.QueryRow(ctx,
+			`INSERT INTO decision_duplicate_scans (org_id, floor, decisions_scanned, pairs_found, scanned_at)
+			 VALUES ($1, $2, $3, $4, now())
+			 ON CONFLICT (org_id) DO UPDATE SET
+			   floor = EXCLUDED.floor,
+			   decisions_scanned = EXCLUDED.decisions_scanned,
+			   pairs_found = EXCLUDED.pairs_found,
+			   scanned_at = EXCLUDED.scanned_at
+			 RETURNING scanned_at`,
+			orgID, floor, scan.DecisionsScanned, scan.PairsFound,
+		).Scan(&scan.ScannedAt); err != nil {
+			return fmt.Errorf("storage: record duplicate scan: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return DuplicateScan{}, err
+	}
+	return scan, nil
+}
+
+// FindRecentDuplicate returns the current decision among agentID's most
+// recent ones of d's namespace and decision type that is most similar to d,
+// provided both its decision and outcome similarity reach threshold, using
+// the same measures as the duplicate scan. d must carry an embedding. Returns
+// nil when there is no such decision.
+func (db *DB) FindRecentDuplicate(ctx context.Context, orgID uuid.UUID, agentID string, d model.Decision, threshold float64) (*DuplicateMatch, error) {
+	if d.Embedding == nil {
+		return nil, nil
+	}
+	namespace := d.Namespace
+	if namespace == "" {
+		namespace = model.DefaultNamespace
+	}
+	var m DuplicateMatch
+	err := db.pool.QueryRow(ctx,
+		`SELECT id, run_id, similarity FROM (
+		   SELECT r.id, r.run_id,
+		          1 - (r.embedding <=> $5) AS similarity,
+		          CASE
+		            WHEN lower(btrim(r.outcome)) = lower(btrim($6)) THEN 1.0
+		            WHEN r.outcome_embedding IS NOT NULL AND $7::vector IS NOT NULL
+		              THEN 1 - (r.outcome_embedding <=> $7::vector)
+		            ELSE 0
+		          END AS outcome_similarity
+		   FROM (
+		     SELECT id, run_id, outcome, embedding, outcome_embedding
+		     FROM decisions
+		     WHERE org_id = $1 AND agent_id = $2 AND namespace = $3 AND decision_type = $4
+		       AND valid_to IS NULL AND embedding IS NOT NULL
+		       AND (embedding_model IS NULL OR $8::text IS NULL OR embedding_model = $8)
+		     ORDER BY valid_from DESC
+		     LIMIT $10
+		   ) r
+		 ) s
+		 WHERE similarity >= $9 AND outcome_similarity >= $9
+		 ORDER BY similarity DESC
+		 LIMIT 1`,
+		orgID, agentID, namespace, d.DecisionType, d.Embedding, d.Outcome, d.OutcomeEmbedding,
+		d.EmbeddingModel, threshold, duplicateRecentLimit,
+	).Scan(&m.DecisionID, &m.RunID, &m.Similarity)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: find recent duplicate: %w", err)
+	}
+	return &m, nil
+}
+
+// GetDuplicateScan returns the org's most recent duplicate scan.
+// Returns ErrNotFound if the org has never been scanned.
+func (db *DB) GetDuplicateScan(ctx context.Context, orgID uuid.UUID) (DuplicateScan, error) {
+	var s DuplicateScan
+	err := db.pool.QueryRow(ctx,
+		`SELECT floor, decisions_scanned, pairs_found, scanned_at
+		 FROM decision_duplicate_scans WHERE org_id = $1`,
+		orgID,
+	).Scan(&s.Floor, &s.DecisionsScanned, &s.PairsFound, &s.ScannedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DuplicateScan{}, fmt.Errorf("storage: duplicate scan for org %s: %w", orgID, ErrNotFound)
+		}
+		return DuplicateScan{}, fmt.Errorf("storage: get duplicate scan: %w", err)
+	}
+	return s, nil
+}
+
+// ListDuplicatePairs returns the org's stored pairs whose similarity and
+// outcome similarity both reach threshold, restricted to pairs where both
+// decisions are still current.
+func (db *DB) ListDuplicatePairs(ctx context.Context, orgID uuid.UUID, threshold float64) ([]DuplicatePair, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT p.decision_a, p.decision_b, p.similarity, p.outcome_similarity
+		 FROM decision_duplicate_pairs p
+		 JOIN decisions a ON a.id = p.decision_a AND a.org_id = p.org_id AND a.valid_to IS NULL
+		 JOIN decisions b ON b.id = p.decision_b AND b.org_id = p.org_id AND b.valid_to IS NULL
+		 WHERE p.org_id = $1 AND p.similarity >= $2 AND p.outcome_similarity >= $2
+		 ORDER BY p.similarity DESC`,
+		orgID, threshold,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list duplicate pairs: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []DuplicatePair
+	for rows.Next() {
+		var p DuplicatePair
+		var sim, outcomeSim float32
+		if err := rows.Scan(&p.DecisionA, &p.DecisionB, &sim, &outcomeSim); err != nil {
+			return nil, fmt.Errorf("storage: scan duplicate pair: %w", err)
+		}
+		p.Similarity, p.OutcomeSimilarity = float64(sim), float64(outcomeSim)
+		pairs = append(pairs, p)
+	}
+	return pairs, rows.Err()
+}
@@ -1,6 +1,9 @@
 package storage
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrNotFound is returned when a requested entity does not exist.
 var ErrNotFound = errors.New("storage: not found")
@@ -16,7 +19,35 @@ var ErrWinningAgentNotInGroup = errors.New("storage: winning agent is not a part
 // does not match either decision_a_id or decision_b_id of the conflict.
 var ErrWinningDecisionNotInConflict = errors.New("storage: winning decision is not a participant in this conflict")
 
+// ErrConflictStatusUnchanged is returned when a conflict is moved to the
+// status it already has. Re-applying a status would overwrite who resolved
+// the conflict and when.
+var ErrConflictStatusUnchanged = errors.New("storage: conflict already has this status")
+
 // ErrRevisedDecisions is returned when a resolution requiring a decisions JOIN
 // finds no current (valid_to IS NULL) decisions, typically because the referenced
 // decisions have been superseded by revisions.
 var ErrRevisedDecisions = errors.New("storage: referenced decisions have been revised")
+
+// ErrDecisionSuperseded is returned when an in-place update targets a
+// decision that has been superseded or retracted. Only the current revision
+// may be corrected; older revisions are part of the audit chain.
+var ErrDecisionSuperseded = errors.New("storage: decision has been superseded")
+
+// ErrRefreshTokenInvalid is returned when a refresh token cannot be used:
+// it is unknown, expired, already rotated or revoked, or the API key it was
+// issued from has been revoked.
+var ErrRefreshTokenInvalid = errors.New("storage: refresh token is invalid")
+
+// TraceBatchError identifies which trace in a batch caused the whole batch to
+// fail. Index is the zero-based position in the caller's input slice.
+type TraceBatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *TraceBatchError) Error() string {
+	return fmt.Sprintf("trace %d: %v", e.Index, e.Err)
+}
+
+func (e *TraceBatchError) Unwrap() error { return e.Err }
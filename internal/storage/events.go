@@ -236,3 +236,49 @@ func scanEvents(rows pgx.Rows) ([]model.AgentEvent, error) {
 	}
 	return events, rows.Err()
 }
+
+// eventRetentionBatch bounds how many events one DeleteEventsOlderThan
+// statement removes, so no single delete holds its locks for long.
+const eventRetentionBatch = 5000
+
+// DeleteEventsOlderThan deletes agent_events whose occurred_at is before
+// cutoff, in chunks of eventRetentionBatch rows, each committed on its own.
+// Events of the excluded orgs are kept; they follow an org-specific
+// retention applied with DeleteOrgEventsOlderThan. Only raw events are
+// removed; decisions and their runs are untouched. Returns the number of
+// events deleted, including chunks committed before an error.
+func (db *DB) DeleteEventsOlderThan(ctx context.Context, cutoff time.Time, excludeOrgs ...uuid.UUID) (int64, error) {
+	if excludeOrgs == nil {
+		excludeOrgs = []uuid.UUID{}
+	}
+	return db.deleteEventsBatched(ctx, `occurred_at < $1 AND org_id <> ALL($3)`, cutoff, excludeOrgs)
+}
+
+// DeleteOrgEventsOlderThan is DeleteEventsOlderThan restricted to one org.
+func (db *DB) DeleteOrgEventsOlderThan(ctx context.Context, orgID uuid.UUID, cutoff time.Time) (int64, error) {
+	return db.deleteEventsBatched(ctx, `occurred_at < $1 AND org_id = $3`, cutoff, orgID)
+}
+
+// deleteEventsBatched deletes agent_events matching where in chunks of
+// eventRetentionBatch. where uses $1 for the cutoff and $3 for scope; $2 is
+// the batch size.
+func (db *DB) deleteEventsBatched(ctx context.Context, where string, cutoff time.Time, scope any) (int64, error) {
+	var total int64
+	for {
+		tag, err := db.pool.Exec(ctx,
+			`DELETE FROM agent_events
+			  WHERE (id, occurred_at) IN (
+			        SELECT id, occurred_at FROM agent_events
+			         WHERE `+where+`
+			         LIMIT $2)`,
+			cutoff, eventRetentionBatch, scope,
+		)
+		if err != nil {
+			return total, fmt.Errorf("storage: delete events older than cutoff: %w", err)
+		}
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < eventRetentionBatch {
+			return total, nil
+		}
+	}
+}
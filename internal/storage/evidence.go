@@ -13,7 +13,9 @@ import (
 	"github.com/ashita-ai/akashi/internal/model"
 )
 
-// CreateEvidence inserts a single piece of evidence for a decision.
+// CreateEvidence inserts a single piece of evidence for a decision. Content
+// over the inline limit is stored in evidence_blobs; the returned evidence
+// still carries it.
 func (db *DB) CreateEvidence(ctx context.Context, ev model.Evidence) (model.Evidence, error) {
 	if ev.ID == uuid.Nil {
 		ev.ID = uuid.New()
@@ -25,12 +27,16 @@ func (db *DB) CreateEvidence(ctx context.Context, ev model.Evidence) (model.Evid
 		ev.Metadata = map[string]any{}
 	}
 
-	_, err := db.pool.Exec(ctx,
+	content, blobHash, err := db.evidenceContentForWrite(ctx, db.pool, ev.OrgID, ev.Content)
+	if err != nil {
+		return model.Evidence{}, err
+	}
+	_, err = db.pool.Exec(ctx,
 		`INSERT INTO evidence (id, decision_id, org_id, source_type, source_uri, content,
-		 relevance_score, embedding, metadata, metrics, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
-		ev.ID, ev.DecisionID, ev.OrgID, string(ev.SourceType), ev.SourceURI, ev.Content,
-		ev.RelevanceScore, ev.Embedding, ev.Metadata, ev.Metrics, ev.CreatedAt,
+		 relevance_score, embedding, metadata, metrics, created_at, blob_hash)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		ev.ID, ev.DecisionID, ev.OrgID, string(ev.SourceType), ev.SourceURI, content,
+		ev.RelevanceScore, ev.Embedding, ev.Metadata, ev.Metrics, ev.CreatedAt, blobHash,
 	)
 	if err != nil {
 		return model.Evidence{}, fmt.Errorf("storage: create evidence: %w", err)
@@ -45,7 +51,7 @@ func (db *DB) CreateEvidenceBatch(ctx context.Context, evs []model.Evidence) err
 	}
 
 	columns := []string{"id", "decision_id", "org_id", "source_type", "source_uri", "content",
-		"relevance_score", "embedding", "metadata", "metrics", "created_at"}
+		"relevance_score", "embedding", "metadata", "metrics", "created_at", "blob_hash"}
 
 	rows := make([][]any, len(evs))
 	for i, ev := range evs {
@@ -61,8 +67,12 @@ func (db *DB) CreateEvidenceBatch(ctx context.Context, evs []model.Evidence) err
 		if meta == nil {
 			meta = map[string]any{}
 		}
-		rows[i] = []any{id, ev.DecisionID, ev.OrgID, string(ev.SourceType), ev.SourceURI, ev.Content,
-			ev.RelevanceScore, ev.Embedding, meta, ev.Metrics, createdAt}
+		content, blobHash, err := db.evidenceContentForWrite(ctx, db.pool, ev.OrgID, ev.Content)
+		if err != nil {
+			return err
+		}
+		rows[i] = []any{id, ev.DecisionID, ev.OrgID, string(ev.SourceType), ev.SourceURI, content,
+			ev.RelevanceScore, ev.Embedding, meta, ev.Metrics, createdAt, blobHash}
 	}
 
 	_, err := db.pool.CopyFrom(ctx, pgx.Identifier{"evidence"}, columns, pgx.CopyFromRows(rows))
@@ -72,6 +82,16 @@ func (db *DB) CreateEvidenceBatch(ctx context.Context, evs []model.Evidence) err
 	return nil
 }
 
+// evidenceByDecisionsSQL loads evidence for a set of decisions ($1) scoped to
+// an org ($2), with blob content rehydrated. Shared by GetEvidenceByDecisions
+// and the pipelined include path in QueryDecisions.
+const evidenceByDecisionsSQL = `SELECT e.id, e.decision_id, e.org_id, e.source_type, e.source_uri,
+		 COALESCE(b.content, e.content), e.relevance_score, e.metrics, e.metadata, e.created_at
+		 FROM evidence e
+		 LEFT JOIN evidence_blobs b ON b.org_id = e.org_id AND b.content_hash = e.blob_hash
+		 WHERE e.decision_id = ANY($1) AND e.org_id = $2
+		 ORDER BY e.relevance_score DESC NULLS LAST`
+
 // GetEvidenceByDecisions retrieves all evidence for a set of decision IDs in a single query.
 // Results are returned as a map from decision ID to its evidence.
 // orgID provides defense-in-depth tenant isolation; even though callers gate access
@@ -81,17 +101,16 @@ func (db *DB) GetEvidenceByDecisions(ctx context.Context, decisionIDs []uuid.UUI
 		return nil, nil
 	}
 
-	rows, err := db.pool.Query(ctx,
-		`SELECT id, decision_id, org_id, source_type, source_uri, content,
-		 relevance_score, metrics, metadata, created_at
-		 FROM evidence WHERE decision_id = ANY($1) AND org_id = $2
-		 ORDER BY relevance_score DESC NULLS LAST`, decisionIDs, orgID,
-	)
+	rows, err := db.pool.Query(ctx, evidenceByDecisionsSQL, decisionIDs, orgID)
 	if err != nil {
 		return nil, fmt.Errorf("storage: get evidence batch: %w", err)
 	}
 	defer rows.Close()
+	return scanEvidenceByDecision(rows)
+}
 
+// scanEvidenceByDecision groups evidenceByDecisionsSQL rows by decision ID.
+func scanEvidenceByDecision(rows pgx.Rows) (map[uuid.UUID][]model.Evidence, error) {
 	result := make(map[uuid.UUID][]model.Evidence)
 	for rows.Next() {
 		var ev model.Evidence
@@ -107,13 +126,19 @@ func (db *DB) GetEvidenceByDecisions(ctx context.Context, decisionIDs []uuid.UUI
 }
 
 // GetEvidenceByDecision retrieves all evidence for a decision.
-// orgID provides defense-in-depth tenant isolation.
-func (db *DB) GetEvidenceByDecision(ctx context.Context, decisionID uuid.UUID, orgID uuid.UUID) ([]model.Evidence, error) {
+// orgID provides defense-in-depth tenant isolation. With includeBlobs, content
+// stored in evidence_blobs is rehydrated into Content; without it, such
+// evidence comes back with empty Content and a Blob reference, so callers
+// that only list evidence do not pull large content.
+func (db *DB) GetEvidenceByDecision(ctx context.Context, decisionID uuid.UUID, orgID uuid.UUID, includeBlobs bool) ([]model.Evidence, error) {
 	rows, err := db.pool.Query(ctx,
-		`SELECT id, decision_id, org_id, source_type, source_uri, content,
-		 relevance_score, metrics, metadata, created_at
-		 FROM evidence WHERE decision_id = $1 AND org_id = $2
-		 ORDER BY relevance_score DESC NULLS LAST`, decisionID, orgID,
+		`SELECT e.id, e.decision_id, e.org_id, e.source_type, e.source_uri,
+		 CASE WHEN $3 THEN COALESCE(b.content, e.content) ELSE e.content END,
+		 e.relevance_score, e.metrics, e.metadata, e.created_at, e.blob_hash, b.size_bytes
+		 FROM evidence e
+		 LEFT JOIN evidence_blobs b ON b.org_id = e.org_id AND b.content_hash = e.blob_hash
+		 WHERE e.decision_id = $1 AND e.org_id = $2
+		 ORDER BY e.relevance_score DESC NULLS LAST`, decisionID, orgID, includeBlobs,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("storage: get evidence: %w", err)
@@ -122,13 +147,23 @@ func (db *DB) GetEvidenceByDecision(ctx context.Context, decisionID uuid.UUID, o
 
 	evs := make([]model.Evidence, 0)
 	for rows.Next() {
-		var ev model.Evidence
+		var (
+			ev       model.Evidence
+			blobHash *string
+			blobSize *int
+		)
 		if err := rows.Scan(
 			&ev.ID, &ev.DecisionID, &ev.OrgID, &ev.SourceType, &ev.SourceURI, &ev.Content,
-			&ev.RelevanceScore, &ev.Metrics, &ev.Metadata, &ev.CreatedAt,
+			&ev.RelevanceScore, &ev.Metrics, &ev.Metadata, &ev.CreatedAt, &blobHash, &blobSize,
 		); err != nil {
 			return nil, fmt.Errorf("storage: scan evidence: %w", err)
 		}
+		if blobHash != nil && !includeBlobs {
+			ev.Blob = &model.EvidenceBlobRef{Hash: *blobHash}
+			if blobSize != nil {
+				ev.Blob.SizeBytes = *blobSize
+			}
+		}
 		evs = append(evs, ev)
 	}
 	return evs, rows.Err()
@@ -154,7 +189,7 @@ func (db *DB) GetEvidenceCoverageStats(ctx context.Context, orgID uuid.UUID, fro
 		args = append(args, *to)
 		q += fmt.Sprintf(" AND d.valid_from < $%d", len(args))
 	}
-	err := db.pool.QueryRow(ctx, q, args...).Scan(
+	err := db.readPool().QueryRow(ctx, q, args...).Scan(
 		&s.TotalDecisions, &s.WithEvidence, &s.TotalRecords)
 	if err != nil {
 		return s, fmt.Errorf("storage: evidence coverage stats: %w", err)
@@ -0,0 +1,69 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultEvidenceInlineMaxBytes is the evidence content size above which
+// content moves out of the evidence row into evidence_blobs.
+const DefaultEvidenceInlineMaxBytes = 64 << 10
+
+// SetEvidenceInlineMaxBytes overrides DefaultEvidenceInlineMaxBytes. Zero
+// keeps all evidence content inline. Call it before the DB is shared.
+func (db *DB) SetEvidenceInlineMaxBytes(n int) {
+	db.evidenceInlineMax = n
+}
+
+// evidenceBlobHash returns the evidence_blobs key for content: its hex SHA-256.
+func evidenceBlobHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// evidenceContentForWrite returns the content and blob_hash to store in an
+// evidence row. Content over the inline limit is upserted into evidence_blobs,
+// where identical content within an org is stored once, and the row keeps an
+// empty string. The upsert refreshes last_referenced_at so the orphan sweep
+// never deletes a blob a write is about to reference.
+func (db *DB) evidenceContentForWrite(ctx context.Context, exec pgxExecer, orgID uuid.UUID, content string) (string, *string, error) {
+	if db.evidenceInlineMax <= 0 || len(content) <= db.evidenceInlineMax {
+		return content, nil, nil
+	}
+	hash := evidenceBlobHash(content)
+	_, err := exec.Exec(ctx,
+		`INSERT INTO evidence_blobs (org_id, content_hash, content, size_bytes)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (org_id, content_hash) DO UPDATE SET last_referenced_at = now()`,
+		orgID, hash, content, len(content),
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("storage: store evidence blob: %w", err)
+	}
+	return "", &hash, nil
+}
+
+// DeleteOrphanEvidenceBlobs removes evidence blobs that no evidence row
+// references and that were last referenced before olderThan. Evidence deletes
+// (agent deletion, retention) leave their blobs behind because other evidence
+// may share them; this sweep reclaims the ones nothing shares.
+func (db *DB) DeleteOrphanEvidenceBlobs(ctx context.Context, olderThan time.Time) (int64, error) {
+	tag, err := db.pool.Exec(ctx,
+		`DELETE FROM evidence_blobs b
+		 WHERE b.last_referenced_at < $1
+		   AND NOT EXISTS (
+		       SELECT 1 FROM evidence e
+		       WHERE e.org_id = b.org_id AND e.blob_hash = b.content_hash
+		   )`, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("storage: delete orphan evidence blobs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -245,3 +245,104 @@ func (db *DB) ListGrantsByGrantee(ctx context.Context, orgID uuid.UUID, granteeI
 
 	return scanGrants(rows)
 }
+
+// ListGrantsByGrantor returns every grant a grantor has issued within an org,
+// including expired ones, ordered by granted_at descending. Used to review
+// what an agent has shared, e.g. after a bulk import.
+func (db *DB) ListGrantsByGrantor(ctx context.Context, orgID uuid.UUID, grantorID uuid.UUID) ([]model.AccessGrant, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+grantCols+`
+		 FROM access_grants
+		 WHERE org_id = $1 AND grantor_id = $2
+		 ORDER BY granted_at DESC`, orgID, grantorID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list grants by grantor: %w", err)
+	}
+	defer rows.Close()
+
+	return scanGrants(rows)
+}
+
+// CreateGrantsBatchWithAudit inserts grants in a single transaction, writing
+// one mutation audit entry per grant created. A grant matching an unexpired
+// one on (grantee, resource_type, resource_id, permission) is not inserted;
+// the existing grant is returned in its place. resource_id is compared with
+// IS NOT DISTINCT FROM, so org-wide grants (NULL resource_id) deduplicate too.
+// An expired match is replaced, and its audit entry records it as the
+// before-state.
+//
+// The returned slices are parallel to grants: the stored grant and whether
+// this call created it.
+func (db *DB) CreateGrantsBatchWithAudit(ctx context.Context, grants []model.AccessGrant, audit MutationAuditEntry) ([]model.AccessGrant, []bool, error) {
+	stored := make([]model.AccessGrant, len(grants))
+	created := make([]bool, len(grants))
+	now := time.Now().UTC()
+
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for i, grant := range grants {
+			existing, err := scanOneGrant(tx.QueryRow(ctx,
+				`SELECT `+grantCols+` FROM access_grants
+				 WHERE org_id = $1 AND grantee_id = $2 AND resource_type = $3
+				   AND resource_id IS NOT DISTINCT FROM $4 AND permission = $5
+				   AND (expires_at IS NULL OR expires_at > now())
+				 LIMIT 1`,
+				grant.OrgID, grant.GranteeID, grant.ResourceType, grant.ResourceID, grant.Permission,
+			))
+			if err == nil {
+				stored[i] = existing
+				continue
+			}
+			if !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("storage: find existing grant %d: %w", i, err)
+			}
+
+			// The unique key ignores expiry, so an expired match must go
+			// before the new grant can be inserted.
+			var before any
+			expired, err := scanOneGrant(tx.QueryRow(ctx,
+				`DELETE FROM access_grants
+				 WHERE org_id = $1 AND grantee_id = $2 AND resource_type = $3
+				   AND resource_id IS NOT DISTINCT FROM $4 AND permission = $5
+				 RETURNING `+grantCols,
+				grant.OrgID, grant.GranteeID, grant.ResourceType, grant.ResourceID, grant.Permission,
+			))
+			switch {
+			case err == nil:
+				before = expired
+			case !errors.Is(err, pgx.ErrNoRows):
+				return fmt.Errorf("storage: replace expired grant %d: %w", i, err)
+			}
+
+			if grant.ID == uuid.Nil {
+				grant.ID = uuid.New()
+			}
+			if grant.GrantedAt.IsZero() {
+				grant.GrantedAt = now
+			}
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO access_grants (id, org_id, grantor_id, grantee_id, resource_type, resource_id,
+				 permission, granted_at, expires_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+				grant.ID, grant.OrgID, grant.GrantorID, grant.GranteeID, grant.ResourceType,
+				grant.ResourceID, grant.Permission, grant.GrantedAt, grant.ExpiresAt,
+			); err != nil {
+				return fmt.Errorf("storage: create grant %d: %w", i, err)
+			}
+
+			entry := audit
+			entry.ResourceID = grant.ID.String()
+			entry.BeforeData = before
+			entry.AfterData = grant
+			if err := InsertMutationAuditTx(ctx, tx, entry); err != nil {
+				return fmt.Errorf("storage: audit in create grants batch tx: %w", err)
+			}
+			stored[i], created[i] = grant, true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, created, nil
+}
@@ -45,6 +45,8 @@ func (db *DB) BeginIdempotency(
 		statusCode   *int
 		responseData []byte
 	)
+	// Read from the primary, never readPool: a replica may not yet have the
+	// key or the completed response the conflicting insert just ran into.
 	if err := db.pool.QueryRow(ctx,
 		`SELECT request_hash, status, status_code, response_data
 		 FROM idempotency_keys
@@ -0,0 +1,344 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/integrity"
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// ImportStatus is the fate of one decision passed to ImportDecisions.
+type ImportStatus string
+
+const (
+	ImportInserted ImportStatus = "inserted"
+	ImportSkipped  ImportStatus = "skipped" // Already present in the org.
+	ImportFailed   ImportStatus = "failed"
+)
+
+// ImportResult reports what ImportDecisions did with one input decision.
+// Err is set only when Status is ImportFailed.
+type ImportResult struct {
+	Status ImportStatus
+	Err    error
+}
+
+// ImportDecisions inserts previously exported decisions into orgID, preserving
+// their id, valid_from, valid_to, and supersedes_id so revision chains and
+// temporal history survive the round trip. Decisions, alternatives, and
+// evidence are written with COPY in a single transaction. Results are
+// returned in input order.
+//
+// Per-decision outcomes:
+//   - a decision whose id already exists in the org is skipped, so re-running
+//     an interrupted import is safe;
+//   - a decision whose id or run_id exists in another org, or whose
+//     supersedes_id names a decision found neither in the org nor earlier in
+//     the input, fails;
+//   - a dangling precedent_ref is cleared rather than failing the decision.
+//
+// content_hash is recomputed from the canonical fields. api_key_id is dropped
+// because keys do not carry across instances. Runs and agents referenced by
+// imported decisions are created if missing; embeddings are left for the
+// backfill worker. The returned error is reserved for failures of the whole
+// batch, in which case nothing was written.
+func (db *DB) ImportDecisions(ctx context.Context, orgID uuid.UUID, decisions []model.Decision, audit MutationAuditEntry) ([]ImportResult, error) {
+	results := make([]ImportResult, len(decisions))
+	if len(decisions) == 0 {
+		return results, nil
+	}
+
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		clear(results)
+		ids := make([]uuid.UUID, len(decisions))
+		for i, d := range decisions {
+			ids[i] = d.ID
+		}
+		existing, err := importOwners(ctx, tx, "decisions", ids)
+		if err != nil {
+			return err
+		}
+		runIDs := make([]uuid.UUID, len(decisions))
+		for i, d := range decisions {
+			runIDs[i] = d.RunID
+		}
+		runOwners, err := importOwners(ctx, tx, "agent_runs", runIDs)
+		if err != nil {
+			return err
+		}
+
+		// Decide which rows to write. A revision may only be written once its
+		// predecessor is known to exist, so rows are visited in input order.
+		present := make(map[uuid.UUID]bool, len(decisions))
+		for id, owner := range existing {
+			if owner == orgID {
+				present[id] = true
+			}
+		}
+		refs, err := importMissingRefs(ctx, tx, orgID, decisions, present)
+		if err != nil {
+			return err
+		}
+		for id := range refs {
+			present[id] = true
+		}
+		var toInsert []int
+		for i, d := range decisions {
+			if owner, ok := existing[d.ID]; ok {
+				if owner == orgID {
+					results[i] = ImportResult{Status: ImportSkipped}
+				} else {
+					results[i] = ImportResult{Status: ImportFailed, Err: fmt.Errorf("decision %s already exists in another organization", d.ID)}
+				}
+				continue
+			}
+			if present[d.ID] {
+				// Duplicate of an earlier row in this batch.
+				results[i] = ImportResult{Status: ImportSkipped}
+				continue
+			}
+			if owner, ok := runOwners[d.RunID]; ok && owner != orgID {
+				results[i] = ImportResult{Status: ImportFailed, Err: fmt.Errorf("run %s already exists in another organization", d.RunID)}
+				continue
+			}
+			if d.SupersedesID != nil && !present[*d.SupersedesID] {
+				results[i] = ImportResult{Status: ImportFailed, Err: fmt.Errorf("superseded decision %s not found", *d.SupersedesID)}
+				continue
+			}
+			present[d.ID] = true
+			results[i] = ImportResult{Status: ImportInserted}
+			toInsert = append(toInsert, i)
+		}
+		if len(toInsert) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		rows := make([]model.Decision, len(toInsert))
+		for n, i := range toInsert {
+			d := decisions[i]
+			d.OrgID = orgID
+			d.APIKeyID = nil
+			if d.PrecedentRef != nil && !present[*d.PrecedentRef] {
+				d.PrecedentRef = nil
+				d.PrecedentReason = nil
+			}
+			if d.RunID == uuid.Nil {
+				d.RunID = uuid.New()
+			}
+			if d.TransactionTime.IsZero() {
+				d.TransactionTime = now
+			}
+			if d.CreatedAt.IsZero() {
+				d.CreatedAt = now
+			}
+			if d.Namespace == "" {
+				d.Namespace = model.DefaultNamespace
+			}
+			if d.Metadata == nil {
+				d.Metadata = map[string]any{}
+			}
+			if d.AgentContext == nil {
+				d.AgentContext = map[string]any{}
+			}
+			d.ContentHash = integrity.ComputeContentHash(d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+			d.HashVersion = integrity.CurrentHashVersion
+			rows[n] = d
+		}
+
+		if err := importEnsureAgentsAndRuns(ctx, tx, orgID, rows); err != nil {
+			return err
+		}
+		if err := db.importCopyDecisions(ctx, tx, rows, now); err != nil {
+			return err
+		}
+
+		// Only current decisions belong in the search index.
+		inserted := make([]string, len(rows))
+		for n, d := range rows {
+			inserted[n] = d.ID.String()
+			if d.ValidTo == nil {
+				if err := queueSearchOutbox(ctx, tx, d.ID, orgID, "upsert"); err != nil {
+					return fmt.Errorf("storage: queue search outbox for import: %w", err)
+				}
+			}
+		}
+
+		audit.OrgID = orgID
+		audit.Operation = "import_decisions"
+		audit.ResourceType = "decision"
+		audit.ResourceID = "batch"
+		audit.AfterData = map[string]any{"inserted": len(rows), "decision_ids": inserted}
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit import: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// importOwners maps each of ids that already exists in table to its org.
+// table is always a constant from this file, never caller input.
+func importOwners(ctx context.Context, tx pgx.Tx, table string, ids []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	rows, err := tx.Query(ctx, `SELECT id, org_id FROM `+table+` WHERE id = ANY($1)`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("storage: import: check existing %s: %w", table, err)
+	}
+	defer rows.Close()
+	existing := make(map[uuid.UUID]uuid.UUID)
+	for rows.Next() {
+		var id, org uuid.UUID
+		if err := rows.Scan(&id, &org); err != nil {
+			return nil, fmt.Errorf("storage: import: scan existing %s: %w", table, err)
+		}
+		existing[id] = org
+	}
+	return existing, rows.Err()
+}
+
+// importMissingRefs returns which supersedes_id and precedent_ref targets not
+// already known to be present exist in the org.
+func importMissingRefs(ctx context.Context, tx pgx.Tx, orgID uuid.UUID, decisions []model.Decision, present map[uuid.UUID]bool) (map[uuid.UUID]bool, error) {
+	inBatch := make(map[uuid.UUID]bool, len(decisions))
+	for _, d := range decisions {
+		inBatch[d.ID] = true
+	}
+	var lookup []uuid.UUID
+	for _, d := range decisions {
+		for _, ref := range []*uuid.UUID{d.SupersedesID, d.PrecedentRef} {
+			if ref != nil && !present[*ref] && !inBatch[*ref] {
+				lookup = append(lookup, *ref)
+			}
+		}
+	}
+	found := make(map[uuid.UUID]bool)
+	if len(lookup) == 0 {
+		return found, nil
+	}
+	rows, err := tx.Query(ctx, `SELECT id FROM decisions WHERE id = ANY($1) AND org_id = $2`, lookup, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("storage: import: check referenced decisions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: import: scan referenced decision: %w", err)
+		}
+		found[id] = true
+	}
+	return found, rows.Err()
+}
+
+// importEnsureAgentsAndRuns creates the agents and runs that imported
+// decisions reference, leaving existing ones untouched. Recreated runs are
+// marked completed as of their earliest decision.
+func importEnsureAgentsAndRuns(ctx context.Context, tx pgx.Tx, orgID uuid.UUID, rows []model.Decision) error {
+	now := time.Now().UTC()
+	agents := make(map[string]bool)
+	runs := make(map[uuid.UUID]model.Decision)
+	for _, d := range rows {
+		if !agents[d.AgentID] {
+			agents[d.AgentID] = true
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO agents (id, agent_id, org_id, name, role, tags, metadata, created_at, updated_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+				 ON CONFLICT (org_id, agent_id) DO NOTHING`,
+				uuid.New(), d.AgentID, orgID, d.AgentID, string(model.RoleAgent), []string{}, map[string]any{}, now,
+			); err != nil {
+				return fmt.Errorf("storage: import: ensure agent %q: %w", d.AgentID, err)
+			}
+		}
+		if first, ok := runs[d.RunID]; !ok || d.ValidFrom.Before(first.ValidFrom) {
+			runs[d.RunID] = d
+		}
+	}
+	for runID, d := range runs {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO agent_runs (id, agent_id, org_id, status, started_at, completed_at, metadata, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $5, $6, $7)
+			 ON CONFLICT (id) DO NOTHING`,
+			runID, d.AgentID, orgID, string(model.RunStatusCompleted), d.ValidFrom, map[string]any{"imported": true}, now,
+		); err != nil {
+			return fmt.Errorf("storage: import: ensure run %s: %w", runID, err)
+		}
+	}
+	return nil
+}
+
+// importCopyDecisions writes rows and their alternatives and evidence via COPY.
+func (db *DB) importCopyDecisions(ctx context.Context, tx pgx.Tx, rows []model.Decision, now time.Time) error {
+	// COPY gets the same dedicated timeout as the trace path.
+	copyCtx, copyCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer copyCancel()
+
+	decisionCols := []string{"id", "run_id", "agent_id", "org_id", "decision_type", "outcome", "confidence",
+		"reasoning", "metadata", "completeness_score", "outcome_score", "precedent_ref", "precedent_reason",
+		"supersedes_id", "content_hash", "valid_from", "valid_to", "transaction_time", "created_at",
+		"session_id", "agent_context", "confidence_low", "confidence_high", "namespace", "outcome_flipped",
+		"batch_id", "context_snapshot", "context_snapshot_truncated", "hash_version"}
+	decisionRows := make([][]any, len(rows))
+	var altRows, evRows [][]any
+	for i, d := range rows {
+		decisionRows[i] = []any{d.ID, d.RunID, d.AgentID, d.OrgID, d.DecisionType, d.Outcome, d.Confidence,
+			d.Reasoning, d.Metadata, d.CompletenessScore, d.OutcomeScore, d.PrecedentRef, d.PrecedentReason,
+			d.SupersedesID, d.ContentHash, d.ValidFrom, d.ValidTo, d.TransactionTime, d.CreatedAt,
+			d.SessionID, d.AgentContext, d.ConfidenceLow, d.ConfidenceHigh, d.Namespace, d.OutcomeFlipped,
+			d.BatchID, d.ContextSnapshot, d.ContextSnapshotTruncated, d.HashVersion}
+		for _, a := range d.Alternatives {
+			id, createdAt, meta := importChildDefaults(a.ID, a.CreatedAt, a.Metadata, now)
+			altRows = append(altRows, []any{id, d.ID, a.Label, a.RejectionReason, meta, createdAt})
+		}
+		for _, ev := range d.Evidence {
+			id, createdAt, meta := importChildDefaults(ev.ID, ev.CreatedAt, ev.Metadata, now)
+			content, blobHash, err := db.evidenceContentForWrite(ctx, tx, d.OrgID, ev.Content)
+			if err != nil {
+				return err
+			}
+			evRows = append(evRows, []any{id, d.ID, d.OrgID, string(ev.SourceType), ev.SourceURI, content,
+				ev.RelevanceScore, meta, createdAt, blobHash})
+		}
+	}
+	if _, err := tx.CopyFrom(copyCtx, pgx.Identifier{"decisions"}, decisionCols, pgx.CopyFromRows(decisionRows)); err != nil {
+		return fmt.Errorf("storage: import decisions: %w", err)
+	}
+	if len(altRows) > 0 {
+		columns := []string{"id", "decision_id", "label", "rejection_reason", "metadata", "created_at"}
+		if _, err := tx.CopyFrom(copyCtx, pgx.Identifier{"alternatives"}, columns, pgx.CopyFromRows(altRows)); err != nil {
+			return fmt.Errorf("storage: import alternatives: %w", err)
+		}
+	}
+	if len(evRows) > 0 {
+		columns := []string{"id", "decision_id", "org_id", "source_type", "source_uri", "content",
+			"relevance_score", "metadata", "created_at", "blob_hash"}
+		if _, err := tx.CopyFrom(copyCtx, pgx.Identifier{"evidence"}, columns, pgx.CopyFromRows(evRows)); err != nil {
+			return fmt.Errorf("storage: import evidence: %w", err)
+		}
+	}
+	return nil
+}
+
+// importChildDefaults fills in the id, created_at, and metadata of an
+// imported alternative or evidence row when the export left them empty.
+func importChildDefaults(id uuid.UUID, createdAt time.Time, meta map[string]any, now time.Time) (uuid.UUID, time.Time, map[string]any) {
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	return id, createdAt, meta
+}
@@ -87,6 +87,115 @@ func (db *DB) UpsertOrgSettingsWithAudit(ctx context.Context, orgID uuid.UUID, s
 	})
 }
 
+// GetOrgSetting returns the raw value stored under one top-level key of an
+// org's settings, or nil when the key (or the whole row) is unset.
+func (db *DB) GetOrgSetting(ctx context.Context, orgID uuid.UUID, key string) (json.RawMessage, error) {
+	var raw []byte
+	err := db.pool.QueryRow(ctx,
+		`SELECT settings->$2 FROM org_settings WHERE org_id = $1`,
+		orgID, key,
+	).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: get org setting %q: %w", key, err)
+	}
+	if raw == nil || string(raw) == "null" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// SetOrgSetting replaces one top-level key of an org's settings, leaving the
+// other keys alone, and records a mutation audit entry in the same
+// transaction with the key's before and after values. A nil or JSON null
+// value removes the key. The caller validates value.
+func (db *DB) SetOrgSetting(ctx context.Context, orgID uuid.UUID, key string, value json.RawMessage, updatedBy string, audit MutationAuditEntry) error {
+	if string(value) == "null" {
+		value = nil
+	}
+	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var before []byte
+		scanErr := tx.QueryRow(ctx,
+			`SELECT settings->$2 FROM org_settings WHERE org_id = $1 FOR UPDATE`,
+			orgID, key,
+		).Scan(&before)
+		if scanErr != nil && !errors.Is(scanErr, pgx.ErrNoRows) {
+			return fmt.Errorf("storage: read org setting before-state: %w", scanErr)
+		}
+		if before != nil {
+			audit.BeforeData = json.RawMessage(before)
+		}
+
+		var err error
+		if value == nil {
+			_, err = tx.Exec(ctx,
+				`UPDATE org_settings SET settings = settings - $2, updated_at = now(), updated_by = $3
+				 WHERE org_id = $1`,
+				orgID, key, updatedBy,
+			)
+		} else {
+			audit.AfterData = value
+			_, err = tx.Exec(ctx,
+				`INSERT INTO org_settings (org_id, settings, updated_at, updated_by)
+				 VALUES ($1, jsonb_build_object($2::text, $3::jsonb), now(), $4)
+				 ON CONFLICT (org_id) DO UPDATE
+				 SET settings = org_settings.settings || jsonb_build_object($2::text, $3::jsonb),
+				     updated_at = now(), updated_by = $4`,
+				orgID, key, []byte(value), updatedBy,
+			)
+		}
+		if err != nil {
+			return fmt.Errorf("storage: set org setting %q: %w", key, err)
+		}
+
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit org setting update: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetOrgConfigOverrides returns the org's ConfigOverridesPolicy, or nil when
+// the org uses the global configuration.
+func (db *DB) GetOrgConfigOverrides(ctx context.Context, orgID uuid.UUID) (*model.ConfigOverridesPolicy, error) {
+	raw, err := db.GetOrgSetting(ctx, orgID, model.OrgSettingConfigOverrides)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	var p model.ConfigOverridesPolicy
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("storage: unmarshal config overrides: %w", err)
+	}
+	return &p, nil
+}
+
+// ListOrgEventRetentionOverrides returns the event retention, in days, of
+// every org that overrides it. 0 means the org keeps its events forever.
+func (db *DB) ListOrgEventRetentionOverrides(ctx context.Context) (map[uuid.UUID]int, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT org_id, (settings->'config_overrides'->>'event_retention_days')::int
+		 FROM org_settings
+		 WHERE jsonb_typeof(settings->'config_overrides'->'event_retention_days') = 'number'`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list org event retention overrides: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var orgID uuid.UUID
+		var days int
+		if err := rows.Scan(&orgID, &days); err != nil {
+			return nil, fmt.Errorf("storage: scan org event retention override: %w", err)
+		}
+		out[orgID] = days
+	}
+	return out, rows.Err()
+}
+
 // OrgAutoResolveConfig holds the parsed auto-resolution policy for an org.
 type OrgAutoResolveConfig struct {
 	OrgID  uuid.UUID
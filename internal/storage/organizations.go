@@ -141,3 +141,46 @@ func (db *DB) CreateOrgWithOwnerAndKeyTx(
 	}
 	return org, agent, key, nil
 }
+
+// GetOrgRateLimit returns the org's rate limit override, or nil when the org
+// uses the global default. Returns ErrNotFound if the org does not exist.
+func (db *DB) GetOrgRateLimit(ctx context.Context, orgID uuid.UUID) (*model.OrgRateLimit, error) {
+	var rps *float64
+	var burst *int
+	err := db.pool.QueryRow(ctx,
+		`SELECT rate_limit_rps, rate_limit_burst FROM organizations WHERE id = $1`, orgID,
+	).Scan(&rps, &burst)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("storage: organization %s: %w", orgID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("storage: get org rate limit: %w", err)
+	}
+	if rps == nil || burst == nil {
+		return nil, nil
+	}
+	return &model.OrgRateLimit{RPS: *rps, Burst: *burst}, nil
+}
+
+// SetOrgRateLimit sets the org's rate limit override; nil clears it.
+// Returns ErrNotFound if the org does not exist.
+func (db *DB) SetOrgRateLimit(ctx context.Context, orgID uuid.UUID, limit *model.OrgRateLimit) error {
+	var rps *float64
+	var burst *int
+	if limit != nil {
+		rps, burst = &limit.RPS, &limit.Burst
+	}
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE organizations
+		 SET rate_limit_rps = $2, rate_limit_burst = $3, updated_at = now()
+		 WHERE id = $1`,
+		orgID, rps, burst,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: set org rate limit: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("storage: organization %s: %w", orgID, ErrNotFound)
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 // and a dedicated pgx.Conn for LISTEN/NOTIFY (direct to Postgres).
 type DB struct {
 	pool       *pgxpool.Pool
+	replica    *pgxpool.Pool // optional read replica; see readPool
 	notifyConn *pgx.Conn
 	notifyDSN  string
 	notifyMu   sync.Mutex
@@ -36,6 +37,11 @@ type DB struct {
 	// listenChannels tracks subscribed channels so they can be re-established after reconnect.
 	listenChannels []string
 	logger         *slog.Logger
+	// evidenceInlineMax is the evidence content size above which content is
+	// stored in evidence_blobs; see SetEvidenceInlineMaxBytes.
+	evidenceInlineMax int
+	// confPrecision rounds confidence on revisions; see SetConfidencePrecision.
+	confPrecision int
 }
 
 // Compile-time assertion: *DB satisfies Store.
@@ -45,15 +51,59 @@ var _ Store = (*DB)(nil)
 type PoolOptions struct {
 	MaxConns int32 // Maximum connections in the pool. 0 = pgxpool default (max(4, NumCPU)).
 	MinConns int32 // Minimum idle connections kept open. 0 = pgxpool default (0).
+	// ReplicaDSN optionally points at a streaming read replica. Empty = all
+	// queries go to the primary.
+	ReplicaDSN string
 }
 
 // New creates a new DB with a connection pool.
 // poolDSN should point to PgBouncer (or directly to Postgres in dev).
 // notifyDSN should point directly to Postgres for LISTEN/NOTIFY support.
+// When opts.ReplicaDSN is set, a second pool of the same size is opened
+// against it for the read-only query paths (see readPool).
 func New(ctx context.Context, poolDSN, notifyDSN string, logger *slog.Logger, opts PoolOptions) (*DB, error) {
-	poolCfg, err := pgxpool.ParseConfig(poolDSN)
+	pool, err := newPool(ctx, poolDSN, logger, opts, "pool")
 	if err != nil {
-		return nil, fmt.Errorf("storage: parse pool DSN: %w", err)
+		return nil, err
+	}
+
+	var replica *pgxpool.Pool
+	if opts.ReplicaDSN != "" {
+		replica, err = newPool(ctx, opts.ReplicaDSN, logger, opts, "replica pool")
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+
+	var notifyConn *pgx.Conn
+	if notifyDSN != "" {
+		notifyConn, err = pgx.Connect(ctx, notifyDSN)
+		if err != nil {
+			pool.Close()
+			if replica != nil {
+				replica.Close()
+			}
+			return nil, fmt.Errorf("storage: connect notify: %w", err)
+		}
+	}
+
+	return &DB{
+		pool:              pool,
+		replica:           replica,
+		notifyConn:        notifyConn,
+		notifyDSN:         notifyDSN,
+		logger:            logger,
+		evidenceInlineMax: DefaultEvidenceInlineMaxBytes,
+	}, nil
+}
+
+// newPool opens and pings a connection pool sized by opts. name labels the
+// pool in logs and errors.
+func newPool(ctx context.Context, dsn string, logger *slog.Logger, opts PoolOptions, name string) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("storage: parse %s DSN: %w", name, err)
 	}
 
 	if opts.MaxConns > 0 {
@@ -63,7 +113,7 @@ func New(ctx context.Context, poolDSN, notifyDSN string, logger *slog.Logger, op
 		poolCfg.MinConns = opts.MinConns
 	}
 
-	logger.Info("storage: pool configured",
+	logger.Info("storage: "+name+" configured",
 		"max_conns", poolCfg.MaxConns,
 		"min_conns", poolCfg.MinConns,
 	)
@@ -82,29 +132,14 @@ func New(ctx context.Context, poolDSN, notifyDSN string, logger *slog.Logger, op
 
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
-		return nil, fmt.Errorf("storage: create pool: %w", err)
+		return nil, fmt.Errorf("storage: create %s: %w", name, err)
 	}
 
 	if err := pool.Ping(ctx); err != nil {
 		pool.Close()
-		return nil, fmt.Errorf("storage: ping pool: %w", err)
-	}
-
-	var notifyConn *pgx.Conn
-	if notifyDSN != "" {
-		notifyConn, err = pgx.Connect(ctx, notifyDSN)
-		if err != nil {
-			pool.Close()
-			return nil, fmt.Errorf("storage: connect notify: %w", err)
-		}
+		return nil, fmt.Errorf("storage: ping %s: %w", name, err)
 	}
-
-	return &DB{
-		pool:       pool,
-		notifyConn: notifyConn,
-		notifyDSN:  notifyDSN,
-		logger:     logger,
-	}, nil
+	return pool, nil
 }
 
 // Pool returns the underlying connection pool for use by other packages.
@@ -112,6 +147,22 @@ func (db *DB) Pool() *pgxpool.Pool {
 	return db.pool
 }
 
+// readPool returns the pool for read-only queries that tolerate replication
+// lag: the replica when one is configured, the primary otherwise. Anything
+// that must observe the caller's own recent writes (idempotency replays,
+// read-after-write lookups, reads inside transactions) uses db.pool.
+func (db *DB) readPool() *pgxpool.Pool {
+	if db.replica != nil {
+		return db.replica
+	}
+	return db.pool
+}
+
+// ReplicaPool returns the read replica pool, or nil when none is configured.
+func (db *DB) ReplicaPool() *pgxpool.Pool {
+	return db.replica
+}
+
 // HasNotifyConn reports whether a dedicated LISTEN/NOTIFY connection is configured.
 // Use this instead of accessing the raw connection — the connection is managed
 // internally by WaitForNotification and its reconnect logic.
@@ -135,6 +186,9 @@ func (db *DB) IsDuplicateKey(err error) bool {
 // Close shuts down the connection pool and notify connection.
 func (db *DB) Close(ctx context.Context) {
 	db.pool.Close()
+	if db.replica != nil {
+		db.replica.Close()
+	}
 	db.notifyMu.Lock()
 	defer db.notifyMu.Unlock()
 	if db.notifyConn != nil {
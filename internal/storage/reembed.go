@@ -0,0 +1,288 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+const reembedJobCols = `id, org_id, from_model, to_model, confirmation_token, status, cursor_id,
+	total, processed, failed, created_by, created_at, updated_at, completed_at`
+
+func scanReembedJob(row pgx.Row) (model.ReembedJob, error) {
+	var j model.ReembedJob
+	err := row.Scan(&j.ID, &j.OrgID, &j.FromModel, &j.ToModel, &j.ConfirmationToken, &j.Status, &j.CursorID,
+		&j.Total, &j.Processed, &j.Failed, &j.CreatedBy, &j.CreatedAt, &j.UpdatedAt, &j.CompletedAt)
+	return j, err
+}
+
+// CreateReembedJob creates a pending re-embedding job for the org. Total is
+// the org's current decision count at creation. Returns an error satisfying
+// DB.IsDuplicateKey if the org already has an unfinished job.
+func (db *DB) CreateReembedJob(ctx context.Context, orgID uuid.UUID, fromModel, toModel, token, createdBy string) (model.ReembedJob, error) {
+	j, err := scanReembedJob(db.pool.QueryRow(ctx,
+		`INSERT INTO reembed_jobs (org_id, from_model, to_model, confirmation_token, created_by, total)
+		 SELECT $1, $2, $3, $4, $5, COUNT(*) FROM decisions WHERE org_id = $1 AND valid_to IS NULL
+		 RETURNING `+reembedJobCols,
+		orgID, fromModel, toModel, token, createdBy,
+	))
+	if err != nil {
+		return model.ReembedJob{}, fmt.Errorf("storage: create reembed job: %w", err)
+	}
+	return j, nil
+}
+
+// GetOpenReembedJob returns the org's unfinished re-embedding job.
+// Returns ErrNotFound if there is none.
+func (db *DB) GetOpenReembedJob(ctx context.Context, orgID uuid.UUID) (model.ReembedJob, error) {
+	j, err := scanReembedJob(db.pool.QueryRow(ctx,
+		`SELECT `+reembedJobCols+` FROM reembed_jobs
+		 WHERE org_id = $1 AND status <> $2`,
+		orgID, model.ReembedStatusCompleted,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReembedJob{}, fmt.Errorf("storage: open reembed job: %w", ErrNotFound)
+		}
+		return model.ReembedJob{}, fmt.Errorf("storage: get open reembed job: %w", err)
+	}
+	return j, nil
+}
+
+// GetLatestReembedJob returns the org's most recently created re-embedding
+// job, finished or not. Returns ErrNotFound if the org has none.
+func (db *DB) GetLatestReembedJob(ctx context.Context, orgID uuid.UUID) (model.ReembedJob, error) {
+	j, err := scanReembedJob(db.pool.QueryRow(ctx,
+		`SELECT `+reembedJobCols+` FROM reembed_jobs
+		 WHERE org_id = $1 ORDER BY created_at DESC LIMIT 1`,
+		orgID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReembedJob{}, fmt.Errorf("storage: latest reembed job: %w", ErrNotFound)
+		}
+		return model.ReembedJob{}, fmt.Errorf("storage: get latest reembed job: %w", err)
+	}
+	return j, nil
+}
+
+// AdvanceReembedJob records a processed batch: the job moves to running, its
+// cursor to cursorID, and its counters by the given deltas. When completed is
+// true the job is closed and a new one may be created for the org.
+func (db *DB) AdvanceReembedJob(ctx context.Context, orgID, jobID uuid.UUID, cursorID *uuid.UUID, processed, failed int, completed bool) (model.ReembedJob, error) {
+	status := model.ReembedStatusRunning
+	if completed {
+		status = model.ReembedStatusCompleted
+	}
+	j, err := scanReembedJob(db.pool.QueryRow(ctx,
+		`UPDATE reembed_jobs SET
+		   status = $3,
+		   cursor_id = COALESCE($4, cursor_id),
+		   processed = processed + $5,
+		   failed = failed + $6,
+		   updated_at = now(),
+		   completed_at = CASE WHEN $3 = 'completed' THEN now() END
+		 WHERE id = $1 AND org_id = $2 AND status <> 'completed'
+		 RETURNING `+reembedJobCols,
+		jobID, orgID, status, cursorID, processed, failed,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReembedJob{}, fmt.Errorf("storage: reembed job %s: %w", jobID, ErrNotFound)
+		}
+		return model.ReembedJob{}, fmt.Errorf("storage: advance reembed job: %w", err)
+	}
+	return j, nil
+}
+
+// FindDecisionsForReembed returns up to limit current decisions in the org
+// with id greater than afterID (all when nil), in id order. Used as a keyset
+// cursor so a re-embedding job can resume where it stopped.
+func (db *DB) FindDecisionsForReembed(ctx context.Context, orgID uuid.UUID, afterID *uuid.UUID, limit int) ([]UnembeddedDecision, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, org_id, decision_type, outcome, reasoning
+		 FROM decisions
+		 WHERE org_id = $1 AND valid_to IS NULL AND ($2::uuid IS NULL OR id > $2)
+		 ORDER BY id ASC
+		 LIMIT $3`,
+		orgID, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: find decisions for reembed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []UnembeddedDecision
+	for rows.Next() {
+		var d UnembeddedDecision
+		if err := rows.Scan(&d.ID, &d.OrgID, &d.DecisionType, &d.Outcome, &d.Reasoning); err != nil {
+			return nil, fmt.Errorf("storage: scan decision for reembed: %w", err)
+		}
+		results = append(results, d)
+	}
+	return results, rows.Err()
+}
+
+// DeleteClaimsForDecisions removes the claims of the given decisions so they
+// are regenerated with the current embedding model.
+func (db *DB) DeleteClaimsForDecisions(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := db.pool.Exec(ctx,
+		`DELETE FROM decision_claims WHERE org_id = $1 AND decision_id = ANY($2)`,
+		orgID, ids,
+	); err != nil {
+		return fmt.Errorf("storage: delete claims for decisions: %w", err)
+	}
+	return nil
+}
+
+// ClearEmbeddingsResult counts the rows ClearEmbeddings reset.
+type ClearEmbeddingsResult struct {
+	Decisions int64 `json:"decisions"`
+	Evidence  int64 `json:"evidence"`
+	Claims    int64 `json:"claims"`
+}
+
+// ClearEmbeddings nulls embedding and outcome_embedding on every decision in
+// the org, superseded ones included, nulls its evidence embeddings, and
+// deletes its claims, in one transaction. The startup backfills then
+// regenerate current decisions' vectors and claims with the configured
+// provider. Used when switching to an embedding model whose vectors cannot
+// be compared with the old ones, including one of another dimension (see
+// AlterEmbeddingDimensions).
+func (db *DB) ClearEmbeddings(ctx context.Context, orgID uuid.UUID) (ClearEmbeddingsResult, error) {
+	var result ClearEmbeddingsResult
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE decisions SET embedding = NULL, outcome_embedding = NULL, embedding_model = NULL
+			 WHERE org_id = $1 AND (embedding IS NOT NULL OR outcome_embedding IS NOT NULL)`,
+			orgID)
+		if err != nil {
+			return fmt.Errorf("storage: clear decision embeddings: %w", err)
+		}
+		result.Decisions = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx,
+			`UPDATE evidence SET embedding = NULL WHERE org_id = $1 AND embedding IS NOT NULL`, orgID)
+		if err != nil {
+			return fmt.Errorf("storage: clear evidence embeddings: %w", err)
+		}
+		result.Evidence = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx, `DELETE FROM decision_claims WHERE org_id = $1`, orgID)
+		if err != nil {
+			return fmt.Errorf("storage: clear claims: %w", err)
+		}
+		result.Claims = tag.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return ClearEmbeddingsResult{}, err
+	}
+	return result, nil
+}
+
+// embeddingColumns lists every pgvector column sized to the embedding
+// provider's dimension.
+var embeddingColumns = []struct{ table, column string }{
+	{"decisions", "embedding"},
+	{"decisions", "outcome_embedding"},
+	{"evidence", "embedding"},
+	{"decision_claims", "embedding"},
+}
+
+// MaxEmbeddingDimensions is the largest dimension a pgvector vector column
+// accepts. maxHNSWDimensions is the largest an HNSW index accepts.
+const (
+	MaxEmbeddingDimensions = 16000
+	maxHNSWDimensions      = 2000
+)
+
+// ErrEmbeddingsPresent is returned by AlterEmbeddingDimensions while an
+// embedding column still holds vectors.
+var ErrEmbeddingsPresent = errors.New("storage: embedding columns still hold vectors; run ClearEmbeddings for every org first")
+
+// EmbeddingDimensions returns the dimension declared for decisions.embedding,
+// which every embedding column shares.
+func (db *DB) EmbeddingDimensions(ctx context.Context) (int, error) {
+	var dims int
+	err := db.pool.QueryRow(ctx,
+		`SELECT atttypmod FROM pg_attribute
+		 WHERE attrelid = 'decisions'::regclass AND attname = 'embedding' AND NOT attisdropped`,
+	).Scan(&dims)
+	if err != nil {
+		return 0, fmt.Errorf("storage: read embedding dimensions: %w", err)
+	}
+	return dims, nil
+}
+
+// AlterEmbeddingDimensions changes every embedding column to vector(dims).
+// pgvector cannot cast a vector to another dimension, so the change is
+// refused with ErrEmbeddingsPresent unless every column is empty; the
+// tables are locked first so no vector can be written between that check
+// and the ALTERs. The claim HNSW index is rebuilt for the new type. A
+// no-op when the columns already have this dimension.
+func (db *DB) AlterEmbeddingDimensions(ctx context.Context, dims int) error {
+	if dims < 1 || dims > MaxEmbeddingDimensions {
+		return fmt.Errorf("storage: embedding dimensions must be between 1 and %d, got %d", MaxEmbeddingDimensions, dims)
+	}
+	current, err := db.EmbeddingDimensions(ctx)
+	if err != nil {
+		return err
+	}
+	if current == dims {
+		return nil
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			`LOCK TABLE decisions, evidence, decision_claims IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return fmt.Errorf("storage: lock embedding tables: %w", err)
+		}
+		for _, c := range embeddingColumns {
+			var present bool
+			if err := tx.QueryRow(ctx, fmt.Sprintf(
+				`SELECT EXISTS (SELECT 1 FROM %s WHERE %s IS NOT NULL)`, c.table, c.column),
+			).Scan(&present); err != nil {
+				return fmt.Errorf("storage: check %s.%s: %w", c.table, c.column, err)
+			}
+			if present {
+				return fmt.Errorf("%w (%s.%s)", ErrEmbeddingsPresent, c.table, c.column)
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `DROP INDEX IF EXISTS idx_decision_claims_embedding`); err != nil {
+			return fmt.Errorf("storage: drop claim embedding index: %w", err)
+		}
+		for _, c := range embeddingColumns {
+			if _, err := tx.Exec(ctx, fmt.Sprintf(
+				`ALTER TABLE %s ALTER COLUMN %s TYPE vector(%d)`, c.table, c.column, dims),
+			); err != nil {
+				return fmt.Errorf("storage: alter %s.%s: %w", c.table, c.column, err)
+			}
+		}
+		// Same definition as migration 120. HNSW cannot index vectors wider
+		// than maxHNSWDimensions; claim lookups then scan instead.
+		if dims > maxHNSWDimensions {
+			db.logger.Warn("storage: claim embedding index not rebuilt; dimension exceeds the HNSW limit",
+				"dims", dims, "limit", maxHNSWDimensions)
+			return nil
+		}
+		if _, err := tx.Exec(ctx,
+			`CREATE INDEX idx_decision_claims_embedding
+			 ON decision_claims USING hnsw (embedding vector_cosine_ops)
+			 WHERE embedding IS NOT NULL`); err != nil {
+			return fmt.Errorf("storage: recreate claim embedding index: %w", err)
+		}
+		return nil
+	})
+}
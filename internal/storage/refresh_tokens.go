@@ -0,0 +1,78 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// RefreshToken is a stored refresh token. TokenHash is the SHA-256 of the
+// signed token (auth.HashRefreshToken); the raw token is never stored.
+type RefreshToken struct {
+	ID        uuid.UUID
+	OrgID     uuid.UUID
+	AgentID   string
+	TokenHash string
+	APIKeyID  *uuid.UUID // managed key the session started from; nil for legacy keys
+	ExpiresAt time.Time
+}
+
+// CreateRefreshToken stores a newly issued refresh token.
+func (db *DB) CreateRefreshToken(ctx context.Context, t RefreshToken) error {
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO refresh_tokens (id, org_id, agent_id, token_hash, api_key_id, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		t.ID, t.OrgID, t.AgentID, t.TokenHash, t.APIKeyID, t.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: create refresh token: %w", err)
+	}
+	return nil
+}
+
+// RotateRefreshToken consumes the refresh token identified by id and
+// tokenHash and stores next as its replacement, in one transaction. The old
+// token is revoked, so each refresh token works exactly once; concurrent
+// uses race on the row lock and only one succeeds. next inherits the API key
+// of the token it replaces (next.APIKeyID is ignored).
+//
+// Returns ErrRefreshTokenInvalid when the token is unknown, expired, already
+// revoked, or was issued from an API key that has since been revoked or has
+// expired.
+func (db *DB) RotateRefreshToken(ctx context.Context, orgID, id uuid.UUID, tokenHash string, next RefreshToken) error {
+	return db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var apiKeyID *uuid.UUID
+		err := tx.QueryRow(ctx,
+			`UPDATE refresh_tokens rt SET revoked_at = now(), replaced_by = $4
+			 WHERE rt.id = $1 AND rt.org_id = $2 AND rt.token_hash = $3
+			   AND rt.revoked_at IS NULL AND rt.expires_at > now()
+			   AND (rt.api_key_id IS NULL OR EXISTS (
+			       SELECT 1 FROM api_keys k
+			       WHERE k.id = rt.api_key_id AND k.revoked_at IS NULL
+			         AND (k.expires_at IS NULL OR k.expires_at > now())))
+			 RETURNING rt.api_key_id`,
+			id, orgID, tokenHash, next.ID,
+		).Scan(&apiKeyID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrRefreshTokenInvalid
+		}
+		if err != nil {
+			return fmt.Errorf("storage: rotate refresh token: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO refresh_tokens (id, org_id, agent_id, token_hash, api_key_id, expires_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			next.ID, next.OrgID, next.AgentID, next.TokenHash, apiKeyID, next.ExpiresAt,
+		); err != nil {
+			return fmt.Errorf("storage: store rotated refresh token: %w", err)
+		}
+		return nil
+	})
+}
@@ -426,11 +426,15 @@ func (db *DB) deleteBatch(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID)
 	txErr := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
 
 		// 1. Archive and delete evidence (scoped by org_id for defense in depth).
+		// Blob content is copied into the archive; the blob itself is left for
+		// the orphan sweep since other evidence may share it.
 		if _, err := tx.Exec(ctx,
 			`INSERT INTO deletion_audit_log (org_id, agent_id, table_name, record_id, record_data)
-		 SELECT $2, d.agent_id, 'evidence', e.id::text, to_jsonb(e)
+		 SELECT $2, d.agent_id, 'evidence', e.id::text,
+		        to_jsonb(e) || jsonb_build_object('content', COALESCE(b.content, e.content))
 		 FROM evidence e
 		 JOIN decisions d ON d.id = e.decision_id
+		 LEFT JOIN evidence_blobs b ON b.org_id = e.org_id AND b.content_hash = e.blob_hash
 		 WHERE e.decision_id = ANY($1) AND e.org_id = $2`,
 			ids, orgID,
 		); err != nil {
@@ -0,0 +1,318 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+const reviewFlagCols = `f.decision_id, f.org_id, f.reason, f.note, f.flagged_by, f.flagged_at,
+	f.reviewed_at, f.reviewed_by, f.review_note, f.assigned_reviewer`
+
+func scanReviewFlag(row pgx.Row, extra ...any) (model.ReviewFlag, error) {
+	var f model.ReviewFlag
+	dest := append([]any{
+		&f.DecisionID, &f.OrgID, &f.Reason, &f.Note, &f.FlaggedBy, &f.FlaggedAt,
+		&f.ReviewedAt, &f.ReviewedBy, &f.ReviewNote, &f.AssignedReviewer,
+	}, extra...)
+	err := row.Scan(dest...)
+	return f, err
+}
+
+// FlagDecisionForReview manually flags an active decision for review. A
+// reviewed flag is reopened with a fresh flagged_at; a pending flag keeps its
+// original flagged_at so re-flagging cannot reset the SLA clock.
+// Returns ErrNotFound if the decision is not active in the org.
+func (db *DB) FlagDecisionForReview(ctx context.Context, orgID, decisionID uuid.UUID, flaggedBy string, note *string) (model.ReviewFlag, error) {
+	f, err := scanReviewFlag(db.pool.QueryRow(ctx,
+		`INSERT INTO decision_review_flags AS f (decision_id, org_id, reason, note, flagged_by)
+		 SELECT d.id, d.org_id, $3, $4, $5
+		 FROM decisions d WHERE d.id = $1 AND d.org_id = $2 AND d.valid_to IS NULL
+		 ON CONFLICT (decision_id) DO UPDATE SET
+		   reason = EXCLUDED.reason,
+		   note = EXCLUDED.note,
+		   flagged_by = EXCLUDED.flagged_by,
+		   flagged_at = CASE WHEN f.reviewed_at IS NULL THEN f.flagged_at ELSE now() END,
+		   reviewed_at = NULL,
+		   reviewed_by = NULL,
+		   review_note = NULL,
+		   overdue_notified_at = CASE WHEN f.reviewed_at IS NULL THEN f.overdue_notified_at END,
+		   assigned_reviewer = CASE WHEN f.reviewed_at IS NULL THEN f.assigned_reviewer END,
+		   routed_at = CASE WHEN f.reviewed_at IS NULL THEN f.routed_at END
+		 RETURNING `+reviewFlagCols,
+		decisionID, orgID, model.ReviewReasonManual, note, flaggedBy,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReviewFlag{}, fmt.Errorf("storage: decision %s: %w", decisionID, ErrNotFound)
+		}
+		return model.ReviewFlag{}, fmt.Errorf("storage: flag decision for review: %w", err)
+	}
+	return f, nil
+}
+
+// GetReviewFlag returns a decision's review flag, pending or reviewed.
+// Returns ErrNotFound if the decision has never been flagged.
+func (db *DB) GetReviewFlag(ctx context.Context, orgID, decisionID uuid.UUID) (model.ReviewFlag, error) {
+	f, err := scanReviewFlag(db.pool.QueryRow(ctx,
+		`SELECT `+reviewFlagCols+` FROM decision_review_flags f
+		 WHERE f.decision_id = $1 AND f.org_id = $2`,
+		decisionID, orgID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReviewFlag{}, fmt.Errorf("storage: review flag for decision %s: %w", decisionID, ErrNotFound)
+		}
+		return model.ReviewFlag{}, fmt.Errorf("storage: get review flag: %w", err)
+	}
+	return f, nil
+}
+
+// MarkDecisionReviewed closes a pending review flag.
+// Returns ErrNotFound if the decision has no pending flag in the org.
+func (db *DB) MarkDecisionReviewed(ctx context.Context, orgID, decisionID uuid.UUID, reviewedBy string, note *string) (model.ReviewFlag, error) {
+	f, err := scanReviewFlag(db.pool.QueryRow(ctx,
+		`UPDATE decision_review_flags AS f
+		 SET reviewed_at = now(), reviewed_by = $3, review_note = $4
+		 WHERE f.decision_id = $1 AND f.org_id = $2 AND f.reviewed_at IS NULL
+		 RETURNING `+reviewFlagCols,
+		decisionID, orgID, reviewedBy, note,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ReviewFlag{}, fmt.Errorf("storage: pending review for decision %s: %w", decisionID, ErrNotFound)
+		}
+		return model.ReviewFlag{}, fmt.Errorf("storage: mark decision reviewed: %w", err)
+	}
+	return f, nil
+}
+
+// UnroutedReviewFlag is a pending review flag whose reviewer has not been
+// chosen yet, with the producing agent's tags for routing.
+type UnroutedReviewFlag struct {
+	DecisionID uuid.UUID
+	AgentID    string
+	AgentTags  []string
+}
+
+// ListUnroutedReviewFlags returns up to limit pending flags on active
+// decisions that have not been through reviewer routing, oldest first.
+func (db *DB) ListUnroutedReviewFlags(ctx context.Context, orgID uuid.UUID, limit int) ([]UnroutedReviewFlag, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT f.decision_id, d.agent_id, COALESCE(a.tags, '{}')
+		 FROM decision_review_flags f
+		 JOIN decisions d ON d.id = f.decision_id AND d.org_id = f.org_id
+		 LEFT JOIN agents a ON a.org_id = d.org_id AND a.agent_id = d.agent_id
+		 WHERE f.org_id = $1 AND f.reviewed_at IS NULL AND f.routed_at IS NULL
+		   AND d.valid_to IS NULL
+		 ORDER BY f.flagged_at ASC, f.decision_id ASC
+		 LIMIT $2`,
+		orgID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list unrouted review flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []UnroutedReviewFlag
+	for rows.Next() {
+		var f UnroutedReviewFlag
+		if err := rows.Scan(&f.DecisionID, &f.AgentID, &f.AgentTags); err != nil {
+			return nil, fmt.Errorf("storage: scan unrouted review flag: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	return flags, rows.Err()
+}
+
+// SetReviewAssignments records routing for pending flags. decisionIDs and
+// reviewers are parallel; an empty reviewer marks the flag routed with no
+// assignee. Flags already routed or reviewed are left untouched.
+func (db *DB) SetReviewAssignments(ctx context.Context, orgID uuid.UUID, decisionIDs []uuid.UUID, reviewers []string) error {
+	if len(decisionIDs) != len(reviewers) {
+		return fmt.Errorf("storage: set review assignments: %d decisions, %d reviewers", len(decisionIDs), len(reviewers))
+	}
+	if len(decisionIDs) == 0 {
+		return nil
+	}
+	_, err := db.pool.Exec(ctx,
+		`UPDATE decision_review_flags AS f
+		 SET assigned_reviewer = NULLIF(a.reviewer, ''), routed_at = now()
+		 FROM unnest($2::uuid[], $3::text[]) AS a(decision_id, reviewer)
+		 WHERE f.decision_id = a.decision_id AND f.org_id = $1
+		   AND f.reviewed_at IS NULL AND f.routed_at IS NULL`,
+		orgID, decisionIDs, reviewers,
+	)
+	if err != nil {
+		return fmt.Errorf("storage: set review assignments: %w", err)
+	}
+	return nil
+}
+
+// reviewRoutingBatch bounds how many flags RouteReviewFlags loads at once.
+const reviewRoutingBatch = 500
+
+// RouteReviewFlags assigns reviewers to the org's unrouted pending flags per
+// policy and returns how many were routed. A nil policy routes nothing, so
+// flags stay unrouted until one is configured.
+func (db *DB) RouteReviewFlags(ctx context.Context, orgID uuid.UUID, policy *model.ReviewRoutingPolicy) (int, error) {
+	if policy == nil {
+		return 0, nil
+	}
+	var routed int
+	for {
+		flags, err := db.ListUnroutedReviewFlags(ctx, orgID, reviewRoutingBatch)
+		if err != nil {
+			return routed, err
+		}
+		ids := make([]uuid.UUID, len(flags))
+		reviewers := make([]string, len(flags))
+		for i, f := range flags {
+			ids[i] = f.DecisionID
+			reviewers[i] = policy.ReviewerFor(f.AgentID, f.AgentTags)
+		}
+		if err := db.SetReviewAssignments(ctx, orgID, ids, reviewers); err != nil {
+			return routed, err
+		}
+		routed += len(flags)
+		if len(flags) < reviewRoutingBatch {
+			return routed, nil
+		}
+	}
+}
+
+// ListOrgsWithUnroutedReviewFlags returns the orgs that have pending flags
+// not yet through reviewer routing.
+func (db *DB) ListOrgsWithUnroutedReviewFlags(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT DISTINCT org_id FROM decision_review_flags
+		 WHERE reviewed_at IS NULL AND routed_at IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list orgs with unrouted review flags: %w", err)
+	}
+	defer rows.Close()
+
+	var orgIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("storage: scan org id: %w", err)
+		}
+		orgIDs = append(orgIDs, id)
+	}
+	return orgIDs, rows.Err()
+}
+
+// ReviewQueueFilters narrows ListReviewQueue.
+type ReviewQueueFilters struct {
+	SLA           time.Duration // Items flagged longer ago than this are overdue.
+	OverdueOnly   bool
+	Reason        string
+	AssignedTo    string     // Restricts to flags assigned to this reviewer agent.
+	RunID         *uuid.UUID // Restricts to decisions recorded in this run.
+	DecisionTypes []string   // Restricts to these decision types; empty = all.
+}
+
+// ListReviewQueue returns pending review flags on active decisions, oldest
+// first, with the total count before pagination.
+func (db *DB) ListReviewQueue(ctx context.Context, orgID uuid.UUID, filters ReviewQueueFilters, limit, offset int) ([]model.ReviewQueueItem, int, error) {
+	cutoff := time.Now().Add(-filters.SLA)
+	query := `SELECT ` + reviewFlagCols + `,
+		d.agent_id, d.decision_type, d.outcome, d.confidence, COUNT(*) OVER()
+		FROM decision_review_flags f
+		JOIN decisions d ON d.id = f.decision_id AND d.org_id = f.org_id
+		WHERE f.org_id = $1 AND f.reviewed_at IS NULL AND d.valid_to IS NULL`
+	args := []any{orgID}
+	if filters.OverdueOnly {
+		args = append(args, cutoff)
+		query += fmt.Sprintf(` AND f.flagged_at < $%d`, len(args))
+	}
+	if filters.Reason != "" {
+		args = append(args, filters.Reason)
+		query += fmt.Sprintf(` AND f.reason = $%d`, len(args))
+	}
+	if filters.AssignedTo != "" {
+		args = append(args, filters.AssignedTo)
+		query += fmt.Sprintf(` AND f.assigned_reviewer = $%d`, len(args))
+	}
+	if filters.RunID != nil {
+		args = append(args, *filters.RunID)
+		query += fmt.Sprintf(` AND d.run_id = $%d`, len(args))
+	}
+	if len(filters.DecisionTypes) > 0 {
+		args = append(args, filters.DecisionTypes)
+		query += fmt.Sprintf(` AND d.decision_type = ANY($%d)`, len(args))
+	}
+	args = append(args, limit, offset)
+	query += fmt.Sprintf(` ORDER BY f.flagged_at ASC, f.decision_id ASC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: list review queue: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	var (
+		items []model.ReviewQueueItem
+		total int
+	)
+	for rows.Next() {
+		var it model.ReviewQueueItem
+		f, err := scanReviewFlag(rows, &it.AgentID, &it.DecisionType, &it.Outcome, &it.Confidence, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("storage: scan review queue item: %w", err)
+		}
+		it.ReviewFlag = f
+		it.AgeSeconds = int64(now.Sub(f.FlaggedAt).Seconds())
+		it.Overdue = f.FlaggedAt.Before(cutoff)
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("storage: iterate review queue: %w", err)
+	}
+	return items, total, nil
+}
+
+// ClaimOverdueReviewFlags marks up to limit pending flags on active decisions
+// that were flagged before cutoff and not yet notified, and returns them.
+// Runs across all orgs for the background sweep; SKIP LOCKED lets concurrent
+// replicas split the work without double-notifying.
+func (db *DB) ClaimOverdueReviewFlags(ctx context.Context, cutoff time.Time, limit int) ([]model.ReviewFlag, error) {
+	rows, err := db.pool.Query(ctx,
+		`UPDATE decision_review_flags AS f SET overdue_notified_at = now()
+		 WHERE f.decision_id IN (
+		   SELECT p.decision_id FROM decision_review_flags p
+		   JOIN decisions d ON d.id = p.decision_id AND d.org_id = p.org_id
+		   WHERE p.reviewed_at IS NULL AND p.overdue_notified_at IS NULL
+		     AND p.flagged_at < $1 AND d.valid_to IS NULL
+		   ORDER BY p.flagged_at
+		   LIMIT $2
+		   FOR UPDATE OF p SKIP LOCKED
+		 )
+		 RETURNING `+reviewFlagCols,
+		cutoff, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: claim overdue review flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []model.ReviewFlag
+	for rows.Next() {
+		f, err := scanReviewFlag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("storage: scan overdue review flag: %w", err)
+		}
+		flags = append(flags, f)
+	}
+	return flags, rows.Err()
+}
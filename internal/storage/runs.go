@@ -92,7 +92,7 @@ func (db *DB) GetRun(ctx context.Context, orgID, id uuid.UUID) (model.AgentRun,
 	var run model.AgentRun
 	err := db.pool.QueryRow(ctx,
 		`SELECT id, agent_id, org_id, trace_id, parent_run_id, status, started_at, completed_at, metadata, created_at
-		 FROM agent_runs WHERE id = $1 AND org_id = $2`, id, orgID,
+		 FROM agent_runs WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID,
 	).Scan(
 		&run.ID, &run.AgentID, &run.OrgID, &run.TraceID, &run.ParentRunID,
 		&run.Status, &run.StartedAt, &run.CompletedAt, &run.Metadata, &run.CreatedAt,
@@ -134,7 +134,7 @@ func (db *DB) CompleteRun(ctx context.Context, orgID, id uuid.UUID, status model
 		}
 
 		// Idempotent success for retries when the run is already finalized.
-		if existingStatus == string(model.RunStatusCompleted) || existingStatus == string(model.RunStatusFailed) {
+		if model.RunStatus(existingStatus).IsTerminal() {
 			return nil
 		}
 		return fmt.Errorf("storage: run %s complete transition rejected from status %q", id, existingStatus)
@@ -171,7 +171,7 @@ func (db *DB) CompleteRunWithAudit(ctx context.Context, orgID, id uuid.UUID, sta
 				}
 				return fmt.Errorf("storage: complete run status lookup: %w", err)
 			}
-			if existingStatus == string(model.RunStatusCompleted) || existingStatus == string(model.RunStatusFailed) {
+			if model.RunStatus(existingStatus).IsTerminal() {
 				// Idempotent — already finalized. Return nil to commit.
 				return nil
 			}
@@ -192,7 +192,7 @@ func (db *DB) ListRunsByAgent(ctx context.Context, orgID uuid.UUID, agentID stri
 
 	var total int
 	err := db.pool.QueryRow(ctx,
-		`SELECT COUNT(*) FROM agent_runs WHERE org_id = $1 AND agent_id = $2`, orgID, agentID,
+		`SELECT COUNT(*) FROM agent_runs WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL`, orgID, agentID,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("storage: count runs: %w", err)
@@ -200,7 +200,7 @@ func (db *DB) ListRunsByAgent(ctx context.Context, orgID uuid.UUID, agentID stri
 
 	rows, err := db.pool.Query(ctx,
 		`SELECT id, agent_id, org_id, trace_id, parent_run_id, status, started_at, completed_at, metadata, created_at
-		 FROM agent_runs WHERE org_id = $1 AND agent_id = $2
+		 FROM agent_runs WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL
 		 ORDER BY started_at DESC
 		 LIMIT $3 OFFSET $4`,
 		orgID, agentID, limit, offset,
@@ -223,3 +223,85 @@ func (db *DB) ListRunsByAgent(ctx context.Context, orgID uuid.UUID, agentID stri
 	}
 	return runs, total, rows.Err()
 }
+
+// CloseIdleRuns moves up to limit running runs that have had no events or
+// decisions since cutoff (and started before it) to status, writing one
+// mutation audit entry per run in the same transaction. Runs locked by a
+// concurrent writer are skipped until the next sweep. Returns the number
+// of runs closed.
+func (db *DB) CloseIdleRuns(ctx context.Context, cutoff time.Time, status model.RunStatus, limit int) (int, error) {
+	if err := model.RunStatusRunning.ValidateTransition(status); err != nil {
+		return 0, fmt.Errorf("storage: close idle runs: %w", err)
+	}
+
+	closed := 0
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		now := time.Now().UTC()
+		rows, err := tx.Query(ctx,
+			`WITH idle AS (
+			     SELECT r.id FROM agent_runs r
+			      WHERE r.status = 'running' AND r.started_at < $1
+			        AND NOT EXISTS (SELECT 1 FROM agent_events e
+			                         WHERE e.run_id = r.id AND e.occurred_at >= $1)
+			        AND NOT EXISTS (SELECT 1 FROM decisions d
+			                         WHERE d.run_id = r.id AND d.created_at >= $1)
+			      ORDER BY r.started_at
+			      LIMIT $4
+			      FOR UPDATE SKIP LOCKED
+			 )
+			 UPDATE agent_runs r
+			    SET status = $2, completed_at = $3,
+			        metadata = r.metadata || jsonb_build_object('closed_idle_since', $1::timestamptz)
+			   FROM idle
+			  WHERE r.id = idle.id
+			 RETURNING r.id, r.org_id, r.agent_id`,
+			cutoff, string(status), now, limit,
+		)
+		if err != nil {
+			return fmt.Errorf("storage: close idle runs: %w", err)
+		}
+		type closedRun struct {
+			id      uuid.UUID
+			orgID   uuid.UUID
+			agentID string
+		}
+		var runs []closedRun
+		for rows.Next() {
+			var r closedRun
+			if err := rows.Scan(&r.id, &r.orgID, &r.agentID); err != nil {
+				rows.Close()
+				return fmt.Errorf("storage: scan idle run: %w", err)
+			}
+			runs = append(runs, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("storage: close idle runs: %w", err)
+		}
+
+		for _, r := range runs {
+			if err := InsertMutationAuditTx(ctx, tx, MutationAuditEntry{
+				RequestID:    uuid.New().String(),
+				OrgID:        r.orgID,
+				ActorAgentID: "system:run_idle_sweep",
+				ActorRole:    "system",
+				HTTPMethod:   "SYSTEM",
+				Endpoint:     "run_idle_sweep_loop",
+				Operation:    "close_idle_run",
+				ResourceType: "agent_run",
+				ResourceID:   r.id.String(),
+				BeforeData:   map[string]any{"status": string(model.RunStatusRunning)},
+				AfterData:    map[string]any{"status": string(status), "completed_at": now},
+				Metadata:     map[string]any{"agent_id": r.agentID, "idle_since": cutoff},
+			}); err != nil {
+				return fmt.Errorf("storage: audit idle run %s: %w", r.id, err)
+			}
+		}
+		closed = len(runs)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return closed, nil
+}
@@ -0,0 +1,87 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ashita-ai/akashi/internal/model"
+)
+
+// ListDeadOutbox returns an org's dead-lettered search outbox entries, most
+// recently dead-lettered first, with the total count.
+// limit is clamped to [1, 1000] with a default of 50; offset must be non-negative.
+func (db *DB) ListDeadOutbox(ctx context.Context, orgID uuid.UUID, limit, offset int) ([]model.DeadOutboxEntry, int, error) {
+	limit, offset = clampPagination(limit, offset, 50, 1000)
+	var total int
+	if err := db.pool.QueryRow(ctx,
+		`SELECT count(*) FROM search_outbox_dead WHERE org_id = $1`, orgID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("storage: count dead outbox entries: %w", err)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`SELECT outbox_id, decision_id, org_id, operation, attempts, last_error, created_at, dead_at
+		 FROM search_outbox_dead
+		 WHERE org_id = $1
+		 ORDER BY dead_at DESC, outbox_id DESC
+		 LIMIT $2 OFFSET $3`, orgID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: list dead outbox entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]model.DeadOutboxEntry, 0)
+	for rows.Next() {
+		var e model.DeadOutboxEntry
+		if err := rows.Scan(&e.OutboxID, &e.DecisionID, &e.OrgID, &e.Operation,
+			&e.Attempts, &e.LastError, &e.CreatedAt, &e.DeadAt); err != nil {
+			return nil, 0, fmt.Errorf("storage: scan dead outbox entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
+
+// RequeueDeadOutboxWithAudit moves dead-lettered entries back into
+// search_outbox with their attempts reset, so the outbox worker retries them.
+// A nil outboxIDs requeues every dead entry in the org; IDs belonging to other
+// orgs are ignored. Returns the number of entries requeued. The
+// search_outbox_dead_letters archive is left untouched.
+func (db *DB) RequeueDeadOutboxWithAudit(ctx context.Context, orgID uuid.UUID, outboxIDs []int64, audit MutationAuditEntry) (int, error) {
+	var requeued int
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// The queued CTE runs even though the outer SELECT does not read it.
+		if err := tx.QueryRow(ctx,
+			`WITH requeued AS (
+			    DELETE FROM search_outbox_dead
+			    WHERE org_id = $1 AND ($2::bigint[] IS NULL OR outbox_id = ANY($2))
+			    RETURNING decision_id, org_id, operation
+			),
+			queued AS (
+			    INSERT INTO search_outbox (decision_id, org_id, operation)
+			    SELECT DISTINCT decision_id, org_id, operation FROM requeued
+			    ON CONFLICT (decision_id, operation) DO UPDATE SET created_at = now(), attempts = 0, locked_until = NULL
+			)
+			SELECT count(*) FROM requeued`,
+			orgID, outboxIDs,
+		).Scan(&requeued); err != nil {
+			return fmt.Errorf("storage: requeue dead outbox entries: %w", err)
+		}
+
+		audit.AfterData = model.RequeueDeadOutboxResponse{Requeued: requeued}
+		if err := InsertMutationAuditTx(ctx, tx, audit); err != nil {
+			return fmt.Errorf("storage: audit in requeue dead outbox tx: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return requeued, nil
+}
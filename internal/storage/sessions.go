@@ -4,9 +4,11 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/ashita-ai/akashi/internal/model"
 )
@@ -28,3 +30,163 @@ func (db *DB) GetSessionDecisions(ctx context.Context, orgID, sessionID uuid.UUI
 
 	return scanDecisions(rows)
 }
+
+// GetBatchDecisions returns all active decisions sharing a batch_id within an
+// org, ordered chronologically (oldest first).
+func (db *DB) GetBatchDecisions(ctx context.Context, orgID, batchID uuid.UUID) ([]model.Decision, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+decisionCols+`
+		 FROM decisions
+		 WHERE org_id = $1 AND batch_id = $2 AND valid_to IS NULL
+		 ORDER BY valid_from ASC`,
+		orgID, batchID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: get batch decisions: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDecisions(rows)
+}
+
+// ListSessions groups an agent's current decisions by session_id, most
+// recently active session first, and returns one page along with the total
+// number of sessions. Decisions without a session are not counted.
+func (db *DB) ListSessions(ctx context.Context, orgID uuid.UUID, agentID string, limit, offset int) ([]model.SessionSummary, int, error) {
+	var total int
+	if err := db.pool.QueryRow(ctx,
+		`SELECT count(DISTINCT session_id)
+		 FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND session_id IS NOT NULL AND valid_to IS NULL`,
+		orgID, agentID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("storage: count sessions: %w", err)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`SELECT session_id, count(*), min(valid_from), max(valid_from),
+		        array_agg(DISTINCT decision_type ORDER BY decision_type)
+		 FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND session_id IS NOT NULL AND valid_to IS NULL
+		 GROUP BY session_id
+		 ORDER BY max(valid_from) DESC, session_id
+		 LIMIT $3 OFFSET $4`,
+		orgID, agentID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("storage: list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := make([]model.SessionSummary, 0)
+	for rows.Next() {
+		var s model.SessionSummary
+		if err := rows.Scan(&s.SessionID, &s.DecisionCount, &s.StartedAt, &s.EndedAt, &s.DecisionTypes); err != nil {
+			return nil, 0, fmt.Errorf("storage: scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("storage: iterate sessions: %w", err)
+	}
+	return sessions, total, nil
+}
+
+const agentSessionCols = `id, org_id, agent_id, started_at, closed_at`
+
+func scanAgentSession(row pgx.Row) (model.AgentSession, error) {
+	var s model.AgentSession
+	err := row.Scan(&s.SessionID, &s.OrgID, &s.AgentID, &s.StartedAt, &s.ClosedAt)
+	return s, err
+}
+
+// StartAgentSession opens a new session for agentID, closing the agent's
+// previously open session if there is one.
+func (db *DB) StartAgentSession(ctx context.Context, orgID uuid.UUID, agentID string) (model.AgentSession, error) {
+	var s model.AgentSession
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Lock the agent row so concurrent starts for the same agent serialize
+		// instead of racing on the one-open-session index.
+		var one int
+		if err := tx.QueryRow(ctx,
+			`SELECT 1 FROM agents WHERE org_id = $1 AND agent_id = $2 FOR UPDATE`,
+			orgID, agentID,
+		).Scan(&one); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("storage: agent %s: %w", agentID, ErrNotFound)
+			}
+			return fmt.Errorf("storage: lock agent for session: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE agent_sessions SET closed_at = now()
+			 WHERE org_id = $1 AND agent_id = $2 AND closed_at IS NULL`,
+			orgID, agentID,
+		); err != nil {
+			return fmt.Errorf("storage: close previous session: %w", err)
+		}
+		var err error
+		s, err = scanAgentSession(tx.QueryRow(ctx,
+			`INSERT INTO agent_sessions (org_id, agent_id) VALUES ($1, $2)
+			 RETURNING `+agentSessionCols,
+			orgID, agentID,
+		))
+		if err != nil {
+			return fmt.Errorf("storage: start session: %w", err)
+		}
+		return nil
+	})
+	return s, err
+}
+
+// GetAgentSession returns a session opened with StartAgentSession.
+// Returns ErrNotFound if no such session exists in the org.
+func (db *DB) GetAgentSession(ctx context.Context, orgID, sessionID uuid.UUID) (model.AgentSession, error) {
+	s, err := scanAgentSession(db.pool.QueryRow(ctx,
+		`SELECT `+agentSessionCols+` FROM agent_sessions WHERE id = $1 AND org_id = $2`,
+		sessionID, orgID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.AgentSession{}, fmt.Errorf("storage: session %s: %w", sessionID, ErrNotFound)
+		}
+		return model.AgentSession{}, fmt.Errorf("storage: get session: %w", err)
+	}
+	return s, nil
+}
+
+// GetOpenAgentSessionID returns the ID of the agent's open session, or nil
+// when none is open.
+func (db *DB) GetOpenAgentSessionID(ctx context.Context, orgID uuid.UUID, agentID string) (*uuid.UUID, error) {
+	var id uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`SELECT id FROM agent_sessions
+		 WHERE org_id = $1 AND agent_id = $2 AND closed_at IS NULL`,
+		orgID, agentID,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("storage: get open session: %w", err)
+	}
+	return &id, nil
+}
+
+// CloseAgentSession closes an open session. Closing an already-closed
+// session is a no-op that returns it unchanged.
+// Returns ErrNotFound if no such session exists in the org.
+func (db *DB) CloseAgentSession(ctx context.Context, orgID, sessionID uuid.UUID) (model.AgentSession, error) {
+	s, err := scanAgentSession(db.pool.QueryRow(ctx,
+		`UPDATE agent_sessions SET closed_at = COALESCE(closed_at, now())
+		 WHERE id = $1 AND org_id = $2
+		 RETURNING `+agentSessionCols,
+		sessionID, orgID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.AgentSession{}, fmt.Errorf("storage: session %s: %w", sessionID, ErrNotFound)
+		}
+		return model.AgentSession{}, fmt.Errorf("storage: close session: %w", err)
+	}
+	return s, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/google/uuid"
 
@@ -134,6 +135,51 @@ func (l *LiteDB) ListAgentIDsBySharedTags(ctx context.Context, orgID uuid.UUID,
 	return ids, rows.Err()
 }
 
+// SuggestAgentIDs returns up to limit agent_ids in the org that look like
+// misspellings of agentID, most similar first. SQLite has no pg_trgm, so the
+// org's agent_ids are scored in Go with the same trigram similarity.
+// Returns nil when agentID itself exists.
+func (l *LiteDB) SuggestAgentIDs(ctx context.Context, orgID uuid.UUID, agentID string, limit int) ([]string, error) {
+	rows, err := l.db.QueryContext(ctx, `SELECT agent_id FROM agents WHERE org_id = ?`, uuidStr(orgID))
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: suggest agent ids: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	type candidate struct {
+		id    string
+		score float64
+	}
+	var candidates []candidate
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("sqlite: scan agent_id: %w", err)
+		}
+		if id == agentID {
+			return nil, nil
+		}
+		if score := trigramSimilarity(id, agentID); score >= storage.AgentSuggestionMinSimilarity {
+			candidates = append(candidates, candidate{id, score})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite: suggest agent ids: %w", err)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].id < candidates[j].id
+	})
+	var ids []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		ids = append(ids, candidates[i].id)
+	}
+	return ids, nil
+}
+
 // scanAgent scans a single agent row.
 func scanAgent(row *sql.Row) (model.Agent, error) {
 	var (
@@ -625,6 +625,10 @@ func conflictWhere(orgID uuid.UUID, f storage.ConflictFilters) (string, []any) {
 		conds = append(conds, "sc.category = ?")
 		args = append(args, *f.Category)
 	}
+	if f.ReasonCode != nil {
+		// Lite mode does not classify conflict reasons.
+		conds = append(conds, "0 = 1")
+	}
 	if f.DecisionID != nil {
 		conds = append(conds, "(sc.decision_a_id = ? OR sc.decision_b_id = ?)")
 		args = append(args, uuidStr(*f.DecisionID), uuidStr(*f.DecisionID))
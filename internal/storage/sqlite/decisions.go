@@ -3,7 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -34,6 +37,31 @@ func (l *LiteDB) CreateTraceTx(ctx context.Context, params storage.CreateTracePa
 	return run, dec, nil
 }
 
+// CreateTracesTx creates several traces in a single transaction, all or nothing.
+func (l *LiteDB) CreateTracesTx(ctx context.Context, params []storage.CreateTraceParams) ([]model.AgentRun, []model.Decision, error) {
+	tx, err := l.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sqlite: begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	runs := make([]model.AgentRun, 0, len(params))
+	decs := make([]model.Decision, 0, len(params))
+	for i, p := range params {
+		run, dec, err := createTraceInTx(ctx, tx, p)
+		if err != nil {
+			return nil, nil, &storage.TraceBatchError{Index: i, Err: err}
+		}
+		runs = append(runs, run)
+		decs = append(decs, dec)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("sqlite: commit traces: %w", err)
+	}
+	return runs, decs, nil
+}
+
 // CreateTraceAndAdjudicateConflictTx creates a trace and resolves a conflict atomically.
 func (l *LiteDB) CreateTraceAndAdjudicateConflictTx(ctx context.Context, traceParams storage.CreateTraceParams, conflictParams storage.AdjudicateConflictInTraceParams) (model.AgentRun, model.Decision, error) {
 	tx, err := l.db.BeginTx(ctx, nil)
@@ -249,6 +277,13 @@ func createTraceInTx(ctx context.Context, tx *sql.Tx, p storage.CreateTraceParam
 
 	d.Alternatives = p.Alternatives
 	d.Evidence = p.Evidence
+
+	// 7. Caller hook (e.g. blocking audit sink write-through).
+	if p.BeforeCommit != nil {
+		if err := p.BeforeCommit(ctx, d); err != nil {
+			return model.AgentRun{}, model.Decision{}, fmt.Errorf("sqlite: before commit in trace tx: %w", err)
+		}
+	}
 	return run, d, nil
 }
 
@@ -285,8 +320,8 @@ func (l *LiteDB) QueryDecisions(ctx context.Context, orgID uuid.UUID, req model.
 
 	// Use COUNT(*) OVER() window function to get the total count alongside data
 	// rows in a single query, eliminating a separate COUNT(*) table scan.
-	q := fmt.Sprintf("SELECT %s, COUNT(*) OVER() FROM decisions %s ORDER BY %s %s LIMIT ? OFFSET ?", //nolint:gosec // G201: interpolated values are sanitized constants
-		decisionCols, where, orderCol, orderDir)
+	q := fmt.Sprintf("SELECT %s, COUNT(*) OVER() FROM decisions %s ORDER BY %s %s, valid_from %s LIMIT ? OFFSET ?", //nolint:gosec // G201: interpolated values are sanitized constants
+		decisionCols, where, orderCol, orderDir, orderDir)
 	args = append(args, limit, offset)
 
 	rows, err := l.db.QueryContext(ctx, q, args...)
@@ -393,6 +428,33 @@ func (l *LiteDB) GetDecisionsByAgent(ctx context.Context, orgID uuid.UUID, agent
 	return l.QueryDecisions(ctx, orgID, req)
 }
 
+// FindSupersedeMatch returns the ID of the most recent active decision by
+// agentID with the given decision type whose metadata has every key/value pair
+// in match. The local store has no namespace column, so namespace is ignored.
+func (l *LiteDB) FindSupersedeMatch(ctx context.Context, orgID uuid.UUID, agentID, _ string, decisionType string, match map[string]any) (*uuid.UUID, error) {
+	var idStr string
+	err := l.db.QueryRowContext(ctx,
+		`SELECT d.id FROM decisions d
+		 WHERE d.org_id = ? AND d.agent_id = ? AND d.decision_type = ? AND d.valid_to IS NULL
+		   AND (SELECT COUNT(*) FROM json_each(d.metadata) m
+		        JOIN json_each(?) p ON p.key = m.key AND p.type = m.type AND p.value IS m.value) = ?
+		 ORDER BY d.valid_from DESC, d.id DESC
+		 LIMIT 1`,
+		uuidStr(orgID), agentID, decisionType, jsonStr(match), len(match),
+	).Scan(&idStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("sqlite: find supersede match: %w", err)
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: parse supersede match id: %w", err)
+	}
+	return &id, nil
+}
+
 // GetDecisionForScoring returns a single decision with embedding fields for conflict scoring.
 func (l *LiteDB) GetDecisionForScoring(ctx context.Context, id, orgID uuid.UUID) (model.Decision, error) {
 	row := l.db.QueryRowContext(ctx,
@@ -441,6 +503,8 @@ func (l *LiteDB) GetDecisionForScoring(ctx context.Context, id, orgID uuid.UUID)
 	if project.Valid {
 		d.Project = &project.String
 	}
+	// The local store has no namespace column; everything lives in the default.
+	d.Namespace = model.DefaultNamespace
 	return d, nil
 }
 
@@ -676,9 +740,11 @@ func buildDecisionWhere(orgID uuid.UUID, f model.QueryFilters, traceID *string)
 		conds = append(conds, "run_id = ?")
 		args = append(args, uuidStr(*f.RunID))
 	}
-	if f.DecisionType != nil {
-		conds = append(conds, "decision_type = ?")
-		args = append(args, *f.DecisionType)
+	if types := f.DecisionTypeList(); len(types) > 0 {
+		conds = append(conds, fmt.Sprintf("decision_type IN (%s)", placeholders(len(types))))
+		for _, t := range types {
+			args = append(args, t)
+		}
 	}
 	if f.ConfidenceMin != nil {
 		conds = append(conds, "confidence >= ?")
@@ -688,10 +754,32 @@ func buildDecisionWhere(orgID uuid.UUID, f model.QueryFilters, traceID *string)
 		conds = append(conds, "outcome = ?")
 		args = append(args, *f.Outcome)
 	}
+	if f.OutcomeFlipped != nil && *f.OutcomeFlipped {
+		// Lite mode does not record outcome flips, so no decision matches.
+		conds = append(conds, "0 = 1")
+	}
 	if f.SessionID != nil {
 		conds = append(conds, "session_id = ?")
 		args = append(args, uuidStr(*f.SessionID))
 	}
+	if f.BatchID != nil {
+		// Lite mode does not batch decisions, so no decision matches.
+		conds = append(conds, "0 = 1")
+	}
+	if f.TemperatureMin != nil || f.TemperatureMax != nil {
+		// Lite mode does not store context snapshots, so no decision matches.
+		conds = append(conds, "0 = 1")
+	}
+	if f.HasEvidence != nil {
+		conds = append(conds, existsCond(*f.HasEvidence, "SELECT 1 FROM evidence ev WHERE ev.decision_id = decisions.id"))
+	}
+	if f.HasAlternatives != nil {
+		conds = append(conds, existsCond(*f.HasAlternatives, "SELECT 1 FROM alternatives alt WHERE alt.decision_id = decisions.id"))
+	}
+	if len(f.Tags) > 0 {
+		// Lite mode does not store decision tags, so no decision matches.
+		conds = append(conds, "0 = 1")
+	}
 	if f.Tool != nil {
 		conds = append(conds, "tool = ?")
 		args = append(args, *f.Tool)
@@ -704,6 +792,10 @@ func buildDecisionWhere(orgID uuid.UUID, f model.QueryFilters, traceID *string)
 		conds = append(conds, "project = ?")
 		args = append(args, *f.Project)
 	}
+	for _, key := range slices.Sorted(maps.Keys(f.ContextFilters)) {
+		conds = append(conds, "json_extract(agent_context, ?) = ?")
+		args = append(args, contextJSONPath(key), f.ContextFilters[key])
+	}
 	if f.TimeRange != nil {
 		if f.TimeRange.From != nil {
 			conds = append(conds, "valid_from >= ?")
@@ -722,6 +814,20 @@ func buildDecisionWhere(orgID uuid.UUID, f model.QueryFilters, traceID *string)
 	return "WHERE " + strings.Join(conds, " AND "), args
 }
 
+// contextJSONPath converts a dotted ContextFilters key into a SQLite JSON
+// path: "client.task" becomes $."client"."task".
+func contextJSONPath(key string) string {
+	return `$."` + strings.Join(model.ContextFilterPath(key), `"."`) + `"`
+}
+
+// existsCond renders "EXISTS (subquery)", or "NOT EXISTS" when want is false.
+func existsCond(want bool, subquery string) string {
+	if want {
+		return "EXISTS (" + subquery + ")"
+	}
+	return "NOT EXISTS (" + subquery + ")"
+}
+
 // buildDecisionFilterWhere builds additional filter conditions for an aliased decisions table.
 // Returns the extra AND clauses (without leading AND) and args.
 func buildDecisionFilterWhere(alias string, orgID uuid.UUID, f model.QueryFilters) (string, []any) {
@@ -734,9 +840,11 @@ func buildDecisionFilterWhere(alias string, orgID uuid.UUID, f model.QueryFilter
 			args = append(args, id)
 		}
 	}
-	if f.DecisionType != nil {
-		conds = append(conds, fmt.Sprintf("%s.decision_type = ?", alias))
-		args = append(args, *f.DecisionType)
+	if types := f.DecisionTypeList(); len(types) > 0 {
+		conds = append(conds, fmt.Sprintf("%s.decision_type IN (%s)", alias, placeholders(len(types))))
+		for _, t := range types {
+			args = append(args, t)
+		}
 	}
 	if f.ConfidenceMin != nil {
 		conds = append(conds, fmt.Sprintf("%s.confidence >= ?", alias))
@@ -768,11 +876,22 @@ func sanitizeOrderCol(col string) string {
 	switch strings.ToLower(col) {
 	case "valid_from", "created_at", "confidence", "completeness_score", "outcome_score", "decision_type":
 		return col
+	case "conflict_count":
+		return openConflictCountSQL
+	case "triage":
+		// Same blend as the PostgreSQL backend: (1 + open conflicts) / (1 + age in days).
+		return "(1.0 + " + openConflictCountSQL + ") / (1.0 + MAX(julianday('now') - julianday(valid_from), 0))"
 	default:
 		return "valid_from"
 	}
 }
 
+// openConflictCountSQL counts a decision's open scored conflicts, correlated
+// on the outer decisions row.
+const openConflictCountSQL = `(SELECT COUNT(*) FROM scored_conflicts sc
+	WHERE sc.org_id = decisions.org_id AND sc.status = 'open'
+	  AND (sc.decision_a_id = decisions.id OR sc.decision_b_id = decisions.id))`
+
 // ---- Row scanning ----
 
 // scanDecisionRows scans multiple decision rows (25 columns matching decisionCols).
@@ -869,6 +988,8 @@ func scanOneDecision(row rowScanner) (model.Decision, error) {
 	if project.Valid {
 		d.Project = &project.String
 	}
+	// The local store has no namespace column; everything lives in the default.
+	d.Namespace = model.DefaultNamespace
 	return d, nil
 }
 
@@ -929,6 +1050,8 @@ func scanOneDecisionWithTotal(row rowScanner) (model.Decision, int, error) {
 	if project.Valid {
 		d.Project = &project.String
 	}
+	// The local store has no namespace column; everything lives in the default.
+	d.Namespace = model.DefaultNamespace
 	return d, total, nil
 }
 
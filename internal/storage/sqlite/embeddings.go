@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -76,8 +77,9 @@ func (l *LiteDB) FindUnembeddedDecisions(ctx context.Context, limit int) ([]stor
 	return result, rows.Err()
 }
 
-// BackfillEmbedding updates a decision's embedding.
-func (l *LiteDB) BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector) error {
+// BackfillEmbedding updates a decision's embedding. Lite mode runs a single
+// local embedding model and does not record embeddingModel.
+func (l *LiteDB) BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector, _ string) error {
 	_, err := l.db.ExecContext(ctx,
 		`UPDATE decisions SET embedding = ? WHERE id = ? AND org_id = ? AND valid_to IS NULL`,
 		vectorToBlob(&emb), uuidStr(id), uuidStr(orgID),
@@ -130,3 +132,55 @@ func (l *LiteDB) BackfillOutcomeEmbedding(ctx context.Context, id, orgID uuid.UU
 	}
 	return nil
 }
+
+// FindUnscoredDecisions returns active decisions with a zero completeness_score
+// and an ID greater than after, in ID order.
+func (l *LiteDB) FindUnscoredDecisions(ctx context.Context, after uuid.UUID, limit int) ([]storage.UnscoredDecision, error) {
+	rows, err := l.db.QueryContext(ctx,
+		`SELECT d.id, d.org_id, d.outcome, d.confidence, d.reasoning, d.precedent_ref IS NOT NULL,
+		        (SELECT json_group_array(a.rejection_reason) FROM alternatives a
+		         WHERE a.decision_id = d.id AND a.rejection_reason IS NOT NULL),
+		        (SELECT COUNT(*) FROM evidence e WHERE e.decision_id = d.id)
+		 FROM decisions d
+		 WHERE d.valid_to IS NULL AND d.completeness_score = 0 AND d.id > ?
+		 ORDER BY d.id LIMIT ?`,
+		uuidStr(after), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: find unscored decisions: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var result []storage.UnscoredDecision
+	for rows.Next() {
+		var (
+			d       storage.UnscoredDecision
+			idStr   string
+			orgStr  string
+			reasons string
+		)
+		if err := rows.Scan(&idStr, &orgStr, &d.Outcome, &d.Confidence, &d.Reasoning,
+			&d.HasPrecedentRef, &reasons, &d.EvidenceCount); err != nil {
+			return nil, fmt.Errorf("sqlite: scan unscored decision: %w", err)
+		}
+		if err := json.Unmarshal([]byte(reasons), &d.RejectionReasons); err != nil {
+			return nil, fmt.Errorf("sqlite: decode rejection reasons: %w", err)
+		}
+		d.ID = parseUUID(idStr)
+		d.OrgID = parseUUID(orgStr)
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+// BackfillCompletenessScore sets an active decision's completeness_score.
+func (l *LiteDB) BackfillCompletenessScore(ctx context.Context, id, orgID uuid.UUID, score float32) error {
+	_, err := l.db.ExecContext(ctx,
+		`UPDATE decisions SET completeness_score = ? WHERE id = ? AND org_id = ? AND valid_to IS NULL`,
+		score, uuidStr(id), uuidStr(orgID),
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: backfill completeness score: %w", err)
+	}
+	return nil
+}
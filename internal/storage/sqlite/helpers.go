@@ -8,6 +8,7 @@ import (
 	"math"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
@@ -162,3 +163,35 @@ func uuidSliceToJSON(ids []uuid.UUID) string {
 	}
 	return "[" + strings.Join(strs, ",") + "]"
 }
+
+// trigramSimilarity mirrors pg_trgm's similarity(): both strings are
+// lowercased and split into alphanumeric words, each word is padded with two
+// leading spaces and one trailing space, and the result is the number of
+// shared trigrams divided by the size of the union.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
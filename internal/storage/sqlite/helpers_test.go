@@ -224,3 +224,13 @@ func TestParseUUID_Invalid(t *testing.T) {
 	got := parseUUID("not-valid")
 	assert.Equal(t, uuid.Nil, got)
 }
+
+func TestTrigramSimilarity(t *testing.T) {
+	// Reference values from PostgreSQL: SELECT similarity(a, b).
+	assert.InDelta(t, 1.0, trigramSimilarity("Agent", "agent"), 1e-9)
+	assert.InDelta(t, 0.0, trigramSimilarity("", "agent"), 1e-9)
+	assert.InDelta(t, 0.0, trigramSimilarity("abc", "xyz"), 1e-9)
+	// "word" has trigrams {"  w"," wo","wor","ord","rd "}; "words" shares 4 of 7.
+	assert.InDelta(t, 4.0/7.0, trigramSimilarity("word", "words"), 1e-9)
+	assert.Greater(t, trigramSimilarity("underwriting-agent", "underwritng-agent"), 0.5)
+}
@@ -138,6 +138,36 @@ func TestListAgentIDsBySharedTags(t *testing.T) {
 	assert.Nil(t, ids)
 }
 
+func TestSuggestAgentIDs(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.EnsureDefaultOrg(ctx))
+	orgID := uuid.Nil
+	now := time.Now().UTC()
+
+	for _, id := range []string{"underwriting-agent", "claims-agent", "pricing-bot"} {
+		_, err := db.CreateAgent(ctx, model.Agent{
+			AgentID: id, OrgID: orgID, Name: id, Role: model.RoleAgent,
+			Metadata: map[string]any{}, CreatedAt: now, UpdatedAt: now,
+		})
+		require.NoError(t, err)
+	}
+
+	ids, err := db.SuggestAgentIDs(ctx, orgID, "underwritng-agent", 3)
+	require.NoError(t, err)
+	require.NotEmpty(t, ids)
+	assert.Equal(t, "underwriting-agent", ids[0])
+	assert.NotContains(t, ids, "pricing-bot")
+
+	ids, err = db.SuggestAgentIDs(ctx, orgID, "underwriting-agent", 3)
+	require.NoError(t, err)
+	assert.Nil(t, ids, "an existing agent_id gets no suggestions")
+
+	ids, err = db.SuggestAgentIDs(ctx, orgID, "zzzz", 3)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
 func TestCreateTraceTx(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -760,6 +790,60 @@ func TestGetDecisionForScoring(t *testing.T) {
 	})
 }
 
+func TestFindSupersedeMatch(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.EnsureDefaultOrg(ctx))
+	orgID := uuid.Nil
+
+	_, err := db.CreateAgent(ctx, model.Agent{
+		AgentID: "standing-agent", OrgID: orgID, Name: "S", Role: model.RoleAgent,
+		Tags: []string{}, Metadata: map[string]any{},
+		CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	trace := func(entity string, supersedes *uuid.UUID) model.Decision {
+		meta := map[string]any{"entity": entity, "region": "eu"}
+		_, d, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: "standing-agent", OrgID: orgID, Metadata: map[string]any{},
+			Decision: model.Decision{
+				DecisionType: "pricing", Outcome: "price for " + entity, Confidence: 0.8,
+				Metadata: meta, SupersedesID: supersedes,
+			},
+		})
+		require.NoError(t, err)
+		return d
+	}
+	first := trace("sku-1", nil)
+	trace("sku-2", nil)
+
+	id, err := db.FindSupersedeMatch(ctx, orgID, "standing-agent", "", "pricing", map[string]any{"entity": "sku-1", "region": "eu"})
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	assert.Equal(t, first.ID, *id)
+
+	// Once superseded, the revision is the standing decision.
+	second := trace("sku-1", id)
+	id, err = db.FindSupersedeMatch(ctx, orgID, "standing-agent", "", "pricing", map[string]any{"entity": "sku-1"})
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	assert.Equal(t, second.ID, *id)
+
+	id, err = db.FindSupersedeMatch(ctx, orgID, "standing-agent", "", "pricing", map[string]any{"entity": "sku-3"})
+	require.NoError(t, err)
+	assert.Nil(t, id)
+
+	id, err = db.FindSupersedeMatch(ctx, orgID, "standing-agent", "", "architecture", map[string]any{"entity": "sku-1"})
+	require.NoError(t, err)
+	assert.Nil(t, id)
+
+	// Another agent's matching trace must not supersede standing-agent's decision.
+	id, err = db.FindSupersedeMatch(ctx, orgID, "other-agent", "", "pricing", map[string]any{"entity": "sku-1"})
+	require.NoError(t, err)
+	assert.Nil(t, id)
+}
+
 func TestQueryDecisions_OrderByAndDirection(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -773,8 +857,9 @@ func TestQueryDecisions_OrderByAndDirection(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	var ids []uuid.UUID
 	for _, conf := range []float32{0.3, 0.7, 0.5} {
-		_, _, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
+		_, d, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
 			AgentID: "order-agent", OrgID: orgID, Metadata: map[string]any{},
 			Decision: model.Decision{
 				DecisionType: "test", Outcome: "o", Confidence: conf,
@@ -782,6 +867,7 @@ func TestQueryDecisions_OrderByAndDirection(t *testing.T) {
 			},
 		})
 		require.NoError(t, err)
+		ids = append(ids, d.ID)
 	}
 
 	t.Run("order by confidence asc", func(t *testing.T) {
@@ -805,6 +891,21 @@ func TestQueryDecisions_OrderByAndDirection(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, decisions, 3)
 	})
+
+	t.Run("order by open conflict count", func(t *testing.T) {
+		insertConflict(t, db, orgID, ids[2], ids[0], nil)
+		insertConflict(t, db, orgID, ids[1], ids[2], map[string]string{"status": "resolved"})
+		other := uuid.New()
+		insertConflict(t, db, orgID, other, ids[2], nil)
+
+		for _, orderBy := range []string{"conflict_count", "triage"} {
+			decisions, _, err := db.QueryDecisions(ctx, orgID, model.QueryRequest{OrderBy: orderBy, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, decisions, 3)
+			got := []uuid.UUID{decisions[0].ID, decisions[1].ID, decisions[2].ID}
+			assert.Equal(t, []uuid.UUID{ids[2], ids[0], ids[1]}, got, orderBy)
+		}
+	})
 }
 
 func TestQueryDecisions_WithIncludeAlternativesAndEvidence(t *testing.T) {
@@ -1318,7 +1419,7 @@ func TestVectorBlobRoundTrip(t *testing.T) {
 	require.NoError(t, err)
 
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3, 0.4})
-	err = db.BackfillEmbedding(ctx, d.ID, orgID, emb)
+	err = db.BackfillEmbedding(ctx, d.ID, orgID, emb, "")
 	require.NoError(t, err)
 
 	outEmb := pgvector.NewVector([]float32{0.5, 0.6, 0.7, 0.8})
@@ -1343,6 +1444,62 @@ func TestVectorBlobRoundTrip(t *testing.T) {
 	assert.InDelta(t, 0.5, scored.OutcomeEmbedding.Slice()[0], 0.001)
 }
 
+func TestFindUnscoredDecisions_BackfillCompletenessScore(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.EnsureDefaultOrg(ctx))
+	orgID := uuid.Nil
+
+	_, err := db.CreateAgent(ctx, model.Agent{
+		AgentID: "unscored-agent", OrgID: orgID, Name: "U", Role: model.RoleAgent,
+		Tags: []string{}, Metadata: map[string]any{},
+		CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	reason := "rejected because it cannot scale"
+	_, d, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: "unscored-agent", OrgID: orgID, Metadata: map[string]any{},
+		Decision: model.Decision{
+			DecisionType: "test", Outcome: "unscored-outcome", Confidence: 0.6,
+			Metadata: map[string]any{},
+		},
+		Alternatives: []model.Alternative{
+			{Label: "chosen", Metadata: map[string]any{}},
+			{Label: "rejected", RejectionReason: &reason, Metadata: map[string]any{}},
+		},
+		Evidence: []model.Evidence{
+			{SourceType: "document", Content: "one", Metadata: map[string]any{}},
+			{SourceType: "document", Content: "two", Metadata: map[string]any{}},
+		},
+	})
+	require.NoError(t, err)
+
+	unscored, err := db.FindUnscoredDecisions(ctx, uuid.Nil, 10)
+	require.NoError(t, err)
+	require.Len(t, unscored, 1)
+	got := unscored[0]
+	assert.Equal(t, d.ID, got.ID)
+	assert.Equal(t, "unscored-outcome", got.Outcome)
+	assert.False(t, got.HasPrecedentRef)
+	assert.Equal(t, []string{reason}, got.RejectionReasons)
+	assert.Equal(t, 2, got.EvidenceCount)
+
+	// Paging past the decision returns nothing.
+	unscored, err = db.FindUnscoredDecisions(ctx, d.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unscored)
+
+	require.NoError(t, db.BackfillCompletenessScore(ctx, d.ID, orgID, 0.42))
+	unscored, err = db.FindUnscoredDecisions(ctx, uuid.Nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unscored, "scored decision should no longer be returned")
+
+	stored, err := db.GetDecisionsByIDs(ctx, orgID, []uuid.UUID{d.ID})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.42, stored[d.ID].CompletenessScore, 0.001)
+}
+
 func TestFindDecisionsMissingOutcomeEmbedding_WithData(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -1367,7 +1524,7 @@ func TestFindDecisionsMissingOutcomeEmbedding_WithData(t *testing.T) {
 	require.NoError(t, err)
 
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb, ""))
 
 	results, err := db.FindDecisionsMissingOutcomeEmbedding(ctx, 10)
 	require.NoError(t, err)
@@ -2171,7 +2328,7 @@ func TestFindDecisionIDsMissingClaims_WithData(t *testing.T) {
 	require.NoError(t, err)
 
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb, ""))
 
 	refs, err := db.FindDecisionIDsMissingClaims(ctx, 10)
 	require.NoError(t, err)
@@ -2223,7 +2380,7 @@ func TestFindRetriableClaimFailures_WithData(t *testing.T) {
 	require.NoError(t, err)
 
 	emb := pgvector.NewVector([]float32{0.1, 0.2})
-	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, orgID, emb, ""))
 
 	require.NoError(t, db.MarkClaimEmbeddingFailed(ctx, d.ID, orgID))
 
@@ -2708,6 +2865,63 @@ func TestIdempotency_InProgressConcurrency(t *testing.T) {
 // TraceHealth — additional coverage
 // ---------------------------------------------------------------------------
 
+func TestAggregateDecisionStats(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.EnsureDefaultOrg(ctx))
+	orgID := uuid.Nil
+
+	for _, agent := range []string{"stats-a", "stats-b"} {
+		_, err := db.CreateAgent(ctx, model.Agent{
+			AgentID: agent, OrgID: orgID, Name: agent, Role: model.RoleAgent,
+			Tags: []string{}, Metadata: map[string]any{},
+			CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+		})
+		require.NoError(t, err)
+	}
+
+	for i, c := range []struct {
+		agent, decisionType string
+		at                  time.Time
+		confidence          float32
+	}{
+		{"stats-a", "architecture", time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), 0.6},
+		{"stats-a", "architecture", time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC), 0.8},
+		{"stats-b", "planning", time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), 0.5},
+	} {
+		_, _, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: c.agent, OrgID: orgID, Metadata: map[string]any{},
+			Decision: model.Decision{
+				DecisionType: c.decisionType, Outcome: fmt.Sprintf("stats %d", i),
+				Confidence: c.confidence, Metadata: map[string]any{}, ValidFrom: c.at,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	days, err := db.AggregateDecisionStats(ctx, orgID, storage.StatsGroupByDay, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, "2026-03-03", days[0].Key)
+	assert.Equal(t, "2026-03-01", days[1].Key)
+	assert.Equal(t, 2, days[1].Count)
+	assert.InDelta(t, 0.7, days[1].AvgConfidence, 0.001)
+
+	since := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	types, err := db.AggregateDecisionStats(ctx, orgID, storage.StatsGroupByDecisionType, &since, 10)
+	require.NoError(t, err)
+	require.Len(t, types, 1)
+	assert.Equal(t, "planning", types[0].Key)
+
+	agents, err := db.AggregateDecisionStats(ctx, orgID, storage.StatsGroupByAgent, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, agents, 1, "limit caps buckets")
+	assert.Equal(t, "stats-a", agents[0].Key, "largest group first")
+
+	_, err = db.AggregateDecisionStats(ctx, orgID, "week", nil, 10)
+	assert.Error(t, err)
+}
+
 func TestTraceHealth_WithDecisions(t *testing.T) {
 	db := newTestDB(t)
 	ctx := context.Background()
@@ -3137,7 +3351,7 @@ func TestBackfillEmbedding_RoundTrip(t *testing.T) {
 
 	// Backfill embedding.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb)
+	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb, "")
 	require.NoError(t, err)
 
 	// Should now be missing outcome embedding.
@@ -3378,7 +3592,7 @@ func TestFindDecisionIDsMissingClaims_WithEmbeddedDecision(t *testing.T) {
 
 	// Backfill embedding so the decision qualifies.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb)
+	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb, "")
 	require.NoError(t, err)
 
 	refs, err := db.FindDecisionIDsMissingClaims(ctx, 10)
@@ -3421,7 +3635,7 @@ func TestClaimEmbeddingFailureRoundTrip(t *testing.T) {
 
 	// Backfill embedding (required for FindRetriableClaimFailures).
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb)
+	err = db.BackfillEmbedding(ctx, dec.ID, orgID, emb, "")
 	require.NoError(t, err)
 
 	// Mark as failed.
@@ -4096,6 +4310,48 @@ func TestCreateTraceTx_WithAgentContext(t *testing.T) {
 	assert.NotEqual(t, uuid.Nil, dec.ID)
 }
 
+func TestQueryDecisions_ContextFilters(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.EnsureDefaultOrg(ctx))
+	orgID := uuid.Nil
+
+	trace := func(outcome string, agentCtx map[string]any) uuid.UUID {
+		t.Helper()
+		_, d, err := db.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID:      "ctx-filter",
+			OrgID:        orgID,
+			Metadata:     map[string]any{},
+			AgentContext: agentCtx,
+			Decision: model.Decision{
+				DecisionType: "test", Outcome: outcome,
+				Confidence: 0.5, Metadata: map[string]any{},
+			},
+		})
+		require.NoError(t, err)
+		return d.ID
+	}
+	review := trace("a", map[string]any{"client": map[string]any{"task": "review"}, "tool": "mcp"})
+	trace("b", map[string]any{"client": map[string]any{"task": "deploy"}, "tool": "mcp"})
+	trace("c", map[string]any{})
+
+	query := func(filters map[string]string) []model.Decision {
+		t.Helper()
+		decisions, _, err := db.QueryDecisions(ctx, orgID, model.QueryRequest{
+			Filters: model.QueryFilters{ContextFilters: filters},
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		return decisions
+	}
+
+	got := query(map[string]string{"client.task": "review"})
+	require.Len(t, got, 1)
+	assert.Equal(t, review, got[0].ID)
+	assert.Len(t, query(map[string]string{"tool": "mcp"}), 2)
+	assert.Empty(t, query(map[string]string{"tool": "mcp", "client.task": "audit"}))
+}
+
 // ---------------------------------------------------------------------------
 // ListConflictGroups with representative conflict (loadRepresentativeConflict)
 // ---------------------------------------------------------------------------
@@ -4248,7 +4504,7 @@ func TestBackfillOutcomeEmbedding_RoundTrip(t *testing.T) {
 
 	// Backfill the main embedding first.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb, ""))
 
 	// Now it should appear as missing outcome embedding.
 	missing, err := db.FindDecisionsMissingOutcomeEmbedding(ctx, 10)
@@ -4356,7 +4612,7 @@ func TestGetDecisionForScoring_WithEmbeddingAndProject(t *testing.T) {
 
 	// Backfill embedding so it's available for scoring.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb, ""))
 
 	scored, err := db.GetDecisionForScoring(ctx, dec.ID, orgID)
 	require.NoError(t, err)
@@ -4397,7 +4653,7 @@ func TestGetDecisionEmbeddings_Multiple(t *testing.T) {
 		decIDs = append(decIDs, dec.ID)
 
 		emb := pgvector.NewVector([]float32{float32(i+1) * 0.1, float32(i+1) * 0.2, float32(i+1) * 0.3})
-		require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb))
+		require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb, ""))
 		outcomeEmb := pgvector.NewVector([]float32{float32(i+1) * 0.4, float32(i+1) * 0.5, float32(i+1) * 0.6})
 		require.NoError(t, db.BackfillOutcomeEmbedding(ctx, dec.ID, orgID, outcomeEmb))
 	}
@@ -4721,7 +4977,7 @@ func TestBackfillEmbedding_NonexistentDecision(t *testing.T) {
 	orgID := uuid.Nil
 
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	err := db.BackfillEmbedding(ctx, uuid.New(), orgID, emb)
+	err := db.BackfillEmbedding(ctx, uuid.New(), orgID, emb, "")
 	require.NoError(t, err, "backfill on nonexistent decision should not error")
 }
 
@@ -4764,7 +5020,7 @@ func TestGetDecisionEmbeddings_PartialEmbeddings(t *testing.T) {
 
 	// Backfill only the base embedding, not the outcome embedding.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb, ""))
 
 	// GetDecisionEmbeddings requires BOTH embeddings, so this should return empty.
 	result, err := db.GetDecisionEmbeddings(ctx, []uuid.UUID{dec.ID}, orgID)
@@ -5317,7 +5573,7 @@ func TestFindDecisionIDsMissingClaims_NewDecision(t *testing.T) {
 
 	// FindDecisionIDsMissingClaims requires embedding IS NOT NULL, so backfill one.
 	emb := pgvector.NewVector([]float32{0.1, 0.2, 0.3})
-	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb))
+	require.NoError(t, db.BackfillEmbedding(ctx, dec.ID, orgID, emb, ""))
 
 	missing, err := db.FindDecisionIDsMissingClaims(ctx, 10)
 	require.NoError(t, err)
@@ -587,3 +587,50 @@ func (l *LiteDB) GetDecisionTypeDistribution(ctx context.Context, orgID uuid.UUI
 	}
 	return result, nil
 }
+
+// statsGroupKeys mirrors the Postgres grouping expressions. valid_from is
+// stored as UTC RFC 3339 text, so its first ten characters are the UTC day.
+var statsGroupKeys = map[string]struct{ expr, order string }{
+	storage.StatsGroupByDecisionType: {"decision_type", "COUNT(*) DESC, 1"},
+	storage.StatsGroupByAgent:        {"agent_id", "COUNT(*) DESC, 1"},
+	storage.StatsGroupByDay:          {"substr(valid_from, 1, 10)", "1 DESC"},
+}
+
+// AggregateDecisionStats returns the count, average confidence, and average
+// completeness of current decisions grouped by decision_type, agent_id, or
+// UTC day of valid_from.
+func (l *LiteDB) AggregateDecisionStats(ctx context.Context, orgID uuid.UUID, groupBy string, since *time.Time, limit int) ([]storage.DecisionStatsBucket, error) {
+	key, ok := statsGroupKeys[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("sqlite: aggregate decision stats: unknown group_by %q", groupBy)
+	}
+	q := `SELECT ` + key.expr + `, COUNT(*), COALESCE(AVG(confidence), 0), COALESCE(AVG(completeness_score), 0)
+		 FROM decisions
+		 WHERE org_id = ? AND valid_to IS NULL`
+	args := []any{uuidStr(orgID)}
+	if since != nil {
+		args = append(args, timeStr(*since))
+		q += " AND valid_from >= ?"
+	}
+	args = append(args, limit)
+	q += " GROUP BY 1 ORDER BY " + key.order + " LIMIT ?"
+
+	rows, err := l.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: aggregate decision stats: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []storage.DecisionStatsBucket
+	for rows.Next() {
+		var b storage.DecisionStatsBucket
+		if err := rows.Scan(&b.Key, &b.Count, &b.AvgConfidence, &b.AvgCompleteness); err != nil {
+			return nil, fmt.Errorf("sqlite: scan decision stats bucket: %w", err)
+		}
+		result = append(result, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sqlite: iterate decision stats: %w", err)
+	}
+	return result, nil
+}
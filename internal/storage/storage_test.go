@@ -4,8 +4,14 @@ package storage_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,6 +23,7 @@ import (
 
 	"github.com/ashita-ai/akashi/internal/integrity"
 	"github.com/ashita-ai/akashi/internal/model"
+	"github.com/ashita-ai/akashi/internal/search"
 	"github.com/ashita-ai/akashi/internal/storage"
 	"github.com/ashita-ai/akashi/internal/testutil"
 )
@@ -123,6 +130,77 @@ func TestInsertAndGetEvents(t *testing.T) {
 	assert.Equal(t, model.EventDecisionMade, got[1].EventType)
 }
 
+func TestDeleteEventsOlderThan(t *testing.T) {
+	ctx := context.Background()
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "event-retention"})
+	require.NoError(t, err)
+	_, decision, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:  "event-retention",
+		OrgID:    uuid.Nil,
+		Decision: model.Decision{DecisionType: "retention_test", Outcome: "kept", Confidence: 0.5},
+	})
+	require.NoError(t, err)
+
+	// Far in the past so other tests' events are never behind the cutoff.
+	old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := old.Add(24 * time.Hour)
+	events := []model.AgentEvent{
+		{ID: uuid.New(), RunID: run.ID, EventType: model.EventDecisionStarted, SequenceNum: 1,
+			OccurredAt: old, AgentID: "event-retention", CreatedAt: old},
+		{ID: uuid.New(), RunID: run.ID, EventType: model.EventDecisionMade, SequenceNum: 2,
+			OccurredAt: cutoff.Add(time.Hour), AgentID: "event-retention", CreatedAt: old},
+	}
+	_, err = testDB.InsertEvents(ctx, events)
+	require.NoError(t, err)
+
+	deleted, err := testDB.DeleteEventsOlderThan(ctx, cutoff)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, events[1].ID, got[0].ID)
+
+	_, err = testDB.GetDecision(ctx, uuid.Nil, decision.ID, storage.GetDecisionOpts{})
+	require.NoError(t, err, "decisions must survive event retention")
+}
+
+func TestDeleteOrgEventsOlderThan(t *testing.T) {
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
+		orgID, "event-retention-org", "event-retention-"+orgID.String()[:8])
+	require.NoError(t, err)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "event-retention-org", OrgID: orgID})
+	require.NoError(t, err)
+
+	old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = testDB.InsertEvents(ctx, []model.AgentEvent{{
+		ID: uuid.New(), RunID: run.ID, OrgID: orgID, EventType: model.EventDecisionStarted, SequenceNum: 1,
+		OccurredAt: old, AgentID: "event-retention-org", CreatedAt: old,
+	}})
+	require.NoError(t, err)
+	cutoff := old.Add(24 * time.Hour)
+
+	// The global sweep skips excluded orgs.
+	_, err = testDB.DeleteEventsOlderThan(ctx, cutoff, orgID)
+	require.NoError(t, err)
+	got, err := testDB.GetEventsByRun(ctx, orgID, run.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, got, 1, "excluded org's events must survive the global sweep")
+
+	deleted, err := testDB.DeleteOrgEventsOlderThan(ctx, orgID, cutoff)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	got, err = testDB.GetEventsByRun(ctx, orgID, run.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
 func TestInsertEventsCOPY(t *testing.T) {
 	ctx := context.Background()
 
@@ -243,6 +321,7 @@ func TestReviseDecision(t *testing.T) {
 	}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "deny", revised.Outcome)
+	assert.True(t, revised.OutcomeFlipped, "approve -> deny is an outcome flip")
 
 	// Original should be invalidated.
 	orig, err := testDB.GetDecision(ctx, original.OrgID, original.ID, storage.GetDecisionOpts{})
@@ -253,6 +332,43 @@ func TestReviseDecision(t *testing.T) {
 	rev, err := testDB.GetDecision(ctx, revised.OrgID, revised.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
 	assert.Nil(t, rev.ValidTo)
+	assert.True(t, rev.OutcomeFlipped)
+}
+
+// TestReviseDecision_PersistsBatchID revises a decision that carries the
+// columns added after the original revision INSERT (batch_id and later), so a
+// column/placeholder mismatch in that INSERT fails here.
+func TestReviseDecision_PersistsBatchID(t *testing.T) {
+	ctx := context.Background()
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "revise-batch-test"})
+	require.NoError(t, err)
+
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      "revise-batch-test",
+		DecisionType: "architecture",
+		Outcome:      "use postgres",
+		Confidence:   0.7,
+	})
+	require.NoError(t, err)
+
+	batchID := uuid.New()
+	revised, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID:        run.ID,
+		AgentID:      "revise-batch-test",
+		DecisionType: "architecture",
+		Outcome:      "use postgres with pgvector",
+		Confidence:   0.9,
+		BatchID:      &batchID,
+	}, nil)
+	require.NoError(t, err)
+
+	rev, err := testDB.GetDecision(ctx, revised.OrgID, revised.ID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	require.NotNil(t, rev.BatchID)
+	assert.Equal(t, batchID, *rev.BatchID)
+	assert.Nil(t, rev.ValidTo)
 }
 
 func TestReviseDecision_AutoResolvesConflicts(t *testing.T) {
@@ -643,6 +759,31 @@ func TestListAgentIDsBySharedTags(t *testing.T) {
 	assert.True(t, idSet3[a3.AgentID])
 }
 
+func TestSuggestAgentIDs(t *testing.T) {
+	ctx := context.Background()
+	for _, id := range []string{"underwriting-agent", "underwriting-bot"} {
+		_, err := testDB.CreateAgent(ctx, model.Agent{
+			AgentID: id, OrgID: uuid.Nil, Name: id, Role: model.RoleAgent, Metadata: map[string]any{},
+		})
+		if err != nil && !testDB.IsDuplicateKey(err) {
+			require.NoError(t, err)
+		}
+	}
+
+	ids, err := testDB.SuggestAgentIDs(ctx, uuid.Nil, "underwritng-agent", 3)
+	require.NoError(t, err)
+	require.NotEmpty(t, ids)
+	assert.Equal(t, "underwriting-agent", ids[0], "closest match ranks first")
+
+	ids, err = testDB.SuggestAgentIDs(ctx, uuid.Nil, "underwriting-agent", 3)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "an existing agent_id gets no suggestions")
+
+	ids, err = testDB.SuggestAgentIDs(ctx, uuid.New(), "underwritng-agent", 3)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "suggestions are scoped to the org")
+}
+
 func TestUpdateAgentTags(t *testing.T) {
 	ctx := context.Background()
 
@@ -763,6 +904,67 @@ func TestDeleteAgentDataClearsExternalSupersedesRefs(t *testing.T) {
 	assert.Nil(t, gotB.SupersedesID)
 }
 
+func TestAgentDeleteImpactAndExternalPrecedentRefs(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "impact-a-" + suffix, Name: "Impact Agent A", Role: model.RoleAgent,
+	})
+	require.NoError(t, err)
+	agentB, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "impact-b-" + suffix, Name: "Impact Agent B", Role: model.RoleAgent,
+	})
+	require.NoError(t, err)
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA.AgentID})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB.AgentID})
+	require.NoError(t, err)
+
+	decA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA.AgentID, OrgID: agentA.OrgID,
+		DecisionType: "impact-test", Outcome: "cited", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+	decB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB.AgentID, OrgID: agentB.OrgID,
+		DecisionType: "impact-test", Outcome: "citing", Confidence: 0.8,
+		PrecedentRef: &decA.ID,
+	})
+	require.NoError(t, err)
+
+	impact, err := testDB.GetAgentDeleteImpact(ctx, agentA.OrgID, agentA.AgentID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), impact.Decisions)
+	assert.Equal(t, int64(1), impact.ExternalPrecedentRefs)
+	assert.Equal(t, int64(0), impact.ExternalSupersedesRefs)
+	assert.Equal(t, int64(1), impact.ReferencingDecisions)
+	assert.Equal(t, []string{agentB.AgentID}, impact.ReferencingAgents)
+
+	_, err = testDB.GetAgentDeleteImpact(ctx, agentA.OrgID, "no-such-agent-"+suffix)
+	assert.ErrorIs(t, err, storage.ErrAgentNotFound)
+
+	result, err := testDB.DeleteAgentData(ctx, agentA.OrgID, agentA.AgentID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.ExternalPrecedentRefsCleared)
+	assert.Equal(t, int64(0), result.ExternalSupersedesRefsCleared)
+
+	gotB, err := testDB.GetDecision(ctx, agentB.OrgID, decB.ID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Nil(t, gotB.PrecedentRef)
+
+	// The cleared reference is archived for traceability.
+	var archived int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM deletion_audit_log
+		 WHERE org_id = $1 AND agent_id = $2 AND table_name = 'decision_references'
+		   AND record_id = $3 AND record_data->>'referenced_decision_id' = $4`,
+		agentA.OrgID, agentA.AgentID, decB.ID.String(), decA.ID.String(),
+	).Scan(&archived))
+	assert.Equal(t, 1, archived)
+}
+
 func TestDeleteAgentDataDeletesClaims(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
@@ -848,12 +1050,85 @@ func TestSearchDecisionsByText_FTS(t *testing.T) {
 			found = true
 			assert.Contains(t, r.Decision.Outcome, uniqueWord)
 			assert.Greater(t, r.SimilarityScore, float32(0), "relevance score should be positive")
+			require.Len(t, r.Highlights, 1)
+			assert.Contains(t, r.Highlights[0], "<b>"+uniqueWord+"</b>")
 			break
 		}
 	}
 	assert.True(t, found, "expected to find decision from agent %s in search results", agentID)
 }
 
+func TestSearchDecisionsByText_RecencyHalfLife(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "recency-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	// The old decision is the strong match: the term dominates its text and
+	// its quality scores are maxed. The recent one mentions the term once
+	// among filler and scores zero on quality.
+	create := func(term string, age time.Duration, strong bool) uuid.UUID {
+		outcome := "chose " + term + " over the alternatives after a long review of logging, metrics, tracing and paging"
+		completeness := float32(0)
+		var outcomeScore *float32
+		if strong {
+			outcome = term + " " + term + " " + term
+			completeness = 1
+			one := float32(1)
+			outcomeScore = &one
+		}
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, DecisionType: "recency_test",
+			Outcome: outcome, Confidence: 0.8, Metadata: map[string]any{},
+			CompletenessScore: completeness, OutcomeScore: outcomeScore,
+			ValidFrom: time.Now().UTC().Add(-age),
+		})
+		require.NoError(t, err)
+		return d.ID
+	}
+	top := func(query string, halfLife float64) uuid.UUID {
+		results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, query,
+			model.QueryFilters{AgentIDs: []string{agentID}, RecencyHalfLifeDays: &halfLife}, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		return results[0].Decision.ID
+	}
+
+	t.Run("fts", func(t *testing.T) {
+		term := "xylophonic" + suffix
+		oldStrong := create(term, 400*24*time.Hour, true)
+		recentWeak := create(term, time.Hour, false)
+
+		assert.Equal(t, oldStrong, top(term, 0), "without decay the stronger match wins")
+		assert.Equal(t, recentWeak, top(term, 1), "aggressive decay favors the recent match")
+	})
+
+	t.Run("ilike", func(t *testing.T) {
+		// A non-dictionary substring so FTS finds nothing and ILIKE ranks.
+		token := "qz" + suffix
+		oldStrong := create("pre_"+token+"_post", 400*24*time.Hour, true)
+		recentWeak := create("pre_"+token+"_post", time.Hour, false)
+
+		assert.Equal(t, oldStrong, top(token[:5], 0), "without decay the higher-quality match wins")
+		assert.Equal(t, recentWeak, top(token[:5], 1), "aggressive decay favors the recent match")
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		term := "marimbaic" + suffix
+		create(term, 400*24*time.Hour, true)
+		recent := create(term, time.Hour, false)
+
+		from := time.Now().Add(-24 * time.Hour)
+		results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, term,
+			model.QueryFilters{AgentIDs: []string{agentID}, TimeRange: &model.TimeRange{From: &from}}, 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, recent, results[0].Decision.ID)
+	})
+}
+
 func TestSearchDecisionsByText_EmptyQuery(t *testing.T) {
 	ctx := context.Background()
 
@@ -984,6 +1259,107 @@ func TestQueryDecisions_Ordering(t *testing.T) {
 	}
 }
 
+func TestQueryDecisions_MultipleDecisionTypes(t *testing.T) {
+	ctx := context.Background()
+	agentID := "types-" + uuid.New().String()[:8]
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	for _, dt := range []string{"architecture", "security", "security", "code_review"} {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: dt,
+			Outcome:      "chose " + dt,
+			Confidence:   0.8,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{
+			AgentIDs:      []string{agentID},
+			DecisionTypes: []string{"architecture", "security"},
+		},
+		Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "count must honor the type list")
+	require.Len(t, decisions, 3)
+	for _, d := range decisions {
+		assert.NotEqual(t, "code_review", d.DecisionType)
+	}
+
+	// The deprecated singular field is ORed into the list.
+	legacy := "code_review"
+	_, total, err = testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{
+			AgentIDs:      []string{agentID},
+			DecisionType:  &legacy,
+			DecisionTypes: []string{"architecture"},
+		},
+		Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestQueryDecisions_OrderByConflictCount(t *testing.T) {
+	ctx := context.Background()
+	agentID := "conflict-order-" + uuid.New().String()[:8]
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	var ds []model.Decision
+	for i := range 4 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "conflict_order_test",
+			Outcome:      fmt.Sprintf("outcome_%d", i),
+			Confidence:   0.5,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+		ds = append(ds, d)
+	}
+
+	// d0 has two open conflicts, d1 and d2 one each, d3 none.
+	for _, pair := range [][2]int{{0, 1}, {0, 2}} {
+		_, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+			ConflictKind:  model.ConflictKindSelfContradiction,
+			DecisionAID:   ds[pair[0]].ID,
+			DecisionBID:   ds[pair[1]].ID,
+			OrgID:         uuid.Nil,
+			AgentA:        agentID,
+			AgentB:        agentID,
+			DecisionTypeA: "conflict_order_test",
+			DecisionTypeB: "conflict_order_test",
+			OutcomeA:      ds[pair[0]].Outcome,
+			OutcomeB:      ds[pair[1]].Outcome,
+			ScoringMethod: "text",
+		})
+		require.NoError(t, err)
+	}
+
+	// Ties are broken by recency, so d2 (newer) precedes d1.
+	want := []uuid.UUID{ds[0].ID, ds[2].ID, ds[1].ID, ds[3].ID}
+	for _, orderBy := range []string{"conflict_count", "triage"} {
+		got, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+			Filters: model.QueryFilters{AgentIDs: []string{agentID}},
+			OrderBy: orderBy,
+			Limit:   50,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, len(want))
+		for i := range want {
+			assert.Equal(t, want[i], got[i].ID, "%s: position %d", orderBy, i)
+		}
+	}
+}
+
 func TestGetDecisionRevisions_Chain(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
@@ -1037,6 +1413,18 @@ func TestGetDecisionRevisions_Chain(t *testing.T) {
 	revisionsFromB, err := testDB.GetDecisionRevisions(ctx, uuid.Nil, b.ID)
 	require.NoError(t, err)
 	assert.Len(t, revisionsFromB, 3, "chain should be fully traversable from any member")
+
+	// The batch variant returns the same chain per input ID, and an empty
+	// chain for unknown IDs.
+	unknown := uuid.New()
+	batch, err := testDB.GetDecisionRevisionsBatch(ctx, uuid.Nil, []uuid.UUID{c.ID, b.ID, unknown})
+	require.NoError(t, err)
+	require.Len(t, batch[c.ID], 3)
+	for i := range revisions {
+		assert.Equal(t, revisions[i].ID, batch[c.ID][i].ID)
+	}
+	assert.Len(t, batch[b.ID], 3)
+	assert.Empty(t, batch[unknown])
 }
 
 func TestGetDecisionRevisions_NotFound(t *testing.T) {
@@ -1047,6 +1435,53 @@ func TestGetDecisionRevisions_NotFound(t *testing.T) {
 	assert.Empty(t, revisions, "nonexistent decision should return empty revision chain")
 }
 
+func TestListRevisedDecisionHeads(t *testing.T) {
+	ctx := context.Background()
+	agentID := "flipflop-" + uuid.New().String()[:8]
+	since := time.Now().Add(-time.Minute)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	newDecision := func(outcome string) model.Decision {
+		return model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "deploy_gate",
+			Outcome:      outcome,
+			Confidence:   0.8,
+			Metadata:     map[string]any{},
+		}
+	}
+
+	// An unrevised decision is never a head worth scanning.
+	_, err = testDB.CreateDecision(ctx, newDecision("approve staging"))
+	require.NoError(t, err)
+
+	// approve -> deny -> approve: only the last revision is current.
+	a, err := testDB.CreateDecision(ctx, newDecision("approve the deploy"))
+	require.NoError(t, err)
+	b, err := testDB.ReviseDecision(ctx, a.ID, newDecision("deny the deploy"), nil)
+	require.NoError(t, err)
+	c, err := testDB.ReviseDecision(ctx, b.ID, newDecision("approve the deploy"), nil)
+	require.NoError(t, err)
+
+	heads, err := testDB.ListRevisedDecisionHeads(ctx, uuid.Nil, agentID, since, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{c.ID}, heads)
+
+	chains, err := testDB.GetDecisionRevisionsBatch(ctx, uuid.Nil, heads)
+	require.NoError(t, err)
+	ff, ok := model.DetectFlipFlop(chains[c.ID], time.Hour, 2)
+	require.True(t, ok, "approve -> deny -> approve should be flagged")
+	assert.Equal(t, 2, ff.FlipCount)
+	assert.Equal(t, 3, ff.RevisionCount)
+
+	heads, err = testDB.ListRevisedDecisionHeads(ctx, uuid.Nil, agentID, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, heads, "chains revised before since are excluded")
+}
+
 func TestGetRevisionChainIDs_TransitiveChain(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
@@ -1279,7 +1714,7 @@ func TestBackfillEmbedding(t *testing.T) {
 	}
 	embedding := pgvector.NewVector(vec)
 
-	err = testDB.BackfillEmbedding(ctx, d.ID, d.OrgID, embedding)
+	err = testDB.BackfillEmbedding(ctx, d.ID, d.OrgID, embedding, "")
 	require.NoError(t, err)
 
 	// Verify the decision is no longer in the unembedded list.
@@ -1522,6 +1957,8 @@ func TestSearchDecisionsByText_ILIKEFallback(t *testing.T) {
 		if r.Decision.AgentID == agentID {
 			found = true
 			assert.Contains(t, r.Decision.Outcome, uniqueToken)
+			require.Len(t, r.Highlights, 1)
+			assert.Contains(t, r.Highlights[0], "<b>"+uniqueToken[:4]+"</b>")
 			break
 		}
 	}
@@ -1622,1461 +2059,1559 @@ func TestCreateTraceTx_WithSession(t *testing.T) {
 	assert.Equal(t, "code_review", gotDec.AgentContext["tool"])
 }
 
-func TestInsertEvents_VerifyFields(t *testing.T) {
+func TestQueryDecisions_ContextFilters(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "inevent-" + suffix
+	agentID := "ctxfilter-" + uuid.New().String()[:8]
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	now := time.Now().UTC()
-	eventID := uuid.New()
-	events := []model.AgentEvent{
-		{
-			ID:          eventID,
-			RunID:       run.ID,
-			EventType:   model.EventToolCallStarted,
-			SequenceNum: 1,
-			OccurredAt:  now,
-			AgentID:     agentID,
-			Payload:     map[string]any{"tool": "search", "query": "test"},
-			CreatedAt:   now,
-		},
+	trace := func(outcome string, agentCtx map[string]any) uuid.UUID {
+		t.Helper()
+		_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID:      agentID,
+			OrgID:        uuid.Nil,
+			AgentContext: agentCtx,
+			Decision:     model.Decision{DecisionType: "ctx_filter", Outcome: outcome, Confidence: 0.6},
+		})
+		require.NoError(t, err)
+		return d.ID
+	}
+	reviewA := trace("a", map[string]any{"client": map[string]any{"task": "review", "model": "gpt-4o"}})
+	reviewB := trace("b", map[string]any{"client": map[string]any{"task": "review", "model": "claude"}})
+	trace("c", map[string]any{"client": map[string]any{"task": "deploy", "model": "gpt-4o"}})
+	trace("d", map[string]any{"tool": "review"})
+
+	query := func(filters map[string]string) []uuid.UUID {
+		t.Helper()
+		decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+			Filters: model.QueryFilters{AgentIDs: []string{agentID}, ContextFilters: filters},
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(decisions))
+		for i, d := range decisions {
+			ids[i] = d.ID
+		}
+		return ids
 	}
 
-	count, err := testDB.InsertEvents(ctx, events)
-	require.NoError(t, err)
-	assert.Equal(t, int64(1), count)
-
-	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
-	require.NoError(t, err)
-	require.Len(t, got, 1)
-	assert.Equal(t, eventID, got[0].ID)
-	assert.Equal(t, model.EventToolCallStarted, got[0].EventType)
-	assert.Equal(t, agentID, got[0].AgentID)
-	assert.Equal(t, "search", got[0].Payload["tool"])
+	assert.ElementsMatch(t, []uuid.UUID{reviewA, reviewB}, query(map[string]string{"client.task": "review"}))
+	assert.ElementsMatch(t, []uuid.UUID{reviewA},
+		query(map[string]string{"client.task": "review", "client.model": "gpt-4o"}), "all filters must match")
+	assert.Empty(t, query(map[string]string{"client.task": "audit"}))
+	assert.Len(t, query(nil), 4)
 }
 
-func TestInsertEvent_Single(t *testing.T) {
+func TestCreateTraceTx_BatchWindow(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "single-evt-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentID := "tracetx-batch-" + suffix
+	sessionID := uuid.New()
+	base := time.Now().UTC().Add(-time.Hour)
 
-	now := time.Now().UTC()
-	eventID := uuid.New()
-	err = testDB.InsertEvent(ctx, model.AgentEvent{
-		ID:          eventID,
-		RunID:       run.ID,
-		EventType:   model.EventAgentHandoff,
-		SequenceNum: 1,
-		OccurredAt:  now,
-		AgentID:     agentID,
-		Payload:     map[string]any{"target": "reviewer"},
-		CreatedAt:   now,
-	})
-	require.NoError(t, err)
+	trace := func(outcome string, at time.Time) model.Decision {
+		t.Helper()
+		_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID:     agentID,
+			OrgID:       uuid.Nil,
+			SessionID:   &sessionID,
+			BatchWindow: 30 * time.Second,
+			Decision: model.Decision{
+				DecisionType: "batch_trace",
+				Outcome:      outcome,
+				Confidence:   0.6,
+				ValidFrom:    at,
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, d.BatchID)
+		return d
+	}
 
-	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
-	require.NoError(t, err)
-	require.Len(t, got, 1)
-	assert.Equal(t, eventID, got[0].ID)
-	assert.Equal(t, model.EventAgentHandoff, got[0].EventType)
-	assert.Equal(t, int64(1), got[0].SequenceNum)
-}
+	first := trace("first", base)
+	second := trace("second", base.Add(10*time.Second))
+	third := trace("third", base.Add(5*time.Minute))
 
-func TestCreateEvidence_Single(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "ev-single-" + suffix
+	assert.Equal(t, *first.BatchID, *second.BatchID, "decisions within the window share a batch")
+	assert.NotEqual(t, *first.BatchID, *third.BatchID, "a gap beyond the window starts a new batch")
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	batch, err := testDB.GetBatchDecisions(ctx, uuid.Nil, *first.BatchID)
 	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, first.ID, batch[0].ID)
+	assert.Equal(t, second.ID, batch[1].ID)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "evidence_test",
-		Outcome:      "proceed",
-		Confidence:   0.7,
+	bid := *first.BatchID
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{BatchID: &bid},
+		Limit:   10,
 	})
 	require.NoError(t, err)
+	assert.Len(t, decisions, 2)
 
-	rel := float32(0.92)
-	sourceURI := "https://example.com/doc"
-	ev, err := testDB.CreateEvidence(ctx, model.Evidence{
-		DecisionID:     d.ID,
-		OrgID:          d.OrgID,
-		SourceType:     model.SourceSearchResult,
-		SourceURI:      &sourceURI,
-		Content:        "Relevant search result content",
-		RelevanceScore: &rel,
+	// Without a window, no batch is assigned.
+	_, unbatched, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:   agentID,
+		OrgID:     uuid.Nil,
+		SessionID: &sessionID,
+		Decision: model.Decision{
+			DecisionType: "batch_trace",
+			Outcome:      "unbatched",
+			Confidence:   0.6,
+		},
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, ev.ID)
-	assert.Equal(t, "Relevant search result content", ev.Content)
-	assert.Equal(t, model.SourceSearchResult, ev.SourceType)
-	assert.Equal(t, &sourceURI, ev.SourceURI)
-	assert.Equal(t, &rel, ev.RelevanceScore)
-
-	// Verify round-trip via GetEvidenceByDecision.
-	evs, err := testDB.GetEvidenceByDecision(ctx, d.ID, d.OrgID)
-	require.NoError(t, err)
-	require.Len(t, evs, 1)
-	assert.Equal(t, ev.ID, evs[0].ID)
-	assert.Equal(t, "Relevant search result content", evs[0].Content)
+	assert.Nil(t, unbatched.BatchID)
 }
 
-func TestHasAccess_WithExpiry(t *testing.T) {
+func TestCreateTraceTx_ContextSnapshot(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	agentID := "tracetx-snapshot-" + uuid.New().String()[:8]
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "has-grantor-" + suffix,
-		Name:    "Grantor",
-		Role:    model.RoleAdmin,
-	})
-	require.NoError(t, err)
+	trace := func(temp float64) model.Decision {
+		t.Helper()
+		_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: agentID,
+			OrgID:   uuid.Nil,
+			Decision: model.Decision{
+				DecisionType: "snapshot_trace",
+				Outcome:      fmt.Sprintf("temperature %.1f", temp),
+				Confidence:   0.6,
+				ContextSnapshot: &model.ContextSnapshot{
+					Model:       "gpt-4o",
+					Temperature: &temp,
+					Tools:       []string{"bash", "edit"},
+				},
+				ContextSnapshotTruncated: temp > 1,
+			},
+		})
+		require.NoError(t, err)
+		return d
+	}
+	cold := trace(0.2)
+	hot := trace(1.2)
 
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "has-grantee-" + suffix,
-		Name:    "Grantee",
-		Role:    model.RoleReader,
-	})
+	got, err := testDB.GetDecision(ctx, uuid.Nil, hot.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
+	require.NotNil(t, got.ContextSnapshot)
+	assert.Equal(t, []string{"bash", "edit"}, got.ContextSnapshot.Tools)
+	assert.True(t, got.ContextSnapshotTruncated)
 
-	resID := "test-resource-" + suffix
-
-	// Grant that expired in the past.
-	expired := time.Now().UTC().Add(-1 * time.Hour)
-	_, err = testDB.CreateGrant(ctx, model.AccessGrant{
-		GrantorID:    grantor.ID,
-		GranteeID:    grantee.ID,
-		ResourceType: "agent_traces",
-		ResourceID:   &resID,
-		Permission:   "read",
-		ExpiresAt:    &expired,
+	lo, hi := 0.0, 0.5
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{
+			AgentIDs:       []string{agentID},
+			TemperatureMin: &lo,
+			TemperatureMax: &hi,
+		},
+		Limit: 10,
 	})
 	require.NoError(t, err)
-
-	// Expired grant should not provide access.
-	has, err := testDB.HasAccess(ctx, uuid.Nil, grantee.ID, "agent_traces", resID, "read")
-	require.NoError(t, err)
-	assert.False(t, has, "expired grant should not provide access")
+	require.Len(t, decisions, 1)
+	assert.Equal(t, cold.ID, decisions[0].ID)
 }
 
-func TestListGrantedAgentIDs(t *testing.T) {
+func TestCreateTraceTx_SupersessionFlagsOutcomeFlip(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "tracetx-flip-" + suffix
+	decisionType := "flip_" + suffix
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lg-grantor-" + suffix,
-		Name:    "Grantor",
-		Role:    model.RoleAdmin,
-	})
-	require.NoError(t, err)
-
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lg-grantee-" + suffix,
-		Name:    "Grantee",
-		Role:    model.RoleReader,
-	})
-	require.NoError(t, err)
-
-	res1 := "agent-alpha-" + suffix
-	res2 := "agent-beta-" + suffix
-	for _, resID := range []string{res1, res2} {
-		r := resID
-		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
-			GrantorID:    grantor.ID,
-			GranteeID:    grantee.ID,
-			ResourceType: "agent_traces",
-			ResourceID:   &r,
-			Permission:   "read",
+	trace := func(outcome string, supersedes *uuid.UUID) model.Decision {
+		t.Helper()
+		_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: agentID,
+			OrgID:   uuid.Nil,
+			Decision: model.Decision{
+				DecisionType: decisionType,
+				Outcome:      outcome,
+				Confidence:   0.8,
+				SupersedesID: supersedes,
+			},
 		})
 		require.NoError(t, err)
+		return d
 	}
 
-	selfAgentID := "lg-grantee-" + suffix
-	granted, err := testDB.ListGrantedAgentIDs(ctx, uuid.Nil, grantee.ID, selfAgentID)
+	original := trace("approve the refund", nil)
+	reworded := trace("approved, refund issued today", &original.ID)
+	assert.False(t, reworded.OutcomeFlipped, "same verdict reworded is not a flip")
+	flipped := trace("deny the refund", &reworded.ID)
+	assert.True(t, flipped.OutcomeFlipped)
+
+	got, err := testDB.GetDecision(ctx, uuid.Nil, flipped.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
+	assert.True(t, got.OutcomeFlipped)
 
-	// Should include self plus the two granted resources.
-	assert.True(t, granted[selfAgentID], "self agent_id should always be included")
-	assert.True(t, granted[res1], "granted resource 1 should be present")
-	assert.True(t, granted[res2], "granted resource 2 should be present")
+	// The flipped revision is the current decision; the filter finds it and
+	// the inverse filter excludes it.
+	yes, no := true, false
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType, OutcomeFlipped: &yes},
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, flipped.ID, decisions[0].ID)
+
+	_, total, err = testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType, OutcomeFlipped: &no},
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
 }
 
-func TestGetGrant(t *testing.T) {
+func TestCreateTraceTx_ConfidenceInterval(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "tracetx-interval-" + suffix
+	decisionType := "interval_" + suffix
+	low, high := float32(0.3), float32(0.9)
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "gg-grantor-" + suffix,
-		Name:    "Grantor",
-		Role:    model.RoleAdmin,
+	_, wide, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType:   decisionType,
+			Outcome:        "wide",
+			Confidence:     0.6,
+			ConfidenceLow:  &low,
+			ConfidenceHigh: &high,
+		},
 	})
 	require.NoError(t, err)
 
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "gg-grantee-" + suffix,
-		Name:    "Grantee",
-		Role:    model.RoleReader,
+	_, _, err = testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: decisionType,
+			Outcome:      "point only",
+			Confidence:   0.6,
+		},
 	})
 	require.NoError(t, err)
 
-	resID := "gg-resource-" + suffix
-	grant, err := testDB.CreateGrant(ctx, model.AccessGrant{
-		GrantorID:    grantor.ID,
-		GranteeID:    grantee.ID,
-		ResourceType: "agent_traces",
-		ResourceID:   &resID,
-		Permission:   "read",
-	})
+	got, err := testDB.GetDecision(ctx, wide.OrgID, wide.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
+	require.NotNil(t, got.ConfidenceLow)
+	require.NotNil(t, got.ConfidenceHigh)
+	assert.InDelta(t, 0.3, *got.ConfidenceLow, 0.001)
+	assert.InDelta(t, 0.9, *got.ConfidenceHigh, 0.001)
 
-	got, err := testDB.GetGrant(ctx, grant.OrgID, grant.ID)
+	minWidth := float32(0.5)
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType, MinConfidenceWidth: &minWidth},
+		Limit:   10,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, grant.ID, got.ID)
-	assert.Equal(t, grantor.ID, got.GrantorID)
-	assert.Equal(t, grantee.ID, got.GranteeID)
-	assert.Equal(t, "agent_traces", got.ResourceType)
-	assert.Equal(t, &resID, got.ResourceID)
+	assert.Equal(t, 1, total)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, wide.ID, decisions[0].ID)
 
-	// Not found case.
-	_, err = testDB.GetGrant(ctx, uuid.Nil, uuid.New())
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	// The CHECK constraint rejects bounds that do not bracket the point estimate.
+	badLow := float32(0.7)
+	_, _, err = testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType:   decisionType,
+			Outcome:        "invalid",
+			Confidence:     0.6,
+			ConfidenceLow:  &badLow,
+			ConfidenceHigh: &high,
+		},
+	})
+	assert.Error(t, err)
 }
 
-func TestListGrantsByGrantee(t *testing.T) {
+func TestQueryDecisions_NamespaceIsolation(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "ns-iso-" + suffix
+	decisionType := "ns_iso_" + suffix
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lgb-grantor-" + suffix,
-		Name:    "Grantor",
-		Role:    model.RoleAdmin,
+	_, prod, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: decisionType,
+			Outcome:      "prod outcome",
+			Confidence:   0.7,
+			Namespace:    "prod",
+		},
 	})
 	require.NoError(t, err)
 
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lgb-grantee-" + suffix,
-		Name:    "Grantee",
-		Role:    model.RoleReader,
+	_, staging, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: decisionType,
+			Outcome:      "staging outcome",
+			Confidence:   0.7,
+			Namespace:    "staging",
+		},
 	})
 	require.NoError(t, err)
 
-	// Create three grants for this grantee.
-	for i := range 3 {
-		resID := fmt.Sprintf("lgb-resource-%d-%s", i, suffix)
-		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
-			GrantorID:    grantor.ID,
-			GranteeID:    grantee.ID,
-			ResourceType: "agent_traces",
-			ResourceID:   &resID,
-			Permission:   "read",
+	_, dflt, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: decisionType,
+			Outcome:      "unscoped outcome",
+			Confidence:   0.7,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultNamespace, dflt.Namespace)
+
+	for _, tc := range []struct {
+		ns   string
+		want uuid.UUID
+	}{
+		{"prod", prod.ID},
+		{"staging", staging.ID},
+		{model.DefaultNamespace, dflt.ID},
+	} {
+		ns := tc.ns
+		decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+			Filters: model.QueryFilters{DecisionType: &decisionType, Namespace: &ns},
+			Limit:   10,
 		})
 		require.NoError(t, err)
+		assert.Equal(t, 1, total, ns)
+		require.Len(t, decisions, 1, ns)
+		assert.Equal(t, tc.want, decisions[0].ID, ns)
+		assert.Equal(t, ns, decisions[0].Namespace)
 	}
 
-	grants, err := testDB.ListGrantsByGrantee(ctx, uuid.Nil, grantee.ID)
+	// Without a namespace filter the storage layer sees all of them.
+	_, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType},
+		Limit:   10,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(grants), 3, "should have at least 3 grants")
+	assert.Equal(t, 3, total)
+}
 
-	// Verify all belong to the grantee.
-	for _, g := range grants {
-		assert.Equal(t, grantee.ID, g.GranteeID)
+// seedDecisionsWithIncludes creates n decisions of a unique type, each with
+// alts alternatives and evs evidence rows, and returns the decision type.
+func seedDecisionsWithIncludes(tb testing.TB, n, alts, evs int) string {
+	tb.Helper()
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	decisionType := "includes_" + suffix
+	for i := 0; i < n; i++ {
+		params := storage.CreateTraceParams{
+			AgentID: "includes-" + suffix,
+			OrgID:   uuid.Nil,
+			Decision: model.Decision{
+				DecisionType: decisionType,
+				Outcome:      fmt.Sprintf("outcome %d", i),
+				Confidence:   0.6,
+			},
+		}
+		for j := 0; j < alts; j++ {
+			params.Alternatives = append(params.Alternatives, model.Alternative{Label: fmt.Sprintf("alt %d", j)})
+		}
+		for j := 0; j < evs; j++ {
+			score := float32(j) / float32(evs)
+			params.Evidence = append(params.Evidence, model.Evidence{
+				OrgID:          uuid.Nil,
+				SourceType:     model.SourceDocument,
+				Content:        fmt.Sprintf("evidence %d", j),
+				RelevanceScore: &score,
+			})
+		}
+		_, _, err := testDB.CreateTraceTx(ctx, params)
+		require.NoError(tb, err)
 	}
+	return decisionType
 }
 
-func TestGetDecision_WithOpts(t *testing.T) {
+func TestDecisionFlags(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "getdec-" + suffix
+	agentID := "flags-" + suffix
+	decisionType := "flags_" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	trace := func(outcome string, confidence float32, source model.SourceType) model.Decision {
+		t.Helper()
+		params := storage.CreateTraceParams{
+			AgentID: agentID,
+			OrgID:   uuid.Nil,
+			Decision: model.Decision{
+				DecisionType: decisionType,
+				Outcome:      outcome,
+				Confidence:   confidence,
+			},
+		}
+		if source != "" {
+			params.Evidence = []model.Evidence{{OrgID: uuid.Nil, SourceType: source, Content: outcome + " evidence"}}
+		}
+		_, d, err := testDB.CreateTraceTx(ctx, params)
+		require.NoError(t, err)
+		return d
+	}
 
-	reasoning := "decision with full data"
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "getdec_test",
-		Outcome:      "selected_option",
-		Confidence:   0.95,
-		Reasoning:    &reasoning,
-	})
-	require.NoError(t, err)
+	solid := trace("solid", 0.9, model.SourceDocument)
+	lowConf := trace("low confidence", 0.3, model.SourceDocument)
+	userOnly := trace("user input only", 0.9, model.SourceUserInput)
+	noEvidence := trace("no evidence", 0.9, "")
+	contested := trace("contested", 0.9, model.SourceDocument)
+	counterpart := trace("counterpart", 0.9, model.SourceDocument)
+	original := trace("original", 0.9, model.SourceDocument)
 
-	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
-		{DecisionID: d.ID, Label: "Selected"},
+	topicSim, outcomeDiv := 0.9, 0.8
+	sig := topicSim * outcomeDiv
+	_, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindSelfContradiction,
+		DecisionAID:       contested.ID,
+		DecisionBID:       counterpart.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentID,
+		AgentB:            agentID,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          contested.Outcome,
+		OutcomeB:          counterpart.Outcome,
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
 	})
 	require.NoError(t, err)
 
-	rel := float32(0.88)
-	_, err = testDB.CreateEvidence(ctx, model.Evidence{
-		DecisionID:     d.ID,
-		OrgID:          d.OrgID,
-		SourceType:     model.SourceToolOutput,
-		Content:        "Tool output data",
-		RelevanceScore: &rel,
-	})
+	revision, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID:        original.RunID,
+		AgentID:      agentID,
+		DecisionType: decisionType,
+		Outcome:      "revision",
+		Confidence:   0.9,
+	}, nil)
 	require.NoError(t, err)
 
-	// Get without includes.
-	bare, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{})
+	flags, err := testDB.GetDecisionFlagsBatch(ctx, []uuid.UUID{
+		solid.ID, lowConf.ID, userOnly.ID, noEvidence.ID, contested.ID, original.ID, revision.ID,
+	}, uuid.Nil)
 	require.NoError(t, err)
-	assert.Empty(t, bare.Alternatives)
-	assert.Empty(t, bare.Evidence)
 
-	// Get with includes.
-	full, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{
-		IncludeAlts:     true,
-		IncludeEvidence: true,
+	assert.Equal(t, model.DecisionFlags{}, flags[solid.ID], "solid decision should carry no flags")
+	assert.Equal(t, model.DecisionFlags{LowConfidence: true}, flags[lowConf.ID])
+	assert.Equal(t, model.DecisionFlags{WeaklyEvidenced: true}, flags[userOnly.ID])
+	assert.Equal(t, model.DecisionFlags{WeaklyEvidenced: true}, flags[noEvidence.ID])
+	assert.Equal(t, model.DecisionFlags{Contested: true}, flags[contested.ID])
+	assert.True(t, flags[original.ID].Revised, "superseded decision is part of a chain")
+	assert.True(t, flags[revision.ID].Revised, "superseding decision is part of a chain")
+
+	// The include=flags path on QueryDecisions attaches the same values.
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType},
+		Include: []string{"flags"},
+		Limit:   20,
 	})
 	require.NoError(t, err)
-	assert.Len(t, full.Alternatives, 1)
-	assert.Len(t, full.Evidence, 1)
-	assert.Equal(t, "Selected", full.Alternatives[0].Label)
-	assert.Equal(t, "Tool output data", full.Evidence[0].Content)
+	require.NotEmpty(t, decisions)
+	for _, d := range decisions {
+		require.NotNil(t, d.Flags, d.Outcome)
+		assert.Equal(t, flags[d.ID], *d.Flags, d.Outcome)
+	}
 
-	// CurrentOnly on an active decision should succeed.
-	current, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{CurrentOnly: true})
+	// Without the include, flags are omitted.
+	decisions, _, err = testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &decisionType},
+		Limit:   20,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, d.ID, current.ID)
-
-	// Not found case.
-	_, err = testDB.GetDecision(ctx, uuid.Nil, uuid.New(), storage.GetDecisionOpts{})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	for _, d := range decisions {
+		assert.Nil(t, d.Flags)
+	}
 }
 
-func TestListAgents_Pagination(t *testing.T) {
+func TestQueryDecisions_IncludesMatchSequentialLoad(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	decisionType := seedDecisionsWithIncludes(t, 5, 3, 2)
+	filters := model.QueryFilters{DecisionType: &decisionType}
 
-	// Create a batch of agents with a unique suffix so we can count them.
-	const agentCount = 5
-	for i := range agentCount {
-		_, err := testDB.CreateAgent(ctx, model.Agent{
-			AgentID: fmt.Sprintf("la-%d-%s", i, suffix),
-			Name:    fmt.Sprintf("List Agent %d", i),
-			Role:    model.RoleAgent,
+	for _, include := range [][]string{{"alternatives"}, {"evidence"}, {"alternatives", "evidence"}} {
+		decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+			Filters: filters, Include: include, Limit: 50,
 		})
 		require.NoError(t, err)
-	}
+		assert.Equal(t, 5, total)
+		require.Len(t, decisions, 5)
 
-	// List all agents in the default org (there may be more from other tests).
-	allAgents, err := testDB.ListAgents(ctx, uuid.Nil, 1000, 0)
-	require.NoError(t, err)
+		ids := make([]uuid.UUID, len(decisions))
+		for i, d := range decisions {
+			ids[i] = d.ID
+		}
+		altsMap, err := testDB.GetAlternativesByDecisions(ctx, ids, uuid.Nil)
+		require.NoError(t, err)
+		evsMap, err := testDB.GetEvidenceByDecisions(ctx, ids, uuid.Nil)
+		require.NoError(t, err)
 
-	// Count how many of our agents appear.
-	var ours int
-	for _, a := range allAgents {
-		for i := range agentCount {
-			if a.AgentID == fmt.Sprintf("la-%d-%s", i, suffix) {
-				ours++
+		wantAlts := slices.Contains(include, "alternatives")
+		wantEvs := slices.Contains(include, "evidence")
+		for _, d := range decisions {
+			if wantAlts {
+				assert.Equal(t, altsMap[d.ID], d.Alternatives)
+				assert.Len(t, d.Alternatives, 3)
+			} else {
+				assert.Nil(t, d.Alternatives)
+			}
+			if wantEvs {
+				assert.Equal(t, evsMap[d.ID], d.Evidence)
+				assert.Len(t, d.Evidence, 2)
+			} else {
+				assert.Nil(t, d.Evidence)
 			}
 		}
 	}
-	assert.Equal(t, agentCount, ours, "all created agents should appear in ListAgents")
-
-	// Test pagination: limit=2, offset=0.
-	page1, err := testDB.ListAgents(ctx, uuid.Nil, 2, 0)
-	require.NoError(t, err)
-	assert.Len(t, page1, 2)
-
-	// Different offset should return different agents.
-	page2, err := testDB.ListAgents(ctx, uuid.Nil, 2, 2)
-	require.NoError(t, err)
-	assert.Len(t, page2, 2)
-	assert.NotEqual(t, page1[0].ID, page2[0].ID, "paginated pages should return different agents")
 }
 
-func TestCountAgents(t *testing.T) {
+// BenchmarkQueryDecisions_Includes compares the pipelined include path in
+// QueryDecisions against loading alternatives and evidence with sequential
+// queries after the main select, on a page of 50 decisions.
+func BenchmarkQueryDecisions_Includes(b *testing.B) {
 	ctx := context.Background()
+	decisionType := seedDecisionsWithIncludes(b, 50, 4, 3)
+	filters := model.QueryFilters{DecisionType: &decisionType}
 
-	count, err := testDB.CountAgents(ctx, uuid.Nil)
-	require.NoError(t, err)
-	assert.Greater(t, count, 0, "default org should have at least one agent from earlier tests")
+	b.Run("sequential", func(b *testing.B) {
+		for b.Loop() {
+			decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{Filters: filters, Limit: 50})
+			if err != nil {
+				b.Fatal(err)
+			}
+			ids := make([]uuid.UUID, len(decisions))
+			for i, d := range decisions {
+				ids[i] = d.ID
+			}
+			if _, err := testDB.GetAlternativesByDecisions(ctx, ids, uuid.Nil); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := testDB.GetEvidenceByDecisions(ctx, ids, uuid.Nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pipelined", func(b *testing.B) {
+		for b.Loop() {
+			_, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+				Filters: filters, Include: []string{"alternatives", "evidence"}, Limit: 50,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
 }
 
-func TestGetSessionDecisions(t *testing.T) {
+func TestInsertEvents_VerifyFields(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "session-" + suffix
-	sessionID := uuid.New()
+	agentID := "inevent-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Create 3 decisions in the same session.
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "session_test",
-			Outcome:      fmt.Sprintf("session_outcome_%d", i),
-			Confidence:   float32(i+1) * 0.25,
-			SessionID:    &sessionID,
-		})
-		require.NoError(t, err)
+	now := time.Now().UTC()
+	eventID := uuid.New()
+	events := []model.AgentEvent{
+		{
+			ID:          eventID,
+			RunID:       run.ID,
+			EventType:   model.EventToolCallStarted,
+			SequenceNum: 1,
+			OccurredAt:  now,
+			AgentID:     agentID,
+			Payload:     map[string]any{"tool": "search", "query": "test"},
+			CreatedAt:   now,
+		},
 	}
 
-	// Also create a decision with a different session to verify isolation.
-	otherSession := uuid.New()
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "session_test",
-		Outcome:      "other_session",
-		Confidence:   0.5,
-		SessionID:    &otherSession,
-	})
+	count, err := testDB.InsertEvents(ctx, events)
 	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
 
-	decisions, err := testDB.GetSessionDecisions(ctx, uuid.Nil, sessionID)
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
-	assert.Len(t, decisions, 3, "should only return decisions from the target session")
-
-	for _, d := range decisions {
-		require.NotNil(t, d.SessionID)
-		assert.Equal(t, sessionID, *d.SessionID)
-	}
-
-	// Verify chronological ordering (valid_from ASC).
-	for i := 1; i < len(decisions); i++ {
-		assert.False(t, decisions[i].ValidFrom.Before(decisions[i-1].ValidFrom),
-			"decisions should be ordered by valid_from ASC")
-	}
+	require.Len(t, got, 1)
+	assert.Equal(t, eventID, got[0].ID)
+	assert.Equal(t, model.EventToolCallStarted, got[0].EventType)
+	assert.Equal(t, agentID, got[0].AgentID)
+	assert.Equal(t, "search", got[0].Payload["tool"])
 }
 
-func TestCreateIntegrityProof_And_GetLatest(t *testing.T) {
+func TestInsertEvent_Single(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "single-evt-" + suffix
 
-	now := time.Now().UTC()
-	batchStart := now.Add(-1 * time.Hour)
-	batchEnd := now
-
-	proof1 := storage.IntegrityProof{
-		OrgID:         uuid.Nil,
-		BatchStart:    batchStart,
-		BatchEnd:      batchEnd,
-		DecisionCount: 10,
-		RootHash:      "abc123hash_first",
-		CreatedAt:     now.Add(-30 * time.Minute),
-	}
-	err := testDB.CreateIntegrityProof(ctx, proof1)
-	require.NoError(t, err)
-
-	prevRoot := "abc123hash_first"
-	proof2 := storage.IntegrityProof{
-		OrgID:         uuid.Nil,
-		BatchStart:    batchEnd,
-		BatchEnd:      now.Add(1 * time.Hour),
-		DecisionCount: 5,
-		RootHash:      "def456hash_second",
-		PreviousRoot:  &prevRoot,
-		CreatedAt:     now,
-	}
-	err = testDB.CreateIntegrityProof(ctx, proof2)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// GetLatestIntegrityProof should return the most recent one.
-	latest, err := testDB.GetLatestIntegrityProof(ctx, uuid.Nil)
+	now := time.Now().UTC()
+	eventID := uuid.New()
+	err = testDB.InsertEvent(ctx, model.AgentEvent{
+		ID:          eventID,
+		RunID:       run.ID,
+		EventType:   model.EventAgentHandoff,
+		SequenceNum: 1,
+		OccurredAt:  now,
+		AgentID:     agentID,
+		Payload:     map[string]any{"target": "reviewer"},
+		CreatedAt:   now,
+	})
 	require.NoError(t, err)
-	require.NotNil(t, latest)
-	assert.Equal(t, "def456hash_second", latest.RootHash)
-	assert.Equal(t, 5, latest.DecisionCount)
-	require.NotNil(t, latest.PreviousRoot)
-	assert.Equal(t, "abc123hash_first", *latest.PreviousRoot)
 
-	// Non-existent org should return nil without error.
-	randomOrg := uuid.New()
-	noProof, err := testDB.GetLatestIntegrityProof(ctx, randomOrg)
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
-	assert.Nil(t, noProof)
+	require.Len(t, got, 1)
+	assert.Equal(t, eventID, got[0].ID)
+	assert.Equal(t, model.EventAgentHandoff, got[0].EventType)
+	assert.Equal(t, int64(1), got[0].SequenceNum)
 }
 
-func TestGetDecisionHashesForBatch(t *testing.T) {
+func TestCreateEvidence_Single(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "hash-batch-" + suffix
+	agentID := "ev-single-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "evidence_test",
+		Outcome:      "proceed",
+		Confidence:   0.7,
+	})
+	require.NoError(t, err)
 
-	// Create several decisions. Each gets a content_hash from CreateDecision.
-	const count = 4
-	for i := range count {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "hash_test",
-			Outcome:      fmt.Sprintf("hash_outcome_%d", i),
-			Confidence:   float32(i+1) * 0.2,
-		})
-		require.NoError(t, err)
-	}
+	rel := float32(0.92)
+	sourceURI := "https://example.com/doc"
+	ev, err := testDB.CreateEvidence(ctx, model.Evidence{
+		DecisionID:     d.ID,
+		OrgID:          d.OrgID,
+		SourceType:     model.SourceSearchResult,
+		SourceURI:      &sourceURI,
+		Content:        "Relevant search result content",
+		RelevanceScore: &rel,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, ev.ID)
+	assert.Equal(t, "Relevant search result content", ev.Content)
+	assert.Equal(t, model.SourceSearchResult, ev.SourceType)
+	assert.Equal(t, &sourceURI, ev.SourceURI)
+	assert.Equal(t, &rel, ev.RelevanceScore)
 
-	afterCreate := time.Now().UTC().Add(1 * time.Second)
+	// Verify round-trip via GetEvidenceByDecision.
+	evs, err := testDB.GetEvidenceByDecision(ctx, d.ID, d.OrgID, true)
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	assert.Equal(t, ev.ID, evs[0].ID)
+	assert.Equal(t, "Relevant search result content", evs[0].Content)
+}
 
-	hashes, err := testDB.GetDecisionHashesForBatch(ctx, uuid.Nil, beforeCreate, afterCreate)
+func TestEvidenceBlobs_DedupAndRehydration(t *testing.T) {
+	ctx := context.Background()
+	testDB.SetEvidenceInlineMaxBytes(64)
+	t.Cleanup(func() { testDB.SetEvidenceInlineMaxBytes(storage.DefaultEvidenceInlineMaxBytes) })
+
+	suffix := uuid.New().String()[:8]
+	agentID := "ev-blob-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(hashes), count, "should find at least %d hashes in the batch window", count)
 
-	// Verify lexicographic ordering.
-	for i := 1; i < len(hashes); i++ {
-		assert.LessOrEqual(t, hashes[i-1], hashes[i],
-			"hashes should be in lexicographic order: %s > %s at index %d", hashes[i-1], hashes[i], i)
+	large := strings.Repeat("log line "+suffix+"\n", 20)
+	var decisions []model.Decision
+	for range 2 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, DecisionType: "evidence_blob_test",
+			Outcome: "proceed", Confidence: 0.7,
+		})
+		require.NoError(t, err)
+		decisions = append(decisions, d)
 	}
 
-	// Verify all hashes are non-empty.
-	for _, h := range hashes {
-		assert.NotEmpty(t, h, "content hash should not be empty")
+	// The same large content on two decisions, one per write path, plus a
+	// small item that stays inline.
+	ev, err := testDB.CreateEvidence(ctx, model.Evidence{
+		DecisionID: decisions[0].ID, OrgID: uuid.Nil, SourceType: model.SourceToolOutput, Content: large,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, large, ev.Content, "the returned evidence keeps its content")
+	require.NoError(t, testDB.CreateEvidenceBatch(ctx, []model.Evidence{
+		{DecisionID: decisions[1].ID, OrgID: uuid.Nil, SourceType: model.SourceToolOutput, Content: large},
+		{DecisionID: decisions[1].ID, OrgID: uuid.Nil, SourceType: model.SourceUserInput, Content: "small"},
+	}))
+
+	sum := sha256.Sum256([]byte(large))
+	hash := hex.EncodeToString(sum[:])
+	var blobs, inlineBytes int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM evidence_blobs WHERE org_id = $1 AND content_hash = $2`, uuid.Nil, hash,
+	).Scan(&blobs))
+	assert.Equal(t, 1, blobs, "identical content is stored once")
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT COALESCE(sum(length(content)), 0) FROM evidence WHERE blob_hash = $1`, hash,
+	).Scan(&inlineBytes))
+	assert.Zero(t, inlineBytes, "offloaded rows keep no inline content")
+
+	// Rehydrated: content comes back transparently, with no reference.
+	evs, err := testDB.GetEvidenceByDecision(ctx, decisions[1].ID, uuid.Nil, true)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+	for _, e := range evs {
+		assert.Nil(t, e.Blob)
+	}
+	assert.ElementsMatch(t, []string{large, "small"}, []string{evs[0].Content, evs[1].Content})
+
+	byDecision, err := testDB.GetEvidenceByDecisions(ctx, []uuid.UUID{decisions[0].ID}, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, byDecision[decisions[0].ID], 1)
+	assert.Equal(t, large, byDecision[decisions[0].ID][0].Content)
+
+	// Reference mode: large content is replaced by its blob reference.
+	evs, err = testDB.GetEvidenceByDecision(ctx, decisions[1].ID, uuid.Nil, false)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+	for _, e := range evs {
+		if e.SourceType == model.SourceUserInput {
+			assert.Equal(t, "small", e.Content)
+			assert.Nil(t, e.Blob)
+			continue
+		}
+		assert.Empty(t, e.Content)
+		require.NotNil(t, e.Blob)
+		assert.Equal(t, hash, e.Blob.Hash)
+		assert.Equal(t, len(large), e.Blob.SizeBytes)
 	}
+
+	// Erasing one decision keeps the blob the other still references;
+	// erasing both removes it.
+	_, err = testDB.EraseDecision(ctx, uuid.Nil, decisions[0].ID, "blob test", agentID, nil)
+	require.NoError(t, err)
+	evs, err = testDB.GetEvidenceByDecision(ctx, decisions[1].ID, uuid.Nil, true)
+	require.NoError(t, err)
+	assert.Contains(t, []string{evs[0].Content, evs[1].Content}, large)
+
+	_, err = testDB.EraseDecision(ctx, uuid.Nil, decisions[1].ID, "blob test", agentID, nil)
+	require.NoError(t, err)
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM evidence_blobs WHERE org_id = $1 AND content_hash = $2`, uuid.Nil, hash,
+	).Scan(&blobs))
+	assert.Zero(t, blobs)
 }
 
-func TestListOrganizationIDs(t *testing.T) {
+func TestDeleteOrphanEvidenceBlobs(t *testing.T) {
 	ctx := context.Background()
+	hash := "orphan-" + uuid.New().String()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO evidence_blobs (org_id, content_hash, content, size_bytes, last_referenced_at)
+		 VALUES ($1, $2, 'orphaned', 8, now() - interval '2 hours')`, uuid.Nil, hash)
+	require.NoError(t, err)
 
-	ids, err := testDB.ListOrganizationIDs(ctx)
+	deleted, err := testDB.DeleteOrphanEvidenceBlobs(ctx, time.Now().Add(-3*time.Hour))
 	require.NoError(t, err)
-	assert.NotEmpty(t, ids, "should have at least the default org")
+	assert.Zero(t, deleted, "blobs inside the grace period are kept")
 
-	foundDefault := false
-	for _, id := range ids {
-		if id == uuid.Nil {
-			foundDefault = true
-			break
-		}
-	}
-	assert.True(t, foundDefault, "default org (uuid.Nil) should be in the list")
+	deleted, err = testDB.DeleteOrphanEvidenceBlobs(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
 }
 
-func TestWithRetry_SucceedsImmediately(t *testing.T) {
+func TestHasAccess_WithExpiry(t *testing.T) {
 	ctx := context.Background()
-	callCount := 0
+	suffix := uuid.New().String()[:8]
 
-	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
-		callCount++
-		return nil
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "has-grantor-" + suffix,
+		Name:    "Grantor",
+		Role:    model.RoleAdmin,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, 1, callCount, "should only call fn once when it succeeds immediately")
-}
-
-func TestWithRetry_NonRetriableError(t *testing.T) {
-	ctx := context.Background()
-	callCount := 0
-	permanent := fmt.Errorf("permanent error")
 
-	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
-		callCount++
-		return permanent
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "has-grantee-" + suffix,
+		Name:    "Grantee",
+		Role:    model.RoleReader,
 	})
-	require.Error(t, err)
-	assert.Equal(t, permanent, err)
-	assert.Equal(t, 1, callCount, "non-retriable error should not trigger retry")
-}
+	require.NoError(t, err)
 
-func TestWithRetry_ContextCancellation(t *testing.T) {
-	// WithRetry with a cancelled context should exit promptly.
-	// We use a pgconn.PgError to trigger the retry path, then cancel context.
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // Cancel immediately.
+	resID := "test-resource-" + suffix
 
-	callCount := 0
-	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
-		callCount++
-		// Return nil on first call since context is already cancelled,
-		// the loop won't sleep but fn is called once.
-		return nil
+	// Grant that expired in the past.
+	expired := time.Now().UTC().Add(-1 * time.Hour)
+	_, err = testDB.CreateGrant(ctx, model.AccessGrant{
+		GrantorID:    grantor.ID,
+		GranteeID:    grantee.ID,
+		ResourceType: "agent_traces",
+		ResourceID:   &resID,
+		Permission:   "read",
+		ExpiresAt:    &expired,
 	})
-	// The function returns nil because fn() returned nil.
 	require.NoError(t, err)
-	assert.Equal(t, 1, callCount)
+
+	// Expired grant should not provide access.
+	has, err := testDB.HasAccess(ctx, uuid.Nil, grantee.ID, "agent_traces", resID, "read")
+	require.NoError(t, err)
+	assert.False(t, has, "expired grant should not provide access")
 }
 
-func TestGetDecisionsByAgent(t *testing.T) {
+func TestListGrantedAgentIDs(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "byagent-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lg-grantor-" + suffix,
+		Name:    "Grantor",
+		Role:    model.RoleAdmin,
+	})
 	require.NoError(t, err)
 
-	const decCount = 4
-	for i := range decCount {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "byagent_test",
-			Outcome:      fmt.Sprintf("agent_dec_%d", i),
-			Confidence:   float32(i+1) * 0.2,
-		})
-		require.NoError(t, err)
-	}
-
-	decisions, total, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, nil, nil)
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lg-grantee-" + suffix,
+		Name:    "Grantee",
+		Role:    model.RoleReader,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, decCount, total)
-	assert.Len(t, decisions, decCount)
-
-	// All returned decisions should belong to our agent.
-	for _, d := range decisions {
-		assert.Equal(t, agentID, d.AgentID)
-	}
 
-	// Verify descending valid_from ordering.
-	for i := 1; i < len(decisions); i++ {
-		assert.False(t, decisions[i].ValidFrom.After(decisions[i-1].ValidFrom),
-			"decisions should be ordered by valid_from DESC")
+	res1 := "agent-alpha-" + suffix
+	res2 := "agent-beta-" + suffix
+	for _, resID := range []string{res1, res2} {
+		r := resID
+		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
+			GrantorID:    grantor.ID,
+			GranteeID:    grantee.ID,
+			ResourceType: "agent_traces",
+			ResourceID:   &r,
+			Permission:   "read",
+		})
+		require.NoError(t, err)
 	}
 
-	// Test pagination.
-	page1, total1, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 2, 0, nil, nil)
+	selfAgentID := "lg-grantee-" + suffix
+	granted, err := testDB.ListGrantedAgentIDs(ctx, uuid.Nil, grantee.ID, selfAgentID)
 	require.NoError(t, err)
-	assert.Equal(t, decCount, total1)
-	assert.Len(t, page1, 2)
 
-	page2, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 2, 2, nil, nil)
-	require.NoError(t, err)
-	assert.Len(t, page2, 2)
-	assert.NotEqual(t, page1[0].ID, page2[0].ID, "pages should not overlap")
+	// Should include self plus the two granted resources.
+	assert.True(t, granted[selfAgentID], "self agent_id should always be included")
+	assert.True(t, granted[res1], "granted resource 1 should be present")
+	assert.True(t, granted[res2], "granted resource 2 should be present")
 }
 
-func TestGetDecisionsByAgent_TimeRange(t *testing.T) {
+func TestGetGrant(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "byagent-tr-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gg-grantor-" + suffix,
+		Name:    "Grantor",
+		Role:    model.RoleAdmin,
+	})
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "tr_test",
-		Outcome:      "in_range",
-		Confidence:   0.8,
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gg-grantee-" + suffix,
+		Name:    "Grantee",
+		Role:    model.RoleReader,
 	})
 	require.NoError(t, err)
 
-	from := time.Now().UTC().Add(-5 * time.Second)
-	to := time.Now().UTC().Add(5 * time.Second)
-	decisions, total, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, &from, &to)
+	resID := "gg-resource-" + suffix
+	grant, err := testDB.CreateGrant(ctx, model.AccessGrant{
+		GrantorID:    grantor.ID,
+		GranteeID:    grantee.ID,
+		ResourceType: "agent_traces",
+		ResourceID:   &resID,
+		Permission:   "read",
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, decisions, 1)
 
-	// Query with a time range in the distant past should return nothing.
-	pastFrom := time.Now().UTC().Add(-48 * time.Hour)
-	pastTo := time.Now().UTC().Add(-24 * time.Hour)
-	decisions, total, err = testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, &pastFrom, &pastTo)
+	got, err := testDB.GetGrant(ctx, grant.OrgID, grant.ID)
 	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
+	assert.Equal(t, grant.ID, got.ID)
+	assert.Equal(t, grantor.ID, got.GrantorID)
+	assert.Equal(t, grantee.ID, got.GranteeID)
+	assert.Equal(t, "agent_traces", got.ResourceType)
+	assert.Equal(t, &resID, got.ResourceID)
+
+	// Not found case.
+	_, err = testDB.GetGrant(ctx, uuid.Nil, uuid.New())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestFindDecisionsMissingOutcomeEmbedding(t *testing.T) {
+func TestListGrantsByGrantee(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "missoe-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lgb-grantor-" + suffix,
+		Name:    "Grantor",
+		Role:    model.RoleAdmin,
+	})
 	require.NoError(t, err)
 
-	// Create a decision with an embedding but no outcome_embedding.
-	dims := 1024
-	vec := make([]float32, dims)
-	for i := range vec {
-		vec[i] = float32(i) / float32(dims)
-	}
-	embedding := pgvector.NewVector(vec)
-
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "missing_oe_test",
-		Outcome:      "needs_outcome_emb",
-		Confidence:   0.65,
-		Embedding:    &embedding,
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lgb-grantee-" + suffix,
+		Name:    "Grantee",
+		Role:    model.RoleReader,
 	})
 	require.NoError(t, err)
 
-	missing, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 1000)
-	require.NoError(t, err)
-
-	found := false
-	for _, m := range missing {
-		if m.ID == d.ID {
-			found = true
-			assert.Equal(t, "missing_oe_test", m.DecisionType)
-			assert.Equal(t, "needs_outcome_emb", m.Outcome)
-			break
-		}
+	// Create three grants for this grantee.
+	for i := range 3 {
+		resID := fmt.Sprintf("lgb-resource-%d-%s", i, suffix)
+		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
+			GrantorID:    grantor.ID,
+			GranteeID:    grantee.ID,
+			ResourceType: "agent_traces",
+			ResourceID:   &resID,
+			Permission:   "read",
+		})
+		require.NoError(t, err)
 	}
-	assert.True(t, found, "decision %s should appear in missing outcome embedding results", d.ID)
 
-	// After backfilling outcome_embedding, it should no longer appear.
-	err = testDB.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, embedding)
+	grants, err := testDB.ListGrantsByGrantee(ctx, uuid.Nil, grantee.ID)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(grants), 3, "should have at least 3 grants")
 
-	missing2, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 1000)
-	require.NoError(t, err)
-	for _, m := range missing2 {
-		assert.NotEqual(t, d.ID, m.ID, "decision should not appear after outcome_embedding backfill")
+	// Verify all belong to the grantee.
+	for _, g := range grants {
+		assert.Equal(t, grantee.ID, g.GranteeID)
 	}
 }
 
-func TestGetEvidenceByDecisions_BatchLookup(t *testing.T) {
+func TestGetDecision_WithOpts(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "ev-batch-" + suffix
+	agentID := "getdec-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Create two decisions, each with evidence.
-	d1, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "ev_batch", Outcome: "dec1", Confidence: 0.7,
+	reasoning := "decision with full data"
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "getdec_test",
+		Outcome:      "selected_option",
+		Confidence:   0.95,
+		Reasoning:    &reasoning,
 	})
 	require.NoError(t, err)
 
-	d2, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "ev_batch", Outcome: "dec2", Confidence: 0.8,
+	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+		{DecisionID: d.ID, Label: "Selected"},
 	})
 	require.NoError(t, err)
 
-	rel1 := float32(0.9)
-	rel2 := float32(0.7)
-	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
-		{DecisionID: d1.ID, OrgID: d1.OrgID, SourceType: model.SourceDocument, Content: "doc for d1", RelevanceScore: &rel1},
-		{DecisionID: d2.ID, OrgID: d2.OrgID, SourceType: model.SourceUserInput, Content: "input for d2", RelevanceScore: &rel2},
+	rel := float32(0.88)
+	_, err = testDB.CreateEvidence(ctx, model.Evidence{
+		DecisionID:     d.ID,
+		OrgID:          d.OrgID,
+		SourceType:     model.SourceToolOutput,
+		Content:        "Tool output data",
+		RelevanceScore: &rel,
 	})
 	require.NoError(t, err)
 
-	evMap, err := testDB.GetEvidenceByDecisions(ctx, []uuid.UUID{d1.ID, d2.ID}, uuid.Nil)
+	// Get without includes.
+	bare, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	assert.Len(t, evMap[d1.ID], 1)
-	assert.Len(t, evMap[d2.ID], 1)
-	assert.Equal(t, "doc for d1", evMap[d1.ID][0].Content)
-	assert.Equal(t, "input for d2", evMap[d2.ID][0].Content)
+	assert.Empty(t, bare.Alternatives)
+	assert.Empty(t, bare.Evidence)
 
-	// Empty slice should return nil.
-	empty, err := testDB.GetEvidenceByDecisions(ctx, []uuid.UUID{}, uuid.Nil)
+	// Get with includes.
+	full, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{
+		IncludeAlts:     true,
+		IncludeEvidence: true,
+	})
 	require.NoError(t, err)
-	assert.Nil(t, empty)
-}
-
-func TestCountConflicts(t *testing.T) {
-	ctx := context.Background()
+	assert.Len(t, full.Alternatives, 1)
+	assert.Len(t, full.Evidence, 1)
+	assert.Equal(t, "Selected", full.Alternatives[0].Label)
+	assert.Equal(t, "Tool output data", full.Evidence[0].Content)
 
-	// CountConflicts with no filters should return zero or more without error.
-	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{})
+	// CurrentOnly on an active decision should succeed.
+	current, err := testDB.GetDecision(ctx, d.OrgID, d.ID, storage.GetDecisionOpts{CurrentOnly: true})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
-}
-
-func TestRefreshConflicts_NoOp(t *testing.T) {
-	ctx := context.Background()
+	assert.Equal(t, d.ID, current.ID)
 
-	// RefreshConflicts is a no-op, should always succeed.
-	err := testDB.RefreshConflicts(ctx)
-	require.NoError(t, err)
+	// Not found case.
+	_, err = testDB.GetDecision(ctx, uuid.Nil, uuid.New(), storage.GetDecisionOpts{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestEnsureDefaultOrg_Idempotent(t *testing.T) {
+func TestListAgents_Pagination(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	// Should succeed even when called multiple times (ON CONFLICT DO NOTHING).
-	err := testDB.EnsureDefaultOrg(ctx)
-	require.NoError(t, err)
+	// Create a batch of agents with a unique suffix so we can count them.
+	const agentCount = 5
+	for i := range agentCount {
+		_, err := testDB.CreateAgent(ctx, model.Agent{
+			AgentID: fmt.Sprintf("la-%d-%s", i, suffix),
+			Name:    fmt.Sprintf("List Agent %d", i),
+			Role:    model.RoleAgent,
+		})
+		require.NoError(t, err)
+	}
 
-	err = testDB.EnsureDefaultOrg(ctx)
+	// List all agents in the default org (there may be more from other tests).
+	allAgents, err := testDB.ListAgents(ctx, uuid.Nil, 1000, 0)
 	require.NoError(t, err)
 
-	// Verify the default org exists via GetOrganization.
-	org, err := testDB.GetOrganization(ctx, uuid.Nil)
-	require.NoError(t, err)
-	assert.Equal(t, "Default", org.Name)
-	assert.Equal(t, "default", org.Slug)
-}
+	// Count how many of our agents appear.
+	var ours int
+	for _, a := range allAgents {
+		for i := range agentCount {
+			if a.AgentID == fmt.Sprintf("la-%d-%s", i, suffix) {
+				ours++
+			}
+		}
+	}
+	assert.Equal(t, agentCount, ours, "all created agents should appear in ListAgents")
 
-func TestGetOrganization_NotFound(t *testing.T) {
-	ctx := context.Background()
+	// Test pagination: limit=2, offset=0.
+	page1, err := testDB.ListAgents(ctx, uuid.Nil, 2, 0)
+	require.NoError(t, err)
+	assert.Len(t, page1, 2)
 
-	_, err := testDB.GetOrganization(ctx, uuid.New())
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	// Different offset should return different agents.
+	page2, err := testDB.ListAgents(ctx, uuid.Nil, 2, 2)
+	require.NoError(t, err)
+	assert.Len(t, page2, 2)
+	assert.NotEqual(t, page1[0].ID, page2[0].ID, "paginated pages should return different agents")
 }
 
-func TestInsertEvents_Empty(t *testing.T) {
+func TestCountAgents(t *testing.T) {
 	ctx := context.Background()
 
-	count, err := testDB.InsertEvents(ctx, nil)
+	count, err := testDB.CountAgents(ctx, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), count)
+	assert.Greater(t, count, 0, "default org should have at least one agent from earlier tests")
 }
 
-func TestCountAgentsGlobal(t *testing.T) {
+func TestGetSessionDecisions(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "session-" + suffix
+	sessionID := uuid.New()
 
-	count, err := testDB.CountAgentsGlobal(ctx)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Greater(t, count, 0, "there should be agents from earlier tests")
-}
 
-func TestGetAgentsByAgentIDGlobal(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "global-" + suffix
+	// Create 3 decisions in the same session.
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "session_test",
+			Outcome:      fmt.Sprintf("session_outcome_%d", i),
+			Confidence:   float32(i+1) * 0.25,
+			SessionID:    &sessionID,
+		})
+		require.NoError(t, err)
+	}
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID,
-		Name:    "Global Lookup Agent",
-		Role:    model.RoleAgent,
+	// Also create a decision with a different session to verify isolation.
+	otherSession := uuid.New()
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "session_test",
+		Outcome:      "other_session",
+		Confidence:   0.5,
+		SessionID:    &otherSession,
 	})
 	require.NoError(t, err)
 
-	agents, err := testDB.GetAgentsByAgentIDGlobal(ctx, agentID)
+	decisions, err := testDB.GetSessionDecisions(ctx, uuid.Nil, sessionID)
 	require.NoError(t, err)
-	require.Len(t, agents, 1)
-	assert.Equal(t, agentID, agents[0].AgentID)
-
-	// Not found case.
-	_, err = testDB.GetAgentsByAgentIDGlobal(ctx, "nonexistent-agent-"+suffix)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
+	assert.Len(t, decisions, 3, "should only return decisions from the target session")
 
-func TestHasDecisionsWithNullSearchVector(t *testing.T) {
-	ctx := context.Background()
+	for _, d := range decisions {
+		require.NotNil(t, d.SessionID)
+		assert.Equal(t, sessionID, *d.SessionID)
+	}
 
-	// This function checks for search_vector IS NULL. The trigger may or may not
-	// be present in the test schema, so we just verify the query runs without error.
-	_, err := testDB.HasDecisionsWithNullSearchVector(ctx)
-	require.NoError(t, err)
+	// Verify chronological ordering (valid_from ASC).
+	for i := 1; i < len(decisions); i++ {
+		assert.False(t, decisions[i].ValidFrom.Before(decisions[i-1].ValidFrom),
+			"decisions should be ordered by valid_from ASC")
+	}
 }
 
-func TestQueryDecisions_WithInclude(t *testing.T) {
+func TestListSessions(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "incl-" + suffix
+	agentID := "sessions-" + uuid.New().String()[:8]
+	first, second := uuid.New(), uuid.New()
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
+	create := func(sessionID *uuid.UUID, decisionType string) {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: decisionType,
+			Outcome:      "outcome " + uuid.NewString()[:8],
+			Confidence:   0.7,
+			SessionID:    sessionID,
+		})
+		require.NoError(t, err)
+	}
+	create(&first, "architecture")
+	create(&first, "security")
+	create(&first, "architecture")
+	create(&second, "code_review")
+	create(nil, "architecture") // no session: not listed
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "include_test",
-		Outcome:      "with_includes",
-		Confidence:   0.77,
-	})
+	sessions, total, err := testDB.ListSessions(ctx, uuid.Nil, agentID, 50, 0)
 	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, sessions, 2)
 
-	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
-		{DecisionID: d.ID, Label: "Alt1"},
-	})
-	require.NoError(t, err)
+	// Most recently active first.
+	assert.Equal(t, second, sessions[0].SessionID)
+	assert.Equal(t, 1, sessions[0].DecisionCount)
+	assert.Equal(t, []string{"code_review"}, sessions[0].DecisionTypes)
 
-	rel := float32(0.9)
-	_, err = testDB.CreateEvidence(ctx, model.Evidence{
-		DecisionID: d.ID, OrgID: d.OrgID, SourceType: model.SourceMemory,
-		Content: "memory content", RelevanceScore: &rel,
-	})
-	require.NoError(t, err)
+	assert.Equal(t, first, sessions[1].SessionID)
+	assert.Equal(t, 3, sessions[1].DecisionCount)
+	assert.Equal(t, []string{"architecture", "security"}, sessions[1].DecisionTypes)
+	assert.False(t, sessions[1].EndedAt.Before(sessions[1].StartedAt))
 
-	// Query with include=["alternatives","evidence"].
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{AgentIDs: []string{agentID}},
-		Include: []string{"alternatives", "evidence"},
-		Limit:   10,
-	})
+	page, total, err := testDB.ListSessions(ctx, uuid.Nil, agentID, 1, 1)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	require.Len(t, decisions, 1)
-	assert.Len(t, decisions[0].Alternatives, 1)
-	assert.Len(t, decisions[0].Evidence, 1)
+	assert.Equal(t, 2, total)
+	require.Len(t, page, 1)
+	assert.Equal(t, first, page[0].SessionID)
 }
 
-func TestQueryDecisions_SessionFilter(t *testing.T) {
+func TestCreateIntegrityProof_And_GetLatest(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "qsess-" + suffix
-	sessionID := uuid.New()
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	now := time.Now().UTC()
+	batchStart := now.Add(-1 * time.Hour)
+	batchEnd := now
+
+	proof1 := storage.IntegrityProof{
+		OrgID:         uuid.Nil,
+		BatchStart:    batchStart,
+		BatchEnd:      batchEnd,
+		DecisionCount: 10,
+		RootHash:      "abc123hash_first",
+		CreatedAt:     now.Add(-30 * time.Minute),
+	}
+	err := testDB.CreateIntegrityProof(ctx, proof1)
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "sess_filter",
-		Outcome:      "in_session",
-		Confidence:   0.8,
-		SessionID:    &sessionID,
-	})
+	prevRoot := "abc123hash_first"
+	proof2 := storage.IntegrityProof{
+		OrgID:         uuid.Nil,
+		BatchStart:    batchEnd,
+		BatchEnd:      now.Add(1 * time.Hour),
+		DecisionCount: 5,
+		RootHash:      "def456hash_second",
+		PreviousRoot:  &prevRoot,
+		CreatedAt:     now,
+	}
+	err = testDB.CreateIntegrityProof(ctx, proof2)
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "sess_filter",
-		Outcome:      "no_session",
-		Confidence:   0.6,
-	})
+	// GetLatestIntegrityProof should return the most recent one.
+	latest, err := testDB.GetLatestIntegrityProof(ctx, uuid.Nil)
 	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "def456hash_second", latest.RootHash)
+	assert.Equal(t, 5, latest.DecisionCount)
+	require.NotNil(t, latest.PreviousRoot)
+	assert.Equal(t, "abc123hash_first", *latest.PreviousRoot)
 
-	// Filter by session_id.
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{
-			AgentIDs:  []string{agentID},
-			SessionID: &sessionID,
-		},
-		Limit: 10,
-	})
+	// Non-existent org should return nil without error.
+	randomOrg := uuid.New()
+	noProof, err := testDB.GetLatestIntegrityProof(ctx, randomOrg)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	require.Len(t, decisions, 1)
-	assert.Equal(t, "in_session", decisions[0].Outcome)
+	assert.Nil(t, noProof)
 }
 
-func TestNewConflictsSinceByOrg(t *testing.T) {
+func TestGetDecisionHashesForBatch(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "hash-batch-" + suffix
 
-	beforeConflicts := time.Now().UTC().Add(-1 * time.Second)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 
-	agentA := "ncbo-a-" + suffix
-	agentB := "ncbo-b-" + suffix
-	decisionType := "ncbo_type_" + suffix
+	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
+	// Create several decisions. Each gets a content_hash from CreateDecision.
+	const count = 4
+	for i := range count {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "hash_test",
+			Outcome:      fmt.Sprintf("hash_outcome_%d", i),
+			Confidence:   float32(i+1) * 0.2,
+		})
+		require.NoError(t, err)
+	}
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: decisionType,
-		Outcome: "option_1", Confidence: 0.85,
-	})
-	require.NoError(t, err)
+	afterCreate := time.Now().UTC().Add(1 * time.Second)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: decisionType,
-		Outcome: "option_2", Confidence: 0.9,
-	})
+	hashes, err := testDB.GetDecisionHashesForBatch(ctx, uuid.Nil, beforeCreate, afterCreate)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(hashes), count, "should find at least %d hashes in the batch window", count)
 
-	topicSim := 0.88
-	outcomeDiv := 0.75
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "option_1", OutcomeB: "option_2",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
-	require.NoError(t, err)
+	// Verify lexicographic ordering.
+	for i := 1; i < len(hashes); i++ {
+		assert.LessOrEqual(t, hashes[i-1], hashes[i],
+			"hashes should be in lexicographic order: %s > %s at index %d", hashes[i-1], hashes[i], i)
+	}
 
-	conflicts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, beforeConflicts, 100)
+	// Verify all hashes are non-empty.
+	for _, h := range hashes {
+		assert.NotEmpty(t, h, "content hash should not be empty")
+	}
+}
+
+func TestListOrganizationIDs(t *testing.T) {
+	ctx := context.Background()
+
+	ids, err := testDB.ListOrganizationIDs(ctx)
 	require.NoError(t, err)
+	assert.NotEmpty(t, ids, "should have at least the default org")
 
-	found := false
-	for _, c := range conflicts {
-		if c.DecisionType == decisionType {
-			found = true
+	foundDefault := false
+	for _, id := range ids {
+		if id == uuid.Nil {
+			foundDefault = true
 			break
 		}
 	}
-	assert.True(t, found, "NewConflictsSinceByOrg should return conflicts for this org")
+	assert.True(t, foundDefault, "default org (uuid.Nil) should be in the list")
 }
 
-func TestRefreshAgentState(t *testing.T) {
+func TestWithRetry_SucceedsImmediately(t *testing.T) {
 	ctx := context.Background()
+	callCount := 0
 
-	// RefreshAgentState should succeed without error (REFRESH MATERIALIZED VIEW CONCURRENTLY).
-	err := testDB.RefreshAgentState(ctx)
+	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
+		callCount++
+		return nil
+	})
 	require.NoError(t, err)
+	assert.Equal(t, 1, callCount, "should only call fn once when it succeeds immediately")
 }
 
-func TestDeleteGrant_NotFound(t *testing.T) {
+func TestWithRetry_NonRetriableError(t *testing.T) {
 	ctx := context.Background()
+	callCount := 0
+	permanent := fmt.Errorf("permanent error")
 
-	err := testDB.DeleteGrant(ctx, uuid.Nil, uuid.New())
+	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
+		callCount++
+		return permanent
+	})
 	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 1, callCount, "non-retriable error should not trigger retry")
 }
 
-func TestBackfillOutcomeEmbedding_NoOpForMissing(t *testing.T) {
-	ctx := context.Background()
-
-	// Backfilling a non-existent decision should silently succeed (0 rows affected).
-	dims := 1024
-	vec := make([]float32, dims)
-	embedding := pgvector.NewVector(vec)
+func TestWithRetry_ContextCancellation(t *testing.T) {
+	// WithRetry with a cancelled context should exit promptly.
+	// We use a pgconn.PgError to trigger the retry path, then cancel context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Cancel immediately.
 
-	err := testDB.BackfillOutcomeEmbedding(ctx, uuid.New(), uuid.Nil, embedding)
+	callCount := 0
+	err := storage.WithRetry(ctx, 3, 10*time.Millisecond, func() error {
+		callCount++
+		// Return nil on first call since context is already cancelled,
+		// the loop won't sleep but fn is called once.
+		return nil
+	})
+	// The function returns nil because fn() returned nil.
 	require.NoError(t, err)
+	assert.Equal(t, 1, callCount)
 }
 
-func TestFindEmbeddedDecisionIDs(t *testing.T) {
+func TestGetDecisionsByAgent(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "embedded-ids-" + suffix
+	agentID := "byagent-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	emb := pgvector.NewVector(make([]float32, 1024))
-	outcomeEmb := pgvector.NewVector(make([]float32, 1024))
+	const decCount = 4
+	for i := range decCount {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "byagent_test",
+			Outcome:      fmt.Sprintf("agent_dec_%d", i),
+			Confidence:   float32(i+1) * 0.2,
+		})
+		require.NoError(t, err)
+	}
 
-	// Decision with both embeddings — should appear.
-	dBoth, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:            run.ID,
-		AgentID:          agentID,
-		DecisionType:     "test",
-		Outcome:          "has both embeddings",
-		Confidence:       0.8,
-		Embedding:        &emb,
-		OutcomeEmbedding: &outcomeEmb,
-		Metadata:         map[string]any{},
-	})
+	decisions, total, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, nil, nil)
 	require.NoError(t, err)
+	assert.Equal(t, decCount, total)
+	assert.Len(t, decisions, decCount)
 
-	// Decision with only embedding — should NOT appear.
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "test",
-		Outcome:      "has only embedding",
-		Confidence:   0.8,
-		Embedding:    &emb,
-		Metadata:     map[string]any{},
-	})
-	require.NoError(t, err)
+	// All returned decisions should belong to our agent.
+	for _, d := range decisions {
+		assert.Equal(t, agentID, d.AgentID)
+	}
 
-	// Decision with no embeddings — should NOT appear.
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "test",
-		Outcome:      "has no embeddings",
-		Confidence:   0.8,
-		Metadata:     map[string]any{},
-	})
-	require.NoError(t, err)
-
-	refs, err := testDB.FindEmbeddedDecisionIDs(ctx, 1000)
-	require.NoError(t, err)
-
-	var foundBoth bool
-	for _, r := range refs {
-		if r.ID == dBoth.ID {
-			foundBoth = true
-			assert.Equal(t, dBoth.OrgID, r.OrgID)
-		}
+	// Verify descending valid_from ordering.
+	for i := 1; i < len(decisions); i++ {
+		assert.False(t, decisions[i].ValidFrom.After(decisions[i-1].ValidFrom),
+			"decisions should be ordered by valid_from DESC")
 	}
-	assert.True(t, foundBoth, "decision with both embeddings should appear in results")
-}
-
-func TestFindEmbeddedDecisionIDs_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
 
-	// Passing 0 or negative limit should default to 1000 and not error.
-	refs, err := testDB.FindEmbeddedDecisionIDs(ctx, 0)
+	// Test pagination.
+	page1, total1, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 2, 0, nil, nil)
 	require.NoError(t, err)
-	assert.NotNil(t, refs) // May be empty or populated from other tests; either is fine.
+	assert.Equal(t, decCount, total1)
+	assert.Len(t, page1, 2)
 
-	refs, err = testDB.FindEmbeddedDecisionIDs(ctx, -1)
+	page2, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 2, 2, nil, nil)
 	require.NoError(t, err)
-	assert.NotNil(t, refs)
+	assert.Len(t, page2, 2)
+	assert.NotEqual(t, page1[0].ID, page2[0].ID, "pages should not overlap")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Claims storage (InsertClaims, FindClaimsByDecision,
-//        FindDecisionIDsMissingClaims, HasClaimsForDecision)
-// ---------------------------------------------------------------------------
-
-func TestInsertClaims_AndFindByDecision(t *testing.T) {
+func TestGetDecisionsByAgent_TimeRange(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "claims-" + suffix
+	agentID := "byagent-tr-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	emb := pgvector.NewVector(make([]float32, 1024))
-	d, err := testDB.CreateDecision(ctx, model.Decision{
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "claim_test",
-		Outcome:      "multi-claim outcome",
+		DecisionType: "tr_test",
+		Outcome:      "in_range",
 		Confidence:   0.8,
-		Embedding:    &emb,
 	})
 	require.NoError(t, err)
 
-	// Insert 3 claims with different embeddings.
-	claimEmbs := make([]pgvector.Vector, 3)
-	for i := range claimEmbs {
-		v := make([]float32, 1024)
-		v[i] = 1.0
-		claimEmbs[i] = pgvector.NewVector(v)
-	}
-
-	claims := []storage.Claim{
-		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 0, ClaimText: "First claim about architecture.", Embedding: &claimEmbs[0]},
-		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 1, ClaimText: "Second claim about security.", Embedding: &claimEmbs[1]},
-		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 2, ClaimText: "Third claim about performance.", Embedding: &claimEmbs[2]},
-	}
-	err = testDB.InsertClaims(ctx, claims)
-	require.NoError(t, err)
-
-	// Read them back.
-	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
-	require.NoError(t, err)
-	require.Len(t, got, 3)
-
-	// Verify ordering by claim_idx.
-	for i, c := range got {
-		assert.Equal(t, i, c.ClaimIdx, "claims should be ordered by claim_idx")
-		assert.Equal(t, d.ID, c.DecisionID)
-		assert.Equal(t, d.OrgID, c.OrgID)
-		assert.NotEqual(t, uuid.Nil, c.ID, "claim should have a generated UUID")
-		assert.NotNil(t, c.Embedding, "claim embedding should be stored")
-	}
-
-	// Verify claim texts.
-	assert.Equal(t, "First claim about architecture.", got[0].ClaimText)
-	assert.Equal(t, "Second claim about security.", got[1].ClaimText)
-	assert.Equal(t, "Third claim about performance.", got[2].ClaimText)
-}
-
-func TestInsertClaims_EmptySlice(t *testing.T) {
-	ctx := context.Background()
-
-	// Empty slice should be a no-op.
-	err := testDB.InsertClaims(ctx, nil)
+	from := time.Now().UTC().Add(-5 * time.Second)
+	to := time.Now().UTC().Add(5 * time.Second)
+	decisions, total, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, &from, &to)
 	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, decisions, 1)
 
-	err = testDB.InsertClaims(ctx, []storage.Claim{})
+	// Query with a time range in the distant past should return nothing.
+	pastFrom := time.Now().UTC().Add(-48 * time.Hour)
+	pastTo := time.Now().UTC().Add(-24 * time.Hour)
+	decisions, total, err = testDB.GetDecisionsByAgent(ctx, uuid.Nil, agentID, 10, 0, &pastFrom, &pastTo)
 	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
 }
 
-func TestInsertClaims_NilEmbedding(t *testing.T) {
+func TestFindDecisionsMissingOutcomeEmbedding(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "claims-nil-" + suffix
+	agentID := "missoe-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
+	// Create a decision with an embedding but no outcome_embedding.
+	dims := 1024
+	vec := make([]float32, dims)
+	for i := range vec {
+		vec[i] = float32(i) / float32(dims)
+	}
+	embedding := pgvector.NewVector(vec)
+
 	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "claim_nil_emb",
-		Outcome:      "claim without embedding",
-		Confidence:   0.7,
-	})
-	require.NoError(t, err)
-
-	// Insert a claim with nil embedding (allowed by schema — embedding is nullable).
-	err = testDB.InsertClaims(ctx, []storage.Claim{
-		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 0, ClaimText: "Claim with no embedding vector.", Embedding: nil},
+		DecisionType: "missing_oe_test",
+		Outcome:      "needs_outcome_emb",
+		Confidence:   0.65,
+		Embedding:    &embedding,
 	})
 	require.NoError(t, err)
 
-	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
-	require.NoError(t, err)
-	require.Len(t, got, 1)
-	assert.Nil(t, got[0].Embedding, "nil embedding should be preserved")
-	assert.Equal(t, "Claim with no embedding vector.", got[0].ClaimText)
-}
-
-func TestFindClaimsByDecision_NoClaims(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "claims-empty-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	missing, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 1000)
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "no_claims",
-		Outcome:      "decision without any claims",
-		Confidence:   0.5,
-	})
-	require.NoError(t, err)
+	found := false
+	for _, m := range missing {
+		if m.ID == d.ID {
+			found = true
+			assert.Equal(t, "missing_oe_test", m.DecisionType)
+			assert.Equal(t, "needs_outcome_emb", m.Outcome)
+			break
+		}
+	}
+	assert.True(t, found, "decision %s should appear in missing outcome embedding results", d.ID)
 
-	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
+	// After backfilling outcome_embedding, it should no longer appear.
+	err = testDB.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, embedding)
 	require.NoError(t, err)
-	assert.Empty(t, got, "decision with no claims should return empty slice")
-}
-
-func TestFindClaimsByDecision_NonexistentDecision(t *testing.T) {
-	ctx := context.Background()
 
-	got, err := testDB.FindClaimsByDecision(ctx, uuid.New(), uuid.New())
+	missing2, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 1000)
 	require.NoError(t, err)
-	assert.Empty(t, got, "nonexistent decision should return empty claims")
+	for _, m := range missing2 {
+		assert.NotEqual(t, d.ID, m.ID, "decision should not appear after outcome_embedding backfill")
+	}
 }
 
-func TestHasClaimsForDecision(t *testing.T) {
+func TestGetEvidenceByDecisions_BatchLookup(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "hasclaims-" + suffix
+	agentID := "ev-batch-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Decision A: will have claims.
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims",
-		Outcome: "will have claims", Confidence: 0.8,
+	// Create two decisions, each with evidence.
+	d1, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "ev_batch", Outcome: "dec1", Confidence: 0.7,
 	})
 	require.NoError(t, err)
 
-	// Decision B: will NOT have claims.
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims",
-		Outcome: "will not have claims", Confidence: 0.7,
+	d2, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "ev_batch", Outcome: "dec2", Confidence: 0.8,
 	})
 	require.NoError(t, err)
 
-	// Before inserting, both should return false.
-	has, err := testDB.HasClaimsForDecision(ctx, dA.ID, dA.OrgID)
-	require.NoError(t, err)
-	assert.False(t, has, "no claims yet for decision A")
-
-	has, err = testDB.HasClaimsForDecision(ctx, dB.ID, dB.OrgID)
-	require.NoError(t, err)
-	assert.False(t, has, "no claims for decision B")
-
-	// Insert claims for A.
-	err = testDB.InsertClaims(ctx, []storage.Claim{
-		{DecisionID: dA.ID, OrgID: dA.OrgID, ClaimIdx: 0, ClaimText: "A claim for decision A."},
+	rel1 := float32(0.9)
+	rel2 := float32(0.7)
+	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
+		{DecisionID: d1.ID, OrgID: d1.OrgID, SourceType: model.SourceDocument, Content: "doc for d1", RelevanceScore: &rel1},
+		{DecisionID: d2.ID, OrgID: d2.OrgID, SourceType: model.SourceUserInput, Content: "input for d2", RelevanceScore: &rel2},
 	})
 	require.NoError(t, err)
 
-	// A should now return true; B should still be false.
-	has, err = testDB.HasClaimsForDecision(ctx, dA.ID, dA.OrgID)
+	evMap, err := testDB.GetEvidenceByDecisions(ctx, []uuid.UUID{d1.ID, d2.ID}, uuid.Nil)
 	require.NoError(t, err)
-	assert.True(t, has, "decision A should have claims after insert")
+	assert.Len(t, evMap[d1.ID], 1)
+	assert.Len(t, evMap[d2.ID], 1)
+	assert.Equal(t, "doc for d1", evMap[d1.ID][0].Content)
+	assert.Equal(t, "input for d2", evMap[d2.ID][0].Content)
 
-	has, err = testDB.HasClaimsForDecision(ctx, dB.ID, dB.OrgID)
+	// Empty slice should return nil.
+	empty, err := testDB.GetEvidenceByDecisions(ctx, []uuid.UUID{}, uuid.Nil)
 	require.NoError(t, err)
-	assert.False(t, has, "decision B should still have no claims")
+	assert.Nil(t, empty)
 }
 
-func TestHasClaimsForDecision_NonexistentDecision(t *testing.T) {
+func TestCountConflicts(t *testing.T) {
 	ctx := context.Background()
 
-	has, err := testDB.HasClaimsForDecision(ctx, uuid.New(), uuid.New())
+	// CountConflicts with no filters should return zero or more without error.
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{})
 	require.NoError(t, err)
-	assert.False(t, has, "nonexistent decision should return false")
+	assert.GreaterOrEqual(t, count, 0)
 }
 
-func TestFindDecisionIDsMissingClaims(t *testing.T) {
+func TestRefreshConflicts_NoOp(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "missclaims-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// RefreshConflicts is a no-op, should always succeed.
+	err := testDB.RefreshConflicts(ctx)
 	require.NoError(t, err)
+}
 
-	emb := pgvector.NewVector(make([]float32, 1024))
+func TestEnsureDefaultOrg_Idempotent(t *testing.T) {
+	ctx := context.Background()
 
-	// Decision with embedding but no claims — should appear.
-	dMissing, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "missing_claims",
-		Outcome: "needs claims generated", Confidence: 0.8,
-		Embedding: &emb,
-	})
+	// Should succeed even when called multiple times (ON CONFLICT DO NOTHING).
+	err := testDB.EnsureDefaultOrg(ctx)
 	require.NoError(t, err)
 
-	// Decision with embedding AND claims — should NOT appear.
-	dHas, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims_already",
-		Outcome: "already has claims", Confidence: 0.9,
-		Embedding: &emb,
-	})
+	err = testDB.EnsureDefaultOrg(ctx)
 	require.NoError(t, err)
 
-	err = testDB.InsertClaims(ctx, []storage.Claim{
-		{DecisionID: dHas.ID, OrgID: dHas.OrgID, ClaimIdx: 0, ClaimText: "Existing claim."},
-	})
-	require.NoError(t, err)
-
-	// Decision without embedding — should NOT appear (no embedding to compare).
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "no_embedding",
-		Outcome: "no embedding at all", Confidence: 0.5,
-	})
+	// Verify the default org exists via GetOrganization.
+	org, err := testDB.GetOrganization(ctx, uuid.Nil)
 	require.NoError(t, err)
+	assert.Equal(t, "Default", org.Name)
+	assert.Equal(t, "default", org.Slug)
+}
 
-	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
-	require.NoError(t, err)
+func TestGetOrganization_NotFound(t *testing.T) {
+	ctx := context.Background()
 
-	foundMissing := false
-	for _, r := range refs {
-		if r.ID == dMissing.ID {
-			foundMissing = true
-			assert.Equal(t, dMissing.OrgID, r.OrgID)
-		}
-		// The decision with claims should never appear.
-		assert.NotEqual(t, dHas.ID, r.ID,
-			"decision with existing claims should not appear in missing list")
-	}
-	assert.True(t, foundMissing, "decision with embedding but no claims should appear")
+	_, err := testDB.GetOrganization(ctx, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
-func TestFindDecisionIDsMissingClaims_DefaultLimit(t *testing.T) {
+func TestInsertEvents_Empty(t *testing.T) {
 	ctx := context.Background()
 
-	// Zero or negative limit should default to 500 and not error.
-	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 0)
-	require.NoError(t, err)
-	_ = refs // may or may not be empty; just verify no error
-
-	refs, err = testDB.FindDecisionIDsMissingClaims(ctx, -1)
+	count, err := testDB.InsertEvents(ctx, nil)
 	require.NoError(t, err)
-	_ = refs
+	assert.Equal(t, int64(0), count)
 }
 
-// --- Decision immutability trigger tests (migration 036) ---
-
-func TestDecisionImmutability_BlocksCoreFieldUpdate(t *testing.T) {
+func TestCountAgentsGlobal(t *testing.T) {
 	ctx := context.Background()
 
-	// Create a decision via trace.
-	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	reasoning := "original reasoning"
-	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: "immut-test-agent",
-		OrgID:   orgID,
-		Decision: model.Decision{
-			DecisionType: "architecture",
-			Outcome:      "original outcome",
-			Confidence:   0.8,
-			Reasoning:    &reasoning,
-		},
-	})
+	count, err := testDB.CountAgentsGlobal(ctx)
 	require.NoError(t, err)
-
-	// Each immutable column should be rejected.
-	immutableUpdates := []struct {
-		column string
-		sql    string
-		args   []any
-	}{
-		{"outcome", `UPDATE decisions SET outcome = 'tampered' WHERE id = $1`, []any{d.ID}},
-		{"reasoning", `UPDATE decisions SET reasoning = 'tampered' WHERE id = $1`, []any{d.ID}},
-		{"confidence", `UPDATE decisions SET confidence = 0.01 WHERE id = $1`, []any{d.ID}},
-		{"decision_type", `UPDATE decisions SET decision_type = 'tampered' WHERE id = $1`, []any{d.ID}},
-		{"agent_id", `UPDATE decisions SET agent_id = 'evil' WHERE id = $1`, []any{d.ID}},
-		{"run_id", `UPDATE decisions SET run_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
-		{"org_id", `UPDATE decisions SET org_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
-		{"content_hash", `UPDATE decisions SET content_hash = 'tampered' WHERE id = $1`, []any{d.ID}},
-		{"valid_from", `UPDATE decisions SET valid_from = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
-		{"created_at", `UPDATE decisions SET created_at = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
-		{"transaction_time", `UPDATE decisions SET transaction_time = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
-	}
-
-	for _, tc := range immutableUpdates {
-		t.Run("blocked_"+tc.column, func(t *testing.T) {
-			_, err := testDB.Pool().Exec(ctx, tc.sql, tc.args...)
-			require.Error(t, err, "UPDATE to %s should be rejected by immutability trigger", tc.column)
-			assert.Contains(t, err.Error(), "immutable")
-		})
-	}
+	assert.Greater(t, count, 0, "there should be agents from earlier tests")
 }
 
-func TestDecisionImmutability_AllowsMutableFieldUpdates(t *testing.T) {
+func TestGetAgentsByAgentIDGlobal(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "global-" + suffix
 
-	// Create a decision.
-	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: "immut-mutable-test",
-		OrgID:   orgID,
-		Decision: model.Decision{
-			DecisionType: "test",
-			Outcome:      "mutable test outcome",
-			Confidence:   0.5,
-		},
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		Name:    "Global Lookup Agent",
+		Role:    model.RoleAgent,
 	})
 	require.NoError(t, err)
 
-	// These updates should all succeed (mutable columns).
-	mutableUpdates := []struct {
-		column string
-		sql    string
-		args   []any
-	}{
-		{"valid_to", `UPDATE decisions SET valid_to = now() WHERE id = $1`, []any{d.ID}},
-		{"embedding", `UPDATE decisions SET embedding = $1 WHERE id = $2`, []any{pgvector.NewVector(make([]float32, 1024)), d.ID}},
-		{"outcome_embedding", `UPDATE decisions SET outcome_embedding = $1 WHERE id = $2`, []any{pgvector.NewVector(make([]float32, 1024)), d.ID}},
-		{"precedent_ref", `UPDATE decisions SET precedent_ref = NULL WHERE id = $1`, []any{d.ID}},
-		{"supersedes_id", `UPDATE decisions SET supersedes_id = NULL WHERE id = $1`, []any{d.ID}},
-		{"completeness_score", `UPDATE decisions SET completeness_score = 0.99 WHERE id = $1`, []any{d.ID}},
-		{"metadata", `UPDATE decisions SET metadata = '{"test": true}' WHERE id = $1`, []any{d.ID}},
-		{"session_id", `UPDATE decisions SET session_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
-		{"agent_context", `UPDATE decisions SET agent_context = '{"enriched": true}' WHERE id = $1`, []any{d.ID}},
-	}
+	agents, err := testDB.GetAgentsByAgentIDGlobal(ctx, agentID)
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, agentID, agents[0].AgentID)
 
-	for _, tc := range mutableUpdates {
-		t.Run("allowed_"+tc.column, func(t *testing.T) {
-			_, err := testDB.Pool().Exec(ctx, tc.sql, tc.args...)
-			require.NoError(t, err, "UPDATE to %s should be allowed", tc.column)
-		})
-	}
+	// Not found case.
+	_, err = testDB.GetAgentsByAgentIDGlobal(ctx, "nonexistent-agent-"+suffix)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestDecisionImmutability_AllowsNoopUpdate(t *testing.T) {
+func TestHasDecisionsWithNullSearchVector(t *testing.T) {
 	ctx := context.Background()
 
-	// Create a decision.
-	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
-	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: "immut-noop-test",
-		OrgID:   orgID,
-		Decision: model.Decision{
-			DecisionType: "test",
-			Outcome:      "noop test",
-			Confidence:   0.5,
-		},
-	})
+	// This function checks for search_vector IS NULL. The trigger may or may not
+	// be present in the test schema, so we just verify the query runs without error.
+	_, err := testDB.HasDecisionsWithNullSearchVector(ctx)
 	require.NoError(t, err)
-
-	// Setting an immutable column to its current value should succeed (no actual change).
-	_, err = testDB.Pool().Exec(ctx,
-		`UPDATE decisions SET outcome = outcome WHERE id = $1`, d.ID)
-	require.NoError(t, err, "no-op update to immutable column should be allowed")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Assessments (CreateAssessment, ListAssessments, GetAssessmentSummary,
-//        GetAssessmentSummaryBatch, UpdateOutcomeScore)
-// ---------------------------------------------------------------------------
-
-func TestCreateAssessment_HappyPath(t *testing.T) {
+func TestQueryDecisions_WithInclude(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "assess-" + suffix
+	agentID := "incl-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
@@ -3084,741 +3619,765 @@ func TestCreateAssessment_HappyPath(t *testing.T) {
 	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "assessment_test",
-		Outcome:      "needs assessment",
-		Confidence:   0.8,
+		DecisionType: "include_test",
+		Outcome:      "with_includes",
+		Confidence:   0.77,
 	})
 	require.NoError(t, err)
 
-	notes := "Verified the output was accurate."
-	a, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      d.ID,
-		AssessorAgentID: "reviewer-agent",
-		Outcome:         model.AssessmentCorrect,
-		Notes:           &notes,
+	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+		{DecisionID: d.ID, Label: "Alt1"},
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, a.ID)
-	assert.Equal(t, d.ID, a.DecisionID)
-	assert.Equal(t, uuid.Nil, a.OrgID)
-	assert.Equal(t, "reviewer-agent", a.AssessorAgentID)
-	assert.Equal(t, model.AssessmentCorrect, a.Outcome)
-	assert.Equal(t, &notes, a.Notes)
-	assert.False(t, a.CreatedAt.IsZero())
-}
 
-func TestCreateAssessment_DecisionNotFound(t *testing.T) {
-	ctx := context.Background()
+	rel := float32(0.9)
+	_, err = testDB.CreateEvidence(ctx, model.Evidence{
+		DecisionID: d.ID, OrgID: d.OrgID, SourceType: model.SourceMemory,
+		Content: "memory content", RelevanceScore: &rel,
+	})
+	require.NoError(t, err)
 
-	_, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      uuid.New(),
-		AssessorAgentID: "reviewer",
-		Outcome:         model.AssessmentCorrect,
+	// Query with include=["alternatives","evidence"].
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{AgentIDs: []string{agentID}},
+		Include: []string{"alternatives", "evidence"},
+		Limit:   10,
 	})
-	require.ErrorIs(t, err, storage.ErrNotFound)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, decisions, 1)
+	assert.Len(t, decisions[0].Alternatives, 1)
+	assert.Len(t, decisions[0].Evidence, 1)
 }
 
-func TestListAssessments(t *testing.T) {
+func TestQueryDecisions_SessionFilter(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "listassess-" + suffix
+	agentID := "qsess-" + suffix
+	sessionID := uuid.New()
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "list_assessment_test",
-		Outcome:      "to be assessed multiple times",
-		Confidence:   0.75,
+		DecisionType: "sess_filter",
+		Outcome:      "in_session",
+		Confidence:   0.8,
+		SessionID:    &sessionID,
 	})
 	require.NoError(t, err)
 
-	// Create multiple assessments from different assessors.
-	for _, outcome := range []model.AssessmentOutcome{
-		model.AssessmentCorrect,
-		model.AssessmentIncorrect,
-		model.AssessmentPartiallyCorrect,
-	} {
-		_, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-			DecisionID:      d.ID,
-			AssessorAgentID: "assessor-" + string(outcome),
-			Outcome:         outcome,
-		})
-		require.NoError(t, err)
-	}
-
-	assessments, err := testDB.ListAssessments(ctx, uuid.Nil, d.ID)
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "sess_filter",
+		Outcome:      "no_session",
+		Confidence:   0.6,
+	})
 	require.NoError(t, err)
-	assert.Len(t, assessments, 3)
-
-	// Should be ordered by created_at DESC (newest first).
-	for i := 1; i < len(assessments); i++ {
-		assert.False(t, assessments[i].CreatedAt.After(assessments[i-1].CreatedAt),
-			"assessments should be ordered newest first")
-	}
-}
 
-func TestListAssessments_DecisionNotFound(t *testing.T) {
-	ctx := context.Background()
-
-	_, err := testDB.ListAssessments(ctx, uuid.Nil, uuid.New())
-	require.ErrorIs(t, err, storage.ErrNotFound)
+	// Filter by session_id.
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{
+			AgentIDs:  []string{agentID},
+			SessionID: &sessionID,
+		},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, "in_session", decisions[0].Outcome)
 }
 
-func TestGetAssessmentSummary(t *testing.T) {
+func TestQueryDecisions_EvidenceAndAlternativesFilters(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "assesssummary-" + suffix
+	agentID := "support-" + uuid.New().String()[:8]
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "summary_test",
-		Outcome:      "to summarize",
-		Confidence:   0.85,
-	})
-	require.NoError(t, err)
+	create := func(outcome string, confidence float32) model.Decision {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "support_filter",
+			Outcome:      outcome,
+			Confidence:   confidence,
+		})
+		require.NoError(t, err)
+		return d
+	}
+	bare := create("bare", 0.95)
+	withEvidence := create("evidence_only", 0.9)
+	withAlts := create("alternatives_only", 0.6)
+	withBoth := create("both", 0.92)
+
+	for _, d := range []model.Decision{withEvidence, withBoth} {
+		// Two evidence rows: the filter must not duplicate the decision.
+		for range 2 {
+			_, err = testDB.CreateEvidence(ctx, model.Evidence{
+				DecisionID: d.ID, OrgID: d.OrgID, SourceType: model.SourceMemory, Content: "support",
+			})
+			require.NoError(t, err)
+		}
+	}
+	for _, d := range []model.Decision{withAlts, withBoth} {
+		require.NoError(t, testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+			{DecisionID: d.ID, Label: "option a"}, {DecisionID: d.ID, Label: "option b"},
+		}))
+	}
 
-	// Two assessors say correct, one says incorrect.
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d.ID, AssessorAgentID: "reviewer-1", Outcome: model.AssessmentCorrect,
-	})
-	require.NoError(t, err)
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d.ID, AssessorAgentID: "reviewer-2", Outcome: model.AssessmentCorrect,
-	})
-	require.NoError(t, err)
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d.ID, AssessorAgentID: "reviewer-3", Outcome: model.AssessmentIncorrect,
-	})
-	require.NoError(t, err)
+	query := func(f model.QueryFilters) []string {
+		f.AgentIDs = []string{agentID}
+		decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+			Filters: f, OrderBy: "outcome", OrderDir: "asc", Limit: 10,
+		})
+		require.NoError(t, err)
+		require.Len(t, decisions, total)
+		outcomes := make([]string, len(decisions))
+		for i, d := range decisions {
+			outcomes[i] = d.Outcome
+		}
+		return outcomes
+	}
+	yes, no := true, false
 
-	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, d.ID)
-	require.NoError(t, err)
-	assert.Equal(t, 3, summary.Total)
-	assert.Equal(t, 2, summary.Correct)
-	assert.Equal(t, 1, summary.Incorrect)
-	assert.Equal(t, 0, summary.PartiallyCorrect)
+	assert.Equal(t, []string{"both", "evidence_only"}, query(model.QueryFilters{HasEvidence: &yes}))
+	assert.Equal(t, []string{"alternatives_only", "bare"}, query(model.QueryFilters{HasEvidence: &no}))
+	assert.Equal(t, []string{"alternatives_only", "both"}, query(model.QueryFilters{HasAlternatives: &yes}))
+	assert.Equal(t, []string{"bare", "evidence_only"}, query(model.QueryFilters{HasAlternatives: &no}))
+	assert.Equal(t, []string{"bare"}, query(model.QueryFilters{HasEvidence: &no, HasAlternatives: &no}))
+
+	// High confidence, zero support: the decisions that most need review.
+	minConf := float32(0.85)
+	assert.Equal(t, []string{"bare"}, query(model.QueryFilters{ConfidenceMin: &minConf, HasEvidence: &no}))
+	assert.Equal(t, bare.Outcome, query(model.QueryFilters{ConfidenceMin: &minConf, HasEvidence: &no, HasAlternatives: &no})[0])
 }
 
-func TestGetAssessmentSummary_LatestPerAssessorOnly(t *testing.T) {
+func TestNewConflictsSinceByOrg(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "assesslatest-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	beforeConflicts := time.Now().UTC().Add(-1 * time.Second)
+
+	agentA := "ncbo-a-" + suffix
+	agentB := "ncbo-b-" + suffix
+	decisionType := "ncbo_type_" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "latest_only_test",
-		Outcome:      "to assess repeatedly",
-		Confidence:   0.7,
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: decisionType,
+		Outcome: "option_1", Confidence: 0.85,
 	})
 	require.NoError(t, err)
 
-	// Same assessor changes verdict: first correct, then incorrect.
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d.ID, AssessorAgentID: "flip-flop-reviewer", Outcome: model.AssessmentCorrect,
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: decisionType,
+		Outcome: "option_2", Confidence: 0.9,
 	})
 	require.NoError(t, err)
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d.ID, AssessorAgentID: "flip-flop-reviewer", Outcome: model.AssessmentIncorrect,
+
+	topicSim := 0.88
+	outcomeDiv := 0.75
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "option_1", OutcomeB: "option_2",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, d.ID)
+	conflicts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, beforeConflicts, 100)
 	require.NoError(t, err)
-	// Only the latest should count: 1 incorrect (not 1 correct + 1 incorrect).
-	assert.Equal(t, 1, summary.Total)
-	assert.Equal(t, 0, summary.Correct)
-	assert.Equal(t, 1, summary.Incorrect)
+
+	found := false
+	for _, c := range conflicts {
+		if c.DecisionType == decisionType {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "NewConflictsSinceByOrg should return conflicts for this org")
 }
 
-func TestGetAssessmentSummaryBatch(t *testing.T) {
+func TestRefreshAgentState(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "assessbatch-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// RefreshAgentState should succeed without error (REFRESH MATERIALIZED VIEW CONCURRENTLY).
+	err := testDB.RefreshAgentState(ctx)
 	require.NoError(t, err)
+}
 
-	d1, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "batch_test",
-		Outcome: "d1", Confidence: 0.8,
-	})
-	require.NoError(t, err)
+func TestDeleteGrant_NotFound(t *testing.T) {
+	ctx := context.Background()
 
-	d2, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID, DecisionType: "batch_test",
-		Outcome: "d2", Confidence: 0.9,
-	})
-	require.NoError(t, err)
+	err := testDB.DeleteGrant(ctx, uuid.Nil, uuid.New())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
 
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d1.ID, AssessorAgentID: "rev-1", Outcome: model.AssessmentCorrect,
-	})
-	require.NoError(t, err)
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID: d2.ID, AssessorAgentID: "rev-1", Outcome: model.AssessmentPartiallyCorrect,
-	})
-	require.NoError(t, err)
+func TestBackfillOutcomeEmbedding_NoOpForMissing(t *testing.T) {
+	ctx := context.Background()
 
-	batch, err := testDB.GetAssessmentSummaryBatch(ctx, uuid.Nil, []uuid.UUID{d1.ID, d2.ID})
-	require.NoError(t, err)
-	assert.Len(t, batch, 2)
-	assert.Equal(t, 1, batch[d1.ID].Correct)
-	assert.Equal(t, 1, batch[d2.ID].PartiallyCorrect)
+	// Backfilling a non-existent decision should silently succeed (0 rows affected).
+	dims := 1024
+	vec := make([]float32, dims)
+	embedding := pgvector.NewVector(vec)
 
-	// Empty slice returns nil.
-	empty, err := testDB.GetAssessmentSummaryBatch(ctx, uuid.Nil, []uuid.UUID{})
+	err := testDB.BackfillOutcomeEmbedding(ctx, uuid.New(), uuid.Nil, embedding)
 	require.NoError(t, err)
-	assert.Nil(t, empty)
 }
 
-func TestUpdateOutcomeScore(t *testing.T) {
+func TestFindEmbeddedDecisionIDs(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "outscore-" + suffix
+	agentID := "embedded-ids-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
+	emb := pgvector.NewVector(make([]float32, 1024))
+	outcomeEmb := pgvector.NewVector(make([]float32, 1024))
+
+	// Decision with both embeddings — should appear.
+	dBoth, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:            run.ID,
+		AgentID:          agentID,
+		DecisionType:     "test",
+		Outcome:          "has both embeddings",
+		Confidence:       0.8,
+		Embedding:        &emb,
+		OutcomeEmbedding: &outcomeEmb,
+		Metadata:         map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Decision with only embedding — should NOT appear.
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "score_test",
-		Outcome:      "to be scored",
+		DecisionType: "test",
+		Outcome:      "has only embedding",
 		Confidence:   0.8,
+		Embedding:    &emb,
+		Metadata:     map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Set a score.
-	score := float32(0.92)
-	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, d.ID, &score)
+	// Decision with no embeddings — should NOT appear.
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "test",
+		Outcome:      "has no embeddings",
+		Confidence:   0.8,
+		Metadata:     map[string]any{},
+	})
 	require.NoError(t, err)
 
-	// Verify round-trip.
-	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	refs, err := testDB.FindEmbeddedDecisionIDs(ctx, 1000)
 	require.NoError(t, err)
-	require.NotNil(t, got.OutcomeScore)
-	assert.InDelta(t, 0.92, float64(*got.OutcomeScore), 0.001)
 
-	// Clear the score.
-	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, d.ID, nil)
+	var foundBoth bool
+	for _, r := range refs {
+		if r.ID == dBoth.ID {
+			foundBoth = true
+			assert.Equal(t, dBoth.OrgID, r.OrgID)
+		}
+	}
+	assert.True(t, foundBoth, "decision with both embeddings should appear in results")
+}
+
+func TestFindEmbeddedDecisionIDs_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
+
+	// Passing 0 or negative limit should default to 1000 and not error.
+	refs, err := testDB.FindEmbeddedDecisionIDs(ctx, 0)
 	require.NoError(t, err)
+	assert.NotNil(t, refs) // May be empty or populated from other tests; either is fine.
 
-	got2, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	refs, err = testDB.FindEmbeddedDecisionIDs(ctx, -1)
 	require.NoError(t, err)
-	assert.Nil(t, got2.OutcomeScore)
+	assert.NotNil(t, refs)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Agent stats (GetAgentStats, GetAgentListStats, UpdateAgent,
-//        TouchLastSeen)
+// Tests: Claims storage (InsertClaims, FindClaimsByDecision,
+//        FindDecisionIDsMissingClaims, HasClaimsForDecision)
 // ---------------------------------------------------------------------------
 
-func TestGetAgentStats(t *testing.T) {
+func TestInsertClaims_AndFindByDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "agentstats-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID,
-		Name:    "Stats Agent",
-		Role:    model.RoleAgent,
-	})
-	require.NoError(t, err)
+	agentID := "claims-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	reasoning := "some reasoning"
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "arch_decision",
-			Outcome:      fmt.Sprintf("outcome_%d", i),
-			Confidence:   float32(i+5) * 0.1,
-			Reasoning:    &reasoning,
-		})
-		require.NoError(t, err)
-	}
-	_, err = testDB.CreateDecision(ctx, model.Decision{
+	emb := pgvector.NewVector(make([]float32, 1024))
+	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "security_decision",
-		Outcome:      "security_outcome",
-		Confidence:   0.9,
+		DecisionType: "claim_test",
+		Outcome:      "multi-claim outcome",
+		Confidence:   0.8,
+		Embedding:    &emb,
 	})
 	require.NoError(t, err)
 
-	stats, err := testDB.GetAgentStats(ctx, uuid.Nil, agentID)
+	// Insert 3 claims with different embeddings.
+	claimEmbs := make([]pgvector.Vector, 3)
+	for i := range claimEmbs {
+		v := make([]float32, 1024)
+		v[i] = 1.0
+		claimEmbs[i] = pgvector.NewVector(v)
+	}
+
+	claims := []storage.Claim{
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 0, ClaimText: "First claim about architecture.", Embedding: &claimEmbs[0]},
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 1, ClaimText: "Second claim about security.", Embedding: &claimEmbs[1]},
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 2, ClaimText: "Third claim about performance.", Embedding: &claimEmbs[2]},
+	}
+	err = testDB.InsertClaims(ctx, claims)
 	require.NoError(t, err)
-	assert.Equal(t, 4, stats.DecisionCount)
-	assert.Greater(t, stats.AvgConfidence, 0.0)
-	assert.NotNil(t, stats.FirstDecision)
-	assert.NotNil(t, stats.LastDecision)
-	assert.Contains(t, stats.TypeBreakdown, "arch_decision")
-	assert.Equal(t, 3, stats.TypeBreakdown["arch_decision"])
-	assert.Contains(t, stats.TypeBreakdown, "security_decision")
-	assert.Equal(t, 1, stats.TypeBreakdown["security_decision"])
+
+	// Read them back.
+	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	// Verify ordering by claim_idx.
+	for i, c := range got {
+		assert.Equal(t, i, c.ClaimIdx, "claims should be ordered by claim_idx")
+		assert.Equal(t, d.ID, c.DecisionID)
+		assert.Equal(t, d.OrgID, c.OrgID)
+		assert.NotEqual(t, uuid.Nil, c.ID, "claim should have a generated UUID")
+		assert.NotNil(t, c.Embedding, "claim embedding should be stored")
+	}
+
+	// Verify claim texts.
+	assert.Equal(t, "First claim about architecture.", got[0].ClaimText)
+	assert.Equal(t, "Second claim about security.", got[1].ClaimText)
+	assert.Equal(t, "Third claim about performance.", got[2].ClaimText)
 }
 
-func TestGetAgentListStats(t *testing.T) {
+func TestInsertClaims_EmptySlice(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "liststats-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// Empty slice should be a no-op.
+	err := testDB.InsertClaims(ctx, nil)
 	require.NoError(t, err)
 
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "liststats_test",
-			Outcome:      fmt.Sprintf("outcome_%d", i),
-			Confidence:   0.7,
-		})
-		require.NoError(t, err)
-	}
-
-	stats, err := testDB.GetAgentListStats(ctx, uuid.Nil)
+	err = testDB.InsertClaims(ctx, []storage.Claim{})
 	require.NoError(t, err)
-	assert.Contains(t, stats, agentID)
-	assert.Equal(t, 3, stats[agentID].DecisionCount)
-	assert.NotNil(t, stats[agentID].LastDecisionAt)
 }
 
-func TestUpdateAgent(t *testing.T) {
+func TestInsertClaims_NilEmbedding(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "update-" + suffix
+	agentID := "claims-nil-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID:  agentID,
-		Name:     "Original Name",
-		Role:     model.RoleAgent,
-		Metadata: map[string]any{"key1": "val1"},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Update name only.
-	newName := "Updated Name"
-	updated, err := testDB.UpdateAgent(ctx, uuid.Nil, agentID, &newName, nil)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "claim_nil_emb",
+		Outcome:      "claim without embedding",
+		Confidence:   0.7,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, "Updated Name", updated.Name)
 
-	// Update metadata only (merge behavior).
-	updated2, err := testDB.UpdateAgent(ctx, uuid.Nil, agentID, nil, map[string]any{"key2": "val2"})
+	// Insert a claim with nil embedding (allowed by schema — embedding is nullable).
+	err = testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimIdx: 0, ClaimText: "Claim with no embedding vector.", Embedding: nil},
+	})
 	require.NoError(t, err)
-	assert.Equal(t, "Updated Name", updated2.Name)
-	assert.Equal(t, "val2", updated2.Metadata["key2"])
 
-	// Not found case.
-	_, err = testDB.UpdateAgent(ctx, uuid.Nil, "nonexistent-"+suffix, &newName, nil)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Nil(t, got[0].Embedding, "nil embedding should be preserved")
+	assert.Equal(t, "Claim with no embedding vector.", got[0].ClaimText)
 }
 
-func TestTouchLastSeen(t *testing.T) {
+func TestFindClaimsByDecision_NoClaims(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "touch-" + suffix
+	agentID := "claims-empty-" + suffix
 
-	agent, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID,
-		Name:    "Touch Agent",
-		Role:    model.RoleAgent,
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// last_seen should initially be nil.
-	got, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "no_claims",
+		Outcome:      "decision without any claims",
+		Confidence:   0.5,
+	})
 	require.NoError(t, err)
-	assert.Nil(t, got.LastSeen)
 
-	err = testDB.TouchLastSeen(ctx, agent.OrgID, agentID)
+	got, err := testDB.FindClaimsByDecision(ctx, d.ID, d.OrgID)
 	require.NoError(t, err)
+	assert.Empty(t, got, "decision with no claims should return empty slice")
+}
 
-	got2, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+func TestFindClaimsByDecision_NonexistentDecision(t *testing.T) {
+	ctx := context.Background()
+
+	got, err := testDB.FindClaimsByDecision(ctx, uuid.New(), uuid.New())
 	require.NoError(t, err)
-	assert.NotNil(t, got2.LastSeen, "last_seen should be set after TouchLastSeen")
+	assert.Empty(t, got, "nonexistent decision should return empty claims")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: API Keys
-// ---------------------------------------------------------------------------
-
-func TestAPIKeyLifecycle(t *testing.T) {
+func TestHasClaimsForDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "apikey-" + suffix
+	agentID := "hasclaims-" + suffix
 
-	// Create agent first.
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID,
-		Name:    "API Key Agent",
-		Role:    model.RoleAgent,
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Create API key with audit.
-	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
-		Prefix:    "ak_test_",
-		KeyHash:   "argon2hash_" + suffix,
-		AgentID:   agentID,
-		OrgID:     uuid.Nil,
-		Label:     "Test Key",
-		CreatedBy: "admin",
-	}, storage.MutationAuditEntry{
-		RequestID: "test-req-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "create_api_key", ResourceType: "api_key",
+	// Decision A: will have claims.
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims",
+		Outcome: "will have claims", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, key.ID)
-	assert.Equal(t, "ak_test_", key.Prefix)
 
-	// Get by ID.
-	gotKey, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
+	// Decision B: will NOT have claims.
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims",
+		Outcome: "will not have claims", Confidence: 0.7,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, key.ID, gotKey.ID)
-	assert.Equal(t, agentID, gotKey.AgentID)
 
-	// Get by prefix and agent.
-	gotByPrefix, err := testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "ak_test_")
+	// Before inserting, both should return false.
+	has, err := testDB.HasClaimsForDecision(ctx, dA.ID, dA.OrgID)
 	require.NoError(t, err)
-	assert.Equal(t, key.ID, gotByPrefix.ID)
-
-	// Get by prefix not found.
-	_, err = testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "nonexistent_prefix_")
-	require.ErrorIs(t, err, storage.ErrNotFound)
+	assert.False(t, has, "no claims yet for decision A")
 
-	// Touch last used.
-	err = testDB.TouchAPIKeyLastUsed(ctx, uuid.Nil, key.ID)
+	has, err = testDB.HasClaimsForDecision(ctx, dB.ID, dB.OrgID)
 	require.NoError(t, err)
+	assert.False(t, has, "no claims for decision B")
 
-	gotKey2, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
+	// Insert claims for A.
+	err = testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: dA.ID, OrgID: dA.OrgID, ClaimIdx: 0, ClaimText: "A claim for decision A."},
+	})
 	require.NoError(t, err)
-	assert.NotNil(t, gotKey2.LastUsedAt, "last_used_at should be set after touch")
 
-	// List API keys.
-	keys, total, err := testDB.ListAPIKeys(ctx, uuid.Nil, 50, 0)
+	// A should now return true; B should still be false.
+	has, err = testDB.HasClaimsForDecision(ctx, dA.ID, dA.OrgID)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 1)
-	foundKey := false
-	for _, k := range keys {
-		if k.ID == key.ID {
-			foundKey = true
-			break
-		}
-	}
-	assert.True(t, foundKey, "created key should appear in list")
+	assert.True(t, has, "decision A should have claims after insert")
 
-	// Get active keys by agent ID (global).
-	activeKeys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, agentID)
+	has, err = testDB.HasClaimsForDecision(ctx, dB.ID, dB.OrgID)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(activeKeys), 1)
+	assert.False(t, has, "decision B should still have no claims")
+}
 
-	// Get API keys by IDs (batch).
-	batch, err := testDB.GetAPIKeysByIDs(ctx, uuid.Nil, []uuid.UUID{key.ID})
-	require.NoError(t, err)
-	assert.Len(t, batch, 1)
+func TestHasClaimsForDecision_NonexistentDecision(t *testing.T) {
+	ctx := context.Background()
 
-	// Empty batch returns nil.
-	empty, err := testDB.GetAPIKeysByIDs(ctx, uuid.Nil, []uuid.UUID{})
+	has, err := testDB.HasClaimsForDecision(ctx, uuid.New(), uuid.New())
 	require.NoError(t, err)
-	assert.Nil(t, empty)
+	assert.False(t, has, "nonexistent decision should return false")
 }
 
-func TestRevokeAPIKey(t *testing.T) {
+func TestFindDecisionIDsMissingClaims(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "revkey-" + suffix
+	agentID := "missclaims-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, Name: "Revoke Key Agent", Role: model.RoleAgent,
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
-		Prefix: "rk_", KeyHash: "hash_" + suffix, AgentID: agentID,
-		OrgID: uuid.Nil, Label: "To Revoke", CreatedBy: "admin",
-	}, storage.MutationAuditEntry{
-		RequestID: "rev-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "create_api_key", ResourceType: "api_key",
-	})
-	require.NoError(t, err)
+	emb := pgvector.NewVector(make([]float32, 1024))
 
-	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, storage.MutationAuditEntry{
-		RequestID: "revoke-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "revoke_api_key", ResourceType: "api_key",
+	// Decision with embedding but no claims — should appear.
+	dMissing, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "missing_claims",
+		Outcome: "needs claims generated", Confidence: 0.8,
+		Embedding: &emb,
 	})
 	require.NoError(t, err)
 
-	revoked, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
-	require.NoError(t, err)
-	assert.NotNil(t, revoked.RevokedAt, "key should be revoked")
-
-	// Revoking again should fail.
-	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, storage.MutationAuditEntry{
-		RequestID: "revoke2-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "revoke_api_key", ResourceType: "api_key",
-	})
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "already revoked")
-
-	// Revoked key should not appear in active keys.
-	_, err = testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "rk_")
-	require.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-func TestRotateAPIKey(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "rotkey-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, Name: "Rotate Key Agent", Role: model.RoleAgent,
+	// Decision with embedding AND claims — should NOT appear.
+	dHas, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "has_claims_already",
+		Outcome: "already has claims", Confidence: 0.9,
+		Embedding: &emb,
 	})
 	require.NoError(t, err)
 
-	oldKey, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
-		Prefix: "old_", KeyHash: "oldhash_" + suffix, AgentID: agentID,
-		OrgID: uuid.Nil, Label: "Old Key", CreatedBy: "admin",
-	}, storage.MutationAuditEntry{
-		RequestID: "rot-old-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "create_api_key", ResourceType: "api_key",
+	err = testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: dHas.ID, OrgID: dHas.OrgID, ClaimIdx: 0, ClaimText: "Existing claim."},
 	})
 	require.NoError(t, err)
 
-	newKey, err := testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID, model.APIKey{
-		Prefix: "new_", KeyHash: "newhash_" + suffix, AgentID: agentID,
-		OrgID: uuid.Nil, Label: "New Key", CreatedBy: "admin",
-	}, storage.MutationAuditEntry{
-		RequestID: "rot-new-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "rotate_api_key", ResourceType: "api_key",
+	// Decision without embedding — should NOT appear (no embedding to compare).
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "no_embedding",
+		Outcome: "no embedding at all", Confidence: 0.5,
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, newKey.ID)
-	assert.Equal(t, "new_", newKey.Prefix)
 
-	// Old key should be revoked.
-	oldGot, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, oldKey.ID)
+	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
 	require.NoError(t, err)
-	assert.NotNil(t, oldGot.RevokedAt)
 
-	// Rotating an already revoked key should fail.
-	_, err = testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID, model.APIKey{
-		Prefix: "fail_", KeyHash: "failhash_" + suffix, AgentID: agentID,
-		OrgID: uuid.Nil, Label: "Fail Key", CreatedBy: "admin",
-	}, storage.MutationAuditEntry{
-		RequestID: "rot-fail-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		Operation: "rotate_api_key", ResourceType: "api_key",
-	})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	foundMissing := false
+	for _, r := range refs {
+		if r.ID == dMissing.ID {
+			foundMissing = true
+			assert.Equal(t, dMissing.OrgID, r.OrgID)
+		}
+		// The decision with claims should never appear.
+		assert.NotEqual(t, dHas.ID, r.ID,
+			"decision with existing claims should not appear in missing list")
+	}
+	assert.True(t, foundMissing, "decision with embedding but no claims should appear")
 }
 
-func TestCountDecisionsByAPIKey(t *testing.T) {
+func TestFindDecisionIDsMissingClaims_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "countkey-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// Zero or negative limit should default to 500 and not error.
+	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 0)
 	require.NoError(t, err)
+	_ = refs // may or may not be empty; just verify no error
 
-	from := time.Now().UTC().Add(-1 * time.Second)
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "count_test",
-			Outcome:      fmt.Sprintf("count_%d", i),
-			Confidence:   0.8,
-		})
-		require.NoError(t, err)
-	}
-	to := time.Now().UTC().Add(1 * time.Second)
-
-	counts, total, err := testDB.CountDecisionsByAPIKey(ctx, uuid.Nil, from, to)
+	refs, err = testDB.FindDecisionIDsMissingClaims(ctx, -1)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 3)
-	// Decisions without api_key_id are grouped under uuid.Nil.
-	assert.GreaterOrEqual(t, counts[uuid.Nil], 3)
+	_ = refs
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Tracehealth (GetDecisionQualityStats, GetEvidenceCoverageStats,
-//        GetConflictStatusCounts, GetOutcomeSignalsSummary)
-// ---------------------------------------------------------------------------
+// --- Decision immutability trigger tests (migration 036) ---
 
-func TestGetDecisionQualityStats(t *testing.T) {
+func TestDecisionImmutability_BlocksCoreFieldUpdate(t *testing.T) {
 	ctx := context.Background()
 
-	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
+	// Create a decision via trace.
+	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	reasoning := "original reasoning"
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: "immut-test-agent",
+		OrgID:   orgID,
+		Decision: model.Decision{
+			DecisionType: "architecture",
+			Outcome:      "original outcome",
+			Confidence:   0.8,
+			Reasoning:    &reasoning,
+		},
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, stats.Total, 0)
-	// From earlier tests, there should be at least some decisions with reasoning.
-	assert.GreaterOrEqual(t, stats.WithReasoning, 0)
-}
 
-func TestGetEvidenceCoverageStats(t *testing.T) {
-	ctx := context.Background()
+	// Each immutable column should be rejected.
+	immutableUpdates := []struct {
+		column string
+		sql    string
+		args   []any
+	}{
+		{"outcome", `UPDATE decisions SET outcome = 'tampered' WHERE id = $1`, []any{d.ID}},
+		{"reasoning", `UPDATE decisions SET reasoning = 'tampered' WHERE id = $1`, []any{d.ID}},
+		{"confidence", `UPDATE decisions SET confidence = 0.01 WHERE id = $1`, []any{d.ID}},
+		{"decision_type", `UPDATE decisions SET decision_type = 'tampered' WHERE id = $1`, []any{d.ID}},
+		{"agent_id", `UPDATE decisions SET agent_id = 'evil' WHERE id = $1`, []any{d.ID}},
+		{"run_id", `UPDATE decisions SET run_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
+		{"org_id", `UPDATE decisions SET org_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
+		{"content_hash", `UPDATE decisions SET content_hash = 'tampered' WHERE id = $1`, []any{d.ID}},
+		{"valid_from", `UPDATE decisions SET valid_from = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
+		{"created_at", `UPDATE decisions SET created_at = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
+		{"transaction_time", `UPDATE decisions SET transaction_time = now() + interval '1 hour' WHERE id = $1`, []any{d.ID}},
+	}
 
-	stats, err := testDB.GetEvidenceCoverageStats(ctx, uuid.Nil, nil, nil)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, stats.TotalDecisions, 0)
-	assert.GreaterOrEqual(t, stats.WithEvidence, 0)
-	assert.Equal(t, stats.TotalDecisions-stats.WithEvidence, stats.WithoutEvidenceCount)
+	for _, tc := range immutableUpdates {
+		t.Run("blocked_"+tc.column, func(t *testing.T) {
+			_, err := testDB.Pool().Exec(ctx, tc.sql, tc.args...)
+			require.Error(t, err, "UPDATE to %s should be rejected by immutability trigger", tc.column)
+			assert.Contains(t, err.Error(), "immutable")
+		})
+	}
 }
 
-func TestGetConflictStatusCounts(t *testing.T) {
+func TestDecisionImmutability_AllowsMutableFieldUpdates(t *testing.T) {
 	ctx := context.Background()
 
-	counts, err := testDB.GetConflictStatusCounts(ctx, uuid.Nil, nil, nil)
+	// Create a decision.
+	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: "immut-mutable-test",
+		OrgID:   orgID,
+		Decision: model.Decision{
+			DecisionType: "test",
+			Outcome:      "mutable test outcome",
+			Confidence:   0.5,
+		},
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Total, 0)
-	assert.Equal(t, counts.Total, counts.Open+counts.Resolved+counts.FalsePositive)
+
+	// These updates should all succeed (mutable columns).
+	mutableUpdates := []struct {
+		column string
+		sql    string
+		args   []any
+	}{
+		{"valid_to", `UPDATE decisions SET valid_to = now() WHERE id = $1`, []any{d.ID}},
+		{"embedding", `UPDATE decisions SET embedding = $1 WHERE id = $2`, []any{pgvector.NewVector(make([]float32, 1024)), d.ID}},
+		{"outcome_embedding", `UPDATE decisions SET outcome_embedding = $1 WHERE id = $2`, []any{pgvector.NewVector(make([]float32, 1024)), d.ID}},
+		{"precedent_ref", `UPDATE decisions SET precedent_ref = NULL WHERE id = $1`, []any{d.ID}},
+		{"supersedes_id", `UPDATE decisions SET supersedes_id = NULL WHERE id = $1`, []any{d.ID}},
+		{"completeness_score", `UPDATE decisions SET completeness_score = 0.99 WHERE id = $1`, []any{d.ID}},
+		{"metadata", `UPDATE decisions SET metadata = '{"test": true}' WHERE id = $1`, []any{d.ID}},
+		{"session_id", `UPDATE decisions SET session_id = $1 WHERE id = $2`, []any{uuid.New(), d.ID}},
+		{"agent_context", `UPDATE decisions SET agent_context = '{"enriched": true}' WHERE id = $1`, []any{d.ID}},
+	}
+
+	for _, tc := range mutableUpdates {
+		t.Run("allowed_"+tc.column, func(t *testing.T) {
+			_, err := testDB.Pool().Exec(ctx, tc.sql, tc.args...)
+			require.NoError(t, err, "UPDATE to %s should be allowed", tc.column)
+		})
+	}
 }
 
-func TestGetOutcomeSignalsSummary(t *testing.T) {
+func TestDecisionImmutability_AllowsNoopUpdate(t *testing.T) {
 	ctx := context.Background()
 
-	summary, err := testDB.GetOutcomeSignalsSummary(ctx, uuid.Nil, nil, nil)
+	// Create a decision.
+	orgID := uuid.MustParse("00000000-0000-0000-0000-000000000000")
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: "immut-noop-test",
+		OrgID:   orgID,
+		Decision: model.Decision{
+			DecisionType: "test",
+			Outcome:      "noop test",
+			Confidence:   0.5,
+		},
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, summary.DecisionsTotal, 0)
-	assert.GreaterOrEqual(t, summary.NeverSuperseded, 0)
+
+	// Setting an immutable column to its current value should succeed (no actual change).
+	_, err = testDB.Pool().Exec(ctx,
+		`UPDATE decisions SET outcome = outcome WHERE id = $1`, d.ID)
+	require.NoError(t, err, "no-op update to immutable column should be allowed")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Retention
+// Tests: Assessments (CreateAssessment, ListAssessments, GetAssessmentSummary,
+//        GetAssessmentSummaryBatch, UpdateOutcomeScore)
 // ---------------------------------------------------------------------------
 
-func TestRetentionPolicyLifecycle(t *testing.T) {
+func TestCreateAssessment_HappyPath(t *testing.T) {
 	ctx := context.Background()
-	retentionInterval := 24 * time.Hour
-
-	// Default org should have no retention policy.
-	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
-	require.NoError(t, err)
-	assert.Nil(t, policy.RetentionDays)
+	suffix := uuid.New().String()[:8]
+	agentID := "assess-" + suffix
 
-	// Set a retention policy.
-	days := 90
-	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"compliance"})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	policy2, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
-	require.NoError(t, err)
-	require.NotNil(t, policy2.RetentionDays)
-	assert.Equal(t, 90, *policy2.RetentionDays)
-	assert.Equal(t, []string{"compliance"}, policy2.RetentionExcludeTypes)
-
-	// GetOrgsWithRetention should now include the default org.
-	configs, err := testDB.GetOrgsWithRetention(ctx)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "assessment_test",
+		Outcome:      "needs assessment",
+		Confidence:   0.8,
+	})
 	require.NoError(t, err)
-	found := false
-	for _, c := range configs {
-		if c.OrgID == uuid.Nil {
-			found = true
-			assert.Equal(t, 90, c.RetentionDays)
-			break
-		}
-	}
-	assert.True(t, found, "default org should appear in orgs with retention")
 
-	// Clear the retention policy.
-	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
+	notes := "Verified the output was accurate."
+	a, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      d.ID,
+		AssessorAgentID: "reviewer-agent",
+		Outcome:         model.AssessmentCorrect,
+		Notes:           &notes,
+	})
 	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, a.ID)
+	assert.Equal(t, d.ID, a.DecisionID)
+	assert.Equal(t, uuid.Nil, a.OrgID)
+	assert.Equal(t, "reviewer-agent", a.AssessorAgentID)
+	assert.Equal(t, model.AssessmentCorrect, a.Outcome)
+	assert.Equal(t, &notes, a.Notes)
+	assert.False(t, a.CreatedAt.IsZero())
+}
 
-	policy3, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
-	require.NoError(t, err)
-	assert.Nil(t, policy3.RetentionDays)
+func TestCreateAssessment_DecisionNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      uuid.New(),
+		AssessorAgentID: "reviewer",
+		Outcome:         model.AssessmentCorrect,
+	})
+	require.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestRetentionHoldLifecycle(t *testing.T) {
+func TestListAssessments(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "hold-" + suffix
+	agentID := "listassess-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
+	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "hold_test",
-		Outcome:      "held decision",
-		Confidence:   0.8,
-	})
-	require.NoError(t, err)
-
-	now := time.Now().UTC()
-	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
-		OrgID:     uuid.Nil,
-		Reason:    "Legal investigation " + suffix,
-		HoldFrom:  now.Add(-24 * time.Hour),
-		HoldTo:    now.Add(24 * time.Hour),
-		CreatedBy: "admin",
+		DecisionType: "list_assessment_test",
+		Outcome:      "to be assessed multiple times",
+		Confidence:   0.75,
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, hold.ID)
-	assert.Equal(t, "Legal investigation "+suffix, hold.Reason)
 
-	// List holds.
-	holds, err := testDB.ListHolds(ctx, uuid.Nil)
-	require.NoError(t, err)
-	foundHold := false
-	for _, h := range holds {
-		if h.ID == hold.ID {
-			foundHold = true
-			assert.Nil(t, h.ReleasedAt)
-			break
-		}
+	// Create multiple assessments from different assessors.
+	for _, outcome := range []model.AssessmentOutcome{
+		model.AssessmentCorrect,
+		model.AssessmentIncorrect,
+		model.AssessmentPartiallyCorrect,
+	} {
+		_, err := testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+			DecisionID:      d.ID,
+			AssessorAgentID: "assessor-" + string(outcome),
+			Outcome:         outcome,
+		})
+		require.NoError(t, err)
 	}
-	assert.True(t, foundHold, "created hold should appear in list")
 
-	// Check if hold exists for agent.
-	hasHold, err := testDB.ActiveHoldsExistForAgent(ctx, uuid.Nil, agentID)
+	assessments, err := testDB.ListAssessments(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	assert.True(t, hasHold, "active hold should cover the agent's decisions")
+	assert.Len(t, assessments, 3)
 
-	// Release the hold.
-	released, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
-	require.NoError(t, err)
-	assert.True(t, released)
+	// Should be ordered by created_at DESC (newest first).
+	for i := 1; i < len(assessments); i++ {
+		assert.False(t, assessments[i].CreatedAt.After(assessments[i-1].CreatedAt),
+			"assessments should be ordered newest first")
+	}
+}
 
-	// Release again should return false (already released).
-	released2, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
-	require.NoError(t, err)
-	assert.False(t, released2)
+func TestListAssessments_DecisionNotFound(t *testing.T) {
+	ctx := context.Background()
 
-	// After release, hold should no longer affect the agent.
-	hasHold2, err := testDB.ActiveHoldsExistForAgent(ctx, uuid.Nil, agentID)
-	require.NoError(t, err)
-	assert.False(t, hasHold2, "released hold should not block agent")
+	_, err := testDB.ListAssessments(ctx, uuid.Nil, uuid.New())
+	require.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestActiveHoldsExistForDecision(t *testing.T) {
+func TestGetAssessmentSummary(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "holdtest-" + suffix
+	agentID := "assesssummary-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
@@ -3826,3065 +4385,2721 @@ func TestActiveHoldsExistForDecision(t *testing.T) {
 	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "hold_dec_test",
-		Outcome:      "covered by hold",
-		Confidence:   0.7,
+		DecisionType: "summary_test",
+		Outcome:      "to summarize",
+		Confidence:   0.85,
 	})
 	require.NoError(t, err)
 
-	// No hold yet.
-	hasHold, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
-	require.NoError(t, err)
-	assert.False(t, hasHold)
-
-	// Create a hold covering this decision's time range.
-	now := time.Now().UTC()
-	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
-		OrgID:    uuid.Nil,
-		Reason:   "Decision hold test",
-		HoldFrom: now.Add(-1 * time.Hour),
-		HoldTo:   now.Add(1 * time.Hour),
-		// No filters — covers all decision types and agents.
-		CreatedBy: "admin",
+	// Two assessors say correct, one says incorrect.
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d.ID, AssessorAgentID: "reviewer-1", Outcome: model.AssessmentCorrect,
 	})
 	require.NoError(t, err)
-
-	hasHold2, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
-	require.NoError(t, err)
-	assert.True(t, hasHold2, "active hold should cover the decision")
-
-	// Release and verify.
-	_, err = testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
-	require.NoError(t, err)
-
-	hasHold3, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d.ID, AssessorAgentID: "reviewer-2", Outcome: model.AssessmentCorrect,
+	})
 	require.NoError(t, err)
-	assert.False(t, hasHold3)
-}
-
-func TestDeletionLogLifecycle(t *testing.T) {
-	ctx := context.Background()
-
-	logID, err := testDB.StartDeletionLog(ctx, uuid.Nil, "manual", "admin", map[string]any{"reason": "test"})
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d.ID, AssessorAgentID: "reviewer-3", Outcome: model.AssessmentIncorrect,
+	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, logID)
 
-	err = testDB.CompleteDeletionLog(ctx, uuid.Nil, logID, map[string]any{"decisions": 5})
+	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 2, summary.Correct)
+	assert.Equal(t, 1, summary.Incorrect)
+	assert.Equal(t, 0, summary.PartiallyCorrect)
 }
 
-func TestCountEligibleDecisions(t *testing.T) {
+func TestGetAssessmentSummary_LatestPerAssessorOnly(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "eligible-" + suffix
+	agentID := "assesslatest-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "eligible_test",
-			Outcome:      fmt.Sprintf("eligible_%d", i),
-			Confidence:   0.7,
-		})
-		require.NoError(t, err)
-	}
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "latest_only_test",
+		Outcome:      "to assess repeatedly",
+		Confidence:   0.7,
+	})
+	require.NoError(t, err)
 
-	before := time.Now().UTC().Add(1 * time.Second)
-	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, nil, nil)
+	// Same assessor changes verdict: first correct, then incorrect.
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d.ID, AssessorAgentID: "flip-flop-reviewer", Outcome: model.AssessmentCorrect,
+	})
+	require.NoError(t, err)
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d.ID, AssessorAgentID: "flip-flop-reviewer", Outcome: model.AssessmentIncorrect,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Decisions, int64(3))
 
-	// With agent filter.
-	a := agentID
-	counts2, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, nil, &a)
+	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), counts2.Decisions)
+	// Only the latest should count: 1 incorrect (not 1 correct + 1 incorrect).
+	assert.Equal(t, 1, summary.Total)
+	assert.Equal(t, 0, summary.Correct)
+	assert.Equal(t, 1, summary.Incorrect)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Conflicts (GetConflictCount, GetConflictCountsBatch, GetConflict,
-//        UpdateConflictStatusWithAudit)
-// ---------------------------------------------------------------------------
-
-func TestGetConflictCount_ForDecision(t *testing.T) {
+func TestGetAssessmentSummaryBatch(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "assessbatch-" + suffix
 
-	agentA := "cc-a-" + suffix
-	agentB := "cc-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "cc_test",
-		Outcome: "yes", Confidence: 0.8,
+	d1, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "batch_test",
+		Outcome: "d1", Confidence: 0.8,
 	})
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "cc_test",
-		Outcome: "no", Confidence: 0.9,
+	d2, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "batch_test",
+		Outcome: "d2", Confidence: 0.9,
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.92
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "cc_test", DecisionTypeB: "cc_test",
-		OutcomeA: "yes", OutcomeB: "no",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d1.ID, AssessorAgentID: "rev-1", Outcome: model.AssessmentCorrect,
+	})
+	require.NoError(t, err)
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID: d2.ID, AssessorAgentID: "rev-1", Outcome: model.AssessmentPartiallyCorrect,
 	})
 	require.NoError(t, err)
 
-	count, err := testDB.GetConflictCount(ctx, dA.ID, uuid.Nil)
+	batch, err := testDB.GetAssessmentSummaryBatch(ctx, uuid.Nil, []uuid.UUID{d1.ID, d2.ID})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 1)
+	assert.Len(t, batch, 2)
+	assert.Equal(t, 1, batch[d1.ID].Correct)
+	assert.Equal(t, 1, batch[d2.ID].PartiallyCorrect)
 
-	// Nonexistent decision should have 0 conflicts.
-	count2, err := testDB.GetConflictCount(ctx, uuid.New(), uuid.Nil)
+	// Empty slice returns nil.
+	empty, err := testDB.GetAssessmentSummaryBatch(ctx, uuid.Nil, []uuid.UUID{})
 	require.NoError(t, err)
-	assert.Equal(t, 0, count2)
+	assert.Nil(t, empty)
 }
 
-func TestGetConflictCountsBatch(t *testing.T) {
+func TestUpdateOutcomeScore(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "outscore-" + suffix
 
-	agentA := "ccb-a-" + suffix
-	agentB := "ccb-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "ccb_test",
-		Outcome: "left", Confidence: 0.8,
-	})
-	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "ccb_test",
-		Outcome: "right", Confidence: 0.9,
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "score_test",
+		Outcome:      "to be scored",
+		Confidence:   0.8,
 	})
 	require.NoError(t, err)
-	dC, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "ccb_other",
-		Outcome: "solo", Confidence: 0.7,
-	})
+
+	// Set a score.
+	score := float32(0.92)
+	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, d.ID, &score)
 	require.NoError(t, err)
 
-	topicSim := 0.90
-	outcomeDiv := 0.80
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "ccb_test", DecisionTypeB: "ccb_test",
-		OutcomeA: "left", OutcomeB: "right",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	// Verify round-trip.
+	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
+	require.NotNil(t, got.OutcomeScore)
+	assert.InDelta(t, 0.92, float64(*got.OutcomeScore), 0.001)
 
-	counts, err := testDB.GetConflictCountsBatch(ctx, []uuid.UUID{dA.ID, dB.ID, dC.ID}, uuid.Nil)
+	// Clear the score.
+	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, d.ID, nil)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts[dA.ID], 1)
-	assert.GreaterOrEqual(t, counts[dB.ID], 1)
-	assert.Equal(t, 0, counts[dC.ID]) // no conflict
 
-	// Empty batch.
-	emptyCounts, err := testDB.GetConflictCountsBatch(ctx, []uuid.UUID{}, uuid.Nil)
+	got2, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	assert.Empty(t, emptyCounts)
+	assert.Nil(t, got2.OutcomeScore)
 }
 
-func TestGetConflict(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: Agent stats (GetAgentStats, GetAgentListStats, UpdateAgent,
+//        TouchLastSeen)
+// ---------------------------------------------------------------------------
+
+func TestGetAgentStats(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "agentstats-" + suffix
 
-	agentA := "gc-a-" + suffix
-	agentB := "gc-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "gc_test",
-		Outcome: "option_a", Confidence: 0.8,
-	})
-	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "gc_test",
-		Outcome: "option_b", Confidence: 0.9,
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		Name:    "Stats Agent",
+		Role:    model.RoleAgent,
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.88
-	outcomeDiv := 0.82
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "gc_test", DecisionTypeB: "gc_test",
-		OutcomeA: "option_a", OutcomeB: "option_b",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	got, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	reasoning := "some reasoning"
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "arch_decision",
+			Outcome:      fmt.Sprintf("outcome_%d", i),
+			Confidence:   float32(i+5) * 0.1,
+			Reasoning:    &reasoning,
+		})
+		require.NoError(t, err)
+	}
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "security_decision",
+		Outcome:      "security_outcome",
+		Confidence:   0.9,
+	})
 	require.NoError(t, err)
-	require.NotNil(t, got)
-	assert.Equal(t, conflictID, got.ID)
-	assert.Equal(t, "open", got.Status)
 
-	// Not found.
-	notFound, err := testDB.GetConflict(ctx, uuid.New(), uuid.Nil)
+	stats, err := testDB.GetAgentStats(ctx, uuid.Nil, agentID)
 	require.NoError(t, err)
-	assert.Nil(t, notFound)
+	assert.Equal(t, 4, stats.DecisionCount)
+	assert.Greater(t, stats.AvgConfidence, 0.0)
+	assert.NotNil(t, stats.FirstDecision)
+	assert.NotNil(t, stats.LastDecision)
+	assert.Contains(t, stats.TypeBreakdown, "arch_decision")
+	assert.Equal(t, 3, stats.TypeBreakdown["arch_decision"])
+	assert.Contains(t, stats.TypeBreakdown, "security_decision")
+	assert.Equal(t, 1, stats.TypeBreakdown["security_decision"])
 }
 
-func TestUpdateConflictStatusWithAudit(t *testing.T) {
+func TestGetAgentStats_Headline(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "agentstats-mix-" + suffix
+	peerID := "agentstats-peer-" + suffix
 
-	agentA := "ucs-a-" + suffix
-	agentB := "ucs-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "ucs_test",
-		Outcome: "left", Confidence: 0.8,
-	})
+	_, err := testDB.CreateAgent(ctx, model.Agent{AgentID: agentID, Name: "Mix", Role: model.RoleAgent})
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "ucs_test",
-		Outcome: "right", Confidence: 0.9,
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-
-	topicSim := 0.9
-	outcomeDiv := 0.8
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "ucs_test", DecisionTypeB: "ucs_test",
-		OutcomeA: "left", OutcomeB: "right",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	peerRun, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: peerID})
 	require.NoError(t, err)
 
-	// Transition to false_positive (terminal status, sets resolved_by/resolved_at).
-	fpNote := "Not a real conflict."
-	fpLabel := "unrelated_false_positive"
-	oldStatus, err := testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"false_positive", "admin-agent", &fpNote, nil, &fpLabel,
-		storage.MutationAuditEntry{
-			RequestID: "fp-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin-agent", ActorRole: "admin",
-			Operation: "false_positive_conflict", ResourceType: "conflict",
+	sessionA, sessionB := uuid.New(), uuid.New()
+	create := func(runID uuid.UUID, agent, decisionType, outcome string, conf float32, session *uuid.UUID) model.Decision {
+		t.Helper()
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: runID, AgentID: agent, DecisionType: decisionType,
+			Outcome: outcome, Confidence: conf, SessionID: session,
+		})
+		require.NoError(t, err)
+		return d
+	}
+	conflict := func(a, b model.Decision) {
+		t.Helper()
+		sig := 0.7
+		_, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+			ConflictKind: model.ConflictKindCrossAgent, DecisionAID: a.ID, DecisionBID: b.ID, OrgID: uuid.Nil,
+			AgentA: a.AgentID, AgentB: b.AgentID, DecisionTypeA: a.DecisionType, DecisionTypeB: b.DecisionType,
+			OutcomeA: a.Outcome, OutcomeB: b.Outcome, Significance: &sig, ScoringMethod: "text",
 		})
+		require.NoError(t, err)
+	}
+
+	// Active: d1 (t_a, 0.6), d2's revision (t_b, 1.0), d3 (t_a, 0.9).
+	// Superseded: d2 (t_b, 0.8). Sessions: A (d1, d2) and B (revision).
+	d1 := create(run.ID, agentID, "stats_t_a", "one", 0.6, &sessionA)
+	d2 := create(run.ID, agentID, "stats_t_b", "two", 0.8, &sessionA)
+	create(run.ID, agentID, "stats_t_a", "three", 0.9, nil)
+	peer := create(peerRun.ID, peerID, "stats_t_a", "other", 0.5, nil)
+
+	// One conflict stays open; the one on d2 is resolved by its revision.
+	conflict(d1, peer)
+	conflict(d2, peer)
+	revised, err := testDB.ReviseDecision(ctx, d2.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "stats_t_b",
+		Outcome: "two-revised", Confidence: 1.0, SessionID: &sessionB,
+	}, nil)
 	require.NoError(t, err)
-	assert.Equal(t, "open", oldStatus)
+	require.NoError(t, testDB.TouchLastSeen(ctx, uuid.Nil, agentID))
 
-	got, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	stats, err := testDB.GetAgentStats(ctx, uuid.Nil, agentID)
 	require.NoError(t, err)
-	assert.Equal(t, "false_positive", got.Status)
-	require.NotNil(t, got.ResolvedBy)
-	assert.Equal(t, "admin-agent", *got.ResolvedBy)
-	assert.Nil(t, got.WinningDecisionID, "false_positive should not set winner")
+	assert.Equal(t, 3, stats.DecisionCount, "active decisions")
+	assert.Equal(t, 4, stats.TotalDecisions)
+	assert.Equal(t, 1, stats.RevisedCount)
+	assert.Equal(t, 2, stats.DistinctSessions)
+	assert.Equal(t, 2, stats.DistinctDecisionTypes)
+	assert.Equal(t, map[string]int{"stats_t_a": 2, "stats_t_b": 1}, stats.TypeBreakdown)
+	assert.InDelta(t, (0.6+1.0+0.9)/3, stats.AvgConfidence, 1e-4)
+	assert.Equal(t, 1, stats.OpenConflicts)
+	require.NotNil(t, stats.LastActiveAt)
+	assert.False(t, stats.LastActiveAt.Before(revised.CreatedAt), "last_seen is newer than the last revision")
+	assert.WithinDuration(t, time.Now(), *stats.LastActiveAt, time.Minute)
+}
 
-	// Transition to resolved with a winner (overriding false_positive).
-	resNote := "Right approach is better."
-	oldStatus2, err := testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "admin-agent", &resNote, &dB.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "resolve-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin-agent", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
+func TestGetConfidenceHistogram(t *testing.T) {
+	ctx := context.Background()
+	agentID := "histogram-" + uuid.New().String()[:8]
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	confidences := []float32{0, 0.05, 0.25, 0.55, 0.95, 1}
+	for i, c := range confidences {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "architecture",
+			Outcome:      fmt.Sprintf("outcome_%d", i),
+			Confidence:   c,
 		})
+		require.NoError(t, err)
+	}
+
+	buckets, err := testDB.GetConfidenceHistogram(ctx, uuid.Nil, agentID, 4)
 	require.NoError(t, err)
-	assert.Equal(t, "false_positive", oldStatus2)
+	require.Len(t, buckets, 4)
 
-	got2, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	counts := make([]int, len(buckets))
+	sum := 0
+	for i, b := range buckets {
+		counts[i] = b.Count
+		sum += b.Count
+	}
+	assert.Equal(t, len(confidences), sum)
+	// 1.0 lands in the last bucket rather than an overflow bucket.
+	assert.Equal(t, []int{2, 1, 1, 2}, counts)
+	assert.InDelta(t, 0.0, buckets[0].Low, 1e-9)
+	assert.InDelta(t, 0.25, buckets[0].High, 1e-9)
+	assert.InDelta(t, 1.0, buckets[3].High, 1e-9)
+
+	empty, err := testDB.GetConfidenceHistogram(ctx, uuid.Nil, "histogram-nobody", 10)
 	require.NoError(t, err)
-	assert.Equal(t, "resolved", got2.Status)
-	require.NotNil(t, got2.ResolvedBy)
-	assert.Equal(t, "admin-agent", *got2.ResolvedBy)
-	require.NotNil(t, got2.WinningDecisionID)
-	assert.Equal(t, dB.ID, *got2.WinningDecisionID)
+	require.Len(t, empty, 10)
+	for _, b := range empty {
+		assert.Zero(t, b.Count)
+	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Conflict reopening archives resolution metadata
-// ---------------------------------------------------------------------------
-
-func TestInsertScoredConflict_ReopenArchivesResolution(t *testing.T) {
+func TestGetAgentListStats(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "liststats-" + suffix
 
-	agentA := "reopen-a-" + suffix
-	agentB := "reopen-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "reopen_test",
-		Outcome: "use Redis", Confidence: 0.8,
-	})
-	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "reopen_test",
-		Outcome: "use Memcached", Confidence: 0.7,
-	})
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "liststats_test",
+			Outcome:      fmt.Sprintf("outcome_%d", i),
+			Confidence:   0.7,
+		})
+		require.NoError(t, err)
+	}
+
+	stats, err := testDB.GetAgentListStats(ctx, uuid.Nil)
 	require.NoError(t, err)
+	assert.Contains(t, stats, agentID)
+	assert.Equal(t, 3, stats[agentID].DecisionCount)
+	assert.NotNil(t, stats[agentID].LastDecisionAt)
+}
 
-	// Step 1: Insert a conflict (starts as open).
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "reopen_test", DecisionTypeB: "reopen_test",
-		OutcomeA: "use Redis", OutcomeB: "use Memcached",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+func TestUpdateAgent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "update-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID:  agentID,
+		Name:     "Original Name",
+		Role:     model.RoleAgent,
+		Metadata: map[string]any{"key1": "val1"},
 	})
 	require.NoError(t, err)
 
-	// Step 2: Resolve the conflict with a winner.
-	resNote := "Redis preferred — already running in prod."
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "human-reviewer", &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "resolve-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "human-reviewer", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	// Update name only.
+	newName := "Updated Name"
+	updated, err := testDB.UpdateAgent(ctx, uuid.Nil, agentID, &newName, nil)
 	require.NoError(t, err)
+	assert.Equal(t, "Updated Name", updated.Name)
 
-	// Verify it's resolved with full metadata.
-	resolved, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	// Update metadata only (merge behavior).
+	updated2, err := testDB.UpdateAgent(ctx, uuid.Nil, agentID, nil, map[string]any{"key2": "val2"})
 	require.NoError(t, err)
-	assert.Equal(t, "resolved", resolved.Status)
-	require.NotNil(t, resolved.ResolvedBy)
-	assert.Equal(t, "human-reviewer", *resolved.ResolvedBy)
-	require.NotNil(t, resolved.WinningDecisionID)
-	assert.Equal(t, dA.ID, *resolved.WinningDecisionID)
+	assert.Equal(t, "Updated Name", updated2.Name)
+	assert.Equal(t, "val2", updated2.Metadata["key2"])
 
-	// Step 3: Re-insert the same conflict pair (simulating scorer re-detection).
-	newSig := 0.95
-	conflictID2, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "reopen_test", DecisionTypeB: "reopen_test",
-		OutcomeA: "use Redis", OutcomeB: "use Memcached",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &newSig, ScoringMethod: "text",
+	// Not found case.
+	_, err = testDB.UpdateAgent(ctx, uuid.Nil, "nonexistent-"+suffix, &newName, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestTouchLastSeen(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "touch-" + suffix
+
+	agent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		Name:    "Touch Agent",
+		Role:    model.RoleAgent,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, conflictID, conflictID2, "upsert should return the same conflict ID")
 
-	// Step 4: Verify the conflict is reopened with NULLed resolution fields.
-	reopened, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	// last_seen should initially be nil.
+	got, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
 	require.NoError(t, err)
-	assert.Equal(t, "open", reopened.Status, "conflict should be reopened")
-	assert.Nil(t, reopened.ResolvedBy, "resolved_by should be NULL after reopening")
-	assert.Nil(t, reopened.ResolvedAt, "resolved_at should be NULL after reopening")
-	assert.Nil(t, reopened.ResolutionNote, "resolution_note should be NULL after reopening")
-	assert.Nil(t, reopened.WinningDecisionID, "winning_decision_id should be NULL after reopening")
+	assert.Nil(t, got.LastSeen)
 
-	// Step 5: Verify the resolution was archived in conflict_resolutions.
-	var archivedResolvedBy string
-	var archivedResNote *string
-	var archivedWinner *uuid.UUID
-	var archivedResolvedAt time.Time
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT resolved_by, resolution_note, winning_decision_id, resolved_at
-		 FROM conflict_resolutions
-		 WHERE conflict_id = $1 AND org_id = $2`,
-		conflictID, uuid.Nil,
-	).Scan(&archivedResolvedBy, &archivedResNote, &archivedWinner, &archivedResolvedAt)
-	require.NoError(t, err, "should find archived resolution in conflict_resolutions")
-	assert.Equal(t, "human-reviewer", archivedResolvedBy)
-	require.NotNil(t, archivedResNote)
-	assert.Equal(t, "Redis preferred — already running in prod.", *archivedResNote)
-	require.NotNil(t, archivedWinner)
-	assert.Equal(t, dA.ID, *archivedWinner)
-	assert.False(t, archivedResolvedAt.IsZero(), "archived resolved_at should be non-zero")
+	err = testDB.TouchLastSeen(ctx, agent.OrgID, agentID)
+	require.NoError(t, err)
+
+	got2, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+	require.NoError(t, err)
+	assert.NotNil(t, got2.LastSeen, "last_seen should be set after TouchLastSeen")
 }
 
-func TestInsertScoredConflict_ReopenWithGroupIDArchivesResolution(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: API Keys
+// ---------------------------------------------------------------------------
+
+func TestAPIKeyLifecycle(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "apikey-" + suffix
 
-	agentA := "reopen-grp-a-" + suffix
-	agentB := "reopen-grp-b-" + suffix
+	// Create agent first.
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		Name:    "API Key Agent",
+		Role:    model.RoleAgent,
+	})
+	require.NoError(t, err)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	// Create API key with audit.
+	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix:    "ak_test_",
+		KeyHash:   "argon2hash_" + suffix,
+		AgentID:   agentID,
+		OrgID:     uuid.Nil,
+		Label:     "Test Key",
+		CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "test-req-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "create_api_key", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	assert.NotEqual(t, uuid.Nil, key.ID)
+	assert.Equal(t, "ak_test_", key.Prefix)
+
+	// Get by ID.
+	gotKey, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
+	assert.Equal(t, key.ID, gotKey.ID)
+	assert.Equal(t, agentID, gotKey.AgentID)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "reopen_grp_test",
-		Outcome: "use Postgres", Confidence: 0.8,
-	})
+	// Get by prefix and agent.
+	gotByPrefix, err := testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "ak_test_")
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "reopen_grp_test",
-		Outcome: "use MySQL", Confidence: 0.7,
-	})
+	assert.Equal(t, key.ID, gotByPrefix.ID)
+
+	// Get by prefix not found.
+	_, err = testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "nonexistent_prefix_")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Touch last used.
+	err = testDB.TouchAPIKeyLastUsed(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
 
-	// Pre-create a group (simulates FindOrCreateTopicGroup).
-	var groupID uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'reopen_grp_test', 'database choice')
-		 RETURNING id`,
-		uuid.Nil, agentA, agentB,
-	).Scan(&groupID)
+	gotKey2, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
+	assert.NotNil(t, gotKey2.LastUsedAt, "last_used_at should be set after touch")
 
-	// Insert conflict with pre-computed group_id.
-	topicSim := 0.88
-	outcomeDiv := 0.9
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "reopen_grp_test", DecisionTypeB: "reopen_grp_test",
-		OutcomeA: "use Postgres", OutcomeB: "use MySQL",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-		GroupID: &groupID,
-	})
+	// List API keys.
+	keys, total, err := testDB.ListAPIKeys(ctx, uuid.Nil, 50, 0)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 1)
+	foundKey := false
+	for _, k := range keys {
+		if k.ID == key.ID {
+			foundKey = true
+			break
+		}
+	}
+	assert.True(t, foundKey, "created key should appear in list")
 
-	// Resolve it.
-	resNote := "Postgres wins — better JSON support."
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "tech-lead", &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "resolve-grp-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "tech-lead", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	// Get active keys by agent ID (global).
+	activeKeys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, agentID)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(activeKeys), 1)
 
-	// Re-insert with the same GroupID (triggers insertScoredConflictWithGroup path).
-	conflictID2, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "reopen_grp_test", DecisionTypeB: "reopen_grp_test",
-		OutcomeA: "use Postgres", OutcomeB: "use MySQL",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-		GroupID: &groupID,
-	})
+	// Get API keys by IDs (batch).
+	batch, err := testDB.GetAPIKeysByIDs(ctx, uuid.Nil, []uuid.UUID{key.ID})
 	require.NoError(t, err)
-	assert.Equal(t, conflictID, conflictID2)
+	assert.Len(t, batch, 1)
 
-	// Verify reopened.
-	reopened, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	// Empty batch returns nil.
+	empty, err := testDB.GetAPIKeysByIDs(ctx, uuid.Nil, []uuid.UUID{})
 	require.NoError(t, err)
-	assert.Equal(t, "open", reopened.Status)
-	assert.Nil(t, reopened.WinningDecisionID)
+	assert.Nil(t, empty)
+}
 
-	// Verify archive.
-	var archivedResolvedBy string
-	var archivedWinner *uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT resolved_by, winning_decision_id
-		 FROM conflict_resolutions
-		 WHERE conflict_id = $1 AND org_id = $2`,
-		conflictID, uuid.Nil,
-	).Scan(&archivedResolvedBy, &archivedWinner)
-	require.NoError(t, err, "should find archived resolution for group-path reopening")
-	assert.Equal(t, "tech-lead", archivedResolvedBy)
-	require.NotNil(t, archivedWinner)
-	assert.Equal(t, dA.ID, *archivedWinner)
-}
-
-func TestInsertScoredConflict_NoArchiveWhenNotResolved(t *testing.T) {
+func TestRevokeAPIKey(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "revkey-" + suffix
 
-	agentA := "noarch-a-" + suffix
-	agentB := "noarch-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, Name: "Revoke Key Agent", Role: model.RoleAgent,
+	})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "noarch_test",
-		Outcome: "approach A", Confidence: 0.8,
+	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix: "rk_", KeyHash: "hash_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "To Revoke", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rev-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "create_api_key", ResourceType: "api_key",
 	})
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "noarch_test",
-		Outcome: "approach B", Confidence: 0.7,
+
+	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, storage.MutationAuditEntry{
+		RequestID: "revoke-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "revoke_api_key", ResourceType: "api_key",
 	})
 	require.NoError(t, err)
 
-	// Insert a conflict (open).
-	topicSim := 0.9
-	outcomeDiv := 0.8
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "noarch_test", DecisionTypeB: "noarch_test",
-		OutcomeA: "approach A", OutcomeB: "approach B",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	revoked, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt, "key should be revoked")
 
-	// Re-insert without resolving first.
-	newSig := 0.95
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "noarch_test", DecisionTypeB: "noarch_test",
-		OutcomeA: "approach A", OutcomeB: "approach B",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &newSig, ScoringMethod: "text",
+	// Revoking again should fail.
+	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, storage.MutationAuditEntry{
+		RequestID: "revoke2-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "revoke_api_key", ResourceType: "api_key",
 	})
-	require.NoError(t, err)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already revoked")
 
-	// Verify no archive was created (the conflict was never resolved).
-	var count int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT count(*) FROM conflict_resolutions WHERE conflict_id = $1 AND org_id = $2`,
-		conflictID, uuid.Nil,
-	).Scan(&count)
-	require.NoError(t, err)
-	assert.Equal(t, 0, count, "no archive should exist for a conflict that was never resolved")
+	// Revoked key should not appear in active keys.
+	_, err = testDB.GetAPIKeyByPrefixAndAgent(ctx, agentID, "rk_")
+	require.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Events (InsertEventsIdempotent)
-// ---------------------------------------------------------------------------
-
-func TestInsertEventsIdempotent(t *testing.T) {
+func TestUpdateAPIKeyHash(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "idem-evt-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	now := time.Now().UTC()
-	events := make([]model.AgentEvent, 5)
-	for i := range events {
-		events[i] = model.AgentEvent{
-			ID:          uuid.New(),
-			RunID:       run.ID,
-			EventType:   model.EventToolCallCompleted,
-			SequenceNum: int64(i + 1),
-			OccurredAt:  now,
-			AgentID:     agentID,
-			Payload:     map[string]any{"step": i},
-			CreatedAt:   now,
-		}
-	}
+	agentID := "rehash-" + suffix
 
-	// First insert.
-	inserted, err := testDB.InsertEventsIdempotent(ctx, events)
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, Name: "Rehash Agent", Role: model.RoleAgent,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(5), inserted)
 
-	// Second insert should be idempotent (0 new rows).
-	inserted2, err := testDB.InsertEventsIdempotent(ctx, events)
+	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix: "rh_", KeyHash: "old_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "Rehash", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rehash-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "create_api_key", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), inserted2)
 
-	// Verify only 5 events exist.
-	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	require.NoError(t, testDB.UpdateAPIKeyHash(ctx, uuid.Nil, key.ID, "old_"+suffix, "new_"+suffix))
+	got, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
-	assert.Len(t, got, 5)
-}
+	assert.Equal(t, "new_"+suffix, got.KeyHash)
 
-func TestInsertEventsIdempotent_Empty(t *testing.T) {
-	ctx := context.Background()
-
-	inserted, err := testDB.InsertEventsIdempotent(ctx, nil)
+	// A stale old hash (e.g. a concurrent upgrade already won) is a no-op.
+	require.NoError(t, testDB.UpdateAPIKeyHash(ctx, uuid.Nil, key.ID, "old_"+suffix, "other_"+suffix))
+	got, err = testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), inserted)
+	assert.Equal(t, "new_"+suffix, got.KeyHash)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Claims (MarkClaimEmbeddingFailed, ClearClaimEmbeddingFailure,
-//        FindRetriableClaimFailures)
-// ---------------------------------------------------------------------------
-
-func TestClaimEmbeddingFailureLifecycle(t *testing.T) {
+func TestRotateAPIKey(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "claimfail-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentID := "rotkey-" + suffix
 
-	emb := pgvector.NewVector(make([]float32, 1024))
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID:        run.ID,
-		AgentID:      agentID,
-		DecisionType: "claim_fail_test",
-		Outcome:      "will fail claims",
-		Confidence:   0.8,
-		Embedding:    &emb,
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, Name: "Rotate Key Agent", Role: model.RoleAgent,
 	})
 	require.NoError(t, err)
 
-	// Mark as failed.
-	err = testDB.MarkClaimEmbeddingFailed(ctx, d.ID, d.OrgID)
-	require.NoError(t, err)
-
-	// Mark again (should increment attempts).
-	err = testDB.MarkClaimEmbeddingFailed(ctx, d.ID, d.OrgID)
+	oldKey, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix: "old_", KeyHash: "oldhash_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "Old Key", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rot-old-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "create_api_key", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
 
-	// The decision should no longer appear in missing claims (it's failed, not missing).
-	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
+	newKey, err := testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID, model.APIKey{
+		Prefix: "new_", KeyHash: "newhash_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "New Key", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rot-new-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "rotate_api_key", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
-	for _, r := range refs {
-		assert.NotEqual(t, d.ID, r.ID, "failed decision should not appear in missing claims")
-	}
+	assert.NotEqual(t, uuid.Nil, newKey.ID)
+	assert.Equal(t, "new_", newKey.Prefix)
 
-	// Clear the failure.
-	err = testDB.ClearClaimEmbeddingFailure(ctx, d.ID, d.OrgID)
+	// Old key should be revoked.
+	oldGot, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, oldKey.ID)
 	require.NoError(t, err)
+	assert.NotNil(t, oldGot.RevokedAt)
 
-	// After clearing, it should appear in missing claims again.
-	refs2, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
-	require.NoError(t, err)
-	found := false
-	for _, r := range refs2 {
-		if r.ID == d.ID {
-			found = true
-			break
-		}
-	}
-	assert.True(t, found, "decision should reappear in missing claims after clearing failure")
+	// Rotating an already revoked key should fail.
+	_, err = testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID, model.APIKey{
+		Prefix: "fail_", KeyHash: "failhash_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "Fail Key", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rot-fail-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "rotate_api_key", ResourceType: "api_key",
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Grants (ListGrants)
-// ---------------------------------------------------------------------------
-
-func TestListGrants(t *testing.T) {
+func TestCountDecisionsByAPIKey(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "countkey-" + suffix
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lg-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
-	})
-	require.NoError(t, err)
-
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "lg-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
+	from := time.Now().UTC().Add(-1 * time.Second)
 	for i := range 3 {
-		resID := fmt.Sprintf("lg-resource-%d-%s", i, suffix)
-		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
-			GrantorID:    grantor.ID,
-			GranteeID:    grantee.ID,
-			ResourceType: "agent_traces",
-			ResourceID:   &resID,
-			Permission:   "read",
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "count_test",
+			Outcome:      fmt.Sprintf("count_%d", i),
+			Confidence:   0.8,
 		})
 		require.NoError(t, err)
 	}
+	to := time.Now().UTC().Add(1 * time.Second)
 
-	grants, total, err := testDB.ListGrants(ctx, uuid.Nil, 50, 0)
+	counts, total, err := testDB.CountDecisionsByAPIKey(ctx, uuid.Nil, from, to)
 	require.NoError(t, err)
 	assert.GreaterOrEqual(t, total, 3)
-	assert.GreaterOrEqual(t, len(grants), 3)
-
-	// Verify pagination.
-	page1, _, err := testDB.ListGrants(ctx, uuid.Nil, 2, 0)
-	require.NoError(t, err)
-	assert.Len(t, page1, 2)
-
-	page2, _, err := testDB.ListGrants(ctx, uuid.Nil, 2, 2)
-	require.NoError(t, err)
-	assert.Len(t, page2, 2)
-	if len(page1) > 0 && len(page2) > 0 {
-		assert.NotEqual(t, page1[0].ID, page2[0].ID, "pages should not overlap")
-	}
+	// Decisions without api_key_id are grouped under uuid.Nil.
+	assert.GreaterOrEqual(t, counts[uuid.Nil], 3)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Mutation audit
+// Tests: Tracehealth (GetDecisionQualityStats, GetEvidenceCoverageStats,
+//        GetConflictStatusCounts, GetOutcomeSignalsSummary)
 // ---------------------------------------------------------------------------
 
-func TestInsertMutationAudit(t *testing.T) {
+func TestGetDecisionQualityStats(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
 
-	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
-		RequestID:    "test-req-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: "test-actor",
-		ActorRole:    "admin",
-		HTTPMethod:   "POST",
-		Endpoint:     "/v1/test",
-		Operation:    "test_operation",
-		ResourceType: "test_resource",
-		ResourceID:   "res-" + suffix,
-		BeforeData:   map[string]any{"before": true},
-		AfterData:    map[string]any{"after": true},
-		Metadata:     map[string]any{"note": "test audit"},
-	})
+	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.Total, 0)
+	// From earlier tests, there should be at least some decisions with reasoning.
+	assert.GreaterOrEqual(t, stats.WithReasoning, 0)
 }
 
-func TestInsertMutationAudit_NilMetadata(t *testing.T) {
+func TestGetEvidenceCoverageStats(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
 
-	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
-		RequestID:    "nil-meta-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: "actor",
-		ActorRole:    "admin",
-		Operation:    "test_nil_meta",
-		ResourceType: "test",
-		ResourceID:   "res-nil-" + suffix,
-		// Intentionally nil Metadata, BeforeData, AfterData.
-	})
+	stats, err := testDB.GetEvidenceCoverageStats(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.TotalDecisions, 0)
+	assert.GreaterOrEqual(t, stats.WithEvidence, 0)
+	assert.Equal(t, stats.TotalDecisions-stats.WithEvidence, stats.WithoutEvidenceCount)
+}
+
+func TestGetConflictStatusCounts(t *testing.T) {
+	ctx := context.Background()
+
+	counts, err := testDB.GetConflictStatusCounts(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts.Total, 0)
+	assert.Equal(t, counts.Total, counts.Open+counts.Resolved+counts.FalsePositive)
+}
+
+func TestGetOutcomeSignalsSummary(t *testing.T) {
+	ctx := context.Background()
+
+	summary, err := testDB.GetOutcomeSignalsSummary(ctx, uuid.Nil, nil, nil)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, summary.DecisionsTotal, 0)
+	assert.GreaterOrEqual(t, summary.NeverSuperseded, 0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateAgentAndKeyTx
+// Tests: Retention
 // ---------------------------------------------------------------------------
 
-func TestCreateAgentAndKeyTx(t *testing.T) {
+func TestRetentionPolicyLifecycle(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "combo-" + suffix
+	retentionInterval := 24 * time.Hour
 
-	agent, key, err := testDB.CreateAgentAndKeyTx(ctx,
-		model.Agent{
-			AgentID: agentID, Name: "Combo Agent", Role: model.RoleAgent,
-		},
-		model.APIKey{
-			Prefix: "cb_", KeyHash: "combohash_" + suffix,
-			AgentID: agentID, OrgID: uuid.Nil, Label: "Combo Key", CreatedBy: "admin",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "combo-agent-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "platform_admin",
-			Operation: "create_agent", ResourceType: "agent",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "combo-key-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "platform_admin",
-			Operation: "create_api_key", ResourceType: "api_key",
-		},
-	)
+	// Default org should have no retention policy.
+	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
 	require.NoError(t, err)
-	assert.Equal(t, agentID, agent.AgentID)
-	assert.Nil(t, agent.APIKeyHash, "APIKeyHash should be nil for new agent+key combo")
-	assert.NotEqual(t, uuid.Nil, key.ID)
-	assert.Equal(t, "cb_", key.Prefix)
+	assert.Nil(t, policy.RetentionDays)
 
-	// Verify agent is retrievable.
-	gotAgent, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+	// Set a retention policy.
+	days := 90
+	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"compliance"})
 	require.NoError(t, err)
-	assert.Equal(t, agentID, gotAgent.AgentID)
 
-	// Verify key is retrievable.
-	gotKey, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
+	policy2, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
 	require.NoError(t, err)
-	assert.Equal(t, agentID, gotKey.AgentID)
-}
+	require.NotNil(t, policy2.RetentionDays)
+	assert.Equal(t, 90, *policy2.RetentionDays)
+	assert.Equal(t, []string{"compliance"}, policy2.RetentionExcludeTypes)
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignalsBatch
-// ---------------------------------------------------------------------------
+	// GetOrgsWithRetention should now include the default org.
+	configs, err := testDB.GetOrgsWithRetention(ctx)
+	require.NoError(t, err)
+	found := false
+	for _, c := range configs {
+		if c.OrgID == uuid.Nil {
+			found = true
+			assert.Equal(t, 90, c.RetentionDays)
+			break
+		}
+	}
+	assert.True(t, found, "default org should appear in orgs with retention")
 
-func TestGetDecisionOutcomeSignalsBatch(t *testing.T) {
+	// Clear the retention policy.
+	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+
+	policy3, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, retentionInterval)
+	require.NoError(t, err)
+	assert.Nil(t, policy3.RetentionDays)
+}
+
+func TestRetentionHoldLifecycle(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "signals-" + suffix
+	agentID := "hold-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID:        run.ID,
 		AgentID:      agentID,
-		DecisionType: "signals_test",
-		Outcome:      "signal outcome",
+		DecisionType: "hold_test",
+		Outcome:      "held decision",
 		Confidence:   0.8,
 	})
 	require.NoError(t, err)
 
-	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{d.ID}, uuid.Nil)
+	now := time.Now().UTC()
+	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
+		OrgID:     uuid.Nil,
+		Reason:    "Legal investigation " + suffix,
+		HoldFrom:  now.Add(-24 * time.Hour),
+		HoldTo:    now.Add(24 * time.Hour),
+		CreatedBy: "admin",
+	})
 	require.NoError(t, err)
-	// The decision exists but may not have signals — just verify no error.
-	_ = signals
-}
+	assert.NotEqual(t, uuid.Nil, hold.ID)
+	assert.Equal(t, "Legal investigation "+suffix, hold.Reason)
 
-// ---------------------------------------------------------------------------
-// Tests: GetResolvedConflictsByType
-// ---------------------------------------------------------------------------
-// ---------------------------------------------------------------------------
-// Tests: Agents (CreateAgentWithAudit, UpdateAgentWithAudit,
-//        UpdateAgentTagsWithAudit)
-// ---------------------------------------------------------------------------
+	// List holds.
+	holds, err := testDB.ListHolds(ctx, uuid.Nil)
+	require.NoError(t, err)
+	foundHold := false
+	for _, h := range holds {
+		if h.ID == hold.ID {
+			foundHold = true
+			assert.Nil(t, h.ReleasedAt)
+			break
+		}
+	}
+	assert.True(t, foundHold, "created hold should appear in list")
 
-func TestUpdateAgentWithAudit(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	// Check if hold exists for agent.
+	hasHold, err := testDB.ActiveHoldsExistForAgent(ctx, uuid.Nil, agentID)
+	require.NoError(t, err)
+	assert.True(t, hasHold, "active hold should cover the agent's decisions")
 
-	agent, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "upd-audit-" + suffix, Name: "Before", Role: model.RoleAgent,
-	})
+	// Release the hold.
+	released, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
 	require.NoError(t, err)
+	assert.True(t, released)
 
-	newName := "After"
-	updated, err := testDB.UpdateAgentWithAudit(ctx, uuid.Nil, agent.AgentID, &newName, nil,
-		storage.MutationAuditEntry{
-			RequestID: "upd-audit-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "update_agent", ResourceType: "agent",
-		})
+	// Release again should return false (already released).
+	released2, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, "After", updated.Name)
+	assert.False(t, released2)
 
-	// Nonexistent agent returns ErrNotFound.
-	_, err = testDB.UpdateAgentWithAudit(ctx, uuid.Nil, "no-such-"+suffix, &newName, nil,
-		storage.MutationAuditEntry{
-			RequestID: "upd-audit-fail-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "update_agent", ResourceType: "agent",
-		})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	// After release, hold should no longer affect the agent.
+	hasHold2, err := testDB.ActiveHoldsExistForAgent(ctx, uuid.Nil, agentID)
+	require.NoError(t, err)
+	assert.False(t, hasHold2, "released hold should not block agent")
 }
 
-func TestCreateAgentWithAudit(t *testing.T) {
+func TestActiveHoldsExistForDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "holdtest-" + suffix
 
-	agent, err := testDB.CreateAgentWithAudit(ctx,
-		model.Agent{
-			AgentID: "cwa-" + suffix, Name: "Audited Agent", Role: model.RoleAgent,
-		},
-		storage.MutationAuditEntry{
-			RequestID: "cwa-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "platform_admin",
-			Operation: "create_agent", ResourceType: "agent",
-		})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Equal(t, "cwa-"+suffix, agent.AgentID)
 
-	// Verify retrievable.
-	got, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, "cwa-"+suffix)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "hold_dec_test",
+		Outcome:      "covered by hold",
+		Confidence:   0.7,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, agent.ID, got.ID)
-}
 
-func TestUpdateAgentTagsWithAudit(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	// No hold yet.
+	hasHold, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
+	require.NoError(t, err)
+	assert.False(t, hasHold)
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "tag-audit-" + suffix, Name: "Tag Audit", Role: model.RoleAgent,
+	// Create a hold covering this decision's time range.
+	now := time.Now().UTC()
+	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
+		OrgID:    uuid.Nil,
+		Reason:   "Decision hold test",
+		HoldFrom: now.Add(-1 * time.Hour),
+		HoldTo:   now.Add(1 * time.Hour),
+		// No filters — covers all decision types and agents.
+		CreatedBy: "admin",
 	})
 	require.NoError(t, err)
 
-	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, "tag-audit-"+suffix, []string{"audited"},
-		storage.MutationAuditEntry{
-			RequestID: "tag-audit-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "update_agent_tags", ResourceType: "agent",
-		})
+	hasHold2, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	assert.Equal(t, []string{"audited"}, updated.Tags)
-}
+	assert.True(t, hasHold2, "active hold should cover the decision")
 
-// ---------------------------------------------------------------------------
-// Tests: API Keys (RotateAPIKeyWithAudit, MigrateAgentKeysToAPIKeys)
-// ---------------------------------------------------------------------------
+	// Release and verify.
+	_, err = testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	require.NoError(t, err)
 
-func TestRotateAPIKeyWithAudit(t *testing.T) {
+	hasHold3, err := testDB.ActiveHoldsExistForDecision(ctx, uuid.Nil, d.ID)
+	require.NoError(t, err)
+	assert.False(t, hasHold3)
+}
+
+func TestDeletionLogLifecycle(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "rotate-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, Name: "Rotate Agent", Role: model.RoleAgent,
-	})
+	logID, err := testDB.StartDeletionLog(ctx, uuid.Nil, "manual", "admin", map[string]any{"reason": "test"})
 	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, logID)
 
-	oldKey, err := testDB.CreateAPIKeyWithAudit(ctx,
-		model.APIKey{
-			Prefix: "old_", KeyHash: "oldhash_" + suffix,
-			AgentID: agentID, OrgID: uuid.Nil, Label: "Old Key", CreatedBy: "admin",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "create-old-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "create_api_key", ResourceType: "api_key",
-		})
+	err = testDB.CompleteDeletionLog(ctx, uuid.Nil, logID, map[string]any{"decisions": 5})
 	require.NoError(t, err)
+}
 
-	newKey, err := testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID,
-		model.APIKey{
-			Prefix: "new_", KeyHash: "newhash_" + suffix,
-			AgentID: agentID, OrgID: uuid.Nil, Label: "New Key", CreatedBy: "admin",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "rotate-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "rotate_api_key", ResourceType: "api_key",
-		})
-	require.NoError(t, err)
-	assert.Equal(t, "new_", newKey.Prefix)
+func TestCountEligibleDecisions(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "eligible-" + suffix
 
-	// Old key should be revoked.
-	gotOld, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, oldKey.ID)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.NotNil(t, gotOld.RevokedAt)
 
-	// Rotating an already-revoked key should fail.
-	_, err = testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID,
-		model.APIKey{
-			Prefix: "fail_", KeyHash: "failhash_" + suffix,
-			AgentID: agentID, OrgID: uuid.Nil, Label: "Fail", CreatedBy: "admin",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "rotate-fail-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "rotate_api_key", ResourceType: "api_key",
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "eligible_test",
+			Outcome:      fmt.Sprintf("eligible_%d", i),
+			Confidence:   0.7,
 		})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
+		require.NoError(t, err)
+	}
 
-func TestMigrateAgentKeysToAPIKeys(t *testing.T) {
-	ctx := context.Background()
+	before := time.Now().UTC().Add(1 * time.Second)
+	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts.Decisions, int64(3))
 
-	// Should be idempotent and succeed (even with no agents to migrate).
-	migrated, err := testDB.MigrateAgentKeysToAPIKeys(ctx)
+	// With agent filter.
+	a := agentID
+	counts2, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, nil, &a)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, migrated, 0)
+	assert.Equal(t, int64(3), counts2.Decisions)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Decisions (RetractDecision, EraseDecision, GetDecisionErasure,
-//        GetRevisionDepth, GetDecisionOutcomeSignals,
-//        GetCitationPercentilesForOrg, ExportDecisionsCursor,
-//        GetDecisionForScoring)
+// Tests: Conflicts (GetConflictCount, GetConflictCountsBatch, GetConflict,
+//        UpdateConflictStatusWithAudit)
 // ---------------------------------------------------------------------------
 
-func TestRetractDecision(t *testing.T) {
+func TestGetConflictCount_ForDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "retract-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentA := "cc-a-" + suffix
+	agentB := "cc-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "retract_test", Outcome: "to_retract", Confidence: 0.7,
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "cc_test",
+		Outcome: "yes", Confidence: 0.8,
 	})
 	require.NoError(t, err)
 
-	err = testDB.RetractDecision(ctx, uuid.Nil, d.ID, "test reason", agentID, &storage.MutationAuditEntry{
-		RequestID: "retract-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: agentID, ActorRole: "admin",
-		Operation: "retract_decision", ResourceType: "decision",
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "cc_test",
+		Outcome: "no", Confidence: 0.9,
 	})
 	require.NoError(t, err)
 
-	// Decision should now have valid_to set.
-	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	topicSim := 0.92
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "cc_test", DecisionTypeB: "cc_test",
+		OutcomeA: "yes", OutcomeB: "no",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
-	assert.NotNil(t, got.ValidTo, "retracted decision should have valid_to set")
 
-	// CurrentOnly should not find the retracted decision.
-	_, err = testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{CurrentOnly: true})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	count, err := testDB.GetConflictCount(ctx, dA.ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 1)
 
-	// Retracting again should fail (already retracted).
-	err = testDB.RetractDecision(ctx, uuid.Nil, d.ID, "double retract", agentID, nil)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	// Nonexistent decision should have 0 conflicts.
+	count2, err := testDB.GetConflictCount(ctx, uuid.New(), uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count2)
 }
 
-func TestEraseDecision(t *testing.T) {
+func TestGetConflictCountsBatch(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "erase-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentA := "ccb-a-" + suffix
+	agentB := "ccb-b-" + suffix
 
-	reasoning := "sensitive data here"
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "erase_test", Outcome: "sensitive outcome",
-		Confidence: 0.9, Reasoning: &reasoning,
-	})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-
-	// Add alternatives and evidence for erasure.
-	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
-		{DecisionID: d.ID, Label: "Alt 1"},
-	})
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	rel := float32(0.9)
-	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
-		{DecisionID: d.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence content", RelevanceScore: &rel},
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "ccb_test",
+		Outcome: "left", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-
-	// Add claims (derived from reasoning, may contain PII).
-	err = testDB.InsertClaims(ctx, []storage.Claim{
-		{DecisionID: d.ID, OrgID: uuid.Nil, ClaimIdx: 0, ClaimText: "sensitive claim one"},
-		{DecisionID: d.ID, OrgID: uuid.Nil, ClaimIdx: 1, ClaimText: "sensitive claim two"},
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "ccb_test",
+		Outcome: "right", Confidence: 0.9,
 	})
 	require.NoError(t, err)
-
-	result, err := testDB.EraseDecision(ctx, uuid.Nil, d.ID, "GDPR request", agentID, &storage.MutationAuditEntry{
-		RequestID: "erase-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: agentID, ActorRole: "admin",
-		Operation: "erase_decision", ResourceType: "decision",
+	dC, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "ccb_other",
+		Outcome: "solo", Confidence: 0.7,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, d.ID, result.Erasure.DecisionID)
-	assert.Equal(t, agentID, result.Erasure.ErasedBy)
-	assert.Equal(t, "GDPR request", result.Erasure.Reason)
-	assert.Equal(t, int64(1), result.AlternativesErased)
-	assert.Equal(t, int64(1), result.EvidenceErased)
-	assert.Equal(t, int64(2), result.ClaimsErased)
 
-	// Verify decision outcome is scrubbed.
-	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	topicSim := 0.90
+	outcomeDiv := 0.80
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "ccb_test", DecisionTypeB: "ccb_test",
+		OutcomeA: "left", OutcomeB: "right",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
-	assert.Equal(t, storage.ErasedSentinel, got.Outcome)
-	assert.Equal(t, storage.ErasedSentinel, *got.Reasoning)
 
-	// Verify claims are scrubbed (claim_text replaced, embedding nulled).
-	claims, err := testDB.FindClaimsByDecision(ctx, d.ID, uuid.Nil)
+	counts, err := testDB.GetConflictCountsBatch(ctx, []uuid.UUID{dA.ID, dB.ID, dC.ID}, uuid.Nil)
 	require.NoError(t, err)
-	require.Len(t, claims, 2, "claims should still exist after erasure")
-	for _, c := range claims {
-		assert.Equal(t, storage.ErasedSentinel, c.ClaimText, "claim_text must be scrubbed")
-		assert.Nil(t, c.Embedding, "claim embedding must be nulled")
-	}
+	assert.GreaterOrEqual(t, counts[dA.ID], 1)
+	assert.GreaterOrEqual(t, counts[dB.ID], 1)
+	assert.Equal(t, 0, counts[dC.ID]) // no conflict
 
-	// GetDecisionErasure should return the record.
-	erasure, err := testDB.GetDecisionErasure(ctx, uuid.Nil, d.ID)
+	// Empty batch.
+	emptyCounts, err := testDB.GetConflictCountsBatch(ctx, []uuid.UUID{}, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, d.ID, erasure.DecisionID)
-
-	// Erasing again should fail.
-	_, err = testDB.EraseDecision(ctx, uuid.Nil, d.ID, "double erase", agentID, nil)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrAlreadyErased)
-}
-
-func TestGetDecisionErasure_NotFound(t *testing.T) {
-	ctx := context.Background()
-
-	_, err := testDB.GetDecisionErasure(ctx, uuid.Nil, uuid.New())
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Empty(t, emptyCounts)
 }
 
-func TestGetRevisionDepth(t *testing.T) {
+func TestGetConflict(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "depth-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentA := "gc-a-" + suffix
+	agentB := "gc-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	// Create original decision.
-	d0, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "depth_test", Outcome: "v0", Confidence: 0.5,
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "gc_test",
+		Outcome: "option_a", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-
-	// Depth of original should be 0.
-	depth, err := testDB.GetRevisionDepth(ctx, d0.ID, uuid.Nil)
-	require.NoError(t, err)
-	assert.Equal(t, 0, depth)
-
-	// Revise once.
-	d1, err := testDB.ReviseDecision(ctx, d0.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "depth_test", Outcome: "v1", Confidence: 0.7,
-	}, nil)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "gc_test",
+		Outcome: "option_b", Confidence: 0.9,
+	})
 	require.NoError(t, err)
 
-	depth1, err := testDB.GetRevisionDepth(ctx, d1.ID, uuid.Nil)
+	topicSim := 0.88
+	outcomeDiv := 0.82
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "gc_test", DecisionTypeB: "gc_test",
+		OutcomeA: "option_a", OutcomeB: "option_b",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 1, depth1)
 
-	// Revise again.
-	d2, err := testDB.ReviseDecision(ctx, d1.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "depth_test", Outcome: "v2", Confidence: 0.9,
-	}, nil)
+	got, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, conflictID, got.ID)
+	assert.Equal(t, "open", got.Status)
 
-	depth2, err := testDB.GetRevisionDepth(ctx, d2.ID, uuid.Nil)
+	// Not found.
+	notFound, err := testDB.GetConflict(ctx, uuid.New(), uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, 2, depth2)
+	assert.Nil(t, notFound)
 }
 
-func TestGetDecisionOutcomeSignals(t *testing.T) {
+func TestUpdateConflictStatusWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "outsig-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentA := "ucs-a-" + suffix
+	agentB := "ucs-b-" + suffix
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "outsig_test_" + suffix, Outcome: "signal_outcome", Confidence: 0.8,
-	})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-
-	signals, err := testDB.GetDecisionOutcomeSignals(ctx, d.ID, uuid.Nil)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
-	// No supersession, no citations, no conflicts for a fresh decision.
-	assert.Nil(t, signals.SupersessionVelocityHours)
-	assert.Equal(t, 0, signals.PrecedentCitationCount)
-	assert.Equal(t, 0, signals.ConflictFate.Won)
-}
-
-func TestGetCitationPercentilesForOrg(t *testing.T) {
-	ctx := context.Background()
 
-	percentiles, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "ucs_test",
+		Outcome: "left", Confidence: 0.8,
+	})
 	require.NoError(t, err)
-	_ = percentiles
-}
-
-func TestGetDecisionForScoring(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "scoring-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "ucs_test",
+		Outcome: "right", Confidence: 0.9,
+	})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "scoring_test", Outcome: "score_me", Confidence: 0.8,
+	topicSim := 0.9
+	outcomeDiv := 0.8
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "ucs_test", DecisionTypeB: "ucs_test",
+		OutcomeA: "left", OutcomeB: "right",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	got, err := testDB.GetDecisionForScoring(ctx, d.ID, uuid.Nil)
+	// Transition to false_positive (terminal status, sets resolved_by/resolved_at).
+	fpNote := "Not a real conflict."
+	fpLabel := "unrelated_false_positive"
+	oldStatus, err := testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"false_positive", "admin-agent", &fpNote, nil, &fpLabel,
+		storage.MutationAuditEntry{
+			RequestID: "fp-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin-agent", ActorRole: "admin",
+			Operation: "false_positive_conflict", ResourceType: "conflict",
+		})
 	require.NoError(t, err)
-	assert.Equal(t, d.ID, got.ID)
-	assert.Equal(t, "score_me", got.Outcome)
-
-	// Nonexistent decision.
-	_, err = testDB.GetDecisionForScoring(ctx, uuid.New(), uuid.Nil)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-func TestExportDecisionsCursor(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "export-" + suffix
+	assert.Equal(t, "open", oldStatus)
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	got, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
+	assert.Equal(t, "false_positive", got.Status)
+	require.NotNil(t, got.ResolvedBy)
+	assert.Equal(t, "admin-agent", *got.ResolvedBy)
+	assert.Nil(t, got.WinningDecisionID, "false_positive should not set winner")
 
-	for i := range 3 {
-		_, err = testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "export_test_" + suffix, Outcome: fmt.Sprintf("export_%d", i),
-			Confidence: 0.7,
+	// Transition to resolved with a winner (overriding false_positive).
+	resNote := "Right approach is better."
+	oldStatus2, err := testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "admin-agent", &resNote, &dB.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "resolve-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin-agent", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
 		})
-		require.NoError(t, err)
-	}
-
-	dType := "export_test_" + suffix
-	decisions, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{
-		DecisionType: &dType,
-	}, nil, 2)
 	require.NoError(t, err)
-	assert.Len(t, decisions, 2)
+	assert.Equal(t, "false_positive", oldStatus2)
 
-	// Build cursor from the last returned decision for keyset pagination.
-	last := decisions[len(decisions)-1]
-	cursor := &storage.ExportCursor{ValidFrom: last.ValidFrom, ID: last.ID}
+	got2, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", got2.Status)
+	require.NotNil(t, got2.ResolvedBy)
+	assert.Equal(t, "admin-agent", *got2.ResolvedBy)
+	require.NotNil(t, got2.WinningDecisionID)
+	assert.Equal(t, dB.ID, *got2.WinningDecisionID)
 
-	// Fetch next page.
-	decisions2, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{
-		DecisionType: &dType,
-	}, cursor, 2)
+	// Re-applying the current status is rejected without touching the row.
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "other-agent", nil, nil, nil,
+		storage.MutationAuditEntry{
+			RequestID: "re-resolve-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "other-agent", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
+	require.ErrorIs(t, err, storage.ErrConflictStatusUnchanged)
+	got3, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(decisions2), 1)
+	assert.Equal(t, "admin-agent", *got3.ResolvedBy)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Grants (CreateGrantWithAudit, DeleteGrantWithAudit)
+// Tests: Conflict reopening archives resolution metadata
 // ---------------------------------------------------------------------------
 
-func TestCreateGrantWithAudit(t *testing.T) {
+func TestInsertScoredConflict_ReopenArchivesResolution(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "gwaud-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
-	})
-	require.NoError(t, err)
+	agentA := "reopen-a-" + suffix
+	agentB := "reopen-b-" + suffix
 
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "gwaud-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
-	})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-
-	resID := "gwaud-resource-" + suffix
-	grant, err := testDB.CreateGrantWithAudit(ctx,
-		model.AccessGrant{
-			GrantorID: grantor.ID, GranteeID: grantee.ID,
-			ResourceType: "agent_traces", ResourceID: &resID,
-			Permission: "read",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "gwaud-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "create_grant", ResourceType: "grant",
-		})
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, grant.ID)
-}
-
-func TestDeleteGrantWithAudit(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
 
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "dgwaud-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "reopen_test",
+		Outcome: "use Redis", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "dgwaud-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "reopen_test",
+		Outcome: "use Memcached", Confidence: 0.7,
 	})
 	require.NoError(t, err)
 
-	resID := "dgwaud-res-" + suffix
-	grant, err := testDB.CreateGrant(ctx, model.AccessGrant{
-		GrantorID: grantor.ID, GranteeID: grantee.ID,
-		ResourceType: "agent_traces", ResourceID: &resID, Permission: "read",
+	// Step 1: Insert a conflict (starts as open).
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "reopen_test", DecisionTypeB: "reopen_test",
+		OutcomeA: "use Redis", OutcomeB: "use Memcached",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	err = testDB.DeleteGrantWithAudit(ctx, uuid.Nil, grant.ID,
+	// Step 2: Resolve the conflict with a winner.
+	resNote := "Redis preferred — already running in prod."
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "human-reviewer", &resNote, &dA.ID, nil,
 		storage.MutationAuditEntry{
-			RequestID: "dgwaud-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "delete_grant", ResourceType: "grant",
+			RequestID: "resolve-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "human-reviewer", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
 		})
 	require.NoError(t, err)
 
-	// Should no longer have access.
-	has, err := testDB.HasAccess(ctx, uuid.Nil, grantee.ID, "agent_traces", "dgwaud-res-"+suffix, "read")
+	// Verify it's resolved with full metadata.
+	resolved, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	assert.False(t, has)
+	assert.Equal(t, "resolved", resolved.Status)
+	require.NotNil(t, resolved.ResolvedBy)
+	assert.Equal(t, "human-reviewer", *resolved.ResolvedBy)
+	require.NotNil(t, resolved.WinningDecisionID)
+	assert.Equal(t, dA.ID, *resolved.WinningDecisionID)
 
-	// Delete again should fail.
-	err = testDB.DeleteGrantWithAudit(ctx, uuid.Nil, grant.ID,
-		storage.MutationAuditEntry{
-			RequestID: "dgwaud-again-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "delete_grant", ResourceType: "grant",
-		})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: Retention (GetOrgsWithRetention, HoldLifecycle, CountEligible)
-// ---------------------------------------------------------------------------
-
-func TestGetOrgsWithRetention(t *testing.T) {
-	ctx := context.Background()
-
-	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
+	// Step 3: Re-insert the same conflict pair (simulating scorer re-detection).
+	newSig := 0.95
+	conflictID2, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "reopen_test", DecisionTypeB: "reopen_test",
+		OutcomeA: "use Redis", OutcomeB: "use Memcached",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &newSig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
+	assert.Equal(t, conflictID, conflictID2, "upsert should return the same conflict ID")
 
-	orgs, err := testDB.GetOrgsWithRetention(ctx)
+	// Step 4: Verify the conflict is reopened with NULLed resolution fields.
+	reopened, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	for _, o := range orgs {
-		assert.NotEqual(t, uuid.Nil, o.OrgID, "default org should not appear without retention policy")
-	}
+	assert.Equal(t, "open", reopened.Status, "conflict should be reopened")
+	assert.Nil(t, reopened.ResolvedBy, "resolved_by should be NULL after reopening")
+	assert.Nil(t, reopened.ResolvedAt, "resolved_at should be NULL after reopening")
+	assert.Nil(t, reopened.ResolutionNote, "resolution_note should be NULL after reopening")
+	assert.Nil(t, reopened.WinningDecisionID, "winning_decision_id should be NULL after reopening")
+
+	// Step 5: Verify the resolution was archived in conflict_resolutions.
+	var archivedResolvedBy string
+	var archivedResNote *string
+	var archivedWinner *uuid.UUID
+	var archivedResolvedAt time.Time
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT resolved_by, resolution_note, winning_decision_id, resolved_at
+		 FROM conflict_resolutions
+		 WHERE conflict_id = $1 AND org_id = $2`,
+		conflictID, uuid.Nil,
+	).Scan(&archivedResolvedBy, &archivedResNote, &archivedWinner, &archivedResolvedAt)
+	require.NoError(t, err, "should find archived resolution in conflict_resolutions")
+	assert.Equal(t, "human-reviewer", archivedResolvedBy)
+	require.NotNil(t, archivedResNote)
+	assert.Equal(t, "Redis preferred — already running in prod.", *archivedResNote)
+	require.NotNil(t, archivedWinner)
+	assert.Equal(t, dA.ID, *archivedWinner)
+	assert.False(t, archivedResolvedAt.IsZero(), "archived resolved_at should be non-zero")
 }
 
-func TestHoldLifecycle(t *testing.T) {
+func TestInsertScoredConflict_ReopenWithGroupIDArchivesResolution(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	now := time.Now().UTC()
-	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
-		OrgID: uuid.Nil, Reason: "legal hold " + suffix,
-		HoldFrom: now.Add(-30 * 24 * time.Hour), HoldTo: now.Add(30 * 24 * time.Hour),
-		CreatedBy: "admin",
+	agentA := "reopen-grp-a-" + suffix
+	agentB := "reopen-grp-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "reopen_grp_test",
+		Outcome: "use Postgres", Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "reopen_grp_test",
+		Outcome: "use MySQL", Confidence: 0.7,
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, hold.ID)
-	assert.Equal(t, "legal hold "+suffix, hold.Reason)
 
-	// List holds.
-	holds, err := testDB.ListHolds(ctx, uuid.Nil)
+	// Pre-create a group (simulates FindOrCreateTopicGroup).
+	var groupID uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'reopen_grp_test', 'database choice')
+		 RETURNING id`,
+		uuid.Nil, agentA, agentB,
+	).Scan(&groupID)
 	require.NoError(t, err)
-	found := false
-	for _, h := range holds {
-		if h.ID == hold.ID {
-			found = true
-			assert.Nil(t, h.ReleasedAt)
-			break
-		}
-	}
-	assert.True(t, found, "should find newly created hold")
 
-	// Release hold.
-	released, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	// Insert conflict with pre-computed group_id.
+	topicSim := 0.88
+	outcomeDiv := 0.9
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "reopen_grp_test", DecisionTypeB: "reopen_grp_test",
+		OutcomeA: "use Postgres", OutcomeB: "use MySQL",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+		GroupID: &groupID,
+	})
 	require.NoError(t, err)
-	assert.True(t, released)
 
-	// Release again should return false.
-	released2, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	// Resolve it.
+	resNote := "Postgres wins — better JSON support."
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "tech-lead", &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "resolve-grp-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "tech-lead", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
 	require.NoError(t, err)
-	assert.False(t, released2)
-}
 
-func TestCountEligibleDecisionsWithFilters(t *testing.T) {
-	ctx := context.Background()
+	// Re-insert with the same GroupID (triggers insertScoredConflictWithGroup path).
+	conflictID2, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "reopen_grp_test", DecisionTypeB: "reopen_grp_test",
+		OutcomeA: "use Postgres", OutcomeB: "use MySQL",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+		GroupID: &groupID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, conflictID, conflictID2)
 
-	before := time.Now().UTC().Add(-365 * 24 * time.Hour)
-	dType := "some_type"
-	agent := "some_agent"
-	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, &dType, &agent)
+	// Verify reopened.
+	reopened, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
-}
+	assert.Equal(t, "open", reopened.Status)
+	assert.Nil(t, reopened.WinningDecisionID)
 
-// ---------------------------------------------------------------------------
-// Tests: Events (InsertEvent)
-// ---------------------------------------------------------------------------
+	// Verify archive.
+	var archivedResolvedBy string
+	var archivedWinner *uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT resolved_by, winning_decision_id
+		 FROM conflict_resolutions
+		 WHERE conflict_id = $1 AND org_id = $2`,
+		conflictID, uuid.Nil,
+	).Scan(&archivedResolvedBy, &archivedWinner)
+	require.NoError(t, err, "should find archived resolution for group-path reopening")
+	assert.Equal(t, "tech-lead", archivedResolvedBy)
+	require.NotNil(t, archivedWinner)
+	assert.Equal(t, dA.ID, *archivedWinner)
+}
 
-func TestInsertEvent(t *testing.T) {
+func TestInsertScoredConflict_NoArchiveWhenNotResolved(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "single-evt-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentA := "noarch-a-" + suffix
+	agentB := "noarch-b-" + suffix
 
-	nums, err := testDB.ReserveSequenceNums(ctx, 1)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	now := time.Now().UTC()
-	err = testDB.InsertEvent(ctx, model.AgentEvent{
-		ID: uuid.New(), RunID: run.ID, OrgID: uuid.Nil,
-		EventType: model.EventDecisionStarted, SequenceNum: nums[0],
-		OccurredAt: now, AgentID: agentID,
-		Payload: map[string]any{"test": true}, CreatedAt: now,
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "noarch_test",
+		Outcome: "approach A", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-
-	events, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "noarch_test",
+		Outcome: "approach B", Confidence: 0.7,
+	})
 	require.NoError(t, err)
-	assert.Len(t, events, 1)
-	assert.Equal(t, model.EventDecisionStarted, events[0].EventType)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: Claims (FindRetriableClaimFailures)
-// ---------------------------------------------------------------------------
+	// Insert a conflict (open).
+	topicSim := 0.9
+	outcomeDiv := 0.8
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "noarch_test", DecisionTypeB: "noarch_test",
+		OutcomeA: "approach A", OutcomeB: "approach B",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
 
-func TestFindRetriableClaimFailures(t *testing.T) {
-	ctx := context.Background()
+	// Re-insert without resolving first.
+	newSig := 0.95
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "noarch_test", DecisionTypeB: "noarch_test",
+		OutcomeA: "approach A", OutcomeB: "approach B",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &newSig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
 
-	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 50)
+	// Verify no archive was created (the conflict was never resolved).
+	var count int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM conflict_resolutions WHERE conflict_id = $1 AND org_id = $2`,
+		conflictID, uuid.Nil,
+	).Scan(&count)
 	require.NoError(t, err)
-	_ = refs
+	assert.Equal(t, 0, count, "no archive should exist for a conflict that was never resolved")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Organizations (EnsureDefaultOrg, GetOrganization)
+// Tests: Events (InsertEventsIdempotent)
 // ---------------------------------------------------------------------------
 
-func TestEnsureDefaultOrg(t *testing.T) {
+func TestInsertEventsIdempotent(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "idem-evt-" + suffix
 
-	err := testDB.EnsureDefaultOrg(ctx)
-	require.NoError(t, err)
-
-	err = testDB.EnsureDefaultOrg(ctx)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-}
-
-func TestGetOrganization(t *testing.T) {
-	ctx := context.Background()
 
-	org, err := testDB.GetOrganization(ctx, uuid.Nil)
-	require.NoError(t, err)
-	assert.Equal(t, uuid.Nil, org.ID)
-	assert.NotEmpty(t, org.Name)
-
-	// Nonexistent org.
-	_, err = testDB.GetOrganization(ctx, uuid.New())
-	require.Error(t, err)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: Conflicts (RefreshConflicts, GetGlobalOpenConflictCount,
-//        GetConflictGroupKind)
-// ---------------------------------------------------------------------------
-
-func TestRefreshConflicts(t *testing.T) {
-	ctx := context.Background()
+	now := time.Now().UTC()
+	events := make([]model.AgentEvent, 5)
+	for i := range events {
+		events[i] = model.AgentEvent{
+			ID:          uuid.New(),
+			RunID:       run.ID,
+			EventType:   model.EventToolCallCompleted,
+			SequenceNum: int64(i + 1),
+			OccurredAt:  now,
+			AgentID:     agentID,
+			Payload:     map[string]any{"step": i},
+			CreatedAt:   now,
+		}
+	}
 
-	err := testDB.RefreshConflicts(ctx)
+	// First insert.
+	inserted, err := testDB.InsertEventsIdempotent(ctx, events)
 	require.NoError(t, err)
-}
-
-func TestGetGlobalOpenConflictCount(t *testing.T) {
-	ctx := context.Background()
+	assert.Equal(t, int64(5), inserted)
 
-	count, err := testDB.GetGlobalOpenConflictCount(ctx)
+	// Second insert should be idempotent (0 new rows).
+	inserted2, err := testDB.InsertEventsIdempotent(ctx, events)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, int64(0))
-}
-
-func TestGetConflictGroupKind(t *testing.T) {
-	ctx := context.Background()
-
-	_, err := testDB.GetConflictGroupKind(ctx, uuid.New(), uuid.Nil)
-	require.Error(t, err)
-}
-
-func TestGetFalsePositiveRate(t *testing.T) {
-	ctx := context.Background()
+	assert.Equal(t, int64(0), inserted2)
 
-	// With no resolved/false_positive conflicts, rate should be 0.
-	rate, err := testDB.GetFalsePositiveRate(ctx, uuid.Nil)
+	// Verify only 5 events exist.
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
-	assert.Equal(t, 0.0, rate.Rate)
-	assert.GreaterOrEqual(t, rate.Resolved, 0)
-	assert.GreaterOrEqual(t, rate.FalsePositive, 0)
+	assert.Len(t, got, 5)
 }
 
-func TestGetGlobalFalsePositiveRate(t *testing.T) {
+func TestInsertEventsIdempotent_Empty(t *testing.T) {
 	ctx := context.Background()
 
-	rate, err := testDB.GetGlobalFalsePositiveRate(ctx)
+	inserted, err := testDB.InsertEventsIdempotent(ctx, nil)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, rate, 0.0)
-	assert.LessOrEqual(t, rate, 1.0)
+	assert.Equal(t, int64(0), inserted)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Idempotency (ClearInProgressIdempotency)
+// Tests: Claims (MarkClaimEmbeddingFailed, ClearClaimEmbeddingFailure,
+//        FindRetriableClaimFailures)
 // ---------------------------------------------------------------------------
 
-func TestClearInProgressIdempotency(t *testing.T) {
+func TestClaimEmbeddingFailureLifecycle(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "claimfail-" + suffix
 
-	// ClearInProgressIdempotency requires orgID, agentID, endpoint, and key.
-	// Call with a non-existent key — should succeed (no rows to clear).
-	err := testDB.ClearInProgressIdempotency(ctx, uuid.Nil, "no-agent", "/v1/trace", "nonexistent-key")
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: CreateOrgWithOwnerAndKeyTx
-// ---------------------------------------------------------------------------
+	emb := pgvector.NewVector(make([]float32, 1024))
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "claim_fail_test",
+		Outcome:      "will fail claims",
+		Confidence:   0.8,
+		Embedding:    &emb,
+	})
+	require.NoError(t, err)
 
-func TestCreateOrgWithOwnerAndKeyTx(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	// Mark as failed.
+	err = testDB.MarkClaimEmbeddingFailed(ctx, d.ID, d.OrgID)
+	require.NoError(t, err)
 
-	org, agent, key, err := testDB.CreateOrgWithOwnerAndKeyTx(ctx,
-		model.Organization{
-			Name: "Test Org " + suffix, Slug: "testorg-" + suffix, Plan: "starter",
-		},
-		model.Agent{
-			AgentID: "owner-" + suffix, Name: "Owner", Role: model.RoleOrgOwner,
-		},
-		model.APIKey{
-			Prefix: "to_", KeyHash: "orghash_" + suffix,
-			AgentID: "owner-" + suffix, Label: "Org Key", CreatedBy: "system",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "org-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "system", ActorRole: "platform_admin",
-			Operation: "create_org", ResourceType: "organization",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "org-agent-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "system", ActorRole: "platform_admin",
-			Operation: "create_agent", ResourceType: "agent",
-		},
-		storage.MutationAuditEntry{
-			RequestID: "org-key-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "system", ActorRole: "platform_admin",
-			Operation: "create_api_key", ResourceType: "api_key",
-		},
-	)
+	// Mark again (should increment attempts).
+	err = testDB.MarkClaimEmbeddingFailed(ctx, d.ID, d.OrgID)
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, org.ID)
-	assert.Equal(t, "Test Org "+suffix, org.Name)
-	assert.Equal(t, org.ID, agent.OrgID)
-	assert.Equal(t, org.ID, key.OrgID)
-	assert.Equal(t, string(model.RoleOrgOwner), string(agent.Role))
 
-	gotOrg, err := testDB.GetOrganization(ctx, org.ID)
+	// The decision should no longer appear in missing claims (it's failed, not missing).
+	refs, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
 	require.NoError(t, err)
-	assert.Equal(t, org.Name, gotOrg.Name)
+	for _, r := range refs {
+		assert.NotEqual(t, d.ID, r.ID, "failed decision should not appear in missing claims")
+	}
+
+	// Clear the failure.
+	err = testDB.ClearClaimEmbeddingFailure(ctx, d.ID, d.OrgID)
+	require.NoError(t, err)
+
+	// After clearing, it should appear in missing claims again.
+	refs2, err := testDB.FindDecisionIDsMissingClaims(ctx, 1000)
+	require.NoError(t, err)
+	found := false
+	for _, r := range refs2 {
+		if r.ID == d.ID {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "decision should reappear in missing claims after clearing failure")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: MarkDecisionConflictScored, CountUnscoredDecisions, ResetConflictScoredAt
+// Tests: Grants (ListGrants)
 // ---------------------------------------------------------------------------
 
-func TestMarkDecisionConflictScored(t *testing.T) {
+func TestListGrants(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	agentID := "scored-" + suffix
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "mark_scored_" + suffix, Outcome: "to be scored",
-		Confidence: 0.5,
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lg-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
 	})
 	require.NoError(t, err)
 
-	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
-	require.NoError(t, err)
-
-	// Marking again should be idempotent (overwrites with now()).
-	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "lg-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
+	})
 	require.NoError(t, err)
-}
 
-func TestCountUnscoredDecisions(t *testing.T) {
-	ctx := context.Background()
+	for i := range 3 {
+		resID := fmt.Sprintf("lg-resource-%d-%s", i, suffix)
+		_, err = testDB.CreateGrant(ctx, model.AccessGrant{
+			GrantorID:    grantor.ID,
+			GranteeID:    grantee.ID,
+			ResourceType: "agent_traces",
+			ResourceID:   &resID,
+			Permission:   "read",
+		})
+		require.NoError(t, err)
+	}
 
-	// Just verify it returns a non-negative count without error.
-	count, err := testDB.CountUnscoredDecisions(ctx)
+	grants, total, err := testDB.ListGrants(ctx, uuid.Nil, 50, 0)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, int64(0))
-}
-
-func TestResetConflictScoredAt(t *testing.T) {
-	ctx := context.Background()
+	assert.GreaterOrEqual(t, total, 3)
+	assert.GreaterOrEqual(t, len(grants), 3)
 
-	affected, err := testDB.ResetConflictScoredAt(ctx)
+	// Verify pagination.
+	page1, _, err := testDB.ListGrants(ctx, uuid.Nil, 2, 0)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, affected, int64(0))
-}
-
-func TestCountUnvalidatedConflicts(t *testing.T) {
-	ctx := context.Background()
+	assert.Len(t, page1, 2)
 
-	count, err := testDB.CountUnvalidatedConflicts(ctx)
+	page2, _, err := testDB.ListGrants(ctx, uuid.Nil, 2, 2)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
+	assert.Len(t, page2, 2)
+	if len(page1) > 0 && len(page2) > 0 {
+		assert.NotEqual(t, page1[0].ID, page2[0].ID, "pages should not overlap")
+	}
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetDecisionEmbeddings (empty input)
+// Tests: Mutation audit
 // ---------------------------------------------------------------------------
 
-func TestGetDecisionEmbeddings_Empty(t *testing.T) {
+func TestInsertMutationAudit(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	result, err := testDB.GetDecisionEmbeddings(ctx, nil, uuid.Nil)
+	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
+		RequestID:    "test-req-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: "test-actor",
+		ActorRole:    "admin",
+		HTTPMethod:   "POST",
+		Endpoint:     "/v1/test",
+		Operation:    "test_operation",
+		ResourceType: "test_resource",
+		ResourceID:   "res-" + suffix,
+		BeforeData:   map[string]any{"before": true},
+		AfterData:    map[string]any{"after": true},
+		Metadata:     map[string]any{"note": "test audit"},
+	})
 	require.NoError(t, err)
-	assert.Empty(t, result)
 }
 
-func TestGetDecisionEmbeddings_NoMatch(t *testing.T) {
+func TestInsertMutationAudit_NilMetadata(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{uuid.New()}, uuid.Nil)
+	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
+		RequestID:    "nil-meta-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: "actor",
+		ActorRole:    "admin",
+		Operation:    "test_nil_meta",
+		ResourceType: "test",
+		ResourceID:   "res-nil-" + suffix,
+		// Intentionally nil Metadata, BeforeData, AfterData.
+	})
 	require.NoError(t, err)
-	assert.Empty(t, result)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CountConflictGroups
+// Tests: CreateAgentAndKeyTx
 // ---------------------------------------------------------------------------
 
-func TestCountConflictGroups(t *testing.T) {
+func TestCreateAgentAndKeyTx(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "combo-" + suffix
 
-	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{})
+	agent, key, err := testDB.CreateAgentAndKeyTx(ctx,
+		model.Agent{
+			AgentID: agentID, Name: "Combo Agent", Role: model.RoleAgent,
+		},
+		model.APIKey{
+			Prefix: "cb_", KeyHash: "combohash_" + suffix,
+			AgentID: agentID, OrgID: uuid.Nil, Label: "Combo Key", CreatedBy: "admin",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "combo-agent-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "platform_admin",
+			Operation: "create_agent", ResourceType: "agent",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "combo-key-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "platform_admin",
+			Operation: "create_api_key", ResourceType: "api_key",
+		},
+	)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
-}
+	assert.Equal(t, agentID, agent.AgentID)
+	assert.Nil(t, agent.APIKeyHash, "APIKeyHash should be nil for new agent+key combo")
+	assert.NotEqual(t, uuid.Nil, key.ID)
+	assert.Equal(t, "cb_", key.Prefix)
 
-func TestCountConflictGroups_StatusFilter(t *testing.T) {
-	ctx := context.Background()
+	// Verify agent is retrievable.
+	gotAgent, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, agentID)
+	require.NoError(t, err)
+	assert.Equal(t, agentID, gotAgent.AgentID)
 
-	st := "open"
-	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{
-		Status: &st,
-	})
+	// Verify key is retrievable.
+	gotKey, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, key.ID)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
+	assert.Equal(t, agentID, gotKey.AgentID)
 }
 
-func TestCountConflictGroups_WithFilters(t *testing.T) {
+func TestCreateAgentsAndKeysTx_RollsBackOnDuplicate(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	newItem := func(agentID string) storage.NewAgentWithKey {
+		return storage.NewAgentWithKey{
+			Agent: model.Agent{AgentID: agentID, Name: agentID, Role: model.RoleAgent},
+			Key: model.APIKey{
+				KeyHash: "bulkhash_" + agentID + "_" + uuid.New().String()[:8],
+				AgentID: agentID, OrgID: uuid.Nil, Label: "default", CreatedBy: "admin",
+			},
+			AgentAudit: storage.MutationAuditEntry{
+				RequestID: "bulk-agent-" + agentID, OrgID: uuid.Nil,
+				ActorAgentID: "admin", ActorRole: "platform_admin",
+				Operation: "create_agent", ResourceType: "agent",
+			},
+			KeyAudit: storage.MutationAuditEntry{
+				RequestID: "bulk-key-" + agentID, OrgID: uuid.Nil,
+				ActorAgentID: "admin", ActorRole: "platform_admin",
+				Operation: "create_api_key", ResourceType: "api_key",
+			},
+		}
+	}
 
-	dt := "some_type"
-	agent := "some_agent"
-	kind := "cross_agent"
-	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{
-		DecisionType: &dt,
-		AgentID:      &agent,
-		ConflictKind: &kind,
-	})
+	first, second := "bulk1-"+suffix, "bulk2-"+suffix
+	created, err := testDB.CreateAgentsAndKeysTx(ctx, []storage.NewAgentWithKey{newItem(first), newItem(second)})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
+	require.Len(t, created, 2)
+	assert.Equal(t, second, created[1].Agent.AgentID)
+
+	existing, err := testDB.ExistingAgentIDs(ctx, uuid.Nil, []string{first, second, "bulk-missing-" + suffix})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first, second}, existing)
+
+	// A batch containing an existing agent_id must not create any of its agents.
+	third := "bulk3-" + suffix
+	_, err = testDB.CreateAgentsAndKeysTx(ctx, []storage.NewAgentWithKey{newItem(third), newItem(first)})
+	require.Error(t, err)
+	_, err = testDB.GetAgentByAgentID(ctx, uuid.Nil, third)
+	require.ErrorIs(t, err, storage.ErrNotFound)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListConflictGroups
+// Tests: GetDecisionOutcomeSignalsBatch
 // ---------------------------------------------------------------------------
 
-func TestListConflictGroups(t *testing.T) {
+func TestGetDecisionOutcomeSignalsBatch(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "signals-" + suffix
 
-	groups, err := testDB.ListConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{}, 10, 0)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	// May be empty on a fresh DB — just verify no error.
-	_ = groups
-}
 
-func TestListConflictGroups_Limits(t *testing.T) {
-	ctx := context.Background()
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      agentID,
+		DecisionType: "signals_test",
+		Outcome:      "signal outcome",
+		Confidence:   0.8,
+	})
+	require.NoError(t, err)
 
-	// Verify clamping: negative offset -> 0, limit 0 -> 50, limit > 1000 -> 1000.
-	groups, err := testDB.ListConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{}, 0, -1)
+	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{d.ID}, uuid.Nil)
 	require.NoError(t, err)
-	_ = groups
+	// The decision exists but may not have signals — just verify no error.
+	_ = signals
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetAgentWinRates
+// Tests: GetResolvedConflictsByType
+// ---------------------------------------------------------------------------
+// ---------------------------------------------------------------------------
+// Tests: Agents (CreateAgentWithAudit, UpdateAgentWithAudit,
+//        UpdateAgentTagsWithAudit)
 // ---------------------------------------------------------------------------
 
-func TestGetAgentWinRates_Empty(t *testing.T) {
+func TestUpdateAgentWithAudit(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, nil, "some_type")
+	agent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "upd-audit-" + suffix, Name: "Before", Role: model.RoleAgent,
+	})
 	require.NoError(t, err)
-	assert.Empty(t, result)
+
+	newName := "After"
+	updated, err := testDB.UpdateAgentWithAudit(ctx, uuid.Nil, agent.AgentID, &newName, nil,
+		storage.MutationAuditEntry{
+			RequestID: "upd-audit-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "update_agent", ResourceType: "agent",
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "After", updated.Name)
+
+	// Nonexistent agent returns ErrNotFound.
+	_, err = testDB.UpdateAgentWithAudit(ctx, uuid.Nil, "no-such-"+suffix, &newName, nil,
+		storage.MutationAuditEntry{
+			RequestID: "upd-audit-fail-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "update_agent", ResourceType: "agent",
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestGetAgentWinRates_NoResolved(t *testing.T) {
+func TestCreateAgentWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, []string{"agent-" + suffix}, "type-"+suffix)
+	agent, err := testDB.CreateAgentWithAudit(ctx,
+		model.Agent{
+			AgentID: "cwa-" + suffix, Name: "Audited Agent", Role: model.RoleAgent,
+		},
+		storage.MutationAuditEntry{
+			RequestID: "cwa-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "platform_admin",
+			Operation: "create_agent", ResourceType: "agent",
+		})
 	require.NoError(t, err)
-	assert.Empty(t, result, "no resolved conflicts means no win rates")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetConflictAnalytics
-// ---------------------------------------------------------------------------
-
-func TestGetConflictAnalytics(t *testing.T) {
-	ctx := context.Background()
+	assert.Equal(t, "cwa-"+suffix, agent.AgentID)
 
-	now := time.Now().UTC()
-	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From: now.Add(-24 * time.Hour),
-		To:   now,
-	})
+	// Verify retrievable.
+	got, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, "cwa-"+suffix)
 	require.NoError(t, err)
-	assert.Equal(t, analytics.Period.Start, now.Add(-24*time.Hour))
-	assert.Equal(t, analytics.Period.End, now)
+	assert.Equal(t, agent.ID, got.ID)
 }
 
-func TestGetConflictAnalytics_WithFilters(t *testing.T) {
+func TestUpdateAgentTagsWithAudit(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	now := time.Now().UTC()
-	agentID := "filter-agent"
-	decType := "filter-type"
-	kind := "cross_agent"
-	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From:         now.Add(-24 * time.Hour),
-		To:           now,
-		AgentID:      &agentID,
-		DecisionType: &decType,
-		ConflictKind: &kind,
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "tag-audit-" + suffix, Name: "Tag Audit", Role: model.RoleAgent,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, 0, analytics.Summary.TotalDetected)
+
+	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, "tag-audit-"+suffix, []string{"audited"},
+		storage.MutationAuditEntry{
+			RequestID: "tag-audit-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "update_agent_tags", ResourceType: "agent",
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audited"}, updated.Tags)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateRunWithAudit, CompleteRunWithAudit
+// Tests: API Keys (RotateAPIKeyWithAudit, MigrateAgentKeysToAPIKeys)
 // ---------------------------------------------------------------------------
 
-func TestCreateRunWithAudit(t *testing.T) {
+func TestRotateAPIKeyWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "rotate-" + suffix
 
-	agentID := "run-audit-" + suffix
-	run, err := testDB.CreateRunWithAudit(ctx, model.CreateRunRequest{
-		AgentID: agentID,
-	}, storage.MutationAuditEntry{
-		RequestID:    "req-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: agentID,
-		ActorRole:    "agent",
-		HTTPMethod:   "POST",
-		Endpoint:     "/v1/runs",
-		Operation:    "create",
-		ResourceType: "run",
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, Name: "Rotate Agent", Role: model.RoleAgent,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, agentID, run.AgentID)
-	assert.Equal(t, model.RunStatusRunning, run.Status)
-	assert.NotEqual(t, uuid.Nil, run.ID)
-}
-
-func TestCompleteRunWithAudit(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
 
-	agentID := "run-complete-" + suffix
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	oldKey, err := testDB.CreateAPIKeyWithAudit(ctx,
+		model.APIKey{
+			Prefix: "old_", KeyHash: "oldhash_" + suffix,
+			AgentID: agentID, OrgID: uuid.Nil, Label: "Old Key", CreatedBy: "admin",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "create-old-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "create_api_key", ResourceType: "api_key",
+		})
 	require.NoError(t, err)
 
-	err = testDB.CompleteRunWithAudit(ctx, uuid.Nil, run.ID,
-		model.RunStatusCompleted,
-		map[string]any{"result": "ok"},
+	newKey, err := testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID,
+		model.APIKey{
+			Prefix: "new_", KeyHash: "newhash_" + suffix,
+			AgentID: agentID, OrgID: uuid.Nil, Label: "New Key", CreatedBy: "admin",
+		},
 		storage.MutationAuditEntry{
-			RequestID:    "req-c-" + suffix,
-			OrgID:        uuid.Nil,
-			ActorAgentID: agentID,
-			ActorRole:    "agent",
-			HTTPMethod:   "POST",
-			Endpoint:     "/v1/runs/" + run.ID.String() + "/complete",
-			Operation:    "complete",
-			ResourceType: "run",
-			ResourceID:   run.ID.String(),
+			RequestID: "rotate-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "rotate_api_key", ResourceType: "api_key",
 		})
 	require.NoError(t, err)
+	assert.Equal(t, "new_", newKey.Prefix)
 
-	got, err := testDB.GetRun(ctx, uuid.Nil, run.ID)
+	// Old key should be revoked.
+	gotOld, err := testDB.GetAPIKeyByID(ctx, uuid.Nil, oldKey.ID)
 	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusCompleted, got.Status)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: Pool utilities (Ping, Close, IsDuplicateKey)
-// ---------------------------------------------------------------------------
-
-func TestPing(t *testing.T) {
-	ctx := context.Background()
+	assert.NotNil(t, gotOld.RevokedAt)
 
-	err := testDB.Ping(ctx)
-	require.NoError(t, err)
-}
-
-func TestIsDuplicateKey(t *testing.T) {
-	// IsDuplicateKey should return false for non-PG errors.
-	assert.False(t, testDB.IsDuplicateKey(fmt.Errorf("some random error")))
-	assert.False(t, testDB.IsDuplicateKey(nil))
+	// Rotating an already-revoked key should fail.
+	_, err = testDB.RotateAPIKeyWithAudit(ctx, uuid.Nil, oldKey.ID,
+		model.APIKey{
+			Prefix: "fail_", KeyHash: "failhash_" + suffix,
+			AgentID: agentID, OrgID: uuid.Nil, Label: "Fail", CreatedBy: "admin",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "rotate-fail-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "rotate_api_key", ResourceType: "api_key",
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: NewPgCandidateFinder
-// ---------------------------------------------------------------------------
+func TestMigrateAgentKeysToAPIKeys(t *testing.T) {
+	ctx := context.Background()
 
-func TestNewPgCandidateFinder(t *testing.T) {
-	finder := storage.NewPgCandidateFinder(testDB)
-	assert.NotNil(t, finder)
+	// Should be idempotent and succeed (even with no agents to migrate).
+	migrated, err := testDB.MigrateAgentKeysToAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, migrated, 0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: BatchDeleteDecisions (empty batch)
+// Tests: Decisions (RetractDecision, EraseDecision, GetDecisionErasure,
+//        GetRevisionDepth, GetDecisionOutcomeSignals,
+//        GetCitationPercentilesForOrg, ExportDecisionsCursor,
+//        GetDecisionForScoring)
 // ---------------------------------------------------------------------------
 
-func TestBatchDeleteDecisions_NothingToDelete(t *testing.T) {
+func TestRetractDecision(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "retract-" + suffix
 
-	// Use a far-past cutoff so nothing qualifies for deletion.
-	farPast := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	nonexistentType := "nonexistent_type_xyz"
-	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, farPast, &nonexistentType, nil, nil, 100)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), result.Decisions)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: InsertEventsIdempotent (10% -> targeted coverage)
-// ---------------------------------------------------------------------------
 
-// NOTE: TestInsertEventsIdempotent_NewEvents, _DuplicatesSafe, and _PartialDuplicate
-// are covered by TestInsertEventsIdempotent, _WithDuplicates, and _SingleEvent.
-// The ON CONFLICT (id, occurred_at) clause now matches the composite PK.
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "retract_test", Outcome: "to_retract", Confidence: 0.7,
+	})
+	require.NoError(t, err)
 
-func TestInsertEventsIdempotent_EmptySlice(t *testing.T) {
-	ctx := context.Background()
+	err = testDB.RetractDecision(ctx, uuid.Nil, d.ID, "test reason", agentID, &storage.MutationAuditEntry{
+		RequestID: "retract-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: agentID, ActorRole: "admin",
+		Operation: "retract_decision", ResourceType: "decision",
+	})
+	require.NoError(t, err)
 
-	count, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{})
+	// Decision should now have valid_to set.
+	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), count, "empty slice should return 0 without error")
-}
+	assert.NotNil(t, got.ValidTo, "retracted decision should have valid_to set")
 
-// ---------------------------------------------------------------------------
-// Tests: GetResolvedConflictsByType (0% -> full coverage)
-// ---------------------------------------------------------------------------
+	// CurrentOnly should not find the retracted decision.
+	_, err = testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{CurrentOnly: true})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 
-func TestGetResolvedConflictsByType(t *testing.T) {
+	// Retracting again should fail (already retracted).
+	err = testDB.RetractDecision(ctx, uuid.Nil, d.ID, "double retract", agentID, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestEraseDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "erase-" + suffix
 
-	agentA := "resolved-a-" + suffix
-	agentB := "resolved-b-" + suffix
-	decisionType := "resolved_type_" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+
+	reasoning := "sensitive data here"
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "erase_test", Outcome: "sensitive outcome",
+		Confidence: 0.9, Reasoning: &reasoning,
+	})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "approach_alpha",
-		Confidence: 0.85, Metadata: map[string]any{},
+	// Add alternatives and evidence for erasure.
+	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+		{DecisionID: d.ID, Label: "Alt 1"},
 	})
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "approach_beta",
-		Confidence: 0.90, Metadata: map[string]any{},
+	rel := float32(0.9)
+	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
+		{DecisionID: d.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence content", RelevanceScore: &rel},
 	})
 	require.NoError(t, err)
 
-	// Insert a conflict.
-	topicSim := 0.92
-	outcomeDiv := 0.88
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decisionType,
-		DecisionTypeB:     decisionType,
-		OutcomeA:          "approach_alpha",
-		OutcomeB:          "approach_beta",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
+	// Add claims (derived from reasoning, may contain PII).
+	err = testDB.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: d.ID, OrgID: uuid.Nil, ClaimIdx: 0, ClaimText: "sensitive claim one"},
+		{DecisionID: d.ID, OrgID: uuid.Nil, ClaimIdx: 1, ClaimText: "sensitive claim two"},
 	})
 	require.NoError(t, err)
 
-	// Resolve the conflict with dA as winner.
-	resNote := "alpha approach was better"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "test-resolver", &resNote, &dA.ID, nil, storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-resolver",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/" + conflictID.String(),
-			Operation:    "resolve_conflict",
-			ResourceType: "conflict",
-			ResourceID:   conflictID.String(),
-		})
+	result, err := testDB.EraseDecision(ctx, uuid.Nil, d.ID, "GDPR request", agentID, &storage.MutationAuditEntry{
+		RequestID: "erase-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: agentID, ActorRole: "admin",
+		Operation: "erase_decision", ResourceType: "decision",
+	})
 	require.NoError(t, err)
+	assert.Equal(t, d.ID, result.Erasure.DecisionID)
+	assert.Equal(t, agentID, result.Erasure.ErasedBy)
+	assert.Equal(t, "GDPR request", result.Erasure.Reason)
+	assert.Equal(t, int64(1), result.AlternativesErased)
+	assert.Equal(t, int64(1), result.EvidenceErased)
+	assert.Equal(t, int64(2), result.ClaimsErased)
 
-	// Query resolved conflicts by type.
-	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, decisionType, 10)
+	// Verify decision outcome is scrubbed.
+	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	require.NotEmpty(t, results, "should find at least one resolved conflict")
+	assert.Equal(t, storage.ErasedSentinel, got.Outcome)
+	assert.Equal(t, storage.ErasedSentinel, *got.Reasoning)
 
-	found := false
-	for _, r := range results {
-		if r.ID == conflictID {
-			found = true
-			assert.Equal(t, decisionType, r.DecisionType)
-			assert.Equal(t, dA.ID, r.WinningDecisionID)
-			assert.Equal(t, "approach_alpha", r.WinningOutcome)
-			assert.Equal(t, "approach_beta", r.LosingOutcome)
-			assert.NotZero(t, r.ResolvedAt)
-			break
-		}
+	// Verify claims are scrubbed (claim_text replaced, embedding nulled).
+	claims, err := testDB.FindClaimsByDecision(ctx, d.ID, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, claims, 2, "claims should still exist after erasure")
+	for _, c := range claims {
+		assert.Equal(t, storage.ErasedSentinel, c.ClaimText, "claim_text must be scrubbed")
+		assert.Nil(t, c.Embedding, "claim embedding must be nulled")
 	}
-	assert.True(t, found, "resolved conflict should appear in results")
-}
-
-func TestGetResolvedConflictsByType_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
 
-	// With limit 0, the function should default to 5 (not error).
-	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, "nonexistent_type", 0)
+	// GetDecisionErasure should return the record.
+	erasure, err := testDB.GetDecisionErasure(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	assert.Empty(t, results, "nonexistent type should return empty")
+	assert.Equal(t, d.ID, erasure.DecisionID)
+
+	// Erasing again should fail.
+	_, err = testDB.EraseDecision(ctx, uuid.Nil, d.ID, "double erase", agentID, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrAlreadyErased)
 }
 
-func TestGetResolvedConflictsByType_NoResults(t *testing.T) {
+func TestGetDecisionErasure_NotFound(t *testing.T) {
 	ctx := context.Background()
 
-	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.New(), "anything", 5)
-	require.NoError(t, err)
-	assert.Empty(t, results)
+	_, err := testDB.GetDecisionErasure(ctx, uuid.Nil, uuid.New())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: ResolveConflictGroup (0% -> full coverage)
-// ---------------------------------------------------------------------------
-
-func TestResolveConflictGroup_WithWinner(t *testing.T) {
+func TestGetRevisionDepth(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "depth-" + suffix
 
-	agentA := "grp-a-" + suffix
-	agentB := "grp-b-" + suffix
-	decisionType := "grp_type_" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	// Create original decision.
+	d0, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "depth_test", Outcome: "v0", Confidence: 0.5,
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+
+	// Depth of original should be 0.
+	depth, err := testDB.GetRevisionDepth(ctx, d0.ID, uuid.Nil)
 	require.NoError(t, err)
+	assert.Equal(t, 0, depth)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "opt_x",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	// Revise once.
+	d1, err := testDB.ReviseDecision(ctx, d0.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "depth_test", Outcome: "v1", Confidence: 0.7,
+	}, nil)
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "opt_y",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
+	depth1, err := testDB.GetRevisionDepth(ctx, d1.ID, uuid.Nil)
 	require.NoError(t, err)
+	assert.Equal(t, 1, depth1)
 
-	topicSim := 0.90
-	outcomeDiv := 0.80
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decisionType,
-		DecisionTypeB:     decisionType,
-		OutcomeA:          "opt_x",
-		OutcomeB:          "opt_y",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
-	})
+	// Revise again.
+	d2, err := testDB.ReviseDecision(ctx, d1.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "depth_test", Outcome: "v2", Confidence: 0.9,
+	}, nil)
 	require.NoError(t, err)
 
-	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	depth2, err := testDB.GetRevisionDepth(ctx, d2.ID, uuid.Nil)
 	require.NoError(t, err)
-	require.NotNil(t, conflict)
-	require.NotNil(t, conflict.GroupID, "conflict should have a group_id")
+	assert.Equal(t, 2, depth2)
+}
 
-	resNote := "agent B had higher confidence"
-	affected, err := testDB.ResolveConflictGroup(ctx,
-		*conflict.GroupID, uuid.Nil,
-		"resolved", "test-admin", &resNote, &agentB, nil,
-		storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-admin",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/groups/" + conflict.GroupID.String(),
-			Operation:    "resolve_conflict_group",
-			ResourceType: "conflict_group",
-			ResourceID:   conflict.GroupID.String(),
-		})
+func TestGetDecisionOutcomeSignals(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "outsig-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Equal(t, 1, affected, "should resolve 1 conflict in the group")
 
-	resolvedStatus := "resolved"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		Status: &resolvedStatus,
-	}, 100, 0)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "outsig_test_" + suffix, Outcome: "signal_outcome", Confidence: 0.8,
+	})
 	require.NoError(t, err)
 
-	found := false
-	for _, c := range conflicts {
-		if c.ID == conflictID {
-			found = true
-			assert.Equal(t, "resolved", c.Status)
-			break
-		}
-	}
-	assert.True(t, found, "conflict should be resolved after group resolution")
+	signals, err := testDB.GetDecisionOutcomeSignals(ctx, d.ID, uuid.Nil)
+	require.NoError(t, err)
+	// No supersession, no citations, no conflicts for a fresh decision.
+	assert.Nil(t, signals.SupersessionVelocityHours)
+	assert.Equal(t, 0, signals.PrecedentCitationCount)
+	assert.Equal(t, 0, signals.ConflictFate.Won)
 }
 
-func TestResolveConflictGroup_FalsePositive(t *testing.T) {
+func TestGetCitationPercentilesForOrg(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	agentA := "grpwf-a-" + suffix
-	agentB := "grpwf-b-" + suffix
-	decisionType := "grpwf_type_" + suffix
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	percentiles, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
 	require.NoError(t, err)
+	_ = percentiles
+}
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "alpha",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+func TestGetDecisionForScoring(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "scoring-" + suffix
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "beta",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	topicSim := 0.85
-	outcomeDiv := 0.75
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decisionType,
-		DecisionTypeB:     decisionType,
-		OutcomeA:          "alpha",
-		OutcomeB:          "beta",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "scoring_test", Outcome: "score_me", Confidence: 0.8,
 	})
 	require.NoError(t, err)
 
-	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	got, err := testDB.GetDecisionForScoring(ctx, d.ID, uuid.Nil)
 	require.NoError(t, err)
-	require.NotNil(t, conflict.GroupID)
+	assert.Equal(t, d.ID, got.ID)
+	assert.Equal(t, "score_me", got.Outcome)
 
-	resNote := "not worth resolving"
-	fpLabel := "unrelated_false_positive"
-	affected, err := testDB.ResolveConflictGroup(ctx,
-		*conflict.GroupID, uuid.Nil,
-		"false_positive", "test-admin", &resNote, nil, &fpLabel,
-		storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-admin",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/groups/" + conflict.GroupID.String(),
-			Operation:    "resolve_conflict_group",
-			ResourceType: "conflict_group",
-			ResourceID:   conflict.GroupID.String(),
-		})
-	require.NoError(t, err)
-	assert.Equal(t, 1, affected)
+	// Nonexistent decision.
+	_, err = testDB.GetDecisionForScoring(ctx, uuid.New(), uuid.Nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestResolveConflictGroup_NotFound(t *testing.T) {
+func TestExportDecisionsCursor(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "export-" + suffix
 
-	_, err := testDB.ResolveConflictGroup(ctx,
-		uuid.New(), uuid.Nil,
-		"resolved", "test-admin", nil, nil, nil,
-		storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-admin",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/groups/nonexistent",
-			Operation:    "resolve_conflict_group",
-			ResourceType: "conflict_group",
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	for i := range 3 {
+		_, err = testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "export_test_" + suffix, Outcome: fmt.Sprintf("export_%d", i),
+			Confidence: 0.7,
 		})
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+		require.NoError(t, err)
+	}
+
+	dType := "export_test_" + suffix
+	decisions, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{
+		DecisionType: &dType,
+	}, nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, decisions, 2)
+
+	// Build cursor from the last returned decision for keyset pagination.
+	last := decisions[len(decisions)-1]
+	cursor := &storage.ExportCursor{ValidFrom: last.ValidFrom, ID: last.ID}
+
+	// Fetch next page.
+	decisions2, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{
+		DecisionType: &dType,
+	}, cursor, 2)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(decisions2), 1)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CascadeResolveByOutcome
+// Tests: Grants (CreateGrantWithAudit, DeleteGrantWithAudit)
 // ---------------------------------------------------------------------------
 
-func TestCascadeResolveByOutcome(t *testing.T) {
+func TestCreateGrantWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	agentA := "cascade-a-" + suffix
-	agentB := "cascade-b-" + suffix
-	decisionType := "cascade_type_" + suffix
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gwaud-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
+	})
+	require.NoError(t, err)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gwaud-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+
+	resID := "gwaud-resource-" + suffix
+	grant, err := testDB.CreateGrantWithAudit(ctx,
+		model.AccessGrant{
+			GrantorID: grantor.ID, GranteeID: grantee.ID,
+			ResourceType: "agent_traces", ResourceID: &resID,
+			Permission: "read",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "gwaud-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "create_grant", ResourceType: "grant",
+		})
 	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, grant.ID)
+}
 
-	// Create a "winning" embedding direction and an opposing direction.
-	// 1024-dim vectors: winning points in +x, loser points in -x.
-	winEmb := make([]float32, 1024)
-	winEmb[0] = 1.0
-	loseEmb := make([]float32, 1024)
-	loseEmb[0] = -1.0
-	// An aligned embedding (slight perturbation of winner).
-	alignedEmb := make([]float32, 1024)
-	alignedEmb[0] = 0.95
-	alignedEmb[1] = 0.05
-	// An unrelated embedding (orthogonal).
-	unrelatedEmb := make([]float32, 1024)
-	unrelatedEmb[2] = 1.0
+func TestDeleteGrantWithAudit(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	// Decision A1 (winner) — outcome aligns with winEmb.
-	dA1, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType:     decisionType,
-		Outcome:          "use Redis for caching",
-		Confidence:       0.9,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(winEmb),
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "dgwaud-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
 	})
 	require.NoError(t, err)
 
-	// Decision B1 (loser) — outcome opposes winner.
-	dB1, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType:     decisionType,
-		Outcome:          "use Memcached for caching",
-		Confidence:       0.8,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(loseEmb),
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "dgwaud-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
 	})
 	require.NoError(t, err)
 
-	// Decision A2 (aligned with winner).
-	dA2, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType:     decisionType,
-		Outcome:          "Redis cluster for sessions",
-		Confidence:       0.85,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(alignedEmb),
+	resID := "dgwaud-res-" + suffix
+	grant, err := testDB.CreateGrant(ctx, model.AccessGrant{
+		GrantorID: grantor.ID, GranteeID: grantee.ID,
+		ResourceType: "agent_traces", ResourceID: &resID, Permission: "read",
 	})
 	require.NoError(t, err)
 
-	// Decision B2 (opposes winner).
-	dB2, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType:     decisionType,
-		Outcome:          "Memcached with mcrouter",
-		Confidence:       0.75,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(loseEmb),
-	})
+	err = testDB.DeleteGrantWithAudit(ctx, uuid.Nil, grant.ID,
+		storage.MutationAuditEntry{
+			RequestID: "dgwaud-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "delete_grant", ResourceType: "grant",
+		})
 	require.NoError(t, err)
 
-	// Decision A3 and B3: unrelated topic (orthogonal embeddings).
-	dA3, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType:     decisionType,
-		Outcome:          "unrelated topic A",
-		Confidence:       0.7,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(unrelatedEmb),
-	})
+	// Should no longer have access.
+	has, err := testDB.HasAccess(ctx, uuid.Nil, grantee.ID, "agent_traces", "dgwaud-res-"+suffix, "read")
 	require.NoError(t, err)
+	assert.False(t, has)
 
-	dB3, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType:     decisionType,
-		Outcome:          "unrelated topic B",
-		Confidence:       0.7,
-		Metadata:         map[string]any{},
-		OutcomeEmbedding: ptrVec(unrelatedEmb),
-	})
-	require.NoError(t, err)
+	// Delete again should fail.
+	err = testDB.DeleteGrantWithAudit(ctx, uuid.Nil, grant.ID,
+		storage.MutationAuditEntry{
+			RequestID: "dgwaud-again-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "delete_grant", ResourceType: "grant",
+		})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
 
-	// Insert 3 conflicts in the same group (same agents, same decision_type).
-	topicSim := 0.90
-	outcomeDiv := 0.80
-	sig := topicSim * outcomeDiv
+// ---------------------------------------------------------------------------
+// Tests: Retention (GetOrgsWithRetention, HoldLifecycle, CountEligible)
+// ---------------------------------------------------------------------------
 
-	conflict1ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
-		DecisionAID: dA1.ID, DecisionBID: dB1.ID,
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "use Redis", OutcomeB: "use Memcached",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
-		ScoringMethod: "text",
-	})
-	require.NoError(t, err)
+func TestGetOrgsWithRetention(t *testing.T) {
+	ctx := context.Background()
 
-	conflict2ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
-		DecisionAID: dA2.ID, DecisionBID: dB2.ID,
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "Redis cluster", OutcomeB: "Memcached mcrouter",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
-		ScoringMethod: "text",
-	})
+	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
 	require.NoError(t, err)
 
-	conflict3ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
-		DecisionAID: dA3.ID, DecisionBID: dB3.ID,
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "unrelated A", OutcomeB: "unrelated B",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
-		ScoringMethod: "text",
-	})
+	orgs, err := testDB.GetOrgsWithRetention(ctx)
 	require.NoError(t, err)
+	for _, o := range orgs {
+		assert.NotEqual(t, uuid.Nil, o.OrgID, "default org should not appear without retention policy")
+	}
+}
 
-	// Verify all 3 conflicts share a group.
-	c1, err := testDB.GetConflict(ctx, conflict1ID, uuid.Nil)
-	require.NoError(t, err)
-	require.NotNil(t, c1.GroupID)
-	groupID := *c1.GroupID
+func TestHoldLifecycle(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	c2, err := testDB.GetConflict(ctx, conflict2ID, uuid.Nil)
+	now := time.Now().UTC()
+	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
+		OrgID: uuid.Nil, Reason: "legal hold " + suffix,
+		HoldFrom: now.Add(-30 * 24 * time.Hour), HoldTo: now.Add(30 * 24 * time.Hour),
+		CreatedBy: "admin",
+	})
 	require.NoError(t, err)
-	require.NotNil(t, c2.GroupID)
-	assert.Equal(t, groupID, *c2.GroupID, "all conflicts should share a group")
+	assert.NotEqual(t, uuid.Nil, hold.ID)
+	assert.Equal(t, "legal hold "+suffix, hold.Reason)
 
-	c3, err := testDB.GetConflict(ctx, conflict3ID, uuid.Nil)
+	// List holds.
+	holds, err := testDB.ListHolds(ctx, uuid.Nil)
 	require.NoError(t, err)
-	require.NotNil(t, c3.GroupID)
-	assert.Equal(t, groupID, *c3.GroupID, "all conflicts should share a group")
+	found := false
+	for _, h := range holds {
+		if h.ID == hold.ID {
+			found = true
+			assert.Nil(t, h.ReleasedAt)
+			break
+		}
+	}
+	assert.True(t, found, "should find newly created hold")
 
-	// Now cascade from conflict1 with dA1 as winner (winEmb direction).
-	cascaded, err := testDB.CascadeResolveByOutcome(ctx,
-		uuid.Nil, groupID, dA1.ID, conflict1ID,
-		0.80, // threshold
-		storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-admin",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/" + conflict1ID.String(),
-			Operation:    "conflict_cascade_resolved",
-			ResourceType: "conflict",
-			ResourceID:   conflict1ID.String(),
-		})
+	// Release hold.
+	released, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
 	require.NoError(t, err)
+	assert.True(t, released)
 
-	// Conflict2 should be cascade-resolved: dA2 has alignedEmb which is similar
-	// to winEmb (cosine sim ~0.998), while dB2 has loseEmb (cosine sim = -1.0).
-	// So dA2 should win.
-	assert.Equal(t, 1, cascaded, "only conflict2 should cascade (conflict3 has unrelated embeddings)")
-
-	// Verify conflict2 was resolved with dA2 as winner.
-	c2After, err := testDB.GetConflict(ctx, conflict2ID, uuid.Nil)
+	// Release again should return false.
+	released2, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, "resolved", c2After.Status)
-	assert.Equal(t, "cascade", *c2After.ResolvedBy)
-	require.NotNil(t, c2After.WinningDecisionID)
-	assert.Equal(t, dA2.ID, *c2After.WinningDecisionID, "aligned side (A) should win")
-	require.NotNil(t, c2After.ResolutionNote)
-	assert.Contains(t, *c2After.ResolutionNote, "cascade")
+	assert.False(t, released2)
+}
 
-	// Verify conflict3 is still open (unrelated embeddings, no side exceeds 0.80).
-	c3After, err := testDB.GetConflict(ctx, conflict3ID, uuid.Nil)
-	require.NoError(t, err)
-	assert.Equal(t, "open", c3After.Status, "conflict3 should remain open (unrelated topic)")
+func TestCountEligibleDecisionsWithFilters(t *testing.T) {
+	ctx := context.Background()
 
-	// Suppress unused variable warnings.
-	_ = dB1
-	_ = dB3
-	_ = conflict3ID
+	before := time.Now().UTC().Add(-365 * 24 * time.Hour)
+	dType := "some_type"
+	agent := "some_agent"
+	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, before, &dType, &agent)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
 }
 
-func TestCascadeResolveByOutcome_NoEmbeddings(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: Events (InsertEvent)
+// ---------------------------------------------------------------------------
+
+func TestInsertEvent(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "single-evt-" + suffix
 
-	agentA := "casc-noemb-a-" + suffix
-	agentB := "casc-noemb-b-" + suffix
-	decisionType := "casc_noemb_" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Decisions without outcome embeddings.
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "opt_x",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	nums, err := testDB.ReserveSequenceNums(ctx, 1)
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "opt_y",
-		Confidence: 0.9, Metadata: map[string]any{},
+	now := time.Now().UTC()
+	err = testDB.InsertEvent(ctx, model.AgentEvent{
+		ID: uuid.New(), RunID: run.ID, OrgID: uuid.Nil,
+		EventType: model.EventDecisionStarted, SequenceNum: nums[0],
+		OccurredAt: now, AgentID: agentID,
+		Payload: map[string]any{"test": true}, CreatedAt: now,
 	})
 	require.NoError(t, err)
 
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
-		DecisionAID: dA.ID, DecisionBID: dB.ID,
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "opt_x", OutcomeB: "opt_y",
-		ScoringMethod: "text",
-	})
+	events, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, model.EventDecisionStarted, events[0].EventType)
+}
 
-	c, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+// ---------------------------------------------------------------------------
+// Tests: Claims (FindRetriableClaimFailures)
+// ---------------------------------------------------------------------------
+
+func TestFindRetriableClaimFailures(t *testing.T) {
+	ctx := context.Background()
+
+	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 50)
 	require.NoError(t, err)
-	require.NotNil(t, c.GroupID)
-
-	// Cascade should resolve 0 — no embeddings to compare.
-	cascaded, err := testDB.CascadeResolveByOutcome(ctx,
-		uuid.Nil, *c.GroupID, dA.ID, uuid.New(), // trigger is a different ID
-		0.80,
-		storage.MutationAuditEntry{
-			RequestID:    uuid.New().String(),
-			OrgID:        uuid.Nil,
-			ActorAgentID: "test-admin",
-			ActorRole:    "admin",
-			HTTPMethod:   "PATCH",
-			Endpoint:     "/v1/conflicts/test",
-			Operation:    "conflict_cascade_resolved",
-			ResourceType: "conflict",
-		})
-	require.NoError(t, err)
-	assert.Equal(t, 0, cascaded, "no cascade without embeddings")
-}
-
-// ptrVec returns a pointer to a pgvector.Vector from a float32 slice.
-func ptrVec(v []float32) *pgvector.Vector {
-	vec := pgvector.NewVector(v)
-	return &vec
-}
+	_ = refs
+}
 
 // ---------------------------------------------------------------------------
-// Tests: CreateTraceAndAdjudicateConflictTx (0% -> full coverage)
+// Tests: Organizations (EnsureDefaultOrg, GetOrganization)
 // ---------------------------------------------------------------------------
 
-func TestCreateTraceAndAdjudicateConflictTx(t *testing.T) {
+func TestEnsureDefaultOrg(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	agentA := "adjud-a-" + suffix
-	agentB := "adjud-b-" + suffix
-	decisionType := "adjud_type_" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "left",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "right",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	topicSim := 0.92
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decisionType,
-		DecisionTypeB:     decisionType,
-		OutcomeA:          "left",
-		OutcomeB:          "right",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
-	})
-	require.NoError(t, err)
 
-	adjudicatorAgent := "adjudicator-" + suffix
-	resNote := "chose left because of policy alignment"
-	run, decision, err := testDB.CreateTraceAndAdjudicateConflictTx(ctx,
-		storage.CreateTraceParams{
-			AgentID:  adjudicatorAgent,
-			OrgID:    uuid.Nil,
-			Metadata: map[string]any{"purpose": "adjudication"},
-			Decision: model.Decision{
-				DecisionType: "adjudication",
-				Outcome:      "selected left approach",
-				Confidence:   0.95,
-			},
-			AuditEntry: &storage.MutationAuditEntry{
-				RequestID:    uuid.New().String(),
-				OrgID:        uuid.Nil,
-				ActorAgentID: adjudicatorAgent,
-				ActorRole:    "admin",
-				HTTPMethod:   "POST",
-				Endpoint:     "/v1/trace",
-				Operation:    "create_trace",
-				ResourceType: "decision",
-			},
-		},
-		storage.AdjudicateConflictInTraceParams{
-			ConflictID:        conflictID,
-			ResolvedBy:        adjudicatorAgent,
-			ResNote:           &resNote,
-			WinningDecisionID: &dA.ID,
-			Audit: storage.MutationAuditEntry{
-				RequestID:    uuid.New().String(),
-				OrgID:        uuid.Nil,
-				ActorAgentID: adjudicatorAgent,
-				ActorRole:    "admin",
-				HTTPMethod:   "POST",
-				Endpoint:     "/v1/trace",
-				Operation:    "adjudicate_conflict",
-				ResourceType: "conflict",
-			},
-		},
-	)
+	err := testDB.EnsureDefaultOrg(ctx)
 	require.NoError(t, err)
 
-	assert.Equal(t, adjudicatorAgent, run.AgentID)
-	assert.Equal(t, model.RunStatusCompleted, run.Status)
-	assert.Equal(t, "selected left approach", decision.Outcome)
-
-	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	err = testDB.EnsureDefaultOrg(ctx)
 	require.NoError(t, err)
-	require.NotNil(t, conflict)
-	assert.Equal(t, "resolved", conflict.Status)
-	require.NotNil(t, conflict.WinningDecisionID)
-	assert.Equal(t, dA.ID, *conflict.WinningDecisionID)
 }
 
-func TestCreateTraceAndAdjudicateConflictTx_ConflictNotFound(t *testing.T) {
+func TestGetOrganization(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
 
-	_, _, err := testDB.CreateTraceAndAdjudicateConflictTx(ctx,
-		storage.CreateTraceParams{
-			AgentID:  "adjud-notfound-" + suffix,
-			OrgID:    uuid.Nil,
-			Metadata: map[string]any{},
-			Decision: model.Decision{
-				DecisionType: "adjudication",
-				Outcome:      "test",
-				Confidence:   0.5,
-			},
-		},
-		storage.AdjudicateConflictInTraceParams{
-			ConflictID: uuid.New(),
-			ResolvedBy: "test",
-			Audit: storage.MutationAuditEntry{
-				RequestID:    uuid.New().String(),
-				OrgID:        uuid.Nil,
-				ActorAgentID: "test",
-				ActorRole:    "admin",
-				HTTPMethod:   "POST",
-				Endpoint:     "/v1/trace",
-				Operation:    "adjudicate_conflict",
-				ResourceType: "conflict",
-			},
-		},
-	)
+	org, err := testDB.GetOrganization(ctx, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, org.ID)
+	assert.NotEmpty(t, org.Name)
+
+	// Nonexistent org.
+	_, err = testDB.GetOrganization(ctx, uuid.New())
 	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: FindSimilar (0% -> full coverage)
+// Tests: Conflicts (RefreshConflicts, GetGlobalOpenConflictCount,
+//        GetConflictGroupKind)
 // ---------------------------------------------------------------------------
 
-func TestFindSimilar_WithEmbeddings(t *testing.T) {
+func TestRefreshConflicts(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "similar-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	err := testDB.RefreshConflicts(ctx)
 	require.NoError(t, err)
+}
 
-	dims := 1024
+func TestGetGlobalOpenConflictCount(t *testing.T) {
+	ctx := context.Background()
 
-	var decisionIDs []uuid.UUID
-	for i := range 3 {
-		d, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: "similar_test",
-			Outcome:      fmt.Sprintf("outcome_%d", i),
-			Confidence:   float32(i+1) * 0.3,
-			Metadata:     map[string]any{},
-		})
-		require.NoError(t, err)
-		decisionIDs = append(decisionIDs, d.ID)
+	count, err := testDB.GetGlobalOpenConflictCount(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(0))
+}
 
-		vec := make([]float32, dims)
-		for j := range vec {
-			vec[j] = float32(i+1) * float32(j) / float32(dims)
-		}
-		emb := pgvector.NewVector(vec)
-		err = testDB.BackfillEmbedding(ctx, d.ID, d.OrgID, emb)
-		require.NoError(t, err)
+func TestGetConflictGroupKind(t *testing.T) {
+	ctx := context.Background()
 
-		outcomeVec := make([]float32, dims)
-		for j := range outcomeVec {
-			outcomeVec[j] = float32(i+1) * float32(j+1) / float32(dims*2)
-		}
-		outcomeEmb := pgvector.NewVector(outcomeVec)
-		err = testDB.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, outcomeEmb)
-		require.NoError(t, err)
-	}
+	_, err := testDB.GetConflictGroupKind(ctx, uuid.New(), uuid.Nil)
+	require.Error(t, err)
+}
 
-	queryVec := make([]float32, dims)
-	for j := range queryVec {
-		queryVec[j] = float32(j) / float32(dims)
-	}
+func TestGetFalsePositiveRate(t *testing.T) {
+	ctx := context.Background()
 
-	finder := storage.NewPgCandidateFinder(testDB)
-	results, err := finder.FindSimilar(ctx, uuid.Nil, queryVec, decisionIDs[0], nil, 10)
+	// With no resolved/false_positive conflicts, rate should be 0.
+	rate, err := testDB.GetFalsePositiveRate(ctx, uuid.Nil)
 	require.NoError(t, err)
-
-	assert.GreaterOrEqual(t, len(results), 2, "should find at least 2 similar decisions (excluding the query decision)")
-	for _, r := range results {
-		assert.NotEqual(t, decisionIDs[0], r.DecisionID, "excluded decision should not appear")
-
-	}
+	assert.Equal(t, 0.0, rate.Rate)
+	assert.GreaterOrEqual(t, rate.Resolved, 0)
+	assert.GreaterOrEqual(t, rate.FalsePositive, 0)
 }
 
-func TestFindSimilar_DefaultLimit(t *testing.T) {
+func TestGetGlobalFalsePositiveRate(t *testing.T) {
 	ctx := context.Background()
 
-	finder := storage.NewPgCandidateFinder(testDB)
-	results, err := finder.FindSimilar(ctx, uuid.New(), []float32{0.1, 0.2, 0.3}, uuid.New(), nil, 0)
+	rate, err := testDB.GetGlobalFalsePositiveRate(ctx)
 	require.NoError(t, err)
-	assert.Empty(t, results, "random org should return empty")
+	assert.GreaterOrEqual(t, rate, 0.0)
+	assert.LessOrEqual(t, rate, 1.0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignalsBatch (41.7% -> full coverage)
+// Tests: Idempotency (ClearInProgressIdempotency)
 // ---------------------------------------------------------------------------
 
-func TestGetDecisionOutcomeSignalsBatch_EmptySlice(t *testing.T) {
+func TestClearInProgressIdempotency(t *testing.T) {
 	ctx := context.Background()
 
-	result, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{}, uuid.Nil)
+	// ClearInProgressIdempotency requires orgID, agentID, endpoint, and key.
+	// Call with a non-existent key — should succeed (no rows to clear).
+	err := testDB.ClearInProgressIdempotency(ctx, uuid.Nil, "no-agent", "/v1/trace", "nonexistent-key")
 	require.NoError(t, err)
-	assert.Empty(t, result)
 }
 
-func TestGetDecisionOutcomeSignalsBatch_WithSupersession(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: CreateOrgWithOwnerAndKeyTx
+// ---------------------------------------------------------------------------
+
+func TestCreateOrgWithOwnerAndKeyTx(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "signals-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	original, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signals_test", Outcome: "v1",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
 
-	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signals_test", Outcome: "v2",
-		Confidence: 0.9, Metadata: map[string]any{},
-	}, nil)
+	org, agent, key, err := testDB.CreateOrgWithOwnerAndKeyTx(ctx,
+		model.Organization{
+			Name: "Test Org " + suffix, Slug: "testorg-" + suffix, Plan: "starter",
+		},
+		model.Agent{
+			AgentID: "owner-" + suffix, Name: "Owner", Role: model.RoleOrgOwner,
+		},
+		model.APIKey{
+			Prefix: "to_", KeyHash: "orghash_" + suffix,
+			AgentID: "owner-" + suffix, Label: "Org Key", CreatedBy: "system",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "org-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "system", ActorRole: "platform_admin",
+			Operation: "create_org", ResourceType: "organization",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "org-agent-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "system", ActorRole: "platform_admin",
+			Operation: "create_agent", ResourceType: "agent",
+		},
+		storage.MutationAuditEntry{
+			RequestID: "org-key-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "system", ActorRole: "platform_admin",
+			Operation: "create_api_key", ResourceType: "api_key",
+		},
+	)
 	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, org.ID)
+	assert.Equal(t, "Test Org "+suffix, org.Name)
+	assert.Equal(t, org.ID, agent.OrgID)
+	assert.Equal(t, org.ID, key.OrgID)
+	assert.Equal(t, string(model.RoleOrgOwner), string(agent.Role))
 
-	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{original.ID}, uuid.Nil)
+	gotOrg, err := testDB.GetOrganization(ctx, org.ID)
 	require.NoError(t, err)
-	require.Contains(t, signals, original.ID)
-	assert.NotNil(t, signals[original.ID].SupersessionVelocityHours,
-		"superseded decision should have supersession velocity")
+	assert.Equal(t, org.Name, gotOrg.Name)
 }
 
-func TestGetDecisionOutcomeSignalsBatch_WithPrecedentCitations(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: MarkDecisionConflictScored, CountUnscoredDecisions, ResetConflictScoredAt
+// ---------------------------------------------------------------------------
+
+func TestMarkDecisionConflictScored(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "cite-signals-" + suffix
 
+	agentID := "scored-" + suffix
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	precedent, err := testDB.CreateDecision(ctx, model.Decision{
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "cite_test", Outcome: "precedent_outcome",
-		Confidence: 0.9, Metadata: map[string]any{},
+		DecisionType: "mark_scored_" + suffix, Outcome: "to be scored",
+		Confidence: 0.5,
 	})
 	require.NoError(t, err)
 
-	for i := range 2 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "cite_test",
-			Outcome:      fmt.Sprintf("cites_precedent_%d", i),
-			Confidence:   0.8,
-			PrecedentRef: &precedent.ID,
-			Metadata:     map[string]any{},
-		})
-		require.NoError(t, err)
-	}
+	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
+	require.NoError(t, err)
 
-	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{precedent.ID}, uuid.Nil)
+	// Marking again should be idempotent (overwrites with now()).
+	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
 	require.NoError(t, err)
-	require.Contains(t, signals, precedent.ID)
-	assert.Equal(t, 2, signals[precedent.ID].PrecedentCitationCount,
-		"should count 2 citations of the precedent")
 }
 
-func TestGetDecisionOutcomeSignalsBatch_WithConflictFate(t *testing.T) {
+func TestCountUnscoredDecisions(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	agentA := "fate-a-" + suffix
-	agentB := "fate-b-" + suffix
-	decisionType := "fate_type_" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "winner_approach",
-		Confidence: 0.85, Metadata: map[string]any{},
-	})
+	// Just verify it returns a non-negative count without error.
+	count, err := testDB.CountUnscoredDecisions(ctx)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, int64(0))
+}
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "loser_approach",
-		Confidence: 0.75, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+func TestResetConflictScoredAt(t *testing.T) {
+	ctx := context.Background()
 
-	topicSim := 0.90
-	outcomeDiv := 0.80
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decisionType,
-		DecisionTypeB:     decisionType,
-		OutcomeA:          "winner_approach",
-		OutcomeB:          "loser_approach",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
-	})
+	affected, err := testDB.ResetConflictScoredAt(ctx)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, affected, int64(0))
+}
 
-	resNote := "dA is better"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "tester", &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: uuid.New().String(), OrgID: uuid.Nil,
-			ActorAgentID: "tester", ActorRole: "admin",
-			HTTPMethod: "PATCH", Endpoint: "/v1/conflicts/" + conflictID.String(),
-			Operation: "resolve_conflict", ResourceType: "conflict",
-			ResourceID: conflictID.String(),
-		})
-	require.NoError(t, err)
+func TestCountUnvalidatedConflicts(t *testing.T) {
+	ctx := context.Background()
 
-	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx,
-		[]uuid.UUID{dA.ID, dB.ID}, uuid.Nil)
+	count, err := testDB.CountUnvalidatedConflicts(ctx)
 	require.NoError(t, err)
-
-	assert.Equal(t, 1, signals[dA.ID].ConflictFate.Won, "dA should have 1 conflict win")
-	assert.Equal(t, 0, signals[dA.ID].ConflictFate.Lost, "dA should have 0 conflict losses")
-	assert.Equal(t, 0, signals[dB.ID].ConflictFate.Won, "dB should have 0 conflict wins")
-	assert.Equal(t, 1, signals[dB.ID].ConflictFate.Lost, "dB should have 1 conflict loss")
+	assert.GreaterOrEqual(t, count, 0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: deleteBatch (0% -> tested via BatchDeleteDecisions with data)
+// Tests: GetDecisionEmbeddings (empty input)
 // ---------------------------------------------------------------------------
 
-func TestBatchDeleteDecisions_WithData(t *testing.T) {
+func TestGetDecisionEmbeddings_Empty(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "delete-batch-" + suffix
-	decisionType := "delete_batch_type_" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	result, err := testDB.GetDecisionEmbeddings(ctx, nil, uuid.Nil)
 	require.NoError(t, err)
+	assert.Empty(t, result)
+}
 
-	pastTime := time.Now().UTC().Add(-48 * time.Hour)
-	for i := range 3 {
-		d, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID:        run.ID,
-			AgentID:      agentID,
-			DecisionType: decisionType,
-			Outcome:      fmt.Sprintf("delete_me_%d", i),
-			Confidence:   0.5,
-			ValidFrom:    pastTime.Add(time.Duration(i) * time.Minute),
-			Metadata:     map[string]any{},
-		})
-		require.NoError(t, err)
+func TestGetDecisionEmbeddings_NoMatch(t *testing.T) {
+	ctx := context.Background()
 
-		err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
-			{DecisionID: d.ID, Label: "alt"},
-		})
-		require.NoError(t, err)
+	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{uuid.New()}, uuid.Nil)
+	require.NoError(t, err)
+	assert.Empty(t, result)
+}
 
-		rel := float32(0.8)
-		_, err = testDB.CreateEvidence(ctx, model.Evidence{
-			DecisionID: d.ID, OrgID: d.OrgID,
-			SourceType: model.SourceDocument, Content: "evidence",
-			RelevanceScore: &rel,
-		})
-		require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: CountConflictGroups
+// ---------------------------------------------------------------------------
 
-		// Add an assessment per decision so the trigger bypass is exercised.
-		_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-			DecisionID:      d.ID,
-			AssessorAgentID: fmt.Sprintf("batch-reviewer-%d", i),
-			Outcome:         model.AssessmentCorrect,
-		})
-		require.NoError(t, err)
-	}
+func TestCountConflictGroups(t *testing.T) {
+	ctx := context.Background()
 
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &decisionType, nil, nil, 100)
+	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{})
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), result.Decisions, "should delete 3 decisions")
-	assert.Equal(t, int64(3), result.Alternatives, "should delete 3 alternatives")
-	assert.Equal(t, int64(3), result.Evidence, "should delete 3 evidence rows")
-	assert.Equal(t, int64(3), result.Assessments, "should delete 3 assessments")
+	assert.GreaterOrEqual(t, count, 0)
 }
 
-func TestGetConflictCount(t *testing.T) {
+func TestCountConflictGroups_StatusFilter(t *testing.T) {
 	ctx := context.Background()
 
-	count, err := testDB.GetConflictCount(ctx, uuid.New(), uuid.Nil)
+	st := "open"
+	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{
+		Status: &st,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 0, count)
+	assert.GreaterOrEqual(t, count, 0)
+}
+
+func TestCountConflictGroups_WithFilters(t *testing.T) {
+	ctx := context.Background()
+
+	dt := "some_type"
+	agent := "some_agent"
+	kind := "cross_agent"
+	count, err := testDB.CountConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{
+		DecisionType: &dt,
+		AgentID:      &agent,
+		ConflictKind: &kind,
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetCitationPercentilesForOrg
+// Tests: ListConflictGroups
 // ---------------------------------------------------------------------------
 
-func TestGetCitationPercentilesForOrg_NoCitations(t *testing.T) {
+func TestListConflictGroups(t *testing.T) {
 	ctx := context.Background()
 
-	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.New())
+	groups, err := testDB.ListConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{}, 10, 0)
 	require.NoError(t, err)
-	assert.Nil(t, breakpoints, "org with no citations should return nil")
+	// May be empty on a fresh DB — just verify no error.
+	_ = groups
 }
 
-func TestGetCitationPercentilesForOrg_WithCitations(t *testing.T) {
+func TestListConflictGroups_Limits(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "percentile-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// Verify clamping: negative offset -> 0, limit 0 -> 50, limit > 1000 -> 1000.
+	groups, err := testDB.ListConflictGroups(ctx, uuid.Nil, storage.ConflictGroupFilters{}, 0, -1)
 	require.NoError(t, err)
+	_ = groups
+}
 
-	precedent, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "percentile_test", Outcome: "precedent",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
+// ---------------------------------------------------------------------------
+// Tests: GetAgentWinRates
+// ---------------------------------------------------------------------------
+
+func TestGetAgentWinRates_Empty(t *testing.T) {
+	ctx := context.Background()
+
+	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, nil, "some_type")
 	require.NoError(t, err)
+	assert.Empty(t, result)
+}
 
-	for i := range 5 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "percentile_test",
-			Outcome:      fmt.Sprintf("cites_%d", i),
-			Confidence:   0.7,
-			PrecedentRef: &precedent.ID,
-			Metadata:     map[string]any{},
-		})
-		require.NoError(t, err)
-	}
+func TestGetAgentWinRates_NoResolved(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
+	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, []string{"agent-" + suffix}, "type-"+suffix)
 	require.NoError(t, err)
-	assert.NotNil(t, breakpoints, "should return percentile breakpoints")
-	assert.Len(t, breakpoints, 4, "should return p25, p50, p75, p90")
+	assert.Empty(t, result, "no resolved conflicts means no win rates")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: WithRetry + isRetriable — cover retry-on-serialization and exhaustion paths
+// Tests: GetConflictAnalytics
 // ---------------------------------------------------------------------------
 
-func TestWithRetry_RetriableErrorSucceedsOnRetry(t *testing.T) {
+func TestGetConflictAnalytics(t *testing.T) {
 	ctx := context.Background()
-	callCount := 0
 
-	// Simulate a serialization failure (40001) that resolves on the 3rd attempt.
-	serialErr := &pgconn.PgError{Code: "40001"}
-	err := storage.WithRetry(ctx, 5, 1*time.Millisecond, func() error {
-		callCount++
-		if callCount < 3 {
-			return serialErr
-		}
-		return nil
+	now := time.Now().UTC()
+	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From: now.Add(-24 * time.Hour),
+		To:   now,
 	})
 	require.NoError(t, err)
-	assert.Equal(t, 3, callCount, "should retry twice before succeeding on the 3rd attempt")
+	assert.Equal(t, analytics.Period.Start, now.Add(-24*time.Hour))
+	assert.Equal(t, analytics.Period.End, now)
 }
 
-func TestWithRetry_RetriableErrorExhaustsRetries(t *testing.T) {
+func TestGetConflictAnalytics_WithFilters(t *testing.T) {
 	ctx := context.Background()
-	callCount := 0
-
-	deadlockErr := &pgconn.PgError{Code: "40P01"}
-	err := storage.WithRetry(ctx, 2, 1*time.Millisecond, func() error {
-		callCount++
-		return deadlockErr
-	})
-	require.Error(t, err)
-	assert.Equal(t, 3, callCount, "should try once + 2 retries = 3 total calls")
-	assert.ErrorIs(t, err, deadlockErr)
-}
-
-func TestWithRetry_ContextCancelledDuringBackoff(t *testing.T) {
-	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
-	defer cancel()
-	callCount := 0
 
-	serialErr := &pgconn.PgError{Code: "40001"}
-	err := storage.WithRetry(ctx, 10, 500*time.Millisecond, func() error {
-		callCount++
-		return serialErr
+	now := time.Now().UTC()
+	agentID := "filter-agent"
+	decType := "filter-type"
+	kind := "cross_agent"
+	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From:         now.Add(-24 * time.Hour),
+		To:           now,
+		AgentID:      &agentID,
+		DecisionType: &decType,
+		ConflictKind: &kind,
 	})
-	// Should be either context.DeadlineExceeded or the pgconn error, depending on timing.
-	require.Error(t, err)
-	// At least 1 call, but context should prevent many retries given the long backoff.
-	assert.GreaterOrEqual(t, callCount, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, analytics.Summary.TotalDetected)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CompleteRunWithAudit (48% -> cover success, not-found, idempotent paths)
+// Tests: CreateRunWithAudit, CompleteRunWithAudit
 // ---------------------------------------------------------------------------
 
-func TestCompleteRunWithAudit_Success(t *testing.T) {
+func TestCreateRunWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "crwa-ok-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusRunning, run.Status)
 
-	audit := storage.MutationAuditEntry{
-		Operation:    "complete_run",
-		ActorRole:    "agent",
+	agentID := "run-audit-" + suffix
+	run, err := testDB.CreateRunWithAudit(ctx, model.CreateRunRequest{
+		AgentID: agentID,
+	}, storage.MutationAuditEntry{
+		RequestID:    "req-" + suffix,
+		OrgID:        uuid.Nil,
 		ActorAgentID: agentID,
-		OrgID:        run.OrgID,
-		ResourceID:   run.ID.String(),
-	}
-	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, map[string]any{"result": "ok"}, audit)
-	require.NoError(t, err)
-
-	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
-	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusCompleted, got.Status)
-	assert.NotNil(t, got.CompletedAt)
-}
-
-func TestCompleteRunWithAudit_NotFound(t *testing.T) {
-	ctx := context.Background()
-	fakeID := uuid.New()
-	audit := storage.MutationAuditEntry{
-		Operation:    "complete_run",
 		ActorRole:    "agent",
-		ActorAgentID: "no-such-agent",
-		OrgID:        uuid.Nil,
-	}
-	err := testDB.CompleteRunWithAudit(ctx, uuid.Nil, fakeID, model.RunStatusCompleted, nil, audit)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+		HTTPMethod:   "POST",
+		Endpoint:     "/v1/runs",
+		Operation:    "create",
+		ResourceType: "run",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, agentID, run.AgentID)
+	assert.Equal(t, model.RunStatusRunning, run.Status)
+	assert.NotEqual(t, uuid.Nil, run.ID)
 }
 
-func TestCompleteRunWithAudit_Idempotent(t *testing.T) {
+func TestCompleteRunWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "crwa-idem-" + suffix
 
+	agentID := "run-complete-" + suffix
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	audit := storage.MutationAuditEntry{
-		Operation:    "complete_run",
-		ActorRole:    "agent",
-		ActorAgentID: agentID,
-		OrgID:        run.OrgID,
-	}
-	// First completion.
-	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, nil, audit)
+	err = testDB.CompleteRunWithAudit(ctx, uuid.Nil, run.ID,
+		model.RunStatusCompleted,
+		map[string]any{"result": "ok"},
+		storage.MutationAuditEntry{
+			RequestID:    "req-c-" + suffix,
+			OrgID:        uuid.Nil,
+			ActorAgentID: agentID,
+			ActorRole:    "agent",
+			HTTPMethod:   "POST",
+			Endpoint:     "/v1/runs/" + run.ID.String() + "/complete",
+			Operation:    "complete",
+			ResourceType: "run",
+			ResourceID:   run.ID.String(),
+		})
 	require.NoError(t, err)
 
-	// Second completion of already-completed run should be idempotent.
-	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, nil, audit)
-	require.NoError(t, err, "completing an already-completed run should be idempotent")
+	got, err := testDB.GetRun(ctx, uuid.Nil, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RunStatusCompleted, got.Status)
 }
 
-func TestCompleteRunWithAudit_NilMetadata(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: Pool utilities (Ping, Close, IsDuplicateKey)
+// ---------------------------------------------------------------------------
+
+func TestPing(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "crwa-nil-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	err := testDB.Ping(ctx)
 	require.NoError(t, err)
+}
 
-	audit := storage.MutationAuditEntry{
-		Operation:    "complete_run",
-		ActorRole:    "agent",
-		ActorAgentID: agentID,
-		OrgID:        run.OrgID,
-	}
-	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusFailed, nil, audit)
-	require.NoError(t, err, "nil metadata should be coerced to empty map")
-
-	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
-	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusFailed, got.Status)
+func TestIsDuplicateKey(t *testing.T) {
+	// IsDuplicateKey should return false for non-PG errors.
+	assert.False(t, testDB.IsDuplicateKey(fmt.Errorf("some random error")))
+	assert.False(t, testDB.IsDuplicateKey(nil))
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetRun (57.1% -> cover not-found branch)
+// Tests: NewPgCandidateFinder
 // ---------------------------------------------------------------------------
 
-func TestGetRun_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.GetRun(ctx, uuid.Nil, uuid.New())
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+func TestNewPgCandidateFinder(t *testing.T) {
+	finder := storage.NewPgCandidateFinder(testDB)
+	assert.NotNil(t, finder)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetAgentByAgentID and GetAgentByID (57.1% -> cover not-found branches)
+// Tests: BatchDeleteDecisions (empty batch)
 // ---------------------------------------------------------------------------
 
-func TestGetAgentByAgentID_NotFound(t *testing.T) {
+func TestBatchDeleteDecisions_NothingToDelete(t *testing.T) {
 	ctx := context.Background()
-	_, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, "nonexistent-agent-"+uuid.New().String()[:8])
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
-}
 
-func TestGetAgentByID_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.GetAgentByID(ctx, uuid.New(), uuid.Nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	// Use a far-past cutoff so nothing qualifies for deletion.
+	farPast := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	nonexistentType := "nonexistent_type_xyz"
+	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, farPast, &nonexistentType, nil, nil, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Decisions)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetAgentWinRates (53.3% -> cover the happy path and empty-agents path)
+// Tests: InsertEventsIdempotent (10% -> targeted coverage)
 // ---------------------------------------------------------------------------
 
-func TestGetAgentWinRates_EmptyAgents(t *testing.T) {
+// NOTE: TestInsertEventsIdempotent_NewEvents, _DuplicatesSafe, and _PartialDuplicate
+// are covered by TestInsertEventsIdempotent, _WithDuplicates, and _SingleEvent.
+// The ON CONFLICT (id, occurred_at) clause now matches the composite PK.
+
+func TestInsertEventsIdempotent_EmptySlice(t *testing.T) {
 	ctx := context.Background()
-	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, []string{}, "architecture")
+
+	count, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{})
 	require.NoError(t, err)
-	assert.Empty(t, result, "empty agentIDs should return empty map")
+	assert.Equal(t, int64(0), count, "empty slice should return 0 without error")
 }
 
-func TestGetAgentWinRates_WithResolvedConflicts(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: GetResolvedConflictsByType (0% -> full coverage)
+// ---------------------------------------------------------------------------
+
+func TestGetResolvedConflictsByType(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentA := "winrate-a-" + suffix
-	agentB := "winrate-b-" + suffix
-	decisionType := "winrate_type_" + suffix
+
+	agentA := "resolved-a-" + suffix
+	agentB := "resolved-b-" + suffix
+	decisionType := "resolved_type_" + suffix
 
 	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
@@ -6893,616 +7108,890 @@ func TestGetAgentWinRates_WithResolvedConflicts(t *testing.T) {
 
 	dA, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decisionType, Outcome: "approach_X",
-		Confidence: 0.9, Metadata: map[string]any{},
+		DecisionType: decisionType, Outcome: "approach_alpha",
+		Confidence: 0.85, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
+
 	dB, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decisionType, Outcome: "approach_Y",
-		Confidence: 0.8, Metadata: map[string]any{},
+		DecisionType: decisionType, Outcome: "approach_beta",
+		Confidence: 0.90, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.95
-	outcomeDiv := 0.85
-	sig := 0.8
+	// Insert a conflict.
+	topicSim := 0.92
+	outcomeDiv := 0.88
+	sig := topicSim * outcomeDiv
 	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: dA.OrgID, ConflictKind: "cross_agent",
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
-		OutcomeA: "approach_X", OutcomeB: "approach_Y",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "embedding",
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          "approach_alpha",
+		OutcomeB:          "approach_beta",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
 	})
 	require.NoError(t, err)
 
-	// Resolve the conflict with agentA as winner.
-	resNote := "agentA wins"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, dA.OrgID,
-		"resolved", "test", &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			OrgID: dA.OrgID, ActorAgentID: "test", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
+	// Resolve the conflict with dA as winner.
+	resNote := "alpha approach was better"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "test-resolver", &resNote, &dA.ID, nil, storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-resolver",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/" + conflictID.String(),
+			Operation:    "resolve_conflict",
+			ResourceType: "conflict",
+			ResourceID:   conflictID.String(),
 		})
 	require.NoError(t, err)
 
-	result, err := testDB.GetAgentWinRates(ctx, dA.OrgID, []string{agentA, agentB}, decisionType)
+	// Query resolved conflicts by type.
+	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, decisionType, 10)
 	require.NoError(t, err)
-	// Both agents should appear. Exactly one should have a win (the winner).
-	// Due to UUID-based normalization in InsertScoredConflict, we check aggregate counts
-	// rather than assuming which agent maps to which side.
-	totalWins := 0
-	for _, r := range result {
-		totalWins += r.WinCount
-		assert.Equal(t, 1, r.Total, "each agent should appear in exactly 1 resolved conflict")
+	require.NotEmpty(t, results, "should find at least one resolved conflict")
+
+	found := false
+	for _, r := range results {
+		if r.ID == conflictID {
+			found = true
+			assert.Equal(t, decisionType, r.DecisionType)
+			assert.Equal(t, dA.ID, r.WinningDecisionID)
+			assert.Equal(t, "approach_alpha", r.WinningOutcome)
+			assert.Equal(t, "approach_beta", r.LosingOutcome)
+			assert.NotZero(t, r.ResolvedAt)
+			break
+		}
 	}
-	assert.Equal(t, 1, totalWins, "exactly one agent should have a win")
+	assert.True(t, found, "resolved conflict should appear in results")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionEmbeddings (57.1% -> cover empty input and happy path)
-// ---------------------------------------------------------------------------
+func TestGetResolvedConflictsByType_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
 
-func TestGetDecisionEmbeddings_EmptyIDs(t *testing.T) {
+	// With limit 0, the function should default to 5 (not error).
+	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, "nonexistent_type", 0)
+	require.NoError(t, err)
+	assert.Empty(t, results, "nonexistent type should return empty")
+}
+
+func TestGetResolvedConflictsByType_NoResults(t *testing.T) {
 	ctx := context.Background()
-	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{}, uuid.Nil)
+
+	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.New(), "anything", 5)
 	require.NoError(t, err)
-	assert.Empty(t, result)
+	assert.Empty(t, results)
 }
 
-func TestGetDecisionEmbeddings_WithEmbeddings(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: ResolveConflictGroup (0% -> full coverage)
+// ---------------------------------------------------------------------------
+
+func TestResolveConflictGroup_WithWinner(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "emb-get-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentA := "grp-a-" + suffix
+	agentB := "grp-b-" + suffix
+	decisionType := "grp_type_" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	embSlice := make([]float32, 1024)
-	outcomeEmbSlice := make([]float32, 1024)
-	for i := range embSlice {
-		embSlice[i] = float32(i) / 1024.0
-		outcomeEmbSlice[i] = float32(1024-i) / 1024.0
-	}
-	emb := pgvector.NewVector(embSlice)
-	outcomeEmb := pgvector.NewVector(outcomeEmbSlice)
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decisionType, Outcome: "opt_x",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "emb_test", Outcome: "some outcome",
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decisionType, Outcome: "opt_y",
 		Confidence: 0.9, Metadata: map[string]any{},
-		Embedding:        &emb,
-		OutcomeEmbedding: &outcomeEmb,
 	})
 	require.NoError(t, err)
 
-	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{d.ID}, d.OrgID)
+	topicSim := 0.90
+	outcomeDiv := 0.80
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          "opt_x",
+		OutcomeB:          "opt_y",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
+	})
 	require.NoError(t, err)
-	assert.Len(t, result, 1, "should return 1 decision with both embeddings")
-	pair, ok := result[d.ID]
-	assert.True(t, ok)
-	assert.Equal(t, 1024, len(pair[0].Slice()))
-	assert.Equal(t, 1024, len(pair[1].Slice()))
-}
 
-// ---------------------------------------------------------------------------
-// Tests: ListAgents edge cases (63.6% -> cover limit clamping)
-// ---------------------------------------------------------------------------
+	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, conflict)
+	require.NotNil(t, conflict.GroupID, "conflict should have a group_id")
 
-func TestListAgents_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
-	// With limit=0, should default to 200. Just verify it doesn't error.
-	agents, err := testDB.ListAgents(ctx, uuid.Nil, 0, 0)
+	resNote := "agent B had higher confidence"
+	affected, err := testDB.ResolveConflictGroup(ctx,
+		*conflict.GroupID, uuid.Nil,
+		"resolved", "test-admin", &resNote, &agentB, nil,
+		storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-admin",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/groups/" + conflict.GroupID.String(),
+			Operation:    "resolve_conflict_group",
+			ResourceType: "conflict_group",
+			ResourceID:   conflict.GroupID.String(),
+		})
 	require.NoError(t, err)
-	_ = agents // may be empty or have agents from other tests; just verifying no error
-}
+	assert.Equal(t, 1, affected, "should resolve 1 conflict in the group")
 
-func TestListAgents_LargeLimit(t *testing.T) {
-	ctx := context.Background()
-	// With limit=5000, should be clamped to 1000. Just verify it doesn't error.
-	agents, err := testDB.ListAgents(ctx, uuid.Nil, 5000, 0)
+	resolvedStatus := "resolved"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		Status: &resolvedStatus,
+	}, 100, 0)
 	require.NoError(t, err)
-	_ = agents
+
+	found := false
+	for _, c := range conflicts {
+		if c.ID == conflictID {
+			found = true
+			assert.Equal(t, "resolved", c.Status)
+			break
+		}
+	}
+	assert.True(t, found, "conflict should be resolved after group resolution")
 }
 
-func TestListAgents_NegativeOffset(t *testing.T) {
+func TestResolveConflictGroup_FalsePositive(t *testing.T) {
 	ctx := context.Background()
-	agents, err := testDB.ListAgents(ctx, uuid.Nil, 10, -5)
-	require.NoError(t, err)
-	_ = agents
-}
+	suffix := uuid.New().String()[:8]
 
-// ---------------------------------------------------------------------------
-// Tests: FindRetriableClaimFailures (53.8% -> default limit, empty results)
-// ---------------------------------------------------------------------------
+	agentA := "grpwf-a-" + suffix
+	agentB := "grpwf-b-" + suffix
+	decisionType := "grpwf_type_" + suffix
 
-func TestFindRetriableClaimFailures_NoFailures(t *testing.T) {
-	ctx := context.Background()
-	// With no decisions having failed claims, should return empty.
-	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 10)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-	assert.Empty(t, refs)
-}
-
-func TestFindRetriableClaimFailures_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
-	// With limit=0, should default to 50. Just verify no error.
-	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 0)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
-	assert.Empty(t, refs)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: GetAPIKeyByID (57.1% -> not-found branch)
-// ---------------------------------------------------------------------------
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decisionType, Outcome: "alpha",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-func TestGetAPIKeyByID_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.GetAPIKeyByID(ctx, uuid.New(), uuid.Nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
-}
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decisionType, Outcome: "beta",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-// ---------------------------------------------------------------------------
-// Tests: ListAPIKeys edge cases (64.7%)
-// ---------------------------------------------------------------------------
-
-func TestListAPIKeys_Empty(t *testing.T) {
-	ctx := context.Background()
-	// Use a random org that has no keys.
-	keys, _, err := testDB.ListAPIKeys(ctx, uuid.New(), 10, 0)
-	require.NoError(t, err)
-	assert.Empty(t, keys)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: ReviseDecision (64.3% -> cover not-found and successful revision)
-// ---------------------------------------------------------------------------
-
-func TestReviseDecision_Success(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "revise-ok-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	topicSim := 0.85
+	outcomeDiv := 0.75
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          "alpha",
+		OutcomeB:          "beta",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
+	})
 	require.NoError(t, err)
 
-	original, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "revise_test", Outcome: "original approach",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
+	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
+	require.NotNil(t, conflict.GroupID)
 
-	audit := storage.MutationAuditEntry{
-		OrgID: original.OrgID, ActorAgentID: agentID, ActorRole: "agent",
-		Operation: "revise_decision", ResourceType: "decision",
-	}
-	revised, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID, OrgID: original.OrgID,
-		DecisionType: "revise_test", Outcome: "revised approach",
-		Confidence: 0.9,
-	}, &audit)
+	resNote := "not worth resolving"
+	fpLabel := "unrelated_false_positive"
+	affected, err := testDB.ResolveConflictGroup(ctx,
+		*conflict.GroupID, uuid.Nil,
+		"false_positive", "test-admin", &resNote, nil, &fpLabel,
+		storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-admin",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/groups/" + conflict.GroupID.String(),
+			Operation:    "resolve_conflict_group",
+			ResourceType: "conflict_group",
+			ResourceID:   conflict.GroupID.String(),
+		})
 	require.NoError(t, err)
-	assert.Equal(t, "revised approach", revised.Outcome)
-	assert.NotEqual(t, original.ID, revised.ID, "revised decision should have a new ID")
-	assert.Equal(t, &original.ID, revised.SupersedesID)
+	assert.Equal(t, 1, affected)
 }
 
-func TestReviseDecision_NotFound(t *testing.T) {
+func TestResolveConflictGroup_NotFound(t *testing.T) {
 	ctx := context.Background()
-	_, err := testDB.ReviseDecision(ctx, uuid.New(), model.Decision{
-		OrgID: uuid.Nil, AgentID: "ghost", DecisionType: "test",
-		Outcome: "anything", Confidence: 0.5,
-	}, nil)
+
+	_, err := testDB.ResolveConflictGroup(ctx,
+		uuid.New(), uuid.Nil,
+		"resolved", "test-admin", nil, nil, nil,
+		storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-admin",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/groups/nonexistent",
+			Operation:    "resolve_conflict_group",
+			ResourceType: "conflict_group",
+		})
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CountConflicts with filters to exercise conflictWhere branches
+// Tests: CascadeResolveByOutcome
 // ---------------------------------------------------------------------------
 
-func TestCountConflicts_AllFilters(t *testing.T) {
+func TestCascadeResolveByOutcome(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	decisionType := "filter_test"
-	agentIDFilter := "filter-agent"
-	conflictKind := "cross_agent"
-	status := "open"
-	severity := "high"
-	category := "factual"
-	decisionIDFilter := uuid.New()
+	agentA := "cascade-a-" + suffix
+	agentB := "cascade-b-" + suffix
+	decisionType := "cascade_type_" + suffix
 
-	// Count with every filter active — should succeed (return 0) without SQL errors.
-	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		DecisionType: &decisionType,
-		AgentID:      &agentIDFilter,
-		ConflictKind: &conflictKind,
-		Status:       &status,
-		Severity:     &severity,
-		Category:     &category,
-		DecisionID:   &decisionIDFilter,
-	})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
-	assert.Equal(t, 0, count)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: ListConflicts with filters to exercise more conflictWhere branches
-// ---------------------------------------------------------------------------
+	// Create a "winning" embedding direction and an opposing direction.
+	// 1024-dim vectors: winning points in +x, loser points in -x.
+	winEmb := make([]float32, 1024)
+	winEmb[0] = 1.0
+	loseEmb := make([]float32, 1024)
+	loseEmb[0] = -1.0
+	// An aligned embedding (slight perturbation of winner).
+	alignedEmb := make([]float32, 1024)
+	alignedEmb[0] = 0.95
+	alignedEmb[1] = 0.05
+	// An unrelated embedding (orthogonal).
+	unrelatedEmb := make([]float32, 1024)
+	unrelatedEmb[2] = 1.0
 
-func TestListConflicts_WithSeverityFilter(t *testing.T) {
-	ctx := context.Background()
-	severity := "critical"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		Severity: &severity,
-	}, 10, 0)
+	// Decision A1 (winner) — outcome aligns with winEmb.
+	dA1, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType:     decisionType,
+		Outcome:          "use Redis for caching",
+		Confidence:       0.9,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(winEmb),
+	})
 	require.NoError(t, err)
-	_ = conflicts // may be empty; just verifying the query with severity filter doesn't error
-}
 
-func TestListConflicts_WithCategoryFilter(t *testing.T) {
-	ctx := context.Background()
-	category := "temporal"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		Category: &category,
-	}, 10, 0)
+	// Decision B1 (loser) — outcome opposes winner.
+	dB1, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType:     decisionType,
+		Outcome:          "use Memcached for caching",
+		Confidence:       0.8,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(loseEmb),
+	})
 	require.NoError(t, err)
-	_ = conflicts
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignals (66.7% -> cover all three query paths)
-// ---------------------------------------------------------------------------
-
-func TestGetDecisionOutcomeSignals_NoSignals(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "signals-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// Decision A2 (aligned with winner).
+	dA2, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType:     decisionType,
+		Outcome:          "Redis cluster for sessions",
+		Confidence:       0.85,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(alignedEmb),
+	})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signal_test", Outcome: "standalone decision",
-		Confidence: 0.8, Metadata: map[string]any{},
+	// Decision B2 (opposes winner).
+	dB2, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType:     decisionType,
+		Outcome:          "Memcached with mcrouter",
+		Confidence:       0.75,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(loseEmb),
 	})
 	require.NoError(t, err)
 
-	signals, err := testDB.GetDecisionOutcomeSignals(ctx, d.ID, d.OrgID)
+	// Decision A3 and B3: unrelated topic (orthogonal embeddings).
+	dA3, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType:     decisionType,
+		Outcome:          "unrelated topic A",
+		Confidence:       0.7,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(unrelatedEmb),
+	})
 	require.NoError(t, err)
-	assert.Nil(t, signals.SupersessionVelocityHours, "un-superseded decision should have nil velocity")
-	assert.Equal(t, 0, signals.PrecedentCitationCount)
-	assert.Equal(t, 0, signals.ConflictFate.Won)
-	assert.Equal(t, 0, signals.ConflictFate.Lost)
-	assert.Equal(t, 0, signals.ConflictFate.ResolvedNoWinner)
-}
 
-func TestGetDecisionOutcomeSignals_WithSupersession(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "signals-sup-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	dB3, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType:     decisionType,
+		Outcome:          "unrelated topic B",
+		Confidence:       0.7,
+		Metadata:         map[string]any{},
+		OutcomeEmbedding: ptrVec(unrelatedEmb),
+	})
 	require.NoError(t, err)
 
-	original, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signal_test", Outcome: "first approach",
-		Confidence: 0.7, Metadata: map[string]any{},
+	// Insert 3 conflicts in the same group (same agents, same decision_type).
+	topicSim := 0.90
+	outcomeDiv := 0.80
+	sig := topicSim * outcomeDiv
+
+	conflict1ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
+		DecisionAID: dA1.ID, DecisionBID: dB1.ID,
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "use Redis", OutcomeB: "use Memcached",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
+		ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	// Revise the decision so SupersessionVelocityHours is populated.
-	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID, OrgID: original.OrgID,
-		DecisionType: "signal_test", Outcome: "revised approach",
-		Confidence: 0.9,
-	}, nil)
+	conflict2ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
+		DecisionAID: dA2.ID, DecisionBID: dB2.ID,
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "Redis cluster", OutcomeB: "Memcached mcrouter",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
+		ScoringMethod: "text",
+	})
 	require.NoError(t, err)
 
-	signals, err := testDB.GetDecisionOutcomeSignals(ctx, original.ID, original.OrgID)
+	conflict3ID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
+		DecisionAID: dA3.ID, DecisionBID: dB3.ID,
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "unrelated A", OutcomeB: "unrelated B",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
+		ScoringMethod: "text",
+	})
 	require.NoError(t, err)
-	assert.NotNil(t, signals.SupersessionVelocityHours, "superseded decision should have velocity")
-}
 
-// ---------------------------------------------------------------------------
-// Tests: GetRetentionPolicy (66.7% -> cover policy lookup)
-// ---------------------------------------------------------------------------
+	// Verify all 3 conflicts share a group.
+	c1, err := testDB.GetConflict(ctx, conflict1ID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, c1.GroupID)
+	groupID := *c1.GroupID
 
-func TestGetRetentionPolicy_DefaultOrg(t *testing.T) {
-	ctx := context.Background()
-	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, time.Hour)
+	c2, err := testDB.GetConflict(ctx, conflict2ID, uuid.Nil)
 	require.NoError(t, err)
-	// Default org likely has no retention policy set.
-	assert.Nil(t, policy.RetentionDays, "default org should have nil retention days unless explicitly set")
-}
+	require.NotNil(t, c2.GroupID)
+	assert.Equal(t, groupID, *c2.GroupID, "all conflicts should share a group")
 
-// ---------------------------------------------------------------------------
-// Tests: DeleteAgentData (67.3% -> not-found path)
-// ---------------------------------------------------------------------------
+	c3, err := testDB.GetConflict(ctx, conflict3ID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, c3.GroupID)
+	assert.Equal(t, groupID, *c3.GroupID, "all conflicts should share a group")
 
-func TestDeleteAgentData_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.DeleteAgentData(ctx, uuid.Nil, "nonexistent-agent-"+uuid.New().String()[:8], nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	// Now cascade from conflict1 with dA1 as winner (winEmb direction).
+	cascaded, err := testDB.CascadeResolveByOutcome(ctx,
+		uuid.Nil, groupID, dA1.ID, conflict1ID,
+		0.80, // threshold
+		storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-admin",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/" + conflict1ID.String(),
+			Operation:    "conflict_cascade_resolved",
+			ResourceType: "conflict",
+			ResourceID:   conflict1ID.String(),
+		})
+	require.NoError(t, err)
+
+	// Conflict2 should be cascade-resolved: dA2 has alignedEmb which is similar
+	// to winEmb (cosine sim ~0.998), while dB2 has loseEmb (cosine sim = -1.0).
+	// So dA2 should win.
+	assert.Equal(t, 1, cascaded, "only conflict2 should cascade (conflict3 has unrelated embeddings)")
+
+	// Verify conflict2 was resolved with dA2 as winner.
+	c2After, err := testDB.GetConflict(ctx, conflict2ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", c2After.Status)
+	assert.Equal(t, "cascade", *c2After.ResolvedBy)
+	require.NotNil(t, c2After.WinningDecisionID)
+	assert.Equal(t, dA2.ID, *c2After.WinningDecisionID, "aligned side (A) should win")
+	require.NotNil(t, c2After.ResolutionNote)
+	assert.Contains(t, *c2After.ResolutionNote, "cascade")
+
+	// Verify conflict3 is still open (unrelated embeddings, no side exceeds 0.80).
+	c3After, err := testDB.GetConflict(ctx, conflict3ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, "open", c3After.Status, "conflict3 should remain open (unrelated topic)")
+
+	// Suppress unused variable warnings.
+	_ = dB1
+	_ = dB3
+	_ = conflict3ID
 }
 
-func TestDeleteAgentData_Success(t *testing.T) {
+func TestCascadeResolveByOutcome_NoEmbeddings(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "delete-me-" + suffix
 
-	// Create the agent record explicitly.
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	agentA := "casc-noemb-a-" + suffix
+	agentB := "casc-noemb-b-" + suffix
+	decisionType := "casc_noemb_" + suffix
 
-	// Create a run and decision for the agent.
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "delete_test", Outcome: "to be deleted",
-		Confidence: 0.5, Metadata: map[string]any{},
+	// Decisions without outcome embeddings.
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decisionType, Outcome: "opt_x",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	audit := storage.MutationAuditEntry{
-		OrgID: run.OrgID, ActorAgentID: "admin", ActorRole: "admin",
-		Operation: "delete_agent", ResourceType: "agent",
-	}
-	result, err := testDB.DeleteAgentData(ctx, run.OrgID, agentID, &audit)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decisionType, Outcome: "opt_y",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, result.Decisions, int64(1), "should delete at least 1 decision")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: BackfillEmbedding (69.2% -> cover not-found and basic success)
-// ---------------------------------------------------------------------------
 
-func TestBackfillEmbedding_NoMatch(t *testing.T) {
-	ctx := context.Background()
-	emb := pgvector.NewVector(make([]float32, 1024))
-	// BackfillEmbedding returns nil when no rows match (decision revised/deleted/missing).
-	err := testDB.BackfillEmbedding(ctx, uuid.New(), uuid.Nil, emb)
-	require.NoError(t, err, "missing decision should be silently skipped")
-}
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, OrgID: uuid.Nil,
+		DecisionAID: dA.ID, DecisionBID: dB.ID,
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "opt_x", OutcomeB: "opt_y",
+		ScoringMethod: "text",
+	})
+	require.NoError(t, err)
 
-// ---------------------------------------------------------------------------
-// Tests: CompleteIdempotency (66.7%)
-// ---------------------------------------------------------------------------
+	c, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, c.GroupID)
 
-func TestCompleteIdempotency_NotFound(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.CompleteIdempotency(ctx, uuid.Nil, "ghost-agent", "/v1/trace", uuid.New().String(), 200, map[string]any{"ok": true})
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	// Cascade should resolve 0 — no embeddings to compare.
+	cascaded, err := testDB.CascadeResolveByOutcome(ctx,
+		uuid.Nil, *c.GroupID, dA.ID, uuid.New(), // trigger is a different ID
+		0.80,
+		storage.MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        uuid.Nil,
+			ActorAgentID: "test-admin",
+			ActorRole:    "admin",
+			HTTPMethod:   "PATCH",
+			Endpoint:     "/v1/conflicts/test",
+			Operation:    "conflict_cascade_resolved",
+			ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cascaded, "no cascade without embeddings")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: RevokeAPIKeyWithAudit (66.7%)
-// ---------------------------------------------------------------------------
-
-func TestRevokeAPIKeyWithAudit_NotFound(t *testing.T) {
-	ctx := context.Background()
-	audit := storage.MutationAuditEntry{
-		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
-		Operation: "revoke_api_key", ResourceType: "api_key",
-	}
-	err := testDB.RevokeAPIKeyWithAudit(ctx, uuid.New(), uuid.Nil, audit)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+// ptrVec returns a pointer to a pgvector.Vector from a float32 slice.
+func ptrVec(v []float32) *pgvector.Vector {
+	vec := pgvector.NewVector(v)
+	return &vec
 }
 
 // ---------------------------------------------------------------------------
-// Tests: UpdateAgentTagsWithAudit (63.2% -> cover success and not-found)
+// Tests: CreateTraceAndAdjudicateConflictTx (0% -> full coverage)
 // ---------------------------------------------------------------------------
 
-func TestUpdateAgentTagsWithAudit_Success(t *testing.T) {
+func TestCreateTraceAndAdjudicateConflictTx(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "tags-ok-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID,
-		Role: model.RoleAgent, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	agentA := "adjud-a-" + suffix
+	agentB := "adjud-b-" + suffix
+	decisionType := "adjud_type_" + suffix
 
-	audit := storage.MutationAuditEntry{
-		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
-		Operation: "update_tags", ResourceType: "agent",
-	}
-	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, agentID, []string{"env:prod", "team:infra"}, audit)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
-	assert.Equal(t, []string{"env:prod", "team:infra"}, updated.Tags)
-}
-
-func TestUpdateAgentTagsWithAudit_NotFound(t *testing.T) {
-	ctx := context.Background()
-	audit := storage.MutationAuditEntry{
-		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
-		Operation: "update_tags", ResourceType: "agent",
-	}
-	_, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8], []string{"a"}, audit)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
-}
-
-func TestUpdateAgentTagsWithAudit_NilTags(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "tags-nil-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID,
-		Role: model.RoleAgent, Metadata: map[string]any{},
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decisionType, Outcome: "left",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	audit := storage.MutationAuditEntry{
-		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
-		Operation: "update_tags", ResourceType: "agent",
-	}
-	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, agentID, nil, audit)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decisionType, Outcome: "right",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Equal(t, []string{}, updated.Tags, "nil tags should be coerced to empty slice")
-}
 
-// ---------------------------------------------------------------------------
-// Tests: ListAPIKeys edge cases (64.7%)
-// ---------------------------------------------------------------------------
+	topicSim := 0.92
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          "left",
+		OutcomeB:          "right",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
+	})
+	require.NoError(t, err)
 
-func TestListAPIKeys_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
-	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 0, 0)
+	adjudicatorAgent := "adjudicator-" + suffix
+	resNote := "chose left because of policy alignment"
+	run, decision, err := testDB.CreateTraceAndAdjudicateConflictTx(ctx,
+		storage.CreateTraceParams{
+			AgentID:  adjudicatorAgent,
+			OrgID:    uuid.Nil,
+			Metadata: map[string]any{"purpose": "adjudication"},
+			Decision: model.Decision{
+				DecisionType: "adjudication",
+				Outcome:      "selected left approach",
+				Confidence:   0.95,
+			},
+			AuditEntry: &storage.MutationAuditEntry{
+				RequestID:    uuid.New().String(),
+				OrgID:        uuid.Nil,
+				ActorAgentID: adjudicatorAgent,
+				ActorRole:    "admin",
+				HTTPMethod:   "POST",
+				Endpoint:     "/v1/trace",
+				Operation:    "create_trace",
+				ResourceType: "decision",
+			},
+		},
+		storage.AdjudicateConflictInTraceParams{
+			ConflictID:        conflictID,
+			ResolvedBy:        adjudicatorAgent,
+			ResNote:           &resNote,
+			WinningDecisionID: &dA.ID,
+			Audit: storage.MutationAuditEntry{
+				RequestID:    uuid.New().String(),
+				OrgID:        uuid.Nil,
+				ActorAgentID: adjudicatorAgent,
+				ActorRole:    "admin",
+				HTTPMethod:   "POST",
+				Endpoint:     "/v1/trace",
+				Operation:    "adjudicate_conflict",
+				ResourceType: "conflict",
+			},
+		},
+	)
 	require.NoError(t, err)
-	_ = keys // just verify default limit=50 doesn't error
-}
 
-func TestListAPIKeys_NegativeOffset(t *testing.T) {
-	ctx := context.Background()
-	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 10, -5)
+	assert.Equal(t, adjudicatorAgent, run.AgentID)
+	assert.Equal(t, model.RunStatusCompleted, run.Status)
+	assert.Equal(t, "selected left approach", decision.Outcome)
+
+	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	_ = keys
+	require.NotNil(t, conflict)
+	assert.Equal(t, "resolved", conflict.Status)
+	require.NotNil(t, conflict.WinningDecisionID)
+	assert.Equal(t, dA.ID, *conflict.WinningDecisionID)
 }
 
-func TestListAPIKeys_LargeLimit(t *testing.T) {
+func TestCreateTraceAndAdjudicateConflictTx_ConflictNotFound(t *testing.T) {
 	ctx := context.Background()
-	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 5000, 0)
-	require.NoError(t, err)
-	_ = keys
+	suffix := uuid.New().String()[:8]
+
+	_, _, err := testDB.CreateTraceAndAdjudicateConflictTx(ctx,
+		storage.CreateTraceParams{
+			AgentID:  "adjud-notfound-" + suffix,
+			OrgID:    uuid.Nil,
+			Metadata: map[string]any{},
+			Decision: model.Decision{
+				DecisionType: "adjudication",
+				Outcome:      "test",
+				Confidence:   0.5,
+			},
+		},
+		storage.AdjudicateConflictInTraceParams{
+			ConflictID: uuid.New(),
+			ResolvedBy: "test",
+			Audit: storage.MutationAuditEntry{
+				RequestID:    uuid.New().String(),
+				OrgID:        uuid.Nil,
+				ActorAgentID: "test",
+				ActorRole:    "admin",
+				HTTPMethod:   "POST",
+				Endpoint:     "/v1/trace",
+				Operation:    "adjudicate_conflict",
+				ResourceType: "conflict",
+			},
+		},
+	)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: SearchDecisionsByText (70%)
+// Tests: FindSimilar (0% -> full coverage)
 // ---------------------------------------------------------------------------
 
-func TestSearchDecisionsByText_NoMatches(t *testing.T) {
+func TestFindSimilar_WithEmbeddings(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "xyzzy_nonexistent_"+uuid.New().String()[:8], model.QueryFilters{}, 10)
+	suffix := uuid.New().String()[:8]
+	agentID := "similar-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Empty(t, results)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignals additional branch (precedent citation)
-// ---------------------------------------------------------------------------
+	dims := 1024
 
-func TestGetDecisionOutcomeSignals_WithPrecedentCitation(t *testing.T) {
+	var decisionIDs []uuid.UUID
+	for i := range 3 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: "similar_test",
+			Outcome:      fmt.Sprintf("outcome_%d", i),
+			Confidence:   float32(i+1) * 0.3,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+		decisionIDs = append(decisionIDs, d.ID)
+
+		vec := make([]float32, dims)
+		for j := range vec {
+			vec[j] = float32(i+1) * float32(j) / float32(dims)
+		}
+		emb := pgvector.NewVector(vec)
+		err = testDB.BackfillEmbedding(ctx, d.ID, d.OrgID, emb, "")
+		require.NoError(t, err)
+
+		outcomeVec := make([]float32, dims)
+		for j := range outcomeVec {
+			outcomeVec[j] = float32(i+1) * float32(j+1) / float32(dims*2)
+		}
+		outcomeEmb := pgvector.NewVector(outcomeVec)
+		err = testDB.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, outcomeEmb)
+		require.NoError(t, err)
+	}
+
+	queryVec := make([]float32, dims)
+	for j := range queryVec {
+		queryVec[j] = float32(j) / float32(dims)
+	}
+
+	finder := storage.NewPgCandidateFinder(testDB)
+	results, err := finder.FindSimilar(ctx, uuid.Nil, queryVec, decisionIDs[0], nil, 10)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, len(results), 2, "should find at least 2 similar decisions (excluding the query decision)")
+	for _, r := range results {
+		assert.NotEqual(t, decisionIDs[0], r.DecisionID, "excluded decision should not appear")
+
+	}
+}
+
+func TestFindSimilar_IsolatesEmbeddingModels(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "signals-cite-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentID := "embmodel-" + suffix
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "embmodel-"+suffix, "embmodel-"+suffix)
 	require.NoError(t, err)
 
-	original, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signal_cite_test", Outcome: "foundational decision",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID, OrgID: orgID})
 	require.NoError(t, err)
 
-	// Create two decisions citing the original as precedent.
-	for i := range 2 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "signal_cite_test", Outcome: fmt.Sprintf("follows precedent %d", i),
-			Confidence: 0.85, Metadata: map[string]any{},
-			PrecedentRef: &original.ID,
+	dims := 1024
+	vec := make([]float32, dims)
+	for j := range vec {
+		vec[j] = float32(j+1) / float32(dims)
+	}
+	emb := pgvector.NewVector(vec)
+
+	// Simulate a half-finished re-embedding job: two decisions on the old
+	// model, two on the new one, and one embedded before models were tracked.
+	create := func(embeddingModel string) uuid.UUID {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, OrgID: orgID, DecisionType: "embmodel_test",
+			Outcome: "outcome", Confidence: 0.8, Metadata: map[string]any{},
 		})
 		require.NoError(t, err)
+		require.NoError(t, testDB.BackfillEmbedding(ctx, d.ID, d.OrgID, emb, embeddingModel))
+		require.NoError(t, testDB.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, emb))
+		return d.ID
 	}
+	oldA, oldB := create("model-old"), create("model-old")
+	newA, newB := create("model-new"), create("model-new")
+	untagged := create("")
 
-	signals, err := testDB.GetDecisionOutcomeSignals(ctx, original.ID, original.OrgID)
+	finder := storage.NewPgCandidateFinder(testDB)
+	ids := func(results []search.Result) []uuid.UUID {
+		out := make([]uuid.UUID, len(results))
+		for i, r := range results {
+			out[i] = r.DecisionID
+		}
+		return out
+	}
+
+	results, err := finder.FindSimilar(ctx, orgID, vec, oldA, nil, 10)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, signals.PrecedentCitationCount, 2, "should count at least 2 citations")
-}
+	assert.ElementsMatch(t, []uuid.UUID{oldB, untagged}, ids(results), "old-model source must not see new-model vectors")
 
-// ---------------------------------------------------------------------------
-// Tests: NewConflictsSinceByOrg (71.4%)
-// ---------------------------------------------------------------------------
+	results, err = finder.FindSimilar(ctx, orgID, vec, newA, nil, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{newB, untagged}, ids(results), "new-model source must not see old-model vectors")
 
-func TestNewConflictsSinceByOrg_Empty(t *testing.T) {
-	ctx := context.Background()
-	// Far future cutoff should return 0 new conflicts.
-	counts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, time.Now().UTC().Add(24*time.Hour), 10)
+	results, err = finder.FindSimilar(ctx, orgID, vec, untagged, nil, 10)
 	require.NoError(t, err)
-	_ = counts
-}
+	assert.ElementsMatch(t, []uuid.UUID{oldA, oldB, newA, newB}, ids(results), "untagged source is compatible with every model")
 
-// ---------------------------------------------------------------------------
-// Tests: FindDecisionsMissingOutcomeEmbedding (76.9% -> default limit)
-// ---------------------------------------------------------------------------
+	got, err := testDB.GetDecision(ctx, orgID, newA, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	require.NotNil(t, got.EmbeddingModel)
+	assert.Equal(t, "model-new", *got.EmbeddingModel)
+}
 
-func TestFindDecisionsMissingOutcomeEmbedding_DefaultLimit(t *testing.T) {
+func TestFindSimilar_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 0)
+
+	finder := storage.NewPgCandidateFinder(testDB)
+	results, err := finder.FindSimilar(ctx, uuid.New(), []float32{0.1, 0.2, 0.3}, uuid.New(), nil, 0)
 	require.NoError(t, err)
-	_ = results
+	assert.Empty(t, results, "random org should return empty")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: TouchAPIKeyLastUsed (75%)
+// Tests: GetDecisionOutcomeSignalsBatch (41.7% -> full coverage)
 // ---------------------------------------------------------------------------
 
-func TestTouchAPIKeyLastUsed_NonExistent(t *testing.T) {
+func TestGetDecisionOutcomeSignalsBatch_EmptySlice(t *testing.T) {
 	ctx := context.Background()
-	// Touching a nonexistent key should not error (fire-and-forget pattern).
-	err := testDB.TouchAPIKeyLastUsed(ctx, uuid.Nil, uuid.New())
+
+	result, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{}, uuid.Nil)
 	require.NoError(t, err)
+	assert.Empty(t, result)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: InsertEvent (75%)
-// ---------------------------------------------------------------------------
-
-func TestInsertEvent_Success(t *testing.T) {
+func TestGetDecisionOutcomeSignalsBatch_WithSupersession(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "insert-event-" + suffix
+	agentID := "signals-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	event := model.AgentEvent{
-		ID:          uuid.New(),
-		RunID:       run.ID,
-		OrgID:       run.OrgID,
-		EventType:   model.EventDecisionMade,
-		SequenceNum: 1,
-		OccurredAt:  time.Now().UTC(),
-		AgentID:     agentID,
-		Payload:     map[string]any{"test": true},
-		CreatedAt:   time.Now().UTC(),
-	}
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "signals_test", Outcome: "v1",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	err = testDB.InsertEvent(ctx, event)
+	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "signals_test", Outcome: "v2",
+		Confidence: 0.9, Metadata: map[string]any{},
+	}, nil)
 	require.NoError(t, err)
 
-	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{original.ID}, uuid.Nil)
 	require.NoError(t, err)
-	assert.Len(t, got, 1)
+	require.Contains(t, signals, original.ID)
+	assert.NotNil(t, signals[original.ID].SupersessionVelocityHours,
+		"superseded decision should have supersession velocity")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignals with conflict fate data
-// ---------------------------------------------------------------------------
+func TestGetDecisionOutcomeSignalsBatch_WithPrecedentCitations(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "cite-signals-" + suffix
 
-func TestGetDecisionOutcomeSignals_WithConflictFate(t *testing.T) {
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	precedent, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "cite_test", Outcome: "precedent_outcome",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	for i := range 2 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "cite_test",
+			Outcome:      fmt.Sprintf("cites_precedent_%d", i),
+			Confidence:   0.8,
+			PrecedentRef: &precedent.ID,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx, []uuid.UUID{precedent.ID}, uuid.Nil)
+	require.NoError(t, err)
+	require.Contains(t, signals, precedent.ID)
+	assert.Equal(t, 2, signals[precedent.ID].PrecedentCitationCount,
+		"should count 2 citations of the precedent")
+}
+
+func TestGetDecisionOutcomeSignalsBatch_WithConflictFate(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+
 	agentA := "fate-a-" + suffix
 	agentB := "fate-b-" + suffix
+	decisionType := "fate_type_" + suffix
 
 	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
@@ -7511,2766 +8000,2646 @@ func TestGetDecisionOutcomeSignals_WithConflictFate(t *testing.T) {
 
 	dA, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: runA.ID, AgentID: agentA,
-		DecisionType: "fate_test", Outcome: "winner approach",
-		Confidence: 0.9, Metadata: map[string]any{},
+		DecisionType: decisionType, Outcome: "winner_approach",
+		Confidence: 0.85, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
+
 	dB, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: runB.ID, AgentID: agentB,
-		DecisionType: "fate_test", Outcome: "loser approach",
-		Confidence: 0.8, Metadata: map[string]any{},
+		DecisionType: decisionType, Outcome: "loser_approach",
+		Confidence: 0.75, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.9
-	outcomeDiv := 0.8
-	sig := 0.72
+	topicSim := 0.90
+	outcomeDiv := 0.80
+	sig := topicSim * outcomeDiv
 	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: dA.OrgID, ConflictKind: "cross_agent",
-		AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "fate_test", DecisionTypeB: "fate_test",
-		OutcomeA: "winner approach", OutcomeB: "loser approach",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "embedding",
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decisionType,
+		DecisionTypeB:     decisionType,
+		OutcomeA:          "winner_approach",
+		OutcomeB:          "loser_approach",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
 	})
 	require.NoError(t, err)
 
-	// Resolve with dA as winner.
-	resNote := "agentA wins"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, dA.OrgID,
-		"resolved", "test", &resNote, &dA.ID, nil,
+	resNote := "dA is better"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "tester", &resNote, &dA.ID, nil,
 		storage.MutationAuditEntry{
-			OrgID: dA.OrgID, ActorAgentID: "test", ActorRole: "admin",
+			RequestID: uuid.New().String(), OrgID: uuid.Nil,
+			ActorAgentID: "tester", ActorRole: "admin",
+			HTTPMethod: "PATCH", Endpoint: "/v1/conflicts/" + conflictID.String(),
 			Operation: "resolve_conflict", ResourceType: "conflict",
+			ResourceID: conflictID.String(),
 		})
 	require.NoError(t, err)
 
-	// Check dA signals — should show Won=1.
-	signalsA, err := testDB.GetDecisionOutcomeSignals(ctx, dA.ID, dA.OrgID)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, signalsA.ConflictFate.Won+signalsA.ConflictFate.Lost, 1,
-		"decision should appear in at least one resolved conflict")
-
-	// Check dB signals — should show Lost=1.
-	signalsB, err := testDB.GetDecisionOutcomeSignals(ctx, dB.ID, dB.OrgID)
+	signals, err := testDB.GetDecisionOutcomeSignalsBatch(ctx,
+		[]uuid.UUID{dA.ID, dB.ID}, uuid.Nil)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, signalsB.ConflictFate.Won+signalsB.ConflictFate.Lost, 1,
-		"decision should appear in at least one resolved conflict")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: RetractDecision (79.5% -> cover not-found)
-// ---------------------------------------------------------------------------
-
-func TestRetractDecision_NotFound(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.RetractDecision(ctx, uuid.Nil, uuid.New(), "test reason", "test-agent", nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: EraseDecision (76.2% -> cover not-found)
-// ---------------------------------------------------------------------------
 
-func TestEraseDecision_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.EraseDecision(ctx, uuid.Nil, uuid.New(), "test reason", "test-agent", nil)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.Equal(t, 1, signals[dA.ID].ConflictFate.Won, "dA should have 1 conflict win")
+	assert.Equal(t, 0, signals[dA.ID].ConflictFate.Lost, "dA should have 0 conflict losses")
+	assert.Equal(t, 0, signals[dB.ID].ConflictFate.Won, "dB should have 0 conflict wins")
+	assert.Equal(t, 1, signals[dB.ID].ConflictFate.Lost, "dB should have 1 conflict loss")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateAlternativesBatch (89.5% -> hit the nil metadata branch)
+// Tests: deleteBatch (0% -> tested via BatchDeleteDecisions with data)
 // ---------------------------------------------------------------------------
 
-func TestCreateAlternativesBatch_NilMetadata(t *testing.T) {
+func TestBatchDeleteDecisions_WithData(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "alt-nil-" + suffix
+	agentID := "delete-batch-" + suffix
+	decisionType := "delete_batch_type_" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	d, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "alt_test", Outcome: "test",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	pastTime := time.Now().UTC().Add(-48 * time.Hour)
+	for i := range 3 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID:        run.ID,
+			AgentID:      agentID,
+			DecisionType: decisionType,
+			Outcome:      fmt.Sprintf("delete_me_%d", i),
+			Confidence:   0.5,
+			ValidFrom:    pastTime.Add(time.Duration(i) * time.Minute),
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
 
-	alts := []model.Alternative{
-		{DecisionID: d.ID, Label: "alt A", Metadata: nil},
-		{DecisionID: d.ID, Label: "alt B", Metadata: map[string]any{"note": "best"}},
+		err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+			{DecisionID: d.ID, Label: "alt"},
+		})
+		require.NoError(t, err)
+
+		rel := float32(0.8)
+		_, err = testDB.CreateEvidence(ctx, model.Evidence{
+			DecisionID: d.ID, OrgID: d.OrgID,
+			SourceType: model.SourceDocument, Content: "evidence",
+			RelevanceScore: &rel,
+		})
+		require.NoError(t, err)
+
+		// Add an assessment per decision so the trigger bypass is exercised.
+		_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+			DecisionID:      d.ID,
+			AssessorAgentID: fmt.Sprintf("batch-reviewer-%d", i),
+			Outcome:         model.AssessmentCorrect,
+		})
+		require.NoError(t, err)
 	}
-	err = testDB.CreateAlternativesBatch(ctx, alts)
+
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &decisionType, nil, nil, 100)
 	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Decisions, "should delete 3 decisions")
+	assert.Equal(t, int64(3), result.Alternatives, "should delete 3 alternatives")
+	assert.Equal(t, int64(3), result.Evidence, "should delete 3 evidence rows")
+	assert.Equal(t, int64(3), result.Assessments, "should delete 3 assessments")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: FindUnembeddedDecisions (76.9% -> default limit)
-// ---------------------------------------------------------------------------
-
-func TestFindUnembeddedDecisions_DefaultLimit(t *testing.T) {
+func TestGetConflictCount(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.FindUnembeddedDecisions(ctx, 0)
+
+	count, err := testDB.GetConflictCount(ctx, uuid.New(), uuid.Nil)
 	require.NoError(t, err)
-	_ = results
+	assert.Equal(t, 0, count)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetConflictAnalytics (77.1%)
+// Tests: GetCitationPercentilesForOrg
 // ---------------------------------------------------------------------------
 
-func TestGetConflictAnalytics_Empty(t *testing.T) {
+func TestGetCitationPercentilesForOrg_NoCitations(t *testing.T) {
 	ctx := context.Background()
-	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.New(), storage.ConflictAnalyticsFilters{})
+
+	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.New())
 	require.NoError(t, err)
-	_ = analytics
+	assert.Nil(t, breakpoints, "org with no citations should return nil")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: QueryDecisions edge cases (78.8%)
-// ---------------------------------------------------------------------------
-
-func TestQueryDecisions_WithAgentFilter(t *testing.T) {
+func TestGetCitationPercentilesForOrg_WithCitations(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "qd-filter-" + suffix
+	agentID := "percentile-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
+	precedent, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "query_test", Outcome: "filtered",
-		Confidence: 0.8, Metadata: map[string]any{},
+		DecisionType: "percentile_test", Outcome: "precedent",
+		Confidence: 0.9, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	decisions, _, err := testDB.QueryDecisions(ctx, run.OrgID, model.QueryRequest{
-		Filters: model.QueryFilters{AgentIDs: []string{agentID}},
-		Limit:   10,
-	})
+	for i := range 5 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "percentile_test",
+			Outcome:      fmt.Sprintf("cites_%d", i),
+			Confidence:   0.7,
+			PrecedentRef: &precedent.ID,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
 	require.NoError(t, err)
-	assert.NotEmpty(t, decisions, "should find at least 1 decision for this agent")
+	assert.NotNil(t, breakpoints, "should return percentile breakpoints")
+	assert.Len(t, breakpoints, 4, "should return p25, p50, p75, p90")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: SetRetentionPolicy + GetRetentionPolicy round-trip
+// Tests: WithRetry + isRetriable — cover retry-on-serialization and exhaustion paths
 // ---------------------------------------------------------------------------
 
-func TestSetAndGetRetentionPolicy(t *testing.T) {
+func TestWithRetry_RetriableErrorSucceedsOnRetry(t *testing.T) {
 	ctx := context.Background()
-	days := 30
-	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"architecture"})
-	require.NoError(t, err)
-
-	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, 24*time.Hour)
-	require.NoError(t, err)
-	require.NotNil(t, policy.RetentionDays)
-	assert.Equal(t, 30, *policy.RetentionDays)
+	callCount := 0
 
-	// Clean up: reset to nil.
-	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
+	// Simulate a serialization failure (40001) that resolves on the 3rd attempt.
+	serialErr := &pgconn.PgError{Code: "40001"}
+	err := storage.WithRetry(ctx, 5, 1*time.Millisecond, func() error {
+		callCount++
+		if callCount < 3 {
+			return serialErr
+		}
+		return nil
+	})
 	require.NoError(t, err)
+	assert.Equal(t, 3, callCount, "should retry twice before succeeding on the 3rd attempt")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: CompleteRun — not-found and nil-metadata paths
-// ---------------------------------------------------------------------------
-
-func TestCompleteRun_NotFound(t *testing.T) {
+func TestWithRetry_RetriableErrorExhaustsRetries(t *testing.T) {
 	ctx := context.Background()
-	err := testDB.CompleteRun(ctx, uuid.Nil, uuid.New(), model.RunStatusCompleted, nil)
+	callCount := 0
+
+	deadlockErr := &pgconn.PgError{Code: "40P01"}
+	err := storage.WithRetry(ctx, 2, 1*time.Millisecond, func() error {
+		callCount++
+		return deadlockErr
+	})
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not found")
+	assert.Equal(t, 3, callCount, "should try once + 2 retries = 3 total calls")
+	assert.ErrorIs(t, err, deadlockErr)
 }
 
-func TestCompleteRun_NilMetadata(t *testing.T) {
-	ctx := context.Background()
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "complete-nilmeta-" + uuid.New().String()[:8]})
-	require.NoError(t, err)
-
-	err = testDB.CompleteRun(ctx, run.OrgID, run.ID, model.RunStatusFailed, nil)
-	require.NoError(t, err)
+func TestWithRetry_ContextCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	callCount := 0
 
-	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
-	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusFailed, got.Status)
-	assert.NotNil(t, got.CompletedAt)
+	serialErr := &pgconn.PgError{Code: "40001"}
+	err := storage.WithRetry(ctx, 10, 500*time.Millisecond, func() error {
+		callCount++
+		return serialErr
+	})
+	// Should be either context.DeadlineExceeded or the pgconn error, depending on timing.
+	require.Error(t, err)
+	// At least 1 call, but context should prevent many retries given the long backoff.
+	assert.GreaterOrEqual(t, callCount, 1)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListRunsByAgent — boundary parameters
+// Tests: CompleteRunWithAudit (48% -> cover success, not-found, idempotent paths)
 // ---------------------------------------------------------------------------
 
-func TestListRunsByAgent_ZeroLimit(t *testing.T) {
-	ctx := context.Background()
-	agentID := "runs-zero-" + uuid.New().String()[:8]
-	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 0, 0)
-	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, runs, 1)
-}
-
-func TestListRunsByAgent_LargeLimit(t *testing.T) {
+func TestCompleteRunWithAudit_Success(t *testing.T) {
 	ctx := context.Background()
-	agentID := "runs-large-" + uuid.New().String()[:8]
-	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	suffix := uuid.New().String()[:8]
+	agentID := "crwa-ok-" + suffix
 
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 5000, 0)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, runs, 1)
-}
+	assert.Equal(t, model.RunStatusRunning, run.Status)
 
-func TestListRunsByAgent_NegativeOffset(t *testing.T) {
-	ctx := context.Background()
-	agentID := "runs-negoff-" + uuid.New().String()[:8]
-	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	audit := storage.MutationAuditEntry{
+		Operation:    "complete_run",
+		ActorRole:    "agent",
+		ActorAgentID: agentID,
+		OrgID:        run.OrgID,
+		ResourceID:   run.ID.String(),
+	}
+	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, map[string]any{"result": "ok"}, audit)
 	require.NoError(t, err)
 
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 10, -5)
+	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, runs, 1)
+	assert.Equal(t, model.RunStatusCompleted, got.Status)
+	assert.NotNil(t, got.CompletedAt)
 }
 
-func TestListRunsByAgent_Empty(t *testing.T) {
+func TestCompleteRunWithAudit_NotFound(t *testing.T) {
 	ctx := context.Background()
-	agentID := "runs-empty-" + uuid.New().String()[:8]
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 10, 0)
-	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, runs)
+	fakeID := uuid.New()
+	audit := storage.MutationAuditEntry{
+		Operation:    "complete_run",
+		ActorRole:    "agent",
+		ActorAgentID: "no-such-agent",
+		OrgID:        uuid.Nil,
+	}
+	err := testDB.CompleteRunWithAudit(ctx, uuid.Nil, fakeID, model.RunStatusCompleted, nil, audit)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetRetentionPolicy — with deletion log
-// ---------------------------------------------------------------------------
-
-func TestGetRetentionPolicy_WithDeletionLog(t *testing.T) {
+func TestCompleteRunWithAudit_Idempotent(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-		orgID, "retention-test-"+suffix, "retention-test-"+suffix)
-	require.NoError(t, err)
+	agentID := "crwa-idem-" + suffix
 
-	days := 90
-	err = testDB.SetRetentionPolicy(ctx, orgID, &days, nil)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	started := time.Now().UTC().Add(-2 * time.Hour)
-	_, err = testDB.Pool().Exec(ctx,
-		`INSERT INTO deletion_log (id, org_id, trigger, started_at, completed_at, deleted_counts, criteria)
-		 VALUES ($1, $2, 'policy', $3, $4, '{"decisions": 42}', '{}')`,
-		uuid.New(), orgID, started, time.Now().UTC())
+	audit := storage.MutationAuditEntry{
+		Operation:    "complete_run",
+		ActorRole:    "agent",
+		ActorAgentID: agentID,
+		OrgID:        run.OrgID,
+	}
+	// First completion.
+	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, nil, audit)
 	require.NoError(t, err)
 
-	policy, err := testDB.GetRetentionPolicy(ctx, orgID, 24*time.Hour)
-	require.NoError(t, err)
-	require.NotNil(t, policy.RetentionDays)
-	assert.Equal(t, 90, *policy.RetentionDays)
-	require.NotNil(t, policy.LastRunAt, "should populate LastRunAt from deletion_log")
-	require.NotNil(t, policy.LastRunDeleted)
-	assert.Equal(t, 42, *policy.LastRunDeleted)
-	require.NotNil(t, policy.NextRunAt, "should compute NextRunAt from interval")
+	// Second completion of already-completed run should be idempotent.
+	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusCompleted, nil, audit)
+	require.NoError(t, err, "completing an already-completed run should be idempotent")
 }
 
-func TestGetRetentionPolicy_ZeroInterval(t *testing.T) {
+func TestCloseIdleRuns(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-		orgID, "retention-zero-"+suffix, "retention-zero-"+suffix)
-	require.NoError(t, err)
-
-	days := 30
-	err = testDB.SetRetentionPolicy(ctx, orgID, &days, nil)
-	require.NoError(t, err)
 
-	_, err = testDB.Pool().Exec(ctx,
-		`INSERT INTO deletion_log (id, org_id, trigger, started_at, completed_at, deleted_counts, criteria)
-		 VALUES ($1, $2, 'policy', $3, $4, '{"decisions": 10}', '{}')`,
-		uuid.New(), orgID, time.Now().UTC().Add(-1*time.Hour), time.Now().UTC())
+	idle, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "idle-run-" + suffix})
 	require.NoError(t, err)
-
-	policy, err := testDB.GetRetentionPolicy(ctx, orgID, 0)
+	active, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "active-run-" + suffix})
 	require.NoError(t, err)
-	require.NotNil(t, policy.LastRunAt)
-	assert.Nil(t, policy.NextRunAt, "zero interval should not set NextRunAt")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionsByAgent — boundary params
-// ---------------------------------------------------------------------------
 
-func TestGetDecisionsByAgent_ZeroLimit(t *testing.T) {
-	ctx := context.Background()
-	agentID := "agent-zerolim-" + uuid.New().String()[:8]
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	// Simulate inactivity: both runs started two hours ago, but the active
+	// one recorded a decision just now.
+	_, err = testDB.Pool().Exec(ctx,
+		`UPDATE agent_runs SET started_at = now() - interval '2 hours' WHERE id = ANY($1)`,
+		[]uuid.UUID{idle.ID, active.ID})
 	require.NoError(t, err)
 	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "test", Outcome: "result",
-		Confidence: 0.8, Metadata: map[string]any{},
+		RunID:        active.ID,
+		AgentID:      active.AgentID,
+		DecisionType: "architecture",
+		Outcome:      "still working",
+		Confidence:   0.6,
+		Metadata:     map[string]any{},
 	})
 	require.NoError(t, err)
 
-	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 0, 0, nil, nil)
+	closed, err := testDB.CloseIdleRuns(ctx, time.Now().Add(-time.Hour), model.RunStatusAbandoned, 1000)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, decisions, 1)
-}
+	assert.GreaterOrEqual(t, closed, 1)
 
-func TestGetDecisionsByAgent_LargeLimit(t *testing.T) {
-	ctx := context.Background()
-	agentID := "agent-largelim-" + uuid.New().String()[:8]
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "test", Outcome: "result",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	got, err := testDB.GetRun(ctx, idle.OrgID, idle.ID)
 	require.NoError(t, err)
+	assert.Equal(t, model.RunStatusAbandoned, got.Status)
+	assert.NotNil(t, got.CompletedAt)
 
-	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 5000, 0, nil, nil)
+	got, err = testDB.GetRun(ctx, active.OrgID, active.ID)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, decisions, 1)
+	assert.Equal(t, model.RunStatusRunning, got.Status, "runs with recent decisions must not be swept")
+
+	var audits int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM mutation_audit_log WHERE resource_type = 'agent_run' AND resource_id = $1 AND operation = 'close_idle_run'`,
+		idle.ID.String()).Scan(&audits))
+	assert.Equal(t, 1, audits)
+
+	// An abandoned run is terminal: a late complete call is an idempotent no-op.
+	require.NoError(t, testDB.CompleteRun(ctx, idle.OrgID, idle.ID, model.RunStatusCompleted, nil))
+
+	_, err = testDB.CloseIdleRuns(ctx, time.Now(), model.RunStatusRunning, 10)
+	assert.Error(t, err, "running is not a valid target status")
 }
 
-func TestGetDecisionsByAgent_NegativeOffset(t *testing.T) {
+func TestCompleteRunWithAudit_NilMetadata(t *testing.T) {
 	ctx := context.Background()
-	agentID := "agent-negoff-" + uuid.New().String()[:8]
+	suffix := uuid.New().String()[:8]
+	agentID := "crwa-nil-" + suffix
+
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "test", Outcome: "result",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
 
-	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 10, -3, nil, nil)
+	audit := storage.MutationAuditEntry{
+		Operation:    "complete_run",
+		ActorRole:    "agent",
+		ActorAgentID: agentID,
+		OrgID:        run.OrgID,
+	}
+	err = testDB.CompleteRunWithAudit(ctx, run.OrgID, run.ID, model.RunStatusFailed, nil, audit)
+	require.NoError(t, err, "nil metadata should be coerced to empty map")
+
+	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	assert.Len(t, decisions, 1)
+	assert.Equal(t, model.RunStatusFailed, got.Status)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: FindRetriableClaimFailures — with actual data
+// Tests: GetRun (57.1% -> cover not-found branch)
 // ---------------------------------------------------------------------------
 
-func TestFindRetriableClaimFailures_WithData(t *testing.T) {
+func TestGetRun_NotFound(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "retry-claims-" + suffix
+	_, err := testDB.GetRun(ctx, uuid.Nil, uuid.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: GetAgentByAgentID and GetAgentByID (57.1% -> cover not-found branches)
+// ---------------------------------------------------------------------------
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "retry_test", Outcome: "will fail claims",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
+func TestGetAgentByAgentID_NotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := testDB.GetAgentByAgentID(ctx, uuid.Nil, "nonexistent-agent-"+uuid.New().String()[:8])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestGetAgentByID_NotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := testDB.GetAgentByID(ctx, uuid.New(), uuid.Nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetAgentWinRates (53.3% -> cover the happy path and empty-agents path)
+// ---------------------------------------------------------------------------
+
+func TestGetAgentWinRates_EmptyAgents(t *testing.T) {
+	ctx := context.Background()
+	result, err := testDB.GetAgentWinRates(ctx, uuid.Nil, []string{}, "architecture")
 	require.NoError(t, err)
+	assert.Empty(t, result, "empty agentIDs should return empty map")
+}
 
-	emb := pgvector.NewVector(make([]float32, 1024))
-	err = testDB.BackfillEmbedding(ctx, dec.ID, dec.OrgID, emb)
+func TestGetAgentWinRates_WithResolvedConflicts(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentA := "winrate-a-" + suffix
+	agentB := "winrate-b-" + suffix
+	decisionType := "winrate_type_" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	err = testDB.MarkClaimEmbeddingFailed(ctx, dec.ID, dec.OrgID)
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decisionType, Outcome: "approach_X",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decisionType, Outcome: "approach_Y",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
 
-	_, err = testDB.Pool().Exec(ctx,
-		`UPDATE decisions SET claim_embeddings_failed_at = now() - interval '1 hour'
-		 WHERE id = $1 AND org_id = $2`, dec.ID, dec.OrgID)
+	topicSim := 0.95
+	outcomeDiv := 0.85
+	sig := 0.8
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: dA.OrgID, ConflictKind: "cross_agent",
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+		OutcomeA: "approach_X", OutcomeB: "approach_Y",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "embedding",
+	})
 	require.NoError(t, err)
 
-	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 50)
+	// Resolve the conflict with agentA as winner.
+	resNote := "agentA wins"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, dA.OrgID,
+		"resolved", "test", &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			OrgID: dA.OrgID, ActorAgentID: "test", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
 	require.NoError(t, err)
 
-	found := false
-	for _, r := range refs {
-		if r.ID == dec.ID {
-			found = true
-			assert.Equal(t, dec.OrgID, r.OrgID)
-			assert.Equal(t, 1, r.Attempts)
-			break
-		}
+	result, err := testDB.GetAgentWinRates(ctx, dA.OrgID, []string{agentA, agentB}, decisionType)
+	require.NoError(t, err)
+	// Both agents should appear. Exactly one should have a win (the winner).
+	// Due to UUID-based normalization in InsertScoredConflict, we check aggregate counts
+	// rather than assuming which agent maps to which side.
+	totalWins := 0
+	for _, r := range result {
+		totalWins += r.WinCount
+		assert.Equal(t, 1, r.Total, "each agent should appear in exactly 1 resolved conflict")
 	}
-	assert.True(t, found, "expected to find the failed decision in retriable results")
+	assert.Equal(t, 1, totalWins, "exactly one agent should have a win")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetConflictAnalytics — broader time window, structure validation
+// Tests: GetDecisionEmbeddings (57.1% -> cover empty input and happy path)
 // ---------------------------------------------------------------------------
 
-func TestGetConflictAnalytics_StructureValidation(t *testing.T) {
+func TestGetDecisionEmbeddings_EmptyIDs(t *testing.T) {
 	ctx := context.Background()
-	now := time.Now().UTC()
-	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From: now.Add(-7 * 24 * time.Hour),
-		To:   now,
-	})
+	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{}, uuid.Nil)
 	require.NoError(t, err)
-	assert.NotNil(t, analytics.ByAgentPair)
-	assert.NotNil(t, analytics.ByDecisionType)
-	assert.NotNil(t, analytics.BySeverity)
-	assert.NotNil(t, analytics.Trend)
-	assert.Len(t, analytics.Trend, 7, "should have one trend point per day")
+	assert.Empty(t, result)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetOutcomeSignalsSummary
-// ---------------------------------------------------------------------------
-
-func TestGetOutcomeSignalsSummary_Populated(t *testing.T) {
+func TestGetDecisionEmbeddings_WithEmbeddings(t *testing.T) {
 	ctx := context.Background()
-	summary, err := testDB.GetOutcomeSignalsSummary(ctx, uuid.Nil, nil, nil)
+	suffix := uuid.New().String()[:8]
+	agentID := "emb-get-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, summary.DecisionsTotal, 0)
-	assert.GreaterOrEqual(t, summary.NeverSuperseded, 0)
-	assert.GreaterOrEqual(t, summary.NeverCited, 0)
-	assert.GreaterOrEqual(t, summary.ConflictsWon, 0)
-	assert.Equal(t, summary.ConflictsWon, summary.ConflictsLost, "wins and losses should be symmetric")
-}
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionQualityStats
-// ---------------------------------------------------------------------------
+	embSlice := make([]float32, 1024)
+	outcomeEmbSlice := make([]float32, 1024)
+	for i := range embSlice {
+		embSlice[i] = float32(i) / 1024.0
+		outcomeEmbSlice[i] = float32(1024-i) / 1024.0
+	}
+	emb := pgvector.NewVector(embSlice)
+	outcomeEmb := pgvector.NewVector(outcomeEmbSlice)
 
-func TestGetDecisionQualityStats_Structure(t *testing.T) {
-	ctx := context.Background()
-	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "emb_test", Outcome: "some outcome",
+		Confidence: 0.9, Metadata: map[string]any{},
+		Embedding:        &emb,
+		OutcomeEmbedding: &outcomeEmb,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, stats.Total, 0)
+
+	result, err := testDB.GetDecisionEmbeddings(ctx, []uuid.UUID{d.ID}, d.OrgID)
+	require.NoError(t, err)
+	assert.Len(t, result, 1, "should return 1 decision with both embeddings")
+	pair, ok := result[d.ID]
+	assert.True(t, ok)
+	assert.Equal(t, 1024, len(pair[0].Slice()))
+	assert.Equal(t, 1024, len(pair[1].Slice()))
 }
 
 // ---------------------------------------------------------------------------
-// Tests: SearchDecisionsByText — FTS error triggers ILIKE fallback
+// Tests: ListAgents edge cases (63.6% -> cover limit clamping)
 // ---------------------------------------------------------------------------
 
-func TestSearchDecisionsByText_MalformedQuery(t *testing.T) {
+func TestListAgents_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, `"unclosed quote`, model.QueryFilters{}, 10)
+	// With limit=0, should default to 200. Just verify it doesn't error.
+	agents, err := testDB.ListAgents(ctx, uuid.Nil, 0, 0)
 	require.NoError(t, err)
-	_ = results
+	_ = agents // may be empty or have agents from other tests; just verifying no error
 }
 
-func TestSearchDecisionsByText_DefaultLimit(t *testing.T) {
+func TestListAgents_LargeLimit(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "test", model.QueryFilters{}, 0)
+	// With limit=5000, should be clamped to 1000. Just verify it doesn't error.
+	agents, err := testDB.ListAgents(ctx, uuid.Nil, 5000, 0)
 	require.NoError(t, err)
-	_ = results
+	_ = agents
 }
 
-func TestSearchDecisionsByText_LargeLimit(t *testing.T) {
+func TestListAgents_NegativeOffset(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "test", model.QueryFilters{}, 5000)
+	agents, err := testDB.ListAgents(ctx, uuid.Nil, 10, -5)
 	require.NoError(t, err)
-	_ = results
+	_ = agents
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ExportDecisionsCursor — with filters
+// Tests: FindRetriableClaimFailures (53.8% -> default limit, empty results)
 // ---------------------------------------------------------------------------
 
-func TestExportDecisionsCursor_WithFilters(t *testing.T) {
+func TestFindRetriableClaimFailures_NoFailures(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "export-filter-" + suffix
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "export_type_" + suffix, Outcome: "exported",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
+	// With no decisions having failed claims, should return empty.
+	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 10)
 	require.NoError(t, err)
+	assert.Empty(t, refs)
+}
 
-	decType := "export_type_" + suffix
-	decisions, err := testDB.ExportDecisionsCursor(ctx, run.OrgID,
-		model.QueryFilters{DecisionType: &decType}, nil, 10)
+func TestFindRetriableClaimFailures_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
+	// With limit=0, should default to 50. Just verify no error.
+	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 0)
 	require.NoError(t, err)
-	assert.Len(t, decisions, 1)
-	assert.Equal(t, "exported", decisions[0].Outcome)
+	assert.Empty(t, refs)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetDecisionErasure — found path
+// Tests: GetAPIKeyByID (57.1% -> not-found branch)
 // ---------------------------------------------------------------------------
 
-func TestGetDecisionErasure_Found(t *testing.T) {
+func TestGetAPIKeyByID_NotFound(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "erasure-" + suffix
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "erasure_test", Outcome: "to be erased",
-		Confidence: 0.5, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	_, err = testDB.EraseDecision(ctx, dec.OrgID, dec.ID, "gdpr", agentID, nil)
-	require.NoError(t, err)
-
-	erasure, err := testDB.GetDecisionErasure(ctx, dec.OrgID, dec.ID)
-	require.NoError(t, err)
-	assert.Equal(t, "gdpr", erasure.Reason)
+	_, err := testDB.GetAPIKeyByID(ctx, uuid.New(), uuid.Nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CountConflicts — with severity and category filters
+// Tests: ListAPIKeys edge cases (64.7%)
 // ---------------------------------------------------------------------------
 
-func TestCountConflicts_WithSeverity(t *testing.T) {
-	ctx := context.Background()
-	sev := "high"
-	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{Severity: &sev})
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
-}
-
-func TestCountConflicts_WithCategory(t *testing.T) {
+func TestListAPIKeys_Empty(t *testing.T) {
 	ctx := context.Background()
-	cat := "architectural"
-	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{Category: &cat})
+	// Use a random org that has no keys.
+	keys, _, err := testDB.ListAPIKeys(ctx, uuid.New(), 10, 0)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
+	assert.Empty(t, keys)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: DeleteAgentData — with comprehensive data and audit
+// Tests: ReviseDecision (64.3% -> cover not-found and successful revision)
 // ---------------------------------------------------------------------------
 
-func TestDeleteAgentData_WithAllRelatedData(t *testing.T) {
+func TestReviseDecision_Success(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "delete-full-" + suffix
-
-	agent, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	agentID := "revise-ok-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
+	original, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "delete_full_test", Outcome: "will be removed",
-		Confidence: 0.6, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	_, err = testDB.CreateEvidence(ctx, model.Evidence{
-		DecisionID: dec.ID, OrgID: dec.OrgID,
-		SourceType: model.SourceAPIResponse, Content: "evidence content",
+		DecisionType: "revise_test", Outcome: "original approach",
+		Confidence: 0.7, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
-		{DecisionID: dec.ID, Label: "alternative A"},
-	})
+	audit := storage.MutationAuditEntry{
+		OrgID: original.OrgID, ActorAgentID: agentID, ActorRole: "agent",
+		Operation: "revise_decision", ResourceType: "decision",
+	}
+	revised, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: original.OrgID,
+		DecisionType: "revise_test", Outcome: "revised approach",
+		Confidence: 0.9,
+	}, &audit)
 	require.NoError(t, err)
+	assert.Equal(t, "revised approach", revised.Outcome)
+	assert.NotEqual(t, original.ID, revised.ID, "revised decision should have a new ID")
+	assert.Equal(t, &original.ID, revised.SupersedesID)
+}
 
-	// Create an assessment so the trigger bypass (SET LOCAL akashi.allow_assessment_delete)
-	// is exercised against a live row, not just zero-match.
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      dec.ID,
-		AssessorAgentID: "reviewer-" + suffix,
-		Outcome:         model.AssessmentCorrect,
-	})
-	require.NoError(t, err)
+func TestReviseDecision_NotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := testDB.ReviseDecision(ctx, uuid.New(), model.Decision{
+		OrgID: uuid.Nil, AgentID: "ghost", DecisionType: "test",
+		Outcome: "anything", Confidence: 0.5,
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
 
-	now := time.Now().UTC()
-	err = testDB.InsertEvent(ctx, model.AgentEvent{
-		ID: uuid.New(), RunID: run.ID, OrgID: run.OrgID,
-		EventType: model.EventDecisionStarted, SequenceNum: 1,
-		OccurredAt: now, AgentID: agentID,
-		Payload: map[string]any{}, CreatedAt: now,
-	})
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: CountConflicts with filters to exercise conflictWhere branches
+// ---------------------------------------------------------------------------
 
-	resID := agentID
-	_, err = testDB.CreateGrant(ctx, model.AccessGrant{
-		OrgID: uuid.Nil, GrantorID: agent.ID, GranteeID: agent.ID,
-		ResourceType: "agent_traces", ResourceID: &resID,
-		Permission: "read",
-	})
-	require.NoError(t, err)
+func TestCountConflicts_AllFilters(t *testing.T) {
+	ctx := context.Background()
 
-	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentID, nil)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, result.Decisions, int64(1))
-	assert.GreaterOrEqual(t, result.Evidence, int64(1))
-	assert.GreaterOrEqual(t, result.Alternatives, int64(1))
-	assert.GreaterOrEqual(t, result.Assessments, int64(1), "assessments should be explicitly deleted")
-	assert.GreaterOrEqual(t, result.Events, int64(1))
-	assert.GreaterOrEqual(t, result.Runs, int64(1))
-	assert.GreaterOrEqual(t, result.Grants, int64(1))
-	assert.GreaterOrEqual(t, result.Agents, int64(1))
+	decisionType := "filter_test"
+	agentIDFilter := "filter-agent"
+	conflictKind := "cross_agent"
+	status := "open"
+	severity := "high"
+	category := "factual"
+	decisionIDFilter := uuid.New()
 
-	// Verify the assessment was archived to deletion_audit_log before deletion.
-	var archived int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM deletion_audit_log
-		 WHERE org_id = $1 AND table_name = 'decision_assessments'
-		   AND record_data->>'assessor_agent_id' = $2`,
-		uuid.Nil, "reviewer-"+suffix,
-	).Scan(&archived)
+	// Count with every filter active — should succeed (return 0) without SQL errors.
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		DecisionType: &decisionType,
+		AgentID:      &agentIDFilter,
+		ConflictKind: &conflictKind,
+		Status:       &status,
+		Severity:     &severity,
+		Category:     &category,
+		DecisionID:   &decisionIDFilter,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 1, archived, "assessment should be archived to deletion_audit_log")
+	assert.Equal(t, 0, count)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: DeleteAgentData — with resolved conflict resolutions
+// Tests: ListConflicts with filters to exercise more conflictWhere branches
 // ---------------------------------------------------------------------------
 
-func TestDeleteAgentData_WithResolvedConflicts(t *testing.T) {
+func TestListConflicts_WithSeverityFilter(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentA := "del-conflict-a-" + suffix
-	agentB := "del-conflict-b-" + suffix
-
-	// Create two agents with decisions that will have a conflict.
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentA, OrgID: uuid.Nil, Name: agentA, Role: model.RoleAgent, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-	_, err = testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentB, OrgID: uuid.Nil, Name: agentB, Role: model.RoleAgent, Metadata: map[string]any{},
-	})
+	severity := "critical"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		Severity: &severity,
+	}, 10, 0)
 	require.NoError(t, err)
+	_ = conflicts // may be empty; just verifying the query with severity filter doesn't error
+}
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+func TestListConflicts_WithCategoryFilter(t *testing.T) {
+	ctx := context.Background()
+	category := "temporal"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		Category: &category,
+	}, 10, 0)
 	require.NoError(t, err)
+	_ = conflicts
+}
+
+func TestListConflicts_WithReasonCodeFilter(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentA, agentB := "reason-a-"+suffix, "reason-b-"+suffix
 
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
 	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: "delete_cr_test", Outcome: "approach A",
-		Confidence: 0.7, Metadata: map[string]any{},
+		RunID: run.ID, AgentID: agentA,
+		DecisionType: "reason_test", Outcome: "ship on friday", Confidence: 0.9,
 	})
 	require.NoError(t, err)
-
 	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: "delete_cr_test", Outcome: "approach B",
-		Confidence: 0.8, Metadata: map[string]any{},
+		RunID: run.ID, AgentID: agentB,
+		DecisionType: "reason_test", Outcome: "ship on friday, probably", Confidence: 0.3,
 	})
 	require.NoError(t, err)
 
-	// Insert a scored conflict between the two decisions.
-	topicSim := 0.90
-	outcomeDiv := 0.80
-	sig := topicSim * outcomeDiv
+	sig := 0.5
+	reason := string(model.ConflictReasonConfidenceDivergence)
 	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     "delete_cr_test",
-		DecisionTypeB:     "delete_cr_test",
-		OutcomeA:          "approach A",
-		OutcomeB:          "approach B",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
+		ConflictKind:  model.ConflictKindCrossAgent,
+		DecisionAID:   dA.ID,
+		DecisionBID:   dB.ID,
+		OrgID:         uuid.Nil,
+		AgentA:        agentA,
+		AgentB:        agentB,
+		DecisionTypeA: "reason_test",
+		DecisionTypeB: "reason_test",
+		OutcomeA:      dA.Outcome,
+		OutcomeB:      dB.Outcome,
+		Significance:  &sig,
+		ScoringMethod: "text",
+		ReasonCode:    &reason,
 	})
 	require.NoError(t, err)
 
-	// Resolve the conflict — this updates the scored_conflicts row in place.
-	resNote := "A wins because reasons"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "admin-"+suffix, &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			OrgID: uuid.Nil, ActorAgentID: "admin-" + suffix, ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		},
-	)
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		AgentID:    &agentA,
+		ReasonCode: &reason,
+	}, 10, 0)
 	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, conflictID, conflicts[0].ID)
+	require.NotNil(t, conflicts[0].ReasonCode)
+	assert.Equal(t, reason, *conflicts[0].ReasonCode)
 
-	// Re-insert the same conflict pair. The CTE in InsertScoredConflict archives
-	// the resolution to conflict_resolutions before the ON CONFLICT DO UPDATE
-	// reopens it. This is the only codepath that creates conflict_resolutions rows.
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     "delete_cr_test",
-		DecisionTypeB:     "delete_cr_test",
-		OutcomeA:          "approach A",
-		OutcomeB:          "approach B",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "text",
+	other := string(model.ConflictReasonOutcomeOpposite)
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		AgentID:    &agentA,
+		ReasonCode: &other,
 	})
 	require.NoError(t, err)
-
-	// Verify the conflict_resolutions row exists before deletion.
-	var resBefore int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM conflict_resolutions WHERE conflict_id = $1`, conflictID,
-	).Scan(&resBefore)
-	require.NoError(t, err)
-	require.Equal(t, 1, resBefore, "should have 1 archived resolution before delete")
-
-	// Delete agent A — this must clean up conflict_resolutions before scored_conflicts.
-	// Before this fix, the ON DELETE RESTRICT FK on conflict_resolutions.conflict_id
-	// would cause a FK violation error, rolling back the entire delete transaction.
-	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentA, nil)
-	require.NoError(t, err, "DeleteAgentData should not fail with resolved conflicts")
-	assert.GreaterOrEqual(t, result.Decisions, int64(1))
-	assert.GreaterOrEqual(t, result.ConflictResolutions, int64(1), "should delete conflict_resolutions")
-
-	// Verify the resolution was archived to deletion_audit_log.
-	var archivedRes int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM deletion_audit_log
-		 WHERE org_id = $1 AND table_name = 'conflict_resolutions'
-		   AND record_data->>'conflict_id' = $2`,
-		uuid.Nil, conflictID.String(),
-	).Scan(&archivedRes)
-	require.NoError(t, err)
-	assert.Equal(t, 1, archivedRes, "resolution should be archived to deletion_audit_log")
-
-	// Verify the conflict_resolutions row is gone.
-	var resAfter int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM conflict_resolutions WHERE conflict_id = $1`, conflictID,
-	).Scan(&resAfter)
-	require.NoError(t, err)
-	assert.Equal(t, 0, resAfter, "conflict_resolutions should be deleted")
+	assert.Equal(t, 0, count)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetCitationPercentilesForOrg — with data
+// Tests: GetDecisionOutcomeSignals (66.7% -> cover all three query paths)
 // ---------------------------------------------------------------------------
 
-func TestGetCitationPercentilesForOrg_WithCitedDecisions(t *testing.T) {
+func TestGetDecisionOutcomeSignals_NoSignals(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "citation-perc-" + suffix
+	agentID := "signals-" + suffix
+
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	precedent, err := testDB.CreateDecision(ctx, model.Decision{
+	d, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "precedent_type", Outcome: "the precedent",
-		Confidence: 0.9, Metadata: map[string]any{},
+		DecisionType: "signal_test", Outcome: "standalone decision",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "citing_type", Outcome: fmt.Sprintf("citing %d", i),
-			Confidence:   0.8,
-			PrecedentRef: &precedent.ID,
-			Metadata:     map[string]any{},
-		})
-		require.NoError(t, err)
-	}
-
-	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, run.OrgID)
+	signals, err := testDB.GetDecisionOutcomeSignals(ctx, d.ID, d.OrgID)
 	require.NoError(t, err)
-	require.Len(t, breakpoints, 4)
-	for _, bp := range breakpoints {
-		assert.GreaterOrEqual(t, bp, float64(0))
-	}
+	assert.Nil(t, signals.SupersessionVelocityHours, "un-superseded decision should have nil velocity")
+	assert.Equal(t, 0, signals.PrecedentCitationCount)
+	assert.Equal(t, 0, signals.ConflictFate.Won)
+	assert.Equal(t, 0, signals.ConflictFate.Lost)
+	assert.Equal(t, 0, signals.ConflictFate.ResolvedNoWinner)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: BackfillOutcomeEmbedding — success path
-// ---------------------------------------------------------------------------
-
-func TestBackfillOutcomeEmbedding_Success(t *testing.T) {
+func TestGetDecisionOutcomeSignals_WithSupersession(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "backfill-oe-" + suffix
+	agentID := "signals-sup-" + suffix
+
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
+	original, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "backfill_oe", Outcome: "needs outcome embedding",
-		Confidence: 0.8, Metadata: map[string]any{},
+		DecisionType: "signal_test", Outcome: "first approach",
+		Confidence: 0.7, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	emb := pgvector.NewVector(make([]float32, 1024))
-	err = testDB.BackfillEmbedding(ctx, dec.ID, dec.OrgID, emb)
+	// Revise the decision so SupersessionVelocityHours is populated.
+	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: original.OrgID,
+		DecisionType: "signal_test", Outcome: "revised approach",
+		Confidence: 0.9,
+	}, nil)
 	require.NoError(t, err)
 
-	oemb := pgvector.NewVector(make([]float32, 1024))
-	err = testDB.BackfillOutcomeEmbedding(ctx, dec.ID, dec.OrgID, oemb)
+	signals, err := testDB.GetDecisionOutcomeSignals(ctx, original.ID, original.OrgID)
 	require.NoError(t, err)
+	assert.NotNil(t, signals.SupersessionVelocityHours, "superseded decision should have velocity")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetAlternativesByDecisions — empty and no-match
+// Tests: GetRetentionPolicy (66.7% -> cover policy lookup)
 // ---------------------------------------------------------------------------
 
-func TestGetAlternativesByDecisions_Empty(t *testing.T) {
-	ctx := context.Background()
-	result, err := testDB.GetAlternativesByDecisions(ctx, []uuid.UUID{}, uuid.Nil)
-	require.NoError(t, err)
-	assert.Nil(t, result)
-}
-
-func TestGetAlternativesByDecisions_NoMatch(t *testing.T) {
+func TestGetRetentionPolicy_DefaultOrg(t *testing.T) {
 	ctx := context.Background()
-	result, err := testDB.GetAlternativesByDecisions(ctx, []uuid.UUID{uuid.New()}, uuid.Nil)
+	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, time.Hour)
 	require.NoError(t, err)
-	assert.Empty(t, result)
+	// Default org likely has no retention policy set.
+	assert.Nil(t, policy.RetentionDays, "default org should have nil retention days unless explicitly set")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetEvidenceByDecision — empty
+// Tests: DeleteAgentData (67.3% -> not-found path)
 // ---------------------------------------------------------------------------
 
-func TestGetEvidenceByDecision_NoEvidence(t *testing.T) {
+func TestDeleteAgentData_NotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := testDB.DeleteAgentData(ctx, uuid.Nil, "nonexistent-agent-"+uuid.New().String()[:8], nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestDeleteAgentData_Success(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "evidence-none-" + suffix
+	agentID := "delete-me-" + suffix
+
+	// Create the agent record explicitly.
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create a run and decision for the agent.
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
+
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "no_evidence", Outcome: "has no evidence",
+		DecisionType: "delete_test", Outcome: "to be deleted",
 		Confidence: 0.5, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	evidence, err := testDB.GetEvidenceByDecision(ctx, dec.ID, dec.OrgID)
+	audit := storage.MutationAuditEntry{
+		OrgID: run.OrgID, ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "delete_agent", ResourceType: "agent",
+	}
+	result, err := testDB.DeleteAgentData(ctx, run.OrgID, agentID, &audit)
 	require.NoError(t, err)
-	assert.Empty(t, evidence)
+	assert.GreaterOrEqual(t, result.Decisions, int64(1), "should delete at least 1 decision")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetAlternativesByDecision — empty
-// ---------------------------------------------------------------------------
-
-func TestGetAlternativesByDecision_NoAlternatives(t *testing.T) {
+func TestTombstoneAgentData_HidesRowsAndKeepsProofs(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "alts-none-" + suffix
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentID := "tombstone-" + suffix
+
+	// Dedicated org so the batch window only holds this test's decisions.
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "tombstone-org-"+suffix, "tombstone-org-"+suffix)
 	require.NoError(t, err)
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "no_alts", Outcome: "has no alternatives",
-		Confidence: 0.5, Metadata: map[string]any{},
+	_, err = testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: orgID, Name: agentID, Role: model.RoleAgent, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
+	runID := uuid.New()
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
+		 VALUES ($1, $2, $3, 'running', now())`,
+		runID, orgID, agentID)
+	require.NoError(t, err)
 
-	alts, err := testDB.GetAlternativesByDecision(ctx, dec.ID, dec.OrgID)
+	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
+	var ids []uuid.UUID
+	for i := range 3 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			ID: uuid.New(), RunID: runID, AgentID: agentID, OrgID: orgID,
+			DecisionType: "tombstone_test", Outcome: fmt.Sprintf("outcome %d", i),
+			Confidence: 0.7, ValidFrom: time.Now().UTC(),
+		})
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+	afterCreate := time.Now().UTC().Add(1 * time.Second)
+
+	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	require.Len(t, hashes, 3)
+	root, err := integrity.BuildMerkleRoot(hashes)
 	require.NoError(t, err)
-	assert.Empty(t, alts)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: TouchLastSeen — non-existent
-// ---------------------------------------------------------------------------
+	result, err := testDB.TombstoneAgentData(ctx, orgID, agentID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Decisions)
+	assert.Equal(t, int64(1), result.Runs)
+	assert.Equal(t, int64(1), result.Agents)
 
-func TestTouchLastSeen_NonExistent(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.TouchLastSeen(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8])
+	// Gone from every read path.
+	decisions, total, err := testDB.QueryDecisions(ctx, orgID, model.QueryRequest{Limit: 10})
 	require.NoError(t, err)
-}
+	assert.Empty(t, decisions)
+	assert.Zero(t, total)
+	history, err := testDB.ListDecisionHistory(ctx, orgID, model.QueryFilters{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, history, "history includes superseded rows but not tombstoned ones")
+	_, err = testDB.GetDecision(ctx, orgID, ids[0], storage.GetDecisionOpts{})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = testDB.GetRun(ctx, orgID, runID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = testDB.GetAgentByAgentID(ctx, orgID, agentID)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 
-// ---------------------------------------------------------------------------
-// Tests: GetEventsByRun — default limit
-// ---------------------------------------------------------------------------
+	// The agent_id stays reserved until purge: it is reported as tombstoned
+	// and cannot be issued a new API key.
+	tombstoned, err := testDB.IsAgentTombstoned(ctx, orgID, agentID)
+	require.NoError(t, err)
+	assert.True(t, tombstoned)
+	_, _, err = testDB.RotateAgentAPIKeyWithAudit(ctx, orgID, agentID,
+		model.APIKey{Prefix: "tomb", KeyHash: "hash-" + suffix, AgentID: agentID, OrgID: orgID, Label: "rotated"},
+		storage.MutationAuditEntry{OrgID: orgID, ActorAgentID: "admin", ActorRole: "admin", Endpoint: "test"})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 
-func TestGetEventsByRun_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
-	events, err := testDB.GetEventsByRun(ctx, uuid.Nil, uuid.New(), 0)
+	// Still present for integrity: the batch hashes and root are unchanged.
+	after, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
 	require.NoError(t, err)
-	assert.Empty(t, events)
+	ok, err := integrity.VerifyBatchProof(root, after)
+	require.NoError(t, err)
+	assert.True(t, ok, "tombstoned decisions must still verify against their Merkle root")
+
+	// Tombstoning twice reports the agent as gone.
+	_, err = testDB.TombstoneAgentData(ctx, orgID, agentID, nil)
+	assert.ErrorIs(t, err, storage.ErrAgentNotFound)
+
+	// A cutoff before the tombstone purges nothing; a later one hard-deletes.
+	purged, err := testDB.PurgeTombstonedData(ctx, beforeCreate)
+	require.NoError(t, err)
+	for _, p := range purged {
+		assert.NotEqual(t, agentID, p.AgentID)
+	}
+	purged, err = testDB.PurgeTombstonedData(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	var found bool
+	for _, p := range purged {
+		if p.OrgID == orgID && p.AgentID == agentID {
+			found = true
+			assert.Equal(t, int64(3), p.Deleted.Decisions)
+		}
+	}
+	assert.True(t, found, "tombstoned agent should be purged")
+	tombstoned, err = testDB.IsAgentTombstoned(ctx, orgID, agentID)
+	require.NoError(t, err)
+	assert.False(t, tombstoned, "purged agent_id is free for reuse")
+	after, err = testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	assert.Empty(t, after)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: NewConflictsSinceByOrg — default limit
+// Tests: BackfillEmbedding (69.2% -> cover not-found and basic success)
 // ---------------------------------------------------------------------------
 
-func TestNewConflictsSinceByOrg_DefaultLimit(t *testing.T) {
+func TestBackfillEmbedding_NoMatch(t *testing.T) {
 	ctx := context.Background()
-	conflicts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, time.Now().Add(-24*time.Hour), 0)
-	require.NoError(t, err)
-	_ = conflicts
+	emb := pgvector.NewVector(make([]float32, 1024))
+	// BackfillEmbedding returns nil when no rows match (decision revised/deleted/missing).
+	err := testDB.BackfillEmbedding(ctx, uuid.New(), uuid.Nil, emb, "")
+	require.NoError(t, err, "missing decision should be silently skipped")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetResolvedConflictsByType — default limit
+// Tests: CompleteIdempotency (66.7%)
 // ---------------------------------------------------------------------------
 
-func TestGetResolvedConflictsByType_DefaultLimitBranch(t *testing.T) {
+func TestCompleteIdempotency_NotFound(t *testing.T) {
 	ctx := context.Background()
-	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, "test_type", 0)
-	require.NoError(t, err)
-	_ = results
+	err := testDB.CompleteIdempotency(ctx, uuid.Nil, "ghost-agent", "/v1/trace", uuid.New().String(), 200, map[string]any{"ok": true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateRun — nil metadata path
+// Tests: RevokeAPIKeyWithAudit (66.7%)
 // ---------------------------------------------------------------------------
 
-func TestCreateRun_NilMetadata(t *testing.T) {
+func TestRevokeAPIKeyWithAudit_NotFound(t *testing.T) {
 	ctx := context.Background()
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{
-		AgentID:  "nilmeta-run-" + uuid.New().String()[:8],
-		Metadata: nil,
-	})
-	require.NoError(t, err)
-	assert.NotNil(t, run.Metadata, "nil metadata should be replaced with empty map")
-	assert.Empty(t, run.Metadata)
+	audit := storage.MutationAuditEntry{
+		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "revoke_api_key", ResourceType: "api_key",
+	}
+	err := testDB.RevokeAPIKeyWithAudit(ctx, uuid.New(), uuid.Nil, audit)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: BatchDeleteDecisions — agentID and excludeTypes filter branches
+// Tests: UpdateAgentTagsWithAudit (63.2% -> cover success and not-found)
 // ---------------------------------------------------------------------------
 
-func TestBatchDeleteDecisions_WithAgentFilter(t *testing.T) {
+func TestUpdateAgentTagsWithAudit_Success(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "bd-agent-" + suffix
-	otherAgent := "bd-other-" + suffix
-	decisionType := "bd_agent_type_" + suffix
+	agentID := "tags-ok-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-	runOther, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: otherAgent})
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID,
+		Role: model.RoleAgent, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
 
-	// Create decisions for both agents.
-	for i := range 2 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: decisionType, Outcome: fmt.Sprintf("agent_%d", i),
-			Confidence: 0.5, Metadata: map[string]any{},
-		})
-		require.NoError(t, err)
+	audit := storage.MutationAuditEntry{
+		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "update_tags", ResourceType: "agent",
 	}
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: runOther.ID, AgentID: otherAgent,
-		DecisionType: decisionType, Outcome: "other",
-		Confidence: 0.5, Metadata: map[string]any{},
-	})
+	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, agentID, []string{"env:prod", "team:infra"}, audit)
 	require.NoError(t, err)
+	assert.Equal(t, []string{"env:prod", "team:infra"}, updated.Tags)
+}
 
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &decisionType, &agentID, nil, 100)
-	require.NoError(t, err)
-	assert.Equal(t, int64(2), result.Decisions, "should only delete agent's decisions")
+func TestUpdateAgentTagsWithAudit_NotFound(t *testing.T) {
+	ctx := context.Background()
+	audit := storage.MutationAuditEntry{
+		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "update_tags", ResourceType: "agent",
+	}
+	_, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8], []string{"a"}, audit)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
-func TestBatchDeleteDecisions_WithExcludeTypes(t *testing.T) {
+func TestUpdateAgentTagsWithAudit_NilTags(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "bd-excl-" + suffix
-	typeKeep := "bd_keep_" + suffix
-	typeDelete := "bd_delete_" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentID := "tags-nil-" + suffix
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: typeKeep, Outcome: "should survive",
-		Confidence: 0.5, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: typeDelete, Outcome: "should be deleted",
-		Confidence: 0.5, Metadata: map[string]any{},
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID,
+		Role: model.RoleAgent, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, nil, &agentID, []string{typeKeep}, 100)
-	require.NoError(t, err)
-	assert.Equal(t, int64(1), result.Decisions, "should only delete non-excluded type")
-}
-
-func TestBatchDeleteDecisions_DefaultBatchSize(t *testing.T) {
-	ctx := context.Background()
-	// batchSize=0 should default to 1000.
-	farPast := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
-	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, farPast, nil, nil, nil, 0)
+	audit := storage.MutationAuditEntry{
+		OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "update_tags", ResourceType: "agent",
+	}
+	updated, err := testDB.UpdateAgentTagsWithAudit(ctx, uuid.Nil, agentID, nil, audit)
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), result.Decisions)
+	assert.Equal(t, []string{}, updated.Tags, "nil tags should be coerced to empty slice")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListConflicts — status and kind filter branches
+// Tests: ListAPIKeys edge cases (64.7%)
 // ---------------------------------------------------------------------------
 
-func TestListConflicts_StatusFilter(t *testing.T) {
-	ctx := context.Background()
-	status := "open"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		Status: &status,
-	}, 50, 0)
-	require.NoError(t, err)
-	for _, c := range conflicts {
-		assert.Equal(t, "open", c.Status, "status filter should only return open conflicts")
-	}
-}
-
-func TestListConflicts_KindFilter(t *testing.T) {
+func TestListAPIKeys_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	kind := "cross_agent"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		ConflictKind: &kind,
-	}, 50, 0)
+	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 0, 0)
 	require.NoError(t, err)
-	for _, c := range conflicts {
-		assert.Equal(t, model.ConflictKindCrossAgent, c.ConflictKind, "kind filter should only return cross_agent conflicts")
-	}
+	_ = keys // just verify default limit=50 doesn't error
 }
 
-func TestListConflicts_DecisionIDFilter(t *testing.T) {
+func TestListAPIKeys_NegativeOffset(t *testing.T) {
 	ctx := context.Background()
-	decID := uuid.New()
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		DecisionID: &decID,
-	}, 50, 0)
+	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 10, -5)
 	require.NoError(t, err)
-	_ = conflicts // just verify no error with this filter
+	_ = keys
 }
 
-func TestListConflicts_DefaultLimit(t *testing.T) {
+func TestListAPIKeys_LargeLimit(t *testing.T) {
 	ctx := context.Background()
-	// limit=0 should default to 50.
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 0, 0)
+	keys, _, err := testDB.ListAPIKeys(ctx, uuid.Nil, 5000, 0)
 	require.NoError(t, err)
-	_ = conflicts
+	_ = keys
 }
 
-func TestListConflicts_LargeLimit(t *testing.T) {
-	ctx := context.Background()
-	// limit > 1000 should be capped at 1000.
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 5000, 0)
-	require.NoError(t, err)
-	_ = conflicts
-}
+// ---------------------------------------------------------------------------
+// Tests: SearchDecisionsByText (70%)
+// ---------------------------------------------------------------------------
 
-func TestListConflicts_NegativeOffset(t *testing.T) {
+func TestSearchDecisionsByText_NoMatches(t *testing.T) {
 	ctx := context.Background()
-	// negative offset should be clamped to 0.
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 50, -10)
+	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "xyzzy_nonexistent_"+uuid.New().String()[:8], model.QueryFilters{}, 10)
 	require.NoError(t, err)
-	_ = conflicts
+	assert.Empty(t, results)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisions — ordering branches
+// Tests: GetDecisionOutcomeSignals additional branch (precedent citation)
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisions_OrderByConfidence(t *testing.T) {
+func TestGetDecisionOutcomeSignals_WithPrecedentCitation(t *testing.T) {
 	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Limit:   10,
-		OrderBy: "confidence",
-	})
-	require.NoError(t, err)
-	_ = decisions
-}
+	suffix := uuid.New().String()[:8]
+	agentID := "signals-cite-" + suffix
 
-func TestQueryDecisions_OrderByCreatedAt(t *testing.T) {
-	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Limit:   10,
-		OrderBy: "created_at",
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	_ = decisions
-}
 
-func TestQueryDecisions_DescDirection(t *testing.T) {
-	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Limit:    10,
-		OrderDir: "desc",
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "signal_cite_test", Outcome: "foundational decision",
+		Confidence: 0.9, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
-	_ = decisions
-}
 
-func TestQueryDecisions_AscDirection(t *testing.T) {
-	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Limit:    10,
-		OrderDir: "asc",
-	})
+	// Create two decisions citing the original as precedent.
+	for i := range 2 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "signal_cite_test", Outcome: fmt.Sprintf("follows precedent %d", i),
+			Confidence: 0.85, Metadata: map[string]any{},
+			PrecedentRef: &original.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	signals, err := testDB.GetDecisionOutcomeSignals(ctx, original.ID, original.OrgID)
 	require.NoError(t, err)
-	_ = decisions
+	assert.GreaterOrEqual(t, signals.PrecedentCitationCount, 2, "should count at least 2 citations")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListGrants — default limit and filter branches
+// Tests: NewConflictsSinceByOrg (71.4%)
 // ---------------------------------------------------------------------------
 
-func TestListGrants_DefaultLimit(t *testing.T) {
-	ctx := context.Background()
-	grants, _, err := testDB.ListGrants(ctx, uuid.Nil, 0, 0)
-	require.NoError(t, err)
-	_ = grants
-}
-
-func TestListGrants_LargeLimit(t *testing.T) {
+func TestNewConflictsSinceByOrg_Empty(t *testing.T) {
 	ctx := context.Background()
-	grants, _, err := testDB.ListGrants(ctx, uuid.Nil, 5000, 0)
+	// Far future cutoff should return 0 new conflicts.
+	counts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, time.Now().UTC().Add(24*time.Hour), 10)
 	require.NoError(t, err)
-	_ = grants
+	_ = counts
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CountEligibleDecisions — with exclude types
+// Tests: FindDecisionsMissingOutcomeEmbedding (76.9% -> default limit)
 // ---------------------------------------------------------------------------
 
-func TestCountEligibleDecisions_WithDecisionTypeFilter(t *testing.T) {
-	ctx := context.Background()
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	dt := "architecture"
-	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, &dt, nil)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
-}
-
-func TestCountEligibleDecisions_WithAgentFilter(t *testing.T) {
+func TestFindDecisionsMissingOutcomeEmbedding_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	agent := "some-agent"
-	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, nil, &agent)
+	results, err := testDB.FindDecisionsMissingOutcomeEmbedding(ctx, 0)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
+	_ = results
 }
 
 // ---------------------------------------------------------------------------
-// Tests: FindEmbeddedDecisionIDs — with limit branch
+// Tests: TouchAPIKeyLastUsed (75%)
 // ---------------------------------------------------------------------------
 
-func TestFindEmbeddedDecisionIDs_WithLimit(t *testing.T) {
+func TestTouchAPIKeyLastUsed_NonExistent(t *testing.T) {
 	ctx := context.Background()
-	ids, err := testDB.FindEmbeddedDecisionIDs(ctx, 5)
+	// Touching a nonexistent key should not error (fire-and-forget pattern).
+	err := testDB.TouchAPIKeyLastUsed(ctx, uuid.Nil, uuid.New())
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(ids), 5)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: DropEventChunks — exercising the function
+// Tests: InsertEvent (75%)
 // ---------------------------------------------------------------------------
 
-func TestDropEventChunks_FarPast(t *testing.T) {
-	ctx := context.Background()
-	// A far-past cutoff should not drop anything.
-	dropped, err := testDB.DropEventChunks(ctx, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
-	require.NoError(t, err)
-	assert.Equal(t, int64(0), dropped)
-}
-
-func TestDropEventChunks_AuditLogPopulated(t *testing.T) {
+func TestInsertEvent_Success(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "chunk-audit-" + suffix
+	agentID := "insert-event-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	// Insert events with occurred_at 5 days ago so they land in an old chunk.
-	// TimescaleDB chunk interval is 1 day, so a 5-day-old event is in a chunk
-	// whose range_end is at most 4 days ago.
-	oldTime := time.Now().UTC().Add(-5 * 24 * time.Hour)
-	events := make([]model.AgentEvent, 3)
-	for i := range events {
-		events[i] = model.AgentEvent{
-			ID:          uuid.New(),
-			RunID:       run.ID,
-			OrgID:       uuid.Nil, // test org
-			EventType:   model.EventDecisionMade,
-			SequenceNum: int64(900_000 + i),
-			OccurredAt:  oldTime.Add(time.Duration(i) * time.Minute),
-			AgentID:     agentID,
-			Payload:     map[string]any{"test": true},
-			CreatedAt:   oldTime.Add(time.Duration(i) * time.Minute),
-		}
+	event := model.AgentEvent{
+		ID:          uuid.New(),
+		RunID:       run.ID,
+		OrgID:       run.OrgID,
+		EventType:   model.EventDecisionMade,
+		SequenceNum: 1,
+		OccurredAt:  time.Now().UTC(),
+		AgentID:     agentID,
+		Payload:     map[string]any{"test": true},
+		CreatedAt:   time.Now().UTC(),
 	}
-	_, err = testDB.InsertEvents(ctx, events)
-	require.NoError(t, err)
 
-	// Record deletion_audit_log count before the drop so we can assert on net-new rows.
-	var beforeCount int64
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM deletion_audit_log WHERE table_name = 'agent_events'`,
-	).Scan(&beforeCount)
+	err = testDB.InsertEvent(ctx, event)
 	require.NoError(t, err)
 
-	// Drop chunks older than 3 days ago. The 5-day-old chunk qualifies.
-	cutoff := time.Now().UTC().Add(-3 * 24 * time.Hour)
-	dropped, err := testDB.DropEventChunks(ctx, cutoff)
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
 
-	if dropped == 0 {
-		t.Skip("TimescaleDB did not create a separate chunk for the old events (chunk interval may exceed test window)")
-	}
-
-	// Verify that deletion_audit_log was populated with summary records.
-	var afterCount int64
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT COUNT(*) FROM deletion_audit_log WHERE table_name = 'agent_events'`,
-	).Scan(&afterCount)
-	require.NoError(t, err)
-	assert.Greater(t, afterCount, beforeCount, "DropEventChunks should write audit log entries")
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionOutcomeSignals with conflict fate data
+// ---------------------------------------------------------------------------
 
-	// Verify the audit log entries contain the expected fields.
-	var recordData map[string]any
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT record_data FROM deletion_audit_log
-		 WHERE table_name = 'agent_events'
-		 ORDER BY id DESC LIMIT 1`,
-	).Scan(&recordData)
+func TestGetDecisionOutcomeSignals_WithConflictFate(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentA := "fate-a-" + suffix
+	agentB := "fate-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	assert.Equal(t, "retention_chunk_drop", recordData["operation"], "operation field")
-	assert.NotNil(t, recordData["chunk_name"], "chunk_name field")
-	assert.NotNil(t, recordData["event_count"], "event_count field")
-	assert.NotNil(t, recordData["min_occurred_at"], "min_occurred_at field")
-	assert.NotNil(t, recordData["max_occurred_at"], "max_occurred_at field")
-	assert.NotNil(t, recordData["event_types"], "event_types field")
-	assert.NotNil(t, recordData["cutoff"], "cutoff field")
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: "fate_test", Outcome: "winner approach",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: "fate_test", Outcome: "loser approach",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	// event_count should be a positive number.
-	eventCount, ok := recordData["event_count"].(float64)
-	assert.True(t, ok, "event_count should be a number")
-	assert.Greater(t, eventCount, float64(0), "event_count should be positive")
+	topicSim := 0.9
+	outcomeDiv := 0.8
+	sig := 0.72
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: dA.OrgID, ConflictKind: "cross_agent",
+		AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "fate_test", DecisionTypeB: "fate_test",
+		OutcomeA: "winner approach", OutcomeB: "loser approach",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "embedding",
+	})
+	require.NoError(t, err)
+
+	// Resolve with dA as winner.
+	resNote := "agentA wins"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, dA.OrgID,
+		"resolved", "test", &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			OrgID: dA.OrgID, ActorAgentID: "test", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+
+	// Check dA signals — should show Won=1.
+	signalsA, err := testDB.GetDecisionOutcomeSignals(ctx, dA.ID, dA.OrgID)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, signalsA.ConflictFate.Won+signalsA.ConflictFate.Lost, 1,
+		"decision should appear in at least one resolved conflict")
+
+	// Check dB signals — should show Lost=1.
+	signalsB, err := testDB.GetDecisionOutcomeSignals(ctx, dB.ID, dB.OrgID)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, signalsB.ConflictFate.Won+signalsB.ConflictFate.Lost, 1,
+		"decision should appear in at least one resolved conflict")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetActiveAPIKeysByAgentIDGlobal — coverage
+// Tests: RetractDecision (79.5% -> cover not-found)
 // ---------------------------------------------------------------------------
 
-func TestGetActiveAPIKeysByAgentIDGlobal_NoKeys(t *testing.T) {
+func TestRetractDecision_NotFound(t *testing.T) {
 	ctx := context.Background()
-	keys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, "nonexistent-"+uuid.New().String()[:8])
-	require.NoError(t, err)
-	assert.Empty(t, keys)
+	err := testDB.RetractDecision(ctx, uuid.Nil, uuid.New(), "test reason", "test-agent", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListAPIKeys — with agent filter
+// Tests: EraseDecision (76.2% -> cover not-found)
 // ---------------------------------------------------------------------------
 
-func TestGetActiveAPIKeysByAgentIDGlobal_NoMatch(t *testing.T) {
+func TestEraseDecision_NotFound(t *testing.T) {
 	ctx := context.Background()
-	keys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, "no-keys-agent-"+uuid.New().String()[:8])
-	require.NoError(t, err)
-	assert.Empty(t, keys)
+	_, err := testDB.EraseDecision(ctx, uuid.Nil, uuid.New(), "test reason", "test-agent", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: InsertEventsIdempotent — full lifecycle with duplicates
+// Tests: CreateAlternativesBatch (89.5% -> hit the nil metadata branch)
 // ---------------------------------------------------------------------------
 
-func TestInsertEventsIdempotent_WithDuplicates(t *testing.T) {
+func TestCreateAlternativesBatch_NilMetadata(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "idem-dup-" + suffix
+	agentID := "alt-nil-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	now := time.Now().UTC()
-	events := make([]model.AgentEvent, 3)
-	for i := range events {
-		events[i] = model.AgentEvent{
-			ID:          uuid.New(),
-			RunID:       run.ID,
-			EventType:   model.EventToolCallCompleted,
-			SequenceNum: int64(i + 1),
-			OccurredAt:  now,
-			AgentID:     agentID,
-			Payload:     map[string]any{"step": i},
-			CreatedAt:   now,
-		}
-	}
-
-	// Insert original events.
-	inserted, err := testDB.InsertEventsIdempotent(ctx, events)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "alt_test", Outcome: "test",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), inserted)
 
-	// Mix duplicates with one new event.
-	newEvent := model.AgentEvent{
-		ID:          uuid.New(),
-		RunID:       run.ID,
-		EventType:   model.EventToolCallCompleted,
-		SequenceNum: 4,
-		OccurredAt:  now,
-		AgentID:     agentID,
-		Payload:     map[string]any{"step": 3},
-		CreatedAt:   now,
+	alts := []model.Alternative{
+		{DecisionID: d.ID, Label: "alt A", Metadata: nil},
+		{DecisionID: d.ID, Label: "alt B", Metadata: map[string]any{"note": "best"}},
 	}
-	mixed := make([]model.AgentEvent, len(events)+1)
-	copy(mixed, events)
-	mixed[len(events)] = newEvent
+	err = testDB.CreateAlternativesBatch(ctx, alts)
+	require.NoError(t, err)
+}
 
-	inserted2, err := testDB.InsertEventsIdempotent(ctx, mixed)
+// ---------------------------------------------------------------------------
+// Tests: FindUnembeddedDecisions (76.9% -> default limit)
+// ---------------------------------------------------------------------------
+
+func TestFindUnembeddedDecisions_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
+	results, err := testDB.FindUnembeddedDecisions(ctx, 0)
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), inserted2, "only the new event should be inserted")
+	_ = results
+}
 
-	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+// ---------------------------------------------------------------------------
+// Tests: GetConflictAnalytics (77.1%)
+// ---------------------------------------------------------------------------
+
+func TestGetConflictAnalytics_Empty(t *testing.T) {
+	ctx := context.Background()
+	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.New(), storage.ConflictAnalyticsFilters{})
 	require.NoError(t, err)
-	assert.Len(t, got, 4)
+	_ = analytics
 }
 
-func TestInsertEventsIdempotent_SingleEvent(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions edge cases (78.8%)
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_WithAgentFilter(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "idem-single-" + suffix
+	agentID := "qd-filter-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	now := time.Now().UTC()
-	event := model.AgentEvent{
-		ID:          uuid.New(),
-		RunID:       run.ID,
-		EventType:   model.EventToolCallCompleted,
-		SequenceNum: 1,
-		OccurredAt:  now,
-		AgentID:     agentID,
-		Payload:     map[string]any{"step": 0},
-		CreatedAt:   now,
-	}
-
-	inserted, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{event})
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "query_test", Outcome: "filtered",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), inserted)
 
-	// Re-insert same event.
-	inserted2, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{event})
+	decisions, _, err := testDB.QueryDecisions(ctx, run.OrgID, model.QueryRequest{
+		Filters: model.QueryFilters{AgentIDs: []string{agentID}},
+		Limit:   10,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), inserted2, "duplicate should be skipped")
+	assert.NotEmpty(t, decisions, "should find at least 1 decision for this agent")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateTraceTx — with alternatives and evidence
+// Tests: SetRetentionPolicy + GetRetentionPolicy round-trip
 // ---------------------------------------------------------------------------
 
-func TestCreateTraceTx_WithAlternativesAndEvidence(t *testing.T) {
+func TestSetAndGetRetentionPolicy(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "trace-tx-full-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	traceID := "trace-" + suffix
-	sessionID := uuid.New()
-	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID:  agentID,
-		OrgID:    uuid.Nil,
-		TraceID:  &traceID,
-		Metadata: map[string]any{"test": true},
-		Decision: model.Decision{
-			DecisionType: "architecture",
-			Outcome:      "use postgres",
-			Confidence:   0.95,
-			Reasoning:    strPtr("better for ACID"),
-			Metadata:     map[string]any{},
-		},
-		Alternatives: []model.Alternative{
-			{Label: "MySQL"},
-			{Label: "Postgres"},
-		},
-		Evidence: []model.Evidence{
-			{SourceType: model.SourceAPIResponse, Content: "benchmarks show...", Metadata: map[string]any{}},
-		},
-		SessionID:    &sessionID,
-		AgentContext: map[string]any{"task": "db-selection"},
-	})
+	days := 30
+	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"architecture"})
 	require.NoError(t, err)
-	assert.Equal(t, agentID, run.AgentID)
-	assert.Equal(t, model.RunStatusCompleted, run.Status)
-	assert.NotNil(t, run.CompletedAt)
-	assert.Equal(t, "architecture", dec.DecisionType)
-	assert.Equal(t, "use postgres", dec.Outcome)
-	assert.NotEqual(t, uuid.Nil, dec.ID)
 
-	// Verify alternatives were created.
-	alts, err := testDB.GetAlternativesByDecision(ctx, dec.ID, uuid.Nil)
+	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, 24*time.Hour)
 	require.NoError(t, err)
-	assert.Len(t, alts, 2)
+	require.NotNil(t, policy.RetentionDays)
+	assert.Equal(t, 30, *policy.RetentionDays)
 
-	// Verify evidence was created.
-	evs, err := testDB.GetEvidenceByDecision(ctx, dec.ID, uuid.Nil)
+	// Clean up: reset to nil.
+	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
 	require.NoError(t, err)
-	assert.Len(t, evs, 1)
 }
 
-func TestCreateTraceTx_WithAuditEntry(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "trace-tx-audit-" + suffix
+// ---------------------------------------------------------------------------
+// Tests: CompleteRun — not-found and nil-metadata paths
+// ---------------------------------------------------------------------------
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+func TestCompleteRun_NotFound(t *testing.T) {
+	ctx := context.Background()
+	err := testDB.CompleteRun(ctx, uuid.Nil, uuid.New(), model.RunStatusCompleted, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestCompleteRun_NilMetadata(t *testing.T) {
+	ctx := context.Background()
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "complete-nilmeta-" + uuid.New().String()[:8]})
 	require.NoError(t, err)
 
-	audit := &storage.MutationAuditEntry{
-		RequestID:    "req-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: agentID,
-		ActorRole:    "agent",
-		HTTPMethod:   "POST",
-		Endpoint:     "/v1/trace",
-		Operation:    "create",
-		ResourceType: "decision",
-	}
+	err = testDB.CompleteRun(ctx, run.OrgID, run.ID, model.RunStatusFailed, nil)
+	require.NoError(t, err)
 
-	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: agentID,
-		OrgID:   uuid.Nil,
-		Decision: model.Decision{
-			DecisionType: "tool_choice",
-			Outcome:      "use grep",
-			Confidence:   0.8,
-			Metadata:     map[string]any{},
-		},
-		AuditEntry: audit,
-	})
+	got, err := testDB.GetRun(ctx, run.OrgID, run.ID)
 	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusCompleted, run.Status)
-	assert.NotEqual(t, uuid.Nil, dec.ID)
+	assert.Equal(t, model.RunStatusFailed, got.Status)
+	assert.NotNil(t, got.CompletedAt)
 }
 
-func TestCreateTraceTx_MinimalDecision(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: ListRunsByAgent — boundary parameters
+// ---------------------------------------------------------------------------
+
+func TestListRunsByAgent_ZeroLimit(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "trace-tx-min-" + suffix
+	agentID := "runs-zero-" + uuid.New().String()[:8]
+	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, runs, 1)
+}
+
+func TestListRunsByAgent_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	agentID := "runs-large-" + uuid.New().String()[:8]
+	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: agentID,
-		OrgID:   uuid.Nil,
-		Decision: model.Decision{
-			DecisionType: "test",
-			Outcome:      "minimal",
-			Confidence:   0.5,
-		},
-	})
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 5000, 0)
 	require.NoError(t, err)
-	assert.Equal(t, model.RunStatusCompleted, run.Status)
-	assert.NotEqual(t, uuid.Nil, dec.ID)
-	assert.NotNil(t, dec.Metadata, "nil metadata should be defaulted to empty map")
+	assert.Equal(t, 1, total)
+	assert.Len(t, runs, 1)
+}
+
+func TestListRunsByAgent_NegativeOffset(t *testing.T) {
+	ctx := context.Background()
+	agentID := "runs-negoff-" + uuid.New().String()[:8]
+	_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 10, -5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, runs, 1)
+}
+
+func TestListRunsByAgent_Empty(t *testing.T) {
+	ctx := context.Background()
+	agentID := "runs-empty-" + uuid.New().String()[:8]
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, runs)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: DeleteAgentData — with audit entry
+// Tests: GetRetentionPolicy — with deletion log
 // ---------------------------------------------------------------------------
 
-func TestDeleteAgentData_WithAuditEntry(t *testing.T) {
+func TestGetRetentionPolicy_WithDeletionLog(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "delete-audit-" + suffix
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "retention-test-"+suffix, "retention-test-"+suffix)
+	require.NoError(t, err)
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+	days := 90
+	err = testDB.SetRetentionPolicy(ctx, orgID, &days, nil)
 	require.NoError(t, err)
 
-	audit := &storage.MutationAuditEntry{
-		RequestID:    "req-del-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: "admin",
-		ActorRole:    "platform_admin",
-		HTTPMethod:   "DELETE",
-		Endpoint:     "/v1/agents/" + agentID,
-		Operation:    "delete",
-		ResourceType: "agent",
-	}
+	started := time.Now().UTC().Add(-2 * time.Hour)
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO deletion_log (id, org_id, trigger, started_at, completed_at, deleted_counts, criteria)
+		 VALUES ($1, $2, 'policy', $3, $4, '{"decisions": 42}', '{}')`,
+		uuid.New(), orgID, started, time.Now().UTC())
+	require.NoError(t, err)
 
-	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentID, audit)
+	policy, err := testDB.GetRetentionPolicy(ctx, orgID, 24*time.Hour)
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), result.Agents)
+	require.NotNil(t, policy.RetentionDays)
+	assert.Equal(t, 90, *policy.RetentionDays)
+	require.NotNil(t, policy.LastRunAt, "should populate LastRunAt from deletion_log")
+	require.NotNil(t, policy.LastRunDeleted)
+	assert.Equal(t, 42, *policy.LastRunDeleted)
+	require.NotNil(t, policy.NextRunAt, "should compute NextRunAt from interval")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionOutcomeSignals — all three signal types populated
-// ---------------------------------------------------------------------------
-
-func TestGetDecisionOutcomeSignals_AllSignals(t *testing.T) {
+func TestGetRetentionPolicy_ZeroInterval(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "signals-all-" + suffix
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "retention-zero-"+suffix, "retention-zero-"+suffix)
+	require.NoError(t, err)
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+	days := 30
+	err = testDB.SetRetentionPolicy(ctx, orgID, &days, nil)
 	require.NoError(t, err)
 
-	// Create a decision to query signals for.
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO deletion_log (id, org_id, trigger, started_at, completed_at, deleted_counts, criteria)
+		 VALUES ($1, $2, 'policy', $3, $4, '{"decisions": 10}', '{}')`,
+		uuid.New(), orgID, time.Now().UTC().Add(-1*time.Hour), time.Now().UTC())
 	require.NoError(t, err)
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
+	policy, err := testDB.GetRetentionPolicy(ctx, orgID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, policy.LastRunAt)
+	assert.Nil(t, policy.NextRunAt, "zero interval should not set NextRunAt")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionsByAgent — boundary params
+// ---------------------------------------------------------------------------
+
+func TestGetDecisionsByAgent_ZeroLimit(t *testing.T) {
+	ctx := context.Background()
+	agentID := "agent-zerolim-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	_, err = testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "signals_test", Outcome: "test outcome",
-		Confidence: 0.7, Metadata: map[string]any{},
+		DecisionType: "test", Outcome: "result",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Create a superseding decision.
-	run2, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 0, 0, nil, nil)
 	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, decisions, 1)
+}
 
-	supersedesID := dec.ID
+func TestGetDecisionsByAgent_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	agentID := "agent-largelim-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run2.ID, AgentID: agentID,
-		DecisionType: "signals_test", Outcome: "revised outcome",
-		Confidence: 0.9, Metadata: map[string]any{},
-		SupersedesID: &supersedesID,
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "test", Outcome: "result",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Create a citing decision.
-	run3, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 5000, 0, nil, nil)
 	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, decisions, 1)
+}
 
-	precedentRef := dec.ID
+func TestGetDecisionsByAgent_NegativeOffset(t *testing.T) {
+	ctx := context.Background()
+	agentID := "agent-negoff-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run3.ID, AgentID: agentID,
-		DecisionType: "signals_test", Outcome: "citing outcome",
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "test", Outcome: "result",
 		Confidence: 0.8, Metadata: map[string]any{},
-		PrecedentRef: &precedentRef,
 	})
 	require.NoError(t, err)
 
-	signals, err := testDB.GetDecisionOutcomeSignals(ctx, dec.ID, uuid.Nil)
+	decisions, total, err := testDB.GetDecisionsByAgent(ctx, run.OrgID, agentID, 10, -3, nil, nil)
 	require.NoError(t, err)
-	assert.NotNil(t, signals.SupersessionVelocityHours, "should have supersession velocity")
-	assert.GreaterOrEqual(t, signals.PrecedentCitationCount, 1, "should have at least one citation")
+	assert.Equal(t, 1, total)
+	assert.Len(t, decisions, 1)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetCitationPercentilesForOrg — with multiple citation levels
+// Tests: FindRetriableClaimFailures — with actual data
 // ---------------------------------------------------------------------------
 
-func TestGetCitationPercentilesForOrg_MultipleCitations(t *testing.T) {
+func TestFindRetriableClaimFailures_WithData(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "cite-pctl-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	agentID := "retry-claims-" + suffix
 
-	// Create a base decision that will be cited.
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	baseDec, err := testDB.CreateDecision(ctx, model.Decision{
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "pctl_test", Outcome: "base",
-		Confidence: 0.9, Metadata: map[string]any{},
+		DecisionType: "retry_test", Outcome: "will fail claims",
+		Confidence: 0.7, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Create several citing decisions.
-	for i := 0; i < 5; i++ {
-		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-		require.NoError(t, err)
+	emb := pgvector.NewVector(make([]float32, 1024))
+	err = testDB.BackfillEmbedding(ctx, dec.ID, dec.OrgID, emb, "")
+	require.NoError(t, err)
 
-		ref := baseDec.ID
-		_, err = testDB.CreateDecision(ctx, model.Decision{
-			RunID: r.ID, AgentID: agentID,
-			DecisionType: "pctl_test", Outcome: fmt.Sprintf("citing %d", i),
-			Confidence: 0.8, Metadata: map[string]any{},
-			PrecedentRef: &ref,
-		})
-		require.NoError(t, err)
-	}
+	err = testDB.MarkClaimEmbeddingFailed(ctx, dec.ID, dec.OrgID)
+	require.NoError(t, err)
 
-	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
+	_, err = testDB.Pool().Exec(ctx,
+		`UPDATE decisions SET claim_embeddings_failed_at = now() - interval '1 hour'
+		 WHERE id = $1 AND org_id = $2`, dec.ID, dec.OrgID)
 	require.NoError(t, err)
-	// With data, should return percentile breakpoints.
-	assert.NotNil(t, breakpoints, "should return percentile breakpoints when citations exist")
-	assert.Len(t, breakpoints, 4, "should return [p25, p50, p75, p90]")
+
+	refs, err := testDB.FindRetriableClaimFailures(ctx, 3, 50)
+	require.NoError(t, err)
+
+	found := false
+	for _, r := range refs {
+		if r.ID == dec.ID {
+			found = true
+			assert.Equal(t, dec.OrgID, r.OrgID)
+			assert.Equal(t, 1, r.Attempts)
+			break
+		}
+	}
+	assert.True(t, found, "expected to find the failed decision in retriable results")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CleanupIdempotencyKeys — exercising the cleanup
+// Tests: GetConflictAnalytics — broader time window, structure validation
 // ---------------------------------------------------------------------------
 
-func TestCleanupIdempotencyKeys_RemovesExpired(t *testing.T) {
+func TestGetConflictAnalytics_StructureValidation(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	// Create an in-progress key.
-	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, "cleanup-agent-"+suffix, "/v1/trace", "cleanup-key-"+suffix, "hash1")
-	require.NoError(t, err)
-
-	// Cleanup with a very short TTL should remove it.
-	// The key was just created, so with a 0 TTL it should be removed.
-	deleted, err := testDB.CleanupIdempotencyKeys(ctx, 0, 0)
+	now := time.Now().UTC()
+	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From: now.Add(-7 * 24 * time.Hour),
+		To:   now,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, deleted, int64(1), "should remove at least the in-progress key")
+	assert.NotNil(t, analytics.ByAgentPair)
+	assert.NotNil(t, analytics.ByDecisionType)
+	assert.NotNil(t, analytics.BySeverity)
+	assert.NotNil(t, analytics.Trend)
+	assert.Len(t, analytics.Trend, 7, "should have one trend point per day")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CompleteIdempotency — full lifecycle
+// Tests: GetOutcomeSignalsSummary
 // ---------------------------------------------------------------------------
 
-func TestCompleteIdempotency_FullLifecycle(t *testing.T) {
+func TestGetOutcomeSignalsSummary_Populated(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	summary, err := testDB.GetOutcomeSignalsSummary(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, summary.DecisionsTotal, 0)
+	assert.GreaterOrEqual(t, summary.NeverSuperseded, 0)
+	assert.GreaterOrEqual(t, summary.NeverCited, 0)
+	assert.GreaterOrEqual(t, summary.ConflictsWon, 0)
+	assert.Equal(t, summary.ConflictsWon, summary.ConflictsLost, "wins and losses should be symmetric")
+}
 
-	agentID := "idemp-lifecycle-" + suffix
-	endpoint := "/v1/trace"
-	key := "idemp-key-" + suffix
-	hash := "hash-" + suffix
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionQualityStats
+// ---------------------------------------------------------------------------
 
-	// Begin: should succeed (new key).
-	lookup, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
+func TestGetDecisionQualityStats_Structure(t *testing.T) {
+	ctx := context.Background()
+	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
 	require.NoError(t, err)
-	assert.False(t, lookup.Completed)
+	assert.GreaterOrEqual(t, stats.Total, 0)
+}
 
-	// Complete: mark the key as done.
-	err = testDB.CompleteIdempotency(ctx, uuid.Nil, agentID, endpoint, key, 201, map[string]any{"id": "dec-123"})
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: SearchDecisionsByText — FTS error triggers ILIKE fallback
+// ---------------------------------------------------------------------------
 
-	// Begin again with same hash: should return completed lookup.
-	lookup, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
+func TestSearchDecisionsByText_MalformedQuery(t *testing.T) {
+	ctx := context.Background()
+	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, `"unclosed quote`, model.QueryFilters{}, 10)
 	require.NoError(t, err)
-	assert.True(t, lookup.Completed)
-	assert.Equal(t, 201, lookup.StatusCode)
-	assert.NotEmpty(t, lookup.ResponseData)
+	_ = results
 }
 
-func TestBeginIdempotency_PayloadMismatch(t *testing.T) {
+func TestSearchDecisionsByText_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	agentID := "idemp-mismatch-" + suffix
-	endpoint := "/v1/trace"
-	key := "idemp-mismatch-key-" + suffix
-
-	// Create initial key.
-	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, "hash-a")
+	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "test", model.QueryFilters{}, 0)
 	require.NoError(t, err)
+	_ = results
+}
 
-	// Complete it.
-	err = testDB.CompleteIdempotency(ctx, uuid.Nil, agentID, endpoint, key, 200, map[string]any{})
+func TestSearchDecisionsByText_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "test", model.QueryFilters{}, 5000)
 	require.NoError(t, err)
-
-	// Retry with different hash: should return ErrIdempotencyPayloadMismatch.
-	_, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, "hash-b")
-	require.ErrorIs(t, err, storage.ErrIdempotencyPayloadMismatch)
+	_ = results
 }
 
-func TestBeginIdempotency_InProgress(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: ExportDecisionsCursor — with filters
+// ---------------------------------------------------------------------------
+
+func TestExportDecisionsCursor_WithFilters(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-
-	agentID := "idemp-inprog-" + suffix
-	endpoint := "/v1/trace"
-	key := "idemp-inprog-key-" + suffix
-	hash := "hash-inprog-" + suffix
-
-	// First call owns processing.
-	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
+	agentID := "export-filter-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "export_type_" + suffix, Outcome: "exported",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
 
-	// Second call with same hash should get ErrIdempotencyInProgress.
-	_, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
-	require.ErrorIs(t, err, storage.ErrIdempotencyInProgress)
+	decType := "export_type_" + suffix
+	decisions, err := testDB.ExportDecisionsCursor(ctx, run.OrgID,
+		model.QueryFilters{DecisionType: &decType}, nil, 10)
+	require.NoError(t, err)
+	assert.Len(t, decisions, 1)
+	assert.Equal(t, "exported", decisions[0].Outcome)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetEventsByRun — with explicit limit
+// Tests: GetDecisionErasure — found path
 // ---------------------------------------------------------------------------
 
-func TestGetEventsByRun_WithExplicitLimit(t *testing.T) {
+func TestGetDecisionErasure_Found(t *testing.T) {
 	ctx := context.Background()
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "events-limit-test"})
+	suffix := uuid.New().String()[:8]
+	agentID := "erasure-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-
-	now := time.Now().UTC()
-	for i := 0; i < 5; i++ {
-		err = testDB.InsertEvent(ctx, model.AgentEvent{
-			ID: uuid.New(), RunID: run.ID, OrgID: run.OrgID,
-			EventType: model.EventDecisionStarted, SequenceNum: int64(i + 1),
-			OccurredAt: now, AgentID: "events-limit-test",
-			Payload: map[string]any{}, CreatedAt: now,
-		})
-		require.NoError(t, err)
-	}
-
-	// Fetch with limit of 3.
-	events, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 3)
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "erasure_test", Outcome: "to be erased",
+		Confidence: 0.5, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Len(t, events, 3)
 
-	// Fetch with limit of 0 (should use default).
-	events, err = testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	_, err = testDB.EraseDecision(ctx, dec.OrgID, dec.ID, "gdpr", agentID, nil)
 	require.NoError(t, err)
-	assert.Len(t, events, 5)
 
-	// Fetch with negative limit (should use default).
-	events, err = testDB.GetEventsByRun(ctx, run.OrgID, run.ID, -1)
+	erasure, err := testDB.GetDecisionErasure(ctx, dec.OrgID, dec.ID)
 	require.NoError(t, err)
-	assert.Len(t, events, 5)
+	assert.Equal(t, "gdpr", erasure.Reason)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetGrant — not found
+// Tests: CountConflicts — with severity and category filters
 // ---------------------------------------------------------------------------
 
-func TestGetGrant_NotFound(t *testing.T) {
+func TestCountConflicts_WithSeverity(t *testing.T) {
 	ctx := context.Background()
-	_, err := testDB.GetGrant(ctx, uuid.Nil, uuid.New())
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
+	sev := "high"
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{Severity: &sev})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 0)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetOrganization — found after EnsureDefaultOrg
-// ---------------------------------------------------------------------------
-
-func TestGetOrganization_AfterEnsureDefault(t *testing.T) {
+func TestCountConflicts_WithCategory(t *testing.T) {
 	ctx := context.Background()
-	err := testDB.EnsureDefaultOrg(ctx)
-	require.NoError(t, err)
-
-	org, err := testDB.GetOrganization(ctx, uuid.Nil)
+	cat := "architectural"
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{Category: &cat})
 	require.NoError(t, err)
-	assert.Equal(t, uuid.Nil, org.ID)
-	assert.Equal(t, "Default", org.Name)
+	assert.GreaterOrEqual(t, count, 0)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: UpdateAgentTags — not found
+// Tests: DeleteAgentData — with comprehensive data and audit
 // ---------------------------------------------------------------------------
 
-func TestUpdateAgentTags_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.UpdateAgentTags(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8], []string{"tag1"})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-func TestUpdateAgentTags_NilTags(t *testing.T) {
+func TestDeleteAgentData_WithAllRelatedData(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "tags-nil-" + suffix
+	agentID := "delete-full-" + suffix
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
+	agent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Nil tags should be converted to empty slice.
-	agent, err := testDB.UpdateAgentTags(ctx, uuid.Nil, agentID, nil)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.NotNil(t, agent.Tags)
-	assert.Empty(t, agent.Tags)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: UpdateAgentWithAudit — not found
-// ---------------------------------------------------------------------------
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "delete_full_test", Outcome: "will be removed",
+		Confidence: 0.6, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-func TestUpdateAgentWithAudit_NotFound(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	name := "new-name"
-	_, err := testDB.UpdateAgentWithAudit(ctx, uuid.Nil, "nonexistent-"+suffix, &name, nil, storage.MutationAuditEntry{
-		RequestID:    "req-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: "admin",
-		ActorRole:    "platform_admin",
-		HTTPMethod:   "PATCH",
-		Endpoint:     "/v1/agents/x",
-		Operation:    "update",
-		ResourceType: "agent",
+	_, err = testDB.CreateEvidence(ctx, model.Evidence{
+		DecisionID: dec.ID, OrgID: dec.OrgID,
+		SourceType: model.SourceAPIResponse, Content: "evidence content",
 	})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: RevokeAPIKeyWithAudit — already revoked
-// ---------------------------------------------------------------------------
+	require.NoError(t, err)
 
-func TestRevokeAPIKeyWithAudit_AlreadyRevoked(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "revoke-twice-" + suffix
+	err = testDB.CreateAlternativesBatch(ctx, []model.Alternative{
+		{DecisionID: dec.ID, Label: "alternative A"},
+	})
+	require.NoError(t, err)
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
+	// Create an assessment so the trigger bypass (SET LOCAL akashi.allow_assessment_delete)
+	// is exercised against a live row, not just zero-match.
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      dec.ID,
+		AssessorAgentID: "reviewer-" + suffix,
+		Outcome:         model.AssessmentCorrect,
 	})
 	require.NoError(t, err)
 
-	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
-		Prefix:    "ak_" + suffix[:6],
-		KeyHash:   "hash-" + suffix,
-		AgentID:   agentID,
-		OrgID:     uuid.Nil,
-		Label:     "test key",
-		CreatedBy: agentID,
-	}, storage.MutationAuditEntry{
-		RequestID: "req-create-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: agentID, ActorRole: "platform_admin",
-		HTTPMethod: "POST", Endpoint: "/v1/api-keys",
-		Operation: "create", ResourceType: "api_key",
+	now := time.Now().UTC()
+	err = testDB.InsertEvent(ctx, model.AgentEvent{
+		ID: uuid.New(), RunID: run.ID, OrgID: run.OrgID,
+		EventType: model.EventDecisionStarted, SequenceNum: 1,
+		OccurredAt: now, AgentID: agentID,
+		Payload: map[string]any{}, CreatedAt: now,
 	})
 	require.NoError(t, err)
 
-	audit := storage.MutationAuditEntry{
-		RequestID: "req-revoke-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: agentID, ActorRole: "platform_admin",
-		HTTPMethod: "DELETE", Endpoint: "/v1/api-keys/" + key.ID.String(),
-		Operation: "revoke", ResourceType: "api_key",
-	}
+	resID := agentID
+	_, err = testDB.CreateGrant(ctx, model.AccessGrant{
+		OrgID: uuid.Nil, GrantorID: agent.ID, GranteeID: agent.ID,
+		ResourceType: "agent_traces", ResourceID: &resID,
+		Permission: "read",
+	})
+	require.NoError(t, err)
 
-	// First revocation should succeed.
-	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, audit)
+	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentID, nil)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.Decisions, int64(1))
+	assert.GreaterOrEqual(t, result.Evidence, int64(1))
+	assert.GreaterOrEqual(t, result.Alternatives, int64(1))
+	assert.GreaterOrEqual(t, result.Assessments, int64(1), "assessments should be explicitly deleted")
+	assert.GreaterOrEqual(t, result.Events, int64(1))
+	assert.GreaterOrEqual(t, result.Runs, int64(1))
+	assert.GreaterOrEqual(t, result.Grants, int64(1))
+	assert.GreaterOrEqual(t, result.Agents, int64(1))
 
-	// Second revocation should fail with "already revoked".
-	audit.RequestID = "req-revoke2-" + suffix
-	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, audit)
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "already revoked")
+	// Verify the assessment was archived to deletion_audit_log before deletion.
+	var archived int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM deletion_audit_log
+		 WHERE org_id = $1 AND table_name = 'decision_assessments'
+		   AND record_data->>'assessor_agent_id' = $2`,
+		uuid.Nil, "reviewer-"+suffix,
+	).Scan(&archived)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived, "assessment should be archived to deletion_audit_log")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: BatchDeleteDecisions — with decision type filter
+// Tests: DeleteAgentData — with resolved conflict resolutions
 // ---------------------------------------------------------------------------
 
-func TestBatchDeleteDecisions_WithDecisionTypeAndAgent(t *testing.T) {
+func TestDeleteAgentData_WithResolvedConflicts(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "batch-del-da-" + suffix
+	agentA := "del-conflict-a-" + suffix
+	agentB := "del-conflict-b-" + suffix
 
+	// Create two agents with decisions that will have a conflict.
 	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
+		AgentID: agentA, OrgID: uuid.Nil, Name: agentA, Role: model.RoleAgent, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
-
-	// Create some decisions.
-	for i := 0; i < 3; i++ {
-		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-		require.NoError(t, err)
-		_, err = testDB.CreateDecision(ctx, model.Decision{
-			RunID: r.ID, AgentID: agentID,
-			DecisionType: "batch_del_type", Outcome: fmt.Sprintf("outcome %d", i),
-			Confidence: 0.5, Metadata: map[string]any{},
-		})
-		require.NoError(t, err)
-	}
-
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	dt := "batch_del_type"
-	counts, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &dt, &agentID, nil, 2)
+	_, err = testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentB, OrgID: uuid.Nil, Name: agentB, Role: model.RoleAgent, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), counts.Decisions, "all 3 decisions should be deleted")
-}
 
-// ---------------------------------------------------------------------------
-// Tests: StartDeletionLog — empty initiatedBy
-// ---------------------------------------------------------------------------
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
 
-func TestStartDeletionLog_EmptyInitiatedBy(t *testing.T) {
-	ctx := context.Background()
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: "delete_cr_test", Outcome: "approach A",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	// Empty initiatedBy should be stored as NULL.
-	logID, err := testDB.StartDeletionLog(ctx, uuid.Nil, "policy", "", map[string]any{})
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: "delete_cr_test", Outcome: "approach B",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, logID)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: CreateHold and ReleaseHold — already released returns false
-// ---------------------------------------------------------------------------
+	// Insert a scored conflict between the two decisions.
+	topicSim := 0.90
+	outcomeDiv := 0.80
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     "delete_cr_test",
+		DecisionTypeB:     "delete_cr_test",
+		OutcomeA:          "approach A",
+		OutcomeB:          "approach B",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
+	})
+	require.NoError(t, err)
 
-func TestReleaseHold_AlreadyReleased(t *testing.T) {
-	ctx := context.Background()
+	// Resolve the conflict — this updates the scored_conflicts row in place.
+	resNote := "A wins because reasons"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "admin-"+suffix, &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			OrgID: uuid.Nil, ActorAgentID: "admin-" + suffix, ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		},
+	)
+	require.NoError(t, err)
 
-	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
-		OrgID:     uuid.Nil,
-		Reason:    "legal hold test",
-		HoldFrom:  time.Now().UTC().Add(-24 * time.Hour),
-		HoldTo:    time.Now().UTC().Add(24 * time.Hour),
-		CreatedBy: "admin",
+	// Re-insert the same conflict pair. The CTE in InsertScoredConflict archives
+	// the resolution to conflict_resolutions before the ON CONFLICT DO UPDATE
+	// reopens it. This is the only codepath that creates conflict_resolutions rows.
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     "delete_cr_test",
+		DecisionTypeB:     "delete_cr_test",
+		OutcomeA:          "approach A",
+		OutcomeB:          "approach B",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "text",
 	})
 	require.NoError(t, err)
 
-	// First release should succeed.
-	ok, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	// Verify the conflict_resolutions row exists before deletion.
+	var resBefore int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM conflict_resolutions WHERE conflict_id = $1`, conflictID,
+	).Scan(&resBefore)
 	require.NoError(t, err)
-	assert.True(t, ok)
+	require.Equal(t, 1, resBefore, "should have 1 archived resolution before delete")
 
-	// Second release should return false (already released).
-	ok, err = testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	// Delete agent A — this must clean up conflict_resolutions before scored_conflicts.
+	// Before this fix, the ON DELETE RESTRICT FK on conflict_resolutions.conflict_id
+	// would cause a FK violation error, rolling back the entire delete transaction.
+	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentA, nil)
+	require.NoError(t, err, "DeleteAgentData should not fail with resolved conflicts")
+	assert.GreaterOrEqual(t, result.Decisions, int64(1))
+	assert.GreaterOrEqual(t, result.ConflictResolutions, int64(1), "should delete conflict_resolutions")
+
+	// Verify the resolution was archived to deletion_audit_log.
+	var archivedRes int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM deletion_audit_log
+		 WHERE org_id = $1 AND table_name = 'conflict_resolutions'
+		   AND record_data->>'conflict_id' = $2`,
+		uuid.Nil, conflictID.String(),
+	).Scan(&archivedRes)
 	require.NoError(t, err)
-	assert.False(t, ok, "releasing an already-released hold should return false")
-}
+	assert.Equal(t, 1, archivedRes, "resolution should be archived to deletion_audit_log")
 
-func TestReleaseHold_NotFound(t *testing.T) {
-	ctx := context.Background()
-	ok, err := testDB.ReleaseHold(ctx, uuid.New(), uuid.Nil)
+	// Verify the conflict_resolutions row is gone.
+	var resAfter int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM conflict_resolutions WHERE conflict_id = $1`, conflictID,
+	).Scan(&resAfter)
 	require.NoError(t, err)
-	assert.False(t, ok)
+	assert.Equal(t, 0, resAfter, "conflict_resolutions should be deleted")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: MarkDecisionConflictScored
+// Tests: GetCitationPercentilesForOrg — with data
 // ---------------------------------------------------------------------------
 
-func TestMarkDecisionConflictScored_Success(t *testing.T) {
+func TestGetCitationPercentilesForOrg_WithCitedDecisions(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "mark-scored-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
+	agentID := "citation-perc-" + suffix
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
+	precedent, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "scored_test", Outcome: "test",
-		Confidence: 0.7, Metadata: map[string]any{},
+		DecisionType: "precedent_type", Outcome: "the precedent",
+		Confidence: 0.9, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "citing_type", Outcome: fmt.Sprintf("citing %d", i),
+			Confidence:   0.8,
+			PrecedentRef: &precedent.ID,
+			Metadata:     map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, run.OrgID)
 	require.NoError(t, err)
+	require.Len(t, breakpoints, 4)
+	for _, bp := range breakpoints {
+		assert.GreaterOrEqual(t, bp, float64(0))
+	}
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetConflictAnalytics — with all filter combinations
+// Tests: BackfillOutcomeEmbedding — success path
 // ---------------------------------------------------------------------------
 
-func TestGetConflictAnalytics_WithAgentFilter(t *testing.T) {
+func TestBackfillOutcomeEmbedding_Success(t *testing.T) {
 	ctx := context.Background()
-	agent := "analytics-agent-filter"
-	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From:    time.Now().UTC().Add(-30 * 24 * time.Hour),
-		To:      time.Now().UTC(),
-		AgentID: &agent,
-	})
+	suffix := uuid.New().String()[:8]
+	agentID := "backfill-oe-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.NotNil(t, result.ByAgentPair)
-	assert.NotNil(t, result.ByDecisionType)
-}
 
-func TestGetConflictAnalytics_WithDecisionTypeFilter(t *testing.T) {
-	ctx := context.Background()
-	dt := "architecture"
-	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
-		To:           time.Now().UTC(),
-		DecisionType: &dt,
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "backfill_oe", Outcome: "needs outcome embedding",
+		Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
-	assert.NotNil(t, result.ByAgentPair)
-}
 
-func TestGetConflictAnalytics_WithConflictKindFilter(t *testing.T) {
-	ctx := context.Background()
-	kind := "cross_agent"
-	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
-		To:           time.Now().UTC(),
-		ConflictKind: &kind,
-	})
+	emb := pgvector.NewVector(make([]float32, 1024))
+	err = testDB.BackfillEmbedding(ctx, dec.ID, dec.OrgID, emb, "")
 	require.NoError(t, err)
-	assert.NotNil(t, result.ByAgentPair)
-}
 
-func TestGetConflictAnalytics_WithAllFilters(t *testing.T) {
-	ctx := context.Background()
-	agent := "some-agent"
-	dt := "architecture"
-	kind := "cross_agent"
-	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
-		To:           time.Now().UTC(),
-		AgentID:      &agent,
-		DecisionType: &dt,
-		ConflictKind: &kind,
-	})
+	oemb := pgvector.NewVector(make([]float32, 1024))
+	err = testDB.BackfillOutcomeEmbedding(ctx, dec.ID, dec.OrgID, oemb)
 	require.NoError(t, err)
-	assert.Equal(t, 0, result.Summary.TotalDetected)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListRunsByAgent — with data
+// Tests: GetAlternativesByDecisions — empty and no-match
 // ---------------------------------------------------------------------------
 
-func TestListRunsByAgent_WithData(t *testing.T) {
+func TestGetAlternativesByDecisions_Empty(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "list-runs-" + suffix
-
-	for i := 0; i < 3; i++ {
-		_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-		require.NoError(t, err)
-	}
-
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 2, 0)
+	result, err := testDB.GetAlternativesByDecisions(ctx, []uuid.UUID{}, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, 3, total)
-	assert.Len(t, runs, 2)
+	assert.Nil(t, result)
+}
 
-	// Second page.
-	runs, total, err = testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 2, 2)
+func TestGetAlternativesByDecisions_NoMatch(t *testing.T) {
+	ctx := context.Background()
+	result, err := testDB.GetAlternativesByDecisions(ctx, []uuid.UUID{uuid.New()}, uuid.Nil)
 	require.NoError(t, err)
-	assert.Equal(t, 3, total)
-	assert.Len(t, runs, 1)
+	assert.Empty(t, result)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetDecisionForScoring — not found
+// Tests: GetEvidenceByDecision — empty
 // ---------------------------------------------------------------------------
 
-func TestGetDecisionForScoring_NotFound(t *testing.T) {
-	ctx := context.Background()
-	_, err := testDB.GetDecisionForScoring(ctx, uuid.New(), uuid.Nil)
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-func TestGetDecisionForScoring_Found(t *testing.T) {
+func TestGetEvidenceByDecision_NoEvidence(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "scoring-found-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
+	agentID := "evidence-none-" + suffix
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-
 	dec, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "scoring_test", Outcome: "test outcome",
-		Confidence: 0.9, Reasoning: strPtr("because reasons"),
-		Metadata: map[string]any{},
+		DecisionType: "no_evidence", Outcome: "has no evidence",
+		Confidence: 0.5, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	got, err := testDB.GetDecisionForScoring(ctx, dec.ID, uuid.Nil)
+	evidence, err := testDB.GetEvidenceByDecision(ctx, dec.ID, dec.OrgID, true)
 	require.NoError(t, err)
-	assert.Equal(t, dec.ID, got.ID)
-	assert.Equal(t, "scoring_test", got.DecisionType)
+	assert.Empty(t, evidence)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: InsertMutationAudit — with before and after data
+// Tests: GetAlternativesByDecision — empty
 // ---------------------------------------------------------------------------
 
-func TestInsertMutationAudit_WithBeforeAndAfterData(t *testing.T) {
+func TestGetAlternativesByDecision_NoAlternatives(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-
-	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
-		RequestID:    "req-ba-" + suffix,
-		OrgID:        uuid.Nil,
-		ActorAgentID: "admin",
-		ActorRole:    "platform_admin",
-		HTTPMethod:   "PATCH",
-		Endpoint:     "/v1/agents/test",
-		Operation:    "update",
-		ResourceType: "agent",
-		ResourceID:   "test-agent",
-		BeforeData:   map[string]any{"name": "old"},
-		AfterData:    map[string]any{"name": "new"},
-		Metadata:     map[string]any{"reason": "test"},
+	agentID := "alts-none-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "no_alts", Outcome: "has no alternatives",
+		Confidence: 0.5, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
+
+	alts, err := testDB.GetAlternativesByDecision(ctx, dec.ID, dec.OrgID)
+	require.NoError(t, err)
+	assert.Empty(t, alts)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: UpdateOutcomeScore — on a real decision
+// Tests: TouchLastSeen — non-existent
 // ---------------------------------------------------------------------------
 
-func TestUpdateOutcomeScore_Success(t *testing.T) {
+func TestTouchLastSeen_NonExistent(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "outcome-score-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "score_test", Outcome: "test",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
+	err := testDB.TouchLastSeen(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8])
 	require.NoError(t, err)
+}
 
-	score := float32(0.85)
-	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, dec.ID, &score)
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: GetEventsByRun — default limit
+// ---------------------------------------------------------------------------
 
-	// Set to nil.
-	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, dec.ID, nil)
+func TestGetEventsByRun_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
+	events, err := testDB.GetEventsByRun(ctx, uuid.Nil, uuid.New(), 0)
 	require.NoError(t, err)
+	assert.Empty(t, events)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: SetRetentionPolicy — success
+// Tests: NewConflictsSinceByOrg — default limit
 // ---------------------------------------------------------------------------
 
-func TestSetRetentionPolicy_ClearPolicy(t *testing.T) {
+func TestNewConflictsSinceByOrg_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	// Set a policy then clear it.
-	days := 30
-	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"audit"})
+	conflicts, err := testDB.NewConflictsSinceByOrg(ctx, uuid.Nil, time.Now().Add(-24*time.Hour), 0)
 	require.NoError(t, err)
+	_ = conflicts
+}
 
-	// Clear the policy.
-	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: GetResolvedConflictsByType — default limit
+// ---------------------------------------------------------------------------
 
-	// Verify it's cleared.
-	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, 24*time.Hour)
+func TestGetResolvedConflictsByType_DefaultLimitBranch(t *testing.T) {
+	ctx := context.Background()
+	results, err := testDB.GetResolvedConflictsByType(ctx, uuid.Nil, "test_type", 0)
 	require.NoError(t, err)
-	assert.Nil(t, policy.RetentionDays)
+	_ = results
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CountEligibleDecisions — with both filters
+// Tests: CreateRun — nil metadata path
 // ---------------------------------------------------------------------------
 
-func TestCountEligibleDecisions_WithBothFilters(t *testing.T) {
+func TestCreateRun_NilMetadata(t *testing.T) {
 	ctx := context.Background()
-	cutoff := time.Now().UTC().Add(1 * time.Hour)
-	dt := "architecture"
-	agent := "some-agent"
-	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, &dt, &agent)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{
+		AgentID:  "nilmeta-run-" + uuid.New().String()[:8],
+		Metadata: nil,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
+	assert.NotNil(t, run.Metadata, "nil metadata should be replaced with empty map")
+	assert.Empty(t, run.Metadata)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListGrants — with data
+// Tests: BatchDeleteDecisions — agentID and excludeTypes filter branches
 // ---------------------------------------------------------------------------
 
-func TestListGrants_WithData(t *testing.T) {
+func TestBatchDeleteDecisions_WithAgentFilter(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "bd-agent-" + suffix
+	otherAgent := "bd-other-" + suffix
+	decisionType := "bd_agent_type_" + suffix
 
-	// Create real agents for FK constraints.
-	grantorAgent, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "grantor-" + suffix, OrgID: uuid.Nil, Name: "Grantor", Role: model.RoleAdmin,
-		Metadata: map[string]any{},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	granteeAgent, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "grantee-" + suffix, OrgID: uuid.Nil, Name: "Grantee", Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+	runOther, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: otherAgent})
 	require.NoError(t, err)
 
-	for i := 0; i < 3; i++ {
-		resID := fmt.Sprintf("res-%d-%s", i, suffix)
-		_, err := testDB.CreateGrant(ctx, model.AccessGrant{
-			OrgID: uuid.Nil, GrantorID: grantorAgent.ID, GranteeID: granteeAgent.ID,
-			ResourceType: "agent_traces", ResourceID: &resID,
-			Permission: "read",
+	// Create decisions for both agents.
+	for i := range 2 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: decisionType, Outcome: fmt.Sprintf("agent_%d", i),
+			Confidence: 0.5, Metadata: map[string]any{},
 		})
 		require.NoError(t, err)
 	}
-
-	grants, total, err := testDB.ListGrants(ctx, uuid.Nil, 2, 0)
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 3)
-	assert.LessOrEqual(t, len(grants), 2)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: DeleteGrantWithAudit — not found
-// ---------------------------------------------------------------------------
-
-func TestDeleteGrantWithAudit_NotFound(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.DeleteGrantWithAudit(ctx, uuid.Nil, uuid.New(), storage.MutationAuditEntry{
-		RequestID:    "req-del-grant",
-		OrgID:        uuid.Nil,
-		ActorAgentID: "admin",
-		ActorRole:    "platform_admin",
-		HTTPMethod:   "DELETE",
-		Endpoint:     "/v1/grants/x",
-		Operation:    "delete",
-		ResourceType: "grant",
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: runOther.ID, AgentID: otherAgent,
+		DecisionType: decisionType, Outcome: "other",
+		Confidence: 0.5, Metadata: map[string]any{},
 	})
-	require.Error(t, err)
-	assert.ErrorIs(t, err, storage.ErrNotFound)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetIntegrityProof — not found
-// ---------------------------------------------------------------------------
+	require.NoError(t, err)
 
-func TestGetLatestIntegrityProof_NoProofs(t *testing.T) {
-	ctx := context.Background()
-	// Use a random org ID to avoid existing proofs.
-	proof, err := testDB.GetLatestIntegrityProof(ctx, uuid.New())
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &decisionType, &agentID, nil, 100)
 	require.NoError(t, err)
-	assert.Nil(t, proof, "should return nil when no proofs exist for the org")
+	assert.Equal(t, int64(2), result.Decisions, "should only delete agent's decisions")
 }
 
-// ---------------------------------------------------------------------------
-// Tests: CreateGrantWithAudit — full lifecycle
-// ---------------------------------------------------------------------------
-
-func TestCreateGrantWithAudit_FullCycle(t *testing.T) {
+func TestBatchDeleteDecisions_WithExcludeTypes(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "bd-excl-" + suffix
+	typeKeep := "bd_keep_" + suffix
+	typeDelete := "bd_delete_" + suffix
 
-	// Create real agents for FK constraints.
-	grantor, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "cwaudit-grantor-" + suffix, OrgID: uuid.Nil, Name: "Grantor", Role: model.RoleAdmin,
-		Metadata: map[string]any{},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	grantee, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: "cwaudit-grantee-" + suffix, OrgID: uuid.Nil, Name: "Grantee", Role: model.RoleAgent,
-		Metadata: map[string]any{},
+
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: typeKeep, Outcome: "should survive",
+		Confidence: 0.5, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	resID := "res-" + suffix
-	grant, err := testDB.CreateGrantWithAudit(ctx, model.AccessGrant{
-		OrgID: uuid.Nil, GrantorID: grantor.ID, GranteeID: grantee.ID,
-		ResourceType: "agent_traces", ResourceID: &resID,
-		Permission: "read",
-	}, storage.MutationAuditEntry{
-		RequestID: "req-grant-" + suffix, OrgID: uuid.Nil,
-		ActorAgentID: "admin", ActorRole: "platform_admin",
-		HTTPMethod: "POST", Endpoint: "/v1/grants",
-		Operation: "create", ResourceType: "grant",
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: typeDelete, Outcome: "should be deleted",
+		Confidence: 0.5, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, grant.ID)
+
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, nil, &agentID, []string{typeKeep}, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Decisions, "should only delete non-excluded type")
+}
+
+func TestBatchDeleteDecisions_DefaultBatchSize(t *testing.T) {
+	ctx := context.Background()
+	// batchSize=0 should default to 1000.
+	farPast := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	result, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, farPast, nil, nil, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.Decisions)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ReserveSequenceNums — edge cases
+// Tests: ListConflicts — status and kind filter branches
 // ---------------------------------------------------------------------------
 
-func TestReserveSequenceNums_ZeroCount(t *testing.T) {
+func TestListConflicts_StatusFilter(t *testing.T) {
 	ctx := context.Background()
-	nums, err := testDB.ReserveSequenceNums(ctx, 0)
+	status := "open"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		Status: &status,
+	}, 50, 0)
 	require.NoError(t, err)
-	assert.Nil(t, nums)
+	for _, c := range conflicts {
+		assert.Equal(t, "open", c.Status, "status filter should only return open conflicts")
+	}
 }
 
-func TestReserveSequenceNums_NegativeCount(t *testing.T) {
+func TestListConflicts_KindFilter(t *testing.T) {
 	ctx := context.Background()
-	nums, err := testDB.ReserveSequenceNums(ctx, -5)
+	kind := "cross_agent"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		ConflictKind: &kind,
+	}, 50, 0)
 	require.NoError(t, err)
-	assert.Nil(t, nums)
+	for _, c := range conflicts {
+		assert.Equal(t, model.ConflictKindCrossAgent, c.ConflictKind, "kind filter should only return cross_agent conflicts")
+	}
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionQualityStats
-// ---------------------------------------------------------------------------
-
-func TestGetDecisionQualityStats_WithDecisions(t *testing.T) {
+func TestListConflicts_DecisionIDFilter(t *testing.T) {
 	ctx := context.Background()
-	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
+	decID := uuid.New()
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		DecisionID: &decID,
+	}, 50, 0)
 	require.NoError(t, err)
-	// The default org will have decisions from other tests.
-	assert.GreaterOrEqual(t, stats.Total, 0)
+	_ = conflicts // just verify no error with this filter
 }
 
-// ---------------------------------------------------------------------------
-// Tests: Notify — exercises the success path
-// ---------------------------------------------------------------------------
-
-func TestNotify_Success(t *testing.T) {
+func TestListConflicts_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	// Notify on the decisions channel — should succeed even without listeners.
-	err := testDB.Notify(ctx, storage.ChannelDecisions, `{"test": true}`)
+	// limit=0 should default to 50.
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 0, 0)
 	require.NoError(t, err)
+	_ = conflicts
+}
 
-	err = testDB.Notify(ctx, storage.ChannelConflicts, `{"conflict": true}`)
+func TestListConflicts_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	// limit > 1000 should be capped at 1000.
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 5000, 0)
 	require.NoError(t, err)
+	_ = conflicts
 }
 
-// ---------------------------------------------------------------------------
-// Tests: CreateOrgWithOwnerAndKeyTx — verifies org, agent, and key creation
-// ---------------------------------------------------------------------------
+func TestListConflicts_NegativeOffset(t *testing.T) {
+	ctx := context.Background()
+	// negative offset should be clamped to 0.
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{}, 50, -10)
+	require.NoError(t, err)
+	_ = conflicts
+}
 
-func TestCreateOrgWithOwnerAndKeyTx_AllFieldsPopulated(t *testing.T) {
+func TestListConflictsCursor_Paginates(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	ownerAgentID := "owner-" + suffix
+	agentA := "cursor-a-" + suffix
+	agentB := "cursor-b-" + suffix
+	decisionType := "cursor_type_" + suffix
 
-	org, agent, key, err := testDB.CreateOrgWithOwnerAndKeyTx(ctx,
-		model.Organization{Name: "Test Org " + suffix, Slug: "test-org-" + suffix, Plan: "pro"},
-		model.Agent{AgentID: ownerAgentID, Name: "Owner", Role: model.RoleOrgOwner, Email: strPtr("owner@test.com")},
-		model.APIKey{Prefix: "ak_" + suffix[:6], KeyHash: "hash-signup-" + suffix, AgentID: ownerAgentID, Label: "initial key", CreatedBy: ownerAgentID},
-		storage.MutationAuditEntry{RequestID: "signup-org-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "organization"},
-		storage.MutationAuditEntry{RequestID: "signup-agent-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "agent"},
-		storage.MutationAuditEntry{RequestID: "signup-key-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "api_key"},
-	)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-	assert.NotEqual(t, uuid.Nil, org.ID)
-	assert.Equal(t, "pro", org.Plan)
-	assert.Equal(t, ownerAgentID, agent.AgentID)
-	assert.Equal(t, org.ID, agent.OrgID)
-	assert.NotEqual(t, uuid.Nil, key.ID)
-	assert.Equal(t, org.ID, key.OrgID)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	insert := func(i int) uuid.UUID {
+		dA, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: runA.ID, AgentID: agentA, DecisionType: decisionType,
+			Outcome: fmt.Sprintf("approve %d", i), Confidence: 0.8, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+		dB, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: runB.ID, AgentID: agentB, DecisionType: decisionType,
+			Outcome: fmt.Sprintf("deny %d", i), Confidence: 0.8, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+		id, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+			ConflictKind: model.ConflictKindCrossAgent,
+			DecisionAID:  dA.ID, DecisionBID: dB.ID, OrgID: uuid.Nil,
+			AgentA: agentA, AgentB: agentB,
+			DecisionTypeA: decisionType, DecisionTypeB: decisionType,
+			OutcomeA: dA.Outcome, OutcomeB: dB.Outcome,
+			ScoringMethod: "text",
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	want := make(map[uuid.UUID]bool)
+	for i := range 7 {
+		want[insert(i)] = true
+	}
+
+	filters := storage.ConflictFilters{AgentID: &agentA}
+	seen := make(map[uuid.UUID]bool)
+	var cursor *storage.ConflictCursor
+	pages := 0
+	for {
+		page, next, err := testDB.ListConflictsCursor(ctx, uuid.Nil, filters, cursor, 3)
+		require.NoError(t, err)
+		pages++
+		require.LessOrEqual(t, len(page), 3)
+		for _, c := range page {
+			assert.False(t, seen[c.ID], "conflict %s returned twice", c.ID)
+			seen[c.ID] = true
+		}
+		if pages == 1 {
+			// A conflict detected mid-pagination sorts ahead of the cursor
+			// and must not shift later pages.
+			insert(99)
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+		require.Less(t, pages, 10, "pagination did not terminate")
+	}
+
+	assert.Equal(t, 3, pages)
+	for id := range want {
+		assert.True(t, seen[id], "conflict %s was never returned", id)
+	}
+	assert.Len(t, seen, len(want))
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisions — with Include (alternatives + evidence)
+// Tests: QueryDecisions — ordering branches
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisions_IncludeBothAlternativesAndEvidence(t *testing.T) {
+func TestQueryDecisions_OrderByConfidence(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "query-include-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Limit:   10,
+		OrderBy: "confidence",
 	})
 	require.NoError(t, err)
+	_ = decisions
+}
 
-	// Create a decision with alternatives and evidence.
-	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: agentID, OrgID: uuid.Nil,
-		Decision: model.Decision{
-			DecisionType: "query_include_test_" + suffix,
-			Outcome:      "include test", Confidence: 0.8,
-			Metadata: map[string]any{},
-		},
-		Alternatives: []model.Alternative{
-			{Label: "Option A"},
-			{Label: "Option B"},
-		},
-		Evidence: []model.Evidence{
-			{SourceType: model.SourceAPIResponse, Content: "benchmark data", Metadata: map[string]any{}},
-		},
+func TestQueryDecisions_OrderByCreatedAt(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Limit:   10,
+		OrderBy: "created_at",
 	})
 	require.NoError(t, err)
-	_ = run
+	_ = decisions
+}
 
-	dt := "query_include_test_" + suffix
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{DecisionType: &dt},
-		Include: []string{"alternatives", "evidence"},
-		Limit:   10,
+func TestQueryDecisions_DescDirection(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Limit:    10,
+		OrderDir: "desc",
 	})
 	require.NoError(t, err)
-	assert.Equal(t, 1, total)
-	require.Len(t, decisions, 1)
-	assert.Equal(t, dec.ID, decisions[0].ID)
-	assert.Len(t, decisions[0].Alternatives, 2, "should include 2 alternatives")
-	assert.Len(t, decisions[0].Evidence, 1, "should include 1 evidence")
+	_ = decisions
 }
 
-// ---------------------------------------------------------------------------
-// Tests: QueryDecisions — with TraceID filter
-// ---------------------------------------------------------------------------
-
-func TestQueryDecisions_WithTraceIDFilter(t *testing.T) {
+func TestQueryDecisions_AscDirection(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "query-trace-" + suffix
-
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
-
-	traceID := "trace-" + suffix
-	_, _, err = testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
-		AgentID: agentID, OrgID: uuid.Nil, TraceID: &traceID,
-		Decision: model.Decision{
-			DecisionType: "trace_filter_test", Outcome: "traced", Confidence: 0.9,
-			Metadata: map[string]any{},
-		},
-	})
-	require.NoError(t, err)
-
-	// Query with trace ID filter.
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		TraceID: &traceID,
-		Limit:   10,
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Limit:    10,
+		OrderDir: "asc",
 	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 1)
-	assert.GreaterOrEqual(t, len(decisions), 1)
+	_ = decisions
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisions — quality_score order by (deprecated alias)
+// Tests: ListGrants — default limit and filter branches
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisions_OrderByQualityScore(t *testing.T) {
+func TestListGrants_DefaultLimit(t *testing.T) {
 	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		OrderBy: "quality_score",
-		Limit:   5,
-	})
+	grants, _, err := testDB.ListGrants(ctx, uuid.Nil, 0, 0)
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(decisions), 5)
+	_ = grants
+}
+
+func TestListGrants_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	grants, _, err := testDB.ListGrants(ctx, uuid.Nil, 5000, 0)
+	require.NoError(t, err)
+	_ = grants
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisionsTemporal — with limit edge cases
+// Tests: CountEligibleDecisions — with exclude types
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisionsTemporal_DefaultLimit(t *testing.T) {
+func TestCountEligibleDecisions_WithDecisionTypeFilter(t *testing.T) {
 	ctx := context.Background()
-	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
-		AsOf:  time.Now().UTC(),
-		Limit: 0, // should default to 100
-	})
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	dt := "architecture"
+	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, &dt, nil)
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(decisions), 100)
+	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
 }
 
-func TestQueryDecisionsTemporal_LargeLimit(t *testing.T) {
+func TestCountEligibleDecisions_WithAgentFilter(t *testing.T) {
 	ctx := context.Background()
-	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
-		AsOf:  time.Now().UTC(),
-		Limit: 5000, // should be capped to 1000
-	})
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	agent := "some-agent"
+	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, nil, &agent)
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(decisions), 1000)
+	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
 }
 
 // ---------------------------------------------------------------------------
-// Tests: Assessment outcomes — partially_correct and incorrect
+// Tests: FindEmbeddedDecisionIDs — with limit branch
 // ---------------------------------------------------------------------------
 
-func TestCreateAssessment_PartiallyCorrectAndIncorrect(t *testing.T) {
+func TestFindEmbeddedDecisionIDs_WithLimit(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "assess-variety-" + suffix
+	ids, err := testDB.FindEmbeddedDecisionIDs(ctx, 5)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(ids), 5)
+}
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+// ---------------------------------------------------------------------------
+// Tests: DropEventChunks — exercising the function
+// ---------------------------------------------------------------------------
+
+func TestDropEventChunks_FarPast(t *testing.T) {
+	ctx := context.Background()
+	// A far-past cutoff should not drop anything.
+	dropped, err := testDB.DropEventChunks(ctx, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
 	require.NoError(t, err)
+	assert.Equal(t, int64(0), dropped)
+}
+
+func TestDropEventChunks_AuditLogPopulated(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "chunk-audit-" + suffix
 
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dec, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "assess_variety_test", Outcome: "test outcome",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
+	// Insert events with occurred_at 5 days ago so they land in an old chunk.
+	// TimescaleDB chunk interval is 1 day, so a 5-day-old event is in a chunk
+	// whose range_end is at most 4 days ago.
+	oldTime := time.Now().UTC().Add(-5 * 24 * time.Hour)
+	events := make([]model.AgentEvent, 3)
+	for i := range events {
+		events[i] = model.AgentEvent{
+			ID:          uuid.New(),
+			RunID:       run.ID,
+			OrgID:       uuid.Nil, // test org
+			EventType:   model.EventDecisionMade,
+			SequenceNum: int64(900_000 + i),
+			OccurredAt:  oldTime.Add(time.Duration(i) * time.Minute),
+			AgentID:     agentID,
+			Payload:     map[string]any{"test": true},
+			CreatedAt:   oldTime.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	_, err = testDB.InsertEvents(ctx, events)
 	require.NoError(t, err)
 
-	// Create assessments with all three outcome types.
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      dec.ID,
-		AssessorAgentID: "assessor-a-" + suffix,
-		Outcome:         model.AssessmentCorrect,
-		Notes:           strPtr("looks good"),
-	})
+	// Record deletion_audit_log count before the drop so we can assert on net-new rows.
+	var beforeCount int64
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM deletion_audit_log WHERE table_name = 'agent_events'`,
+	).Scan(&beforeCount)
 	require.NoError(t, err)
 
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      dec.ID,
-		AssessorAgentID: "assessor-b-" + suffix,
-		Outcome:         model.AssessmentIncorrect,
-		Notes:           strPtr("wrong approach"),
-	})
+	// Drop chunks older than 3 days ago. The 5-day-old chunk qualifies.
+	cutoff := time.Now().UTC().Add(-3 * 24 * time.Hour)
+	dropped, err := testDB.DropEventChunks(ctx, cutoff)
 	require.NoError(t, err)
 
-	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
-		DecisionID:      dec.ID,
-		AssessorAgentID: "assessor-c-" + suffix,
-		Outcome:         model.AssessmentPartiallyCorrect,
-		Notes:           strPtr("partially right"),
-	})
+	if dropped == 0 {
+		t.Skip("TimescaleDB did not create a separate chunk for the old events (chunk interval may exceed test window)")
+	}
+
+	// Verify that deletion_audit_log was populated with summary records.
+	var afterCount int64
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT COUNT(*) FROM deletion_audit_log WHERE table_name = 'agent_events'`,
+	).Scan(&afterCount)
 	require.NoError(t, err)
+	assert.Greater(t, afterCount, beforeCount, "DropEventChunks should write audit log entries")
 
-	// Verify the summary covers all three types.
-	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, dec.ID)
+	// Verify the audit log entries contain the expected fields.
+	var recordData map[string]any
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT record_data FROM deletion_audit_log
+		 WHERE table_name = 'agent_events'
+		 ORDER BY id DESC LIMIT 1`,
+	).Scan(&recordData)
 	require.NoError(t, err)
-	assert.Equal(t, 3, summary.Total)
-	assert.Equal(t, 1, summary.Correct)
-	assert.Equal(t, 1, summary.Incorrect)
-	assert.Equal(t, 1, summary.PartiallyCorrect)
+
+	assert.Equal(t, "retention_chunk_drop", recordData["operation"], "operation field")
+	assert.NotNil(t, recordData["chunk_name"], "chunk_name field")
+	assert.NotNil(t, recordData["event_count"], "event_count field")
+	assert.NotNil(t, recordData["min_occurred_at"], "min_occurred_at field")
+	assert.NotNil(t, recordData["max_occurred_at"], "max_occurred_at field")
+	assert.NotNil(t, recordData["event_types"], "event_types field")
+	assert.NotNil(t, recordData["cutoff"], "cutoff field")
+
+	// event_count should be a positive number.
+	eventCount, ok := recordData["event_count"].(float64)
+	assert.True(t, ok, "event_count should be a number")
+	assert.Greater(t, eventCount, float64(0), "event_count should be positive")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ExportDecisionsCursor — with cursor pagination
+// Tests: GetActiveAPIKeysByAgentIDGlobal — coverage
 // ---------------------------------------------------------------------------
 
-func TestExportDecisionsCursor_WithCursorPagination(t *testing.T) {
+func TestGetActiveAPIKeysByAgentIDGlobal_NoKeys(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "export-cursor-" + suffix
+	keys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, "nonexistent-"+uuid.New().String()[:8])
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
 
-	_, err := testDB.CreateAgent(ctx, model.Agent{
-		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-		Metadata: map[string]any{},
-	})
+// ---------------------------------------------------------------------------
+// Tests: ListAPIKeys — with agent filter
+// ---------------------------------------------------------------------------
+
+func TestGetActiveAPIKeysByAgentIDGlobal_NoMatch(t *testing.T) {
+	ctx := context.Background()
+	keys, err := testDB.GetActiveAPIKeysByAgentIDGlobal(ctx, "no-keys-agent-"+uuid.New().String()[:8])
 	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
 
-	dt := "export_cursor_test_" + suffix
+// ---------------------------------------------------------------------------
+// Tests: InsertEventsIdempotent — full lifecycle with duplicates
+// ---------------------------------------------------------------------------
 
-	// Create several decisions.
-	for i := 0; i < 5; i++ {
-		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-		require.NoError(t, err)
-		_, err = testDB.CreateDecision(ctx, model.Decision{
-			RunID: r.ID, AgentID: agentID,
-			DecisionType: dt, Outcome: fmt.Sprintf("outcome %d", i),
-			Confidence: 0.5, Metadata: map[string]any{},
-		})
-		require.NoError(t, err)
-	}
+func TestInsertEventsIdempotent_WithDuplicates(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "idem-dup-" + suffix
 
-	// First page.
-	page1, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{DecisionType: &dt}, nil, 2)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Len(t, page1, 2)
 
-	// Second page using cursor from first page.
-	cursor := &storage.ExportCursor{
-		ValidFrom: page1[1].ValidFrom,
-		ID:        page1[1].ID,
-	}
-	page2, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{DecisionType: &dt}, cursor, 2)
-	require.NoError(t, err)
-	assert.Len(t, page2, 2)
-
-	// Verify no overlap between pages.
-	for _, d1 := range page1 {
-		for _, d2 := range page2 {
-			assert.NotEqual(t, d1.ID, d2.ID, "pages should not overlap")
+	now := time.Now().UTC()
+	events := make([]model.AgentEvent, 3)
+	for i := range events {
+		events[i] = model.AgentEvent{
+			ID:          uuid.New(),
+			RunID:       run.ID,
+			EventType:   model.EventToolCallCompleted,
+			SequenceNum: int64(i + 1),
+			OccurredAt:  now,
+			AgentID:     agentID,
+			Payload:     map[string]any{"step": i},
+			CreatedAt:   now,
 		}
 	}
-}
-
-// ---------------------------------------------------------------------------
-// Tests: QueryDecisions — with multiple filter types
-// ---------------------------------------------------------------------------
 
-func TestQueryDecisions_WithOutcomeFilter(t *testing.T) {
-	ctx := context.Background()
-	outcome := "nonexistent-outcome-" + uuid.New().String()[:8]
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{Outcome: &outcome},
-		Limit:   10,
-	})
+	// Insert original events.
+	inserted, err := testDB.InsertEventsIdempotent(ctx, events)
 	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
-}
+	assert.Equal(t, int64(3), inserted)
 
-func TestQueryDecisions_WithConfidenceMinFilter(t *testing.T) {
-	ctx := context.Background()
-	confMin := float32(0.99)
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{ConfidenceMin: &confMin},
-		Limit:   5,
-	})
-	require.NoError(t, err)
-	for _, d := range decisions {
-		assert.GreaterOrEqual(t, d.Confidence, float32(0.99))
+	// Mix duplicates with one new event.
+	newEvent := model.AgentEvent{
+		ID:          uuid.New(),
+		RunID:       run.ID,
+		EventType:   model.EventToolCallCompleted,
+		SequenceNum: 4,
+		OccurredAt:  now,
+		AgentID:     agentID,
+		Payload:     map[string]any{"step": 3},
+		CreatedAt:   now,
 	}
-}
-
-func TestQueryDecisions_WithTimeRangeFilter(t *testing.T) {
-	ctx := context.Background()
-	now := time.Now().UTC()
-	from := now.Add(-1 * time.Hour)
-	to := now.Add(1 * time.Hour)
-	_, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{TimeRange: &model.TimeRange{From: &from, To: &to}},
-		Limit:   5,
-	})
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 0)
-}
-
-func TestQueryDecisions_WithSessionFilter(t *testing.T) {
-	ctx := context.Background()
-	sessionID := uuid.New()
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{SessionID: &sessionID},
-		Limit:   5,
-	})
-	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
-}
+	mixed := make([]model.AgentEvent, len(events)+1)
+	copy(mixed, events)
+	mixed[len(events)] = newEvent
 
-func TestQueryDecisions_WithProjectFilter(t *testing.T) {
-	ctx := context.Background()
-	project := "nonexistent-project"
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{Project: &project},
-		Limit:   5,
-	})
+	inserted2, err := testDB.InsertEventsIdempotent(ctx, mixed)
 	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetConflictStatusCounts
-// ---------------------------------------------------------------------------
+	assert.Equal(t, int64(1), inserted2, "only the new event should be inserted")
 
-func TestGetConflictStatusCounts_StructureVerification(t *testing.T) {
-	ctx := context.Background()
-	counts, err := testDB.GetConflictStatusCounts(ctx, uuid.Nil, nil, nil)
+	got, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, counts.Total, 0)
-	assert.GreaterOrEqual(t, counts.Open, 0)
-	assert.GreaterOrEqual(t, counts.Resolved, 0)
-	assert.GreaterOrEqual(t, counts.FalsePositive, 0)
+	assert.Len(t, got, 4)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetConflict — not found
-// ---------------------------------------------------------------------------
-
-func TestGetConflict_NotFound(t *testing.T) {
+func TestInsertEventsIdempotent_SingleEvent(t *testing.T) {
 	ctx := context.Background()
-	// GetConflict returns nil, nil when not found (not ErrNotFound).
-	conflict, err := testDB.GetConflict(ctx, uuid.New(), uuid.New())
-	require.NoError(t, err)
-	assert.Nil(t, conflict)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: ListConflicts — with severity filter
-// ---------------------------------------------------------------------------
+	suffix := uuid.New().String()[:8]
+	agentID := "idem-single-" + suffix
 
-func TestListConflicts_SeverityFilter_Coverage(t *testing.T) {
-	ctx := context.Background()
-	sev := "critical"
-	cat := "factual"
-	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		Severity: &sev,
-		Category: &cat,
-	}, 10, 0)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(conflicts), 0)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: GetDecisionsByAgent — with filters exercised
-// ---------------------------------------------------------------------------
 
-func TestGetDecisionsByAgent_WithTimeRange(t *testing.T) {
-	ctx := context.Background()
 	now := time.Now().UTC()
-	from := now.Add(-1 * time.Hour)
-	to := now.Add(1 * time.Hour)
-	decisions, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, "nonexistent", 10, 0, &from, &to)
-	require.NoError(t, err)
-	assert.Empty(t, decisions)
-}
-
-// ---------------------------------------------------------------------------
-// Tests: MigrateAgentKeysToAPIKeys — no legacy keys (fast path)
-// ---------------------------------------------------------------------------
+	event := model.AgentEvent{
+		ID:          uuid.New(),
+		RunID:       run.ID,
+		EventType:   model.EventToolCallCompleted,
+		SequenceNum: 1,
+		OccurredAt:  now,
+		AgentID:     agentID,
+		Payload:     map[string]any{"step": 0},
+		CreatedAt:   now,
+	}
 
-func TestMigrateAgentKeysToAPIKeys_NoLegacyKeys(t *testing.T) {
-	ctx := context.Background()
-	// All agents in tests use managed API keys, so migration should find nothing.
-	migrated, err := testDB.MigrateAgentKeysToAPIKeys(ctx)
+	inserted, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{event})
 	require.NoError(t, err)
-	assert.Equal(t, 0, migrated, "no agents should have legacy keys to migrate")
-}
-
-// ---------------------------------------------------------------------------
-// Tests: CreateAlternativesBatch — empty batch
-// ---------------------------------------------------------------------------
+	assert.Equal(t, int64(1), inserted)
 
-func TestCreateAlternativesBatch_Empty(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.CreateAlternativesBatch(ctx, nil)
+	// Re-insert same event.
+	inserted2, err := testDB.InsertEventsIdempotent(ctx, []model.AgentEvent{event})
 	require.NoError(t, err)
+	assert.Equal(t, int64(0), inserted2, "duplicate should be skipped")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: SearchDecisionsByText — FTS then ILIKE fallback
+// Tests: CreateTraceTx — with alternatives and evidence
 // ---------------------------------------------------------------------------
 
-func TestSearchDecisionsByText_WithFilters(t *testing.T) {
+func TestCreateTraceTx_WithAlternativesAndEvidence(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "search-text-" + suffix
+	agentID := "trace-tx-full-" + suffix
 
 	_, err := testDB.CreateAgent(ctx, model.Agent{
 		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
@@ -10278,108 +10647,152 @@ func TestSearchDecisionsByText_WithFilters(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	traceID := "trace-" + suffix
+	sessionID := uuid.New()
+	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:  agentID,
+		OrgID:    uuid.Nil,
+		TraceID:  &traceID,
+		Metadata: map[string]any{"test": true},
+		Decision: model.Decision{
+			DecisionType: "architecture",
+			Outcome:      "use postgres",
+			Confidence:   0.95,
+			Reasoning:    strPtr("better for ACID"),
+			Metadata:     map[string]any{},
+		},
+		Alternatives: []model.Alternative{
+			{Label: "MySQL"},
+			{Label: "Postgres"},
+		},
+		Evidence: []model.Evidence{
+			{SourceType: model.SourceAPIResponse, Content: "benchmarks show...", Metadata: map[string]any{}},
+		},
+		SessionID:    &sessionID,
+		AgentContext: map[string]any{"task": "db-selection"},
+	})
 	require.NoError(t, err)
+	assert.Equal(t, agentID, run.AgentID)
+	assert.Equal(t, model.RunStatusCompleted, run.Status)
+	assert.NotNil(t, run.CompletedAt)
+	assert.Equal(t, "architecture", dec.DecisionType)
+	assert.Equal(t, "use postgres", dec.Outcome)
+	assert.NotEqual(t, uuid.Nil, dec.ID)
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "search_text_test", Outcome: "use postgres for data storage " + suffix,
-		Confidence: 0.9, Reasoning: strPtr("ACID compliance"),
-		Metadata: map[string]any{},
-	})
+	// Verify alternatives were created.
+	alts, err := testDB.GetAlternativesByDecision(ctx, dec.ID, uuid.Nil)
 	require.NoError(t, err)
+	assert.Len(t, alts, 2)
 
-	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "postgres "+suffix, model.QueryFilters{
-		AgentIDs: []string{agentID},
-	}, 5)
+	// Verify evidence was created.
+	evs, err := testDB.GetEvidenceByDecision(ctx, dec.ID, uuid.Nil, true)
 	require.NoError(t, err)
-	// Should find via FTS or ILIKE fallback.
-	assert.GreaterOrEqual(t, len(results), 0)
+	assert.Len(t, evs, 1)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: CountConflicts — with decision type filter
-// ---------------------------------------------------------------------------
-
-func TestCountConflicts_WithDecisionTypeFilter(t *testing.T) {
+func TestCreateTraceTx_WithAuditEntry(t *testing.T) {
 	ctx := context.Background()
-	dt := "architecture"
-	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{
-		DecisionType: &dt,
+	suffix := uuid.New().String()[:8]
+	agentID := "trace-tx-audit-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, count, 0)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: GetEvidenceCoverageStats
-// ---------------------------------------------------------------------------
+	audit := &storage.MutationAuditEntry{
+		RequestID:    "req-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: agentID,
+		ActorRole:    "agent",
+		HTTPMethod:   "POST",
+		Endpoint:     "/v1/trace",
+		Operation:    "create",
+		ResourceType: "decision",
+	}
 
-func TestGetEvidenceCoverageStats_Structure(t *testing.T) {
-	ctx := context.Background()
-	stats, err := testDB.GetEvidenceCoverageStats(ctx, uuid.Nil, nil, nil)
+	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "tool_choice",
+			Outcome:      "use grep",
+			Confidence:   0.8,
+			Metadata:     map[string]any{},
+		},
+		AuditEntry: audit,
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, stats.TotalDecisions, 0)
-	assert.GreaterOrEqual(t, stats.TotalRecords, 0)
+	assert.Equal(t, model.RunStatusCompleted, run.Status)
+	assert.NotEqual(t, uuid.Nil, dec.ID)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: ListAgentIDsBySharedTags
-// ---------------------------------------------------------------------------
-
-func TestListAgentIDsBySharedTags_WithNewTag(t *testing.T) {
+func TestCreateTraceTx_MinimalDecision(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "trace-tx-min-" + suffix
 
-	tag := "shared-tag-" + suffix
-	for i := 0; i < 2; i++ {
-		agentID := fmt.Sprintf("shared-tag-agent-%d-%s", i, suffix)
-		_, err := testDB.CreateAgent(ctx, model.Agent{
-			AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
-			Tags: []string{tag}, Metadata: map[string]any{},
-		})
-		require.NoError(t, err)
-	}
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	ids, err := testDB.ListAgentIDsBySharedTags(ctx, uuid.Nil, []string{tag})
+	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "test",
+			Outcome:      "minimal",
+			Confidence:   0.5,
+		},
+	})
 	require.NoError(t, err)
-	assert.GreaterOrEqual(t, len(ids), 2)
+	assert.Equal(t, model.RunStatusCompleted, run.Status)
+	assert.NotEqual(t, uuid.Nil, dec.ID)
+	assert.NotNil(t, dec.Metadata, "nil metadata should be defaulted to empty map")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetAgentListStats — with freshly created agent
+// Tests: DeleteAgentData — with audit entry
 // ---------------------------------------------------------------------------
 
-func TestGetAgentListStats_Fresh(t *testing.T) {
+func TestDeleteAgentData_WithAuditEntry(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "delete-audit-" + suffix
 
-	stats, err := testDB.GetAgentListStats(ctx, uuid.Nil)
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.NotNil(t, stats)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: CountDecisionsByAPIKey
-// ---------------------------------------------------------------------------
+	audit := &storage.MutationAuditEntry{
+		RequestID:    "req-del-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: "admin",
+		ActorRole:    "platform_admin",
+		HTTPMethod:   "DELETE",
+		Endpoint:     "/v1/agents/" + agentID,
+		Operation:    "delete",
+		ResourceType: "agent",
+	}
 
-func TestCountDecisionsByAPIKey_WithTimeRange(t *testing.T) {
-	ctx := context.Background()
-	from := time.Now().UTC().Add(-24 * time.Hour)
-	to := time.Now().UTC().Add(24 * time.Hour)
-	counts, total, err := testDB.CountDecisionsByAPIKey(ctx, uuid.Nil, from, to)
+	result, err := testDB.DeleteAgentData(ctx, uuid.Nil, agentID, audit)
 	require.NoError(t, err)
-	assert.NotNil(t, counts)
-	assert.GreaterOrEqual(t, total, 0)
+	assert.Equal(t, int64(1), result.Agents)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: CreateEvidence and CreateEvidenceBatch
+// Tests: GetDecisionOutcomeSignals — all three signal types populated
 // ---------------------------------------------------------------------------
 
-func TestCreateEvidenceBatch(t *testing.T) {
+func TestGetDecisionOutcomeSignals_AllSignals(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "evi-batch-" + suffix
+	agentID := "signals-all-" + suffix
 
 	_, err := testDB.CreateAgent(ctx, model.Agent{
 		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
@@ -10387,1843 +10800,4934 @@ func TestCreateEvidenceBatch(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	// Create a decision to query signals for.
 	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
 	dec, err := testDB.CreateDecision(ctx, model.Decision{
 		RunID: run.ID, AgentID: agentID,
-		DecisionType: "evi_batch_test", Outcome: "test",
+		DecisionType: "signals_test", Outcome: "test outcome",
 		Confidence: 0.7, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
-		{DecisionID: dec.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence 1"},
-		{DecisionID: dec.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence 2"},
+	// Create a superseding decision.
+	run2, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	supersedesID := dec.ID
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run2.ID, AgentID: agentID,
+		DecisionType: "signals_test", Outcome: "revised outcome",
+		Confidence: 0.9, Metadata: map[string]any{},
+		SupersedesID: &supersedesID,
 	})
 	require.NoError(t, err)
 
-	evs, err := testDB.GetEvidenceByDecision(ctx, dec.ID, uuid.Nil)
+	// Create a citing decision.
+	run3, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Len(t, evs, 2)
-}
 
-func TestCreateEvidenceBatch_Empty(t *testing.T) {
-	ctx := context.Background()
-	err := testDB.CreateEvidenceBatch(ctx, nil)
+	precedentRef := dec.ID
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run3.ID, AgentID: agentID,
+		DecisionType: "signals_test", Outcome: "citing outcome",
+		Confidence: 0.8, Metadata: map[string]any{},
+		PrecedentRef: &precedentRef,
+	})
 	require.NoError(t, err)
-}
 
-// ---------------------------------------------------------------------------
-// Helpers
-// ---------------------------------------------------------------------------
+	signals, err := testDB.GetDecisionOutcomeSignals(ctx, dec.ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.NotNil(t, signals.SupersessionVelocityHours, "should have supersession velocity")
+	assert.GreaterOrEqual(t, signals.PrecedentCitationCount, 1, "should have at least one citation")
+}
 
 // ---------------------------------------------------------------------------
-// Tests: GetConflictAnalytics — with actual scored conflict data
+// Tests: GetCitationPercentilesForOrg — with multiple citation levels
 // ---------------------------------------------------------------------------
 
-func TestGetConflictAnalytics_WithScoredConflict(t *testing.T) {
+func TestGetCitationPercentilesForOrg_MultipleCitations(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "cite-pctl-" + suffix
 
-	agentA := "analytics-a-" + suffix
-	agentB := "analytics-b-" + suffix
-	decType := "analytics_type_" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+
+	// Create a base decision that will be cited.
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: decType, Outcome: "approach_alpha",
+	baseDec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "pctl_test", Outcome: "base",
 		Confidence: 0.9, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: decType, Outcome: "approach_beta",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	// Create several citing decisions.
+	for i := 0; i < 5; i++ {
+		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+		require.NoError(t, err)
 
-	topicSim := 0.85
-	outcomeDiv := 0.75
-	sig := 0.70
-	sev := "high"
-	cat := "factual"
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     decType,
-		DecisionTypeB:     decType,
-		OutcomeA:          "approach_alpha",
-		OutcomeB:          "approach_beta",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "llm_v2",
-		Severity:          &sev,
-		Category:          &cat,
-	})
-	require.NoError(t, err)
+		ref := baseDec.ID
+		_, err = testDB.CreateDecision(ctx, model.Decision{
+			RunID: r.ID, AgentID: agentID,
+			DecisionType: "pctl_test", Outcome: fmt.Sprintf("citing %d", i),
+			Confidence: 0.8, Metadata: map[string]any{},
+			PrecedentRef: &ref,
+		})
+		require.NoError(t, err)
+	}
 
-	// Query analytics for the org with a time range that includes now.
-	// The trend query uses generate_series(from::date, (to::date - '1 day')::date, '1 day'),
-	// so from and to must span at least 2 calendar days to produce rows.
-	now := time.Now().UTC()
-	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
-		From: now.Add(-24 * time.Hour),
-		To:   now.Add(24 * time.Hour),
-	})
+	breakpoints, err := testDB.GetCitationPercentilesForOrg(ctx, uuid.Nil)
 	require.NoError(t, err)
+	// With data, should return percentile breakpoints.
+	assert.NotNil(t, breakpoints, "should return percentile breakpoints when citations exist")
+	assert.Len(t, breakpoints, 4, "should return [p25, p50, p75, p90]")
+}
 
-	// Summary should reflect at least the one conflict we inserted.
-	assert.GreaterOrEqual(t, analytics.Summary.TotalDetected, 1)
-
-	// ByAgentPair should have entries (covers the pairRows.Next() loop).
-	assert.NotEmpty(t, analytics.ByAgentPair, "expected at least one agent pair in analytics")
+// ---------------------------------------------------------------------------
+// Tests: CleanupIdempotencyKeys — exercising the cleanup
+// ---------------------------------------------------------------------------
 
-	// ByDecisionType should have entries (covers the typeRows.Next() loop).
-	assert.NotEmpty(t, analytics.ByDecisionType, "expected at least one decision type in analytics")
+func TestCleanupIdempotencyKeys_RemovesExpired(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
 
-	// BySeverity should have entries (covers the sevRows.Next() loop).
-	assert.NotEmpty(t, analytics.BySeverity, "expected at least one severity in analytics")
+	// Create an in-progress key.
+	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, "cleanup-agent-"+suffix, "/v1/trace", "cleanup-key-"+suffix, "hash1")
+	require.NoError(t, err)
 
-	// Trend should have entries (covers the trendRows.Next() loop — generate_series produces rows).
-	assert.NotEmpty(t, analytics.Trend, "expected at least one trend point in analytics")
+	// Cleanup with a very short TTL should remove it.
+	// The key was just created, so with a 0 TTL it should be removed.
+	deleted, err := testDB.CleanupIdempotencyKeys(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1), "should remove at least the in-progress key")
 }
 
 // ---------------------------------------------------------------------------
-// Tests: GetConflict — found
+// Tests: CompleteIdempotency — full lifecycle
 // ---------------------------------------------------------------------------
 
-func TestGetConflict_Found(t *testing.T) {
+func TestCompleteIdempotency_FullLifecycle(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	agentA := "get-conflict-a-" + suffix
-	agentB := "get-conflict-b-" + suffix
+	agentID := "idemp-lifecycle-" + suffix
+	endpoint := "/v1/trace"
+	key := "idemp-key-" + suffix
+	hash := "hash-" + suffix
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	// Begin: should succeed (new key).
+	lookup, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
 	require.NoError(t, err)
+	assert.False(t, lookup.Completed)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA,
-		DecisionType: "get_conflict_test", Outcome: "found_a",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	// Complete: mark the key as done.
+	err = testDB.CompleteIdempotency(ctx, uuid.Nil, agentID, endpoint, key, 201, map[string]any{"id": "dec-123"})
 	require.NoError(t, err)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB,
-		DecisionType: "get_conflict_test", Outcome: "found_b",
-		Confidence: 0.7, Metadata: map[string]any{},
-	})
+	// Begin again with same hash: should return completed lookup.
+	lookup, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
 	require.NoError(t, err)
+	assert.True(t, lookup.Completed)
+	assert.Equal(t, 201, lookup.StatusCode)
+	assert.NotEmpty(t, lookup.ResponseData)
+}
 
-	topicSim := 0.9
-	outcomeDiv := 0.8
-	sig := 0.72
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind:      model.ConflictKindCrossAgent,
-		DecisionAID:       dA.ID,
-		DecisionBID:       dB.ID,
-		OrgID:             uuid.Nil,
-		AgentA:            agentA,
-		AgentB:            agentB,
-		DecisionTypeA:     "get_conflict_test",
-		DecisionTypeB:     "get_conflict_test",
-		OutcomeA:          "found_a",
-		OutcomeB:          "found_b",
-		TopicSimilarity:   &topicSim,
-		OutcomeDivergence: &outcomeDiv,
-		Significance:      &sig,
-		ScoringMethod:     "llm_v2",
-	})
+func TestBeginIdempotency_PayloadMismatch(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentID := "idemp-mismatch-" + suffix
+	endpoint := "/v1/trace"
+	key := "idemp-mismatch-key-" + suffix
+
+	// Create initial key.
+	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, "hash-a")
 	require.NoError(t, err)
 
-	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	// Complete it.
+	err = testDB.CompleteIdempotency(ctx, uuid.Nil, agentID, endpoint, key, 200, map[string]any{})
 	require.NoError(t, err)
-	require.NotNil(t, conflict, "expected conflict to be found")
-	assert.Equal(t, conflictID, conflict.ID)
-	assert.Equal(t, "open", conflict.Status)
-}
 
-// ---------------------------------------------------------------------------
-// Tests: QueryDecisions — with RunID filter
-// ---------------------------------------------------------------------------
+	// Retry with different hash: should return ErrIdempotencyPayloadMismatch.
+	_, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, "hash-b")
+	require.ErrorIs(t, err, storage.ErrIdempotencyPayloadMismatch)
+}
 
-func TestQueryDecisions_WithRunIDFilter(t *testing.T) {
+func TestBeginIdempotency_InProgress(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "runid-filter-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentID := "idemp-inprog-" + suffix
+	endpoint := "/v1/trace"
+	key := "idemp-inprog-key-" + suffix
+	hash := "hash-inprog-" + suffix
 
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "runid_test", Outcome: "filtered_by_run",
-		Confidence: 0.8, Metadata: map[string]any{},
-	})
+	// First call owns processing.
+	_, err := testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
 	require.NoError(t, err)
 
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{RunID: &run.ID},
-	})
-	require.NoError(t, err)
-	assert.GreaterOrEqual(t, total, 1)
-	for _, d := range decisions {
-		assert.Equal(t, run.ID, d.RunID)
-	}
+	// Second call with same hash should get ErrIdempotencyInProgress.
+	_, err = testDB.BeginIdempotency(ctx, uuid.Nil, agentID, endpoint, key, hash)
+	require.ErrorIs(t, err, storage.ErrIdempotencyInProgress)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisions — with Tool and Model filters
+// Tests: GetEventsByRun — with explicit limit
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisions_WithToolFilter(t *testing.T) {
+func TestGetEventsByRun_WithExplicitLimit(t *testing.T) {
 	ctx := context.Background()
-	tool := "nonexistent-tool-" + uuid.New().String()[:8]
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{Tool: &tool},
-	})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "events-limit-test"})
 	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
-}
 
-func TestQueryDecisions_WithModelFilter(t *testing.T) {
-	ctx := context.Background()
-	mdl := "nonexistent-model-" + uuid.New().String()[:8]
-	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Filters: model.QueryFilters{Model: &mdl},
-	})
+	now := time.Now().UTC()
+	for i := 0; i < 5; i++ {
+		err = testDB.InsertEvent(ctx, model.AgentEvent{
+			ID: uuid.New(), RunID: run.ID, OrgID: run.OrgID,
+			EventType: model.EventDecisionStarted, SequenceNum: int64(i + 1),
+			OccurredAt: now, AgentID: "events-limit-test",
+			Payload: map[string]any{}, CreatedAt: now,
+		})
+		require.NoError(t, err)
+	}
+
+	// Fetch with limit of 3.
+	events, err := testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 3)
 	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, decisions)
+	assert.Len(t, events, 3)
+
+	// Fetch with limit of 0 (should use default).
+	events, err = testDB.GetEventsByRun(ctx, run.OrgID, run.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
+
+	// Fetch with negative limit (should use default).
+	events, err = testDB.GetEventsByRun(ctx, run.OrgID, run.ID, -1)
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisions — ordering edge cases
+// Tests: GetGrant — not found
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisions_OrderByOutcomeScore(t *testing.T) {
+func TestGetGrant_NotFound(t *testing.T) {
 	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		OrderBy: "outcome_score",
-		Limit:   5,
-	})
-	require.NoError(t, err)
-	_ = decisions // just verify the query runs without error
+	_, err := testDB.GetGrant(ctx, uuid.Nil, uuid.New())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestQueryDecisions_OrderAsc(t *testing.T) {
-	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		OrderBy:  "valid_from",
-		OrderDir: "asc",
-		Limit:    5,
-	})
-	require.NoError(t, err)
-	_ = decisions
-}
+// ---------------------------------------------------------------------------
+// Tests: GetOrganization — found after EnsureDefaultOrg
+// ---------------------------------------------------------------------------
 
-func TestQueryDecisions_LimitOver1000(t *testing.T) {
+func TestGetOrganization_AfterEnsureDefault(t *testing.T) {
 	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Limit: 5000,
-	})
+	err := testDB.EnsureDefaultOrg(ctx)
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(decisions), 1000)
-}
 
-func TestQueryDecisions_NegativeOffsetClamped(t *testing.T) {
-	ctx := context.Background()
-	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
-		Offset: -10,
-		Limit:  5,
-	})
+	org, err := testDB.GetOrganization(ctx, uuid.Nil)
 	require.NoError(t, err)
-	_ = decisions
+	assert.Equal(t, uuid.Nil, org.ID)
+	assert.Equal(t, "Default", org.Name)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: QueryDecisionsTemporal — limit edge cases
+// Tests: UpdateAgentTags — not found
 // ---------------------------------------------------------------------------
 
-func TestQueryDecisionsTemporal_LimitOver1000(t *testing.T) {
+func TestUpdateAgentTags_NotFound(t *testing.T) {
 	ctx := context.Background()
-	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
-		AsOf:  time.Now().UTC(),
-		Limit: 5000,
-	})
-	require.NoError(t, err)
-	assert.LessOrEqual(t, len(decisions), 1000)
+	_, err := testDB.UpdateAgentTags(ctx, uuid.Nil, "nonexistent-"+uuid.New().String()[:8], []string{"tag1"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestGetDecisionsByAgent_LimitClamping(t *testing.T) {
+func TestUpdateAgentTags_NilTags(t *testing.T) {
 	ctx := context.Background()
-	// Limit <= 0 defaults to 50, limit > 1000 caps to 1000, offset < 0 defaults to 0.
-	decisions, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, "no-such-agent", 0, -1, nil, nil)
+	suffix := uuid.New().String()[:8]
+	agentID := "tags-nil-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	assert.Empty(t, decisions)
 
-	decisions, _, err = testDB.GetDecisionsByAgent(ctx, uuid.Nil, "no-such-agent", 9999, 0, nil, nil)
+	// Nil tags should be converted to empty slice.
+	agent, err := testDB.UpdateAgentTags(ctx, uuid.Nil, agentID, nil)
 	require.NoError(t, err)
-	assert.Empty(t, decisions)
+	assert.NotNil(t, agent.Tags)
+	assert.Empty(t, agent.Tags)
 }
 
 // ---------------------------------------------------------------------------
-// Tests: ListRunsByAgent — limit edge cases
+// Tests: UpdateAgentWithAudit — not found
 // ---------------------------------------------------------------------------
 
-func TestListRunsByAgent_LimitClamping(t *testing.T) {
+func TestUpdateAgentWithAudit_NotFound(t *testing.T) {
 	ctx := context.Background()
-	// Limit <= 0 defaults to 50, limit > 1000 caps to 1000, offset < 0 defaults to 0.
-	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, "no-such-agent", 0, -1)
-	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, runs)
-
-	runs, total, err = testDB.ListRunsByAgent(ctx, uuid.Nil, "no-such-agent", 9999, 0)
-	require.NoError(t, err)
-	assert.Equal(t, 0, total)
-	assert.Empty(t, runs)
-}
-
-func strPtr(s string) *string {
-	return &s
+	suffix := uuid.New().String()[:8]
+	name := "new-name"
+	_, err := testDB.UpdateAgentWithAudit(ctx, uuid.Nil, "nonexistent-"+suffix, &name, nil, storage.MutationAuditEntry{
+		RequestID:    "req-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: "admin",
+		ActorRole:    "platform_admin",
+		HTTPMethod:   "PATCH",
+		Endpoint:     "/v1/agents/x",
+		Operation:    "update",
+		ResourceType: "agent",
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
 // ---------------------------------------------------------------------------
-// Integrity violation persistence + tampered proof detection (Finding #1, #9)
+// Tests: RevokeAPIKeyWithAudit — already revoked
 // ---------------------------------------------------------------------------
 
-func TestCreateIntegrityViolation_And_GetViolations(t *testing.T) {
+func TestRevokeAPIKeyWithAudit_AlreadyRevoked(t *testing.T) {
 	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "revoke-twice-" + suffix
 
-	// Use the default test org (uuid.Nil).
-	orgID := uuid.Nil
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	// We need a proof to reference.
-	proof := storage.IntegrityProof{
-		OrgID:         orgID,
-		BatchStart:    time.Now().UTC().Add(-1 * time.Hour),
-		BatchEnd:      time.Now().UTC(),
-		DecisionCount: 5,
-		RootHash:      "viol-test-root-" + uuid.New().String()[:8],
-		CreatedAt:     time.Now().UTC(),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
-	// Re-fetch to get the auto-generated ID.
-	fetched, err := testDB.GetLatestIntegrityProof(ctx, orgID)
+	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix:    "ak_" + suffix[:6],
+		KeyHash:   "hash-" + suffix,
+		AgentID:   agentID,
+		OrgID:     uuid.Nil,
+		Label:     "test key",
+		CreatedBy: agentID,
+	}, storage.MutationAuditEntry{
+		RequestID: "req-create-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: agentID, ActorRole: "platform_admin",
+		HTTPMethod: "POST", Endpoint: "/v1/api-keys",
+		Operation: "create", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
-	require.NotNil(t, fetched)
 
-	// Insert a violation.
-	v := storage.IntegrityViolation{
-		OrgID:         orgID,
-		ProofID:       fetched.ID,
-		ViolationType: "merkle_root_mismatch",
-		Details: map[string]any{
-			"stored_root":    "aaaa",
-			"decision_count": 5,
-		},
-		CreatedAt: time.Now().UTC(),
+	audit := storage.MutationAuditEntry{
+		RequestID: "req-revoke-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: agentID, ActorRole: "platform_admin",
+		HTTPMethod: "DELETE", Endpoint: "/v1/api-keys/" + key.ID.String(),
+		Operation: "revoke", ResourceType: "api_key",
 	}
-	err = testDB.CreateIntegrityViolation(ctx, v)
-	require.NoError(t, err)
 
-	// Fetch violations.
-	violations, err := testDB.GetIntegrityViolations(ctx, orgID, 10)
+	// First revocation should succeed.
+	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, audit)
 	require.NoError(t, err)
-	require.NotEmpty(t, violations)
 
-	found := false
-	for _, viol := range violations {
-		if viol.ProofID == fetched.ID && viol.ViolationType == "merkle_root_mismatch" {
-			found = true
-			assert.Equal(t, orgID, viol.OrgID)
-			assert.NotNil(t, viol.Details)
-			break
-		}
-	}
-	assert.True(t, found, "expected to find the created violation")
+	// Second revocation should fail with "already revoked".
+	audit.RequestID = "req-revoke2-" + suffix
+	err = testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, audit)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already revoked")
 }
 
-func TestVerifyBatchProof_DetectsTamperingIntegration(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: BatchDeleteDecisions — with decision type filter
+// ---------------------------------------------------------------------------
+
+func TestBatchDeleteDecisions_WithDecisionTypeAndAgent(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "tamper-detect-" + suffix
-
-	// Use a dedicated org so other tests' decisions in the default org
-	// don't leak into our batch window and inflate the hash count.
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-		orgID, "tamper-org-"+suffix, "tamper-org-"+suffix)
-	require.NoError(t, err)
+	agentID := "batch-del-da-" + suffix
 
-	// CreateRun uses the default org; we need to insert the run manually
-	// under our isolated org so decisions land in the right org_id.
-	runID := uuid.New()
-	_, err = testDB.Pool().Exec(ctx,
-		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
-		 VALUES ($1, $2, $3, 'running', now())`,
-		runID, orgID, agentID)
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
 
-	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
-
-	// Create 4 decisions with content hashes.
-	const count = 4
-	for i := range count {
-		reasoning := fmt.Sprintf("reasoning %d", i)
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			ID:           uuid.New(),
-			RunID:        runID,
-			AgentID:      agentID,
-			OrgID:        orgID,
-			DecisionType: "tamper_test",
-			Outcome:      fmt.Sprintf("outcome %d for %s", i, suffix),
-			Confidence:   0.8,
-			Reasoning:    &reasoning,
-			ValidFrom:    time.Now().UTC(),
+	// Create some decisions.
+	for i := 0; i < 3; i++ {
+		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+		require.NoError(t, err)
+		_, err = testDB.CreateDecision(ctx, model.Decision{
+			RunID: r.ID, AgentID: agentID,
+			DecisionType: "batch_del_type", Outcome: fmt.Sprintf("outcome %d", i),
+			Confidence: 0.5, Metadata: map[string]any{},
 		})
 		require.NoError(t, err)
 	}
 
-	afterCreate := time.Now().UTC().Add(1 * time.Second)
-
-	// Get hashes and build a legitimate Merkle root.
-	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	dt := "batch_del_type"
+	counts, err := testDB.BatchDeleteDecisions(ctx, uuid.Nil, cutoff, &dt, &agentID, nil, 2)
 	require.NoError(t, err)
-	require.Len(t, hashes, count)
+	assert.Equal(t, int64(3), counts.Decisions, "all 3 decisions should be deleted")
+}
 
-	root, err := integrity.BuildMerkleRoot(hashes)
-	require.NoError(t, err)
+// ---------------------------------------------------------------------------
+// Tests: StartDeletionLog — empty initiatedBy
+// ---------------------------------------------------------------------------
 
-	// Verify passes with correct root.
-	ok, err := integrity.VerifyBatchProof(root, hashes)
+func TestStartDeletionLog_EmptyInitiatedBy(t *testing.T) {
+	ctx := context.Background()
+
+	// Empty initiatedBy should be stored as NULL.
+	logID, err := testDB.StartDeletionLog(ctx, uuid.Nil, "policy", "", map[string]any{})
 	require.NoError(t, err)
-	assert.True(t, ok, "legitimate proof should verify")
+	assert.NotEqual(t, uuid.Nil, logID)
+}
 
-	// Store the proof.
-	proof := storage.IntegrityProof{
-		OrgID:         orgID,
-		BatchStart:    beforeCreate,
-		BatchEnd:      afterCreate,
-		DecisionCount: count,
-		RootHash:      root,
-		CreatedAt:     time.Now().UTC(),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+// ---------------------------------------------------------------------------
+// Tests: CreateHold and ReleaseHold — already released returns false
+// ---------------------------------------------------------------------------
 
-	// Simulate tampering: the decisions table has an immutability trigger
-	// (correctly preventing direct mutation), so we simulate what would happen
-	// if the stored root was corrupted or if a decision was inserted/deleted
-	// outside the normal write path. We verify against a wrong root.
-	wrongRoot := "0000000000000000000000000000000000000000000000000000000000000000"
-	ok, err = integrity.VerifyBatchProof(wrongRoot, hashes)
+func TestReleaseHold_AlreadyReleased(t *testing.T) {
+	ctx := context.Background()
+
+	hold, err := testDB.CreateHold(ctx, storage.RetentionHold{
+		OrgID:     uuid.Nil,
+		Reason:    "legal hold test",
+		HoldFrom:  time.Now().UTC().Add(-24 * time.Hour),
+		HoldTo:    time.Now().UTC().Add(24 * time.Hour),
+		CreatedBy: "admin",
+	})
 	require.NoError(t, err)
-	assert.False(t, ok, "wrong stored root should fail verification")
 
-	// Also verify that adding an extra hash (simulating an injected decision)
-	// causes verification to fail against the original root.
-	injectedHashes := make([]string, len(hashes)+1)
-	copy(injectedHashes, hashes)
-	injectedHashes[len(hashes)] = "zzz_injected_hash"
-	ok, err = integrity.VerifyBatchProof(root, injectedHashes)
+	// First release should succeed.
+	ok, err := testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
 	require.NoError(t, err)
-	assert.False(t, ok, "injected hash should cause root mismatch")
+	assert.True(t, ok)
+
+	// Second release should return false (already released).
+	ok, err = testDB.ReleaseHold(ctx, hold.ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.False(t, ok, "releasing an already-released hold should return false")
 }
 
-func TestIntegrityViolation_ChainLinkageDetection(t *testing.T) {
+func TestReleaseHold_NotFound(t *testing.T) {
 	ctx := context.Background()
-	orgID := uuid.Nil
-	now := time.Now().UTC()
+	ok, err := testDB.ReleaseHold(ctx, uuid.New(), uuid.Nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
 
-	// Create two proofs with correct chain linkage.
-	proof1 := storage.IntegrityProof{
-		OrgID:         orgID,
-		BatchStart:    now.Add(-2 * time.Hour),
-		BatchEnd:      now.Add(-1 * time.Hour),
-		DecisionCount: 3,
-		RootHash:      "chain-root-1-" + uuid.New().String()[:8],
-		CreatedAt:     now.Add(-1 * time.Hour),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof1))
+// ---------------------------------------------------------------------------
+// Tests: MarkDecisionConflictScored
+// ---------------------------------------------------------------------------
 
-	prevRoot := proof1.RootHash
-	proof2 := storage.IntegrityProof{
-		OrgID:         orgID,
-		BatchStart:    now.Add(-1 * time.Hour),
-		BatchEnd:      now,
-		DecisionCount: 5,
-		RootHash:      "chain-root-2-" + uuid.New().String()[:8],
-		PreviousRoot:  &prevRoot,
-		CreatedAt:     now,
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof2))
+func TestMarkDecisionConflictScored_Success(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "mark-scored-" + suffix
 
-	// Fetch recent proofs (newest-first).
-	proofs, err := testDB.GetRecentIntegrityProofs(ctx, orgID, 10)
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
-	require.GreaterOrEqual(t, len(proofs), 2)
-
-	// Find our two proofs in the result (may have other proofs from other tests).
-	var newest, older *storage.IntegrityProof
-	for i := range proofs {
-		if proofs[i].RootHash == proof2.RootHash {
-			newest = &proofs[i]
-		}
-		if proofs[i].RootHash == proof1.RootHash {
-			older = &proofs[i]
-		}
-	}
-	require.NotNil(t, newest, "should find proof2 in recent proofs")
-	require.NotNil(t, older, "should find proof1 in recent proofs")
 
-	// Chain linkage should be valid.
-	require.NotNil(t, newest.PreviousRoot)
-	assert.Equal(t, older.RootHash, *newest.PreviousRoot, "chain linkage should match")
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 
-	// Now create a proof with broken chain linkage.
-	brokenPrev := "wrong-root-hash"
-	proof3 := storage.IntegrityProof{
-		OrgID:         orgID,
-		BatchStart:    now,
-		BatchEnd:      now.Add(1 * time.Hour),
-		DecisionCount: 2,
-		RootHash:      "chain-root-3-" + uuid.New().String()[:8],
-		PreviousRoot:  &brokenPrev,
-		CreatedAt:     now.Add(1 * time.Second),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof3))
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "scored_test", Outcome: "test",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
 
-	// Verify the broken linkage is detectable.
-	proofs, err = testDB.GetRecentIntegrityProofs(ctx, orgID, 10)
+	err = testDB.MarkDecisionConflictScored(ctx, dec.ID, uuid.Nil)
 	require.NoError(t, err)
+}
 
-	var broken *storage.IntegrityProof
-	for i := range proofs {
-		if proofs[i].RootHash == proof3.RootHash {
-			broken = &proofs[i]
-			break
-		}
+// ---------------------------------------------------------------------------
+// Tests: GetConflictAnalytics — with all filter combinations
+// ---------------------------------------------------------------------------
+
+func TestGetConflictAnalytics_WithAgentFilter(t *testing.T) {
+	ctx := context.Background()
+	agent := "analytics-agent-filter"
+	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From:    time.Now().UTC().Add(-30 * 24 * time.Hour),
+		To:      time.Now().UTC(),
+		AgentID: &agent,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, result.ByAgentPair)
+	assert.NotNil(t, result.ByDecisionType)
+}
+
+func TestGetConflictAnalytics_WithDecisionTypeFilter(t *testing.T) {
+	ctx := context.Background()
+	dt := "architecture"
+	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
+		To:           time.Now().UTC(),
+		DecisionType: &dt,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, result.ByAgentPair)
+}
+
+func TestGetConflictAnalytics_WithConflictKindFilter(t *testing.T) {
+	ctx := context.Background()
+	kind := "cross_agent"
+	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
+		To:           time.Now().UTC(),
+		ConflictKind: &kind,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, result.ByAgentPair)
+}
+
+func TestGetConflictAnalytics_WithAllFilters(t *testing.T) {
+	ctx := context.Background()
+	agent := "some-agent"
+	dt := "architecture"
+	kind := "cross_agent"
+	result, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From:         time.Now().UTC().Add(-30 * 24 * time.Hour),
+		To:           time.Now().UTC(),
+		AgentID:      &agent,
+		DecisionType: &dt,
+		ConflictKind: &kind,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Summary.TotalDetected)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ListRunsByAgent — with data
+// ---------------------------------------------------------------------------
+
+func TestListRunsByAgent_WithData(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "list-runs-" + suffix
+
+	for i := 0; i < 3; i++ {
+		_, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+		require.NoError(t, err)
 	}
-	require.NotNil(t, broken)
-	require.NotNil(t, broken.PreviousRoot)
-	// The broken proof's previous_root should NOT match proof2's root.
-	assert.NotEqual(t, proof2.RootHash, *broken.PreviousRoot,
-		"chain linkage should be broken — previous_root doesn't match prior proof")
 
-	// Record a violation for the broken linkage.
-	err = testDB.CreateIntegrityViolation(ctx, storage.IntegrityViolation{
-		OrgID:         orgID,
-		ProofID:       broken.ID,
-		ViolationType: "chain_linkage_broken",
-		Details: map[string]any{
-			"expected_previous": proof2.RootHash,
-			"actual_previous":   *broken.PreviousRoot,
-		},
-		CreatedAt: time.Now().UTC(),
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, runs, 2)
+
+	// Second page.
+	runs, total, err = testDB.ListRunsByAgent(ctx, uuid.Nil, agentID, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, runs, 1)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionForScoring — not found
+// ---------------------------------------------------------------------------
+
+func TestGetDecisionForScoring_NotFound(t *testing.T) {
+	ctx := context.Background()
+	_, err := testDB.GetDecisionForScoring(ctx, uuid.New(), uuid.Nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGetDecisionForScoring_Found(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "scoring-found-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "scoring_test", Outcome: "test outcome",
+		Confidence: 0.9, Reasoning: strPtr("because reasons"),
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	got, err := testDB.GetDecisionForScoring(ctx, dec.ID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, dec.ID, got.ID)
+	assert.Equal(t, "scoring_test", got.DecisionType)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: InsertMutationAudit — with before and after data
+// ---------------------------------------------------------------------------
+
+func TestInsertMutationAudit_WithBeforeAndAfterData(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	err := testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
+		RequestID:    "req-ba-" + suffix,
+		OrgID:        uuid.Nil,
+		ActorAgentID: "admin",
+		ActorRole:    "platform_admin",
+		HTTPMethod:   "PATCH",
+		Endpoint:     "/v1/agents/test",
+		Operation:    "update",
+		ResourceType: "agent",
+		ResourceID:   "test-agent",
+		BeforeData:   map[string]any{"name": "old"},
+		AfterData:    map[string]any{"name": "new"},
+		Metadata:     map[string]any{"reason": "test"},
+	})
+	require.NoError(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: UpdateOutcomeScore — on a real decision
+// ---------------------------------------------------------------------------
+
+func TestUpdateOutcomeScore_Success(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "outcome-score-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "score_test", Outcome: "test",
+		Confidence: 0.7, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	// Verify the violation was persisted.
-	violations, err := testDB.GetIntegrityViolations(ctx, orgID, 10)
+	score := float32(0.85)
+	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, dec.ID, &score)
+	require.NoError(t, err)
+
+	// Set to nil.
+	err = testDB.UpdateOutcomeScore(ctx, uuid.Nil, dec.ID, nil)
+	require.NoError(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: SetRetentionPolicy — success
+// ---------------------------------------------------------------------------
+
+func TestSetRetentionPolicy_ClearPolicy(t *testing.T) {
+	ctx := context.Background()
+	// Set a policy then clear it.
+	days := 30
+	err := testDB.SetRetentionPolicy(ctx, uuid.Nil, &days, []string{"audit"})
+	require.NoError(t, err)
+
+	// Clear the policy.
+	err = testDB.SetRetentionPolicy(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+
+	// Verify it's cleared.
+	policy, err := testDB.GetRetentionPolicy(ctx, uuid.Nil, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, policy.RetentionDays)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CountEligibleDecisions — with both filters
+// ---------------------------------------------------------------------------
+
+func TestCountEligibleDecisions_WithBothFilters(t *testing.T) {
+	ctx := context.Background()
+	cutoff := time.Now().UTC().Add(1 * time.Hour)
+	dt := "architecture"
+	agent := "some-agent"
+	counts, err := testDB.CountEligibleDecisions(ctx, uuid.Nil, cutoff, &dt, &agent)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts.Decisions, int64(0))
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ListGrants — with data
+// ---------------------------------------------------------------------------
+
+func TestListGrants_WithData(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	// Create real agents for FK constraints.
+	grantorAgent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "grantor-" + suffix, OrgID: uuid.Nil, Name: "Grantor", Role: model.RoleAdmin,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	granteeAgent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "grantee-" + suffix, OrgID: uuid.Nil, Name: "Grantee", Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		resID := fmt.Sprintf("res-%d-%s", i, suffix)
+		_, err := testDB.CreateGrant(ctx, model.AccessGrant{
+			OrgID: uuid.Nil, GrantorID: grantorAgent.ID, GranteeID: granteeAgent.ID,
+			ResourceType: "agent_traces", ResourceID: &resID,
+			Permission: "read",
+		})
+		require.NoError(t, err)
+	}
+
+	grants, total, err := testDB.ListGrants(ctx, uuid.Nil, 2, 0)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 3)
+	assert.LessOrEqual(t, len(grants), 2)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: DeleteGrantWithAudit — not found
+// ---------------------------------------------------------------------------
+
+func TestDeleteGrantWithAudit_NotFound(t *testing.T) {
+	ctx := context.Background()
+	err := testDB.DeleteGrantWithAudit(ctx, uuid.Nil, uuid.New(), storage.MutationAuditEntry{
+		RequestID:    "req-del-grant",
+		OrgID:        uuid.Nil,
+		ActorAgentID: "admin",
+		ActorRole:    "platform_admin",
+		HTTPMethod:   "DELETE",
+		Endpoint:     "/v1/grants/x",
+		Operation:    "delete",
+		ResourceType: "grant",
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetIntegrityProof — not found
+// ---------------------------------------------------------------------------
+
+func TestGetLatestIntegrityProof_NoProofs(t *testing.T) {
+	ctx := context.Background()
+	// Use a random org ID to avoid existing proofs.
+	proof, err := testDB.GetLatestIntegrityProof(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, proof, "should return nil when no proofs exist for the org")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CreateGrantWithAudit — full lifecycle
+// ---------------------------------------------------------------------------
+
+func TestCreateGrantWithAudit_FullCycle(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	// Create real agents for FK constraints.
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "cwaudit-grantor-" + suffix, OrgID: uuid.Nil, Name: "Grantor", Role: model.RoleAdmin,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "cwaudit-grantee-" + suffix, OrgID: uuid.Nil, Name: "Grantee", Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	resID := "res-" + suffix
+	grant, err := testDB.CreateGrantWithAudit(ctx, model.AccessGrant{
+		OrgID: uuid.Nil, GrantorID: grantor.ID, GranteeID: grantee.ID,
+		ResourceType: "agent_traces", ResourceID: &resID,
+		Permission: "read",
+	}, storage.MutationAuditEntry{
+		RequestID: "req-grant-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "platform_admin",
+		HTTPMethod: "POST", Endpoint: "/v1/grants",
+		Operation: "create", ResourceType: "grant",
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, grant.ID)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ReserveSequenceNums — edge cases
+// ---------------------------------------------------------------------------
+
+func TestReserveSequenceNums_ZeroCount(t *testing.T) {
+	ctx := context.Background()
+	nums, err := testDB.ReserveSequenceNums(ctx, 0)
+	require.NoError(t, err)
+	assert.Nil(t, nums)
+}
+
+func TestReserveSequenceNums_NegativeCount(t *testing.T) {
+	ctx := context.Background()
+	nums, err := testDB.ReserveSequenceNums(ctx, -5)
+	require.NoError(t, err)
+	assert.Nil(t, nums)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionQualityStats
+// ---------------------------------------------------------------------------
+
+func TestGetDecisionQualityStats_WithDecisions(t *testing.T) {
+	ctx := context.Background()
+	stats, err := testDB.GetDecisionQualityStats(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	// The default org will have decisions from other tests.
+	assert.GreaterOrEqual(t, stats.Total, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Notify — exercises the success path
+// ---------------------------------------------------------------------------
+
+func TestNotify_Success(t *testing.T) {
+	ctx := context.Background()
+	// Notify on the decisions channel — should succeed even without listeners.
+	err := testDB.Notify(ctx, storage.ChannelDecisions, `{"test": true}`)
+	require.NoError(t, err)
+
+	err = testDB.Notify(ctx, storage.ChannelConflicts, `{"conflict": true}`)
+	require.NoError(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CreateOrgWithOwnerAndKeyTx — verifies org, agent, and key creation
+// ---------------------------------------------------------------------------
+
+func TestCreateOrgWithOwnerAndKeyTx_AllFieldsPopulated(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	ownerAgentID := "owner-" + suffix
+
+	org, agent, key, err := testDB.CreateOrgWithOwnerAndKeyTx(ctx,
+		model.Organization{Name: "Test Org " + suffix, Slug: "test-org-" + suffix, Plan: "pro"},
+		model.Agent{AgentID: ownerAgentID, Name: "Owner", Role: model.RoleOrgOwner, Email: strPtr("owner@test.com")},
+		model.APIKey{Prefix: "ak_" + suffix[:6], KeyHash: "hash-signup-" + suffix, AgentID: ownerAgentID, Label: "initial key", CreatedBy: ownerAgentID},
+		storage.MutationAuditEntry{RequestID: "signup-org-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "organization"},
+		storage.MutationAuditEntry{RequestID: "signup-agent-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "agent"},
+		storage.MutationAuditEntry{RequestID: "signup-key-" + suffix, ActorAgentID: "system", ActorRole: "platform_admin", HTTPMethod: "POST", Endpoint: "/v1/signup", Operation: "create", ResourceType: "api_key"},
+	)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, org.ID)
+	assert.Equal(t, "pro", org.Plan)
+	assert.Equal(t, ownerAgentID, agent.AgentID)
+	assert.Equal(t, org.ID, agent.OrgID)
+	assert.NotEqual(t, uuid.Nil, key.ID)
+	assert.Equal(t, org.ID, key.OrgID)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — with Include (alternatives + evidence)
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_IncludeBothAlternativesAndEvidence(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "query-include-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create a decision with alternatives and evidence.
+	run, dec, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID, OrgID: uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "query_include_test_" + suffix,
+			Outcome:      "include test", Confidence: 0.8,
+			Metadata: map[string]any{},
+		},
+		Alternatives: []model.Alternative{
+			{Label: "Option A"},
+			{Label: "Option B"},
+		},
+		Evidence: []model.Evidence{
+			{SourceType: model.SourceAPIResponse, Content: "benchmark data", Metadata: map[string]any{}},
+		},
+	})
+	require.NoError(t, err)
+	_ = run
+
+	dt := "query_include_test_" + suffix
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{DecisionType: &dt},
+		Include: []string{"alternatives", "evidence"},
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, dec.ID, decisions[0].ID)
+	assert.Len(t, decisions[0].Alternatives, 2, "should include 2 alternatives")
+	assert.Len(t, decisions[0].Evidence, 1, "should include 1 evidence")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — with TraceID filter
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_WithTraceIDFilter(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "query-trace-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	traceID := "trace-" + suffix
+	_, _, err = testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID, OrgID: uuid.Nil, TraceID: &traceID,
+		Decision: model.Decision{
+			DecisionType: "trace_filter_test", Outcome: "traced", Confidence: 0.9,
+			Metadata: map[string]any{},
+		},
+	})
+	require.NoError(t, err)
+
+	// Query with trace ID filter.
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		TraceID: &traceID,
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 1)
+	assert.GreaterOrEqual(t, len(decisions), 1)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — quality_score order by (deprecated alias)
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_OrderByQualityScore(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		OrderBy: "quality_score",
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(decisions), 5)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisionsTemporal — with limit edge cases
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisionsTemporal_DefaultLimit(t *testing.T) {
+	ctx := context.Background()
+	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
+		AsOf:  time.Now().UTC(),
+		Limit: 0, // should default to 100
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(decisions), 100)
+}
+
+func TestQueryDecisionsTemporal_LargeLimit(t *testing.T) {
+	ctx := context.Background()
+	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
+		AsOf:  time.Now().UTC(),
+		Limit: 5000, // should be capped to 1000
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(decisions), 1000)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: Assessment outcomes — partially_correct and incorrect
+// ---------------------------------------------------------------------------
+
+func TestCreateAssessment_PartiallyCorrectAndIncorrect(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "assess-variety-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "assess_variety_test", Outcome: "test outcome",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create assessments with all three outcome types.
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      dec.ID,
+		AssessorAgentID: "assessor-a-" + suffix,
+		Outcome:         model.AssessmentCorrect,
+		Notes:           strPtr("looks good"),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      dec.ID,
+		AssessorAgentID: "assessor-b-" + suffix,
+		Outcome:         model.AssessmentIncorrect,
+		Notes:           strPtr("wrong approach"),
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.CreateAssessment(ctx, uuid.Nil, model.DecisionAssessment{
+		DecisionID:      dec.ID,
+		AssessorAgentID: "assessor-c-" + suffix,
+		Outcome:         model.AssessmentPartiallyCorrect,
+		Notes:           strPtr("partially right"),
+	})
+	require.NoError(t, err)
+
+	// Verify the summary covers all three types.
+	summary, err := testDB.GetAssessmentSummary(ctx, uuid.Nil, dec.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Correct)
+	assert.Equal(t, 1, summary.Incorrect)
+	assert.Equal(t, 1, summary.PartiallyCorrect)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ExportDecisionsCursor — with cursor pagination
+// ---------------------------------------------------------------------------
+
+func TestExportDecisionsCursor_WithCursorPagination(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "export-cursor-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	dt := "export_cursor_test_" + suffix
+
+	// Create several decisions.
+	for i := 0; i < 5; i++ {
+		r, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+		require.NoError(t, err)
+		_, err = testDB.CreateDecision(ctx, model.Decision{
+			RunID: r.ID, AgentID: agentID,
+			DecisionType: dt, Outcome: fmt.Sprintf("outcome %d", i),
+			Confidence: 0.5, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	// First page.
+	page1, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{DecisionType: &dt}, nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, page1, 2)
+
+	// Second page using cursor from first page.
+	cursor := &storage.ExportCursor{
+		ValidFrom: page1[1].ValidFrom,
+		ID:        page1[1].ID,
+	}
+	page2, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{DecisionType: &dt}, cursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, page2, 2)
+
+	// Verify no overlap between pages.
+	for _, d1 := range page1 {
+		for _, d2 := range page2 {
+			assert.NotEqual(t, d1.ID, d2.ID, "pages should not overlap")
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — with multiple filter types
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_WithOutcomeFilter(t *testing.T) {
+	ctx := context.Background()
+	outcome := "nonexistent-outcome-" + uuid.New().String()[:8]
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{Outcome: &outcome},
+		Limit:   10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
+}
+
+func TestQueryDecisions_WithConfidenceMinFilter(t *testing.T) {
+	ctx := context.Background()
+	confMin := float32(0.99)
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{ConfidenceMin: &confMin},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	for _, d := range decisions {
+		assert.GreaterOrEqual(t, d.Confidence, float32(0.99))
+	}
+}
+
+func TestQueryDecisions_WithTimeRangeFilter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	from := now.Add(-1 * time.Hour)
+	to := now.Add(1 * time.Hour)
+	_, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{TimeRange: &model.TimeRange{From: &from, To: &to}},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 0)
+}
+
+func TestQueryDecisions_WithSessionFilter(t *testing.T) {
+	ctx := context.Background()
+	sessionID := uuid.New()
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{SessionID: &sessionID},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
+}
+
+func TestQueryDecisions_WithProjectFilter(t *testing.T) {
+	ctx := context.Background()
+	project := "nonexistent-project"
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{Project: &project},
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetConflictStatusCounts
+// ---------------------------------------------------------------------------
+
+func TestGetConflictStatusCounts_StructureVerification(t *testing.T) {
+	ctx := context.Background()
+	counts, err := testDB.GetConflictStatusCounts(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, counts.Total, 0)
+	assert.GreaterOrEqual(t, counts.Open, 0)
+	assert.GreaterOrEqual(t, counts.Resolved, 0)
+	assert.GreaterOrEqual(t, counts.FalsePositive, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetConflict — not found
+// ---------------------------------------------------------------------------
+
+func TestGetConflict_NotFound(t *testing.T) {
+	ctx := context.Background()
+	// GetConflict returns nil, nil when not found (not ErrNotFound).
+	conflict, err := testDB.GetConflict(ctx, uuid.New(), uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, conflict)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ListConflicts — with severity filter
+// ---------------------------------------------------------------------------
+
+func TestListConflicts_SeverityFilter_Coverage(t *testing.T) {
+	ctx := context.Background()
+	sev := "critical"
+	cat := "factual"
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		Severity: &sev,
+		Category: &cat,
+	}, 10, 0)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(conflicts), 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetDecisionsByAgent — with filters exercised
+// ---------------------------------------------------------------------------
+
+func TestGetDecisionsByAgent_WithTimeRange(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	from := now.Add(-1 * time.Hour)
+	to := now.Add(1 * time.Hour)
+	decisions, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, "nonexistent", 10, 0, &from, &to)
+	require.NoError(t, err)
+	assert.Empty(t, decisions)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: MigrateAgentKeysToAPIKeys — no legacy keys (fast path)
+// ---------------------------------------------------------------------------
+
+func TestMigrateAgentKeysToAPIKeys_NoLegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	// All agents in tests use managed API keys, so migration should find nothing.
+	migrated, err := testDB.MigrateAgentKeysToAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated, "no agents should have legacy keys to migrate")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CreateAlternativesBatch — empty batch
+// ---------------------------------------------------------------------------
+
+func TestCreateAlternativesBatch_Empty(t *testing.T) {
+	ctx := context.Background()
+	err := testDB.CreateAlternativesBatch(ctx, nil)
+	require.NoError(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: SearchDecisionsByText — FTS then ILIKE fallback
+// ---------------------------------------------------------------------------
+
+func TestSearchDecisionsByText_WithFilters(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "search-text-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "search_text_test", Outcome: "use postgres for data storage " + suffix,
+		Confidence: 0.9, Reasoning: strPtr("ACID compliance"),
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	results, err := testDB.SearchDecisionsByText(ctx, uuid.Nil, "postgres "+suffix, model.QueryFilters{
+		AgentIDs: []string{agentID},
+	}, 5)
+	require.NoError(t, err)
+	// Should find via FTS or ILIKE fallback.
+	assert.GreaterOrEqual(t, len(results), 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CountConflicts — with decision type filter
+// ---------------------------------------------------------------------------
+
+func TestCountConflicts_WithDecisionTypeFilter(t *testing.T) {
+	ctx := context.Background()
+	dt := "architecture"
+	count, err := testDB.CountConflicts(ctx, uuid.Nil, storage.ConflictFilters{
+		DecisionType: &dt,
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, count, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetEvidenceCoverageStats
+// ---------------------------------------------------------------------------
+
+func TestGetEvidenceCoverageStats_Structure(t *testing.T) {
+	ctx := context.Background()
+	stats, err := testDB.GetEvidenceCoverageStats(ctx, uuid.Nil, nil, nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, stats.TotalDecisions, 0)
+	assert.GreaterOrEqual(t, stats.TotalRecords, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ListAgentIDsBySharedTags
+// ---------------------------------------------------------------------------
+
+func TestListAgentIDsBySharedTags_WithNewTag(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	tag := "shared-tag-" + suffix
+	for i := 0; i < 2; i++ {
+		agentID := fmt.Sprintf("shared-tag-agent-%d-%s", i, suffix)
+		_, err := testDB.CreateAgent(ctx, model.Agent{
+			AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+			Tags: []string{tag}, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	ids, err := testDB.ListAgentIDsBySharedTags(ctx, uuid.Nil, []string{tag})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(ids), 2)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetAgentListStats — with freshly created agent
+// ---------------------------------------------------------------------------
+
+func TestGetAgentListStats_Fresh(t *testing.T) {
+	ctx := context.Background()
+
+	stats, err := testDB.GetAgentListStats(ctx, uuid.Nil)
+	require.NoError(t, err)
+	assert.NotNil(t, stats)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CountDecisionsByAPIKey
+// ---------------------------------------------------------------------------
+
+func TestCountDecisionsByAPIKey_WithTimeRange(t *testing.T) {
+	ctx := context.Background()
+	from := time.Now().UTC().Add(-24 * time.Hour)
+	to := time.Now().UTC().Add(24 * time.Hour)
+	counts, total, err := testDB.CountDecisionsByAPIKey(ctx, uuid.Nil, from, to)
+	require.NoError(t, err)
+	assert.NotNil(t, counts)
+	assert.GreaterOrEqual(t, total, 0)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: CreateEvidence and CreateEvidenceBatch
+// ---------------------------------------------------------------------------
+
+func TestCreateEvidenceBatch(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "evi-batch-" + suffix
+
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID, OrgID: uuid.Nil, Name: agentID, Role: model.RoleAgent,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	dec, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "evi_batch_test", Outcome: "test",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	err = testDB.CreateEvidenceBatch(ctx, []model.Evidence{
+		{DecisionID: dec.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence 1"},
+		{DecisionID: dec.ID, OrgID: uuid.Nil, SourceType: model.SourceAPIResponse, Content: "evidence 2"},
+	})
+	require.NoError(t, err)
+
+	evs, err := testDB.GetEvidenceByDecision(ctx, dec.ID, uuid.Nil, true)
+	require.NoError(t, err)
+	assert.Len(t, evs, 2)
+}
+
+func TestCreateEvidenceBatch_Empty(t *testing.T) {
+	ctx := context.Background()
+	err := testDB.CreateEvidenceBatch(ctx, nil)
+	require.NoError(t, err)
+}
+
+// ---------------------------------------------------------------------------
+// Helpers
+// ---------------------------------------------------------------------------
+
+// ---------------------------------------------------------------------------
+// Tests: GetConflictAnalytics — with actual scored conflict data
+// ---------------------------------------------------------------------------
+
+func TestGetConflictAnalytics_WithScoredConflict(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "analytics-a-" + suffix
+	agentB := "analytics-b-" + suffix
+	decType := "analytics_type_" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: decType, Outcome: "approach_alpha",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: decType, Outcome: "approach_beta",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	topicSim := 0.85
+	outcomeDiv := 0.75
+	sig := 0.70
+	sev := "high"
+	cat := "factual"
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     decType,
+		DecisionTypeB:     decType,
+		OutcomeA:          "approach_alpha",
+		OutcomeB:          "approach_beta",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "llm_v2",
+		Severity:          &sev,
+		Category:          &cat,
+	})
+	require.NoError(t, err)
+
+	// Query analytics for the org with a time range that includes now.
+	// The trend query uses generate_series(from::date, (to::date - '1 day')::date, '1 day'),
+	// so from and to must span at least 2 calendar days to produce rows.
+	now := time.Now().UTC()
+	analytics, err := testDB.GetConflictAnalytics(ctx, uuid.Nil, storage.ConflictAnalyticsFilters{
+		From: now.Add(-24 * time.Hour),
+		To:   now.Add(24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	// Summary should reflect at least the one conflict we inserted.
+	assert.GreaterOrEqual(t, analytics.Summary.TotalDetected, 1)
+
+	// ByAgentPair should have entries (covers the pairRows.Next() loop).
+	assert.NotEmpty(t, analytics.ByAgentPair, "expected at least one agent pair in analytics")
+
+	// ByDecisionType should have entries (covers the typeRows.Next() loop).
+	assert.NotEmpty(t, analytics.ByDecisionType, "expected at least one decision type in analytics")
+
+	// BySeverity should have entries (covers the sevRows.Next() loop).
+	assert.NotEmpty(t, analytics.BySeverity, "expected at least one severity in analytics")
+
+	// Trend should have entries (covers the trendRows.Next() loop — generate_series produces rows).
+	assert.NotEmpty(t, analytics.Trend, "expected at least one trend point in analytics")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetConflict — found
+// ---------------------------------------------------------------------------
+
+func TestGetConflict_Found(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "get-conflict-a-" + suffix
+	agentB := "get-conflict-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA,
+		DecisionType: "get_conflict_test", Outcome: "found_a",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB,
+		DecisionType: "get_conflict_test", Outcome: "found_b",
+		Confidence: 0.7, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.8
+	sig := 0.72
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind:      model.ConflictKindCrossAgent,
+		DecisionAID:       dA.ID,
+		DecisionBID:       dB.ID,
+		OrgID:             uuid.Nil,
+		AgentA:            agentA,
+		AgentB:            agentB,
+		DecisionTypeA:     "get_conflict_test",
+		DecisionTypeB:     "get_conflict_test",
+		OutcomeA:          "found_a",
+		OutcomeB:          "found_b",
+		TopicSimilarity:   &topicSim,
+		OutcomeDivergence: &outcomeDiv,
+		Significance:      &sig,
+		ScoringMethod:     "llm_v2",
+	})
+	require.NoError(t, err)
+
+	conflict, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, conflict, "expected conflict to be found")
+	assert.Equal(t, conflictID, conflict.ID)
+	assert.Equal(t, "open", conflict.Status)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — with RunID filter
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_WithRunIDFilter(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "runid-filter-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "runid_test", Outcome: "filtered_by_run",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{RunID: &run.ID},
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, total, 1)
+	for _, d := range decisions {
+		assert.Equal(t, run.ID, d.RunID)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — with Tool and Model filters
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_WithToolFilter(t *testing.T) {
+	ctx := context.Background()
+	tool := "nonexistent-tool-" + uuid.New().String()[:8]
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{Tool: &tool},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
+}
+
+func TestQueryDecisions_WithModelFilter(t *testing.T) {
+	ctx := context.Background()
+	mdl := "nonexistent-model-" + uuid.New().String()[:8]
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{Model: &mdl},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, decisions)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisions — ordering edge cases
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisions_OrderByOutcomeScore(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		OrderBy: "outcome_score",
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	_ = decisions // just verify the query runs without error
+}
+
+func TestQueryDecisions_OrderAsc(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		OrderBy:  "valid_from",
+		OrderDir: "asc",
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	_ = decisions
+}
+
+func TestQueryDecisions_LimitOver1000(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Limit: 5000,
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(decisions), 1000)
+}
+
+func TestQueryDecisions_NegativeOffsetClamped(t *testing.T) {
+	ctx := context.Background()
+	decisions, _, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Offset: -10,
+		Limit:  5,
+	})
+	require.NoError(t, err)
+	_ = decisions
+}
+
+// ---------------------------------------------------------------------------
+// Tests: QueryDecisionsTemporal — limit edge cases
+// ---------------------------------------------------------------------------
+
+func TestQueryDecisionsTemporal_LimitOver1000(t *testing.T) {
+	ctx := context.Background()
+	decisions, err := testDB.QueryDecisionsTemporal(ctx, uuid.Nil, model.TemporalQueryRequest{
+		AsOf:  time.Now().UTC(),
+		Limit: 5000,
+	})
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(decisions), 1000)
+}
+
+func TestGetDecisionsByAgent_LimitClamping(t *testing.T) {
+	ctx := context.Background()
+	// Limit <= 0 defaults to 50, limit > 1000 caps to 1000, offset < 0 defaults to 0.
+	decisions, _, err := testDB.GetDecisionsByAgent(ctx, uuid.Nil, "no-such-agent", 0, -1, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, decisions)
+
+	decisions, _, err = testDB.GetDecisionsByAgent(ctx, uuid.Nil, "no-such-agent", 9999, 0, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, decisions)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: ListRunsByAgent — limit edge cases
+// ---------------------------------------------------------------------------
+
+func TestListRunsByAgent_LimitClamping(t *testing.T) {
+	ctx := context.Background()
+	// Limit <= 0 defaults to 50, limit > 1000 caps to 1000, offset < 0 defaults to 0.
+	runs, total, err := testDB.ListRunsByAgent(ctx, uuid.Nil, "no-such-agent", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, runs)
+
+	runs, total, err = testDB.ListRunsByAgent(ctx, uuid.Nil, "no-such-agent", 9999, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, runs)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// ---------------------------------------------------------------------------
+// Integrity violation persistence + tampered proof detection (Finding #1, #9)
+// ---------------------------------------------------------------------------
+
+func TestCreateIntegrityViolation_And_GetViolations(t *testing.T) {
+	ctx := context.Background()
+
+	// Use the default test org (uuid.Nil).
+	orgID := uuid.Nil
+
+	// We need a proof to reference.
+	proof := storage.IntegrityProof{
+		OrgID:         orgID,
+		BatchStart:    time.Now().UTC().Add(-1 * time.Hour),
+		BatchEnd:      time.Now().UTC(),
+		DecisionCount: 5,
+		RootHash:      "viol-test-root-" + uuid.New().String()[:8],
+		CreatedAt:     time.Now().UTC(),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+	// Re-fetch to get the auto-generated ID.
+	fetched, err := testDB.GetLatestIntegrityProof(ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, fetched)
+
+	// Insert a violation.
+	v := storage.IntegrityViolation{
+		OrgID:         orgID,
+		ProofID:       fetched.ID,
+		ViolationType: "merkle_root_mismatch",
+		Details: map[string]any{
+			"stored_root":    "aaaa",
+			"decision_count": 5,
+		},
+		CreatedAt: time.Now().UTC(),
+	}
+	err = testDB.CreateIntegrityViolation(ctx, v)
+	require.NoError(t, err)
+
+	// Fetch violations.
+	violations, err := testDB.GetIntegrityViolations(ctx, orgID, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, violations)
+
+	found := false
+	for _, viol := range violations {
+		if viol.ProofID == fetched.ID && viol.ViolationType == "merkle_root_mismatch" {
+			found = true
+			assert.Equal(t, orgID, viol.OrgID)
+			assert.NotNil(t, viol.Details)
+			break
+		}
+	}
+	assert.True(t, found, "expected to find the created violation")
+}
+
+func TestVerifyBatchProof_DetectsTamperingIntegration(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "tamper-detect-" + suffix
+
+	// Use a dedicated org so other tests' decisions in the default org
+	// don't leak into our batch window and inflate the hash count.
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "tamper-org-"+suffix, "tamper-org-"+suffix)
+	require.NoError(t, err)
+
+	// CreateRun uses the default org; we need to insert the run manually
+	// under our isolated org so decisions land in the right org_id.
+	runID := uuid.New()
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
+		 VALUES ($1, $2, $3, 'running', now())`,
+		runID, orgID, agentID)
+	require.NoError(t, err)
+
+	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
+
+	// Create 4 decisions with content hashes.
+	const count = 4
+	for i := range count {
+		reasoning := fmt.Sprintf("reasoning %d", i)
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			ID:           uuid.New(),
+			RunID:        runID,
+			AgentID:      agentID,
+			OrgID:        orgID,
+			DecisionType: "tamper_test",
+			Outcome:      fmt.Sprintf("outcome %d for %s", i, suffix),
+			Confidence:   0.8,
+			Reasoning:    &reasoning,
+			ValidFrom:    time.Now().UTC(),
+		})
+		require.NoError(t, err)
+	}
+
+	afterCreate := time.Now().UTC().Add(1 * time.Second)
+
+	// Get hashes and build a legitimate Merkle root.
+	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	require.Len(t, hashes, count)
+
+	root, err := integrity.BuildMerkleRoot(hashes)
+	require.NoError(t, err)
+
+	// Verify passes with correct root.
+	ok, err := integrity.VerifyBatchProof(root, hashes)
+	require.NoError(t, err)
+	assert.True(t, ok, "legitimate proof should verify")
+
+	// Store the proof.
+	proof := storage.IntegrityProof{
+		OrgID:         orgID,
+		BatchStart:    beforeCreate,
+		BatchEnd:      afterCreate,
+		DecisionCount: count,
+		RootHash:      root,
+		CreatedAt:     time.Now().UTC(),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+
+	// Simulate tampering: the decisions table has an immutability trigger
+	// (correctly preventing direct mutation), so we simulate what would happen
+	// if the stored root was corrupted or if a decision was inserted/deleted
+	// outside the normal write path. We verify against a wrong root.
+	wrongRoot := "0000000000000000000000000000000000000000000000000000000000000000"
+	ok, err = integrity.VerifyBatchProof(wrongRoot, hashes)
+	require.NoError(t, err)
+	assert.False(t, ok, "wrong stored root should fail verification")
+
+	// Also verify that adding an extra hash (simulating an injected decision)
+	// causes verification to fail against the original root.
+	injectedHashes := make([]string, len(hashes)+1)
+	copy(injectedHashes, hashes)
+	injectedHashes[len(hashes)] = "zzz_injected_hash"
+	ok, err = integrity.VerifyBatchProof(root, injectedHashes)
+	require.NoError(t, err)
+	assert.False(t, ok, "injected hash should cause root mismatch")
+}
+
+func TestIntegrityViolation_ChainLinkageDetection(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.Nil
+	now := time.Now().UTC()
+
+	// Create two proofs with correct chain linkage.
+	proof1 := storage.IntegrityProof{
+		OrgID:         orgID,
+		BatchStart:    now.Add(-2 * time.Hour),
+		BatchEnd:      now.Add(-1 * time.Hour),
+		DecisionCount: 3,
+		RootHash:      "chain-root-1-" + uuid.New().String()[:8],
+		CreatedAt:     now.Add(-1 * time.Hour),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof1))
+
+	prevRoot := proof1.RootHash
+	proof2 := storage.IntegrityProof{
+		OrgID:         orgID,
+		BatchStart:    now.Add(-1 * time.Hour),
+		BatchEnd:      now,
+		DecisionCount: 5,
+		RootHash:      "chain-root-2-" + uuid.New().String()[:8],
+		PreviousRoot:  &prevRoot,
+		CreatedAt:     now,
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof2))
+
+	// Fetch recent proofs (newest-first).
+	proofs, err := testDB.GetRecentIntegrityProofs(ctx, orgID, 10)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(proofs), 2)
+
+	// Find our two proofs in the result (may have other proofs from other tests).
+	var newest, older *storage.IntegrityProof
+	for i := range proofs {
+		if proofs[i].RootHash == proof2.RootHash {
+			newest = &proofs[i]
+		}
+		if proofs[i].RootHash == proof1.RootHash {
+			older = &proofs[i]
+		}
+	}
+	require.NotNil(t, newest, "should find proof2 in recent proofs")
+	require.NotNil(t, older, "should find proof1 in recent proofs")
+
+	// Chain linkage should be valid.
+	require.NotNil(t, newest.PreviousRoot)
+	assert.Equal(t, older.RootHash, *newest.PreviousRoot, "chain linkage should match")
+
+	// Now create a proof with broken chain linkage.
+	brokenPrev := "wrong-root-hash"
+	proof3 := storage.IntegrityProof{
+		OrgID:         orgID,
+		BatchStart:    now,
+		BatchEnd:      now.Add(1 * time.Hour),
+		DecisionCount: 2,
+		RootHash:      "chain-root-3-" + uuid.New().String()[:8],
+		PreviousRoot:  &brokenPrev,
+		CreatedAt:     now.Add(1 * time.Second),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof3))
+
+	// Verify the broken linkage is detectable.
+	proofs, err = testDB.GetRecentIntegrityProofs(ctx, orgID, 10)
+	require.NoError(t, err)
+
+	var broken *storage.IntegrityProof
+	for i := range proofs {
+		if proofs[i].RootHash == proof3.RootHash {
+			broken = &proofs[i]
+			break
+		}
+	}
+	require.NotNil(t, broken)
+	require.NotNil(t, broken.PreviousRoot)
+	// The broken proof's previous_root should NOT match proof2's root.
+	assert.NotEqual(t, proof2.RootHash, *broken.PreviousRoot,
+		"chain linkage should be broken — previous_root doesn't match prior proof")
+
+	// Record a violation for the broken linkage.
+	err = testDB.CreateIntegrityViolation(ctx, storage.IntegrityViolation{
+		OrgID:         orgID,
+		ProofID:       broken.ID,
+		ViolationType: "chain_linkage_broken",
+		Details: map[string]any{
+			"expected_previous": proof2.RootHash,
+			"actual_previous":   *broken.PreviousRoot,
+		},
+		CreatedAt: time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	// Verify the violation was persisted.
+	violations, err := testDB.GetIntegrityViolations(ctx, orgID, 10)
+	require.NoError(t, err)
+	found := false
+	for _, v := range violations {
+		if v.ProofID == broken.ID && v.ViolationType == "chain_linkage_broken" {
+			found = true
+			break
+		}
+	}
+	assert.True(t, found, "chain linkage violation should be persisted and retrievable")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: proof_leaves — proofs survive retention purge and GDPR erasure
+// ---------------------------------------------------------------------------
+
+func TestProofLeaves_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "pl-org-"+suffix, "pl-org-"+suffix)
+	require.NoError(t, err)
+
+	proofID := uuid.New()
+	proof := storage.IntegrityProof{
+		ID:            proofID,
+		OrgID:         orgID,
+		BatchStart:    time.Now().UTC().Add(-1 * time.Hour),
+		BatchEnd:      time.Now().UTC(),
+		DecisionCount: 3,
+		RootHash:      "pl-root-" + suffix,
+		CreatedAt:     time.Now().UTC(),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+
+	leaves := []string{"aaa_hash", "bbb_hash", "ccc_hash"}
+	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, leaves))
+
+	got, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	require.NoError(t, err)
+	assert.Equal(t, leaves, got, "leaves should round-trip in sorted order")
+
+	// No leaves for a random proof.
+	empty, err := testDB.GetProofLeaves(ctx, orgID, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestProofLeaves_Empty(t *testing.T) {
+	ctx := context.Background()
+	// CreateProofLeaves with empty slice should be a no-op.
+	require.NoError(t, testDB.CreateProofLeaves(ctx, uuid.New(), uuid.Nil, nil))
+}
+
+func TestProofLeaves_SurvivesRetentionPurge(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "pl-retention-" + suffix
+
+	// Isolated org.
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
+		orgID, "pl-ret-org-"+suffix, "pl-ret-org-"+suffix)
+	require.NoError(t, err)
+
+	runID := uuid.New()
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
+		 VALUES ($1, $2, $3, 'running', now())`,
+		runID, orgID, agentID)
+	require.NoError(t, err)
+
+	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
+
+	// Create decisions.
+	const count = 3
+	for i := range count {
+		reasoning := fmt.Sprintf("reasoning %d %s", i, suffix)
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			ID:           uuid.New(),
+			RunID:        runID,
+			AgentID:      agentID,
+			OrgID:        orgID,
+			DecisionType: "retention_proof_test",
+			Outcome:      fmt.Sprintf("outcome %d %s", i, suffix),
+			Confidence:   0.7,
+			Reasoning:    &reasoning,
+			ValidFrom:    time.Now().UTC(),
+		})
+		require.NoError(t, err)
+	}
+
+	afterCreate := time.Now().UTC().Add(1 * time.Second)
+
+	// Build proof and snapshot leaves.
+	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	require.Len(t, hashes, count)
+
+	root, err := integrity.BuildMerkleRoot(hashes)
+	require.NoError(t, err)
+
+	proofID := uuid.New()
+	proof := storage.IntegrityProof{
+		ID:            proofID,
+		OrgID:         orgID,
+		BatchStart:    beforeCreate,
+		BatchEnd:      afterCreate,
+		DecisionCount: count,
+		RootHash:      root,
+		CreatedAt:     time.Now().UTC(),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, hashes))
+
+	// Verify passes before purge.
+	ok, err := integrity.VerifyBatchProof(root, hashes)
+	require.NoError(t, err)
+	require.True(t, ok, "proof should verify before purge")
+
+	// Purge all decisions (cutoff in the future).
+	_, err = testDB.BatchDeleteDecisions(ctx, orgID, time.Now().UTC().Add(1*time.Hour), nil, nil, nil, 100)
+	require.NoError(t, err)
+
+	// Decisions should be gone.
+	remaining, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	assert.Empty(t, remaining, "decisions should be purged")
+
+	// But proof_leaves survive — verification still passes.
+	savedLeaves, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	require.NoError(t, err)
+	require.Len(t, savedLeaves, count, "proof leaves should survive retention purge")
+
+	ok, err = integrity.VerifyBatchProof(root, savedLeaves)
+	require.NoError(t, err)
+	assert.True(t, ok, "proof should still verify after retention purge via saved leaves")
+}
+
+func TestProofLeaves_SurvivesGDPRErasure(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "pl-gdpr-" + suffix
+
+	// Isolated org.
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
+		orgID, "pl-gdpr-org-"+suffix, "pl-gdpr-org-"+suffix)
+	require.NoError(t, err)
+
+	runID := uuid.New()
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
+		 VALUES ($1, $2, $3, 'running', now())`,
+		runID, orgID, agentID)
+	require.NoError(t, err)
+
+	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
+
+	// Create a decision that will be erased.
+	decisionID := uuid.New()
+	reasoning := "sensitive reasoning " + suffix
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		ID:           decisionID,
+		RunID:        runID,
+		AgentID:      agentID,
+		OrgID:        orgID,
+		DecisionType: "gdpr_proof_test",
+		Outcome:      "sensitive outcome " + suffix,
+		Confidence:   0.9,
+		Reasoning:    &reasoning,
+		ValidFrom:    time.Now().UTC(),
+	})
+	require.NoError(t, err)
+
+	afterCreate := time.Now().UTC().Add(1 * time.Second)
+
+	// Build proof and snapshot leaves.
+	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	require.Len(t, hashes, 1)
+
+	root, err := integrity.BuildMerkleRoot(hashes)
+	require.NoError(t, err)
+
+	proofID := uuid.New()
+	proof := storage.IntegrityProof{
+		ID:            proofID,
+		OrgID:         orgID,
+		BatchStart:    beforeCreate,
+		BatchEnd:      afterCreate,
+		DecisionCount: 1,
+		RootHash:      root,
+		CreatedAt:     time.Now().UTC(),
+	}
+	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
+	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, hashes))
+
+	// Erase the decision (GDPR).
+	_, err = testDB.EraseDecision(ctx, orgID, decisionID, "GDPR request", "admin-"+suffix, nil)
+	require.NoError(t, err)
+
+	// The decision's content_hash has changed.
+	postErasureHashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	require.NoError(t, err)
+	require.Len(t, postErasureHashes, 1)
+	assert.NotEqual(t, hashes[0], postErasureHashes[0], "content_hash should change after erasure")
+
+	// Verifying against the decisions table would fail (false violation).
+	ok, err := integrity.VerifyBatchProof(root, postErasureHashes)
+	require.NoError(t, err)
+	assert.False(t, ok, "verifying against mutated decisions table should fail")
+
+	// But proof_leaves preserve the original hashes — verification passes.
+	savedLeaves, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	require.NoError(t, err)
+	require.Len(t, savedLeaves, 1)
+	assert.Equal(t, hashes[0], savedLeaves[0], "proof leaves should preserve original hash")
+
+	ok, err = integrity.VerifyBatchProof(root, savedLeaves)
+	require.NoError(t, err)
+	assert.True(t, ok, "proof should still verify after GDPR erasure via saved leaves")
+}
+
+// ---------------------------------------------------------------------------
+// GetDecisionLineage tests (issue #498)
+// ---------------------------------------------------------------------------
+
+func TestGetDecisionLineage_UpstreamPrecedent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-up-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	// Create a precedent decision.
+	precedent, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_test", Outcome: "original_approach",
+		Confidence: 0.8, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create a decision that cites the precedent.
+	child, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_test", Outcome: "follows_precedent",
+		Confidence: 0.85, PrecedentRef: &precedent.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	lineage, err := testDB.GetDecisionLineage(ctx, child.ID, child.OrgID, 20)
+	require.NoError(t, err)
+
+	assert.Equal(t, child.ID, lineage.DecisionID)
+	require.NotNil(t, lineage.PrecededBy, "child should have an upstream precedent")
+	assert.Equal(t, precedent.ID, lineage.PrecededBy.ID)
+	assert.Equal(t, "original_approach", lineage.PrecededBy.Outcome)
+}
+
+func TestGetDecisionLineage_DownstreamCitations(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-down-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	root, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_test", Outcome: "root_decision",
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create 3 decisions that cite root.
+	for i := range 3 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "lineage_test",
+			Outcome:      fmt.Sprintf("citer_%d_%s", i, suffix),
+			Confidence:   0.7, PrecedentRef: &root.ID,
+			Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	require.NoError(t, err)
+
+	assert.Nil(t, lineage.PrecededBy, "root has no precedent")
+	require.Len(t, lineage.CitedBy, 3, "root should be cited by 3 decisions")
+	assert.False(t, lineage.CitedByMore, "3 citations is within limit of 20")
+}
+
+func TestGetDecisionLineage_PaginationHasMore(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-page-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	root, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_page_test", Outcome: "root_" + suffix,
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create 5 citations with a small limit of 3 to test pagination.
+	for i := range 5 {
+		_, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "lineage_page_test",
+			Outcome:      fmt.Sprintf("page_citer_%d_%s", i, suffix),
+			Confidence:   0.6, PrecedentRef: &root.ID,
+			Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+	}
+
+	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 3)
+	require.NoError(t, err)
+
+	require.Len(t, lineage.CitedBy, 3, "should be capped at limit")
+	assert.True(t, lineage.CitedByMore, "5 citations exceeds limit of 3, has_more should be true")
+}
+
+func TestFindDecisionsCitingPrecedent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "cited-by-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	precedent, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "cited_by_test", Outcome: "precedent_" + suffix,
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	var citerIDs []uuid.UUID
+	for i := range 2 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID,
+			DecisionType: "cited_by_test",
+			Outcome:      fmt.Sprintf("citer_%d_%s", i, suffix),
+			Confidence:   0.7, PrecedentRef: &precedent.ID,
+			Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+		citerIDs = append(citerIDs, d.ID)
+	}
+
+	// An unrelated decision must not be returned.
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "cited_by_test", Outcome: "unrelated_" + suffix,
+		Confidence: 0.5, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Nor a citer in another namespace.
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "cited_by_test", Outcome: "staging_citer_" + suffix,
+		Confidence: 0.7, PrecedentRef: &precedent.ID, Namespace: "staging",
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	citing, err := testDB.FindDecisionsCitingPrecedent(ctx, precedent.OrgID, model.DefaultNamespace, precedent.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, citing, 2)
+	var gotIDs []uuid.UUID
+	for _, d := range citing {
+		assert.Equal(t, precedent.ID, *d.PrecedentRef)
+		gotIDs = append(gotIDs, d.ID)
+	}
+	assert.ElementsMatch(t, citerIDs, gotIDs)
+
+	limited, err := testDB.FindDecisionsCitingPrecedent(ctx, precedent.OrgID, model.DefaultNamespace, precedent.ID, 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func TestGetDecisionLineage_OrgIsolation(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-org-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	root, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_org_test", Outcome: "root_" + suffix,
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Citation within the same org (default org).
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_org_test", Outcome: "same_org_citer_" + suffix,
+		Confidence: 0.7, PrecedentRef: &root.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Query with a different org — should see neither the root nor its citation.
+	otherOrg := uuid.New()
+	_, err = testDB.GetDecisionLineage(ctx, root.ID, otherOrg, 20)
+	require.Error(t, err, "decision should not be found in a different org")
+
+	// Query with the correct org — should find the citation.
+	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	require.NoError(t, err)
+	require.Len(t, lineage.CitedBy, 1, "only the same-org citation should appear")
+}
+
+func TestGetDecisionLineage_SupersededExcludedFromCitedBy(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-supersede-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	root, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_supersede_test", Outcome: "root_" + suffix,
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create a citation that will later be superseded (revised).
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_supersede_test", Outcome: "will_be_revised_" + suffix,
+		Confidence: 0.7, PrecedentRef: &root.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Create a citation that stays active.
+	_, err = testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_supersede_test", Outcome: "stays_active_" + suffix,
+		Confidence: 0.75, PrecedentRef: &root.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Revise the first citation — this sets valid_to on the original.
+	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: root.OrgID,
+		DecisionType: "lineage_supersede_test", Outcome: "revised_version_" + suffix,
+		Confidence: 0.8, PrecedentRef: &root.ID,
+		Metadata: map[string]any{},
+	}, nil)
+	require.NoError(t, err)
+
+	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	require.NoError(t, err)
+
+	// Should see the active citation and the revised version, but NOT the superseded original.
+	for _, entry := range lineage.CitedBy {
+		assert.NotEqual(t, original.ID, entry.ID,
+			"superseded decision (valid_to IS NOT NULL) must not appear in cited_by")
+	}
+	assert.Len(t, lineage.CitedBy, 2,
+		"should have the still-active citation and the new revised version")
+}
+
+func TestGetDecisionLineage_EmptyState(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "lineage-empty-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	// Decision with no precedent and no citations.
+	standalone, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "lineage_empty_test", Outcome: "alone_" + suffix,
+		Confidence: 0.5, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	lineage, err := testDB.GetDecisionLineage(ctx, standalone.ID, standalone.OrgID, 20)
+	require.NoError(t, err)
+
+	assert.Equal(t, standalone.ID, lineage.DecisionID)
+	assert.Nil(t, lineage.PrecededBy, "standalone decision has no precedent")
+	assert.Empty(t, lineage.CitedBy, "standalone decision has no citations")
+	assert.False(t, lineage.CitedByMore)
+}
+
+func TestGetDecisionLineage_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testDB.GetDecisionLineage(ctx, uuid.New(), uuid.New(), 20)
+	require.Error(t, err, "querying a nonexistent decision should return an error")
+}
+
+func TestGetDecisionAncestry_SupersededAncestor(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "ancestry-" + suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+
+	grandparent, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "ancestry_test", Outcome: "grandparent_" + suffix,
+		Confidence: 0.9, Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	parent, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "ancestry_test", Outcome: "parent_" + suffix,
+		Confidence: 0.8, PrecedentRef: &grandparent.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+	child, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID,
+		DecisionType: "ancestry_test", Outcome: "child_" + suffix,
+		Confidence: 0.7, PrecedentRef: &parent.ID,
+		Metadata: map[string]any{},
+	})
+	require.NoError(t, err)
+
+	// Before any revision the chain is healthy.
+	health, err := testDB.GetDecisionAncestry(ctx, child.ID, child.OrgID)
+	require.NoError(t, err)
+	require.Len(t, health.Ancestors, 2)
+	assert.Equal(t, parent.ID, health.Ancestors[0].ID)
+	assert.Equal(t, 1, health.Ancestors[0].Depth)
+	assert.Equal(t, grandparent.ID, health.Ancestors[1].ID)
+	assert.Equal(t, 2, health.Ancestors[1].Depth)
+	assert.False(t, health.StaleAncestry)
+	assert.False(t, health.Truncated)
+
+	// Revising the grandparent makes the child's ancestry stale.
+	revised, err := testDB.ReviseDecision(ctx, grandparent.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: child.OrgID,
+		DecisionType: "ancestry_test", Outcome: "grandparent_revised_" + suffix,
+		Confidence: 0.6, Metadata: map[string]any{},
+	}, nil)
+	require.NoError(t, err)
+
+	health, err = testDB.GetDecisionAncestry(ctx, child.ID, child.OrgID)
+	require.NoError(t, err)
+	require.Len(t, health.Ancestors, 2)
+	assert.False(t, health.Ancestors[0].Stale(), "parent is still current")
+	gp := health.Ancestors[1]
+	assert.True(t, gp.Superseded)
+	require.NotNil(t, gp.SupersededBy)
+	assert.Equal(t, revised.ID, *gp.SupersededBy)
+	assert.False(t, gp.Expired, "a replaced decision is superseded, not expired")
+	assert.True(t, health.StaleAncestry)
+	assert.Equal(t, 1, health.StaleAncestors)
+}
+
+func TestGetDecisionAncestry_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := testDB.GetDecisionAncestry(ctx, uuid.New(), uuid.New())
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// ---------------------------------------------------------------------------
+// Tests: GetConflictResolution
+// ---------------------------------------------------------------------------
+
+func TestGetConflictResolution_ResolvedConflict(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "gcr-a-" + suffix
+	agentB := "gcr-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_test",
+		Outcome: "use gRPC", Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_test",
+		Outcome: "use REST", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "gcr_test", DecisionTypeB: "gcr_test",
+		OutcomeA: "use gRPC", OutcomeB: "use REST",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
+
+	// Before resolution, GetConflictResolution should return nil.
+	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Nil(t, res, "unresolved conflict should return nil")
+
+	// Resolve with dA as winner.
+	resNote := "gRPC chosen for performance"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "reviewer", &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "gcr-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "reviewer", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+
+	// Now GetConflictResolution should return the resolution.
+	res, err = testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, conflictID, res.ID)
+	assert.Equal(t, "gcr_test", res.DecisionType)
+	assert.Equal(t, dA.ID, res.WinningDecisionID)
+	assert.Equal(t, "use gRPC", res.WinningOutcome)
+	assert.Equal(t, "use REST", res.LosingOutcome)
+	// Agent slot mapping depends on UUID byte ordering vs string ordering
+	// (InsertScoredConflict normalizes them independently for group lookup).
+	// Verify both agents are present without assuming which is winner/loser.
+	agents := []string{res.WinningAgent, res.LosingAgent}
+	assert.Contains(t, agents, agentA)
+	assert.Contains(t, agents, agentB)
+	require.NotNil(t, res.ResolutionNote)
+	assert.Equal(t, "gRPC chosen for performance", *res.ResolutionNote)
+	assert.False(t, res.ResolvedAt.IsZero())
+}
+
+func TestGetConflictResolution_WinnerIsDecisionB(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "gcr-bwin-a-" + suffix
+	agentB := "gcr-bwin-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_bwin",
+		Outcome: "outcome_alpha", Confidence: 0.6,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_bwin",
+		Outcome: "outcome_beta", Confidence: 0.9,
+	})
+	require.NoError(t, err)
+
+	topicSim := 0.88
+	outcomeDiv := 0.82
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "gcr_bwin", DecisionTypeB: "gcr_bwin",
+		OutcomeA: "outcome_alpha", OutcomeB: "outcome_beta",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
+
+	// Resolve with dB as winner — tests the CASE expression reversal.
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "lead", nil, &dB.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "gcr-bwin-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "lead", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+
+	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, dB.ID, res.WinningDecisionID)
+	// InsertScoredConflict normalizes by UUID order (bytes.Compare), so the
+	// stored slot positions may differ from the caller's A/B designation.
+	// Verify the outcome mapping is consistent: winning outcome matches dB,
+	// losing outcome matches dA.
+	assert.Equal(t, "outcome_beta", res.WinningOutcome)
+	assert.Equal(t, "outcome_alpha", res.LosingOutcome)
+	// Verify agents are present (the exact mapping depends on UUID ordering
+	// which swaps all fields as a unit, so just check both agents appear).
+	agents := []string{res.WinningAgent, res.LosingAgent}
+	assert.Contains(t, agents, agentA)
+	assert.Contains(t, agents, agentB)
+	assert.Nil(t, res.Explanation, "no explanation was set")
+	assert.Nil(t, res.ResolutionNote, "no resolution note was set")
+}
+
+func TestGetConflictResolution_NotFound(t *testing.T) {
+	ctx := context.Background()
+
+	res, err := testDB.GetConflictResolution(ctx, uuid.New(), uuid.Nil)
+	require.NoError(t, err)
+	assert.Nil(t, res, "nonexistent conflict should return nil, not error")
+}
+
+func TestGetConflictResolution_WrongOrg(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "gcr-org-a-" + suffix
+	agentB := "gcr-org-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_org",
+		Outcome: "x", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_org",
+		Outcome: "y", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.8
+	sig := 0.72
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "gcr_org", DecisionTypeB: "gcr_org",
+		OutcomeA: "x", OutcomeB: "y",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
+
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "admin", nil, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "gcr-org-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+
+	// Query with a different org — should not find it.
+	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, res, "wrong org_id should not return the resolution")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: HasGroupParticipation
+// ---------------------------------------------------------------------------
+
+func TestHasGroupParticipation_BothPresent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "hgp-a-" + suffix
+	agentB := "hgp-b-" + suffix
+	agentC := "hgp-c-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+	runC, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentC})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_test",
+		Outcome: "approach_a", Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_test",
+		Outcome: "approach_b", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+	dC, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runC.ID, AgentID: agentC, DecisionType: "hgp_test",
+		Outcome: "approach_c", Confidence: 0.6,
+	})
+	require.NoError(t, err)
+
+	// Create a group manually.
+	var groupID uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_test', 'testing')
+		 RETURNING id`,
+		uuid.Nil, agentA, agentB,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	// Insert conflict A↔B in the group.
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "hgp_test", DecisionTypeB: "hgp_test",
+		OutcomeA: "approach_a", OutcomeB: "approach_b",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	})
+	require.NoError(t, err)
+
+	// Insert conflict A↔C in the same group.
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dC.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentC,
+		DecisionTypeA: "hgp_test", DecisionTypeB: "hgp_test",
+		OutcomeA: "approach_a", OutcomeB: "approach_c",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	})
+	require.NoError(t, err)
+
+	// B and C both participate in the group (B via A↔B, C via A↔C).
+	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dB.ID, dC.ID)
+	require.NoError(t, err)
+	assert.True(t, has, "both B and C participate in group via separate conflicts")
+
+	// A and B also both participate.
+	has, err = testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, dB.ID)
+	require.NoError(t, err)
+	assert.True(t, has, "A and B directly participate in the group")
+}
+
+func TestHasGroupParticipation_OnlyOnePresent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "hgp-one-a-" + suffix
+	agentB := "hgp-one-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_one",
+		Outcome: "x", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_one",
+		Outcome: "y", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+
+	// Create a group with A↔B conflict.
+	var groupID uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_one', 'test')
+		 RETURNING id`,
+		uuid.Nil, agentA, agentB,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "hgp_one", DecisionTypeB: "hgp_one",
+		OutcomeA: "x", OutcomeB: "y",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	})
+	require.NoError(t, err)
+
+	// Query with A and an outsider decision not in any conflict in the group.
+	outsider := uuid.New()
+	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, outsider)
+	require.NoError(t, err)
+	assert.False(t, has, "outsider decision does not participate in group")
+}
+
+func TestHasGroupParticipation_NeitherPresent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	// Create an empty group (no conflicts inserted).
+	var groupID uuid.UUID
+	err := testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_none_`+suffix+`', 'test')
+		 RETURNING id`,
+		uuid.Nil, "hgp-none-a-"+suffix, "hgp-none-b-"+suffix,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, uuid.New(), uuid.New())
+	require.NoError(t, err)
+	assert.False(t, has, "no decisions in group means no participation")
+}
+
+func TestHasGroupParticipation_WrongOrg(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "hgp-worg-a-" + suffix
+	agentB := "hgp-worg-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_worg",
+		Outcome: "a", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_worg",
+		Outcome: "b", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+
+	var groupID uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_worg', 'test')
+		 RETURNING id`,
+		uuid.Nil, agentA, agentB,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "hgp_worg", DecisionTypeB: "hgp_worg",
+		OutcomeA: "a", OutcomeB: "b",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	})
+	require.NoError(t, err)
+
+	// Wrong org should not see the participation.
+	has, err := testDB.HasGroupParticipation(ctx, uuid.New(), groupID, dA.ID, dB.ID)
+	require.NoError(t, err)
+	assert.False(t, has, "wrong org should not see participation")
+}
+
+func TestHasGroupParticipation_IgnoresFalsePositives(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	agentA := "hgp-fp-a-" + suffix
+	agentB := "hgp-fp-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.NoError(t, err)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_fp",
+		Outcome: "a", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_fp",
+		Outcome: "b", Confidence: 0.5,
+	})
+	require.NoError(t, err)
+
+	var groupID uuid.UUID
+	err = testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_fp', 'test')
+		 RETURNING id`,
+		uuid.Nil, agentA, agentB,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "hgp_fp", DecisionTypeB: "hgp_fp",
+		OutcomeA: "a", OutcomeB: "b",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	})
+	require.NoError(t, err)
+
+	// Mark as false_positive — HasGroupParticipation only counts open/resolved.
+	fpLabel := "unrelated_false_positive"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"false_positive", "admin", nil, nil, &fpLabel,
+		storage.MutationAuditEntry{
+			RequestID: "hgp-fp-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "false_positive_conflict", ResourceType: "conflict",
+		})
+	require.NoError(t, err)
+
+	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, dB.ID)
+	require.NoError(t, err)
+	assert.False(t, has, "false_positive conflicts should not count as participation")
+}
+
+// ---------------------------------------------------------------------------
+// Tests: IncrementGroupTimesReopened
+// ---------------------------------------------------------------------------
+
+func TestIncrementGroupTimesReopened(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	var groupID uuid.UUID
+	err := testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'igtr_`+suffix+`', 'test')
+		 RETURNING id`,
+		uuid.Nil, "igtr-a-"+suffix, "igtr-b-"+suffix,
+	).Scan(&groupID)
+	require.NoError(t, err)
+
+	// Verify initial value is 0.
+	var timesReopened int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
+	).Scan(&timesReopened)
+	require.NoError(t, err)
+	assert.Equal(t, 0, timesReopened, "initial times_reopened should be 0")
+
+	// Increment once.
+	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.Nil)
+	require.NoError(t, err)
+
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
+	).Scan(&timesReopened)
+	require.NoError(t, err)
+	assert.Equal(t, 1, timesReopened)
+
+	// Increment again — should be atomic and additive.
+	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.Nil)
 	require.NoError(t, err)
-	found := false
-	for _, v := range violations {
-		if v.ProofID == broken.ID && v.ViolationType == "chain_linkage_broken" {
-			found = true
-			break
-		}
-	}
-	assert.True(t, found, "chain linkage violation should be persisted and retrievable")
-}
 
-// ---------------------------------------------------------------------------
-// Tests: proof_leaves — proofs survive retention purge and GDPR erasure
-// ---------------------------------------------------------------------------
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
+	).Scan(&timesReopened)
+	require.NoError(t, err)
+	assert.Equal(t, 2, timesReopened)
+}
 
-func TestProofLeaves_CreateAndGet(t *testing.T) {
+func TestIncrementGroupTimesReopened_WrongOrg(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
-		orgID, "pl-org-"+suffix, "pl-org-"+suffix)
+	var groupID uuid.UUID
+	err := testDB.Pool().QueryRow(ctx,
+		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
+		 VALUES ($1, $2, $3, 'cross_agent', 'igtr_worg_`+suffix+`', 'test')
+		 RETURNING id`,
+		uuid.Nil, "igtr-worg-a-"+suffix, "igtr-worg-b-"+suffix,
+	).Scan(&groupID)
 	require.NoError(t, err)
 
-	proofID := uuid.New()
-	proof := storage.IntegrityProof{
-		ID:            proofID,
-		OrgID:         orgID,
-		BatchStart:    time.Now().UTC().Add(-1 * time.Hour),
-		BatchEnd:      time.Now().UTC(),
-		DecisionCount: 3,
-		RootHash:      "pl-root-" + suffix,
-		CreatedAt:     time.Now().UTC(),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
-
-	leaves := []string{"aaa_hash", "bbb_hash", "ccc_hash"}
-	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, leaves))
-
-	got, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	// Increment with wrong org — should not error but should not change anything.
+	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.New())
 	require.NoError(t, err)
-	assert.Equal(t, leaves, got, "leaves should round-trip in sorted order")
 
-	// No leaves for a random proof.
-	empty, err := testDB.GetProofLeaves(ctx, orgID, uuid.New())
+	var timesReopened int
+	err = testDB.Pool().QueryRow(ctx,
+		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
+	).Scan(&timesReopened)
 	require.NoError(t, err)
-	assert.Empty(t, empty)
+	assert.Equal(t, 0, timesReopened, "wrong org should not increment counter")
 }
 
-func TestProofLeaves_Empty(t *testing.T) {
-	ctx := context.Background()
-	// CreateProofLeaves with empty slice should be a no-op.
-	require.NoError(t, testDB.CreateProofLeaves(ctx, uuid.New(), uuid.Nil, nil))
+// ---------------------------------------------------------------------------
+// Tests: FindReopenedResolution
+// ---------------------------------------------------------------------------
+
+// makeEmbeddingAtDim creates a 1024-dim vector with `value` at dimension `dim`
+// and zeros elsewhere. Two vectors at the same dimension have cosine
+// similarity = 1.0; vectors at different dimensions are orthogonal (sim = 0.0).
+// Use different dims across tests to prevent cross-test interference in the
+// shared integration database.
+func makeEmbeddingAtDim(dim int, value float32) pgvector.Vector {
+	v := make([]float32, 1024)
+	v[dim] = value
+	return pgvector.NewVector(v)
 }
 
-func TestProofLeaves_SurvivesRetentionPurge(t *testing.T) {
+func TestFindReopenedResolution_MatchFound(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "pl-retention-" + suffix
 
-	// Isolated org.
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
-		orgID, "pl-ret-org-"+suffix, "pl-ret-org-"+suffix)
-	require.NoError(t, err)
+	agentA := "frr-a-" + suffix
+	agentB := "frr-b-" + suffix
 
-	runID := uuid.New()
-	_, err = testDB.Pool().Exec(ctx,
-		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
-		 VALUES ($1, $2, $3, 'running', now())`,
-		runID, orgID, agentID)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
-
-	// Create decisions.
-	const count = 3
-	for i := range count {
-		reasoning := fmt.Sprintf("reasoning %d %s", i, suffix)
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			ID:           uuid.New(),
-			RunID:        runID,
-			AgentID:      agentID,
-			OrgID:        orgID,
-			DecisionType: "retention_proof_test",
-			Outcome:      fmt.Sprintf("outcome %d %s", i, suffix),
-			Confidence:   0.7,
-			Reasoning:    &reasoning,
-			ValidFrom:    time.Now().UTC(),
-		})
-		require.NoError(t, err)
-	}
-
-	afterCreate := time.Now().UTC().Add(1 * time.Second)
+	// Create the original conflict pair with embeddings.
+	// Use dims 10/11 — unique to this test to avoid cross-test interference.
+	embWinner := makeEmbeddingAtDim(10, 1.0)
+	embLoser := makeEmbeddingAtDim(11, 1.0)
 
-	// Build proof and snapshot leaves.
-	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_test",
+		Outcome: "use Redis", Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embWinner)
 	require.NoError(t, err)
-	require.Len(t, hashes, count)
 
-	root, err := integrity.BuildMerkleRoot(hashes)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_test",
+		Outcome: "use Memcached", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embLoser)
 	require.NoError(t, err)
 
-	proofID := uuid.New()
-	proof := storage.IntegrityProof{
-		ID:            proofID,
-		OrgID:         orgID,
-		BatchStart:    beforeCreate,
-		BatchEnd:      afterCreate,
-		DecisionCount: count,
-		RootHash:      root,
-		CreatedAt:     time.Now().UTC(),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
-	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, hashes))
+	// Insert and resolve the original conflict with dA as winner.
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "frr_test", DecisionTypeB: "frr_test",
+		OutcomeA: "use Redis", OutcomeB: "use Memcached",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
+	require.NoError(t, err)
 
-	// Verify passes before purge.
-	ok, err := integrity.VerifyBatchProof(root, hashes)
+	resNote := "Redis wins"
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "reviewer", &resNote, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "frr-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "reviewer", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
 	require.NoError(t, err)
-	require.True(t, ok, "proof should verify before purge")
 
-	// Purge all decisions (cutoff in the future).
-	_, err = testDB.BatchDeleteDecisions(ctx, orgID, time.Now().UTC().Add(1*time.Hour), nil, nil, nil, 100)
+	// Create NEW decisions with embeddings in the same directions.
+	agentC := "frr-c-" + suffix
+	agentD := "frr-d-" + suffix
+	runC, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentC})
+	require.NoError(t, err)
+	runD, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentD})
 	require.NoError(t, err)
 
-	// Decisions should be gone.
-	remaining, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	// newEmbA aligns with the winner (dim 10), newEmbB aligns with the loser (dim 11).
+	newEmbA := makeEmbeddingAtDim(10, 0.95)
+	newEmbB := makeEmbeddingAtDim(11, 0.95)
+
+	dC, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runC.ID, AgentID: agentC, DecisionType: "frr_test",
+		Outcome: "use Redis again", Confidence: 0.9,
+	})
 	require.NoError(t, err)
-	assert.Empty(t, remaining, "decisions should be purged")
 
-	// But proof_leaves survive — verification still passes.
-	savedLeaves, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	dD, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runD.ID, AgentID: agentD, DecisionType: "frr_test",
+		Outcome: "use Memcached again", Confidence: 0.6,
+	})
 	require.NoError(t, err)
-	require.Len(t, savedLeaves, count, "proof leaves should survive retention purge")
 
-	ok, err = integrity.VerifyBatchProof(root, savedLeaves)
+	// FindReopenedResolution should find the prior resolution.
+	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
+		dC.ID, dD.ID, newEmbA, newEmbB, 0.80)
 	require.NoError(t, err)
-	assert.True(t, ok, "proof should still verify after retention purge via saved leaves")
+	require.NotNil(t, match, "should find the prior resolution that is contradicted")
+
+	assert.Equal(t, conflictID, match.ResolutionID)
+	assert.Equal(t, "use Redis", match.WinningOutcome)
+	// WinningAgent may not match the decision's original agent due to
+	// independent string normalization of agent slots in InsertScoredConflict.
+	assert.Contains(t, []string{agentA, agentB}, match.WinningAgent,
+		"winning agent should be one of the original agents")
+	require.NotNil(t, match.ResolutionNote)
+	assert.Equal(t, "Redis wins", *match.ResolutionNote)
+	assert.Greater(t, match.OutcomeSimilarity, 0.80)
 }
 
-func TestProofLeaves_SurvivesGDPRErasure(t *testing.T) {
+func TestFindReopenedResolution_NoMatch_BelowThreshold(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "pl-gdpr-" + suffix
 
-	// Isolated org.
-	orgID := uuid.New()
-	_, err := testDB.Pool().Exec(ctx,
-		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
-		orgID, "pl-gdpr-org-"+suffix, "pl-gdpr-org-"+suffix)
-	require.NoError(t, err)
+	agentA := "frr-lo-a-" + suffix
+	agentB := "frr-lo-b-" + suffix
 
-	runID := uuid.New()
-	_, err = testDB.Pool().Exec(ctx,
-		`INSERT INTO agent_runs (id, org_id, agent_id, status, created_at)
-		 VALUES ($1, $2, $3, 'running', now())`,
-		runID, orgID, agentID)
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-
-	beforeCreate := time.Now().UTC().Add(-1 * time.Second)
-
-	// Create a decision that will be erased.
-	decisionID := uuid.New()
-	reasoning := "sensitive reasoning " + suffix
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		ID:           decisionID,
-		RunID:        runID,
-		AgentID:      agentID,
-		OrgID:        orgID,
-		DecisionType: "gdpr_proof_test",
-		Outcome:      "sensitive outcome " + suffix,
-		Confidence:   0.9,
-		Reasoning:    &reasoning,
-		ValidFrom:    time.Now().UTC(),
-	})
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	afterCreate := time.Now().UTC().Add(1 * time.Second)
+	// Use dims 20/21 — unique to this test.
+	embA := makeEmbeddingAtDim(20, 1.0)
+	embB := makeEmbeddingAtDim(21, 1.0)
 
-	// Build proof and snapshot leaves.
-	hashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_lo",
+		Outcome: "approach_a", Confidence: 0.8,
+	})
 	require.NoError(t, err)
-	require.Len(t, hashes, 1)
-
-	root, err := integrity.BuildMerkleRoot(hashes)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
 	require.NoError(t, err)
 
-	proofID := uuid.New()
-	proof := storage.IntegrityProof{
-		ID:            proofID,
-		OrgID:         orgID,
-		BatchStart:    beforeCreate,
-		BatchEnd:      afterCreate,
-		DecisionCount: 1,
-		RootHash:      root,
-		CreatedAt:     time.Now().UTC(),
-	}
-	require.NoError(t, testDB.CreateIntegrityProof(ctx, proof))
-	require.NoError(t, testDB.CreateProofLeaves(ctx, proofID, orgID, hashes))
-
-	// Erase the decision (GDPR).
-	_, err = testDB.EraseDecision(ctx, orgID, decisionID, "GDPR request", "admin-"+suffix, nil)
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_lo",
+		Outcome: "approach_b", Confidence: 0.7,
+	})
 	require.NoError(t, err)
-
-	// The decision's content_hash has changed.
-	postErasureHashes, err := testDB.GetDecisionHashesForBatch(ctx, orgID, beforeCreate, afterCreate)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
 	require.NoError(t, err)
-	require.Len(t, postErasureHashes, 1)
-	assert.NotEqual(t, hashes[0], postErasureHashes[0], "content_hash should change after erasure")
 
-	// Verifying against the decisions table would fail (false violation).
-	ok, err := integrity.VerifyBatchProof(root, postErasureHashes)
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "frr_lo", DecisionTypeB: "frr_lo",
+		OutcomeA: "approach_a", OutcomeB: "approach_b",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
-	assert.False(t, ok, "verifying against mutated decisions table should fail")
 
-	// But proof_leaves preserve the original hashes — verification passes.
-	savedLeaves, err := testDB.GetProofLeaves(ctx, orgID, proofID)
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "admin", nil, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "frr-lo-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
+		})
 	require.NoError(t, err)
-	require.Len(t, savedLeaves, 1)
-	assert.Equal(t, hashes[0], savedLeaves[0], "proof leaves should preserve original hash")
 
-	ok, err = integrity.VerifyBatchProof(root, savedLeaves)
+	// Both new embeddings point in the same direction (dim 20) — no cross-match
+	// because there's no loser-aligned embedding.
+	sameDir := makeEmbeddingAtDim(20, 1.0)
+	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
+		uuid.New(), uuid.New(), sameDir, sameDir, 0.99)
 	require.NoError(t, err)
-	assert.True(t, ok, "proof should still verify after GDPR erasure via saved leaves")
+	assert.Nil(t, match, "embeddings that don't cross-match should return nil")
 }
 
-// ---------------------------------------------------------------------------
-// GetDecisionLineage tests (issue #498)
-// ---------------------------------------------------------------------------
-
-func TestGetDecisionLineage_UpstreamPrecedent(t *testing.T) {
+func TestFindReopenedResolution_NoMatch_UnresolvedConflict(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "lineage-up-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	agentA := "frr-unres-a-" + suffix
+	agentB := "frr-unres-b-" + suffix
+
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	require.NoError(t, err)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	// Create a precedent decision.
-	precedent, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_test", Outcome: "original_approach",
-		Confidence: 0.8, Metadata: map[string]any{},
+	// Use dims 30/31 — unique to this test.
+	embA := makeEmbeddingAtDim(30, 1.0)
+	embB := makeEmbeddingAtDim(31, 1.0)
+
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_unres",
+		Outcome: "x", Confidence: 0.8,
 	})
 	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
+	require.NoError(t, err)
 
-	// Create a decision that cites the precedent.
-	child, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_test", Outcome: "follows_precedent",
-		Confidence: 0.85, PrecedentRef: &precedent.ID,
-		Metadata: map[string]any{},
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_unres",
+		Outcome: "y", Confidence: 0.7,
 	})
 	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
+	require.NoError(t, err)
 
-	lineage, err := testDB.GetDecisionLineage(ctx, child.ID, child.OrgID, 20)
+	// Insert conflict but do NOT resolve it.
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "frr_unres", DecisionTypeB: "frr_unres",
+		OutcomeA: "x", OutcomeB: "y",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
+	})
 	require.NoError(t, err)
 
-	assert.Equal(t, child.ID, lineage.DecisionID)
-	require.NotNil(t, lineage.PrecededBy, "child should have an upstream precedent")
-	assert.Equal(t, precedent.ID, lineage.PrecededBy.ID)
-	assert.Equal(t, "original_approach", lineage.PrecededBy.Outcome)
+	// Even with matching embeddings, unresolved conflicts should not match.
+	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
+		uuid.New(), uuid.New(), embA, embB, 0.50)
+	require.NoError(t, err)
+	assert.Nil(t, match, "unresolved conflict should not be found")
 }
 
-func TestGetDecisionLineage_DownstreamCitations(t *testing.T) {
+func TestFindReopenedResolution_ExcludesSameDecisions(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-	agentID := "lineage-down-" + suffix
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
+	agentA := "frr-self-a-" + suffix
+	agentB := "frr-self-b-" + suffix
 
-	root, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_test", Outcome: "root_decision",
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
+	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
 	require.NoError(t, err)
-
-	// Create 3 decisions that cite root.
-	for i := range 3 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "lineage_test",
-			Outcome:      fmt.Sprintf("citer_%d_%s", i, suffix),
-			Confidence:   0.7, PrecedentRef: &root.ID,
-			Metadata: map[string]any{},
-		})
-		require.NoError(t, err)
-	}
-
-	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
 	require.NoError(t, err)
 
-	assert.Nil(t, lineage.PrecededBy, "root has no precedent")
-	require.Len(t, lineage.CitedBy, 3, "root should be cited by 3 decisions")
-	assert.False(t, lineage.CitedByMore, "3 citations is within limit of 20")
-}
+	// Use dims 40/41 — unique to this test.
+	embA := makeEmbeddingAtDim(40, 1.0)
+	embB := makeEmbeddingAtDim(41, 1.0)
 
-func TestGetDecisionLineage_PaginationHasMore(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "lineage-page-" + suffix
+	dA, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_self",
+		Outcome: "x", Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
+	require.NoError(t, err)
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	dB, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_self",
+		Outcome: "y", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
 	require.NoError(t, err)
 
-	root, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_page_test", Outcome: "root_" + suffix,
-		Confidence: 0.9, Metadata: map[string]any{},
+	topicSim := 0.9
+	outcomeDiv := 0.85
+	sig := topicSim * outcomeDiv
+	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
+		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
+		DecisionTypeA: "frr_self", DecisionTypeB: "frr_self",
+		OutcomeA: "x", OutcomeB: "y",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
+		Significance: &sig, ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	// Create 5 citations with a small limit of 3 to test pagination.
-	for i := range 5 {
-		_, err := testDB.CreateDecision(ctx, model.Decision{
-			RunID: run.ID, AgentID: agentID,
-			DecisionType: "lineage_page_test",
-			Outcome:      fmt.Sprintf("page_citer_%d_%s", i, suffix),
-			Confidence:   0.6, PrecedentRef: &root.ID,
-			Metadata: map[string]any{},
+	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
+		"resolved", "admin", nil, &dA.ID, nil,
+		storage.MutationAuditEntry{
+			RequestID: "frr-self-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "resolve_conflict", ResourceType: "conflict",
 		})
-		require.NoError(t, err)
-	}
-
-	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 3)
 	require.NoError(t, err)
 
-	require.Len(t, lineage.CitedBy, 3, "should be capped at limit")
-	assert.True(t, lineage.CitedByMore, "5 citations exceeds limit of 3, has_more should be true")
+	// Query with the SAME decision IDs as the resolved conflict — should be
+	// excluded to prevent a conflict from "reopening" itself.
+	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
+		dA.ID, dB.ID, embA, embB, 0.50)
+	require.NoError(t, err)
+	assert.Nil(t, match, "should exclude conflicts involving the same decisions")
 }
 
-func TestGetDecisionLineage_OrgIsolation(t *testing.T) {
+func TestListAuditEntries_AccessAndMutation(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "lineage-org-" + suffix
-
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
-	require.NoError(t, err)
-
-	root, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_org_test", Outcome: "root_" + suffix,
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	orgID := uuid.New()
+	target := uuid.New()
+	other := uuid.New()
 
-	// Citation within the same org (default org).
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_org_test", Outcome: "same_org_citer_" + suffix,
-		Confidence: 0.7, PrecedentRef: &root.ID,
-		Metadata: map[string]any{},
-	})
+	require.NoError(t, testDB.InsertAccessAudit(ctx, storage.AccessAuditEntry{
+		RequestID:    "req-access-1",
+		OrgID:        orgID,
+		ActorAgentID: "reader-a",
+		ActorRole:    "reader",
+		HTTPMethod:   "POST",
+		Endpoint:     "/v1/query",
+		ResourceType: "decision",
+		ResourceIDs:  []uuid.UUID{target, other},
+		ResultCount:  5,
+	}))
+	require.NoError(t, testDB.InsertAccessAudit(ctx, storage.AccessAuditEntry{
+		RequestID:    "req-access-2",
+		OrgID:        orgID,
+		ActorAgentID: "reader-b",
+		ActorRole:    "reader",
+		HTTPMethod:   "GET",
+		Endpoint:     "/v1/decisions/" + other.String(),
+		ResourceType: "decision",
+		ResourceIDs:  []uuid.UUID{other},
+		ResultCount:  1,
+	}))
+	require.NoError(t, testDB.InsertMutationAudit(ctx, storage.MutationAuditEntry{
+		RequestID:    "req-mutation-1",
+		OrgID:        orgID,
+		ActorAgentID: "admin",
+		ActorRole:    "admin",
+		HTTPMethod:   "PATCH",
+		Endpoint:     "/v1/decisions/" + target.String(),
+		Operation:    "decision_project_updated",
+		ResourceType: "decision",
+		ResourceID:   target.String(),
+	}))
+
+	// Access entries: newest first, with the untruncated result count.
+	entries, total, err := testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess}, 10, 0)
 	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "req-access-2", entries[0].RequestID)
+	assert.Equal(t, model.AuditTypeAccess, entries[1].Type)
+	assert.Equal(t, []uuid.UUID{target, other}, entries[1].ResourceIDs)
+	require.NotNil(t, entries[1].ResultCount)
+	assert.Equal(t, 5, *entries[1].ResultCount)
 
-	// Query with a different org — should see neither the root nor its citation.
-	otherOrg := uuid.New()
-	_, err = testDB.GetDecisionLineage(ctx, root.ID, otherOrg, 20)
-	require.Error(t, err, "decision should not be found in a different org")
+	// "Who looked at this decision": membership in resource_ids.
+	targetStr := target.String()
+	entries, total, err = testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess, ResourceID: &targetStr}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "reader-a", entries[0].ActorAgentID)
 
-	// Query with the correct org — should find the citation.
-	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	actor := "reader-b"
+	entries, _, err = testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess, ActorAgentID: &actor}, 10, 0)
 	require.NoError(t, err)
-	require.Len(t, lineage.CitedBy, 1, "only the same-org citation should appear")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-access-2", entries[0].RequestID)
+
+	// Default type is the mutation log, which does not see access entries.
+	entries, total, err = testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, model.AuditTypeMutation, entries[0].Type)
+	assert.Equal(t, "decision_project_updated", entries[0].Operation)
+	assert.Equal(t, targetStr, entries[0].ResourceID)
+	assert.Nil(t, entries[0].ResultCount)
+
+	// Access log rows are append-only.
+	_, err = testDB.Pool().Exec(ctx, `DELETE FROM access_audit_log WHERE org_id = $1`, orgID)
+	require.Error(t, err)
 }
 
-func TestGetDecisionLineage_SupersededExcludedFromCitedBy(t *testing.T) {
+func TestInsertAccessAuditBatch_RequestEntries(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "lineage-supersede-" + suffix
+	orgID := uuid.New()
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+	ptr := func(v int) *int { return &v }
+	ms := func(v float64) *float64 { return &v }
+
+	require.NoError(t, testDB.InsertAccessAuditBatch(ctx, []storage.AccessAuditEntry{
+		{OccurredAt: base, RequestID: "req-1", OrgID: orgID, ActorAgentID: "agent-a", ActorRole: "agent", HTTPMethod: "POST", Endpoint: "/v1/query", ResourceType: model.AccessResourceRequest, Status: ptr(200), LatencyMs: ms(1.5)},
+		{OccurredAt: base.Add(10 * time.Minute), RequestID: "req-2", OrgID: orgID, ActorAgentID: "agent-b", ActorRole: "agent", HTTPMethod: "GET", Endpoint: "/v1/decisions/recent", ResourceType: model.AccessResourceRequest, Status: ptr(200), LatencyMs: ms(2)},
+		{OccurredAt: base.Add(20 * time.Minute), RequestID: "req-3", OrgID: orgID, ActorAgentID: "agent-a", ActorRole: "agent", HTTPMethod: "GET", Endpoint: "/v1/conflicts", ResourceType: model.AccessResourceRequest, Status: ptr(403), LatencyMs: ms(0.25)},
+		{OccurredAt: base.Add(20 * time.Minute), RequestID: "req-3", OrgID: orgID, ActorAgentID: "agent-a", ActorRole: "agent", HTTPMethod: "GET", Endpoint: "/v1/decisions/recent", ResourceType: "decision", ResourceIDs: []uuid.UUID{uuid.New()}, ResultCount: 1},
+		{OccurredAt: base, RequestID: "req-other", OrgID: uuid.New(), ActorAgentID: "agent-a", ActorRole: "agent", HTTPMethod: "GET", Endpoint: "/v1/query", ResourceType: model.AccessResourceRequest, Status: ptr(200), LatencyMs: ms(1)},
+	}))
+
+	request := model.AccessResourceRequest
+	entries, total, err := testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess, ResourceType: &request}, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "read entries and other orgs' requests are excluded")
+	require.Len(t, entries, 3)
+	assert.Equal(t, "req-3", entries[0].RequestID, "newest first")
+	require.NotNil(t, entries[0].Status)
+	assert.Equal(t, 403, *entries[0].Status)
+	require.NotNil(t, entries[0].LatencyMs)
+	assert.InDelta(t, 0.25, *entries[0].LatencyMs, 1e-9)
+
+	agent := "agent-a"
+	entries, total, err = testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess, ActorAgentID: &agent}, 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "without resource_type both read and request entries are returned")
+	for _, e := range entries {
+		assert.Equal(t, "agent-a", e.ActorAgentID)
+		if e.ResourceType == "decision" {
+			assert.Nil(t, e.Status, "read entries carry no status")
+			assert.Nil(t, e.LatencyMs)
+		}
+	}
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	from, to := base.Add(5*time.Minute), base.Add(15*time.Minute)
+	entries, total, err = testDB.ListAuditEntries(ctx, orgID, storage.AuditFilters{Type: model.AuditTypeAccess, ResourceType: &request, From: &from, To: &to}, 50, 0)
 	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-2", entries[0].RequestID)
+}
 
-	root, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_supersede_test", Outcome: "root_" + suffix,
-		Confidence: 0.9, Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+func TestGetDecisionUsage_CountsEveryInsert(t *testing.T) {
+	ctx := context.Background()
+	agentID := "usage-" + uuid.New().String()[:8]
 
-	// Create a citation that will later be superseded (revised).
-	original, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_supersede_test", Outcome: "will_be_revised_" + suffix,
-		Confidence: 0.7, PrecedentRef: &root.ID,
-		Metadata: map[string]any{},
-	})
+	before, err := testDB.GetDecisionUsage(ctx, uuid.Nil, agentID, time.Now())
 	require.NoError(t, err)
+	assert.Zero(t, before.AgentDaily)
+	assert.Zero(t, before.AgentMonthly)
 
-	// Create a citation that stays active.
-	_, err = testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_supersede_test", Outcome: "stays_active_" + suffix,
-		Confidence: 0.75, PrecedentRef: &root.ID,
-		Metadata: map[string]any{},
-	})
-	require.NoError(t, err)
+	var last model.Decision
+	for i := range 3 {
+		_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: agentID,
+			OrgID:   uuid.Nil,
+			Decision: model.Decision{
+				DecisionType: "usage_test",
+				Outcome:      fmt.Sprintf("outcome %d", i),
+				Confidence:   0.5,
+			},
+		})
+		require.NoError(t, err)
+		last = d
+	}
 
-	// Revise the first citation — this sets valid_to on the original.
-	_, err = testDB.ReviseDecision(ctx, original.ID, model.Decision{
-		RunID: run.ID, AgentID: agentID, OrgID: root.OrgID,
-		DecisionType: "lineage_supersede_test", Outcome: "revised_version_" + suffix,
-		Confidence: 0.8, PrecedentRef: &root.ID,
-		Metadata: map[string]any{},
+	// Revisions are decision inserts too, and count toward usage.
+	_, err = testDB.ReviseDecision(ctx, last.ID, model.Decision{
+		RunID:        last.RunID,
+		AgentID:      agentID,
+		DecisionType: "usage_test",
+		Outcome:      "revised",
+		Confidence:   0.6,
 	}, nil)
 	require.NoError(t, err)
 
-	lineage, err := testDB.GetDecisionLineage(ctx, root.ID, root.OrgID, 20)
+	usage, err := testDB.GetDecisionUsage(ctx, uuid.Nil, agentID, time.Now())
 	require.NoError(t, err)
+	assert.Equal(t, int64(4), usage.AgentDaily)
+	assert.Equal(t, int64(4), usage.AgentMonthly)
+	assert.GreaterOrEqual(t, usage.OrgDaily, usage.AgentDaily)
+	assert.GreaterOrEqual(t, usage.OrgMonthly, usage.OrgDaily)
 
-	// Should see the active citation and the revised version, but NOT the superseded original.
-	for _, entry := range lineage.CitedBy {
-		assert.NotEqual(t, original.ID, entry.ID,
-			"superseded decision (valid_to IS NOT NULL) must not appear in cited_by")
-	}
-	assert.Len(t, lineage.CitedBy, 2,
-		"should have the still-active citation and the new revised version")
+	// A different org sees none of it.
+	other, err := testDB.GetDecisionUsage(ctx, uuid.New(), agentID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, model.DecisionUsage{}, other)
 }
 
-func TestGetDecisionLineage_EmptyState(t *testing.T) {
+func TestReviewQueue_LowConfidenceFlagLifecycle(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-	agentID := "lineage-empty-" + suffix
+	agentID := "review-" + uuid.New().String()[:8]
 
-	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "review_test",
+			Outcome:      "unsure",
+			Confidence:   0.2,
+		},
+	})
 	require.NoError(t, err)
 
-	// Decision with no precedent and no citations.
-	standalone, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: run.ID, AgentID: agentID,
-		DecisionType: "lineage_empty_test", Outcome: "alone_" + suffix,
-		Confidence: 0.5, Metadata: map[string]any{},
-	})
+	findItem := func(filters storage.ReviewQueueFilters) *model.ReviewQueueItem {
+		t.Helper()
+		items, _, err := testDB.ListReviewQueue(ctx, uuid.Nil, filters, 1000, 0)
+		require.NoError(t, err)
+		for i := range items {
+			if items[i].DecisionID == d.ID {
+				return &items[i]
+			}
+		}
+		return nil
+	}
+
+	// The insert trigger flags low-confidence decisions.
+	item := findItem(storage.ReviewQueueFilters{SLA: time.Hour, Reason: model.ReviewReasonLowConfidence})
+	require.NotNil(t, item, "low-confidence decision should be in the review queue")
+	assert.Equal(t, agentID, item.AgentID)
+	assert.False(t, item.Overdue)
+	assert.Nil(t, findItem(storage.ReviewQueueFilters{SLA: time.Hour, OverdueOnly: true}))
+
+	// A negative SLA puts the cutoff in the future, so the flag counts as overdue.
+	item = findItem(storage.ReviewQueueFilters{SLA: -time.Hour, OverdueOnly: true})
+	require.NotNil(t, item)
+	assert.True(t, item.Overdue)
+
+	// Manual re-flagging of a pending flag keeps the original flagged_at.
+	flaggedAt := item.FlaggedAt
+	note := "double-check"
+	f, err := testDB.FlagDecisionForReview(ctx, uuid.Nil, d.ID, "admin", &note)
 	require.NoError(t, err)
+	assert.Equal(t, model.ReviewReasonManual, f.Reason)
+	assert.True(t, flaggedAt.Equal(f.FlaggedAt))
 
-	lineage, err := testDB.GetDecisionLineage(ctx, standalone.ID, standalone.OrgID, 20)
+	// The overdue sweep claims each flag once.
+	claimed, err := testDB.ClaimOverdueReviewFlags(ctx, time.Now().Add(time.Hour), 10000)
+	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(claimed, func(f model.ReviewFlag) bool { return f.DecisionID == d.ID }))
+	claimed, err = testDB.ClaimOverdueReviewFlags(ctx, time.Now().Add(time.Hour), 10000)
 	require.NoError(t, err)
+	assert.False(t, slices.ContainsFunc(claimed, func(f model.ReviewFlag) bool { return f.DecisionID == d.ID }))
 
-	assert.Equal(t, standalone.ID, lineage.DecisionID)
-	assert.Nil(t, lineage.PrecededBy, "standalone decision has no precedent")
-	assert.Empty(t, lineage.CitedBy, "standalone decision has no citations")
-	assert.False(t, lineage.CitedByMore)
-}
+	// Reviewing removes it from the queue; a second review finds nothing pending.
+	reviewed, err := testDB.MarkDecisionReviewed(ctx, uuid.Nil, d.ID, "admin", nil)
+	require.NoError(t, err)
+	require.NotNil(t, reviewed.ReviewedAt)
+	assert.Nil(t, findItem(storage.ReviewQueueFilters{SLA: time.Hour}))
 
-func TestGetDecisionLineage_NotFound(t *testing.T) {
-	ctx := context.Background()
+	_, err = testDB.MarkDecisionReviewed(ctx, uuid.Nil, d.ID, "admin", nil)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 
-	_, err := testDB.GetDecisionLineage(ctx, uuid.New(), uuid.New(), 20)
-	require.Error(t, err, "querying a nonexistent decision should return an error")
+	// Other orgs cannot see or flag it.
+	_, err = testDB.FlagDecisionForReview(ctx, uuid.New(), d.ID, "admin", nil)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: GetConflictResolution
-// ---------------------------------------------------------------------------
-
-func TestGetConflictResolution_ResolvedConflict(t *testing.T) {
+func TestReviewFlagRouting(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
-
-	agentA := "gcr-a-" + suffix
-	agentB := "gcr-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
-	require.NoError(t, err)
+	agentID := "routing-" + uuid.New().String()[:8]
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_test",
-		Outcome: "use gRPC", Confidence: 0.8,
-	})
-	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_test",
-		Outcome: "use REST", Confidence: 0.7,
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Name:    agentID,
+		Role:    model.RoleAgent,
+		Tags:    []string{"payments"},
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "gcr_test", DecisionTypeB: "gcr_test",
-		OutcomeA: "use gRPC", OutcomeB: "use REST",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "routing_test",
+			Outcome:      "unsure",
+			Confidence:   0.1,
+		},
 	})
 	require.NoError(t, err)
 
-	// Before resolution, GetConflictResolution should return nil.
-	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
-	require.NoError(t, err)
-	assert.Nil(t, res, "unresolved conflict should return nil")
+	unrouted := func() *storage.UnroutedReviewFlag {
+		t.Helper()
+		flags, err := testDB.ListUnroutedReviewFlags(ctx, uuid.Nil, 10000)
+		require.NoError(t, err)
+		for i := range flags {
+			if flags[i].DecisionID == d.ID {
+				return &flags[i]
+			}
+		}
+		return nil
+	}
 
-	// Resolve with dA as winner.
-	resNote := "gRPC chosen for performance"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "reviewer", &resNote, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "gcr-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "reviewer", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	f := unrouted()
+	require.NotNil(t, f, "trigger-created flag starts unrouted")
+	assert.Equal(t, agentID, f.AgentID)
+	assert.Equal(t, []string{"payments"}, f.AgentTags)
+
+	require.NoError(t, testDB.SetReviewAssignments(ctx, uuid.Nil, []uuid.UUID{d.ID}, []string{"payments-lead"}))
+	assert.Nil(t, unrouted())
+
+	items, _, err := testDB.ListReviewQueue(ctx, uuid.Nil,
+		storage.ReviewQueueFilters{SLA: time.Hour, AssignedTo: "payments-lead"}, 1000, 0)
 	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(items, func(it model.ReviewQueueItem) bool { return it.DecisionID == d.ID }))
 
-	// Now GetConflictResolution should return the resolution.
-	res, err = testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
+	// Routing is applied once; a second assignment does not overwrite it.
+	require.NoError(t, testDB.SetReviewAssignments(ctx, uuid.Nil, []uuid.UUID{d.ID}, []string{"someone-else"}))
+	flag, err := testDB.GetReviewFlag(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	require.NotNil(t, res)
+	require.NotNil(t, flag.AssignedReviewer)
+	assert.Equal(t, "payments-lead", *flag.AssignedReviewer)
 
-	assert.Equal(t, conflictID, res.ID)
-	assert.Equal(t, "gcr_test", res.DecisionType)
-	assert.Equal(t, dA.ID, res.WinningDecisionID)
-	assert.Equal(t, "use gRPC", res.WinningOutcome)
-	assert.Equal(t, "use REST", res.LosingOutcome)
-	// Agent slot mapping depends on UUID byte ordering vs string ordering
-	// (InsertScoredConflict normalizes them independently for group lookup).
-	// Verify both agents are present without assuming which is winner/loser.
-	agents := []string{res.WinningAgent, res.LosingAgent}
-	assert.Contains(t, agents, agentA)
-	assert.Contains(t, agents, agentB)
-	require.NotNil(t, res.ResolutionNote)
-	assert.Equal(t, "gRPC chosen for performance", *res.ResolutionNote)
-	assert.False(t, res.ResolvedAt.IsZero())
+	// Reviewing and re-flagging clears the assignment so the new flag is routed afresh.
+	_, err = testDB.MarkDecisionReviewed(ctx, uuid.Nil, d.ID, "admin", nil)
+	require.NoError(t, err)
+	flag, err = testDB.FlagDecisionForReview(ctx, uuid.Nil, d.ID, "admin", nil)
+	require.NoError(t, err)
+	assert.Nil(t, flag.AssignedReviewer)
+	assert.NotNil(t, unrouted())
 }
 
-func TestGetConflictResolution_WinnerIsDecisionB(t *testing.T) {
+func TestReembedJob_Lifecycle(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "reembed-" + suffix
 
-	agentA := "gcr-bwin-a-" + suffix
-	agentB := "gcr-bwin-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	// A dedicated org keeps the job's decision count and cursor walk exact.
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		orgID, "reembed-org-"+suffix, "reembed-org-"+suffix)
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+
+	for i := range 3 {
+		_, _, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+			AgentID: agentID,
+			OrgID:   orgID,
+			Decision: model.Decision{
+				DecisionType: "reembed_test",
+				Outcome:      fmt.Sprintf("outcome %d", i),
+				Confidence:   0.7,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = testDB.GetOpenReembedJob(ctx, orgID)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	job, err := testDB.CreateReembedJob(ctx, orgID, "old-model", "new-model", "tok", "admin")
 	require.NoError(t, err)
+	assert.Equal(t, model.ReembedStatusPending, job.Status)
+	assert.Equal(t, 3, job.Total)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_bwin",
-		Outcome: "outcome_alpha", Confidence: 0.6,
-	})
+	_, err = testDB.CreateReembedJob(ctx, orgID, "old-model", "new-model", "tok2", "admin")
+	require.Error(t, err)
+	assert.True(t, testDB.IsDuplicateKey(err), "only one open job per org")
+
+	// Walk the decisions with the keyset cursor, two at a time.
+	first, err := testDB.FindDecisionsForReembed(ctx, orgID, nil, 2)
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_bwin",
-		Outcome: "outcome_beta", Confidence: 0.9,
-	})
+	require.Len(t, first, 2)
+	last := first[1].ID
+	job, err = testDB.AdvanceReembedJob(ctx, orgID, job.ID, &last, 2, 0, false)
 	require.NoError(t, err)
+	assert.Equal(t, model.ReembedStatusRunning, job.Status)
+	assert.Equal(t, 2, job.Processed)
 
-	topicSim := 0.88
-	outcomeDiv := 0.82
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "gcr_bwin", DecisionTypeB: "gcr_bwin",
-		OutcomeA: "outcome_alpha", OutcomeB: "outcome_beta",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	open, err := testDB.GetOpenReembedJob(ctx, orgID)
 	require.NoError(t, err)
+	require.NotNil(t, open.CursorID)
+	rest, err := testDB.FindDecisionsForReembed(ctx, orgID, open.CursorID, 2)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.NotContains(t, []uuid.UUID{first[0].ID, first[1].ID}, rest[0].ID)
 
-	// Resolve with dB as winner — tests the CASE expression reversal.
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "lead", nil, &dB.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "gcr-bwin-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "lead", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	job, err = testDB.AdvanceReembedJob(ctx, orgID, job.ID, nil, 0, 1, true)
 	require.NoError(t, err)
+	assert.Equal(t, model.ReembedStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.Failed)
+	require.NotNil(t, job.CompletedAt)
+	assert.Equal(t, last, *job.CursorID, "a nil cursor keeps the previous one")
 
-	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.Nil)
+	_, err = testDB.GetOpenReembedJob(ctx, orgID)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	latest, err := testDB.GetLatestReembedJob(ctx, orgID)
 	require.NoError(t, err)
-	require.NotNil(t, res)
+	assert.Equal(t, job.ID, latest.ID)
 
-	assert.Equal(t, dB.ID, res.WinningDecisionID)
-	// InsertScoredConflict normalizes by UUID order (bytes.Compare), so the
-	// stored slot positions may differ from the caller's A/B designation.
-	// Verify the outcome mapping is consistent: winning outcome matches dB,
-	// losing outcome matches dA.
-	assert.Equal(t, "outcome_beta", res.WinningOutcome)
-	assert.Equal(t, "outcome_alpha", res.LosingOutcome)
-	// Verify agents are present (the exact mapping depends on UUID ordering
-	// which swaps all fields as a unit, so just check both agents appear).
-	agents := []string{res.WinningAgent, res.LosingAgent}
-	assert.Contains(t, agents, agentA)
-	assert.Contains(t, agents, agentB)
-	assert.Nil(t, res.Explanation, "no explanation was set")
-	assert.Nil(t, res.ResolutionNote, "no resolution note was set")
+	// Completed jobs cannot be advanced.
+	_, err = testDB.AdvanceReembedJob(ctx, orgID, job.ID, nil, 1, 0, false)
+	require.ErrorIs(t, err, storage.ErrNotFound)
 }
 
-func TestGetConflictResolution_NotFound(t *testing.T) {
+func TestRouteReviewFlags(t *testing.T) {
 	ctx := context.Background()
+	agentID := "route-" + uuid.New().String()[:8]
 
-	res, err := testDB.GetConflictResolution(ctx, uuid.New(), uuid.Nil)
+	_, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Name:    agentID,
+		Role:    model.RoleAgent,
+		Tags:    []string{"payments"},
+	})
 	require.NoError(t, err)
-	assert.Nil(t, res, "nonexistent conflict should return nil, not error")
-}
 
-func TestGetConflictResolution_WrongOrg(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	_, d, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "routing_test",
+			Outcome:      "unsure",
+			Confidence:   0.1,
+		},
+	})
+	require.NoError(t, err)
 
-	agentA := "gcr-org-a-" + suffix
-	agentB := "gcr-org-b-" + suffix
+	orgIDs, err := testDB.ListOrgsWithUnroutedReviewFlags(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, orgIDs, uuid.Nil)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	// Without a policy nothing is routed.
+	routed, err := testDB.RouteReviewFlags(ctx, uuid.Nil, nil)
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	assert.Zero(t, routed)
+
+	policy := &model.ReviewRoutingPolicy{
+		Routes: []model.ReviewRoute{{Tag: "payments", Reviewers: []string{"payments-lead"}}},
+	}
+	routed, err = testDB.RouteReviewFlags(ctx, uuid.Nil, policy)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, routed, 1)
+
+	flag, err := testDB.GetReviewFlag(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
+	require.NotNil(t, flag.AssignedReviewer)
+	assert.Equal(t, "payments-lead", *flag.AssignedReviewer)
+}
 
+func TestConflictSuggestion_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: "suggest-test"})
+	require.NoError(t, err)
 	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "gcr_org",
-		Outcome: "x", Confidence: 0.5,
+		RunID: run.ID, AgentID: "suggest-test",
+		DecisionType: "suggest_test", Outcome: "use redis", Confidence: 0.8,
 	})
 	require.NoError(t, err)
 	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "gcr_org",
-		Outcome: "y", Confidence: 0.5,
+		RunID: run.ID, AgentID: "suggest-counterpart",
+		DecisionType: "suggest_test", Outcome: "use memcached", Confidence: 0.7,
 	})
 	require.NoError(t, err)
 
-	topicSim := 0.9
-	outcomeDiv := 0.8
-	sig := 0.72
+	sig := 0.7
 	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "gcr_org", DecisionTypeB: "gcr_org",
-		OutcomeA: "x", OutcomeB: "y",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+		ConflictKind:  model.ConflictKindCrossAgent,
+		DecisionAID:   dA.ID,
+		DecisionBID:   dB.ID,
+		OrgID:         uuid.Nil,
+		AgentA:        "suggest-test",
+		AgentB:        "suggest-counterpart",
+		DecisionTypeA: "suggest_test",
+		DecisionTypeB: "suggest_test",
+		OutcomeA:      "use redis",
+		OutcomeB:      "use memcached",
+		Significance:  &sig,
+		ScoringMethod: "text",
 	})
 	require.NoError(t, err)
 
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "admin", nil, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "gcr-org-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	pending, err := testDB.ConflictsPendingSuggestion(ctx, 10000)
 	require.NoError(t, err)
+	assert.True(t, slices.ContainsFunc(pending, func(c model.DecisionConflict) bool { return c.ID == conflictID }))
 
-	// Query with a different org — should not find it.
-	res, err := testDB.GetConflictResolution(ctx, conflictID, uuid.New())
+	require.NoError(t, testDB.SetConflictSuggestion(ctx, uuid.Nil, conflictID, &model.ConflictSuggestion{
+		Explanation: "Both pick a cache for the same service.",
+		Resolution:  "Keep redis.",
+		Model:       "test-model",
+	}))
+	// A second write does not overwrite the first.
+	require.NoError(t, testDB.SetConflictSuggestion(ctx, uuid.Nil, conflictID, nil))
+
+	c, err := testDB.GetConflict(ctx, conflictID, uuid.Nil)
 	require.NoError(t, err)
-	assert.Nil(t, res, "wrong org_id should not return the resolution")
-}
+	require.NotNil(t, c)
+	require.NotNil(t, c.Suggestion)
+	assert.Equal(t, "Keep redis.", c.Suggestion.Resolution)
+	assert.Equal(t, "test-model", c.Suggestion.Model)
+	assert.False(t, c.Suggestion.GeneratedAt.IsZero())
 
-// ---------------------------------------------------------------------------
-// Tests: HasGroupParticipation
-// ---------------------------------------------------------------------------
+	pending, err = testDB.ConflictsPendingSuggestion(ctx, 10000)
+	require.NoError(t, err)
+	assert.False(t, slices.ContainsFunc(pending, func(c model.DecisionConflict) bool { return c.ID == conflictID }))
+}
 
-func TestHasGroupParticipation_BothPresent(t *testing.T) {
+// seedClaimCorpus creates n decisions of one new decision type with
+// claimsPerDecision random claim embeddings each, and returns the
+// first decision's ID and the decision type.
+func seedClaimCorpus(tb testing.TB, n, claimsPerDecision int) (uuid.UUID, string) {
+	tb.Helper()
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "claim-corpus-" + suffix
+	decisionType := "claim_corpus_" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(tb, err)
 
-	agentA := "hgp-a-" + suffix
-	agentB := "hgp-b-" + suffix
-	agentC := "hgp-c-" + suffix
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test data
+	var first uuid.UUID
+	for i := 0; i < n; i++ {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, DecisionType: decisionType,
+			Outcome: fmt.Sprintf("outcome %d", i), Confidence: 0.6, Metadata: map[string]any{},
+		})
+		require.NoError(tb, err)
+		if i == 0 {
+			first = d.ID
+		}
+		claims := make([]storage.Claim, claimsPerDecision)
+		for j := range claims {
+			vec := make([]float32, 1024)
+			for k := range vec {
+				vec[k] = rng.Float32() - 0.5
+			}
+			emb := pgvector.NewVector(vec)
+			claims[j] = storage.Claim{DecisionID: d.ID, OrgID: uuid.Nil, ClaimIdx: j, ClaimText: fmt.Sprintf("claim %d.%d", i, j), Embedding: &emb}
+		}
+		require.NoError(tb, testDB.InsertClaims(ctx, claims))
+	}
+	return first, decisionType
+}
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+// BenchmarkFindClaimOverlapDecisionIDs shows that claim-overlap retrieval
+// cost tracks claims x limit, not corpus size: each source claim is one ANN
+// probe of the claims index, where exhaustive claim-pair comparison would
+// grow linearly with the number of stored claims.
+func BenchmarkFindClaimOverlapDecisionIDs(b *testing.B) {
+	ctx := context.Background()
+	for _, decisions := range []int{250, 2500} {
+		source, decisionType := seedClaimCorpus(b, decisions, 4)
+		b.Run(fmt.Sprintf("claims=%d", decisions*4), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := testDB.FindClaimOverlapDecisionIDs(ctx, source, uuid.Nil, storage.ClaimOverlapQuery{
+					DecisionType: decisionType, Namespace: model.DefaultNamespace, Limit: 5, MinSimilarity: 0,
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindDecisionsByContentHash_RoundedHashes(t *testing.T) {
+	ctx := context.Background()
+	agentID := "hash-rounded-" + uuid.New().String()[:8]
+
+	_, traced, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:             agentID,
+		OrgID:               uuid.Nil,
+		ConfidencePrecision: 2,
+		Decision:            model.Decision{DecisionType: "hash_test", Outcome: "rounded", Confidence: 0.8712},
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.True(t, strings.HasPrefix(traced.ContentHash, "v2r2:"), "got %s", traced.ContentHash)
+	digest := traced.ContentHash[strings.LastIndex(traced.ContentHash, ":")+1:]
+
+	found, total, err := testDB.FindDecisionsByContentHash(ctx, uuid.Nil, model.DefaultNamespace, digest[:16], true, 10, 0)
 	require.NoError(t, err)
-	runC, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentC})
+	assert.Equal(t, 1, total, "prefix lookup must match rounded v2rN: hashes")
+	require.Len(t, found, 1)
+	assert.Equal(t, traced.ID, found[0].ID)
+
+	_, total, err = testDB.FindDecisionsByContentHash(ctx, uuid.Nil, "staging", digest[:16], true, 10, 0)
 	require.NoError(t, err)
+	assert.Zero(t, total, "lookups are scoped to the namespace")
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_test",
-		Outcome: "approach_a", Confidence: 0.8,
-	})
+	// Revisions round and hash confidence the same way traces do.
+	testDB.SetConfidencePrecision(2)
+	defer testDB.SetConfidencePrecision(0)
+	revised, err := testDB.ReviseDecision(ctx, traced.ID, model.Decision{
+		RunID: traced.RunID, AgentID: agentID, DecisionType: "hash_test", Outcome: "revised", Confidence: 0.6549,
+	}, nil)
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_test",
-		Outcome: "approach_b", Confidence: 0.7,
-	})
+	assert.InDelta(t, 0.65, revised.Confidence, 1e-6)
+	assert.True(t, strings.HasPrefix(revised.ContentHash, "v2r2:"), "got %s", revised.ContentHash)
+	assert.True(t, integrity.VerifyContentHash(revised.ContentHash, revised.ID, revised.DecisionType, revised.Outcome, revised.Confidence, revised.Reasoning, revised.ValidFrom))
+}
+
+func TestDecisionChangeLog(t *testing.T) {
+	ctx := context.Background()
+	agentID := "cdc-" + uuid.New().String()[:8]
+
+	_, err := testDB.SequenceDecisionChanges(ctx)
 	require.NoError(t, err)
-	dC, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runC.ID, AgentID: agentC, DecisionType: "hgp_test",
-		Outcome: "approach_c", Confidence: 0.6,
+	var cursor int64
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT COALESCE(max(lsn), 0) FROM decision_cdc`).Scan(&cursor))
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "cdc_test", Outcome: "approve", Confidence: 0.8,
 	})
 	require.NoError(t, err)
-
-	// Create a group manually.
-	var groupID uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_test', 'testing')
-		 RETURNING id`,
-		uuid.Nil, agentA, agentB,
-	).Scan(&groupID)
+	revised, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "cdc_test", Outcome: "deny", Confidence: 0.9,
+	}, nil)
 	require.NoError(t, err)
+	require.NoError(t, testDB.RetractDecision(ctx, uuid.Nil, revised.ID, "cdc test", agentID, nil))
 
-	// Insert conflict A↔B in the group.
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "hgp_test", DecisionTypeB: "hgp_test",
-		OutcomeA: "approach_a", OutcomeB: "approach_b",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
-	})
+	page, err := testDB.ListDecisionChanges(ctx, uuid.Nil, cursor, 1000)
 	require.NoError(t, err)
+	type entry struct {
+		op string
+		id uuid.UUID
+	}
+	var got []entry
+	var lastLSN int64
+	for _, c := range page.Changes {
+		assert.Greater(t, c.LSN, lastLSN, "LSNs must increase")
+		lastLSN = c.LSN
+		if c.DecisionID != original.ID && c.DecisionID != revised.ID {
+			continue
+		}
+		got = append(got, entry{c.Operation, c.DecisionID})
+		if c.Operation == model.ChangeOpRevise {
+			assert.Contains(t, string(c.Before), original.ID.String(), "revise carries the superseded row")
+			assert.Contains(t, string(c.After), `"outcome": "deny"`)
+			assert.NotContains(t, string(c.After), `"embedding"`)
+		}
+	}
+	assert.Equal(t, []entry{
+		{model.ChangeOpCreate, original.ID},
+		{model.ChangeOpArchive, original.ID},
+		{model.ChangeOpRevise, revised.ID},
+		{model.ChangeOpArchive, revised.ID},
+	}, got)
 
-	// Insert conflict A↔C in the same group.
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dC.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentC,
-		DecisionTypeA: "hgp_test", DecisionTypeB: "hgp_test",
-		OutcomeA: "approach_a", OutcomeB: "approach_c",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
-	})
+	// Polling from the last LSN returns nothing new for these decisions.
+	page, err = testDB.ListDecisionChanges(ctx, uuid.Nil, lastLSN, 1000)
 	require.NoError(t, err)
+	for _, c := range page.Changes {
+		assert.NotEqual(t, original.ID, c.DecisionID)
+		assert.NotEqual(t, revised.ID, c.DecisionID)
+	}
 
-	// B and C both participate in the group (B via A↔B, C via A↔C).
-	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dB.ID, dC.ID)
+	// Other orgs see none of it.
+	page, err = testDB.ListDecisionChanges(ctx, uuid.New(), cursor, 1000)
 	require.NoError(t, err)
-	assert.True(t, has, "both B and C participate in group via separate conflicts")
+	assert.Empty(t, page.Changes)
 
-	// A and B also both participate.
-	has, err = testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, dB.ID)
+	// Compaction removes entries up to the cutoff and advances the watermark.
+	deleted, err := testDB.CompactDecisionChanges(ctx, time.Now().Add(time.Minute))
 	require.NoError(t, err)
-	assert.True(t, has, "A and B directly participate in the group")
+	assert.GreaterOrEqual(t, deleted, int64(4))
+	page, err = testDB.ListDecisionChanges(ctx, uuid.Nil, cursor, 1000)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, page.CompactedLSN, lastLSN)
+	for _, c := range page.Changes {
+		assert.Greater(t, c.LSN, page.CompactedLSN)
+	}
 }
 
-func TestHasGroupParticipation_OnlyOnePresent(t *testing.T) {
+func TestEraseDecision_RedactsChangeLog(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	agentID := "cdc-erase-" + uuid.New().String()[:8]
 
-	agentA := "hgp-one-a-" + suffix
-	agentB := "hgp-one-b-" + suffix
+	_, err := testDB.SequenceDecisionChanges(ctx)
+	require.NoError(t, err)
+	var cursor int64
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT COALESCE(max(lsn), 0) FROM decision_cdc`).Scan(&cursor))
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	reasoning := "private reasoning"
+	original, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "cdc_erase_test",
+		Outcome: "private outcome", Reasoning: &reasoning, Confidence: 0.8,
+	})
+	require.NoError(t, err)
+	revised, err := testDB.ReviseDecision(ctx, original.ID, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "cdc_erase_test",
+		Outcome: "public outcome", Confidence: 0.9,
+	}, nil)
 	require.NoError(t, err)
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_one",
-		Outcome: "x", Confidence: 0.5,
-	})
+	_, err = testDB.EraseDecision(ctx, uuid.Nil, original.ID, "GDPR request", agentID, nil)
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_one",
-		Outcome: "y", Confidence: 0.5,
-	})
+
+	page, err := testDB.ListDecisionChanges(ctx, uuid.Nil, cursor, 1000)
 	require.NoError(t, err)
+	var seen int
+	for _, c := range page.Changes {
+		if c.DecisionID != original.ID && c.DecisionID != revised.ID {
+			continue
+		}
+		seen++
+		for _, image := range []json.RawMessage{c.Before, c.After} {
+			assert.NotContains(t, string(image), "private outcome", "%s entry leaks the erased outcome", c.Operation)
+			assert.NotContains(t, string(image), "private reasoning", "%s entry leaks the erased reasoning", c.Operation)
+		}
+		if c.Operation == model.ChangeOpRevise {
+			assert.Contains(t, string(c.Before), storage.ErasedSentinel, "revise before image is redacted")
+			assert.Contains(t, string(c.After), "public outcome", "the successor's own image is untouched")
+		}
+	}
+	assert.Equal(t, 3, seen, "create, archive, and revise entries remain after erasure")
+}
 
-	// Create a group with A↔B conflict.
-	var groupID uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_one', 'test')
-		 RETURNING id`,
-		uuid.Nil, agentA, agentB,
-	).Scan(&groupID)
+func TestOrgRateLimit(t *testing.T) {
+	ctx := context.Background()
+
+	limit, err := testDB.GetOrgRateLimit(ctx, uuid.Nil)
 	require.NoError(t, err)
+	assert.Nil(t, limit, "orgs default to the global limit")
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "hgp_one", DecisionTypeB: "hgp_one",
-		OutcomeA: "x", OutcomeB: "y",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
-	})
+	require.NoError(t, testDB.SetOrgRateLimit(ctx, uuid.Nil, &model.OrgRateLimit{RPS: 2.5, Burst: 10}))
+	t.Cleanup(func() { _ = testDB.SetOrgRateLimit(context.Background(), uuid.Nil, nil) })
+
+	limit, err = testDB.GetOrgRateLimit(ctx, uuid.Nil)
 	require.NoError(t, err)
+	require.NotNil(t, limit)
+	assert.Equal(t, model.OrgRateLimit{RPS: 2.5, Burst: 10}, *limit)
 
-	// Query with A and an outsider decision not in any conflict in the group.
-	outsider := uuid.New()
-	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, outsider)
+	require.NoError(t, testDB.SetOrgRateLimit(ctx, uuid.Nil, nil))
+	limit, err = testDB.GetOrgRateLimit(ctx, uuid.Nil)
 	require.NoError(t, err)
-	assert.False(t, has, "outsider decision does not participate in group")
+	assert.Nil(t, limit)
+
+	_, err = testDB.GetOrgRateLimit(ctx, uuid.New())
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.ErrorIs(t, testDB.SetOrgRateLimit(ctx, uuid.New(), nil), storage.ErrNotFound)
 }
 
-func TestHasGroupParticipation_NeitherPresent(t *testing.T) {
+func TestConflictThresholds(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	decisionType := "threshold_type_" + suffix
+	audit := storage.MutationAuditEntry{
+		RequestID: "threshold-" + suffix, OrgID: uuid.Nil,
+		ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "set_conflict_threshold", ResourceType: "conflict_threshold",
+	}
 
-	// Create an empty group (no conflicts inserted).
-	var groupID uuid.UUID
-	err := testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_none_`+suffix+`', 'test')
-		 RETURNING id`,
-		uuid.Nil, "hgp-none-a-"+suffix, "hgp-none-b-"+suffix,
-	).Scan(&groupID)
+	ct, err := testDB.UpsertConflictThreshold(ctx, model.ConflictThreshold{
+		OrgID: uuid.Nil, DecisionType: decisionType, SignificanceThreshold: 0.2, UpdatedBy: "admin",
+	}, audit)
+	require.NoError(t, err)
+	assert.False(t, ct.UpdatedAt.IsZero())
+
+	// Upserting again replaces the threshold rather than adding a row.
+	_, err = testDB.UpsertConflictThreshold(ctx, model.ConflictThreshold{
+		OrgID: uuid.Nil, DecisionType: decisionType, SignificanceThreshold: 0.45, UpdatedBy: "admin",
+	}, audit)
+	require.NoError(t, err)
+
+	list, err := testDB.ListConflictThresholds(ctx, uuid.Nil)
+	require.NoError(t, err)
+	var matches []model.ConflictThreshold
+	for _, c := range list {
+		if c.DecisionType == decisionType {
+			matches = append(matches, c)
+		}
+	}
+	require.Len(t, matches, 1)
+	assert.InDelta(t, 0.45, matches[0].SignificanceThreshold, 1e-9)
+
+	// The range check rejects thresholds outside (0, 1].
+	_, err = testDB.UpsertConflictThreshold(ctx, model.ConflictThreshold{
+		OrgID: uuid.Nil, DecisionType: decisionType, SignificanceThreshold: 1.5, UpdatedBy: "admin",
+	}, audit)
+	require.Error(t, err)
+
+	require.NoError(t, testDB.DeleteConflictThreshold(ctx, uuid.Nil, decisionType, audit))
+	err = testDB.DeleteConflictThreshold(ctx, uuid.Nil, decisionType, audit)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestImportDecisions_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	agentID := "import-rt-" + suffix
+
+	reasoning := "kafka needs a dedicated ops rotation"
+	rel := float32(0.7)
+	_, original, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:  agentID,
+		OrgID:    uuid.Nil,
+		Decision: model.Decision{DecisionType: "architecture", Outcome: "chose kafka for events", Confidence: 0.6},
+	})
+	require.NoError(t, err)
+	_, head, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID: agentID,
+		OrgID:   uuid.Nil,
+		Decision: model.Decision{
+			DecisionType: "architecture", Outcome: "chose postgres for events", Confidence: 0.8,
+			Reasoning: &reasoning, SupersedesID: &original.ID,
+		},
+		Alternatives: []model.Alternative{{Label: "kafka"}},
+		Evidence:     []model.Evidence{{SourceType: model.SourceDocument, Content: "ops survey", RelevanceScore: &rel}},
+	})
+	require.NoError(t, err)
+
+	// Export as GET /v1/export/decisions?export_mode=full_history would, then
+	// push each line through JSON to mirror the wire format.
+	heads, err := testDB.ExportDecisionsCursor(ctx, uuid.Nil, model.QueryFilters{AgentIDs: []string{agentID}}, nil, 100)
+	require.NoError(t, err)
+	require.Len(t, heads, 1)
+	chains, err := testDB.GetDecisionRevisionsBatch(ctx, uuid.Nil, []uuid.UUID{head.ID})
+	require.NoError(t, err)
+	var exported []model.Decision
+	for _, rev := range chains[head.ID] {
+		if rev.ID != head.ID {
+			exported = append(exported, rev)
+		}
+	}
+	exported = append(exported, heads[0])
+	raw, err := json.Marshal(exported)
+	require.NoError(t, err)
+	var lines []model.Decision
+	require.NoError(t, json.Unmarshal(raw, &lines))
+	require.Len(t, lines, 2)
+
+	dst, err := testTC.NewIsolatedTestDB(ctx, "import_rt_"+suffix, testutil.TestLogger())
 	require.NoError(t, err)
+	defer dst.Close(ctx)
 
-	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, uuid.New(), uuid.New())
+	audit := storage.MutationAuditEntry{RequestID: "import-" + suffix, ActorAgentID: "admin", ActorRole: "admin"}
+	results, err := dst.ImportDecisions(ctx, uuid.Nil, lines, audit)
 	require.NoError(t, err)
-	assert.False(t, has, "no decisions in group means no participation")
-}
+	for i, res := range results {
+		require.Equal(t, storage.ImportInserted, res.Status, "decision %d: %v", i, res.Err)
+	}
 
-func TestHasGroupParticipation_WrongOrg(t *testing.T) {
-	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	for _, id := range []uuid.UUID{original.ID, head.ID} {
+		want, err := testDB.GetDecision(ctx, uuid.Nil, id, storage.GetDecisionOpts{IncludeAlts: true, IncludeEvidence: true})
+		require.NoError(t, err)
+		got, err := dst.GetDecision(ctx, uuid.Nil, id, storage.GetDecisionOpts{IncludeAlts: true, IncludeEvidence: true})
+		require.NoError(t, err)
 
-	agentA := "hgp-worg-a-" + suffix
-	agentB := "hgp-worg-b-" + suffix
+		assert.Equal(t, want.RunID, got.RunID)
+		assert.Equal(t, want.AgentID, got.AgentID)
+		assert.Equal(t, want.DecisionType, got.DecisionType)
+		assert.Equal(t, want.Outcome, got.Outcome)
+		assert.Equal(t, want.Confidence, got.Confidence)
+		assert.Equal(t, want.Reasoning, got.Reasoning)
+		assert.Equal(t, want.SupersedesID, got.SupersedesID)
+		assert.Equal(t, want.OutcomeFlipped, got.OutcomeFlipped)
+		assert.Equal(t, want.ContentHash, got.ContentHash)
+		assert.True(t, want.ValidFrom.Equal(got.ValidFrom), "valid_from: want %v, got %v", want.ValidFrom, got.ValidFrom)
+		if want.ValidTo == nil {
+			assert.Nil(t, got.ValidTo)
+		} else {
+			require.NotNil(t, got.ValidTo)
+			assert.True(t, want.ValidTo.Equal(*got.ValidTo), "valid_to: want %v, got %v", want.ValidTo, got.ValidTo)
+		}
+		require.Len(t, got.Alternatives, len(want.Alternatives))
+		for i := range want.Alternatives {
+			assert.Equal(t, want.Alternatives[i].ID, got.Alternatives[i].ID)
+			assert.Equal(t, want.Alternatives[i].Label, got.Alternatives[i].Label)
+		}
+		require.Len(t, got.Evidence, len(want.Evidence))
+		for i := range want.Evidence {
+			assert.Equal(t, want.Evidence[i].ID, got.Evidence[i].ID)
+			assert.Equal(t, want.Evidence[i].Content, got.Evidence[i].Content)
+			assert.Equal(t, want.Evidence[i].RelevanceScore, got.Evidence[i].RelevanceScore)
+		}
+	}
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	// The revision chain is intact on the importing side.
+	chain, err := dst.GetDecisionRevisions(ctx, uuid.Nil, head.ID)
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	require.Len(t, chain, 2)
+	assert.Equal(t, original.ID, chain[0].ID)
+	assert.Equal(t, head.ID, chain[1].ID)
+
+	// Re-importing the same stream is a no-op.
+	results, err = dst.ImportDecisions(ctx, uuid.Nil, lines, audit)
 	require.NoError(t, err)
+	for _, res := range results {
+		assert.Equal(t, storage.ImportSkipped, res.Status)
+	}
+}
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_worg",
-		Outcome: "a", Confidence: 0.5,
+func TestImportDecisions_MissingPredecessorFails(t *testing.T) {
+	ctx := context.Background()
+	missing := uuid.New()
+	d := model.Decision{
+		ID: uuid.New(), RunID: uuid.New(), AgentID: "import-orphan-" + uuid.New().String()[:8],
+		DecisionType: "architecture", Outcome: "orphaned revision", Confidence: 0.5,
+		ValidFrom: time.Now().UTC(), SupersedesID: &missing,
+	}
+	results, err := testDB.ImportDecisions(ctx, uuid.Nil, []model.Decision{d}, storage.MutationAuditEntry{
+		RequestID: "import-orphan", ActorAgentID: "admin", ActorRole: "admin",
 	})
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_worg",
-		Outcome: "b", Confidence: 0.5,
+	require.Len(t, results, 1)
+	assert.Equal(t, storage.ImportFailed, results[0].Status)
+	require.Error(t, results[0].Err)
+	assert.Contains(t, results[0].Err.Error(), "not found")
+
+	_, err = testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestDecisionTags_AddRemoveList(t *testing.T) {
+	ctx := context.Background()
+	agentID := "dtags-" + uuid.New().String()[:8]
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "tag_test",
+		Outcome: "tagged", Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
 
-	var groupID uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_worg', 'test')
-		 RETURNING id`,
-		uuid.Nil, agentA, agentB,
-	).Scan(&groupID)
+	require.NoError(t, testDB.AddDecisionTags(ctx, uuid.Nil, d.ID, []string{"q3-launch", "postmortem"}))
+	// Re-adding an existing tag is a no-op.
+	require.NoError(t, testDB.AddDecisionTags(ctx, uuid.Nil, d.ID, []string{"postmortem"}))
+	tags, err := testDB.ListDecisionTags(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
+	assert.Equal(t, []string{"postmortem", "q3-launch"}, tags)
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "hgp_worg", DecisionTypeB: "hgp_worg",
-		OutcomeA: "a", OutcomeB: "b",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
-	})
+	require.NoError(t, testDB.RemoveDecisionTags(ctx, uuid.Nil, d.ID, []string{"q3-launch", "absent"}))
+	tags, err = testDB.ListDecisionTags(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
+	assert.Equal(t, []string{"postmortem"}, tags)
 
-	// Wrong org should not see the participation.
-	has, err := testDB.HasGroupParticipation(ctx, uuid.New(), groupID, dA.ID, dB.ID)
+	// Tags cannot be attached across orgs.
+	require.NoError(t, testDB.AddDecisionTags(ctx, uuid.New(), d.ID, []string{"foreign"}))
+	tags, err = testDB.ListDecisionTags(ctx, uuid.Nil, d.ID)
 	require.NoError(t, err)
-	assert.False(t, has, "wrong org should not see participation")
+	assert.Equal(t, []string{"postmortem"}, tags)
 }
 
-func TestHasGroupParticipation_IgnoresFalsePositives(t *testing.T) {
+func TestQueryDecisions_FilterByTags(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "dtags-query-" + suffix
+	tag := "release-" + suffix
 
-	agentA := "hgp-fp-a-" + suffix
-	agentB := "hgp-fp-b-" + suffix
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	var ids []uuid.UUID
+	for i := range 3 {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, DecisionType: "tag_test",
+			Outcome: fmt.Sprintf("outcome %d", i), Confidence: 0.8, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+	require.NoError(t, testDB.AddDecisionTags(ctx, uuid.Nil, ids[0], []string{tag}))
+	require.NoError(t, testDB.AddDecisionTags(ctx, uuid.Nil, ids[2], []string{tag, "other-" + suffix}))
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	decisions, total, err := testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{AgentIDs: []string{agentID}, Tags: []string{tag}},
+		Limit:   10,
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	assert.Equal(t, 2, total, "a decision with several matching tags is returned once")
+	got := make([]uuid.UUID, 0, len(decisions))
+	for _, d := range decisions {
+		got = append(got, d.ID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{ids[0], ids[2]}, got)
+
+	// Any one of the listed tags matches.
+	_, total, err = testDB.QueryDecisions(ctx, uuid.Nil, model.QueryRequest{
+		Filters: model.QueryFilters{AgentIDs: []string{agentID}, Tags: []string{"other-" + suffix, "missing"}},
+		Limit:   10,
+	})
 	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+}
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "hgp_fp",
-		Outcome: "a", Confidence: 0.5,
+func TestDeleteAgentData_CascadesDecisionTags(t *testing.T) {
+	ctx := context.Background()
+	agentID := "dtags-delete-" + uuid.New().String()[:8]
+
+	agent, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: agentID,
+		Name:    "Decision Tags Delete Agent",
+		Role:    model.RoleAgent,
 	})
 	require.NoError(t, err)
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "hgp_fp",
-		Outcome: "b", Confidence: 0.5,
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, OrgID: agent.OrgID, DecisionType: "tag_test",
+		Outcome: "to be erased", Confidence: 0.8, Metadata: map[string]any{},
 	})
 	require.NoError(t, err)
+	require.NoError(t, testDB.AddDecisionTags(ctx, agent.OrgID, d.ID, []string{"gdpr"}))
 
-	var groupID uuid.UUID
-	err = testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'hgp_fp', 'test')
-		 RETURNING id`,
-		uuid.Nil, agentA, agentB,
-	).Scan(&groupID)
+	_, err = testDB.DeleteAgentData(ctx, agent.OrgID, agentID, nil)
 	require.NoError(t, err)
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "hgp_fp", DecisionTypeB: "hgp_fp",
-		OutcomeA: "a", OutcomeB: "b",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text", GroupID: &groupID,
+	var n int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM decision_tags WHERE decision_id = $1`, d.ID).Scan(&n))
+	assert.Zero(t, n, "decision tags must be deleted with their decision")
+}
+
+func TestUpdateDecisionMetadata_MergesAndAudits(t *testing.T) {
+	ctx := context.Background()
+	agentID := "meta-patch-" + uuid.New().String()[:8]
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "meta_test",
+		Outcome: "ship it", Confidence: 0.8,
+		Metadata: map[string]any{"tool_version": "1.2.0", "ticket": "OPS-1", "owner": "infra"},
 	})
 	require.NoError(t, err)
 
-	// Mark as false_positive — HasGroupParticipation only counts open/resolved.
-	fpLabel := "unrelated_false_positive"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"false_positive", "admin", nil, nil, &fpLabel,
+	merged, err := testDB.UpdateDecisionMetadata(ctx, uuid.Nil, d.ID,
+		map[string]any{"tool_version": "1.2.1", "ticket_url": "https://example.com/OPS-1"},
 		storage.MutationAuditEntry{
-			RequestID: "hgp-fp-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "false_positive_conflict", ResourceType: "conflict",
+			RequestID: "meta-" + agentID, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "platform_admin",
 		})
 	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"tool_version": "1.2.1",
+		"ticket":       "OPS-1",
+		"owner":        "infra",
+		"ticket_url":   "https://example.com/OPS-1",
+	}, merged)
 
-	has, err := testDB.HasGroupParticipation(ctx, uuid.Nil, groupID, dA.ID, dB.ID)
+	got, err := testDB.GetDecision(ctx, uuid.Nil, d.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	assert.False(t, has, "false_positive conflicts should not count as participation")
-}
+	assert.Equal(t, merged, got.Metadata)
+	assert.Equal(t, d.Outcome, got.Outcome)
+	assert.Equal(t, d.ContentHash, got.ContentHash, "metadata patches must not change the content hash")
+	assert.Nil(t, got.ValidTo, "metadata patches must not create a revision")
 
-// ---------------------------------------------------------------------------
-// Tests: IncrementGroupTimesReopened
-// ---------------------------------------------------------------------------
+	var before, after map[string]any
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT before_data->'metadata', after_data->'metadata' FROM mutation_audit_log
+		 WHERE resource_type = 'decision' AND resource_id = $1 AND operation = 'decision_metadata_updated'`,
+		d.ID.String()).Scan(&before, &after))
+	assert.Equal(t, "1.2.0", before["tool_version"])
+	assert.Equal(t, "1.2.1", after["tool_version"])
 
-func TestIncrementGroupTimesReopened(t *testing.T) {
+	_, err = testDB.UpdateDecisionMetadata(ctx, uuid.Nil, uuid.New(), map[string]any{"a": 1}, storage.MutationAuditEntry{})
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestUpdateDecisionMetadata_RejectsSuperseded(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	agentID := "meta-superseded-" + uuid.New().String()[:8]
 
-	var groupID uuid.UUID
-	err := testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'igtr_`+suffix+`', 'test')
-		 RETURNING id`,
-		uuid.Nil, "igtr-a-"+suffix, "igtr-b-"+suffix,
-	).Scan(&groupID)
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	d, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "meta_test",
+		Outcome: "first take", Confidence: 0.6, Metadata: map[string]any{},
+	})
 	require.NoError(t, err)
+	require.NoError(t, testDB.RetractDecision(ctx, uuid.Nil, d.ID, "wrong", "admin", nil))
 
-	// Verify initial value is 0.
-	var timesReopened int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
-	).Scan(&timesReopened)
+	_, err = testDB.UpdateDecisionMetadata(ctx, uuid.Nil, d.ID, map[string]any{"ticket": "OPS-2"}, storage.MutationAuditEntry{})
+	assert.ErrorIs(t, err, storage.ErrDecisionSuperseded)
+}
+
+func TestUpsertConsensusDriftConflict(t *testing.T) {
+	ctx := context.Background()
+	agentID := "drift-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	assert.Equal(t, 0, timesReopened, "initial times_reopened should be 0")
 
-	// Increment once.
-	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.Nil)
+	var ids []uuid.UUID
+	for _, outcome := range []string{"use postgres", "use mongodb", "use mongodb"} {
+		d, err := testDB.CreateDecision(ctx, model.Decision{
+			RunID: run.ID, AgentID: agentID, DecisionType: "drift_test",
+			Outcome: outcome, Confidence: 0.8, Metadata: map[string]any{},
+		})
+		require.NoError(t, err)
+		ids = append(ids, d.ID)
+	}
+	majority, minority := ids[0], ids[1]
+	topicSim, outcomeDiv, sig := 0.9, 0.5, 0.45
+
+	c := model.DecisionConflict{
+		DecisionAID: majority, DecisionBID: minority, OrgID: uuid.Nil,
+		AgentA: agentID, AgentB: agentID,
+		DecisionTypeA: "drift_test", DecisionTypeB: "drift_test",
+		OutcomeA: "use postgres", OutcomeB: "use mongodb",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
+		MinorityIDs: []uuid.UUID{minority},
+	}
+	id, inserted, err := testDB.UpsertConsensusDriftConflict(ctx, c)
 	require.NoError(t, err)
+	assert.True(t, inserted)
 
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
-	).Scan(&timesReopened)
+	// Re-detection refreshes the same row with the grown minority.
+	c.MinorityIDs = []uuid.UUID{minority, ids[2]}
+	again, inserted, err := testDB.UpsertConsensusDriftConflict(ctx, c)
 	require.NoError(t, err)
-	assert.Equal(t, 1, timesReopened)
+	assert.False(t, inserted)
+	assert.Equal(t, id, again)
 
-	// Increment again — should be atomic and additive.
-	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.Nil)
+	kind := string(model.ConflictKindConsensusDrift)
+	conflicts, err := testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{ConflictKind: &kind, DecisionID: &minority}, 10, 0)
 	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	got := conflicts[0]
+	assert.Equal(t, model.ConflictKindConsensusDrift, got.ConflictKind)
+	assert.Equal(t, majority, got.DecisionAID, "majority exemplar stays on side A")
+	assert.ElementsMatch(t, []uuid.UUID{minority, ids[2]}, got.MinorityIDs)
 
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
-	).Scan(&timesReopened)
+	// A pairwise conflict on the same pair coexists with the drift row.
+	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
+		ConflictKind: model.ConflictKindSelfContradiction,
+		DecisionAID:  majority, DecisionBID: minority, OrgID: uuid.Nil,
+		AgentA: agentID, AgentB: agentID,
+		DecisionTypeA: "drift_test", DecisionTypeB: "drift_test",
+		OutcomeA: "use postgres", OutcomeB: "use mongodb",
+		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv, Significance: &sig,
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 2, timesReopened)
+	conflicts, err = testDB.ListConflicts(ctx, uuid.Nil, storage.ConflictFilters{DecisionID: &minority}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, conflicts, 2)
 }
 
-func TestIncrementGroupTimesReopened_WrongOrg(t *testing.T) {
+func TestRotateRefreshToken(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
-
-	var groupID uuid.UUID
-	err := testDB.Pool().QueryRow(ctx,
-		`INSERT INTO conflict_groups (org_id, agent_a, agent_b, conflict_kind, decision_type, group_topic)
-		 VALUES ($1, $2, $3, 'cross_agent', 'igtr_worg_`+suffix+`', 'test')
-		 RETURNING id`,
-		uuid.Nil, "igtr-worg-a-"+suffix, "igtr-worg-b-"+suffix,
-	).Scan(&groupID)
+	agentID := "refresh-" + suffix
+	_, err := testDB.CreateAgent(ctx, model.Agent{AgentID: agentID, Name: agentID, Role: model.RoleAgent})
 	require.NoError(t, err)
 
-	// Increment with wrong org — should not error but should not change anything.
-	err = testDB.IncrementGroupTimesReopened(ctx, groupID, uuid.New())
-	require.NoError(t, err)
+	newToken := func(hash string, expires time.Time) storage.RefreshToken {
+		return storage.RefreshToken{
+			ID: uuid.New(), OrgID: uuid.Nil, AgentID: agentID,
+			TokenHash: hash + "-" + suffix, ExpiresAt: expires,
+		}
+	}
+	later := time.Now().Add(time.Hour)
 
-	var timesReopened int
-	err = testDB.Pool().QueryRow(ctx,
-		`SELECT times_reopened FROM conflict_groups WHERE id = $1`, groupID,
-	).Scan(&timesReopened)
+	first := newToken("first", later)
+	require.NoError(t, testDB.CreateRefreshToken(ctx, first))
+
+	// Rotation consumes the token and stores its replacement.
+	second := newToken("second", later)
+	require.NoError(t, testDB.RotateRefreshToken(ctx, uuid.Nil, first.ID, first.TokenHash, second))
+	err = testDB.RotateRefreshToken(ctx, uuid.Nil, first.ID, first.TokenHash, newToken("reuse", later))
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenInvalid, "a rotated token is revoked")
+
+	// The hash must match the stored token.
+	err = testDB.RotateRefreshToken(ctx, uuid.Nil, second.ID, "wrong-hash", newToken("wrong", later))
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenInvalid)
+	require.NoError(t, testDB.RotateRefreshToken(ctx, uuid.Nil, second.ID, second.TokenHash, newToken("third", later)))
+
+	// Expired tokens are rejected.
+	expired := newToken("expired", time.Now().Add(-time.Minute))
+	require.NoError(t, testDB.CreateRefreshToken(ctx, expired))
+	err = testDB.RotateRefreshToken(ctx, uuid.Nil, expired.ID, expired.TokenHash, newToken("after-expiry", later))
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenInvalid)
+
+	// Revoking the API key a session started from ends the session.
+	key, err := testDB.CreateAPIKeyWithAudit(ctx, model.APIKey{
+		Prefix: "ak_rt_", KeyHash: "hash_" + suffix, AgentID: agentID,
+		OrgID: uuid.Nil, Label: "refresh", CreatedBy: "admin",
+	}, storage.MutationAuditEntry{
+		RequestID: "rt-" + suffix, OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "create_api_key", ResourceType: "api_key",
+	})
 	require.NoError(t, err)
-	assert.Equal(t, 0, timesReopened, "wrong org should not increment counter")
+	keyed := newToken("keyed", later)
+	keyed.APIKeyID = &key.ID
+	require.NoError(t, testDB.CreateRefreshToken(ctx, keyed))
+	require.NoError(t, testDB.RevokeAPIKeyWithAudit(ctx, uuid.Nil, key.ID, storage.MutationAuditEntry{
+		RequestID: "rt-revoke-" + suffix, OrgID: uuid.Nil, ActorAgentID: "admin", ActorRole: "platform_admin",
+		Operation: "revoke_api_key", ResourceType: "api_key", ResourceID: key.ID.String(),
+	}))
+	err = testDB.RotateRefreshToken(ctx, uuid.Nil, keyed.ID, keyed.TokenHash, newToken("after-revoke", later))
+	assert.ErrorIs(t, err, storage.ErrRefreshTokenInvalid)
 }
 
-// ---------------------------------------------------------------------------
-// Tests: FindReopenedResolution
-// ---------------------------------------------------------------------------
+func TestDecisionHashVersion(t *testing.T) {
+	ctx := context.Background()
+	agentID := "hashver-" + uuid.New().String()[:8]
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
 
-// makeEmbeddingAtDim creates a 1024-dim vector with `value` at dimension `dim`
-// and zeros elsewhere. Two vectors at the same dimension have cosine
-// similarity = 1.0; vectors at different dimensions are orthogonal (sim = 0.0).
-// Use different dims across tests to prevent cross-test interference in the
-// shared integration database.
-func makeEmbeddingAtDim(dim int, value float32) pgvector.Vector {
-	v := make([]float32, 1024)
-	v[dim] = value
-	return pgvector.NewVector(v)
+	// New rows are hashed with the current algorithm and record its version.
+	current, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "hash_version", Outcome: "current", Confidence: 0.7,
+	})
+	require.NoError(t, err)
+	got, err := testDB.GetDecision(ctx, uuid.Nil, current.ID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.HashVersion)
+	assert.True(t, integrity.VerifyContentHashVersion(got.HashVersion, got.ContentHash,
+		got.ID, got.DecisionType, got.Outcome, got.Confidence, got.Reasoning, got.ValidFrom))
+
+	// A row written before v2 existed keeps verifying against v1.
+	legacyID := uuid.New()
+	reasoning := "written by the v1 algorithm"
+	validFrom := time.Now().UTC().Truncate(time.Microsecond)
+	legacyHash := integrity.HashAlgorithms[1].Compute(legacyID, "hash_version", "legacy", 0.7, 0, &reasoning, validFrom)
+	_, err = testDB.Pool().Exec(ctx,
+		`INSERT INTO decisions (id, run_id, agent_id, org_id, decision_type, outcome, confidence, reasoning,
+		 content_hash, hash_version, valid_from, transaction_time)
+		 VALUES ($1, $2, $3, $4, 'hash_version', 'legacy', 0.7, $5, $6, 1, $7, $7)`,
+		legacyID, run.ID, agentID, uuid.Nil, reasoning, legacyHash, validFrom)
+	require.NoError(t, err)
+	legacy, err := testDB.GetDecision(ctx, uuid.Nil, legacyID, storage.GetDecisionOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, legacy.HashVersion)
+	assert.True(t, integrity.VerifyContentHashVersion(legacy.HashVersion, legacy.ContentHash,
+		legacy.ID, legacy.DecisionType, legacy.Outcome, legacy.Confidence, legacy.Reasoning, legacy.ValidFrom))
 }
 
-func TestFindReopenedResolution_MatchFound(t *testing.T) {
+func TestCreateGrantsBatchWithAudit(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
 
-	agentA := "frr-a-" + suffix
-	agentB := "frr-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gbatch-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
+	})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gbatch-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
+	})
 	require.NoError(t, err)
 
-	// Create the original conflict pair with embeddings.
-	// Use dims 10/11 — unique to this test to avoid cross-test interference.
-	embWinner := makeEmbeddingAtDim(10, 1.0)
-	embLoser := makeEmbeddingAtDim(11, 1.0)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_test",
-		Outcome: "use Redis", Confidence: 0.8,
+	resID := "gbatch-res-" + suffix
+	existing, err := testDB.CreateGrant(ctx, model.AccessGrant{
+		GrantorID: grantor.ID, GranteeID: grantee.ID,
+		ResourceType: "agent_traces", ResourceID: &resID, Permission: "read",
 	})
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embWinner)
+
+	orgWide := model.AccessGrant{GrantorID: grantor.ID, GranteeID: grantee.ID, ResourceType: "agent_traces", Permission: "read"}
+	stored, created, err := testDB.CreateGrantsBatchWithAudit(ctx,
+		[]model.AccessGrant{
+			{GrantorID: grantor.ID, GranteeID: grantee.ID, ResourceType: "agent_traces", ResourceID: &resID, Permission: "read"},
+			orgWide,
+			orgWide, // NULL resource_id deduplicates within the batch too.
+		},
+		storage.MutationAuditEntry{
+			RequestID: "gbatch-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "create_grant", ResourceType: "access_grant",
+		})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, true, false}, created)
+	assert.Equal(t, existing.ID, stored[0].ID)
+	assert.Equal(t, stored[1].ID, stored[2].ID)
+
+	byGrantor, err := testDB.ListGrantsByGrantor(ctx, uuid.Nil, grantor.ID)
 	require.NoError(t, err)
+	assert.Len(t, byGrantor, 2)
+}
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_test",
-		Outcome: "use Memcached", Confidence: 0.7,
+func TestCreateGrantsBatchWithAudit_RegrantAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+
+	grantor, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gexp-grantor-" + suffix, Name: "Grantor", Role: model.RoleAdmin,
 	})
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embLoser)
+	grantee, err := testDB.CreateAgent(ctx, model.Agent{
+		AgentID: "gexp-grantee-" + suffix, Name: "Grantee", Role: model.RoleReader,
+	})
 	require.NoError(t, err)
 
-	// Insert and resolve the original conflict with dA as winner.
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "frr_test", DecisionTypeB: "frr_test",
-		OutcomeA: "use Redis", OutcomeB: "use Memcached",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+	resID := "gexp-res-" + suffix
+	expiredAt := time.Now().Add(-time.Hour)
+	expired, err := testDB.CreateGrant(ctx, model.AccessGrant{
+		GrantorID: grantor.ID, GranteeID: grantee.ID,
+		ResourceType: "agent_traces", ResourceID: &resID, Permission: "read", ExpiresAt: &expiredAt,
 	})
 	require.NoError(t, err)
 
-	resNote := "Redis wins"
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "reviewer", &resNote, &dA.ID, nil,
+	stored, created, err := testDB.CreateGrantsBatchWithAudit(ctx,
+		[]model.AccessGrant{
+			{GrantorID: grantor.ID, GranteeID: grantee.ID, ResourceType: "agent_traces", ResourceID: &resID, Permission: "read"},
+		},
 		storage.MutationAuditEntry{
-			RequestID: "frr-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "reviewer", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
+			RequestID: "gexp-" + suffix, OrgID: uuid.Nil,
+			ActorAgentID: "admin", ActorRole: "admin",
+			Operation: "create_grant", ResourceType: "access_grant",
 		})
 	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, created, "an expired grant does not satisfy the re-grant")
+	assert.NotEqual(t, expired.ID, stored[0].ID)
+	assert.Nil(t, stored[0].ExpiresAt)
 
-	// Create NEW decisions with embeddings in the same directions.
-	agentC := "frr-c-" + suffix
-	agentD := "frr-d-" + suffix
-	runC, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentC})
+	ok, err := testDB.HasAccess(ctx, uuid.Nil, grantee.ID, "agent_traces", resID, "read")
 	require.NoError(t, err)
-	runD, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentD})
+	assert.True(t, ok)
+}
+
+func TestOrgSettingKeys(t *testing.T) {
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	_, err := testDB.Pool().Exec(ctx,
+		`INSERT INTO organizations (id, name, slug) VALUES ($1, $2, $3)`,
+		orgID, "org-setting-keys", "org-setting-keys-"+orgID.String()[:8])
+	require.NoError(t, err)
+	audit := storage.MutationAuditEntry{
+		RequestID: "org-setting-keys", OrgID: orgID,
+		ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "org_setting_updated", ResourceType: "org_settings", ResourceID: orgID.String(),
+	}
+
+	raw, err := testDB.GetOrgSetting(ctx, orgID, model.OrgSettingConfigOverrides)
+	require.NoError(t, err)
+	assert.Nil(t, raw, "unset key")
+	overrides, err := testDB.GetOrgConfigOverrides(ctx, orgID)
 	require.NoError(t, err)
+	assert.Nil(t, overrides)
 
-	// newEmbA aligns with the winner (dim 10), newEmbB aligns with the loser (dim 11).
-	newEmbA := makeEmbeddingAtDim(10, 0.95)
-	newEmbB := makeEmbeddingAtDim(11, 0.95)
+	require.NoError(t, testDB.SetOrgSetting(ctx, orgID, model.OrgSettingConfigOverrides,
+		json.RawMessage(`{"conflict_significance_threshold": 0.7, "event_retention_days": 14}`), "admin", audit))
+	require.NoError(t, testDB.SetOrgSetting(ctx, orgID, "mcp_tools",
+		json.RawMessage(`{"disabled": ["akashi_assess"]}`), "admin", audit))
 
-	dC, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runC.ID, AgentID: agentC, DecisionType: "frr_test",
-		Outcome: "use Redis again", Confidence: 0.9,
-	})
+	overrides, err = testDB.GetOrgConfigOverrides(ctx, orgID)
 	require.NoError(t, err)
+	require.NotNil(t, overrides)
+	assert.InDelta(t, 0.7, overrides.SignificanceThreshold(0.3), 1e-9)
 
-	dD, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runD.ID, AgentID: agentD, DecisionType: "frr_test",
-		Outcome: "use Memcached again", Confidence: 0.6,
-	})
+	retention, err := testDB.ListOrgEventRetentionOverrides(ctx)
 	require.NoError(t, err)
+	assert.Equal(t, 14, retention[orgID])
 
-	// FindReopenedResolution should find the prior resolution.
-	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
-		dC.ID, dD.ID, newEmbA, newEmbB, 0.80)
+	settings, err := testDB.GetOrgSettings(ctx, orgID)
 	require.NoError(t, err)
-	require.NotNil(t, match, "should find the prior resolution that is contradicted")
+	require.NotNil(t, settings.Settings.MCPTools, "setting one key leaves the others in place")
+	assert.True(t, settings.Settings.MCPTools.IsDisabled("akashi_assess"))
 
-	assert.Equal(t, conflictID, match.ResolutionID)
-	assert.Equal(t, "use Redis", match.WinningOutcome)
-	// WinningAgent may not match the decision's original agent due to
-	// independent string normalization of agent slots in InsertScoredConflict.
-	assert.Contains(t, []string{agentA, agentB}, match.WinningAgent,
-		"winning agent should be one of the original agents")
-	require.NotNil(t, match.ResolutionNote)
-	assert.Equal(t, "Redis wins", *match.ResolutionNote)
-	assert.Greater(t, match.OutcomeSimilarity, 0.80)
+	// A null value removes the key.
+	require.NoError(t, testDB.SetOrgSetting(ctx, orgID, model.OrgSettingConfigOverrides, nil, "admin", audit))
+	raw, err = testDB.GetOrgSetting(ctx, orgID, model.OrgSettingConfigOverrides)
+	require.NoError(t, err)
+	assert.Nil(t, raw)
+	retention, err = testDB.ListOrgEventRetentionOverrides(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, retention, orgID)
+
+	var audits int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT count(*) FROM mutation_audit_log WHERE org_id = $1 AND operation = 'org_setting_updated'`,
+		orgID).Scan(&audits))
+	assert.Equal(t, 3, audits)
 }
 
-func TestFindReopenedResolution_NoMatch_BelowThreshold(t *testing.T) {
+func TestListAndRequeueDeadOutbox(t *testing.T) {
 	ctx := context.Background()
-	suffix := uuid.New().String()[:8]
+	orgID := uuid.New()
+	otherOrg := uuid.New()
 
-	agentA := "frr-lo-a-" + suffix
-	agentB := "frr-lo-b-" + suffix
+	insertDead := func(org uuid.UUID, age time.Duration) (int64, uuid.UUID) {
+		t.Helper()
+		id := rand.Int64N(1<<62) + 1
+		decisionID := uuid.New()
+		_, err := testDB.Pool().Exec(ctx,
+			`INSERT INTO search_outbox_dead (outbox_id, decision_id, org_id, operation, attempts, last_error, created_at, dead_at)
+			 VALUES ($1, $2, $3, 'upsert', 10, 'qdrant unavailable', now() - interval '1 day', now() - $4::interval)`,
+			id, decisionID, org, fmt.Sprintf("%d seconds", int(age.Seconds())))
+		require.NoError(t, err)
+		return id, decisionID
+	}
+	older, _ := insertDead(orgID, time.Hour)
+	newer, newerDecision := insertDead(orgID, time.Minute)
+	foreign, _ := insertDead(otherOrg, time.Minute)
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	entries, total, err := testDB.ListDeadOutbox(ctx, orgID, 10, 0)
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	assert.Equal(t, 2, total)
+	require.Len(t, entries, 2)
+	assert.Equal(t, newer, entries[0].OutboxID, "most recently dead-lettered first")
+	assert.Equal(t, older, entries[1].OutboxID)
+	require.NotNil(t, entries[0].LastError)
+	assert.Equal(t, "qdrant unavailable", *entries[0].LastError)
+
+	audit := storage.MutationAuditEntry{
+		RequestID: "requeue-" + orgID.String()[:8], OrgID: orgID,
+		ActorAgentID: "admin", ActorRole: "admin",
+		Operation: "requeue_dead_outbox", ResourceType: "search_outbox",
+	}
+
+	// Another org's ID is ignored.
+	requeued, err := testDB.RequeueDeadOutboxWithAudit(ctx, orgID, []int64{newer, foreign}, audit)
 	require.NoError(t, err)
+	assert.Equal(t, 1, requeued)
 
-	// Use dims 20/21 — unique to this test.
-	embA := makeEmbeddingAtDim(20, 1.0)
-	embB := makeEmbeddingAtDim(21, 1.0)
+	var attempts int
+	require.NoError(t, testDB.Pool().QueryRow(ctx,
+		`SELECT attempts FROM search_outbox WHERE decision_id = $1 AND operation = 'upsert'`, newerDecision,
+	).Scan(&attempts))
+	assert.Equal(t, 0, attempts, "requeued entry should start over")
 
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_lo",
-		Outcome: "approach_a", Confidence: 0.8,
-	})
+	entries, total, err = testDB.ListDeadOutbox(ctx, orgID, 10, 0)
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, older, entries[0].OutboxID)
+
+	requeued, err = testDB.RequeueDeadOutboxWithAudit(ctx, orgID, nil, audit)
 	require.NoError(t, err)
+	assert.Equal(t, 1, requeued, "nil IDs requeue the rest of the org")
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_lo",
-		Outcome: "approach_b", Confidence: 0.7,
-	})
+	_, total, err = testDB.ListDeadOutbox(ctx, otherOrg, 10, 0)
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
+	assert.Equal(t, 1, total, "other org's dead entries are untouched")
+
+	_, err = testDB.Pool().Exec(ctx, `DELETE FROM search_outbox WHERE org_id = $1`, orgID)
+	require.NoError(t, err)
+	_, err = testDB.Pool().Exec(ctx, `DELETE FROM search_outbox_dead WHERE org_id = $1`, otherOrg)
 	require.NoError(t, err)
+}
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "frr_lo", DecisionTypeB: "frr_lo",
-		OutcomeA: "approach_a", OutcomeB: "approach_b",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+func TestFindUnscoredDecisionsAndBackfill(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	reason := "rejected because it cannot scale"
+
+	_, decision, err := testDB.CreateTraceTx(ctx, storage.CreateTraceParams{
+		AgentID:  "unscored-" + suffix,
+		OrgID:    uuid.Nil,
+		Metadata: map[string]any{},
+		Decision: model.Decision{
+			DecisionType: "completeness_test", Outcome: "unscored_" + suffix,
+			Confidence: 0.6, Metadata: map[string]any{},
+		},
+		Alternatives: []model.Alternative{
+			{Label: "chosen", Metadata: map[string]any{}},
+			{Label: "rejected", RejectionReason: &reason, Metadata: map[string]any{}},
+		},
+		Evidence: []model.Evidence{
+			{SourceType: "document", Content: "one", Metadata: map[string]any{}},
+			{SourceType: "document", Content: "two", Metadata: map[string]any{}},
+		},
 	})
 	require.NoError(t, err)
 
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "admin", nil, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "frr-lo-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
-	require.NoError(t, err)
+	// Other tests leave unscored decisions behind, so page until ours appears.
+	findOurs := func() *storage.UnscoredDecision {
+		var after uuid.UUID
+		for {
+			page, err := testDB.FindUnscoredDecisions(ctx, after, 100)
+			require.NoError(t, err)
+			for i := range page {
+				if page[i].ID == decision.ID {
+					return &page[i]
+				}
+				assert.Greater(t, page[i].ID.String(), after.String(), "pages are in ID order")
+				after = page[i].ID
+			}
+			if len(page) < 100 {
+				return nil
+			}
+		}
+	}
 
-	// Both new embeddings point in the same direction (dim 20) — no cross-match
-	// because there's no loser-aligned embedding.
-	sameDir := makeEmbeddingAtDim(20, 1.0)
-	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
-		uuid.New(), uuid.New(), sameDir, sameDir, 0.99)
+	got := findOurs()
+	require.NotNil(t, got, "unscored decision should be returned")
+	assert.Equal(t, "unscored_"+suffix, got.Outcome)
+	assert.InDelta(t, 0.6, got.Confidence, 0.001)
+	assert.False(t, got.HasPrecedentRef)
+	assert.Equal(t, []string{reason}, got.RejectionReasons)
+	assert.Equal(t, 2, got.EvidenceCount)
+
+	require.NoError(t, testDB.BackfillCompletenessScore(ctx, decision.ID, uuid.Nil, 0.42))
+	assert.Nil(t, findOurs(), "scored decision should no longer be returned")
+
+	stored, err := testDB.GetDecision(ctx, uuid.Nil, decision.ID, storage.GetDecisionOpts{})
 	require.NoError(t, err)
-	assert.Nil(t, match, "embeddings that don't cross-match should return nil")
+	assert.InDelta(t, 0.42, stored.CompletenessScore, 0.001)
 }
 
-func TestFindReopenedResolution_NoMatch_UnresolvedConflict(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: ClearEmbeddings / AlterEmbeddingDimensions
+// ---------------------------------------------------------------------------
+
+func TestClearEmbeddings_RebackfillAtNewDimension(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "reembed-" + suffix
 
-	agentA := "frr-unres-a-" + suffix
-	agentB := "frr-unres-b-" + suffix
-
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
-	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	// Resizing columns is schema-wide, so use a private database.
+	db, err := testTC.NewIsolatedTestDB(ctx, "reembed_"+suffix, testutil.TestLogger())
 	require.NoError(t, err)
+	defer db.Close(ctx)
 
-	// Use dims 30/31 — unique to this test.
-	embA := makeEmbeddingAtDim(30, 1.0)
-	embB := makeEmbeddingAtDim(31, 1.0)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_unres",
-		Outcome: "x", Confidence: 0.8,
+	run, err := db.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
+	require.NoError(t, err)
+	emb := makeEmbeddingAtDim(3, 1)
+	d, err := db.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "architecture",
+		Outcome: "chose postgres", Confidence: 0.8, Embedding: &emb, OutcomeEmbedding: &emb,
 	})
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
+	require.NoError(t, db.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimText: "Postgres is the store.", Embedding: &emb},
+	}))
+
+	dims, err := db.EmbeddingDimensions(ctx)
 	require.NoError(t, err)
+	assert.Equal(t, 1024, dims)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_unres",
-		Outcome: "y", Confidence: 0.7,
-	})
+	err = db.AlterEmbeddingDimensions(ctx, 8)
+	require.ErrorIs(t, err, storage.ErrEmbeddingsPresent, "resize must refuse while vectors remain")
+
+	cleared, err := db.ClearEmbeddings(ctx, d.OrgID)
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
+	assert.Equal(t, storage.ClearEmbeddingsResult{Decisions: 1, Claims: 1}, cleared)
+
+	require.NoError(t, db.AlterEmbeddingDimensions(ctx, 8))
+	dims, err = db.EmbeddingDimensions(ctx)
 	require.NoError(t, err)
+	assert.Equal(t, 8, dims)
 
-	// Insert conflict but do NOT resolve it.
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	_, err = testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "frr_unres", DecisionTypeB: "frr_unres",
-		OutcomeA: "x", OutcomeB: "y",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
-	})
+	// The startup backfill picks the decision up again and stores a vector
+	// of the new dimension.
+	pending, err := db.FindUnembeddedDecisions(ctx, 10)
 	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, d.ID, pending[0].ID)
 
-	// Even with matching embeddings, unresolved conflicts should not match.
-	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
-		uuid.New(), uuid.New(), embA, embB, 0.50)
+	small := pgvector.NewVector([]float32{1, 0, 0, 0, 0, 0, 0, 0})
+	require.NoError(t, db.BackfillEmbedding(ctx, d.ID, d.OrgID, small, "nomic-embed-text"))
+	require.NoError(t, db.BackfillOutcomeEmbedding(ctx, d.ID, d.OrgID, small))
+	require.NoError(t, db.InsertClaims(ctx, []storage.Claim{
+		{DecisionID: d.ID, OrgID: d.OrgID, ClaimText: "Postgres is the store.", Embedding: &small},
+	}))
+
+	var gotDims int
+	var gotModel string
+	require.NoError(t, db.Pool().QueryRow(ctx,
+		`SELECT vector_dims(embedding), embedding_model FROM decisions WHERE id = $1`, d.ID,
+	).Scan(&gotDims, &gotModel))
+	assert.Equal(t, 8, gotDims)
+	assert.Equal(t, "nomic-embed-text", gotModel)
+	pending, err = db.FindUnembeddedDecisions(ctx, 10)
 	require.NoError(t, err)
-	assert.Nil(t, match, "unresolved conflict should not be found")
+	assert.Empty(t, pending)
+
+	// Old-dimension vectors are rejected by the resized columns.
+	err = db.BackfillEmbedding(ctx, d.ID, d.OrgID, emb, "")
+	assert.Error(t, err)
 }
 
-func TestFindReopenedResolution_ExcludesSameDecisions(t *testing.T) {
+// ---------------------------------------------------------------------------
+// Tests: read replica routing
+// ---------------------------------------------------------------------------
+
+func TestReadReplica_RoutesReadsToReplicaPool(t *testing.T) {
 	ctx := context.Background()
 	suffix := uuid.New().String()[:8]
+	agentID := "replica-" + suffix
 
-	agentA := "frr-self-a-" + suffix
-	agentB := "frr-self-b-" + suffix
+	// Both DSNs point at the same container: the primary's writes are
+	// immediately visible through the "replica", so the test checks routing
+	// rather than replication.
+	db, err := storage.New(ctx, testTC.DSN, "", testutil.TestLogger(), storage.PoolOptions{ReplicaDSN: testTC.DSN})
+	require.NoError(t, err)
+	defer db.Close(ctx)
+	require.NotNil(t, db.ReplicaPool())
+	assert.NotSame(t, db.Pool(), db.ReplicaPool())
 
-	runA, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentA})
+	run, err := db.CreateRun(ctx, model.CreateRunRequest{AgentID: agentID})
 	require.NoError(t, err)
-	runB, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: agentB})
+	d, err := db.CreateDecision(ctx, model.Decision{
+		RunID: run.ID, AgentID: agentID, DecisionType: "architecture",
+		Outcome: "route reads to the replica " + suffix, Confidence: 0.7,
+	})
 	require.NoError(t, err)
 
-	// Use dims 40/41 — unique to this test.
-	embA := makeEmbeddingAtDim(40, 1.0)
-	embB := makeEmbeddingAtDim(41, 1.0)
-
-	dA, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runA.ID, AgentID: agentA, DecisionType: "frr_self",
-		Outcome: "x", Confidence: 0.8,
+	before := db.ReplicaPool().Stat().AcquireCount()
+	decisions, total, err := db.QueryDecisions(ctx, d.OrgID, model.QueryRequest{
+		Filters: model.QueryFilters{AgentIDs: []string{agentID}},
+		Include: []string{"alternatives"},
 	})
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dA.ID, uuid.Nil, embA)
+	require.Equal(t, 1, total)
+	assert.Equal(t, d.ID, decisions[0].ID)
+
+	results, err := db.SearchDecisionsByText(ctx, d.OrgID, "replica "+suffix, model.QueryFilters{AgentIDs: []string{agentID}}, 10)
 	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, d.ID, results[0].Decision.ID)
 
-	dB, err := testDB.CreateDecision(ctx, model.Decision{
-		RunID: runB.ID, AgentID: agentB, DecisionType: "frr_self",
-		Outcome: "y", Confidence: 0.7,
-	})
+	exported, err := db.ExportDecisionsCursor(ctx, d.OrgID, model.QueryFilters{AgentIDs: []string{agentID}}, nil, 10)
 	require.NoError(t, err)
-	err = testDB.BackfillOutcomeEmbedding(ctx, dB.ID, uuid.Nil, embB)
+	assert.Len(t, exported, 1)
+	assert.Greater(t, db.ReplicaPool().Stat().AcquireCount(), before, "reads should acquire replica connections")
+
+	// Idempotency lookups stay on the primary.
+	before = db.ReplicaPool().Stat().AcquireCount()
+	_, err = db.BeginIdempotency(ctx, d.OrgID, agentID, "POST /v1/trace", "key-"+suffix, "hash")
 	require.NoError(t, err)
+	_, err = db.BeginIdempotency(ctx, d.OrgID, agentID, "POST /v1/trace", "key-"+suffix, "hash")
+	require.ErrorIs(t, err, storage.ErrIdempotencyInProgress)
+	assert.Equal(t, before, db.ReplicaPool().Stat().AcquireCount())
+}
 
-	topicSim := 0.9
-	outcomeDiv := 0.85
-	sig := topicSim * outcomeDiv
-	conflictID, err := testDB.InsertScoredConflict(ctx, model.DecisionConflict{
-		ConflictKind: model.ConflictKindCrossAgent, DecisionAID: dA.ID, DecisionBID: dB.ID,
-		OrgID: uuid.Nil, AgentA: agentA, AgentB: agentB,
-		DecisionTypeA: "frr_self", DecisionTypeB: "frr_self",
-		OutcomeA: "x", OutcomeB: "y",
-		TopicSimilarity: &topicSim, OutcomeDivergence: &outcomeDiv,
-		Significance: &sig, ScoringMethod: "text",
+func TestFindSupersedeMatch_ScopedToAgent(t *testing.T) {
+	ctx := context.Background()
+	suffix := uuid.New().String()[:8]
+	owner, other := "supersede-owner-"+suffix, "supersede-other-"+suffix
+
+	run, err := testDB.CreateRun(ctx, model.CreateRunRequest{AgentID: owner})
+	require.NoError(t, err)
+	standing, err := testDB.CreateDecision(ctx, model.Decision{
+		RunID:        run.ID,
+		AgentID:      owner,
+		DecisionType: "pricing",
+		Outcome:      "price sku at 10",
+		Confidence:   0.8,
+		Metadata:     map[string]any{"entity": "sku-" + suffix},
 	})
 	require.NoError(t, err)
 
-	_, err = testDB.UpdateConflictStatusWithAudit(ctx, conflictID, uuid.Nil,
-		"resolved", "admin", nil, &dA.ID, nil,
-		storage.MutationAuditEntry{
-			RequestID: "frr-self-" + suffix, OrgID: uuid.Nil,
-			ActorAgentID: "admin", ActorRole: "admin",
-			Operation: "resolve_conflict", ResourceType: "conflict",
-		})
+	match := map[string]any{"entity": "sku-" + suffix}
+	id, err := testDB.FindSupersedeMatch(ctx, uuid.Nil, owner, "", "pricing", match)
 	require.NoError(t, err)
+	require.NotNil(t, id)
+	assert.Equal(t, standing.ID, *id)
 
-	// Query with the SAME decision IDs as the resolved conflict — should be
-	// excluded to prevent a conflict from "reopening" itself.
-	match, err := testDB.FindReopenedResolution(ctx, uuid.Nil,
-		dA.ID, dB.ID, embA, embB, 0.50)
+	id, err = testDB.FindSupersedeMatch(ctx, uuid.Nil, other, "", "pricing", match)
 	require.NoError(t, err)
-	assert.Nil(t, match, "should exclude conflicts involving the same decisions")
+	assert.Nil(t, id, "another agent's trace must not supersede the owner's decision")
 }
@@ -18,6 +18,11 @@ import (
 	"github.com/ashita-ai/akashi/internal/model"
 )
 
+// AgentSuggestionMinSimilarity is the trigram similarity (pg_trgm's
+// similarity(), 0–1) an agent_id needs to be returned by SuggestAgentIDs.
+// It matches pg_trgm's default similarity_threshold.
+const AgentSuggestionMinSimilarity = 0.3
+
 // Store is the storage interface consumed by the MCP tool path.
 //
 // Every method on this interface is already implemented by *DB (PostgreSQL).
@@ -41,6 +46,7 @@ type Store interface {
 	CreateAgentWithAudit(ctx context.Context, agent model.Agent, audit MutationAuditEntry) (model.Agent, error)
 	CountAgents(ctx context.Context, orgID uuid.UUID) (int, error)
 	ListAgentIDsBySharedTags(ctx context.Context, orgID uuid.UUID, tags []string) ([]string, error)
+	SuggestAgentIDs(ctx context.Context, orgID uuid.UUID, agentID string, limit int) ([]string, error)
 
 	// ---- API Keys ----
 
@@ -50,6 +56,7 @@ type Store interface {
 
 	CreateTraceTx(ctx context.Context, params CreateTraceParams) (model.AgentRun, model.Decision, error)
 	CreateTraceAndAdjudicateConflictTx(ctx context.Context, traceParams CreateTraceParams, conflictParams AdjudicateConflictInTraceParams) (model.AgentRun, model.Decision, error)
+	CreateTracesTx(ctx context.Context, params []CreateTraceParams) ([]model.AgentRun, []model.Decision, error)
 
 	// ---- Decisions (query) ----
 
@@ -59,6 +66,7 @@ type Store interface {
 	GetDecisionsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]model.Decision, error)
 	GetDecisionsByAgent(ctx context.Context, orgID uuid.UUID, agentID string, limit, offset int, from, to *time.Time) ([]model.Decision, int, error)
 	GetDecisionForScoring(ctx context.Context, id, orgID uuid.UUID) (model.Decision, error)
+	FindSupersedeMatch(ctx context.Context, orgID uuid.UUID, agentID, namespace, decisionType string, match map[string]any) (*uuid.UUID, error)
 
 	// ---- Conflicts ----
 
@@ -77,9 +85,11 @@ type Store interface {
 
 	GetDecisionEmbeddings(ctx context.Context, ids []uuid.UUID, orgID uuid.UUID) (map[uuid.UUID][2]pgvector.Vector, error)
 	FindUnembeddedDecisions(ctx context.Context, limit int) ([]UnembeddedDecision, error)
-	BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector) error
+	BackfillEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector, embeddingModel string) error
 	FindDecisionsMissingOutcomeEmbedding(ctx context.Context, limit int) ([]UnembeddedDecision, error)
 	BackfillOutcomeEmbedding(ctx context.Context, id, orgID uuid.UUID, emb pgvector.Vector) error
+	FindUnscoredDecisions(ctx context.Context, after uuid.UUID, limit int) ([]UnscoredDecision, error)
+	BackfillCompletenessScore(ctx context.Context, id, orgID uuid.UUID, score float32) error
 
 	// ---- Signals & Assessments ----
 
@@ -180,6 +190,11 @@ type Store interface {
 	// GetDecisionTypeDistribution returns counts grouped by decision_type.
 	// When from/to are non-nil, only decisions with valid_from in [from, to) are included.
 	GetDecisionTypeDistribution(ctx context.Context, orgID uuid.UUID, from, to *time.Time) ([]DecisionTypeCount, error)
+	// AggregateDecisionStats returns counts and averages of current decisions
+	// grouped by groupBy (one of the StatsGroupBy constants), at most limit
+	// buckets. When since is non-nil, only decisions with valid_from >= since
+	// are included.
+	AggregateDecisionStats(ctx context.Context, orgID uuid.UUID, groupBy string, since *time.Time, limit int) ([]DecisionStatsBucket, error)
 	// GetCompletenessByDecisionType returns per-type average completeness for current decisions.
 	// Ordered by avg completeness ascending so the worst types surface first.
 	GetCompletenessByDecisionType(ctx context.Context, orgID uuid.UUID, from, to *time.Time) ([]DecisionTypeCompleteness, error)
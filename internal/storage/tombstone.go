@@ -0,0 +1,191 @@
+//go:build !lite
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// TombstoneAgentResult contains the count of rows tombstoned per table.
+type TombstoneAgentResult struct {
+	Decisions int64 `json:"decisions"`
+	Runs      int64 `json:"runs"`
+	APIKeys   int64 `json:"api_keys_revoked"`
+	Agents    int64 `json:"agents"`
+}
+
+// ErrAgentTombstoned is returned when an agent_id belongs to a tombstoned
+// agent. The agents row (and its unique org_id/agent_id key) is kept until
+// PurgeTombstonedData removes it, so the agent_id cannot be reused before then.
+var ErrAgentTombstoned = errors.New("storage: agent is tombstoned")
+
+// IsAgentTombstoned reports whether agentID names a tombstoned agent in the
+// org. A live or unknown agent_id returns false.
+func (db *DB) IsAgentTombstoned(ctx context.Context, orgID uuid.UUID, agentID string) (bool, error) {
+	var tombstoned bool
+	err := db.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM agents WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NOT NULL)`,
+		orgID, agentID,
+	).Scan(&tombstoned)
+	if err != nil {
+		return false, fmt.Errorf("storage: check agent tombstone: %w", err)
+	}
+	return tombstoned, nil
+}
+
+// TombstoneAgentData soft-deletes an agent within an org in a single
+// transaction: deleted_at is stamped on the agent, its runs, and its
+// decisions, current decisions get valid_to closed and queued for search
+// index removal, and the agent's API keys are revoked. No rows are removed,
+// so integrity proofs over the agent's decisions keep verifying; read paths
+// exclude tombstoned rows. PurgeTombstonedData hard-deletes them later.
+//
+// When audit is non-nil, a mutation audit entry is inserted inside the same
+// transaction before commit.
+func (db *DB) TombstoneAgentData(ctx context.Context, orgID uuid.UUID, agentID string, audit *MutationAuditEntry) (TombstoneAgentResult, error) {
+	var result TombstoneAgentResult
+	txErr := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var agentUUID uuid.UUID
+		err := tx.QueryRow(ctx,
+			`SELECT id FROM agents WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL FOR UPDATE`,
+			orgID, agentID,
+		).Scan(&agentUUID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%w: %s", ErrAgentNotFound, agentID)
+			}
+			return fmt.Errorf("storage: lookup agent: %w", err)
+		}
+		now := time.Now().UTC()
+
+		// Queue search index deletions before valid_to is closed, while the
+		// current decisions are still identifiable.
+		_, err = tx.Exec(ctx,
+			`INSERT INTO search_outbox (decision_id, org_id, operation)
+		 SELECT id, org_id, 'delete' FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND valid_to IS NULL AND deleted_at IS NULL
+		 ON CONFLICT (decision_id, operation) DO UPDATE SET created_at = now(), attempts = 0, locked_until = NULL`,
+			orgID, agentID)
+		if err != nil {
+			return fmt.Errorf("storage: queue search outbox deletes: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx,
+			`UPDATE decisions SET deleted_at = $3, valid_to = COALESCE(valid_to, $3)
+		 WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL`,
+			orgID, agentID, now)
+		if err != nil {
+			return fmt.Errorf("storage: tombstone decisions: %w", err)
+		}
+		result.Decisions = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx,
+			`UPDATE agent_runs SET deleted_at = $3 WHERE org_id = $1 AND agent_id = $2 AND deleted_at IS NULL`,
+			orgID, agentID, now)
+		if err != nil {
+			return fmt.Errorf("storage: tombstone runs: %w", err)
+		}
+		result.Runs = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx,
+			`UPDATE api_keys SET revoked_at = $3 WHERE org_id = $1 AND agent_id = $2 AND revoked_at IS NULL`,
+			orgID, agentID, now)
+		if err != nil {
+			return fmt.Errorf("storage: revoke api keys: %w", err)
+		}
+		result.APIKeys = tag.RowsAffected()
+
+		tag, err = tx.Exec(ctx, `UPDATE agents SET deleted_at = $2 WHERE id = $1`, agentUUID, now)
+		if err != nil {
+			return fmt.Errorf("storage: tombstone agent: %w", err)
+		}
+		result.Agents = tag.RowsAffected()
+
+		if audit != nil {
+			audit.ResourceID = agentID
+			audit.AfterData = map[string]any{"tombstoned": result, "deleted_at": now}
+			if err := InsertMutationAuditTx(ctx, tx, *audit); err != nil {
+				return fmt.Errorf("storage: audit in tombstone agent tx: %w", err)
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return TombstoneAgentResult{}, txErr
+	}
+	return result, nil
+}
+
+// PurgedAgent records one tombstoned agent removed by PurgeTombstonedData.
+type PurgedAgent struct {
+	OrgID   uuid.UUID
+	AgentID string
+	Deleted DeleteAgentResult
+}
+
+// PurgeTombstonedData hard-deletes agents tombstoned before olderThan, with
+// all their data, via DeleteAgentData. Agents covered by an active legal hold
+// are skipped and retried on the next call. A failure on one agent is
+// returned after the others have been attempted; the agents purged so far
+// are returned alongside it.
+func (db *DB) PurgeTombstonedData(ctx context.Context, olderThan time.Time) ([]PurgedAgent, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT org_id, agent_id FROM agents
+		 WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		 ORDER BY deleted_at ASC`,
+		olderThan,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage: list tombstoned agents: %w", err)
+	}
+	var candidates []PurgedAgent
+	for rows.Next() {
+		var p PurgedAgent
+		if err := rows.Scan(&p.OrgID, &p.AgentID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("storage: scan tombstoned agent: %w", err)
+		}
+		candidates = append(candidates, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("storage: list tombstoned agents: %w", err)
+	}
+
+	var purged []PurgedAgent
+	var errs []error
+	for _, p := range candidates {
+		held, err := db.ActiveHoldsExistForAgent(ctx, p.OrgID, p.AgentID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if held {
+			continue
+		}
+		audit := MutationAuditEntry{
+			RequestID:    uuid.New().String(),
+			OrgID:        p.OrgID,
+			ActorAgentID: "system:tombstone_purge",
+			ActorRole:    "system",
+			HTTPMethod:   "SYSTEM",
+			Endpoint:     "retention_loop",
+			Operation:    "purge_tombstoned_agent",
+			ResourceType: "agent",
+			Metadata:     map[string]any{"tombstoned_before": olderThan},
+		}
+		p.Deleted, err = db.DeleteAgentData(ctx, p.OrgID, p.AgentID, &audit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("storage: purge tombstoned agent %s: %w", p.AgentID, err))
+			continue
+		}
+		purged = append(purged, p)
+	}
+	return purged, errors.Join(errs...)
+}
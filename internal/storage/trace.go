@@ -4,6 +4,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -31,6 +32,29 @@ func (db *DB) CreateTraceTx(ctx context.Context, params CreateTraceParams) (mode
 	return run, d, nil
 }
 
+// CreateTracesTx creates several traces within one database transaction: either
+// every trace is written or none is. Results are returned in input order. A
+// failure is wrapped in a *TraceBatchError naming the offending trace.
+func (db *DB) CreateTracesTx(ctx context.Context, params []CreateTraceParams) ([]model.AgentRun, []model.Decision, error) {
+	runs := make([]model.AgentRun, 0, len(params))
+	decisions := make([]model.Decision, 0, len(params))
+	err := db.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		for i, p := range params {
+			run, d, txErr := db.createTraceInTx(ctx, tx, p)
+			if txErr != nil {
+				return &TraceBatchError{Index: i, Err: txErr}
+			}
+			runs = append(runs, run)
+			decisions = append(decisions, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return runs, decisions, nil
+}
+
 // CreateTraceAndAdjudicateConflictTx creates a decision trace AND adjudicates a
 // conflict in a single atomic transaction. This prevents the failure mode where
 // an adjudication decision exists but the conflict remains unresolved.
@@ -133,20 +157,45 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 	if d.CreatedAt.IsZero() {
 		d.CreatedAt = now
 	}
+	if d.Namespace == "" {
+		d.Namespace = model.DefaultNamespace
+	}
 	if d.Metadata == nil {
 		d.Metadata = map[string]any{}
 	}
-	d.ContentHash = integrity.ComputeContentHash(d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom)
+	if params.ConfidencePrecision > 0 {
+		d.Confidence = integrity.RoundConfidence(d.Confidence, params.ConfidencePrecision)
+		if d.ConfidenceLow != nil {
+			low := integrity.RoundConfidence(*d.ConfidenceLow, params.ConfidencePrecision)
+			d.ConfidenceLow = &low
+		}
+		if d.ConfidenceHigh != nil {
+			high := integrity.RoundConfidence(*d.ConfidenceHigh, params.ConfidencePrecision)
+			d.ConfidenceHigh = &high
+		}
+	}
+	d.ContentHash = integrity.ComputeRoundedContentHash(d.ID, d.DecisionType, d.Outcome, d.Confidence, d.Reasoning, d.ValidFrom, params.ConfidencePrecision)
+	d.HashVersion = integrity.CurrentHashVersion
+	if params.BatchWindow > 0 && d.SessionID != nil {
+		batchID, err := assignBatchID(ctx, tx, d, params.BatchWindow)
+		if err != nil {
+			return model.AgentRun{}, model.Decision{}, err
+		}
+		d.BatchID = &batchID
+	}
 	if _, err := tx.Exec(ctx,
 		`INSERT INTO decisions (id, run_id, agent_id, org_id, decision_type, outcome, confidence,
 		 reasoning, embedding, outcome_embedding, metadata, completeness_score, outcome_score, precedent_ref, precedent_reason, supersedes_id, content_hash,
-		 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)`,
+		 valid_from, valid_to, transaction_time, created_at, session_id, agent_context, api_key_id,
+		 confidence_low, confidence_high, namespace, embedding_model, batch_id, context_snapshot, context_snapshot_truncated, hash_version)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32)`,
 		d.ID, d.RunID, d.AgentID, d.OrgID, d.DecisionType, d.Outcome, d.Confidence,
 		d.Reasoning, d.Embedding, d.OutcomeEmbedding, d.Metadata, d.CompletenessScore, d.OutcomeScore, d.PrecedentRef,
 		d.PrecedentReason, d.SupersedesID, d.ContentHash,
 		d.ValidFrom, d.ValidTo, d.TransactionTime, d.CreatedAt,
 		d.SessionID, d.AgentContext, d.APIKeyID,
+		d.ConfidenceLow, d.ConfidenceHigh, d.Namespace, d.EmbeddingModel, d.BatchID,
+		d.ContextSnapshot, d.ContextSnapshotTruncated, d.HashVersion,
 	); err != nil {
 		return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: create decision in trace tx: %w", err)
 	}
@@ -184,7 +233,7 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 	// 4. Create evidence via COPY.
 	if len(params.Evidence) > 0 {
 		columns := []string{"id", "decision_id", "org_id", "source_type", "source_uri", "content",
-			"relevance_score", "embedding", "metadata", "created_at"}
+			"relevance_score", "embedding", "metadata", "created_at", "blob_hash"}
 		rows := make([][]any, len(params.Evidence))
 		for i, ev := range params.Evidence {
 			id := ev.ID
@@ -199,8 +248,12 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 			if meta == nil {
 				meta = map[string]any{}
 			}
-			rows[i] = []any{id, d.ID, params.OrgID, string(ev.SourceType), ev.SourceURI, ev.Content,
-				ev.RelevanceScore, ev.Embedding, meta, createdAt}
+			content, blobHash, err := db.evidenceContentForWrite(ctx, tx, params.OrgID, ev.Content)
+			if err != nil {
+				return model.AgentRun{}, model.Decision{}, err
+			}
+			rows[i] = []any{id, d.ID, params.OrgID, string(ev.SourceType), ev.SourceURI, content,
+				ev.RelevanceScore, ev.Embedding, meta, createdAt, blobHash}
 		}
 		copyCtx, copyCancel := context.WithTimeout(ctx, 30*time.Second)
 		_, err := tx.CopyFrom(copyCtx, pgx.Identifier{"evidence"}, columns, pgx.CopyFromRows(rows))
@@ -220,15 +273,28 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 	// 4c. Handle explicit supersession: invalidate the superseded decision and
 	// auto-resolve its open conflicts, matching the ReviseDecision pattern.
 	if d.SupersedesID != nil {
-		tag, err := tx.Exec(ctx,
-			`UPDATE decisions SET valid_to = $1 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL`,
+		var supersededOutcome string
+		err := tx.QueryRow(ctx,
+			`UPDATE decisions SET valid_to = $1 WHERE id = $2 AND org_id = $3 AND valid_to IS NULL
+			 RETURNING outcome`,
 			now, *d.SupersedesID, params.OrgID,
-		)
+		).Scan(&supersededOutcome)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: superseded decision %s not found (or already superseded): %w", *d.SupersedesID, ErrNotFound)
+		}
 		if err != nil {
 			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: invalidate superseded decision: %w", err)
 		}
-		if tag.RowsAffected() == 0 {
-			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: superseded decision %s not found (or already superseded): %w", *d.SupersedesID, ErrNotFound)
+		// Flag a reversed verdict on the revision. outcome_flipped is not an
+		// immutable column, so it can be set after the insert above.
+		if model.IsOutcomeFlip(supersededOutcome, d.Outcome) {
+			if _, err := tx.Exec(ctx,
+				`UPDATE decisions SET outcome_flipped = true WHERE id = $1 AND org_id = $2`,
+				d.ID, params.OrgID,
+			); err != nil {
+				return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: flag outcome flip: %w", err)
+			}
+			d.OutcomeFlipped = true
 		}
 		// Queue search index deletion for the superseded decision.
 		if err := queueSearchOutbox(ctx, tx, *d.SupersedesID, params.OrgID, "delete"); err != nil {
@@ -251,6 +317,7 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 			now, params.AgentID, map[string]any{
 				"superseded_decision_id": d.SupersedesID.String(),
 				"new_decision_id":        d.ID.String(),
+				"outcome_flipped":        d.OutcomeFlipped,
 			}, now,
 		); err != nil {
 			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: insert supersession event: %w", err)
@@ -270,6 +337,7 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 				"superseded_by":   d.ID,
 				"new_decision_id": d.ID,
 				"superseded_id":   *d.SupersedesID,
+				"outcome_flipped": d.OutcomeFlipped,
 			},
 		}); err != nil {
 			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: audit supersession in trace tx: %w", err)
@@ -299,5 +367,37 @@ func (db *DB) createTraceInTx(ctx context.Context, tx pgx.Tx, params CreateTrace
 		}
 	}
 
+	// 7. Caller hook (e.g. blocking audit sink write-through).
+	if params.BeforeCommit != nil {
+		if err := params.BeforeCommit(ctx, d); err != nil {
+			return model.AgentRun{}, model.Decision{}, fmt.Errorf("storage: before commit in trace tx: %w", err)
+		}
+	}
+
 	return run, d, nil
 }
+
+// assignBatchID returns the batch for d: the batch of the agent's most recent
+// decision in the same session when that decision falls within window of
+// d.ValidFrom, or a new batch otherwise. Revised and retracted decisions
+// still count, since they were part of the burst when they were made.
+func assignBatchID(ctx context.Context, tx pgx.Tx, d model.Decision, window time.Duration) (uuid.UUID, error) {
+	var (
+		prevBatch *uuid.UUID
+		prevAt    time.Time
+	)
+	err := tx.QueryRow(ctx,
+		`SELECT batch_id, valid_from FROM decisions
+		 WHERE org_id = $1 AND agent_id = $2 AND session_id = $3
+		 ORDER BY valid_from DESC
+		 LIMIT 1`,
+		d.OrgID, d.AgentID, *d.SessionID,
+	).Scan(&prevBatch, &prevAt)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("storage: find previous batch: %w", err)
+	}
+	if prevBatch != nil && d.ValidFrom.Sub(prevAt) <= window {
+		return *prevBatch, nil
+	}
+	return uuid.New(), nil
+}
@@ -7,6 +7,7 @@ package storage
 // free from PostgreSQL compile-time dependencies (ADR-009).
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"time"
@@ -48,11 +49,25 @@ type CreateTraceParams struct {
 	SessionID    *uuid.UUID
 	AgentContext map[string]any
 
+	// BatchWindow, when positive, groups the decision with the same agent's
+	// previous decision in the session if that one was made within the
+	// window (see assignBatchID). Zero disables batching.
+	BatchWindow time.Duration
+
+	// ConfidencePrecision, when positive, rounds confidence (and its bounds)
+	// to that many decimal places before storage and hashes it with
+	// integrity.ComputeRoundedContentHash. Zero stores confidence exactly.
+	ConfidencePrecision int
+
 	// AuditEntry, when non-nil, is inserted into mutation_audit_log inside the
 	// same transaction. ResourceID is populated automatically from the generated
 	// decision ID. This ensures the audit record is atomic with the trace —
 	// if the tx rolls back, the audit entry never persists.
 	AuditEntry *MutationAuditEntry
+
+	// BeforeCommit, when non-nil, is called with the written decision as the
+	// last step inside the transaction. An error rolls the trace back.
+	BeforeCommit func(ctx context.Context, d model.Decision) error
 }
 
 // AdjudicateConflictInTraceParams holds data needed for the conflict adjudication
@@ -93,14 +108,17 @@ type MutationAuditEntry struct {
 type ConflictFilters struct {
 	DecisionType *string
 	AgentID      *string
-	ConflictKind *string    // "cross_agent" or "self_contradiction"
+	ConflictKind *string    // "cross_agent", "self_contradiction", or "consensus_drift"
 	Status       *string    // "open", "resolved", "false_positive"
 	StatusIn     []string   // Multi-value status filter (OR). Takes precedence over Status when set.
 	Severity     *string    // "critical", "high", "medium", "low"
 	Category     *string    // "factual", "assessment", "strategic", "temporal"
+	ReasonCode   *string    // "outcome_opposite", "confidence_divergence", "same_input_different_outcome"
 	DecisionID   *uuid.UUID // conflicts involving this decision (A or B side)
 	GroupID      *uuid.UUID // conflicts belonging to this conflict group
 	Project      *string    // conflicts where project_a or project_b matches
+	DetectedFrom *time.Time // detected_at >= DetectedFrom
+	DetectedTo   *time.Time // detected_at < DetectedTo
 }
 
 // ConflictStatusCounts holds the number of conflicts in each resolution status.
@@ -162,12 +180,32 @@ type UnembeddedDecision struct {
 	Reasoning    *string
 }
 
+// UnscoredDecision holds the fields needed to backfill a completeness score.
+type UnscoredDecision struct {
+	ID               uuid.UUID
+	OrgID            uuid.UUID
+	Outcome          string
+	Confidence       float32
+	Reasoning        *string
+	HasPrecedentRef  bool
+	RejectionReasons []string // Non-null rejection reasons of the decision's alternatives.
+	EvidenceCount    int
+}
+
 // DecisionRef is a lightweight reference to a decision for batch operations.
 type DecisionRef struct {
 	ID    uuid.UUID
 	OrgID uuid.UUID
 }
 
+// DuplicateMatch is an existing current decision found to be near-identical
+// to one about to be traced.
+type DuplicateMatch struct {
+	DecisionID uuid.UUID
+	RunID      uuid.UUID
+	Similarity float64 // Cosine similarity of the decision embeddings.
+}
+
 // ---------------------------------------------------------------------------
 // Evidence types (originally in evidence.go)
 // ---------------------------------------------------------------------------
@@ -326,6 +364,30 @@ type DecisionTypeCount struct {
 	Count        int    `json:"count"`
 }
 
+// Decision stats groupings accepted by AggregateDecisionStats.
+const (
+	StatsGroupByDecisionType = "decision_type"
+	StatsGroupByAgent        = "agent_id"
+	StatsGroupByDay          = "day"
+)
+
+// DecisionStatsBucket aggregates current decisions sharing one group key: a
+// decision_type, an agent_id, or a UTC day formatted as YYYY-MM-DD.
+type DecisionStatsBucket struct {
+	Key             string  `json:"key"`
+	Count           int     `json:"count"`
+	AvgConfidence   float64 `json:"avg_confidence"`
+	AvgCompleteness float64 `json:"avg_completeness"`
+}
+
+// DecisionTypeSummary describes one decision_type in use within an org, as
+// returned by GET /v1/decision-types.
+type DecisionTypeSummary struct {
+	DecisionType string    `json:"decision_type"`
+	Count        int       `json:"count"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
 // DecisionTypeCompleteness holds per-type aggregate completeness metrics with
 // health threshold enrichment. ExpectedMin and Status are populated server-side
 // by the tracehealth service, not by the storage query.
@@ -368,6 +430,51 @@ type DecisionLineage struct {
 	CitedByMore bool           `json:"cited_by_has_more"`
 }
 
+// AncestryEntry is one decision in a precedent chain, annotated with what
+// has happened to it since it was cited. Depth 1 is the direct precedent.
+type AncestryEntry struct {
+	LineageEntry
+	Depth int `json:"depth"`
+	// Superseded: a later revision replaced this decision.
+	Superseded   bool       `json:"superseded"`
+	SupersededBy *uuid.UUID `json:"superseded_by,omitempty"`
+	// Expired: the validity window closed without a replacement revision
+	// (e.g. the decision was retracted).
+	Expired bool `json:"expired"`
+	// Contested: the decision is party to at least one open conflict.
+	Contested     bool `json:"contested"`
+	OpenConflicts int  `json:"open_conflicts"`
+}
+
+// Stale reports whether this ancestor no longer stands as originally cited.
+func (e AncestryEntry) Stale() bool {
+	return e.Superseded || e.Expired || e.Contested
+}
+
+// DecisionAncestryHealth reports whether the precedents a decision cites,
+// transitively, still hold. StaleAncestry is true when any ancestor is stale.
+// Ancestors removed by retention cannot appear: purging a decision clears
+// precedent_ref on the decisions that cited it, which ends the chain there.
+type DecisionAncestryHealth struct {
+	DecisionID     uuid.UUID       `json:"decision_id"`
+	Ancestors      []AncestryEntry `json:"ancestors"`
+	StaleAncestry  bool            `json:"stale_ancestry"`
+	StaleAncestors int             `json:"stale_ancestors"`
+	// Truncated is true when the chain is deeper than the traversal limit.
+	Truncated bool `json:"truncated"`
+}
+
+// Summarize recomputes the rolled-up stale counters from Ancestors.
+func (h *DecisionAncestryHealth) Summarize() {
+	h.StaleAncestors = 0
+	for _, a := range h.Ancestors {
+		if a.Stale() {
+			h.StaleAncestors++
+		}
+	}
+	h.StaleAncestry = h.StaleAncestors > 0
+}
+
 // ---------------------------------------------------------------------------
 // Utility functions (shared between full and lite builds)
 // ---------------------------------------------------------------------------
@@ -22,10 +22,12 @@ import (
 type Shutdown func(ctx context.Context) error
 
 // Init configures the global OpenTelemetry tracer and meter providers.
-// If endpoint is empty, OTEL is disabled and no-op providers are used.
+// If endpoint is empty, OTLP export is disabled and no-op providers are used,
+// except that any extra metric readers (e.g. the Prometheus /metrics
+// exporter) still get a meter provider of their own.
 // Returns a shutdown function that must be called during graceful shutdown.
-func Init(ctx context.Context, endpoint, serviceName, version string, insecure bool, sampleRate float64) (Shutdown, error) {
-	if endpoint == "" {
+func Init(ctx context.Context, endpoint, serviceName, version string, insecure bool, sampleRate float64, readers ...sdkmetric.Reader) (Shutdown, error) {
+	if endpoint == "" && len(readers) == 0 {
 		return func(ctx context.Context) error { return nil }, nil
 	}
 
@@ -39,6 +41,12 @@ func Init(ctx context.Context, endpoint, serviceName, version string, insecure b
 		return nil, fmt.Errorf("telemetry: create resource: %w", err)
 	}
 
+	if endpoint == "" {
+		mp := newMeterProvider(res, readers)
+		otel.SetMeterProvider(mp)
+		return mp.Shutdown, nil
+	}
+
 	// Trace exporter.
 	traceOpts := []otlptracehttp.Option{
 		otlptracehttp.WithEndpoint(endpoint),
@@ -89,14 +97,11 @@ func Init(ctx context.Context, endpoint, serviceName, version string, insecure b
 		return nil, fmt.Errorf("telemetry: create metric exporter: %w", err)
 	}
 
-	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(
-			sdkmetric.NewPeriodicReader(metricExp,
-				sdkmetric.WithInterval(15*time.Second),
-			),
+	mp := newMeterProvider(res, append([]sdkmetric.Reader{
+		sdkmetric.NewPeriodicReader(metricExp,
+			sdkmetric.WithInterval(15*time.Second),
 		),
-		sdkmetric.WithResource(res),
-	)
+	}, readers...))
 	otel.SetMeterProvider(mp)
 
 	shutdown := func(ctx context.Context) error {
@@ -106,6 +111,14 @@ func Init(ctx context.Context, endpoint, serviceName, version string, insecure b
 	return shutdown, nil
 }
 
+func newMeterProvider(res *resource.Resource, readers []sdkmetric.Reader) *sdkmetric.MeterProvider {
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	for _, r := range readers {
+		opts = append(opts, sdkmetric.WithReader(r))
+	}
+	return sdkmetric.NewMeterProvider(opts...)
+}
+
 // Meter returns the global meter for the given instrumentation scope.
 func Meter(name string) metric.Meter {
 	return otel.GetMeterProvider().Meter(name)
@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -121,6 +122,51 @@ func (tc *TestContainer) NewTestDB(ctx context.Context, logger *slog.Logger) (*s
 	return db, nil
 }
 
+// NewIsolatedTestDB creates a separate database named name in this container,
+// migrates it, and returns a storage.DB connected to it. Use it when a test
+// needs a second, empty instance, e.g. to restore data exported from the
+// shared test database.
+func (tc *TestContainer) NewIsolatedTestDB(ctx context.Context, name string, logger *slog.Logger) (*storage.DB, error) {
+	conn, err := pgx.Connect(ctx, tc.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("testutil: connect: %w", err)
+	}
+	_, err = conn.Exec(ctx, "CREATE DATABASE "+pgx.Identifier{name}.Sanitize())
+	_ = conn.Close(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("testutil: create database %s: %w", name, err)
+	}
+
+	u, err := url.Parse(tc.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("testutil: parse DSN: %w", err)
+	}
+	u.Path = "/" + name
+	dsn := u.String()
+
+	// Extensions must exist before the pool registers pgvector types.
+	conn, err = pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("testutil: connect to %s: %w", name, err)
+	}
+	for _, ext := range []string{"vector", "timescaledb"} {
+		if _, err := conn.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS "+ext); err != nil {
+			_ = conn.Close(ctx)
+			return nil, fmt.Errorf("testutil: create %s extension: %w", ext, err)
+		}
+	}
+	_ = conn.Close(ctx)
+
+	db, err := storage.New(ctx, dsn, "", logger, storage.PoolOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("testutil: create DB: %w", err)
+	}
+	if err := db.RunMigrations(ctx, migrations.FS); err != nil {
+		return nil, fmt.Errorf("testutil: run migrations: %w", err)
+	}
+	return db, nil
+}
+
 // Terminate stops and removes the container.
 func (tc *TestContainer) Terminate() {
 	_ = tc.Container.Terminate(context.Background())
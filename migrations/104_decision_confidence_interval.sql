@@ -0,0 +1,20 @@
+-- 104: Add optional confidence interval bounds to decisions.
+--
+-- confidence remains the required point estimate. confidence_low and
+-- confidence_high let agents record their uncertainty around it. Both bounds
+-- are set together or not at all, and must bracket the point estimate.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS confidence_low REAL,
+    ADD COLUMN IF NOT EXISTS confidence_high REAL;
+
+ALTER TABLE decisions
+    ADD CONSTRAINT decisions_confidence_interval_valid
+    CHECK (
+        (confidence_low IS NULL AND confidence_high IS NULL)
+        OR (confidence_low IS NOT NULL AND confidence_high IS NOT NULL
+            AND 0 <= confidence_low
+            AND confidence_low <= confidence
+            AND confidence <= confidence_high
+            AND confidence_high <= 1)
+    );
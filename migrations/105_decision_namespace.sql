@@ -0,0 +1,13 @@
+-- 105: Add namespace column to decisions for logical partitioning within an org.
+--
+-- A namespace (e.g. prod, staging) sits below org and above agent. Queries,
+-- precedent checks, and conflict detection are scoped to the caller's
+-- namespace so staging traffic does not pollute production precedent.
+-- Existing rows belong to the 'default' namespace.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS idx_decisions_org_namespace_current
+    ON decisions (org_id, namespace, valid_from DESC)
+    WHERE valid_to IS NULL;
@@ -0,0 +1,51 @@
+-- 106: Append-only read access log for access transparency.
+--
+-- When AKASHI_ACCESS_LOG_ENABLED is set, read endpoints record who looked at
+-- which decisions. Rows are sampled and capped at write time (see
+-- AKASHI_ACCESS_LOG_SAMPLE_RATE / AKASHI_ACCESS_LOG_MAX_IDS); result_count is
+-- the untruncated number of resources the request touched.
+--
+-- Like mutation_audit_log, there is intentionally no FK to organizations:
+-- the access record must survive org deletion.
+
+CREATE TABLE IF NOT EXISTS access_audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    occurred_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    request_id      TEXT NOT NULL,
+    org_id          UUID NOT NULL,
+    actor_agent_id  TEXT NOT NULL,
+    actor_role      TEXT NOT NULL,
+    http_method     TEXT NOT NULL,
+    endpoint        TEXT NOT NULL,
+    resource_type   TEXT NOT NULL,
+    resource_ids    UUID[] NOT NULL DEFAULT '{}',
+    result_count    INTEGER NOT NULL DEFAULT 0,
+    metadata        JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_audit_log_org_time
+    ON access_audit_log (org_id, occurred_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_access_audit_log_resource_ids
+    ON access_audit_log USING GIN (resource_ids);
+
+CREATE OR REPLACE FUNCTION access_audit_log_immutable_guard()
+RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    RAISE EXCEPTION 'access_audit_log is append-only';
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_access_audit_log_no_update ON access_audit_log;
+CREATE TRIGGER trg_access_audit_log_no_update
+BEFORE UPDATE ON access_audit_log
+FOR EACH ROW
+EXECUTE FUNCTION access_audit_log_immutable_guard();
+
+DROP TRIGGER IF EXISTS trg_access_audit_log_no_delete ON access_audit_log;
+CREATE TRIGGER trg_access_audit_log_no_delete
+BEFORE DELETE ON access_audit_log
+FOR EACH ROW
+EXECUTE FUNCTION access_audit_log_immutable_guard();
@@ -0,0 +1,39 @@
+-- 107: Per-agent decision usage rollup for quota enforcement.
+--
+-- One row per (org, agent, period, period_start), incremented by a trigger on
+-- every decision insert so all write paths (trace, events, revisions,
+-- adjudication) are counted. Periods are UTC calendar days and months.
+-- Org-level usage is the sum over agent rows. Deletions do not decrement:
+-- quotas bound write volume, not stored volume.
+
+CREATE TABLE IF NOT EXISTS decision_usage (
+    org_id          UUID NOT NULL,
+    agent_id        TEXT NOT NULL,
+    period          TEXT NOT NULL CHECK (period IN ('day', 'month')),
+    period_start    DATE NOT NULL,
+    decision_count  BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (org_id, agent_id, period, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_usage_org_period
+    ON decision_usage (org_id, period, period_start);
+
+CREATE OR REPLACE FUNCTION increment_decision_usage()
+RETURNS trigger AS $$
+DECLARE
+  today DATE := (now() AT TIME ZONE 'UTC')::date;
+BEGIN
+  INSERT INTO decision_usage (org_id, agent_id, period, period_start, decision_count)
+  VALUES (NEW.org_id, NEW.agent_id, 'day', today, 1),
+         (NEW.org_id, NEW.agent_id, 'month', date_trunc('month', today)::date, 1)
+  ON CONFLICT (org_id, agent_id, period, period_start) DO UPDATE SET
+    decision_count = decision_usage.decision_count + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_decisions_usage ON decisions;
+CREATE TRIGGER trg_decisions_usage
+  AFTER INSERT ON decisions
+  FOR EACH ROW
+  EXECUTE FUNCTION increment_decision_usage();
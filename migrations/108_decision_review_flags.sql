@@ -0,0 +1,83 @@
+-- 108: Review flags for decisions, with SLA tracking.
+--
+-- A decision enters review when it is traced with low confidence (< 0.4,
+-- matching model.LowConfidenceThreshold), when it becomes party to a scored
+-- conflict, or when flagged manually. Triggers cover the automatic cases so
+-- every write path flags consistently. One row per decision: re-flagging a
+-- reviewed decision reopens it with a fresh flagged_at; re-flagging a pending
+-- one leaves its age untouched so SLA clocks cannot be reset.
+--
+-- overdue_notified_at records that the overdue notification was sent, so the
+-- background sweep notifies each overdue flag exactly once.
+
+CREATE TABLE IF NOT EXISTS decision_review_flags (
+    decision_id          UUID PRIMARY KEY REFERENCES decisions(id) ON DELETE CASCADE,
+    org_id               UUID NOT NULL,
+    reason               TEXT NOT NULL CHECK (reason IN ('low_confidence', 'conflict', 'manual')),
+    note                 TEXT,
+    flagged_by           TEXT,
+    flagged_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    reviewed_at          TIMESTAMPTZ,
+    reviewed_by          TEXT,
+    review_note          TEXT,
+    overdue_notified_at  TIMESTAMPTZ
+);
+
+-- Serves the review queue and the overdue sweep:
+-- flagged_at < now() - sla AND reviewed_at IS NULL.
+CREATE INDEX IF NOT EXISTS idx_decision_review_flags_pending
+    ON decision_review_flags (org_id, flagged_at)
+    WHERE reviewed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_decision_review_flags_unnotified
+    ON decision_review_flags (flagged_at)
+    WHERE reviewed_at IS NULL AND overdue_notified_at IS NULL;
+
+CREATE OR REPLACE FUNCTION flag_decision_for_review(p_decision_id UUID, p_org_id UUID, p_reason TEXT)
+RETURNS void AS $$
+BEGIN
+  INSERT INTO decision_review_flags (decision_id, org_id, reason)
+  VALUES (p_decision_id, p_org_id, p_reason)
+  ON CONFLICT (decision_id) DO UPDATE SET
+    reason = EXCLUDED.reason,
+    note = NULL,
+    flagged_by = NULL,
+    flagged_at = now(),
+    reviewed_at = NULL,
+    reviewed_by = NULL,
+    review_note = NULL,
+    overdue_notified_at = NULL
+  WHERE decision_review_flags.reviewed_at IS NOT NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION flag_low_confidence_decision()
+RETURNS trigger AS $$
+BEGIN
+  IF NEW.confidence < 0.4 THEN
+    PERFORM flag_decision_for_review(NEW.id, NEW.org_id, 'low_confidence');
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_decisions_review_flag ON decisions;
+CREATE TRIGGER trg_decisions_review_flag
+  AFTER INSERT ON decisions
+  FOR EACH ROW
+  EXECUTE FUNCTION flag_low_confidence_decision();
+
+CREATE OR REPLACE FUNCTION flag_conflicting_decisions()
+RETURNS trigger AS $$
+BEGIN
+  PERFORM flag_decision_for_review(NEW.decision_a_id, NEW.org_id, 'conflict');
+  PERFORM flag_decision_for_review(NEW.decision_b_id, NEW.org_id, 'conflict');
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_scored_conflicts_review_flag ON scored_conflicts;
+CREATE TRIGGER trg_scored_conflicts_review_flag
+  AFTER INSERT ON scored_conflicts
+  FOR EACH ROW
+  EXECUTE FUNCTION flag_conflicting_decisions();
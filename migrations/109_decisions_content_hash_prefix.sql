@@ -0,0 +1,10 @@
+-- 109: Org-scoped content hash index for hash and hash-prefix lookups.
+--
+-- GET /v1/decisions/by-hash matches content_hash exactly or by prefix
+-- (LIKE 'abc%'). text_pattern_ops lets the prefix form use the index
+-- regardless of the database collation. The existing
+-- idx_decisions_content_hash is not org-scoped and cannot serve LIKE.
+
+CREATE INDEX IF NOT EXISTS idx_decisions_org_content_hash_pattern
+    ON decisions (org_id, content_hash text_pattern_ops)
+    WHERE content_hash IS NOT NULL;
@@ -0,0 +1,16 @@
+-- 110: LLM-written explanation and resolution suggestion on scored conflicts.
+--
+-- Filled asynchronously by the conflict suggestion worker when
+-- AKASHI_CONFLICT_SUGGESTIONS_ENABLED is set. suggested_at doubles as the
+-- worker's cursor: open conflicts with suggested_at IS NULL are pending.
+-- Kept separate from explanation, which the validator writes while scoring.
+
+ALTER TABLE scored_conflicts
+    ADD COLUMN IF NOT EXISTS suggestion_explanation TEXT,
+    ADD COLUMN IF NOT EXISTS suggested_resolution   TEXT,
+    ADD COLUMN IF NOT EXISTS suggestion_model       TEXT,
+    ADD COLUMN IF NOT EXISTS suggested_at           TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_scored_conflicts_suggestion_pending
+    ON scored_conflicts (detected_at)
+    WHERE status = 'open' AND suggested_at IS NULL;
@@ -0,0 +1,38 @@
+-- 111: Reviewer assignment for review flags.
+--
+-- assigned_reviewer is chosen from the org's review_routing settings by the
+-- producing agent's tags. routed_at records that routing was evaluated, so
+-- flags no route matched are not re-examined on every queue read. Both are
+-- cleared when a reviewed flag is reopened, so the new flag is routed under
+-- the policy current at that time.
+
+ALTER TABLE decision_review_flags ADD COLUMN IF NOT EXISTS assigned_reviewer TEXT;
+ALTER TABLE decision_review_flags ADD COLUMN IF NOT EXISTS routed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_decision_review_flags_assigned
+    ON decision_review_flags (org_id, assigned_reviewer, flagged_at)
+    WHERE reviewed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_decision_review_flags_unrouted
+    ON decision_review_flags (org_id, flagged_at)
+    WHERE reviewed_at IS NULL AND routed_at IS NULL;
+
+CREATE OR REPLACE FUNCTION flag_decision_for_review(p_decision_id UUID, p_org_id UUID, p_reason TEXT)
+RETURNS void AS $$
+BEGIN
+  INSERT INTO decision_review_flags (decision_id, org_id, reason)
+  VALUES (p_decision_id, p_org_id, p_reason)
+  ON CONFLICT (decision_id) DO UPDATE SET
+    reason = EXCLUDED.reason,
+    note = NULL,
+    flagged_by = NULL,
+    flagged_at = now(),
+    reviewed_at = NULL,
+    reviewed_by = NULL,
+    review_note = NULL,
+    overdue_notified_at = NULL,
+    assigned_reviewer = NULL,
+    routed_at = NULL
+  WHERE decision_review_flags.reviewed_at IS NOT NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,32 @@
+-- 112: Re-embedding jobs for embedding provider/model migrations.
+--
+-- POST /v1/admin/reembed first creates a pending job and returns its
+-- confirmation_token; repeating the call with the token runs it. Runs are
+-- bounded, so a job advances cursor_id (the last decision re-embedded, in id
+-- order) and is resumed by calling again with the same token. At most one
+-- unfinished job per org.
+
+CREATE TABLE IF NOT EXISTS reembed_jobs (
+    id                  UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id              UUID        NOT NULL,
+    from_model          TEXT        NOT NULL,
+    to_model            TEXT        NOT NULL,
+    confirmation_token  TEXT        NOT NULL,
+    status              TEXT        NOT NULL DEFAULT 'pending'
+                                    CHECK (status IN ('pending', 'running', 'completed')),
+    cursor_id           UUID,
+    total               INT         NOT NULL DEFAULT 0,
+    processed           INT         NOT NULL DEFAULT 0,
+    failed              INT         NOT NULL DEFAULT 0,
+    created_by          TEXT        NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at        TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reembed_jobs_open
+    ON reembed_jobs (org_id)
+    WHERE status <> 'completed';
+
+CREATE INDEX IF NOT EXISTS idx_reembed_jobs_org_created
+    ON reembed_jobs (org_id, created_at DESC);
@@ -0,0 +1,13 @@
+-- 113: Flag revisions whose outcome reverses the superseded decision.
+--
+-- outcome_flipped is set when a revision's verdict has the opposite polarity
+-- of the decision it supersedes (approve -> deny). Written in the same
+-- transaction that invalidates the superseded row; never changes afterwards.
+-- Existing revisions are not backfilled.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS outcome_flipped BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_decisions_org_outcome_flipped
+    ON decisions (org_id, valid_from DESC)
+    WHERE outcome_flipped;
@@ -0,0 +1,30 @@
+-- 114: Near-duplicate decision pairs for the corpus duplicates report.
+--
+-- A periodic scan finds, for each current decision with an embedding, its
+-- nearest neighbours in the same org and namespace, and stores pairs whose
+-- decision similarity and outcome similarity both reach the scan floor.
+-- Outcome similarity is 1 for textually identical outcomes, else the cosine
+-- similarity of the outcome embeddings. Each scan replaces the org's pairs.
+-- GET /v1/stats/duplicates clusters these pairs at a caller threshold that
+-- must be at or above the floor recorded in decision_duplicate_scans.
+
+CREATE TABLE IF NOT EXISTS decision_duplicate_pairs (
+    org_id              UUID NOT NULL,
+    decision_a          UUID NOT NULL REFERENCES decisions(id) ON DELETE CASCADE,
+    decision_b          UUID NOT NULL REFERENCES decisions(id) ON DELETE CASCADE,
+    similarity          REAL NOT NULL,
+    outcome_similarity  REAL NOT NULL,
+    PRIMARY KEY (decision_a, decision_b),
+    CHECK (decision_a < decision_b)
+);
+
+CREATE INDEX IF NOT EXISTS idx_decision_duplicate_pairs_org
+    ON decision_duplicate_pairs (org_id, similarity DESC);
+
+CREATE TABLE IF NOT EXISTS decision_duplicate_scans (
+    org_id             UUID PRIMARY KEY,
+    floor              REAL NOT NULL,
+    decisions_scanned  INTEGER NOT NULL,
+    pairs_found        INTEGER NOT NULL,
+    scanned_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
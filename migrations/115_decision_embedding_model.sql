@@ -0,0 +1,10 @@
+-- 115: Record which embedding model produced each decision's vectors.
+--
+-- During an embedding model migration the corpus briefly holds vectors from
+-- two models, whose similarities are meaningless across models. Similarity
+-- search compares only vectors tagged with the same model. Rows embedded
+-- before this column existed stay NULL and are treated as compatible with
+-- any model until they are re-embedded.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS embedding_model TEXT;
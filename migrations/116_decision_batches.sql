@@ -0,0 +1,13 @@
+-- 116: Batch grouping for agents that decide in bursts.
+--
+-- When AKASHI_DECISION_BATCH_WINDOW is set, a traced decision joins the batch
+-- of the same agent's previous decision in the same session if that decision
+-- was made within the window; otherwise it starts a new batch. Decisions
+-- traced without a session, or while batching is disabled, have no batch.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS batch_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_decisions_org_batch
+    ON decisions (org_id, batch_id, valid_from)
+    WHERE batch_id IS NOT NULL;
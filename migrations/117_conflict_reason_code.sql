@@ -0,0 +1,16 @@
+-- 117: Reason code on scored conflicts.
+--
+-- significance and outcome_divergence say how strongly two decisions
+-- conflict but not why. reason_code names the signal that dominated the
+-- score so triage can separate opposite verdicts from confidence-only
+-- divergence. Set by the scorer; existing rows stay NULL until rescored
+-- (POST /v1/admin/conflicts/rescore).
+
+ALTER TABLE scored_conflicts
+    ADD COLUMN IF NOT EXISTS reason_code TEXT CHECK (reason_code IN (
+        'outcome_opposite', 'confidence_divergence', 'same_input_different_outcome'
+    ));
+
+CREATE INDEX IF NOT EXISTS idx_scored_conflicts_org_reason
+    ON scored_conflicts (org_id, reason_code)
+    WHERE reason_code IS NOT NULL;
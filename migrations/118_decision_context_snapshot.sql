@@ -0,0 +1,10 @@
+-- 118: Typed, size-capped agent context snapshot per decision.
+--
+-- agent_context stays free-form. context_snapshot holds the reproducibility
+-- fields an agent reports at trace time (model, system prompt hash, sampling
+-- parameters, tool list), bounded by AKASHI_CONTEXT_SNAPSHOT_MAX_BYTES.
+-- context_snapshot_truncated records that the server dropped tools to fit.
+
+ALTER TABLE decisions
+    ADD COLUMN IF NOT EXISTS context_snapshot JSONB,
+    ADD COLUMN IF NOT EXISTS context_snapshot_truncated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,21 @@
+-- 119: Server-side default session binding per agent.
+--
+-- POST /v1/sessions opens a session for an agent; traces from that agent
+-- without an X-Akashi-Session header join the open session until it is
+-- closed. At most one session per agent is open at a time: opening a new one
+-- closes the previous. Sessions go with their agent on deletion.
+
+CREATE TABLE IF NOT EXISTS agent_sessions (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    org_id      UUID NOT NULL,
+    agent_id    TEXT NOT NULL,
+    started_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    closed_at   TIMESTAMPTZ,
+    CONSTRAINT fk_agent_sessions_agent
+        FOREIGN KEY (org_id, agent_id) REFERENCES agents(org_id, agent_id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agent_sessions_open
+    ON agent_sessions (org_id, agent_id)
+    WHERE closed_at IS NULL;
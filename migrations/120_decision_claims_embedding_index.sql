@@ -0,0 +1,12 @@
+-- 120: ANN index for bounded claim-overlap candidate retrieval.
+--
+-- Conflict scoring looks up each new decision's claims' nearest existing
+-- claims (top AKASHI_CONFLICT_CLAIM_OVERLAP_LIMIT per claim) instead of
+-- comparing claim pairs exhaustively, so per-decision cost stays bounded as
+-- the claims table grows. Decision vectors moved to Qdrant in 049, but claim
+-- vectors were never indexed there, so this index lives in Postgres. Claims
+-- whose embedding failed are excluded.
+
+CREATE INDEX IF NOT EXISTS idx_decision_claims_embedding
+    ON decision_claims USING hnsw (embedding vector_cosine_ops)
+    WHERE embedding IS NOT NULL;
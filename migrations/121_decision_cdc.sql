@@ -0,0 +1,116 @@
+-- 121: Replayable change-data-capture log for decisions.
+--
+-- LISTEN/NOTIFY is fire-and-forget: a consumer that is disconnected misses
+-- events. decision_cdc is a durable log that consumers poll by LSN through
+-- GET /v1/cdc/decisions?after_lsn=N.
+--
+-- Triggers append an entry inside the same transaction as every decision
+-- mutation, so no write path can skip the log:
+--   create  — INSERT without supersedes_id (after = new row)
+--   revise  — INSERT with supersedes_id (before = superseded row, after = new row)
+--   archive — valid_to set on an active decision (retraction or supersession)
+--   delete  — hard delete by retention or agent erasure (before = identity only)
+-- A revision therefore appends archive for the old decision followed by
+-- revise for the new one. Row images exclude embeddings and search_vector.
+--
+-- Gap-free ordering: a sequence value taken at insert time does not follow
+-- commit order, so a poller could read LSN 11 before the transaction holding
+-- LSN 10 commits and skip it forever. Entries are therefore inserted with a
+-- NULL lsn and numbered later by a single sequencer (serialized by an
+-- advisory lock) that only sees committed rows. Every LSN is assigned after
+-- all smaller ones are visible.
+--
+-- On delete, the payloads of earlier entries for the decision are scrubbed so
+-- erased content does not survive in the log; the entries themselves remain.
+-- Sequenced entries older than AKASHI_CDC_RETENTION are compacted away, and
+-- decision_cdc_state.compacted_lsn records the highest LSN removed so
+-- consumers can tell when their cursor fell behind the retained window.
+
+CREATE SEQUENCE IF NOT EXISTS decision_cdc_lsn_seq;
+
+CREATE TABLE IF NOT EXISTS decision_cdc (
+    id           BIGSERIAL PRIMARY KEY,
+    lsn          BIGINT UNIQUE,
+    org_id       UUID NOT NULL,
+    decision_id  UUID NOT NULL,
+    operation    TEXT NOT NULL CHECK (operation IN ('create', 'revise', 'archive', 'delete')),
+    before       JSONB,
+    after        JSONB,
+    recorded_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Serves polling: WHERE org_id = $1 AND lsn > $2 ORDER BY lsn.
+CREATE INDEX IF NOT EXISTS idx_decision_cdc_org_lsn
+    ON decision_cdc (org_id, lsn)
+    WHERE lsn IS NOT NULL;
+
+-- Serves the sequencer.
+CREATE INDEX IF NOT EXISTS idx_decision_cdc_unsequenced
+    ON decision_cdc (id)
+    WHERE lsn IS NULL;
+
+-- Serves payload scrubbing on delete.
+CREATE INDEX IF NOT EXISTS idx_decision_cdc_decision
+    ON decision_cdc (decision_id);
+
+CREATE TABLE IF NOT EXISTS decision_cdc_state (
+    singleton      BOOLEAN PRIMARY KEY DEFAULT true CHECK (singleton),
+    compacted_lsn  BIGINT NOT NULL DEFAULT 0
+);
+INSERT INTO decision_cdc_state (singleton) VALUES (true) ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION decision_cdc_image(d decisions)
+RETURNS jsonb AS $$
+  SELECT to_jsonb(d) - 'embedding' - 'outcome_embedding' - 'search_vector';
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION record_decision_cdc()
+RETURNS trigger AS $$
+DECLARE
+  prev decisions;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    IF NEW.supersedes_id IS NULL THEN
+      INSERT INTO decision_cdc (org_id, decision_id, operation, after)
+      VALUES (NEW.org_id, NEW.id, 'create', decision_cdc_image(NEW));
+    ELSE
+      SELECT * INTO prev FROM decisions WHERE id = NEW.supersedes_id;
+      INSERT INTO decision_cdc (org_id, decision_id, operation, before, after)
+      VALUES (NEW.org_id, NEW.id, 'revise',
+              CASE WHEN prev.id IS NULL THEN NULL ELSE decision_cdc_image(prev) END,
+              decision_cdc_image(NEW));
+    END IF;
+    RETURN NEW;
+  ELSIF TG_OP = 'UPDATE' THEN
+    INSERT INTO decision_cdc (org_id, decision_id, operation, before, after)
+    VALUES (NEW.org_id, NEW.id, 'archive', decision_cdc_image(OLD), decision_cdc_image(NEW));
+    RETURN NEW;
+  ELSE
+    UPDATE decision_cdc SET before = NULL, after = NULL
+     WHERE decision_id = OLD.id AND (before IS NOT NULL OR after IS NOT NULL);
+    INSERT INTO decision_cdc (org_id, decision_id, operation, before)
+    VALUES (OLD.org_id, OLD.id, 'delete',
+            jsonb_build_object('id', OLD.id, 'org_id', OLD.org_id, 'agent_id', OLD.agent_id));
+    RETURN OLD;
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_decisions_cdc_insert ON decisions;
+CREATE TRIGGER trg_decisions_cdc_insert
+  AFTER INSERT ON decisions
+  FOR EACH ROW
+  EXECUTE FUNCTION record_decision_cdc();
+
+DROP TRIGGER IF EXISTS trg_decisions_cdc_archive ON decisions;
+CREATE TRIGGER trg_decisions_cdc_archive
+  AFTER UPDATE OF valid_to ON decisions
+  FOR EACH ROW
+  WHEN (OLD.valid_to IS NULL AND NEW.valid_to IS NOT NULL)
+  EXECUTE FUNCTION record_decision_cdc();
+
+DROP TRIGGER IF EXISTS trg_decisions_cdc_delete ON decisions;
+CREATE TRIGGER trg_decisions_cdc_delete
+  AFTER DELETE ON decisions
+  FOR EACH ROW
+  EXECUTE FUNCTION record_decision_cdc();
@@ -0,0 +1,18 @@
+-- 122: Per-org rate limits.
+--
+-- organizations.rate_limit_rps / rate_limit_burst override the process-wide
+-- AKASHI_RATE_LIMIT_RPS / AKASHI_RATE_LIMIT_BURST for every agent and API key
+-- in the org. Both NULL = use the global default; they are set and cleared
+-- together.
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS rate_limit_rps    DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS rate_limit_burst  INTEGER;
+
+ALTER TABLE organizations
+    DROP CONSTRAINT IF EXISTS chk_organizations_rate_limit;
+ALTER TABLE organizations
+    ADD CONSTRAINT chk_organizations_rate_limit CHECK (
+        (rate_limit_rps IS NULL AND rate_limit_burst IS NULL)
+        OR (rate_limit_rps > 0 AND rate_limit_burst > 0)
+    );
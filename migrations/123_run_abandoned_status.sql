@@ -0,0 +1,11 @@
+-- 123: 'abandoned' run status.
+--
+-- The idle-run sweeper (AKASHI_RUN_IDLE_TIMEOUT) closes runs that received no
+-- events or decisions within the idle window. It can mark them 'completed' or,
+-- by default, 'abandoned' so they stay distinguishable from runs an agent
+-- finished explicitly.
+
+ALTER TABLE agent_runs DROP CONSTRAINT IF EXISTS agent_runs_status_check;
+ALTER TABLE agent_runs
+    ADD CONSTRAINT agent_runs_status_check
+    CHECK (status IN ('running', 'completed', 'failed', 'abandoned'));
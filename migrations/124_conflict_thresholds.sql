@@ -0,0 +1,19 @@
+-- 124: Per-decision-type conflict significance thresholds.
+--
+-- The conflict scorer stores a conflict when its significance reaches
+-- AKASHI_CONFLICT_SIGNIFICANCE_THRESHOLD. A row here overrides that global
+-- default for one decision_type within an org: binary types can flag at low
+-- divergence while types that tolerate variety can require more.
+
+CREATE TABLE IF NOT EXISTS conflict_thresholds (
+    org_id                  UUID NOT NULL,
+    decision_type           TEXT NOT NULL,
+    significance_threshold  DOUBLE PRECISION NOT NULL,
+    updated_by              TEXT NOT NULL,
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, decision_type),
+    CONSTRAINT chk_conflict_thresholds_range
+        CHECK (significance_threshold > 0 AND significance_threshold <= 1),
+    CONSTRAINT fk_conflict_thresholds_org
+        FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- 125: Decision-level tags, independent of agent tags.
+--
+-- Agent tags (agents.tags) describe who made a decision; decision tags let
+-- callers label individual decisions ("postmortem", "q3-launch") and filter
+-- queries by them. Rows cascade with their decision, so hard-deleting an
+-- agent's decisions (GDPR erasure) removes their tags too.
+
+CREATE TABLE IF NOT EXISTS decision_tags (
+    decision_id UUID NOT NULL REFERENCES decisions(id) ON DELETE CASCADE,
+    org_id      UUID NOT NULL,
+    tag         TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (decision_id, tag)
+);
+
+-- Serves tag filters on decision queries: org_id = $1 AND tag = ANY($2).
+CREATE INDEX IF NOT EXISTS idx_decision_tags_org_tag
+    ON decision_tags (org_id, tag);
@@ -0,0 +1,23 @@
+-- 126: Consensus-drift conflicts.
+--
+-- A consensus_drift conflict records a small minority of related decisions
+-- that reached a different outcome from an established majority. The row's
+-- A side is the majority exemplar, the B side the minority exemplar, and
+-- minority_ids lists every decision in the minority.
+--
+-- A drift row can share its decision pair with an ordinary pairwise
+-- conflict, so pair uniqueness now includes conflict_kind.
+
+ALTER TABLE scored_conflicts DROP CONSTRAINT IF EXISTS scored_conflicts_conflict_kind_check;
+ALTER TABLE scored_conflicts ADD CONSTRAINT scored_conflicts_conflict_kind_check
+    CHECK (conflict_kind IN ('cross_agent', 'self_contradiction', 'consensus_drift'));
+
+ALTER TABLE conflict_groups DROP CONSTRAINT IF EXISTS conflict_groups_conflict_kind_check;
+ALTER TABLE conflict_groups ADD CONSTRAINT conflict_groups_conflict_kind_check
+    CHECK (conflict_kind IN ('cross_agent', 'self_contradiction', 'consensus_drift'));
+
+ALTER TABLE scored_conflicts ADD COLUMN IF NOT EXISTS minority_ids UUID[];
+
+ALTER TABLE scored_conflicts DROP CONSTRAINT IF EXISTS scored_conflicts_decision_a_id_decision_b_id_key;
+ALTER TABLE scored_conflicts ADD CONSTRAINT scored_conflicts_decision_pair_kind_key
+    UNIQUE (decision_a_id, decision_b_id, conflict_kind);
@@ -0,0 +1,26 @@
+-- 127: Refresh tokens.
+--
+-- POST /auth/token issues a refresh token alongside the access token so
+-- long-lived sessions can mint new access tokens at POST /auth/refresh
+-- without holding the raw API key. Tokens are stored as SHA-256 hashes and
+-- rotated on every use: the presented token is revoked and replaced_by
+-- points at its successor. api_key_id records the managed key the session
+-- started from, so revoking that key ends the session.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id           UUID        PRIMARY KEY,
+    org_id       UUID        NOT NULL,
+    agent_id     TEXT        NOT NULL,
+    token_hash   TEXT        NOT NULL UNIQUE,
+    api_key_id   UUID        REFERENCES api_keys(id) ON DELETE CASCADE,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at   TIMESTAMPTZ,
+    replaced_by  UUID,
+    CONSTRAINT fk_refresh_tokens_agent
+        FOREIGN KEY (org_id, agent_id) REFERENCES agents(org_id, agent_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_org_agent
+    ON refresh_tokens (org_id, agent_id) WHERE revoked_at IS NULL;
@@ -0,0 +1,16 @@
+-- 128: Record the content-hash algorithm version on each decision.
+--
+-- decisions.content_hash has been produced by two algorithms: legacy v1
+-- (pipe-delimited, no prefix) and v2 (length-prefixed, "v2:" or "v2rN:"
+-- prefix). hash_version records which integrity.HashAlgorithms entry produced
+-- the hash so verification dispatches on the stored version instead of
+-- guessing from the hash text, and a future v3 can be introduced without
+-- rehashing existing rows.
+--
+-- The backfill derives the version from the prefix. hash_version is not a
+-- column guarded by trg_decisions_immutable, so no trigger bypass is needed.
+
+ALTER TABLE decisions ADD COLUMN hash_version SMALLINT NOT NULL DEFAULT 1;
+
+UPDATE decisions SET hash_version = 2
+ WHERE content_hash LIKE 'v2:%' OR content_hash LIKE 'v2r_:%';
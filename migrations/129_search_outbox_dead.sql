@@ -0,0 +1,51 @@
+-- 129: Terminal dead-letter state for the search outbox.
+--
+-- Entries that exhausted their attempts used to stay in search_outbox,
+-- skipped by the worker but counted against the table, until an hourly sweep
+-- archived them after 7 days. The worker now moves an entry to
+-- search_outbox_dead as soon as it reaches AKASHI_OUTBOX_MAX_ATTEMPTS, so
+-- search_outbox only holds work that will still be retried.
+--
+-- search_outbox_dead is the operational queue: admins list it and requeue
+-- entries back into search_outbox, which removes them here. Every move is
+-- still appended to search_outbox_dead_letters, the immutable archive from
+-- migration 029, so requeueing does not erase the failure history.
+
+CREATE TABLE search_outbox_dead (
+    outbox_id   BIGINT PRIMARY KEY,
+    decision_id UUID NOT NULL,
+    org_id      UUID NOT NULL,
+    operation   TEXT NOT NULL CHECK (operation IN ('upsert', 'delete')),
+    attempts    INT NOT NULL,
+    last_error  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL,
+    dead_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_search_outbox_dead_org_dead
+    ON search_outbox_dead (org_id, dead_at DESC);
+
+-- The attempt cap is configurable, so the pending index can no longer bake
+-- in "attempts < 10". Exhausted rows leave the table, so none is needed.
+DROP INDEX IF EXISTS idx_search_outbox_pending;
+
+CREATE INDEX idx_search_outbox_pending
+    ON search_outbox (created_at ASC);
+
+-- Move entries already exhausted under the old fixed cap of 10.
+WITH dead AS (
+    DELETE FROM search_outbox
+    WHERE attempts >= 10
+    RETURNING id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
+),
+archived AS (
+    INSERT INTO search_outbox_dead_letters (
+        outbox_id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
+    )
+    SELECT id, decision_id, org_id, operation, attempts, last_error, created_at, locked_until
+    FROM dead
+    ON CONFLICT (outbox_id) DO NOTHING
+)
+INSERT INTO search_outbox_dead (outbox_id, decision_id, org_id, operation, attempts, last_error, created_at)
+SELECT id, decision_id, org_id, operation, attempts, last_error, created_at
+FROM dead;
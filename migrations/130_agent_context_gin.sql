@@ -0,0 +1,10 @@
+-- 130: GIN index for agent_context containment filters.
+--
+-- POST /v1/query accepts filters.agent_context, which storage renders as
+-- "agent_context @> $n::jsonb". jsonb_path_ops supports exactly that operator
+-- and is smaller than the default jsonb_ops opclass.
+--
+-- Note: not using CONCURRENTLY because migrations run inside transactions.
+
+CREATE INDEX IF NOT EXISTS idx_decisions_agent_context
+    ON decisions USING gin (agent_context jsonb_path_ops);
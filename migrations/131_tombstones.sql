@@ -0,0 +1,21 @@
+-- 131: Tombstone (soft-delete) columns for agent deletion.
+--
+-- With AKASHI_SOFT_DELETE=true, DELETE /v1/agents/{agent_id} no longer removes
+-- rows. It stamps deleted_at on the agent, its runs, and its decisions, and
+-- closes valid_to on decisions that were still current. Read paths exclude
+-- tombstoned rows, but they stay in place so integrity proofs built over them
+-- keep verifying. The retention worker hard-deletes agents tombstoned longer
+-- than AKASHI_TOMBSTONE_RETENTION.
+--
+-- deleted_at is not listed in decisions_immutable_guard (migration 036), so
+-- it is writable like valid_to.
+
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE agent_runs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE decisions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- The purge sweep scans tombstoned agents by age; almost all rows are live,
+-- so a partial index stays tiny.
+CREATE INDEX IF NOT EXISTS idx_agents_tombstoned
+    ON agents (deleted_at)
+    WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,32 @@
+-- 132: Out-of-line storage for large evidence content.
+--
+-- Evidence content larger than AKASHI_EVIDENCE_INLINE_MAX_BYTES is written to
+-- evidence_blobs, keyed by the SHA-256 of the content within an org, and the
+-- evidence row keeps an empty content string plus the hash in blob_hash.
+-- Identical content attached to many decisions (the same log or JSON dump) is
+-- stored once. Reads join the blob back in, or return just the reference when
+-- the caller asks for it.
+--
+-- There is no foreign key from evidence.blob_hash: blobs are shared, so they
+-- cannot cascade with any one evidence row. Blobs no longer referenced by any
+-- evidence row are removed by a periodic sweep once last_referenced_at is past
+-- a grace period, so a write that is about to reference an existing blob is
+-- never raced by its deletion.
+
+CREATE TABLE evidence_blobs (
+    org_id             UUID NOT NULL,
+    content_hash       TEXT NOT NULL,
+    content            TEXT NOT NULL,
+    size_bytes         INT NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_referenced_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (org_id, content_hash)
+);
+
+ALTER TABLE evidence ADD COLUMN IF NOT EXISTS blob_hash TEXT;
+
+-- The orphan sweep and erasure look evidence up by blob; almost all rows keep
+-- content inline, so a partial index stays small.
+CREATE INDEX IF NOT EXISTS idx_evidence_blob_hash
+    ON evidence (org_id, blob_hash)
+    WHERE blob_hash IS NOT NULL;
@@ -0,0 +1,15 @@
+-- 133: Per-request entries in access_audit_log for compliance deployments.
+--
+-- When AKASHI_ACCESS_LOG_REQUESTS is set, every authenticated HTTP request
+-- (reads included) is recorded in access_audit_log with resource_type
+-- 'request', alongside the decision-read entries from 106. Request entries
+-- also carry the response status and latency; both stay NULL for read
+-- entries. Rows are buffered in memory and written in batches, so requests
+-- do not pay a per-request insert.
+
+ALTER TABLE access_audit_log ADD COLUMN IF NOT EXISTS status INTEGER;
+ALTER TABLE access_audit_log ADD COLUMN IF NOT EXISTS latency_ms DOUBLE PRECISION;
+
+-- Investigators typically ask "what did this agent do between X and Y".
+CREATE INDEX IF NOT EXISTS idx_access_audit_log_org_actor_time
+    ON access_audit_log (org_id, actor_agent_id, occurred_at DESC);
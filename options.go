@@ -14,6 +14,7 @@ type resolvedOptions struct {
 	port              int
 	databaseURL       string
 	notifyURL         string
+	replicaURL        string
 	logger            *slog.Logger
 	version           string
 	embeddingProvider EmbeddingProvider
@@ -42,6 +43,13 @@ func WithNotifyURL(url string) Option {
 	return func(o *resolvedOptions) { o.notifyURL = url }
 }
 
+// WithDatabaseReplicaURL routes read-only queries, search, exports, and
+// stats to a read replica (DATABASE_REPLICA_URL env var). Writes and
+// read-after-write lookups stay on the primary.
+func WithDatabaseReplicaURL(url string) Option {
+	return func(o *resolvedOptions) { o.replicaURL = url }
+}
+
 // WithLogger sets the structured logger for the App.
 // If not set, the default slog logger is used.
 func WithLogger(logger *slog.Logger) Option {
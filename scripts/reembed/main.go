@@ -0,0 +1,112 @@
+// Command reembed prepares the database for an embedding provider whose
+// vectors are not comparable with the stored ones, typically one with a
+// different dimension.
+//
+// Usage:
+//
+//	DATABASE_URL=postgres://... go run ./scripts/reembed -dims 768
+//	DATABASE_URL=postgres://... go run ./scripts/reembed -org <uuid>
+//
+// The script nulls every decision, outcome, and evidence embedding and
+// deletes the derived claims, for one org (-org) or for all of them. With
+// -dims, and only when every org was cleared, it then resizes the vector
+// columns to the new dimension; the resize refuses to run while any vector
+// remains, so a concurrent writer cannot leave the schema half-migrated.
+//
+// Afterwards, set AKASHI_EMBEDDING_DIMENSIONS and the new provider and
+// restart. The startup backfills regenerate vectors and claims for current
+// decisions, one batch per restart. With Qdrant, run a re-embedding job
+// (POST /v1/admin/reembed, see docs/runbook.md) for every org instead: it
+// recreates the collection at the new vector size, which the outbox worker
+// cannot do, and finishes large orgs without restarts.
+//
+// Safe to run multiple times — clearing an empty org and resizing to the
+// current dimension are no-ops.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+
+	"github.com/ashita-ai/akashi/internal/storage"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run() error {
+	orgFlag := flag.String("org", "", "clear only this org's embeddings (cannot be combined with -dims)")
+	dims := flag.Int("dims", 0, "resize the embedding columns to this dimension after clearing")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		return fmt.Errorf("DATABASE_URL is required")
+	}
+	if *orgFlag != "" && *dims != 0 {
+		return fmt.Errorf("-dims resizes columns shared by every org; run without -org")
+	}
+	if *dims < 0 || *dims > storage.MaxEmbeddingDimensions {
+		return fmt.Errorf("-dims must be between 1 and %d", storage.MaxEmbeddingDimensions)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	db, err := storage.New(ctx, dbURL, "", logger, storage.PoolOptions{})
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer db.Close(context.Background())
+
+	var orgIDs []uuid.UUID
+	if *orgFlag != "" {
+		id, err := uuid.Parse(*orgFlag)
+		if err != nil {
+			return fmt.Errorf("-org: %w", err)
+		}
+		orgIDs = []uuid.UUID{id}
+	} else {
+		orgIDs, err = db.ListOrganizationIDs(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, orgID := range orgIDs {
+		res, err := db.ClearEmbeddings(ctx, orgID)
+		if err != nil {
+			return fmt.Errorf("org %s: %w", orgID, err)
+		}
+		fmt.Printf("org %s: cleared %d decisions, %d evidence, %d claims\n",
+			orgID, res.Decisions, res.Evidence, res.Claims)
+	}
+
+	if *dims != 0 {
+		current, err := db.EmbeddingDimensions(ctx)
+		if err != nil {
+			return err
+		}
+		if err := db.AlterEmbeddingDimensions(ctx, *dims); err != nil {
+			return err
+		}
+		fmt.Printf("embedding columns: vector(%d) -> vector(%d)\n", current, *dims)
+	}
+
+	fmt.Println("done; restart akashi with the new embedding provider, then run a re-embedding job per org")
+	return nil
+}
@@ -106,8 +106,8 @@ func run() error {
 	for _, r := range stale {
 		expected := integrity.ComputeContentHash(r.id, r.decisionType, r.outcome, r.confidence, r.reasoning, r.validFrom)
 		tag, err := pool.Exec(ctx,
-			`UPDATE decisions SET content_hash = $1 WHERE id = $2`,
-			expected, r.id)
+			`UPDATE decisions SET content_hash = $1, hash_version = $2 WHERE id = $3`,
+			expected, integrity.CurrentHashVersion, r.id)
 		if err != nil {
 			log.Printf("update %s: %v", r.id, err)
 			continue
@@ -125,7 +125,7 @@ def main() -> int:
 
         dead_letter_raw = run_psql(
             database_url,
-            "SELECT count(*) FROM search_outbox WHERE attempts >= 10;",
+            "SELECT count(*) FROM search_outbox_dead;",
         )
         dead_letters = int(dead_letter_raw or "0")
         checks.append(
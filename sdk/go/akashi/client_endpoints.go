@@ -752,7 +752,8 @@ func (c *Client) UpdateAgent(ctx context.Context, agentID string, req UpdateAgen
 	return &resp, nil
 }
 
-// GetAgentStats retrieves aggregate metrics for a specific agent. Requires admin role.
+// GetAgentStats retrieves aggregate metrics for a specific agent. Non-admin
+// callers need a grant on the agent.
 func (c *Client) GetAgentStats(ctx context.Context, agentID string) (*AgentStatsResponse, error) {
 	var resp AgentStatsResponse
 	if err := c.get(ctx, "/v1/agents/"+agentID+"/stats", &resp); err != nil {
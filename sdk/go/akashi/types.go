@@ -25,6 +25,7 @@ type Decision struct {
 	PrecedentReason   *string        `json:"precedent_reason,omitempty"`
 	SupersedesID      *uuid.UUID     `json:"supersedes_id,omitempty"`
 	ContentHash       string         `json:"content_hash,omitempty"`
+	HashVersion       int            `json:"hash_version,omitempty"`
 
 	// Composite agent identity: session and runtime context from the calling agent.
 	SessionID    *uuid.UUID     `json:"session_id,omitempty"`
@@ -108,6 +109,7 @@ type ConflictKind string
 const (
 	ConflictKindCrossAgent        ConflictKind = "cross_agent"
 	ConflictKindSelfContradiction ConflictKind = "self_contradiction"
+	ConflictKindConsensusDrift    ConflictKind = "consensus_drift"
 )
 
 // DecisionConflict represents a detected conflict between two decisions.
@@ -169,6 +171,9 @@ type DecisionConflict struct {
 	// Denormalized project names for project-scoped queries.
 	ProjectA *string `json:"project_a,omitempty"`
 	ProjectB *string `json:"project_b,omitempty"`
+
+	// Consensus drift: every decision in the dissenting minority.
+	MinorityIDs []uuid.UUID `json:"minority_ids,omitempty"`
 }
 
 // --- Request types ---
@@ -314,6 +319,7 @@ const (
 	RunStatusRunning   RunStatus = "running"
 	RunStatusCompleted RunStatus = "completed"
 	RunStatusFailed    RunStatus = "failed"
+	RunStatusAbandoned RunStatus = "abandoned"
 )
 
 // AgentRun is the top-level execution context for an agent.
@@ -1154,6 +1160,13 @@ type AgentStats struct {
 	LastDecisionAt *time.Time `json:"last_decision_at,omitempty"`
 	AvgConfidence  float64    `json:"avg_confidence"`
 	ConflictRate   float64    `json:"conflict_rate"`
+
+	TotalDecisions        int        `json:"total_decisions"`
+	RevisedCount          int        `json:"revised_count"`
+	DistinctSessions      int        `json:"distinct_sessions"`
+	DistinctDecisionTypes int        `json:"distinct_decision_types"`
+	OpenConflicts         int        `json:"open_conflicts"`
+	LastActiveAt          *time.Time `json:"last_active_at,omitempty"`
 }
 
 // ListGrantsOptions are optional filters for Client.ListGrants.
@@ -45,6 +45,7 @@ class Decision(BaseModel):
     precedent_reason: str | None = None
     supersedes_id: UUID | None = None
     content_hash: str = ""
+    hash_version: int = 0
     session_id: UUID | None = None
     agent_context: dict[str, Any] = Field(default_factory=dict)
     tool: str | None = None
@@ -95,6 +96,7 @@ class Evidence(BaseModel):
 class ConflictKind(str, Enum):
     cross_agent = "cross_agent"
     self_contradiction = "self_contradiction"
+    consensus_drift = "consensus_drift"
 
 
 class DecisionConflict(BaseModel):
@@ -143,6 +145,7 @@ class DecisionConflict(BaseModel):
     reopens_resolution_id: UUID | None = None
     project_a: str | None = None
     project_b: str | None = None
+    minority_ids: list[UUID] | None = None
 
 
 class AgentRun(BaseModel):
@@ -868,6 +871,12 @@ class AgentStats(BaseModel):
     last_decision_at: datetime | None = None
     avg_confidence: float = 0.0
     conflict_rate: float = 0.0
+    total_decisions: int = 0
+    revised_count: int = 0
+    distinct_sessions: int = 0
+    distinct_decision_types: int = 0
+    open_conflicts: int = 0
+    last_active_at: datetime | None = None
 
 
 class AgentStatsResponse(BaseModel):
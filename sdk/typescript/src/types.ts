@@ -30,6 +30,7 @@ export interface Decision {
   precedent_reason?: string;
   supersedes_id?: string;
   content_hash?: string;
+  hash_version?: number;
   /** Composite agent identity (spec 31). */
   session_id?: string;
   agent_context?: Record<string, unknown>;
@@ -81,7 +82,7 @@ export interface Evidence {
   created_at: string;
 }
 
-export type ConflictKind = "cross_agent" | "self_contradiction";
+export type ConflictKind = "cross_agent" | "self_contradiction" | "consensus_drift";
 
 /** A detected conflict between two decisions. */
 export interface DecisionConflict {
@@ -135,6 +136,8 @@ export interface DecisionConflict {
   /** Denormalized project names. */
   project_a?: string;
   project_b?: string;
+  /** Consensus drift: every decision in the dissenting minority. */
+  minority_ids?: string[];
 }
 
 /** An agent run (a unit of work that can contain decisions and events). */
@@ -805,6 +808,12 @@ export interface AgentStatValues {
   last_decision_at?: string;
   avg_confidence: number;
   conflict_rate: number;
+  total_decisions: number;
+  revised_count: number;
+  distinct_sessions: number;
+  distinct_decision_types: number;
+  open_conflicts: number;
+  last_active_at?: string;
 }
 
 export interface AgentStatsResponse {